@@ -0,0 +1,132 @@
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Tributary-ai-services/aether-be/pkg/client"
+)
+
+func newConsistencyCommand(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "consistency",
+		Short: "Check and repair the space fields and relationships of the graph",
+	}
+	cmd.AddCommand(newConsistencyCheckCommand(opts), newConsistencyRepairCommand(opts))
+	return cmd
+}
+
+func newConsistencyCheckCommand(opts *globalOptions) *cobra.Command {
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Report notebooks and users whose space fields disagree with their relationships",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := opts.client(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			result, err := c.CheckConsistency(cmd.Context(), &client.CheckConsistencyParams{Limit: limit})
+			if err != nil {
+				return err
+			}
+
+			return opts.render(cmd.OutOrStdout(), result, func(w io.Writer) {
+				row(w, "CHECK", "COUNT")
+				row(w, "notebooks", result.TotalNotebooks)
+				row(w, "inconsistent notebooks", result.InconsistentNotebooks)
+				row(w, "orphaned notebooks", result.OrphanedNotebooks)
+				row(w, "users", result.TotalUsers)
+				row(w, "inconsistent users", result.InconsistentUsers)
+				row(w, "users without OWNS", result.UsersWithoutOwnsRelation)
+				row(w, "orphaned spaces", result.OrphanedSpaces)
+				if len(result.Inconsistencies) == 0 {
+					return
+				}
+				row(w)
+				row(w, "TYPE", "ID", "ISSUE")
+				for _, issue := range result.Inconsistencies {
+					row(w, issue.EntityType, issue.EntityID, issue.Issue)
+				}
+				if result.Truncated {
+					row(w, "...", "more not listed")
+				}
+			})
+		},
+	}
+	cmd.Flags().IntVar(&limit, "limit", 100, "inconsistencies listed per entity type (max 100)")
+	return cmd
+}
+
+func newConsistencyRepairCommand(opts *globalOptions) *cobra.Command {
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "repair",
+		Short: "Restore missing space relationships and fields",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := opts.client(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			result, err := c.RepairConsistency(cmd.Context(), &client.RepairConsistencyParams{DryRun: dryRun})
+			if err != nil {
+				return err
+			}
+
+			return opts.render(cmd.OutOrStdout(), result, func(w io.Writer) {
+				row(w, "REPAIR", "COUNT")
+				row(w, "notebook fields updated", result.NotebookFieldsUpdated)
+				row(w, "notebook relationships created", result.NotebookRelationshipsCreated)
+				row(w, "user relationships created", result.UserRelationshipsCreated)
+				row(w, "unrepairable", result.Unrepairable)
+				if result.DryRun {
+					row(w, "(dry run, nothing changed)")
+				}
+			})
+		},
+	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "count the repairs without making them")
+	return cmd
+}
+
+func newOrphansCommand(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "orphans",
+		Short: "Clean up orphaned entities",
+	}
+
+	var dryRun bool
+	cleanup := &cobra.Command{
+		Use:   "cleanup",
+		Short: "Delete personal spaces nobody owns and retry jobs of deleted documents",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := opts.client(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			result, err := c.CleanupOrphans(cmd.Context(), &client.CleanupOrphansParams{DryRun: dryRun})
+			if err != nil {
+				return err
+			}
+
+			return opts.render(cmd.OutOrStdout(), result, func(w io.Writer) {
+				row(w, "ORPHANS", "DELETED")
+				row(w, "spaces", result.SpacesDeleted)
+				row(w, "retry jobs", result.RetryJobsDeleted)
+				if result.DryRun {
+					row(w, "(dry run, nothing deleted)")
+				}
+			})
+		},
+	}
+	cleanup.Flags().BoolVar(&dryRun, "dry-run", false, "count the orphans without deleting them")
+	cmd.AddCommand(cleanup)
+	return cmd
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Tributary-ai-services/aether-be/pkg/client"
+)
+
+func newDeadLetterCommand(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "dlq",
+		Aliases: []string{"dead-letters"},
+		Short:   "Inspect and requeue document processing jobs that failed",
+	}
+	cmd.AddCommand(newDeadLetterListCommand(opts), newDeadLetterRequeueCommand(opts))
+	return cmd
+}
+
+func newDeadLetterListCommand(opts *globalOptions) *cobra.Command {
+	var params client.ListDeadLettersParams
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List failed processing retry jobs, most recently failed first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := opts.client(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			list, err := c.ListDeadLetters(cmd.Context(), &params)
+			if err != nil {
+				return err
+			}
+
+			return opts.render(cmd.OutOrStdout(), list, func(w io.Writer) {
+				row(w, "JOB", "DOCUMENT", "TENANT", "ATTEMPTS", "FAILED AT", "CAMPAIGN")
+				for _, job := range list.Jobs {
+					row(w, job.ID, job.DocumentID, job.TenantID, job.RetryAttempt, formatTime(job.UpdatedAt), valueOr(job.CampaignID, "-"))
+				}
+				if list.HasMore {
+					row(w, "...", "more with --offset", list.Offset+len(list.Jobs))
+				}
+			})
+		},
+	}
+	cmd.Flags().IntVar(&params.Limit, "limit", 20, "number of jobs to list")
+	cmd.Flags().IntVar(&params.Offset, "offset", 0, "number of jobs to skip")
+	return cmd
+}
+
+func newDeadLetterRequeueCommand(opts *globalOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "requeue JOB_ID...",
+		Short: "Schedule failed jobs to run on the next poll of the retry queue",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := opts.client(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			jobs := make([]*client.ProcessingRetryJob, 0, len(args))
+			for _, id := range args {
+				job, err := c.RequeueDeadLetter(cmd.Context(), id)
+				if err != nil {
+					return err
+				}
+				jobs = append(jobs, job)
+			}
+
+			return opts.render(cmd.OutOrStdout(), jobs, func(w io.Writer) {
+				row(w, "JOB", "DOCUMENT", "STATUS", "RETRY AT")
+				for _, job := range jobs {
+					row(w, job.ID, job.DocumentID, job.Status, formatTime(job.RetryAt))
+				}
+			})
+		},
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Tributary-ai-services/aether-be/pkg/client"
+)
+
+func newEncryptionCommand(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "encryption",
+		Short: "Encrypt the extracted text of spaces at rest",
+	}
+	cmd.AddCommand(
+		newEncryptionSetCommand(opts, "enable", true,
+			"Turn encryption at rest on for a space and encrypt its documents' text",
+			"Encrypt the extracted text of the space's documents with its tenant's key.\n"+
+				"The text is then left out of search, so the documents are found by name,\n"+
+				"description and tags only."),
+		newEncryptionSetCommand(opts, "disable", false,
+			"Turn encryption at rest off for a space and decrypt its documents' text",
+			"Decrypt the extracted text of the space's documents, which are then found\n"+
+				"by their text again."),
+	)
+	return cmd
+}
+
+// newEncryptionSetCommand returns a command setting the encryption of a
+// space, repeating the request until every document is migrated
+func newEncryptionSetCommand(opts *globalOptions, use string, enabled bool, short, long string) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " SPACE_ID",
+		Short: short,
+		Long: long + "\n\nThe API migrates documents for a bounded time per request; the request is\n" +
+			"repeated until none remain.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := opts.client(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			total := 0
+			var encryption *client.SpaceEncryption
+			for {
+				encryption, err = c.UpdateSpaceEncryption(cmd.Context(), args[0], client.SpaceEncryptionUpdateRequest{Enabled: enabled})
+				if err != nil {
+					return err
+				}
+				total += encryption.Migrated
+				// Documents that cannot be migrated stay remaining
+				if encryption.Remaining == 0 || encryption.Migrated == 0 {
+					break
+				}
+			}
+			encryption.Migrated = total
+
+			return opts.render(cmd.OutOrStdout(), encryption, func(w io.Writer) {
+				row(w, "SPACE", "ENABLED", "MIGRATED", "REMAINING")
+				row(w, encryption.SpaceID, encryption.Enabled, encryption.Migrated, encryption.Remaining)
+			})
+		},
+	}
+}
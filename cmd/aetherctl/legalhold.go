@@ -0,0 +1,246 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Tributary-ai-services/aether-be/pkg/client"
+)
+
+func newLegalHoldCommand(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "hold",
+		Aliases: []string{"legal-hold"},
+		Short:   "Place content under e-discovery holds and collect it",
+	}
+	cmd.AddCommand(
+		newLegalHoldCreateCommand(opts),
+		newLegalHoldListCommand(opts),
+		newLegalHoldReleaseCommand(opts),
+		newLegalHoldCollectCommand(opts),
+		newLegalHoldExportCommand(opts),
+	)
+	return cmd
+}
+
+func newLegalHoldCreateCommand(opts *globalOptions) *cobra.Command {
+	var req client.LegalHoldCreateRequest
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Place users and notebooks of a tenant under a legal hold",
+		Long: "While the hold is active, the documents of its notebooks and the documents\n" +
+			"and notebooks its users own cannot be edited, deleted or purged.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(req.UserIDs) == 0 && len(req.NotebookIDs) == 0 {
+				return fmt.Errorf("--user or --notebook is required")
+			}
+			c, err := opts.client(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			hold, err := c.CreateLegalHold(cmd.Context(), req)
+			if err != nil {
+				return err
+			}
+			return opts.render(cmd.OutOrStdout(), hold, func(w io.Writer) {
+				renderLegalHolds(w, []*client.LegalHold{hold})
+			})
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&req.TenantID, "tenant", "", "tenant of the held content")
+	flags.StringVar(&req.Name, "name", "", "name of the hold")
+	flags.StringVar(&req.Matter, "matter", "", "case or matter reference")
+	flags.StringVar(&req.Description, "description", "", "description of the hold")
+	flags.StringSliceVar(&req.UserIDs, "user", nil, "ID of a custodian whose content to hold (repeatable)")
+	flags.StringSliceVar(&req.NotebookIDs, "notebook", nil, "ID of a notebook to hold (repeatable)")
+	cmd.MarkFlagRequired("tenant")
+	cmd.MarkFlagRequired("name")
+	return cmd
+}
+
+func newLegalHoldListCommand(opts *globalOptions) *cobra.Command {
+	var params client.ListLegalHoldsParams
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List legal holds, newest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := opts.client(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			holds, err := c.ListLegalHolds(cmd.Context(), &params)
+			if err != nil {
+				return err
+			}
+			return opts.render(cmd.OutOrStdout(), holds, func(w io.Writer) {
+				renderLegalHolds(w, holds.Holds)
+			})
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&params.TenantID, "tenant", "", "only holds of this tenant")
+	flags.StringVar(&params.Status, "status", "", "only holds with this status: active or released")
+	flags.IntVar(&params.Limit, "limit", 20, "maximum number of holds to list (max 100)")
+	flags.IntVar(&params.Offset, "offset", 0, "number of holds to skip")
+	return cmd
+}
+
+func newLegalHoldReleaseCommand(opts *globalOptions) *cobra.Command {
+	var req client.LegalHoldReleaseRequest
+	cmd := &cobra.Command{
+		Use:   "release HOLD_ID",
+		Short: "Release a legal hold",
+		Long: "The held content can be edited and deleted again unless another active hold\n" +
+			"covers it. The hold is kept, released, for the record.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := opts.client(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			hold, err := c.ReleaseLegalHold(cmd.Context(), args[0], req)
+			if err != nil {
+				return err
+			}
+			return opts.render(cmd.OutOrStdout(), hold, func(w io.Writer) {
+				renderLegalHolds(w, []*client.LegalHold{hold})
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&req.Reason, "reason", "", "why the hold is released")
+	cmd.MarkFlagRequired("reason")
+	return cmd
+}
+
+// legalHoldCriteriaFlags adds the flags of collection criteria to a
+// command, returning a function reading them
+func legalHoldCriteriaFlags(cmd *cobra.Command) func() (client.LegalHoldCriteria, error) {
+	var (
+		keywords []string
+		from, to string
+	)
+	flags := cmd.Flags()
+	flags.StringSliceVar(&keywords, "keyword", nil, "keyword the documents contain (repeatable; any matches)")
+	flags.StringVar(&from, "from", "", "only documents uploaded at or after this date or RFC 3339 time")
+	flags.StringVar(&to, "to", "", "only documents uploaded before this date or RFC 3339 time")
+
+	return func() (client.LegalHoldCriteria, error) {
+		criteria := client.LegalHoldCriteria{Keywords: keywords}
+		var err error
+		if criteria.From, err = parseDate("--from", from); err != nil {
+			return criteria, err
+		}
+		if criteria.To, err = parseDate("--to", to); err != nil {
+			return criteria, err
+		}
+		return criteria, nil
+	}
+}
+
+// parseDate reads an optional date, as 2006-01-02 or an RFC 3339 time
+func parseDate(flag, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("%s %q is neither a date nor an RFC 3339 time", flag, value)
+}
+
+func newLegalHoldCollectCommand(opts *globalOptions) *cobra.Command {
+	var params client.CollectLegalHoldParams
+	cmd := &cobra.Command{
+		Use:   "collect HOLD_ID",
+		Short: "Search the documents a legal hold covers",
+		Long: "List the held documents, including those in the trash, that contain any of\n" +
+			"the keywords as whole words and were uploaded in the date range.",
+		Args: cobra.ExactArgs(1),
+	}
+	criteria := legalHoldCriteriaFlags(cmd)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		body, err := criteria()
+		if err != nil {
+			return err
+		}
+		c, err := opts.client(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		collection, err := c.CollectLegalHold(cmd.Context(), args[0], &params, body)
+		if err != nil {
+			return err
+		}
+		return opts.render(cmd.OutOrStdout(), collection, func(w io.Writer) {
+			row(w, "DOCUMENT", "NAME", "STATUS", "UPLOADED", "KEYWORDS")
+			for _, document := range collection.Documents {
+				row(w, document.DocumentID, document.Name, document.Status, formatTime(document.CreatedAt),
+					valueOr(strings.Join(document.MatchedKeywords, ","), "-"))
+			}
+			row(w, "")
+			row(w, "Matched", collection.Total, "documents")
+		})
+	}
+
+	flags := cmd.Flags()
+	flags.IntVar(&params.Limit, "limit", 20, "maximum number of documents to list (max 100)")
+	flags.IntVar(&params.Offset, "offset", 0, "number of documents to skip")
+	return cmd
+}
+
+func newLegalHoldExportCommand(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export HOLD_ID",
+		Short: "Export the documents a collection finds with their chain of custody",
+		Long: "Start a job writing the matching held documents, their text, stored files\n" +
+			"and audit events to a bundle in object storage. Its manifest records who\n" +
+			"collected the bundle and when, and the SHA-256 hash of every file; follow the\n" +
+			"job at GET /api/v1/jobs/{id}.",
+		Args: cobra.ExactArgs(1),
+	}
+	criteria := legalHoldCriteriaFlags(cmd)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		body, err := criteria()
+		if err != nil {
+			return err
+		}
+		c, err := opts.client(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		job, err := c.StartLegalHoldExport(cmd.Context(), args[0], body)
+		if err != nil {
+			return err
+		}
+		return opts.render(cmd.OutOrStdout(), job, func(w io.Writer) {
+			row(w, "Export job", job.ID, job.Status)
+		})
+	}
+	return cmd
+}
+
+// renderLegalHolds writes legal holds as a table
+func renderLegalHolds(w io.Writer, holds []*client.LegalHold) {
+	row(w, "ID", "TENANT", "NAME", "MATTER", "STATUS", "USERS", "NOTEBOOKS", "CREATED")
+	for _, hold := range holds {
+		row(w, hold.ID, hold.TenantID, hold.Name, valueOr(hold.Matter, "-"), hold.Status,
+			len(hold.UserIDs), len(hold.NotebookIDs), formatTime(hold.CreatedAt))
+	}
+}
@@ -0,0 +1,128 @@
+// Command aetherctl is the operator CLI of the Aether admin API: tenant
+// provisioning and region failover, graph consistency checks and repairs,
+// orphan cleanup, reprocessing campaigns, dead letter inspection, usage
+// reports, the encryption of spaces' text at rest and legal holds.
+//
+// Usage:
+//
+//	aetherctl --url https://aether.example.com consistency check
+//	aetherctl dlq list -o json
+//
+// Requests are authenticated with --token or, for unattended use, with the
+// service account of a Keycloak client holding the admin realm role
+// (--keycloak-url, --realm, --client-id, --client-secret). Every flag can
+// also be set through the environment variable named in its help.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Tributary-ai-services/aether-be/pkg/client"
+)
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "aetherctl: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// envFlags are the flags defaulting to an environment variable. They are
+// read when a command runs so that secrets are not shown by --help.
+var envFlags = map[string]string{
+	"url":           "AETHER_URL",
+	"token":         "AETHER_TOKEN",
+	"keycloak-url":  "KEYCLOAK_URL",
+	"realm":         "KEYCLOAK_REALM",
+	"client-id":     "AETHERCTL_CLIENT_ID",
+	"client-secret": "AETHERCTL_CLIENT_SECRET",
+}
+
+// globalOptions are the connection and output flags shared by every
+// command
+type globalOptions struct {
+	url          string
+	token        string
+	keycloakURL  string
+	realm        string
+	clientID     string
+	clientSecret string
+	output       string
+}
+
+func newRootCommand() *cobra.Command {
+	opts := &globalOptions{}
+	root := &cobra.Command{
+		Use:           "aetherctl",
+		Short:         "Operate an Aether deployment through its admin API",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			for name, env := range envFlags {
+				value, ok := os.LookupEnv(env)
+				if !ok || cmd.Flags().Changed(name) {
+					continue
+				}
+				if err := cmd.Flags().Set(name, value); err != nil {
+					return err
+				}
+			}
+			if opts.output != outputTable && opts.output != outputJSON {
+				return fmt.Errorf("--output must be %s or %s", outputTable, outputJSON)
+			}
+			return nil
+		},
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&opts.url, "url", "", "Aether API base URL (AETHER_URL)")
+	flags.StringVar(&opts.token, "token", "", "bearer token (AETHER_TOKEN)")
+	flags.StringVar(&opts.keycloakURL, "keycloak-url", "", "Keycloak base URL (KEYCLOAK_URL)")
+	flags.StringVar(&opts.realm, "realm", "aether", "Keycloak realm (KEYCLOAK_REALM)")
+	flags.StringVar(&opts.clientID, "client-id", "", "service account client ID (AETHERCTL_CLIENT_ID)")
+	flags.StringVar(&opts.clientSecret, "client-secret", "", "service account client secret (AETHERCTL_CLIENT_SECRET)")
+	flags.StringVarP(&opts.output, "output", "o", outputTable, "output format: table or json")
+
+	root.AddCommand(
+		newTenantCommand(opts),
+		newConsistencyCommand(opts),
+		newOrphansCommand(opts),
+		newReprocessCommand(opts),
+		newDeadLetterCommand(opts),
+		newUsageCommand(opts),
+		newEncryptionCommand(opts),
+		newLegalHoldCommand(opts),
+	)
+	return root
+}
+
+// client creates an API client authenticated with the token, or else the
+// Keycloak service account
+func (o *globalOptions) client(ctx context.Context) (*client.Client, error) {
+	if o.url == "" {
+		return nil, fmt.Errorf("--url or AETHER_URL is required")
+	}
+
+	options := []client.Option{client.WithUserAgent("aetherctl/" + client.APIVersion)}
+	switch {
+	case o.token != "":
+		options = append(options, client.WithToken(o.token))
+	case o.clientID != "":
+		if o.keycloakURL == "" {
+			return nil, fmt.Errorf("--keycloak-url or KEYCLOAK_URL is required with --client-id")
+		}
+		options = append(options, client.WithTokenSource(client.KeycloakTokenSource(ctx, client.KeycloakConfig{
+			URL:          o.keycloakURL,
+			Realm:        o.realm,
+			ClientID:     o.clientID,
+			ClientSecret: o.clientSecret,
+		})))
+	default:
+		return nil, fmt.Errorf("no credentials: set --token or --client-id and --client-secret")
+	}
+	return client.New(o.url, options...)
+}
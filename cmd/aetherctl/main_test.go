@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// request is a request received by the fake admin API
+type request struct {
+	method, path, query, auth string
+	body                      map[string]interface{}
+}
+
+// run executes aetherctl against a fake admin API answering every request
+// with response and returns the received requests and the output
+func run(t *testing.T, response interface{}, args ...string) ([]request, string, error) {
+	t.Helper()
+	var received []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := request{method: r.Method, path: r.URL.Path, query: r.URL.RawQuery, auth: r.Header.Get("Authorization")}
+		if r.ContentLength > 0 {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req.body))
+		}
+		received = append(received, req)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+
+	var out bytes.Buffer
+	cmd := newRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetArgs(append([]string{"--url", server.URL, "--token", "admin-token"}, args...))
+	err := cmd.Execute()
+	return received, out.String(), err
+}
+
+func TestConsistencyRepairDryRun(t *testing.T) {
+	received, out, err := run(t, map[string]interface{}{
+		"dry_run":                        true,
+		"notebook_relationships_created": 3,
+		"unrepairable":                   1,
+	}, "consistency", "repair", "--dry-run")
+	require.NoError(t, err)
+
+	require.Len(t, received, 1)
+	assert.Equal(t, request{method: http.MethodPost, path: "/api/v1/admin/consistency/repair", query: "dry_run=true", auth: "Bearer admin-token"}, received[0])
+	assert.Contains(t, out, "notebook relationships created  3")
+	assert.Contains(t, out, "unrepairable                    1")
+	assert.Contains(t, out, "dry run")
+}
+
+func TestReprocessSendsTheCampaign(t *testing.T) {
+	received, out, err := run(t, map[string]interface{}{
+		"campaign_id":  "campaign-1",
+		"queued":       2,
+		"document_ids": []string{"doc-1", "doc-2"},
+	}, "reprocess", "--tenant", "tenant_1", "--since", "2026-10-01T00:00:00Z", "--limit", "50", "-o", "json")
+	require.NoError(t, err)
+
+	require.Len(t, received, 1)
+	assert.Equal(t, "/api/v1/admin/reprocess", received[0].path)
+	assert.Equal(t, map[string]interface{}{
+		"status":        "failed",
+		"tenant_id":     "tenant_1",
+		"created_after": "2026-10-01T00:00:00Z",
+		"limit":         float64(50),
+	}, received[0].body)
+
+	var campaign map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(out), &campaign))
+	assert.Equal(t, "campaign-1", campaign["campaign_id"])
+}
+
+func TestDeadLetterRequeueEachJob(t *testing.T) {
+	received, out, err := run(t, map[string]interface{}{"id": "job-1", "document_id": "doc-1", "status": "scheduled"},
+		"dlq", "requeue", "job-1", "job-2")
+	require.NoError(t, err)
+
+	require.Len(t, received, 2)
+	assert.Equal(t, "/api/v1/admin/dead-letters/job-1/requeue", received[0].path)
+	assert.Equal(t, "/api/v1/admin/dead-letters/job-2/requeue", received[1].path)
+	assert.Contains(t, out, "scheduled")
+}
+
+func TestTenantProvision(t *testing.T) {
+	received, out, err := run(t, map[string]interface{}{
+		"organization": map[string]interface{}{"id": "org-1", "name": "Acme"},
+		"space":        map[string]interface{}{"id": "space_1", "tenantId": "tenant_1"},
+	}, "tenant", "provision", "Acme", "--owner", "user-1")
+	require.NoError(t, err)
+
+	require.Len(t, received, 1)
+	assert.Equal(t, map[string]interface{}{
+		"owner_id":     "user-1",
+		"organization": map[string]interface{}{"name": "Acme", "visibility": "private"},
+	}, received[0].body)
+	assert.Contains(t, out, "org-1")
+	assert.Contains(t, out, "tenant_1")
+}
+
+func TestTenantFailover(t *testing.T) {
+	received, out, err := run(t, map[string]interface{}{
+		"from_region": "eu-west",
+		"to_region":   "us-east",
+		"tenant":      map[string]interface{}{"tenant_id": "tenant_1", "active_region": "us-east", "passive_region": "eu-west", "failovers": 1},
+		"hooks":       []interface{}{map[string]interface{}{"name": "neo4j", "status": 200, "duration_ms": 1200}},
+	}, "tenant", "failover", "tenant_1", "--to", "us-east")
+	require.NoError(t, err)
+
+	require.Len(t, received, 1)
+	assert.Equal(t, "/api/v1/admin/tenants/tenant_1/failover", received[0].path)
+	assert.Equal(t, map[string]interface{}{"target_region": "us-east"}, received[0].body)
+	assert.Contains(t, out, "neo4j")
+	assert.Contains(t, out, "us-east")
+}
+
+func TestEncryptionEnable(t *testing.T) {
+	received, out, err := run(t, map[string]interface{}{
+		"space_id": "space_1",
+		"enabled":  true,
+		"migrated": 12,
+	}, "encryption", "enable", "space_1")
+	require.NoError(t, err)
+
+	require.Len(t, received, 1)
+	assert.Equal(t, http.MethodPut, received[0].method)
+	assert.Equal(t, "/api/v1/admin/spaces/space_1/encryption", received[0].path)
+	assert.Equal(t, map[string]interface{}{"enabled": true}, received[0].body)
+	assert.Contains(t, out, "space_1")
+	assert.Contains(t, out, "12")
+}
+
+func TestLegalHoldExport(t *testing.T) {
+	received, out, err := run(t, map[string]interface{}{
+		"id":     "job-1",
+		"type":   "legal_hold_export",
+		"status": "pending",
+	}, "hold", "export", "hold-1", "--keyword", "merger", "--from", "2026-01-01")
+	require.NoError(t, err)
+
+	require.Len(t, received, 1)
+	assert.Equal(t, http.MethodPost, received[0].method)
+	assert.Equal(t, "/api/v1/admin/legal-holds/hold-1/export", received[0].path)
+	assert.Equal(t, map[string]interface{}{
+		"keywords": []interface{}{"merger"},
+		"from":     "2026-01-01T00:00:00Z",
+	}, received[0].body)
+	assert.Contains(t, out, "job-1")
+
+	_, _, err = run(t, nil, "hold", "collect", "hold-1", "--to", "yesterday")
+	assert.ErrorContains(t, err, "--to")
+}
+
+func TestCredentialsAreRequired(t *testing.T) {
+	t.Setenv("AETHER_TOKEN", "")
+	t.Setenv("AETHERCTL_CLIENT_ID", "")
+	cmd := newRootCommand()
+	cmd.SetArgs([]string{"--url", "http://localhost:8080", "usage"})
+	assert.ErrorContains(t, cmd.Execute(), "no credentials")
+
+	t.Setenv("AETHER_URL", "")
+	cmd = newRootCommand()
+	cmd.SetArgs([]string{"--token", "t", "usage"})
+	assert.ErrorContains(t, cmd.Execute(), "--url")
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	since, err := parseSince("72h", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 13, 12, 0, 0, 0, time.UTC), since)
+
+	since, err = parseSince("2026-10-01T00:00:00Z", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), since)
+
+	_, err = parseSince("last week", now)
+	assert.Error(t, err)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Tributary-ai-services/aether-be/pkg/client"
+)
+
+func newReprocessCommand(opts *globalOptions) *cobra.Command {
+	var (
+		req   client.ReprocessCampaignRequest
+		since string
+	)
+	cmd := &cobra.Command{
+		Use:   "reprocess",
+		Short: "Start a campaign reprocessing the documents with a status",
+		Long: "Queue the matching documents on the retry queue, which resubmits them to\n" +
+			"AudiModal a bounded batch at a time. Documents already queued are skipped.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if since != "" {
+				createdAfter, err := parseSince(since, time.Now())
+				if err != nil {
+					return err
+				}
+				req.CreatedAfter = &createdAfter
+			}
+
+			c, err := opts.client(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			campaign, err := c.StartReprocessing(cmd.Context(), req)
+			if err != nil {
+				return err
+			}
+
+			return opts.render(cmd.OutOrStdout(), campaign, func(w io.Writer) {
+				if campaign.DryRun {
+					row(w, "Would queue", campaign.Queued, "documents")
+				} else {
+					row(w, "Campaign", campaign.CampaignID, "queued", campaign.Queued, "documents")
+				}
+				for _, id := range campaign.DocumentIDs {
+					row(w, "", id)
+				}
+			})
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&req.Status, "status", "failed", "status of the documents: failed, processing or processed")
+	flags.StringVar(&req.TenantID, "tenant", "", "only documents of this tenant")
+	flags.StringVar(&since, "since", "", "only documents created after this RFC 3339 time or duration ago, e.g. 72h")
+	flags.IntVar(&req.Limit, "limit", 100, "maximum number of documents to queue (max 1000)")
+	flags.BoolVar(&req.DryRun, "dry-run", false, "list the documents without queueing them")
+	return cmd
+}
+
+// parseSince reads an RFC 3339 time or a duration before now
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return time.Time{}, fmt.Errorf("--since %q is neither an RFC 3339 time nor a duration", value)
+	}
+	return now.Add(-d), nil
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Tributary-ai-services/aether-be/pkg/client"
+)
+
+func newTenantCommand(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tenant",
+		Short: "Manage tenants",
+	}
+	cmd.AddCommand(
+		newTenantProvisionCommand(opts),
+		newTenantRegionCommand(opts),
+		newTenantPinCommand(opts),
+		newTenantFailoverCommand(opts),
+	)
+	return cmd
+}
+
+func newTenantProvisionCommand(opts *globalOptions) *cobra.Command {
+	var (
+		owner     string
+		org       client.OrganizationCreateRequest
+		spaceName string
+	)
+	cmd := &cobra.Command{
+		Use:   "provision NAME",
+		Short: "Create an organization owned by an existing user and its first space",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := opts.client(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			org.Name = args[0]
+			tenant, err := c.ProvisionTenant(cmd.Context(), client.TenantProvisionRequest{
+				OwnerID:      owner,
+				Organization: &org,
+				SpaceName:    spaceName,
+			})
+			if err != nil {
+				return err
+			}
+
+			return opts.render(cmd.OutOrStdout(), tenant, func(w io.Writer) {
+				row(w, "ORGANIZATION", "SPACE", "TENANT", "AUDIMODAL TENANT")
+				row(w, tenant.Organization.ID, tenant.Space.ID, tenant.Space.TenantID, valueOr(tenant.Space.AudimodalTenantID, "-"))
+			})
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&owner, "owner", "", "ID of the user owning the organization")
+	flags.StringVar(&org.Slug, "slug", "", "organization slug")
+	flags.StringVar(&org.Description, "description", "", "organization description")
+	flags.StringVar(&org.Visibility, "visibility", "private", "organization visibility: private or public")
+	flags.StringVar(&org.BillingEmail, "billing-email", "", "organization billing email")
+	flags.StringVar(&spaceName, "space-name", "", "name of the first space (default the organization name)")
+	_ = cmd.MarkFlagRequired("owner")
+	return cmd
+}
+
+func newTenantRegionCommand(opts *globalOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "region TENANT_ID",
+		Short: "Show the region that serves a tenant and its passive region",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := opts.client(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			pin, err := c.GetTenantRegion(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			return opts.render(cmd.OutOrStdout(), pin, func(w io.Writer) {
+				renderTenantRegion(w, pin)
+			})
+		},
+	}
+}
+
+func newTenantPinCommand(opts *globalOptions) *cobra.Command {
+	var req client.TenantRegionRequest
+	cmd := &cobra.Command{
+		Use:   "pin TENANT_ID",
+		Short: "Pin a tenant to the region that serves it",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := opts.client(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			pin, err := c.PinTenantRegion(cmd.Context(), args[0], req)
+			if err != nil {
+				return err
+			}
+			return opts.render(cmd.OutOrStdout(), pin, func(w io.Writer) {
+				renderTenantRegion(w, pin)
+			})
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&req.ActiveRegion, "active", "", "region serving the tenant")
+	flags.StringVar(&req.PassiveRegion, "passive", "", "region holding the tenant's replicas, to fail over to")
+	_ = cmd.MarkFlagRequired("active")
+	return cmd
+}
+
+func newTenantFailoverCommand(opts *globalOptions) *cobra.Command {
+	var req client.TenantFailoverRequest
+	cmd := &cobra.Command{
+		Use:   "failover TENANT_ID",
+		Short: "Fail a tenant over to its passive region after running the replication hooks",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := opts.client(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			failover, err := c.FailoverTenant(cmd.Context(), args[0], req)
+			if err != nil {
+				return err
+			}
+			return opts.render(cmd.OutOrStdout(), failover, func(w io.Writer) {
+				row(w, "HOOK", "STATUS", "DURATION (MS)", "ERROR")
+				for _, hook := range failover.Hooks {
+					row(w, hook.Name, hook.Status, hook.DurationMs, valueOr(hook.Error, "-"))
+				}
+				row(w)
+				renderTenantRegion(w, failover.Tenant)
+			})
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&req.TargetRegion, "to", "", "region to fail over to (default the tenant's passive region)")
+	flags.BoolVar(&req.Force, "force", false, "fail over even when a replication hook fails")
+	return cmd
+}
+
+func renderTenantRegion(w io.Writer, pin *client.TenantRegion) {
+	row(w, "TENANT", "ACTIVE", "PASSIVE", "FAILOVERS", "FAILED OVER AT")
+	row(w, pin.TenantID, pin.ActiveRegion, valueOr(pin.PassiveRegion, "-"), pin.Failovers, formatTime(pin.FailedOverAt))
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Tributary-ai-services/aether-be/pkg/client"
+)
+
+func newUsageCommand(opts *globalOptions) *cobra.Command {
+	var params client.GetUsageReportParams
+	cmd := &cobra.Command{
+		Use:   "usage",
+		Short: "Report document usage per space",
+		Long: "Total the uploads and processed, failed and deleted documents per space from\n" +
+			"the reporting read models. The period defaults to the last 30 days.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := opts.client(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			report, err := c.GetUsageReport(cmd.Context(), &params)
+			if err != nil {
+				return err
+			}
+
+			return opts.render(cmd.OutOrStdout(), report, func(w io.Writer) {
+				row(w, "SPACE", "UPLOADED", "BYTES", "PROCESSED", "FAILED", "DELETED")
+				for _, usage := range report.Spaces {
+					usageRow(w, usage.SpaceID, usage)
+				}
+				if report.Total != nil {
+					usageRow(w, "TOTAL", report.Total)
+				}
+			})
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&params.From, "from", "", "first day of the period (YYYY-MM-DD)")
+	flags.StringVar(&params.To, "to", "", "last day of the period (YYYY-MM-DD), default today")
+	flags.StringVar(&params.SpaceID, "space", "", "report only this space")
+	return cmd
+}
+
+func usageRow(w io.Writer, label string, usage *client.SpaceUsage) {
+	row(w, label, usage.DocumentsUploaded, usage.BytesUploaded, usage.DocumentsProcessed, usage.DocumentsFailed, usage.DocumentsDeleted)
+}
@@ -0,0 +1,89 @@
+// Command loadreport compares k6 summary exports from a load test run
+// against a stored baseline and exits non-zero when a scenario regressed.
+//
+// Usage:
+//
+//	loadreport -baseline tests/performance/baseline -current tests/performance/reports/<run>
+//
+// Each scenario is matched by file name (search.json, stream_ingest.json,
+// ...). Scenarios without a baseline are reported but never fail the run.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+func main() {
+	baselineDir := flag.String("baseline", "tests/performance/baseline", "directory holding baseline k6 summaries")
+	currentDir := flag.String("current", "", "directory holding the k6 summaries to check")
+	latency := flag.Float64("latency-tolerance", 0.10, "allowed relative p95 latency increase")
+	errorRate := flag.Float64("error-tolerance", 0.01, "allowed absolute error rate increase")
+	throughput := flag.Float64("throughput-tolerance", 0.10, "allowed relative throughput decrease")
+	flag.Parse()
+
+	if *currentDir == "" {
+		fmt.Fprintln(os.Stderr, "loadreport: -current is required")
+		os.Exit(2)
+	}
+
+	tol := Tolerance{Latency: *latency, ErrorRate: *errorRate, Throughput: *throughput}
+	failed, err := run(*baselineDir, *currentDir, tol)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "loadreport: %v\n", err)
+		os.Exit(2)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// run prints a comparison for every summary in currentDir and reports
+// whether any scenario regressed
+func run(baselineDir, currentDir string, tol Tolerance) (bool, error) {
+	files, err := filepath.Glob(filepath.Join(currentDir, "*.json"))
+	if err != nil {
+		return false, err
+	}
+	if len(files) == 0 {
+		return false, fmt.Errorf("no k6 summaries found in %s", currentDir)
+	}
+	sort.Strings(files)
+
+	failed := false
+	for _, file := range files {
+		name := filepath.Base(file)
+
+		current, err := LoadResult(file)
+		if err != nil {
+			return false, err
+		}
+		fmt.Printf("%-24s p95=%.1fms errors=%.2f%% rps=%.1f requests=%d\n",
+			name, current.P95Ms, current.ErrorRate*100, current.Throughput, current.Requests)
+
+		baselinePath := filepath.Join(baselineDir, name)
+		if _, err := os.Stat(baselinePath); os.IsNotExist(err) {
+			fmt.Printf("  no baseline, skipping comparison\n")
+			continue
+		}
+		baseline, err := LoadResult(baselinePath)
+		if err != nil {
+			return false, err
+		}
+
+		regressions := Compare(baseline, current, tol)
+		if len(regressions) == 0 {
+			fmt.Printf("  ok (baseline p95=%.1fms rps=%.1f)\n", baseline.P95Ms, baseline.Throughput)
+			continue
+		}
+		failed = true
+		for _, r := range regressions {
+			fmt.Printf("  FAIL %s\n", r)
+		}
+	}
+
+	return failed, nil
+}
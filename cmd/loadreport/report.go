@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Summary is the subset of a k6 --summary-export file the comparison uses
+type Summary struct {
+	Metrics struct {
+		HTTPReqDuration struct {
+			P95 float64 `json:"p(95)"`
+			P99 float64 `json:"p(99)"`
+		} `json:"http_req_duration"`
+		HTTPReqFailed struct {
+			Value float64 `json:"value"`
+		} `json:"http_req_failed"`
+		HTTPReqs struct {
+			Count float64 `json:"count"`
+			Rate  float64 `json:"rate"`
+		} `json:"http_reqs"`
+	} `json:"metrics"`
+}
+
+// Result holds the headline numbers of one scenario run
+type Result struct {
+	P95Ms      float64
+	ErrorRate  float64
+	Throughput float64 // requests per second
+	Requests   int64
+}
+
+// Tolerance bounds how far a run may drift from its baseline before it is
+// reported as a regression
+type Tolerance struct {
+	Latency    float64 // allowed relative p95 increase, e.g. 0.10 for 10%
+	ErrorRate  float64 // allowed absolute error rate increase, e.g. 0.01
+	Throughput float64 // allowed relative throughput decrease
+}
+
+// Regression describes one metric that exceeded its tolerance
+type Regression struct {
+	Metric   string
+	Baseline float64
+	Current  float64
+}
+
+func (r Regression) String() string {
+	return fmt.Sprintf("%s regressed: baseline %.2f, current %.2f", r.Metric, r.Baseline, r.Current)
+}
+
+// LoadResult reads a k6 summary export
+func LoadResult(path string) (Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var summary Summary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return Result{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if summary.Metrics.HTTPReqs.Count == 0 {
+		return Result{}, fmt.Errorf("%s contains no HTTP requests", path)
+	}
+
+	return Result{
+		P95Ms:      summary.Metrics.HTTPReqDuration.P95,
+		ErrorRate:  summary.Metrics.HTTPReqFailed.Value,
+		Throughput: summary.Metrics.HTTPReqs.Rate,
+		Requests:   int64(summary.Metrics.HTTPReqs.Count),
+	}, nil
+}
+
+// Compare returns the metrics of current that regressed against baseline
+func Compare(baseline, current Result, tol Tolerance) []Regression {
+	var regressions []Regression
+
+	if current.P95Ms > baseline.P95Ms*(1+tol.Latency) {
+		regressions = append(regressions, Regression{"p95 latency (ms)", baseline.P95Ms, current.P95Ms})
+	}
+	if current.ErrorRate > baseline.ErrorRate+tol.ErrorRate {
+		regressions = append(regressions, Regression{"error rate", baseline.ErrorRate, current.ErrorRate})
+	}
+	if current.Throughput < baseline.Throughput*(1-tol.Throughput) {
+		regressions = append(regressions, Regression{"throughput (req/s)", baseline.Throughput, current.Throughput})
+	}
+
+	return regressions
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadResult(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "search.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"metrics": {
+			"http_req_duration": {"avg": 80.1, "p(95)": 212.5, "p(99)": 390.2},
+			"http_req_failed": {"passes": 3, "fails": 997, "value": 0.003},
+			"http_reqs": {"count": 1000, "rate": 16.6}
+		}
+	}`), 0o644))
+
+	result, err := LoadResult(path)
+	require.NoError(t, err)
+	assert.Equal(t, Result{P95Ms: 212.5, ErrorRate: 0.003, Throughput: 16.6, Requests: 1000}, result)
+}
+
+func TestLoadResultRejectsEmptyRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "search.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"metrics": {}}`), 0o644))
+
+	_, err := LoadResult(path)
+	assert.Error(t, err)
+}
+
+func TestCompare(t *testing.T) {
+	baseline := Result{P95Ms: 200, ErrorRate: 0.002, Throughput: 100}
+	tol := Tolerance{Latency: 0.10, ErrorRate: 0.01, Throughput: 0.10}
+
+	tests := []struct {
+		name    string
+		current Result
+		metrics []string
+	}{
+		{"within tolerance", Result{P95Ms: 219, ErrorRate: 0.011, Throughput: 91}, nil},
+		{"faster", Result{P95Ms: 150, ErrorRate: 0, Throughput: 130}, nil},
+		{"slower", Result{P95Ms: 221, ErrorRate: 0.002, Throughput: 100}, []string{"p95 latency (ms)"}},
+		{"more errors", Result{P95Ms: 200, ErrorRate: 0.02, Throughput: 100}, []string{"error rate"}},
+		{"everything", Result{P95Ms: 400, ErrorRate: 0.5, Throughput: 10},
+			[]string{"p95 latency (ms)", "error rate", "throughput (req/s)"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var metrics []string
+			for _, r := range Compare(baseline, tt.current, tol) {
+				metrics = append(metrics, r.Metric)
+			}
+			assert.Equal(t, tt.metrics, metrics)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+// Command openapi generates the OpenAPI document served at
+// /api/v1/openapi.json from the handler annotations, and the Go client in
+// pkg/client from the document.
+//
+// Usage:
+//
+//	openapi -root . -out internal/openapi/openapi.json -client pkg/client/api_gen.go
+//
+// It runs through go generate in internal/openapi; see make generate.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Tributary-ai-services/aether-be/internal/openapi"
+)
+
+func main() {
+	root := flag.String("root", ".", "repository root")
+	out := flag.String("out", "internal/openapi/openapi.json", "file to write the document to")
+	client := flag.String("client", "", "file to write the Go client to; empty skips the client")
+	flag.Parse()
+
+	doc, err := openapi.Generate(*root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openapi: %v\n", err)
+		os.Exit(1)
+	}
+	data, err := openapi.Marshal(doc)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openapi: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*out, data, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "openapi: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *client == "" {
+		return
+	}
+	source, err := openapi.GenerateClient(doc)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openapi: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*client, source, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "openapi: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -16,11 +17,21 @@ import (
 	"github.com/Tributary-ai-services/aether-be/internal/config"
 	"github.com/Tributary-ai-services/aether-be/internal/database"
 	"github.com/Tributary-ai-services/aether-be/internal/handlers"
+	"github.com/Tributary-ai-services/aether-be/internal/health"
 	"github.com/Tributary-ai-services/aether-be/internal/logger"
 	"github.com/Tributary-ai-services/aether-be/internal/metrics"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
 	"github.com/Tributary-ai-services/aether-be/internal/services"
 )
 
+// @title Aether API
+// @version 0.1.0
+// @description Backend API of the Aether AI platform. Space-scoped endpoints
+// @description read the space from the X-Space-Type and X-Space-ID headers.
+// @securityDefinitions.apikey Bearer
+// @in header
+// @name Authorization
+// @description Keycloak access token, sent as "Bearer <token>"
 func main() {
 	// Load configuration
 	cfg, err := config.Load()
@@ -30,8 +41,10 @@ func main() {
 
 	// Initialize logger
 	loggerConfig := logger.Config{
-		Level:  cfg.Logger.Level,
-		Format: cfg.Logger.Format,
+		Level:            cfg.Logger.Level,
+		Format:           cfg.Logger.Format,
+		DisableRedaction: !cfg.Logger.Redaction,
+		RedactFields:     cfg.Logger.RedactFields,
 	}
 	appLogger, err := logger.New(loggerConfig)
 	if err != nil {
@@ -59,6 +72,47 @@ func main() {
 	}
 	defer neo4jClient.Close(context.Background())
 
+	// Dependency health checks; critical ones gate readiness
+	healthRegistry := health.NewRegistry(health.DefaultCheckTimeout)
+	healthRegistry.Register("neo4j", true, neo4jClient.HealthCheck)
+
+	// Redis holds the leader lease shared by replicas, carries real-time
+	// events between them, queues notifications and webhook deliveries,
+	// keeps the rate limit buckets and idempotent responses, stores the
+	// search suggestion index and caches computed results; without it this
+	// instance assumes it runs alone
+	var lockStore services.LockStore
+	var pubSub services.PubSub
+	var queueStore services.QueueStore
+	var rateLimitStore middleware.RateLimitStore
+	var idempotencyStore middleware.IdempotencyStore
+	var suggestIndex services.SuggestIndex
+	var resultCache services.ResultCache
+	if cfg.Redis.Enabled {
+		redisClient, err := database.NewRedisClient(cfg.Redis, appLogger)
+		if err != nil {
+			appLogger.Error("Failed to initialize Redis client", zap.Error(err))
+		} else {
+			defer redisClient.Close()
+			healthRegistry.Register("redis", false, redisClient.HealthCheck)
+			lockStore = redisClient
+			pubSub = redisClient
+			queueStore = redisClient
+			rateLimitStore = redisClient
+			idempotencyStore = redisClient
+			suggestIndex = redisClient
+			resultCache = redisClient
+		}
+	}
+	if lockStore == nil {
+		// Every replica would elect itself leader and run singleton jobs
+		if cfg.Cluster.Replicas > 1 {
+			appLogger.Fatal("Multiple replicas require Redis for leader election",
+				zap.Int("replicas", cfg.Cluster.Replicas))
+		}
+		appLogger.Warn("Redis disabled - leader election is local, run a single replica only")
+	}
+
 	// Initialize external services
 	appLogger.Info("Initializing external services")
 
@@ -66,6 +120,7 @@ func main() {
 	if err != nil {
 		appLogger.Fatal("Failed to initialize Keycloak client", zap.Error(err))
 	}
+	healthRegistry.Register("keycloak", false, keycloakClient.HealthCheck)
 
 	var storageService *services.S3StorageService
 	if cfg.Storage.Enabled {
@@ -76,6 +131,7 @@ func main() {
 			storageService = nil // Explicitly set to nil for clarity
 		} else {
 			appLogger.Info("Storage service initialized successfully")
+			healthRegistry.Register("s3", true, storageService.HealthCheck)
 		}
 	} else {
 		appLogger.Info("Storage service disabled in configuration")
@@ -89,6 +145,21 @@ func main() {
 			// Don't fail startup, but log the error
 		} else {
 			appLogger.Info("Kafka service initialized successfully")
+			// Event publishing degrades gracefully, so Kafka does not gate readiness
+			healthRegistry.Register("kafka", false, kafkaService.HealthCheck)
+		}
+	}
+
+	// PostgreSQL only holds reporting read models; without it the API is
+	// unaffected
+	var postgresClient *database.PostgresClient
+	if cfg.Postgres.Enabled {
+		postgresClient, err = database.NewPostgresClient(cfg.Postgres, appLogger)
+		if err != nil {
+			appLogger.Error("Failed to initialize Postgres client - reporting projection disabled", zap.Error(err))
+		} else {
+			defer postgresClient.Close()
+			healthRegistry.Register("postgres", false, postgresClient.HealthCheck)
 		}
 	}
 
@@ -96,11 +167,44 @@ func main() {
 	if cfg.AudiModal.Enabled {
 		audiModalService = services.NewAudiModalService(cfg.AudiModal.BaseURL, cfg.AudiModal.APIKey, &cfg.AudiModal, appLogger)
 		appLogger.Info("AudiModal service initialized successfully")
+		healthRegistry.Register("audimodal", false, audiModalService.HealthCheck)
 	}
 
 	// Initialize metrics
 	appLogger.Info("Initializing metrics system")
 	metricsInstance := metrics.NewMetrics(appLogger)
+	metricsInstance.SetTenantLabeler(metrics.NewTenantLabeler(cfg.Monitoring.TenantAllowlist, cfg.Monitoring.TenantLabelLimit))
+	if cfg.SLO.Enabled {
+		latencyOverrides, _ := cfg.SLO.LatencyTargets() // validated by config.Load
+		metricsInstance.SetSLOTracker(metrics.NewSLOTracker(metrics.SLOObjectives{
+			WindowDays:         cfg.SLO.WindowDays,
+			AvailabilityTarget: cfg.SLO.AvailabilityTarget,
+			LatencyP95Ms:       cfg.SLO.LatencyP95Ms,
+			LatencyOverrides:   latencyOverrides,
+			ProcessingTarget:   cfg.SLO.ProcessingTarget,
+			FastBurnRate:       cfg.SLO.FastBurnRate,
+			SlowBurnRate:       cfg.SLO.SlowBurnRate,
+		}))
+	}
+	if cfg.Anomaly.Enabled {
+		metricsInstance.SetUsageAnomalyDetector(metrics.NewUsageAnomalyDetector(metrics.AnomalyThresholds{
+			Alpha:           cfg.Anomaly.Alpha,
+			ZThreshold:      cfg.Anomaly.ZThreshold,
+			WarmupIntervals: cfg.Anomaly.WarmupIntervals,
+			MaxTenants:      cfg.Anomaly.MaxTenants,
+			MinUploads:      cfg.Anomaly.MinUploads,
+			MinAgentCostUSD: cfg.Anomaly.MinAgentCostUSD,
+			MinRequests:     int64(cfg.Anomaly.MinRequests),
+		}))
+	}
+	neo4jClient.SetMetrics(metricsInstance)
+	keycloakClient.SetMetrics(metricsInstance)
+	if storageService != nil {
+		storageService.SetMetrics(metricsInstance)
+	}
+	if audiModalService != nil {
+		audiModalService.SetMetrics(metricsInstance)
+	}
 
 	// Initialize metrics collector
 	metricsCollector := metrics.NewMetricsCollector(
@@ -108,6 +212,7 @@ func main() {
 		neo4jClient,
 		appLogger,
 	)
+	metricsCollector.SetHealthRegistry(healthRegistry)
 
 	// Initialize API server
 	appLogger.Info("Initializing API server")
@@ -117,15 +222,24 @@ func main() {
 		keycloakClient,
 		storageService,
 		kafkaService,
+		postgresClient,
+		lockStore,
+		pubSub,
+		queueStore,
+		rateLimitStore,
+		idempotencyStore,
+		suggestIndex,
+		resultCache,
 		audiModalService,
 		metricsInstance,
+		healthRegistry,
 		appLogger,
 	)
 
 	// Create HTTP server
 	server := &http.Server{
 		Addr:         ":" + cfg.Server.Port,
-		Handler:      apiServer.Router,
+		Handler:      apiServer.Handler(),
 		ReadTimeout:  time.Duration(cfg.Server.ReadTimeout) * time.Second,
 		WriteTimeout: time.Duration(cfg.Server.WriteTimeout) * time.Second,
 		IdleTimeout:  time.Duration(cfg.Server.IdleTimeout) * time.Second,
@@ -149,10 +263,36 @@ func main() {
 		}
 	}()
 
+	// Start the internal gRPC server for sibling services
+	if apiServer.GRPC != nil {
+		lis, err := net.Listen("tcp", ":"+cfg.GRPC.Port)
+		if err != nil {
+			appLogger.Fatal("Failed to listen for gRPC", zap.Error(err))
+		}
+		go func() {
+			if err := apiServer.GRPC.Serve(lis); err != nil {
+				appLogger.Fatal("Failed to start gRPC server", zap.Error(err))
+			}
+		}()
+	}
+
+	// Reload runtime configuration on SIGHUP
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			appLogger.Info("Received SIGHUP, reloading runtime configuration")
+			if _, err := apiServer.RuntimeConfig.Reload(context.Background(), "system", services.ConfigSourceSignal); err != nil {
+				appLogger.Error("Runtime configuration reload failed", zap.Error(err))
+			}
+		}
+	}()
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
+	signal.Stop(reload)
 
 	appLogger.Info("Shutting down server...")
 
@@ -161,23 +301,29 @@ func main() {
 	metricsCollector.Stop()
 	appLogger.Info("Metrics collection stopped")
 
-	// Give outstanding requests 30 seconds to complete
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	// Every shutdown step below shares the configured grace period
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), time.Duration(cfg.Timeouts.ShutdownSeconds)*time.Second)
 	defer shutdownCancel()
 
-	// Shutdown HTTP server
+	// Stop accepting WebSocket connections and close open ones
+	apiServer.BeginDrain()
+
+	// Shutdown HTTP server, letting in-flight requests complete
 	if err := server.Shutdown(shutdownCtx); err != nil {
 		appLogger.Error("Server forced to shutdown", zap.Error(err))
 	}
+	if apiServer.GRPC != nil {
+		apiServer.GRPC.Shutdown(shutdownCtx)
+	}
 
-	// Shutdown API server (close external connections)
-	if err := apiServer.Shutdown(); err != nil {
+	// Drain stream events and wait for background workers
+	if err := apiServer.Shutdown(shutdownCtx); err != nil {
 		appLogger.Error("Error during API server shutdown", zap.Error(err))
 	}
 
-	// Close Kafka service
+	// Finish in-flight Kafka messages and flush the producer
 	if kafkaService != nil {
-		if err := kafkaService.Close(); err != nil {
+		if err := kafkaService.Shutdown(shutdownCtx); err != nil {
 			appLogger.Error("Error closing Kafka service", zap.Error(err))
 		}
 	}
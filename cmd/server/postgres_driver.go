@@ -0,0 +1,8 @@
+//go:build postgres
+
+package main
+
+// The reporting projection talks to PostgreSQL through database/sql. The
+// driver is only linked into binaries built with -tags postgres so the
+// default build carries no PostgreSQL dependency.
+import _ "github.com/jackc/pgx/v5/stdlib"
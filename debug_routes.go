@@ -14,6 +14,7 @@ import (
 	"github.com/Tributary-ai-services/aether-be/internal/config"
 	"github.com/Tributary-ai-services/aether-be/internal/database"
 	"github.com/Tributary-ai-services/aether-be/internal/handlers"
+	"github.com/Tributary-ai-services/aether-be/internal/health"
 	"github.com/Tributary-ai-services/aether-be/internal/logger"
 	"github.com/Tributary-ai-services/aether-be/internal/metrics"
 )
@@ -80,8 +81,17 @@ func main() {
 		keycloakClient,
 		nil, // storage service
 		nil, // kafka service
+		nil, // postgres
+		nil, // lock store
+		nil, // pub/sub
+		nil, // queue store
+		nil, // rate limit store
+		nil, // idempotency store
+		nil, // suggestion index
+		nil, // result cache
 		nil, // audimodal service
 		metricsInstance,
+		health.NewRegistry(health.DefaultCheckTimeout),
 		appLogger,
 	)
 
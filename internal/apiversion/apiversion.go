@@ -0,0 +1,250 @@
+// Package apiversion negotiates the API version of a request.
+//
+// A request names its version in the path (/api/v2/notebooks) or, on paths
+// without one (/api/notebooks), in the API-Version header; requests naming
+// neither get the default version. A version only registers the routes that
+// changed in it: requests for other routes are served by the newest earlier
+// version that has them, so /api/v2/users/me is answered by the v1 handler
+// until v2 registers its own.
+package apiversion
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Header is the request and response header carrying the API version
+const Header = "API-Version"
+
+// pathPrefix precedes the version in API paths
+const pathPrefix = "/api/"
+
+// Version is one API version and its lifecycle
+type Version struct {
+	Name       string    // "v1", "v2", ...
+	Deprecated time.Time // When the version was or will be deprecated; zero while it is not
+	Sunset     time.Time // When the version stops being served; zero if not scheduled
+	Link       string    // Migration guide sent to clients of a deprecated version
+}
+
+// Gone reports whether the version is past its sunset date
+func (v Version) Gone(now time.Time) bool {
+	return !v.Sunset.IsZero() && !now.Before(v.Sunset)
+}
+
+// Set is the versions the API serves. Register every route before serving;
+// a Set is not safe for concurrent registration.
+type Set struct {
+	versions       []Version // Oldest first
+	defaultVersion string
+	routes         map[string][]route // By version name
+}
+
+type route struct {
+	method   string
+	segments []string
+}
+
+// NewSet creates a set of versions. Requests that do not name a version get
+// defaultVersion, or the oldest version if it is not in the set.
+func NewSet(defaultVersion string, versions ...Version) *Set {
+	s := &Set{
+		versions: append([]Version(nil), versions...),
+		routes:   make(map[string][]route),
+	}
+	sort.Slice(s.versions, func(i, j int) bool {
+		return number(s.versions[i].Name) < number(s.versions[j].Name)
+	})
+	s.defaultVersion = defaultVersion
+	if _, ok := s.Lookup(defaultVersion); !ok && len(s.versions) > 0 {
+		s.defaultVersion = s.versions[0].Name
+	}
+	return s
+}
+
+// Deprecate schedules the deprecation and sunset of a version
+func (s *Set) Deprecate(name string, deprecated, sunset time.Time, link string) error {
+	for i := range s.versions {
+		if s.versions[i].Name == name {
+			s.versions[i].Deprecated = deprecated
+			s.versions[i].Sunset = sunset
+			s.versions[i].Link = link
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown API version %q", name)
+}
+
+// Lookup returns the named version
+func (s *Set) Lookup(name string) (Version, bool) {
+	for _, v := range s.versions {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return Version{}, false
+}
+
+// Default returns the version of requests that do not name one
+func (s *Set) Default() string {
+	return s.defaultVersion
+}
+
+// Names returns the version names, oldest first
+func (s *Set) Names() []string {
+	names := make([]string, len(s.versions))
+	for i, v := range s.versions {
+		names[i] = v.Name
+	}
+	return names
+}
+
+// Register records a route as served by the version in its path. Paths
+// use the router's syntax (:param, *rest); routes outside /api/<version>/
+// are ignored.
+func (s *Set) Register(method, path string) {
+	version, rest, ok := splitPath(path)
+	if !ok || version == "" {
+		return
+	}
+	if _, known := s.Lookup(version); !known {
+		return
+	}
+	s.routes[version] = append(s.routes[version], route{method: method, segments: segments(rest)})
+}
+
+type requestedKey struct{}
+
+// Requested returns the version a request asked for, which Handler stores
+// on API requests. The version is not necessarily in the set.
+func Requested(ctx context.Context) (string, bool) {
+	version, ok := ctx.Value(requestedKey{}).(string)
+	return version, ok
+}
+
+// Handler negotiates the version of API requests before next routes them.
+// The requested version is stored on the request context and the path is
+// rewritten to the version that serves the route. Paths of unknown
+// versions are left alone; next decides how to answer them.
+func (s *Set) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version, rest, ok := splitPath(r.URL.Path)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if version == "" {
+			version = normalize(r.Header.Get(Header))
+			if version == "" {
+				version = s.defaultVersion
+			}
+		}
+
+		r = r.WithContext(context.WithValue(r.Context(), requestedKey{}, version))
+		if resolved := s.resolve(version, r.Method, rest); resolved != "" {
+			u := *r.URL
+			u.Path = pathPrefix + resolved + rest
+			u.RawPath = ""
+			r.URL = &u
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// resolve returns the newest version up to the requested one that serves
+// the route, the requested version if none does, or "" if it is unknown
+func (s *Set) resolve(version, method, rest string) string {
+	index := -1
+	for i, v := range s.versions {
+		if v.Name == version {
+			index = i
+		}
+	}
+	if index < 0 {
+		return ""
+	}
+
+	path := segments(rest)
+	for i := index; i >= 0; i-- {
+		for _, rt := range s.routes[s.versions[i].Name] {
+			if rt.method == method && rt.matches(path) {
+				return s.versions[i].Name
+			}
+		}
+	}
+	return version
+}
+
+func (rt route) matches(path []string) bool {
+	for i, segment := range rt.segments {
+		if strings.HasPrefix(segment, "*") {
+			return true
+		}
+		if i >= len(path) {
+			return false
+		}
+		if strings.HasPrefix(segment, ":") {
+			if path[i] == "" {
+				return false
+			}
+			continue
+		}
+		if segment != path[i] {
+			return false
+		}
+	}
+	return len(path) == len(rt.segments)
+}
+
+// splitPath splits an API path into its version, "" if the path names
+// none, and the rest of the path
+func splitPath(path string) (version, rest string, ok bool) {
+	if !strings.HasPrefix(path, pathPrefix) {
+		return "", "", false
+	}
+	after := path[len(pathPrefix):]
+	first, remainder, found := strings.Cut(after, "/")
+	if number(first) < 0 {
+		return "", "/" + after, true
+	}
+	if found {
+		rest = "/" + remainder
+	}
+	return first, rest, true
+}
+
+func segments(rest string) []string {
+	if rest == "" || rest == "/" {
+		return nil
+	}
+	return strings.Split(strings.TrimPrefix(rest, "/"), "/")
+}
+
+// normalize accepts a version header as "v2" or "2"
+func normalize(header string) string {
+	header = strings.ToLower(strings.TrimSpace(header))
+	if header == "" {
+		return ""
+	}
+	if !strings.HasPrefix(header, "v") {
+		header = "v" + header
+	}
+	return header
+}
+
+// number returns the number of a version name, or -1 if it is not one
+func number(name string) int {
+	if len(name) < 2 || name[0] != 'v' {
+		return -1
+	}
+	n, err := strconv.Atoi(name[1:])
+	if err != nil || n < 0 {
+		return -1
+	}
+	return n
+}
@@ -0,0 +1,92 @@
+package apiversion
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerResolvesVersions(t *testing.T) {
+	set := NewSet("v1", Version{Name: "v2"}, Version{Name: "v1"})
+	set.Register(http.MethodGet, "/api/v1/notebooks")
+	set.Register(http.MethodGet, "/api/v1/notebooks/:id")
+	set.Register(http.MethodGet, "/api/v1/files/*path")
+	set.Register(http.MethodPost, "/api/v1/notebooks")
+	set.Register(http.MethodGet, "/api/v2/notebooks")
+	set.Register(http.MethodGet, "/health")
+
+	var path, requested string
+	handler := set.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		requested, _ = Requested(r.Context())
+	}))
+	serve := func(method, target, header string) {
+		path, requested = "", ""
+		req := httptest.NewRequest(method, target, nil)
+		if header != "" {
+			req.Header.Set(Header, header)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	tests := []struct {
+		name      string
+		method    string
+		target    string
+		header    string
+		path      string
+		requested string
+	}{
+		{"v1 route", http.MethodGet, "/api/v1/notebooks", "", "/api/v1/notebooks", "v1"},
+		{"v2 override", http.MethodGet, "/api/v2/notebooks", "", "/api/v2/notebooks", "v2"},
+		{"v2 falls back by method", http.MethodPost, "/api/v2/notebooks", "", "/api/v1/notebooks", "v2"},
+		{"v2 falls back by param", http.MethodGet, "/api/v2/notebooks/42", "", "/api/v1/notebooks/42", "v2"},
+		{"v2 falls back by wildcard", http.MethodGet, "/api/v2/files/a/b", "", "/api/v1/files/a/b", "v2"},
+		{"unmatched route keeps version", http.MethodGet, "/api/v2/missing", "", "/api/v2/missing", "v2"},
+		{"unversioned uses default", http.MethodGet, "/api/notebooks", "", "/api/v1/notebooks", "v1"},
+		{"unversioned uses header", http.MethodGet, "/api/notebooks", "2", "/api/v2/notebooks", "v2"},
+		{"path wins over header", http.MethodGet, "/api/v1/notebooks", "v2", "/api/v1/notebooks", "v1"},
+		{"unknown version untouched", http.MethodGet, "/api/v9/notebooks", "", "/api/v9/notebooks", "v9"},
+		{"unknown header untouched", http.MethodGet, "/api/notebooks", "beta", "/api/notebooks", "vbeta"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serve(tt.method, tt.target, tt.header)
+			assert.Equal(t, tt.path, path)
+			assert.Equal(t, tt.requested, requested)
+		})
+	}
+
+	// Requests outside the API are not negotiated
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, "/health", path)
+	_, ok := Requested(req.Context())
+	assert.False(t, ok)
+}
+
+func TestSet(t *testing.T) {
+	set := NewSet("v3", Version{Name: "v2"}, Version{Name: "v1"})
+	assert.Equal(t, []string{"v1", "v2"}, set.Names())
+	assert.Equal(t, "v1", set.Default(), "unknown defaults fall back to the oldest version")
+
+	require.NoError(t, set.Deprecate("v1", testTime(2026, 1), testTime(2026, 6), "https://example.com/migrate"))
+	v1, ok := set.Lookup("v1")
+	require.True(t, ok)
+	assert.Equal(t, "https://example.com/migrate", v1.Link)
+	assert.False(t, v1.Gone(testTime(2026, 5)))
+	assert.True(t, v1.Gone(testTime(2026, 6)))
+
+	v2, _ := set.Lookup("v2")
+	assert.False(t, v2.Gone(testTime(2100, 1)), "versions without a sunset are never gone")
+
+	assert.Error(t, set.Deprecate("v7", testTime(2026, 1), testTime(2026, 6), ""))
+}
+
+func testTime(year int, month time.Month) time.Time {
+	return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+}
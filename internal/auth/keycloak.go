@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"fmt"
+	"net/http"
 
 	"github.com/coreos/go-oidc/v3/oidc"
 	"go.uber.org/zap"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/Tributary-ai-services/aether-be/internal/config"
 	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/metrics"
 )
 
 // KeycloakClient handles Keycloak OIDC authentication
@@ -17,6 +19,8 @@ type KeycloakClient struct {
 	provider      *oidc.Provider
 	verifier      *oidc.IDTokenVerifier
 	oauth2Config  *oauth2.Config
+	httpClient    *http.Client
+	transport     *metrics.ExternalTransport
 	logger        *logger.Logger
 	config        config.KeycloakConfig
 	allowedIssuers []string  // Support multiple issuer URLs for dev/prod environments
@@ -61,7 +65,11 @@ type RealmAccess struct {
 
 // NewKeycloakClient creates a new Keycloak client
 func NewKeycloakClient(cfg config.KeycloakConfig, log *logger.Logger) (*KeycloakClient, error) {
-	ctx := context.Background()
+	// The provider keeps this client for discovery and key fetches; other
+	// calls pass it through clientContext
+	transport := metrics.NewExternalTransport("keycloak", nil, metrics.ExternalTransportOptions{})
+	httpClient := &http.Client{Transport: transport}
+	ctx := oidc.ClientContext(context.Background(), httpClient)
 
 	// Construct provider URL
 	providerURL := fmt.Sprintf("%s/realms/%s", cfg.URL, cfg.Realm)
@@ -134,6 +142,8 @@ func NewKeycloakClient(cfg config.KeycloakConfig, log *logger.Logger) (*Keycloak
 		provider:       provider,
 		verifier:       verifier,
 		oauth2Config:   oauth2Config,
+		httpClient:     httpClient,
+		transport:      transport,
 		logger:         log.WithService("keycloak"),
 		config:         cfg,
 		allowedIssuers: allowedIssuers,
@@ -201,7 +211,7 @@ func (k *KeycloakClient) GetUserInfo(ctx context.Context, accessToken string) (*
 	}
 
 	// Get user info from provider
-	userInfo, err := k.provider.UserInfo(ctx, oauth2.StaticTokenSource(token))
+	userInfo, err := k.provider.UserInfo(k.clientContext(ctx), oauth2.StaticTokenSource(token))
 	if err != nil {
 		k.logger.Error("Failed to get user info", zap.Error(err))
 		return nil, fmt.Errorf("failed to get user info: %w", err)
@@ -257,7 +267,7 @@ func (k *KeycloakClient) ExchangeCode(ctx context.Context, code string, redirect
 	config.RedirectURL = redirectURL
 
 	// Exchange code for token
-	token, err := config.Exchange(ctx, code)
+	token, err := config.Exchange(k.clientContext(ctx), code)
 	if err != nil {
 		k.logger.Error("Failed to exchange authorization code", zap.Error(err))
 		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
@@ -274,7 +284,7 @@ func (k *KeycloakClient) RefreshToken(ctx context.Context, refreshToken string)
 		RefreshToken: refreshToken,
 	}
 
-	tokenSource := k.oauth2Config.TokenSource(ctx, token)
+	tokenSource := k.oauth2Config.TokenSource(k.clientContext(ctx), token)
 
 	// Get new token
 	newToken, err := tokenSource.Token()
@@ -335,10 +345,21 @@ func (k *KeycloakClient) HealthCheck(ctx context.Context) error {
 	// Try to get provider configuration
 	providerURL := fmt.Sprintf("%s/realms/%s", k.config.URL, k.config.Realm)
 
-	_, err := oidc.NewProvider(ctx, providerURL)
+	_, err := oidc.NewProvider(k.clientContext(ctx), providerURL)
 	if err != nil {
 		return fmt.Errorf("keycloak health check failed: %w", err)
 	}
 
 	return nil
 }
+
+// SetMetrics records metrics for calls to Keycloak
+func (k *KeycloakClient) SetMetrics(m *metrics.Metrics) {
+	k.transport.SetMetrics(m)
+}
+
+// clientContext makes OIDC and OAuth2 calls made with ctx use the
+// instrumented HTTP client
+func (k *KeycloakClient) clientContext(ctx context.Context) context.Context {
+	return oidc.ClientContext(ctx, k.httpClient)
+}
@@ -2,29 +2,76 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+
+	"github.com/Tributary-ai-services/aether-be/internal/cron"
+	"github.com/Tributary-ai-services/aether-be/internal/fieldcrypt"
+	"github.com/Tributary-ai-services/aether-be/internal/filetype"
 )
 
 // Config holds all configuration for the application
 type Config struct {
-	Server     ServerConfig
-	Neo4j      DatabaseConfig
-	Redis      RedisConfig
-	Keycloak   KeycloakConfig
-	Storage    StorageConfig
-	Kafka      KafkaConfig
-	Monitoring MonitoringConfig
-	Logger     LoggingConfig
-	AudiModal  AudiModalConfig
-	Embedding  EmbeddingConfig
-	DeepLake   DeepLakeConfig
-	OpenAI     OpenAIConfig
-	Compliance ComplianceConfig
-	Router     RouterConfig
+	Server      ServerConfig
+	Neo4j       DatabaseConfig
+	Redis       RedisConfig
+	Keycloak    KeycloakConfig
+	Storage     StorageConfig
+	Kafka       KafkaConfig
+	Monitoring  MonitoringConfig
+	Logger      LoggingConfig
+	AudiModal   AudiModalConfig
+	Embedding   EmbeddingConfig
+	DeepLake    DeepLakeConfig
+	OpenAI      OpenAIConfig
+	Compliance  ComplianceConfig
+	Router      RouterConfig
+	Timeouts    TimeoutConfig
+	Cluster     ClusterConfig
+	Scheduler   SchedulerConfig
+	Postgres    PostgresConfig
+	BodyLimits  BodyLimitConfig
+	AccessLog   AccessLogConfig
+	SLO         SLOConfig
+	Anomaly     AnomalyConfig
+	Synthetic   SyntheticConfig
+	GraphQL     GraphQLConfig
+	GRPC        GRPCConfig
+	Jobs        JobsConfig
+	Webhooks    WebhooksConfig
+	Feeds       FeedsConfig
+	API         APIVersionConfig
+	QA          QAConfig
+	Summary     SummaryConfig
+	ChunkSync   ChunkSyncConfig
+	Encryption  TextEncryptionConfig
+	Moderation  ModerationConfig
+	Scanner     ScannerConfig
+	Uploads     FileValidationConfig
+	Trash       TrashConfig
+	Email       InboundEmailConfig
+	S3Watch     S3WatchConfig
+	Reports     ReportsConfig
+	Maintenance MaintenanceConfig
+	Quotas      QuotaConfig
+	Compression CompressionConfig
+	Concurrency ConcurrencyConfig
+	Region      RegionConfig
+	Roles       RolesConfig
+	Queue       DeliveryQueueConfig
+	Idempotency IdempotencyConfig
+	Priority    ProcessingPriorityConfig
+	Compare     CompareConfig
+	Downloads   DownloadConfig
+
+	// Runtime holds settings that can be reloaded without a restart
+	Runtime RuntimeConfig
 }
 
 // ServerConfig holds server-specific configuration
@@ -37,6 +84,8 @@ type ServerConfig struct {
 	ReadTimeout  int
 	WriteTimeout int
 	IdleTimeout  int
+
+	ConfigReloadFile string // Env file re-read on SIGHUP or admin reload
 }
 
 // DatabaseConfig holds Neo4j database configuration
@@ -47,10 +96,29 @@ type DatabaseConfig struct {
 	Database    string
 	MaxConns    int
 	TLSInsecure bool
+
+	QueryTimeoutSeconds int // Default deadline for a single query/transaction; 0 disables
+	BatchSize           int // Rows per UNWIND transaction for bulk writes
+	StreamFetchSize     int // Records pulled per round trip when streaming large results
+
+	// Connection pool and backpressure
+	ConnAcquisitionTimeoutSeconds int // Driver wait for a pooled connection
+	MaxConnLifetimeMinutes        int // Connections older than this are replaced
+	PoolMaxQueue                  int // Operations allowed to wait for a connection before shedding
+	PoolWaitTimeoutMs             int // How long a queued operation waits before it is shed
+
+	// Slow query detection
+	SlowQueryThresholdMs int  // Queries slower than this are logged; 0 disables
+	ProfileSlowQueries   bool // Re-run read-only slow queries with PROFILE
+
+	// Traversal guardrails for relationship-heavy endpoints
+	MaxTraversalDepth int // Deepest variable-length traversal a request may ask for
+	MaxEstimatedRows  int // Planner row estimate above which a traversal is refused; 0 disables
 }
 
 // RedisConfig holds Redis configuration
 type RedisConfig struct {
+	Enabled  bool
 	Addr     string
 	Password string
 	DB       int
@@ -87,12 +155,16 @@ type KafkaConfig struct {
 type MonitoringConfig struct {
 	PrometheusEnabled bool
 	OTELEndpoint      string
+	TenantAllowlist   []string // Tenants labelled individually in tenant metrics; empty labels the first TenantLabelLimit seen
+	TenantLabelLimit  int      // Most tenants labelled individually when no allowlist is set; others share "other"
 }
 
 // LoggingConfig holds logging configuration
 type LoggingConfig struct {
-	Level  string
-	Format string
+	Level        string
+	Format       string
+	Redaction    bool     // Scrub credentials, document text, emails, file names and storage keys
+	RedactFields []string // Further field keys whose values are never logged
 }
 
 // AudiModalConfig holds AudiModal API configuration
@@ -108,6 +180,14 @@ type AudiModalConfig struct {
 	WebhookSecret        string
 	MaxConcurrentFiles   int
 	ChunkSizeLimit       int
+	// DefaultTenantUUID and DefaultDataSourceUUID name a shared AudiModal
+	// tenant for tenants without one of their own, instead of provisioning
+	// one; both must be set
+	DefaultTenantUUID     string
+	DefaultDataSourceUUID string
+	// OCRLanguagePacks are the Tesseract language packs installed with
+	// AudiModal's OCR fallback; spaces can enable only these
+	OCRLanguagePacks []string
 }
 
 // EmbeddingConfig holds embedding service configuration
@@ -127,7 +207,8 @@ type DeepLakeConfig struct {
 	VectorDimensions  int
 	TimeoutSeconds    int
 	Enabled           bool
-	UseDefaultDataset bool // Feature flag: use "default" dataset instead of notebook-specific datasets
+	UseDefaultDataset bool   // Feature flag: use "default" dataset instead of notebook-specific datasets
+	SpaceDataset      string // Dataset searched in each space's namespace by semantic search
 }
 
 // OpenAIConfig holds OpenAI API configuration
@@ -154,6 +235,678 @@ type ComplianceConfig struct {
 	EncryptSensitive    bool
 }
 
+// TimeoutConfig holds default per-operation timeouts
+type TimeoutConfig struct {
+	ExternalHTTPSeconds  int // Outbound calls to sibling services; default for per-service timeouts
+	BackgroundJobMinutes int // Scheduled retries and other background jobs
+	ShutdownSeconds      int // Grace period for in-flight work on shutdown
+}
+
+// ClusterConfig holds settings for running several replicas side by side
+type ClusterConfig struct {
+	InstanceID         string // Unique per replica; defaults to the hostname (pod name)
+	Replicas           int    // Number of replicas deployed; more than one requires Redis
+	LeaderLeaseSeconds int    // How long a leader holds the lease without renewing
+}
+
+// SchedulerConfig holds the schedules of background jobs run by the leader.
+// Schedules are cron expressions evaluated in UTC, or "@every <duration>".
+type SchedulerConfig struct {
+	HistoryLimit        int    // Runs kept per job in the run history
+	ProcessingRetries   string // Picks up due processing retries
+	CountReconciliation string // Repairs drifted notebook counters
+	SyntheticProbes     string // Exercises critical API paths when synthetic monitoring is enabled
+	JobCleanup          string // Fails interrupted async jobs and deletes expired ones
+	VectorSync          string // Pushes chunk embeddings of processed documents to DeepLake
+	UploadCleanup       string // Aborts expired resumable and direct uploads
+	DocumentDeletions   string // Retries removing deleted documents' copies from AudiModal, vectors and storage
+	Summaries           string // Summarizes processed documents when automatic summaries are enabled
+	TrashPurge          string // Permanently deletes documents and notebooks kept in the trash past retention
+	S3Watchers          string // Ingests new objects of the S3 prefixes notebooks watch
+	Reports             string // Generates and delivers the scheduled reports of spaces that are due
+	WebhookDeliveries   string // Sends due outbound webhook deliveries and retries failed ones
+	RelatedDocuments    string // Links processed and updated documents to their most similar ones
+	ChunkSync           string // Mirrors the chunks of processed documents into Neo4j when chunk sync is enabled
+	Moderation          string // Scans the queued documents of public notebooks when moderation is enabled
+	NotebookDigests     string // Sends the notebook digests whose period has ended
+}
+
+// Schedules returns the configured schedule of every job by job name
+func (c SchedulerConfig) Schedules() map[string]string {
+	return map[string]string{
+		"processing_retries":            c.ProcessingRetries,
+		"notebook_count_reconciliation": c.CountReconciliation,
+		"synthetic_probes":              c.SyntheticProbes,
+		"job_cleanup":                   c.JobCleanup,
+		"vector_sync":                   c.VectorSync,
+		"upload_cleanup":                c.UploadCleanup,
+		"document_deletions":            c.DocumentDeletions,
+		"trash_purge":                   c.TrashPurge,
+		"document_summaries":            c.Summaries,
+		"s3_watchers":                   c.S3Watchers,
+		"space_reports":                 c.Reports,
+		"webhook_deliveries":            c.WebhookDeliveries,
+		"related_documents":             c.RelatedDocuments,
+		"chunk_sync":                    c.ChunkSync,
+		"moderation":                    c.Moderation,
+		"notebook_digests":              c.NotebookDigests,
+	}
+}
+
+// JobsConfig holds async jobs: batches, exports and cascading deletes that
+// run in the background while clients poll /api/v1/jobs/{id}
+type JobsConfig struct {
+	MaxConcurrent      int // Jobs run at once on each replica; others wait queued
+	RetentionHours     int // Finished jobs and their results are kept this long
+	MaxBatchOperations int // Operations accepted by one POST /api/v1/batch
+}
+
+// WebhooksConfig holds inbound webhooks and the outbound webhooks spaces
+// subscribe to. Each inbound integration verifies deliveries with its own
+// secret; AudiModal's is AudiModalConfig.WebhookSecret.
+type WebhooksConfig struct {
+	ToleranceSeconds int    // Deliveries signed further from now are rejected
+	StripeSecret     string // Stripe endpoint signing secret (whsec_...)
+	StreamSecret     string // Secret of stream source webhooks
+	ProcessingSecret string // Secret of processing callbacks, for deployments without Kafka
+
+	DeliveryAttempts    int  // Attempts of an outbound delivery before it is given up
+	DeliveryTimeoutSecs int  // Time a subscriber has to answer one attempt
+	AllowPrivateTargets bool // Outbound webhooks may reach private and loopback addresses; for development only
+}
+
+// FeedsConfig holds the token-authenticated RSS, Atom and iCal feeds
+type FeedsConfig struct {
+	BaseURL  string // Public URL feed links are built from; defaults to the request's host
+	MaxItems int    // Entries in an activity feed
+}
+
+// QAConfig holds notebook question answering, which answers from a
+// notebook's chunks through the LLM router without an agent
+type QAConfig struct {
+	Model         string // Model the router is asked to answer with
+	Provider      string // Provider the router is asked to use
+	MaxTokens     int    // Longest answer
+	ContextChunks int    // Chunks retrieved as context for an answer
+}
+
+// SummaryConfig holds document abstracts and notebook digests, written
+// through the LLM router and cached on the Document and Notebook nodes
+type SummaryConfig struct {
+	AutoEnabled        bool   // Summarize documents once processed; needs router service auth
+	Model              string // Model the router is asked to summarize with
+	Provider           string // Provider the router is asked to use
+	MaxTokens          int    // Longest summary
+	InputChars         int    // Text of a document, or of a notebook's summaries, sent to the model
+	MonthlyTokenBudget int    // Tokens a space may spend on summaries per calendar month; 0 is unlimited
+	BatchSize          int    // Documents summarized per scheduled run
+}
+
+// ChunkSyncConfig holds the mirroring of processed documents' chunks from
+// AudiModal into Neo4j, as Chunk nodes answers can cite without a request
+// to AudiModal
+type ChunkSyncConfig struct {
+	Enabled      bool // Mirror the chunks of documents once processed
+	StoreContent bool // Keep the text of chunks; without it only their metadata is mirrored
+	MaxChunks    int  // Chunks mirrored per document; later chunks stay in AudiModal only
+	BatchSize    int  // Documents synced per scheduled run
+}
+
+// TextEncryptionConfig holds the encryption at rest of the extracted text
+// of documents in spaces that turn it on. Each tenant's key is derived from
+// MasterKey; without it no space can turn encryption on.
+type TextEncryptionConfig struct {
+	MasterKey string // Base64 of a 32 byte key
+	BatchSize int    // Documents encrypted or decrypted per query when a space's setting changes
+}
+
+// ModerationConfig holds the content moderation of public notebooks. Their
+// documents are held from the space until Provider has scanned them, and
+// their names and descriptions are scanned as they are saved.
+type ModerationConfig struct {
+	Enabled        bool
+	Provider       string   // "keywords", or "router" to ask a model; the router needs service auth
+	BlockedTerms   []string // Words and phrases the keywords provider blocks
+	FlaggedTerms   []string // Words and phrases the keywords provider flags for review
+	Model          string   // Model the router is asked to moderate with
+	RouterProvider string   // Provider the router is asked to use
+	InputChars     int      // Text of a document sent to the provider
+	BatchSize      int      // Documents scanned per scheduled run
+}
+
+// ScannerConfig holds the malware scanning of uploaded files. Files
+// uploaded to spaces that scan are sent to a ClamAV daemon or an ICAP
+// server before they are stored; infected files are rejected and kept in
+// QuarantineBucket.
+type ScannerConfig struct {
+	Provider         string // "clamav" or "icap"; scanning is off when empty
+	Address          string // host:port of clamd or of the ICAP server
+	ICAPService      string // Service the ICAP server scans with, such as "avscan"
+	TimeoutSeconds   int
+	ScanByDefault    bool   // Scan the uploads of spaces that have not chosen
+	FailOpen         bool   // Store files the scanner fails on rather than rejecting them
+	QuarantineBucket string // Bucket infected files are kept in, out of tenants' buckets
+}
+
+// FileValidationConfig holds the checks of uploaded files. Their type is
+// sniffed from their content and must agree with their extension and the
+// MIME type the client declares.
+type FileValidationConfig struct {
+	Validate     bool     // Sniff and check uploaded files; the client's MIME type is trusted when false
+	AllowedTypes []string // MIME types, or types such as "image/*", accepted everywhere; any when empty
+}
+
+// InboundEmailConfig holds email-to-notebook ingestion. Amazon SES receives
+// the mail of Domain and publishes it to the SNS topic SNSTopicARN, which
+// delivers it to /webhooks/email.
+type InboundEmailConfig struct {
+	Domain             string // Domain of notebook addresses; ingestion is off when empty
+	SNSTopicARN        string // Deliveries from other topics are rejected
+	MaxMessageBytes    int64  // Larger messages are rejected; SES publishes at most 150KB to SNS
+	MinAttachmentBytes int64  // Smaller attachments, such as signature logos, are skipped
+}
+
+// S3WatchConfig holds S3 bucket watchers, which ingest the new objects of
+// customers' S3 prefixes into notebooks. Customers let PrincipalARN assume
+// a role in their account, using the external ID of their tenant.
+type S3WatchConfig struct {
+	Enabled          bool   // Watchers can be created and are run
+	PrincipalARN     string // AWS principal this deployment runs as, shown to customers for their role's trust policy
+	MaxObjectsPerRun int    // Objects one watcher ingests per scheduled run; the rest wait for the next
+}
+
+// ReportsConfig holds scheduled space reports. Reports are stored in the
+// tenant's bucket and announced to users; they are also emailed when an
+// SMTP server is configured.
+type ReportsConfig struct {
+	SMTPAddr     string // host:port of the SMTP server; reports are not emailed when empty
+	SMTPUsername string // PLAIN authentication, over TLS only; none when empty
+	SMTPPassword string
+	From         string // Sender address of report emails
+}
+
+// TrashConfig holds the trash, where deleted documents and notebooks stay
+// restorable until they are purged
+type TrashConfig struct {
+	RetentionDays int // Trashed items are purged this long after deletion
+}
+
+// MaintenanceConfig holds maintenance mode, in which writes are rejected
+// system-wide or for one tenant while reads keep working
+type MaintenanceConfig struct {
+	RefreshSeconds int // How often each replica re-reads the maintenance windows set through another
+}
+
+// RolesConfig holds the custom roles organizations define on top of the
+// built-in ones
+type RolesConfig struct {
+	RefreshSeconds int // How often each replica re-reads the custom roles changed through another
+}
+
+// DeliveryQueueConfig holds the queue notifications and webhook deliveries
+// are handed to. With Redis the queue is a stream read by every replica;
+// without it jobs are handled in-process.
+type DeliveryQueueConfig struct {
+	Workers          int   // Jobs each replica handles at a time
+	ClaimIdleSeconds int   // How long a job handed to a replica that stopped answering waits before another takes it
+	MaxDeliveries    int   // Times a job is handed out before it is dropped
+	MaxLength        int64 // Jobs the stream holds at most; the oldest are trimmed
+}
+
+// IdempotencyConfig holds the responses kept for POST requests sent with
+// an Idempotency-Key header, which retries of the request are answered with
+type IdempotencyConfig struct {
+	TTLSeconds       int // How long a response is kept for retries
+	MaxResponseBytes int // Larger responses are not kept, so retries run again
+}
+
+// Processing priority tiers, lowest first. Each maps to an AudiModal
+// processing tier of the same name and to a level of the internal queues.
+const (
+	PriorityLow      = "low"
+	PriorityNormal   = "normal"
+	PriorityHigh     = "high"
+	PriorityRealtime = "realtime"
+)
+
+// ProcessingPriorities lists the processing priority tiers, lowest first
+var ProcessingPriorities = []string{PriorityLow, PriorityNormal, PriorityHigh, PriorityRealtime}
+
+// PriorityRank returns the position of a tier in ProcessingPriorities, -1
+// for an unknown tier
+func PriorityRank(tier string) int {
+	for i, known := range ProcessingPriorities {
+		if known == tier {
+			return i
+		}
+	}
+	return -1
+}
+
+// ProcessingPriorityConfig holds the priority documents are processed
+// with. Uploads and reprocessing may ask for a tier up to the cap of their
+// space's billing plan.
+type ProcessingPriorityConfig struct {
+	Default  string // Tier of submissions that do not ask for one, lowered to the plan's cap
+	PlanCaps string // Highest tier per billing plan, as plan=tier pairs; "default" covers other plans
+}
+
+// Caps returns the highest tier of each billing plan
+func (c ProcessingPriorityConfig) Caps() (map[string]string, error) {
+	caps := make(map[string]string)
+	if strings.TrimSpace(c.PlanCaps) == "" {
+		return caps, nil
+	}
+	for _, pair := range strings.Split(c.PlanCaps, ",") {
+		plan, tier, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		plan = strings.ToLower(strings.TrimSpace(plan))
+		tier = strings.ToLower(strings.TrimSpace(tier))
+		if !ok || plan == "" || PriorityRank(tier) < 0 {
+			return nil, fmt.Errorf("invalid priority cap %q, expected plan=tier", pair)
+		}
+		caps[plan] = tier
+	}
+	return caps, nil
+}
+
+// Cap returns the highest tier a billing plan may ask for: the plan's own
+// cap, else the cap of DefaultProcessingPlan, else the highest tier
+func (c ProcessingPriorityConfig) Cap(plan string) string {
+	caps, _ := c.Caps()
+	if tier, ok := caps[strings.ToLower(plan)]; ok {
+		return tier
+	}
+	if tier, ok := caps[DefaultProcessingPlan]; ok {
+		return tier
+	}
+	return PriorityRealtime
+}
+
+// CompareConfig holds the comparison of the extracted text of two
+// documents, or two versions of one
+type CompareConfig struct {
+	MaxTextBytes int // Largest text either side of a comparison may have
+	MaxLines     int // Most lines either side of a comparison may have
+	CacheSeconds int // How long a comparison is cached; 0 turns caching off
+}
+
+// DownloadConfig holds the download of document files, streamed from
+// storage or handed off to it with a presigned URL
+type DownloadConfig struct {
+	RedirectBytes int64 // Files from this size are redirected to a presigned URL; 0 streams every file unless asked otherwise
+	URLSeconds    int   // How long a presigned download URL stays valid
+}
+
+// QuotaConfig holds the enforcement of space quotas: document count,
+// storage, and monthly agent executions and stream events
+type QuotaConfig struct {
+	Enforced          bool   // Refuse writes over a quota; when false usage is still counted and reported
+	WarningThresholds string // Comma-separated percentages of a quota at which the space's admins are warned
+}
+
+// Thresholds returns the warning thresholds in ascending order
+func (c QuotaConfig) Thresholds() ([]int, error) {
+	var thresholds []int
+	if strings.TrimSpace(c.WarningThresholds) == "" {
+		return thresholds, nil
+	}
+	for _, value := range strings.Split(c.WarningThresholds, ",") {
+		percent, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || percent <= 0 || percent > 100 {
+			return nil, fmt.Errorf("invalid quota warning threshold %q, expected a percentage from 1 to 100", value)
+		}
+		thresholds = append(thresholds, percent)
+	}
+	sort.Ints(thresholds)
+	return thresholds, nil
+}
+
+// CompressionConfig holds HTTP payload compression. Compressed request
+// bodies (gzip or zstd) are accepted whether or not responses are
+// compressed.
+type CompressionConfig struct {
+	Enabled   bool   // Compress responses for clients that accept an encoding
+	Encodings string // Comma-separated response encodings, in order of preference: zstd, gzip
+	MinBytes  int    // Responses smaller than this are sent uncompressed
+	GzipLevel int    // 1 (fastest) to 9 (smallest)
+}
+
+// compressionEncodings are the content encodings the server reads and writes
+var compressionEncodings = map[string]bool{"zstd": true, "gzip": true}
+
+// EncodingList returns the response encodings in order of preference
+func (c CompressionConfig) EncodingList() ([]string, error) {
+	var encodings []string
+	for _, encoding := range strings.Split(c.Encodings, ",") {
+		encoding = strings.ToLower(strings.TrimSpace(encoding))
+		if encoding == "" {
+			continue
+		}
+		if !compressionEncodings[encoding] {
+			return nil, fmt.Errorf("unsupported compression encoding %q, expected zstd or gzip", encoding)
+		}
+		encodings = append(encodings, encoding)
+	}
+	return encodings, nil
+}
+
+// RegionConfig holds multi-region deployment, where each tenant is pinned
+// to an active region that serves it and a passive region its data is
+// replicated to. A failover swaps the two.
+type RegionConfig struct {
+	Name             string // Region of this deployment; empty for a single-region deployment, which serves every tenant
+	Regions          string // Comma-separated name=base URL of every region, this one included
+	RefreshSeconds   int    // How often each replica re-reads the tenant pins set through another
+	ReplicationHooks string // Comma-separated name=URL of the hooks called, in order, before a tenant fails over
+	HookSecret       string // Signs the calls to replication hooks
+	HookTimeoutSecs  int    // How long a replication hook may take
+}
+
+// ReplicationHook is a hook called before a tenant fails over, which
+// promotes or checks the tenant's replicas in the target region
+type ReplicationHook struct {
+	Name string
+	URL  string
+}
+
+// Enabled reports whether the deployment is one of several regions
+func (c RegionConfig) Enabled() bool {
+	return c.Name != ""
+}
+
+// RegionURLs returns the base URL of each region by name
+func (c RegionConfig) RegionURLs() (map[string]string, error) {
+	urls := make(map[string]string)
+	if strings.TrimSpace(c.Regions) == "" {
+		return urls, nil
+	}
+	for _, pair := range strings.Split(c.Regions, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if parsed, err := url.Parse(value); !ok || name == "" || err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			return nil, fmt.Errorf("invalid region %q, expected name=base URL", pair)
+		}
+		urls[name] = strings.TrimRight(value, "/")
+	}
+	return urls, nil
+}
+
+// Hooks returns the replication hooks in the order they are called
+func (c RegionConfig) Hooks() ([]ReplicationHook, error) {
+	var hooks []ReplicationHook
+	if strings.TrimSpace(c.ReplicationHooks) == "" {
+		return hooks, nil
+	}
+	for _, pair := range strings.Split(c.ReplicationHooks, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if parsed, err := url.Parse(value); !ok || name == "" || err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			return nil, fmt.Errorf("invalid replication hook %q, expected name=URL", pair)
+		}
+		hooks = append(hooks, ReplicationHook{Name: name, URL: value})
+	}
+	return hooks, nil
+}
+
+// Concurrency classes of API routes, from the highest priority to the
+// lowest
+const (
+	ConcurrencyRead  = "read"  // Reads, which users wait on
+	ConcurrencyWrite = "write" // Other changes
+	ConcurrencyAdmin = "admin" // Administration routes
+	ConcurrencyBulk  = "bulk"  // Uploads, imports and exports, which hold a slot for long
+)
+
+// ConcurrencyClasses lists the concurrency classes in order of priority
+var ConcurrencyClasses = []string{ConcurrencyRead, ConcurrencyWrite, ConcurrencyAdmin, ConcurrencyBulk}
+
+// ConcurrencyConfig holds the limits on requests served at once. Each
+// class of routes has a limit of its own within MaxRequests, so that
+// expensive routes cannot take every slot; requests over a limit queue,
+// and freed slots go to the queued requests of the highest priority class.
+type ConcurrencyConfig struct {
+	Enabled        bool
+	MaxRequests    int    // Requests served at once by a replica, every class included
+	ClassLimits    string // Comma-separated class=limit pairs; classes not named may use all of MaxRequests
+	MaxQueue       int    // Requests of a class that may wait for a slot; more are rejected with 429
+	QueueTimeoutMs int    // How long a request waits for a slot before it is rejected with 429
+}
+
+// Limits returns the limit of each concurrency class
+func (c ConcurrencyConfig) Limits() (map[string]int, error) {
+	limits := make(map[string]int, len(ConcurrencyClasses))
+	for _, class := range ConcurrencyClasses {
+		limits[class] = c.MaxRequests
+	}
+
+	if strings.TrimSpace(c.ClassLimits) == "" {
+		return limits, nil
+	}
+	for _, pair := range strings.Split(c.ClassLimits, ",") {
+		class, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		class = strings.TrimSpace(class)
+		limit, err := strconv.Atoi(strings.TrimSpace(value))
+		if _, known := limits[class]; !ok || !known || err != nil || limit <= 0 {
+			return nil, fmt.Errorf("invalid concurrency limit %q, expected class=limit with class one of %s", pair, strings.Join(ConcurrencyClasses, ", "))
+		}
+		limits[class] = min(limit, c.MaxRequests)
+	}
+	return limits, nil
+}
+
+// APIVersionConfig holds API version negotiation and the deprecation
+// schedule of old versions
+type APIVersionConfig struct {
+	DefaultVersion  string // Version of /api/... requests that name none in the path or API-Version header
+	Deprecations    string // Comma-separated version=deprecated/sunset dates (YYYY-MM-DD); the sunset is optional
+	DeprecationLink string // Migration guide linked from the responses of deprecated versions
+}
+
+// APIVersionSchedule is when a version is deprecated and stops being served
+type APIVersionSchedule struct {
+	Deprecated time.Time
+	Sunset     time.Time // Zero if not scheduled
+}
+
+// Schedules returns the deprecation schedule of each deprecated version
+func (c APIVersionConfig) Schedules() (map[string]APIVersionSchedule, error) {
+	schedules := make(map[string]APIVersionSchedule)
+	if strings.TrimSpace(c.Deprecations) == "" {
+		return schedules, nil
+	}
+	for _, pair := range strings.Split(c.Deprecations, ",") {
+		version, dates, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		version = strings.TrimSpace(version)
+		if !ok || version == "" {
+			return nil, fmt.Errorf("invalid API deprecation %q, expected version=deprecated/sunset", pair)
+		}
+		deprecatedDate, sunsetDate, hasSunset := strings.Cut(dates, "/")
+
+		var schedule APIVersionSchedule
+		var err error
+		if schedule.Deprecated, err = time.Parse(time.DateOnly, strings.TrimSpace(deprecatedDate)); err != nil {
+			return nil, fmt.Errorf("invalid deprecation date in %q: %w", pair, err)
+		}
+		if hasSunset {
+			if schedule.Sunset, err = time.Parse(time.DateOnly, strings.TrimSpace(sunsetDate)); err != nil {
+				return nil, fmt.Errorf("invalid sunset date in %q: %w", pair, err)
+			}
+			if !schedule.Sunset.After(schedule.Deprecated) {
+				return nil, fmt.Errorf("sunset of %s must be after its deprecation", version)
+			}
+		}
+		schedules[version] = schedule
+	}
+	return schedules, nil
+}
+
+// BodyLimitConfig holds request body size limits. Bodies over the limit of
+// their route are rejected before they are read.
+type BodyLimitConfig struct {
+	DefaultBytes int64  // Limit for routes without their own
+	UploadBytes  int64  // Largest file accepted by the document upload endpoints
+	Overrides    string // Comma-separated route=bytes pairs; routes as registered, e.g. /api/v1/streams/sources/:id/events
+
+	BulkUploadBytes int64 // Largest body of a bulk upload, all files together
+	BulkUploadFiles int   // Files accepted by one bulk upload
+
+	ResumableUploadBytes int64 // Largest file of a resumable upload
+	UploadPartBytes      int64 // Size of the parts of a resumable upload; S3 requires at least 5MB
+
+	DirectUploadBytes int64 // Largest file of a presigned direct upload; S3 stores at most 5GB in one PUT
+
+	ImportBytes int64 // Largest export archive accepted by an import
+	ImportFiles int   // Files accepted in one export archive
+}
+
+// minUploadPartBytes is the smallest part S3 accepts in a multipart
+// upload, other than the last
+const minUploadPartBytes = 5 << 20
+
+// maxUploadParts is the most parts S3 accepts in a multipart upload
+const maxUploadParts = 10000
+
+// maxSinglePutBytes is the largest object S3 stores in one PUT
+const maxSinglePutBytes = 5 << 30
+
+// uploadOverheadBytes allows for the form fields and part headers sent
+// alongside an uploaded file
+const uploadOverheadBytes = 1 << 20
+
+// RouteLimits returns the limit of every route that does not use the
+// default, keyed by route pattern
+func (c BodyLimitConfig) RouteLimits() (map[string]int64, error) {
+	limits := map[string]int64{
+		"/api/v1/documents/upload": c.UploadBytes + uploadOverheadBytes,
+		// A new version is uploaded like the document it replaces
+		"/api/v1/documents/:id/versions": c.UploadBytes + uploadOverheadBytes,
+		// Base64 encoding grows the file by a third
+		"/api/v1/documents/upload-base64": c.UploadBytes/3*4 + uploadOverheadBytes,
+		// All files of a bulk upload share one body
+		"/api/v1/notebooks/:id/documents/bulk": c.BulkUploadBytes + uploadOverheadBytes,
+		"/api/v1/imports":                      c.ImportBytes + uploadOverheadBytes,
+		// Parts of a resumable upload are sent as raw bodies
+		"/api/v1/uploads/:id/parts/:number": c.UploadPartBytes,
+	}
+
+	if strings.TrimSpace(c.Overrides) == "" {
+		return limits, nil
+	}
+	for _, pair := range strings.Split(c.Overrides, ",") {
+		route, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		limit, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if !ok || strings.TrimSpace(route) == "" || err != nil || limit <= 0 {
+			return nil, fmt.Errorf("invalid body limit override %q, expected route=bytes", pair)
+		}
+		limits[strings.TrimSpace(route)] = limit
+	}
+	return limits, nil
+}
+
+// SLOConfig holds the service level objectives tracked by each replica and
+// the burn rates at which an alert is raised. Burn rate is the rate the
+// error budget is being spent relative to spending it evenly over the window.
+type SLOConfig struct {
+	Enabled            bool
+	WindowDays         int     // Compliance window
+	AvailabilityTarget float64 // Fraction of requests answered without a 5xx
+	LatencyP95Ms       int     // p95 latency objective of route groups without their own
+	LatencyOverrides   string  // Comma-separated group=ms pairs; the group is the path segment after /api/v1/
+	ProcessingTarget   float64 // Fraction of processing jobs that succeed
+	FastBurnRate       float64 // Alert when the last hour burns faster than this
+	SlowBurnRate       float64 // Alert when the last six hours burn faster than this
+	EvaluationSeconds  int     // How often alerts are evaluated
+}
+
+// LatencyTargets returns the p95 latency objective of every route group
+// that does not use the default, in milliseconds
+func (c SLOConfig) LatencyTargets() (map[string]int, error) {
+	targets := make(map[string]int)
+	if strings.TrimSpace(c.LatencyOverrides) == "" {
+		return targets, nil
+	}
+	for _, pair := range strings.Split(c.LatencyOverrides, ",") {
+		group, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		ms, err := strconv.Atoi(strings.TrimSpace(value))
+		if !ok || strings.TrimSpace(group) == "" || err != nil || ms <= 0 {
+			return nil, fmt.Errorf("invalid latency objective %q, expected group=ms", pair)
+		}
+		targets[strings.TrimSpace(group)] = ms
+	}
+	return targets, nil
+}
+
+// AnomalyConfig holds the usage anomaly detector. Each tenant's uploads,
+// agent cost and API error rate are rolled up per interval and compared
+// with an exponentially weighted moving average of earlier intervals.
+type AnomalyConfig struct {
+	Enabled         bool
+	IntervalSeconds int     // Rollup interval
+	Alpha           float64 // EWMA smoothing factor, between 0 and 1
+	ZThreshold      float64 // Standard deviations above the baseline that are anomalous
+	WarmupIntervals int     // Intervals of history needed before a tenant is judged
+	MaxTenants      int     // Tenants tracked per replica
+	MinUploads      float64 // Upload spikes smaller than this per interval are ignored
+	MinAgentCostUSD float64 // Agent cost surges smaller than this per interval are ignored
+	MinRequests     int     // Requests per interval needed to judge the error rate
+}
+
+// SyntheticConfig holds the synthetic monitoring probes. The probes log in
+// as a dedicated account and call the API the way a client would, so the
+// account, its space and notebook must exist.
+type SyntheticConfig struct {
+	Enabled        bool
+	BaseURL        string // API the probes call; defaults to this server
+	Username       string
+	Password       string
+	SpaceType      string
+	SpaceID        string
+	NotebookID     string // Notebook the upload probe uploads to
+	AgentID        string // Agent the dry-run probe executes; empty skips the probe
+	TimeoutSeconds int    // Per probe
+}
+
+// GraphQLConfig holds the GraphQL endpoint
+type GraphQLConfig struct {
+	Enabled  bool
+	MaxDepth int // Deepest query accepted; deeper queries are rejected before they run
+}
+
+// GRPCConfig holds the internal gRPC server, which sibling services such
+// as the AudiModal bridge call. Callers authenticate with a shared token.
+type GRPCConfig struct {
+	Enabled   bool
+	Port      string
+	AuthToken string // Bearer token callers send in the authorization metadata
+}
+
+// AccessLogConfig holds API access logging. Access logs are written apart
+// from application logs and can be exported for traffic analysis and
+// billing evidence.
+type AccessLogConfig struct {
+	Enabled      bool
+	SampleRate   float64  // Fraction of requests logged, 0-1
+	KeepErrors   bool     // Log every 4xx/5xx response regardless of sampling
+	SkipPaths    []string // Paths never logged, e.g. health probes
+	Export       string   // "", "kafka" or "s3"
+	KafkaTopic   string
+	S3Prefix     string
+	BatchSize    int
+	FlushSeconds int
+	QueueSize    int // Entries buffered for export; more are dropped
+}
+
+// PostgresConfig holds the optional PostgreSQL reporting database. When
+// enabled, documents, notebooks and usage are projected into relational
+// tables from domain events.
+type PostgresConfig struct {
+	Enabled      bool
+	Driver       string // database/sql driver name; the binary must be built with -tags postgres
+	URL          string
+	MaxOpenConns int
+	MaxIdleConns int
+}
+
 // RouterConfig holds LLM router proxy configuration
 type RouterConfig struct {
 	Enabled     bool                 `json:"enabled"`
@@ -195,6 +948,10 @@ func Load() (*Config, error) {
 	// Load .env file if it exists (ignore error if file doesn't exist)
 	_ = godotenv.Load()
 
+	// Outbound calls to other services default to one shared timeout;
+	// per-service settings below override it
+	externalHTTPSeconds := getEnvInt("EXTERNAL_HTTP_TIMEOUT_SECONDS", 30)
+
 	config := &Config{
 		Server: ServerConfig{
 			Host:         getEnv("HOST", "0.0.0.0"),
@@ -205,6 +962,8 @@ func Load() (*Config, error) {
 			ReadTimeout:  getEnvInt("READ_TIMEOUT", 10),
 			WriteTimeout: getEnvInt("WRITE_TIMEOUT", 10),
 			IdleTimeout:  getEnvInt("IDLE_TIMEOUT", 60),
+
+			ConfigReloadFile: getEnv("CONFIG_RELOAD_FILE", ".env"),
 		},
 		Neo4j: DatabaseConfig{
 			URI:         getEnv("NEO4J_URI", "bolt://localhost:7687"),
@@ -213,8 +972,24 @@ func Load() (*Config, error) {
 			Database:    getEnv("NEO4J_DATABASE", "aether"),
 			MaxConns:    getEnvInt("NEO4J_MAX_CONNS", 50),
 			TLSInsecure: getEnvBool("NEO4J_TLS_INSECURE", false),
+
+			QueryTimeoutSeconds: getEnvInt("NEO4J_QUERY_TIMEOUT_SECONDS", 30),
+			BatchSize:           getEnvInt("NEO4J_BATCH_SIZE", 500),
+			StreamFetchSize:     getEnvInt("NEO4J_STREAM_FETCH_SIZE", 1000),
+
+			ConnAcquisitionTimeoutSeconds: getEnvInt("NEO4J_CONN_ACQUISITION_TIMEOUT_SECONDS", 30),
+			MaxConnLifetimeMinutes:        getEnvInt("NEO4J_MAX_CONN_LIFETIME_MINUTES", 60),
+			PoolMaxQueue:                  getEnvInt("NEO4J_POOL_MAX_QUEUE", 100),
+			PoolWaitTimeoutMs:             getEnvInt("NEO4J_POOL_WAIT_TIMEOUT_MS", 2000),
+
+			SlowQueryThresholdMs: getEnvInt("NEO4J_SLOW_QUERY_THRESHOLD_MS", 500),
+			ProfileSlowQueries:   getEnvBool("NEO4J_PROFILE_SLOW_QUERIES", false),
+
+			MaxTraversalDepth: getEnvInt("NEO4J_MAX_TRAVERSAL_DEPTH", 3),
+			MaxEstimatedRows:  getEnvInt("NEO4J_MAX_ESTIMATED_ROWS", 100000),
 		},
 		Redis: RedisConfig{
+			Enabled:  getEnvBool("REDIS_ENABLED", false),
 			Addr:     getEnv("REDIS_ADDR", "localhost:6379"),
 			Password: getEnv("REDIS_PASSWORD", ""),
 			DB:       getEnvInt("REDIS_DB", 0),
@@ -243,10 +1018,14 @@ func Load() (*Config, error) {
 		Monitoring: MonitoringConfig{
 			PrometheusEnabled: getEnvBool("PROMETHEUS_ENABLED", true),
 			OTELEndpoint:      getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+			TenantAllowlist:   getEnvSlice("TENANT_METRICS_ALLOWLIST", nil),
+			TenantLabelLimit:  getEnvInt("TENANT_METRICS_MAX_TENANTS", 50),
 		},
 		Logger: LoggingConfig{
-			Level:  getEnv("LOG_LEVEL", "info"),
-			Format: getEnv("LOG_FORMAT", "json"),
+			Level:        getEnv("LOG_LEVEL", "info"),
+			Format:       getEnv("LOG_FORMAT", "json"),
+			Redaction:    getEnvBool("LOG_REDACTION_ENABLED", true),
+			RedactFields: getEnvSlice("LOG_REDACT_FIELDS", nil),
 		},
 		AudiModal: AudiModalConfig{
 			BaseURL:              getEnv("AUDIMODAL_BASE_URL", "http://audimodal:8080"),
@@ -260,6 +1039,9 @@ func Load() (*Config, error) {
 			WebhookSecret:        getEnv("AUDIMODAL_WEBHOOK_SECRET", ""),
 			MaxConcurrentFiles:   getEnvInt("AUDIMODAL_MAX_CONCURRENT_FILES", 5),
 			ChunkSizeLimit:       getEnvInt("AUDIMODAL_CHUNK_SIZE_LIMIT", 4096),
+			DefaultTenantUUID:     getEnv("AUDIMODAL_DEFAULT_TENANT_UUID", ""),
+			DefaultDataSourceUUID: getEnv("AUDIMODAL_DEFAULT_DATASOURCE_UUID", ""),
+			OCRLanguagePacks:      getEnvSlice("AUDIMODAL_OCR_LANGUAGE_PACKS", []string{"eng"}),
 		},
 		Embedding: EmbeddingConfig{
 			Provider:           getEnv("EMBEDDING_PROVIDER", "openai"),
@@ -273,16 +1055,17 @@ func Load() (*Config, error) {
 			APIKey:            getEnv("DEEPLAKE_API_KEY", ""),
 			CollectionName:    getEnv("DEEPLAKE_COLLECTION_NAME", "aether_embeddings"),
 			VectorDimensions:  getEnvInt("DEEPLAKE_VECTOR_DIMENSIONS", 1536),
-			TimeoutSeconds:    getEnvInt("DEEPLAKE_TIMEOUT_SECONDS", 30),
+			TimeoutSeconds:    getEnvInt("DEEPLAKE_TIMEOUT_SECONDS", externalHTTPSeconds),
 			Enabled:           getEnvBool("DEEPLAKE_ENABLED", true),
 			UseDefaultDataset: getEnvBool("DEEPLAKE_USE_DEFAULT_DATASET", true),
+			SpaceDataset:      getEnv("DEEPLAKE_SPACE_DATASET", "documents"),
 		},
 		OpenAI: OpenAIConfig{
 			APIKey:         getEnv("OPENAI_API_KEY", ""),
 			Model:          getEnv("OPENAI_EMBEDDING_MODEL", "text-embedding-ada-002"),
 			BaseURL:        getEnv("OPENAI_BASE_URL", "https://api.openai.com/v1"),
 			Dimensions:     getEnvInt("OPENAI_EMBEDDING_DIMENSIONS", 1536),
-			TimeoutSeconds: getEnvInt("OPENAI_TIMEOUT_SECONDS", 30),
+			TimeoutSeconds: getEnvInt("OPENAI_TIMEOUT_SECONDS", externalHTTPSeconds),
 		},
 		Compliance: ComplianceConfig{
 			Enabled:                   getEnvBool("COMPLIANCE_ENABLED", true),
@@ -303,7 +1086,7 @@ func Load() (*Config, error) {
 				BaseURL:        getEnv("ROUTER_SERVICE_BASE_URL", "http://localhost:8086"),
 				APIKey:         getEnv("ROUTER_API_KEY", ""),
 				UseServiceAuth: getEnvBool("ROUTER_USE_SERVICE_AUTH", false),
-				Timeout:        getEnv("ROUTER_SERVICE_TIMEOUT", "30s"),
+				Timeout:        getEnv("ROUTER_SERVICE_TIMEOUT", fmt.Sprintf("%ds", externalHTTPSeconds)),
 				MaxRetries:     getEnvInt("ROUTER_SERVICE_MAX_RETRIES", 3),
 				ConnectTimeout: getEnv("ROUTER_SERVICE_CONNECT_TIMEOUT", "10s"),
 			},
@@ -318,8 +1101,262 @@ func Load() (*Config, error) {
 			},
 			ProxyRoutes: getDefaultProxyRoutes(),
 		},
+		Timeouts: TimeoutConfig{
+			ExternalHTTPSeconds:  externalHTTPSeconds,
+			BackgroundJobMinutes: getEnvInt("BACKGROUND_JOB_TIMEOUT_MINUTES", 30),
+			ShutdownSeconds:      getEnvInt("SHUTDOWN_TIMEOUT_SECONDS", 30),
+		},
+		Cluster: ClusterConfig{
+			InstanceID:         getEnv("INSTANCE_ID", defaultInstanceID()),
+			Replicas:           getEnvInt("CLUSTER_REPLICAS", 1),
+			LeaderLeaseSeconds: getEnvInt("LEADER_LEASE_SECONDS", 15),
+		},
+		Scheduler: SchedulerConfig{
+			HistoryLimit:        getEnvInt("SCHEDULER_HISTORY_LIMIT", 50),
+			ProcessingRetries:   getEnv("SCHEDULE_PROCESSING_RETRIES", "@every 30s"),
+			CountReconciliation: getEnv("SCHEDULE_COUNT_RECONCILIATION", "0 * * * *"),
+			SyntheticProbes:     getEnv("SCHEDULE_SYNTHETIC_PROBES", "@every 5m"),
+			JobCleanup:          getEnv("SCHEDULE_JOB_CLEANUP", "*/15 * * * *"),
+			VectorSync:          getEnv("SCHEDULE_VECTOR_SYNC", "@every 30s"),
+			UploadCleanup:       getEnv("SCHEDULE_UPLOAD_CLEANUP", "0 * * * *"),
+			DocumentDeletions:   getEnv("SCHEDULE_DOCUMENT_DELETIONS", "@every 1m"),
+			Summaries:           getEnv("SCHEDULE_SUMMARIES", "@every 1m"),
+			TrashPurge:          getEnv("SCHEDULE_TRASH_PURGE", "30 * * * *"),
+			S3Watchers:          getEnv("SCHEDULE_S3_WATCHERS", "@every 1m"),
+			Reports:             getEnv("SCHEDULE_REPORTS", "@every 5m"),
+			WebhookDeliveries:   getEnv("SCHEDULE_WEBHOOK_DELIVERIES", "@every 10s"),
+			RelatedDocuments:    getEnv("SCHEDULE_RELATED_DOCUMENTS", "@every 1m"),
+			ChunkSync:           getEnv("SCHEDULE_CHUNK_SYNC", "@every 30s"),
+			Moderation:          getEnv("SCHEDULE_MODERATION", "@every 30s"),
+			NotebookDigests:     getEnv("SCHEDULE_NOTEBOOK_DIGESTS", "@every 5m"),
+		},
+		AccessLog: AccessLogConfig{
+			Enabled:      getEnvBool("ACCESS_LOG_ENABLED", true),
+			SampleRate:   getEnvFloat("ACCESS_LOG_SAMPLE_RATE", 1),
+			KeepErrors:   getEnvBool("ACCESS_LOG_KEEP_ERRORS", true),
+			SkipPaths:    getEnvSlice("ACCESS_LOG_SKIP_PATHS", []string{"/health/live", "/health/ready", "/metrics"}),
+			Export:       getEnv("ACCESS_LOG_EXPORT", ""),
+			KafkaTopic:   getEnv("ACCESS_LOG_KAFKA_TOPIC", "access-logs"),
+			S3Prefix:     getEnv("ACCESS_LOG_S3_PREFIX", "access-logs"),
+			BatchSize:    getEnvInt("ACCESS_LOG_BATCH_SIZE", 500),
+			FlushSeconds: getEnvInt("ACCESS_LOG_FLUSH_SECONDS", 10),
+			QueueSize:    getEnvInt("ACCESS_LOG_QUEUE_SIZE", 10000),
+		},
+		SLO: SLOConfig{
+			Enabled:            getEnvBool("SLO_ENABLED", true),
+			WindowDays:         getEnvInt("SLO_WINDOW_DAYS", 30),
+			AvailabilityTarget: getEnvFloat("SLO_AVAILABILITY_TARGET", 0.999),
+			LatencyP95Ms:       getEnvInt("SLO_LATENCY_P95_MS", 1000),
+			LatencyOverrides:   getEnv("SLO_LATENCY_P95_OVERRIDES", "documents=5000,agents=15000"),
+			ProcessingTarget:   getEnvFloat("SLO_PROCESSING_TARGET", 0.95),
+			FastBurnRate:       getEnvFloat("SLO_FAST_BURN_RATE", 14.4),
+			SlowBurnRate:       getEnvFloat("SLO_SLOW_BURN_RATE", 6),
+			EvaluationSeconds:  getEnvInt("SLO_EVALUATION_SECONDS", 60),
+		},
+		Anomaly: AnomalyConfig{
+			Enabled:         getEnvBool("ANOMALY_DETECTION_ENABLED", true),
+			IntervalSeconds: getEnvInt("ANOMALY_INTERVAL_SECONDS", 300),
+			Alpha:           getEnvFloat("ANOMALY_EWMA_ALPHA", 0.1),
+			ZThreshold:      getEnvFloat("ANOMALY_Z_THRESHOLD", 4),
+			WarmupIntervals: getEnvInt("ANOMALY_WARMUP_INTERVALS", 12),
+			MaxTenants:      getEnvInt("ANOMALY_MAX_TENANTS", 10000),
+			MinUploads:      getEnvFloat("ANOMALY_MIN_UPLOADS", 20),
+			MinAgentCostUSD: getEnvFloat("ANOMALY_MIN_AGENT_COST_USD", 1),
+			MinRequests:     getEnvInt("ANOMALY_MIN_REQUESTS", 50),
+		},
+		Synthetic: SyntheticConfig{
+			Enabled:        getEnvBool("SYNTHETIC_ENABLED", false),
+			BaseURL:        getEnv("SYNTHETIC_BASE_URL", "http://localhost:"+getEnv("PORT", "8080")),
+			Username:       getEnv("SYNTHETIC_USERNAME", ""),
+			Password:       getEnv("SYNTHETIC_PASSWORD", ""),
+			SpaceType:      getEnv("SYNTHETIC_SPACE_TYPE", "personal"),
+			SpaceID:        getEnv("SYNTHETIC_SPACE_ID", ""),
+			NotebookID:     getEnv("SYNTHETIC_NOTEBOOK_ID", ""),
+			AgentID:        getEnv("SYNTHETIC_AGENT_ID", ""),
+			TimeoutSeconds: getEnvInt("SYNTHETIC_TIMEOUT_SECONDS", 30),
+		},
+		GraphQL: GraphQLConfig{
+			Enabled:  getEnvBool("GRAPHQL_ENABLED", true),
+			MaxDepth: getEnvInt("GRAPHQL_MAX_DEPTH", 10),
+		},
+		GRPC: GRPCConfig{
+			Enabled:   getEnvBool("GRPC_ENABLED", false),
+			Port:      getEnv("GRPC_PORT", "9090"),
+			AuthToken: getEnv("GRPC_AUTH_TOKEN", ""),
+		},
+		Jobs: JobsConfig{
+			MaxConcurrent:      getEnvInt("JOBS_MAX_CONCURRENT", 4),
+			RetentionHours:     getEnvInt("JOBS_RETENTION_HOURS", 168),
+			MaxBatchOperations: getEnvInt("BATCH_MAX_OPERATIONS", 50),
+		},
+		Webhooks: WebhooksConfig{
+			ToleranceSeconds: getEnvInt("WEBHOOK_TOLERANCE_SECONDS", 300),
+			StripeSecret:     getEnv("STRIPE_WEBHOOK_SECRET", ""),
+			StreamSecret:     getEnv("STREAM_WEBHOOK_SECRET", ""),
+			ProcessingSecret: getEnv("PROCESSING_WEBHOOK_SECRET", ""),
+
+			DeliveryAttempts:    getEnvInt("WEBHOOK_DELIVERY_ATTEMPTS", 8),
+			DeliveryTimeoutSecs: getEnvInt("WEBHOOK_DELIVERY_TIMEOUT_SECONDS", 10),
+			AllowPrivateTargets: getEnvBool("WEBHOOK_ALLOW_PRIVATE_TARGETS", false),
+		},
+		Feeds: FeedsConfig{
+			BaseURL:  getEnv("FEED_BASE_URL", ""),
+			MaxItems: getEnvInt("FEED_MAX_ITEMS", 50),
+		},
+		QA: QAConfig{
+			Model:         getEnv("QA_MODEL", "gpt-4o-mini"),
+			Provider:      getEnv("QA_PROVIDER", "openai"),
+			MaxTokens:     getEnvInt("QA_MAX_TOKENS", 800),
+			ContextChunks: getEnvInt("QA_CONTEXT_CHUNKS", 8),
+		},
+		Summary: SummaryConfig{
+			AutoEnabled:        getEnvBool("SUMMARY_AUTO_ENABLED", false),
+			Model:              getEnv("SUMMARY_MODEL", "gpt-4o-mini"),
+			Provider:           getEnv("SUMMARY_PROVIDER", "openai"),
+			MaxTokens:          getEnvInt("SUMMARY_MAX_TOKENS", 400),
+			InputChars:         getEnvInt("SUMMARY_INPUT_CHARS", 24000),
+			MonthlyTokenBudget: getEnvInt("SUMMARY_MONTHLY_TOKEN_BUDGET", 500000),
+			BatchSize:          getEnvInt("SUMMARY_BATCH_SIZE", 10),
+		},
+		ChunkSync: ChunkSyncConfig{
+			Enabled:      getEnvBool("CHUNK_SYNC_ENABLED", true),
+			StoreContent: getEnvBool("CHUNK_SYNC_STORE_CONTENT", true),
+			MaxChunks:    getEnvInt("CHUNK_SYNC_MAX_CHUNKS", 5000),
+			BatchSize:    getEnvInt("CHUNK_SYNC_BATCH_SIZE", 20),
+		},
+		Encryption: TextEncryptionConfig{
+			MasterKey: getEnv("TEXT_ENCRYPTION_KEY", ""),
+			BatchSize: getEnvInt("TEXT_ENCRYPTION_BATCH_SIZE", 100),
+		},
+		Moderation: ModerationConfig{
+			Enabled:        getEnvBool("MODERATION_ENABLED", false),
+			Provider:       getEnv("MODERATION_PROVIDER", "keywords"),
+			BlockedTerms:   getEnvSlice("MODERATION_BLOCKED_TERMS", nil),
+			FlaggedTerms:   getEnvSlice("MODERATION_FLAGGED_TERMS", nil),
+			Model:          getEnv("MODERATION_MODEL", "gpt-4o-mini"),
+			RouterProvider: getEnv("MODERATION_ROUTER_PROVIDER", "openai"),
+			InputChars:     getEnvInt("MODERATION_INPUT_CHARS", 20000),
+			BatchSize:      getEnvInt("MODERATION_BATCH_SIZE", 10),
+		},
+		Scanner: ScannerConfig{
+			Provider:         getEnv("SCANNER_PROVIDER", ""),
+			Address:          getEnv("SCANNER_ADDRESS", "localhost:3310"),
+			ICAPService:      getEnv("SCANNER_ICAP_SERVICE", "avscan"),
+			TimeoutSeconds:   getEnvInt("SCANNER_TIMEOUT_SECONDS", 60),
+			ScanByDefault:    getEnvBool("SCANNER_SCAN_BY_DEFAULT", true),
+			FailOpen:         getEnvBool("SCANNER_FAIL_OPEN", false),
+			QuarantineBucket: getEnv("SCANNER_QUARANTINE_BUCKET", "aether-quarantine"),
+		},
+		Uploads: FileValidationConfig{
+			Validate:     getEnvBool("UPLOAD_VALIDATION_ENABLED", true),
+			AllowedTypes: getEnvSlice("UPLOAD_ALLOWED_TYPES", nil),
+		},
+		Trash: TrashConfig{
+			RetentionDays: getEnvInt("TRASH_RETENTION_DAYS", 30),
+		},
+		Maintenance: MaintenanceConfig{
+			RefreshSeconds: getEnvInt("MAINTENANCE_REFRESH_SECONDS", 5),
+		},
+		Quotas: QuotaConfig{
+			Enforced:          getEnvBool("QUOTAS_ENFORCED", true),
+			WarningThresholds: getEnv("QUOTA_WARNING_THRESHOLDS", "80,90,100"),
+		},
+		Roles: RolesConfig{
+			RefreshSeconds: getEnvInt("ROLES_REFRESH_SECONDS", 30),
+		},
+		Idempotency: IdempotencyConfig{
+			TTLSeconds:       getEnvInt("IDEMPOTENCY_TTL_SECONDS", 86400),
+			MaxResponseBytes: getEnvInt("IDEMPOTENCY_MAX_RESPONSE_BYTES", 1<<20),
+		},
+		Priority: ProcessingPriorityConfig{
+			Default:  strings.ToLower(getEnv("PROCESSING_PRIORITY_DEFAULT", PriorityNormal)),
+			PlanCaps: getEnv("PROCESSING_PRIORITY_PLAN_CAPS", "enterprise=realtime,default=high"),
+		},
+		Compare: CompareConfig{
+			MaxTextBytes: getEnvInt("COMPARE_MAX_TEXT_BYTES", 2<<20),
+			MaxLines:     getEnvInt("COMPARE_MAX_LINES", 20000),
+			CacheSeconds: getEnvInt("COMPARE_CACHE_SECONDS", 3600),
+		},
+		Downloads: DownloadConfig{
+			RedirectBytes: int64(getEnvInt("DOWNLOAD_REDIRECT_BYTES", 0)),
+			URLSeconds:    getEnvInt("DOWNLOAD_URL_SECONDS", 900),
+		},
+		Queue: DeliveryQueueConfig{
+			Workers:          getEnvInt("DELIVERY_QUEUE_WORKERS", 4),
+			ClaimIdleSeconds: getEnvInt("DELIVERY_QUEUE_CLAIM_IDLE_SECONDS", 60),
+			MaxDeliveries:    getEnvInt("DELIVERY_QUEUE_MAX_DELIVERIES", 5),
+			MaxLength:        int64(getEnvInt("DELIVERY_QUEUE_MAX_LENGTH", 100000)),
+		},
+		Region: RegionConfig{
+			Name:             getEnv("REGION_NAME", ""),
+			Regions:          getEnv("REGIONS", ""),
+			RefreshSeconds:   getEnvInt("REGION_REFRESH_SECONDS", 10),
+			ReplicationHooks: getEnv("REGION_REPLICATION_HOOKS", ""),
+			HookSecret:       getEnv("REGION_HOOK_SECRET", ""),
+			HookTimeoutSecs:  getEnvInt("REGION_HOOK_TIMEOUT_SECONDS", 300),
+		},
+		Concurrency: ConcurrencyConfig{
+			Enabled:        getEnvBool("CONCURRENCY_LIMITS_ENABLED", true),
+			MaxRequests:    getEnvInt("CONCURRENCY_MAX_REQUESTS", 256),
+			ClassLimits:    getEnv("CONCURRENCY_CLASS_LIMITS", "write=128,admin=16,bulk=32"),
+			MaxQueue:       getEnvInt("CONCURRENCY_MAX_QUEUE", 128),
+			QueueTimeoutMs: getEnvInt("CONCURRENCY_QUEUE_TIMEOUT_MS", 2000),
+		},
+		Compression: CompressionConfig{
+			Enabled:   getEnvBool("COMPRESSION_ENABLED", true),
+			Encodings: getEnv("COMPRESSION_ENCODINGS", "zstd,gzip"),
+			MinBytes:  getEnvInt("COMPRESSION_MIN_BYTES", 1024),
+			GzipLevel: getEnvInt("COMPRESSION_GZIP_LEVEL", 5),
+		},
+		Email: InboundEmailConfig{
+			Domain:             getEnv("INBOUND_EMAIL_DOMAIN", ""),
+			SNSTopicARN:        getEnv("INBOUND_EMAIL_SNS_TOPIC_ARN", ""),
+			MaxMessageBytes:    int64(getEnvInt("INBOUND_EMAIL_MAX_BYTES", 150<<10)),
+			MinAttachmentBytes: int64(getEnvInt("INBOUND_EMAIL_MIN_ATTACHMENT_BYTES", 2<<10)),
+		},
+		S3Watch: S3WatchConfig{
+			Enabled:          getEnvBool("S3_WATCH_ENABLED", false),
+			PrincipalARN:     getEnv("S3_WATCH_PRINCIPAL_ARN", ""),
+			MaxObjectsPerRun: getEnvInt("S3_WATCH_MAX_OBJECTS_PER_RUN", 100),
+		},
+		Reports: ReportsConfig{
+			SMTPAddr:     getEnv("REPORTS_SMTP_ADDR", ""),
+			SMTPUsername: getEnv("REPORTS_SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("REPORTS_SMTP_PASSWORD", ""),
+			From:         getEnv("REPORTS_EMAIL_FROM", ""),
+		},
+		API: APIVersionConfig{
+			DefaultVersion:  getEnv("API_DEFAULT_VERSION", "v1"),
+			Deprecations:    getEnv("API_DEPRECATIONS", ""),
+			DeprecationLink: getEnv("API_DEPRECATION_LINK", ""),
+		},
+		BodyLimits: BodyLimitConfig{
+			DefaultBytes: int64(getEnvInt("MAX_REQUEST_BODY_BYTES", 10<<20)),
+			UploadBytes:  int64(getEnvInt("MAX_UPLOAD_BYTES", 100<<20)),
+			Overrides:    getEnv("MAX_REQUEST_BODY_OVERRIDES", ""),
+
+			BulkUploadBytes: int64(getEnvInt("MAX_BULK_UPLOAD_BYTES", 500<<20)),
+			BulkUploadFiles: getEnvInt("MAX_BULK_UPLOAD_FILES", 20),
+
+			ResumableUploadBytes: int64(getEnvInt("MAX_RESUMABLE_UPLOAD_BYTES", 10<<30)),
+			UploadPartBytes:      int64(getEnvInt("UPLOAD_PART_BYTES", 16<<20)),
+
+			DirectUploadBytes: int64(getEnvInt("MAX_DIRECT_UPLOAD_BYTES", maxSinglePutBytes)),
+
+			ImportBytes: int64(getEnvInt("MAX_IMPORT_BYTES", 1<<30)),
+			ImportFiles: getEnvInt("MAX_IMPORT_FILES", 5000),
+		},
+		Postgres: PostgresConfig{
+			Enabled:      getEnvBool("POSTGRES_ENABLED", false),
+			Driver:       getEnv("POSTGRES_DRIVER", "pgx"),
+			URL:          getEnv("POSTGRES_URL", ""),
+			MaxOpenConns: getEnvInt("POSTGRES_MAX_OPEN_CONNS", 10),
+			MaxIdleConns: getEnvInt("POSTGRES_MAX_IDLE_CONNS", 2),
+		},
 	}
 
+	config.Runtime = loadRuntimeConfig()
+
 	// Validate required configuration
 	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
@@ -346,6 +1383,326 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("at least one Kafka broker is required when Kafka is enabled")
 	}
 
+	if c.Neo4j.MaxConns <= 0 {
+		return fmt.Errorf("NEO4J_MAX_CONNS must be positive")
+	}
+
+	if c.Neo4j.PoolMaxQueue < 0 || c.Neo4j.PoolWaitTimeoutMs <= 0 {
+		return fmt.Errorf("NEO4J_POOL_MAX_QUEUE must not be negative and NEO4J_POOL_WAIT_TIMEOUT_MS must be positive")
+	}
+
+	if c.Neo4j.MaxTraversalDepth <= 0 || c.Neo4j.MaxEstimatedRows < 0 {
+		return fmt.Errorf("NEO4J_MAX_TRAVERSAL_DEPTH must be positive and NEO4J_MAX_ESTIMATED_ROWS must not be negative")
+	}
+
+	if c.Cluster.Replicas < 1 {
+		return fmt.Errorf("CLUSTER_REPLICAS must be at least 1")
+	}
+
+	if c.Cluster.LeaderLeaseSeconds < 3 {
+		return fmt.Errorf("LEADER_LEASE_SECONDS must be at least 3")
+	}
+
+	if c.Scheduler.HistoryLimit <= 0 {
+		return fmt.Errorf("SCHEDULER_HISTORY_LIMIT must be positive")
+	}
+
+	if c.Jobs.MaxConcurrent <= 0 || c.Jobs.RetentionHours <= 0 || c.Jobs.MaxBatchOperations <= 0 {
+		return fmt.Errorf("JOBS_MAX_CONCURRENT, JOBS_RETENTION_HOURS and BATCH_MAX_OPERATIONS must be positive")
+	}
+
+	if c.Webhooks.ToleranceSeconds <= 0 {
+		return fmt.Errorf("WEBHOOK_TOLERANCE_SECONDS must be positive")
+	}
+
+	if c.Webhooks.DeliveryAttempts <= 0 || c.Webhooks.DeliveryTimeoutSecs <= 0 {
+		return fmt.Errorf("WEBHOOK_DELIVERY_ATTEMPTS and WEBHOOK_DELIVERY_TIMEOUT_SECONDS must be positive")
+	}
+
+	if c.Maintenance.RefreshSeconds <= 0 {
+		return fmt.Errorf("MAINTENANCE_REFRESH_SECONDS must be positive")
+	}
+
+	if _, err := c.Quotas.Thresholds(); err != nil {
+		return err
+	}
+
+	if c.Roles.RefreshSeconds <= 0 {
+		return fmt.Errorf("ROLES_REFRESH_SECONDS must be positive")
+	}
+
+	if c.Queue.Workers <= 0 || c.Queue.ClaimIdleSeconds <= 0 || c.Queue.MaxDeliveries <= 0 || c.Queue.MaxLength <= 0 {
+		return fmt.Errorf("DELIVERY_QUEUE_WORKERS, DELIVERY_QUEUE_CLAIM_IDLE_SECONDS, DELIVERY_QUEUE_MAX_DELIVERIES and DELIVERY_QUEUE_MAX_LENGTH must be positive")
+	}
+
+	if c.Idempotency.TTLSeconds <= 0 || c.Idempotency.MaxResponseBytes <= 0 {
+		return fmt.Errorf("IDEMPOTENCY_TTL_SECONDS and IDEMPOTENCY_MAX_RESPONSE_BYTES must be positive")
+	}
+
+	if PriorityRank(c.Priority.Default) < 0 {
+		return fmt.Errorf("PROCESSING_PRIORITY_DEFAULT must be one of %s", strings.Join(ProcessingPriorities, ", "))
+	}
+	if _, err := c.Priority.Caps(); err != nil {
+		return fmt.Errorf("invalid PROCESSING_PRIORITY_PLAN_CAPS: %w", err)
+	}
+
+	if c.Compare.MaxTextBytes <= 0 || c.Compare.MaxLines <= 0 {
+		return fmt.Errorf("COMPARE_MAX_TEXT_BYTES and COMPARE_MAX_LINES must be positive")
+	}
+	if c.Compare.CacheSeconds < 0 {
+		return fmt.Errorf("COMPARE_CACHE_SECONDS must not be negative")
+	}
+
+	if c.Downloads.RedirectBytes < 0 {
+		return fmt.Errorf("DOWNLOAD_REDIRECT_BYTES must not be negative")
+	}
+	// Presigned URLs are valid for at most seven days
+	if c.Downloads.URLSeconds <= 0 || c.Downloads.URLSeconds > 7*24*3600 {
+		return fmt.Errorf("DOWNLOAD_URL_SECONDS must be between 1 and 604800")
+	}
+
+	if err := c.validateRegion(); err != nil {
+		return err
+	}
+
+	if c.Concurrency.MaxRequests <= 0 || c.Concurrency.MaxQueue < 0 || c.Concurrency.QueueTimeoutMs < 0 {
+		return fmt.Errorf("CONCURRENCY_MAX_REQUESTS must be positive, CONCURRENCY_MAX_QUEUE and CONCURRENCY_QUEUE_TIMEOUT_MS not negative")
+	}
+	if _, err := c.Concurrency.Limits(); err != nil {
+		return err
+	}
+
+	if c.Compression.MinBytes < 0 {
+		return fmt.Errorf("COMPRESSION_MIN_BYTES must not be negative")
+	}
+	if c.Compression.GzipLevel < 1 || c.Compression.GzipLevel > 9 {
+		return fmt.Errorf("COMPRESSION_GZIP_LEVEL must be between 1 and 9")
+	}
+	if _, err := c.Compression.EncodingList(); err != nil {
+		return err
+	}
+
+	if c.Trash.RetentionDays <= 0 {
+		return fmt.Errorf("TRASH_RETENTION_DAYS must be positive")
+	}
+
+	if c.Email.MaxMessageBytes <= 0 || c.Email.MinAttachmentBytes < 0 {
+		return fmt.Errorf("INBOUND_EMAIL_MAX_BYTES must be positive and INBOUND_EMAIL_MIN_ATTACHMENT_BYTES not negative")
+	}
+
+	if c.S3Watch.MaxObjectsPerRun <= 0 {
+		return fmt.Errorf("S3_WATCH_MAX_OBJECTS_PER_RUN must be positive")
+	}
+
+	if c.Reports.SMTPAddr != "" && c.Reports.From == "" {
+		return fmt.Errorf("REPORTS_EMAIL_FROM is required when REPORTS_SMTP_ADDR is set")
+	}
+
+	if c.Feeds.MaxItems <= 0 {
+		return fmt.Errorf("FEED_MAX_ITEMS must be positive")
+	}
+
+	if c.QA.MaxTokens <= 0 {
+		return fmt.Errorf("QA_MAX_TOKENS must be positive")
+	}
+	if c.QA.ContextChunks <= 0 || c.QA.ContextChunks > 100 {
+		return fmt.Errorf("QA_CONTEXT_CHUNKS must be between 1 and 100")
+	}
+
+	if c.Summary.MaxTokens <= 0 {
+		return fmt.Errorf("SUMMARY_MAX_TOKENS must be positive")
+	}
+	if c.Summary.InputChars < 1000 {
+		return fmt.Errorf("SUMMARY_INPUT_CHARS must be at least 1000")
+	}
+	if c.Summary.MonthlyTokenBudget < 0 {
+		return fmt.Errorf("SUMMARY_MONTHLY_TOKEN_BUDGET must not be negative")
+	}
+	if c.Summary.BatchSize <= 0 {
+		return fmt.Errorf("SUMMARY_BATCH_SIZE must be positive")
+	}
+
+	if c.ChunkSync.MaxChunks <= 0 {
+		return fmt.Errorf("CHUNK_SYNC_MAX_CHUNKS must be positive")
+	}
+	if c.ChunkSync.BatchSize <= 0 {
+		return fmt.Errorf("CHUNK_SYNC_BATCH_SIZE must be positive")
+	}
+
+	if c.Encryption.MasterKey != "" {
+		if _, err := fieldcrypt.ParseKey(c.Encryption.MasterKey); err != nil {
+			return fmt.Errorf("TEXT_ENCRYPTION_KEY is invalid: %w", err)
+		}
+	}
+	if c.Encryption.BatchSize <= 0 {
+		return fmt.Errorf("TEXT_ENCRYPTION_BATCH_SIZE must be positive")
+	}
+
+	if c.Moderation.Provider != "keywords" && c.Moderation.Provider != "router" {
+		return fmt.Errorf("MODERATION_PROVIDER must be keywords or router")
+	}
+	if c.Moderation.InputChars < 1000 {
+		return fmt.Errorf("MODERATION_INPUT_CHARS must be at least 1000")
+	}
+	if c.Moderation.BatchSize <= 0 {
+		return fmt.Errorf("MODERATION_BATCH_SIZE must be positive")
+	}
+
+	if c.Scanner.Provider != "" {
+		if c.Scanner.Provider != "clamav" && c.Scanner.Provider != "icap" {
+			return fmt.Errorf("SCANNER_PROVIDER must be clamav or icap")
+		}
+		if c.Scanner.Address == "" {
+			return fmt.Errorf("SCANNER_ADDRESS is required when SCANNER_PROVIDER is set")
+		}
+		if c.Scanner.TimeoutSeconds <= 0 {
+			return fmt.Errorf("SCANNER_TIMEOUT_SECONDS must be positive")
+		}
+		if c.Scanner.QuarantineBucket == "" {
+			return fmt.Errorf("SCANNER_QUARANTINE_BUCKET is required when SCANNER_PROVIDER is set")
+		}
+	}
+
+	for _, pattern := range c.Uploads.AllowedTypes {
+		if !filetype.ValidPattern(pattern) {
+			return fmt.Errorf("UPLOAD_ALLOWED_TYPES: %q is not a MIME type or a type such as image/*", pattern)
+		}
+	}
+
+	if c.API.DefaultVersion == "" {
+		return fmt.Errorf("API_DEFAULT_VERSION is required")
+	}
+	if _, err := c.API.Schedules(); err != nil {
+		return fmt.Errorf("invalid API_DEPRECATIONS: %w", err)
+	}
+
+	for name, spec := range c.Scheduler.Schedules() {
+		if _, err := cron.Parse(spec); err != nil {
+			return fmt.Errorf("invalid schedule for %s: %w", name, err)
+		}
+	}
+
+	if c.AccessLog.SampleRate < 0 || c.AccessLog.SampleRate > 1 {
+		return fmt.Errorf("ACCESS_LOG_SAMPLE_RATE must be between 0 and 1")
+	}
+
+	switch c.AccessLog.Export {
+	case "", "kafka", "s3":
+	default:
+		return fmt.Errorf("ACCESS_LOG_EXPORT must be kafka, s3 or empty, got %q", c.AccessLog.Export)
+	}
+
+	if c.AccessLog.Export != "" && (c.AccessLog.BatchSize <= 0 || c.AccessLog.FlushSeconds <= 0 || c.AccessLog.QueueSize <= 0) {
+		return fmt.Errorf("ACCESS_LOG_BATCH_SIZE, ACCESS_LOG_FLUSH_SECONDS and ACCESS_LOG_QUEUE_SIZE must be positive")
+	}
+
+	if c.Monitoring.TenantLabelLimit < 0 {
+		return fmt.Errorf("TENANT_METRICS_MAX_TENANTS must not be negative")
+	}
+
+	if c.BodyLimits.DefaultBytes <= 0 || c.BodyLimits.UploadBytes <= 0 {
+		return fmt.Errorf("MAX_REQUEST_BODY_BYTES and MAX_UPLOAD_BYTES must be positive")
+	}
+
+	if c.BodyLimits.BulkUploadBytes <= 0 || c.BodyLimits.BulkUploadFiles <= 0 {
+		return fmt.Errorf("MAX_BULK_UPLOAD_BYTES and MAX_BULK_UPLOAD_FILES must be positive")
+	}
+	if c.BodyLimits.UploadPartBytes < minUploadPartBytes {
+		return fmt.Errorf("UPLOAD_PART_BYTES must be at least %d", minUploadPartBytes)
+	}
+	if c.BodyLimits.ResumableUploadBytes <= 0 || c.BodyLimits.ResumableUploadBytes > c.BodyLimits.UploadPartBytes*maxUploadParts {
+		return fmt.Errorf("MAX_RESUMABLE_UPLOAD_BYTES must be positive and at most %d parts of UPLOAD_PART_BYTES", maxUploadParts)
+	}
+	if c.BodyLimits.DirectUploadBytes <= 0 || c.BodyLimits.DirectUploadBytes > maxSinglePutBytes {
+		return fmt.Errorf("MAX_DIRECT_UPLOAD_BYTES must be positive and at most %d", int64(maxSinglePutBytes))
+	}
+	if c.BodyLimits.ImportBytes <= 0 || c.BodyLimits.ImportFiles <= 0 {
+		return fmt.Errorf("MAX_IMPORT_BYTES and MAX_IMPORT_FILES must be positive")
+	}
+
+	if _, err := c.BodyLimits.RouteLimits(); err != nil {
+		return fmt.Errorf("invalid MAX_REQUEST_BODY_OVERRIDES: %w", err)
+	}
+
+	if c.SLO.Enabled {
+		if c.SLO.WindowDays <= 0 || c.SLO.LatencyP95Ms <= 0 || c.SLO.EvaluationSeconds <= 0 {
+			return fmt.Errorf("SLO_WINDOW_DAYS, SLO_LATENCY_P95_MS and SLO_EVALUATION_SECONDS must be positive")
+		}
+		for name, target := range map[string]float64{
+			"SLO_AVAILABILITY_TARGET": c.SLO.AvailabilityTarget,
+			"SLO_PROCESSING_TARGET":   c.SLO.ProcessingTarget,
+		} {
+			if target <= 0 || target >= 1 {
+				return fmt.Errorf("%s must be between 0 and 1 exclusive", name)
+			}
+		}
+		if c.SLO.FastBurnRate <= 0 || c.SLO.SlowBurnRate <= 0 {
+			return fmt.Errorf("SLO_FAST_BURN_RATE and SLO_SLOW_BURN_RATE must be positive")
+		}
+		if _, err := c.SLO.LatencyTargets(); err != nil {
+			return fmt.Errorf("invalid SLO_LATENCY_P95_OVERRIDES: %w", err)
+		}
+	}
+
+	if c.Anomaly.Enabled {
+		if c.Anomaly.IntervalSeconds <= 0 || c.Anomaly.WarmupIntervals <= 0 || c.Anomaly.MaxTenants <= 0 {
+			return fmt.Errorf("ANOMALY_INTERVAL_SECONDS, ANOMALY_WARMUP_INTERVALS and ANOMALY_MAX_TENANTS must be positive")
+		}
+		if c.Anomaly.Alpha <= 0 || c.Anomaly.Alpha >= 1 {
+			return fmt.Errorf("ANOMALY_EWMA_ALPHA must be between 0 and 1 exclusive")
+		}
+		if c.Anomaly.ZThreshold <= 0 {
+			return fmt.Errorf("ANOMALY_Z_THRESHOLD must be positive")
+		}
+		if c.Anomaly.MinUploads < 0 || c.Anomaly.MinAgentCostUSD < 0 || c.Anomaly.MinRequests < 0 {
+			return fmt.Errorf("ANOMALY_MIN_UPLOADS, ANOMALY_MIN_AGENT_COST_USD and ANOMALY_MIN_REQUESTS must not be negative")
+		}
+	}
+
+	if c.Synthetic.Enabled {
+		if c.Synthetic.Username == "" || c.Synthetic.Password == "" {
+			return fmt.Errorf("SYNTHETIC_USERNAME and SYNTHETIC_PASSWORD are required when synthetic monitoring is enabled")
+		}
+		if c.Synthetic.SpaceID == "" || c.Synthetic.NotebookID == "" {
+			return fmt.Errorf("SYNTHETIC_SPACE_ID and SYNTHETIC_NOTEBOOK_ID are required when synthetic monitoring is enabled")
+		}
+		if c.Synthetic.SpaceType != "personal" && c.Synthetic.SpaceType != "organization" {
+			return fmt.Errorf("SYNTHETIC_SPACE_TYPE must be personal or organization")
+		}
+		if c.Synthetic.TimeoutSeconds <= 0 {
+			return fmt.Errorf("SYNTHETIC_TIMEOUT_SECONDS must be positive")
+		}
+	}
+
+	if c.GraphQL.Enabled && c.GraphQL.MaxDepth <= 0 {
+		return fmt.Errorf("GRAPHQL_MAX_DEPTH must be positive")
+	}
+
+	if c.GRPC.Enabled {
+		if c.GRPC.Port == "" {
+			return fmt.Errorf("GRPC_PORT is required when gRPC is enabled")
+		}
+		if c.GRPC.Port == c.Server.Port {
+			return fmt.Errorf("GRPC_PORT must differ from PORT")
+		}
+		if c.GRPC.AuthToken == "" {
+			return fmt.Errorf("GRPC_AUTH_TOKEN is required when gRPC is enabled")
+		}
+	}
+
+	if c.Postgres.Enabled {
+		if c.Postgres.URL == "" {
+			return fmt.Errorf("POSTGRES_URL is required when Postgres is enabled")
+		}
+		if c.Postgres.MaxOpenConns <= 0 {
+			return fmt.Errorf("POSTGRES_MAX_OPEN_CONNS must be positive")
+		}
+	}
+
+	if err := c.Runtime.Validate(); err != nil {
+		return fmt.Errorf("invalid runtime configuration: %w", err)
+	}
+
 	if c.Router.Enabled {
 		if c.Router.Service.BaseURL == "" {
 			return fmt.Errorf("ROUTER_SERVICE_BASE_URL is required when router is enabled")
@@ -361,6 +1718,31 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// validateRegion checks the multi-region settings
+func (c *Config) validateRegion() error {
+	if !c.Region.Enabled() {
+		return nil
+	}
+	urls, err := c.Region.RegionURLs()
+	if err != nil {
+		return err
+	}
+	if _, ok := urls[c.Region.Name]; !ok {
+		return fmt.Errorf("REGIONS must include REGION_NAME %q", c.Region.Name)
+	}
+	if c.Region.RefreshSeconds <= 0 || c.Region.HookTimeoutSecs <= 0 {
+		return fmt.Errorf("REGION_REFRESH_SECONDS and REGION_HOOK_TIMEOUT_SECONDS must be positive")
+	}
+	hooks, err := c.Region.Hooks()
+	if err != nil {
+		return err
+	}
+	if len(hooks) > 0 && c.Region.HookSecret == "" {
+		return fmt.Errorf("REGION_HOOK_SECRET is required with REGION_REPLICATION_HOOKS")
+	}
+	return nil
+}
+
 // IsDevelopment returns true if running in development mode
 func (c *Config) IsDevelopment() bool {
 	return c.Server.GinMode == "debug" || c.Server.GinMode == "dev"
@@ -373,6 +1755,14 @@ func (c *Config) IsProduction() bool {
 
 // Helper functions for environment variables
 
+// defaultInstanceID identifies this replica when INSTANCE_ID is not set
+func defaultInstanceID() string {
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		return hostname
+	}
+	return fmt.Sprintf("aether-be-%d", os.Getpid())
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -389,6 +1779,15 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 func getEnvBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		if boolValue, err := strconv.ParseBool(value); err == nil {
@@ -445,3 +1844,4 @@ func getDefaultProxyRoutes() []ProxyRoute {
 		},
 	}
 }
+
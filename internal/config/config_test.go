@@ -0,0 +1,257 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	t.Setenv("NEO4J_PASSWORD", "secret")
+	t.Setenv("KEYCLOAK_CLIENT_SECRET", "secret")
+	t.Setenv("LOG_LEVEL", "DEBUG")
+	t.Setenv("FEATURE_FLAGS", "beta_ui")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "debug", cfg.Runtime.LogLevel)
+	assert.True(t, cfg.Runtime.FeatureFlags["beta_ui"])
+}
+
+func TestLoadTimeouts(t *testing.T) {
+	t.Setenv("NEO4J_PASSWORD", "secret")
+	t.Setenv("KEYCLOAK_CLIENT_SECRET", "secret")
+	t.Setenv("EXTERNAL_HTTP_TIMEOUT_SECONDS", "12")
+	t.Setenv("OPENAI_TIMEOUT_SECONDS", "60")
+	t.Setenv("NEO4J_QUERY_TIMEOUT_SECONDS", "7")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, 12, cfg.Timeouts.ExternalHTTPSeconds)
+	assert.Equal(t, 12, cfg.DeepLake.TimeoutSeconds, "per-service timeouts default to the external one")
+	assert.Equal(t, "12s", cfg.Router.Service.Timeout)
+	assert.Equal(t, 60, cfg.OpenAI.TimeoutSeconds)
+	assert.Equal(t, 7, cfg.Neo4j.QueryTimeoutSeconds)
+	assert.Equal(t, 30, cfg.Timeouts.ShutdownSeconds)
+}
+
+func TestLoadRejectsInvalidRuntimeConfig(t *testing.T) {
+	t.Setenv("NEO4J_PASSWORD", "secret")
+	t.Setenv("KEYCLOAK_CLIENT_SECRET", "secret")
+	t.Setenv("LOG_LEVEL", "verbose")
+
+	_, err := Load()
+	assert.ErrorContains(t, err, "invalid log level")
+}
+
+func TestLoadRejectsZeroReplicas(t *testing.T) {
+	t.Setenv("NEO4J_PASSWORD", "secret")
+	t.Setenv("KEYCLOAK_CLIENT_SECRET", "secret")
+	t.Setenv("CLUSTER_REPLICAS", "0")
+
+	_, err := Load()
+	assert.ErrorContains(t, err, "CLUSTER_REPLICAS")
+}
+
+func TestLoadRejectsInvalidSchedule(t *testing.T) {
+	t.Setenv("NEO4J_PASSWORD", "secret")
+	t.Setenv("KEYCLOAK_CLIENT_SECRET", "secret")
+	t.Setenv("SCHEDULE_COUNT_RECONCILIATION", "every hour")
+
+	_, err := Load()
+	assert.ErrorContains(t, err, "invalid schedule for notebook_count_reconciliation")
+}
+
+func TestLoadRequiresPostgresURL(t *testing.T) {
+	t.Setenv("NEO4J_PASSWORD", "secret")
+	t.Setenv("KEYCLOAK_CLIENT_SECRET", "secret")
+	t.Setenv("POSTGRES_ENABLED", "true")
+
+	_, err := Load()
+	assert.ErrorContains(t, err, "POSTGRES_URL is required")
+}
+
+func TestLoadRequiresSyntheticAccount(t *testing.T) {
+	t.Setenv("NEO4J_PASSWORD", "secret")
+	t.Setenv("KEYCLOAK_CLIENT_SECRET", "secret")
+	t.Setenv("SYNTHETIC_ENABLED", "true")
+
+	_, err := Load()
+	assert.ErrorContains(t, err, "SYNTHETIC_USERNAME and SYNTHETIC_PASSWORD are required")
+}
+
+func TestLoadRequiresGRPCAuthToken(t *testing.T) {
+	t.Setenv("NEO4J_PASSWORD", "secret")
+	t.Setenv("KEYCLOAK_CLIENT_SECRET", "secret")
+	t.Setenv("GRPC_ENABLED", "true")
+
+	_, err := Load()
+	assert.ErrorContains(t, err, "GRPC_AUTH_TOKEN is required")
+}
+
+func TestBodyLimitRouteLimits(t *testing.T) {
+	cfg := BodyLimitConfig{
+		DefaultBytes: 10 << 20,
+		UploadBytes:  30 << 20,
+		Overrides:    "/api/v1/streams/sources/:id/events=1048576, /api/v1/documents/upload=5000",
+
+		BulkUploadBytes: 200 << 20,
+		UploadPartBytes: 8 << 20,
+		ImportBytes:     2 << 30,
+	}
+
+	limits, err := cfg.RouteLimits()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1048576), limits["/api/v1/streams/sources/:id/events"])
+	assert.Equal(t, int64(5000), limits["/api/v1/documents/upload"])
+	assert.Equal(t, int64(40<<20+uploadOverheadBytes), limits["/api/v1/documents/upload-base64"])
+	assert.Equal(t, int64(30<<20+uploadOverheadBytes), limits["/api/v1/documents/:id/versions"])
+	assert.Equal(t, int64(200<<20+uploadOverheadBytes), limits["/api/v1/notebooks/:id/documents/bulk"])
+	assert.Equal(t, int64(8<<20), limits["/api/v1/uploads/:id/parts/:number"])
+	assert.Equal(t, int64(2<<30+uploadOverheadBytes), limits["/api/v1/imports"])
+
+	for _, overrides := range []string{"/api/v1/users", "/api/v1/users=0", "=100", "/api/v1/users=10MB"} {
+		cfg.Overrides = overrides
+		_, err := cfg.RouteLimits()
+		assert.Error(t, err, overrides)
+	}
+}
+
+func TestQuotaWarningThresholds(t *testing.T) {
+	thresholds, err := QuotaConfig{WarningThresholds: "100, 80,90"}.Thresholds()
+	assert.NoError(t, err)
+	assert.Equal(t, []int{80, 90, 100}, thresholds)
+
+	for _, value := range []string{"80,", "0", "120", "ninety"} {
+		_, err := QuotaConfig{WarningThresholds: value}.Thresholds()
+		assert.Error(t, err, value)
+	}
+}
+
+func TestSLOLatencyTargets(t *testing.T) {
+	cfg := SLOConfig{LatencyOverrides: "documents=5000, agents=15000"}
+
+	targets, err := cfg.LatencyTargets()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"documents": 5000, "agents": 15000}, targets)
+
+	for _, overrides := range []string{"documents", "documents=0", "=100", "documents=5s"} {
+		cfg.LatencyOverrides = overrides
+		_, err := cfg.LatencyTargets()
+		assert.Error(t, err, overrides)
+	}
+}
+
+func TestAPIVersionSchedules(t *testing.T) {
+	cfg := APIVersionConfig{Deprecations: "v1=2026-11-01/2027-05-01, v2=2027-01-15"}
+
+	schedules, err := cfg.Schedules()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), schedules["v1"].Deprecated)
+	assert.Equal(t, time.Date(2027, 5, 1, 0, 0, 0, 0, time.UTC), schedules["v1"].Sunset)
+	assert.True(t, schedules["v2"].Sunset.IsZero())
+
+	for _, deprecations := range []string{"v1", "=2026-11-01", "v1=11/01/2026", "v1=2026-11-01/soon", "v1=2026-11-01/2026-10-01"} {
+		cfg.Deprecations = deprecations
+		_, err := cfg.Schedules()
+		assert.Error(t, err, deprecations)
+	}
+}
+
+func TestLoadRejectsInvalidAccessLogExport(t *testing.T) {
+	t.Setenv("NEO4J_PASSWORD", "secret")
+	t.Setenv("KEYCLOAK_CLIENT_SECRET", "secret")
+	t.Setenv("ACCESS_LOG_EXPORT", "syslog")
+	_, err := Load()
+	assert.ErrorContains(t, err, "ACCESS_LOG_EXPORT")
+
+	t.Setenv("ACCESS_LOG_EXPORT", "kafka")
+	t.Setenv("ACCESS_LOG_SAMPLE_RATE", "1.5")
+	_, err = Load()
+	assert.ErrorContains(t, err, "ACCESS_LOG_SAMPLE_RATE")
+}
+
+func TestLoadRejectsInvalidScanner(t *testing.T) {
+	t.Setenv("NEO4J_PASSWORD", "secret")
+	t.Setenv("KEYCLOAK_CLIENT_SECRET", "secret")
+	t.Setenv("SCANNER_PROVIDER", "mcafee")
+	_, err := Load()
+	assert.ErrorContains(t, err, "SCANNER_PROVIDER")
+
+	t.Setenv("SCANNER_PROVIDER", "clamav")
+	t.Setenv("SCANNER_TIMEOUT_SECONDS", "0")
+	_, err = Load()
+	assert.ErrorContains(t, err, "SCANNER_TIMEOUT_SECONDS")
+}
+
+func TestLoadUploadAllowedTypes(t *testing.T) {
+	t.Setenv("NEO4J_PASSWORD", "secret")
+	t.Setenv("KEYCLOAK_CLIENT_SECRET", "secret")
+	t.Setenv("UPLOAD_ALLOWED_TYPES", "application/pdf,image/*")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.Uploads.Validate)
+	assert.Equal(t, []string{"application/pdf", "image/*"}, cfg.Uploads.AllowedTypes)
+
+	t.Setenv("UPLOAD_ALLOWED_TYPES", "pdf")
+	_, err = Load()
+	assert.ErrorContains(t, err, "UPLOAD_ALLOWED_TYPES")
+}
+
+func TestProcessingPriorityCaps(t *testing.T) {
+	cfg := ProcessingPriorityConfig{PlanCaps: "Enterprise=realtime, default=high,free=normal"}
+
+	caps, err := cfg.Caps()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"enterprise": PriorityRealtime, DefaultProcessingPlan: PriorityHigh, "free": PriorityNormal}, caps)
+	assert.Equal(t, PriorityRealtime, cfg.Cap("enterprise"))
+	assert.Equal(t, PriorityNormal, cfg.Cap("Free"))
+	assert.Equal(t, PriorityHigh, cfg.Cap("pro"))
+	assert.Equal(t, PriorityRealtime, ProcessingPriorityConfig{}.Cap("free"), "plans are not capped by default")
+
+	for _, value := range []string{"enterprise", "enterprise=urgent", "=high"} {
+		_, err := ProcessingPriorityConfig{PlanCaps: value}.Caps()
+		assert.Error(t, err, value)
+	}
+}
+
+func TestLoadRejectsInvalidProcessingPriority(t *testing.T) {
+	t.Setenv("NEO4J_PASSWORD", "secret")
+	t.Setenv("KEYCLOAK_CLIENT_SECRET", "secret")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, PriorityNormal, cfg.Priority.Default)
+	assert.Equal(t, PriorityRealtime, cfg.Priority.Cap("enterprise"))
+
+	t.Setenv("PROCESSING_PRIORITY_DEFAULT", "urgent")
+	_, err = Load()
+	assert.ErrorContains(t, err, "PROCESSING_PRIORITY_DEFAULT")
+}
+
+func TestLoadRejectsInvalidCompareLimits(t *testing.T) {
+	t.Setenv("NEO4J_PASSWORD", "secret")
+	t.Setenv("KEYCLOAK_CLIENT_SECRET", "secret")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, 2<<20, cfg.Compare.MaxTextBytes)
+	assert.Equal(t, 3600, cfg.Compare.CacheSeconds)
+
+	t.Setenv("COMPARE_MAX_LINES", "-1")
+	_, err = Load()
+	assert.ErrorContains(t, err, "COMPARE_MAX_LINES")
+}
+
+func TestLoadRejectsInvalidDownloadURLLifetime(t *testing.T) {
+	t.Setenv("NEO4J_PASSWORD", "secret")
+	t.Setenv("KEYCLOAK_CLIENT_SECRET", "secret")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Zero(t, cfg.Downloads.RedirectBytes, "files are streamed unless asked otherwise")
+	assert.Equal(t, 900, cfg.Downloads.URLSeconds)
+
+	t.Setenv("DOWNLOAD_URL_SECONDS", "604801")
+	_, err = Load()
+	assert.ErrorContains(t, err, "DOWNLOAD_URL_SECONDS")
+}
@@ -0,0 +1,451 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/joho/godotenv"
+)
+
+// RuntimeConfig is the subset of configuration that can be changed while the
+// server is running, via SIGHUP or the admin API
+type RuntimeConfig struct {
+	LogLevel     string            `json:"log_level"`
+	RateLimit    RateLimitConfig   `json:"rate_limit"`
+	FeatureFlags map[string]bool   `json:"feature_flags"`
+	AudiModal    AudiModalTimeouts `json:"audimodal"`
+}
+
+// RateLimitConfig holds request rate limit settings. Users are limited per
+// route class, by the class's entry in Classes or else by
+// RequestsPerMinute and Burst; tenants likewise, when
+// TenantRequestsPerMinute or TenantClasses set a limit. The processing
+// submissions of each tenant are limited by the rule of its billing plan
+// in ProcessingPlans.
+type RateLimitConfig struct {
+	Enabled           bool                     `json:"enabled"`
+	RequestsPerMinute int                      `json:"requests_per_minute"`
+	Burst             int                      `json:"burst"`
+	Classes           map[string]RateLimitRule `json:"classes,omitempty"`
+
+	TenantRequestsPerMinute int                      `json:"tenant_requests_per_minute"` // 0 leaves tenants unlimited
+	TenantBurst             int                      `json:"tenant_burst"`
+	TenantClasses           map[string]RateLimitRule `json:"tenant_classes,omitempty"`
+
+	ProcessingPlans map[string]RateLimitRule `json:"processing_plans,omitempty"`
+	// DebtCost is the tokens a request taken on overdraft costs, so that a
+	// spike over the limit is paid back with slower requests afterwards.
+	// Below 1 it counts as 1.
+	DebtCost float64 `json:"debt_cost"`
+}
+
+// RateLimitRule is a token bucket: Burst requests at once, refilled at
+// RequestsPerMinute. A rule of 0 requests per minute does not limit. Once
+// the bucket is empty, Overdraft more requests may be taken on credit; the
+// bucket then owes their cost and refills from below zero.
+type RateLimitRule struct {
+	RequestsPerMinute int `json:"requests_per_minute"`
+	Burst             int `json:"burst"`
+	Overdraft         int `json:"overdraft,omitempty"`
+}
+
+// DefaultProcessingPlan is the entry of ProcessingPlans that applies to
+// plans without their own
+const DefaultProcessingPlan = "default"
+
+// Route classes rate limits are set per
+const (
+	RateLimitRead   = "read"   // GET requests
+	RateLimitWrite  = "write"  // Other changes
+	RateLimitUpload = "upload" // Uploads, imports and exports
+	RateLimitAgent  = "agent"  // Agent and workflow executions and model completions
+)
+
+// RateLimitClasses lists the route classes rate limits are set per
+var RateLimitClasses = []string{RateLimitRead, RateLimitWrite, RateLimitUpload, RateLimitAgent}
+
+// UserRule returns the limit of each user's requests of a route class
+func (r RateLimitConfig) UserRule(class string) RateLimitRule {
+	if rule, ok := r.Classes[class]; ok {
+		return rule
+	}
+	return RateLimitRule{RequestsPerMinute: r.RequestsPerMinute, Burst: r.Burst}
+}
+
+// TenantRule returns the limit of the requests of a route class made in
+// the spaces of each tenant, all users together
+func (r RateLimitConfig) TenantRule(class string) RateLimitRule {
+	if rule, ok := r.TenantClasses[class]; ok {
+		return rule
+	}
+	return RateLimitRule{RequestsPerMinute: r.TenantRequestsPerMinute, Burst: r.TenantBurst}
+}
+
+// ProcessingRule returns the limit of the processing submissions of each
+// tenant on a billing plan, or a rule that does not limit when neither the
+// plan nor DefaultProcessingPlan has one
+func (r RateLimitConfig) ProcessingRule(plan string) RateLimitRule {
+	if rule, ok := r.ProcessingPlans[strings.ToLower(plan)]; ok {
+		return rule
+	}
+	return r.ProcessingPlans[DefaultProcessingPlan]
+}
+
+// AudiModalTimeouts holds the reloadable AudiModal client timeouts
+type AudiModalTimeouts struct {
+	RequestTimeoutSeconds int `json:"request_timeout_seconds"`
+}
+
+// RuntimeChange describes a single changed runtime setting
+type RuntimeChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+var (
+	validLogLevels  = map[string]bool{"debug": true, "info": true, "warn": true, "error": true}
+	featureFlagName = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]*$`)
+)
+
+// runtimeEnvKeys are the environment variables read by loadRuntimeConfig.
+// Only these are taken from the env file on reload.
+var runtimeEnvKeys = []string{
+	"LOG_LEVEL",
+	"RATE_LIMIT_ENABLED",
+	"RATE_LIMIT_REQUESTS_PER_MINUTE",
+	"RATE_LIMIT_BURST",
+	"RATE_LIMIT_CLASSES",
+	"RATE_LIMIT_TENANT_REQUESTS_PER_MINUTE",
+	"RATE_LIMIT_TENANT_BURST",
+	"RATE_LIMIT_TENANT_CLASSES",
+	"RATE_LIMIT_PROCESSING_PLANS",
+	"RATE_LIMIT_DEBT_COST",
+	"FEATURE_FLAGS",
+	"AUDIMODAL_PROCESSING_TIMEOUT",
+}
+
+// loadRuntimeConfig reads the runtime settings from the environment
+func loadRuntimeConfig() RuntimeConfig {
+	return RuntimeConfig{
+		LogLevel: strings.ToLower(getEnv("LOG_LEVEL", "info")),
+		RateLimit: RateLimitConfig{
+			Enabled:           getEnvBool("RATE_LIMIT_ENABLED", false),
+			RequestsPerMinute: getEnvInt("RATE_LIMIT_REQUESTS_PER_MINUTE", 600),
+			Burst:             getEnvInt("RATE_LIMIT_BURST", 100),
+			Classes:           parseRateLimitRules(os.Getenv("RATE_LIMIT_CLASSES")),
+
+			TenantRequestsPerMinute: getEnvInt("RATE_LIMIT_TENANT_REQUESTS_PER_MINUTE", 0),
+			TenantBurst:             getEnvInt("RATE_LIMIT_TENANT_BURST", 0),
+			TenantClasses:           parseRateLimitRules(os.Getenv("RATE_LIMIT_TENANT_CLASSES")),
+
+			ProcessingPlans: parseRateLimitRules(os.Getenv("RATE_LIMIT_PROCESSING_PLANS")),
+			DebtCost:        getEnvFloat("RATE_LIMIT_DEBT_COST", 2),
+		},
+		FeatureFlags: parseFeatureFlags(os.Getenv("FEATURE_FLAGS")),
+		AudiModal: AudiModalTimeouts{
+			RequestTimeoutSeconds: getEnvInt("AUDIMODAL_PROCESSING_TIMEOUT", 300),
+		},
+	}
+}
+
+// LoadRuntimeConfig re-reads the runtime settings. Runtime values in
+// envFile, if it exists, override the process environment so a mounted file
+// can be edited and picked up on SIGHUP. Other keys in the file are ignored;
+// they only take effect on restart.
+func LoadRuntimeConfig(envFile string) (RuntimeConfig, error) {
+	if envFile != "" {
+		values, err := godotenv.Read(envFile)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return RuntimeConfig{}, fmt.Errorf("failed to read %s: %w", envFile, err)
+		}
+		for _, key := range runtimeEnvKeys {
+			if value, ok := values[key]; ok {
+				if err := os.Setenv(key, value); err != nil {
+					return RuntimeConfig{}, fmt.Errorf("failed to apply %s: %w", key, err)
+				}
+			}
+		}
+	}
+
+	runtime := loadRuntimeConfig()
+	if err := runtime.Validate(); err != nil {
+		return RuntimeConfig{}, err
+	}
+	return runtime, nil
+}
+
+// parseFeatureFlags parses "name=true,other=false"; a bare name enables the flag
+func parseFeatureFlags(value string) map[string]bool {
+	flags := make(map[string]bool)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, raw, found := strings.Cut(item, "=")
+		enabled := true
+		if found {
+			parsed, err := strconv.ParseBool(strings.TrimSpace(raw))
+			if err != nil {
+				continue
+			}
+			enabled = parsed
+		}
+		flags[strings.ToLower(strings.TrimSpace(name))] = enabled
+	}
+	return flags
+}
+
+// parseRateLimitRules parses "upload=60/10,agent=120/20+40", the requests
+// per minute, burst and optional overdraft of route classes or plans;
+// malformed entries are skipped
+func parseRateLimitRules(value string) map[string]RateLimitRule {
+	rules := make(map[string]RateLimitRule)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		class, raw, found := strings.Cut(item, "=")
+		if !found {
+			continue
+		}
+		rpm, burst, found := strings.Cut(raw, "/")
+		if !found {
+			continue
+		}
+		requestsPerMinute, err := strconv.Atoi(strings.TrimSpace(rpm))
+		if err != nil {
+			continue
+		}
+		burst, overdraft, hasOverdraft := strings.Cut(burst, "+")
+		burstSize, err := strconv.Atoi(strings.TrimSpace(burst))
+		if err != nil {
+			continue
+		}
+		rule := RateLimitRule{RequestsPerMinute: requestsPerMinute, Burst: burstSize}
+		if hasOverdraft {
+			if rule.Overdraft, err = strconv.Atoi(strings.TrimSpace(overdraft)); err != nil {
+				continue
+			}
+		}
+		rules[strings.ToLower(strings.TrimSpace(class))] = rule
+	}
+	return rules
+}
+
+// Validate validates the runtime configuration
+func (r RuntimeConfig) Validate() error {
+	if !validLogLevels[r.LogLevel] {
+		return fmt.Errorf("invalid log level %q (expected debug, info, warn or error)", r.LogLevel)
+	}
+
+	if r.RateLimit.RequestsPerMinute < 0 || r.RateLimit.Burst < 0 {
+		return fmt.Errorf("rate limit values must not be negative")
+	}
+	if r.RateLimit.Enabled && r.RateLimit.RequestsPerMinute == 0 {
+		return fmt.Errorf("rate limit requests_per_minute must be positive when rate limiting is enabled")
+	}
+	if r.RateLimit.TenantRequestsPerMinute < 0 || r.RateLimit.TenantBurst < 0 {
+		return fmt.Errorf("tenant rate limit values must not be negative")
+	}
+	for _, rules := range []map[string]RateLimitRule{r.RateLimit.Classes, r.RateLimit.TenantClasses} {
+		for class, rule := range rules {
+			if !isRateLimitClass(class) {
+				return fmt.Errorf("unknown rate limit class %q (expected %s)", class, strings.Join(RateLimitClasses, ", "))
+			}
+			if rule.RequestsPerMinute < 0 || rule.Burst < 0 || rule.Overdraft < 0 {
+				return fmt.Errorf("rate limit values of class %q must not be negative", class)
+			}
+		}
+	}
+	for plan, rule := range r.RateLimit.ProcessingPlans {
+		if plan == "" {
+			return fmt.Errorf("processing rate limit plans must be named")
+		}
+		if rule.RequestsPerMinute < 0 || rule.Burst < 0 || rule.Overdraft < 0 {
+			return fmt.Errorf("processing rate limit values of plan %q must not be negative", plan)
+		}
+	}
+	if r.RateLimit.DebtCost < 0 {
+		return fmt.Errorf("rate limit debt_cost must not be negative")
+	}
+
+	for name := range r.FeatureFlags {
+		if !featureFlagName.MatchString(name) {
+			return fmt.Errorf("invalid feature flag name %q", name)
+		}
+	}
+
+	if r.AudiModal.RequestTimeoutSeconds < 1 || r.AudiModal.RequestTimeoutSeconds > 3600 {
+		return fmt.Errorf("audimodal request_timeout_seconds must be between 1 and 3600")
+	}
+
+	return nil
+}
+
+func isRateLimitClass(class string) bool {
+	for _, known := range RateLimitClasses {
+		if class == known {
+			return true
+		}
+	}
+	return false
+}
+
+// clone returns a copy that does not share the feature flag and rate limit
+// class maps
+func (r RuntimeConfig) clone() RuntimeConfig {
+	flags := make(map[string]bool, len(r.FeatureFlags))
+	for name, enabled := range r.FeatureFlags {
+		flags[name] = enabled
+	}
+	r.FeatureFlags = flags
+	r.RateLimit.Classes = cloneRateLimitRules(r.RateLimit.Classes)
+	r.RateLimit.TenantClasses = cloneRateLimitRules(r.RateLimit.TenantClasses)
+	r.RateLimit.ProcessingPlans = cloneRateLimitRules(r.RateLimit.ProcessingPlans)
+	return r
+}
+
+func cloneRateLimitRules(rules map[string]RateLimitRule) map[string]RateLimitRule {
+	if rules == nil {
+		return nil
+	}
+	cloned := make(map[string]RateLimitRule, len(rules))
+	for class, rule := range rules {
+		cloned[class] = rule
+	}
+	return cloned
+}
+
+// DiffRuntime lists the settings that differ between two runtime configurations
+func DiffRuntime(old, next RuntimeConfig) []RuntimeChange {
+	var changes []RuntimeChange
+	add := func(field string, a, b interface{}) {
+		if !reflect.DeepEqual(a, b) {
+			changes = append(changes, RuntimeChange{Field: field, Old: a, New: b})
+		}
+	}
+
+	add("log_level", old.LogLevel, next.LogLevel)
+	add("rate_limit.enabled", old.RateLimit.Enabled, next.RateLimit.Enabled)
+	add("rate_limit.requests_per_minute", old.RateLimit.RequestsPerMinute, next.RateLimit.RequestsPerMinute)
+	add("rate_limit.burst", old.RateLimit.Burst, next.RateLimit.Burst)
+	add("rate_limit.classes", nonEmptyRules(old.RateLimit.Classes), nonEmptyRules(next.RateLimit.Classes))
+	add("rate_limit.tenant_requests_per_minute", old.RateLimit.TenantRequestsPerMinute, next.RateLimit.TenantRequestsPerMinute)
+	add("rate_limit.tenant_burst", old.RateLimit.TenantBurst, next.RateLimit.TenantBurst)
+	add("rate_limit.tenant_classes", nonEmptyRules(old.RateLimit.TenantClasses), nonEmptyRules(next.RateLimit.TenantClasses))
+	add("rate_limit.processing_plans", nonEmptyRules(old.RateLimit.ProcessingPlans), nonEmptyRules(next.RateLimit.ProcessingPlans))
+	add("rate_limit.debt_cost", old.RateLimit.DebtCost, next.RateLimit.DebtCost)
+	add("audimodal.request_timeout_seconds", old.AudiModal.RequestTimeoutSeconds, next.AudiModal.RequestTimeoutSeconds)
+
+	names := make(map[string]bool)
+	for name := range old.FeatureFlags {
+		names[name] = true
+	}
+	for name := range next.FeatureFlags {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	for _, name := range sorted {
+		oldValue, oldSet := old.FeatureFlags[name]
+		newValue, newSet := next.FeatureFlags[name]
+		if oldSet != newSet || oldValue != newValue {
+			changes = append(changes, RuntimeChange{
+				Field: "feature_flags." + name,
+				Old:   flagValue(oldValue, oldSet),
+				New:   flagValue(newValue, newSet),
+			})
+		}
+	}
+
+	return changes
+}
+
+// nonEmptyRules reports no class or plan rules as nil, so an empty map and a
+// missing one compare equal
+func nonEmptyRules(rules map[string]RateLimitRule) map[string]RateLimitRule {
+	if len(rules) == 0 {
+		return nil
+	}
+	return rules
+}
+
+// flagValue reports an unset flag as nil
+func flagValue(value, set bool) interface{} {
+	if !set {
+		return nil
+	}
+	return value
+}
+
+// RuntimeStore holds the current runtime configuration and notifies
+// subscribers when it changes
+type RuntimeStore struct {
+	mu          sync.RWMutex
+	current     RuntimeConfig
+	subscribers []func(RuntimeConfig)
+}
+
+// NewRuntimeStore creates a store holding the initial runtime configuration
+func NewRuntimeStore(initial RuntimeConfig) *RuntimeStore {
+	return &RuntimeStore{current: initial.clone()}
+}
+
+// Current returns a copy of the current runtime configuration
+func (s *RuntimeStore) Current() RuntimeConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current.clone()
+}
+
+// RateLimit returns the current rate limit settings without copying the
+// rest of the configuration; it is read on every request
+func (s *RuntimeStore) RateLimit() RateLimitConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current.RateLimit
+}
+
+// Subscribe registers fn to be called with the new configuration after every change
+func (s *RuntimeStore) Subscribe(fn func(RuntimeConfig)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscribers = append(s.subscribers, fn)
+}
+
+// Apply validates and stores next, notifies subscribers and returns the
+// changes. Nothing is stored when validation fails or nothing changed.
+func (s *RuntimeStore) Apply(next RuntimeConfig) ([]RuntimeChange, error) {
+	if err := next.Validate(); err != nil {
+		return nil, err
+	}
+	next = next.clone()
+
+	s.mu.Lock()
+	changes := DiffRuntime(s.current, next)
+	if len(changes) == 0 {
+		s.mu.Unlock()
+		return nil, nil
+	}
+	s.current = next
+	subscribers := append([]func(RuntimeConfig){}, s.subscribers...)
+	s.mu.Unlock()
+
+	for _, fn := range subscribers {
+		fn(next.clone())
+	}
+	return changes, nil
+}
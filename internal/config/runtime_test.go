@@ -0,0 +1,131 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validRuntime() RuntimeConfig {
+	return RuntimeConfig{
+		LogLevel:     "info",
+		RateLimit:    RateLimitConfig{Enabled: true, RequestsPerMinute: 600, Burst: 100},
+		FeatureFlags: map[string]bool{"default_dataset": false},
+		AudiModal:    AudiModalTimeouts{RequestTimeoutSeconds: 300},
+	}
+}
+
+func TestRuntimeConfigValidate(t *testing.T) {
+	assert.NoError(t, validRuntime().Validate())
+
+	invalid := validRuntime()
+	invalid.LogLevel = "verbose"
+	assert.Error(t, invalid.Validate())
+
+	invalid = validRuntime()
+	invalid.RateLimit.RequestsPerMinute = 0
+	assert.Error(t, invalid.Validate())
+
+	invalid = validRuntime()
+	invalid.FeatureFlags["Bad Flag"] = true
+	assert.Error(t, invalid.Validate())
+
+	invalid = validRuntime()
+	invalid.AudiModal.RequestTimeoutSeconds = 0
+	assert.Error(t, invalid.Validate())
+
+	invalid = validRuntime()
+	invalid.RateLimit.TenantClasses = map[string]RateLimitRule{"downloads": {RequestsPerMinute: 10, Burst: 5}}
+	assert.Error(t, invalid.Validate())
+}
+
+func TestParseFeatureFlags(t *testing.T) {
+	flags := parseFeatureFlags("beta_ui, default_dataset=false ,broken=maybe,")
+	assert.Equal(t, map[string]bool{"beta_ui": true, "default_dataset": false}, flags)
+}
+
+func TestParseRateLimitRules(t *testing.T) {
+	rules := parseRateLimitRules("upload=60/10, Agent=120/20,read=fast,write=5")
+	assert.Equal(t, map[string]RateLimitRule{
+		RateLimitUpload: {RequestsPerMinute: 60, Burst: 10},
+		RateLimitAgent:  {RequestsPerMinute: 120, Burst: 20},
+	}, rules)
+
+	limits := RateLimitConfig{RequestsPerMinute: 600, Burst: 100, Classes: rules}
+	assert.Equal(t, RateLimitRule{RequestsPerMinute: 60, Burst: 10}, limits.UserRule(RateLimitUpload))
+	assert.Equal(t, RateLimitRule{RequestsPerMinute: 600, Burst: 100}, limits.UserRule(RateLimitRead))
+	assert.Equal(t, RateLimitRule{}, limits.TenantRule(RateLimitRead), "tenants are not limited by default")
+}
+
+func TestProcessingRule(t *testing.T) {
+	plans := parseRateLimitRules("free=10/5+10, default=60/20,pro=120/x+5")
+	assert.Equal(t, map[string]RateLimitRule{
+		"free":                {RequestsPerMinute: 10, Burst: 5, Overdraft: 10},
+		DefaultProcessingPlan: {RequestsPerMinute: 60, Burst: 20},
+	}, plans)
+
+	limits := RateLimitConfig{ProcessingPlans: plans}
+	assert.Equal(t, plans["free"], limits.ProcessingRule("Free"))
+	assert.Equal(t, plans[DefaultProcessingPlan], limits.ProcessingRule("enterprise"))
+	assert.Equal(t, RateLimitRule{}, RateLimitConfig{}.ProcessingRule("free"), "processing is not limited by default")
+
+	invalid := validRuntime()
+	invalid.RateLimit.ProcessingPlans = map[string]RateLimitRule{"free": {RequestsPerMinute: 10, Burst: 5, Overdraft: -1}}
+	assert.Error(t, invalid.Validate())
+}
+
+func TestRuntimeStoreApply(t *testing.T) {
+	store := NewRuntimeStore(validRuntime())
+
+	var notified []RuntimeConfig
+	store.Subscribe(func(c RuntimeConfig) { notified = append(notified, c) })
+
+	next := store.Current()
+	next.LogLevel = "debug"
+	next.FeatureFlags["beta_ui"] = true
+
+	changes, err := store.Apply(next)
+	require.NoError(t, err)
+	assert.Equal(t, []RuntimeChange{
+		{Field: "log_level", Old: "info", New: "debug"},
+		{Field: "feature_flags.beta_ui", Old: nil, New: true},
+	}, changes)
+	require.Len(t, notified, 1)
+	assert.True(t, store.Current().FeatureFlags["beta_ui"])
+
+	// No-op and invalid updates do not notify
+	changes, err = store.Apply(store.Current())
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	bad := store.Current()
+	bad.LogLevel = "loud"
+	_, err = store.Apply(bad)
+	assert.Error(t, err)
+	assert.Len(t, notified, 1)
+	assert.Equal(t, "debug", store.Current().LogLevel)
+}
+
+func TestLoadRuntimeConfigAppliesOnlyRuntimeKeys(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "info")
+	t.Setenv("NEO4J_PASSWORD", "original")
+
+	envFile := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(envFile, []byte("LOG_LEVEL=debug\nNEO4J_PASSWORD=changed\n"), 0o600))
+
+	runtime, err := LoadRuntimeConfig(envFile)
+	require.NoError(t, err)
+	assert.Equal(t, "debug", runtime.LogLevel)
+	assert.Equal(t, "original", os.Getenv("NEO4J_PASSWORD"), "non-runtime keys must not be reloaded")
+}
+
+func TestLoadRuntimeConfigMissingFile(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "warn")
+
+	runtime, err := LoadRuntimeConfig(filepath.Join(t.TempDir(), "missing.env"))
+	require.NoError(t, err)
+	assert.Equal(t, "warn", runtime.LogLevel)
+}
@@ -0,0 +1,92 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func at(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestParseNext(t *testing.T) {
+	tests := []struct {
+		spec string
+		from string
+		want string
+	}{
+		{"* * * * *", "2026-03-10T10:15:30Z", "2026-03-10T10:16:00Z"},
+		{"0 * * * *", "2026-03-10T10:15:00Z", "2026-03-10T11:00:00Z"},
+		{"*/15 * * * *", "2026-03-10T10:15:00Z", "2026-03-10T10:30:00Z"},
+		{"30 2 * * *", "2026-03-10T10:15:00Z", "2026-03-11T02:30:00Z"},
+		{"0 9-17/4 * * *", "2026-03-10T14:00:00Z", "2026-03-10T17:00:00Z"},
+		{"0 0 1 * *", "2026-03-10T10:15:00Z", "2026-04-01T00:00:00Z"},
+		{"0 0 * * 1,3", "2026-03-10T10:15:00Z", "2026-03-11T00:00:00Z"}, // Tuesday -> Wednesday
+		{"0 0 * * 7", "2026-03-10T10:15:00Z", "2026-03-15T00:00:00Z"},   // Sunday as 7
+		{"0 0 29 2 *", "2026-03-10T10:15:00Z", "2028-02-29T00:00:00Z"},
+		{"@daily", "2026-12-31T23:59:00Z", "2027-01-01T00:00:00Z"},
+		{"@hourly", "2026-03-10T10:59:59Z", "2026-03-10T11:00:00Z"},
+		{"@every 90s", "2026-03-10T10:15:00Z", "2026-03-10T10:16:30Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule, err := Parse(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, at(tt.want), schedule.Next(at(tt.from)))
+		})
+	}
+}
+
+func TestParseDayFieldsMatchEither(t *testing.T) {
+	// The 15th of the month or any Friday, as in standard cron
+	schedule, err := Parse("0 0 15 * 5")
+	require.NoError(t, err)
+
+	assert.Equal(t, at("2026-03-13T00:00:00Z"), schedule.Next(at("2026-03-10T00:00:00Z")))
+	assert.Equal(t, at("2026-03-15T00:00:00Z"), schedule.Next(at("2026-03-13T00:00:00Z")))
+}
+
+func TestParseNeverMatches(t *testing.T) {
+	schedule, err := Parse("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, schedule.Next(at("2026-03-10T00:00:00Z")).IsZero())
+}
+
+func TestParseRejectsInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@fortnightly",
+		"@every soon",
+		"@every 10ms",
+	} {
+		_, err := Parse(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func BenchmarkNext(b *testing.B) {
+	schedule, err := Parse("*/15 9-17 * * 1-5")
+	require.NoError(b, err)
+	from := at("2026-03-13T17:50:00Z")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		schedule.Next(from)
+	}
+}
@@ -0,0 +1,137 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+)
+
+// DefaultBatchSize is used when no batch size is configured
+const DefaultBatchSize = 500
+
+// BatchRowsParam is the parameter name batch queries must UNWIND, e.g.
+// "UNWIND $rows AS row CREATE (n:Node) SET n = row"
+const BatchRowsParam = "rows"
+
+// BatchResult summarizes a batched write
+type BatchResult struct {
+	Rows                 int
+	Batches              int
+	NodesCreated         int
+	RelationshipsCreated int
+	PropertiesSet        int
+}
+
+// batchSize returns the configured number of rows per transaction
+func (c *Neo4jClient) batchSize() int {
+	if c.config.BatchSize > 0 {
+		return c.config.BatchSize
+	}
+	return DefaultBatchSize
+}
+
+// WriteBatch writes rows with a single UNWIND query, splitting them into
+// transactions of at most the configured batch size. Extra parameters are
+// passed to every batch alongside $rows. Batches already committed are not
+// rolled back if a later batch fails; queries should be idempotent (MERGE)
+// when callers retry.
+func (c *Neo4jClient) WriteBatch(ctx context.Context, query string, rows []map[string]interface{}, params map[string]interface{}) (*BatchResult, error) {
+	if !strings.Contains(query, "$"+BatchRowsParam) {
+		return nil, fmt.Errorf("batch query must UNWIND $%s", BatchRowsParam)
+	}
+
+	// Rows become node properties, so each must hold only Neo4j property types
+	if err := c.validateNeo4jParameters(params); err != nil {
+		return nil, fmt.Errorf("invalid parameters: %w", err)
+	}
+	for i, row := range rows {
+		if err := c.validateNeo4jParameters(row); err != nil {
+			return nil, fmt.Errorf("invalid row %d: %w", i, err)
+		}
+	}
+
+	result := &BatchResult{}
+	for _, batch := range splitBatches(rows, c.batchSize()) {
+		batchParams := make(map[string]interface{}, len(params)+1)
+		for k, v := range params {
+			batchParams[k] = v
+		}
+		batchParams[BatchRowsParam] = batch
+
+		counters, err := c.writeBatch(ctx, query, batchParams, len(batch))
+		if err != nil {
+			return result, fmt.Errorf("batch %d (%d rows) failed: %w", result.Batches+1, len(batch), err)
+		}
+
+		result.Batches++
+		result.Rows += len(batch)
+		result.NodesCreated += counters.NodesCreated()
+		result.RelationshipsCreated += counters.RelationshipsCreated()
+		result.PropertiesSet += counters.PropertiesSet()
+	}
+
+	return result, nil
+}
+
+// writeBatch runs one batch in its own write transaction
+func (c *Neo4jClient) writeBatch(ctx context.Context, query string, params map[string]interface{}, size int) (neo4j.Counters, error) {
+	release, err := c.acquireSlot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	ctx, cancel := c.withQueryTimeout(ctx)
+	defer cancel()
+
+	session := c.Session(ctx)
+	defer session.Close(ctx)
+
+	start := time.Now()
+	counters, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		res, err := tx.Run(ctx, query, params)
+		if err != nil {
+			return nil, err
+		}
+		summary, err := res.Consume(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return summary.Counters(), nil
+	})
+	elapsed := time.Since(start)
+	c.observeQuery(ctx, "write_batch", query, nil, elapsed, err)
+
+	log := c.logger.FromContext(ctx)
+	if err != nil {
+		log.Error("Batch write failed", zap.Int("rows", size), zap.Error(err))
+		return nil, err
+	}
+
+	log.Debug("Batch write completed",
+		zap.Int("rows", size),
+		zap.Float64("duration_ms", elapsed.Seconds()*1000),
+	)
+	return counters.(neo4j.Counters), nil
+}
+
+// splitBatches splits rows into consecutive slices of at most size rows
+func splitBatches(rows []map[string]interface{}, size int) [][]map[string]interface{} {
+	if size <= 0 {
+		size = DefaultBatchSize
+	}
+
+	batches := make([][]map[string]interface{}, 0, (len(rows)+size-1)/size)
+	for start := 0; start < len(rows); start += size {
+		end := start + size
+		if end > len(rows) {
+			end = len(rows)
+		}
+		batches = append(batches, rows[start:end])
+	}
+	return batches
+}
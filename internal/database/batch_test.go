@@ -0,0 +1,70 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitBatches(t *testing.T) {
+	rows := make([]map[string]interface{}, 7)
+	for i := range rows {
+		rows[i] = map[string]interface{}{"i": i}
+	}
+
+	batches := splitBatches(rows, 3)
+	assert.Len(t, batches, 3)
+	assert.Len(t, batches[0], 3)
+	assert.Len(t, batches[2], 1)
+	assert.Equal(t, 6, batches[2][0]["i"])
+
+	assert.Empty(t, splitBatches(nil, 3))
+	assert.Len(t, splitBatches(rows, 0), 1)
+}
+
+func TestWriteBatchRejectsInvalidInput(t *testing.T) {
+	client := &Neo4jClient{}
+	ctx := context.Background()
+	query := "UNWIND $rows AS row CREATE (n:Node) SET n = row"
+
+	_, err := client.WriteBatch(ctx, "CREATE (n:Node)", nil, nil)
+	assert.ErrorContains(t, err, "must UNWIND $rows")
+
+	rows := []map[string]interface{}{
+		{"id": "a", "created_at": time.Now()},
+		{"id": "b", "metadata": map[string]interface{}{"nested": true}},
+	}
+	_, err = client.WriteBatch(ctx, query, rows, nil)
+	assert.ErrorContains(t, err, "invalid row 1")
+
+	_, err = client.WriteBatch(ctx, query, rows[:1], map[string]interface{}{"source": struct{}{}})
+	assert.ErrorContains(t, err, "invalid parameters")
+}
+
+func BenchmarkValidateBatchRows(b *testing.B) {
+	client := &Neo4jClient{}
+	rows := make([]map[string]interface{}, 500)
+	for i := range rows {
+		rows[i] = map[string]interface{}{
+			"id":         "chunk-id",
+			"content":    "chunk content",
+			"index":      i,
+			"score":      0.5,
+			"tags":       []string{"a", "b"},
+			"created_at": time.Now(),
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, row := range rows {
+			if err := client.validateNeo4jParameters(row); err != nil {
+				b.Fatal(err)
+			}
+		}
+		_ = splitBatches(rows, 100)
+	}
+}
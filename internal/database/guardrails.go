@@ -0,0 +1,122 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+)
+
+// Default traversal limits, used when the configuration leaves them unset
+const (
+	DefaultMaxTraversalDepth = 3
+	DefaultMaxEstimatedRows  = 100000
+)
+
+// QueryCostError reports a query the planner expects to touch more rows
+// than allowed
+type QueryCostError struct {
+	Name          string
+	EstimatedRows int64
+	MaxRows       int64
+}
+
+func (e *QueryCostError) Error() string {
+	return fmt.Sprintf("query %s is estimated to touch %d rows, above the limit of %d", e.Name, e.EstimatedRows, e.MaxRows)
+}
+
+// MaxTraversalDepth returns the deepest variable-length traversal allowed
+func (c *Neo4jClient) MaxTraversalDepth() int {
+	if c.config.MaxTraversalDepth > 0 {
+		return c.config.MaxTraversalDepth
+	}
+	return DefaultMaxTraversalDepth
+}
+
+// ClampDepth limits a requested traversal depth to between 1 and
+// MaxTraversalDepth. Depths are written into Cypher patterns, which cannot
+// take them as parameters, so they must always pass through here.
+func (c *Neo4jClient) ClampDepth(depth int) int {
+	if depth < 1 {
+		return 1
+	}
+	if max := c.MaxTraversalDepth(); depth > max {
+		return max
+	}
+	return depth
+}
+
+// EstimateRows asks the planner how many rows a query touches, without
+// running it. The estimate is the largest row count of any operator in the
+// plan, so a traversal that fans out before a LIMIT is still caught.
+func (c *Neo4jClient) EstimateRows(ctx context.Context, query string, params map[string]interface{}) (int64, error) {
+	if err := c.validateNeo4jParameters(params); err != nil {
+		return 0, fmt.Errorf("invalid parameters: %w", err)
+	}
+
+	queryCtx, cancel := c.withQueryTimeout(ctx)
+	defer cancel()
+
+	result, err := neo4j.ExecuteQuery(queryCtx, c.driver, "EXPLAIN "+query, params,
+		neo4j.EagerResultTransformer,
+		neo4j.ExecuteQueryWithDatabase(c.config.Database),
+		neo4j.ExecuteQueryWithReadersRouting())
+	if err != nil {
+		return 0, fmt.Errorf("failed to explain query: %w", err)
+	}
+
+	plan := result.Summary.Plan()
+	if plan == nil {
+		return 0, nil
+	}
+	return maxEstimatedRows(plan), nil
+}
+
+// CheckQueryCost rejects a read query whose estimated rows exceed the
+// configured limit with a *QueryCostError. The check fails open: when the
+// estimate cannot be obtained the query is allowed and the failure logged.
+func (c *Neo4jClient) CheckQueryCost(ctx context.Context, query string, params map[string]interface{}) error {
+	limit := int64(c.config.MaxEstimatedRows)
+	if limit <= 0 {
+		return nil
+	}
+
+	name := queryName(ctx, "traversal")
+	estimate, err := c.EstimateRows(ctx, query, params)
+	if err != nil {
+		c.logger.FromContext(ctx).Warn("Failed to estimate query cost",
+			zap.String("query_name", name),
+			zap.Error(err),
+		)
+		return nil
+	}
+	if estimate > limit {
+		c.logger.FromContext(ctx).Warn("Query rejected by cost estimate",
+			zap.String("query_name", name),
+			zap.Int64("estimated_rows", estimate),
+			zap.Int64("max_rows", limit),
+		)
+		return &QueryCostError{Name: name, EstimatedRows: estimate, MaxRows: limit}
+	}
+	return nil
+}
+
+// maxEstimatedRows returns the largest EstimatedRows argument in the plan tree
+func maxEstimatedRows(plan neo4j.Plan) int64 {
+	var max int64
+	if value, ok := plan.Arguments()["EstimatedRows"]; ok {
+		switch v := value.(type) {
+		case float64:
+			max = int64(v)
+		case int64:
+			max = v
+		}
+	}
+	for _, child := range plan.Children() {
+		if rows := maxEstimatedRows(child); rows > max {
+			max = rows
+		}
+	}
+	return max
+}
@@ -0,0 +1,51 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+)
+
+type fakePlan struct {
+	rows     interface{}
+	children []neo4j.Plan
+}
+
+func (p fakePlan) Operator() string { return "Expand(All)" }
+func (p fakePlan) Arguments() map[string]any {
+	if p.rows == nil {
+		return map[string]any{}
+	}
+	return map[string]any{"EstimatedRows": p.rows}
+}
+func (p fakePlan) Identifiers() []string  { return nil }
+func (p fakePlan) Children() []neo4j.Plan { return p.children }
+
+func TestMaxEstimatedRowsFindsFanOutBelowLimit(t *testing.T) {
+	// A LIMIT at the root hides a traversal that fans out further down
+	plan := fakePlan{rows: 21.0, children: []neo4j.Plan{
+		fakePlan{rows: 250000.5, children: []neo4j.Plan{fakePlan{rows: 1.0}}},
+		fakePlan{},
+	}}
+	assert.Equal(t, int64(250000), maxEstimatedRows(plan))
+	assert.Equal(t, int64(0), maxEstimatedRows(fakePlan{}))
+}
+
+func TestClampDepth(t *testing.T) {
+	client := &Neo4jClient{config: config.DatabaseConfig{MaxTraversalDepth: 4}}
+	assert.Equal(t, 1, client.ClampDepth(0))
+	assert.Equal(t, 3, client.ClampDepth(3))
+	assert.Equal(t, 4, client.ClampDepth(10))
+
+	unset := &Neo4jClient{}
+	assert.Equal(t, DefaultMaxTraversalDepth, unset.ClampDepth(10))
+}
+
+func TestCheckQueryCostDisabled(t *testing.T) {
+	client := &Neo4jClient{config: config.DatabaseConfig{MaxEstimatedRows: 0}}
+	assert.NoError(t, client.CheckQueryCost(context.Background(), "MATCH (n) RETURN n", nil))
+}
@@ -17,9 +17,18 @@ import (
 
 // Neo4jClient wraps the Neo4j driver with additional functionality
 type Neo4jClient struct {
-	driver neo4j.DriverWithContext
-	logger *logger.Logger
-	config config.DatabaseConfig
+	driver  neo4j.DriverWithContext
+	logger  *logger.Logger
+	config  config.DatabaseConfig
+	metrics QueryMetrics
+
+	// gate bounds concurrent operations to the pool size and sheds load
+	// once the wait queue is full. Sessions opened directly with Session
+	// bypass it and are bounded only by the driver's acquisition timeout.
+	gate *poolGate
+
+	// profiler bounds PROFILE re-runs of slow queries
+	profiler *queryProfiler
 }
 
 // NewNeo4jClient creates a new Neo4j client
@@ -30,7 +39,8 @@ func NewNeo4jClient(cfg config.DatabaseConfig, log *logger.Logger) (*Neo4jClient
 	// Configure driver options
 	driverConfig := func(conf *neo4jconfig.Config) {
 		conf.MaxConnectionPoolSize = cfg.MaxConns
-		conf.ConnectionAcquisitionTimeout = 30 * time.Second
+		conf.ConnectionAcquisitionTimeout = time.Duration(cfg.ConnAcquisitionTimeoutSeconds) * time.Second
+		conf.MaxConnectionLifetime = time.Duration(cfg.MaxConnLifetimeMinutes) * time.Minute
 		conf.SocketConnectTimeout = 5 * time.Second
 		conf.SocketKeepalive = true
 
@@ -55,6 +65,9 @@ func NewNeo4jClient(cfg config.DatabaseConfig, log *logger.Logger) (*Neo4jClient
 		driver: driver,
 		logger: log.WithService("neo4j"),
 		config: cfg,
+		gate:   newPoolGate(cfg.MaxConns, cfg.PoolMaxQueue, time.Duration(cfg.PoolWaitTimeoutMs)*time.Millisecond),
+
+		profiler: newQueryProfiler(),
 	}
 
 	// Test connection
@@ -98,39 +111,72 @@ func (c *Neo4jClient) Session(ctx context.Context, options ...func(*neo4j.Sessio
 	return c.driver.NewSession(ctx, sessionConfig)
 }
 
+// withQueryTimeout bounds ctx by the configured query timeout. An earlier
+// deadline on ctx still applies.
+func (c *Neo4jClient) withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.config.QueryTimeoutSeconds <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Duration(c.config.QueryTimeoutSeconds)*time.Second)
+}
+
 // ReadTransaction executes a read transaction
 func (c *Neo4jClient) ReadTransaction(ctx context.Context, work neo4j.ManagedTransactionWork) (interface{}, error) {
+	release, err := c.acquireSlot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	ctx, cancel := c.withQueryTimeout(ctx)
+	defer cancel()
+
 	session := c.Session(ctx)
 	defer session.Close(ctx)
 
 	start := time.Now()
 	result, err := session.ExecuteRead(ctx, work)
-	duration := time.Since(start).Seconds() * 1000
+	elapsed := time.Since(start)
+	duration := elapsed.Seconds() * 1000
+	c.observeQuery(ctx, "read_transaction", "", nil, elapsed, err)
 
+	log := c.logger.FromContext(ctx)
 	if err != nil {
-		c.logger.LogDatabaseQuery("read_transaction", duration, err)
+		log.LogDatabaseQuery("read_transaction", duration, err)
 		return nil, err
 	}
 
-	c.logger.LogDatabaseQuery("read_transaction", duration, nil)
+	log.LogDatabaseQuery("read_transaction", duration, nil)
 	return result, nil
 }
 
 // WriteTransaction executes a write transaction
 func (c *Neo4jClient) WriteTransaction(ctx context.Context, work neo4j.ManagedTransactionWork) (interface{}, error) {
+	release, err := c.acquireSlot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	ctx, cancel := c.withQueryTimeout(ctx)
+	defer cancel()
+
 	session := c.Session(ctx)
 	defer session.Close(ctx)
 
 	start := time.Now()
 	result, err := session.ExecuteWrite(ctx, work)
-	duration := time.Since(start).Seconds() * 1000
+	elapsed := time.Since(start)
+	duration := elapsed.Seconds() * 1000
+	c.observeQuery(ctx, "write_transaction", "", nil, elapsed, err)
 
+	log := c.logger.FromContext(ctx)
 	if err != nil {
-		c.logger.LogDatabaseQuery("write_transaction", duration, err)
+		log.LogDatabaseQuery("write_transaction", duration, err)
 		return nil, err
 	}
 
-	c.logger.LogDatabaseQuery("write_transaction", duration, nil)
+	log.LogDatabaseQuery("write_transaction", duration, nil)
 	return result, nil
 }
 
@@ -149,6 +195,10 @@ func (c *Neo4jClient) validateNeo4jValue(key string, value interface{}) error {
 	if value == nil {
 		return nil
 	}
+	// Temporal values are sent as native Neo4j date-times
+	if _, ok := value.(time.Time); ok {
+		return nil
+	}
 
 	switch reflect.TypeOf(value).Kind() {
 	case reflect.String, reflect.Bool:
@@ -195,18 +245,32 @@ func (c *Neo4jClient) validateNeo4jSlice(key string, value interface{}) error {
 
 // ExecuteQuery executes a query with parameters
 func (c *Neo4jClient) ExecuteQuery(ctx context.Context, query string, params map[string]interface{}) (*neo4j.EagerResult, error) {
+	log := c.logger.FromContext(ctx)
+
 	// Validate parameters before execution
 	if err := c.validateNeo4jParameters(params); err != nil {
-		c.logger.Error("Invalid Neo4j parameters", zap.Error(err))
+		log.Error("Invalid Neo4j parameters", zap.Error(err))
 		return nil, fmt.Errorf("invalid parameters: %w", err)
 	}
+
+	release, err := c.acquireSlot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	queryCtx, cancel := c.withQueryTimeout(ctx)
+	defer cancel()
+
 	start := time.Now()
-	result, err := neo4j.ExecuteQuery(ctx, c.driver, query, params,
+	result, err := neo4j.ExecuteQuery(queryCtx, c.driver, query, params,
 		neo4j.EagerResultTransformer,
 		neo4j.ExecuteQueryWithDatabase(c.config.Database))
-	duration := time.Since(start).Seconds() * 1000
+	elapsed := time.Since(start)
+	duration := elapsed.Seconds() * 1000
+	c.observeQuery(ctx, "execute_query", query, params, elapsed, err)
 
-	c.logger.LogDatabaseQuery(query, duration, err)
+	log.LogDatabaseQuery(query, duration, err)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
@@ -217,7 +281,8 @@ func (c *Neo4jClient) ExecuteQuery(ctx context.Context, query string, params map
 
 // ExecuteQueryWithLogging executes a query and logs performance metrics
 func (c *Neo4jClient) ExecuteQueryWithLogging(ctx context.Context, query string, params map[string]interface{}) (*neo4j.EagerResult, error) {
-	c.logger.Debug("Executing Neo4j query",
+	log := c.logger.FromContext(ctx)
+	log.Debug("Executing Neo4j query",
 		zap.String("query", query),
 		zap.Any("params", params),
 	)
@@ -225,14 +290,14 @@ func (c *Neo4jClient) ExecuteQueryWithLogging(ctx context.Context, query string,
 	result, err := c.ExecuteQuery(ctx, query, params)
 
 	if err != nil {
-		c.logger.Error("Neo4j query failed",
+		log.Error("Neo4j query failed",
 			zap.String("query", query),
 			zap.Error(err),
 		)
 		return nil, err
 	}
 
-	c.logger.Debug("Neo4j query completed",
+	log.Debug("Neo4j query completed",
 		zap.String("query", query),
 		zap.Int("records", len(result.Records)),
 	)
@@ -278,6 +343,7 @@ func (c *Neo4jClient) CreateConstraints(ctx context.Context) error {
 
 		// Notebook constraints
 		"CREATE CONSTRAINT notebook_id_unique IF NOT EXISTS FOR (n:Notebook) REQUIRE n.id IS UNIQUE",
+		"CREATE CONSTRAINT notebook_inbound_email_unique IF NOT EXISTS FOR (n:Notebook) REQUIRE n.inbound_email_token IS UNIQUE",
 
 		// Document constraints
 		"CREATE CONSTRAINT document_id_unique IF NOT EXISTS FOR (d:Document) REQUIRE d.id IS UNIQUE",
@@ -287,6 +353,57 @@ func (c *Neo4jClient) CreateConstraints(ctx context.Context) error {
 
 		// Processing job constraints
 		"CREATE CONSTRAINT job_id_unique IF NOT EXISTS FOR (j:ProcessingJob) REQUIRE j.id IS UNIQUE",
+
+		// Scheduler constraints
+		"CREATE CONSTRAINT scheduled_job_name_unique IF NOT EXISTS FOR (j:ScheduledJob) REQUIRE j.name IS UNIQUE",
+		"CREATE CONSTRAINT scheduled_job_run_id_unique IF NOT EXISTS FOR (r:ScheduledJobRun) REQUIRE r.id IS UNIQUE",
+
+		// Audit log constraints; unique sequences keep the hash chain linear
+		"CREATE CONSTRAINT audit_event_id_unique IF NOT EXISTS FOR (a:AuditEvent) REQUIRE a.id IS UNIQUE",
+		"CREATE CONSTRAINT audit_event_sequence_unique IF NOT EXISTS FOR (a:AuditEvent) REQUIRE a.sequence IS UNIQUE",
+		"CREATE CONSTRAINT audit_chain_id_unique IF NOT EXISTS FOR (c:AuditChain) REQUIRE c.id IS UNIQUE",
+
+		// Async job constraints
+		"CREATE CONSTRAINT async_job_id_unique IF NOT EXISTS FOR (j:Job) REQUIRE j.id IS UNIQUE",
+
+		// Saga constraints
+		"CREATE CONSTRAINT saga_id_unique IF NOT EXISTS FOR (s:Saga) REQUIRE s.id IS UNIQUE",
+		"CREATE CONSTRAINT document_deletion_id_unique IF NOT EXISTS FOR (x:DocumentDeletion) REQUIRE x.id IS UNIQUE",
+
+		// Feed token constraints
+		"CREATE CONSTRAINT feed_token_id_unique IF NOT EXISTS FOR (t:FeedToken) REQUIRE t.id IS UNIQUE",
+		"CREATE CONSTRAINT feed_token_hash_unique IF NOT EXISTS FOR (t:FeedToken) REQUIRE t.token_hash IS UNIQUE",
+
+		// S3 watcher constraints; the unique dedupe key suppresses objects
+		// ingested before
+		"CREATE CONSTRAINT s3_watcher_id_unique IF NOT EXISTS FOR (w:S3Watcher) REQUIRE w.id IS UNIQUE",
+		"CREATE CONSTRAINT s3_watched_object_dedupe_unique IF NOT EXISTS FOR (o:S3WatchedObject) REQUIRE o.dedupe_key IS UNIQUE",
+
+		// Report constraints
+		"CREATE CONSTRAINT report_schedule_id_unique IF NOT EXISTS FOR (r:ReportSchedule) REQUIRE r.id IS UNIQUE",
+		"CREATE CONSTRAINT report_run_id_unique IF NOT EXISTS FOR (r:ReportRun) REQUIRE r.id IS UNIQUE",
+
+		// Notebook digest subscriptions, one per user and notebook
+		"CREATE CONSTRAINT notebook_digest_subscription_unique IF NOT EXISTS FOR (d:NotebookDigestSubscription) REQUIRE (d.notebook_id, d.user_id) IS UNIQUE",
+
+		// Outbound webhook constraints
+		"CREATE CONSTRAINT webhook_subscription_id_unique IF NOT EXISTS FOR (w:WebhookSubscription) REQUIRE w.id IS UNIQUE",
+		"CREATE CONSTRAINT webhook_delivery_id_unique IF NOT EXISTS FOR (d:WebhookDelivery) REQUIRE d.id IS UNIQUE",
+
+		// Maintenance window constraints: one system-wide window, one per tenant
+		"CREATE CONSTRAINT maintenance_window_key_unique IF NOT EXISTS FOR (m:MaintenanceWindow) REQUIRE m.key IS UNIQUE",
+
+		// Monthly quota usage, one node per space and month
+		"CREATE CONSTRAINT space_quota_usage_unique IF NOT EXISTS FOR (u:SpaceQuotaUsage) REQUIRE (u.space_id, u.period) IS UNIQUE",
+
+		// Quota warnings sent, one node per space, quota and month
+		"CREATE CONSTRAINT space_quota_warning_unique IF NOT EXISTS FOR (w:SpaceQuotaWarning) REQUIRE (w.space_id, w.quota, w.period) IS UNIQUE",
+
+		// Tenant region pins, one per tenant
+		"CREATE CONSTRAINT tenant_region_unique IF NOT EXISTS FOR (r:TenantRegion) REQUIRE r.tenant_id IS UNIQUE",
+
+		// Custom roles, named uniquely within their organization
+		"CREATE CONSTRAINT organization_role_unique IF NOT EXISTS FOR (r:OrganizationRole) REQUIRE (r.organization_id, r.name) IS UNIQUE",
 	}
 
 	for _, constraint := range constraints {
@@ -322,6 +439,49 @@ func (c *Neo4jClient) CreateIndexes(ctx context.Context) error {
 		"CREATE INDEX document_type_idx IF NOT EXISTS FOR (d:Document) ON (d.type)",
 		"CREATE INDEX document_status_idx IF NOT EXISTS FOR (d:Document) ON (d.status)",
 		"CREATE INDEX document_created_at_idx IF NOT EXISTS FOR (d:Document) ON (d.created_at)",
+		"CREATE INDEX document_summary_pending_idx IF NOT EXISTS FOR (d:Document) ON (d.summary_pending)",
+		"CREATE INDEX document_upload_expires_idx IF NOT EXISTS FOR (d:Document) ON (d.upload_expires_at)",
+		"CREATE INDEX document_space_id_idx IF NOT EXISTS FOR (d:Document) ON (d.space_id)",
+		"CREATE INDEX document_version_idx IF NOT EXISTS FOR (v:DocumentVersion) ON (v.document_id, v.version)",
+
+		// Sagas, found by recovery once stale
+		"CREATE INDEX saga_status_idx IF NOT EXISTS FOR (s:Saga) ON (s.status, s.updated_at)",
+		"CREATE INDEX document_deletion_status_idx IF NOT EXISTS FOR (x:DocumentDeletion) ON (x.status, x.next_attempt_at)",
+
+		// Audit log indexes
+		"CREATE INDEX audit_event_created_at_idx IF NOT EXISTS FOR (a:AuditEvent) ON (a.created_at)",
+		"CREATE INDEX audit_event_actor_id_idx IF NOT EXISTS FOR (a:AuditEvent) ON (a.actor_id)",
+		"CREATE INDEX audit_event_resource_idx IF NOT EXISTS FOR (a:AuditEvent) ON (a.resource_type, a.resource_id)",
+		"CREATE INDEX audit_event_space_id_idx IF NOT EXISTS FOR (a:AuditEvent) ON (a.space_id)",
+
+		// Async job indexes
+		"CREATE INDEX async_job_created_by_idx IF NOT EXISTS FOR (j:Job) ON (j.created_by, j.created_at)",
+		"CREATE INDEX async_job_status_idx IF NOT EXISTS FOR (j:Job) ON (j.status, j.updated_at)",
+
+		// Processing job indexes
+		"CREATE INDEX processing_job_tenant_idx IF NOT EXISTS FOR (j:ProcessingJob) ON (j.tenant_id, j.created_at)",
+		"CREATE INDEX processing_job_document_idx IF NOT EXISTS FOR (j:ProcessingJob) ON (j.document_id)",
+		"CREATE INDEX processing_job_file_id_idx IF NOT EXISTS FOR (j:ProcessingJob) ON (j.file_id)",
+
+		// Entity indexes; entities are deduplicated by key within a tenant
+		"CREATE INDEX entity_key_idx IF NOT EXISTS FOR (e:Entity) ON (e.tenant_id, e.type, e.key)",
+
+		// Summary token usage, one node per space and month
+		"CREATE INDEX summary_usage_idx IF NOT EXISTS FOR (u:SummaryUsage) ON (u.space_id, u.period)",
+
+		// Classification policies, listed per space
+		"CREATE INDEX classification_policy_space_idx IF NOT EXISTS FOR (p:ClassificationPolicy) ON (p.space_id)",
+
+		// Feed token indexes
+		"CREATE INDEX feed_token_user_id_idx IF NOT EXISTS FOR (t:FeedToken) ON (t.user_id, t.created_at)",
+
+		// Due notebook digests are found by their next run
+		"CREATE INDEX notebook_digest_next_run_idx IF NOT EXISTS FOR (d:NotebookDigestSubscription) ON (d.next_run_at)",
+
+		// Outbound webhooks: subscriptions matched per space, deliveries
+		// claimed when due
+		"CREATE INDEX webhook_subscription_space_idx IF NOT EXISTS FOR (w:WebhookSubscription) ON (w.space_id)",
+		"CREATE INDEX webhook_delivery_status_idx IF NOT EXISTS FOR (d:WebhookDelivery) ON (d.status, d.next_attempt_at)",
 
 		// Full-text search indexes
 		"CREATE FULLTEXT INDEX document_content_fulltext IF NOT EXISTS FOR (d:Document) ON EACH [d.content, d.extracted_text]",
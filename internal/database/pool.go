@@ -0,0 +1,126 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrPoolSaturated is returned when an operation cannot get a connection
+// slot in time. Handlers map it to 429 so callers back off instead of
+// piling more load onto a saturated database.
+var ErrPoolSaturated = errors.New("neo4j connection pool saturated")
+
+// PoolStats is a snapshot of connection slot usage
+type PoolStats struct {
+	Capacity int `json:"capacity"`
+	InUse    int `json:"in_use"`
+	Waiting  int `json:"waiting"`
+}
+
+// poolGate admits at most capacity concurrent operations. Up to maxWaiting
+// further operations may queue for up to waitTimeout; anything beyond is
+// rejected at once. Bounding the queue keeps latency from growing without
+// limit when the database cannot keep up.
+type poolGate struct {
+	slots       chan struct{}
+	waiting     atomic.Int64
+	maxWaiting  int64
+	waitTimeout time.Duration
+}
+
+func newPoolGate(capacity, maxWaiting int, waitTimeout time.Duration) *poolGate {
+	return &poolGate{
+		slots:       make(chan struct{}, capacity),
+		maxWaiting:  int64(maxWaiting),
+		waitTimeout: waitTimeout,
+	}
+}
+
+// acquire takes a slot, returning how long it waited. The caller must call
+// release once the operation finishes.
+func (g *poolGate) acquire(ctx context.Context) (time.Duration, error) {
+	select {
+	case g.slots <- struct{}{}:
+		return 0, nil
+	default:
+	}
+
+	if g.waiting.Add(1) > g.maxWaiting {
+		g.waiting.Add(-1)
+		return 0, ErrPoolSaturated
+	}
+	defer g.waiting.Add(-1)
+
+	start := time.Now()
+	timer := time.NewTimer(g.waitTimeout)
+	defer timer.Stop()
+
+	select {
+	case g.slots <- struct{}{}:
+		return time.Since(start), nil
+	case <-timer.C:
+		return time.Since(start), ErrPoolSaturated
+	case <-ctx.Done():
+		return time.Since(start), ctx.Err()
+	}
+}
+
+func (g *poolGate) release() {
+	<-g.slots
+}
+
+// saturated reports whether the wait queue is full. Without a queue
+// (maxWaiting 0) operations are rejected individually in acquire instead.
+func (g *poolGate) saturated() bool {
+	return g.maxWaiting > 0 && g.waiting.Load() >= g.maxWaiting
+}
+
+func (g *poolGate) stats() PoolStats {
+	return PoolStats{
+		Capacity: cap(g.slots),
+		InUse:    len(g.slots),
+		Waiting:  int(g.waiting.Load()),
+	}
+}
+
+// acquireSlot admits an operation through the pool gate and records wait
+// metrics. The returned release func must be called when it finishes.
+func (c *Neo4jClient) acquireSlot(ctx context.Context) (func(), error) {
+	wait, err := c.gate.acquire(ctx)
+	if c.metrics != nil {
+		c.metrics.RecordDBPoolWait(wait, errors.Is(err, ErrPoolSaturated))
+	}
+	if err != nil {
+		if errors.Is(err, ErrPoolSaturated) {
+			c.logger.FromContext(ctx).Debug("Neo4j pool saturated, shedding operation")
+		}
+		return nil, err
+	}
+	c.reportPoolUsage()
+
+	return func() {
+		c.gate.release()
+		c.reportPoolUsage()
+	}, nil
+}
+
+func (c *Neo4jClient) reportPoolUsage() {
+	if c.metrics == nil {
+		return
+	}
+	stats := c.gate.stats()
+	c.metrics.SetDBPoolUsage(stats.InUse, stats.Capacity, stats.Waiting)
+}
+
+// PoolStats returns current connection slot usage
+func (c *Neo4jClient) PoolStats() PoolStats {
+	return c.gate.stats()
+}
+
+// Saturated reports whether the connection wait queue is full, meaning new
+// operations would be rejected. Used to shed requests before they start.
+func (c *Neo4jClient) Saturated() bool {
+	return c.gate.saturated()
+}
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolGateAdmitsUpToCapacity(t *testing.T) {
+	gate := newPoolGate(2, 1, 10*time.Millisecond)
+
+	_, err := gate.acquire(context.Background())
+	require.NoError(t, err)
+	_, err = gate.acquire(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, PoolStats{Capacity: 2, InUse: 2, Waiting: 0}, gate.stats())
+
+	gate.release()
+	_, err = gate.acquire(context.Background())
+	assert.NoError(t, err, "a released slot can be reused")
+}
+
+func TestPoolGateWaitsForRelease(t *testing.T) {
+	gate := newPoolGate(1, 1, time.Second)
+	_, err := gate.acquire(context.Background())
+	require.NoError(t, err)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		gate.release()
+	}()
+
+	wait, err := gate.acquire(context.Background())
+	require.NoError(t, err)
+	assert.Greater(t, wait, time.Duration(0))
+}
+
+func TestPoolGateWaitTimeout(t *testing.T) {
+	gate := newPoolGate(1, 1, 10*time.Millisecond)
+	_, err := gate.acquire(context.Background())
+	require.NoError(t, err)
+
+	_, err = gate.acquire(context.Background())
+	assert.ErrorIs(t, err, ErrPoolSaturated)
+	assert.Equal(t, 0, gate.stats().Waiting, "timed out waiters leave the queue")
+}
+
+func TestPoolGateContextCancel(t *testing.T) {
+	gate := newPoolGate(1, 1, time.Second)
+	_, err := gate.acquire(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = gate.acquire(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, gate.stats().Waiting)
+}
+
+func TestPoolGateQueueOverflow(t *testing.T) {
+	gate := newPoolGate(1, 1, time.Second)
+	_, err := gate.acquire(context.Background())
+	require.NoError(t, err)
+	assert.False(t, gate.saturated())
+
+	// One waiter fills the queue
+	queued := make(chan error, 1)
+	go func() {
+		_, err := gate.acquire(context.Background())
+		queued <- err
+	}()
+	require.Eventually(t, gate.saturated, time.Second, time.Millisecond)
+
+	// The next operation is rejected at once instead of queueing
+	start := time.Now()
+	_, err = gate.acquire(context.Background())
+	assert.ErrorIs(t, err, ErrPoolSaturated)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	gate.release()
+	assert.NoError(t, <-queued)
+	assert.False(t, gate.saturated())
+}
+
+func TestPoolGateWithoutQueue(t *testing.T) {
+	gate := newPoolGate(1, 0, time.Second)
+	assert.False(t, gate.saturated(), "a gate without a queue is never saturated as a whole")
+
+	_, err := gate.acquire(context.Background())
+	require.NoError(t, err)
+	assert.False(t, gate.saturated())
+
+	_, err = gate.acquire(context.Background())
+	assert.ErrorIs(t, err, ErrPoolSaturated, "with no queue an operation is rejected when no slot is free")
+}
@@ -0,0 +1,81 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+)
+
+// PostgresClient wraps the PostgreSQL reporting database. Neo4j remains the
+// system of record; PostgreSQL only holds read models derived from it.
+type PostgresClient struct {
+	db     *sql.DB
+	logger *logger.Logger
+	config config.PostgresConfig
+}
+
+// NewPostgresClient creates a new PostgreSQL client. The database/sql driver
+// named in the configuration must be registered, which for the default
+// "pgx" driver means building with -tags postgres.
+func NewPostgresClient(cfg config.PostgresConfig, log *logger.Logger) (*PostgresClient, error) {
+	if !slices.Contains(sql.Drivers(), cfg.Driver) {
+		return nil, fmt.Errorf("postgres driver %q is not registered; build with -tags postgres", cfg.Driver)
+	}
+
+	db, err := sql.Open(cfg.Driver, cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Postgres connection: %w", err)
+	}
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(time.Hour)
+
+	client := &PostgresClient{
+		db:     db,
+		logger: log.WithService("postgres"),
+		config: cfg,
+	}
+
+	// Test connection
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.HealthCheck(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to Postgres: %w", err)
+	}
+
+	client.logger.Info("Connected to Postgres",
+		zap.String("driver", cfg.Driver),
+		zap.Int("max_open_conns", cfg.MaxOpenConns),
+	)
+
+	return client, nil
+}
+
+// DB returns the underlying connection pool
+func (p *PostgresClient) DB() *sql.DB {
+	return p.db
+}
+
+// HealthCheck performs a health check on the database
+func (p *PostgresClient) HealthCheck(ctx context.Context) error {
+	start := time.Now()
+	err := p.db.PingContext(ctx)
+	duration := time.Since(start).Seconds() * 1000
+
+	p.logger.LogServiceCall("postgres", "ping", duration, err)
+	return err
+}
+
+// Close closes the connection pool
+func (p *PostgresClient) Close() error {
+	return p.db.Close()
+}
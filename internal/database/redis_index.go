@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Prefix index operations
+//
+// An index is a sorted set whose members all score 0, so Redis orders them
+// lexicographically and a prefix lookup is a ZRANGEBYLEX. Each owner (an
+// entity contributing members) keeps a set of "<index key>\n<member>"
+// entries, so its members can be replaced or removed without knowing which
+// index or members it used before.
+
+// ReplaceIndexMembers makes members the only members ownerKey contributes,
+// all to indexKey. Empty members removes the owner from every index.
+func (r *RedisClient) ReplaceIndexMembers(ctx context.Context, indexKey, ownerKey string, members []string) error {
+	previous, err := r.client.SMembers(ctx, ownerKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to read index owner %s: %w", ownerKey, err)
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, entry := range previous {
+			if key, member, ok := strings.Cut(entry, "\n"); ok {
+				pipe.ZRem(ctx, key, member)
+			}
+		}
+		pipe.Del(ctx, ownerKey)
+		if len(members) == 0 {
+			return nil
+		}
+
+		scored := make([]redis.Z, len(members))
+		entries := make([]interface{}, len(members))
+		for i, member := range members {
+			scored[i] = redis.Z{Member: member}
+			entries[i] = indexKey + "\n" + member
+		}
+		pipe.ZAdd(ctx, indexKey, scored...)
+		pipe.SAdd(ctx, ownerKey, entries...)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update index %s: %w", indexKey, err)
+	}
+	return nil
+}
+
+// RangeIndexPrefix returns up to limit members of indexKey starting with
+// prefix, in lexicographic order
+func (r *RedisClient) RangeIndexPrefix(ctx context.Context, indexKey, prefix string, limit int64) ([]string, error) {
+	members, err := r.client.ZRangeByLex(ctx, indexKey, &redis.ZRangeBy{
+		Min:   "[" + prefix,
+		Max:   "[" + prefix + "\xff",
+		Count: limit,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to range index %s: %w", indexKey, err)
+	}
+	return members, nil
+}
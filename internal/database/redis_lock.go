@@ -0,0 +1,56 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Lock operations
+//
+// Locks are plain keys whose value is the owner token. Renew and release
+// only act when the caller still owns the key, so a replica whose lease
+// expired cannot extend or delete a lock another replica has since taken.
+
+var renewLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// AcquireLock takes the lock for owner if no one holds it
+func (r *RedisClient) AcquireLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	return r.SetNX(ctx, key, owner, ttl)
+}
+
+// RenewLock extends the lock if owner still holds it
+func (r *RedisClient) RenewLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	start := time.Now()
+	renewed, err := renewLockScript.Run(ctx, r.client, []string{key}, owner, ttl.Milliseconds()).Int64()
+	duration := time.Since(start).Seconds() * 1000
+
+	r.logger.LogServiceCall("redis", fmt.Sprintf("renew_lock:%s", key), duration, err)
+
+	return renewed == 1, err
+}
+
+// ReleaseLock deletes the lock if owner still holds it
+func (r *RedisClient) ReleaseLock(ctx context.Context, key, owner string) error {
+	start := time.Now()
+	err := releaseLockScript.Run(ctx, r.client, []string{key}, owner).Err()
+	duration := time.Since(start).Seconds() * 1000
+
+	r.logger.LogServiceCall("redis", fmt.Sprintf("release_lock:%s", key), duration, err)
+
+	return err
+}
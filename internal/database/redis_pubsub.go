@@ -0,0 +1,58 @@
+package database
+
+import (
+	"context"
+	"fmt"
+)
+
+// Pub/Sub operations
+//
+// Messages are fire-and-forget: Redis delivers them only to subscribers
+// connected at the time of publishing and keeps no backlog.
+
+// pubSubBuffer is how many messages a subscriber may fall behind before
+// delivery blocks
+const pubSubBuffer = 64
+
+// Publish sends a message to every subscriber of channel
+func (r *RedisClient) Publish(ctx context.Context, channel string, payload []byte) error {
+	if err := r.client.Publish(ctx, channel, payload).Err(); err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", channel, err)
+	}
+	return nil
+}
+
+// Subscribe delivers the messages published to channel until ctx is done,
+// then closes the returned channel. It returns once Redis has confirmed the
+// subscription, so messages published afterwards are not missed.
+func (r *RedisClient) Subscribe(ctx context.Context, channel string) (<-chan []byte, error) {
+	sub := r.client.Subscribe(ctx, channel)
+	if _, err := sub.Receive(ctx); err != nil {
+		sub.Close()
+		return nil, fmt.Errorf("failed to subscribe to %s: %w", channel, err)
+	}
+
+	messages := make(chan []byte, pubSubBuffer)
+	go func() {
+		defer close(messages)
+		defer sub.Close()
+
+		incoming := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-incoming:
+				if !ok {
+					return
+				}
+				select {
+				case messages <- []byte(msg.Payload):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return messages, nil
+}
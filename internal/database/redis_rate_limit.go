@@ -0,0 +1,113 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Rate limit operations
+//
+// Each bucket is a hash of its tokens and when they were counted, updated
+// by a script so that replicas taking tokens at once do not overwrite each
+// other. The script reads the clock of Redis, which every replica shares.
+
+// rateLimitKeyPrefix namespaces rate limit buckets
+const rateLimitKeyPrefix = "aether-be:ratelimit:"
+
+// takeTokenScript refills the bucket KEYS[1] at ARGV[1] tokens per
+// millisecond up to ARGV[2] tokens and takes one if it can. An empty bucket
+// lends ARGV[4] tokens a request down to ARGV[3] tokens below zero. It
+// returns whether it took one and the tokens left, as a string since Redis
+// turns Lua numbers into integers.
+var takeTokenScript = redis.NewScript(`
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local overdraft = tonumber(ARGV[3])
+local cost = tonumber(ARGV[4])
+
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(bucket[1]) or burst
+local ts = tonumber(bucket[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+elseif tokens - cost >= -overdraft then
+	tokens = tokens - cost
+	allowed = 1
+end
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", now)
+redis.call("PEXPIRE", KEYS[1], math.ceil((burst - tokens) / rate) + 1000)
+return {allowed, tostring(tokens)}
+`)
+
+// TokenBucketResult is the outcome of taking a token from a bucket
+type TokenBucketResult struct {
+	Allowed   bool
+	Remaining int // Whole tokens left
+	// Debt is the whole tokens owed for requests taken on overdraft
+	Debt int
+	// RetryAfter is how long until a token is available, when none was
+	RetryAfter time.Duration
+	// ResetAfter is how long until the bucket is full again
+	ResetAfter time.Duration
+}
+
+// TakeToken takes a token from the bucket key, which holds up to burst
+// tokens and is refilled at perSecond tokens a second. Once it is empty, a
+// request may take debtCost tokens on credit as long as the bucket owes at
+// most overdraft tokens. A bucket is created full and dropped once it has
+// been full for a second.
+func (r *RedisClient) TakeToken(ctx context.Context, key string, perSecond float64, burst, overdraft int, debtCost float64) (TokenBucketResult, error) {
+	start := time.Now()
+	perMillisecond := perSecond / 1000
+	values, err := takeTokenScript.Run(ctx, r.client, []string{rateLimitKeyPrefix + key},
+		strconv.FormatFloat(perMillisecond, 'g', -1, 64), burst, overdraft,
+		strconv.FormatFloat(debtCost, 'g', -1, 64)).Slice()
+	duration := time.Since(start).Seconds() * 1000
+
+	r.logger.LogServiceCall("redis", "take_token", duration, err)
+
+	if err != nil {
+		return TokenBucketResult{}, fmt.Errorf("failed to take rate limit token: %w", err)
+	}
+	if len(values) != 2 {
+		return TokenBucketResult{}, fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+	allowed, _ := values[0].(int64)
+	raw, _ := values[1].(string)
+	tokens, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return TokenBucketResult{}, fmt.Errorf("unexpected rate limit tokens %q: %w", raw, err)
+	}
+	return NewTokenBucketResult(allowed == 1, tokens, perSecond, burst, overdraft, debtCost), nil
+}
+
+// NewTokenBucketResult describes a bucket refilled at perSecond tokens a
+// second up to burst, lending debtCost tokens a request down to overdraft
+// tokens below zero, that has tokens left after a token was taken, or was
+// not when allowed is false
+func NewTokenBucketResult(allowed bool, tokens, perSecond float64, burst, overdraft int, debtCost float64) TokenBucketResult {
+	result := TokenBucketResult{
+		Allowed:    allowed,
+		Remaining:  int(math.Max(0, math.Floor(tokens))),
+		Debt:       int(math.Max(0, math.Ceil(-tokens))),
+		ResetAfter: time.Duration((float64(burst) - tokens) / perSecond * float64(time.Second)),
+	}
+	if !allowed {
+		// The next request is taken from a token, or on credit if that
+		// comes first
+		needed := math.Min(1, debtCost-float64(overdraft))
+		result.RetryAfter = time.Duration((needed - tokens) / perSecond * float64(time.Second))
+	}
+	return result
+}
@@ -0,0 +1,158 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Stream operations
+//
+// Streams are read through consumer groups: each entry is handed to one
+// consumer of the group and stays pending until acknowledged, so an entry
+// whose consumer died is claimed by another once it has been idle long
+// enough.
+
+// streamPayloadField is the field of a stream entry holding its payload
+const streamPayloadField = "payload"
+
+// StreamEntry is an entry read from a stream by a consumer group
+type StreamEntry struct {
+	ID      string
+	Payload []byte
+	// Deliveries counts how many times the entry was handed to a
+	// consumer, this time included
+	Deliveries int64
+}
+
+// StreamAdd appends payload to stream, trimming it to about maxLen entries
+// when maxLen is positive, and returns the entry's ID
+func (r *RedisClient) StreamAdd(ctx context.Context, stream string, payload []byte, maxLen int64) (string, error) {
+	args := &redis.XAddArgs{
+		Stream: stream,
+		Values: map[string]interface{}{streamPayloadField: payload},
+	}
+	if maxLen > 0 {
+		args.MaxLen = maxLen
+		args.Approx = true
+	}
+	id, err := r.client.XAdd(ctx, args).Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to add to stream %s: %w", stream, err)
+	}
+	return id, nil
+}
+
+// StreamCreateGroup creates a consumer group reading stream from its next
+// entry, creating the stream if needed. A group that exists is kept.
+func (r *RedisClient) StreamCreateGroup(ctx context.Context, stream, group string) error {
+	err := r.client.XGroupCreateMkStream(ctx, stream, group, "$").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("failed to create group %s of stream %s: %w", group, stream, err)
+	}
+	return nil
+}
+
+// StreamReadGroup hands up to count entries no consumer of group has
+// received to consumer, waiting up to block for one to arrive. It returns
+// no entries when none arrived in time.
+func (r *RedisClient) StreamReadGroup(ctx context.Context, stream, group, consumer string, count int64, block time.Duration) ([]StreamEntry, error) {
+	streams, err := r.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    group,
+		Consumer: consumer,
+		Streams:  []string{stream, ">"},
+		Count:    count,
+		Block:    block,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stream %s: %w", stream, err)
+	}
+
+	var entries []StreamEntry
+	for _, s := range streams {
+		for _, message := range s.Messages {
+			entries = append(entries, streamEntry(message, 1))
+		}
+	}
+	return entries, nil
+}
+
+// StreamClaimIdle hands to consumer up to count entries that other
+// consumers of group received but have not acknowledged for minIdle
+func (r *RedisClient) StreamClaimIdle(ctx context.Context, stream, group, consumer string, minIdle time.Duration, count int64) ([]StreamEntry, error) {
+	pending, err := r.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: stream,
+		Group:  group,
+		Idle:   minIdle,
+		Start:  "-",
+		End:    "+",
+		Count:  count,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending entries of stream %s: %w", stream, err)
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(pending))
+	deliveries := make(map[string]int64, len(pending))
+	for _, entry := range pending {
+		ids = append(ids, entry.ID)
+		deliveries[entry.ID] = entry.RetryCount + 1
+	}
+	// Entries another consumer claimed in between are left out
+	messages, err := r.client.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   stream,
+		Group:    group,
+		Consumer: consumer,
+		MinIdle:  minIdle,
+		Messages: ids,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim entries of stream %s: %w", stream, err)
+	}
+
+	entries := make([]StreamEntry, 0, len(messages))
+	for _, message := range messages {
+		entries = append(entries, streamEntry(message, deliveries[message.ID]))
+	}
+	return entries, nil
+}
+
+// StreamAck acknowledges entries group has handled, so they are not
+// claimed again, and deletes them from stream
+func (r *RedisClient) StreamAck(ctx context.Context, stream, group string, ids ...string) error {
+	pipe := r.client.TxPipeline()
+	pipe.XAck(ctx, stream, group, ids...)
+	pipe.XDel(ctx, stream, ids...)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to acknowledge entries of stream %s: %w", stream, err)
+	}
+	return nil
+}
+
+// StreamLength returns how many entries stream holds. Streams whose
+// entries are deleted once acknowledged hold only the waiting ones.
+func (r *RedisClient) StreamLength(ctx context.Context, stream string) (int64, error) {
+	length, err := r.client.XLen(ctx, stream).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read length of stream %s: %w", stream, err)
+	}
+	return length, nil
+}
+
+func streamEntry(message redis.XMessage, deliveries int64) StreamEntry {
+	entry := StreamEntry{ID: message.ID, Deliveries: deliveries}
+	if payload, ok := message.Values[streamPayloadField].(string); ok {
+		entry.Payload = []byte(payload)
+	}
+	return entry
+}
@@ -0,0 +1,245 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+)
+
+// PROFILE re-runs add load exactly when the database is slow, so they are
+// bounded: few at a time, and each query profiled at most once per cooldown
+const (
+	profileTimeout        = 30 * time.Second
+	profileCooldown       = 10 * time.Minute
+	maxConcurrentProfiles = 2
+	maxProfiledQueries    = 1000
+)
+
+var (
+	whitespacePattern  = regexp.MustCompile(`\s+`)
+	readClausePattern  = regexp.MustCompile(`(?i)^(OPTIONAL\s+MATCH|MATCH|WITH|UNWIND|RETURN)\b`)
+	writeClausePattern = regexp.MustCompile(`(?i)\b(CREATE|MERGE|SET|DELETE|REMOVE|DETACH|DROP|LOAD\s+CSV|FOREACH|CALL)\b`)
+)
+
+type queryNameKey struct{}
+
+// WithQueryName labels the queries run with ctx for metrics and slow query
+// logs. Names must come from a fixed set (e.g. "chunk.create_batch") since
+// they become metric labels.
+func WithQueryName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, queryNameKey{}, name)
+}
+
+// queryName returns the name set with WithQueryName, or fallback
+func queryName(ctx context.Context, fallback string) string {
+	if name, ok := ctx.Value(queryNameKey{}).(string); ok && name != "" {
+		return name
+	}
+	return fallback
+}
+
+// queryProfiler limits PROFILE re-runs of slow queries
+type queryProfiler struct {
+	slots    chan struct{}
+	mu       sync.Mutex
+	profiled map[string]time.Time
+}
+
+func newQueryProfiler() *queryProfiler {
+	return &queryProfiler{
+		slots:    make(chan struct{}, maxConcurrentProfiles),
+		profiled: make(map[string]time.Time),
+	}
+}
+
+// tryStart reserves a profile run for query. It returns false when the query
+// was profiled within the cooldown or too many profiles are running; call
+// done after a successful reservation.
+func (p *queryProfiler) tryStart(query string, now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if last, ok := p.profiled[query]; ok && now.Sub(last) < profileCooldown {
+		return false
+	}
+
+	select {
+	case p.slots <- struct{}{}:
+	default:
+		return false
+	}
+
+	if len(p.profiled) >= maxProfiledQueries {
+		for key, last := range p.profiled {
+			if now.Sub(last) >= profileCooldown {
+				delete(p.profiled, key)
+			}
+		}
+	}
+	p.profiled[query] = now
+	return true
+}
+
+func (p *queryProfiler) done() {
+	<-p.slots
+}
+
+// QueryMetrics receives database query observations. It is implemented by
+// metrics.Metrics and kept as an interface to avoid an import cycle.
+type QueryMetrics interface {
+	RecordDBQuery(database, operation, status string, duration time.Duration)
+	RecordSlowQuery(database, query string)
+	SetDBPoolUsage(inUse, capacity, waiting int)
+	RecordDBPoolWait(wait time.Duration, rejected bool)
+}
+
+// SetMetrics attaches a metrics recorder to the client
+func (c *Neo4jClient) SetMetrics(m QueryMetrics) {
+	c.metrics = m
+}
+
+// observeQuery records metrics for a finished query and reports it when it
+// exceeds the configured slow query threshold
+func (c *Neo4jClient) observeQuery(ctx context.Context, operation, query string, params map[string]interface{}, duration time.Duration, err error) {
+	status := "success"
+	if err != nil {
+		status = "error"
+	}
+	if c.metrics != nil {
+		c.metrics.RecordDBQuery("neo4j", operation, status, duration)
+	}
+
+	threshold := time.Duration(c.config.SlowQueryThresholdMs) * time.Millisecond
+	if threshold <= 0 || duration < threshold {
+		return
+	}
+
+	name := queryName(ctx, operation)
+	if c.metrics != nil {
+		c.metrics.RecordSlowQuery("neo4j", name)
+	}
+
+	normalized := normalizeQuery(query)
+	c.logger.FromContext(ctx).Warn("Slow Neo4j query detected",
+		zap.String("operation", operation),
+		zap.String("query_name", name),
+		zap.String("query", normalized),
+		zap.Any("params", sanitizeParams(params)),
+		zap.Float64("duration_ms", float64(duration.Microseconds())/1000),
+		zap.Float64("threshold_ms", float64(threshold.Microseconds())/1000),
+		zap.Bool("failed", err != nil),
+	)
+
+	if c.config.ProfileSlowQueries && query != "" && err == nil && isReadOnlyQuery(query) &&
+		c.profiler.tryStart(normalized, time.Now()) {
+		requestID := logger.RequestIDFromContext(ctx)
+		go func() {
+			defer c.profiler.done()
+			c.profileQuery(requestID, name, query, params)
+		}()
+	}
+}
+
+// profileQuery re-runs a read-only query with PROFILE and logs the plan summary.
+// It runs detached from the request context so the caller is not delayed.
+func (c *Neo4jClient) profileQuery(requestID, name, query string, params map[string]interface{}) {
+	ctx, cancel := context.WithTimeout(logger.ContextWithRequestID(context.Background(), requestID), profileTimeout)
+	defer cancel()
+
+	log := c.logger.FromContext(ctx)
+
+	result, err := neo4j.ExecuteQuery(ctx, c.driver, "PROFILE "+query, params,
+		neo4j.EagerResultTransformer,
+		neo4j.ExecuteQueryWithDatabase(c.config.Database),
+		neo4j.ExecuteQueryWithReadersRouting())
+	if err != nil {
+		log.Warn("Failed to capture query profile",
+			zap.String("query_name", name),
+			zap.Error(err),
+		)
+		return
+	}
+
+	plan := result.Summary.Profile()
+	if plan == nil {
+		return
+	}
+
+	log.Warn("Slow Neo4j query profile",
+		zap.String("query_name", name),
+		zap.Int64("total_db_hits", totalDbHits(plan)),
+		zap.Int64("rows", plan.Records()),
+		zap.Strings("plan", describePlan(plan, 0, nil)),
+	)
+}
+
+// normalizeQuery collapses whitespace so multi-line Cypher logs on one line
+func normalizeQuery(query string) string {
+	return strings.TrimSpace(whitespacePattern.ReplaceAllString(query, " "))
+}
+
+// isReadOnlyQuery reports whether a query is known to only read and is
+// therefore safe to re-run with PROFILE. PROFILE executes the query, so
+// anything not recognized as a plain read (including every CALL, which may
+// run a writing procedure) is treated as a write.
+func isReadOnlyQuery(query string) bool {
+	normalized := normalizeQuery(query)
+	return readClausePattern.MatchString(normalized) && !writeClausePattern.MatchString(normalized)
+}
+
+// sanitizeParams replaces parameter values with type/size descriptions so
+// slow query logs never contain user content. Numbers and booleans are kept
+// because they are usually limits, offsets and flags that explain the cost.
+func sanitizeParams(params map[string]interface{}) map[string]interface{} {
+	if len(params) == 0 {
+		return nil
+	}
+
+	sanitized := make(map[string]interface{}, len(params))
+	for key, value := range params {
+		switch v := value.(type) {
+		case nil:
+			sanitized[key] = nil
+		case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+			sanitized[key] = v
+		case string:
+			sanitized[key] = fmt.Sprintf("<string len=%d>", len(v))
+		case []string:
+			sanitized[key] = fmt.Sprintf("<list len=%d>", len(v))
+		case []interface{}:
+			sanitized[key] = fmt.Sprintf("<list len=%d>", len(v))
+		case map[string]interface{}:
+			sanitized[key] = fmt.Sprintf("<map len=%d>", len(v))
+		default:
+			sanitized[key] = fmt.Sprintf("<%T>", v)
+		}
+	}
+	return sanitized
+}
+
+// totalDbHits sums db hits across the whole plan tree
+func totalDbHits(plan neo4j.ProfiledPlan) int64 {
+	total := plan.DbHits()
+	for _, child := range plan.Children() {
+		total += totalDbHits(child)
+	}
+	return total
+}
+
+// describePlan flattens the plan tree into indented operator lines
+func describePlan(plan neo4j.ProfiledPlan, depth int, lines []string) []string {
+	lines = append(lines, fmt.Sprintf("%s%s (db_hits=%d, rows=%d)",
+		strings.Repeat("  ", depth), plan.Operator(), plan.DbHits(), plan.Records()))
+	for _, child := range plan.Children() {
+		lines = describePlan(child, depth+1, lines)
+	}
+	return lines
+}
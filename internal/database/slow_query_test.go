@@ -0,0 +1,62 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryName(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, "execute_query", queryName(ctx, "execute_query"))
+	assert.Equal(t, "chunk.create_batch", queryName(WithQueryName(ctx, "chunk.create_batch"), "write_batch"))
+}
+
+func TestQueryProfilerDedupesAndBoundsConcurrency(t *testing.T) {
+	profiler := newQueryProfiler()
+	now := time.Now()
+
+	assert.True(t, profiler.tryStart("MATCH (a) RETURN a", now))
+	assert.False(t, profiler.tryStart("MATCH (a) RETURN a", now), "same query is not profiled again within the cooldown")
+
+	assert.True(t, profiler.tryStart("MATCH (b) RETURN b", now))
+	assert.False(t, profiler.tryStart("MATCH (c) RETURN c", now), "no slot while the maximum number of profiles run")
+
+	profiler.done()
+	assert.True(t, profiler.tryStart("MATCH (c) RETURN c", now))
+
+	profiler.done()
+	profiler.done()
+	assert.True(t, profiler.tryStart("MATCH (a) RETURN a", now.Add(profileCooldown)), "profiled again after the cooldown")
+}
+
+func TestIsReadOnlyQuery(t *testing.T) {
+	assert.True(t, isReadOnlyQuery("MATCH (d:Document) WHERE d.status <> 'deleted' RETURN d"))
+	assert.True(t, isReadOnlyQuery("MATCH (d:Document) RETURN d.created_at AS created"))
+	assert.False(t, isReadOnlyQuery("MATCH (d:Document {id: $id}) SET d.status = 'deleted'"))
+	assert.False(t, isReadOnlyQuery("MERGE (u:User {id: $id})"))
+	assert.False(t, isReadOnlyQuery("MATCH (d) DETACH DELETE d"))
+	assert.False(t, isReadOnlyQuery("CALL apoc.create.node(['Doc'], {})"))
+	assert.False(t, isReadOnlyQuery("MATCH (d) CALL apoc.periodic.iterate('a', 'b', {}) YIELD batches RETURN batches"))
+	assert.False(t, isReadOnlyQuery("CALL db.labels()"), "only queries known to be reads are profiled")
+	assert.False(t, isReadOnlyQuery("SHOW INDEXES"))
+}
+
+func TestSanitizeParams(t *testing.T) {
+	params := map[string]interface{}{
+		"email":  "alice@example.com",
+		"limit":  20,
+		"active": true,
+		"tags":   []string{"a", "b"},
+	}
+
+	sanitized := sanitizeParams(params)
+
+	assert.Equal(t, "<string len=17>", sanitized["email"])
+	assert.Equal(t, 20, sanitized["limit"])
+	assert.Equal(t, true, sanitized["active"])
+	assert.Equal(t, "<list len=2>", sanitized["tags"])
+	assert.Nil(t, sanitizeParams(nil))
+}
@@ -0,0 +1,43 @@
+package database
+
+import (
+	"context"
+	"fmt"
+)
+
+// StatusDeleted is the status of a soft-deleted node. Soft-deleted nodes
+// stay in the graph but are hidden from reads unless deleted records are
+// explicitly requested.
+const StatusDeleted = "deleted"
+
+type includeDeletedKey struct{}
+
+// WithIncludeDeleted marks ctx so reads filtered with SoftDeleteFilter also
+// return soft-deleted nodes. Only operators may request this; see
+// middleware.IncludeDeleted.
+func WithIncludeDeleted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, includeDeletedKey{}, true)
+}
+
+// IncludeDeleted reports whether ctx asks for soft-deleted nodes
+func IncludeDeleted(ctx context.Context) bool {
+	include, _ := ctx.Value(includeDeletedKey{}).(bool)
+	return include
+}
+
+// NotDeleted returns a Cypher predicate matching nodes bound to alias that
+// are not soft-deleted. Nodes without a status count as not deleted.
+func NotDeleted(alias string) string {
+	return fmt.Sprintf("coalesce(%s.status, '') <> '%s'", alias, StatusDeleted)
+}
+
+// SoftDeleteFilter returns the soft-delete predicate for alias, or a
+// predicate that always holds when ctx includes deleted nodes. Every read
+// of a soft-deletable label (Space, Notebook, Document) should use it so
+// deleted records are hidden the same way everywhere.
+func SoftDeleteFilter(ctx context.Context, alias string) string {
+	if IncludeDeleted(ctx) {
+		return "true"
+	}
+	return NotDeleted(alias)
+}
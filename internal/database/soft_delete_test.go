@@ -0,0 +1,18 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSoftDeleteFilter(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, "coalesce(n.status, '') <> 'deleted'", SoftDeleteFilter(ctx, "n"))
+	assert.False(t, IncludeDeleted(ctx))
+
+	ctx = WithIncludeDeleted(ctx)
+	assert.True(t, IncludeDeleted(ctx))
+	assert.Equal(t, "true", SoftDeleteFilter(ctx, "n"))
+}
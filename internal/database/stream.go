@@ -0,0 +1,101 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+)
+
+// DefaultStreamFetchSize is used when no fetch size is configured
+const DefaultStreamFetchSize = 1000
+
+// RecordHandler is called for each record of a streamed query. Returning an
+// error stops the stream and is returned from StreamQuery.
+type RecordHandler func(record *neo4j.Record) error
+
+// streamFetchSize returns how many records the driver pulls per round trip
+func (c *Neo4jClient) streamFetchSize() int {
+	if c.config.StreamFetchSize > 0 {
+		return c.config.StreamFetchSize
+	}
+	return DefaultStreamFetchSize
+}
+
+// StreamQuery runs a read query and hands each record to fn as it arrives,
+// instead of collecting the whole result. The driver fetches records in
+// batches of the configured fetch size, so memory stays bounded however many
+// records the query returns.
+//
+// The query runs in an auto-commit read transaction that is not retried:
+// records already handed to fn cannot be taken back. The default query
+// timeout does not apply; ctx bounds the whole stream, and the connection
+// slot is held until it ends. It returns the number of records handled.
+func (c *Neo4jClient) StreamQuery(ctx context.Context, query string, params map[string]interface{}, fn RecordHandler) (int, error) {
+	if err := c.validateNeo4jParameters(params); err != nil {
+		return 0, fmt.Errorf("invalid parameters: %w", err)
+	}
+
+	release, err := c.acquireSlot(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	session := c.Session(ctx, func(sc *neo4j.SessionConfig) {
+		sc.AccessMode = neo4j.AccessModeRead
+		sc.FetchSize = c.streamFetchSize()
+	})
+	defer session.Close(ctx)
+
+	start := time.Now()
+	count, err := streamRecords(ctx, session, query, params, fn)
+	elapsed := time.Since(start)
+	log := c.logger.FromContext(ctx)
+
+	// Streams are expected to run long, so only the query metric is recorded
+	if c.metrics != nil {
+		status := "success"
+		if err != nil {
+			status = "error"
+		}
+		c.metrics.RecordDBQuery("neo4j", "stream", status, elapsed)
+	}
+
+	if err != nil {
+		log.Error("Streamed query failed",
+			zap.String("query_name", queryName(ctx, "stream")),
+			zap.Int("records", count),
+			zap.Error(err),
+		)
+		return count, err
+	}
+
+	log.Debug("Streamed query completed",
+		zap.String("query_name", queryName(ctx, "stream")),
+		zap.Int("records", count),
+		zap.Float64("duration_ms", elapsed.Seconds()*1000),
+	)
+	return count, nil
+}
+
+// streamRecords iterates the result of query, calling fn for each record
+func streamRecords(ctx context.Context, session neo4j.SessionWithContext, query string, params map[string]interface{}, fn RecordHandler) (int, error) {
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for result.Next(ctx) {
+		if err := fn(result.Record()); err != nil {
+			// Discard the rest so the server stops producing records
+			_, _ = result.Consume(ctx)
+			return count, err
+		}
+		count++
+	}
+	return count, result.Err()
+}
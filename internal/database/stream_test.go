@@ -0,0 +1,31 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamFetchSize(t *testing.T) {
+	client := &Neo4jClient{}
+	assert.Equal(t, DefaultStreamFetchSize, client.streamFetchSize())
+
+	client.config.StreamFetchSize = 250
+	assert.Equal(t, 250, client.streamFetchSize())
+}
+
+func TestStreamQueryRejectsInvalidParameters(t *testing.T) {
+	client := &Neo4jClient{}
+	called := false
+
+	_, err := client.StreamQuery(context.Background(), "MATCH (n) RETURN n", map[string]interface{}{
+		"filter": map[string]interface{}{"nested": true},
+	}, func(*neo4j.Record) error {
+		called = true
+		return nil
+	})
+	assert.ErrorContains(t, err, "invalid parameters")
+	assert.False(t, called)
+}
@@ -0,0 +1,40 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+)
+
+func TestWithQueryTimeout(t *testing.T) {
+	client := &Neo4jClient{config: config.DatabaseConfig{QueryTimeoutSeconds: 5}}
+
+	ctx, cancel := client.withQueryTimeout(context.Background())
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	assert.True(t, ok, "queries get the configured deadline")
+	assert.WithinDuration(t, time.Now().Add(5*time.Second), deadline, time.Second)
+
+	// An earlier deadline of the caller still applies
+	callerCtx, callerCancel := context.WithTimeout(context.Background(), time.Second)
+	defer callerCancel()
+	ctx, cancel = client.withQueryTimeout(callerCtx)
+	defer cancel()
+	callerDeadline, _ := callerCtx.Deadline()
+	deadline, _ = ctx.Deadline()
+	assert.Equal(t, callerDeadline, deadline)
+
+	// Cancelling the caller's context still cancels the query
+	client.config.QueryTimeoutSeconds = 0
+	parent, parentCancel := context.WithCancel(context.Background())
+	ctx, cancel = client.withQueryTimeout(parent)
+	defer cancel()
+	_, ok = ctx.Deadline()
+	assert.False(t, ok, "0 disables the deadline")
+	parentCancel()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
@@ -0,0 +1,90 @@
+package feeds
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testFeed = Feed{
+	ID:      "urn:uuid:notebook-1",
+	Title:   "Research <activity>",
+	Link:    "https://aether.example.com/feeds/notebooks/notebook-1/rss",
+	Author:  "Aether",
+	Updated: time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC),
+	Items: []Item{{
+		ID:          "urn:uuid:event-1",
+		Title:       "Document uploaded: Q3 & Q4.pdf",
+		Description: "Document uploaded by user user-1",
+		Published:   time.Date(2026, 10, 16, 11, 30, 0, 0, time.UTC),
+	}},
+}
+
+func TestWriteRSS(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteRSS(&buf, testFeed))
+
+	var doc rss
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, "2.0", doc.Version)
+	assert.Equal(t, "Research <activity>", doc.Channel.Title)
+	require.Len(t, doc.Channel.Items, 1)
+	assert.Equal(t, "Document uploaded: Q3 & Q4.pdf", doc.Channel.Items[0].Title)
+	assert.Equal(t, "urn:uuid:event-1", doc.Channel.Items[0].GUID.Value)
+	assert.Equal(t, "Fri, 16 Oct 2026 11:30:00 +0000", doc.Channel.Items[0].PubDate)
+}
+
+func TestWriteAtom(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteAtom(&buf, testFeed))
+	assert.Contains(t, buf.String(), `<feed xmlns="http://www.w3.org/2005/Atom">`)
+
+	var doc atomFeed
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, "urn:uuid:notebook-1", doc.ID)
+	assert.Equal(t, "2026-10-16T12:00:00Z", doc.Updated)
+	require.NotNil(t, doc.Author)
+	require.Len(t, doc.Entries, 1)
+	assert.Equal(t, "2026-10-16T11:30:00Z", doc.Entries[0].Updated)
+}
+
+func TestWriteICal(t *testing.T) {
+	start := time.Date(2026, 10, 17, 3, 0, 0, 0, time.UTC)
+	cal := Calendar{
+		Name:      "Aether scheduled jobs",
+		Generated: time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC),
+		Events: []Event{{
+			UID:         "cleanup-1792206000@aether-scheduler",
+			Summary:     "cleanup",
+			Description: "Failed: connection refused; retrying, later\n" + strings.Repeat("é", 60),
+			Start:       start,
+			End:         start.Add(time.Minute),
+			Status:      EventTentative,
+		}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteICal(&buf, cal))
+	out := buf.String()
+
+	lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+	assert.Equal(t, "BEGIN:VCALENDAR", lines[0])
+	assert.Equal(t, "END:VCALENDAR", lines[len(lines)-1])
+	assert.Contains(t, lines, "DTSTART:20261017T030000Z")
+	assert.Contains(t, lines, "DTSTAMP:20261016T120000Z")
+	assert.Contains(t, lines, "STATUS:TENTATIVE")
+
+	// Lines are folded within 75 octets without splitting characters
+	for _, line := range lines {
+		assert.LessOrEqual(t, len(line), 75)
+		assert.True(t, utf8.ValidString(line), line)
+	}
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	assert.Contains(t, unfolded, `DESCRIPTION:Failed: connection refused\; retrying\, later\n`+strings.Repeat("é", 60)+"\r\n")
+}
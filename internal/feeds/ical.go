@@ -0,0 +1,123 @@
+package feeds
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+)
+
+// Event statuses of a calendar event
+const (
+	EventConfirmed = "CONFIRMED"
+	EventTentative = "TENTATIVE"
+	EventCancelled = "CANCELLED"
+)
+
+// Calendar is a list of events
+type Calendar struct {
+	Name        string
+	Description string
+	Generated   time.Time // Stamped on every event
+	Events      []Event
+}
+
+// Event is one calendar event
+type Event struct {
+	UID         string // Stable and globally unique, so clients update rather than duplicate events
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+	Status      string
+	Categories  []string
+}
+
+// icalTime is the UTC date-time format of iCalendar
+const icalTime = "20060102T150405Z"
+
+// icalLineLength is the maximum length of a content line in octets,
+// excluding the line break
+const icalLineLength = 75
+
+// WriteICal writes the calendar as iCalendar (RFC 5545)
+func WriteICal(w io.Writer, cal Calendar) error {
+	out := bufio.NewWriter(w)
+	line := func(name, value string) {
+		writeICalLine(out, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//Tributary AI Services//Aether//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if cal.Name != "" {
+		line("X-WR-CALNAME", escapeICal(cal.Name))
+	}
+	if cal.Description != "" {
+		line("X-WR-CALDESC", escapeICal(cal.Description))
+	}
+	stamp := cal.Generated.UTC().Format(icalTime)
+	for _, event := range cal.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", escapeICal(event.UID))
+		line("DTSTAMP", stamp)
+		line("DTSTART", event.Start.UTC().Format(icalTime))
+		if !event.End.IsZero() {
+			line("DTEND", event.End.UTC().Format(icalTime))
+		}
+		line("SUMMARY", escapeICal(event.Summary))
+		if event.Description != "" {
+			line("DESCRIPTION", escapeICal(event.Description))
+		}
+		if event.Status != "" {
+			line("STATUS", event.Status)
+		}
+		if len(event.Categories) > 0 {
+			categories := make([]string, len(event.Categories))
+			for i, category := range event.Categories {
+				categories[i] = escapeICal(category)
+			}
+			line("CATEGORIES", strings.Join(categories, ","))
+		}
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return out.Flush()
+}
+
+// escapeICal escapes a TEXT value
+func escapeICal(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(value)
+}
+
+// writeICalLine writes a content line, folding it after every 75 octets
+// without splitting a UTF-8 sequence
+func writeICalLine(out *bufio.Writer, content string) {
+	limit := icalLineLength
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(content[cut]) {
+			cut--
+		}
+		out.WriteString(content[:cut])
+		out.WriteString("\r\n ")
+		content = content[cut:]
+		// Continuation lines start with a space, which counts towards the limit
+		limit = icalLineLength - 1
+	}
+	out.WriteString(content)
+	out.WriteString("\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
@@ -0,0 +1,113 @@
+// Package fieldcrypt encrypts single node properties, such as the extracted
+// text of documents, with AES-256-GCM under a key per tenant. Tenant keys
+// are derived from one master key, so only the master key is configured and
+// a value encrypted for one tenant cannot be decrypted as another's.
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Prefix marks encrypted values. What follows it is the base64 of the nonce
+// and the sealed value.
+const Prefix = "enc:v1:"
+
+// KeySize is the size of the master key in bytes
+const KeySize = 32
+
+// ErrDecrypt is returned for values that were not encrypted with the key
+// of the tenant, or were altered
+var ErrDecrypt = errors.New("fieldcrypt: value cannot be decrypted")
+
+// Cipher encrypts and decrypts values with the keys of tenants
+type Cipher struct {
+	master []byte
+}
+
+// New creates a cipher from a master key of KeySize bytes
+func New(master []byte) (*Cipher, error) {
+	if len(master) != KeySize {
+		return nil, fmt.Errorf("fieldcrypt: master key must be %d bytes, got %d", KeySize, len(master))
+	}
+	return &Cipher{master: append([]byte(nil), master...)}, nil
+}
+
+// ParseKey decodes a base64 master key, as configured
+func ParseKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("fieldcrypt: master key is not base64: %w", err)
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("fieldcrypt: master key must be %d bytes, got %d", KeySize, len(key))
+	}
+	return key, nil
+}
+
+// IsEncrypted reports whether a value was encrypted by a Cipher
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+// Encrypt encrypts a value with the key of a tenant. Empty and already
+// encrypted values are returned as they are.
+func (c *Cipher) Encrypt(tenantID, value string) (string, error) {
+	if value == "" || IsEncrypted(value) {
+		return value, nil
+	}
+	aead, err := c.aead(tenantID)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("fieldcrypt: failed to generate nonce: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(value), []byte(tenantID))
+	return Prefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a value encrypted with the key of a tenant. Values that
+// are not encrypted are returned as they are, so data written before its
+// space was encrypted reads the same.
+func (c *Cipher) Decrypt(tenantID, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	sealed, err := base64.StdEncoding.DecodeString(value[len(Prefix):])
+	if err != nil {
+		return "", ErrDecrypt
+	}
+	aead, err := c.aead(tenantID)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", ErrDecrypt
+	}
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(tenantID))
+	if err != nil {
+		return "", ErrDecrypt
+	}
+	return string(plain), nil
+}
+
+// aead returns the AES-GCM cipher of a tenant's key, derived from the
+// master key with HMAC-SHA256
+func (c *Cipher) aead(tenantID string) (cipher.AEAD, error) {
+	mac := hmac.New(sha256.New, c.master)
+	mac.Write([]byte("aether:tenant-key:" + tenantID))
+	block, err := aes.NewCipher(mac.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,74 @@
+package fieldcrypt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testCipher(t *testing.T) *Cipher {
+	c, err := New(bytes.Repeat([]byte{7}, KeySize))
+	require.NoError(t, err)
+	return c
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	c := testCipher(t)
+
+	encrypted, err := c.Encrypt("tenant-a", "Quarterly results")
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(encrypted))
+	assert.NotContains(t, encrypted, "Quarterly")
+
+	again, err := c.Encrypt("tenant-a", "Quarterly results")
+	require.NoError(t, err)
+	assert.NotEqual(t, encrypted, again, "each value gets its own nonce")
+
+	plain, err := c.Decrypt("tenant-a", encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "Quarterly results", plain)
+
+	unchanged, err := c.Encrypt("tenant-a", encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, encrypted, unchanged)
+}
+
+func TestDecryptOtherTenant(t *testing.T) {
+	c := testCipher(t)
+	encrypted, err := c.Encrypt("tenant-a", "secret")
+	require.NoError(t, err)
+
+	_, err = c.Decrypt("tenant-b", encrypted)
+	assert.ErrorIs(t, err, ErrDecrypt)
+
+	_, err = c.Decrypt("tenant-a", encrypted[:len(encrypted)-4]+"AAAA")
+	assert.ErrorIs(t, err, ErrDecrypt)
+}
+
+func TestDecryptPlaintext(t *testing.T) {
+	c := testCipher(t)
+	plain, err := c.Decrypt("tenant-a", "not encrypted")
+	require.NoError(t, err)
+	assert.Equal(t, "not encrypted", plain)
+
+	empty, err := c.Encrypt("tenant-a", "")
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+}
+
+func TestParseKey(t *testing.T) {
+	key, err := ParseKey(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, KeySize)))
+	require.NoError(t, err)
+	assert.Len(t, key, KeySize)
+
+	_, err = ParseKey(base64.StdEncoding.EncodeToString([]byte("short")))
+	assert.Error(t, err)
+	_, err = ParseKey("not base64!")
+	assert.Error(t, err)
+
+	_, err = New([]byte("short"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,289 @@
+// Package filetype identifies files by their content rather than by the
+// name or MIME type a client sends. It sniffs the leading bytes of a file,
+// knows which MIME types file extensions stand for, and decides whether a
+// claimed type is consistent with the content. It only inspects; the
+// callers decide what to reject.
+package filetype
+
+import (
+	"bytes"
+	"encoding/binary"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// Common MIME types
+const (
+	OctetStream = "application/octet-stream"
+	TextPlain   = "text/plain"
+	Zip         = "application/zip"
+	// OLEStorage is the compound file container of the legacy Office
+	// formats and Outlook messages
+	OLEStorage = "application/x-ole-storage"
+
+	PDF  = "application/pdf"
+	DOCX = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	XLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	PPTX = "application/vnd.openxmlformats-officedocument.presentationml.presentation"
+)
+
+// SniffBytes is how much of a file Detect reads, apart from the names of
+// the entries of zip archives
+const SniffBytes = 3072
+
+// extensionTypes are the MIME types each extension stands for, the usual
+// one first
+var extensionTypes = map[string][]string{
+	".pdf":      {PDF},
+	".doc":      {"application/msword"},
+	".docx":     {DOCX},
+	".xls":      {"application/vnd.ms-excel"},
+	".xlsx":     {XLSX},
+	".ppt":      {"application/vnd.ms-powerpoint"},
+	".pptx":     {PPTX},
+	".msg":      {"application/vnd.ms-outlook"},
+	".odt":      {"application/vnd.oasis.opendocument.text"},
+	".ods":      {"application/vnd.oasis.opendocument.spreadsheet"},
+	".odp":      {"application/vnd.oasis.opendocument.presentation"},
+	".epub":     {"application/epub+zip"},
+	".rtf":      {"application/rtf"},
+	".txt":      {TextPlain},
+	".log":      {TextPlain},
+	".md":       {"text/markdown"},
+	".markdown": {"text/markdown"},
+	".csv":      {"text/csv"},
+	".tsv":      {"text/tab-separated-values"},
+	".json":     {"application/json"},
+	".xml":      {"application/xml"},
+	".html":     {"text/html"},
+	".htm":      {"text/html"},
+	".yaml":     {"application/yaml"},
+	".yml":      {"application/yaml"},
+	".eml":      {"message/rfc822"},
+	".png":      {"image/png"},
+	".jpg":      {"image/jpeg"},
+	".jpeg":     {"image/jpeg"},
+	".gif":      {"image/gif"},
+	".webp":     {"image/webp"},
+	".bmp":      {"image/bmp"},
+	".tif":      {"image/tiff"},
+	".tiff":     {"image/tiff"},
+	".svg":      {"image/svg+xml"},
+	".mp3":      {"audio/mpeg"},
+	".wav":      {"audio/wav"},
+	".mp4":      {"video/mp4"},
+	".webm":     {"video/webm"},
+	".zip":      {Zip},
+	".gz":       {"application/gzip"},
+}
+
+// aliases map the other names clients send for a type to the one used here
+var aliases = map[string]string{
+	"image/jpg":                    "image/jpeg",
+	"image/pjpeg":                  "image/jpeg",
+	"image/x-png":                  "image/png",
+	"image/x-ms-bmp":               "image/bmp",
+	"audio/wave":                   "audio/wav",
+	"audio/x-wav":                  "audio/wav",
+	"audio/vnd.wave":               "audio/wav",
+	"audio/mp3":                    "audio/mpeg",
+	"application/x-zip-compressed": Zip,
+	"application/x-gzip":           "application/gzip",
+	"text/rtf":                     "application/rtf",
+	"text/xml":                     "application/xml",
+	"text/x-markdown":              "text/markdown",
+	"application/x-yaml":           "application/yaml",
+	"text/yaml":                    "application/yaml",
+	"text/x-yaml":                  "application/yaml",
+	"application/x-pdf":            PDF,
+}
+
+// oleTypes are stored in OLE compound files
+var oleTypes = map[string]bool{
+	"application/msword":            true,
+	"application/vnd.ms-excel":      true,
+	"application/vnd.ms-powerpoint": true,
+	"application/vnd.ms-outlook":    true,
+}
+
+// zipTypes are zip archives
+var zipTypes = map[string]bool{
+	Zip:                    true,
+	DOCX:                   true,
+	XLSX:                   true,
+	PPTX:                   true,
+	"application/epub+zip": true,
+	"application/vnd.oasis.opendocument.text":         true,
+	"application/vnd.oasis.opendocument.spreadsheet":  true,
+	"application/vnd.oasis.opendocument.presentation": true,
+	"application/java-archive":                        true,
+}
+
+// signed are the types Detect recognizes by their content, so a file
+// claiming one of them must have it
+var signed = map[string]bool{
+	PDF:                true,
+	"application/rtf":  true,
+	"application/gzip": true,
+	"image/png":        true,
+	"image/jpeg":       true,
+	"image/gif":        true,
+	"image/webp":       true,
+	"image/bmp":        true,
+	"image/tiff":       true,
+	"audio/wav":        true,
+	"video/mp4":        true,
+	"video/webm":       true,
+}
+
+// textApplicationTypes are application types whose files are text
+var textApplicationTypes = map[string]bool{
+	"application/json":       true,
+	"application/xml":        true,
+	"application/yaml":       true,
+	"application/javascript": true,
+	"application/x-ndjson":   true,
+	"application/sql":        true,
+	"application/x-sh":       true,
+	"image/svg+xml":          true,
+	"message/rfc822":         true,
+}
+
+// Normalize returns a MIME type in lower case without parameters, under
+// the name used here
+func Normalize(mimeType string) string {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(strings.Split(mimeType, ";")[0]))
+	}
+	if alias, ok := aliases[mediaType]; ok {
+		return alias
+	}
+	return mediaType
+}
+
+// ForExtension returns the MIME types a file name's extension stands for,
+// the usual one first, or nil when the extension is unknown
+func ForExtension(fileName string) []string {
+	return extensionTypes[strings.ToLower(filepath.Ext(fileName))]
+}
+
+// IsText reports whether files of a MIME type are text
+func IsText(mimeType string) bool {
+	mimeType = Normalize(mimeType)
+	return strings.HasPrefix(mimeType, "text/") || textApplicationTypes[mimeType]
+}
+
+// verifiable reports whether Detect recognizes files of a type, so their
+// content can contradict a claim of it
+func verifiable(mimeType string) bool {
+	return signed[mimeType] || oleTypes[mimeType] || zipTypes[mimeType]
+}
+
+// Detect returns the MIME type of a file from its content: a specific type
+// for the formats it recognizes, text/plain for other text and
+// application/octet-stream for other binary data. Office documents are
+// told apart by the entries of their zip archives, which Detect looks for
+// in all of data.
+func Detect(data []byte) string {
+	head := data
+	if len(head) > SniffBytes {
+		head = head[:SniffBytes]
+	}
+
+	switch {
+	case bytes.HasPrefix(head, []byte("%PDF-")):
+		return PDF
+	case bytes.HasPrefix(head, []byte("\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1")):
+		return OLEStorage
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")):
+		return detectZip(data)
+	case bytes.HasPrefix(head, []byte(`{\rtf`)):
+		return "application/rtf"
+	case bytes.HasPrefix(head, []byte("II*\x00")), bytes.HasPrefix(head, []byte("MM\x00*")):
+		return "image/tiff"
+	}
+
+	// Other text, including UTF-16 with a byte order mark, is text/plain
+	return Normalize(http.DetectContentType(head))
+}
+
+// detectZip tells Office Open XML, OpenDocument and EPUB files apart from
+// other zip archives
+func detectZip(data []byte) string {
+	// OpenDocument and EPUB files start with an uncompressed "mimetype"
+	// entry holding their type
+	if len(data) >= 30 && binary.LittleEndian.Uint16(data[8:10]) == 0 {
+		size := int(binary.LittleEndian.Uint32(data[18:22]))
+		nameLen := int(binary.LittleEndian.Uint16(data[26:28]))
+		extraLen := int(binary.LittleEndian.Uint16(data[28:30]))
+		start := 30 + nameLen + extraLen
+		if nameLen == len("mimetype") && string(data[30:30+nameLen]) == "mimetype" && size < 100 && start+size <= len(data) {
+			if mimeType := Normalize(string(data[start : start+size])); zipTypes[mimeType] {
+				return mimeType
+			}
+		}
+	}
+
+	// Entry names are stored uncompressed in the local and central headers
+	if bytes.Contains(data, []byte("[Content_Types].xml")) {
+		switch {
+		case bytes.Contains(data, []byte("word/document")):
+			return DOCX
+		case bytes.Contains(data, []byte("xl/workbook")):
+			return XLSX
+		case bytes.Contains(data, []byte("ppt/presentation")):
+			return PPTX
+		}
+	}
+	return Zip
+}
+
+// Matches reports whether a file whose content Detect identified as
+// detected may be of the claimed type. A claim is rejected when the
+// content contradicts it: when the claimed type is one Detect recognizes
+// and the content is something else, or when a text type is claimed for
+// binary content. Claims of types Detect does not know are accepted.
+func Matches(detected, claimed string) bool {
+	detected, claimed = Normalize(detected), Normalize(claimed)
+	switch {
+	case detected == claimed:
+		return true
+	case IsText(detected):
+		return !verifiable(claimed)
+	case IsText(claimed):
+		return false
+	case detected == OLEStorage && oleTypes[claimed]:
+		return true
+	case detected == Zip && zipTypes[claimed]:
+		// A zip archive Detect could not tell apart
+		return true
+	}
+	return !verifiable(claimed)
+}
+
+// MatchesPattern reports whether a MIME type matches a pattern, a type
+// such as "application/pdf" or all subtypes of a type, as "image/*"
+func MatchesPattern(mimeType, pattern string) bool {
+	mimeType, pattern = Normalize(mimeType), Normalize(pattern)
+	if prefix, ok := strings.CutSuffix(pattern, "/*"); ok {
+		return strings.HasPrefix(mimeType, prefix+"/")
+	}
+	return mimeType == pattern
+}
+
+// ValidPattern reports whether a pattern is a MIME type or a type with a
+// wildcard subtype
+func ValidPattern(pattern string) bool {
+	kind, subtype, ok := strings.Cut(pattern, "/")
+	if !ok || kind == "" || subtype == "" || kind == "*" {
+		return false
+	}
+	if subtype == "*" {
+		subtype = "x"
+	}
+	_, _, err := mime.ParseMediaType(kind + "/" + subtype)
+	return err == nil && !strings.Contains(pattern, ";")
+}
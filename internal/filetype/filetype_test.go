@@ -0,0 +1,116 @@
+package filetype
+
+import (
+	"archive/zip"
+	"bytes"
+	"hash/crc32"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// zipFile builds a zip archive of the named entries, storing the first
+// uncompressed with its size in the local header, as OpenDocument requires
+// of its mimetype entry
+func zipFile(t *testing.T, entries ...[2]string) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for i, entry := range entries {
+		var f io.Writer
+		var err error
+		if i == 0 {
+			f, err = w.CreateRaw(&zip.FileHeader{
+				Name:               entry[0],
+				Method:             zip.Store,
+				CRC32:              crc32.ChecksumIEEE([]byte(entry[1])),
+				CompressedSize64:   uint64(len(entry[1])),
+				UncompressedSize64: uint64(len(entry[1])),
+			})
+		} else {
+			f, err = w.Create(entry[0])
+		}
+		require.NoError(t, err)
+		_, err = f.Write([]byte(entry[1]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"pdf", []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n1 0 obj"), PDF},
+		{"png", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), "image/png"},
+		{"jpeg", []byte("\xff\xd8\xff\xe0\x00\x10JFIF"), "image/jpeg"},
+		{"tiff", []byte("II*\x00\x08\x00\x00\x00"), "image/tiff"},
+		{"legacy office", []byte("\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1\x00\x00"), OLEStorage},
+		{"rtf", []byte(`{\rtf1\ansi Hello}`), "application/rtf"},
+		{"text", []byte("Quarterly numbers, région Île-de-France\n"), TextPlain},
+		{"html", []byte("<!DOCTYPE html><html><body>hi</body></html>"), "text/html"},
+		{"binary", []byte{0x00, 0x01, 0x02, 0x03, 0xfe}, OctetStream},
+		{"docx", zipFile(t, [2]string{"[Content_Types].xml", "<Types/>"}, [2]string{"word/document.xml", "<w:document/>"}), DOCX},
+		{"xlsx", zipFile(t, [2]string{"[Content_Types].xml", "<Types/>"}, [2]string{"xl/workbook.xml", "<workbook/>"}), XLSX},
+		{"odt", zipFile(t, [2]string{"mimetype", "application/vnd.oasis.opendocument.text"}, [2]string{"content.xml", "<office/>"}), "application/vnd.oasis.opendocument.text"},
+		{"zip", zipFile(t, [2]string{"notes.txt", "hello"}), Zip},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Detect(tt.data))
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		detected, claimed string
+		want              bool
+	}{
+		{PDF, "application/pdf", true},
+		{PDF, "application/x-pdf", true},
+		{"image/jpeg", "image/jpg", true},
+		{TextPlain, "text/csv; charset=utf-8", true},
+		{TextPlain, "application/json", true},
+		{"text/html", "text/markdown", true},
+		{OLEStorage, "application/msword", true},
+		{Zip, DOCX, true},
+		{OctetStream, "application/x-parquet", true},
+		{TextPlain, "application/x-custom", true},
+
+		{"image/png", PDF, false},
+		{DOCX, XLSX, false},
+		{TextPlain, PDF, false},
+		{PDF, "text/plain", false},
+		{OctetStream, "text/csv", false},
+		{OctetStream, DOCX, false},
+		{OLEStorage, PDF, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Matches(tt.detected, tt.claimed), "%s claimed as %s", tt.detected, tt.claimed)
+	}
+}
+
+func TestForExtension(t *testing.T) {
+	assert.Equal(t, []string{PDF}, ForExtension("Invoice.PDF"))
+	assert.Equal(t, []string{"text/markdown"}, ForExtension("notes.md"))
+	assert.Nil(t, ForExtension("archive.xyz"))
+	assert.Nil(t, ForExtension("README"))
+}
+
+func TestPatterns(t *testing.T) {
+	assert.True(t, MatchesPattern("image/png", "image/*"))
+	assert.True(t, MatchesPattern("application/pdf", "application/pdf"))
+	assert.True(t, MatchesPattern("image/jpg", "image/jpeg"), "aliases match")
+	assert.False(t, MatchesPattern("application/pdf", "image/*"))
+
+	assert.True(t, ValidPattern("image/*"))
+	assert.True(t, ValidPattern("application/vnd.ms-excel"))
+	assert.False(t, ValidPattern("*/*"))
+	assert.False(t, ValidPattern("pdf"))
+	assert.False(t, ValidPattern("text/plain; charset=utf-8"))
+}
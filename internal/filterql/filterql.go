@@ -0,0 +1,366 @@
+// Package filterql compiles structured search filters such as
+//
+//	tag:contract AND mime:pdf AND created:>2024-01-01
+//
+// into Cypher predicates. An expression is a list of field:value terms
+// combined with AND, OR, NOT and parentheses; adjacent terms are ANDed.
+// Values containing spaces are quoted: name:"q3 report". Values are always
+// passed as query parameters, never spliced into the query.
+package filterql
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kind is how a field's values are matched
+type Kind int
+
+const (
+	// KindString matches a whole value case-insensitively; a trailing *
+	// matches a prefix
+	KindString Kind = iota
+	// KindText matches values containing the term
+	KindText
+	// KindList matches list properties containing the value
+	KindList
+	// KindMIME matches a MIME type or either of its halves, so mime:pdf
+	// and mime:image match application/pdf and image/png
+	KindMIME
+	// KindNumber compares numbers with =, >, >=, < and <=
+	KindNumber
+	// KindTime compares times given as 2006-01-02 dates or RFC 3339
+	// timestamps. A date stands for the whole UTC day.
+	KindTime
+)
+
+// Field maps a filter field to a node property
+type Field struct {
+	Property string
+	Kind     Kind
+}
+
+// Schema lists the fields an expression may use
+type Schema map[string]Field
+
+// Names returns the field names of the schema, sorted
+func (s Schema) Names() []string {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Limits on an expression, keeping the generated query small
+const (
+	maxTerms = 20
+	maxDepth = 8
+)
+
+// paramPrefix namespaces the generated parameters so they cannot collide
+// with the caller's
+const paramPrefix = "filter_"
+
+// Compile parses expr against schema and returns a Cypher predicate on
+// the node bound to variable, with its parameters. An empty expression
+// compiles to an empty predicate.
+func Compile(expr string, schema Schema, variable string) (string, map[string]interface{}, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(tokens) == 0 {
+		return "", nil, nil
+	}
+
+	c := &compiler{tokens: tokens, schema: schema, variable: variable, params: make(map[string]interface{})}
+	predicate, err := c.parseOr(0)
+	if err != nil {
+		return "", nil, err
+	}
+	if c.pos < len(c.tokens) {
+		return "", nil, fmt.Errorf("unexpected %q", c.tokens[c.pos].text)
+	}
+	return predicate, c.params, nil
+}
+
+type tokenKind int
+
+const (
+	tokenTerm tokenKind = iota
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenOpen
+	tokenClose
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// tokenize splits an expression into parentheses, operators and terms.
+// Quotes only group characters; they are removed from the term.
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		switch r := runes[i]; {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenOpen, text: "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenClose, text: ")"})
+			i++
+		default:
+			var word strings.Builder
+			quoted := false
+			for i < len(runes) {
+				r := runes[i]
+				if r == '"' {
+					end := i + 1
+					for end < len(runes) && runes[end] != '"' {
+						end++
+					}
+					if end == len(runes) {
+						return nil, fmt.Errorf("unterminated quote")
+					}
+					word.WriteString(string(runes[i+1 : end]))
+					quoted = true
+					i = end + 1
+					continue
+				}
+				if r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '(' || r == ')' {
+					break
+				}
+				word.WriteRune(r)
+				i++
+			}
+
+			text := word.String()
+			kind := tokenTerm
+			if !quoted {
+				switch text {
+				case "AND":
+					kind = tokenAnd
+				case "OR":
+					kind = tokenOr
+				case "NOT":
+					kind = tokenNot
+				}
+			}
+			tokens = append(tokens, token{kind: kind, text: text})
+		}
+	}
+	return tokens, nil
+}
+
+type compiler struct {
+	tokens   []token
+	pos      int
+	terms    int
+	schema   Schema
+	variable string
+	params   map[string]interface{}
+}
+
+func (c *compiler) peek() (token, bool) {
+	if c.pos >= len(c.tokens) {
+		return token{}, false
+	}
+	return c.tokens[c.pos], true
+}
+
+// parseOr parses terms joined by OR, which binds loosest
+func (c *compiler) parseOr(depth int) (string, error) {
+	predicates := []string{}
+	for {
+		predicate, err := c.parseAnd(depth)
+		if err != nil {
+			return "", err
+		}
+		predicates = append(predicates, predicate)
+
+		if next, ok := c.peek(); !ok || next.kind != tokenOr {
+			break
+		}
+		c.pos++
+	}
+	return join(predicates, " OR "), nil
+}
+
+// parseAnd parses terms joined by AND or by nothing
+func (c *compiler) parseAnd(depth int) (string, error) {
+	predicates := []string{}
+	for {
+		predicate, err := c.parseNot(depth)
+		if err != nil {
+			return "", err
+		}
+		predicates = append(predicates, predicate)
+
+		next, ok := c.peek()
+		if !ok || next.kind == tokenOr || next.kind == tokenClose {
+			break
+		}
+		if next.kind == tokenAnd {
+			c.pos++
+		}
+	}
+	return join(predicates, " AND "), nil
+}
+
+func (c *compiler) parseNot(depth int) (string, error) {
+	if next, ok := c.peek(); ok && next.kind == tokenNot {
+		c.pos++
+		predicate, err := c.parseNot(depth)
+		if err != nil {
+			return "", err
+		}
+		return "NOT " + predicate, nil
+	}
+	return c.parsePrimary(depth)
+}
+
+func (c *compiler) parsePrimary(depth int) (string, error) {
+	next, ok := c.peek()
+	if !ok {
+		return "", fmt.Errorf("expression ends unexpectedly")
+	}
+	c.pos++
+
+	switch next.kind {
+	case tokenOpen:
+		if depth >= maxDepth {
+			return "", fmt.Errorf("expression is nested more than %d levels deep", maxDepth)
+		}
+		predicate, err := c.parseOr(depth + 1)
+		if err != nil {
+			return "", err
+		}
+		if closing, ok := c.peek(); !ok || closing.kind != tokenClose {
+			return "", fmt.Errorf("missing closing parenthesis")
+		}
+		c.pos++
+		// Combinations are parenthesized when joined
+		return predicate, nil
+	case tokenTerm:
+		return c.compileTerm(next.text)
+	default:
+		return "", fmt.Errorf("unexpected %q", next.text)
+	}
+}
+
+// join combines predicates, parenthesizing a combination
+func join(predicates []string, operator string) string {
+	if len(predicates) == 1 {
+		return predicates[0]
+	}
+	return "(" + strings.Join(predicates, operator) + ")"
+}
+
+// comparison operators, longest first so >= is not read as >
+var comparisons = []string{">=", "<=", ">", "<", "="}
+
+// compileTerm compiles one field:value term
+func (c *compiler) compileTerm(term string) (string, error) {
+	c.terms++
+	if c.terms > maxTerms {
+		return "", fmt.Errorf("expression has more than %d terms", maxTerms)
+	}
+
+	name, value, ok := strings.Cut(term, ":")
+	if !ok {
+		return "", fmt.Errorf("expected field:value, got %q", term)
+	}
+	name = strings.ToLower(name)
+	field, ok := c.schema[name]
+	if !ok {
+		return "", fmt.Errorf("unknown field %q; fields are %s", name, strings.Join(c.schema.Names(), ", "))
+	}
+
+	operator := "="
+	for _, candidate := range comparisons {
+		if rest, ok := strings.CutPrefix(value, candidate); ok {
+			operator, value = candidate, rest
+			break
+		}
+	}
+	if value == "" {
+		return "", fmt.Errorf("field %q needs a value", name)
+	}
+
+	property := c.variable + "." + field.Property
+	switch field.Kind {
+	case KindNumber:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "", fmt.Errorf("field %q needs a number, got %q", name, value)
+		}
+		return fmt.Sprintf("%s %s $%s", property, operator, c.param(number)), nil
+	case KindTime:
+		return c.compileTime(name, property, operator, value)
+	}
+
+	if operator != "=" {
+		return "", fmt.Errorf("field %q cannot be compared with %s", name, operator)
+	}
+	value = strings.ToLower(value)
+
+	switch field.Kind {
+	case KindText:
+		return fmt.Sprintf("toLower(%s) CONTAINS $%s", property, c.param(value)), nil
+	case KindList:
+		return fmt.Sprintf("ANY(item IN coalesce(%s, []) WHERE toLower(item) = $%s)", property, c.param(value)), nil
+	case KindMIME:
+		param := c.param(value)
+		return fmt.Sprintf("(toLower(%[1]s) = $%[2]s OR toLower(%[1]s) STARTS WITH $%[2]s + '/' OR toLower(%[1]s) ENDS WITH '/' + $%[2]s)", property, param), nil
+	default:
+		if prefix, ok := strings.CutSuffix(value, "*"); ok && prefix != "" {
+			return fmt.Sprintf("toLower(%s) STARTS WITH $%s", property, c.param(prefix)), nil
+		}
+		return fmt.Sprintf("toLower(%s) = $%s", property, c.param(value)), nil
+	}
+}
+
+// compileTime compares a time property. A date covers its whole day, so
+// created:2024-01-01 matches the day and created:>2024-01-01 starts the
+// day after.
+func (c *compiler) compileTime(name, property, operator, value string) (string, error) {
+	if instant, err := time.Parse(time.RFC3339, value); err == nil {
+		return fmt.Sprintf("%s %s $%s", property, operator, c.param(instant.UTC())), nil
+	}
+	day, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return "", fmt.Errorf("field %q needs a date (2006-01-02) or RFC 3339 time, got %q", name, value)
+	}
+	next := day.AddDate(0, 0, 1)
+
+	switch operator {
+	case ">":
+		return fmt.Sprintf("%s >= $%s", property, c.param(next)), nil
+	case ">=":
+		return fmt.Sprintf("%s >= $%s", property, c.param(day)), nil
+	case "<":
+		return fmt.Sprintf("%s < $%s", property, c.param(day)), nil
+	case "<=":
+		return fmt.Sprintf("%s < $%s", property, c.param(next)), nil
+	default:
+		return fmt.Sprintf("(%s >= $%s AND %s < $%s)", property, c.param(day), property, c.param(next)), nil
+	}
+}
+
+// param stores a parameter value and returns its name
+func (c *compiler) param(value interface{}) string {
+	name := fmt.Sprintf("%s%d", paramPrefix, len(c.params))
+	c.params[name] = value
+	return name
+}
@@ -0,0 +1,89 @@
+package filterql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSchema = Schema{
+	"tag":        {Property: "tags", Kind: KindList},
+	"mime":       {Property: "mime_type", Kind: KindMIME},
+	"status":     {Property: "status", Kind: KindString},
+	"name":       {Property: "name", Kind: KindText},
+	"size":       {Property: "size_bytes", Kind: KindNumber},
+	"created":    {Property: "created_at", Kind: KindTime},
+	"confidence": {Property: "confidence", Kind: KindNumber},
+}
+
+func day(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestCompile(t *testing.T) {
+	predicate, params, err := Compile("tag:contract AND mime:pdf AND created:>2024-01-01", testSchema, "d")
+	require.NoError(t, err)
+	assert.Equal(t, "(ANY(item IN coalesce(d.tags, []) WHERE toLower(item) = $filter_0) AND "+
+		"(toLower(d.mime_type) = $filter_1 OR toLower(d.mime_type) STARTS WITH $filter_1 + '/' OR toLower(d.mime_type) ENDS WITH '/' + $filter_1) AND "+
+		"d.created_at >= $filter_2)", predicate)
+	assert.Equal(t, map[string]interface{}{
+		"filter_0": "contract",
+		"filter_1": "pdf",
+		"filter_2": day("2024-01-02"),
+	}, params)
+}
+
+func TestCompilePrecedence(t *testing.T) {
+	predicate, _, err := Compile(`status:processed OR NOT status:failed name:"Q3 report"`, testSchema, "d")
+	require.NoError(t, err)
+	assert.Equal(t, "(toLower(d.status) = $filter_0 OR (NOT toLower(d.status) = $filter_1 AND toLower(d.name) CONTAINS $filter_2))", predicate,
+		"AND binds tighter than OR and adjacent terms are ANDed")
+
+	predicate, params, err := Compile("(status:proc* OR size:>=1024) AND created:2024-03-05", testSchema, "d")
+	require.NoError(t, err)
+	assert.Equal(t, "((toLower(d.status) STARTS WITH $filter_0 OR d.size_bytes >= $filter_1) AND (d.created_at >= $filter_2 AND d.created_at < $filter_3))", predicate)
+	assert.Equal(t, "proc", params["filter_0"])
+	assert.Equal(t, 1024.0, params["filter_1"])
+	assert.Equal(t, day("2024-03-06"), params["filter_3"])
+}
+
+func TestCompileEmpty(t *testing.T) {
+	predicate, params, err := Compile("   ", testSchema, "d")
+	require.NoError(t, err)
+	assert.Empty(t, predicate)
+	assert.Nil(t, params)
+}
+
+func TestCompileErrors(t *testing.T) {
+	for expr, message := range map[string]string{
+		"owner:me":                  `unknown field "owner"`,
+		"contract":                  "expected field:value",
+		"status:>processed":         "cannot be compared",
+		"size:big":                  "needs a number",
+		"created:yesterday":         "needs a date",
+		"tag:a AND":                 "ends unexpectedly",
+		"(tag:a OR tag:b":           "missing closing parenthesis",
+		"tag:a)":                    `unexpected ")"`,
+		`name:"open`:                "unterminated quote",
+		"tag:":                      "needs a value",
+		"((((((((((tag:a))))))))))": "nested",
+	} {
+		_, _, err := Compile(expr, testSchema, "d")
+		if assert.Error(t, err, expr) {
+			assert.Contains(t, err.Error(), message, expr)
+		}
+	}
+
+	expr := "tag:a"
+	for i := 0; i < maxTerms; i++ {
+		expr += " OR tag:a"
+	}
+	_, _, err := Compile(expr, testSchema, "d")
+	assert.ErrorContains(t, err, "more than")
+}
@@ -0,0 +1,239 @@
+// Package gql serves a GraphQL view over spaces, notebooks, documents and
+// agents. Resolvers call the same services as the REST handlers, so the
+// space context and per-resource permission checks apply unchanged, and
+// per-request dataloaders batch the lookups of nested fields.
+package gql
+
+import (
+	"context"
+	_ "embed"
+	"sync"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+//go:embed schema.graphql
+var schemaSource string
+
+// DefaultMaxDepth is the deepest query accepted when the configuration
+// leaves the limit unset
+const DefaultMaxDepth = 10
+
+// NotebookService is the part of the notebook service the API reads
+type NotebookService interface {
+	ListNotebooks(ctx context.Context, userID string, spaceCtx *models.SpaceContext, limit, offset int) (*models.NotebookListResponse, error)
+	GetNotebooksByIDs(ctx context.Context, notebookIDs []string, spaceCtx *models.SpaceContext) (map[string]*models.NotebookResponse, error)
+}
+
+// DocumentService is the part of the document service the API reads
+type DocumentService interface {
+	GetDocumentByID(ctx context.Context, documentID string, userID string, spaceCtx *models.SpaceContext) (*models.Document, error)
+	ListDocumentsByNotebooks(ctx context.Context, notebookIDs []string, spaceCtx *models.SpaceContext, limit, offset int) (map[string]*models.DocumentListResponse, error)
+}
+
+// AgentService is the part of the agent service the API reads
+type AgentService interface {
+	GetAgent(ctx context.Context, agentID string, userID string, userTeams []string) (*models.AgentResponse, error)
+	ListAgents(ctx context.Context, req models.AgentSearchRequest, userID string, userTeams []string, authToken string) (*models.AgentListResponse, error)
+	GetKnowledgeSourceIDs(ctx context.Context, agentIDs []string) (map[string][]string, error)
+}
+
+// SpaceService lists and resolves the spaces of a user
+type SpaceService interface {
+	GetUserSpaces(ctx context.Context, userID string) (*models.SpaceListResponse, error)
+	ResolveSpaceContext(ctx context.Context, userID string, req models.SpaceContextRequest) (*models.SpaceContext, error)
+}
+
+// TeamService looks up the teams of a user, which grant access to agents
+type TeamService interface {
+	GetUserTeamIDs(ctx context.Context, userID string) ([]string, error)
+}
+
+// Deps are the services the resolvers call
+type Deps struct {
+	Notebooks NotebookService
+	Documents DocumentService
+	Agents    AgentService
+	Spaces    SpaceService
+	Teams     TeamService
+}
+
+// Viewer identifies who a query runs for
+type Viewer struct {
+	UserID     string // Internal user ID, which owns resources
+	KeycloakID string // Identity of the token, which space membership is keyed by
+	AuthToken  string
+	// SpaceContext is the space selected by the request headers; nil when
+	// none was selected, in which case only spaces can be queried
+	SpaceContext *models.SpaceContext
+}
+
+// API executes GraphQL queries
+type API struct {
+	schema *graphql.Schema
+	deps   Deps
+}
+
+// New parses the schema and binds it to the services. Queries nested more
+// than maxDepth levels are rejected before they run.
+func New(deps Deps, maxDepth int, log *logger.Logger) (*API, error) {
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxDepth
+	}
+	schema, err := graphql.ParseSchema(schemaSource, &rootResolver{},
+		graphql.MaxDepth(maxDepth),
+		graphql.Logger(panicLogger{log}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &API{schema: schema, deps: deps}, nil
+}
+
+// Exec runs a query for the viewer. Failures are reported in the errors of
+// the response, as GraphQL requires.
+func (a *API) Exec(ctx context.Context, viewer Viewer, query, operationName string, variables map[string]interface{}) *graphql.Response {
+	ctx = context.WithValue(ctx, requestKey{}, newRequest(a.deps, viewer))
+	return a.schema.Exec(ctx, query, operationName, variables)
+}
+
+// requestKey is the context key of the per-request state
+type requestKey struct{}
+
+// request holds the state of one query: who runs it, the space contexts it
+// resolved and the loaders batching its lookups
+type request struct {
+	deps   Deps
+	viewer Viewer
+
+	teamsOnce sync.Once
+	teams     []string
+
+	spacesMu sync.Mutex
+	spaces   map[string]*models.SpaceContext
+
+	loadersMu sync.Mutex
+	loaders   map[string]*spaceLoaders
+	sources   *knowledgeSourceLoader
+}
+
+func newRequest(deps Deps, viewer Viewer) *request {
+	return &request{
+		deps:    deps,
+		viewer:  viewer,
+		spaces:  make(map[string]*models.SpaceContext),
+		loaders: make(map[string]*spaceLoaders),
+		sources: newKnowledgeSourceLoader(deps.Agents),
+	}
+}
+
+func requestFrom(ctx context.Context) *request {
+	return ctx.Value(requestKey{}).(*request)
+}
+
+// currentSpace returns the space selected by the request headers
+func (r *request) currentSpace() (*models.SpaceContext, error) {
+	if r.viewer.SpaceContext == nil {
+		return nil, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired)
+	}
+	return r.viewer.SpaceContext, nil
+}
+
+// spaceContext resolves the viewer's context in a space, checking their
+// membership as the space context middleware does. Contexts are resolved
+// once per request.
+func (r *request) spaceContext(ctx context.Context, spaceType models.SpaceType, spaceID string) (*models.SpaceContext, error) {
+	if current := r.viewer.SpaceContext; current != nil && current.SpaceType == spaceType && current.SpaceID == spaceID {
+		return current, nil
+	}
+
+	key := string(spaceType) + ":" + spaceID
+	r.spacesMu.Lock()
+	defer r.spacesMu.Unlock()
+	if spaceCtx, ok := r.spaces[key]; ok {
+		return spaceCtx, nil
+	}
+	spaceCtx, err := r.deps.Spaces.ResolveSpaceContext(ctx, r.viewer.KeycloakID, models.SpaceContextRequest{
+		SpaceType: spaceType,
+		SpaceID:   spaceID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.spaces[key] = spaceCtx
+	return spaceCtx, nil
+}
+
+// userTeams returns the viewer's team IDs, which grant access to agents.
+// Like the REST handlers, a failed lookup leaves the viewer without teams.
+func (r *request) userTeams(ctx context.Context) []string {
+	r.teamsOnce.Do(func() {
+		teams, err := r.deps.Teams.GetUserTeamIDs(ctx, r.viewer.UserID)
+		if err != nil || teams == nil {
+			teams = []string{}
+		}
+		r.teams = teams
+	})
+	return r.teams
+}
+
+// loadersFor returns the loaders reading from a space
+func (r *request) loadersFor(spaceCtx *models.SpaceContext) *spaceLoaders {
+	r.loadersMu.Lock()
+	defer r.loadersMu.Unlock()
+	key := string(spaceCtx.SpaceType) + ":" + spaceCtx.SpaceID
+	loaders, ok := r.loaders[key]
+	if !ok {
+		loaders = newSpaceLoaders(r.deps, spaceCtx)
+		r.loaders[key] = loaders
+	}
+	return loaders
+}
+
+// resolverError carries the API error code of a failed resolver into the
+// extensions of the GraphQL error. The message is the client-facing one, so
+// causes such as database errors are not exposed.
+type resolverError struct {
+	apiErr *errors.APIError
+}
+
+func newResolverError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &resolverError{apiErr: errors.Normalize(err)}
+}
+
+func (e *resolverError) Error() string {
+	return e.apiErr.Message
+}
+
+func (e *resolverError) Unwrap() error {
+	return e.apiErr
+}
+
+// Extensions implements the graphql-go interface for error extensions
+func (e *resolverError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":       e.apiErr.Code,
+		"error_code": e.apiErr.ErrorCode,
+	}
+}
+
+// panicLogger logs resolver panics, which graphql-go turns into errors
+type panicLogger struct {
+	log *logger.Logger
+}
+
+// LogPanic implements the graphql-go logger
+func (l panicLogger) LogPanic(ctx context.Context, value interface{}) {
+	if l.log == nil {
+		return
+	}
+	l.log.FromContext(ctx).Error("GraphQL resolver panicked", zap.Any("panic", value))
+}
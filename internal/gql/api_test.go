@@ -0,0 +1,266 @@
+package gql
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// fakeServices serves a fixed tree and counts the calls of each method
+type fakeServices struct {
+	mu        sync.Mutex
+	calls     map[string]int
+	notebooks []*models.NotebookResponse
+	documents map[string][]*models.DocumentResponse
+	agents    []*models.AgentResponse
+	sources   map[string][]string
+}
+
+func newFakeServices() *fakeServices {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	return &fakeServices{
+		calls: map[string]int{},
+		notebooks: []*models.NotebookResponse{
+			{ID: "nb-1", Name: "Research", Status: "active", Visibility: "private", CreatedAt: now, UpdatedAt: now},
+			{ID: "nb-2", Name: "Drafts", Status: "active", Visibility: "private", ParentID: "nb-1", CreatedAt: now, UpdatedAt: now},
+			{ID: "nb-3", Name: "Archive", Status: "archived", Visibility: "private", CreatedAt: now, UpdatedAt: now},
+		},
+		documents: map[string][]*models.DocumentResponse{
+			"nb-1": {{ID: "doc-1", Name: "paper.pdf", NotebookID: "nb-1", CreatedAt: now, UpdatedAt: now}},
+			"nb-2": {{ID: "doc-2", Name: "notes.md", NotebookID: "nb-2", CreatedAt: now, UpdatedAt: now}},
+		},
+		agents: []*models.AgentResponse{
+			{ID: "agent-1", Name: "Mine", OwnerID: "user-1", CreatedAt: now, UpdatedAt: now},
+			{ID: "agent-2", Name: "Shared", OwnerID: "user-2", IsPublic: true, CreatedAt: now, UpdatedAt: now},
+		},
+		sources: map[string][]string{
+			"agent-1": {"nb-3", "nb-1", "nb-elsewhere"},
+		},
+	}
+}
+
+func (f *fakeServices) count(method string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[method]++
+}
+
+func (f *fakeServices) callsTo(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[method]
+}
+
+func (f *fakeServices) ListNotebooks(ctx context.Context, userID string, spaceCtx *models.SpaceContext, limit, offset int) (*models.NotebookListResponse, error) {
+	f.count("ListNotebooks")
+	return &models.NotebookListResponse{Notebooks: f.notebooks, Total: len(f.notebooks), Limit: limit, Offset: offset}, nil
+}
+
+func (f *fakeServices) GetNotebooksByIDs(ctx context.Context, notebookIDs []string, spaceCtx *models.SpaceContext) (map[string]*models.NotebookResponse, error) {
+	f.count("GetNotebooksByIDs")
+	found := map[string]*models.NotebookResponse{}
+	for _, notebook := range f.notebooks {
+		for _, id := range notebookIDs {
+			if notebook.ID == id {
+				found[id] = notebook
+			}
+		}
+	}
+	return found, nil
+}
+
+func (f *fakeServices) GetDocumentByID(ctx context.Context, documentID string, userID string, spaceCtx *models.SpaceContext) (*models.Document, error) {
+	f.count("GetDocumentByID")
+	return nil, errors.NotFound("Document not found")
+}
+
+func (f *fakeServices) ListDocumentsByNotebooks(ctx context.Context, notebookIDs []string, spaceCtx *models.SpaceContext, limit, offset int) (map[string]*models.DocumentListResponse, error) {
+	f.count("ListDocumentsByNotebooks")
+	pages := map[string]*models.DocumentListResponse{}
+	for _, id := range notebookIDs {
+		documents := f.documents[id]
+		pages[id] = &models.DocumentListResponse{Documents: documents, Total: len(documents), Limit: limit, Offset: offset}
+	}
+	return pages, nil
+}
+
+func (f *fakeServices) GetAgent(ctx context.Context, agentID string, userID string, userTeams []string) (*models.AgentResponse, error) {
+	f.count("GetAgent")
+	for _, agent := range f.agents {
+		if agent.ID == agentID {
+			return agent, nil
+		}
+	}
+	return nil, errors.NotFound("Agent not found")
+}
+
+func (f *fakeServices) ListAgents(ctx context.Context, req models.AgentSearchRequest, userID string, userTeams []string, authToken string) (*models.AgentListResponse, error) {
+	f.count("ListAgents")
+	return &models.AgentListResponse{Agents: f.agents, Total: len(f.agents), Limit: req.Limit, Offset: req.Offset}, nil
+}
+
+func (f *fakeServices) GetKnowledgeSourceIDs(ctx context.Context, agentIDs []string) (map[string][]string, error) {
+	f.count("GetKnowledgeSourceIDs")
+	return f.sources, nil
+}
+
+func (f *fakeServices) GetUserSpaces(ctx context.Context, userID string) (*models.SpaceListResponse, error) {
+	f.count("GetUserSpaces")
+	if userID != "kc-1" {
+		return nil, errors.NotFound("User not found")
+	}
+	return &models.SpaceListResponse{
+		PersonalSpace:      &models.SpaceInfo{SpaceType: models.SpaceTypePersonal, SpaceID: "space-personal", SpaceName: "Personal", UserRole: "owner"},
+		OrganizationSpaces: []*models.SpaceInfo{{SpaceType: models.SpaceTypeOrganization, SpaceID: "space-org", SpaceName: "Acme", UserRole: "member", OrganizationID: "org-1"}},
+	}, nil
+}
+
+func (f *fakeServices) ResolveSpaceContext(ctx context.Context, userID string, req models.SpaceContextRequest) (*models.SpaceContext, error) {
+	f.count("ResolveSpaceContext")
+	if userID != "kc-1" {
+		return nil, errors.NotFound("User not found")
+	}
+	return &models.SpaceContext{SpaceType: req.SpaceType, SpaceID: req.SpaceID, UserID: userID, UserRole: "member"}, nil
+}
+
+func (f *fakeServices) GetUserTeamIDs(ctx context.Context, userID string) ([]string, error) {
+	f.count("GetUserTeamIDs")
+	return []string{"team-1"}, nil
+}
+
+func newTestAPI(t *testing.T) (*API, *fakeServices) {
+	t.Helper()
+	fake := newFakeServices()
+	api, err := New(Deps{Notebooks: fake, Documents: fake, Agents: fake, Spaces: fake, Teams: fake}, 6, nil)
+	require.NoError(t, err)
+	return api, fake
+}
+
+var testViewer = Viewer{
+	UserID:     "user-1",
+	KeycloakID: "kc-1",
+	SpaceContext: &models.SpaceContext{
+		SpaceType: models.SpaceTypePersonal,
+		SpaceID:   "space-personal",
+		UserID:    "user-1",
+		UserRole:  "owner",
+	},
+}
+
+func TestNestedDocumentsAreBatched(t *testing.T) {
+	api, fake := newTestAPI(t)
+
+	resp := api.Exec(context.Background(), testViewer, `{
+		notebooks(limit: 10) {
+			total
+			items {
+				id
+				parent { id }
+				documents(limit: 5) { total items { id notebook { name } } }
+			}
+		}
+	}`, "", nil)
+	require.Empty(t, resp.Errors)
+
+	var data struct {
+		Notebooks struct {
+			Total int
+			Items []struct {
+				ID        string
+				Parent    *struct{ ID string }
+				Documents struct {
+					Total int
+					Items []struct {
+						ID       string
+						Notebook struct{ Name string }
+					}
+				}
+			}
+		}
+	}
+	require.NoError(t, json.Unmarshal(resp.Data, &data))
+	require.Len(t, data.Notebooks.Items, 3)
+	assert.Equal(t, "nb-1", data.Notebooks.Items[1].Parent.ID)
+	assert.Equal(t, "doc-1", data.Notebooks.Items[0].Documents.Items[0].ID)
+	assert.Equal(t, "Research", data.Notebooks.Items[0].Documents.Items[0].Notebook.Name)
+	assert.Empty(t, data.Notebooks.Items[2].Documents.Items)
+
+	assert.Equal(t, 1, fake.callsTo("ListNotebooks"))
+	assert.Equal(t, 1, fake.callsTo("ListDocumentsByNotebooks"))
+	// Parents and the notebooks of documents share one batch per level
+	assert.LessOrEqual(t, fake.callsTo("GetNotebooksByIDs"), 2)
+}
+
+func TestSpaceScopedFieldsRequireSpaceContext(t *testing.T) {
+	api, fake := newTestAPI(t)
+
+	resp := api.Exec(context.Background(), Viewer{UserID: "user-1", KeycloakID: "kc-1"}, `{ notebooks { total } }`, "", nil)
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, "Space context is required", resp.Errors[0].Message)
+	assert.Equal(t, errors.CodeSpaceContextRequired, resp.Errors[0].Extensions["error_code"])
+
+	// Spaces are listed without one, and each is resolved on demand
+	resp = api.Exec(context.Background(), Viewer{UserID: "user-1", KeycloakID: "kc-1"}, `{ spaces { id organizationId notebooks { total } } }`, "", nil)
+	require.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"spaces":[
+		{"id":"space-personal","organizationId":null,"notebooks":{"total":3}},
+		{"id":"space-org","organizationId":"org-1","notebooks":{"total":3}}
+	]}`, string(resp.Data))
+	assert.Equal(t, 2, fake.callsTo("ResolveSpaceContext"))
+}
+
+func TestKnowledgeSourcesAreOwnerOnly(t *testing.T) {
+	api, fake := newTestAPI(t)
+
+	resp := api.Exec(context.Background(), testViewer, `{ agents { items { id knowledgeSources { id } } } }`, "", nil)
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, []interface{}{"agents", "items", 1, "knowledgeSources"}, resp.Errors[0].Path)
+	assert.Equal(t, errors.CodeForbidden, resp.Errors[0].Extensions["error_code"])
+
+	var data struct {
+		Agents struct {
+			Items []struct {
+				ID               string
+				KnowledgeSources []struct{ ID string }
+			}
+		}
+	}
+	require.NoError(t, json.Unmarshal(resp.Data, &data))
+	var ids []string
+	for _, source := range data.Agents.Items[0].KnowledgeSources {
+		ids = append(ids, source.ID)
+	}
+	// Sources keep their search weight order; notebooks outside the space are dropped
+	assert.Equal(t, []string{"nb-3", "nb-1"}, ids)
+	assert.Nil(t, data.Agents.Items[1].KnowledgeSources)
+	assert.Equal(t, 1, fake.callsTo("GetKnowledgeSourceIDs"))
+	assert.Equal(t, 1, fake.callsTo("GetUserTeamIDs"))
+}
+
+func TestResolverErrorsHideCauses(t *testing.T) {
+	api, _ := newTestAPI(t)
+
+	resp := api.Exec(context.Background(), testViewer, `{ document(id: "missing") { id } }`, "", nil)
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, "Document not found", resp.Errors[0].Message)
+	assert.Equal(t, errors.ErrNotFound, resp.Errors[0].Extensions["code"])
+}
+
+func TestQueriesDeeperThanMaxDepthAreRejected(t *testing.T) {
+	api, fake := newTestAPI(t)
+
+	resp := api.Exec(context.Background(), testViewer, `{
+		notebooks { items { parent { parent { parent { parent { parent { id } } } } } } }
+	}`, "", nil)
+	require.NotEmpty(t, resp.Errors)
+	assert.Contains(t, resp.Errors[0].Message, "exceeds max depth 6")
+	assert.Empty(t, fake.calls)
+}
@@ -0,0 +1,139 @@
+package gql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/graph-gophers/dataloader"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+// loaderWait is how long a loader collects keys before running its batch.
+// Resolvers of sibling fields run concurrently, so a short window is enough.
+const loaderWait = 2 * time.Millisecond
+
+// spaceLoaders batch the lookups made within one space
+type spaceLoaders struct {
+	notebooks *dataloader.Loader
+	documents *dataloader.Loader
+}
+
+func newSpaceLoaders(deps Deps, spaceCtx *models.SpaceContext) *spaceLoaders {
+	return &spaceLoaders{
+		notebooks: dataloader.NewBatchedLoader(func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+			notebooks, err := deps.Notebooks.GetNotebooksByIDs(ctx, keys.Keys(), spaceCtx)
+			results := make([]*dataloader.Result, len(keys))
+			for i, key := range keys {
+				if err != nil {
+					results[i] = &dataloader.Result{Error: err}
+					continue
+				}
+				// Notebooks outside the space are left out and resolve to null
+				results[i] = &dataloader.Result{Data: notebooks[key.String()]}
+			}
+			return results
+		}, dataloader.WithWait(loaderWait)),
+
+		documents: dataloader.NewBatchedLoader(func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+			// Pages of different sizes cannot share a query, so keys are
+			// batched per limit and offset
+			groups := make(map[documentPageKey][]string)
+			for _, key := range keys {
+				page := key.Raw().(documentPageKey)
+				group := documentPageKey{limit: page.limit, offset: page.offset}
+				groups[group] = append(groups[group], page.notebookID)
+			}
+
+			pages := make(map[documentPageKey]*models.DocumentListResponse, len(keys))
+			errs := make(map[documentPageKey]error)
+			for group, notebookIDs := range groups {
+				byNotebook, err := deps.Documents.ListDocumentsByNotebooks(ctx, notebookIDs, spaceCtx, group.limit, group.offset)
+				for _, notebookID := range notebookIDs {
+					key := documentPageKey{notebookID: notebookID, limit: group.limit, offset: group.offset}
+					if err != nil {
+						errs[key] = err
+						continue
+					}
+					pages[key] = byNotebook[notebookID]
+				}
+			}
+
+			results := make([]*dataloader.Result, len(keys))
+			for i, key := range keys {
+				page := key.Raw().(documentPageKey)
+				results[i] = &dataloader.Result{Data: pages[page], Error: errs[page]}
+			}
+			return results
+		}, dataloader.WithWait(loaderWait)),
+	}
+}
+
+// notebook loads a notebook of the space; nil when it is not in the space
+func (l *spaceLoaders) notebook(ctx context.Context, notebookID string) (*models.NotebookResponse, error) {
+	data, err := l.notebooks.Load(ctx, dataloader.StringKey(notebookID))()
+	if err != nil {
+		return nil, err
+	}
+	notebook, _ := data.(*models.NotebookResponse)
+	return notebook, nil
+}
+
+// documentPage loads one page of the documents of a notebook
+func (l *spaceLoaders) documentPage(ctx context.Context, notebookID string, limit, offset int) (*models.DocumentListResponse, error) {
+	data, err := l.documents.Load(ctx, documentPageKey{notebookID: notebookID, limit: limit, offset: offset})()
+	if err != nil {
+		return nil, err
+	}
+	page, _ := data.(*models.DocumentListResponse)
+	if page == nil {
+		page = &models.DocumentListResponse{Documents: []*models.DocumentResponse{}, Limit: limit, Offset: offset}
+	}
+	return page, nil
+}
+
+// documentPageKey identifies one page of the documents of a notebook
+type documentPageKey struct {
+	notebookID string
+	limit      int
+	offset     int
+}
+
+// String implements dataloader.Key
+func (k documentPageKey) String() string {
+	return fmt.Sprintf("%s:%d:%d", k.notebookID, k.limit, k.offset)
+}
+
+// Raw implements dataloader.Key
+func (k documentPageKey) Raw() interface{} {
+	return k
+}
+
+// knowledgeSourceLoader batches the lookups of the notebooks agents search
+type knowledgeSourceLoader struct {
+	loader *dataloader.Loader
+}
+
+func newKnowledgeSourceLoader(agents AgentService) *knowledgeSourceLoader {
+	return &knowledgeSourceLoader{
+		loader: dataloader.NewBatchedLoader(func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+			sources, err := agents.GetKnowledgeSourceIDs(ctx, keys.Keys())
+			results := make([]*dataloader.Result, len(keys))
+			for i, key := range keys {
+				results[i] = &dataloader.Result{Data: sources[key.String()], Error: err}
+			}
+			return results
+		}, dataloader.WithWait(loaderWait)),
+	}
+}
+
+// notebookIDs loads the IDs of the notebooks an agent searches
+func (l *knowledgeSourceLoader) notebookIDs(ctx context.Context, agentID string) ([]string, error) {
+	data, err := l.loader.Load(ctx, dataloader.StringKey(agentID))()
+	if err != nil {
+		return nil, err
+	}
+	ids, _ := data.([]string)
+	return ids, nil
+}
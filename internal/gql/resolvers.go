@@ -0,0 +1,375 @@
+package gql
+
+import (
+	"context"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// pageArgs are the arguments of paginated fields
+type pageArgs struct {
+	Limit  int32
+	Offset int32
+}
+
+func (a pageArgs) clamp() (int, int) {
+	return pagination.Clamp(int(a.Limit), int(a.Offset))
+}
+
+// idArgs are the arguments of fields that look up one node
+type idArgs struct {
+	ID graphql.ID
+}
+
+// rootResolver resolves the fields of Query
+type rootResolver struct{}
+
+func (r *rootResolver) Spaces(ctx context.Context) ([]*spaceResolver, error) {
+	req := requestFrom(ctx)
+	spaces, err := req.deps.Spaces.GetUserSpaces(ctx, req.viewer.KeycloakID)
+	if err != nil {
+		return nil, newResolverError(err)
+	}
+
+	resolvers := make([]*spaceResolver, 0, len(spaces.OrganizationSpaces)+1)
+	if spaces.PersonalSpace != nil {
+		resolvers = append(resolvers, &spaceResolver{info: spaces.PersonalSpace})
+	}
+	for _, info := range spaces.OrganizationSpaces {
+		resolvers = append(resolvers, &spaceResolver{info: info})
+	}
+	return resolvers, nil
+}
+
+func (r *rootResolver) Notebooks(ctx context.Context, args pageArgs) (*notebookPageResolver, error) {
+	spaceCtx, err := requestFrom(ctx).currentSpace()
+	if err != nil {
+		return nil, newResolverError(err)
+	}
+	return listNotebooks(ctx, spaceCtx, args)
+}
+
+func (r *rootResolver) Notebook(ctx context.Context, args idArgs) (*notebookResolver, error) {
+	req := requestFrom(ctx)
+	spaceCtx, err := req.currentSpace()
+	if err != nil {
+		return nil, newResolverError(err)
+	}
+	return loadNotebook(ctx, spaceCtx, string(args.ID))
+}
+
+func (r *rootResolver) Document(ctx context.Context, args idArgs) (*documentResolver, error) {
+	req := requestFrom(ctx)
+	spaceCtx, err := req.currentSpace()
+	if err != nil {
+		return nil, newResolverError(err)
+	}
+	document, err := req.deps.Documents.GetDocumentByID(ctx, string(args.ID), req.viewer.UserID, spaceCtx)
+	if err != nil {
+		return nil, newResolverError(err)
+	}
+	return &documentResolver{document: document.ToResponse(), space: spaceCtx}, nil
+}
+
+func (r *rootResolver) Agents(ctx context.Context, args pageArgs) (*agentPageResolver, error) {
+	spaceCtx, err := requestFrom(ctx).currentSpace()
+	if err != nil {
+		return nil, newResolverError(err)
+	}
+	return listAgents(ctx, spaceCtx, args)
+}
+
+func (r *rootResolver) Agent(ctx context.Context, args idArgs) (*agentResolver, error) {
+	req := requestFrom(ctx)
+	agent, err := req.deps.Agents.GetAgent(ctx, string(args.ID), req.viewer.UserID, req.userTeams(ctx))
+	if err != nil {
+		return nil, newResolverError(err)
+	}
+	// Agents are not bound to the selected space, which may be unset; it is
+	// only needed to resolve knowledge sources
+	return &agentResolver{agent: agent, space: req.viewer.SpaceContext}, nil
+}
+
+func listNotebooks(ctx context.Context, spaceCtx *models.SpaceContext, args pageArgs) (*notebookPageResolver, error) {
+	req := requestFrom(ctx)
+	limit, offset := args.clamp()
+	list, err := req.deps.Notebooks.ListNotebooks(ctx, req.viewer.UserID, spaceCtx, limit, offset)
+	if err != nil {
+		return nil, newResolverError(err)
+	}
+	return &notebookPageResolver{list: list, space: spaceCtx}, nil
+}
+
+func loadNotebook(ctx context.Context, spaceCtx *models.SpaceContext, notebookID string) (*notebookResolver, error) {
+	notebook, err := requestFrom(ctx).loadersFor(spaceCtx).notebook(ctx, notebookID)
+	if err != nil {
+		return nil, newResolverError(err)
+	}
+	if notebook == nil {
+		return nil, nil
+	}
+	return &notebookResolver{notebook: notebook, space: spaceCtx}, nil
+}
+
+func listAgents(ctx context.Context, spaceCtx *models.SpaceContext, args pageArgs) (*agentPageResolver, error) {
+	req := requestFrom(ctx)
+	limit, offset := args.clamp()
+	list, err := req.deps.Agents.ListAgents(ctx, models.AgentSearchRequest{
+		SpaceID:   spaceCtx.SpaceID,
+		SpaceType: spaceCtx.SpaceType,
+		Limit:     limit,
+		Offset:    offset,
+	}, req.viewer.UserID, req.userTeams(ctx), req.viewer.AuthToken)
+	if err != nil {
+		return nil, newResolverError(err)
+	}
+	return &agentPageResolver{list: list, space: spaceCtx}, nil
+}
+
+// spaceResolver resolves a space the viewer belongs to
+type spaceResolver struct {
+	info *models.SpaceInfo
+}
+
+func (r *spaceResolver) ID() graphql.ID        { return graphql.ID(r.info.SpaceID) }
+func (r *spaceResolver) Type() string          { return string(r.info.SpaceType) }
+func (r *spaceResolver) Name() string          { return r.info.SpaceName }
+func (r *spaceResolver) Role() string          { return r.info.UserRole }
+func (r *spaceResolver) Permissions() []string { return nonNil(r.info.Permissions) }
+
+func (r *spaceResolver) OrganizationID() *graphql.ID {
+	if r.info.OrganizationID == "" {
+		return nil
+	}
+	id := graphql.ID(r.info.OrganizationID)
+	return &id
+}
+
+func (r *spaceResolver) OrganizationName() *string {
+	return optional(r.info.OrganizationName)
+}
+
+func (r *spaceResolver) Notebooks(ctx context.Context, args pageArgs) (*notebookPageResolver, error) {
+	spaceCtx, err := requestFrom(ctx).spaceContext(ctx, r.info.SpaceType, r.info.SpaceID)
+	if err != nil {
+		return nil, newResolverError(err)
+	}
+	return listNotebooks(ctx, spaceCtx, args)
+}
+
+func (r *spaceResolver) Agents(ctx context.Context, args pageArgs) (*agentPageResolver, error) {
+	spaceCtx, err := requestFrom(ctx).spaceContext(ctx, r.info.SpaceType, r.info.SpaceID)
+	if err != nil {
+		return nil, newResolverError(err)
+	}
+	return listAgents(ctx, spaceCtx, args)
+}
+
+// userResolver resolves the public profile of a user
+type userResolver struct {
+	user *models.PublicUserResponse
+}
+
+func newUserResolver(user *models.PublicUserResponse) *userResolver {
+	if user == nil {
+		return nil
+	}
+	return &userResolver{user: user}
+}
+
+func (r *userResolver) ID() graphql.ID     { return graphql.ID(r.user.ID) }
+func (r *userResolver) Username() string   { return r.user.Username }
+func (r *userResolver) FullName() string   { return r.user.FullName }
+func (r *userResolver) AvatarURL() *string { return optional(r.user.AvatarURL) }
+
+// notebookResolver resolves a notebook read from a space
+type notebookResolver struct {
+	notebook *models.NotebookResponse
+	space    *models.SpaceContext
+}
+
+func (r *notebookResolver) ID() graphql.ID          { return graphql.ID(r.notebook.ID) }
+func (r *notebookResolver) Name() string            { return r.notebook.Name }
+func (r *notebookResolver) Description() *string    { return optional(r.notebook.Description) }
+func (r *notebookResolver) Visibility() string      { return r.notebook.Visibility }
+func (r *notebookResolver) Status() string          { return r.notebook.Status }
+func (r *notebookResolver) Tags() []string          { return nonNil(r.notebook.Tags) }
+func (r *notebookResolver) DocumentCount() int32    { return int32(r.notebook.DocumentCount) }
+func (r *notebookResolver) TotalSizeBytes() float64 { return float64(r.notebook.TotalSizeBytes) }
+func (r *notebookResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.notebook.CreatedAt} }
+func (r *notebookResolver) UpdatedAt() graphql.Time { return graphql.Time{Time: r.notebook.UpdatedAt} }
+func (r *notebookResolver) Owner() *userResolver    { return newUserResolver(r.notebook.Owner) }
+
+func (r *notebookResolver) Parent(ctx context.Context) (*notebookResolver, error) {
+	if r.notebook.ParentID == "" {
+		return nil, nil
+	}
+	return loadNotebook(ctx, r.space, r.notebook.ParentID)
+}
+
+func (r *notebookResolver) Documents(ctx context.Context, args pageArgs) (*documentPageResolver, error) {
+	limit, offset := args.clamp()
+	page, err := requestFrom(ctx).loadersFor(r.space).documentPage(ctx, r.notebook.ID, limit, offset)
+	if err != nil {
+		return nil, newResolverError(err)
+	}
+	return &documentPageResolver{list: page, space: r.space}, nil
+}
+
+// documentResolver resolves a document read from a space
+type documentResolver struct {
+	document *models.DocumentResponse
+	space    *models.SpaceContext
+}
+
+func (r *documentResolver) ID() graphql.ID             { return graphql.ID(r.document.ID) }
+func (r *documentResolver) Name() string               { return r.document.Name }
+func (r *documentResolver) Description() *string       { return optional(r.document.Description) }
+func (r *documentResolver) Type() string               { return r.document.Type }
+func (r *documentResolver) Status() string             { return r.document.Status }
+func (r *documentResolver) OriginalName() string       { return r.document.OriginalName }
+func (r *documentResolver) MimeType() string           { return r.document.MimeType }
+func (r *documentResolver) SizeBytes() float64         { return float64(r.document.SizeBytes) }
+func (r *documentResolver) Tags() []string             { return nonNil(r.document.Tags) }
+func (r *documentResolver) ChunkCount() int32          { return int32(r.document.ChunkCount) }
+func (r *documentResolver) ProcessedAt() *graphql.Time { return optionalTime(r.document.ProcessedAt) }
+func (r *documentResolver) CreatedAt() graphql.Time    { return graphql.Time{Time: r.document.CreatedAt} }
+func (r *documentResolver) UpdatedAt() graphql.Time    { return graphql.Time{Time: r.document.UpdatedAt} }
+func (r *documentResolver) Owner() *userResolver       { return newUserResolver(r.document.Owner) }
+
+func (r *documentResolver) Notebook(ctx context.Context) (*notebookResolver, error) {
+	return loadNotebook(ctx, r.space, r.document.NotebookID)
+}
+
+// agentResolver resolves an agent; space is where its knowledge sources are
+// read from
+type agentResolver struct {
+	agent *models.AgentResponse
+	space *models.SpaceContext
+}
+
+func (r *agentResolver) ID() graphql.ID                { return graphql.ID(r.agent.ID) }
+func (r *agentResolver) Name() string                  { return r.agent.Name }
+func (r *agentResolver) Description() *string          { return optional(r.agent.Description) }
+func (r *agentResolver) Status() string                { return string(r.agent.Status) }
+func (r *agentResolver) Type() string                  { return string(r.agent.Type) }
+func (r *agentResolver) IsPublic() bool                { return r.agent.IsPublic }
+func (r *agentResolver) IsTemplate() bool              { return r.agent.IsTemplate }
+func (r *agentResolver) Tags() []string                { return nonNil(r.agent.Tags) }
+func (r *agentResolver) TotalExecutions() int32        { return int32(r.agent.TotalExecutions) }
+func (r *agentResolver) LastExecutedAt() *graphql.Time { return optionalTime(r.agent.LastExecutedAt) }
+func (r *agentResolver) CreatedAt() graphql.Time       { return graphql.Time{Time: r.agent.CreatedAt} }
+func (r *agentResolver) UpdatedAt() graphql.Time       { return graphql.Time{Time: r.agent.UpdatedAt} }
+
+// KnowledgeSources returns the notebooks the agent searches that are in the
+// space. As in the REST API, only the agent's owner may see them.
+func (r *agentResolver) KnowledgeSources(ctx context.Context) (*[]*notebookResolver, error) {
+	req := requestFrom(ctx)
+	if r.agent.OwnerID != req.viewer.UserID {
+		return nil, newResolverError(errors.Forbidden("Insufficient permissions to view agent knowledge sources"))
+	}
+	if r.space == nil {
+		return nil, newResolverError(errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+	}
+
+	notebookIDs, err := req.sources.notebookIDs(ctx, r.agent.ID)
+	if err != nil {
+		return nil, newResolverError(err)
+	}
+	notebooks := make([]*notebookResolver, 0, len(notebookIDs))
+	for _, notebookID := range notebookIDs {
+		notebook, err := loadNotebook(ctx, r.space, notebookID)
+		if err != nil {
+			return nil, err
+		}
+		if notebook != nil {
+			notebooks = append(notebooks, notebook)
+		}
+	}
+	return &notebooks, nil
+}
+
+// notebookPageResolver resolves a page of notebooks
+type notebookPageResolver struct {
+	list  *models.NotebookListResponse
+	space *models.SpaceContext
+}
+
+func (r *notebookPageResolver) Items() []*notebookResolver {
+	items := make([]*notebookResolver, 0, len(r.list.Notebooks))
+	for _, notebook := range r.list.Notebooks {
+		items = append(items, &notebookResolver{notebook: notebook, space: r.space})
+	}
+	return items
+}
+
+func (r *notebookPageResolver) Total() int32  { return int32(r.list.Total) }
+func (r *notebookPageResolver) Limit() int32  { return int32(r.list.Limit) }
+func (r *notebookPageResolver) Offset() int32 { return int32(r.list.Offset) }
+func (r *notebookPageResolver) HasMore() bool { return r.list.HasMore }
+
+// documentPageResolver resolves a page of documents
+type documentPageResolver struct {
+	list  *models.DocumentListResponse
+	space *models.SpaceContext
+}
+
+func (r *documentPageResolver) Items() []*documentResolver {
+	items := make([]*documentResolver, 0, len(r.list.Documents))
+	for _, document := range r.list.Documents {
+		items = append(items, &documentResolver{document: document, space: r.space})
+	}
+	return items
+}
+
+func (r *documentPageResolver) Total() int32  { return int32(r.list.Total) }
+func (r *documentPageResolver) Limit() int32  { return int32(r.list.Limit) }
+func (r *documentPageResolver) Offset() int32 { return int32(r.list.Offset) }
+func (r *documentPageResolver) HasMore() bool { return r.list.HasMore }
+
+// agentPageResolver resolves a page of agents
+type agentPageResolver struct {
+	list  *models.AgentListResponse
+	space *models.SpaceContext
+}
+
+func (r *agentPageResolver) Items() []*agentResolver {
+	items := make([]*agentResolver, 0, len(r.list.Agents))
+	for _, agent := range r.list.Agents {
+		items = append(items, &agentResolver{agent: agent, space: r.space})
+	}
+	return items
+}
+
+func (r *agentPageResolver) Total() int32  { return int32(r.list.Total) }
+func (r *agentPageResolver) Limit() int32  { return int32(r.list.Limit) }
+func (r *agentPageResolver) Offset() int32 { return int32(r.list.Offset) }
+func (r *agentPageResolver) HasMore() bool { return r.list.HasMore }
+
+// optional maps an empty string to null
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func optionalTime(t *time.Time) *graphql.Time {
+	if t == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *t}
+}
+
+// nonNil maps a nil list to an empty one, as the schema lists are non-null
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
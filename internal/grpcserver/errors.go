@@ -0,0 +1,52 @@
+package grpcserver
+
+import (
+	"net/http"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// errorDomain is the domain of the ErrorInfo attached to failed calls
+const errorDomain = "aether-be"
+
+// statusCodes maps the HTTP status of API errors to gRPC codes
+var statusCodes = map[int]codes.Code{
+	http.StatusBadRequest:            codes.InvalidArgument,
+	http.StatusUnauthorized:          codes.Unauthenticated,
+	http.StatusForbidden:             codes.PermissionDenied,
+	http.StatusNotFound:              codes.NotFound,
+	http.StatusConflict:              codes.AlreadyExists,
+	http.StatusPreconditionFailed:    codes.FailedPrecondition,
+	http.StatusRequestEntityTooLarge: codes.InvalidArgument,
+	http.StatusUnprocessableEntity:   codes.InvalidArgument,
+	http.StatusTooManyRequests:       codes.ResourceExhausted,
+	http.StatusNotImplemented:        codes.Unimplemented,
+	http.StatusServiceUnavailable:    codes.Unavailable,
+	http.StatusGatewayTimeout:        codes.DeadlineExceeded,
+}
+
+// toStatus converts an error to a gRPC status error. Like the REST API it
+// exposes only the client-facing message; the error code is attached as
+// an ErrorInfo whose reason is the catalogue code, e.g. AETHER-DOC-001.
+func toStatus(err error) error {
+	apiErr := errors.Normalize(err)
+	code, ok := statusCodes[apiErr.StatusCode]
+	if !ok {
+		code = codes.Internal
+	}
+
+	st := status.New(code, apiErr.Message)
+	info := &errdetails.ErrorInfo{
+		Reason:   apiErr.ErrorCode,
+		Domain:   errorDomain,
+		Metadata: map[string]string{"code": apiErr.Code},
+	}
+	if detailed, err := st.WithDetails(info); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
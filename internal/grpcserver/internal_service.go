@@ -0,0 +1,167 @@
+package grpcserver
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+	internalapiv1 "github.com/Tributary-ai-services/aether-be/pkg/internalapi/v1"
+)
+
+// DocumentService records the processing outcome of documents
+type DocumentService interface {
+	UpdateProcessingResultForTenant(ctx context.Context, documentID string, tenantID string, status string, result map[string]interface{}, errorMsg string) error
+}
+
+// TenantResolver looks up tenants
+type TenantResolver interface {
+	ResolveTenant(ctx context.Context, key models.TenantLookupKey, value string) (*models.TenantInfo, error)
+}
+
+// Deps are the services the internal API calls
+type Deps struct {
+	Documents DocumentService
+	Events    services.DomainEventPublisher
+	Tenants   TenantResolver
+}
+
+// eventTypePattern is the shape of event types: dotted lowercase words
+// such as "agent_run.completed"
+var eventTypePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*(\.[a-z][a-z0-9_]*)+$`)
+
+// documentStatuses maps the status enum to the document statuses
+var documentStatuses = map[internalapiv1.DocumentStatus]string{
+	internalapiv1.DocumentStatus_DOCUMENT_STATUS_PROCESSING: "processing",
+	internalapiv1.DocumentStatus_DOCUMENT_STATUS_PROCESSED:  "processed",
+	internalapiv1.DocumentStatus_DOCUMENT_STATUS_FAILED:     "failed",
+}
+
+// internalService implements the InternalService of pkg/internalapi/v1
+type internalService struct {
+	internalapiv1.UnimplementedInternalServiceServer
+
+	deps   Deps
+	logger *logger.Logger
+}
+
+func newInternalService(deps Deps, log *logger.Logger) *internalService {
+	return &internalService{deps: deps, logger: log}
+}
+
+// UpdateDocumentStatus records the processing outcome of a document
+func (s *internalService) UpdateDocumentStatus(ctx context.Context, req *internalapiv1.UpdateDocumentStatusRequest) (*internalapiv1.UpdateDocumentStatusResponse, error) {
+	if req.GetDocumentId() == "" || req.GetTenantId() == "" {
+		return nil, toStatus(errors.Validation("document_id and tenant_id are required", nil))
+	}
+	documentStatus, ok := documentStatuses[req.GetStatus()]
+	if !ok {
+		return nil, toStatus(errors.ValidationWithDetails("A document status is required", map[string]interface{}{
+			"field": "status",
+		}))
+	}
+
+	var result map[string]interface{}
+	if req.GetResult() != nil {
+		result = req.GetResult().AsMap()
+	}
+	errorMsg := ""
+	if documentStatus == "failed" {
+		errorMsg = req.GetErrorMessage()
+	}
+
+	if err := s.deps.Documents.UpdateProcessingResultForTenant(ctx, req.GetDocumentId(), req.GetTenantId(), documentStatus, result, errorMsg); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &internalapiv1.UpdateDocumentStatusResponse{
+		DocumentId: req.GetDocumentId(),
+		Status:     req.GetStatus(),
+	}, nil
+}
+
+// PublishEvent publishes a domain event on behalf of a sibling service
+func (s *internalService) PublishEvent(ctx context.Context, req *internalapiv1.PublishEventRequest) (*internalapiv1.PublishEventResponse, error) {
+	eventType := services.EventType(req.GetType())
+	if !eventTypePattern.MatchString(req.GetType()) {
+		return nil, toStatus(errors.ValidationWithDetails("Event type must be dotted lowercase words, e.g. agent_run.completed", map[string]interface{}{
+			"type": req.GetType(),
+		}))
+	}
+	if services.IsAetherEventType(eventType) {
+		return nil, toStatus(errors.ValidationWithDetails("Event type is published by aether only", map[string]interface{}{
+			"type": req.GetType(),
+		}))
+	}
+	if req.GetSource() == "" {
+		return nil, toStatus(errors.Validation("source is required", nil))
+	}
+
+	data := map[string]interface{}{}
+	if req.GetData() != nil {
+		data = req.GetData().AsMap()
+	}
+	// Subscribers such as the audit log read the tenant from the data
+	if req.GetTenantId() != "" {
+		data["tenant_id"] = req.GetTenantId()
+	}
+
+	event := services.Event{
+		ID:      uuid.New().String(),
+		Type:    eventType,
+		Source:  req.GetSource(),
+		Subject: req.GetSubject(),
+		Data:    data,
+		UserID:  req.GetUserId(),
+	}
+	if err := s.deps.Events.PublishEvent(ctx, event); err != nil {
+		s.logger.FromContext(ctx).Warn("Failed to publish event for sibling service",
+			zap.String("event_type", req.GetType()),
+			zap.String("source", req.GetSource()),
+			zap.Error(err),
+		)
+		return nil, toStatus(errors.ServiceUnavailable("Failed to publish event"))
+	}
+
+	return &internalapiv1.PublishEventResponse{EventId: event.ID}, nil
+}
+
+// ResolveTenant looks up a tenant by one of its identifiers
+func (s *internalService) ResolveTenant(ctx context.Context, req *internalapiv1.ResolveTenantRequest) (*internalapiv1.ResolveTenantResponse, error) {
+	var key models.TenantLookupKey
+	var value string
+	switch k := req.GetKey().(type) {
+	case *internalapiv1.ResolveTenantRequest_TenantId:
+		key, value = models.TenantLookupByTenantID, k.TenantId
+	case *internalapiv1.ResolveTenantRequest_SpaceId:
+		key, value = models.TenantLookupBySpaceID, k.SpaceId
+	case *internalapiv1.ResolveTenantRequest_AudimodalTenantId:
+		key, value = models.TenantLookupByAudimodalTenantID, k.AudimodalTenantId
+	}
+	if value == "" {
+		return nil, toStatus(errors.Validation("One of tenant_id, space_id or audimodal_tenant_id is required", nil))
+	}
+
+	tenant, err := s.deps.Tenants.ResolveTenant(ctx, key, value)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &internalapiv1.ResolveTenantResponse{
+		Tenant: &internalapiv1.Tenant{
+			TenantId:          tenant.TenantID,
+			SpaceId:           tenant.SpaceID,
+			SpaceType:         string(tenant.SpaceType),
+			Name:              tenant.Name,
+			OwnerId:           tenant.OwnerID,
+			AudimodalTenantId: tenant.AudimodalTenantID,
+			DeeplakeNamespace: tenant.DeeplakeNamespace,
+			Status:            string(tenant.Status),
+		},
+	}, nil
+}
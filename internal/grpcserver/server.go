@@ -0,0 +1,163 @@
+// Package grpcserver serves the internal gRPC API defined in
+// pkg/internalapi/v1. Sibling services such as the AudiModal bridge and
+// agent-builder call it for document status updates, event publication and
+// tenant resolution instead of posting JSON to the REST API. It listens on
+// its own port, apart from the public API, and every call must carry the
+// shared GRPC_AUTH_TOKEN.
+package grpcserver
+
+import (
+	"context"
+	"crypto/subtle"
+	"net"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	internalapiv1 "github.com/Tributary-ai-services/aether-be/pkg/internalapi/v1"
+)
+
+// requestIDKey is the metadata key carrying the request ID, matching the
+// X-Request-ID header of the REST API
+const requestIDKey = "x-request-id"
+
+// Server is the internal gRPC server
+type Server struct {
+	server *grpc.Server
+	health *health.Server
+	logger *logger.Logger
+}
+
+// New creates the server and registers the internal service and the
+// standard health service. Only the health service answers without the
+// auth token, so orchestrators can probe it.
+func New(cfg config.GRPCConfig, deps Deps, log *logger.Logger) *Server {
+	log = log.WithService("grpc_server")
+	s := &Server{
+		health: health.NewServer(),
+		logger: log,
+	}
+	s.server = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			requestIDInterceptor,
+			recoveryInterceptor(log),
+			loggingInterceptor(log),
+			authInterceptor(cfg.AuthToken),
+		),
+	)
+	internalapiv1.RegisterInternalServiceServer(s.server, newInternalService(deps, log))
+	healthpb.RegisterHealthServer(s.server, s.health)
+	s.health.SetServingStatus(internalapiv1.InternalService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	return s
+}
+
+// Serve accepts connections on the listener until the server stops
+func (s *Server) Serve(lis net.Listener) error {
+	s.logger.Info("gRPC server listening", zap.String("address", lis.Addr().String()))
+	return s.server.Serve(lis)
+}
+
+// Shutdown stops accepting calls and waits for those in flight. If ctx
+// ends first the remaining calls are cancelled.
+func (s *Server) Shutdown(ctx context.Context) {
+	s.health.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.logger.Warn("gRPC graceful stop timed out, cancelling remaining calls")
+		s.server.Stop()
+		<-stopped
+	}
+}
+
+// requestIDInterceptor puts the caller's request ID, or a new one, on the
+// context so logs across services can be correlated, and returns it in the
+// response header
+func requestIDInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(requestIDKey); len(ids) > 0 && ids[0] != "" {
+			ctx = logger.ContextWithRequestID(ctx, ids[0])
+		}
+	}
+	ctx, requestID := logger.EnsureRequestID(ctx)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, requestID))
+	return handler(ctx, req)
+}
+
+// recoveryInterceptor turns a panicking handler into an Internal error
+func recoveryInterceptor(log *logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.FromContext(ctx).Error("gRPC handler panicked",
+					zap.String("method", info.FullMethod),
+					zap.Any("panic", r),
+				)
+				err = status.Error(codes.Internal, "Internal server error")
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// loggingInterceptor logs each call with its outcome
+func loggingInterceptor(log *logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		code := status.Code(err)
+		fields := []zap.Field{
+			zap.String("method", info.FullMethod),
+			zap.String("code", code.String()),
+			zap.Duration("duration", time.Since(start)),
+		}
+		switch code {
+		case codes.OK:
+			log.FromContext(ctx).Info("gRPC call", fields...)
+		case codes.Internal, codes.Unknown, codes.DataLoss:
+			log.FromContext(ctx).Error("gRPC call failed", append(fields, zap.Error(err))...)
+		default:
+			log.FromContext(ctx).Warn("gRPC call failed", append(fields, zap.Error(err))...)
+		}
+		return resp, err
+	}
+}
+
+// authInterceptor requires the shared token as a bearer token in the
+// authorization metadata. Health checks are exempt.
+func authInterceptor(token string) grpc.UnaryServerInterceptor {
+	expected := []byte("Bearer " + token)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if strings.HasPrefix(info.FullMethod, "/"+healthpb.Health_ServiceDesc.ServiceName+"/") {
+			return handler(ctx, req)
+		}
+
+		var provided string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get("authorization"); len(values) > 0 {
+				provided = values[0]
+			}
+		}
+		if token == "" || subtle.ConstantTimeCompare([]byte(provided), expected) != 1 {
+			return nil, status.Error(codes.Unauthenticated, "Invalid or missing auth token")
+		}
+		return handler(ctx, req)
+	}
+}
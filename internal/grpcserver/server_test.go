@@ -0,0 +1,194 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+	internalapiv1 "github.com/Tributary-ai-services/aether-be/pkg/internalapi/v1"
+)
+
+const testToken = "secret-token"
+
+type statusUpdate struct {
+	documentID, tenantID, status, errorMsg string
+	result                                 map[string]interface{}
+}
+
+type fakeDocuments struct {
+	updates []statusUpdate
+}
+
+func (f *fakeDocuments) UpdateProcessingResultForTenant(ctx context.Context, documentID string, tenantID string, status string, result map[string]interface{}, errorMsg string) error {
+	if tenantID != "tenant_1" {
+		return errors.NotFound("Document not found").WithErrorCode(errors.CodeDocumentNotFound)
+	}
+	f.updates = append(f.updates, statusUpdate{documentID, tenantID, status, errorMsg, result})
+	return nil
+}
+
+type fakeEvents struct {
+	events []services.Event
+}
+
+func (f *fakeEvents) PublishEvent(ctx context.Context, event services.Event) error {
+	f.events = append(f.events, event)
+	return nil
+}
+
+type fakeTenants struct{}
+
+func (fakeTenants) ResolveTenant(ctx context.Context, key models.TenantLookupKey, value string) (*models.TenantInfo, error) {
+	if key != models.TenantLookupByAudimodalTenantID || value != "9f1c" {
+		return nil, errors.NotFound("Tenant not found")
+	}
+	return &models.TenantInfo{TenantID: "tenant_9f1c", SpaceID: "space_9f1c", SpaceType: models.SpaceTypePersonal, AudimodalTenantID: "9f1c", Status: models.SpaceStatusActive}, nil
+}
+
+func newTestClient(t *testing.T) (internalapiv1.InternalServiceClient, *grpc.ClientConn, *fakeDocuments, *fakeEvents) {
+	t.Helper()
+	log, err := logger.NewDefault()
+	require.NoError(t, err)
+
+	documents, events := &fakeDocuments{}, &fakeEvents{}
+	server := New(config.GRPCConfig{AuthToken: testToken}, Deps{Documents: documents, Events: events, Tenants: fakeTenants{}}, log)
+	lis := bufconn.Listen(1 << 20)
+	go server.Serve(lis)
+	t.Cleanup(func() { server.Shutdown(context.Background()) })
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return internalapiv1.NewInternalServiceClient(conn), conn, documents, events
+}
+
+func authed() context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+testToken)
+}
+
+func TestCallsRequireTheAuthToken(t *testing.T) {
+	client, conn, _, _ := newTestClient(t)
+
+	_, err := client.ResolveTenant(context.Background(), &internalapiv1.ResolveTenantRequest{
+		Key: &internalapiv1.ResolveTenantRequest_TenantId{TenantId: "tenant_1"},
+	})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer wrong")
+	_, err = client.ResolveTenant(ctx, &internalapiv1.ResolveTenantRequest{
+		Key: &internalapiv1.ResolveTenantRequest_TenantId{TenantId: "tenant_1"},
+	})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// Health checks are answered without it
+	resp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+}
+
+func TestUpdateDocumentStatus(t *testing.T) {
+	client, _, documents, _ := newTestClient(t)
+
+	result, err := structpb.NewStruct(map[string]interface{}{"chunks_created": 4})
+	require.NoError(t, err)
+	var header metadata.MD
+	ctx := metadata.AppendToOutgoingContext(authed(), "x-request-id", "req-42")
+	resp, err := client.UpdateDocumentStatus(ctx, &internalapiv1.UpdateDocumentStatusRequest{
+		DocumentId:   "doc-1",
+		TenantId:     "tenant_1",
+		Status:       internalapiv1.DocumentStatus_DOCUMENT_STATUS_PROCESSED,
+		Result:       result,
+		ErrorMessage: "ignored unless failed",
+	}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.Equal(t, internalapiv1.DocumentStatus_DOCUMENT_STATUS_PROCESSED, resp.Status)
+	assert.Equal(t, []string{"req-42"}, header.Get("x-request-id"))
+	require.Len(t, documents.updates, 1)
+	assert.Equal(t, statusUpdate{"doc-1", "tenant_1", "processed", "", map[string]interface{}{"chunks_created": float64(4)}}, documents.updates[0])
+
+	// A status must be given
+	_, err = client.UpdateDocumentStatus(authed(), &internalapiv1.UpdateDocumentStatusRequest{DocumentId: "doc-1", TenantId: "tenant_1"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// Documents of other tenants are not found, with the catalogue code attached
+	_, err = client.UpdateDocumentStatus(authed(), &internalapiv1.UpdateDocumentStatusRequest{
+		DocumentId: "doc-1",
+		TenantId:   "tenant_2",
+		Status:     internalapiv1.DocumentStatus_DOCUMENT_STATUS_FAILED,
+	})
+	st := status.Convert(err)
+	assert.Equal(t, codes.NotFound, st.Code())
+	assert.Equal(t, "Document not found", st.Message())
+	require.Len(t, st.Details(), 1)
+	assert.Equal(t, errors.CodeDocumentNotFound, st.Details()[0].(*errdetails.ErrorInfo).Reason)
+}
+
+func TestPublishEvent(t *testing.T) {
+	client, _, _, events := newTestClient(t)
+
+	data, err := structpb.NewStruct(map[string]interface{}{"agent_id": "agent-1"})
+	require.NoError(t, err)
+	resp, err := client.PublishEvent(authed(), &internalapiv1.PublishEventRequest{
+		Type:     "agent_run.completed",
+		Source:   "agent-builder",
+		Subject:  "run-1",
+		TenantId: "tenant_1",
+		UserId:   "user-1",
+		Data:     data,
+	})
+	require.NoError(t, err)
+	require.Len(t, events.events, 1)
+	event := events.events[0]
+	assert.Equal(t, resp.EventId, event.ID)
+	assert.Equal(t, services.EventType("agent_run.completed"), event.Type)
+	assert.Equal(t, "agent-builder", event.Source)
+	assert.Equal(t, map[string]interface{}{"agent_id": "agent-1", "tenant_id": "tenant_1"}, event.Data)
+
+	// Aether's own event types cannot be published by other services
+	_, err = client.PublishEvent(authed(), &internalapiv1.PublishEventRequest{Type: string(services.EventDocumentProcessed), Source: "audimodal"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.PublishEvent(authed(), &internalapiv1.PublishEventRequest{Type: "Not An Event", Source: "audimodal"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Len(t, events.events, 1)
+}
+
+func TestResolveTenant(t *testing.T) {
+	client, _, _, _ := newTestClient(t)
+
+	resp, err := client.ResolveTenant(authed(), &internalapiv1.ResolveTenantRequest{
+		Key: &internalapiv1.ResolveTenantRequest_AudimodalTenantId{AudimodalTenantId: "9f1c"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "tenant_9f1c", resp.Tenant.TenantId)
+	assert.Equal(t, "space_9f1c", resp.Tenant.SpaceId)
+	assert.Equal(t, "personal", resp.Tenant.SpaceType)
+
+	_, err = client.ResolveTenant(authed(), &internalapiv1.ResolveTenantRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.ResolveTenant(authed(), &internalapiv1.ResolveTenantRequest{
+		Key: &internalapiv1.ResolveTenantRequest_SpaceId{SpaceId: "space_missing"},
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
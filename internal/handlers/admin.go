@@ -0,0 +1,1203 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
+	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// AdminHandler handles operator-only administration requests
+type AdminHandler struct {
+	runtimeConfig *services.RuntimeConfigService
+	scheduler     *services.Scheduler
+	reporting     *services.ReportingProjector
+	slo           *services.SLOMonitor
+	anomalies     *services.AnomalyMonitor
+	synthetic     *services.SyntheticProber
+	spaces        *services.SpaceService
+	documents     *services.DocumentService
+	deletions     *services.DeletionOrchestrator
+	organizations *services.OrganizationService
+	users         *services.UserService
+	tenantExports *services.TenantExportService
+	jobs          *services.JobService
+	maintenance   *services.MaintenanceService
+	quotas        *services.QuotaService
+	regions       *services.RegionService
+	encryption    *services.TextEncryptionService
+	logger        *logger.Logger
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(runtimeConfig *services.RuntimeConfigService, scheduler *services.Scheduler, log *logger.Logger) *AdminHandler {
+	return &AdminHandler{
+		runtimeConfig: runtimeConfig,
+		scheduler:     scheduler,
+		logger:        log.WithService("admin_handler"),
+	}
+}
+
+// SetReportingProjector enables the reporting endpoints; without it they
+// respond 503
+func (h *AdminHandler) SetReportingProjector(reporting *services.ReportingProjector) {
+	h.reporting = reporting
+}
+
+// SetSLOMonitor enables the SLO endpoint; without it it responds 503
+func (h *AdminHandler) SetSLOMonitor(slo *services.SLOMonitor) {
+	h.slo = slo
+}
+
+// SetAnomalyMonitor enables the usage anomaly endpoint; without it it
+// responds 503
+func (h *AdminHandler) SetAnomalyMonitor(anomalies *services.AnomalyMonitor) {
+	h.anomalies = anomalies
+}
+
+// SetSyntheticProber enables the synthetic monitoring endpoint; without it
+// it responds 503
+func (h *AdminHandler) SetSyntheticProber(synthetic *services.SyntheticProber) {
+	h.synthetic = synthetic
+}
+
+// SetSpaceService enables the graph consistency check; without it it
+// responds 503
+func (h *AdminHandler) SetSpaceService(spaces *services.SpaceService) {
+	h.spaces = spaces
+}
+
+// SetDocumentService enables the dead letter and reprocessing endpoints;
+// without it they respond 503
+func (h *AdminHandler) SetDocumentService(documents *services.DocumentService) {
+	h.documents = documents
+}
+
+// SetDeletionOrchestrator enables the document deletion endpoints;
+// without it they respond 503
+func (h *AdminHandler) SetDeletionOrchestrator(deletions *services.DeletionOrchestrator) {
+	h.deletions = deletions
+}
+
+// SetTenantServices enables tenant provisioning; without them it responds
+// 503
+func (h *AdminHandler) SetTenantServices(organizations *services.OrganizationService, users *services.UserService) {
+	h.organizations = organizations
+	h.users = users
+}
+
+// SetTenantExports enables tenant exports, run as jobs; without them the
+// endpoint responds 503
+func (h *AdminHandler) SetTenantExports(exports *services.TenantExportService, jobs *services.JobService) {
+	h.tenantExports = exports
+	h.jobs = jobs
+}
+
+// SetMaintenanceService enables maintenance mode; without it the
+// maintenance endpoints respond 503
+func (h *AdminHandler) SetMaintenanceService(maintenance *services.MaintenanceService) {
+	h.maintenance = maintenance
+}
+
+// SetQuotaService enables changing space quotas; without it the quota
+// endpoint responds 503
+func (h *AdminHandler) SetQuotaService(quotas *services.QuotaService) {
+	h.quotas = quotas
+}
+
+// SetRegionService enables tenant region pins and failover; without it the
+// region endpoints respond 503
+func (h *AdminHandler) SetRegionService(regions *services.RegionService) {
+	h.regions = regions
+}
+
+// SetTextEncryption enables encrypting the text of spaces at rest; without
+// it the encryption endpoint responds 503
+func (h *AdminHandler) SetTextEncryption(encryption *services.TextEncryptionService) {
+	h.encryption = encryption
+}
+
+// RuntimeConfigResponse represents the runtime configuration and the changes
+// made by the request, if any
+type RuntimeConfigResponse struct {
+	Config  config.RuntimeConfig   `json:"config"`
+	Changes []config.RuntimeChange `json:"changes,omitempty"`
+}
+
+// GetRuntimeConfig returns the active runtime configuration
+// @Summary Get runtime configuration
+// @Description Get the configuration that can be changed without a restart
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} RuntimeConfigResponse
+// @Router /api/v1/admin/config [get]
+func (h *AdminHandler) GetRuntimeConfig(c *gin.Context) {
+	c.JSON(http.StatusOK, RuntimeConfigResponse{Config: h.runtimeConfig.Current()})
+}
+
+// FeatureFlagsResponse lists the current feature flags
+type FeatureFlagsResponse struct {
+	FeatureFlags map[string]bool `json:"feature_flags"`
+}
+
+// GetFeatureFlags returns the current feature flags. Unlike the rest of the
+// runtime configuration it is available to every authenticated user so
+// clients can toggle features without a redeploy.
+// @Summary Get feature flags
+// @Description Get the feature flags of the runtime configuration
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} FeatureFlagsResponse
+// @Router /api/v1/features [get]
+func (h *AdminHandler) GetFeatureFlags(c *gin.Context) {
+	c.JSON(http.StatusOK, FeatureFlagsResponse{FeatureFlags: h.runtimeConfig.Current().FeatureFlags})
+}
+
+// UpdateRuntimeConfig applies a partial runtime configuration update.
+// Omitted fields keep their current values; feature flags are merged.
+// @Summary Update runtime configuration
+// @Description Change log level, rate limits, feature flags or AudiModal timeouts without a restart
+// @Tags admin
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param config body config.RuntimeConfig true "Runtime configuration fields to change"
+// @Success 200 {object} RuntimeConfigResponse
+// @Failure 400 {object} errors.APIError
+// @Router /api/v1/admin/config [patch]
+func (h *AdminHandler) UpdateRuntimeConfig(c *gin.Context) {
+	next := h.runtimeConfig.Current()
+	if err := c.ShouldBindJSON(&next); err != nil {
+		h.logger.Warn("Invalid runtime config update", zap.Error(err))
+		c.JSON(http.StatusBadRequest, errors.ValidationWithDetails("Invalid request data", map[string]interface{}{
+			"error": err.Error(),
+		}))
+		return
+	}
+
+	changes, err := h.runtimeConfig.Update(c.Request.Context(), next, getUserID(c))
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, RuntimeConfigResponse{
+		Config:  h.runtimeConfig.Current(),
+		Changes: changes,
+	})
+}
+
+// ReloadRuntimeConfig re-reads the runtime configuration, like SIGHUP
+// @Summary Reload runtime configuration
+// @Description Re-read runtime configuration from the environment file
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} RuntimeConfigResponse
+// @Failure 400 {object} errors.APIError
+// @Router /api/v1/admin/config/reload [post]
+func (h *AdminHandler) ReloadRuntimeConfig(c *gin.Context) {
+	changes, err := h.runtimeConfig.Reload(c.Request.Context(), getUserID(c), services.ConfigSourceAdmin)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, RuntimeConfigResponse{
+		Config:  h.runtimeConfig.Current(),
+		Changes: changes,
+	})
+}
+
+// ListScheduledJobs lists the background jobs and their schedules
+// @Summary List scheduled jobs
+// @Description List background jobs with their schedule, next run and last outcome
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} models.ScheduledJobListResponse
+// @Router /api/v1/admin/jobs [get]
+func (h *AdminHandler) ListScheduledJobs(c *gin.Context) {
+	jobs, err := h.scheduler.Jobs(c.Request.Context())
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ScheduledJobListResponse{Jobs: jobs})
+}
+
+// ListJobRuns lists the recent runs of a background job
+// @Summary List job runs
+// @Description List the most recent runs of a scheduled job, newest first
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Param name path string true "Job name"
+// @Param limit query int false "Number of runs to return" default(20)
+// @Success 200 {object} models.JobRunListResponse
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/admin/jobs/{name}/runs [get]
+func (h *AdminHandler) ListJobRuns(c *gin.Context) {
+	params := parsePaginationParams(c, pagination.DefaultLimit)
+
+	runs, err := h.scheduler.JobRuns(c.Request.Context(), c.Param("name"), params.Limit)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.JobRunListResponse{Runs: runs})
+}
+
+// GetReportingStatus reports the state of the PostgreSQL reporting projection
+// @Summary Get reporting projection status
+// @Description Get the number of logged domain events and projected rows
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} models.ReportingStatus
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/admin/reporting [get]
+func (h *AdminHandler) GetReportingStatus(c *gin.Context) {
+	if h.reporting == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Reporting projection is not enabled"))
+		return
+	}
+
+	status, err := h.reporting.Status(c.Request.Context())
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// RebuildReporting rebuilds the reporting read models from the event log
+// @Summary Rebuild reporting read models
+// @Description Truncate the reporting tables and replay every logged domain event into them
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} models.ReportingRebuildResponse
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/admin/reporting/rebuild [post]
+func (h *AdminHandler) RebuildReporting(c *gin.Context) {
+	if h.reporting == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Reporting projection is not enabled"))
+		return
+	}
+
+	start := time.Now()
+	replayed, err := h.reporting.Rebuild(c.Request.Context())
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	h.logger.Info("Reporting read models rebuilt by admin",
+		zap.String("user_id", getUserID(c)),
+		zap.Int("events_replayed", replayed),
+	)
+
+	c.JSON(http.StatusOK, models.ReportingRebuildResponse{
+		EventsReplayed: replayed,
+		DurationMs:     time.Since(start).Milliseconds(),
+	})
+}
+
+// GetSLOReport reports compliance with the service level objectives
+// @Summary Get SLO compliance
+// @Description Get the SLIs, remaining error budget and burn rates of this replica over the compliance window
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} models.SLOReport
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/admin/slo [get]
+func (h *AdminHandler) GetSLOReport(c *gin.Context) {
+	if h.slo == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("SLO tracking is not enabled"))
+		return
+	}
+
+	c.JSON(http.StatusOK, h.slo.Report())
+}
+
+// GetUsageAnomalies lists unusual tenant usage seen by this replica
+// @Summary Get usage anomalies
+// @Description Get the tenant upload, agent cost and error rate anomalies active in the last interval and the most recently detected ones
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} models.UsageAnomalyReport
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/admin/anomalies [get]
+func (h *AdminHandler) GetUsageAnomalies(c *gin.Context) {
+	if h.anomalies == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Usage anomaly detection is not enabled"))
+		return
+	}
+
+	c.JSON(http.StatusOK, h.anomalies.Report())
+}
+
+// GetSyntheticReport reports the last result of every synthetic probe
+// @Summary Get synthetic probe results
+// @Description Get the last success, latency and error of the login, upload, search and agent dry-run probes. Probes run on the leader, so other replicas report no runs.
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} models.SyntheticReport
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/admin/synthetic [get]
+func (h *AdminHandler) GetSyntheticReport(c *gin.Context) {
+	if h.synthetic == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Synthetic monitoring is not enabled"))
+		return
+	}
+
+	c.JSON(http.StatusOK, h.synthetic.Report())
+}
+
+// CheckConsistency checks the embedded space fields against relationships
+// @Summary Check graph consistency
+// @Description Compare the space and tenant IDs stored on notebooks and users with their relationships. At most limit inconsistencies are listed per entity type; truncated reports that more exist.
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Param limit query int false "Inconsistencies listed per entity type (max 100)" default(100)
+// @Success 200 {object} services.ConsistencyCheckResult
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/admin/consistency [get]
+func (h *AdminHandler) CheckConsistency(c *gin.Context) {
+	if h.spaces == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Consistency checks are not available"))
+		return
+	}
+
+	page := parsePaginationParams(c, pagination.MaxLimit)
+	result, err := h.spaces.CheckConsistency(c.Request.Context(), page.Limit)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// RepairConsistency repairs the inconsistencies found by CheckConsistency
+// @Summary Repair graph consistency
+// @Description Restore missing notebook and user space relationships and copy the space fields of notebooks from their relationships. Notebooks with neither are reported as unrepairable. A dry run only counts the repairs.
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Param dry_run query bool false "Count the repairs without making them" default(false)
+// @Success 200 {object} services.ConsistencyRepairResult
+// @Failure 400 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/admin/consistency/repair [post]
+func (h *AdminHandler) RepairConsistency(c *gin.Context) {
+	if h.spaces == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Consistency checks are not available"))
+		return
+	}
+	dryRun, ok := parseDryRun(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.spaces.RepairConsistency(c.Request.Context(), dryRun)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	h.logger.Info("Graph consistency repaired by admin",
+		zap.String("user_id", getUserID(c)),
+		zap.Bool("dry_run", dryRun),
+		zap.Int("notebook_fields_updated", result.NotebookFieldsUpdated),
+		zap.Int("notebook_relationships_created", result.NotebookRelationshipsCreated),
+		zap.Int("user_relationships_created", result.UserRelationshipsCreated),
+	)
+
+	c.JSON(http.StatusOK, result)
+}
+
+// CleanupOrphans deletes spaces without an owner and retry jobs of deleted
+// documents
+// @Summary Clean up orphaned entities
+// @Description Delete personal spaces no user owns and processing retry jobs whose document no longer exists. A dry run only counts them.
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Param dry_run query bool false "Count the orphans without deleting them" default(false)
+// @Success 200 {object} services.OrphanCleanupResult
+// @Failure 400 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/admin/orphans/cleanup [post]
+func (h *AdminHandler) CleanupOrphans(c *gin.Context) {
+	if h.spaces == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Orphan cleanup is not available"))
+		return
+	}
+	dryRun, ok := parseDryRun(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.spaces.CleanupOrphans(c.Request.Context(), dryRun)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	h.logger.Info("Orphaned entities cleaned up by admin",
+		zap.String("user_id", getUserID(c)),
+		zap.Bool("dry_run", dryRun),
+		zap.Int("spaces_deleted", result.SpacesDeleted),
+		zap.Int("retry_jobs_deleted", result.RetryJobsDeleted),
+	)
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ListDeadLetters lists the processing retry jobs that failed
+// @Summary List dead letters
+// @Description List the document processing retry jobs that exhausted their attempts, most recently failed first
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Param limit query int false "Number of jobs to return" default(20)
+// @Param offset query int false "Number of jobs to skip" default(0)
+// @Success 200 {object} models.DeadLetterListResponse
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/admin/dead-letters [get]
+func (h *AdminHandler) ListDeadLetters(c *gin.Context) {
+	if h.documents == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Document processing is not available"))
+		return
+	}
+
+	page := parsePaginationParams(c, pagination.DefaultLimit)
+	jobs, hasMore, err := h.documents.ListFailedRetryJobs(c.Request.Context(), page.Limit, page.Offset)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.DeadLetterListResponse{
+		Jobs:    jobs,
+		Limit:   page.Limit,
+		Offset:  page.Offset,
+		HasMore: hasMore,
+	})
+}
+
+// RequeueDeadLetter schedules a failed processing retry job again
+// @Summary Requeue a dead letter
+// @Description Schedule a failed processing retry job to run on the next poll of the retry queue
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Retry job ID"
+// @Success 200 {object} models.ProcessingRetryJob
+// @Failure 404 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/admin/dead-letters/{id}/requeue [post]
+func (h *AdminHandler) RequeueDeadLetter(c *gin.Context) {
+	if h.documents == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Document processing is not available"))
+		return
+	}
+
+	job, err := h.documents.RequeueRetryJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	h.logger.Info("Dead letter requeued by admin",
+		zap.String("user_id", getUserID(c)),
+		zap.String("job_id", job.ID),
+		zap.String("document_id", job.DocumentID),
+	)
+
+	c.JSON(http.StatusOK, job)
+}
+
+// ListDocumentDeletions lists the removals of documents' copies
+// @Summary List document deletions
+// @Description List the removals of deleted documents' files, chunks and vectors from AudiModal, the vector store and storage, with the outcome for each target, most recently updated first. Deletions with reason reprocessed recycle what a reprocessed document's earlier processing left behind: the processed file and chunks it replaced and the vectors of its earlier text. Completed deletions are kept for 7 days.
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Param status query string false "Only deletions in this status" Enums(pending, completed, failed)
+// @Param reason query string false "Only deletions for this reason" Enums(deleted, reprocessed)
+// @Param limit query int false "Number of deletions to return" default(20)
+// @Param offset query int false "Number of deletions to skip" default(0)
+// @Success 200 {object} models.DocumentDeletionListResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/admin/deletions [get]
+func (h *AdminHandler) ListDocumentDeletions(c *gin.Context) {
+	if h.deletions == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Document deletions are not available"))
+		return
+	}
+	status := c.Query("status")
+	switch status {
+	case "", models.DocumentDeletionPending, models.DocumentDeletionCompleted, models.DocumentDeletionFailed:
+	default:
+		middleware.WriteError(c, h.logger, errors.ValidationWithDetails("Invalid status", map[string]interface{}{
+			"param": "status",
+		}))
+		return
+	}
+	reason := c.Query("reason")
+	switch reason {
+	case "", models.DocumentDeletionReasonDeleted, models.DocumentDeletionReasonReprocessed:
+	default:
+		middleware.WriteError(c, h.logger, errors.ValidationWithDetails("Invalid reason", map[string]interface{}{
+			"param": "reason",
+		}))
+		return
+	}
+
+	page := parsePaginationParams(c, pagination.DefaultLimit)
+	deletions, hasMore, err := h.deletions.ListDeletions(c.Request.Context(), status, reason, page.Limit, page.Offset)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.DocumentDeletionListResponse{
+		Deletions: deletions,
+		Limit:     page.Limit,
+		Offset:    page.Offset,
+		HasMore:   hasMore,
+	})
+}
+
+// RetryDocumentDeletion retries the failed targets of a document deletion
+// @Summary Retry a document deletion
+// @Description Make the failed targets of a document deletion pending again, to be retried on the next poll. A deletion that has not failed is returned unchanged.
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Deletion ID"
+// @Success 200 {object} models.DocumentDeletion
+// @Failure 404 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/admin/deletions/{id}/retry [post]
+func (h *AdminHandler) RetryDocumentDeletion(c *gin.Context) {
+	if h.deletions == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Document deletions are not available"))
+		return
+	}
+
+	deletion, err := h.deletions.RetryDeletion(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	h.logger.Info("Document deletion retried by admin",
+		zap.String("user_id", getUserID(c)),
+		zap.String("deletion_id", deletion.ID),
+		zap.String("document_id", deletion.DocumentID),
+	)
+
+	c.JSON(http.StatusOK, deletion)
+}
+
+// StartReprocessing starts a reprocessing campaign
+// @Summary Start a reprocessing campaign
+// @Description Queue the documents with a status, optionally of one tenant and created after a time, for reprocessing through the retry queue. A dry run only lists them.
+// @Tags admin
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param campaign body models.ReprocessCampaignRequest true "Documents to reprocess"
+// @Success 200 {object} models.ReprocessCampaignResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/admin/reprocess [post]
+func (h *AdminHandler) StartReprocessing(c *gin.Context) {
+	if h.documents == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Document processing is not available"))
+		return
+	}
+
+	var req models.ReprocessCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.ValidationWithDetails("Invalid request data", map[string]interface{}{
+			"error": err.Error(),
+		}))
+		return
+	}
+	if err := validateStruct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.Validation("Validation failed", err))
+		return
+	}
+
+	response, err := h.documents.StartReprocessingCampaign(c.Request.Context(), req)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	h.logger.Info("Reprocessing campaign started by admin",
+		zap.String("user_id", getUserID(c)),
+		zap.String("campaign_id", response.CampaignID),
+		zap.Bool("dry_run", response.DryRun),
+		zap.Int("queued", response.Queued),
+	)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetUsageReport totals the projected usage per space over a period
+// @Summary Get usage report
+// @Description Total the uploads, processed, failed and deleted documents per space from the reporting read models. The period defaults to the last 30 days.
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Param from query string false "First day of the period (YYYY-MM-DD)"
+// @Param to query string false "Last day of the period (YYYY-MM-DD), default today"
+// @Param space_id query string false "Report only this space"
+// @Success 200 {object} models.UsageReport
+// @Failure 400 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/admin/reporting/usage [get]
+func (h *AdminHandler) GetUsageReport(c *gin.Context) {
+	if h.reporting == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Reporting projection is not enabled"))
+		return
+	}
+
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if value := c.Query("to"); value != "" {
+		day, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errors.Validation("to must be a date (YYYY-MM-DD)", err))
+			return
+		}
+		to = day
+	}
+	from := to.AddDate(0, 0, -29)
+	if value := c.Query("from"); value != "" {
+		day, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errors.Validation("from must be a date (YYYY-MM-DD)", err))
+			return
+		}
+		from = day
+	}
+	if from.After(to) {
+		c.JSON(http.StatusBadRequest, errors.BadRequest("from must not be after to"))
+		return
+	}
+
+	// The report excludes its end, so it ends the day after the last day
+	report, err := h.reporting.UsageReport(c.Request.Context(), from, to.AddDate(0, 0, 1), c.Query("space_id"))
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ProvisionTenant creates an organization tenant for an existing user
+// @Summary Provision a tenant
+// @Description Create an organization owned by an existing user and its first space, which is registered as an AudiModal tenant
+// @Tags admin
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param tenant body models.TenantProvisionRequest true "Tenant to provision"
+// @Success 201 {object} models.TenantProvisionResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 409 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/admin/tenants [post]
+func (h *AdminHandler) ProvisionTenant(c *gin.Context) {
+	if h.organizations == nil || h.users == nil || h.spaces == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Tenant provisioning is not available"))
+		return
+	}
+
+	var req models.TenantProvisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.ValidationWithDetails("Invalid request data", map[string]interface{}{
+			"error": err.Error(),
+		}))
+		return
+	}
+	if err := validateStruct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.Validation("Validation failed", err))
+		return
+	}
+
+	ctx := c.Request.Context()
+	owner, err := h.users.GetUserByID(ctx, req.OwnerID)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	org, err := h.organizations.CreateOrganization(ctx, req.Organization, owner.ID)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	spaceName := req.SpaceName
+	if spaceName == "" {
+		spaceName = org.Name
+	}
+	space, err := h.spaces.CreateSpace(ctx, owner.ID, models.SpaceCreateRequest{
+		Name:           spaceName,
+		OrganizationID: org.ID,
+	})
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	h.logger.Info("Tenant provisioned by admin",
+		zap.String("user_id", getUserID(c)),
+		zap.String("owner_id", owner.ID),
+		zap.String("organization_id", org.ID),
+		zap.String("space_id", space.ID),
+		zap.String("tenant_id", space.TenantID),
+	)
+
+	c.JSON(http.StatusCreated, models.TenantProvisionResponse{
+		Organization: org.ToResponse(),
+		Space:        space.ToFullResponse(),
+	})
+}
+
+// StartTenantExport exports everything a tenant holds as an async job
+// @Summary Start a tenant export job
+// @Description Export every space, notebook, document with its versions and stored files, and the audit events of the tenant's spaces to a bundle in object storage, for offboarding. Responds 202 with a tenant_export job whose progress counts the items written; once it has succeeded its result gives the bundle prefix and a download URL for its manifest, which lists the SHA-256 hash of every file.
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Success 202 {object} models.Job
+// @Failure 404 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/admin/tenants/{tenant_id}/export [post]
+func (h *AdminHandler) StartTenantExport(c *gin.Context) {
+	if h.tenantExports == nil || h.jobs == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Tenant exports are not available"))
+		return
+	}
+
+	tenantID := c.Param("tenant_id")
+	userID := getUserID(c)
+
+	// Fail fast on an unknown tenant rather than in a job the client has
+	// to poll
+	if err := h.tenantExports.CheckTenant(c.Request.Context(), tenantID); err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	job, err := h.jobs.Start(c.Request.Context(), models.JobTypeTenantExport, tenantID, userID, func(ctx context.Context, progress func(models.JobProgress)) (map[string]interface{}, error) {
+		export, err := h.tenantExports.Export(ctx, tenantID, progress)
+		if err != nil {
+			return nil, err
+		}
+		return jobResult(export)
+	})
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	h.logger.Info("Tenant export started by admin",
+		zap.String("user_id", userID),
+		zap.String("tenant_id", tenantID),
+		zap.String("job_id", job.ID),
+	)
+	respondAccepted(c, job)
+}
+
+// GetMaintenanceStatus returns the maintenance that applies to the user
+// @Summary Get maintenance status
+// @Description Get the maintenance in progress system-wide and, when the request names a space, for the space's tenant, to show a banner. While a window is active, writes are rejected with 503 and AETHER-MAINT-001 and responses carry X-Maintenance-Mode with its scope; reads and downloads keep working.
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} models.MaintenanceStatus
+// @Router /api/v1/maintenance [get]
+func (h *AdminHandler) GetMaintenanceStatus(c *gin.Context) {
+	if h.maintenance == nil {
+		c.JSON(http.StatusOK, models.MaintenanceStatus{Windows: []*models.MaintenanceWindow{}})
+		return
+	}
+
+	tenantID := ""
+	if spaceContext, err := middleware.GetSpaceContext(c); err == nil {
+		tenantID = spaceContext.TenantID
+	}
+	c.JSON(http.StatusOK, h.maintenance.Status(tenantID))
+}
+
+// ListMaintenance lists the maintenance windows in progress
+// @Summary List maintenance windows
+// @Description List the maintenance in progress, system-wide first, then per tenant
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} models.MaintenanceStatus
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/admin/maintenance [get]
+func (h *AdminHandler) ListMaintenance(c *gin.Context) {
+	if h.maintenance == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Maintenance mode is not available"))
+		return
+	}
+
+	status, err := h.maintenance.List(c.Request.Context())
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// StartMaintenance starts system-wide maintenance
+// @Summary Start system-wide maintenance
+// @Description Reject writes of every tenant with 503 and AETHER-MAINT-001, whose details carry the message and expected end for the banner, until maintenance is ended. Reads, downloads and WebSocket streams keep working, as do the admin endpoints that end maintenance. Every replica applies the window within MAINTENANCE_REFRESH_SECONDS. Starting it again changes the message and expected end and keeps the start.
+// @Tags admin
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body models.MaintenanceRequest true "Maintenance banner"
+// @Success 200 {object} models.MaintenanceWindow
+// @Failure 400 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/admin/maintenance [put]
+func (h *AdminHandler) StartMaintenance(c *gin.Context) {
+	h.startMaintenance(c, "")
+}
+
+// EndMaintenance ends system-wide maintenance
+// @Summary End system-wide maintenance
+// @Description End the system-wide maintenance; tenant maintenance windows stay in progress. Fails with 404 when none is in progress.
+// @Tags admin
+// @Security Bearer
+// @Success 204
+// @Failure 404 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/admin/maintenance [delete]
+func (h *AdminHandler) EndMaintenance(c *gin.Context) {
+	h.endMaintenance(c, "")
+}
+
+// StartTenantMaintenance starts maintenance of one tenant
+// @Summary Start tenant maintenance
+// @Description Reject writes made in the spaces of one tenant with 503 and AETHER-MAINT-001 until its maintenance is ended, like system-wide maintenance. Requests that name no space are not affected. Starting it again changes the message and expected end and keeps the start.
+// @Tags admin
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param request body models.MaintenanceRequest true "Maintenance banner"
+// @Success 200 {object} models.MaintenanceWindow
+// @Failure 400 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/admin/maintenance/tenants/{tenant_id} [put]
+func (h *AdminHandler) StartTenantMaintenance(c *gin.Context) {
+	h.startMaintenance(c, c.Param("tenant_id"))
+}
+
+// EndTenantMaintenance ends maintenance of one tenant
+// @Summary End tenant maintenance
+// @Description End the maintenance of one tenant. Fails with 404 when none is in progress for the tenant.
+// @Tags admin
+// @Security Bearer
+// @Param tenant_id path string true "Tenant ID"
+// @Success 204
+// @Failure 404 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/admin/maintenance/tenants/{tenant_id} [delete]
+func (h *AdminHandler) EndTenantMaintenance(c *gin.Context) {
+	h.endMaintenance(c, c.Param("tenant_id"))
+}
+
+// startMaintenance starts maintenance of a tenant, or system-wide when
+// tenantID is empty
+func (h *AdminHandler) startMaintenance(c *gin.Context, tenantID string) {
+	if h.maintenance == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Maintenance mode is not available"))
+		return
+	}
+
+	var req models.MaintenanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Invalid request body", err))
+		return
+	}
+	if err := validateStruct(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Validation failed", err))
+		return
+	}
+
+	window, err := h.maintenance.Start(c.Request.Context(), tenantID, req, getUserID(c))
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+	c.JSON(http.StatusOK, window)
+}
+
+// endMaintenance ends maintenance of a tenant, or system-wide when
+// tenantID is empty
+func (h *AdminHandler) endMaintenance(c *gin.Context, tenantID string) {
+	if h.maintenance == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Maintenance mode is not available"))
+		return
+	}
+
+	if err := h.maintenance.End(c.Request.Context(), tenantID, getUserID(c)); err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// UpdateSpaceQuotas changes the limits of a space's quotas
+// @Summary Update space quotas
+// @Description Change the limits of a space's quotas, such as after a plan change; omitted limits keep their value. Limits default by space type. The change is recorded in the audit log.
+// @Tags admin
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param space_id path string true "Space ID"
+// @Param request body models.SpaceQuotasUpdateRequest true "Quota limits"
+// @Success 200 {object} models.SpaceQuotas
+// @Failure 400 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/admin/spaces/{space_id}/quotas [put]
+func (h *AdminHandler) UpdateSpaceQuotas(c *gin.Context) {
+	if h.quotas == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Space quotas are not available"))
+		return
+	}
+
+	var req models.SpaceQuotasUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Invalid request body", err))
+		return
+	}
+	if err := validateStruct(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Validation failed", err))
+		return
+	}
+
+	quotas, err := h.quotas.UpdateLimits(c.Request.Context(), c.Param("space_id"), req, getUserID(c))
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+	c.JSON(http.StatusOK, quotas)
+}
+
+// UpdateSpaceEncryption turns the encryption at rest of a space's text on
+// or off
+// @Summary Update space text encryption
+// @Description Turn the encryption at rest of the extracted text of a space's documents on or off, then encrypt, or decrypt, the text its documents already have. Text is encrypted with AES-256-GCM under a key per tenant and decrypted transparently for the users who can read the documents; encrypted text is left out of search, so documents of encrypted spaces are found by name, description and tags. Migration stops after about 20 seconds and reports the documents remaining; repeating the request carries on with them. The change is recorded in the audit log.
+// @Tags admin
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param space_id path string true "Space ID"
+// @Param request body models.SpaceEncryptionUpdateRequest true "Encryption setting"
+// @Success 200 {object} models.SpaceEncryption
+// @Failure 400 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/admin/spaces/{space_id}/encryption [put]
+func (h *AdminHandler) UpdateSpaceEncryption(c *gin.Context) {
+	if h.encryption == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Text encryption is not configured"))
+		return
+	}
+
+	var req models.SpaceEncryptionUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Invalid request body", err))
+		return
+	}
+	if err := validateStruct(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Validation failed", err))
+		return
+	}
+
+	encryption, err := h.encryption.SetSpaceEncryption(c.Request.Context(), c.Param("space_id"), *req.Enabled, getUserID(c))
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+	c.JSON(http.StatusOK, encryption)
+}
+
+// ListRegions lists the regions of the deployment
+// @Summary List regions
+// @Description List the regions of a multi-region deployment with the number of tenants each serves (active) and replicates (passive), and name the region answering
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} models.RegionStatus
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/admin/regions [get]
+func (h *AdminHandler) ListRegions(c *gin.Context) {
+	if h.regions == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Regions are not configured"))
+		return
+	}
+
+	status, err := h.regions.Regions(c.Request.Context())
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// GetTenantRegion returns the region pin of a tenant
+// @Summary Get tenant region
+// @Description Get the region that serves a tenant and the passive region that holds its replicas. Fails with 404 and AETHER-REGION-003 when the tenant is not pinned, in which case every region serves it.
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Success 200 {object} models.TenantRegion
+// @Failure 404 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/admin/tenants/{tenant_id}/region [get]
+func (h *AdminHandler) GetTenantRegion(c *gin.Context) {
+	if h.regions == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Regions are not configured"))
+		return
+	}
+
+	pin, err := h.regions.GetPin(c.Request.Context(), c.Param("tenant_id"))
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+	c.JSON(http.StatusOK, pin)
+}
+
+// PinTenantRegion pins a tenant to a region
+// @Summary Pin tenant region
+// @Description Pin a tenant to the region that serves it and, optionally, a passive region to fail over to. Other regions reject requests in the tenant's spaces with 421 and AETHER-REGION-001, naming the region's URL; every replica applies the pin within REGION_REFRESH_SECONDS. Pinning does not move data: replicate it to the region first. The change is recorded in the audit log.
+// @Tags admin
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param request body models.TenantRegionRequest true "Regions"
+// @Success 200 {object} models.TenantRegion
+// @Failure 400 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/admin/tenants/{tenant_id}/region [put]
+func (h *AdminHandler) PinTenantRegion(c *gin.Context) {
+	if h.regions == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Regions are not configured"))
+		return
+	}
+
+	var req models.TenantRegionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Invalid request body", err))
+		return
+	}
+	if err := validateStruct(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Validation failed", err))
+		return
+	}
+
+	pin, err := h.regions.SetPin(c.Request.Context(), c.Param("tenant_id"), req, getUserID(c))
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+	c.JSON(http.StatusOK, pin)
+}
+
+// FailoverTenant fails a tenant over to another region
+// @Summary Fail over tenant
+// @Description Make the tenant's passive region, or target_region, the one that serves it; the former active region becomes the passive one. The replication hooks in REGION_REPLICATION_HOOKS are called first, in order, to promote the tenant's Neo4j and S3 replicas; when one fails the tenant is not failed over and the request fails with 502 and AETHER-REGION-005, unless force is set. The failover is recorded in the audit log.
+// @Tags admin
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param request body models.TenantFailoverRequest false "Failover options"
+// @Success 200 {object} models.TenantFailover
+// @Failure 400 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 409 {object} errors.APIError
+// @Failure 502 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/admin/tenants/{tenant_id}/failover [post]
+func (h *AdminHandler) FailoverTenant(c *gin.Context) {
+	if h.regions == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Regions are not configured"))
+		return
+	}
+
+	var req models.TenantFailoverRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middleware.WriteError(c, h.logger, errors.Validation("Invalid request body", err))
+			return
+		}
+	}
+
+	failover, err := h.regions.Failover(c.Request.Context(), c.Param("tenant_id"), req, getUserID(c))
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+	c.JSON(http.StatusOK, failover)
+}
+
+// parseDryRun reads the dry_run query parameter, responding 400 when it is
+// not a boolean
+func parseDryRun(c *gin.Context) (bool, bool) {
+	value := c.Query("dry_run")
+	if value == "" {
+		return false, true
+	}
+	dryRun, err := strconv.ParseBool(value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.Validation("dry_run must be a boolean", err))
+		return false, false
+	}
+	return dryRun, true
+}
@@ -57,7 +57,7 @@ func (h *AgentHandler) CreateAgent(c *gin.Context) {
 	userID, err := ensureUserExists(c, h.userService, h.logger)
 	if err != nil {
 		h.logger.Error("Failed to resolve user", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -77,7 +77,7 @@ func (h *AgentHandler) CreateAgent(c *gin.Context) {
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -91,7 +91,7 @@ func (h *AgentHandler) CreateAgent(c *gin.Context) {
 	agent, err := h.agentService.CreateAgent(c.Request.Context(), req, spaceContext, authToken)
 	if err != nil {
 		h.logger.Error("Failed to create agent", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -128,7 +128,7 @@ func (h *AgentHandler) GetAgent(c *gin.Context) {
 	userID, err := ensureUserExists(c, h.userService, h.logger)
 	if err != nil {
 		h.logger.Error("Failed to resolve user", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -142,7 +142,7 @@ func (h *AgentHandler) GetAgent(c *gin.Context) {
 	agent, err := h.agentService.GetAgent(c.Request.Context(), agentID, userID, userTeams)
 	if err != nil {
 		h.logger.Error("Failed to get agent", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -176,7 +176,7 @@ func (h *AgentHandler) UpdateAgent(c *gin.Context) {
 	userID, err := ensureUserExists(c, h.userService, h.logger)
 	if err != nil {
 		h.logger.Error("Failed to resolve user", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -203,7 +203,7 @@ func (h *AgentHandler) UpdateAgent(c *gin.Context) {
 	agent, err := h.agentService.UpdateAgent(c.Request.Context(), agentID, req, userID, authToken)
 	if err != nil {
 		h.logger.Error("Failed to update agent", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -238,7 +238,7 @@ func (h *AgentHandler) DeleteAgent(c *gin.Context) {
 	userID, err := ensureUserExists(c, h.userService, h.logger)
 	if err != nil {
 		h.logger.Error("Failed to resolve user", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -252,7 +252,7 @@ func (h *AgentHandler) DeleteAgent(c *gin.Context) {
 	err = h.agentService.DeleteAgent(c.Request.Context(), agentID, userID, authToken)
 	if err != nil {
 		h.logger.Error("Failed to delete agent", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -290,7 +290,7 @@ func (h *AgentHandler) ListAgents(c *gin.Context) {
 	userID, err := ensureUserExists(c, h.userService, h.logger)
 	if err != nil {
 		h.logger.Error("Failed to resolve user", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -367,7 +367,7 @@ func (h *AgentHandler) ListAgents(c *gin.Context) {
 	agents, err := h.agentService.ListAgents(c.Request.Context(), req, userID, userTeams, authToken)
 	if err != nil {
 		h.logger.Error("Failed to list agents", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -401,7 +401,7 @@ func (h *AgentHandler) AddKnowledgeSource(c *gin.Context) {
 	userID, err := ensureUserExists(c, h.userService, h.logger)
 	if err != nil {
 		h.logger.Error("Failed to resolve user", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -421,7 +421,7 @@ func (h *AgentHandler) AddKnowledgeSource(c *gin.Context) {
 	err = h.agentService.AddKnowledgeSource(c.Request.Context(), agentID, req.NotebookID, userID, req)
 	if err != nil {
 		h.logger.Error("Failed to add knowledge source", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -465,14 +465,14 @@ func (h *AgentHandler) RemoveKnowledgeSource(c *gin.Context) {
 	userID, err := ensureUserExists(c, h.userService, h.logger)
 	if err != nil {
 		h.logger.Error("Failed to resolve user", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
 	err = h.agentService.RemoveKnowledgeSource(c.Request.Context(), agentID, notebookID, userID)
 	if err != nil {
 		h.logger.Error("Failed to remove knowledge source", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -510,14 +510,14 @@ func (h *AgentHandler) GetKnowledgeSources(c *gin.Context) {
 	userID, err := ensureUserExists(c, h.userService, h.logger)
 	if err != nil {
 		h.logger.Error("Failed to resolve user", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
 	sources, err := h.agentService.GetAgentKnowledgeSources(c.Request.Context(), agentID, userID)
 	if err != nil {
 		h.logger.Error("Failed to get agent knowledge sources", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -538,6 +538,7 @@ func (h *AgentHandler) GetKnowledgeSources(c *gin.Context) {
 // @Failure 401 {object} errors.APIError
 // @Failure 403 {object} errors.APIError
 // @Failure 404 {object} errors.APIError
+// @Failure 429 {object} errors.APIError
 // @Failure 500 {object} errors.APIError
 // @Router /api/v1/agents/{id}/execute [post]
 func (h *AgentHandler) ExecuteAgent(c *gin.Context) {
@@ -551,7 +552,7 @@ func (h *AgentHandler) ExecuteAgent(c *gin.Context) {
 	userID, err := ensureUserExists(c, h.userService, h.logger)
 	if err != nil {
 		h.logger.Error("Failed to resolve user", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -585,7 +586,7 @@ func (h *AgentHandler) ExecuteAgent(c *gin.Context) {
 	response, err := h.agentService.ExecuteAgent(c.Request.Context(), agentID, req, userID, userTeams, authToken)
 	if err != nil {
 		h.logger.Error("Failed to execute agent", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -638,17 +639,18 @@ func (h *AgentHandler) getUserTeams(c *gin.Context, userID string) ([]string, er
 // ListExecutions lists execution history for agents
 // @Summary Get execution history
 // @Description Retrieve execution history with optional filtering by agent_id
-// @Tags Executions
+// @Tags agents
 // @Accept json
 // @Produce json
+// @Security Bearer
 // @Param agent_id query string false "Filter by agent ID"
 // @Param limit query int false "Number of results to return" default(20)
 // @Param offset query int false "Number of results to skip" default(0)
-// @Success 200 {object} ExecutionListResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 401 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
-// @Router /executions [get]
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 500 {object} errors.APIError
+// @Router /api/v1/executions [get]
 func (h *AgentHandler) ListExecutions(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -701,16 +703,17 @@ func (h *AgentHandler) ListExecutions(c *gin.Context) {
 // GetAgentStats returns statistics for a specific agent
 // @Summary Get agent statistics
 // @Description Retrieve statistics and metrics for a specific agent
-// @Tags Stats
+// @Tags agents
 // @Accept json
 // @Produce json
+// @Security Bearer
 // @Param id path string true "Agent ID"
-// @Success 200 {object} AgentStatsResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 401 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
-// @Router /stats/agents/{id} [get]
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 500 {object} errors.APIError
+// @Router /api/v1/stats/agents/{id} [get]
 func (h *AgentHandler) GetAgentStats(c *gin.Context) {
 	agentID := c.Param("id")
 	if agentID == "" {
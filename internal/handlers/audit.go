@@ -0,0 +1,439 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
+	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// auditCSVHeader lists the columns of a CSV audit export
+var auditCSVHeader = []string{
+	"sequence", "id", "created_at", "action", "resource_type", "resource_id",
+	"actor_id", "space_id", "tenant_id", "organization_id", "source",
+	"details", "prev_hash", "hash",
+}
+
+// AuditHandler serves the audit log to administrators and to space and
+// organization admins
+type AuditHandler struct {
+	auditService        *services.AuditService
+	spaceService        *services.SpaceService
+	organizationService *services.OrganizationService
+	userService         *services.UserService
+	logger              *logger.Logger
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(auditService *services.AuditService, spaceService *services.SpaceService, organizationService *services.OrganizationService, userService *services.UserService, log *logger.Logger) *AuditHandler {
+	return &AuditHandler{
+		auditService:        auditService,
+		spaceService:        spaceService,
+		organizationService: organizationService,
+		userService:         userService,
+		logger:              log.WithService("audit_handler"),
+	}
+}
+
+// ListAuditEvents lists audit events across the platform
+// @Summary List audit events
+// @Description List audit events, newest first, optionally filtered
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Param actor_id query string false "Actor user ID"
+// @Param resource_type query string false "Resource type"
+// @Param resource_id query string false "Resource ID"
+// @Param action query string false "Action, e.g. notebook.deleted"
+// @Param space_id query string false "Space ID"
+// @Param organization_id query string false "Organization ID, including its spaces"
+// @Param from query string false "Earliest event time (RFC3339, inclusive)"
+// @Param to query string false "Latest event time (RFC3339, exclusive)"
+// @Param limit query int false "Page size" default(20)
+// @Param offset query int false "Page offset" default(0)
+// @Success 200 {object} models.AuditEventListResponse
+// @Failure 400 {object} errors.APIError
+// @Router /api/v1/admin/audit [get]
+func (h *AuditHandler) ListAuditEvents(c *gin.Context) {
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+	if spaceID := c.Query("space_id"); spaceID != "" {
+		filter.SpaceIDs = []string{spaceID}
+	}
+	filter.OrganizationID = c.Query("organization_id")
+
+	h.list(c, filter)
+}
+
+// ExportAuditEvents exports audit events across the platform
+// @Summary Export audit events
+// @Description Stream matching audit events, oldest first, as CSV or newline-delimited JSON
+// @Tags admin
+// @Produce text/csv,application/x-ndjson
+// @Security Bearer
+// @Param format query string false "csv or json" default(json)
+// @Param actor_id query string false "Actor user ID"
+// @Param resource_type query string false "Resource type"
+// @Param resource_id query string false "Resource ID"
+// @Param action query string false "Action"
+// @Param space_id query string false "Space ID"
+// @Param organization_id query string false "Organization ID, including its spaces"
+// @Param from query string false "Earliest event time (RFC3339, inclusive)"
+// @Param to query string false "Latest event time (RFC3339, exclusive)"
+// @Success 200 {object} models.AuditEvent "One event per line"
+// @Failure 400 {object} errors.APIError
+// @Router /api/v1/admin/audit/export [get]
+func (h *AuditHandler) ExportAuditEvents(c *gin.Context) {
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+	if spaceID := c.Query("space_id"); spaceID != "" {
+		filter.SpaceIDs = []string{spaceID}
+	}
+	filter.OrganizationID = c.Query("organization_id")
+
+	h.export(c, filter, "audit")
+}
+
+// VerifyAuditLog checks the integrity of the audit hash chain
+// @Summary Verify audit log
+// @Description Recompute the audit hash chain and report the first entry that was altered or removed
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.AuditVerifyResponse
+// @Router /api/v1/admin/audit/verify [get]
+func (h *AuditHandler) VerifyAuditLog(c *gin.Context) {
+	result, err := h.auditService.Verify(c.Request.Context())
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+	if !result.Valid {
+		h.logger.Error("Audit log verification failed",
+			zap.Int64p("broken_at", result.BrokenAt),
+			zap.String("reason", result.Reason))
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ListSpaceAuditEvents lists the audit events of a space
+// @Summary List space audit events
+// @Description List audit events of a space, newest first. Requires the space admin role.
+// @Tags spaces
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Space ID"
+// @Param actor_id query string false "Actor user ID"
+// @Param resource_type query string false "Resource type"
+// @Param resource_id query string false "Resource ID"
+// @Param action query string false "Action"
+// @Param from query string false "Earliest event time (RFC3339, inclusive)"
+// @Param to query string false "Latest event time (RFC3339, exclusive)"
+// @Param limit query int false "Page size" default(20)
+// @Param offset query int false "Page offset" default(0)
+// @Success 200 {object} models.AuditEventListResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Router /api/v1/spaces/{id}/audit [get]
+func (h *AuditHandler) ListSpaceAuditEvents(c *gin.Context) {
+	filter, ok := h.spaceFilter(c)
+	if !ok {
+		return
+	}
+	h.list(c, filter)
+}
+
+// ExportSpaceAuditEvents exports the audit events of a space
+// @Summary Export space audit events
+// @Description Stream audit events of a space, oldest first, as CSV or newline-delimited JSON. Requires the space admin role.
+// @Tags spaces
+// @Produce text/csv,application/x-ndjson
+// @Security Bearer
+// @Param id path string true "Space ID"
+// @Param format query string false "csv or json" default(json)
+// @Success 200 {object} models.AuditEvent "One event per line"
+// @Failure 400 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Router /api/v1/spaces/{id}/audit/export [get]
+func (h *AuditHandler) ExportSpaceAuditEvents(c *gin.Context) {
+	filter, ok := h.spaceFilter(c)
+	if !ok {
+		return
+	}
+	h.export(c, filter, "space-"+c.Param("id")+"-audit")
+}
+
+// ListOrganizationAuditEvents lists the audit events of an organization
+// @Summary List organization audit events
+// @Description List audit events of an organization and its spaces, newest first. Requires the organization owner or admin role.
+// @Tags organizations
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Organization ID"
+// @Param actor_id query string false "Actor user ID"
+// @Param resource_type query string false "Resource type"
+// @Param resource_id query string false "Resource ID"
+// @Param action query string false "Action"
+// @Param from query string false "Earliest event time (RFC3339, inclusive)"
+// @Param to query string false "Latest event time (RFC3339, exclusive)"
+// @Param limit query int false "Page size" default(20)
+// @Param offset query int false "Page offset" default(0)
+// @Success 200 {object} models.AuditEventListResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Router /api/v1/organizations/{id}/audit [get]
+func (h *AuditHandler) ListOrganizationAuditEvents(c *gin.Context) {
+	filter, ok := h.organizationFilter(c)
+	if !ok {
+		return
+	}
+	h.list(c, filter)
+}
+
+// ExportOrganizationAuditEvents exports the audit events of an organization
+// @Summary Export organization audit events
+// @Description Stream audit events of an organization and its spaces, oldest first, as CSV or newline-delimited JSON. Requires the organization owner or admin role.
+// @Tags organizations
+// @Produce text/csv,application/x-ndjson
+// @Security Bearer
+// @Param id path string true "Organization ID"
+// @Param format query string false "csv or json" default(json)
+// @Success 200 {object} models.AuditEvent "One event per line"
+// @Failure 400 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Router /api/v1/organizations/{id}/audit/export [get]
+func (h *AuditHandler) ExportOrganizationAuditEvents(c *gin.Context) {
+	filter, ok := h.organizationFilter(c)
+	if !ok {
+		return
+	}
+	h.export(c, filter, "organization-"+c.Param("id")+"-audit")
+}
+
+// spaceFilter parses the filter of a space audit request after checking
+// that the caller administers the space. It writes the error response and
+// returns false when the request cannot proceed.
+func (h *AuditHandler) spaceFilter(c *gin.Context) (models.AuditFilter, bool) {
+	spaceID := c.Param("id")
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return filter, false
+	}
+
+	userID, err := ensureUserExists(c, h.userService, h.logger)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return filter, false
+	}
+
+	role, err := h.spaceService.GetUserRoleInSpace(c.Request.Context(), spaceID, userID)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return filter, false
+	}
+	if role == "" {
+		middleware.WriteError(c, h.logger, errors.ForbiddenWithDetails("You do not have access to this space", map[string]interface{}{
+			"space_id": spaceID,
+		}).WithErrorCode(errors.CodeSpaceAccessDenied))
+		return filter, false
+	}
+	if err := h.spaceService.AuthorizeRole(c.Request.Context(), spaceID, userID, role, models.ActionSpaceAudit); err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return filter, false
+	}
+
+	filter.SpaceIDs = []string{spaceID}
+	return filter, true
+}
+
+// organizationFilter parses the filter of an organization audit request
+// after checking that the caller's role may view its audit log
+func (h *AuditHandler) organizationFilter(c *gin.Context) (models.AuditFilter, bool) {
+	orgID := c.Param("id")
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return filter, false
+	}
+
+	userID, err := ensureUserExists(c, h.userService, h.logger)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return filter, false
+	}
+
+	if _, err := h.organizationService.AuthorizeMember(c.Request.Context(), orgID, userID, models.ActionOrganizationAudit); err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return filter, false
+	}
+
+	filter.OrganizationID = orgID
+	return filter, true
+}
+
+// list writes a page of audit events
+func (h *AuditHandler) list(c *gin.Context, filter models.AuditFilter) {
+	response, err := h.auditService.Query(c.Request.Context(), filter)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// export streams audit events as CSV or NDJSON, following the format
+// query parameter
+func (h *AuditHandler) export(c *gin.Context, filter models.AuditFilter, filename string) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		middleware.WriteError(c, h.logger, errors.ValidationWithDetails("format must be csv or json", map[string]interface{}{
+			"field": "format",
+		}))
+		return
+	}
+
+	// Headers are written with the first event so errors raised before
+	// streaming starts still get the standard error response
+	var csvWriter *csv.Writer
+	var write func(*models.AuditEvent) error
+	var flush func()
+	started := false
+	if format == "csv" {
+		csvWriter = csv.NewWriter(c.Writer)
+		write = func(event *models.AuditEvent) error { return csvWriter.Write(auditCSVRow(event)) }
+		flush = func() {
+			csvWriter.Flush()
+			c.Writer.Flush()
+		}
+	} else {
+		encoder := json.NewEncoder(c.Writer)
+		write = func(event *models.AuditEvent) error { return encoder.Encode(event) }
+		flush = c.Writer.Flush
+	}
+	start := func() {
+		if started {
+			return
+		}
+		started = true
+		if csvWriter != nil {
+			c.Header("Content-Type", "text/csv")
+			c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, filename))
+			c.Status(http.StatusOK)
+			_ = csvWriter.Write(auditCSVHeader)
+		} else {
+			c.Header("Content-Type", "application/x-ndjson")
+			c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.ndjson"`, filename))
+			c.Status(http.StatusOK)
+		}
+	}
+
+	written := 0
+	count, err := h.auditService.Export(c.Request.Context(), filter, func(event *models.AuditEvent) error {
+		start()
+		if err := write(event); err != nil {
+			return err
+		}
+		if written++; written%exportFlushInterval == 0 {
+			flush()
+		}
+		return nil
+	})
+	if err != nil {
+		if !started {
+			middleware.WriteError(c, h.logger, err)
+			return
+		}
+		// The status line is already sent; the client sees a truncated export
+		h.logger.Error("Audit export aborted",
+			zap.String("format", format),
+			zap.Int("exported", count),
+			zap.Error(err))
+		return
+	}
+
+	start()
+	flush()
+}
+
+// auditCSVRow formats an event as a row of auditCSVHeader columns
+func auditCSVRow(event *models.AuditEvent) []string {
+	details := ""
+	if len(event.Details) > 0 {
+		data, _ := json.Marshal(event.Details)
+		details = string(data)
+	}
+	return []string{
+		strconv.FormatInt(event.Sequence, 10),
+		event.ID,
+		event.CreatedAt.UTC().Format(time.RFC3339Nano),
+		event.Action,
+		event.ResourceType,
+		event.ResourceID,
+		event.ActorID,
+		event.SpaceID,
+		event.TenantID,
+		event.OrganizationID,
+		event.Source,
+		details,
+		event.PrevHash,
+		event.Hash,
+	}
+}
+
+// parseAuditFilter parses the filters shared by every audit endpoint
+func parseAuditFilter(c *gin.Context) (models.AuditFilter, error) {
+	page := pagination.FromQuery(c, pagination.DefaultLimit)
+	filter := models.AuditFilter{
+		ActorID:      c.Query("actor_id"),
+		ResourceType: c.Query("resource_type"),
+		ResourceID:   c.Query("resource_id"),
+		Action:       c.Query("action"),
+		Limit:        page.Limit,
+		Offset:       page.Offset,
+	}
+
+	for _, bound := range []struct {
+		name   string
+		target **time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		value := c.Query(bound.name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, errors.ValidationWithDetails(bound.name+" must be an RFC3339 timestamp", map[string]interface{}{
+				"field": bound.name,
+				"value": value,
+			})
+		}
+		*bound.target = &t
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return filter, errors.ValidationWithDetails("from must be before to", map[string]interface{}{
+			"field": "from",
+		})
+	}
+	return filter, nil
+}
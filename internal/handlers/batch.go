@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// batchPath is the route of the batch endpoint
+const batchPath = "/api/v1/batch"
+
+// batchPathPattern matches the batch endpoint under any API version, or
+// none, as the versioned handler operations are dispatched to resolves it
+var batchPathPattern = regexp.MustCompile(`^/api/(v[0-9]+/)?batch(/|$)`)
+
+// batchForwardedHeaders are the request headers each operation of a batch
+// inherits from the batch request
+var batchForwardedHeaders = []string{"Authorization", "X-Space-Type", "X-Space-ID"}
+
+// BatchHandler runs several API requests in one call
+type BatchHandler struct {
+	jobService    *services.JobService
+	handler       http.Handler
+	maxOperations int
+	logger        *logger.Logger
+}
+
+// NewBatchHandler creates a new batch handler
+func NewBatchHandler(jobService *services.JobService, maxOperations int, log *logger.Logger) *BatchHandler {
+	return &BatchHandler{
+		jobService:    jobService,
+		maxOperations: maxOperations,
+		logger:        log.WithService("batch_handler"),
+	}
+}
+
+// SetHandler sets the handler operations are dispatched to, normally the
+// one serving live traffic, which negotiates the API version of each
+// request before routing it
+func (h *BatchHandler) SetHandler(handler http.Handler) {
+	h.handler = handler
+}
+
+// Batch runs several API requests in one call
+// @Summary Run a batch of requests
+// @Description Run up to the configured number of API requests in order, each with the caller's credentials and space headers. Every operation gets its own status and body; a failed operation does not stop the ones after it. With async set the batch runs as a job: the response is 202 with the job, whose result holds the batch response once it has finished.
+// @Tags jobs
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body models.BatchRequest true "Operations to run"
+// @Success 200 {object} models.BatchResponse
+// @Success 202 {object} models.Job
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/batch [post]
+func (h *BatchHandler) Batch(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == "" {
+		middleware.WriteError(c, h.logger, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
+		return
+	}
+
+	var req models.BatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if middleware.IsBodyTooLarge(err) {
+			middleware.WriteError(c, h.logger, err)
+			return
+		}
+		middleware.WriteError(c, h.logger, errors.ValidationWithDetails("Invalid request data", map[string]interface{}{
+			"error": err.Error(),
+		}))
+		return
+	}
+
+	if err := validateStruct(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Validation failed", err))
+		return
+	}
+
+	if len(req.Operations) > h.maxOperations {
+		middleware.WriteError(c, h.logger, errors.ValidationWithDetails(fmt.Sprintf("A batch may have at most %d operations", h.maxOperations), map[string]interface{}{
+			"operations": len(req.Operations),
+		}))
+		return
+	}
+
+	for i, op := range req.Operations {
+		if problem := checkBatchPath(op.Path); problem != "" {
+			middleware.WriteError(c, h.logger, errors.ValidationWithDetails(problem, map[string]interface{}{
+				"operation": i,
+				"path":      op.Path,
+			}))
+			return
+		}
+	}
+
+	if h.handler == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Batch requests are not available"))
+		return
+	}
+
+	// Async operations run after this request has been answered, so they
+	// get their own copy of its headers
+	parent := c.Request.Clone(c.Request.Context())
+	if !req.Async {
+		c.JSON(http.StatusOK, h.runBatch(c.Request.Context(), parent, req.Operations, nil))
+		return
+	}
+
+	job, err := h.jobService.Start(c.Request.Context(), models.JobTypeBatch, "", userID, func(ctx context.Context, progress func(models.JobProgress)) (map[string]interface{}, error) {
+		return jobResult(h.runBatch(ctx, parent, req.Operations, progress))
+	})
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	h.logger.Info("Batch job started",
+		zap.String("job_id", job.ID),
+		zap.Int("operations", len(req.Operations)),
+		zap.String("user_id", userID),
+	)
+	respondAccepted(c, job)
+}
+
+// checkBatchPath describes why an operation path is not a plain API path
+// or would start a nested batch, or returns "" for a valid path
+func checkBatchPath(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.Fragment != "" {
+		return "Operation path must be an API path"
+	}
+	if path.Clean(u.Path) != u.Path {
+		return "Operation path must not contain dot segments or repeated slashes"
+	}
+	if batchPathPattern.MatchString(u.Path) {
+		return "Batches cannot be nested"
+	}
+	return ""
+}
+
+// runBatch runs the operations in order and collects their responses
+func (h *BatchHandler) runBatch(ctx context.Context, parent *http.Request, operations []models.BatchOperation, progress func(models.JobProgress)) *models.BatchResponse {
+	response := &models.BatchResponse{Results: make([]models.BatchResult, 0, len(operations))}
+	for i, op := range operations {
+		if err := ctx.Err(); err != nil {
+			// Report the operations that did not run rather than dropping them
+			apiErr := errors.ServiceUnavailable("Batch stopped before the operation ran")
+			body, _ := json.Marshal(apiErr)
+			response.Results = append(response.Results, models.BatchResult{ID: op.ID, Status: http.StatusServiceUnavailable, Body: body})
+			response.Failed++
+			continue
+		}
+
+		result := h.runOperation(ctx, parent, i, op)
+		response.Results = append(response.Results, result)
+		if result.Status < http.StatusBadRequest {
+			response.Succeeded++
+		} else {
+			response.Failed++
+		}
+
+		if progress != nil {
+			progress(models.JobProgress{Total: len(operations), Completed: response.Succeeded, Failed: response.Failed})
+		}
+	}
+	return response
+}
+
+// runOperation dispatches one operation to the router as a request of its
+// own
+func (h *BatchHandler) runOperation(ctx context.Context, parent *http.Request, index int, op models.BatchOperation) models.BatchResult {
+	req, err := http.NewRequestWithContext(ctx, op.Method, op.Path, bytes.NewReader(op.Body))
+	if err != nil {
+		apiErr := errors.Validation("Invalid operation", nil)
+		encoded, _ := json.Marshal(apiErr)
+		return models.BatchResult{ID: op.ID, Status: http.StatusBadRequest, Body: encoded}
+	}
+	for _, name := range batchForwardedHeaders {
+		if value := parent.Header.Get(name); value != "" {
+			req.Header.Set(name, value)
+		}
+	}
+	if len(op.Body) > 0 {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if requestID := logger.RequestIDFromContext(parent.Context()); requestID != "" {
+		req.Header.Set(logger.RequestIDHeader, fmt.Sprintf("%s-%d", requestID, index))
+	}
+	req.RemoteAddr = parent.RemoteAddr
+
+	recorder := newBatchRecorder()
+	h.handler.ServeHTTP(recorder, req)
+
+	result := models.BatchResult{ID: op.ID, Status: recorder.status}
+	if recorder.body.Len() > 0 {
+		if json.Valid(recorder.body.Bytes()) {
+			result.Body = recorder.body.Bytes()
+		} else {
+			// Non-JSON responses, such as exports, are returned as a string
+			result.Body, _ = json.Marshal(recorder.body.String())
+		}
+	}
+	return result
+}
+
+// batchRecorder captures the response to one operation of a batch
+type batchRecorder struct {
+	header      http.Header
+	body        bytes.Buffer
+	status      int
+	wroteHeader bool
+}
+
+func newBatchRecorder() *batchRecorder {
+	return &batchRecorder{header: make(http.Header), status: http.StatusOK}
+}
+
+func (r *batchRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *batchRecorder) WriteHeader(status int) {
+	if r.wroteHeader {
+		return
+	}
+	r.wroteHeader = true
+	r.status = status
+}
+
+func (r *batchRecorder) Write(data []byte) (int, error) {
+	r.WriteHeader(http.StatusOK)
+	return r.body.Write(data)
+}
+
+// Flush is a no-op; the response is returned once the operation is done
+func (r *batchRecorder) Flush() {}
+
+// Hijack fails so that WebSocket upgrades are refused within a batch
+func (r *batchRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, http.ErrNotSupported
+}
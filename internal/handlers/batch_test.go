@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/apiversion"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// newBatchRouter serves the batch endpoint next to a few routes that
+// report what the operations sent them
+func newBatchRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	log, err := logger.New(logger.Config{Level: "error", Format: "json"})
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(middleware.RequestIDMiddleware())
+	router.Use(func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		c.Set("user_id", "user-1")
+	})
+
+	versions := apiversion.NewSet("v1", apiversion.Version{Name: "v1"}, apiversion.Version{Name: "v2"})
+	handler := NewBatchHandler(nil, 3, log)
+	handler.SetHandler(versions.Handler(router))
+	router.POST("/api/v1/batch", handler.Batch)
+	router.GET("/api/v1/items/:id", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"id":         c.Param("id"),
+			"space_id":   c.GetHeader("X-Space-ID"),
+			"request_id": c.GetHeader("X-Request-ID"),
+		})
+	})
+	router.POST("/api/v1/items", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.Data(http.StatusCreated, c.ContentType(), body)
+	})
+	router.GET("/api/v1/items/:id/export", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/x-ndjson", []byte("{\"a\":1}\n{\"a\":2}\n"))
+	})
+	router.GET("/api/v2/items/:id", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"id": c.Param("id"), "version": "v2"})
+	})
+	for _, route := range router.Routes() {
+		versions.Register(route.Method, route.Path)
+	}
+	return router
+}
+
+func postBatch(router http.Handler, req models.BatchRequest) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(req)
+	httpReq := httptest.NewRequest(http.MethodPost, "/api/v1/batch", bytes.NewReader(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer token-1")
+	httpReq.Header.Set("X-Space-ID", "space-1")
+	httpReq.Header.Set("X-Request-ID", "req-1")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httpReq)
+	return recorder
+}
+
+func TestBatchRunsOperationsInOrder(t *testing.T) {
+	router := newBatchRouter(t)
+
+	recorder := postBatch(router, models.BatchRequest{Operations: []models.BatchOperation{
+		{ID: "get", Method: http.MethodGet, Path: "/api/v1/items/item-1"},
+		{ID: "create", Method: http.MethodPost, Path: "/api/v1/items", Body: json.RawMessage(`{"name":"item-2"}`)},
+		{ID: "missing", Method: http.MethodGet, Path: "/api/v1/nothing"},
+	}})
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response models.BatchResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.Succeeded)
+	assert.Equal(t, 1, response.Failed)
+	require.Len(t, response.Results, 3)
+
+	// Operations inherit the caller's credentials and space headers
+	assert.Equal(t, "get", response.Results[0].ID)
+	assert.Equal(t, http.StatusOK, response.Results[0].Status)
+	assert.JSONEq(t, `{"id":"item-1","space_id":"space-1","request_id":"req-1-0"}`, string(response.Results[0].Body))
+
+	assert.Equal(t, http.StatusCreated, response.Results[1].Status)
+	assert.JSONEq(t, `{"name":"item-2"}`, string(response.Results[1].Body))
+
+	assert.Equal(t, "missing", response.Results[2].ID)
+	assert.Equal(t, http.StatusNotFound, response.Results[2].Status)
+}
+
+func TestBatchReturnsNonJSONBodiesAsStrings(t *testing.T) {
+	router := newBatchRouter(t)
+
+	recorder := postBatch(router, models.BatchRequest{Operations: []models.BatchOperation{
+		{Method: http.MethodGet, Path: "/api/v1/items/item-1/export"},
+	}})
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response models.BatchResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	var body string
+	require.NoError(t, json.Unmarshal(response.Results[0].Body, &body))
+	assert.Equal(t, "{\"a\":1}\n{\"a\":2}\n", body)
+}
+
+func TestBatchRejectsInvalidOperations(t *testing.T) {
+	router := newBatchRouter(t)
+	get := models.BatchOperation{Method: http.MethodGet, Path: "/api/v1/items/item-1"}
+
+	tests := map[string][]models.BatchOperation{
+		"nested batch":   {{Method: http.MethodPost, Path: "/api/v1/batch"}},
+		"nested v2":      {{Method: http.MethodPost, Path: "/api/v2/batch"}},
+		"unversioned":    {{Method: http.MethodPost, Path: "/api/batch"}},
+		"dot segments":   {{Method: http.MethodGet, Path: "/api/v1/items/../batch"}},
+		"absolute URL":   {{Method: http.MethodGet, Path: "/api/v1/items//evil.example.com"}},
+		"outside API":    {{Method: http.MethodGet, Path: "/metrics"}},
+		"unknown method": {{Method: "TRACE", Path: "/api/v1/items/item-1"}},
+		"too many":       {get, get, get, get},
+		"empty":          {},
+	}
+	for name, operations := range tests {
+		t.Run(name, func(t *testing.T) {
+			recorder := postBatch(router, models.BatchRequest{Operations: operations})
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		})
+	}
+}
+
+func TestBatchResolvesVersionedPaths(t *testing.T) {
+	router := newBatchRouter(t)
+
+	recorder := postBatch(router, models.BatchRequest{Operations: []models.BatchOperation{
+		{Method: http.MethodGet, Path: "/api/v2/items/item-1"},
+		// v2 serves the v1 routes whose contract did not change
+		{Method: http.MethodGet, Path: "/api/v2/items/item-1/export"},
+	}})
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response models.BatchResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Len(t, response.Results, 2)
+	assert.Equal(t, http.StatusOK, response.Results[0].Status)
+	assert.JSONEq(t, `{"id":"item-1","version":"v2"}`, string(response.Results[0].Body))
+	assert.Equal(t, http.StatusOK, response.Results[1].Status)
+}
+
+func TestBatchErrorsUseTheErrorEnvelope(t *testing.T) {
+	router := newBatchRouter(t)
+	log, err := logger.New(logger.Config{Level: "error", Format: "json"})
+	require.NoError(t, err)
+	limited := gin.New()
+	limited.Use(middleware.RequestIDMiddleware())
+	limited.Use(middleware.BodyLimit(log, 64, nil))
+	limited.Use(func(c *gin.Context) { c.Set("user_id", "user-1") })
+	limited.POST("/api/v1/batch", NewBatchHandler(nil, 3, log).Batch)
+
+	recorder := postBatch(router, models.BatchRequest{})
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	var apiErr errors.APIError
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &apiErr))
+	assert.Equal(t, "req-1", apiErr.RequestID)
+	assert.NotEmpty(t, apiErr.ErrorCode)
+
+	// A body without a Content-Length is cut off while the handler reads it
+	body, _ := json.Marshal(models.BatchRequest{Operations: []models.BatchOperation{
+		{Method: http.MethodGet, Path: "/api/v1/items/item-1"},
+		{Method: http.MethodGet, Path: "/api/v1/items/item-2"},
+	}})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/batch", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.ContentLength = -1
+	recorder = httptest.NewRecorder()
+	limited.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code, "an oversized batch is rejected as too large")
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &apiErr))
+	assert.Equal(t, errors.CodePayloadTooLarge, apiErr.ErrorCode)
+}
@@ -3,7 +3,6 @@ package handlers
 import (
 	"fmt"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -11,7 +10,9 @@ import (
 
 	"github.com/Tributary-ai-services/aether-be/internal/database"
 	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
 	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
 	"github.com/Tributary-ai-services/aether-be/internal/services"
 	"github.com/Tributary-ai-services/aether-be/pkg/errors"
 )
@@ -21,6 +22,7 @@ type ChunkHandler struct {
 	db             *database.Neo4jClient
 	chunkService   *services.ChunkService
 	audiModalService *services.AudiModalService
+	redaction      *services.RedactionService
 	logger         *logger.Logger
 }
 
@@ -34,8 +36,49 @@ func NewChunkHandler(db *database.Neo4jClient, chunkService *services.ChunkServi
 	}
 }
 
+// SetRedactionService enforces the redaction rules of shared notebooks on
+// the chunks of their documents
+func (h *ChunkHandler) SetRedactionService(redaction *services.RedactionService) {
+	h.redaction = redaction
+}
+
+// redactionRules returns the redaction rules that apply to the user reading
+// the chunks of a file, or nil when they read them all
+func (h *ChunkHandler) redactionRules(c *gin.Context, fileID, userID string, spaceContext *models.SpaceContext) (*models.RedactionRules, error) {
+	if h.redaction == nil {
+		return nil, nil
+	}
+	return h.redaction.RulesForFile(c.Request.Context(), fileID, userID, spaceContext)
+}
+
+// StrategyRecommendationRequest describes a file to recommend a chunking
+// strategy for
+type StrategyRecommendationRequest struct {
+	ContentType string `json:"content_type" binding:"required"`
+	FileSize    int64  `json:"file_size" binding:"required,min=0"`
+	Complexity  string `json:"complexity,omitempty"`
+}
+
+// ReprocessFileRequest selects the chunking strategy to reprocess a file with
+type ReprocessFileRequest struct {
+	Strategy       string                 `json:"strategy" binding:"required"`
+	StrategyConfig map[string]interface{} `json:"strategy_config,omitempty"`
+}
+
 // GetFileChunks retrieves all chunks for a specific file
-// GET /api/v1/tenants/:tenant_id/files/:file_id/chunks
+// @Summary List file chunks
+// @Description Get the chunks AudiModal produced for a file. Users the file's notebook is shared with at a redacted role don't get the chunks its redaction rules omit; redacted counts those left out of the page.
+// @Tags chunks
+// @Produce json
+// @Security Bearer
+// @Param file_id path string true "File ID"
+// @Param limit query int false "Number of chunks to return"
+// @Param offset query int false "Number of chunks to skip"
+// @Success 200 {object} models.ChunkListResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 500 {object} errors.APIError
+// @Router /api/v1/files/{file_id}/chunks [get]
 func (h *ChunkHandler) GetFileChunks(c *gin.Context) {
 	// Get space context
 	spaceCtx, exists := c.Get("space_context")
@@ -53,20 +96,8 @@ func (h *ChunkHandler) GetFileChunks(c *gin.Context) {
 	}
 
 	// Get pagination parameters
-	limit := 50 // default
-	offset := 0 // default
-
-	if limitStr := c.Query("limit"); limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
-			limit = l
-		}
-	}
-
-	if offsetStr := c.Query("offset"); offsetStr != "" {
-		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
-			offset = o
-		}
-	}
+	page := parsePaginationParams(c, 50)
+	limit, offset := page.Limit, page.Offset
 
 	// Get user ID from context
 	userID, exists := c.Get("user_id")
@@ -111,25 +142,49 @@ func (h *ChunkHandler) GetFileChunks(c *gin.Context) {
 		return
 	}
 
-	// Convert to response format
-	chunkResponses := make([]*models.ChunkResponse, len(chunks.Data))
-	for i, chunk := range chunks.Data {
-		chunkResponses[i] = convertAudiModalChunkToResponse(chunk)
+	rules, err := h.redactionRules(c, fileID, userID.(string), spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	// Convert to response format, leaving out the chunks redacted for the user
+	chunkResponses := make([]*models.ChunkResponse, 0, len(chunks.Data))
+	redacted := 0
+	for _, chunk := range chunks.Data {
+		if rules != nil && rules.Omits(chunk.PIIDetected, models.ChunkSection(chunk.Context, chunk.Metadata)) {
+			redacted++
+			continue
+		}
+		chunkResponses = append(chunkResponses, convertAudiModalChunkToResponse(chunk))
 	}
 
 	response := &models.ChunkListResponse{
-		Chunks:  chunkResponses,
-		Total:   chunks.Total,
-		Limit:   limit,
-		Offset:  offset,
-		HasMore: offset+limit < chunks.Total,
+		Chunks:   chunkResponses,
+		Total:    chunks.Total,
+		Limit:    limit,
+		Offset:   offset,
+		HasMore:  pagination.HasMore(offset, len(chunks.Data), chunks.Total),
+		Redacted: redacted,
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
 // GetChunk retrieves a specific chunk by ID
-// GET /api/v1/tenants/:tenant_id/files/:file_id/chunks/:chunk_id
+// @Summary Get chunk
+// @Description Get one chunk of a file
+// @Tags chunks
+// @Produce json
+// @Security Bearer
+// @Param file_id path string true "File ID"
+// @Param chunk_id path string true "Chunk ID"
+// @Success 200 {object} models.ChunkResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 500 {object} errors.APIError
+// @Router /api/v1/files/{file_id}/chunks/{chunk_id} [get]
 func (h *ChunkHandler) GetChunk(c *gin.Context) {
 	// Get space context
 	spaceCtx, exists := c.Get("space_context")
@@ -193,13 +248,38 @@ func (h *ChunkHandler) GetChunk(c *gin.Context) {
 		return
 	}
 
+	rules, err := h.redactionRules(c, fileID, userID.(string), spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+	if rules != nil && rules.Omits(chunk.PIIDetected, models.ChunkSection(chunk.Context, chunk.Metadata)) {
+		// A redacted chunk is not revealed to exist
+		apiErr := errors.ChunkNotFoundWithDetails("Chunk not found", map[string]interface{}{
+			"file_id":  fileID,
+			"chunk_id": chunkID,
+		})
+		c.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
 	// Convert to response format
 	response := convertAudiModalChunkToResponse(*chunk)
 	c.JSON(http.StatusOK, response)
 }
 
 // SearchChunks searches for chunks across files
-// POST /api/v1/tenants/:tenant_id/chunks/search
+// @Summary Search chunks
+// @Description Search chunks across the files of the space
+// @Tags chunks
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body models.ChunkSearchRequest true "Search filters"
+// @Success 200 {object} models.ChunkListResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Router /api/v1/chunks/search [post]
 func (h *ChunkHandler) SearchChunks(c *gin.Context) {
 	// Get space context
 	spaceCtx, exists := c.Get("space_context")
@@ -217,12 +297,7 @@ func (h *ChunkHandler) SearchChunks(c *gin.Context) {
 	}
 
 	// Validate search request
-	if searchReq.Limit <= 0 || searchReq.Limit > 100 {
-		searchReq.Limit = 20
-	}
-	if searchReq.Offset < 0 {
-		searchReq.Offset = 0
-	}
+	searchReq.Limit, searchReq.Offset = pagination.Clamp(searchReq.Limit, searchReq.Offset)
 
 	// Get user ID from context
 	userID, exists := c.Get("user_id")
@@ -251,7 +326,14 @@ func (h *ChunkHandler) SearchChunks(c *gin.Context) {
 }
 
 // GetAvailableStrategies retrieves available chunking strategies
-// GET /api/v1/strategies
+// @Summary List chunking strategies
+// @Description Get the chunking strategies AudiModal supports
+// @Tags chunks
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} errors.APIError
+// @Router /api/v1/strategies [get]
 func (h *ChunkHandler) GetAvailableStrategies(c *gin.Context) {
 	h.logger.Info("Fetching available chunking strategies")
 
@@ -311,13 +393,20 @@ func (h *ChunkHandler) GetAvailableStrategies(c *gin.Context) {
 }
 
 // GetOptimalStrategy gets recommended strategy for file characteristics
-// POST /api/v1/strategies/recommend
+// @Summary Recommend chunking strategy
+// @Description Recommend a chunking strategy for a file's content type and size
+// @Tags chunks
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body StrategyRecommendationRequest true "File characteristics"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 502 {object} errors.APIError
+// @Router /api/v1/strategies/recommend [post]
 func (h *ChunkHandler) GetOptimalStrategy(c *gin.Context) {
-	var request struct {
-		ContentType string `json:"content_type" binding:"required"`
-		FileSize    int64  `json:"file_size" binding:"required,min=0"`
-		Complexity  string `json:"complexity,omitempty"`
-	}
+	var request StrategyRecommendationRequest
 
 	if err := c.ShouldBindJSON(&request); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
@@ -370,7 +459,21 @@ func (h *ChunkHandler) GetOptimalStrategy(c *gin.Context) {
 }
 
 // ReprocessFileWithStrategy reprocesses a file with a different chunking strategy
-// POST /api/v1/tenants/:tenant_id/files/:file_id/reprocess
+// @Summary Reprocess file
+// @Description Reprocess a file with a different chunking strategy
+// @Tags chunks
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param file_id path string true "File ID"
+// @Param request body ReprocessFileRequest true "Chunking strategy"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 409 {object} errors.APIError
+// @Failure 502 {object} errors.APIError
+// @Router /api/v1/files/{file_id}/reprocess [post]
 func (h *ChunkHandler) ReprocessFileWithStrategy(c *gin.Context) {
 	// Get space context
 	spaceCtx, exists := c.Get("space_context")
@@ -388,10 +491,7 @@ func (h *ChunkHandler) ReprocessFileWithStrategy(c *gin.Context) {
 	}
 
 	// Parse request
-	var request struct {
-		Strategy       string                 `json:"strategy" binding:"required"`
-		StrategyConfig map[string]interface{} `json:"strategy_config,omitempty"`
-	}
+	var request ReprocessFileRequest
 
 	if err := c.ShouldBindJSON(&request); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
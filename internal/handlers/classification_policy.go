@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// ClassificationHandler manages the classification policies that route
+// processed documents
+type ClassificationHandler struct {
+	policyService *services.ClassificationPolicyService
+	logger        *logger.Logger
+}
+
+// NewClassificationHandler creates a new classification policy handler
+func NewClassificationHandler(policyService *services.ClassificationPolicyService, log *logger.Logger) *ClassificationHandler {
+	return &ClassificationHandler{
+		policyService: policyService,
+		logger:        log.WithService("classification_handler"),
+	}
+}
+
+// CreateClassificationPolicy creates a classification policy
+// @Summary Create a classification policy
+// @Description Create a classification policy in the current space. Once a document of the space is processed, every enabled policy whose conditions all hold for it is applied, in priority order (lowest first): it can move the document to another notebook of the space, tag it, or restrict it to its owner and the space's owners and admins. Conditions test the AudiModal category, content type, language and PII detection, and the document's MIME type, extension, name and tags. Fails with 400 and AETHER-POLICY-002 when a condition lacks its value, the policy has no actions or the target notebook is not in the space. Requires the owner or admin role.
+// @Tags classification
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body models.ClassificationPolicyCreateRequest true "Policy"
+// @Success 201 {object} models.ClassificationPolicy
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Router /api/v1/classification-policies [post]
+func (h *ClassificationHandler) CreateClassificationPolicy(c *gin.Context) {
+	userID, spaceContext, ok := requireUserAndSpace(c, h.logger)
+	if !ok {
+		return
+	}
+
+	var req models.ClassificationPolicyCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Invalid request body", err))
+		return
+	}
+	if err := validateStruct(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Validation failed", err))
+		return
+	}
+
+	policy, err := h.policyService.CreatePolicy(c.Request.Context(), req, userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, policy)
+}
+
+// ListClassificationPolicies lists the classification policies of the
+// current space
+// @Summary List classification policies
+// @Description List the classification policies of the current space in the order they run.
+// @Tags classification
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.ClassificationPolicyListResponse
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Router /api/v1/classification-policies [get]
+func (h *ClassificationHandler) ListClassificationPolicies(c *gin.Context) {
+	_, spaceContext, ok := requireUserAndSpace(c, h.logger)
+	if !ok {
+		return
+	}
+
+	policies, err := h.policyService.ListPolicies(c.Request.Context(), spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ClassificationPolicyListResponse{Policies: policies})
+}
+
+// GetClassificationPolicy returns a classification policy
+// @Summary Get a classification policy
+// @Description Get a classification policy of the current space.
+// @Tags classification
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Policy ID"
+// @Success 200 {object} models.ClassificationPolicy
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/classification-policies/{id} [get]
+func (h *ClassificationHandler) GetClassificationPolicy(c *gin.Context) {
+	_, spaceContext, ok := requireUserAndSpace(c, h.logger)
+	if !ok {
+		return
+	}
+
+	policy, err := h.policyService.GetPolicy(c.Request.Context(), c.Param("id"), spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// UpdateClassificationPolicy changes a classification policy
+// @Summary Update a classification policy
+// @Description Change the given fields of a classification policy; conditions and actions are replaced whole. Documents already processed are not reclassified. Requires the owner or admin role.
+// @Tags classification
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Policy ID"
+// @Param request body models.ClassificationPolicyUpdateRequest true "Changes"
+// @Success 200 {object} models.ClassificationPolicy
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/classification-policies/{id} [put]
+func (h *ClassificationHandler) UpdateClassificationPolicy(c *gin.Context) {
+	_, spaceContext, ok := requireUserAndSpace(c, h.logger)
+	if !ok {
+		return
+	}
+
+	var req models.ClassificationPolicyUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Invalid request body", err))
+		return
+	}
+	if err := validateStruct(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Validation failed", err))
+		return
+	}
+
+	policy, err := h.policyService.UpdatePolicy(c.Request.Context(), c.Param("id"), req, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// DeleteClassificationPolicy deletes a classification policy
+// @Summary Delete a classification policy
+// @Description Delete a classification policy. Documents it already moved, tagged or restricted are left as they are. Requires the owner or admin role.
+// @Tags classification
+// @Security Bearer
+// @Param id path string true "Policy ID"
+// @Success 204
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/classification-policies/{id} [delete]
+func (h *ClassificationHandler) DeleteClassificationPolicy(c *gin.Context) {
+	_, spaceContext, ok := requireUserAndSpace(c, h.logger)
+	if !ok {
+		return
+	}
+
+	if err := h.policyService.DeletePolicy(c.Request.Context(), c.Param("id"), spaceContext); err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// EvaluateClassificationPolicies reports what policies would do without
+// applying them
+// @Summary Evaluate classification policies
+// @Description Dry run: report what the space's enabled policies, or the unsaved policy given, would do to the documents given or to the processed documents of a notebook (up to 100). Nothing is changed. Requires the owner or admin role.
+// @Tags classification
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body models.ClassificationEvaluateRequest true "Documents and policy"
+// @Success 200 {object} models.ClassificationEvaluateResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Router /api/v1/classification-policies/evaluate [post]
+func (h *ClassificationHandler) EvaluateClassificationPolicies(c *gin.Context) {
+	userID, spaceContext, ok := requireUserAndSpace(c, h.logger)
+	if !ok {
+		return
+	}
+
+	var req models.ClassificationEvaluateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Invalid request body", err))
+		return
+	}
+	if err := validateStruct(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Validation failed", err))
+		return
+	}
+
+	response, err := h.policyService.Evaluate(c.Request.Context(), req, userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// LiftDocumentRestriction makes a restricted document visible to the space
+// again
+// @Summary Lift a document restriction
+// @Description Make a document restricted by a classification policy visible to every member of the space again. A policy that restricts it does so again if the document is reprocessed. Requires the owner or admin role.
+// @Tags classification
+// @Security Bearer
+// @Param id path string true "Document ID"
+// @Success 204
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/documents/{id}/restriction [delete]
+func (h *ClassificationHandler) LiftDocumentRestriction(c *gin.Context) {
+	_, spaceContext, ok := requireUserAndSpace(c, h.logger)
+	if !ok {
+		return
+	}
+
+	if err := h.policyService.LiftRestriction(c.Request.Context(), c.Param("id"), spaceContext); err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
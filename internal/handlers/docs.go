@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tributary-ai-services/aether-be/internal/openapi"
+)
+
+// swaggerUIPage renders the OpenAPI document with Swagger UI, loaded from
+// the unpkg CDN so the server does not have to ship its assets
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Aether API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.ui = SwaggerUIBundle({
+      url: "/api/v1/openapi.json",
+      dom_id: "#swagger-ui",
+      persistAuthorization: true
+    });
+  </script>
+</body>
+</html>
+`
+
+// DocsHandler serves the generated OpenAPI document and Swagger UI
+type DocsHandler struct{}
+
+// NewDocsHandler creates a new docs handler
+func NewDocsHandler() *DocsHandler {
+	return &DocsHandler{}
+}
+
+// GetOpenAPISpec returns the OpenAPI document
+// @Summary Get OpenAPI document
+// @Description Get the OpenAPI 3.1 document describing this API
+// @Tags docs
+// @Produce json
+// @Success 200 {object} object
+// @Router /api/v1/openapi.json [get]
+func (h *DocsHandler) GetOpenAPISpec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json", openapi.Spec())
+}
+
+// SwaggerUI serves an interactive viewer for the OpenAPI document
+// @Summary Browse API documentation
+// @Description Swagger UI for the OpenAPI document
+// @Tags docs
+// @Produce html
+// @Success 200 {string} string
+// @Router /api/v1/docs [get]
+func (h *DocsHandler) SwaggerUI(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerUIPage))
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/openapi"
+)
+
+// undocumentedRoutes are served outside the public API
+var undocumentedRoutes = map[string]bool{
+	"GET /metrics":            true,
+	"GET /metrics/prometheus": true,
+}
+
+var ginParamPattern = regexp.MustCompile(`[:*](\w+)`)
+
+// TestOpenAPISpecCoversRoutes fails when a route is added without @Router
+// annotations, or an annotation names a route that does not exist
+func TestOpenAPISpecCoversRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log, err := logger.New(logger.Config{Level: "error", Format: "json"})
+	require.NoError(t, err)
+	server := &APIServer{Router: gin.New(), RouterHandler: &RouterHandler{}, logger: log}
+	server.setupRoutes(nil)
+
+	var doc openapi.Document
+	require.NoError(t, json.Unmarshal(openapi.Spec(), &doc))
+
+	documented := make(map[string]bool)
+	for path, item := range doc.Paths {
+		for method := range item {
+			documented[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+
+	var missing []string
+	for _, route := range server.Router.Routes() {
+		key := route.Method + " " + ginParamPattern.ReplaceAllString(route.Path, "{$1}")
+		if undocumentedRoutes[key] {
+			continue
+		}
+		if !documented[key] {
+			missing = append(missing, key)
+		}
+		delete(documented, key)
+	}
+
+	var stale []string
+	for key := range documented {
+		stale = append(stale, key)
+	}
+	sort.Strings(missing)
+	sort.Strings(stale)
+	assert.Empty(t, missing, "routes without @Router annotations")
+	assert.Empty(t, stale, "@Router annotations without routes")
+}
@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -14,7 +16,9 @@ import (
 	"github.com/Tributary-ai-services/aether-be/internal/logger"
 	"github.com/Tributary-ai-services/aether-be/internal/middleware"
 	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/reports"
 	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/internal/webhooks"
 	"github.com/Tributary-ai-services/aether-be/pkg/errors"
 )
 
@@ -22,7 +26,16 @@ import (
 type DocumentHandler struct {
 	documentService   *services.DocumentService
 	audiModalService  *services.AudiModalService
+	jobService        *services.JobService
+	vectorSync        *services.VectorSyncService
+	similar           *services.SimilarDocumentService
+	related           *services.RelatedDocumentService
+	comparison        *services.DocumentComparisonService
+	redaction         *services.RedactionService
+	watermark         *services.WatermarkService
 	logger            *logger.Logger
+	maxUploadBytes    int64
+	maxBulkFiles      int
 }
 
 // NewDocumentHandler creates a new document handler
@@ -31,9 +44,92 @@ func NewDocumentHandler(documentService *services.DocumentService, audiModalServ
 		documentService:  documentService,
 		audiModalService: audiModalService,
 		logger:           log.WithService("document_handler"),
+		maxUploadBytes:   100 << 20,
+		maxBulkFiles:     20,
 	}
 }
 
+// SetMaxUploadBytes sets the largest file accepted by the upload endpoints
+func (h *DocumentHandler) SetMaxUploadBytes(limit int64) {
+	h.maxUploadBytes = limit
+}
+
+// SetJobService enables exports that run as async jobs
+func (h *DocumentHandler) SetJobService(jobService *services.JobService) {
+	h.jobService = jobService
+}
+
+// SetVectorSyncService enables the vector sync endpoints; without it they
+// respond 503
+func (h *DocumentHandler) SetVectorSyncService(vectorSync *services.VectorSyncService) {
+	h.vectorSync = vectorSync
+}
+
+// SetSimilarDocumentService enables the similar documents endpoint
+func (h *DocumentHandler) SetSimilarDocumentService(similar *services.SimilarDocumentService) {
+	h.similar = similar
+}
+
+// SetRelatedDocumentService enables the related documents endpoint
+func (h *DocumentHandler) SetRelatedDocumentService(related *services.RelatedDocumentService) {
+	h.related = related
+}
+
+// SetDocumentComparisonService enables the document comparison endpoint
+func (h *DocumentHandler) SetDocumentComparisonService(comparison *services.DocumentComparisonService) {
+	h.comparison = comparison
+}
+
+// SetRedactionService enforces the redaction rules of shared notebooks on
+// the extracted text of their documents
+func (h *DocumentHandler) SetRedactionService(redaction *services.RedactionService) {
+	h.redaction = redaction
+}
+
+// SetWatermarkService serves users a notebook is shared with view-only
+// watermarked renditions of its documents instead of their original files
+func (h *DocumentHandler) SetWatermarkService(watermark *services.WatermarkService) {
+	h.watermark = watermark
+}
+
+// viewOnlyDocument returns the document when the user gets watermarked
+// renditions of it instead of its original file, and nil when they get the
+// original. It writes the error response and returns false when the check
+// fails.
+func (h *DocumentHandler) viewOnlyDocument(c *gin.Context, documentID, userID string, spaceContext *models.SpaceContext) (*models.Document, bool) {
+	if h.watermark == nil {
+		return nil, true
+	}
+	document, err := h.documentService.GetDocumentByID(c.Request.Context(), documentID, userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return nil, false
+	}
+	viewOnly, err := h.watermark.ViewOnly(c.Request.Context(), document, userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return nil, false
+	}
+	if !viewOnly {
+		return nil, true
+	}
+	return document, true
+}
+
+// redactionRules returns the redaction rules that apply to the user reading
+// a document, or nil when they read it whole
+func (h *DocumentHandler) redactionRules(c *gin.Context, document *models.Document, userID string, spaceContext *models.SpaceContext) (*models.RedactionRules, error) {
+	if h.redaction == nil {
+		return nil, nil
+	}
+	return h.redaction.RulesFor(c.Request.Context(), document, userID, spaceContext)
+}
+
+// fileTooLarge returns the error for an upload over the file size limit
+func (h *DocumentHandler) fileTooLarge() *errors.APIError {
+	return errors.Validation(fmt.Sprintf("File too large (max %s)", formatByteLimit(h.maxUploadBytes)), nil).WithErrorCode(errors.CodeFileTooLarge)
+}
+
 // CreateDocument creates a new document record in Neo4j (without file upload)
 // @Summary Create a new document record
 // @Description Create a document record in Neo4j, typically after external upload to AudiModal
@@ -45,19 +141,20 @@ func NewDocumentHandler(documentService *services.DocumentService, audiModalServ
 // @Success 201 {object} models.DocumentResponse
 // @Failure 400 {object} errors.APIError
 // @Failure 401 {object} errors.APIError
+// @Failure 402 {object} errors.APIError
 // @Failure 500 {object} errors.APIError
 // @Router /api/v1/documents [post]
 func (h *DocumentHandler) CreateDocument(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -91,7 +188,7 @@ func (h *DocumentHandler) CreateDocument(c *gin.Context) {
 	document, err := h.documentService.CreateDocument(c.Request.Context(), req, userID, spaceContext, fileInfo)
 	if err != nil {
 		h.logger.Error("Failed to create document", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -100,7 +197,7 @@ func (h *DocumentHandler) CreateDocument(c *gin.Context) {
 
 // UploadDocument uploads a new document
 // @Summary Upload document
-// @Description Upload a new document to a notebook
+// @Description Upload a new document to a notebook. In spaces that scan uploads for malware, infected files are rejected with 422 and AETHER-SCAN-001 and quarantined; when the scanner is unavailable uploads fail with 503 and AETHER-SCAN-002. Files whose content contradicts their declared MIME type or extension fail with 422 and AETHER-FILE-001, files of a type the deployment or the space does not allow with 415 and AETHER-FILE-002, and files over the space's size limit with 400 and AETHER-DOC-003. A missing or generic MIME type is replaced by the detected one.
 // @Tags documents
 // @Accept multipart/form-data
 // @Produce json
@@ -109,12 +206,17 @@ func (h *DocumentHandler) CreateDocument(c *gin.Context) {
 // @Param name formData string false "Document name (optional, will use filename if not provided)"
 // @Param description formData string false "Document description"
 // @Param tags formData []string false "Document tags"
+// @Param priority formData string false "Processing priority tier, up to the cap of the space's plan" Enums(low, normal, high, realtime)
 // @Param file formData file true "Document file"
 // @Success 201 {object} models.DocumentResponse
 // @Failure 400 {object} errors.APIError
 // @Failure 401 {object} errors.APIError
+// @Failure 402 {object} errors.APIError
 // @Failure 413 {object} errors.APIError
+// @Failure 415 {object} errors.APIError
+// @Failure 422 {object} errors.APIError
 // @Failure 500 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
 // @Router /api/v1/documents/upload [post]
 func (h *DocumentHandler) UploadDocument(c *gin.Context) {
 	h.logger.Info("=== UPLOAD HANDLER START ===", 
@@ -127,16 +229,26 @@ func (h *DocumentHandler) UploadDocument(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
 		h.logger.Error("Upload failed: User not authenticated")
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 	
 	h.logger.Info("Processing upload for user", zap.String("user_id", userID))
 
-	// Parse multipart form
-	h.logger.Info("About to parse multipart form")
-	err := c.Request.ParseMultipartForm(128 << 20) // 128MB max memory
-	if err != nil {
+	// Stream the multipart form so oversized files are rejected early
+	form, err := readUploadForm(c.Request, h.maxUploadBytes)
+	switch {
+	case err == errFileTooLarge:
+		c.JSON(http.StatusRequestEntityTooLarge, h.fileTooLarge())
+		return
+	case middleware.IsBodyTooLarge(err):
+		middleware.WriteError(c, h.logger, err)
+		return
+	case err == http.ErrMissingFile:
+		h.logger.Error("Failed to get uploaded file", zap.Error(err))
+		c.JSON(http.StatusBadRequest, errors.Validation("File is required", err))
+		return
+	case err != nil:
 		h.logger.Error("Failed to parse multipart form", zap.Error(err))
 		c.JSON(http.StatusBadRequest, errors.Validation("Invalid multipart form", err))
 		return
@@ -144,10 +256,10 @@ func (h *DocumentHandler) UploadDocument(c *gin.Context) {
 	h.logger.Info("Multipart form parsed successfully")
 
 	// Get form values
-	notebookID := c.PostForm("notebook_id")
-	name := c.PostForm("name")
-	description := c.PostForm("description")
-	tagsStr := c.PostForm("tags")
+	notebookID := form.fields["notebook_id"]
+	name := form.fields["name"]
+	description := form.fields["description"]
+	tagsStr := form.fields["tags"]
 	
 	h.logger.Info("Form values extracted", 
 		zap.String("notebook_id", notebookID),
@@ -170,34 +282,11 @@ func (h *DocumentHandler) UploadDocument(c *gin.Context) {
 		}
 	}
 
-	// Get uploaded file
-	file, header, err := c.Request.FormFile("file")
-	if err != nil {
-		h.logger.Error("Failed to get uploaded file", zap.Error(err))
-		c.JSON(http.StatusBadRequest, errors.Validation("File is required", err))
-		return
-	}
-	defer file.Close()
-
 	// Use filename as name if not provided
 	if name == "" {
-		name = header.Filename
-	}
-
-	// Read file data
-	fileData, err := io.ReadAll(file)
-	if err != nil {
-		h.logger.Error("Failed to read file data", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, errors.Internal("Failed to read file"))
-		return
-	}
-
-	// Check file size (100MB limit)
-	const maxFileSize = 100 << 20 // 100MB
-	if len(fileData) > maxFileSize {
-		c.JSON(http.StatusRequestEntityTooLarge, errors.Validation("File too large (max 100MB)", nil))
-		return
+		name = form.fileName
 	}
+	fileData := form.fileData
 
 	// Create upload request
 	req := models.DocumentUploadRequest{
@@ -206,14 +295,15 @@ func (h *DocumentHandler) UploadDocument(c *gin.Context) {
 			Description: description,
 			NotebookID:  notebookID,
 			Tags:        tags,
+			Priority:    form.fields["priority"],
 		},
 		FileData: fileData,
 	}
 	
 	// Create file info with proper MIME type from multipart form
 	fileInfo := models.FileInfo{
-		OriginalName: header.Filename,
-		MimeType:     header.Header.Get("Content-Type"),
+		OriginalName: form.fileName,
+		MimeType:     form.mimeType,
 		SizeBytes:    int64(len(fileData)),
 		Checksum:     "", // TODO: Calculate checksum if needed
 	}
@@ -232,7 +322,7 @@ func (h *DocumentHandler) UploadDocument(c *gin.Context) {
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
 		h.logger.Error("Failed to get space context", zap.Error(err))
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 	h.logger.Info("Space context retrieved", 
@@ -250,7 +340,7 @@ func (h *DocumentHandler) UploadDocument(c *gin.Context) {
 	h.logger.Info("documentService.UploadDocument call completed", zap.Bool("has_error", err != nil))
 	if err != nil {
 		h.logger.Error("Failed to upload document", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 	
@@ -262,7 +352,7 @@ func (h *DocumentHandler) UploadDocument(c *gin.Context) {
 
 // UploadDocumentBase64 uploads a document using base64 encoded content
 // @Summary Upload document (base64)
-// @Description Upload a new document using base64 encoded content
+// @Description Upload a new document using base64 encoded content. In spaces that scan uploads for malware, infected files are rejected with 422 and AETHER-SCAN-001 and quarantined; when the scanner is unavailable uploads fail with 503 and AETHER-SCAN-002. Files whose content contradicts their declared MIME type or extension fail with 422 and AETHER-FILE-001, files of a type the deployment or the space does not allow with 415 and AETHER-FILE-002, and files over the space's size limit with 400 and AETHER-DOC-003. A missing or generic MIME type is replaced by the detected one.
 // @Tags documents
 // @Accept json
 // @Produce json
@@ -271,18 +361,26 @@ func (h *DocumentHandler) UploadDocument(c *gin.Context) {
 // @Success 201 {object} models.DocumentResponse
 // @Failure 400 {object} errors.APIError
 // @Failure 401 {object} errors.APIError
+// @Failure 402 {object} errors.APIError
 // @Failure 413 {object} errors.APIError
+// @Failure 415 {object} errors.APIError
+// @Failure 422 {object} errors.APIError
 // @Failure 500 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
 // @Router /api/v1/documents/upload-base64 [post]
 func (h *DocumentHandler) UploadDocumentBase64(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	var req models.DocumentBase64UploadRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
+		if middleware.IsBodyTooLarge(err) {
+			middleware.WriteError(c, h.logger, err)
+			return
+		}
 		h.logger.Error("Failed to bind JSON request", zap.Error(err))
 		c.JSON(http.StatusBadRequest, errors.Validation("Invalid request format", err))
 		return
@@ -303,10 +401,8 @@ func (h *DocumentHandler) UploadDocumentBase64(c *gin.Context) {
 		return
 	}
 
-	// Check file size (100MB limit)
-	const maxFileSize = 100 << 20 // 100MB
-	if len(fileData) > maxFileSize {
-		c.JSON(http.StatusRequestEntityTooLarge, errors.Validation("File too large (max 100MB)", nil))
+	if int64(len(fileData)) > h.maxUploadBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, h.fileTooLarge())
 		return
 	}
 
@@ -346,14 +442,14 @@ func (h *DocumentHandler) UploadDocumentBase64(c *gin.Context) {
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
 		h.logger.Error("Failed to get space context", zap.Error(err))
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
 	document, err := h.documentService.UploadDocument(c.Request.Context(), uploadReq, userID, spaceContext, fileInfo)
 	if err != nil {
 		h.logger.Error("Failed to upload document", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -383,7 +479,7 @@ func (h *DocumentHandler) UploadDocumentBase64(c *gin.Context) {
 func (h *DocumentHandler) GetDocument(c *gin.Context) {
 	documentID := c.Param("id")
 	if documentID == "" {
-		c.JSON(http.StatusBadRequest, errors.Validation("Document ID is required", nil))
+		c.JSON(http.StatusBadRequest, errors.Validation("Document ID is required", nil).WithErrorCode(errors.CodeDocumentIDRequired))
 		return
 	}
 
@@ -392,18 +488,29 @@ func (h *DocumentHandler) GetDocument(c *gin.Context) {
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 	
 	document, err := h.documentService.GetDocumentByID(c.Request.Context(), documentID, userID, spaceContext)
 	if err != nil {
 		h.logger.Error("Failed to get document", zap.String("document_id", documentID), zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, document.ToResponse())
+	rules, err := h.redactionRules(c, document, userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+	response := document.ToResponse()
+	if rules != nil {
+		// The redacted text is served by the extracted text endpoint
+		response.ExtractedText = ""
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 // GetDocumentStatus gets the processing status of a document
@@ -422,20 +529,20 @@ func (h *DocumentHandler) GetDocument(c *gin.Context) {
 func (h *DocumentHandler) GetDocumentStatus(c *gin.Context) {
 	documentID := c.Param("id")
 	if documentID == "" {
-		c.JSON(http.StatusBadRequest, errors.Validation("Document ID is required", nil))
+		c.JSON(http.StatusBadRequest, errors.Validation("Document ID is required", nil).WithErrorCode(errors.CodeDocumentIDRequired))
 		return
 	}
 
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -443,7 +550,7 @@ func (h *DocumentHandler) GetDocumentStatus(c *gin.Context) {
 	document, err := h.documentService.GetDocumentByID(c.Request.Context(), documentID, userID, spaceContext)
 	if err != nil {
 		h.logger.Error("Failed to get document for status check", zap.String("document_id", documentID), zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -510,13 +617,13 @@ func calculateProgress(status string) float64 {
 func (h *DocumentHandler) UpdateDocument(c *gin.Context) {
 	documentID := c.Param("id")
 	if documentID == "" {
-		c.JSON(http.StatusBadRequest, errors.Validation("Document ID is required", nil))
+		c.JSON(http.StatusBadRequest, errors.Validation("Document ID is required", nil).WithErrorCode(errors.CodeDocumentIDRequired))
 		return
 	}
 
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
@@ -536,14 +643,14 @@ func (h *DocumentHandler) UpdateDocument(c *gin.Context) {
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
 	document, err := h.documentService.UpdateDocument(c.Request.Context(), documentID, req, userID, spaceContext)
 	if err != nil {
 		h.logger.Error("Failed to update document", zap.String("document_id", documentID), zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -552,7 +659,7 @@ func (h *DocumentHandler) UpdateDocument(c *gin.Context) {
 
 // DeleteDocument deletes a document
 // @Summary Delete document
-// @Description Delete a document (soft delete)
+// @Description Move a document to the trash. It can be restored from /api/v1/trash until it is purged, with its files, TRASH_RETENTION_DAYS after deletion.
 // @Tags documents
 // @Accept json
 // @Produce json
@@ -568,27 +675,27 @@ func (h *DocumentHandler) UpdateDocument(c *gin.Context) {
 func (h *DocumentHandler) DeleteDocument(c *gin.Context) {
 	documentID := c.Param("id")
 	if documentID == "" {
-		c.JSON(http.StatusBadRequest, errors.Validation("Document ID is required", nil))
+		c.JSON(http.StatusBadRequest, errors.Validation("Document ID is required", nil).WithErrorCode(errors.CodeDocumentIDRequired))
 		return
 	}
 
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
 	err = h.documentService.DeleteDocument(c.Request.Context(), documentID, userID, spaceContext)
 	if err != nil {
 		h.logger.Error("Failed to delete document", zap.String("document_id", documentID), zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -603,6 +710,7 @@ func (h *DocumentHandler) DeleteDocument(c *gin.Context) {
 // @Produce json
 // @Security Bearer
 // @Param id path string true "Document ID"
+// @Param priority query string false "Processing priority tier, up to the cap of the space's plan; defaults to the tier the document was last submitted with" Enums(low, normal, high, realtime)
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} errors.APIError
 // @Failure 401 {object} errors.APIError
@@ -613,20 +721,20 @@ func (h *DocumentHandler) DeleteDocument(c *gin.Context) {
 func (h *DocumentHandler) ReprocessDocument(c *gin.Context) {
 	documentID := c.Param("id")
 	if documentID == "" {
-		c.JSON(http.StatusBadRequest, errors.Validation("Document ID is required", nil))
+		c.JSON(http.StatusBadRequest, errors.Validation("Document ID is required", nil).WithErrorCode(errors.CodeDocumentIDRequired))
 		return
 	}
 
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -634,17 +742,17 @@ func (h *DocumentHandler) ReprocessDocument(c *gin.Context) {
 	document, err := h.documentService.GetDocumentByID(c.Request.Context(), documentID, userID, spaceContext)
 	if err != nil {
 		h.logger.Error("Failed to get document for reprocessing", zap.String("document_id", documentID), zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
 	// Submit reprocessing job
-	job, err := h.documentService.ReprocessDocument(c.Request.Context(), document, spaceContext)
+	job, err := h.documentService.ReprocessDocument(c.Request.Context(), document, spaceContext, c.Query("priority"))
 	if err != nil {
 		h.logger.Error("Failed to submit document reprocessing job", 
 			zap.String("document_id", documentID), 
 			zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -658,6 +766,7 @@ func (h *DocumentHandler) ReprocessDocument(c *gin.Context) {
 		"document_id": documentID,
 		"job_id": job.ID,
 		"status": "processing",
+		"priority": document.ProcessingPriority,
 	})
 }
 
@@ -686,7 +795,7 @@ func (h *DocumentHandler) ListDocumentsByNotebook(c *gin.Context) {
 
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
@@ -709,23 +818,160 @@ func (h *DocumentHandler) ListDocumentsByNotebook(c *gin.Context) {
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
 	response, err := h.documentService.ListDocumentsByNotebook(c.Request.Context(), notebookID, userID, spaceContext, limit, offset)
 	if err != nil {
 		h.logger.Error("Failed to list documents", zap.String("notebook_id", notebookID), zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+// exportFlushInterval is how many exported documents are buffered before
+// they are flushed to the client
+const exportFlushInterval = 500
+
+// ExportNotebookDocuments streams the metadata of every document in a notebook
+// @Summary Export notebook documents
+// @Description Stream document metadata of a notebook as newline-delimited JSON
+// @Tags documents
+// @Produce application/x-ndjson
+// @Security Bearer
+// @Param id path string true "Notebook ID"
+// @Success 200 {object} models.DocumentResponse "One document per line"
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/notebooks/{id}/documents/export [get]
+func (h *DocumentHandler) ExportNotebookDocuments(c *gin.Context) {
+	notebookID := c.Param("id")
+	if notebookID == "" {
+		c.JSON(http.StatusBadRequest, errors.Validation("Notebook ID is required", nil))
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
+		return
+	}
+
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return
+	}
+
+	// Headers are written with the first document so errors raised before
+	// streaming starts still get the standard error response
+	started := false
+	start := func() {
+		if started {
+			return
+		}
+		started = true
+		c.Header("Content-Type", "application/x-ndjson")
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="notebook-%s-documents.ndjson"`, notebookID))
+		c.Status(http.StatusOK)
+	}
+
+	encoder := json.NewEncoder(c.Writer)
+	written := 0
+	count, err := h.documentService.ExportNotebookDocuments(c.Request.Context(), notebookID, userID, spaceContext, func(document *models.DocumentResponse) error {
+		start()
+		if err := encoder.Encode(document); err != nil {
+			return err
+		}
+		if written++; written%exportFlushInterval == 0 {
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		if !started {
+			middleware.WriteError(c, h.logger, err)
+			return
+		}
+		// The status line is already sent; the client sees a truncated export
+		h.logger.Error("Document export aborted",
+			zap.String("notebook_id", notebookID),
+			zap.Int("exported", count),
+			zap.Error(err))
+		return
+	}
+
+	start()
+	c.Writer.Flush()
+}
+
+// StartNotebookExport exports the metadata of every document in a notebook
+// as an async job
+// @Summary Start a notebook export job
+// @Description Export document metadata of a notebook as newline-delimited JSON to object storage in the background. Responds 202 with a notebook_export job; once it has succeeded its result holds a download URL.
+// @Tags documents
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Notebook ID"
+// @Success 202 {object} models.Job
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/notebooks/{id}/documents/export [post]
+func (h *DocumentHandler) StartNotebookExport(c *gin.Context) {
+	notebookID := c.Param("id")
+	if notebookID == "" {
+		c.JSON(http.StatusBadRequest, errors.Validation("Notebook ID is required", nil))
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
+		return
+	}
+
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return
+	}
+
+	if h.jobService == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Export jobs are not available"))
+		return
+	}
+
+	// Fail fast on permissions rather than in a job the client has to poll
+	if err := h.documentService.CheckNotebookExport(c.Request.Context(), notebookID, userID, spaceContext); err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	job, err := h.jobService.Start(c.Request.Context(), models.JobTypeNotebookExport, spaceContext.TenantID, userID, func(ctx context.Context, progress func(models.JobProgress)) (map[string]interface{}, error) {
+		export, err := h.documentService.StoreNotebookExport(ctx, notebookID, userID, spaceContext, func(exported int) {
+			progress(models.JobProgress{Completed: exported})
+		})
+		if err != nil {
+			return nil, err
+		}
+		progress(models.JobProgress{Total: export.Documents, Completed: export.Documents})
+		return jobResult(export)
+	})
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	respondAccepted(c, job)
+}
+
 // SearchDocuments searches documents
 // @Summary Search documents
-// @Description Search documents by query, notebook, owner, etc.
+// @Description Search documents by query, notebook, owner, etc. The filter parameter takes an expression of field:value terms combined with AND, OR, NOT and parentheses, e.g. tag:contract AND mime:pdf AND created:>2024-01-01. Fields are tag, mime, type, status, name, filename, notebook, owner, size, created, updated and processed; size and the dates also take >, >=, < and <=.
 // @Tags documents
 // @Accept json
 // @Produce json
@@ -737,6 +983,7 @@ func (h *DocumentHandler) ListDocumentsByNotebook(c *gin.Context) {
 // @Param status query string false "Status filter"
 // @Param mime_type query string false "MIME type filter"
 // @Param tags query []string false "Tags filter"
+// @Param filter query string false "Filter expression, e.g. tag:contract AND mime:pdf AND created:>2024-01-01"
 // @Param limit query int false "Results limit (max 100)" default(20)
 // @Param offset query int false "Results offset" default(0)
 // @Success 200 {object} models.DocumentListResponse
@@ -747,7 +994,7 @@ func (h *DocumentHandler) ListDocumentsByNotebook(c *gin.Context) {
 func (h *DocumentHandler) SearchDocuments(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
@@ -761,6 +1008,7 @@ func (h *DocumentHandler) SearchDocuments(c *gin.Context) {
 	req.Status = c.Query("status")
 	req.MimeType = c.Query("mime_type")
 	req.Tags = c.QueryArray("tags")
+	req.Filter = c.Query("filter")
 
 	if limitStr := c.Query("limit"); limitStr != "" {
 		if limit, err := strconv.Atoi(limitStr); err == nil {
@@ -783,14 +1031,14 @@ func (h *DocumentHandler) SearchDocuments(c *gin.Context) {
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
 	response, err := h.documentService.SearchDocuments(c.Request.Context(), req, userID, spaceContext)
 	if err != nil {
 		h.logger.Error("Failed to search documents", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -799,23 +1047,32 @@ func (h *DocumentHandler) SearchDocuments(c *gin.Context) {
 
 // DownloadDocument downloads document content
 // @Summary Download document
-// @Description Download document file content
+// @Description Download document file content. The file is streamed from storage. A single byte range may be asked for with a Range header, answered with 206 and Content-Range (a range past the end fails with 416); If-Range limits it to the file with that ETag. Responses carry the file's ETag, and If-None-Match with it answers 304 while the file is unchanged. With mode=redirect, or in the default auto mode for files of DOWNLOAD_REDIRECT_BYTES or more, the response is a 307 redirect to a presigned storage URL valid for DOWNLOAD_URL_SECONDS; mode=stream always streams. Users the document's notebook is shared with as viewers or commenters download a PDF rendition of its text instead, watermarked on every page with their email and the time of the download; the notebook's redaction rules apply to it. The X-Rendition header is watermarked on such responses. The original file is restricted to the document's owner, the notebook's editors and the space's owners and admins.
 // @Tags documents
 // @Accept json
 // @Produce application/octet-stream
 // @Security Bearer
 // @Param id path string true "Document ID"
+// @Param mode query string false "auto, stream or redirect" default(auto)
+// @Param Range header string false "A single byte range, such as bytes=0-1023"
+// @Param If-Range header string false "ETag the range applies to"
+// @Param If-None-Match header string false "ETag of a copy the client holds"
 // @Success 200 {file} binary
+// @Success 206 {file} binary
+// @Success 304 "Not modified"
+// @Success 307 "Redirect to a presigned URL"
 // @Failure 400 {object} errors.APIError
 // @Failure 401 {object} errors.APIError
 // @Failure 403 {object} errors.APIError
 // @Failure 404 {object} errors.APIError
+// @Failure 416 {object} errors.APIError
 // @Failure 500 {object} errors.APIError
+// @Failure 502 {object} errors.APIError
 // @Router /api/v1/documents/{id}/download [get]
 func (h *DocumentHandler) DownloadDocument(c *gin.Context) {
 	documentID := c.Param("id")
 	if documentID == "" {
-		c.JSON(http.StatusBadRequest, errors.Validation("Document ID is required", nil))
+		c.JSON(http.StatusBadRequest, errors.Validation("Document ID is required", nil).WithErrorCode(errors.CodeDocumentIDRequired))
 		return
 	}
 
@@ -824,24 +1081,86 @@ func (h *DocumentHandler) DownloadDocument(c *gin.Context) {
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return
+	}
+
+	viewOnly, ok := h.viewOnlyDocument(c, documentID, userID, spaceContext)
+	if !ok {
+		return
+	}
+	if viewOnly != nil {
+		h.downloadRendition(c, viewOnly, userID, spaceContext)
 		return
 	}
 	
-	fileData, document, err := h.documentService.DownloadDocumentFile(c.Request.Context(), documentID, userID, spaceContext)
+	download, err := h.documentService.DownloadDocumentFile(c.Request.Context(), documentID, userID, spaceContext, c.Query("mode"), services.FileReadOptions{
+		Range:       services.SingleByteRange(c.GetHeader("Range")),
+		IfRange:     c.GetHeader("If-Range"),
+		IfNoneMatch: c.GetHeader("If-None-Match"),
+	})
+	if err != nil {
+		if apiErr, ok := errors.AsAPIError(err); ok && apiErr.Code == errors.ErrRangeNotSatisfiable {
+			c.Header("Content-Range", fmt.Sprintf("bytes */%v", apiErr.Details["size_bytes"]))
+		} else {
+			h.logger.Error("Failed to download document file", zap.String("document_id", documentID), zap.Error(err))
+		}
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	if download.URL != "" {
+		c.Header("Cache-Control", "private, no-store")
+		c.Redirect(http.StatusTemporaryRedirect, download.URL)
+		return
+	}
+	if download.NotModified {
+		c.Header("ETag", c.GetHeader("If-None-Match"))
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	file := download.File
+	defer file.Body.Close()
+	headers := map[string]string{
+		"Content-Disposition": "attachment; filename=\"" + download.Document.OriginalName + "\"",
+		"Accept-Ranges":       "bytes",
+		"Cache-Control":       "private, no-cache",
+	}
+	if file.ETag != "" {
+		headers["ETag"] = file.ETag
+	}
+	if !file.LastModified.IsZero() {
+		headers["Last-Modified"] = file.LastModified.UTC().Format(http.TimeFormat)
+	}
+	status := http.StatusOK
+	if file.ContentRange != "" {
+		status = http.StatusPartialContent
+		headers["Content-Range"] = file.ContentRange
+	}
+	// Streamed from storage as it is read, never held in memory whole
+	c.DataFromReader(status, file.ContentLength, download.Document.MimeType, file.Body, headers)
+}
+
+// downloadRendition serves a watermarked PDF rendition of a document in
+// place of its original file
+func (h *DocumentHandler) downloadRendition(c *gin.Context, document *models.Document, userID string, spaceContext *models.SpaceContext) {
+	viewer := c.GetString("user_email")
+	if viewer == "" {
+		viewer = userID
+	}
+	pdf, err := h.watermark.Render(c.Request.Context(), document, userID, viewer, spaceContext)
 	if err != nil {
-		h.logger.Error("Failed to download document file", zap.String("document_id", documentID), zap.Error(err))
-		handleServiceError(c, err)
+		h.logger.Error("Failed to render watermarked document", zap.String("document_id", document.ID), zap.Error(err))
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
-	// Set appropriate headers for file download
-	c.Header("Content-Disposition", "attachment; filename=\""+document.OriginalName+"\"")
-	c.Header("Content-Type", document.MimeType)
-	c.Header("Content-Length", fmt.Sprintf("%d", len(fileData)))
-	
-	// Stream the file data
-	c.Data(http.StatusOK, document.MimeType, fileData)
+	name := strings.TrimSuffix(document.OriginalName, filepath.Ext(document.OriginalName)) + ".pdf"
+	c.Header("Content-Disposition", "attachment; filename=\""+name+"\"")
+	c.Header("X-Rendition", "watermarked")
+	c.Header("Cache-Control", "private, no-store")
+	c.Data(http.StatusOK, reports.ContentTypePDF, pdf)
 }
 
 // GetDocumentURL gets a presigned URL for document access
@@ -863,7 +1182,7 @@ func (h *DocumentHandler) DownloadDocument(c *gin.Context) {
 func (h *DocumentHandler) GetDocumentURL(c *gin.Context) {
 	documentID := c.Param("id")
 	if documentID == "" {
-		c.JSON(http.StatusBadRequest, errors.Validation("Document ID is required", nil))
+		c.JSON(http.StatusBadRequest, errors.Validation("Document ID is required", nil).WithErrorCode(errors.CodeDocumentIDRequired))
 		return
 	}
 
@@ -872,14 +1191,14 @@ func (h *DocumentHandler) GetDocumentURL(c *gin.Context) {
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 	
 	document, err := h.documentService.GetDocumentByID(c.Request.Context(), documentID, userID, spaceContext)
 	if err != nil {
 		h.logger.Error("Failed to get document", zap.String("document_id", documentID), zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -924,7 +1243,7 @@ func (h *DocumentHandler) RefreshProcessingResults(c *gin.Context) {
 	err := h.documentService.RefreshProcessingResults(c.Request.Context())
 	if err != nil {
 		h.logger.Error("Failed to refresh processing results", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -935,27 +1254,32 @@ func (h *DocumentHandler) RefreshProcessingResults(c *gin.Context) {
 
 // AudiModalProcessingWebhook handles webhook notifications from AudiModal when processing completes
 // @Summary AudiModal processing webhook
-// @Description Webhook endpoint for AudiModal to notify when document processing is complete
+// @Description Webhook endpoint for AudiModal to notify when document processing is complete. Deliveries are signed with AUDIMODAL_WEBHOOK_SECRET: X-Webhook-Signature is "v1=" and the hex HMAC-SHA256 of "<X-Webhook-ID>.<X-Webhook-Timestamp>.<body>".
 // @Tags webhooks
 // @Accept json
 // @Produce json
+// @Param X-Webhook-ID header string true "Unique delivery ID"
+// @Param X-Webhook-Timestamp header string true "Unix time the delivery was signed"
+// @Param X-Webhook-Signature header string true "v1=<hex HMAC-SHA256>"
 // @Param payload body object true "Webhook payload from AudiModal"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
 // @Failure 500 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
 // @Router /webhooks/audimodal/processing-complete [post]
 func (h *DocumentHandler) AudiModalProcessingWebhook(c *gin.Context) {
 	var payload struct {
-		FileID      string `json:"file_id" binding:"required"`
-		TenantID    string `json:"tenant_id" binding:"required"`
-		Status      string `json:"status" binding:"required"`
-		Event       string `json:"event" binding:"required"`
+		FileID      string `json:"file_id" validate:"required"`
+		TenantID    string `json:"tenant_id" validate:"required"`
+		Status      string `json:"status" validate:"required"`
+		Event       string `json:"event" validate:"required"`
 		ProcessedAt string `json:"processed_at,omitempty"`
 	}
 
-	if err := c.ShouldBindJSON(&payload); err != nil {
+	if err := webhooks.DecodePayload(middleware.WebhookBody(c), &payload); err != nil {
 		h.logger.Error("Invalid webhook payload", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payload"})
+		c.JSON(http.StatusBadRequest, errors.Validation("Invalid webhook payload", err))
 		return
 	}
 
@@ -978,7 +1302,7 @@ func (h *DocumentHandler) AudiModalProcessingWebhook(c *gin.Context) {
 	err := h.documentService.RefreshProcessingResults(c.Request.Context())
 	if err != nil {
 		h.logger.Error("Failed to refresh processing results from webhook", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh processing results"})
+		middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to refresh processing results", err))
 		return
 	}
 
@@ -1007,20 +1331,20 @@ func (h *DocumentHandler) AudiModalProcessingWebhook(c *gin.Context) {
 func (h *DocumentHandler) GetDocumentAnalysis(c *gin.Context) {
 	documentID := c.Param("id")
 	if documentID == "" {
-		c.JSON(http.StatusBadRequest, errors.Validation("Document ID is required", nil))
+		c.JSON(http.StatusBadRequest, errors.Validation("Document ID is required", nil).WithErrorCode(errors.CodeDocumentIDRequired))
 		return
 	}
 
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -1028,7 +1352,7 @@ func (h *DocumentHandler) GetDocumentAnalysis(c *gin.Context) {
 	document, err := h.documentService.GetDocumentByID(c.Request.Context(), documentID, userID, spaceContext)
 	if err != nil {
 		h.logger.Error("Failed to get document for analysis", zap.String("document_id", documentID), zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -1084,7 +1408,7 @@ func (h *DocumentHandler) GetDocumentAnalysis(c *gin.Context) {
 			zap.String("document_id", documentID),
 			zap.String("file_id", fileID),
 			zap.Error(err))
-		c.JSON(http.StatusInternalServerError, errors.Internal("Failed to retrieve ML analysis"))
+		middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to retrieve ML analysis", err))
 		return
 	}
 
@@ -1111,17 +1435,20 @@ func getMapKeys(m map[string]interface{}) []string {
 	return keys
 }
 
-// GetDocumentExtractedText fetches the extracted text content from audimodal
+// GetDocumentExtractedText fetches the extracted text content from the
+// document's processing provider
 // @Summary Get extracted text for a document
-// @Description Fetches the extracted text content from audimodal's processed chunks
+// @Description Fetches the extracted text content from the processing provider that processed the document, AudiModal by default. Users the document's notebook is shared with at a redacted role get the text of the chunks its redaction rules keep, with redacted set and the number of chunks left out in redacted_chunks.
 // @Tags documents
 // @Accept json
 // @Produce json
 // @Param id path string true "Document ID"
 // @Success 200 {object} map[string]interface{} "Extracted text"
-// @Failure 404 {object} errors.Error "Document not found"
-// @Failure 500 {object} errors.Error "Internal server error"
-// @Router /documents/{id}/text [get]
+// @Failure 404 {object} errors.APIError
+// @Failure 500 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Security Bearer
+// @Router /api/v1/documents/{id}/text [get]
 func (h *DocumentHandler) GetDocumentExtractedText(c *gin.Context) {
 	documentID := c.Param("id")
 
@@ -1129,58 +1456,67 @@ func (h *DocumentHandler) GetDocumentExtractedText(c *gin.Context) {
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
 		h.logger.Error("Failed to get space context", zap.Error(err))
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
 	// Get user ID from context
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
-	// Get document to verify access and get audimodal file ID
+	// Get document to verify access and get its processed file
 	document, err := h.documentService.GetDocumentByID(c.Request.Context(), documentID, userID.(string), spaceContext)
 	if err != nil {
 		h.logger.Error("Failed to get document",
 			zap.String("document_id", documentID),
 			zap.Error(err))
-		c.JSON(http.StatusNotFound, errors.NotFound("Document not found"))
+		c.JSON(http.StatusNotFound, errors.NotFound("Document not found").WithErrorCode(errors.CodeDocumentNotFound))
 		return
 	}
 
-	// Check if audimodal service is available
-	if h.audiModalService == nil {
-		h.logger.Warn("AudiModal service not configured")
-		c.JSON(http.StatusServiceUnavailable, errors.ExternalService("Text extraction service not available", nil))
+	h.logger.Info("Fetching extracted text for document",
+		zap.String("document_id", documentID),
+		zap.String("processing_provider", document.ProcessingProvider),
+		zap.String("tenant_id", spaceContext.TenantID))
+
+	rules, err := h.redactionRules(c, document, userID.(string), spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
-
-	// Get audimodal file ID from processing_result or processing_job_id
-	fileID := documentID
-	if document.ProcessingResult != nil {
-		if audimodalFileID, ok := document.ProcessingResult["audimodal_file_id"].(string); ok && audimodalFileID != "" {
-			fileID = audimodalFileID
+	if rules != nil {
+		redactedText, omitted, err := h.redaction.RedactText(c.Request.Context(), document, rules)
+		if err != nil {
+			h.logger.Error("Failed to redact extracted text",
+				zap.String("document_id", documentID),
+				zap.Error(err))
+			middleware.WriteError(c, h.logger, err)
+			return
 		}
-	}
-	if fileID == documentID && document.ProcessingJobID != "" {
-		fileID = document.ProcessingJobID
+		c.JSON(http.StatusOK, gin.H{
+			"document_id":     documentID,
+			"extracted_text":  redactedText,
+			"text_length":     len(redactedText),
+			"redacted":        true,
+			"redacted_chunks": omitted,
+		})
+		return
 	}
 
-	h.logger.Info("Fetching extracted text for document",
-		zap.String("document_id", documentID),
-		zap.String("file_id", fileID),
-		zap.String("tenant_id", spaceContext.TenantID))
-
-	// Fetch extracted text from AudiModal
-	extractedText, err := h.audiModalService.GetFileContent(c.Request.Context(), spaceContext.TenantID, fileID)
+	// Fetch extracted text from the document's processing provider
+	extractedText, err := h.documentService.GetExtractedText(c.Request.Context(), document)
 	if err != nil {
-		h.logger.Error("Failed to get extracted text from AudiModal",
+		h.logger.Error("Failed to get extracted text from processing provider",
 			zap.String("document_id", documentID),
-			zap.String("file_id", fileID),
 			zap.Error(err))
-		c.JSON(http.StatusInternalServerError, errors.Internal("Failed to retrieve extracted text"))
+		if errors.IsAPIError(err) {
+			middleware.WriteError(c, h.logger, err)
+			return
+		}
+		middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to retrieve extracted text", err))
 		return
 	}
 
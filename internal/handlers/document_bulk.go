@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// SetMaxBulkUploadFiles sets how many files one bulk upload accepts
+func (h *DocumentHandler) SetMaxBulkUploadFiles(limit int) {
+	h.maxBulkFiles = limit
+}
+
+// BulkUploadDocuments uploads several documents into a notebook
+// @Summary Bulk upload documents
+// @Description Upload several files into a notebook in one multipart request, each as a "files" part, and submit each for processing. Files are uploaded concurrently and succeed or fail on their own: the response reports the created document or the error of every file, in the order sent, and is 200 even when some files failed. A file over the upload size limit fails with AETHER-DOC-003 without failing the others. Sending more files than MAX_BULK_UPLOAD_FILES fails the request with 400.
+// @Tags documents
+// @Accept multipart/form-data
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Notebook ID"
+// @Param files formData file true "Document files"
+// @Param tags formData []string false "Tags of every document"
+// @Success 200 {object} models.BulkUploadResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 413 {object} errors.APIError
+// @Router /api/v1/notebooks/{id}/documents/bulk [post]
+func (h *DocumentHandler) BulkUploadDocuments(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == "" {
+		middleware.WriteError(c, h.logger, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
+		return
+	}
+
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return
+	}
+
+	form, err := readBulkUploadForm(c.Request, h.maxUploadBytes, h.maxBulkFiles)
+	switch {
+	case err == errTooManyFiles:
+		middleware.WriteError(c, h.logger, errors.ValidationWithDetails("Too many files", map[string]interface{}{
+			"max_files": h.maxBulkFiles,
+		}))
+		return
+	case middleware.IsBodyTooLarge(err):
+		middleware.WriteError(c, h.logger, err)
+		return
+	case err == http.ErrMissingFile:
+		middleware.WriteError(c, h.logger, errors.Validation("At least one file is required", nil))
+		return
+	case err != nil:
+		h.logger.Error("Failed to parse bulk upload form", zap.Error(err))
+		middleware.WriteError(c, h.logger, errors.Validation("Invalid multipart form", nil))
+		return
+	}
+
+	var tags []string
+	if value := form.fields["tags"]; value != "" {
+		for _, tag := range strings.Split(value, ",") {
+			tags = append(tags, strings.TrimSpace(tag))
+		}
+	}
+
+	files := make([]models.BulkUploadFile, 0, len(form.files))
+	for _, part := range form.files {
+		if !part.tooLarge {
+			files = append(files, models.BulkUploadFile{FileName: part.fileName, MimeType: part.mimeType, Data: part.data})
+		}
+	}
+
+	response, err := h.documentService.BulkUpload(c.Request.Context(), c.Param("id"), files, tags, userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	// Report the files rejected while reading the form in their place
+	if len(files) < len(form.files) {
+		uploaded := response.Results
+		response.Results = make([]*models.BulkUploadResult, 0, len(form.files))
+		for _, part := range form.files {
+			if part.tooLarge {
+				response.Results = append(response.Results, services.BulkUploadFailure(part.fileName, h.fileTooLarge()))
+				response.Failed++
+				continue
+			}
+			response.Results = append(response.Results, uploaded[0])
+			uploaded = uploaded[1:]
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
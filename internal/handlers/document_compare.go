@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// CompareDocuments diffs the extracted text of two documents or versions
+// @Summary Compare documents
+// @Description Diff the extracted text of two documents, or two versions of one, line by line. The response lists the changed lines in hunks with context_lines unchanged lines around them (3 by default, at most 20) and a similarity score from 0 to 1, the share of the texts' characters they have in common. Version 0, or no version, is the document's current text. Earlier versions keep their text only when they were processed before being replaced, and are not compared for users the notebook's redaction rules apply to. Texts over COMPARE_MAX_TEXT_BYTES or COMPARE_MAX_LINES are refused with 422 AETHER-DOC-010. Diffs are cached for COMPARE_CACHE_SECONDS.
+// @Tags documents
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body models.DocumentCompareRequest true "Texts to compare"
+// @Success 200 {object} models.DocumentCompareResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 422 {object} errors.APIError
+// @Router /api/v1/documents/compare [post]
+func (h *DocumentHandler) CompareDocuments(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == "" {
+		middleware.WriteError(c, h.logger, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
+		return
+	}
+
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return
+	}
+
+	var req models.DocumentCompareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Invalid request body", err))
+		return
+	}
+	if err := validateStruct(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Validation failed", err))
+		return
+	}
+
+	comparison, err := h.comparison.Compare(c.Request.Context(), &req, userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, comparison)
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
+	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// DocumentContentHandler serves the extracted text and chunks of documents
+// a page at a time
+type DocumentContentHandler struct {
+	contentService *services.DocumentContentService
+	logger         *logger.Logger
+}
+
+// NewDocumentContentHandler creates a new document content handler
+func NewDocumentContentHandler(contentService *services.DocumentContentService, log *logger.Logger) *DocumentContentHandler {
+	return &DocumentContentHandler{
+		contentService: contentService,
+		logger:         log.WithService("document_content_handler"),
+	}
+}
+
+// GetDocumentContent serves the extracted text of a document a page at a
+// time, or as plain text with byte range support
+// @Summary Get the extracted text of a document
+// @Description Serve the text extracted from a document one page at a time: limit characters from offset, with the total length and the offset of the next page. With format=text, or a Range header, the whole text is served as text/plain instead, honouring byte ranges (206 Partial Content), If-Range and If-None-Match against its ETag. Users the document's notebook is shared with at a redacted role get the text of the chunks its redaction rules keep, with redacted set (X-Redacted-Chunks as plain text). Documents the user cannot read fail as GET /api/v1/documents/{id} does.
+// @Tags documents
+// @Produce json,plain
+// @Security Bearer
+// @Param id path string true "Document ID"
+// @Param offset query int false "Characters to skip" default(0)
+// @Param limit query int false "Characters to return (max 200000)" default(20000)
+// @Param format query string false "Response format" Enums(json, text) default(json)
+// @Param Range header string false "Byte range of the plain text, such as bytes=0-1023"
+// @Success 200 {object} models.DocumentContentPage
+// @Success 206 {string} string "Part of the plain text"
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 416 {string} string "Range not satisfiable"
+// @Failure 502 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/documents/{id}/content [get]
+func (h *DocumentContentHandler) GetDocumentContent(c *gin.Context) {
+	userID, spaceContext, ok := requireUserAndSpace(c, h.logger)
+	if !ok {
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "text" {
+		middleware.WriteError(c, h.logger, errors.ValidationWithDetails("format must be json or text", map[string]interface{}{
+			"format": format,
+		}))
+		return
+	}
+	if format == "text" || c.GetHeader("Range") != "" {
+		h.serveText(c, userID, spaceContext)
+		return
+	}
+
+	offset, ok := h.intQuery(c, "offset", 0)
+	if !ok {
+		return
+	}
+	limit, ok := h.intQuery(c, "limit", services.DefaultContentPageChars)
+	if !ok {
+		return
+	}
+
+	page, err := h.contentService.Page(c.Request.Context(), c.Param("id"), offset, limit, userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// serveText serves the whole extracted text of a document as text/plain,
+// leaving byte ranges and conditional requests to http.ServeContent
+func (h *DocumentContentHandler) serveText(c *gin.Context, userID string, spaceContext *models.SpaceContext) {
+	document, text, err := h.contentService.Text(c.Request.Context(), c.Param("id"), userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	sum := sha256.Sum256([]byte(text.Text))
+	modified := document.UpdatedAt
+	if document.ProcessedAt != nil {
+		modified = *document.ProcessedAt
+	}
+	header := c.Writer.Header()
+	header.Set("Content-Type", "text/plain; charset=utf-8")
+	header.Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+	header.Set("Cache-Control", "private, no-cache")
+	if text.Redacted {
+		header.Set("X-Redacted-Chunks", strconv.Itoa(text.RedactedChunks))
+	}
+	http.ServeContent(c.Writer, c.Request, "", modified.Truncate(time.Second), strings.NewReader(text.Text))
+}
+
+// intQuery reads a non-negative integer query parameter, writing the error
+// response when it is not one
+func (h *DocumentContentHandler) intQuery(c *gin.Context, name string, defaultValue int) (int, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return defaultValue, true
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		middleware.WriteError(c, h.logger, errors.ValidationWithDetails(name+" must be a non-negative integer", map[string]interface{}{
+			name: value,
+		}))
+		return 0, false
+	}
+	return parsed, true
+}
+
+// GetDocumentChunks lists the chunks of a document
+// @Summary List document chunks
+// @Description Get a page of the chunks the processing service produced for a document, in the document's space and with the same access checks as GET /api/v1/documents/{id}: restricted documents and documents held for moderation are readable only by their owner and the space's owners and admins. Users the document's notebook is shared with at a redacted role don't get the chunks its redaction rules omit; redacted counts those left out of the page.
+// @Tags documents
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Document ID"
+// @Param limit query int false "Page size (max 100)" default(50)
+// @Param offset query int false "Page offset" default(0)
+// @Success 200 {object} models.ChunkListResponse
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 502 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/documents/{id}/chunks [get]
+func (h *DocumentContentHandler) GetDocumentChunks(c *gin.Context) {
+	userID, spaceContext, ok := requireUserAndSpace(c, h.logger)
+	if !ok {
+		return
+	}
+
+	params := parsePaginationParams(c, 50)
+	chunks, redacted, err := h.contentService.Chunks(c.Request.Context(), c.Param("id"), params.Limit, params.Offset, userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	responses := make([]*models.ChunkResponse, 0, len(chunks.Data))
+	for _, chunk := range chunks.Data {
+		responses = append(responses, convertAudiModalChunkToResponse(chunk))
+	}
+	c.JSON(http.StatusOK, &models.ChunkListResponse{
+		Chunks:   responses,
+		Total:    chunks.Total,
+		Limit:    params.Limit,
+		Offset:   params.Offset,
+		HasMore:  pagination.HasMore(params.Offset, len(chunks.Data)+redacted, chunks.Total),
+		Redacted: redacted,
+	})
+}
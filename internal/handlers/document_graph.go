@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// GetDocumentGraph lists the nodes around a document
+// @Summary Get document graph
+// @Description Get one page of the notebooks, documents, spaces and users within depth relationships of a document, nearest first. Depth is capped by NEO4J_MAX_TRAVERSAL_DEPTH; traversals estimated to touch too many rows are refused with 422.
+// @Tags documents
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Document ID"
+// @Param depth query int false "Relationships to follow" default(1)
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param offset query int false "Page offset" default(0)
+// @Success 200 {object} models.DocumentGraphResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 422 {object} errors.APIError
+// @Router /api/v1/documents/{id}/graph [get]
+func (h *DocumentHandler) GetDocumentGraph(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == "" {
+		middleware.WriteError(c, h.logger, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
+		return
+	}
+
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return
+	}
+
+	depth := 1
+	if value := c.Query("depth"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			middleware.WriteError(c, h.logger, errors.Validation("depth must be a positive integer", nil))
+			return
+		}
+		depth = parsed
+	}
+
+	page := parsePaginationParams(c, pagination.DefaultLimit)
+	graph, err := h.documentService.GetDocumentGraph(c.Request.Context(), c.Param("id"), userID, spaceContext, depth, page.Limit, page.Offset)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, graph)
+}
+
+// GetSimilarDocuments recommends documents related to a document
+// @Summary Get similar documents
+// @Description Get the documents of the space most related to a document, best first. Documents are related by the vector similarity of their contents, shared tags and graph proximity (same notebook, shared referenced entities); each result reports these signals and the combined score. Without vector search documents are related by tags and the graph alone. Rankings are cached per document for an hour and recomputed when the document is updated or reprocessed.
+// @Tags documents
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Document ID"
+// @Param limit query int false "Documents to return (max 50)" default(10)
+// @Success 200 {object} models.SimilarDocumentsResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/documents/{id}/similar [get]
+func (h *DocumentHandler) GetSimilarDocuments(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == "" {
+		middleware.WriteError(c, h.logger, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
+		return
+	}
+
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return
+	}
+
+	limit := 0
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			middleware.WriteError(c, h.logger, errors.Validation("limit must be a positive integer", nil))
+			return
+		}
+		limit = parsed
+	}
+
+	similar, err := h.similar.GetSimilarDocuments(c.Request.Context(), c.Param("id"), userID, spaceContext, limit)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, similar)
+}
+
+// GetRelatedDocuments traverses the documents related to a document
+// @Summary Get related documents
+// @Description Get the documents within depth SIMILAR_TO relationships of a document, best first. Each processed or updated document is linked to up to 10 of its best ranked similar documents (see /documents/{id}/similar) scoring at least 0.2; a document never linked is linked on the first request. The score of a document found further away is the product of the scores along the best path to it, and paths only pass through documents the user may see. Depth is capped at 3 and by NEO4J_MAX_TRAVERSAL_DEPTH; traversals estimated to touch too many rows are refused with 422.
+// @Tags documents
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Document ID"
+// @Param depth query int false "SIMILAR_TO relationships to follow (max 3)" default(1)
+// @Param limit query int false "Documents to return (max 100)" default(20)
+// @Success 200 {object} models.RelatedDocumentsResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 422 {object} errors.APIError
+// @Router /api/v1/documents/{id}/related [get]
+func (h *DocumentHandler) GetRelatedDocuments(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == "" {
+		middleware.WriteError(c, h.logger, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
+		return
+	}
+
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return
+	}
+
+	depth := 1
+	if value := c.Query("depth"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			middleware.WriteError(c, h.logger, errors.Validation("depth must be a positive integer", nil))
+			return
+		}
+		depth = parsed
+	}
+
+	page := parsePaginationParams(c, pagination.DefaultLimit)
+	related, err := h.related.GetRelatedDocuments(c.Request.Context(), c.Param("id"), userID, spaceContext, depth, page.Limit)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, related)
+}
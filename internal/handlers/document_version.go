@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// ListDocumentVersions lists the versions of a document
+// @Summary List document versions
+// @Description List the versions of a document's file, newest first, marking the current one. A document never given a new file has only version 1.
+// @Tags documents
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Document ID"
+// @Success 200 {object} models.DocumentVersionListResponse
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/documents/{id}/versions [get]
+func (h *DocumentHandler) ListDocumentVersions(c *gin.Context) {
+	userID, spaceContext, ok := h.versionRequestContext(c)
+	if !ok {
+		return
+	}
+
+	versions, err := h.documentService.ListDocumentVersions(c.Request.Context(), c.Param("id"), userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, versions)
+}
+
+// UploadDocumentVersion uploads a new file for a document
+// @Summary Upload a document version
+// @Description Upload a new file for an existing document as its next version, which becomes current and is processed again. The document keeps its ID, notebook, name and tags; the files of earlier versions are kept and can be downloaded or restored. Only the document's owner can add versions. Fails with 409 while a direct or resumable upload of the document is pending. In spaces that scan uploads, infected files are rejected with 422 and quarantined. The file is validated against its content and the space's type restrictions as on upload.
+// @Tags documents
+// @Accept multipart/form-data
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Document ID"
+// @Param file formData file true "File"
+// @Success 201 {object} models.DocumentVersion
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 402 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 409 {object} errors.APIError
+// @Failure 413 {object} errors.APIError
+// @Failure 415 {object} errors.APIError
+// @Failure 422 {object} errors.APIError
+// @Failure 502 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/documents/{id}/versions [post]
+func (h *DocumentHandler) UploadDocumentVersion(c *gin.Context) {
+	userID, spaceContext, ok := h.versionRequestContext(c)
+	if !ok {
+		return
+	}
+
+	form, err := readUploadForm(c.Request, h.maxUploadBytes)
+	switch {
+	case err == errFileTooLarge:
+		c.JSON(http.StatusRequestEntityTooLarge, h.fileTooLarge())
+		return
+	case middleware.IsBodyTooLarge(err):
+		middleware.WriteError(c, h.logger, err)
+		return
+	case err == http.ErrMissingFile:
+		middleware.WriteError(c, h.logger, errors.Validation("File is required", err))
+		return
+	case err != nil:
+		h.logger.Error("Failed to parse multipart form", zap.Error(err))
+		middleware.WriteError(c, h.logger, errors.Validation("Invalid multipart form", err))
+		return
+	}
+
+	version, err := h.documentService.UploadDocumentVersion(c.Request.Context(), c.Param("id"), form.fileData, models.FileInfo{
+		OriginalName: form.fileName,
+		MimeType:     form.mimeType,
+	}, userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, version)
+}
+
+// DownloadDocumentVersion downloads the file of a document version
+// @Summary Download a document version
+// @Description Download the file of a version of a document. Fails with 404 and AETHER-DOC-006 when the document has no such version, and with 403 and AETHER-DOC-008 for users the document's notebook is shared with as viewers or commenters, who download watermarked renditions of the current version instead.
+// @Tags documents
+// @Produce application/octet-stream
+// @Security Bearer
+// @Param id path string true "Document ID"
+// @Param version path int true "Version number"
+// @Success 200 {file} binary
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 502 {object} errors.APIError
+// @Router /api/v1/documents/{id}/versions/{version}/download [get]
+func (h *DocumentHandler) DownloadDocumentVersion(c *gin.Context) {
+	userID, spaceContext, ok := h.versionRequestContext(c)
+	if !ok {
+		return
+	}
+	number, ok := h.versionNumber(c)
+	if !ok {
+		return
+	}
+
+	viewOnly, ok := h.viewOnlyDocument(c, c.Param("id"), userID, spaceContext)
+	if !ok {
+		return
+	}
+	if viewOnly != nil {
+		middleware.WriteError(c, h.logger, errors.ForbiddenWithDetails("The original file is restricted to the document's editors", map[string]interface{}{
+			"document_id": viewOnly.ID,
+		}).WithErrorCode(errors.CodeOriginalFileRestricted))
+		return
+	}
+
+	version, file, err := h.documentService.OpenDocumentVersion(c.Request.Context(), c.Param("id"), number, userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+	defer file.Close()
+
+	c.DataFromReader(http.StatusOK, version.SizeBytes, version.MimeType, file, map[string]string{
+		"Content-Disposition": "attachment; filename=\"" + version.OriginalName + "\"",
+	})
+}
+
+// RestoreDocumentVersion makes an earlier version of a document current
+// @Summary Restore a document version
+// @Description Make an earlier version of a document current again; its file is processed again. Later versions are kept, and restoring the current version changes nothing. Only the document's owner can restore versions. Fails with 404 and AETHER-DOC-006 when the document has no such version.
+// @Tags documents
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Document ID"
+// @Param version path int true "Version number"
+// @Success 200 {object} models.DocumentVersion
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 409 {object} errors.APIError
+// @Failure 502 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/documents/{id}/versions/{version}/restore [post]
+func (h *DocumentHandler) RestoreDocumentVersion(c *gin.Context) {
+	userID, spaceContext, ok := h.versionRequestContext(c)
+	if !ok {
+		return
+	}
+	number, ok := h.versionNumber(c)
+	if !ok {
+		return
+	}
+
+	version, err := h.documentService.RestoreDocumentVersion(c.Request.Context(), c.Param("id"), number, userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, version)
+}
+
+// versionRequestContext returns the user and space of a version request,
+// writing the error response when either is missing
+func (h *DocumentHandler) versionRequestContext(c *gin.Context) (string, *models.SpaceContext, bool) {
+	userID := getUserID(c)
+	if userID == "" {
+		middleware.WriteError(c, h.logger, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
+		return "", nil, false
+	}
+
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return "", nil, false
+	}
+	return userID, spaceContext, true
+}
+
+// versionNumber parses the version path parameter, writing the error
+// response when it is not a positive number
+func (h *DocumentHandler) versionNumber(c *gin.Context) (int, bool) {
+	number, err := strconv.Atoi(c.Param("version"))
+	if err != nil || number < 1 {
+		middleware.WriteError(c, h.logger, errors.ValidationWithDetails("Invalid version number", map[string]interface{}{
+			"param": "version",
+		}))
+		return 0, false
+	}
+	return number, true
+}
@@ -0,0 +1,346 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
+	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// EntityHandler serves the people, organizations, topics, locations and
+// dates detected in documents
+type EntityHandler struct {
+	entityService   *services.EntityService
+	documentService *services.DocumentService
+	notebookService *services.NotebookService
+	userService     *services.UserService
+	logger          *logger.Logger
+}
+
+// NewEntityHandler creates a new entity handler
+func NewEntityHandler(entityService *services.EntityService, documentService *services.DocumentService, notebookService *services.NotebookService, userService *services.UserService, log *logger.Logger) *EntityHandler {
+	return &EntityHandler{
+		entityService:   entityService,
+		documentService: documentService,
+		notebookService: notebookService,
+		userService:     userService,
+		logger:          log.WithService("entity_handler"),
+	}
+}
+
+// ListEntities lists the entities mentioned by the space's documents
+// @Summary List entities
+// @Description List one page of the people, organizations, topics, locations and dates mentioned by the documents of the space's tenant, most mentioned first. Filter by type, or by a name or alias containing q. With type=topic this is the entry point of the topic explorer.
+// @Tags entities
+// @Produce json
+// @Security Bearer
+// @Param type query string false "Entity type" Enums(person, organization, topic, location, date)
+// @Param q query string false "Text the name or an alias contains"
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param offset query int false "Page offset" default(0)
+// @Success 200 {object} models.EntityListResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Router /api/v1/entities [get]
+func (h *EntityHandler) ListEntities(c *gin.Context) {
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return
+	}
+
+	entityType, ok := h.entityType(c)
+	if !ok {
+		return
+	}
+
+	params := parsePaginationParams(c, pagination.DefaultLimit)
+	entities, hasMore, err := h.entityService.ListEntities(c.Request.Context(), entityType, c.Query("q"), spaceContext, params.Limit, params.Offset)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.EntityListResponse{
+		Entities: entities,
+		Limit:    params.Limit,
+		Offset:   params.Offset,
+		HasMore:  hasMore,
+	})
+}
+
+// GetEntity returns an entity
+// @Summary Get entity
+// @Description Get a person, organization, topic, location or date with its aliases and the number of documents mentioning it.
+// @Tags entities
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Entity ID"
+// @Success 200 {object} models.Entity
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/entities/{id} [get]
+func (h *EntityHandler) GetEntity(c *gin.Context) {
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return
+	}
+
+	entity, err := h.entityService.GetEntity(c.Request.Context(), c.Param("id"), spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entity)
+}
+
+// ListEntityDocuments lists the documents mentioning an entity
+// @Summary List entity documents
+// @Description List one page of the documents mentioning an entity, most mentions first, e.g. all documents mentioning an organization. With notebook_id, only the documents of that notebook.
+// @Tags entities
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Entity ID"
+// @Param notebook_id query string false "Only the documents of this notebook"
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param offset query int false "Page offset" default(0)
+// @Success 200 {object} models.EntityDocumentsResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/entities/{id}/documents [get]
+func (h *EntityHandler) ListEntityDocuments(c *gin.Context) {
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return
+	}
+
+	notebookID := c.Query("notebook_id")
+	if notebookID != "" && !h.readNotebook(c, notebookID, spaceContext) {
+		return
+	}
+
+	params := parsePaginationParams(c, pagination.DefaultLimit)
+	documents, err := h.entityService.ListEntityDocuments(c.Request.Context(), c.Param("id"), notebookID, spaceContext, params.Limit, params.Offset)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, documents)
+}
+
+// ListRelatedEntities lists the entities mentioned together with an entity
+// @Summary List related entities
+// @Description List the entities most often mentioned by the same documents as an entity. With type=topic it lists the neighbouring topics of the topic explorer.
+// @Tags entities
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Entity ID"
+// @Param type query string false "Entity type" Enums(person, organization, topic, location, date)
+// @Param limit query int false "Entities to return (max 50)" default(10)
+// @Success 200 {object} models.RelatedEntitiesResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/entities/{id}/related [get]
+func (h *EntityHandler) ListRelatedEntities(c *gin.Context) {
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return
+	}
+
+	entityType, ok := h.entityType(c)
+	if !ok {
+		return
+	}
+
+	limit := 0
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			middleware.WriteError(c, h.logger, errors.Validation("limit must be a positive integer", nil))
+			return
+		}
+		limit = parsed
+	}
+
+	related, err := h.entityService.ListRelatedEntities(c.Request.Context(), c.Param("id"), entityType, spaceContext, limit)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, related)
+}
+
+// MergeEntity merges an entity into another
+// @Summary Merge entities
+// @Description Merge an entity into a target entity of the same type, for spellings the automatic deduplication missed. The target takes over the entity's document mentions and its names as aliases; the entity is removed.
+// @Tags entities
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "ID of the entity to merge"
+// @Param request body models.EntityMergeRequest true "Entity to merge into"
+// @Success 200 {object} models.Entity
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/entities/{id}/merge [post]
+func (h *EntityHandler) MergeEntity(c *gin.Context) {
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return
+	}
+
+	var req models.EntityMergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.TargetID == "" {
+		middleware.WriteError(c, h.logger, errors.Validation("target_id is required", nil))
+		return
+	}
+
+	entity, err := h.entityService.MergeEntities(c.Request.Context(), c.Param("id"), req.TargetID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entity)
+}
+
+// ListDocumentEntities lists the entities a document mentions
+// @Summary List document entities
+// @Description List the people, organizations, topics, locations and dates a document mentions, most mentioned first. Entities are linked when the document finishes processing, from those its processor reports and, for the types it reports none of, from its extracted text.
+// @Tags documents
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Document ID"
+// @Success 200 {array} models.Entity
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/documents/{id}/entities [get]
+func (h *EntityHandler) ListDocumentEntities(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == "" {
+		middleware.WriteError(c, h.logger, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
+		return
+	}
+
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return
+	}
+
+	documentID := c.Param("id")
+	if _, err := h.documentService.GetDocumentByID(c.Request.Context(), documentID, userID, spaceContext); err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	entities, err := h.entityService.ListDocumentEntities(c.Request.Context(), documentID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entities)
+}
+
+// ListNotebookEntities lists the entities the documents of a notebook mention
+// @Summary List notebook entities
+// @Description List one page of the people, organizations, topics, locations and dates the documents of a notebook mention, mentioned by most of its documents first; document_count counts the notebook's documents. Filter by type, or by a name or alias containing q. List the documents of the notebook mentioning one with /entities/{id}/documents?notebook_id=.
+// @Tags notebooks
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Notebook ID"
+// @Param type query string false "Entity type" Enums(person, organization, topic, location, date)
+// @Param q query string false "Text the name or an alias contains"
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param offset query int false "Page offset" default(0)
+// @Success 200 {object} models.EntityListResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/notebooks/{id}/entities [get]
+func (h *EntityHandler) ListNotebookEntities(c *gin.Context) {
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return
+	}
+
+	notebookID := c.Param("id")
+	if !h.readNotebook(c, notebookID, spaceContext) {
+		return
+	}
+	entityType, ok := h.entityType(c)
+	if !ok {
+		return
+	}
+
+	params := parsePaginationParams(c, pagination.DefaultLimit)
+	entities, hasMore, err := h.entityService.ListNotebookEntities(c.Request.Context(), notebookID, entityType, c.Query("q"), spaceContext, params.Limit, params.Offset)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.EntityListResponse{
+		NotebookID: notebookID,
+		Entities:   entities,
+		Limit:      params.Limit,
+		Offset:     params.Offset,
+		HasMore:    hasMore,
+	})
+}
+
+// readNotebook checks the user may read a notebook, writing the error
+// response when they may not
+func (h *EntityHandler) readNotebook(c *gin.Context, notebookID string, spaceContext *models.SpaceContext) bool {
+	userID, err := ensureUserExists(c, h.userService, h.logger)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return false
+	}
+	if _, err := h.notebookService.GetNotebookByID(c.Request.Context(), notebookID, userID, spaceContext); err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return false
+	}
+	return true
+}
+
+// entityType reads the type query parameter, answering 400 when it is not
+// an entity type
+func (h *EntityHandler) entityType(c *gin.Context) (models.EntityType, bool) {
+	entityType := models.EntityType(c.Query("type"))
+	if entityType != "" && entityType.Label() == "" {
+		middleware.WriteError(c, h.logger, errors.ValidationWithDetails("Invalid entity type", map[string]interface{}{
+			"param":   "type",
+			"allowed": models.EntityTypes,
+		}))
+		return "", false
+	}
+	return entityType, true
+}
@@ -0,0 +1,395 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/feeds"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+const (
+	// feedUpcomingWindow is how far ahead the jobs calendar lists runs
+	feedUpcomingWindow = 7 * 24 * time.Hour
+	// feedUpcomingPerJob bounds the upcoming runs listed per job, so jobs
+	// running every few minutes do not flood the calendar
+	feedUpcomingPerJob = 50
+	// feedRunHistory is the number of past runs listed per job
+	feedRunHistory = 20
+)
+
+// FeedHandler serves read-only feeds to feed readers and calendar apps,
+// which authenticate with a feed token in the URL, and manages the tokens
+type FeedHandler struct {
+	feedService     *services.FeedService
+	notebookService *services.NotebookService
+	scheduler       *services.Scheduler
+	userService     *services.UserService
+	baseURL         string
+	maxItems        int
+	logger          *logger.Logger
+}
+
+// NewFeedHandler creates a new feed handler. Feed URLs are built from
+// baseURL, or from the request when it is empty.
+func NewFeedHandler(feedService *services.FeedService, notebookService *services.NotebookService, scheduler *services.Scheduler, userService *services.UserService, baseURL string, maxItems int, log *logger.Logger) *FeedHandler {
+	return &FeedHandler{
+		feedService:     feedService,
+		notebookService: notebookService,
+		scheduler:       scheduler,
+		userService:     userService,
+		baseURL:         strings.TrimSuffix(baseURL, "/"),
+		maxItems:        maxItems,
+		logger:          log.WithService("feed_handler"),
+	}
+}
+
+// CreateNotebookFeedToken creates a token for the activity feed of a notebook
+// @Summary Create a notebook activity feed token
+// @Description Create a token for the RSS and Atom feeds of a notebook's activity. The response holds the token and the feed URLs, which embed it; the token is not shown again. Feeds stop working when the token is revoked or its creator loses access to the notebook.
+// @Tags feeds
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Notebook ID"
+// @Success 201 {object} models.FeedTokenCreateResponse
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/notebooks/{id}/feed-tokens [post]
+func (h *FeedHandler) CreateNotebookFeedToken(c *gin.Context) {
+	notebookID := c.Param("id")
+
+	userID, err := ensureUserExists(c, h.userService, h.logger)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return
+	}
+
+	notebook, err := h.notebookService.GetNotebookByID(c.Request.Context(), notebookID, userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	token, secret, err := h.feedService.CreateToken(c.Request.Context(), userID, models.FeedTypeNotebookActivity, notebook.ID, notebook.SpaceID)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	base := h.feedBaseURL(c) + "/feeds/notebooks/" + url.PathEscape(notebook.ID)
+	c.JSON(http.StatusCreated, models.FeedTokenCreateResponse{
+		FeedToken: *token,
+		Token:     secret,
+		URLs: map[string]string{
+			"rss":  base + "/rss?token=" + secret,
+			"atom": base + "/atom?token=" + secret,
+		},
+	})
+}
+
+// CreateScheduledJobsFeedToken creates a token for the scheduled jobs calendar
+// @Summary Create a scheduled jobs calendar token
+// @Description Create a token for the iCal feed of the background jobs' upcoming and recent runs. The response holds the token and the calendar URL, which embeds it; the token is not shown again.
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Success 201 {object} models.FeedTokenCreateResponse
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Router /api/v1/admin/jobs/feed-tokens [post]
+func (h *FeedHandler) CreateScheduledJobsFeedToken(c *gin.Context) {
+	userID, err := ensureUserExists(c, h.userService, h.logger)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	token, secret, err := h.feedService.CreateToken(c.Request.Context(), userID, models.FeedTypeScheduledJobs, "", "")
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.FeedTokenCreateResponse{
+		FeedToken: *token,
+		Token:     secret,
+		URLs: map[string]string{
+			"ical": h.feedBaseURL(c) + "/feeds/jobs.ics?token=" + secret,
+		},
+	})
+}
+
+// ListFeedTokens lists the caller's feed tokens
+// @Summary List feed tokens
+// @Description List the caller's feed tokens, newest first. The tokens themselves are not returned.
+// @Tags feeds
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.FeedTokenListResponse
+// @Failure 401 {object} errors.APIError
+// @Router /api/v1/users/me/feed-tokens [get]
+func (h *FeedHandler) ListFeedTokens(c *gin.Context) {
+	userID, err := ensureUserExists(c, h.userService, h.logger)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	tokens, err := h.feedService.ListTokens(c.Request.Context(), userID)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.FeedTokenListResponse{Tokens: tokens})
+}
+
+// RevokeFeedToken revokes one of the caller's feed tokens
+// @Summary Revoke a feed token
+// @Description Revoke a feed token; feeds using it respond 401 from then on
+// @Tags feeds
+// @Security Bearer
+// @Param id path string true "Feed token ID"
+// @Success 204
+// @Failure 401 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/users/me/feed-tokens/{id} [delete]
+func (h *FeedHandler) RevokeFeedToken(c *gin.Context) {
+	userID, err := ensureUserExists(c, h.userService, h.logger)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	if err := h.feedService.RevokeToken(c.Request.Context(), userID, c.Param("id")); err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// NotebookActivityRSS serves a notebook's activity as RSS
+// @Summary Notebook activity RSS feed
+// @Description Recent activity of a notebook and its documents as RSS 2.0, newest first
+// @Tags feeds
+// @Produce application/rss+xml
+// @Param id path string true "Notebook ID"
+// @Param token query string true "Feed token"
+// @Success 200 {string} string "RSS 2.0 feed"
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /feeds/notebooks/{id}/rss [get]
+func (h *FeedHandler) NotebookActivityRSS(c *gin.Context) {
+	h.notebookActivity(c, feeds.ContentTypeRSS, feeds.WriteRSS)
+}
+
+// NotebookActivityAtom serves a notebook's activity as Atom
+// @Summary Notebook activity Atom feed
+// @Description Recent activity of a notebook and its documents as Atom 1.0, newest first
+// @Tags feeds
+// @Produce application/atom+xml
+// @Param id path string true "Notebook ID"
+// @Param token query string true "Feed token"
+// @Success 200 {string} string "Atom 1.0 feed"
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /feeds/notebooks/{id}/atom [get]
+func (h *FeedHandler) NotebookActivityAtom(c *gin.Context) {
+	h.notebookActivity(c, feeds.ContentTypeAtom, feeds.WriteAtom)
+}
+
+// notebookActivity authenticates a notebook activity feed request and
+// writes the feed with write
+func (h *FeedHandler) notebookActivity(c *gin.Context, contentType string, write func(io.Writer, feeds.Feed) error) {
+	notebookID := c.Param("id")
+	token, err := h.feedService.Authenticate(c.Request.Context(), c.Query("token"), models.FeedTypeNotebookActivity, notebookID)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	notebook, events, err := h.feedService.NotebookActivity(c.Request.Context(), token, h.maxItems)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	feed := feeds.Feed{
+		ID:          "urn:uuid:" + notebook.ID,
+		Title:       notebook.Name + " activity",
+		Description: "Recent activity in the notebook " + notebook.Name,
+		Link:        h.feedBaseURL(c) + c.Request.URL.Path,
+		Author:      "Aether",
+		Updated:     time.Now().UTC(),
+		Items:       make([]feeds.Item, 0, len(events)),
+	}
+	if len(events) > 0 {
+		feed.Updated = events[0].CreatedAt
+	}
+	for _, event := range events {
+		feed.Items = append(feed.Items, activityItem(event))
+	}
+
+	h.writeFeed(c, contentType, func(buf *bytes.Buffer) error { return write(buf, feed) })
+}
+
+// activityItem describes an audit event as a feed entry
+func activityItem(event *models.AuditEvent) feeds.Item {
+	// notebook.created becomes "Notebook created"
+	title := strings.ReplaceAll(strings.ReplaceAll(event.Action, ".", " "), "_", " ")
+	if title != "" {
+		title = strings.ToUpper(title[:1]) + title[1:]
+	}
+	if name, ok := event.Details["name"].(string); ok && name != "" {
+		title += ": " + name
+	}
+
+	description := title
+	if event.ActorID != "" {
+		description += " by user " + event.ActorID
+	}
+	return feeds.Item{
+		ID:          "urn:uuid:" + event.ID,
+		Title:       title,
+		Description: description,
+		Published:   event.CreatedAt,
+	}
+}
+
+// ScheduledJobsCalendar serves the scheduled jobs as iCal
+// @Summary Scheduled jobs calendar
+// @Description The background jobs' runs as an iCal feed: the runs scheduled for the next week as tentative events and each job's recent runs, with their outcome, as confirmed ones
+// @Tags feeds
+// @Produce text/calendar
+// @Param token query string true "Feed token"
+// @Success 200 {string} string "iCalendar feed"
+// @Failure 401 {object} errors.APIError
+// @Router /feeds/jobs.ics [get]
+func (h *FeedHandler) ScheduledJobsCalendar(c *gin.Context) {
+	if _, err := h.feedService.Authenticate(c.Request.Context(), c.Query("token"), models.FeedTypeScheduledJobs, ""); err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	jobs, err := h.scheduler.Jobs(c.Request.Context())
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	now := time.Now().UTC()
+	cal := feeds.Calendar{
+		Name:        "Aether scheduled jobs",
+		Description: "Upcoming and recent runs of Aether's background jobs",
+		Generated:   now,
+	}
+	for _, job := range jobs {
+		runs, err := h.scheduler.JobRuns(c.Request.Context(), job.Name, feedRunHistory)
+		if err != nil {
+			middleware.WriteError(c, h.logger, err)
+			return
+		}
+
+		// Upcoming runs are shown as long as the last finished run took,
+		// and at least a minute
+		duration := time.Duration(0)
+		for _, run := range runs {
+			cal.Events = append(cal.Events, jobRunEvent(run))
+			if run.FinishedAt != nil && duration == 0 {
+				duration = time.Duration(run.DurationMs) * time.Millisecond
+			}
+		}
+		if duration < time.Minute {
+			duration = time.Minute
+		}
+
+		if job.NextRunAt == nil {
+			continue
+		}
+		upcoming := append([]time.Time{*job.NextRunAt}, h.scheduler.Upcoming(job.Name, *job.NextRunAt, now.Add(feedUpcomingWindow), feedUpcomingPerJob-1)...)
+		for _, start := range upcoming {
+			cal.Events = append(cal.Events, feeds.Event{
+				UID:         fmt.Sprintf("%s-%d@aether-scheduler", job.Name, start.Unix()),
+				Summary:     job.Name,
+				Description: "Scheduled run of " + job.Name + " (" + job.Schedule + ")",
+				Start:       start,
+				End:         start.Add(duration),
+				Status:      feeds.EventTentative,
+				Categories:  []string{"scheduled"},
+			})
+		}
+	}
+
+	h.writeFeed(c, feeds.ContentTypeICal, func(buf *bytes.Buffer) error { return feeds.WriteICal(buf, cal) })
+}
+
+// jobRunEvent describes a past or running job run as a calendar event
+func jobRunEvent(run *models.JobRun) feeds.Event {
+	event := feeds.Event{
+		UID:        run.ID + "@aether-scheduler",
+		Summary:    run.JobName + " (" + run.Status + ")",
+		Start:      run.StartedAt,
+		Status:     feeds.EventConfirmed,
+		Categories: []string{run.Status},
+	}
+	if run.FinishedAt != nil {
+		event.End = *run.FinishedAt
+	}
+	event.Description = "Run of " + run.JobName + " on " + run.InstanceID
+	if run.Error != "" {
+		event.Description += ": " + run.Error
+	}
+	return event
+}
+
+// writeFeed renders a feed before writing it, so a rendering failure is
+// still answered with an error response
+func (h *FeedHandler) writeFeed(c *gin.Context, contentType string, render func(*bytes.Buffer) error) {
+	var buf bytes.Buffer
+	if err := render(&buf); err != nil {
+		h.logger.Error("Failed to render feed", zap.String("path", c.FullPath()), zap.Error(err))
+		middleware.WriteError(c, h.logger, errors.Internal("Failed to render feed"))
+		return
+	}
+	// Feed URLs carry a token, so responses must not be cached by proxies
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// feedBaseURL returns the configured base URL of feed links, or the one
+// the request was made to
+func (h *FeedHandler) feedBaseURL(c *gin.Context) string {
+	if h.baseURL != "" {
+		return h.baseURL
+	}
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
+	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// GraphHandler serves views of the knowledge graph for
+// visualization
+type GraphHandler struct {
+	graphService *services.KnowledgeGraphService
+	logger       *logger.Logger
+}
+
+// NewGraphHandler creates a new knowledge graph handler
+func NewGraphHandler(graphService *services.KnowledgeGraphService, log *logger.Logger) *GraphHandler {
+	return &GraphHandler{
+		graphService: graphService,
+		logger:       log.WithService("graph_handler"),
+	}
+}
+
+// GetKnowledgeGraph returns the subgraph around a node
+// @Summary Get knowledge graph
+// @Description Get one page of the documents, notebooks, people, organizations and topics within depth relationships of a document, notebook or entity, nearest first, with the typed edges (BELONGS_TO, CONTAINS, MENTIONS, SIMILAR_TO) between the root and the page's nodes. Nodes the user may not see, such as documents of another user's private notebook, are left out and paths do not pass through them. Pass next_page_token as token for the next page, or a node's expand_token to centre the view on that node. Depth is capped by NEO4J_MAX_TRAVERSAL_DEPTH; traversals estimated to touch too many rows are refused with 422.
+// @Tags graph
+// @Produce json
+// @Security Bearer
+// @Param root_type query string false "Type of the root node" Enums(document, notebook, entity)
+// @Param root_id query string false "ID of the root node"
+// @Param depth query int false "Relationships to follow" default(1)
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param token query string false "Page or expansion token; replaces root_type, root_id and depth"
+// @Success 200 {object} models.GraphResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 422 {object} errors.APIError
+// @Router /api/v1/graph [get]
+func (h *GraphHandler) GetKnowledgeGraph(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == "" {
+		middleware.WriteError(c, h.logger, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
+		return
+	}
+
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return
+	}
+
+	depth := 1
+	if value := c.Query("depth"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			middleware.WriteError(c, h.logger, errors.Validation("depth must be a positive integer", nil))
+			return
+		}
+		depth = parsed
+	}
+
+	page := parsePaginationParams(c, pagination.DefaultLimit)
+	graph, err := h.graphService.GetGraph(c.Request.Context(), services.GraphQuery{
+		RootType: models.GraphRootType(c.Query("root_type")),
+		RootID:   c.Query("root_id"),
+		Depth:    depth,
+		Limit:    page.Limit,
+		Token:    c.Query("token"),
+	}, userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, graph)
+}
+
+// GetNotebookGraph returns the graph of a notebook's documents
+// @Summary Get notebook graph
+// @Description Get one page of the documents of a notebook the user may see, ordered by name, with the entities they mention most (up to limit) and the edges between them and the notebook for visualization: BELONGS_TO, MENTIONS, and SIMILAR_TO between documents of the page with their similarity score. Pass next_page_token as token for the next page, or a node's expand_token to /graph to centre a view on that node.
+// @Tags graph
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Notebook ID"
+// @Param limit query int false "Documents per page (max 100)" default(20)
+// @Param token query string false "Page token"
+// @Success 200 {object} models.GraphResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/notebooks/{id}/graph [get]
+func (h *GraphHandler) GetNotebookGraph(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == "" {
+		middleware.WriteError(c, h.logger, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
+		return
+	}
+
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return
+	}
+
+	page := parsePaginationParams(c, pagination.DefaultLimit)
+	graph, err := h.graphService.GetNotebookGraph(c.Request.Context(), c.Param("id"), page.Limit, c.Query("token"), userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, graph)
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tributary-ai-services/aether-be/internal/gql"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// GraphQLRequest is a GraphQL query
+type GraphQLRequest struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// GraphQLHandler serves the GraphQL endpoint
+type GraphQLHandler struct {
+	api         *gql.API
+	userService *services.UserService
+	logger      *logger.Logger
+}
+
+// NewGraphQLHandler creates a new GraphQL handler
+func NewGraphQLHandler(userService *services.UserService, log *logger.Logger) *GraphQLHandler {
+	return &GraphQLHandler{
+		userService: userService,
+		logger:      log.WithService("graphql_handler"),
+	}
+}
+
+// SetAPI enables the endpoint; without it it responds 503
+func (h *GraphQLHandler) SetAPI(api *gql.API) {
+	h.api = api
+}
+
+// Query runs a GraphQL query
+// @Summary Run a GraphQL query
+// @Description Query spaces, notebooks, documents and agents and their relationships in one request. Fields other than spaces and agent read from the space selected by the X-Space-Type and X-Space-ID headers. Permissions match the REST API. Query errors are returned with status 200 in the errors of the response, each with the code and error_code of the failure in its extensions; queries nested deeper than GRAPHQL_MAX_DEPTH are rejected.
+// @Tags graphql
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body GraphQLRequest true "Query"
+// @Success 200 {object} object
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/graphql [post]
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	if h.api == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("GraphQL is not enabled"))
+		return
+	}
+
+	var req GraphQLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.BadRequest("Invalid GraphQL request: a query is required"))
+		return
+	}
+
+	// Resolve Keycloak ID to internal user ID
+	userID, err := ensureUserExists(c, h.userService, h.logger)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	viewer := gql.Viewer{
+		UserID:     userID,
+		KeycloakID: getUserID(c),
+		AuthToken:  extractAuthToken(c),
+	}
+	if spaceContext, err := middleware.GetSpaceContext(c); err == nil {
+		viewer.SpaceContext = spaceContext
+	}
+
+	resp := h.api.Exec(c.Request.Context(), viewer, req.Query, req.OperationName, req.Variables)
+	c.JSON(http.StatusOK, resp)
+}
@@ -6,49 +6,50 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"go.uber.org/zap"
 
-	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/health"
 	"github.com/Tributary-ai-services/aether-be/internal/logger"
-	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
 )
 
 // HealthHandler handles health check requests
 type HealthHandler struct {
-	neo4j          *database.Neo4jClient
-	storageService *services.S3StorageService
-	kafkaService   *services.KafkaService
-	logger         *logger.Logger
+	registry *health.Registry
+	drainer  *middleware.Drainer
+	version  string
+	logger   *logger.Logger
 }
 
 // NewHealthHandler creates a new health handler
 func NewHealthHandler(
-	neo4j *database.Neo4jClient,
-	storageService *services.S3StorageService,
-	kafkaService *services.KafkaService,
+	registry *health.Registry,
+	drainer *middleware.Drainer,
+	version string,
 	log *logger.Logger,
 ) *HealthHandler {
 	return &HealthHandler{
-		neo4j:          neo4j,
-		storageService: storageService,
-		kafkaService:   kafkaService,
-		logger:         log.WithService("health_handler"),
+		registry: registry,
+		drainer:  drainer,
+		version:  version,
+		logger:   log.WithService("health_handler"),
 	}
 }
 
 // HealthResponse represents the health check response
 type HealthResponse struct {
-	Status    string                   `json:"status"`
-	Timestamp time.Time                `json:"timestamp"`
-	Version   string                   `json:"version,omitempty"`
-	Services  map[string]ServiceHealth `json:"services"`
+	Status    string                             `json:"status"`
+	Timestamp time.Time                          `json:"timestamp"`
+	Version   string                             `json:"version,omitempty"`
+	Services  map[string]health.DependencyStatus `json:"services"`
 }
 
-// ServiceHealth represents individual service health
-type ServiceHealth struct {
-	Status       string        `json:"status"`
-	ResponseTime time.Duration `json:"response_time_ms"`
-	Error        string        `json:"error,omitempty"`
+// SystemStatusResponse represents per-dependency status for operators
+type SystemStatusResponse struct {
+	Status       string                    `json:"status"`
+	Timestamp    time.Time                 `json:"timestamp"`
+	Version      string                    `json:"version,omitempty"`
+	Draining     bool                      `json:"draining"`
+	Dependencies []health.DependencyStatus `json:"dependencies"`
 }
 
 // LivenessCheck handles liveness probe
@@ -58,6 +59,7 @@ type ServiceHealth struct {
 // @Accept json
 // @Produce json
 // @Success 200 {object} map[string]string
+// @Router /healthz [get]
 // @Router /health/live [get]
 func (h *HealthHandler) LivenessCheck(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
@@ -66,7 +68,8 @@ func (h *HealthHandler) LivenessCheck(c *gin.Context) {
 	})
 }
 
-// ReadinessCheck handles readiness probe
+// ReadinessCheck handles readiness probe. Only critical dependencies are
+// checked, and the instance reports not ready while draining for shutdown.
 // @Summary Readiness check
 // @Description Check if the application is ready to serve requests
 // @Tags health
@@ -74,44 +77,21 @@ func (h *HealthHandler) LivenessCheck(c *gin.Context) {
 // @Produce json
 // @Success 200 {object} HealthResponse
 // @Failure 503 {object} HealthResponse
+// @Router /readyz [get]
 // @Router /health/ready [get]
 func (h *HealthHandler) ReadinessCheck(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
-
-	response := HealthResponse{
-		Timestamp: time.Now(),
-		Services:  make(map[string]ServiceHealth),
-	}
-
-	allHealthy := true
-
-	// Check Neo4j
-	neo4jHealth := h.checkNeo4j(ctx)
-	response.Services["neo4j"] = neo4jHealth
-	if neo4jHealth.Status != "healthy" {
-		allHealthy = false
-	}
-
-	// Check Storage Service
-	if h.storageService != nil {
-		storageHealth := h.checkStorage(ctx)
-		response.Services["storage"] = storageHealth
-		if storageHealth.Status != "healthy" {
-			allHealthy = false
-		}
+	if h.drainer != nil && h.drainer.Draining() {
+		c.JSON(http.StatusServiceUnavailable, HealthResponse{
+			Status:    "draining",
+			Timestamp: time.Now(),
+			Version:   h.version,
+			Services:  map[string]health.DependencyStatus{},
+		})
+		return
 	}
 
-	// Check Kafka Service
-	if h.kafkaService != nil {
-		kafkaHealth := h.checkKafka(ctx)
-		response.Services["kafka"] = kafkaHealth
-		if kafkaHealth.Status != "healthy" {
-			allHealthy = false
-		}
-	}
-
-	if allHealthy {
+	response, healthy := h.check(c.Request.Context(), true)
+	if healthy {
 		response.Status = "ready"
 		c.JSON(http.StatusOK, response)
 	} else {
@@ -122,7 +102,7 @@ func (h *HealthHandler) ReadinessCheck(c *gin.Context) {
 
 // HealthCheck handles comprehensive health check
 // @Summary Health check
-// @Description Comprehensive health check for all services
+// @Description Comprehensive health check for all dependencies
 // @Tags health
 // @Accept json
 // @Produce json
@@ -130,71 +110,66 @@ func (h *HealthHandler) ReadinessCheck(c *gin.Context) {
 // @Failure 503 {object} HealthResponse
 // @Router /health [get]
 func (h *HealthHandler) HealthCheck(c *gin.Context) {
-	// Delegate to readiness check for now
-	h.ReadinessCheck(c)
+	response, healthy := h.check(c.Request.Context(), false)
+	if healthy {
+		c.JSON(http.StatusOK, response)
+	} else {
+		c.JSON(http.StatusServiceUnavailable, response)
+	}
 }
 
-func (h *HealthHandler) checkNeo4j(ctx context.Context) ServiceHealth {
-	start := time.Now()
-
-	err := h.neo4j.HealthCheck(ctx)
-	responseTime := time.Since(start)
-
-	if err != nil {
-		h.logger.Error("Neo4j health check failed", zap.Error(err))
-		return ServiceHealth{
-			Status:       "unhealthy",
-			ResponseTime: responseTime,
-			Error:        err.Error(),
-		}
+// SystemStatus returns the latest health of every dependency with latency
+// and last error. Results come from the periodic checks run by the metrics
+// collector; pass refresh=true to check all dependencies now.
+// @Summary System status
+// @Description Per-dependency health with latency and last error
+// @Tags health
+// @Security Bearer
+// @Produce json
+// @Param refresh query bool false "Run checks now instead of returning cached results"
+// @Success 200 {object} SystemStatusResponse
+// @Router /api/v1/admin/system/status [get]
+func (h *HealthHandler) SystemStatus(c *gin.Context) {
+	dependencies := h.registry.Snapshot()
+	if c.Query("refresh") == "true" || hasUnchecked(dependencies) {
+		dependencies = h.registry.Check(c.Request.Context(), false)
 	}
 
-	return ServiceHealth{
-		Status:       "healthy",
-		ResponseTime: responseTime,
-	}
+	c.JSON(http.StatusOK, SystemStatusResponse{
+		Status:       health.Overall(dependencies),
+		Timestamp:    time.Now(),
+		Version:      h.version,
+		Draining:     h.drainer != nil && h.drainer.Draining(),
+		Dependencies: dependencies,
+	})
 }
 
-// Redis health check removed - no longer using Redis
-
-func (h *HealthHandler) checkStorage(ctx context.Context) ServiceHealth {
-	start := time.Now()
+// check runs dependency checks and reports whether all critical ones passed
+func (h *HealthHandler) check(ctx context.Context, criticalOnly bool) (HealthResponse, bool) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
 
-	err := h.storageService.HealthCheck(ctx)
-	responseTime := time.Since(start)
+	statuses := h.registry.Check(ctx, criticalOnly)
 
-	if err != nil {
-		h.logger.Error("Storage health check failed", zap.Error(err))
-		return ServiceHealth{
-			Status:       "unhealthy",
-			ResponseTime: responseTime,
-			Error:        err.Error(),
-		}
+	response := HealthResponse{
+		Status:    health.Overall(statuses),
+		Timestamp: time.Now(),
+		Version:   h.version,
+		Services:  make(map[string]health.DependencyStatus, len(statuses)),
 	}
-
-	return ServiceHealth{
-		Status:       "healthy",
-		ResponseTime: responseTime,
+	for _, status := range statuses {
+		response.Services[status.Name] = status
 	}
-}
 
-func (h *HealthHandler) checkKafka(ctx context.Context) ServiceHealth {
-	start := time.Now()
-
-	err := h.kafkaService.HealthCheck(ctx)
-	responseTime := time.Since(start)
+	return response, response.Status != health.StatusUnhealthy
+}
 
-	if err != nil {
-		h.logger.Error("Kafka health check failed", zap.Error(err))
-		return ServiceHealth{
-			Status:       "unhealthy",
-			ResponseTime: responseTime,
-			Error:        err.Error(),
+// hasUnchecked reports whether any dependency has not been checked yet
+func hasUnchecked(statuses []health.DependencyStatus) bool {
+	for _, status := range statuses {
+		if status.Status == health.StatusUnknown {
+			return true
 		}
 	}
-
-	return ServiceHealth{
-		Status:       "healthy",
-		ResponseTime: responseTime,
-	}
+	return false
 }
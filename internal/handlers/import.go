@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// ImportHandler serves imports of other tools' export archives
+type ImportHandler struct {
+	importService *services.ImportService
+	jobService    *services.JobService
+	logger        *logger.Logger
+
+	// maxArchiveBytes bounds the uploaded archive
+	maxArchiveBytes int64
+}
+
+// NewImportHandler creates a new import handler. Without a job service
+// imports are unavailable.
+func NewImportHandler(importService *services.ImportService, jobService *services.JobService, maxArchiveBytes int64, log *logger.Logger) *ImportHandler {
+	return &ImportHandler{
+		importService:   importService,
+		jobService:      jobService,
+		logger:          log.WithService("import_handler"),
+		maxArchiveBytes: maxArchiveBytes,
+	}
+}
+
+// importForm holds the fields of an import and the archive, written to a
+// temporary file
+type importForm struct {
+	fields   map[string]string
+	fileName string
+	filePath string
+}
+
+// readImportForm reads a multipart import part by part, writing the
+// "file" part to a temporary file instead of holding it in memory. The
+// caller removes the file.
+func readImportForm(r *http.Request, maxFileBytes int64) (*importForm, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+
+	form := &importForm{fields: make(map[string]string)}
+	fail := func(err error) (*importForm, error) {
+		if form.filePath != "" {
+			os.Remove(form.filePath)
+		}
+		return nil, err
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fail(err)
+		}
+
+		switch {
+		case part.FormName() == "file" && part.FileName() != "" && form.filePath == "":
+			file, err := os.CreateTemp("", "aether-import-*.zip")
+			if err != nil {
+				return fail(err)
+			}
+			form.fileName = part.FileName()
+			form.filePath = file.Name()
+			written, err := io.Copy(file, io.LimitReader(part, maxFileBytes+1))
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return fail(err)
+			}
+			if written > maxFileBytes {
+				return fail(errFileTooLarge)
+			}
+		case part.FileName() == "" && part.FormName() != "":
+			value, err := io.ReadAll(io.LimitReader(part, maxFormFieldBytes+1))
+			if err != nil {
+				return fail(err)
+			}
+			if len(value) > maxFormFieldBytes {
+				return fail(fmt.Errorf("form field %q exceeds %d bytes", part.FormName(), maxFormFieldBytes))
+			}
+			if _, seen := form.fields[part.FormName()]; !seen {
+				form.fields[part.FormName()] = string(value)
+			}
+		}
+		part.Close()
+	}
+
+	if form.filePath == "" {
+		return nil, http.ErrMissingFile
+	}
+	return form, nil
+}
+
+// StartImport imports an export archive as an async job
+// @Summary Import an export archive
+// @Description Import the zip export of another tool into a new notebook, as an async job. Formats are notion (a Notion "Markdown & CSV" export), google_drive (a Google Takeout archive of Drive, or a zipped Drive folder) and sharepoint (a zipped document library with the library's Export to CSV listing at the root of the archive). The archive's folders become notebooks under the new notebook and its files documents, submitted for processing like uploads. Every document keeps its path in the archive, its modification time and the metadata of its source, such as Notion page properties or SharePoint columns, in its metadata. The archive is checked before the job starts; the job result reports the document, the skip reason or the error of every archive entry, and items fail on their own. Archives are limited to MAX_IMPORT_BYTES and MAX_IMPORT_FILES files, and each file to MAX_UPLOAD_BYTES. Poll the job at the Location header.
+// @Tags imports
+// @Accept multipart/form-data
+// @Produce json
+// @Security Bearer
+// @Param format formData string true "Export format" Enums(notion, google_drive, sharepoint)
+// @Param file formData file true "Export archive (zip)"
+// @Param name formData string false "Name of the notebook created for the import; defaults to the archive's file name"
+// @Param notebook_id formData string false "Notebook to create the import's notebook in"
+// @Success 202 {object} models.Job
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 413 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/imports [post]
+func (h *ImportHandler) StartImport(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == "" {
+		middleware.WriteError(c, h.logger, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
+		return
+	}
+
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return
+	}
+
+	if h.jobService == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Import jobs are not available"))
+		return
+	}
+
+	form, err := readImportForm(c.Request, h.maxArchiveBytes)
+	switch {
+	case err == errFileTooLarge:
+		middleware.WriteError(c, h.logger, errors.Validation(fmt.Sprintf("Archive too large (max %s)", formatByteLimit(h.maxArchiveBytes)), nil).WithErrorCode(errors.CodeFileTooLarge))
+		return
+	case middleware.IsBodyTooLarge(err):
+		middleware.WriteError(c, h.logger, err)
+		return
+	case err == http.ErrMissingFile:
+		middleware.WriteError(c, h.logger, errors.Validation("An archive file is required", nil))
+		return
+	case err != nil:
+		h.logger.Error("Failed to parse import form", zap.Error(err))
+		middleware.WriteError(c, h.logger, errors.Validation("Invalid multipart form", nil))
+		return
+	}
+
+	name := form.fields["name"]
+	if name == "" {
+		name = form.fileName
+	}
+
+	// Fail fast on bad archives rather than in a job the client has to poll
+	archive, err := h.importService.Open(c.Request.Context(), form.fields["format"], form.filePath, name, form.fields["notebook_id"], userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	job, err := h.jobService.Start(c.Request.Context(), models.JobTypeImport, spaceContext.TenantID, userID, func(ctx context.Context, progress func(models.JobProgress)) (map[string]interface{}, error) {
+		defer archive.Close()
+		result, err := h.importService.Run(ctx, archive, userID, spaceContext, progress)
+		if err != nil {
+			return nil, err
+		}
+		return jobResult(result)
+	})
+	if err != nil {
+		archive.Close()
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	respondAccepted(c, job)
+}
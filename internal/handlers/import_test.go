@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newImportRequest builds an import of an archive with the given contents
+func newImportRequest(t *testing.T, contents string) *http.Request {
+	t.Helper()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	require.NoError(t, writer.WriteField("format", "notion"))
+	part, err := writer.CreateFormFile("file", "Export.zip")
+	require.NoError(t, err)
+	_, err = part.Write([]byte(contents))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/imports", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestReadImportForm(t *testing.T) {
+	form, err := readImportForm(newImportRequest(t, "zip contents"), 20)
+	require.NoError(t, err)
+	defer os.Remove(form.filePath)
+	assert.Equal(t, "notion", form.fields["format"])
+	assert.Equal(t, "Export.zip", form.fileName)
+	data, err := os.ReadFile(form.filePath)
+	require.NoError(t, err)
+	assert.Equal(t, "zip contents", string(data))
+
+	_, err = readImportForm(newImportRequest(t, "zip contents over the limit"), 20)
+	assert.Equal(t, errFileTooLarge, err)
+}
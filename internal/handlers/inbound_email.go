@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/internal/webhooks"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// snsConfirmer confirms SNS subscriptions
+type snsConfirmer interface {
+	Confirm(ctx context.Context, message webhooks.SNSMessage) error
+}
+
+// InboundEmailHandler serves the inbound email addresses of notebooks and
+// receives their mail from SES through SNS
+type InboundEmailHandler struct {
+	emailService *services.InboundEmailService
+	sns          snsConfirmer
+	userService  *services.UserService
+	logger       *logger.Logger
+}
+
+// NewInboundEmailHandler creates a new inbound email handler
+func NewInboundEmailHandler(emailService *services.InboundEmailService, sns snsConfirmer, userService *services.UserService, log *logger.Logger) *InboundEmailHandler {
+	return &InboundEmailHandler{
+		emailService: emailService,
+		sns:          sns,
+		userService:  userService,
+		logger:       log.WithService("inbound_email_handler"),
+	}
+}
+
+// CreateInboundEmail gives a notebook a new inbound email address
+// @Summary Create notebook inbound email address
+// @Description Give a notebook a new inbound email address, replacing the one it had. The body and attachments of mail sent to the address are added to the notebook as documents named after the subject, owned by the notebook's owner. allowed_senders limits who may send, by address or @domain. Only the notebook's owner manages its address. Fails with 503 and AETHER-EMAIL-001 when inbound email is not configured.
+// @Tags notebooks
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Notebook ID"
+// @Param request body models.InboundEmailRequest false "Allowed senders"
+// @Success 201 {object} models.InboundEmailAddress
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/notebooks/{id}/inbound-email [post]
+func (h *InboundEmailHandler) CreateInboundEmail(c *gin.Context) {
+	userID, spaceContext, ok := requireInternalUserAndSpace(c, h.userService, h.logger)
+	if !ok {
+		return
+	}
+
+	var req models.InboundEmailRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middleware.WriteError(c, h.logger, errors.Validation("Invalid request payload", err))
+			return
+		}
+	}
+	if err := validateStruct(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Validation failed", err))
+		return
+	}
+
+	address, err := h.emailService.CreateAddress(c.Request.Context(), c.Param("id"), req, userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, address)
+}
+
+// GetInboundEmail returns the inbound email address of a notebook
+// @Summary Get notebook inbound email address
+// @Description Get the inbound email address of a notebook. Fails with 404 and AETHER-EMAIL-002 when it has none.
+// @Tags notebooks
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Notebook ID"
+// @Success 200 {object} models.InboundEmailAddress
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/notebooks/{id}/inbound-email [get]
+func (h *InboundEmailHandler) GetInboundEmail(c *gin.Context) {
+	userID, spaceContext, ok := requireInternalUserAndSpace(c, h.userService, h.logger)
+	if !ok {
+		return
+	}
+
+	address, err := h.emailService.GetAddress(c.Request.Context(), c.Param("id"), userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, address)
+}
+
+// DeleteInboundEmail removes the inbound email address of a notebook
+// @Summary Delete notebook inbound email address
+// @Description Remove the inbound email address of a notebook; mail sent to it is rejected from then on.
+// @Tags notebooks
+// @Security Bearer
+// @Param id path string true "Notebook ID"
+// @Success 204
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/notebooks/{id}/inbound-email [delete]
+func (h *InboundEmailHandler) DeleteInboundEmail(c *gin.Context) {
+	userID, spaceContext, ok := requireInternalUserAndSpace(c, h.userService, h.logger)
+	if !ok {
+		return
+	}
+
+	if err := h.emailService.DeleteAddress(c.Request.Context(), c.Param("id"), userID, spaceContext); err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// InboundEmailWebhook receives the mail of notebook addresses
+// @Summary Inbound email webhook
+// @Description HTTPS subscription of the SNS topic INBOUND_EMAIL_SNS_TOPIC_ARN, to which an SES receipt rule publishes the mail of INBOUND_EMAIL_DOMAIN. Messages are verified with the SNS signing certificate and must come from the topic. A subscription confirmation is confirmed. A received email is added to the notebooks it is addressed to; mail that cannot be added, such as spam, mail over INBOUND_EMAIL_MAX_BYTES or mail to unknown addresses, is rejected with 200 and its reason so SNS does not deliver it again.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param message body webhooks.SNSMessage true "SNS message"
+// @Success 200 {object} models.InboundEmailResult
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 500 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /webhooks/email [post]
+func (h *InboundEmailHandler) InboundEmailWebhook(c *gin.Context) {
+	var message webhooks.SNSMessage
+	if err := webhooks.DecodePayload(middleware.WebhookBody(c), &message); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Invalid webhook payload", err))
+		return
+	}
+
+	switch message.Type {
+	case webhooks.SNSSubscriptionConfirmation:
+		if err := h.sns.Confirm(c.Request.Context(), message); err != nil {
+			h.logger.Error("Failed to confirm SNS subscription", zap.String("topic_arn", message.TopicArn), zap.Error(err))
+			middleware.WriteError(c, h.logger, errors.ExternalService("Failed to confirm SNS subscription", err))
+			return
+		}
+		h.logger.Info("Confirmed SNS subscription", zap.String("topic_arn", message.TopicArn))
+		c.JSON(http.StatusOK, gin.H{"message": "Subscription confirmed"})
+	case webhooks.SNSNotification:
+		result, err := h.emailService.ReceiveSESNotification(c.Request.Context(), message.Message)
+		if err != nil {
+			middleware.WriteError(c, h.logger, err)
+			return
+		}
+		c.JSON(http.StatusOK, result)
+	default:
+		c.JSON(http.StatusOK, gin.H{"message": "Message ignored"})
+	}
+}
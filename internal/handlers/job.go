@@ -2,41 +2,48 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
 	"github.com/Tributary-ai-services/aether-be/internal/logger"
 	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
 	"github.com/Tributary-ai-services/aether-be/internal/services"
 	"github.com/Tributary-ai-services/aether-be/pkg/errors"
 )
 
 // JobHandler handles job-related HTTP requests
 type JobHandler struct {
-	documentService *services.DocumentService
+	jobService       *services.JobService
+	documentService  *services.DocumentService
 	audiModalService *services.AudiModalService
-	logger          *logger.Logger
+	logger           *logger.Logger
 }
 
 // NewJobHandler creates a new job handler
-func NewJobHandler(documentService *services.DocumentService, audiModalService *services.AudiModalService, log *logger.Logger) *JobHandler {
+func NewJobHandler(jobService *services.JobService, documentService *services.DocumentService, audiModalService *services.AudiModalService, log *logger.Logger) *JobHandler {
 	return &JobHandler{
-		documentService: documentService,
+		jobService:       jobService,
+		documentService:  documentService,
 		audiModalService: audiModalService,
-		logger:          log.WithService("job_handler"),
+		logger:           log.WithService("job_handler"),
 	}
 }
 
-// GetJobStatus gets the status of a generic job by ID
+// GetJobStatus gets the status of an async job by ID
 // @Summary Get job status
-// @Description Get real-time status for any processing job by job ID
+// @Description Get the status, progress and, once finished, the result or error of an async job started by the caller. IDs of AudiModal processing jobs are also accepted and reported as document_processing jobs.
 // @Tags jobs
 // @Produce json
 // @Security Bearer
 // @Param id path string true "Job ID"
-// @Success 200 {object} map[string]interface{}
+// @Success 200 {object} models.Job
 // @Failure 400 {object} errors.APIError
 // @Failure 401 {object} errors.APIError
 // @Failure 404 {object} errors.APIError
@@ -51,57 +58,182 @@ func (h *JobHandler) GetJobStatus(c *gin.Context) {
 
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
 	h.logger.Info("Getting job status", zap.String("job_id", jobID), zap.String("user_id", userID))
 
-	// Try to get status from AudiModal first (as processing jobs are typically there)
-	status, err := h.getAudiModalJobStatus(c.Request.Context(), jobID, spaceContext.TenantID)
-	if err != nil {
-		h.logger.Error("Failed to get job status", zap.String("job_id", jobID), zap.Error(err))
-		c.JSON(http.StatusNotFound, errors.NotFound("Job not found"))
+	job, err := h.jobService.GetJob(c.Request.Context(), jobID, userID)
+	if err == nil {
+		c.JSON(http.StatusOK, job)
+		return
+	}
+	if !errors.IsNotFound(err) {
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, status)
+	// Not an async job of ours; processing jobs are tracked by AudiModal
+	c.JSON(http.StatusOK, h.getAudiModalJobStatus(c.Request.Context(), jobID, spaceContext.TenantID, userID))
 }
 
-// getAudiModalJobStatus gets job status from AudiModal service
-func (h *JobHandler) getAudiModalJobStatus(ctx context.Context, jobID string, tenantID string) (map[string]interface{}, error) {
-	// Use the AudiModal service to get file status (since most jobs are processing jobs)
+// getAudiModalJobStatus reports an AudiModal processing job in the shape of
+// an async job. AudiModal does not expose job state, so a file without
+// chunks yet is reported as running.
+func (h *JobHandler) getAudiModalJobStatus(ctx context.Context, jobID string, tenantID string, userID string) *models.Job {
+	job := &models.Job{
+		ID:        jobID,
+		Type:      models.JobTypeDocumentProcessing,
+		Status:    models.JobStatusRunning,
+		TenantID:  tenantID,
+		CreatedBy: userID,
+	}
 
-	// First try to get file chunks to see if this is a completed processing job
 	chunks, err := h.audiModalService.GetFileChunks(ctx, tenantID, jobID, 10, 0) // Get first 10 chunks
 	if err == nil && chunks != nil && len(chunks.Data) > 0 {
 		// Job completed successfully - we have chunks
-		return map[string]interface{}{
-			"job_id":        jobID,
-			"status":        "completed",
-			"progress":      100.0,
-			"chunks_count":  len(chunks.Data),
-			"total_chunks":  chunks.Total,
-			"job_type":      "document_processing",
-		}, nil
-	}
-
-	// For now, return a basic status structure since we can't easily check AudiModal health
-	// In a full implementation, we'd have a proper job tracking system
-	return map[string]interface{}{
-		"job_id":      jobID,
-		"status":      "processing", // Could be: queued, processing, completed, failed
-		"progress":    50.0,         // Percentage complete
-		"job_type":    "document_processing",
-		"started_at":  nil,
-		"estimated_completion": nil,
-		"error":       nil,
-	}, nil
+		job.Status = models.JobStatusSucceeded
+		job.Progress = models.JobProgress{Total: chunks.Total, Completed: chunks.Total}
+		job.Result = map[string]interface{}{
+			"chunks_count": len(chunks.Data),
+			"total_chunks": chunks.Total,
+		}
+	}
+	return job
+}
+
+// ListJobs lists the caller's async jobs
+// @Summary List jobs
+// @Description List the async jobs started by the caller, newest first. Finished jobs are listed until their retention expires.
+// @Tags jobs
+// @Produce json
+// @Security Bearer
+// @Param type query string false "Only jobs of this type" Enums(batch, notebook_export, organization_delete, tenant_export, import, legal_hold_export)
+// @Param status query string false "Only jobs in this status" Enums(queued, running, succeeded, failed)
+// @Param limit query int false "Maximum number of jobs to return" default(20)
+// @Param offset query int false "Number of jobs to skip" default(0)
+// @Success 200 {object} models.JobListResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 500 {object} errors.APIError
+// @Router /api/v1/jobs [get]
+func (h *JobHandler) ListJobs(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
+		return
+	}
+
+	status := models.JobStatus(c.Query("status"))
+	switch status {
+	case "", models.JobStatusQueued, models.JobStatusRunning, models.JobStatusSucceeded, models.JobStatusFailed:
+	default:
+		c.JSON(http.StatusBadRequest, errors.ValidationWithDetails("Invalid job status", map[string]interface{}{
+			"param": "status",
+		}))
+		return
+	}
+
+	params := parsePaginationParams(c, pagination.DefaultLimit)
+	jobs, hasMore, err := h.jobService.ListJobs(c.Request.Context(), userID, models.JobType(c.Query("type")), status, params.Limit, params.Offset)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.JobListResponse{
+		Jobs:    jobs,
+		Limit:   params.Limit,
+		Offset:  params.Offset,
+		HasMore: hasMore,
+	})
+}
+
+// ListProcessingJobs lists the document processing jobs of the space
+// @Summary List processing jobs
+// @Description List the document processing jobs of the current space's tenant, newest first. Jobs move from pending to processing and end completed, failed or cancelled.
+// @Tags jobs
+// @Produce json
+// @Security Bearer
+// @Param document_id query string false "Only jobs of this document"
+// @Param status query string false "Only jobs in this status" Enums(pending, processing, completed, failed, cancelled)
+// @Param limit query int false "Maximum number of jobs to return" default(20)
+// @Param offset query int false "Number of jobs to skip" default(0)
+// @Success 200 {object} models.ProcessingJobListResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 500 {object} errors.APIError
+// @Router /api/v1/processing-jobs [get]
+func (h *JobHandler) ListProcessingJobs(c *gin.Context) {
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return
+	}
+
+	status := c.Query("status")
+	switch status {
+	case "", models.ProcessingJobPending, models.ProcessingJobProcessing, models.ProcessingJobCompleted, models.ProcessingJobFailed, models.ProcessingJobCancelled:
+	default:
+		c.JSON(http.StatusBadRequest, errors.ValidationWithDetails("Invalid processing job status", map[string]interface{}{
+			"param": "status",
+		}))
+		return
+	}
+
+	params := parsePaginationParams(c, pagination.DefaultLimit)
+	jobs, hasMore, err := h.documentService.ListProcessingJobs(c.Request.Context(), c.Query("document_id"), status, spaceContext, params.Limit, params.Offset)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ProcessingJobListResponse{
+		Jobs:    jobs,
+		Limit:   params.Limit,
+		Offset:  params.Offset,
+		HasMore: hasMore,
+	})
+}
+
+// prefersAsync reports whether the client asked for an async response
+// with a "Prefer: respond-async" header (RFC 7240)
+func prefersAsync(c *gin.Context) bool {
+	for _, value := range c.Request.Header.Values("Prefer") {
+		for _, preference := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(preference), "respond-async") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// respondAccepted answers a request that started an async job with 202
+// and the job, which the client polls at its Location
+func respondAccepted(c *gin.Context, job *models.Job) {
+	c.Header("Location", fmt.Sprintf("/api/v1/jobs/%s", job.ID))
+	c.JSON(http.StatusAccepted, job)
+}
+
+// jobResult converts a typed result into the generic result of a job
+func jobResult(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
\ No newline at end of file
@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// LegalHoldHandler handles the e-discovery workflow of administrators:
+// placing and releasing legal holds, and collecting and exporting held
+// content
+type LegalHoldHandler struct {
+	legalHolds *services.LegalHoldService
+	jobs       *services.JobService
+	logger     *logger.Logger
+}
+
+// NewLegalHoldHandler creates a new legal hold handler
+func NewLegalHoldHandler(legalHolds *services.LegalHoldService, jobs *services.JobService, log *logger.Logger) *LegalHoldHandler {
+	return &LegalHoldHandler{
+		legalHolds: legalHolds,
+		jobs:       jobs,
+		logger:     log.WithService("legal_hold_handler"),
+	}
+}
+
+// CreateLegalHold places users and notebooks under a legal hold
+// @Summary Create a legal hold
+// @Description Place users and notebooks of a tenant under an e-discovery hold. While the hold is active, the documents of its notebooks and the documents and notebooks its users own cannot be edited, versioned, deleted or purged from the trash (409 with AETHER-HOLD-001), and the trash keeps them past its retention; organizations with a hold in their spaces cannot be deleted. Unknown users or notebooks respond 400 listing them. The hold is recorded in the audit log.
+// @Tags admin
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body models.LegalHoldCreateRequest true "Hold"
+// @Success 201 {object} models.LegalHold
+// @Failure 400 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/admin/legal-holds [post]
+func (h *LegalHoldHandler) CreateLegalHold(c *gin.Context) {
+	var req models.LegalHoldCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Invalid request body", err))
+		return
+	}
+	if err := validateStruct(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Validation failed", err))
+		return
+	}
+
+	hold, err := h.legalHolds.CreateHold(c.Request.Context(), req, getUserID(c))
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+	c.JSON(http.StatusCreated, hold)
+}
+
+// ListLegalHolds lists legal holds
+// @Summary List legal holds
+// @Description List one page of legal holds, newest first, optionally of one tenant and with one status.
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Param tenant_id query string false "Tenant ID"
+// @Param status query string false "Hold status" Enums(active, released)
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param offset query int false "Page offset" default(0)
+// @Success 200 {object} models.LegalHoldListResponse
+// @Failure 400 {object} errors.APIError
+// @Router /api/v1/admin/legal-holds [get]
+func (h *LegalHoldHandler) ListLegalHolds(c *gin.Context) {
+	params := parsePaginationParams(c, 20)
+	holds, err := h.legalHolds.ListHolds(c.Request.Context(), c.Query("tenant_id"), models.LegalHoldStatus(c.Query("status")), params.Limit, params.Offset)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+	c.JSON(http.StatusOK, holds)
+}
+
+// GetLegalHold returns a legal hold
+// @Summary Get a legal hold
+// @Description Get a legal hold with the users and notebooks it holds.
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Param hold_id path string true "Legal hold ID"
+// @Success 200 {object} models.LegalHold
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/admin/legal-holds/{hold_id} [get]
+func (h *LegalHoldHandler) GetLegalHold(c *gin.Context) {
+	hold, err := h.legalHolds.GetHold(c.Request.Context(), c.Param("hold_id"))
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+	c.JSON(http.StatusOK, hold)
+}
+
+// ReleaseLegalHold releases a legal hold
+// @Summary Release a legal hold
+// @Description Release a legal hold, saying why. Its content can be edited and deleted again unless another active hold covers it. The hold is kept, released, for the record, and the release is recorded in the audit log. A hold already released responds 409 with AETHER-HOLD-003.
+// @Tags admin
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param hold_id path string true "Legal hold ID"
+// @Param request body models.LegalHoldReleaseRequest true "Release"
+// @Success 200 {object} models.LegalHold
+// @Failure 400 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 409 {object} errors.APIError
+// @Router /api/v1/admin/legal-holds/{hold_id}/release [post]
+func (h *LegalHoldHandler) ReleaseLegalHold(c *gin.Context) {
+	var req models.LegalHoldReleaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Invalid request body", err))
+		return
+	}
+	if err := validateStruct(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Validation failed", err))
+		return
+	}
+
+	hold, err := h.legalHolds.ReleaseHold(c.Request.Context(), c.Param("hold_id"), req.Reason, getUserID(c))
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+	c.JSON(http.StatusOK, hold)
+}
+
+// bindCriteria reads the collection criteria of a request, writing the
+// error response when they are invalid
+func (h *LegalHoldHandler) bindCriteria(c *gin.Context) (models.LegalHoldCriteria, bool) {
+	var criteria models.LegalHoldCriteria
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&criteria); err != nil {
+			middleware.WriteError(c, h.logger, errors.Validation("Invalid request body", err))
+			return criteria, false
+		}
+	}
+	if err := validateStruct(&criteria); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Validation failed", err))
+		return criteria, false
+	}
+	return criteria, true
+}
+
+// CollectLegalHold searches the content a legal hold covers
+// @Summary Collect held documents
+// @Description Search the documents an active legal hold covers, including those in the trash, for any of the keywords (whole words, ignoring case, in the name, description, tags or extracted text, decrypted for spaces that encrypt text) uploaded in the time range, which includes from and excludes to. Without criteria every held document matches. Returns one page of matches, oldest first, with the keywords each contains and the total. A released hold responds 409 with AETHER-HOLD-003.
+// @Tags admin
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param hold_id path string true "Legal hold ID"
+// @Param request body models.LegalHoldCriteria false "Collection criteria"
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param offset query int false "Page offset" default(0)
+// @Success 200 {object} models.LegalHoldCollection
+// @Failure 400 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 409 {object} errors.APIError
+// @Router /api/v1/admin/legal-holds/{hold_id}/collect [post]
+func (h *LegalHoldHandler) CollectLegalHold(c *gin.Context) {
+	criteria, ok := h.bindCriteria(c)
+	if !ok {
+		return
+	}
+
+	params := parsePaginationParams(c, 20)
+	collection, err := h.legalHolds.Collect(c.Request.Context(), c.Param("hold_id"), criteria, params.Limit, params.Offset)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+	c.JSON(http.StatusOK, collection)
+}
+
+// StartLegalHoldExport exports the content a collection finds as an async
+// job
+// @Summary Start a legal hold export job
+// @Description Collect the documents an active legal hold covers that match the criteria, as POST /api/v1/admin/legal-holds/{hold_id}/collect does, and export them to a bundle in object storage with their records, extracted text, stored files of every version and audit events. Responds 202 with a legal_hold_export job whose progress counts the documents and files written; once it has succeeded its result gives the bundle prefix, the SHA-256 hash of its manifest and a download URL for it. The manifest records the chain of custody: the hold and criteria, who collected the bundle and when, the hash of every file and the custody steps. Its hash is recorded in the audit log.
+// @Tags admin
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param hold_id path string true "Legal hold ID"
+// @Param request body models.LegalHoldCriteria false "Collection criteria"
+// @Success 202 {object} models.Job
+// @Failure 400 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 409 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/admin/legal-holds/{hold_id}/export [post]
+func (h *LegalHoldHandler) StartLegalHoldExport(c *gin.Context) {
+	if h.jobs == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Legal hold exports are not available"))
+		return
+	}
+	criteria, ok := h.bindCriteria(c)
+	if !ok {
+		return
+	}
+
+	holdID := c.Param("hold_id")
+	userID := getUserID(c)
+
+	// Fail fast on an unknown or released hold rather than in a job the
+	// client has to poll
+	hold, err := h.legalHolds.CheckExport(c.Request.Context(), holdID, criteria)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	job, err := h.jobs.Start(c.Request.Context(), models.JobTypeLegalHoldExport, hold.TenantID, userID, func(ctx context.Context, progress func(models.JobProgress)) (map[string]interface{}, error) {
+		export, err := h.legalHolds.Export(ctx, holdID, criteria, userID, progress)
+		if err != nil {
+			return nil, err
+		}
+		return jobResult(export)
+	})
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	h.logger.Info("Legal hold export started by admin",
+		zap.String("user_id", userID),
+		zap.String("hold_id", holdID),
+		zap.String("job_id", job.ID),
+	)
+	respondAccepted(c, job)
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tributary-ai-services/aether-be/internal/i18n"
+)
+
+// LocaleHandler serves the localized labels clients display for enumerated
+// values
+type LocaleHandler struct{}
+
+// NewLocaleHandler creates a new locale handler
+func NewLocaleHandler() *LocaleHandler {
+	return &LocaleHandler{}
+}
+
+// EnumerationsResponse lists the labels of enumerations in a locale
+type EnumerationsResponse struct {
+	Locale           string                       `json:"locale"`
+	SupportedLocales []string                     `json:"supported_locales"`
+	Enumerations     map[string]map[string]string `json:"enumerations"`
+}
+
+// GetEnumerations returns the labels of enumerated values in the request's
+// locale
+// @Summary Get enumeration labels
+// @Description Get the labels of the values of document_status, notebook_status, notebook_visibility, agent_status, role, share_role and job_status, by enumeration and value, in the locale negotiated from Accept-Language. The locale is the first of the client's languages, by quality, that is supported or whose base language is (de for de-AT), and English otherwise; it is returned in Content-Language. Labels missing from a locale fall back to English.
+// @Tags i18n
+// @Security Bearer
+// @Produce json
+// @Param Accept-Language header string false "Preferred languages, such as de-DE,de;q=0.9,en;q=0.5"
+// @Success 200 {object} EnumerationsResponse
+// @Router /api/v1/i18n/enumerations [get]
+func (h *LocaleHandler) GetEnumerations(c *gin.Context) {
+	locale := i18n.FromContext(c.Request.Context())
+	c.JSON(http.StatusOK, EnumerationsResponse{
+		Locale:           locale,
+		SupportedLocales: i18n.Supported(),
+		Enumerations:     i18n.Enumerations(locale),
+	})
+}
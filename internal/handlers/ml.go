@@ -50,14 +50,14 @@ func (h *MLHandler) CreateModel(c *gin.Context) {
 
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -69,7 +69,7 @@ func (h *MLHandler) CreateModel(c *gin.Context) {
 	model, err := h.mlService.CreateModel(c.Request.Context(), req, userID, spaceContext)
 	if err != nil {
 		h.logger.Error("Failed to create ML model", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, errors.Internal("Failed to create ML model"))
+		middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to create ML model", err))
 		return
 	}
 
@@ -91,14 +91,14 @@ func (h *MLHandler) CreateModel(c *gin.Context) {
 func (h *MLHandler) GetModels(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -124,7 +124,7 @@ func (h *MLHandler) GetModels(c *gin.Context) {
 	models, total, err := h.mlService.GetModels(c.Request.Context(), spaceContext, limit, offset)
 	if err != nil {
 		h.logger.Error("Failed to get ML models", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, errors.Internal("Failed to get ML models"))
+		middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to get ML models", err))
 		return
 	}
 
@@ -159,14 +159,14 @@ func (h *MLHandler) GetModel(c *gin.Context) {
 
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -175,7 +175,7 @@ func (h *MLHandler) GetModel(c *gin.Context) {
 	model, err := h.mlService.GetModelByID(c.Request.Context(), modelID, spaceContext)
 	if err != nil {
 		h.logger.Error("Failed to get ML model", zap.String("model_id", modelID), zap.Error(err))
-		c.JSON(http.StatusNotFound, errors.NotFound("ML model not found"))
+		c.JSON(http.StatusNotFound, errors.NotFound("ML model not found").WithErrorCode(errors.CodeMLModelNotFound))
 		return
 	}
 
@@ -212,14 +212,14 @@ func (h *MLHandler) UpdateModel(c *gin.Context) {
 
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -229,9 +229,9 @@ func (h *MLHandler) UpdateModel(c *gin.Context) {
 	if err != nil {
 		h.logger.Error("Failed to update ML model", zap.String("model_id", modelID), zap.Error(err))
 		if err.Error() == "ML model not found" {
-			c.JSON(http.StatusNotFound, errors.NotFound("ML model not found"))
+			c.JSON(http.StatusNotFound, errors.NotFound("ML model not found").WithErrorCode(errors.CodeMLModelNotFound))
 		} else {
-			c.JSON(http.StatusInternalServerError, errors.Internal("Failed to update ML model"))
+			middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to update ML model", err))
 		}
 		return
 	}
@@ -259,14 +259,14 @@ func (h *MLHandler) DeleteModel(c *gin.Context) {
 
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -276,9 +276,9 @@ func (h *MLHandler) DeleteModel(c *gin.Context) {
 	if err != nil {
 		h.logger.Error("Failed to delete ML model", zap.String("model_id", modelID), zap.Error(err))
 		if err.Error() == "ML model not found" {
-			c.JSON(http.StatusNotFound, errors.NotFound("ML model not found"))
+			c.JSON(http.StatusNotFound, errors.NotFound("ML model not found").WithErrorCode(errors.CodeMLModelNotFound))
 		} else {
-			c.JSON(http.StatusInternalServerError, errors.Internal("Failed to delete ML model"))
+			middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to delete ML model", err))
 		}
 		return
 	}
@@ -306,14 +306,14 @@ func (h *MLHandler) DeployModel(c *gin.Context) {
 
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -323,9 +323,9 @@ func (h *MLHandler) DeployModel(c *gin.Context) {
 	if err != nil {
 		h.logger.Error("Failed to deploy ML model", zap.String("model_id", modelID), zap.Error(err))
 		if err.Error() == "ML model not found" {
-			c.JSON(http.StatusNotFound, errors.NotFound("ML model not found"))
+			c.JSON(http.StatusNotFound, errors.NotFound("ML model not found").WithErrorCode(errors.CodeMLModelNotFound))
 		} else {
-			c.JSON(http.StatusInternalServerError, errors.Internal("Failed to deploy ML model"))
+			middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to deploy ML model", err))
 		}
 		return
 	}
@@ -355,14 +355,14 @@ func (h *MLHandler) CreateExperiment(c *gin.Context) {
 
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -377,7 +377,7 @@ func (h *MLHandler) CreateExperiment(c *gin.Context) {
 		if err.Error() == "model not found: ML model not found" {
 			c.JSON(http.StatusBadRequest, errors.BadRequest("Model not found"))
 		} else {
-			c.JSON(http.StatusInternalServerError, errors.Internal("Failed to create ML experiment"))
+			middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to create ML experiment", err))
 		}
 		return
 	}
@@ -400,14 +400,14 @@ func (h *MLHandler) CreateExperiment(c *gin.Context) {
 func (h *MLHandler) GetExperiments(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -433,7 +433,7 @@ func (h *MLHandler) GetExperiments(c *gin.Context) {
 	experiments, total, err := h.mlService.GetExperiments(c.Request.Context(), spaceContext, limit, offset)
 	if err != nil {
 		h.logger.Error("Failed to get ML experiments", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, errors.Internal("Failed to get ML experiments"))
+		middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to get ML experiments", err))
 		return
 	}
 
@@ -468,14 +468,14 @@ func (h *MLHandler) GetExperiment(c *gin.Context) {
 
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -484,7 +484,7 @@ func (h *MLHandler) GetExperiment(c *gin.Context) {
 	experiment, err := h.mlService.GetExperimentByID(c.Request.Context(), experimentID, spaceContext)
 	if err != nil {
 		h.logger.Error("Failed to get ML experiment", zap.String("experiment_id", experimentID), zap.Error(err))
-		c.JSON(http.StatusNotFound, errors.NotFound("ML experiment not found"))
+		c.JSON(http.StatusNotFound, errors.NotFound("ML experiment not found").WithErrorCode(errors.CodeMLExperimentNotFound))
 		return
 	}
 
@@ -521,14 +521,14 @@ func (h *MLHandler) UpdateExperiment(c *gin.Context) {
 
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -538,9 +538,9 @@ func (h *MLHandler) UpdateExperiment(c *gin.Context) {
 	if err != nil {
 		h.logger.Error("Failed to update ML experiment", zap.String("experiment_id", experimentID), zap.Error(err))
 		if err.Error() == "ML experiment not found" {
-			c.JSON(http.StatusNotFound, errors.NotFound("ML experiment not found"))
+			c.JSON(http.StatusNotFound, errors.NotFound("ML experiment not found").WithErrorCode(errors.CodeMLExperimentNotFound))
 		} else {
-			c.JSON(http.StatusInternalServerError, errors.Internal("Failed to update ML experiment"))
+			middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to update ML experiment", err))
 		}
 		return
 	}
@@ -568,14 +568,14 @@ func (h *MLHandler) DeleteExperiment(c *gin.Context) {
 
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -585,9 +585,9 @@ func (h *MLHandler) DeleteExperiment(c *gin.Context) {
 	if err != nil {
 		h.logger.Error("Failed to delete ML experiment", zap.String("experiment_id", experimentID), zap.Error(err))
 		if err.Error() == "ML experiment not found" {
-			c.JSON(http.StatusNotFound, errors.NotFound("ML experiment not found"))
+			c.JSON(http.StatusNotFound, errors.NotFound("ML experiment not found").WithErrorCode(errors.CodeMLExperimentNotFound))
 		} else {
-			c.JSON(http.StatusInternalServerError, errors.Internal("Failed to delete ML experiment"))
+			middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to delete ML experiment", err))
 		}
 		return
 	}
@@ -609,14 +609,14 @@ func (h *MLHandler) DeleteExperiment(c *gin.Context) {
 func (h *MLHandler) GetAnalytics(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -630,7 +630,7 @@ func (h *MLHandler) GetAnalytics(c *gin.Context) {
 	analytics, err := h.mlService.GetAnalytics(c.Request.Context(), spaceContext, period)
 	if err != nil {
 		h.logger.Error("Failed to get ML analytics", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, errors.Internal("Failed to get ML analytics"))
+		middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to get ML analytics", err))
 		return
 	}
 
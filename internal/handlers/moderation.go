@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
+	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// ModerationHandler lets space owners and admins review the documents
+// moderation held or flagged in public notebooks
+type ModerationHandler struct {
+	moderationService *services.ModerationService
+	logger            *logger.Logger
+}
+
+// NewModerationHandler creates a new moderation handler. moderationService
+// is nil when moderation is not configured.
+func NewModerationHandler(moderationService *services.ModerationService, log *logger.Logger) *ModerationHandler {
+	return &ModerationHandler{
+		moderationService: moderationService,
+		logger:            log.WithService("moderation_handler"),
+	}
+}
+
+// ListModeratedDocuments lists the moderated documents of the space
+// @Summary List moderated documents
+// @Description List one page of the documents of the space's public notebooks that moderation scanned or holds, most recently moderated first. Without a status, the documents pending a scan, flagged for review and blocked are listed. Pending and blocked documents are hidden from everyone but their owner and the space's owners and admins. Requires the owner or admin role.
+// @Tags moderation
+// @Produce json
+// @Security Bearer
+// @Param status query string false "Moderation status" Enums(pending, approved, flagged, blocked)
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param offset query int false "Page offset" default(0)
+// @Success 200 {object} models.ModeratedDocumentListResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/moderation/documents [get]
+func (h *ModerationHandler) ListModeratedDocuments(c *gin.Context) {
+	if h.moderationService == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Content moderation is not configured"))
+		return
+	}
+
+	_, spaceContext, ok := requireUserAndSpace(c, h.logger)
+	if !ok {
+		return
+	}
+
+	params := parsePaginationParams(c, pagination.DefaultLimit)
+	response, err := h.moderationService.ListModeratedDocuments(c.Request.Context(), models.ModerationStatus(c.Query("status")), params.Limit, params.Offset, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ReviewModeratedDocument approves or blocks a moderated document
+// @Summary Review a moderated document
+// @Description Override moderation's verdict on a document of a public notebook: approved makes it readable by the space, blocked holds it from everyone but its owner and the space's owners and admins. The decision stands until the document is processed again. Requires the owner or admin role.
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Document ID"
+// @Param request body models.ModerationReviewRequest true "Decision"
+// @Success 200 {object} models.ModeratedDocument
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/moderation/documents/{id}/review [post]
+func (h *ModerationHandler) ReviewModeratedDocument(c *gin.Context) {
+	if h.moderationService == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Content moderation is not configured"))
+		return
+	}
+
+	userID, spaceContext, ok := requireUserAndSpace(c, h.logger)
+	if !ok {
+		return
+	}
+
+	var req models.ModerationReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Invalid request body", err))
+		return
+	}
+	if err := validateStruct(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Validation failed", err))
+		return
+	}
+
+	document, err := h.moderationService.ReviewDocument(c.Request.Context(), c.Param("id"), req, userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, document)
+}
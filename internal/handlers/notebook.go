@@ -10,6 +10,7 @@ import (
 	"github.com/Tributary-ai-services/aether-be/internal/logger"
 	"github.com/Tributary-ai-services/aether-be/internal/middleware"
 	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
 	"github.com/Tributary-ai-services/aether-be/internal/services"
 	"github.com/Tributary-ai-services/aether-be/pkg/errors"
 )
@@ -48,7 +49,7 @@ func (h *NotebookHandler) CreateNotebook(c *gin.Context) {
 	userID, err := ensureUserExists(c, h.userService, h.logger)
 	if err != nil {
 		h.logger.Error("Failed to resolve user", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -68,14 +69,14 @@ func (h *NotebookHandler) CreateNotebook(c *gin.Context) {
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
 	notebook, err := h.notebookService.CreateNotebook(c.Request.Context(), req, userID, spaceContext)
 	if err != nil {
 		h.logger.Error("Failed to create notebook", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -108,21 +109,21 @@ func (h *NotebookHandler) GetNotebook(c *gin.Context) {
 	userID, err := ensureUserExists(c, h.userService, h.logger)
 	if err != nil {
 		h.logger.Error("Failed to resolve user", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
 	notebook, err := h.notebookService.GetNotebookByID(c.Request.Context(), notebookID, userID, spaceContext)
 	if err != nil {
 		h.logger.Error("Failed to get notebook", zap.String("notebook_id", notebookID), zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -156,7 +157,7 @@ func (h *NotebookHandler) UpdateNotebook(c *gin.Context) {
 	userID, err := ensureUserExists(c, h.userService, h.logger)
 	if err != nil {
 		h.logger.Error("Failed to resolve user", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -176,14 +177,14 @@ func (h *NotebookHandler) UpdateNotebook(c *gin.Context) {
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
 	notebook, err := h.notebookService.UpdateNotebook(c.Request.Context(), notebookID, req, userID, spaceContext)
 	if err != nil {
 		h.logger.Error("Failed to update notebook", zap.String("notebook_id", notebookID), zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -192,7 +193,7 @@ func (h *NotebookHandler) UpdateNotebook(c *gin.Context) {
 
 // DeleteNotebook deletes a notebook
 // @Summary Delete notebook
-// @Description Delete a notebook (soft delete)
+// @Description Move a notebook to the trash. It can be restored from /api/v1/trash until it is purged, with its documents, TRASH_RETENTION_DAYS after deletion.
 // @Tags notebooks
 // @Accept json
 // @Produce json
@@ -216,21 +217,21 @@ func (h *NotebookHandler) DeleteNotebook(c *gin.Context) {
 	userID, err := ensureUserExists(c, h.userService, h.logger)
 	if err != nil {
 		h.logger.Error("Failed to resolve user", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
 	err = h.notebookService.DeleteNotebook(c.Request.Context(), notebookID, userID, spaceContext)
 	if err != nil {
 		h.logger.Error("Failed to delete notebook", zap.String("notebook_id", notebookID), zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -256,7 +257,7 @@ func (h *NotebookHandler) ListNotebooks(c *gin.Context) {
 	userID, err := ensureUserExists(c, h.userService, h.logger)
 	if err != nil {
 		h.logger.Error("Failed to resolve user", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -279,20 +280,66 @@ func (h *NotebookHandler) ListNotebooks(c *gin.Context) {
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
 	response, err := h.notebookService.ListNotebooks(c.Request.Context(), userID, spaceContext, limit, offset)
 	if err != nil {
 		h.logger.Error("Failed to list notebooks", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+// ListNotebooksV2 lists notebooks for current user in the v2 list envelope
+// @Summary List notebooks (v2)
+// @Description List notebooks accessible to the current user. Unlike v1, the notebooks are in data and the page in pagination.
+// @Tags notebooks
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param limit query int false "Results limit (max 100)" default(20)
+// @Param offset query int false "Results offset" default(0)
+// @Success 200 {object} models.NotebookListResponseV2
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 500 {object} errors.APIError
+// @Router /api/v2/notebooks [get]
+func (h *NotebookHandler) ListNotebooksV2(c *gin.Context) {
+	userID, err := ensureUserExists(c, h.userService, h.logger)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return
+	}
+
+	params := pagination.FromQuery(c, pagination.DefaultLimit)
+	response, err := h.notebookService.ListNotebooks(c.Request.Context(), userID, spaceContext, params.Limit, params.Offset)
+	if err != nil {
+		h.logger.Error("Failed to list notebooks", zap.Error(err))
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NotebookListResponseV2{
+		Data: response.Notebooks,
+		Pagination: models.Pagination{
+			Total:   response.Total,
+			Limit:   response.Limit,
+			Offset:  response.Offset,
+			HasMore: response.HasMore,
+		},
+	})
+}
+
 // SearchNotebooks searches notebooks
 // @Summary Search notebooks
 // @Description Search notebooks by query, owner, visibility, etc.
@@ -317,7 +364,7 @@ func (h *NotebookHandler) SearchNotebooks(c *gin.Context) {
 	userID, err := ensureUserExists(c, h.userService, h.logger)
 	if err != nil {
 		h.logger.Error("Failed to resolve user", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -351,77 +398,16 @@ func (h *NotebookHandler) SearchNotebooks(c *gin.Context) {
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
 	response, err := h.notebookService.SearchNotebooks(c.Request.Context(), req, userID, spaceContext)
 	if err != nil {
 		h.logger.Error("Failed to search notebooks", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, response)
 }
-
-// ShareNotebook shares a notebook with users or groups
-// @Summary Share notebook
-// @Description Share notebook with users or groups
-// @Tags notebooks
-// @Accept json
-// @Produce json
-// @Security Bearer
-// @Param id path string true "Notebook ID"
-// @Param share body models.NotebookShareRequest true "Share data"
-// @Success 200 {object} map[string]string
-// @Failure 400 {object} errors.APIError
-// @Failure 401 {object} errors.APIError
-// @Failure 403 {object} errors.APIError
-// @Failure 404 {object} errors.APIError
-// @Failure 500 {object} errors.APIError
-// @Router /api/v1/notebooks/{id}/share [post]
-func (h *NotebookHandler) ShareNotebook(c *gin.Context) {
-	notebookID := c.Param("id")
-	if notebookID == "" {
-		c.JSON(http.StatusBadRequest, errors.Validation("Notebook ID is required", nil))
-		return
-	}
-
-	// Resolve Keycloak ID to internal user ID
-	userID, err := ensureUserExists(c, h.userService, h.logger)
-	if err != nil {
-		h.logger.Error("Failed to resolve user", zap.Error(err))
-		handleServiceError(c, err)
-		return
-	}
-
-	var req models.NotebookShareRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Error("Invalid request payload", zap.Error(err))
-		c.JSON(http.StatusBadRequest, errors.Validation("Invalid request payload", err))
-		return
-	}
-
-	// Validate request
-	if err := validateStruct(&req); err != nil {
-		c.JSON(http.StatusBadRequest, errors.Validation("Validation failed", err))
-		return
-	}
-
-	// Get space context
-	spaceContext, err := middleware.GetSpaceContext(c)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
-		return
-	}
-
-	err = h.notebookService.ShareNotebook(c.Request.Context(), notebookID, req, userID, spaceContext)
-	if err != nil {
-		h.logger.Error("Failed to share notebook", zap.String("notebook_id", notebookID), zap.Error(err))
-		handleServiceError(c, err)
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "Notebook shared successfully"})
-}
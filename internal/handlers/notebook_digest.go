@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// NotebookDigestHandler manages users' subscriptions to digests of
+// notebook changes
+type NotebookDigestHandler struct {
+	digestService   *services.NotebookDigestService
+	notebookService *services.NotebookService
+	userService     *services.UserService
+	logger          *logger.Logger
+}
+
+// NewNotebookDigestHandler creates a new notebook digest handler
+func NewNotebookDigestHandler(digestService *services.NotebookDigestService, notebookService *services.NotebookService, userService *services.UserService, log *logger.Logger) *NotebookDigestHandler {
+	return &NotebookDigestHandler{
+		digestService:   digestService,
+		notebookService: notebookService,
+		userService:     userService,
+		logger:          log.WithService("notebook_digest_handler"),
+	}
+}
+
+// SubscribeNotebookDigest subscribes the caller to a notebook's digest
+// @Summary Subscribe to a notebook digest
+// @Description Subscribe the caller to a summary of the documents added to and changed in a notebook, sent at the end of every UTC day or week from Monday, or change the cadence of their subscription. Digests are built from the notebook's activity feed, announced in the app as a notebook.digest notification and, when email is configured, emailed to the caller. Periods with no changes send nothing. The first digest covers the period the subscription was made in.
+// @Tags feeds
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Notebook ID"
+// @Param request body models.NotebookDigestSubscribeRequest true "Digest cadence"
+// @Success 200 {object} models.NotebookDigestSubscription
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/notebooks/{id}/digest [put]
+func (h *NotebookDigestHandler) SubscribeNotebookDigest(c *gin.Context) {
+	userID, err := ensureUserExists(c, h.userService, h.logger)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return
+	}
+
+	var req models.NotebookDigestSubscribeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Invalid request body", err))
+		return
+	}
+	if err := validateStruct(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Validation failed", err))
+		return
+	}
+
+	notebook, err := h.notebookService.GetNotebookByID(c.Request.Context(), c.Param("id"), userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	subscription, err := h.digestService.Subscribe(c.Request.Context(), notebook.ID, userID, req)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, subscription)
+}
+
+// GetNotebookDigest returns the caller's subscription to a notebook's digest
+// @Summary Get a notebook digest subscription
+// @Description Get the caller's subscription to a notebook's digest, with when the next digest is due and the error of the last one, if any. Fails with 404 and AETHER-FEED-003 when the caller is not subscribed.
+// @Tags feeds
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Notebook ID"
+// @Success 200 {object} models.NotebookDigestSubscription
+// @Failure 401 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/notebooks/{id}/digest [get]
+func (h *NotebookDigestHandler) GetNotebookDigest(c *gin.Context) {
+	userID, err := ensureUserExists(c, h.userService, h.logger)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	subscription, err := h.digestService.GetSubscription(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, subscription)
+}
+
+// UnsubscribeNotebookDigest unsubscribes the caller from a notebook's digest
+// @Summary Unsubscribe from a notebook digest
+// @Description Stop sending the caller digests of a notebook. Fails with 404 and AETHER-FEED-003 when the caller is not subscribed.
+// @Tags feeds
+// @Security Bearer
+// @Param id path string true "Notebook ID"
+// @Success 204
+// @Failure 401 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/notebooks/{id}/digest [delete]
+func (h *NotebookDigestHandler) UnsubscribeNotebookDigest(c *gin.Context) {
+	userID, err := ensureUserExists(c, h.userService, h.logger)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	if err := h.digestService.Unsubscribe(c.Request.Context(), c.Param("id"), userID); err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListNotebookDigests lists the caller's digest subscriptions
+// @Summary List notebook digest subscriptions
+// @Description List the caller's notebook digest subscriptions, oldest first
+// @Tags feeds
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.NotebookDigestSubscriptionList
+// @Failure 401 {object} errors.APIError
+// @Router /api/v1/users/me/digests [get]
+func (h *NotebookDigestHandler) ListNotebookDigests(c *gin.Context) {
+	userID, err := ensureUserExists(c, h.userService, h.logger)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	list, err := h.digestService.ListSubscriptions(c.Request.Context(), userID)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
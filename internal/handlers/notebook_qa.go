@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// NotebookQAHandler answers questions about a notebook's documents
+type NotebookQAHandler struct {
+	qaService *services.NotebookQAService
+	logger    *logger.Logger
+}
+
+// NewNotebookQAHandler creates a new notebook question answering handler
+func NewNotebookQAHandler(qaService *services.NotebookQAService, log *logger.Logger) *NotebookQAHandler {
+	return &NotebookQAHandler{
+		qaService: qaService,
+		logger:    log.WithService("notebook_qa_handler"),
+	}
+}
+
+// AskNotebook answers a question about a notebook's documents
+// @Summary Ask a notebook
+// @Description Answer a one-off question from the documents of a notebook without creating an agent. The notebook's chunks most relevant to the question are retrieved, by vector search or, when it is unavailable, by keyword, and the configured model (QA_MODEL) answers from them through the LLM router, citing them by number. The response lists the cited chunks as sources, in the order they are numbered. When no chunk matches, the answer says so and the model is not called. Fails with 503 when the LLM router is not enabled.
+// @Tags notebooks
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Notebook ID"
+// @Param request body models.NotebookAskRequest true "Question"
+// @Success 200 {object} models.NotebookAskResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 502 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/notebooks/{id}/ask [post]
+func (h *NotebookQAHandler) AskNotebook(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == "" {
+		middleware.WriteError(c, h.logger, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
+		return
+	}
+
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return
+	}
+
+	var req models.NotebookAskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Invalid request body", err))
+		return
+	}
+	req.Question = strings.TrimSpace(req.Question)
+	if err := validateStruct(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Validation failed", err))
+		return
+	}
+
+	response, err := h.qaService.Ask(c.Request.Context(), c.Param("id"), req, userID, extractAuthToken(c), spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
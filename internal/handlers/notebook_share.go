@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// shareContext returns the internal ID of the user and the space of a
+// sharing request, writing the error response when either is missing
+func (h *NotebookHandler) shareContext(c *gin.Context) (string, *models.SpaceContext, bool) {
+	userID, err := ensureUserExists(c, h.userService, h.logger)
+	if err != nil {
+		h.logger.Error("Failed to resolve user", zap.Error(err))
+		middleware.WriteError(c, h.logger, err)
+		return "", nil, false
+	}
+
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return "", nil, false
+	}
+	return userID, spaceContext, true
+}
+
+// ShareNotebook shares a notebook with users and teams
+// @Summary Share notebook
+// @Description Share a notebook with members and teams of its organization, all with the given role. Viewers read the notebook and its documents, and editors also change its documents. Commenters have the access of viewers until comments are supported. Sharing again with the same user or team changes its role; a team's members get the role through the team, and a user shared with directly and through teams gets the highest role. Only the notebook's owner shares it, and personal notebooks cannot be shared. Users and teams not in the organization fail the request with 404 and AETHER-NB-003, listed in details. Responds with all the notebook's shares.
+// @Tags notebooks
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Notebook ID"
+// @Param share body models.NotebookShareRequest true "Users and teams to share with, and their role"
+// @Success 200 {object} models.NotebookShareListResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/notebooks/{id}/share [post]
+func (h *NotebookHandler) ShareNotebook(c *gin.Context) {
+	userID, spaceContext, ok := h.shareContext(c)
+	if !ok {
+		return
+	}
+
+	var req models.NotebookShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Invalid request payload", err))
+		return
+	}
+	if err := validateStruct(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Validation failed", err))
+		return
+	}
+
+	shares, err := h.notebookService.ShareNotebook(c.Request.Context(), c.Param("id"), req, userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NotebookShareListResponse{Shares: shares})
+}
+
+// ListNotebookShares lists the users and teams a notebook is shared with
+// @Summary List notebook shares
+// @Description List the users and teams a notebook is shared with and their roles, oldest share first.
+// @Tags notebooks
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Notebook ID"
+// @Success 200 {object} models.NotebookShareListResponse
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/notebooks/{id}/shares [get]
+func (h *NotebookHandler) ListNotebookShares(c *gin.Context) {
+	userID, spaceContext, ok := h.shareContext(c)
+	if !ok {
+		return
+	}
+
+	shares, err := h.notebookService.ListNotebookShares(c.Request.Context(), c.Param("id"), userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NotebookShareListResponse{Shares: shares})
+}
+
+// UnshareNotebook stops sharing a notebook with a user or team
+// @Summary Unshare notebook
+// @Description Stop sharing a notebook with a user or team. Members of a team keep any role they were given directly or through other teams. Only the notebook's owner unshares it. Fails with 404 and AETHER-NB-004 when the notebook is not shared with the user or team.
+// @Tags notebooks
+// @Security Bearer
+// @Param id path string true "Notebook ID"
+// @Param type path string true "Share type" Enums(user, team)
+// @Param share_id path string true "User or team ID"
+// @Success 204
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/notebooks/{id}/shares/{type}/{share_id} [delete]
+func (h *NotebookHandler) UnshareNotebook(c *gin.Context) {
+	userID, spaceContext, ok := h.shareContext(c)
+	if !ok {
+		return
+	}
+
+	shareType := c.Param("type")
+	if shareType != models.NotebookShareUser && shareType != models.NotebookShareTeam {
+		middleware.WriteError(c, h.logger, errors.ValidationWithDetails("Invalid share type", map[string]interface{}{
+			"param": "type",
+		}))
+		return
+	}
+
+	if err := h.notebookService.UnshareNotebook(c.Request.Context(), c.Param("id"), shareType, c.Param("share_id"), userID, spaceContext); err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListSharedNotebooks lists the notebooks shared with the user
+// @Summary List notebooks shared with me
+// @Description List the notebooks of the space shared with the user, directly or through one of their teams, most recently shared first. role is the highest role the user was given.
+// @Tags notebooks
+// @Produce json
+// @Security Bearer
+// @Param limit query int false "Number of notebooks to return" default(20)
+// @Param offset query int false "Number of notebooks to skip" default(0)
+// @Success 200 {object} models.SharedNotebookListResponse
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Router /api/v1/notebooks/shared-with-me [get]
+func (h *NotebookHandler) ListSharedNotebooks(c *gin.Context) {
+	userID, spaceContext, ok := h.shareContext(c)
+	if !ok {
+		return
+	}
+
+	page := parsePaginationParams(c, pagination.DefaultLimit)
+	notebooks, err := h.notebookService.ListSharedNotebooks(c.Request.Context(), userID, spaceContext, page.Limit, page.Offset)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, notebooks)
+}
@@ -1,14 +1,19 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
 	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
 	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
 	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/internal/webhooks"
 	"github.com/Tributary-ai-services/aether-be/pkg/errors"
 )
 
@@ -16,6 +21,8 @@ import (
 type OrganizationHandler struct {
 	orgService  *services.OrganizationService
 	userService *services.UserService
+	jobService  *services.JobService
+	authz       *services.AuthorizationService
 	logger      *logger.Logger
 }
 
@@ -28,6 +35,11 @@ func NewOrganizationHandler(orgService *services.OrganizationService, userServic
 	}
 }
 
+// SetJobService enables organization deletes that run as async jobs
+func (h *OrganizationHandler) SetJobService(jobService *services.JobService) {
+	h.jobService = jobService
+}
+
 // CreateOrganization creates a new organization
 // @Summary Create a new organization
 // @Description Create a new organization with the provided information
@@ -47,7 +59,7 @@ func (h *OrganizationHandler) CreateOrganization(c *gin.Context) {
 	userID, err := ensureUserExists(c, h.userService, h.logger)
 	if err != nil {
 		h.logger.Error("Failed to ensure user exists", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -63,7 +75,7 @@ func (h *OrganizationHandler) CreateOrganization(c *gin.Context) {
 	org, err := h.orgService.CreateOrganization(c.Request.Context(), req, userID)
 	if err != nil {
 		h.logger.Error("Failed to create organization", zap.Error(err), zap.String("user_id", userID))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -87,14 +99,14 @@ func (h *OrganizationHandler) GetOrganizations(c *gin.Context) {
 	userID, err := ensureUserExists(c, h.userService, h.logger)
 	if err != nil {
 		h.logger.Error("Failed to ensure user exists", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
 	organizations, err := h.orgService.GetOrganizations(c.Request.Context(), userID)
 	if err != nil {
 		h.logger.Error("Failed to get organizations", zap.Error(err), zap.String("user_id", userID))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -124,7 +136,7 @@ func (h *OrganizationHandler) GetOrganizations(c *gin.Context) {
 func (h *OrganizationHandler) GetOrganization(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
@@ -139,7 +151,7 @@ func (h *OrganizationHandler) GetOrganization(c *gin.Context) {
 	org, err := h.orgService.GetOrganization(c.Request.Context(), orgID, userID)
 	if err != nil {
 		h.logger.Error("Failed to get organization", zap.Error(err), zap.String("org_id", orgID), zap.String("user_id", userID))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -166,7 +178,7 @@ func (h *OrganizationHandler) GetOrganization(c *gin.Context) {
 func (h *OrganizationHandler) UpdateOrganization(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
@@ -190,7 +202,7 @@ func (h *OrganizationHandler) UpdateOrganization(c *gin.Context) {
 	org, err := h.orgService.UpdateOrganization(c.Request.Context(), orgID, req, userID)
 	if err != nil {
 		h.logger.Error("Failed to update organization", zap.Error(err), zap.String("org_id", orgID), zap.String("user_id", userID))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -200,12 +212,14 @@ func (h *OrganizationHandler) UpdateOrganization(c *gin.Context) {
 
 // DeleteOrganization deletes an organization
 // @Summary Delete organization
-// @Description Delete an organization (only organization owners can delete organizations)
+// @Description Delete an organization (only organization owners can delete organizations). Deleting a large organization can take a while; with "Prefer: respond-async" the delete runs as an organization_delete job and the response is 202 with the job.
 // @Tags organizations
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param id path string true "Organization ID"
+// @Param Prefer header string false "respond-async to delete in the background"
+// @Success 202 {object} models.Job
 // @Success 204
 // @Failure 401 {object} errors.APIError
 // @Failure 403 {object} errors.APIError
@@ -215,7 +229,7 @@ func (h *OrganizationHandler) UpdateOrganization(c *gin.Context) {
 func (h *OrganizationHandler) DeleteOrganization(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
@@ -227,10 +241,15 @@ func (h *OrganizationHandler) DeleteOrganization(c *gin.Context) {
 		return
 	}
 
+	if prefersAsync(c) && h.jobService != nil {
+		h.deleteOrganizationAsync(c, orgID, userID)
+		return
+	}
+
 	err := h.orgService.DeleteOrganization(c.Request.Context(), orgID, userID)
 	if err != nil {
 		h.logger.Error("Failed to delete organization", zap.Error(err), zap.String("org_id", orgID), zap.String("user_id", userID))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -238,15 +257,41 @@ func (h *OrganizationHandler) DeleteOrganization(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
-// GetOrganizationMembers gets all members of an organization
+// deleteOrganizationAsync checks the user may delete the organization and
+// deletes it in an organization_delete job
+func (h *OrganizationHandler) deleteOrganizationAsync(c *gin.Context, orgID string, userID string) {
+	if err := h.orgService.CheckCanDeleteOrganization(c.Request.Context(), orgID, userID); err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	job, err := h.jobService.Start(c.Request.Context(), models.JobTypeOrganizationDelete, "", userID, func(ctx context.Context, progress func(models.JobProgress)) (map[string]interface{}, error) {
+		if err := h.orgService.DeleteOrganization(ctx, orgID, userID); err != nil {
+			return nil, err
+		}
+		progress(models.JobProgress{Total: 1, Completed: 1})
+		return map[string]interface{}{"organization_id": orgID}, nil
+	})
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	h.logger.Info("Organization delete job started", zap.String("org_id", orgID), zap.String("job_id", job.ID), zap.String("user_id", userID))
+	respondAccepted(c, job)
+}
+
+// GetOrganizationMembers gets one page of the members of an organization
 // @Summary Get organization members
-// @Description Get all members of a specific organization
+// @Description Get a page of the members of a specific organization, oldest first
 // @Tags organizations
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param id path string true "Organization ID"
-// @Success 200 {array} models.OrganizationMemberResponse
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param offset query int false "Page offset" default(0)
+// @Success 200 {object} models.OrganizationMemberListResponse
 // @Failure 401 {object} errors.APIError
 // @Failure 403 {object} errors.APIError
 // @Failure 404 {object} errors.APIError
@@ -255,7 +300,7 @@ func (h *OrganizationHandler) DeleteOrganization(c *gin.Context) {
 func (h *OrganizationHandler) GetOrganizationMembers(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
@@ -267,20 +312,15 @@ func (h *OrganizationHandler) GetOrganizationMembers(c *gin.Context) {
 		return
 	}
 
-	members, err := h.orgService.GetOrganizationMembers(c.Request.Context(), orgID, userID)
+	page := parsePaginationParams(c, pagination.DefaultLimit)
+	members, err := h.orgService.GetOrganizationMembers(c.Request.Context(), orgID, userID, page.Limit, page.Offset)
 	if err != nil {
 		h.logger.Error("Failed to get organization members", zap.Error(err), zap.String("org_id", orgID), zap.String("user_id", userID))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
-	// Convert to responses
-	responses := make([]*models.OrganizationMemberResponse, len(members))
-	for i, member := range members {
-		responses[i] = member.ToMemberResponse()
-	}
-
-	c.JSON(http.StatusOK, responses)
+	c.JSON(http.StatusOK, members)
 }
 
 // InviteOrganizationMember invites a new member to the organization
@@ -303,7 +343,7 @@ func (h *OrganizationHandler) GetOrganizationMembers(c *gin.Context) {
 func (h *OrganizationHandler) InviteOrganizationMember(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
@@ -328,7 +368,7 @@ func (h *OrganizationHandler) InviteOrganizationMember(c *gin.Context) {
 	if err != nil {
 		h.logger.Error("Failed to invite organization member", zap.Error(err), 
 			zap.String("org_id", orgID), zap.String("user_id", userID), zap.String("email", req.Email))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -357,7 +397,7 @@ func (h *OrganizationHandler) InviteOrganizationMember(c *gin.Context) {
 func (h *OrganizationHandler) UpdateOrganizationMemberRole(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
@@ -384,7 +424,7 @@ func (h *OrganizationHandler) UpdateOrganizationMemberRole(c *gin.Context) {
 	if err != nil {
 		h.logger.Error("Failed to update organization member role", zap.Error(err), 
 			zap.String("org_id", orgID), zap.String("target_user_id", targetUserID), zap.String("user_id", userID))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -411,7 +451,7 @@ func (h *OrganizationHandler) UpdateOrganizationMemberRole(c *gin.Context) {
 func (h *OrganizationHandler) RemoveOrganizationMember(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
@@ -429,11 +469,117 @@ func (h *OrganizationHandler) RemoveOrganizationMember(c *gin.Context) {
 	if err != nil {
 		h.logger.Error("Failed to remove organization member", zap.Error(err), 
 			zap.String("org_id", orgID), zap.String("target_user_id", targetUserID), zap.String("user_id", userID))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
 	h.logger.Info("Organization member removed successfully", 
 		zap.String("org_id", orgID), zap.String("target_user_id", targetUserID), zap.String("removed_by", userID))
 	c.Status(http.StatusNoContent)
-}
\ No newline at end of file
+}
+// stripeEvent is the part of a Stripe event the billing webhook reads
+type stripeEvent struct {
+	ID      string `json:"id" validate:"required"`
+	Type    string `json:"type" validate:"required"`
+	Created int64  `json:"created" validate:"required"`
+	Data    struct {
+		Object stripeSubscription `json:"object"`
+	} `json:"data"`
+}
+
+// stripeSubscription is the part of a Stripe subscription the billing
+// webhook reads. Subscriptions are created with the organization ID in
+// their metadata.
+type stripeSubscription struct {
+	ID               string            `json:"id"`
+	Customer         string            `json:"customer"`
+	Status           string            `json:"status"`
+	CurrentPeriodEnd int64             `json:"current_period_end"`
+	Metadata         map[string]string `json:"metadata"`
+	Items            struct {
+		Data []struct {
+			Quantity int `json:"quantity"`
+			Price    struct {
+				ID        string `json:"id"`
+				LookupKey string `json:"lookup_key"`
+			} `json:"price"`
+		} `json:"data"`
+	} `json:"items"`
+}
+
+// StripeBillingWebhook records subscription changes sent by Stripe
+// @Summary Stripe billing webhook
+// @Description Record subscription changes in the billing of the organization named by the subscription's organization_id metadata. Handles customer.subscription.created, .updated and .deleted; other events are acknowledged and ignored. Deliveries are verified with STRIPE_WEBHOOK_SECRET.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param Stripe-Signature header string true "Stripe signature"
+// @Param payload body object true "Stripe event"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /webhooks/stripe [post]
+func (h *OrganizationHandler) StripeBillingWebhook(c *gin.Context) {
+	var event stripeEvent
+	if err := webhooks.DecodePayload(middleware.WebhookBody(c), &event); err != nil {
+		c.JSON(http.StatusBadRequest, errors.Validation("Invalid webhook payload", err))
+		return
+	}
+
+	switch event.Type {
+	case "customer.subscription.created", "customer.subscription.updated", "customer.subscription.deleted":
+	default:
+		c.JSON(http.StatusOK, gin.H{"message": "Event ignored", "event_id": event.ID})
+		return
+	}
+
+	subscription := event.Data.Object
+	orgID := subscription.Metadata["organization_id"]
+	if orgID == "" {
+		// Not a subscription Aether created; acknowledge so Stripe stops retrying
+		h.logger.Warn("Stripe subscription without organization_id metadata",
+			zap.String("event_id", event.ID), zap.String("subscription_id", subscription.ID))
+		c.JSON(http.StatusOK, gin.H{"message": "Event ignored", "event_id": event.ID})
+		return
+	}
+
+	update := models.BillingSubscription{
+		OrganizationID: orgID,
+		CustomerID:     subscription.Customer,
+		SubscriptionID: subscription.ID,
+		Status:         subscription.Status,
+		ChangedAt:      time.Unix(event.Created, 0),
+	}
+	if len(subscription.Items.Data) > 0 {
+		item := subscription.Items.Data[0]
+		update.Plan = item.Price.LookupKey
+		if update.Plan == "" {
+			update.Plan = item.Price.ID
+		}
+		update.Seats = item.Quantity
+	}
+	if subscription.CurrentPeriodEnd > 0 {
+		periodEnd := time.Unix(subscription.CurrentPeriodEnd, 0)
+		update.CurrentPeriodEnd = &periodEnd
+	}
+	if event.Type == "customer.subscription.deleted" {
+		update.Status = "canceled"
+		update.Plan = "free"
+	}
+
+	applied, err := h.orgService.ApplyBillingSubscription(c.Request.Context(), update)
+	if errors.IsNotFound(err) {
+		// The organization was deleted; retries would not find it either
+		h.logger.Warn("Stripe subscription of an unknown organization",
+			zap.String("event_id", event.ID), zap.String("org_id", orgID))
+		c.JSON(http.StatusOK, gin.H{"message": "Event ignored", "event_id": event.ID})
+		return
+	}
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Event processed", "event_id": event.ID, "applied": applied})
+}
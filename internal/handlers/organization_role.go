@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// SetAuthorizationService enables the custom roles of organizations;
+// without it the role endpoints respond 503
+func (h *OrganizationHandler) SetAuthorizationService(authz *services.AuthorizationService) {
+	h.authz = authz
+}
+
+// ListOrganizationRoles lists the roles of an organization
+// @Summary List organization roles
+// @Description List the built-in member roles and the custom roles of an organization, with the actions roles can grant. Any member may list them.
+// @Tags organizations
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Organization ID"
+// @Success 200 {object} models.RoleList
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 500 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/organizations/{id}/roles [get]
+func (h *OrganizationHandler) ListOrganizationRoles(c *gin.Context) {
+	orgID, userID, ok := h.roleRequest(c, "")
+	if !ok {
+		return
+	}
+
+	roles, err := h.authz.ListRoles(c.Request.Context(), orgID)
+	if err != nil {
+		h.logger.Error("Failed to list organization roles", zap.Error(err), zap.String("org_id", orgID), zap.String("user_id", userID))
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, roles)
+}
+
+// CreateOrganizationRole defines a custom role of an organization
+// @Summary Create organization role
+// @Description Define a custom role of an organization granting the listed actions. Members can then be invited with or moved to the role.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Organization ID"
+// @Param role body models.RoleCreateRequest true "Role definition"
+// @Success 201 {object} models.Role
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 409 {object} errors.APIError
+// @Failure 500 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/organizations/{id}/roles [post]
+func (h *OrganizationHandler) CreateOrganizationRole(c *gin.Context) {
+	orgID, userID, ok := h.roleRequest(c, models.ActionOrganizationRoles)
+	if !ok {
+		return
+	}
+
+	var req models.RoleCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.ValidationWithDetails("Invalid request data", map[string]interface{}{
+			"error": err.Error(),
+		}))
+		return
+	}
+
+	role, err := h.authz.CreateRole(c.Request.Context(), orgID, req, userID)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	h.logger.Info("Organization role created", zap.String("org_id", orgID), zap.String("role", role.Name), zap.String("user_id", userID))
+	c.JSON(http.StatusCreated, role)
+}
+
+// UpdateOrganizationRole changes a custom role of an organization
+// @Summary Update organization role
+// @Description Change the description or the actions of a custom role. Members holding the role are granted the new actions within the roles refresh interval.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Organization ID"
+// @Param role path string true "Role name"
+// @Param update body models.RoleUpdateRequest true "Role changes"
+// @Success 200 {object} models.Role
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 500 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/organizations/{id}/roles/{role} [put]
+func (h *OrganizationHandler) UpdateOrganizationRole(c *gin.Context) {
+	orgID, userID, ok := h.roleRequest(c, models.ActionOrganizationRoles)
+	if !ok {
+		return
+	}
+
+	var req models.RoleUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.ValidationWithDetails("Invalid request data", map[string]interface{}{
+			"error": err.Error(),
+		}))
+		return
+	}
+
+	role, err := h.authz.UpdateRole(c.Request.Context(), orgID, c.Param("role"), req, userID)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	h.logger.Info("Organization role updated", zap.String("org_id", orgID), zap.String("role", role.Name), zap.String("user_id", userID))
+	c.JSON(http.StatusOK, role)
+}
+
+// DeleteOrganizationRole deletes a custom role of an organization
+// @Summary Delete organization role
+// @Description Delete a custom role of an organization. Roles still held by members are refused; move the members to another role first.
+// @Tags organizations
+// @Security Bearer
+// @Param id path string true "Organization ID"
+// @Param role path string true "Role name"
+// @Success 204
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 409 {object} errors.APIError
+// @Failure 500 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/organizations/{id}/roles/{role} [delete]
+func (h *OrganizationHandler) DeleteOrganizationRole(c *gin.Context) {
+	orgID, userID, ok := h.roleRequest(c, models.ActionOrganizationRoles)
+	if !ok {
+		return
+	}
+
+	if err := h.authz.DeleteRole(c.Request.Context(), orgID, c.Param("role"), userID); err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	h.logger.Info("Organization role deleted", zap.String("org_id", orgID), zap.String("role", c.Param("role")), zap.String("user_id", userID))
+	c.Status(http.StatusNoContent)
+}
+
+// roleRequest checks that custom roles are enabled and that the caller is
+// a member of the organization whose role grants action, if any. It
+// returns the organization and the caller, or writes the response and
+// reports false.
+func (h *OrganizationHandler) roleRequest(c *gin.Context, action string) (string, string, bool) {
+	if h.authz == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Custom roles are not available"))
+		return "", "", false
+	}
+
+	userID := getUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
+		return "", "", false
+	}
+
+	orgID := c.Param("id")
+	if action != "" {
+		if _, err := h.orgService.AuthorizeMember(c.Request.Context(), orgID, userID, action); err != nil {
+			middleware.WriteError(c, h.logger, err)
+			return "", "", false
+		}
+		return orgID, userID, true
+	}
+
+	role, err := h.orgService.GetUserRoleInOrganization(c.Request.Context(), orgID, userID)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return "", "", false
+	}
+	if role == "" {
+		middleware.WriteError(c, h.logger, errors.ForbiddenWithDetails("You are not a member of this organization", map[string]interface{}{
+			"organization_id": orgID,
+		}))
+		return "", "", false
+	}
+	return orgID, userID, true
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// Pipeline summary query limits
+const (
+	defaultPipelineWindow  = 24 * time.Hour
+	maxPipelineWindow      = 30 * 24 * time.Hour
+	defaultPipelineSlowest = 10
+	maxPipelineSlowest     = 100
+)
+
+// GetDocumentPipeline returns how long a document spent in each processing stage
+// @Summary Get document pipeline timings
+// @Description Get the upload, AudiModal submit, processing and indexing durations of a document
+// @Tags documents
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Document ID"
+// @Success 200 {object} models.DocumentPipeline
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/documents/{id}/pipeline [get]
+func (h *DocumentHandler) GetDocumentPipeline(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == "" {
+		middleware.WriteError(c, h.logger, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
+		return
+	}
+
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return
+	}
+
+	pipeline, err := h.documentService.GetDocumentPipeline(c.Request.Context(), c.Param("id"), userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pipeline)
+}
+
+// GetPipelineSummary summarizes processing pipeline timings per tenant
+// @Summary Get pipeline timing summary
+// @Description Get per-tenant percentiles of each pipeline stage for recently uploaded documents, and the slowest documents
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Param window query string false "How far back to look, as a Go duration (max 720h)" default(24h)
+// @Param tenant_id query string false "Only summarize this tenant"
+// @Param slowest query int false "Number of slowest documents to return (max 100)" default(10)
+// @Success 200 {object} models.PipelineSummaryResponse
+// @Failure 400 {object} errors.APIError
+// @Router /api/v1/admin/pipeline [get]
+func (h *DocumentHandler) GetPipelineSummary(c *gin.Context) {
+	window := defaultPipelineWindow
+	if value := c.Query("window"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 || parsed > maxPipelineWindow {
+			middleware.WriteError(c, h.logger, errors.Validation("window must be a positive duration of at most 720h", nil))
+			return
+		}
+		window = parsed
+	}
+
+	slowest := defaultPipelineSlowest
+	if value := c.Query("slowest"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 || parsed > maxPipelineSlowest {
+			middleware.WriteError(c, h.logger, errors.Validation("slowest must be between 0 and 100", nil))
+			return
+		}
+		slowest = parsed
+	}
+
+	since := time.Now().Add(-window)
+	summary, err := h.documentService.PipelineSummary(c.Request.Context(), since, c.Query("tenant_id"), slowest)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
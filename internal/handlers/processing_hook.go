@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/internal/webhooks"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// ProcessingHookHandler receives processing callbacks, the webhook
+// counterpart of the processing events AudiModal publishes on Kafka
+type ProcessingHookHandler struct {
+	processingEvents *services.ProcessingEventHandler
+	logger           *logger.Logger
+}
+
+// NewProcessingHookHandler creates a new processing callback handler
+func NewProcessingHookHandler(processingEvents *services.ProcessingEventHandler, log *logger.Logger) *ProcessingHookHandler {
+	return &ProcessingHookHandler{
+		processingEvents: processingEvents,
+		logger:           log.WithService("processing_hook_handler"),
+	}
+}
+
+// ProcessingCallback records a processing outcome on its document
+// @Summary Processing callback
+// @Description Record the outcome of processing a document, for AudiModal or another processing service on deployments without Kafka. The body is the event AudiModal publishes on processing.complete; data.success false records a failure with data.error. The event must carry tenant_id and name its document by data.document_id, data.file_id or data.url, looked up in that tenant only. Deliveries are signed with PROCESSING_WEBHOOK_SECRET: X-Webhook-Signature is "v1=" and the hex HMAC-SHA256 of "<X-Webhook-ID>.<X-Webhook-Timestamp>.<body>". X-Webhook-ID is a nonce: a delivery ID is accepted once, and deliveries signed more than WEBHOOK_TOLERANCE_SECONDS from now are rejected with 401 and AETHER-HOOK-002. An event ID is also applied once, whether it arrives here or on Kafka; a repeated event, or one for a submission the document has since replaced, answers 200 with applied false. Fails with 404 and AETHER-DOC-001 when the tenant has no such document.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param X-Webhook-ID header string true "Unique delivery ID"
+// @Param X-Webhook-Timestamp header string true "Unix time the delivery was signed"
+// @Param X-Webhook-Signature header string true "v1=<hex HMAC-SHA256>"
+// @Param payload body services.ProcessingCompleteEvent true "Processing event"
+// @Success 200 {object} services.ProcessingCallbackResult
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/webhooks/processing [post]
+func (h *ProcessingHookHandler) ProcessingCallback(c *gin.Context) {
+	var event services.ProcessingCompleteEvent
+	if err := webhooks.DecodePayload(middleware.WebhookBody(c), &event); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Invalid processing callback", err))
+		return
+	}
+
+	result, err := h.processingEvents.HandleCallback(c.Request.Context(), &event)
+	if err != nil {
+		h.logger.Warn("Failed to apply processing callback",
+			zap.String("event_id", event.ID),
+			zap.String("tenant_id", event.TenantID),
+			zap.Error(err))
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// RedactionHandler serves the redaction rules of notebooks
+type RedactionHandler struct {
+	redactionService *services.RedactionService
+	userService      *services.UserService
+	logger           *logger.Logger
+}
+
+// NewRedactionHandler creates a new redaction handler
+func NewRedactionHandler(redactionService *services.RedactionService, userService *services.UserService, log *logger.Logger) *RedactionHandler {
+	return &RedactionHandler{
+		redactionService: redactionService,
+		userService:      userService,
+		logger:           log.WithService("redaction_handler"),
+	}
+}
+
+// SetRedactionRules sets the redaction rules of a notebook
+// @Summary Set notebook redaction rules
+// @Description Set the redaction rules of a notebook, replacing the ones it had. Users the notebook is shared with at one of roles (viewer and commenter by default) read its documents without the chunks the rules omit: chunks in which PII was detected when omit_pii_chunks is set, and the chunks of the named sections, matched by title ignoring case. The rules apply to the extracted text and chunk endpoints and to the extracted_text of the document; the document's owner and the space's owners and admins read it whole. Only the notebook's owner manages its rules.
+// @Tags notebooks
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Notebook ID"
+// @Param request body models.RedactionRulesRequest true "Redaction rules"
+// @Success 200 {object} models.RedactionRules
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/notebooks/{id}/redaction [put]
+func (h *RedactionHandler) SetRedactionRules(c *gin.Context) {
+	userID, spaceContext, ok := requireInternalUserAndSpace(c, h.userService, h.logger)
+	if !ok {
+		return
+	}
+
+	var req models.RedactionRulesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Invalid request payload", err))
+		return
+	}
+	if err := validateStruct(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Validation failed", err))
+		return
+	}
+
+	rules, err := h.redactionService.SetRules(c.Request.Context(), c.Param("id"), req, userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// GetRedactionRules returns the redaction rules of a notebook
+// @Summary Get notebook redaction rules
+// @Description Get the redaction rules of a notebook. Fails with 404 and AETHER-REDACT-001 when it has none. Only the notebook's owner reads its rules.
+// @Tags notebooks
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Notebook ID"
+// @Success 200 {object} models.RedactionRules
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/notebooks/{id}/redaction [get]
+func (h *RedactionHandler) GetRedactionRules(c *gin.Context) {
+	userID, spaceContext, ok := requireInternalUserAndSpace(c, h.userService, h.logger)
+	if !ok {
+		return
+	}
+
+	rules, err := h.redactionService.GetRules(c.Request.Context(), c.Param("id"), userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// DeleteRedactionRules removes the redaction rules of a notebook
+// @Summary Delete notebook redaction rules
+// @Description Remove the redaction rules of a notebook; everyone it is shared with reads its documents whole from then on.
+// @Tags notebooks
+// @Security Bearer
+// @Param id path string true "Notebook ID"
+// @Success 204
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/notebooks/{id}/redaction [delete]
+func (h *RedactionHandler) DeleteRedactionRules(c *gin.Context) {
+	userID, spaceContext, ok := requireInternalUserAndSpace(c, h.userService, h.logger)
+	if !ok {
+		return
+	}
+
+	if err := h.redactionService.DeleteRules(c.Request.Context(), c.Param("id"), userID, spaceContext); err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// ReportHandler manages the scheduled reports of spaces
+type ReportHandler struct {
+	reportService *services.ReportService
+	logger        *logger.Logger
+}
+
+// NewReportHandler creates a new report handler
+func NewReportHandler(reportService *services.ReportService, log *logger.Logger) *ReportHandler {
+	return &ReportHandler{
+		reportService: reportService,
+		logger:        log.WithService("report_handler"),
+	}
+}
+
+// spaceContext returns the space of a request, writing the error response
+// when the user or space is missing
+func (h *ReportHandler) spaceContext(c *gin.Context) (*models.SpaceContext, bool) {
+	if getUserID(c) == "" {
+		middleware.WriteError(c, h.logger, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
+		return nil, false
+	}
+
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return nil, false
+	}
+	return spaceContext, true
+}
+
+// CreateReportSchedule schedules a report of the current space
+// @Summary Create a report schedule
+// @Description Schedule a report of the current space, rendered to PDF or CSV at the end of every UTC day, week from Monday or calendar month. processing_summary counts the documents uploaded, processed and failed in the period per notebook; storage_usage lists the documents and bytes stored per notebook at its end; agent_cost lists each agent's executions and cost since the previous report. Each report is stored for download, announced in the app to notify_user_ids (the creator when omitted) and emailed as an attachment to email_to. A space has at most 20 schedules. Fails with 400 when email_to is given but email is not configured, and with 503 when storage is unavailable. Requires the owner or admin role.
+// @Tags spaces
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body models.ReportScheduleCreateRequest true "Report schedule"
+// @Success 201 {object} models.ReportSchedule
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/reports/schedules [post]
+func (h *ReportHandler) CreateReportSchedule(c *gin.Context) {
+	spaceContext, ok := h.spaceContext(c)
+	if !ok {
+		return
+	}
+
+	var req models.ReportScheduleCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Invalid request body", err))
+		return
+	}
+	if err := validateStruct(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Validation failed", err))
+		return
+	}
+
+	schedule, err := h.reportService.CreateSchedule(c.Request.Context(), req, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, schedule)
+}
+
+// ListReportSchedules lists the report schedules of the current space
+// @Summary List report schedules
+// @Description List the report schedules of the current space, oldest first, with the outcome of their last run. Requires the owner or admin role.
+// @Tags spaces
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.ReportScheduleList
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Router /api/v1/reports/schedules [get]
+func (h *ReportHandler) ListReportSchedules(c *gin.Context) {
+	spaceContext, ok := h.spaceContext(c)
+	if !ok {
+		return
+	}
+
+	list, err := h.reportService.ListSchedules(c.Request.Context(), spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// GetReportSchedule returns a report schedule
+// @Summary Get a report schedule
+// @Description Get a report schedule of the current space. Fails with 404 and AETHER-REPORT-001 when the schedule does not exist. Requires the owner or admin role.
+// @Tags spaces
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Report schedule ID"
+// @Success 200 {object} models.ReportSchedule
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/reports/schedules/{id} [get]
+func (h *ReportHandler) GetReportSchedule(c *gin.Context) {
+	spaceContext, ok := h.spaceContext(c)
+	if !ok {
+		return
+	}
+
+	schedule, err := h.reportService.GetSchedule(c.Request.Context(), c.Param("id"), spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// DeleteReportSchedule deletes a report schedule
+// @Summary Delete a report schedule
+// @Description Delete a report schedule of the current space with the reports it generated. Fails with 404 and AETHER-REPORT-001 when the schedule does not exist. Requires the owner or admin role.
+// @Tags spaces
+// @Security Bearer
+// @Param id path string true "Report schedule ID"
+// @Success 204
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/reports/schedules/{id} [delete]
+func (h *ReportHandler) DeleteReportSchedule(c *gin.Context) {
+	spaceContext, ok := h.spaceContext(c)
+	if !ok {
+		return
+	}
+
+	if err := h.reportService.DeleteSchedule(c.Request.Context(), c.Param("id"), spaceContext); err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RunReportSchedule generates a schedule's report now
+// @Summary Generate a report now
+// @Description Generate a schedule's report for its current period so far and deliver it like a scheduled one. The schedule's next run is unchanged, and an agent_cost report counts from the previous scheduled report as usual. A report that could not be built or stored is returned with status failed; one that could not be emailed succeeds with the email's error. Fails with 404 and AETHER-REPORT-001 when the schedule does not exist, and with 503 when storage is unavailable. Requires the owner or admin role.
+// @Tags spaces
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Report schedule ID"
+// @Success 200 {object} models.ReportRun
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/reports/schedules/{id}/run [post]
+func (h *ReportHandler) RunReportSchedule(c *gin.Context) {
+	spaceContext, ok := h.spaceContext(c)
+	if !ok {
+		return
+	}
+
+	run, err := h.reportService.RunNow(c.Request.Context(), c.Param("id"), spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
+
+// ListReportRuns lists the reports a schedule generated
+// @Summary List generated reports
+// @Description List the reports a schedule generated, newest first; the latest 60 are kept. Fails with 404 and AETHER-REPORT-001 when the schedule does not exist. Requires the owner or admin role.
+// @Tags spaces
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Report schedule ID"
+// @Success 200 {object} models.ReportRunList
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/reports/schedules/{id}/runs [get]
+func (h *ReportHandler) ListReportRuns(c *gin.Context) {
+	spaceContext, ok := h.spaceContext(c)
+	if !ok {
+		return
+	}
+
+	list, err := h.reportService.ListRuns(c.Request.Context(), c.Param("id"), spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// DownloadReport downloads a generated report
+// @Summary Download a generated report
+// @Description Download the PDF or CSV file of a generated report. Fails with 404 and AETHER-REPORT-002 when the report does not exist or failed to generate. Requires the owner or admin role.
+// @Tags spaces
+// @Produce application/octet-stream
+// @Security Bearer
+// @Param id path string true "Report run ID"
+// @Success 200 {file} binary
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 502 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/reports/runs/{id}/download [get]
+func (h *ReportHandler) DownloadReport(c *gin.Context) {
+	spaceContext, ok := h.spaceContext(c)
+	if !ok {
+		return
+	}
+
+	data, fileName, contentType, err := h.reportService.DownloadRun(c.Request.Context(), c.Param("id"), spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	c.Data(http.StatusOK, contentType, data)
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/Tributary-ai-services/aether-be/internal/config"
 	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
 	"github.com/Tributary-ai-services/aether-be/pkg/errors"
 )
 
@@ -44,12 +45,12 @@ func NewRouterHandler(routerConfig *config.RouterConfig, log *logger.Logger) (*R
 	// Create HTTP client with timeouts
 	httpClient := &http.Client{
 		Timeout: timeout,
-		Transport: &http.Transport{
+		Transport: logger.NewRequestIDTransport(&http.Transport{
 			ResponseHeaderTimeout: connectTimeout,
 			MaxIdleConns:        100,
 			MaxIdleConnsPerHost: 10,
 			IdleConnTimeout:     30 * time.Second,
-		},
+		}),
 	}
 
 	return &RouterHandler{
@@ -92,7 +93,7 @@ func (h *RouterHandler) ProxyRequest(c *gin.Context, targetPath string) {
 	req, err := http.NewRequestWithContext(c.Request.Context(), c.Request.Method, targetURL, body)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to create proxy request")
-		c.JSON(http.StatusInternalServerError, errors.Internal("Failed to create proxy request"))
+		middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to create proxy request", err))
 		return
 	}
 
@@ -286,36 +287,99 @@ func (h *RouterHandler) cloneRequest(original *http.Request) (*http.Request, err
 // Handler methods for specific endpoints
 
 // GetProviders proxies GET /providers requests
+// @Summary List LLM providers
+// @Description Proxied to the LLM router service
+// @Tags router
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 502 {object} errors.APIError
+// @Router /api/v1/router/providers [get]
 func (h *RouterHandler) GetProviders(c *gin.Context) {
 	h.ProxyRequest(c, h.config.Endpoints.Providers)
 }
 
 // GetProvider proxies GET /providers/{name} requests
+// @Summary Get LLM provider
+// @Description Proxied to the LLM router service
+// @Tags router
+// @Produce json
+// @Param name path string true "Provider name"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 502 {object} errors.APIError
+// @Router /api/v1/router/providers/{name} [get]
 func (h *RouterHandler) GetProvider(c *gin.Context) {
 	h.ProxyRequest(c, h.config.Endpoints.ProviderDetail)
 }
 
 // GetHealth proxies GET /health requests
+// @Summary LLM router health
+// @Description Proxied to the LLM router service
+// @Tags router
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 502 {object} errors.APIError
+// @Router /api/v1/router/health [get]
 func (h *RouterHandler) GetHealth(c *gin.Context) {
 	h.ProxyRequest(c, h.config.Endpoints.Health)
 }
 
 // GetCapabilities proxies GET /capabilities requests
+// @Summary List LLM router capabilities
+// @Description Proxied to the LLM router service
+// @Tags router
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 502 {object} errors.APIError
+// @Router /api/v1/router/capabilities [get]
 func (h *RouterHandler) GetCapabilities(c *gin.Context) {
 	h.ProxyRequest(c, h.config.Endpoints.Capabilities)
 }
 
 // ChatCompletions proxies POST /chat/completions requests
+// @Summary Chat completion
+// @Description OpenAI-compatible chat completion, proxied to the LLM router service
+// @Tags router
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body map[string]interface{} true "Chat completion request"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} errors.APIError
+// @Failure 502 {object} errors.APIError
+// @Router /api/v1/router/chat/completions [post]
 func (h *RouterHandler) ChatCompletions(c *gin.Context) {
 	h.ProxyRequest(c, h.config.Endpoints.ChatCompletions)
 }
 
 // Completions proxies POST /completions requests
+// @Summary Text completion
+// @Description OpenAI-compatible text completion, proxied to the LLM router service
+// @Tags router
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body map[string]interface{} true "Completion request"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} errors.APIError
+// @Failure 502 {object} errors.APIError
+// @Router /api/v1/router/completions [post]
 func (h *RouterHandler) Completions(c *gin.Context) {
 	h.ProxyRequest(c, h.config.Endpoints.Completions)
 }
 
 // Messages proxies POST /messages requests
+// @Summary Messages
+// @Description Anthropic-compatible messages request, proxied to the LLM router service
+// @Tags router
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body map[string]interface{} true "Messages request"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} errors.APIError
+// @Failure 502 {object} errors.APIError
+// @Router /api/v1/router/messages [post]
 func (h *RouterHandler) Messages(c *gin.Context) {
 	h.ProxyRequest(c, h.config.Endpoints.Messages)
 }
\ No newline at end of file
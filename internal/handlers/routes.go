@@ -1,42 +1,196 @@
 package handlers
 
 import (
+	"context"
+	"fmt"
+	"net/http"
 	"os"
-	
+	"strings"
+	"time"
+
 	"github.com/gin-gonic/gin"
 
+	"github.com/Tributary-ai-services/aether-be/internal/apiversion"
 	"github.com/Tributary-ai-services/aether-be/internal/auth"
 	"github.com/Tributary-ai-services/aether-be/internal/config"
 	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/gql"
+	"github.com/Tributary-ai-services/aether-be/internal/grpcserver"
+	"github.com/Tributary-ai-services/aether-be/internal/health"
 	"github.com/Tributary-ai-services/aether-be/internal/logger"
 	"github.com/Tributary-ai-services/aether-be/internal/metrics"
 	"github.com/Tributary-ai-services/aether-be/internal/middleware"
 	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/internal/webhooks"
 )
 
 // APIServer represents the API server with all dependencies
 type APIServer struct {
-	Router               *gin.Engine
-	UserHandler          *UserHandler
-	NotebookHandler      *NotebookHandler
-	DocumentHandler      *DocumentHandler
-	ChunkHandler         *ChunkHandler
-	JobHandler           *JobHandler
-	WebSocketHandler     *WebSocketHandler
-	MLHandler            *MLHandler
-	WorkflowHandler      *WorkflowHandler
-	TeamHandler          *TeamHandler
-	OrganizationHandler  *OrganizationHandler
-	SpaceHandler         *SpaceHandler
-	AgentHandler         *AgentHandler
-	HealthHandler        *HealthHandler
-	StreamHandler        *StreamHandler
-	RouterHandler        *RouterHandler
-	LoggingHandler       *LoggingHandler
-	VectorSearchHandler  *VectorSearchHandler
-	SpaceService         *services.SpaceContextService
-	Metrics              *metrics.Metrics
-	logger               *logger.Logger
+	Router                *gin.Engine
+	UserHandler           *UserHandler
+	NotebookHandler       *NotebookHandler
+	DocumentHandler       *DocumentHandler
+	ChunkHandler          *ChunkHandler
+	JobHandler            *JobHandler
+	BatchHandler          *BatchHandler
+	WebSocketHandler      *WebSocketHandler
+	MLHandler             *MLHandler
+	WorkflowHandler       *WorkflowHandler
+	TeamHandler           *TeamHandler
+	OrganizationHandler   *OrganizationHandler
+	SpaceHandler          *SpaceHandler
+	AgentHandler          *AgentHandler
+	HealthHandler         *HealthHandler
+	StreamHandler         *StreamHandler
+	RouterHandler         *RouterHandler
+	LoggingHandler        *LoggingHandler
+	VectorSearchHandler   *VectorSearchHandler
+	AdminHandler          *AdminHandler
+	AuditHandler          *AuditHandler
+	FeedHandler           *FeedHandler
+	NotebookDigestHandler *NotebookDigestHandler
+	SearchHandler         *SearchHandler
+	EntityHandler         *EntityHandler
+	GraphHandler          *GraphHandler
+	NotebookQAHandler     *NotebookQAHandler
+	UploadSessionHandler  *UploadSessionHandler
+	SummaryHandler        *SummaryHandler
+	TrashHandler          *TrashHandler
+	ImportHandler         *ImportHandler
+	InboundEmailHandler   *InboundEmailHandler
+	RedactionHandler      *RedactionHandler
+	S3WatcherHandler      *S3WatcherHandler
+	ReportHandler         *ReportHandler
+	WebhookHandler        *WebhookSubscriptionHandler
+	ProcessingHookHandler *ProcessingHookHandler
+	ClassificationHandler *ClassificationHandler
+	ModerationHandler     *ModerationHandler
+	ContentHandler        *DocumentContentHandler
+	LegalHoldHandler      *LegalHoldHandler
+	LocaleHandler         *LocaleHandler
+	DocsHandler           *DocsHandler
+	GraphQLHandler        *GraphQLHandler
+	GRPC                  *grpcserver.Server // Internal gRPC API; nil when disabled
+	SpaceService          *services.SpaceContextService
+	RuntimeConfig         *services.RuntimeConfigService
+	Metrics               *metrics.Metrics
+	logger                *logger.Logger
+
+	// backgroundCancel stops goroutines started by services (retries, stream processing)
+	backgroundCancel context.CancelFunc
+	streamService    *services.StreamService
+	accessLogs       *services.AccessLogExporter
+	workers          *services.WorkerGroup
+	drainer          *middleware.Drainer
+	dbProbe          middleware.SaturationProbe
+	rateLimits       middleware.RateLimitSource
+	rateLimitStore   middleware.RateLimitStore
+	maintenance      middleware.MaintenanceSource
+	region           middleware.RegionSource
+	concurrency      gin.HandlerFunc // Shared by every API version, so that its limits hold across them
+	idempotency      gin.HandlerFunc // Shared by every API version, so that a key holds across them
+	webhooks         webhookVerifiers
+	versions         *apiversion.Set
+}
+
+// webhookVerifiers verify the deliveries of each inbound webhook integration
+type webhookVerifiers struct {
+	audiModal  *webhooks.Verifier
+	stripe     *webhooks.Verifier
+	streams    *webhooks.Verifier
+	email      *webhooks.Verifier
+	processing *webhooks.Verifier
+}
+
+// maintenanceExemptRoutes stay available during maintenance although they
+// are not GETs: POSTs that only read, the batch endpoint, whose operations
+// are checked one by one, and the routes that change maintenance
+var maintenanceExemptRoutes = map[string]bool{
+	"/api/v1/batch":                                true,
+	"/api/v1/logs":                                 true,
+	"/api/v1/graphql":                              true,
+	"/api/v1/chunks/search":                        true,
+	"/api/v1/strategies/recommend":                 true,
+	"/api/v1/classification-policies/evaluate":     true,
+	"/api/v1/notebooks/:id/ask":                    true,
+	"/api/v1/notebooks/:id/vector-search/text":     true,
+	"/api/v1/notebooks/:id/vector-search/hybrid":   true,
+	"/api/v1/spaces/:id/search/semantic":           true,
+	"/api/v1/router/chat/completions":              true,
+	"/api/v1/router/completions":                   true,
+	"/api/v1/router/messages":                      true,
+	"/api/v1/admin/maintenance":                    true,
+	"/api/v1/admin/maintenance/tenants/:tenant_id": true,
+}
+
+// bulkRoutes are the routes in the bulk concurrency class: uploads,
+// imports, exports and downloads, which hold their slot for as long as a
+// file takes to transfer or build
+var bulkRoutes = map[string]bool{
+	"/api/v1/documents/upload":                         true,
+	"/api/v1/documents/upload-base64":                  true,
+	"/api/v1/documents/:id/download":                   true,
+	"/api/v1/documents/:id/versions":                   true,
+	"/api/v1/documents/:id/versions/:version/download": true,
+	"/api/v1/notebooks/:id/documents/bulk":             true,
+	"/api/v1/notebooks/:id/documents/export":           true,
+	"/api/v1/uploads/:id/parts/:number":                true,
+	"/api/v1/imports":                                  true,
+	"/api/v1/reports/runs/:id/download":                true,
+	"/api/v1/organizations/:id/audit/export":           true,
+	"/api/v1/spaces/:id/audit/export":                  true,
+	"/api/v1/admin/audit/export":                       true,
+	"/api/v1/admin/tenants/:tenant_id/export":          true,
+}
+
+// concurrencyClass returns the concurrency class of a request. The batch
+// endpoint is not limited itself since each of its operations takes a slot.
+func concurrencyClass(c *gin.Context) string {
+	route := c.FullPath()
+	switch {
+	case route == batchPath:
+		return ""
+	case bulkRoutes[route]:
+		return config.ConcurrencyBulk
+	case strings.HasPrefix(route, "/api/v1/admin/"):
+		return config.ConcurrencyAdmin
+	}
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return config.ConcurrencyRead
+	}
+	return config.ConcurrencyWrite
+}
+
+// agentRoutes are the routes in the agent rate limit class: executions of
+// agents and workflows and model completions, which cost the most
+var agentRoutes = map[string]bool{
+	"/api/v1/agents/:id/execute":      true,
+	"/api/v1/workflows/:id/execute":   true,
+	"/api/v1/router/chat/completions": true,
+	"/api/v1/router/completions":      true,
+	"/api/v1/router/messages":         true,
+}
+
+// rateLimitClass returns the rate limit class of a request. Uploads,
+// imports and exports are the bulk routes that are not GETs. The batch
+// endpoint is not limited itself since each of its operations is.
+func rateLimitClass(c *gin.Context) string {
+	route := c.FullPath()
+	switch {
+	case route == batchPath:
+		return ""
+	case agentRoutes[route]:
+		return config.RateLimitAgent
+	}
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return config.RateLimitRead
+	}
+	if bulkRoutes[route] {
+		return config.RateLimitUpload
+	}
+	return config.RateLimitWrite
 }
 
 // NewAPIServer creates a new API server with all routes configured
@@ -46,14 +200,32 @@ func NewAPIServer(
 	keycloakClient *auth.KeycloakClient,
 	storageService *services.S3StorageService,
 	kafkaService *services.KafkaService,
+	postgres *database.PostgresClient,
+	lockStore services.LockStore,
+	pubSub services.PubSub,
+	queueStore services.QueueStore,
+	rateLimitStore middleware.RateLimitStore,
+	idempotencyStore middleware.IdempotencyStore,
+	suggestIndex services.SuggestIndex,
+	resultCache services.ResultCache,
 	audiModalClient *services.AudiModalService,
 	metricsInstance *metrics.Metrics,
+	healthRegistry *health.Registry,
 	log *logger.Logger,
 ) *APIServer {
 	// Initialize services
 	userService := services.NewUserService(neo4j, audiModalClient, log)
 	organizationService := services.NewOrganizationService(neo4j, audiModalClient, log)
 	spaceService := services.NewSpaceService(neo4j, log)
+	spaceService.SetOCRLanguagePacks(cfg.AudiModal.OCRLanguagePacks)
+
+	// Documents are processed by the provider their space chooses;
+	// AudiModal is the default. Other providers are registered here.
+	processingProviders := services.NewProcessingProviderRegistry()
+	if audiModalClient != nil {
+		processingProviders.Register(services.NewAudiModalProvider(audiModalClient))
+	}
+	spaceService.SetProcessingProviders(processingProviders.Names())
 	spaceContextService := services.NewSpaceContextService(userService, organizationService, spaceService, audiModalClient, log)
 	notebookService := services.NewNotebookService(neo4j, log)
 	documentService := services.NewDocumentService(neo4j, notebookService, log)
@@ -72,6 +244,12 @@ func NewAPIServer(
 		log.Warn("AGENT_BUILDER_URL not configured - agent endpoints will not work")
 	}
 	agentService := services.NewAgentService(neo4j, userService, notebookService, teamService, agentBuilderURL, log)
+	if metricsInstance != nil {
+		agentService.SetMetrics(metricsInstance)
+	}
+	if os.Getenv("AGENT_BUILDER_URL") != "" {
+		healthRegistry.Register("agent_builder", false, agentService.HealthCheck)
+	}
 
 	// Onboarding service for automatic new user setup
 	onboardingService := services.NewOnboardingService(
@@ -85,15 +263,431 @@ func NewAPIServer(
 
 	// Set dependencies for document service
 	documentService.SetStorageService(storageService)
-	documentService.SetProcessingService(audiModalClient)
+	documentService.SetProcessingProviders(processingProviders)
+	documentService.SetMaxDirectUploadBytes(cfg.BodyLimits.DirectUploadBytes)
+
+	// Status changes reach WebSocket clients on every replica through the
+	// event hub; without Redis it only reaches clients of this instance
+	eventHub := services.NewEventHub(pubSub, log)
+
+	// Processing jobs are stored so their state survives restarts, and
+	// their progress is announced to real-time clients
+	processingJobs := services.NewAnnouncedProcessingJobRepository(services.NewNeo4jProcessingJobRepository(neo4j, log), eventHub)
+	documentService.SetProcessingJobRepository(processingJobs)
+	if audiModalClient != nil {
+		audiModalClient.SetJobRepository(processingJobs)
+		audiModalClient.SetTenantMapping(services.NewTenantMappingService(neo4j, spaceService, audiModalClient, &cfg.AudiModal, resultCache, log))
+	}
+	if metricsInstance != nil {
+		documentService.SetMetrics(metricsInstance)
+	}
+
+	// Notebook and document changes are published as domain events to
+	// Kafka and, when PostgreSQL is configured, projected into reporting
+	// tables
+	domainEvents := services.NewDomainEventBus(kafkaService, log)
+	notebookService.SetEventPublisher(domainEvents)
+	documentService.SetEventPublisher(domainEvents)
+
+	// User-initiated changes are appended to the hash-chained audit log
+	auditService := services.NewAuditService(neo4j, log)
+	domainEvents.Subscribe(auditService.RecordDomainEvent)
+
+	// With TEXT_ENCRYPTION_KEY, spaces can have the extracted text of their
+	// documents encrypted at rest under a key per tenant
+	var textEncryptionService *services.TextEncryptionService
+	if cfg.Encryption.MasterKey != "" {
+		encryption, err := services.NewTextEncryptionService(neo4j, auditService, cfg.Encryption, log)
+		if err != nil {
+			// Validate rejects bad keys at startup
+			log.WithError(err).Error("Invalid text encryption key, text encryption is unavailable")
+		} else {
+			textEncryptionService = encryption
+			documentService.SetTextEncryption(textEncryptionService)
+		}
+	}
+
+	// Search bar typeahead reads a prefix index kept current from domain
+	// events; without Redis the index is local to this instance
+	suggestService := services.NewSuggestService(neo4j, suggestIndex, log)
+	domainEvents.Subscribe(suggestService.HandleDomainEvent)
+
+	// Similar document rankings are cached per document and dropped when
+	// it changes; without Redis the cache is local to this instance
+	vectorSearchService := services.NewVectorSearchService(&cfg.DeepLake, spaceService, neo4j, log)
+	similarDocumentService := services.NewSimilarDocumentService(neo4j, documentService, vectorSearchService, resultCache, log)
+	domainEvents.Subscribe(similarDocumentService.HandleDomainEvent)
+	relatedDocumentService := services.NewRelatedDocumentService(neo4j, documentService, similarDocumentService, log)
+	domainEvents.Subscribe(relatedDocumentService.HandleDomainEvent)
+
+	// People, organizations and topics detected in processed documents
+	// become Entity nodes the documents MENTION
+	entityService := services.NewEntityService(neo4j, log)
+	entityService.SetTextEncryption(textEncryptionService)
+	domainEvents.Subscribe(entityService.HandleDomainEvent)
+	knowledgeGraphService := services.NewKnowledgeGraphService(neo4j, log)
+	notebookQAService := services.NewNotebookQAService(neo4j, notebookService, vectorSearchService, &cfg.Router, &cfg.QA, log)
+	uploadSessionService := services.NewUploadSessionService(neo4j, documentService, &cfg.BodyLimits, log)
+
+	// Document abstracts and notebook digests are cached on their nodes;
+	// with SUMMARY_AUTO_ENABLED processed documents are queued for one
+	summaryService := services.NewSummaryService(neo4j, documentService, notebookService, &cfg.Router, &cfg.Summary, log)
+	domainEvents.Subscribe(summaryService.HandleDomainEvent)
+
+	// Classification policies route documents once they are processed
+	classificationService := services.NewClassificationPolicyService(neo4j, documentService, log)
+	classificationService.SetEventPublisher(domainEvents)
+	domainEvents.Subscribe(classificationService.HandleDomainEvent)
+
+	// With MODERATION_ENABLED, documents of public notebooks are held from
+	// the space until they are scanned. Reviews stay available when it is
+	// turned off, so documents it held can still be released.
+	moderationService, err := services.NewModerationService(neo4j, documentService, &cfg.Router, cfg.Moderation, log)
+	if err != nil {
+		// Validate rejects unknown providers at startup
+		log.WithError(err).Error("Invalid moderation provider, content moderation is unavailable")
+	} else {
+		notebookService.SetModeration(moderationService)
+		domainEvents.Subscribe(moderationService.HandleDomainEvent)
+	}
+
+	// With SCANNER_PROVIDER, files uploaded to spaces that scan are checked
+	// for malware before they are stored, and infected ones quarantined
+	if cfg.Scanner.Provider != "" && storageService != nil {
+		scannerService, err := services.NewScannerService(cfg.Scanner, storageService, auditService, log)
+		if err != nil {
+			// Validate rejects unknown providers at startup
+			log.WithError(err).Error("Invalid scanner provider, uploads are not scanned")
+		} else {
+			documentService.SetScanner(scannerService)
+			spaceService.SetScannerConfigured(true)
+		}
+	}
+
+	// Uploaded files are checked against their content, and against the
+	// types in UPLOAD_ALLOWED_TYPES and each space's own restrictions
+	documentService.SetFileValidator(services.NewFileValidator(cfg.Uploads, log))
+	documentService.SetProcessingPriorities(cfg.Priority)
+	documentService.SetDownloads(cfg.Downloads)
+
+	var reportingProjector *services.ReportingProjector
+	if postgres != nil {
+		reportingProjector = services.NewReportingProjector(postgres.DB(), log)
+		schemaCtx, schemaCancel := context.WithTimeout(context.Background(), 30*time.Second)
+		if err := reportingProjector.EnsureSchema(schemaCtx); err != nil {
+			log.WithError(err).Error("Failed to create reporting schema - reporting projection disabled")
+			reportingProjector = nil
+		} else {
+			domainEvents.Subscribe(reportingProjector.Apply)
+		}
+		schemaCancel()
+	}
+
+	// Bind background work to the server lifetime and apply configured timeouts
+	backgroundCtx, backgroundCancel := context.WithCancel(context.Background())
+	workers := services.NewWorkerGroup()
+	documentService.SetBackgroundContext(backgroundCtx)
+	documentService.SetWorkerGroup(workers)
+	documentService.SetBackgroundJobTimeout(time.Duration(cfg.Timeouts.BackgroundJobMinutes) * time.Minute)
+	documentService.SetInstanceID(cfg.Cluster.InstanceID)
+	jobService := services.NewJobService(neo4j, cfg.Jobs, log)
+	jobService.SetBackgroundContext(backgroundCtx)
+	jobService.SetWorkerGroup(workers)
+	jobService.SetTimeout(time.Duration(cfg.Timeouts.BackgroundJobMinutes) * time.Minute)
+	agentService.SetHTTPTimeout(time.Duration(cfg.Timeouts.ExternalHTTPSeconds) * time.Second)
+
+	// Singleton jobs run only on the elected leader so replicas can be
+	// added without repeating work. Without a shared lock store every
+	// instance leads, which is only safe for a single replica.
+	if lockStore == nil {
+		lockStore = services.NewLocalLockStore()
+	}
+	elector := services.NewLeaderElector(
+		lockStore,
+		services.LeaderLockKey,
+		cfg.Cluster.InstanceID,
+		time.Duration(cfg.Cluster.LeaderLeaseSeconds)*time.Second,
+		log,
+	)
+	workers.Go(func() { elector.Run(backgroundCtx) })
+
+	// Inbound webhooks share the lock store to reject replayed deliveries
+	// across replicas
+	webhookTolerance := time.Duration(cfg.Webhooks.ToleranceSeconds) * time.Second
+	snsScheme := webhooks.NewSNSScheme(&http.Client{Timeout: 10 * time.Second})
+	webhookVerifiers := webhookVerifiers{
+		audiModal:  webhooks.NewVerifier("audimodal", cfg.AudiModal.WebhookSecret, webhooks.HMACScheme{}, webhookTolerance, lockStore),
+		stripe:     webhooks.NewVerifier("stripe", cfg.Webhooks.StripeSecret, webhooks.StripeScheme{}, webhookTolerance, lockStore),
+		streams:    webhooks.NewVerifier("streams", cfg.Webhooks.StreamSecret, webhooks.HMACScheme{}, webhookTolerance, lockStore),
+		// SNS deliveries are signed by AWS; the secret is the topic they come from
+		email:      webhooks.NewVerifier("email", cfg.Email.SNSTopicARN, snsScheme, webhookTolerance, lockStore),
+		processing: webhooks.NewVerifier("processing", cfg.Webhooks.ProcessingSecret, webhooks.HMACScheme{}, webhookTolerance, lockStore),
+	}
+
+	// API versions; deprecation schedules come from configuration
+	versions := apiversion.NewSet(cfg.API.DefaultVersion, apiversion.Version{Name: "v1"}, apiversion.Version{Name: "v2"})
+	schedules, err := cfg.API.Schedules()
+	if err != nil {
+		// Validate rejects bad schedules at startup
+		log.WithError(err).Error("Invalid API deprecation schedule, ignoring it")
+	}
+	for name, schedule := range schedules {
+		if err := versions.Deprecate(name, schedule.Deprecated, schedule.Sunset, cfg.API.DeprecationLink); err != nil {
+			log.WithError(err).Error("Ignoring deprecation of unknown API version")
+		}
+	}
+
+	// Periodic jobs are registered with the scheduler rather than given
+	// their own tickers; schedules come from configuration
+	scheduler := services.NewScheduler(neo4j, elector, cfg.Cluster.InstanceID, cfg.Scheduler.HistoryLimit, log)
+	scheduledJobs := []struct {
+		name string
+		spec string
+		fn   services.JobFunc
+	}{
+		{"processing_retries", cfg.Scheduler.ProcessingRetries, documentService.ProcessDueRetries},
+		{"notebook_count_reconciliation", cfg.Scheduler.CountReconciliation, notebookService.ReconcileDocumentCounts},
+		{"job_cleanup", cfg.Scheduler.JobCleanup, jobService.CleanupJobs},
+		{"upload_cleanup", cfg.Scheduler.UploadCleanup, uploadSessionService.CleanupUploads},
+		{"document_summaries", cfg.Scheduler.Summaries, summaryService.SummarizePending},
+		{"related_documents", cfg.Scheduler.RelatedDocuments, relatedDocumentService.LinkPending},
+	}
+	for _, job := range scheduledJobs {
+		if err := scheduler.Register(job.name, job.spec, job.fn); err != nil {
+			log.WithError(err).Error("Failed to register scheduled job")
+		}
+	}
+
+	// Processed documents queue their chunk embeddings for DeepLake through
+	// domain events; the leader pushes them on a schedule
+	var vectorSyncService *services.VectorSyncService
+	if cfg.DeepLake.Enabled && cfg.Embedding.Enabled && cfg.OpenAI.APIKey != "" && audiModalClient != nil {
+		embedder := services.NewOpenAIEmbeddingProvider(&cfg.OpenAI, log)
+		vectorSyncService = services.NewVectorSyncService(neo4j, documentService, spaceService, audiModalClient, embedder, &cfg.DeepLake, &cfg.Embedding, log)
+		domainEvents.Subscribe(vectorSyncService.HandleDomainEvent)
+		if err := scheduler.Register("vector_sync", cfg.Scheduler.VectorSync, vectorSyncService.ProcessDue); err != nil {
+			log.WithError(err).Error("Failed to register scheduled job")
+		}
+	}
+
+	// Processed documents' chunks are mirrored from AudiModal into Neo4j
+	// so answers cite them without a request to AudiModal
+	if cfg.ChunkSync.Enabled && audiModalClient != nil {
+		chunkSyncService := services.NewChunkSyncService(neo4j, documentService, audiModalClient, cfg.ChunkSync, log)
+		domainEvents.Subscribe(chunkSyncService.HandleDomainEvent)
+		if err := scheduler.Register("chunk_sync", cfg.Scheduler.ChunkSync, chunkSyncService.SyncPending); err != nil {
+			log.WithError(err).Error("Failed to register scheduled job")
+		}
+	}
+
+	// Queued documents of public notebooks are scanned on a schedule once
+	// processed
+	if cfg.Moderation.Enabled && moderationService != nil {
+		if err := scheduler.Register("moderation", cfg.Scheduler.Moderation, moderationService.ModeratePending); err != nil {
+			log.WithError(err).Error("Failed to register scheduled job")
+		}
+	}
+
+	// Deleted documents' files, chunks and vectors are removed from the
+	// other services by the deletion orchestrator; the leader retries the
+	// targets that failed
+	var deletionFiles services.DeletionFileStore
+	if len(processingProviders.Names()) > 0 {
+		deletionFiles = processingProviders
+	}
+	var objectStorage services.StorageService
+	if storageService != nil {
+		objectStorage = storageService
+	}
+	deletionOrchestrator := services.NewDeletionOrchestrator(neo4j, objectStorage, deletionFiles, log)
+	if vectorSyncService != nil {
+		deletionOrchestrator.SetVectorRemover(vectorSyncService)
+	}
+	documentService.SetDeletionOrchestrator(deletionOrchestrator)
+	if err := scheduler.Register("document_deletions", cfg.Scheduler.DocumentDeletions, deletionOrchestrator.ProcessDue); err != nil {
+		log.WithError(err).Error("Failed to register scheduled job")
+	}
+
+	// Deleted documents and notebooks stay in the trash, restorable, until
+	// the leader purges them once the retention has passed
+	trashService := services.NewTrashService(neo4j, documentService, cfg.Trash.RetentionDays, log)
+	trashService.SetEventPublisher(domainEvents)
+	if err := scheduler.Register("trash_purge", cfg.Scheduler.TrashPurge, trashService.PurgeExpired); err != nil {
+		log.WithError(err).Error("Failed to register scheduled job")
+	}
+
+	// Imports of other tools' export archives run as async jobs
+	importService := services.NewImportService(notebookService, documentService, cfg.BodyLimits.ImportFiles, cfg.BodyLimits.UploadBytes, log)
+	inboundEmailService := services.NewInboundEmailService(neo4j, notebookService, documentService, spaceContextService, cfg.Email, cfg.BodyLimits.UploadBytes, log)
+
+	// Redaction rules of shared notebooks are enforced on the extracted
+	// text and chunks their documents are read through
+	var redactionChunks services.ChunkSource
+	if audiModalClient != nil {
+		redactionChunks = audiModalClient
+	}
+	redactionService := services.NewRedactionService(neo4j, notebookService, redactionChunks, log)
+	watermarkService := services.NewWatermarkService(documentService, notebookService, redactionService, log)
+	// Extracted text and chunks are served a page at a time under the same
+	// access checks and redaction
+	documentContentService := services.NewDocumentContentService(documentService, redactionService, redactionChunks, log)
+
+	// S3 watchers ingest from customers' buckets on the leader alone, so
+	// an object is not ingested by several instances at once
+	s3WatcherService := services.NewS3WatcherService(neo4j, notebookService, documentService, spaceContextService, cfg.S3Watch, cfg.BodyLimits.UploadBytes, log)
+	if err := scheduler.Register("s3_watchers", cfg.Scheduler.S3Watchers, s3WatcherService.ProcessDue); err != nil {
+		log.WithError(err).Error("Failed to register scheduled job")
+	}
+
+	// Scheduled space reports are generated by the leader once their
+	// period ends and kept in the tenant's bucket
+	reportService := services.NewReportService(neo4j, objectStorage, cfg.Reports, log)
+	reportService.SetEventPublisher(domainEvents)
+	if err := scheduler.Register("space_reports", cfg.Scheduler.Reports, reportService.ProcessDue); err != nil {
+		log.WithError(err).Error("Failed to register scheduled job")
+	}
+
+	// Notebook digests are built from the activity feed by the leader once
+	// their period ends, and emailed through the reports' SMTP server
+	feedService := services.NewFeedService(neo4j, auditService, spaceService, log)
+	notebookDigestService := services.NewNotebookDigestService(neo4j, feedService, cfg.Reports, log)
+	notebookDigestService.SetEventPublisher(domainEvents)
+	if err := scheduler.Register("notebook_digests", cfg.Scheduler.NotebookDigests, notebookDigestService.ProcessDue); err != nil {
+		log.WithError(err).Error("Failed to register scheduled job")
+	}
+
+	// Notifications and the first attempts of webhook deliveries go
+	// through a queue every replica works on; with Redis it is a stream,
+	// so jobs survive restarts
+	deliveryQueue := services.NewDeliveryQueue(queueStore, cfg.Cluster.InstanceID, cfg.Queue, log)
+
+	// Outbound webhooks are queued as domain events happen and first sent
+	// through the delivery queue; the leader retries failed deliveries
+	// with backoff
+	webhookService := services.NewWebhookService(neo4j, cfg.Webhooks, log)
+	webhookService.SetDeliveryQueue(deliveryQueue)
+	spaceService.SetEventPublisher(domainEvents)
+	agentService.SetEventPublisher(domainEvents)
+	domainEvents.Subscribe(webhookService.HandleDomainEvent)
+	if err := scheduler.Register("webhook_deliveries", cfg.Scheduler.WebhookDeliveries, webhookService.ProcessDue); err != nil {
+		log.WithError(err).Error("Failed to register scheduled job")
+	}
+
+	// Synthetic probes call the API like a client, so running them on the
+	// leader alone is enough
+	var syntheticProber *services.SyntheticProber
+	if cfg.Synthetic.Enabled {
+		syntheticProber = services.NewSyntheticProber(cfg.Synthetic, cfg.Keycloak, cfg.Cluster.InstanceID, log)
+		syntheticProber.SetEventPublisher(domainEvents)
+		if metricsInstance != nil {
+			syntheticProber.SetMetrics(metricsInstance)
+		}
+		if err := scheduler.Register("synthetic_probes", cfg.Scheduler.SyntheticProbes, syntheticProber.RunOnce); err != nil {
+			log.WithError(err).Error("Failed to register scheduled job")
+		}
+	}
+	workers.Go(func() { scheduler.Run(backgroundCtx) })
+
+	// SLOs are evaluated on every replica against its own traffic
+	var sloMonitor *services.SLOMonitor
+	if metricsInstance != nil && metricsInstance.SLOTracker() != nil {
+		sloMonitor = services.NewSLOMonitor(
+			metricsInstance.SLOTracker(),
+			cfg.Cluster.InstanceID,
+			time.Duration(cfg.SLO.EvaluationSeconds)*time.Second,
+			log,
+		)
+		sloMonitor.SetEventPublisher(domainEvents)
+		workers.Go(func() { sloMonitor.Run(backgroundCtx) })
+	}
+
+	// Tenant usage is compared with its baseline every interval, also per replica
+	var anomalyMonitor *services.AnomalyMonitor
+	if metricsInstance != nil && metricsInstance.UsageAnomalyDetector() != nil {
+		anomalyMonitor = services.NewAnomalyMonitor(
+			metricsInstance.UsageAnomalyDetector(),
+			cfg.Cluster.InstanceID,
+			time.Duration(cfg.Anomaly.IntervalSeconds)*time.Second,
+			log,
+		)
+		anomalyMonitor.SetEventPublisher(domainEvents)
+		workers.Go(func() { anomalyMonitor.Run(backgroundCtx) })
+	}
+
+	// Runtime configuration can be reloaded without a restart; subscribers
+	// push changed values into the components that use them
+	runtimeStore := config.NewRuntimeStore(cfg.Runtime)
+	runtimeStore.Subscribe(func(rc config.RuntimeConfig) {
+		if err := log.SetLevel(rc.LogLevel); err != nil {
+			log.WithError(err).Error("Failed to apply log level")
+		}
+		if audiModalClient != nil {
+			audiModalClient.SetRequestTimeout(time.Duration(rc.AudiModal.RequestTimeoutSeconds) * time.Second)
+		}
+	})
+	runtimeConfigService := services.NewRuntimeConfigService(runtimeStore, auditService, cfg.Server.ConfigReloadFile, log)
+
+	// Maintenance windows are stored in Neo4j and re-read by every replica,
+	// which rejects writes while one applies
+	maintenanceService := services.NewMaintenanceService(neo4j, auditService, time.Duration(cfg.Maintenance.RefreshSeconds)*time.Second, log)
+	workers.Go(func() { maintenanceService.Run(backgroundCtx) })
+
+	// Tenant region pins, when the deployment spans regions; each replica
+	// re-reads them and turns away requests for tenants served elsewhere
+	var regionService *services.RegionService
+	var regionSource middleware.RegionSource
+	if cfg.Region.Enabled() {
+		regionService = services.NewRegionService(neo4j, auditService, cfg.Region, log)
+		workers.Go(func() { regionService.Run(backgroundCtx) })
+		regionSource = regionService
+	}
+
+	// Every permission check goes through the policy engine, which adds the
+	// custom roles of organizations to the built-in ones; each replica
+	// re-reads them
+	authzService := services.NewAuthorizationService(neo4j, auditService, time.Duration(cfg.Roles.RefreshSeconds)*time.Second, log)
+	workers.Go(func() { authzService.Run(backgroundCtx) })
+	spaceContextService.SetAuthorizationService(authzService)
+	spaceService.SetAuthorizationService(authzService)
+	organizationService.SetAuthorizationService(authzService)
+	documentService.SetAuthorizationService(authzService)
+	agentService.SetAuthorizationService(authzService)
 
-	// Initialize processing event handler for Kafka events from audimodal
+	// Space quotas are enforced on uploads, agent executions and stream
+	// events; usage reaching a warning threshold is published as an event
+	quotaService := services.NewQuotaService(neo4j, auditService, cfg.Quotas, log)
+	quotaService.SetEventPublisher(domainEvents)
+	documentService.SetQuotaService(quotaService)
+	agentService.SetQuotaService(quotaService)
+	streamService.SetQuotaService(quotaService)
+
+	agentService.SetEventHub(eventHub)
+	streamService.SetEventHub(eventHub)
+	eventHub.SetDeliveryQueue(deliveryQueue)
+	domainEvents.Subscribe(eventHub.HandleDomainEvent)
+	// Started once every kind of job has its handler
+	workers.Go(func() { deliveryQueue.Run(backgroundCtx) })
+
+	// Processing outcomes arrive as Kafka events from audimodal or, where
+	// Kafka is not deployed, as processing callbacks; both are claimed in
+	// the lock store, so an outcome sent both ways is applied once
+	processingEventHandler := services.NewProcessingEventHandler(documentService, kafkaService, lockStore, log)
 	if kafkaService != nil {
-		processingEventHandler := services.NewProcessingEventHandler(documentService, kafkaService, log)
 		if err := processingEventHandler.Start(); err != nil {
 			log.WithError(err).Error("Failed to start processing event handler - document sync from audimodal will not work")
 		} else {
-			log.Info("Processing event handler started - listening for processing.complete events")
+			log.Info("Processing event handler started - listening for processing.complete and processing.failed events")
+		}
+	}
+
+	// Fan live events out through Kafka so WebSocket clients on every
+	// replica receive them
+	if kafkaService != nil {
+		streamBus := services.NewKafkaStreamBus(kafkaService, log)
+		if err := streamBus.Start(streamService.BroadcastEvent); err != nil {
+			log.WithError(err).Error("Failed to start stream event bus - live events will only reach clients of this instance")
+		} else {
+			streamService.SetEventBus(streamBus)
 		}
 	}
 
@@ -101,19 +695,99 @@ func NewAPIServer(
 	userHandler := NewUserHandler(userService, spaceContextService, onboardingService, log)
 	notebookHandler := NewNotebookHandler(notebookService, userService, log)
 	documentHandler := NewDocumentHandler(documentService, audiModalClient, log)
+	documentHandler.SetMaxUploadBytes(cfg.BodyLimits.UploadBytes)
+	documentHandler.SetMaxBulkUploadFiles(cfg.BodyLimits.BulkUploadFiles)
+	documentHandler.SetJobService(jobService)
+	documentHandler.SetSimilarDocumentService(similarDocumentService)
+	documentHandler.SetRelatedDocumentService(relatedDocumentService)
+	documentHandler.SetDocumentComparisonService(services.NewDocumentComparisonService(documentContentService, resultCache, cfg.Compare, log))
+	documentHandler.SetRedactionService(redactionService)
+	documentHandler.SetWatermarkService(watermarkService)
+	if vectorSyncService != nil {
+		documentHandler.SetVectorSyncService(vectorSyncService)
+	}
 	chunkHandler := NewChunkHandler(neo4j, chunkService, audiModalClient, log)
-	jobHandler := NewJobHandler(documentService, audiModalClient, log)
+	chunkHandler.SetRedactionService(redactionService)
+	jobHandler := NewJobHandler(jobService, documentService, audiModalClient, log)
+	batchHandler := NewBatchHandler(jobService, cfg.Jobs.MaxBatchOperations, log)
 	webSocketHandler := NewWebSocketHandler(documentService, audiModalClient, log)
+	webSocketHandler.SetEventHub(eventHub)
+	webSocketHandler.SetSubscriptionServices(spaceContextService, teamService)
 	mlHandler := NewMLHandler(mlService, log)
 	workflowHandler := NewWorkflowHandler(workflowService, log)
 	teamHandler := NewTeamHandler(teamService, userService, log)
 	organizationHandler := NewOrganizationHandler(organizationService, userService, log)
+	organizationHandler.SetJobService(jobService)
+	organizationHandler.SetAuthorizationService(authzService)
 	spaceHandler := NewSpaceHandler(spaceContextService, spaceService, userService, organizationService, log)
+	spaceHandler.SetQuotaService(quotaService)
 	agentHandler := NewAgentHandler(agentService, userService, teamService, log)
 	streamHandler := NewStreamHandler(streamService, log)
-	healthHandler := NewHealthHandler(neo4j, storageService, kafkaService, log)
+	drainer := middleware.NewDrainer()
+	healthHandler := NewHealthHandler(healthRegistry, drainer, cfg.Server.Version, log)
 	loggingHandler := NewLoggingHandler(log)
+	adminHandler := NewAdminHandler(runtimeConfigService, scheduler, log)
+	auditHandler := NewAuditHandler(auditService, spaceService, organizationService, userService, log)
+	feedHandler := NewFeedHandler(feedService, notebookService, scheduler, userService, cfg.Feeds.BaseURL, cfg.Feeds.MaxItems, log)
+	entityHandler := NewEntityHandler(entityService, documentService, notebookService, userService, log)
+	searchHandler := NewSearchHandler(services.NewSearchService(neo4j, teamService, log), suggestService, userService, log)
+	if reportingProjector != nil {
+		adminHandler.SetReportingProjector(reportingProjector)
+	}
+	if sloMonitor != nil {
+		adminHandler.SetSLOMonitor(sloMonitor)
+	}
+	if anomalyMonitor != nil {
+		adminHandler.SetAnomalyMonitor(anomalyMonitor)
+	}
+	if syntheticProber != nil {
+		adminHandler.SetSyntheticProber(syntheticProber)
+	}
+	adminHandler.SetSpaceService(spaceService)
+	adminHandler.SetDocumentService(documentService)
+	adminHandler.SetDeletionOrchestrator(deletionOrchestrator)
+	adminHandler.SetTenantServices(organizationService, userService)
+	adminHandler.SetMaintenanceService(maintenanceService)
+	if regionService != nil {
+		adminHandler.SetRegionService(regionService)
+	}
+	adminHandler.SetQuotaService(quotaService)
+	if textEncryptionService != nil {
+		adminHandler.SetTextEncryption(textEncryptionService)
+	}
+	tenantExportService := services.NewTenantExportService(neo4j, objectStorage, log)
+	tenantExportService.SetAuditService(auditService)
+	adminHandler.SetTenantExports(tenantExportService, jobService)
+	legalHoldService := services.NewLegalHoldService(neo4j, tenantExportService, auditService, log)
+	legalHoldService.SetTextEncryption(textEncryptionService)
+	documentService.SetLegalHolds(legalHoldService)
+	notebookService.SetLegalHolds(legalHoldService)
+	organizationService.SetLegalHolds(legalHoldService)
 	vectorSearchHandler := NewVectorSearchHandler(notebookService, documentService, userService, &cfg.DeepLake, log)
+	vectorSearchHandler.SetVectorSearchService(vectorSearchService)
+	graphQLHandler := NewGraphQLHandler(userService, log)
+	if cfg.GraphQL.Enabled {
+		graphQLAPI, err := gql.New(gql.Deps{
+			Notebooks: notebookService,
+			Documents: documentService,
+			Agents:    agentService,
+			Spaces:    spaceContextService,
+			Teams:     teamService,
+		}, cfg.GraphQL.MaxDepth, log)
+		if err != nil {
+			log.WithError(err).Error("Failed to initialize GraphQL API - /api/v1/graphql will respond 503")
+		} else {
+			graphQLHandler.SetAPI(graphQLAPI)
+		}
+	}
+	var grpcServer *grpcserver.Server
+	if cfg.GRPC.Enabled {
+		grpcServer = grpcserver.New(cfg.GRPC, grpcserver.Deps{
+			Documents: documentService,
+			Events:    domainEvents,
+			Tenants:   spaceService,
+		}, log)
+	}
 
 	// Initialize router handler (may be nil if disabled)
 	routerHandler, err := NewRouterHandler(&cfg.Router, log)
@@ -126,43 +800,112 @@ func NewAPIServer(
 	gin.SetMode(gin.ReleaseMode) // Set to DebugMode for development
 	router := gin.New()
 
-	// Global middleware
+	// Global middleware - request ID first so every later log line can be correlated
+	router.Use(middleware.RequestIDMiddleware())
+	router.Use(middleware.Locale())
+	router.Use(drainer.Middleware())
 	router.Use(debugRequestMiddleware(log))
-	router.Use(customRecoveryMiddleware(log))
-	router.Use(requestLoggingMiddleware())
+	router.Use(middleware.Recovery(log))
+	router.Use(middleware.ErrorHandler(log))
+	var accessLogExporter *services.AccessLogExporter
+	if cfg.AccessLog.Enabled {
+		var exporter middleware.AccessLogExporter
+		if accessLogExporter = newAccessLogExporter(cfg, kafkaService, storageService, log); accessLogExporter != nil {
+			exporter = accessLogExporter
+		}
+		router.Use(middleware.AccessLog(log, cfg.AccessLog, exporter))
+	}
 	router.Use(corsMiddleware())
-	router.Use(middleware.RequestIDMiddleware())
 	router.Use(middleware.SecurityHeaders())
-	router.Use(middleware.RequestSizeLimit(10 << 20)) // 10MB limit
+	router.Use(middleware.APIVersion(versions, log))
+	var compressionRecorder middleware.CompressionRecorder
+	if metricsInstance != nil {
+		compressionRecorder = metricsInstance
+	}
+	router.Use(middleware.Compression(log, cfg.Compression, compressionRecorder))
+	var concurrencyRecorder middleware.ConcurrencyRecorder
+	if metricsInstance != nil {
+		concurrencyRecorder = metricsInstance
+	}
+	router.Use(bodyLimit(cfg, log))
 	router.Use(middleware.ValidationMiddleware(log))
 	router.Use(metrics.HTTPMetricsMiddleware(metricsInstance, log))
 
 	server := &APIServer{
-		Router:               router,
-		UserHandler:          userHandler,
-		NotebookHandler:      notebookHandler,
-		DocumentHandler:      documentHandler,
-		ChunkHandler:         chunkHandler,
-		JobHandler:           jobHandler,
-		WebSocketHandler:     webSocketHandler,
-		MLHandler:            mlHandler,
-		WorkflowHandler:      workflowHandler,
-		TeamHandler:          teamHandler,
-		OrganizationHandler:  organizationHandler,
-		SpaceHandler:         spaceHandler,
-		AgentHandler:         agentHandler,
-		HealthHandler:        healthHandler,
-		StreamHandler:        streamHandler,
-		RouterHandler:        routerHandler,
-		LoggingHandler:       loggingHandler,
-		VectorSearchHandler:  vectorSearchHandler,
-		SpaceService:         spaceContextService,
-		Metrics:              metricsInstance,
-		logger:               log.WithService("api_server"),
+		Router:                router,
+		UserHandler:           userHandler,
+		NotebookHandler:       notebookHandler,
+		DocumentHandler:       documentHandler,
+		ChunkHandler:          chunkHandler,
+		JobHandler:            jobHandler,
+		BatchHandler:          batchHandler,
+		WebSocketHandler:      webSocketHandler,
+		MLHandler:             mlHandler,
+		WorkflowHandler:       workflowHandler,
+		TeamHandler:           teamHandler,
+		OrganizationHandler:   organizationHandler,
+		SpaceHandler:          spaceHandler,
+		AgentHandler:          agentHandler,
+		HealthHandler:         healthHandler,
+		StreamHandler:         streamHandler,
+		RouterHandler:         routerHandler,
+		LoggingHandler:        loggingHandler,
+		VectorSearchHandler:   vectorSearchHandler,
+		AdminHandler:          adminHandler,
+		AuditHandler:          auditHandler,
+		FeedHandler:           feedHandler,
+		NotebookDigestHandler: NewNotebookDigestHandler(notebookDigestService, notebookService, userService, log),
+		SearchHandler:         searchHandler,
+		EntityHandler:         entityHandler,
+		GraphHandler:          NewGraphHandler(knowledgeGraphService, log),
+		NotebookQAHandler:     NewNotebookQAHandler(notebookQAService, log),
+		UploadSessionHandler:  NewUploadSessionHandler(uploadSessionService, log),
+		SummaryHandler:        NewSummaryHandler(summaryService, log),
+		TrashHandler:          NewTrashHandler(trashService, log),
+		ImportHandler:         NewImportHandler(importService, jobService, cfg.BodyLimits.ImportBytes, log),
+		InboundEmailHandler:   NewInboundEmailHandler(inboundEmailService, snsScheme, userService, log),
+		RedactionHandler:      NewRedactionHandler(redactionService, userService, log),
+		S3WatcherHandler:      NewS3WatcherHandler(s3WatcherService, userService, log),
+		ReportHandler:         NewReportHandler(reportService, log),
+		WebhookHandler:        NewWebhookSubscriptionHandler(webhookService, log),
+		ProcessingHookHandler: NewProcessingHookHandler(processingEventHandler, log),
+		ClassificationHandler: NewClassificationHandler(classificationService, log),
+		ModerationHandler:     NewModerationHandler(moderationService, log),
+		ContentHandler:        NewDocumentContentHandler(documentContentService, log),
+		LegalHoldHandler:      NewLegalHoldHandler(legalHoldService, jobService, log),
+		LocaleHandler:         NewLocaleHandler(),
+		DocsHandler:           NewDocsHandler(),
+		GraphQLHandler:        graphQLHandler,
+		GRPC:                  grpcServer,
+		SpaceService:          spaceContextService,
+		RuntimeConfig:         runtimeConfigService,
+		Metrics:               metricsInstance,
+		logger:                log.WithService("api_server"),
+		backgroundCancel:      backgroundCancel,
+		streamService:         streamService,
+		accessLogs:            accessLogExporter,
+		workers:               workers,
+		drainer:               drainer,
+		dbProbe:               neo4j,
+		rateLimits:            runtimeStore,
+		rateLimitStore:        rateLimitStore,
+		maintenance:           maintenanceService,
+		region:                regionSource,
+		concurrency:           middleware.ConcurrencyLimit(cfg.Concurrency, concurrencyClass, concurrencyRecorder),
+		idempotency:           middleware.Idempotency(idempotencyStore, cfg.Idempotency, log),
+		webhooks:              webhookVerifiers,
+		versions:              versions,
 	}
 
 	// Setup routes
 	server.setupRoutes(keycloakClient)
+	for _, route := range router.Routes() {
+		versions.Register(route.Method, route.Path)
+	}
+
+	// Batch operations are dispatched through the handler serving live
+	// traffic, so versioned paths resolve as they do outside a batch
+	batchHandler.SetHandler(server.Handler())
 
 	return server
 }
@@ -173,17 +916,65 @@ func (s *APIServer) setupRoutes(keycloakClient *auth.KeycloakClient) {
 	s.Router.GET("/health", s.HealthHandler.HealthCheck)
 	s.Router.GET("/health/live", s.HealthHandler.LivenessCheck)
 	s.Router.GET("/health/ready", s.HealthHandler.ReadinessCheck)
+	s.Router.GET("/healthz", s.HealthHandler.LivenessCheck)
+	s.Router.GET("/readyz", s.HealthHandler.ReadinessCheck)
+
+	// Webhook routes (no user auth; deliveries are verified by signature)
+	webhookRoutes := s.Router.Group("/webhooks")
+	webhookRoutes.POST("/audimodal/processing-complete", middleware.VerifyWebhook(s.webhooks.audiModal, s.logger), s.DocumentHandler.AudiModalProcessingWebhook)
+	webhookRoutes.POST("/stripe", middleware.VerifyWebhook(s.webhooks.stripe, s.logger), s.OrganizationHandler.StripeBillingWebhook)
+	webhookRoutes.POST("/streams/:id", middleware.VerifyWebhook(s.webhooks.streams, s.logger), s.StreamHandler.StreamSourceWebhook)
+	webhookRoutes.POST("/email", middleware.VerifyWebhook(s.webhooks.email, s.logger), s.InboundEmailHandler.InboundEmailWebhook)
+	// Processing callbacks are versioned with the API, for senders outside
+	// this repository
+	s.Router.POST("/api/v1/webhooks/processing", middleware.VerifyWebhook(s.webhooks.processing, s.logger), s.ProcessingHookHandler.ProcessingCallback)
 
-	// Webhook routes (no auth required)
-	s.Router.POST("/webhooks/audimodal/processing-complete", s.DocumentHandler.AudiModalProcessingWebhook)
+	// Feed routes (no user auth; feed readers send a feed token)
+	feedRoutes := s.Router.Group("/feeds")
+	feedRoutes.GET("/notebooks/:id/rss", s.FeedHandler.NotebookActivityRSS)
+	feedRoutes.GET("/notebooks/:id/atom", s.FeedHandler.NotebookActivityAtom)
+	feedRoutes.GET("/jobs.ics", s.FeedHandler.ScheduledJobsCalendar)
+
+	// API documentation (no auth required)
+	s.Router.GET("/api/v1/openapi.json", s.DocsHandler.GetOpenAPISpec)
+	s.Router.GET("/api/v1/docs", s.DocsHandler.SwaggerUI)
 
 	// API routes with authentication
-	api := s.Router.Group("/api/v1")
-	api.Use(middleware.AuthMiddleware(keycloakClient, s.logger))
+	api := s.apiGroup("/api/v1", keycloakClient)
+
+	// Routes that submit documents for processing are limited per tenant
+	// by its billing plan
+	processing := middleware.ProcessingRateLimit()
 
 	// Logging routes - frontend logs sent to backend
 	api.POST("/logs", s.LoggingHandler.SubmitFrontendLogs)
 
+	// Feature flags - reloadable toggles read by clients
+	api.GET("/features", s.AdminHandler.GetFeatureFlags)
+
+	// Maintenance - the banner of maintenance in progress; names the
+	// tenant's maintenance too when the request names a space
+	api.GET("/maintenance", middleware.SpaceContextMiddleware(s.SpaceService, s.logger), s.AdminHandler.GetMaintenanceStatus)
+
+	// Localization - labels of enumerated values in the request's locale
+	api.GET("/i18n/enumerations", s.LocaleHandler.GetEnumerations)
+
+	// Unified WebSocket - subscribe to documents, notebooks, agents and
+	// streams events of any readable space over one connection
+	api.GET("/ws/events", s.WebSocketHandler.SubscribeEvents)
+
+	// Batch - several API requests in one call
+	api.POST("/batch", s.BatchHandler.Batch)
+
+	// Unified search - documents, notebooks, agents and events for the global search bar
+	search := api.Group("/search")
+	search.Use(middleware.SpaceContextMiddleware(s.SpaceService, s.logger))
+	search.Use(middleware.RequireSpaceContext(s.logger))
+	{
+		search.GET("", s.SearchHandler.Search)
+		search.GET("/suggest", s.SearchHandler.Suggest)
+	}
+
 	// User routes
 	users := api.Group("/users")
 	{
@@ -194,6 +985,9 @@ func (s *APIServer) setupRoutes(keycloakClient *auth.KeycloakClient) {
 		users.PUT("/me/preferences", s.UserHandler.UpdateUserPreferences)
 		users.GET("/me/stats", s.UserHandler.GetUserStats)
 		users.GET("/me/spaces", s.UserHandler.GetUserSpaces)
+		users.GET("/me/feed-tokens", s.FeedHandler.ListFeedTokens)
+		users.DELETE("/me/feed-tokens/:id", s.FeedHandler.RevokeFeedToken)
+		users.GET("/me/digests", s.NotebookDigestHandler.ListNotebookDigests)
 		users.GET("/me/onboarding", s.UserHandler.GetOnboardingStatus)
 		users.POST("/me/onboarding", s.UserHandler.MarkTutorialComplete)
 		users.DELETE("/me/onboarding", s.UserHandler.ResetTutorial)
@@ -209,13 +1003,40 @@ func (s *APIServer) setupRoutes(keycloakClient *auth.KeycloakClient) {
 		notebooks.POST("", s.NotebookHandler.CreateNotebook)
 		notebooks.GET("", s.NotebookHandler.ListNotebooks)
 		notebooks.GET("/search", s.NotebookHandler.SearchNotebooks)
+		notebooks.GET("/shared-with-me", s.NotebookHandler.ListSharedNotebooks)
 		notebooks.GET("/:id", s.NotebookHandler.GetNotebook)
 		notebooks.PUT("/:id", s.NotebookHandler.UpdateNotebook)
 		notebooks.DELETE("/:id", s.NotebookHandler.DeleteNotebook)
 		notebooks.POST("/:id/share", s.NotebookHandler.ShareNotebook)
+		notebooks.GET("/:id/shares", s.NotebookHandler.ListNotebookShares)
+		notebooks.DELETE("/:id/shares/:type/:share_id", s.NotebookHandler.UnshareNotebook)
+		notebooks.GET("/:id/graph", s.GraphHandler.GetNotebookGraph)
+		notebooks.GET("/:id/entities", s.EntityHandler.ListNotebookEntities)
+		notebooks.GET("/:id/redaction", s.RedactionHandler.GetRedactionRules)
+		notebooks.PUT("/:id/redaction", s.RedactionHandler.SetRedactionRules)
+		notebooks.DELETE("/:id/redaction", s.RedactionHandler.DeleteRedactionRules)
+		notebooks.POST("/:id/feed-tokens", s.FeedHandler.CreateNotebookFeedToken)
+		notebooks.PUT("/:id/digest", s.NotebookDigestHandler.SubscribeNotebookDigest)
+		notebooks.GET("/:id/digest", s.NotebookDigestHandler.GetNotebookDigest)
+		notebooks.DELETE("/:id/digest", s.NotebookDigestHandler.UnsubscribeNotebookDigest)
+		notebooks.POST("/:id/inbound-email", s.InboundEmailHandler.CreateInboundEmail)
+		notebooks.GET("/:id/inbound-email", s.InboundEmailHandler.GetInboundEmail)
+		notebooks.DELETE("/:id/inbound-email", s.InboundEmailHandler.DeleteInboundEmail)
+		notebooks.POST("/:id/s3-watchers", s.S3WatcherHandler.CreateS3Watcher)
+		notebooks.GET("/:id/s3-watchers", s.S3WatcherHandler.ListS3Watchers)
+		notebooks.GET("/:id/s3-watchers/:watcherId", s.S3WatcherHandler.GetS3Watcher)
+		notebooks.DELETE("/:id/s3-watchers/:watcherId", s.S3WatcherHandler.DeleteS3Watcher)
+		notebooks.POST("/:id/ask", s.NotebookQAHandler.AskNotebook)
+		notebooks.GET("/:id/summary", s.SummaryHandler.GetNotebookSummary)
+		notebooks.POST("/:id/summary/refresh", s.SummaryHandler.RefreshNotebookSummary)
 
 		// Documents within notebooks - use same parameter name to avoid conflict
 		notebooks.GET("/:id/documents", s.DocumentHandler.ListDocumentsByNotebook)
+		notebooks.POST("/:id/documents/bulk", processing, s.DocumentHandler.BulkUploadDocuments)
+		notebooks.POST("/:id/uploads", s.UploadSessionHandler.InitiateUpload)
+		notebooks.POST("/:id/upload-intents", s.DocumentHandler.CreateUploadIntent)
+		notebooks.GET("/:id/documents/export", s.DocumentHandler.ExportNotebookDocuments)
+		notebooks.POST("/:id/documents/export", s.DocumentHandler.StartNotebookExport)
 
 		// Vector search routes for RAG-only lookup
 		notebooks.POST("/:id/vector-search/text", s.VectorSearchHandler.TextSearch)
@@ -229,20 +1050,38 @@ func (s *APIServer) setupRoutes(keycloakClient *auth.KeycloakClient) {
 	documents.Use(middleware.RequireSpaceContext(s.logger))
 	{
 		documents.POST("", s.DocumentHandler.CreateDocument)
-		documents.POST("/upload", s.DocumentHandler.UploadDocument)
-		documents.POST("/upload-base64", s.DocumentHandler.UploadDocumentBase64)
+		documents.POST("/upload", processing, s.DocumentHandler.UploadDocument)
+		documents.POST("/upload-base64", processing, s.DocumentHandler.UploadDocumentBase64)
 		documents.GET("/search", s.DocumentHandler.SearchDocuments)
+		documents.POST("/compare", s.DocumentHandler.CompareDocuments)
 		documents.GET("/:id", s.DocumentHandler.GetDocument)
 		documents.GET("/:id/status", s.DocumentHandler.GetDocumentStatus)
+		documents.GET("/:id/pipeline", s.DocumentHandler.GetDocumentPipeline)
+		documents.GET("/:id/vector-sync", s.DocumentHandler.GetDocumentVectorSync)
+		documents.POST("/:id/vector-sync", s.DocumentHandler.ResyncDocumentVectors)
+		documents.GET("/:id/graph", s.DocumentHandler.GetDocumentGraph)
+		documents.GET("/:id/similar", s.DocumentHandler.GetSimilarDocuments)
+		documents.GET("/:id/related", s.DocumentHandler.GetRelatedDocuments)
+		documents.GET("/:id/entities", s.EntityHandler.ListDocumentEntities)
+		documents.GET("/:id/summary", s.SummaryHandler.GetDocumentSummary)
+		documents.POST("/:id/summary/refresh", s.SummaryHandler.RefreshDocumentSummary)
+		documents.DELETE("/:id/restriction", s.ClassificationHandler.LiftDocumentRestriction)
+		documents.POST("/:id/confirm-upload", processing, s.DocumentHandler.ConfirmUpload)
+		documents.GET("/:id/versions", s.DocumentHandler.ListDocumentVersions)
+		documents.POST("/:id/versions", processing, s.DocumentHandler.UploadDocumentVersion)
+		documents.GET("/:id/versions/:version/download", s.DocumentHandler.DownloadDocumentVersion)
+		documents.POST("/:id/versions/:version/restore", s.DocumentHandler.RestoreDocumentVersion)
 		documents.GET("/:id/stream", s.WebSocketHandler.StreamDocumentStatus)
 		documents.PUT("/:id", s.DocumentHandler.UpdateDocument)
 		documents.DELETE("/:id", s.DocumentHandler.DeleteDocument)
-		documents.POST("/:id/reprocess", s.DocumentHandler.ReprocessDocument)
+		documents.POST("/:id/reprocess", processing, s.DocumentHandler.ReprocessDocument)
 		documents.POST("/refresh-processing", s.DocumentHandler.RefreshProcessingResults)
 		documents.GET("/:id/download", s.DocumentHandler.DownloadDocument)
 		documents.GET("/:id/url", s.DocumentHandler.GetDocumentURL)
 		documents.GET("/:id/analysis", s.DocumentHandler.GetDocumentAnalysis)
 		documents.GET("/:id/text", s.DocumentHandler.GetDocumentExtractedText)
+		documents.GET("/:id/content", s.ContentHandler.GetDocumentContent)
+		documents.GET("/:id/chunks", s.ContentHandler.GetDocumentChunks)
 	}
 
 	// Chunk routes - file-specific chunks
@@ -252,7 +1091,7 @@ func (s *APIServer) setupRoutes(keycloakClient *auth.KeycloakClient) {
 	{
 		files.GET("/:file_id/chunks", s.ChunkHandler.GetFileChunks)
 		files.GET("/:file_id/chunks/:chunk_id", s.ChunkHandler.GetChunk)
-		files.POST("/:file_id/reprocess", s.ChunkHandler.ReprocessFileWithStrategy)
+		files.POST("/:file_id/reprocess", processing, s.ChunkHandler.ReprocessFileWithStrategy)
 	}
 
 	// Chunk search routes
@@ -275,10 +1114,122 @@ func (s *APIServer) setupRoutes(keycloakClient *auth.KeycloakClient) {
 	jobs.Use(middleware.SpaceContextMiddleware(s.SpaceService, s.logger))
 	jobs.Use(middleware.RequireSpaceContext(s.logger))
 	{
+		jobs.GET("", s.JobHandler.ListJobs)
 		jobs.GET("/:id", s.JobHandler.GetJobStatus)
 		jobs.GET("/:id/stream", s.WebSocketHandler.StreamJobStatus)
 	}
 
+	entities := api.Group("/entities")
+	entities.Use(middleware.SpaceContextMiddleware(s.SpaceService, s.logger))
+	entities.Use(middleware.RequireSpaceContext(s.logger))
+	{
+		entities.GET("", s.EntityHandler.ListEntities)
+		entities.GET("/:id", s.EntityHandler.GetEntity)
+		entities.GET("/:id/documents", s.EntityHandler.ListEntityDocuments)
+		entities.GET("/:id/related", s.EntityHandler.ListRelatedEntities)
+		entities.POST("/:id/merge", s.EntityHandler.MergeEntity)
+	}
+
+	uploads := api.Group("/uploads")
+	uploads.Use(middleware.SpaceContextMiddleware(s.SpaceService, s.logger))
+	uploads.Use(middleware.RequireSpaceContext(s.logger))
+	{
+		uploads.GET("/:id", s.UploadSessionHandler.GetUpload)
+		uploads.PUT("/:id/parts/:number", s.UploadSessionHandler.UploadPart)
+		uploads.POST("/:id/complete", processing, s.UploadSessionHandler.CompleteUpload)
+		uploads.DELETE("/:id", s.UploadSessionHandler.AbortUpload)
+	}
+
+	trash := api.Group("/trash")
+	trash.Use(middleware.SpaceContextMiddleware(s.SpaceService, s.logger))
+	trash.Use(middleware.RequireSpaceContext(s.logger))
+	{
+		trash.GET("", s.TrashHandler.ListTrash)
+		trash.POST("/:type/:id/restore", s.TrashHandler.RestoreTrashItem)
+		trash.DELETE("/:type/:id", s.TrashHandler.PurgeTrashItem)
+	}
+
+	s3Watchers := api.Group("/s3-watchers")
+	s3Watchers.Use(middleware.SpaceContextMiddleware(s.SpaceService, s.logger))
+	s3Watchers.Use(middleware.RequireSpaceContext(s.logger))
+	{
+		s3Watchers.GET("/setup", s.S3WatcherHandler.GetS3WatchSetup)
+	}
+
+	reports := api.Group("/reports")
+	reports.Use(middleware.SpaceContextMiddleware(s.SpaceService, s.logger))
+	reports.Use(middleware.RequireSpaceContext(s.logger))
+	{
+		reports.POST("/schedules", s.ReportHandler.CreateReportSchedule)
+		reports.GET("/schedules", s.ReportHandler.ListReportSchedules)
+		reports.GET("/schedules/:id", s.ReportHandler.GetReportSchedule)
+		reports.DELETE("/schedules/:id", s.ReportHandler.DeleteReportSchedule)
+		reports.POST("/schedules/:id/run", s.ReportHandler.RunReportSchedule)
+		reports.GET("/schedules/:id/runs", s.ReportHandler.ListReportRuns)
+		reports.GET("/runs/:id/download", s.ReportHandler.DownloadReport)
+	}
+
+	webhookSubscriptions := api.Group("/webhook-subscriptions")
+	webhookSubscriptions.Use(middleware.SpaceContextMiddleware(s.SpaceService, s.logger))
+	webhookSubscriptions.Use(middleware.RequireSpaceContext(s.logger))
+	{
+		webhookSubscriptions.POST("", s.WebhookHandler.CreateWebhookSubscription)
+		webhookSubscriptions.GET("", s.WebhookHandler.ListWebhookSubscriptions)
+		webhookSubscriptions.GET("/:id", s.WebhookHandler.GetWebhookSubscription)
+		webhookSubscriptions.PUT("/:id", s.WebhookHandler.UpdateWebhookSubscription)
+		webhookSubscriptions.DELETE("/:id", s.WebhookHandler.DeleteWebhookSubscription)
+		webhookSubscriptions.GET("/:id/deliveries", s.WebhookHandler.ListWebhookDeliveries)
+		webhookSubscriptions.POST("/:id/test", s.WebhookHandler.TestWebhookSubscription)
+	}
+
+	imports := api.Group("/imports")
+	imports.Use(middleware.SpaceContextMiddleware(s.SpaceService, s.logger))
+	imports.Use(middleware.RequireSpaceContext(s.logger))
+	{
+		imports.POST("", processing, s.ImportHandler.StartImport)
+	}
+
+	summaries := api.Group("/summaries")
+	summaries.Use(middleware.SpaceContextMiddleware(s.SpaceService, s.logger))
+	summaries.Use(middleware.RequireSpaceContext(s.logger))
+	{
+		summaries.GET("/usage", s.SummaryHandler.GetSummaryUsage)
+	}
+
+	classification := api.Group("/classification-policies")
+	classification.Use(middleware.SpaceContextMiddleware(s.SpaceService, s.logger))
+	classification.Use(middleware.RequireSpaceContext(s.logger))
+	{
+		classification.POST("", s.ClassificationHandler.CreateClassificationPolicy)
+		classification.GET("", s.ClassificationHandler.ListClassificationPolicies)
+		classification.POST("/evaluate", s.ClassificationHandler.EvaluateClassificationPolicies)
+		classification.GET("/:id", s.ClassificationHandler.GetClassificationPolicy)
+		classification.PUT("/:id", s.ClassificationHandler.UpdateClassificationPolicy)
+		classification.DELETE("/:id", s.ClassificationHandler.DeleteClassificationPolicy)
+	}
+
+	moderation := api.Group("/moderation")
+	moderation.Use(middleware.SpaceContextMiddleware(s.SpaceService, s.logger))
+	moderation.Use(middleware.RequireSpaceContext(s.logger))
+	{
+		moderation.GET("/documents", s.ModerationHandler.ListModeratedDocuments)
+		moderation.POST("/documents/:id/review", s.ModerationHandler.ReviewModeratedDocument)
+	}
+
+	graph := api.Group("/graph")
+	graph.Use(middleware.SpaceContextMiddleware(s.SpaceService, s.logger))
+	graph.Use(middleware.RequireSpaceContext(s.logger))
+	{
+		graph.GET("", s.GraphHandler.GetKnowledgeGraph)
+	}
+
+	processingJobs := api.Group("/processing-jobs")
+	processingJobs.Use(middleware.SpaceContextMiddleware(s.SpaceService, s.logger))
+	processingJobs.Use(middleware.RequireSpaceContext(s.logger))
+	{
+		processingJobs.GET("", s.JobHandler.ListProcessingJobs)
+	}
+
 	// Team routes
 	teams := api.Group("/teams")
 	{
@@ -309,6 +1260,16 @@ func (s *APIServer) setupRoutes(keycloakClient *auth.KeycloakClient) {
 		organizations.POST("/:id/members", s.OrganizationHandler.InviteOrganizationMember)
 		organizations.PUT("/:id/members/:user_id", s.OrganizationHandler.UpdateOrganizationMemberRole)
 		organizations.DELETE("/:id/members/:user_id", s.OrganizationHandler.RemoveOrganizationMember)
+
+		// Custom roles of the organization
+		organizations.GET("/:id/roles", s.OrganizationHandler.ListOrganizationRoles)
+		organizations.POST("/:id/roles", s.OrganizationHandler.CreateOrganizationRole)
+		organizations.PUT("/:id/roles/:role", s.OrganizationHandler.UpdateOrganizationRole)
+		organizations.DELETE("/:id/roles/:role", s.OrganizationHandler.DeleteOrganizationRole)
+
+		// Audit log of the organization and its spaces
+		organizations.GET("/:id/audit", s.AuditHandler.ListOrganizationAuditEvents)
+		organizations.GET("/:id/audit/export", s.AuditHandler.ExportOrganizationAuditEvents)
 	}
 
 	// Agent routes - with space context for multi-tenancy
@@ -361,6 +1322,16 @@ func (s *APIServer) setupRoutes(keycloakClient *auth.KeycloakClient) {
 		spaces.POST("/:id/members", s.SpaceHandler.AddSpaceMember)
 		spaces.PATCH("/:id/members/:userId", s.SpaceHandler.UpdateSpaceMember)
 		spaces.DELETE("/:id/members/:userId", s.SpaceHandler.RemoveSpaceMember)
+
+		// Audit log
+		spaces.GET("/:id/audit", s.AuditHandler.ListSpaceAuditEvents)
+		spaces.GET("/:id/audit/export", s.AuditHandler.ExportSpaceAuditEvents)
+
+		// Use of the space's quotas
+		spaces.GET("/:id/usage", s.SpaceHandler.GetSpaceUsage)
+
+		// Semantic search over the space's indexed chunks
+		spaces.POST("/:id/search/semantic", s.VectorSearchHandler.SemanticSearch)
 	}
 
 	// ML/Analytics routes
@@ -406,6 +1377,14 @@ func (s *APIServer) setupRoutes(keycloakClient *auth.KeycloakClient) {
 		workflows.GET("/:id/executions", s.WorkflowHandler.GetWorkflowExecutions)
 	}
 
+	// GraphQL - the space context is optional, as spaces can be queried
+	// without one
+	graphql := api.Group("/graphql")
+	graphql.Use(middleware.SpaceContextMiddleware(s.SpaceService, s.logger))
+	{
+		graphql.POST("", s.GraphQLHandler.Query)
+	}
+
 	// Live streaming routes
 	streams := api.Group("/streams")
 	streams.Use(middleware.SpaceContextMiddleware(s.SpaceService, s.logger))
@@ -461,10 +1440,66 @@ func (s *APIServer) setupRoutes(keycloakClient *auth.KeycloakClient) {
 	admin := api.Group("/admin")
 	admin.Use(middleware.RequireRole("admin"))
 	{
+		admin.GET("/config", s.AdminHandler.GetRuntimeConfig)
+		admin.PATCH("/config", s.AdminHandler.UpdateRuntimeConfig)
+		admin.POST("/config/reload", s.AdminHandler.ReloadRuntimeConfig)
+		admin.GET("/jobs", s.AdminHandler.ListScheduledJobs)
+		admin.GET("/jobs/:name/runs", s.AdminHandler.ListJobRuns)
+		admin.POST("/jobs/feed-tokens", s.FeedHandler.CreateScheduledJobsFeedToken)
+		admin.GET("/reporting", s.AdminHandler.GetReportingStatus)
+		admin.POST("/reporting/rebuild", s.AdminHandler.RebuildReporting)
+		admin.GET("/reporting/usage", s.AdminHandler.GetUsageReport)
+		admin.GET("/slo", s.AdminHandler.GetSLOReport)
+		admin.GET("/anomalies", s.AdminHandler.GetUsageAnomalies)
+		admin.GET("/synthetic", s.AdminHandler.GetSyntheticReport)
+		admin.GET("/consistency", s.AdminHandler.CheckConsistency)
+		admin.POST("/consistency/repair", s.AdminHandler.RepairConsistency)
+		admin.POST("/orphans/cleanup", s.AdminHandler.CleanupOrphans)
+		admin.GET("/dead-letters", s.AdminHandler.ListDeadLetters)
+		admin.POST("/dead-letters/:id/requeue", s.AdminHandler.RequeueDeadLetter)
+		admin.GET("/deletions", s.AdminHandler.ListDocumentDeletions)
+		admin.POST("/deletions/:id/retry", s.AdminHandler.RetryDocumentDeletion)
+		admin.POST("/reprocess", s.AdminHandler.StartReprocessing)
+		admin.POST("/tenants", s.AdminHandler.ProvisionTenant)
+		admin.POST("/tenants/:tenant_id/export", s.AdminHandler.StartTenantExport)
+		admin.GET("/pipeline", s.DocumentHandler.GetPipelineSummary)
+		admin.GET("/audit", s.AuditHandler.ListAuditEvents)
+		admin.GET("/audit/export", s.AuditHandler.ExportAuditEvents)
+		admin.GET("/audit/verify", s.AuditHandler.VerifyAuditLog)
+		admin.GET("/maintenance", s.AdminHandler.ListMaintenance)
+		admin.PUT("/maintenance", s.AdminHandler.StartMaintenance)
+		admin.DELETE("/maintenance", s.AdminHandler.EndMaintenance)
+		admin.PUT("/maintenance/tenants/:tenant_id", s.AdminHandler.StartTenantMaintenance)
+		admin.DELETE("/maintenance/tenants/:tenant_id", s.AdminHandler.EndTenantMaintenance)
+		admin.PUT("/spaces/:space_id/quotas", s.AdminHandler.UpdateSpaceQuotas)
+		admin.PUT("/spaces/:space_id/encryption", s.AdminHandler.UpdateSpaceEncryption)
+		admin.GET("/regions", s.AdminHandler.ListRegions)
+		admin.GET("/tenants/:tenant_id/region", s.AdminHandler.GetTenantRegion)
+		admin.PUT("/tenants/:tenant_id/region", s.AdminHandler.PinTenantRegion)
+		admin.POST("/tenants/:tenant_id/failover", s.AdminHandler.FailoverTenant)
+		admin.POST("/legal-holds", s.LegalHoldHandler.CreateLegalHold)
+		admin.GET("/legal-holds", s.LegalHoldHandler.ListLegalHolds)
+		admin.GET("/legal-holds/:hold_id", s.LegalHoldHandler.GetLegalHold)
+		admin.POST("/legal-holds/:hold_id/release", s.LegalHoldHandler.ReleaseLegalHold)
+		admin.POST("/legal-holds/:hold_id/collect", s.LegalHoldHandler.CollectLegalHold)
+		admin.POST("/legal-holds/:hold_id/export", s.LegalHoldHandler.StartLegalHoldExport)
+
+		// System status - per-dependency health for operators
+		admin.GET("/system/status", s.HealthHandler.SystemStatus)
+
 		// TODO: Add admin-specific routes
 		// admin.GET("/users", s.UserHandler.ListAllUsers)
 		// admin.GET("/stats", s.AdminHandler.GetSystemStats)
-		// admin.POST("/maintenance", s.AdminHandler.MaintenanceMode)
+	}
+
+	// v2 registers only the endpoints whose contract changed; the rest of
+	// /api/v2 is served by the v1 handlers
+	v2 := s.apiGroup("/api/v2", keycloakClient)
+	v2Notebooks := v2.Group("/notebooks")
+	v2Notebooks.Use(middleware.SpaceContextMiddleware(s.SpaceService, s.logger))
+	v2Notebooks.Use(middleware.RequireSpaceContext(s.logger))
+	{
+		v2Notebooks.GET("", s.NotebookHandler.ListNotebooksV2)
 	}
 
 	// Metrics and monitoring routes (can be separate from main API)
@@ -477,20 +1512,82 @@ func (s *APIServer) setupRoutes(keycloakClient *auth.KeycloakClient) {
 	}
 }
 
+// bodyLimit caps request bodies at the limits configured per route
+func bodyLimit(cfg *config.Config, log *logger.Logger) gin.HandlerFunc {
+	bodyLimits, err := cfg.BodyLimits.RouteLimits()
+	if err != nil {
+		// Validate rejects bad overrides at startup; keep the upload defaults regardless
+		log.WithError(err).Error("Invalid body limit overrides, ignoring them")
+		bodyLimits, _ = (config.BodyLimitConfig{UploadBytes: cfg.BodyLimits.UploadBytes, BulkUploadBytes: cfg.BodyLimits.BulkUploadBytes, ImportBytes: cfg.BodyLimits.ImportBytes}).RouteLimits()
+	}
+	return middleware.BodyLimit(log, cfg.BodyLimits.DefaultBytes, bodyLimits)
+}
+
+// apiGroup creates the authenticated route group of an API version.
+// Requests are shed before auth while the database pool is saturated;
+// health routes stay reachable.
+func (s *APIServer) apiGroup(prefix string, keycloakClient *auth.KeycloakClient) *gin.RouterGroup {
+	group := s.Router.Group(prefix)
+	group.Use(middleware.Region(s.region))
+	group.Use(middleware.LoadShedding(s.dbProbe))
+	group.Use(s.concurrency)
+	group.Use(middleware.AuthMiddleware(keycloakClient, s.logger))
+	group.Use(middleware.RateLimit(s.rateLimits, s.rateLimitStore, rateLimitClass, s.logger))
+	group.Use(middleware.Maintenance(s.maintenance, maintenanceExemptRoutes))
+	group.Use(s.idempotency)
+	group.Use(middleware.IncludeDeleted())
+	return group
+}
+
+// Handler returns the server's HTTP handler, which negotiates the API
+// version of each request before routing it
+func (s *APIServer) Handler() http.Handler {
+	return s.versions.Handler(s.Router)
+}
+
 // Start starts the HTTP server
 func (s *APIServer) Start(addr string) error {
 	s.logger.Info("Starting API server")
-	return s.Router.Run(addr)
+	return http.ListenAndServe(addr, s.Handler())
 }
 
-// Shutdown gracefully shuts down the server
-func (s *APIServer) Shutdown() error {
+// BeginDrain stops accepting new WebSocket connections and closes open ones.
+// Call it before shutting down the HTTP server, which does not track them.
+func (s *APIServer) BeginDrain() {
+	s.logger.Info("Draining API server connections")
+	s.drainer.Start()
+}
+
+// Shutdown gracefully shuts down the server. Queued stream events are stored
+// and running background workers are waited for until ctx is done.
+func (s *APIServer) Shutdown(ctx context.Context) error {
 	s.logger.Info("Shutting down API server")
-	// TODO: Implement graceful shutdown
-	// This would typically involve:
-	// 1. Stop accepting new requests
-	// 2. Wait for existing requests to complete
-	// 3. Close database connections
-	// 4. Close other resources
-	return nil
+	s.drainer.Start()
+
+	// Cancel background goroutines that have not started work (waiting retries)
+	if s.backgroundCancel != nil {
+		s.backgroundCancel()
+	}
+
+	var shutdownErr error
+	if s.streamService != nil {
+		if err := s.streamService.Shutdown(ctx); err != nil {
+			shutdownErr = fmt.Errorf("stream service: %w", err)
+		}
+	}
+
+	if s.accessLogs != nil {
+		if err := s.accessLogs.Shutdown(ctx); err != nil && shutdownErr == nil {
+			shutdownErr = fmt.Errorf("access log export: %w", err)
+		}
+	}
+
+	if err := s.workers.Wait(ctx); err != nil {
+		s.logger.Warn("Background workers still running at shutdown deadline")
+		if shutdownErr == nil {
+			shutdownErr = fmt.Errorf("background workers: %w", err)
+		}
+	}
+
+	return shutdownErr
 }
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+)
+
+func TestBodyLimitDocumentVersions(t *testing.T) {
+	t.Setenv("NEO4J_PASSWORD", "secret")
+	t.Setenv("KEYCLOAK_CLIENT_SECRET", "secret")
+	t.Setenv("MAX_UPLOAD_BYTES", strconv.Itoa(12<<20))
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	require.Less(t, cfg.BodyLimits.DefaultBytes, int64(11<<20), "a version must not fit the default limit")
+
+	gin.SetMode(gin.TestMode)
+	log, err := logger.New(logger.Config{Level: "error", Format: "json"})
+	require.NoError(t, err)
+
+	// The global middleware that reads bodies, in the order NewAPIServer
+	// installs it, in front of the route as setupRoutes registers it
+	router := gin.New()
+	router.Use(middleware.ErrorHandler(log))
+	router.Use(bodyLimit(cfg, log))
+	router.Use(middleware.ValidationMiddleware(log))
+	documents := router.Group("/api/v1").Group("/documents")
+	documents.POST("/:id/versions", func(c *gin.Context) {
+		form, err := readUploadForm(c.Request, cfg.BodyLimits.UploadBytes)
+		if err != nil {
+			middleware.WriteError(c, log, err)
+			return
+		}
+		c.String(http.StatusOK, "%d", len(form.fileData))
+	})
+
+	send := func(size int) *httptest.ResponseRecorder {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile("file", "report.pdf")
+		require.NoError(t, err)
+		_, err = part.Write(bytes.Repeat([]byte("a"), size))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/documents/doc-1/versions", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(11 << 20)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, strconv.Itoa(11<<20), w.Body.String())
+
+	// Versions are still limited to the upload limit
+	assert.Equal(t, http.StatusRequestEntityTooLarge, send(14<<20).Code)
+}
+
+func TestDocumentVersionUploadClasses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	var concurrency, rateLimit string
+	router.POST("/api/v1/documents/:id/versions", func(c *gin.Context) {
+		concurrency, rateLimit = concurrencyClass(c), rateLimitClass(c)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/v1/documents/doc-1/versions", nil))
+	assert.Equal(t, config.ConcurrencyBulk, concurrency)
+	assert.Equal(t, config.RateLimitUpload, rateLimit)
+}
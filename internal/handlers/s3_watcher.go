@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// S3WatcherHandler serves the S3 watchers of notebooks
+type S3WatcherHandler struct {
+	watcherService *services.S3WatcherService
+	userService    *services.UserService
+	logger         *logger.Logger
+}
+
+// NewS3WatcherHandler creates a new S3 watcher handler
+func NewS3WatcherHandler(watcherService *services.S3WatcherService, userService *services.UserService, log *logger.Logger) *S3WatcherHandler {
+	return &S3WatcherHandler{
+		watcherService: watcherService,
+		userService:    userService,
+		logger:         log.WithService("s3_watcher_handler"),
+	}
+}
+
+// GetS3WatchSetup returns what a role needs to trust for watchers to
+// assume it
+// @Summary Get S3 watcher setup
+// @Description Get the principal watchers assume customer roles as and the external ID of the space's tenant. A role given to a watcher must trust the principal on the condition sts:ExternalId equals the external ID, and allow s3:ListBucket and s3:GetObject on the watched prefix, plus sqs:ReceiveMessage and sqs:DeleteMessage on the queue when one is given. Fails with 503 and AETHER-WATCH-001 when S3 watchers are not enabled.
+// @Tags notebooks
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.S3WatchSetup
+// @Failure 401 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/s3-watchers/setup [get]
+func (h *S3WatcherHandler) GetS3WatchSetup(c *gin.Context) {
+	_, spaceContext, ok := requireInternalUserAndSpace(c, h.userService, h.logger)
+	if !ok {
+		return
+	}
+
+	setup, err := h.watcherService.Setup(spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, setup)
+}
+
+// CreateS3Watcher starts watching an S3 prefix for a notebook
+// @Summary Create notebook S3 watcher
+// @Description Watch an S3 prefix and add its new objects to the notebook as documents owned by the notebook's owner. With queue_url the bucket's ObjectCreated notifications are read from the SQS queue, directly or through SNS; without it the prefix is listed for objects modified since the last run. Only objects created after the watcher are added. include and exclude are globs matched against keys relative to the prefix, where "**" spans path segments and a pattern without "/" matches the file name; excludes win. An object already added with the same ETag is not added again. Only the notebook's owner manages its watchers, at most 10.
+// @Tags notebooks
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Notebook ID"
+// @Param request body models.S3WatcherCreateRequest true "Watched bucket and prefix"
+// @Success 201 {object} models.S3Watcher
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/notebooks/{id}/s3-watchers [post]
+func (h *S3WatcherHandler) CreateS3Watcher(c *gin.Context) {
+	userID, spaceContext, ok := requireInternalUserAndSpace(c, h.userService, h.logger)
+	if !ok {
+		return
+	}
+
+	var req models.S3WatcherCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Invalid request payload", err))
+		return
+	}
+	if err := validateStruct(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Validation failed", err))
+		return
+	}
+
+	watcher, err := h.watcherService.CreateWatcher(c.Request.Context(), c.Param("id"), req, userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, watcher)
+}
+
+// ListS3Watchers lists the S3 watchers of a notebook
+// @Summary List notebook S3 watchers
+// @Description List the S3 watchers of a notebook with the outcome of their last run.
+// @Tags notebooks
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Notebook ID"
+// @Success 200 {object} models.S3WatcherList
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/notebooks/{id}/s3-watchers [get]
+func (h *S3WatcherHandler) ListS3Watchers(c *gin.Context) {
+	userID, spaceContext, ok := requireInternalUserAndSpace(c, h.userService, h.logger)
+	if !ok {
+		return
+	}
+
+	list, err := h.watcherService.ListWatchers(c.Request.Context(), c.Param("id"), userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// GetS3Watcher returns an S3 watcher of a notebook
+// @Summary Get notebook S3 watcher
+// @Description Get an S3 watcher of a notebook. Fails with 404 and AETHER-WATCH-002 when the notebook has no such watcher.
+// @Tags notebooks
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Notebook ID"
+// @Param watcherId path string true "Watcher ID"
+// @Success 200 {object} models.S3Watcher
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/notebooks/{id}/s3-watchers/{watcherId} [get]
+func (h *S3WatcherHandler) GetS3Watcher(c *gin.Context) {
+	userID, spaceContext, ok := requireInternalUserAndSpace(c, h.userService, h.logger)
+	if !ok {
+		return
+	}
+
+	watcher, err := h.watcherService.GetWatcher(c.Request.Context(), c.Param("id"), c.Param("watcherId"), userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, watcher)
+}
+
+// DeleteS3Watcher stops watching an S3 prefix
+// @Summary Delete notebook S3 watcher
+// @Description Stop watching an S3 prefix. Documents the watcher added stay in the notebook.
+// @Tags notebooks
+// @Security Bearer
+// @Param id path string true "Notebook ID"
+// @Param watcherId path string true "Watcher ID"
+// @Success 204
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/notebooks/{id}/s3-watchers/{watcherId} [delete]
+func (h *S3WatcherHandler) DeleteS3Watcher(c *gin.Context) {
+	userID, spaceContext, ok := requireInternalUserAndSpace(c, h.userService, h.logger)
+	if !ok {
+		return
+	}
+
+	if err := h.watcherService.DeleteWatcher(c.Request.Context(), c.Param("id"), c.Param("watcherId"), userID, spaceContext); err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
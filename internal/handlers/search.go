@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// SearchHandler serves the unified search behind the global search bar
+type SearchHandler struct {
+	searchService  *services.SearchService
+	suggestService *services.SuggestService
+	userService    *services.UserService
+	logger         *logger.Logger
+}
+
+// NewSearchHandler creates a new search handler
+func NewSearchHandler(searchService *services.SearchService, suggestService *services.SuggestService, userService *services.UserService, log *logger.Logger) *SearchHandler {
+	return &SearchHandler{
+		searchService:  searchService,
+		suggestService: suggestService,
+		userService:    userService,
+		logger:         log.WithService("search_handler"),
+	}
+}
+
+// Search searches documents, notebooks, agents and live events at once
+// @Summary Unified search
+// @Description Search documents, notebooks and agents of the current space and the tenant's live stream events at once. Hits are ranked together in results and grouped by type in groups, best group first. Matching is case-insensitive on names, descriptions, tags and event content.
+// @Tags search
+// @Produce json
+// @Security Bearer
+// @Param q query string true "Search text (2-100 characters)"
+// @Param types query []string false "Types to search: document, notebook, agent, event (comma-separated or repeated); all when omitted"
+// @Param limit query int false "Hits per type (max 20)" default(5)
+// @Success 200 {object} models.UnifiedSearchResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 500 {object} errors.APIError
+// @Router /api/v1/search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	userID, err := ensureUserExists(c, h.userService, h.logger)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	req := models.UnifiedSearchRequest{Query: strings.TrimSpace(c.Query("q"))}
+	for _, value := range c.QueryArray("types") {
+		for _, searchType := range strings.Split(value, ",") {
+			if searchType = strings.TrimSpace(searchType); searchType != "" {
+				req.Types = append(req.Types, models.SearchType(searchType))
+			}
+		}
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			middleware.WriteError(c, h.logger, errors.BadRequest("limit must be a number"))
+			return
+		}
+		req.Limit = limit
+	}
+	if err := validateStruct(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Validation failed", err))
+		return
+	}
+
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return
+	}
+
+	response, err := h.searchService.Search(c.Request.Context(), req, userID, spaceContext)
+	if err != nil {
+		h.logger.Error("Unified search failed", zap.Error(err))
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Suggest completes what the user is typing in the search bar
+// @Summary Search suggestions
+// @Description Typeahead completions for the search bar: names of the current space's documents and notebooks and tags of its documents that start with q, or have a word that does. Matching is case-insensitive. Suggestions are ordered alphabetically by the text they matched.
+// @Tags search
+// @Produce json
+// @Security Bearer
+// @Param q query string true "Text typed so far (1-100 characters)"
+// @Param limit query int false "Maximum suggestions (max 20)" default(8)
+// @Success 200 {object} models.SearchSuggestResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 500 {object} errors.APIError
+// @Failure 502 {object} errors.APIError
+// @Router /api/v1/search/suggest [get]
+func (h *SearchHandler) Suggest(c *gin.Context) {
+	if _, err := ensureUserExists(c, h.userService, h.logger); err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	req := models.SearchSuggestRequest{Query: strings.TrimSpace(c.Query("q"))}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			middleware.WriteError(c, h.logger, errors.BadRequest("limit must be a number"))
+			return
+		}
+		req.Limit = limit
+	}
+	if err := validateStruct(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Validation failed", err))
+		return
+	}
+
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return
+	}
+
+	response, err := h.suggestService.Suggest(c.Request.Context(), req, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
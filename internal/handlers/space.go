@@ -8,6 +8,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
 	"github.com/Tributary-ai-services/aether-be/internal/models"
 	"github.com/Tributary-ai-services/aether-be/internal/services"
 	"github.com/Tributary-ai-services/aether-be/pkg/errors"
@@ -19,6 +20,7 @@ type SpaceHandler struct {
 	spaceService        *services.SpaceService        // CRUD and member management
 	userService         *services.UserService
 	organizationService *services.OrganizationService
+	quotas              *services.QuotaService
 	logger              *logger.Logger
 }
 
@@ -39,6 +41,11 @@ func NewSpaceHandler(
 	}
 }
 
+// SetQuotaService enables the usage endpoint; without it it responds 503
+func (h *SpaceHandler) SetQuotaService(quotas *services.QuotaService) {
+	h.quotas = quotas
+}
+
 // CreateSpace creates a new space
 // @Summary Create space
 // @Description Create a new space (organization space)
@@ -57,7 +64,7 @@ func (h *SpaceHandler) CreateSpace(c *gin.Context) {
 	userID, err := ensureUserExists(c, h.userService, h.logger)
 	if err != nil {
 		h.logger.Error("Failed to resolve user", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -89,7 +96,7 @@ func (h *SpaceHandler) CreateSpace(c *gin.Context) {
 			zap.String("user_id", userID),
 			zap.String("org_id", req.OrganizationID),
 			zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -100,11 +107,8 @@ func (h *SpaceHandler) CreateSpace(c *gin.Context) {
 		return
 	}
 
-	if role != "owner" && role != "admin" {
-		c.JSON(http.StatusForbidden, errors.ForbiddenWithDetails("Only organization owners and admins can create spaces", map[string]interface{}{
-			"your_role":       role,
-			"organization_id": req.OrganizationID,
-		}))
+	if err := h.organizationService.AuthorizeRole(c.Request.Context(), req.OrganizationID, userID, role, models.ActionSpaceCreate); err != nil {
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -121,7 +125,7 @@ func (h *SpaceHandler) CreateSpace(c *gin.Context) {
 			zap.String("user_id", userID),
 			zap.String("org_id", req.OrganizationID),
 			zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -153,7 +157,7 @@ func (h *SpaceHandler) GetSpaces(c *gin.Context) {
 	userID, err := ensureUserExists(c, h.userService, h.logger)
 	if err != nil {
 		h.logger.Error("Failed to resolve user", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -161,7 +165,7 @@ func (h *SpaceHandler) GetSpaces(c *gin.Context) {
 	spaces, err := h.spaceService.GetUserSpaces(c.Request.Context(), userID)
 	if err != nil {
 		h.logger.Error("Failed to get user spaces", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -212,7 +216,7 @@ func (h *SpaceHandler) GetSpace(c *gin.Context) {
 	userID, err := ensureUserExists(c, h.userService, h.logger)
 	if err != nil {
 		h.logger.Error("Failed to resolve user", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -220,13 +224,13 @@ func (h *SpaceHandler) GetSpace(c *gin.Context) {
 	role, err := h.spaceService.GetUserRoleInSpace(c.Request.Context(), spaceID, userID)
 	if err != nil {
 		h.logger.Error("Failed to check user role", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 	if role == "" {
 		c.JSON(http.StatusForbidden, errors.ForbiddenWithDetails("You do not have access to this space", map[string]interface{}{
 			"space_id": spaceID,
-		}))
+		}).WithErrorCode(errors.CodeSpaceAccessDenied))
 		return
 	}
 
@@ -234,7 +238,7 @@ func (h *SpaceHandler) GetSpace(c *gin.Context) {
 	space, err := h.spaceService.GetSpaceByID(c.Request.Context(), spaceID)
 	if err != nil {
 		h.logger.Error("Failed to get space", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -250,9 +254,53 @@ func (h *SpaceHandler) GetSpace(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// GetSpaceUsage returns a space's use of its quotas
+// @Summary Get space usage
+// @Description Get the documents, storage, and this month's agent executions and stream events of a space against its quotas. Uploads over the document or storage quota fail with 402 and AETHER-QUOTA-001 or AETHER-QUOTA-002; executions and stream events over the monthly quota fail with 429 and AETHER-QUOTA-003 or AETHER-QUOTA-004 until resets_at.
+// @Tags spaces
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Space ID"
+// @Success 200 {object} models.SpaceQuotaUsage
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/spaces/{id}/usage [get]
+func (h *SpaceHandler) GetSpaceUsage(c *gin.Context) {
+	if h.quotas == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Space usage is not available"))
+		return
+	}
+	spaceID := c.Param("id")
+
+	userID, err := ensureUserExists(c, h.userService, h.logger)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+	role, err := h.spaceService.GetUserRoleInSpace(c.Request.Context(), spaceID, userID)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+	if role == "" {
+		middleware.WriteError(c, h.logger, errors.ForbiddenWithDetails("You do not have access to this space", map[string]interface{}{
+			"space_id": spaceID,
+		}).WithErrorCode(errors.CodeSpaceAccessDenied))
+		return
+	}
+
+	usage, err := h.quotas.Usage(c.Request.Context(), spaceID)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+	c.JSON(http.StatusOK, usage)
+}
+
 // UpdateSpace updates a space
 // @Summary Update space
-// @Description Update space details
+// @Description Update space details and settings. scanning.enabled turns malware scanning of uploads on or off for the space; null follows the deployment default, and turning it on fails with 400 when no scanner is configured. file_validation restricts the files uploaded to the space: allowed_types lists the MIME types it accepts, such as application/pdf or image/*, on top of UPLOAD_ALLOWED_TYPES, and max_size_bytes bounds their size.
 // @Tags spaces
 // @Accept json
 // @Produce json
@@ -277,7 +325,7 @@ func (h *SpaceHandler) UpdateSpace(c *gin.Context) {
 	userID, err := ensureUserExists(c, h.userService, h.logger)
 	if err != nil {
 		h.logger.Error("Failed to resolve user", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -298,21 +346,17 @@ func (h *SpaceHandler) UpdateSpace(c *gin.Context) {
 	role, err := h.spaceService.GetUserRoleInSpace(c.Request.Context(), spaceID, userID)
 	if err != nil {
 		h.logger.Error("Failed to check user role", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 	if role == "" {
 		c.JSON(http.StatusForbidden, errors.ForbiddenWithDetails("You do not have access to this space", map[string]interface{}{
 			"space_id": spaceID,
-		}))
+		}).WithErrorCode(errors.CodeSpaceAccessDenied))
 		return
 	}
-	if !models.HasPermissionLevel(role, "admin") {
-		c.JSON(http.StatusForbidden, errors.ForbiddenWithDetails("You do not have permission to update this space", map[string]interface{}{
-			"space_id":      spaceID,
-			"current_role":  role,
-			"required_role": "admin",
-		}))
+	if err := h.spaceService.AuthorizeRole(c.Request.Context(), spaceID, userID, role, models.ActionSpaceUpdate); err != nil {
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -320,7 +364,7 @@ func (h *SpaceHandler) UpdateSpace(c *gin.Context) {
 	space, err := h.spaceService.UpdateSpace(c.Request.Context(), spaceID, req)
 	if err != nil {
 		h.logger.Error("Failed to update space", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -361,7 +405,7 @@ func (h *SpaceHandler) DeleteSpace(c *gin.Context) {
 	userID, err := ensureUserExists(c, h.userService, h.logger)
 	if err != nil {
 		h.logger.Error("Failed to resolve user", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -369,13 +413,13 @@ func (h *SpaceHandler) DeleteSpace(c *gin.Context) {
 	role, err := h.spaceService.GetUserRoleInSpace(c.Request.Context(), spaceID, userID)
 	if err != nil {
 		h.logger.Error("Failed to check user role", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 	if role == "" {
 		c.JSON(http.StatusForbidden, errors.ForbiddenWithDetails("You do not have access to this space", map[string]interface{}{
 			"space_id": spaceID,
-		}))
+		}).WithErrorCode(errors.CodeSpaceAccessDenied))
 		return
 	}
 	if role != "owner" {
@@ -391,7 +435,7 @@ func (h *SpaceHandler) DeleteSpace(c *gin.Context) {
 	space, err := h.spaceService.GetSpaceByID(c.Request.Context(), spaceID)
 	if err != nil {
 		h.logger.Error("Failed to get space", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -408,7 +452,7 @@ func (h *SpaceHandler) DeleteSpace(c *gin.Context) {
 	err = h.spaceService.DeleteSpace(c.Request.Context(), spaceID, userID)
 	if err != nil {
 		h.logger.Error("Failed to delete space", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -451,7 +495,7 @@ func (h *SpaceHandler) ListSpaceMembers(c *gin.Context) {
 	userID, err := ensureUserExists(c, h.userService, h.logger)
 	if err != nil {
 		h.logger.Error("Failed to resolve user", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -459,13 +503,13 @@ func (h *SpaceHandler) ListSpaceMembers(c *gin.Context) {
 	role, err := h.spaceService.GetUserRoleInSpace(c.Request.Context(), spaceID, userID)
 	if err != nil {
 		h.logger.Error("Failed to check user role", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 	if role == "" {
 		c.JSON(http.StatusForbidden, errors.ForbiddenWithDetails("You do not have access to this space", map[string]interface{}{
 			"space_id": spaceID,
-		}))
+		}).WithErrorCode(errors.CodeSpaceAccessDenied))
 		return
 	}
 
@@ -487,7 +531,7 @@ func (h *SpaceHandler) ListSpaceMembers(c *gin.Context) {
 	response, err := h.spaceService.GetSpaceMembers(c.Request.Context(), spaceID, limit, offset)
 	if err != nil {
 		h.logger.Error("Failed to get space members", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -522,7 +566,7 @@ func (h *SpaceHandler) AddSpaceMember(c *gin.Context) {
 	userID, err := ensureUserExists(c, h.userService, h.logger)
 	if err != nil {
 		h.logger.Error("Failed to resolve user", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -530,21 +574,17 @@ func (h *SpaceHandler) AddSpaceMember(c *gin.Context) {
 	role, err := h.spaceService.GetUserRoleInSpace(c.Request.Context(), spaceID, userID)
 	if err != nil {
 		h.logger.Error("Failed to check user role", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 	if role == "" {
 		c.JSON(http.StatusForbidden, errors.ForbiddenWithDetails("You do not have access to this space", map[string]interface{}{
 			"space_id": spaceID,
-		}))
+		}).WithErrorCode(errors.CodeSpaceAccessDenied))
 		return
 	}
-	if !models.HasPermissionLevel(role, "admin") {
-		c.JSON(http.StatusForbidden, errors.ForbiddenWithDetails("You do not have permission to invite members", map[string]interface{}{
-			"space_id":      spaceID,
-			"current_role":  role,
-			"required_role": "admin",
-		}))
+	if err := h.spaceService.AuthorizeRole(c.Request.Context(), spaceID, userID, role, models.ActionSpaceMembers); err != nil {
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -565,7 +605,7 @@ func (h *SpaceHandler) AddSpaceMember(c *gin.Context) {
 	err = h.spaceService.AddMember(c.Request.Context(), spaceID, req.UserID, req.Role, userID)
 	if err != nil {
 		h.logger.Error("Failed to add member", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -615,7 +655,7 @@ func (h *SpaceHandler) UpdateSpaceMember(c *gin.Context) {
 	userID, err := ensureUserExists(c, h.userService, h.logger)
 	if err != nil {
 		h.logger.Error("Failed to resolve user", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -623,21 +663,17 @@ func (h *SpaceHandler) UpdateSpaceMember(c *gin.Context) {
 	role, err := h.spaceService.GetUserRoleInSpace(c.Request.Context(), spaceID, userID)
 	if err != nil {
 		h.logger.Error("Failed to check user role", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 	if role == "" {
 		c.JSON(http.StatusForbidden, errors.ForbiddenWithDetails("You do not have access to this space", map[string]interface{}{
 			"space_id": spaceID,
-		}))
+		}).WithErrorCode(errors.CodeSpaceAccessDenied))
 		return
 	}
-	if !models.HasPermissionLevel(role, "admin") {
-		c.JSON(http.StatusForbidden, errors.ForbiddenWithDetails("You do not have permission to update member roles", map[string]interface{}{
-			"space_id":      spaceID,
-			"current_role":  role,
-			"required_role": "admin",
-		}))
+	if err := h.spaceService.AuthorizeRole(c.Request.Context(), spaceID, userID, role, models.ActionSpaceMembers); err != nil {
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -658,7 +694,7 @@ func (h *SpaceHandler) UpdateSpaceMember(c *gin.Context) {
 	err = h.spaceService.UpdateMemberRole(c.Request.Context(), spaceID, targetUserID, req.Role)
 	if err != nil {
 		h.logger.Error("Failed to update member role", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -706,7 +742,7 @@ func (h *SpaceHandler) RemoveSpaceMember(c *gin.Context) {
 	userID, err := ensureUserExists(c, h.userService, h.logger)
 	if err != nil {
 		h.logger.Error("Failed to resolve user", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -714,21 +750,17 @@ func (h *SpaceHandler) RemoveSpaceMember(c *gin.Context) {
 	role, err := h.spaceService.GetUserRoleInSpace(c.Request.Context(), spaceID, userID)
 	if err != nil {
 		h.logger.Error("Failed to check user role", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 	if role == "" {
 		c.JSON(http.StatusForbidden, errors.ForbiddenWithDetails("You do not have access to this space", map[string]interface{}{
 			"space_id": spaceID,
-		}))
+		}).WithErrorCode(errors.CodeSpaceAccessDenied))
 		return
 	}
-	if !models.HasPermissionLevel(role, "admin") {
-		c.JSON(http.StatusForbidden, errors.ForbiddenWithDetails("You do not have permission to remove members", map[string]interface{}{
-			"space_id":      spaceID,
-			"current_role":  role,
-			"required_role": "admin",
-		}))
+	if err := h.spaceService.AuthorizeRole(c.Request.Context(), spaceID, userID, role, models.ActionSpaceMembers); err != nil {
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -736,7 +768,7 @@ func (h *SpaceHandler) RemoveSpaceMember(c *gin.Context) {
 	err = h.spaceService.RemoveMember(c.Request.Context(), spaceID, targetUserID)
 	if err != nil {
 		h.logger.Error("Failed to remove member", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -15,6 +15,7 @@ import (
 	"github.com/Tributary-ai-services/aether-be/internal/middleware"
 	"github.com/Tributary-ai-services/aether-be/internal/models"
 	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/internal/webhooks"
 	"github.com/Tributary-ai-services/aether-be/pkg/errors"
 )
 
@@ -63,14 +64,14 @@ func (h *StreamHandler) CreateStreamSource(c *gin.Context) {
 
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -83,7 +84,7 @@ func (h *StreamHandler) CreateStreamSource(c *gin.Context) {
 	source, err := h.streamService.CreateStreamSource(c.Request.Context(), req, userID, spaceContext)
 	if err != nil {
 		h.logger.Error("Failed to create stream source", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, errors.Internal("Failed to create stream source"))
+		middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to create stream source", err))
 		return
 	}
 
@@ -105,14 +106,14 @@ func (h *StreamHandler) CreateStreamSource(c *gin.Context) {
 func (h *StreamHandler) GetStreamSources(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -138,7 +139,7 @@ func (h *StreamHandler) GetStreamSources(c *gin.Context) {
 	sources, total, err := h.streamService.GetStreamSources(c.Request.Context(), spaceContext, limit, offset)
 	if err != nil {
 		h.logger.Error("Failed to get stream sources", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, errors.Internal("Failed to get stream sources"))
+		middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to get stream sources", err))
 		return
 	}
 
@@ -173,14 +174,14 @@ func (h *StreamHandler) GetStreamSource(c *gin.Context) {
 
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -189,7 +190,7 @@ func (h *StreamHandler) GetStreamSource(c *gin.Context) {
 	source, err := h.streamService.GetStreamSourceByID(c.Request.Context(), sourceID, spaceContext)
 	if err != nil {
 		h.logger.Error("Failed to get stream source", zap.String("source_id", sourceID), zap.Error(err))
-		c.JSON(http.StatusNotFound, errors.NotFound("Stream source not found"))
+		c.JSON(http.StatusNotFound, errors.NotFound("Stream source not found").WithErrorCode(errors.CodeStreamSourceNotFound))
 		return
 	}
 
@@ -226,14 +227,14 @@ func (h *StreamHandler) UpdateStreamSource(c *gin.Context) {
 
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -243,9 +244,9 @@ func (h *StreamHandler) UpdateStreamSource(c *gin.Context) {
 	if err != nil {
 		h.logger.Error("Failed to update stream source", zap.String("source_id", sourceID), zap.Error(err))
 		if err.Error() == "stream source not found" {
-			c.JSON(http.StatusNotFound, errors.NotFound("Stream source not found"))
+			c.JSON(http.StatusNotFound, errors.NotFound("Stream source not found").WithErrorCode(errors.CodeStreamSourceNotFound))
 		} else {
-			c.JSON(http.StatusInternalServerError, errors.Internal("Failed to update stream source"))
+			middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to update stream source", err))
 		}
 		return
 	}
@@ -273,14 +274,14 @@ func (h *StreamHandler) DeleteStreamSource(c *gin.Context) {
 
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -290,9 +291,9 @@ func (h *StreamHandler) DeleteStreamSource(c *gin.Context) {
 	if err != nil {
 		h.logger.Error("Failed to delete stream source", zap.String("source_id", sourceID), zap.Error(err))
 		if err.Error() == "stream source not found" {
-			c.JSON(http.StatusNotFound, errors.NotFound("Stream source not found"))
+			c.JSON(http.StatusNotFound, errors.NotFound("Stream source not found").WithErrorCode(errors.CodeStreamSourceNotFound))
 		} else {
-			c.JSON(http.StatusInternalServerError, errors.Internal("Failed to delete stream source"))
+			middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to delete stream source", err))
 		}
 		return
 	}
@@ -300,9 +301,13 @@ func (h *StreamHandler) DeleteStreamSource(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// maxFilterLength bounds live event filter expressions, like the filter of
+// a document search
+const maxFilterLength = 500
+
 // GetLiveEvents retrieves live events
 // @Summary Get live events
-// @Description Get a list of live events with optional filtering
+// @Description Get a list of live events with optional filtering. The filter parameter takes an expression of field:value terms combined with AND, OR, NOT and parentheses, e.g. type:alert AND confidence:>=0.8 AND processed:>2024-01-01. Fields are type, source, media, sentiment, content, confidence, score, processed and occurred; the numbers and dates also take >, >=, < and <=.
 // @Tags streams
 // @Produce json
 // @Security Bearer
@@ -313,21 +318,23 @@ func (h *StreamHandler) DeleteStreamSource(c *gin.Context) {
 // @Param media_types query string false "Comma-separated list of media types to filter by"
 // @Param sentiments query string false "Comma-separated list of sentiments to filter by"
 // @Param min_confidence query number false "Minimum confidence score to filter by"
+// @Param filter query string false "Filter expression, e.g. type:alert AND confidence:>=0.8"
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} errors.APIError
 // @Failure 401 {object} errors.APIError
 // @Failure 500 {object} errors.APIError
 // @Router /api/v1/streams/events [get]
 func (h *StreamHandler) GetLiveEvents(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -365,6 +372,14 @@ func (h *StreamHandler) GetLiveEvents(c *gin.Context) {
 			filters.MinConfidence = minConf
 		}
 	}
+	filters.Filter = c.Query("filter")
+	if len(filters.Filter) > maxFilterLength {
+		c.JSON(http.StatusBadRequest, errors.ValidationWithDetails("Filter expression is too long", map[string]interface{}{
+			"param":      "filter",
+			"max_length": maxFilterLength,
+		}).WithErrorCode(errors.CodeInvalidFilter))
+		return
+	}
 
 	h.logger.Info("Getting live events", 
 		zap.String("user_id", userID), 
@@ -373,9 +388,13 @@ func (h *StreamHandler) GetLiveEvents(c *gin.Context) {
 		zap.Any("filters", filters))
 
 	events, total, err := h.streamService.GetLiveEvents(c.Request.Context(), spaceContext, filters, limit, offset)
+	if errors.IsValidation(err) {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
 	if err != nil {
 		h.logger.Error("Failed to get live events", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, errors.Internal("Failed to get live events"))
+		middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to get live events", err))
 		return
 	}
 
@@ -397,12 +416,14 @@ func (h *StreamHandler) GetLiveEvents(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Security Bearer
+// @Param id path string true "Stream source ID"
 // @Param event body map[string]interface{} true "Event ingestion request"
 // @Success 201 {object} models.LiveEvent
 // @Failure 400 {object} errors.APIError
 // @Failure 401 {object} errors.APIError
+// @Failure 429 {object} errors.APIError
 // @Failure 500 {object} errors.APIError
-// @Router /api/v1/streams/events [post]
+// @Router /api/v1/streams/sources/{id}/events [post]
 func (h *StreamHandler) IngestEvent(c *gin.Context) {
 	var req struct {
 		SourceID   string                 `json:"source_id" binding:"required"`
@@ -420,14 +441,14 @@ func (h *StreamHandler) IngestEvent(c *gin.Context) {
 
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -441,11 +462,14 @@ func (h *StreamHandler) IngestEvent(c *gin.Context) {
 	if err != nil {
 		h.logger.Error("Failed to ingest event", zap.Error(err))
 		if err.Error() == "stream source not found" {
-			c.JSON(http.StatusBadRequest, errors.BadRequest("Stream source not found"))
+			c.JSON(http.StatusBadRequest, errors.BadRequest("Stream source not found").WithErrorCode(errors.CodeStreamSourceNotFound))
 		} else if err.Error() == "stream source is not active" {
 			c.JSON(http.StatusBadRequest, errors.BadRequest("Stream source is not active"))
+		} else if errors.IsAPIError(err) {
+			// Such as a space's exhausted stream event quota
+			middleware.WriteError(c, h.logger, err)
 		} else {
-			c.JSON(http.StatusInternalServerError, errors.Internal("Failed to ingest event"))
+			middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to ingest event", err))
 		}
 		return
 	}
@@ -467,14 +491,14 @@ func (h *StreamHandler) IngestEvent(c *gin.Context) {
 func (h *StreamHandler) GetStreamAnalytics(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -488,7 +512,7 @@ func (h *StreamHandler) GetStreamAnalytics(c *gin.Context) {
 	analytics, err := h.streamService.GetStreamAnalytics(c.Request.Context(), spaceContext, period)
 	if err != nil {
 		h.logger.Error("Failed to get stream analytics", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, errors.Internal("Failed to get stream analytics"))
+		middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to get stream analytics", err))
 		return
 	}
 
@@ -505,18 +529,20 @@ func (h *StreamHandler) GetStreamAnalytics(c *gin.Context) {
 // @Param media_types query string false "Comma-separated list of media types to filter by"
 // @Param sentiments query string false "Comma-separated list of sentiments to filter by"
 // @Param min_confidence query number false "Minimum confidence score to filter by"
-// @Router /api/v1/streams/events/stream [get]
+// @Success 101 "Switching Protocols"
+// @Failure 401 {object} errors.APIError
+// @Router /api/v1/streams/live [get]
 func (h *StreamHandler) StreamEvents(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -573,11 +599,12 @@ func (h *StreamHandler) StreamEvents(c *gin.Context) {
 	}
 
 	// Handle WebSocket connection
-	h.handleWebSocketConnection(conn, streamConn)
+	h.handleWebSocketConnection(c.Request.Context(), conn, streamConn)
 }
 
-// handleWebSocketConnection handles an active WebSocket connection
-func (h *StreamHandler) handleWebSocketConnection(conn *websocket.Conn, streamConn *models.StreamConnection) {
+// handleWebSocketConnection handles an active WebSocket connection until the
+// client goes away or ctx is cancelled (server shutdown)
+func (h *StreamHandler) handleWebSocketConnection(ctx context.Context, conn *websocket.Conn, streamConn *models.StreamConnection) {
 	// Set up ping/pong handlers for connection health
 	conn.SetReadDeadline(time.Now().Add(60 * time.Second))
 	conn.SetPongHandler(func(string) error {
@@ -595,6 +622,12 @@ func (h *StreamHandler) handleWebSocketConnection(conn *websocket.Conn, streamCo
 
 	for {
 		select {
+		case <-ctx.Done():
+			_ = conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"),
+				time.Now().Add(time.Second))
+			return
+
 		case <-pingTicker.C:
 			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
 				h.logger.Debug("Failed to send ping", zap.String("connection_id", streamConn.ID), zap.Error(err))
@@ -671,7 +704,7 @@ func (h *StreamHandler) UpdateStreamSourceStatus(c *gin.Context) {
 	source, err := h.streamService.UpdateStreamSourceStatus(c.Request.Context(), sourceID, req.Status, spaceContext)
 	if err != nil {
 		h.logger.Error("Failed to update stream source status", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update stream source status"})
+		middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to update stream source status", err))
 		return
 	}
 
@@ -701,7 +734,7 @@ func (h *StreamHandler) GetLiveEvent(c *gin.Context) {
 	event, err := h.streamService.GetLiveEventByID(c.Request.Context(), eventID, spaceContext)
 	if err != nil {
 		h.logger.Error("Failed to get live event", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get live event"})
+		middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to get live event", err))
 		return
 	}
 
@@ -728,7 +761,7 @@ func (h *StreamHandler) GetRealtimeAnalytics(c *gin.Context) {
 	analytics, err := h.streamService.GetRealtimeAnalytics(c.Request.Context(), spaceContext)
 	if err != nil {
 		h.logger.Error("Failed to get real-time analytics", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get real-time analytics"})
+		middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to get real-time analytics", err))
 		return
 	}
 
@@ -749,4 +782,49 @@ func parseCommaSeparated(input string) []string {
 	}
 	
 	return parts
+}
+// StreamSourceWebhook ingests an event pushed by a stream source
+// @Summary Stream source webhook
+// @Description Ingest a live event pushed by an external stream source. The source must be active; the event is recorded in the source's space. Deliveries are signed with STREAM_WEBHOOK_SECRET: X-Webhook-Signature is "v1=" and the hex HMAC-SHA256 of "<X-Webhook-ID>.<X-Webhook-Timestamp>.<body>".
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Stream source ID"
+// @Param X-Webhook-ID header string true "Unique delivery ID"
+// @Param X-Webhook-Timestamp header string true "Unix time the delivery was signed"
+// @Param X-Webhook-Signature header string true "v1=<hex HMAC-SHA256>"
+// @Param event body models.StreamWebhookEvent true "Event"
+// @Success 201 {object} models.LiveEvent
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 429 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /webhooks/streams/{id} [post]
+func (h *StreamHandler) StreamSourceWebhook(c *gin.Context) {
+	sourceID := c.Param("id")
+
+	var req models.StreamWebhookEvent
+	if err := webhooks.DecodePayload(middleware.WebhookBody(c), &req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.Validation("Invalid webhook payload", err))
+		return
+	}
+
+	event, err := h.streamService.IngestWebhookEvent(c.Request.Context(), sourceID, req.EventType, req.Content, req.MediaType, req.Metadata)
+	if err != nil {
+		switch err.Error() {
+		case "stream source not found":
+			c.JSON(http.StatusNotFound, errors.NotFound("Stream source not found").WithErrorCode(errors.CodeStreamSourceNotFound))
+		case "stream source is not active":
+			c.JSON(http.StatusBadRequest, errors.BadRequest("Stream source is not active"))
+		default:
+			if !errors.IsAPIError(err) {
+				err = errors.InternalWithCause("Failed to ingest event", err)
+			}
+			middleware.WriteError(c, h.logger, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, event)
 }
\ No newline at end of file
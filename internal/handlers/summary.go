@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/services"
+)
+
+// SummaryHandler serves document abstracts and notebook digests
+type SummaryHandler struct {
+	summaryService *services.SummaryService
+	logger         *logger.Logger
+}
+
+// NewSummaryHandler creates a new summary handler
+func NewSummaryHandler(summaryService *services.SummaryService, log *logger.Logger) *SummaryHandler {
+	return &SummaryHandler{
+		summaryService: summaryService,
+		logger:         log.WithService("summary_handler"),
+	}
+}
+
+// documentSummary serves a document's abstract, written again when refresh
+// is set
+func (h *SummaryHandler) documentSummary(c *gin.Context, refresh bool) {
+	userID, spaceContext, ok := requireUserAndSpace(c, h.logger)
+	if !ok {
+		return
+	}
+
+	summary, err := h.summaryService.DocumentSummary(c.Request.Context(), c.Param("id"), refresh, userID, extractAuthToken(c), spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// notebookSummary serves a notebook's digest, written again when refresh
+// is set
+func (h *SummaryHandler) notebookSummary(c *gin.Context, refresh bool) {
+	userID, spaceContext, ok := requireUserAndSpace(c, h.logger)
+	if !ok {
+		return
+	}
+
+	summary, err := h.summaryService.NotebookSummary(c.Request.Context(), c.Param("id"), refresh, userID, extractAuthToken(c), spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetDocumentSummary returns the abstract of a document
+// @Summary Get document summary
+// @Description Get the abstract of a document, written by the configured model (SUMMARY_MODEL) from its extracted text. The abstract is cached on the document; one that does not exist yet is written now, counting against the space's monthly summary token budget. stale is set when the document was reprocessed after the abstract was written. Fails with 409 and AETHER-SUMMARY-002 when the document has no extracted text, and with 429 and AETHER-SUMMARY-001 when the budget is spent.
+// @Tags documents
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Document ID"
+// @Success 200 {object} models.Summary
+// @Failure 401 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 409 {object} errors.APIError
+// @Failure 429 {object} errors.APIError
+// @Failure 502 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/documents/{id}/summary [get]
+func (h *SummaryHandler) GetDocumentSummary(c *gin.Context) {
+	h.documentSummary(c, false)
+}
+
+// RefreshDocumentSummary writes the abstract of a document again
+// @Summary Refresh document summary
+// @Description Write the abstract of a document again, for example after it was reprocessed, replacing the cached one. Counts against the space's monthly summary token budget.
+// @Tags documents
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Document ID"
+// @Success 200 {object} models.Summary
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 409 {object} errors.APIError
+// @Failure 429 {object} errors.APIError
+// @Failure 502 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/documents/{id}/summary/refresh [post]
+func (h *SummaryHandler) RefreshDocumentSummary(c *gin.Context) {
+	h.documentSummary(c, true)
+}
+
+// GetNotebookSummary returns the digest of a notebook
+// @Summary Get notebook summary
+// @Description Get the digest of a notebook, written by the configured model from the abstracts, or the start of the text, of its processed documents. The digest is cached on the notebook; one that does not exist yet is written now, counting against the space's monthly summary token budget. stale is set when documents were added, removed, reprocessed or summarized again after the digest was written. Fails with 409 and AETHER-SUMMARY-002 when the notebook has no processed documents.
+// @Tags notebooks
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Notebook ID"
+// @Success 200 {object} models.Summary
+// @Failure 401 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 409 {object} errors.APIError
+// @Failure 429 {object} errors.APIError
+// @Failure 502 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/notebooks/{id}/summary [get]
+func (h *SummaryHandler) GetNotebookSummary(c *gin.Context) {
+	h.notebookSummary(c, false)
+}
+
+// RefreshNotebookSummary writes the digest of a notebook again
+// @Summary Refresh notebook summary
+// @Description Write the digest of a notebook again from its current documents, replacing the cached one. Counts against the space's monthly summary token budget.
+// @Tags notebooks
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Notebook ID"
+// @Success 200 {object} models.Summary
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 409 {object} errors.APIError
+// @Failure 429 {object} errors.APIError
+// @Failure 502 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/notebooks/{id}/summary/refresh [post]
+func (h *SummaryHandler) RefreshNotebookSummary(c *gin.Context) {
+	h.notebookSummary(c, true)
+}
+
+// GetSummaryUsage returns the space's summary token spending this month
+// @Summary Get summary usage
+// @Description Get the tokens the current space has spent on summaries this calendar month (UTC), its budget (SUMMARY_MONTHLY_TOKEN_BUDGET, 0 when unlimited) and when the budget renews.
+// @Tags spaces
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.SummaryUsage
+// @Failure 401 {object} errors.APIError
+// @Router /api/v1/summaries/usage [get]
+func (h *SummaryHandler) GetSummaryUsage(c *gin.Context) {
+	_, spaceContext, ok := requireUserAndSpace(c, h.logger)
+	if !ok {
+		return
+	}
+
+	usage, err := h.summaryService.Usage(c.Request.Context(), spaceContext.SpaceID)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
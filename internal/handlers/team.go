@@ -7,6 +7,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
 	"github.com/Tributary-ai-services/aether-be/internal/models"
 	"github.com/Tributary-ai-services/aether-be/internal/services"
 	"github.com/Tributary-ai-services/aether-be/pkg/errors"
@@ -46,7 +47,7 @@ func (h *TeamHandler) CreateTeam(c *gin.Context) {
 	userID, err := ensureUserExists(c, h.userService, h.logger)
 	if err != nil {
 		h.logger.Error("Failed to ensure user exists", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -62,7 +63,7 @@ func (h *TeamHandler) CreateTeam(c *gin.Context) {
 	team, err := h.teamService.CreateTeam(c.Request.Context(), req, userID)
 	if err != nil {
 		h.logger.Error("Failed to create team", zap.Error(err), zap.String("user_id", userID))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -87,7 +88,7 @@ func (h *TeamHandler) GetTeams(c *gin.Context) {
 	userID, err := ensureUserExists(c, h.userService, h.logger)
 	if err != nil {
 		h.logger.Error("Failed to ensure user exists", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -96,7 +97,7 @@ func (h *TeamHandler) GetTeams(c *gin.Context) {
 	teams, err := h.teamService.GetTeams(c.Request.Context(), userID, organizationID)
 	if err != nil {
 		h.logger.Error("Failed to get teams", zap.Error(err), zap.String("user_id", userID))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -126,7 +127,7 @@ func (h *TeamHandler) GetTeams(c *gin.Context) {
 func (h *TeamHandler) GetTeam(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
@@ -141,7 +142,7 @@ func (h *TeamHandler) GetTeam(c *gin.Context) {
 	team, err := h.teamService.GetTeam(c.Request.Context(), teamID, userID)
 	if err != nil {
 		h.logger.Error("Failed to get team", zap.Error(err), zap.String("team_id", teamID), zap.String("user_id", userID))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -167,7 +168,7 @@ func (h *TeamHandler) GetTeam(c *gin.Context) {
 func (h *TeamHandler) UpdateTeam(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
@@ -191,7 +192,7 @@ func (h *TeamHandler) UpdateTeam(c *gin.Context) {
 	team, err := h.teamService.UpdateTeam(c.Request.Context(), teamID, req, userID)
 	if err != nil {
 		h.logger.Error("Failed to update team", zap.Error(err), zap.String("team_id", teamID), zap.String("user_id", userID))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -216,7 +217,7 @@ func (h *TeamHandler) UpdateTeam(c *gin.Context) {
 func (h *TeamHandler) DeleteTeam(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
@@ -231,7 +232,7 @@ func (h *TeamHandler) DeleteTeam(c *gin.Context) {
 	err := h.teamService.DeleteTeam(c.Request.Context(), teamID, userID)
 	if err != nil {
 		h.logger.Error("Failed to delete team", zap.Error(err), zap.String("team_id", teamID), zap.String("user_id", userID))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -256,7 +257,7 @@ func (h *TeamHandler) DeleteTeam(c *gin.Context) {
 func (h *TeamHandler) GetTeamMembers(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
@@ -271,7 +272,7 @@ func (h *TeamHandler) GetTeamMembers(c *gin.Context) {
 	members, err := h.teamService.GetTeamMembers(c.Request.Context(), teamID, userID)
 	if err != nil {
 		h.logger.Error("Failed to get team members", zap.Error(err), zap.String("team_id", teamID), zap.String("user_id", userID))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -304,7 +305,7 @@ func (h *TeamHandler) GetTeamMembers(c *gin.Context) {
 func (h *TeamHandler) InviteTeamMember(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
@@ -329,7 +330,7 @@ func (h *TeamHandler) InviteTeamMember(c *gin.Context) {
 	if err != nil {
 		h.logger.Error("Failed to invite team member", zap.Error(err), 
 			zap.String("team_id", teamID), zap.String("user_id", userID), zap.String("email", req.Email))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -358,7 +359,7 @@ func (h *TeamHandler) InviteTeamMember(c *gin.Context) {
 func (h *TeamHandler) UpdateTeamMemberRole(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
@@ -385,7 +386,7 @@ func (h *TeamHandler) UpdateTeamMemberRole(c *gin.Context) {
 	if err != nil {
 		h.logger.Error("Failed to update team member role", zap.Error(err), 
 			zap.String("team_id", teamID), zap.String("target_user_id", targetUserID), zap.String("user_id", userID))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -412,7 +413,7 @@ func (h *TeamHandler) UpdateTeamMemberRole(c *gin.Context) {
 func (h *TeamHandler) RemoveTeamMember(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
@@ -430,7 +431,7 @@ func (h *TeamHandler) RemoveTeamMember(c *gin.Context) {
 	if err != nil {
 		h.logger.Error("Failed to remove team member", zap.Error(err), 
 			zap.String("team_id", teamID), zap.String("target_user_id", targetUserID), zap.String("user_id", userID))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
+	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// TrashHandler serves the trash of deleted documents and notebooks
+type TrashHandler struct {
+	trashService *services.TrashService
+	logger       *logger.Logger
+}
+
+// NewTrashHandler creates a new trash handler
+func NewTrashHandler(trashService *services.TrashService, log *logger.Logger) *TrashHandler {
+	return &TrashHandler{
+		trashService: trashService,
+		logger:       log.WithService("trash_handler"),
+	}
+}
+
+// itemType returns the item type of a trash request, writing the error
+// response when it is invalid
+func (h *TrashHandler) itemType(c *gin.Context, itemType, param string) (string, bool) {
+	if itemType != "" && !services.ValidTrashItemType(itemType) {
+		middleware.WriteError(c, h.logger, errors.ValidationWithDetails("Invalid trash item type", map[string]interface{}{
+			"param": param,
+		}))
+		return "", false
+	}
+	return itemType, true
+}
+
+// ListTrash lists the deleted documents and notebooks of the space
+// @Summary List trash
+// @Description List the deleted documents and notebooks of the space that can still be restored, most recently deleted first. purge_at is when each is purged for good, TRASH_RETENTION_DAYS after deletion. Documents of a deleted notebook are not listed; they are restored and purged with it.
+// @Tags trash
+// @Security Bearer
+// @Produce json
+// @Param type query string false "Only items of this type" Enums(document, notebook)
+// @Param limit query int false "Number of items to return" default(20)
+// @Param offset query int false "Number of items to skip" default(0)
+// @Success 200 {object} models.TrashListResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Router /api/v1/trash [get]
+func (h *TrashHandler) ListTrash(c *gin.Context) {
+	userID, spaceContext, ok := requireUserAndSpace(c, h.logger)
+	if !ok {
+		return
+	}
+	itemType, ok := h.itemType(c, c.Query("type"), "type")
+	if !ok {
+		return
+	}
+
+	page := parsePaginationParams(c, pagination.DefaultLimit)
+	trash, err := h.trashService.List(c.Request.Context(), itemType, userID, spaceContext, page.Limit, page.Offset)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, trash)
+}
+
+// RestoreTrashItem restores a deleted document or notebook
+// @Summary Restore from trash
+// @Description Take a document or notebook out of the trash. A document gets back the status it had, except that one deleted while uploading or processing is restored as failed so it can be reprocessed. A document of a deleted notebook fails with 409 and AETHER-TRASH-002 until the notebook is restored. Items not in the trash fail with 404 and AETHER-TRASH-001.
+// @Tags trash
+// @Security Bearer
+// @Param type path string true "Item type" Enums(document, notebook)
+// @Param id path string true "Document or notebook ID"
+// @Success 204
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 409 {object} errors.APIError
+// @Router /api/v1/trash/{type}/{id}/restore [post]
+func (h *TrashHandler) RestoreTrashItem(c *gin.Context) {
+	userID, spaceContext, ok := requireUserAndSpace(c, h.logger)
+	if !ok {
+		return
+	}
+	itemType, ok := h.itemType(c, c.Param("type"), "type")
+	if !ok {
+		return
+	}
+
+	if err := h.trashService.Restore(c.Request.Context(), itemType, c.Param("id"), userID, spaceContext); err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// PurgeTrashItem permanently deletes a document or notebook in the trash
+// @Summary Purge from trash
+// @Description Permanently delete a document or notebook in the trash without waiting for TRASH_RETENTION_DAYS to pass. A notebook is purged with all its documents. The files, chunks and vectors of purged documents are removed from AudiModal, the vector store and storage by the deletion orchestrator (GET /api/v1/admin/deletions).
+// @Tags trash
+// @Security Bearer
+// @Param type path string true "Item type" Enums(document, notebook)
+// @Param id path string true "Document or notebook ID"
+// @Success 204
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/trash/{type}/{id} [delete]
+func (h *TrashHandler) PurgeTrashItem(c *gin.Context) {
+	userID, spaceContext, ok := requireUserAndSpace(c, h.logger)
+	if !ok {
+		return
+	}
+	itemType, ok := h.itemType(c, c.Param("type"), "type")
+	if !ok {
+		return
+	}
+
+	if err := h.trashService.Purge(c.Request.Context(), itemType, c.Param("id"), userID, spaceContext); err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
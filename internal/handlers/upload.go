@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxFormFieldBytes caps each non-file field of a multipart upload
+const maxFormFieldBytes = 64 << 10
+
+// errFileTooLarge is returned by readUploadForm when the file part is
+// larger than the upload limit
+var errFileTooLarge = stderrors.New("file too large")
+
+// uploadForm holds the parts of a multipart document upload
+type uploadForm struct {
+	fields   map[string]string
+	fileName string
+	mimeType string
+	fileData []byte
+}
+
+// readUploadForm reads a multipart upload part by part instead of
+// buffering the whole form, so a file over maxFileBytes is rejected as soon
+// as the limit is passed. Only the first "file" part is kept.
+func readUploadForm(r *http.Request, maxFileBytes int64) (*uploadForm, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+
+	form := &uploadForm{fields: make(map[string]string)}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case part.FormName() == "file" && part.FileName() != "" && form.fileData == nil:
+			data, err := io.ReadAll(io.LimitReader(part, maxFileBytes+1))
+			if err != nil {
+				return nil, err
+			}
+			if int64(len(data)) > maxFileBytes {
+				return nil, errFileTooLarge
+			}
+			form.fileName = part.FileName()
+			form.mimeType = part.Header.Get("Content-Type")
+			form.fileData = data
+		case part.FileName() == "" && part.FormName() != "":
+			value, err := io.ReadAll(io.LimitReader(part, maxFormFieldBytes+1))
+			if err != nil {
+				return nil, err
+			}
+			if len(value) > maxFormFieldBytes {
+				return nil, fmt.Errorf("form field %q exceeds %d bytes", part.FormName(), maxFormFieldBytes)
+			}
+			if _, seen := form.fields[part.FormName()]; !seen {
+				form.fields[part.FormName()] = string(value)
+			}
+		}
+		part.Close()
+	}
+
+	if form.fileData == nil {
+		return nil, http.ErrMissingFile
+	}
+	return form, nil
+}
+
+// formatByteLimit renders a size limit for error messages
+func formatByteLimit(limit int64) string {
+	if limit >= 1<<20 && limit%(1<<20) == 0 {
+		return fmt.Sprintf("%dMB", limit>>20)
+	}
+	return fmt.Sprintf("%d bytes", limit)
+}
+
+// errTooManyFiles is returned by readBulkUploadForm when more files are
+// sent than a bulk upload accepts
+var errTooManyFiles = stderrors.New("too many files")
+
+// bulkUploadPart is one file of a bulk upload. A file over the upload
+// limit is kept without its data so it can be reported as failed.
+type bulkUploadPart struct {
+	fileName string
+	mimeType string
+	data     []byte
+	tooLarge bool
+}
+
+// bulkUploadForm holds the parts of a multipart bulk upload
+type bulkUploadForm struct {
+	fields map[string]string
+	files  []bulkUploadPart
+}
+
+// readBulkUploadForm reads a multipart bulk upload part by part. Every
+// "files" part is a file; reading stops with errTooManyFiles once more
+// than maxFiles are sent. A file over maxFileBytes does not fail the
+// upload, its remaining bytes are skipped instead.
+func readBulkUploadForm(r *http.Request, maxFileBytes int64, maxFiles int) (*bulkUploadForm, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+
+	form := &bulkUploadForm{fields: make(map[string]string)}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case part.FormName() == "files" && part.FileName() != "":
+			if len(form.files) == maxFiles {
+				part.Close()
+				return nil, errTooManyFiles
+			}
+			file := bulkUploadPart{fileName: part.FileName(), mimeType: part.Header.Get("Content-Type")}
+			data, err := io.ReadAll(io.LimitReader(part, maxFileBytes+1))
+			if err != nil {
+				return nil, err
+			}
+			if int64(len(data)) > maxFileBytes {
+				if _, err := io.Copy(io.Discard, part); err != nil {
+					return nil, err
+				}
+				file.tooLarge = true
+			} else {
+				file.data = data
+			}
+			form.files = append(form.files, file)
+		case part.FileName() == "" && part.FormName() != "":
+			value, err := io.ReadAll(io.LimitReader(part, maxFormFieldBytes+1))
+			if err != nil {
+				return nil, err
+			}
+			if len(value) > maxFormFieldBytes {
+				return nil, fmt.Errorf("form field %q exceeds %d bytes", part.FormName(), maxFormFieldBytes)
+			}
+			if _, seen := form.fields[part.FormName()]; !seen {
+				form.fields[part.FormName()] = string(value)
+			}
+		}
+		part.Close()
+	}
+
+	if len(form.files) == 0 {
+		return nil, http.ErrMissingFile
+	}
+	return form, nil
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// CreateUploadIntent starts a direct upload into a notebook
+// @Summary Start a direct upload
+// @Description Start an upload the client stores in object storage itself, so the file does not pass through the API. The document is created in status uploading, and the response gives a presigned URL to PUT the file to, with the headers to send, valid for an hour. Declare the file's size, MIME type and hex SHA-256 checksum; once stored, confirm the upload with POST /documents/{id}/confirm-upload. Files over MAX_DIRECT_UPLOAD_BYTES or the space's size limit fail with AETHER-DOC-003. A declared MIME type that contradicts the file's extension fails with 422 and AETHER-FILE-001, and a type the deployment or the space does not allow with 415 and AETHER-FILE-002. Documents of uploads not confirmed in time are deleted.
+// @Tags documents
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Notebook ID"
+// @Param request body models.UploadIntentCreateRequest true "File to upload"
+// @Success 201 {object} models.UploadIntent
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 402 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 415 {object} errors.APIError
+// @Failure 422 {object} errors.APIError
+// @Failure 502 {object} errors.APIError
+// @Router /api/v1/notebooks/{id}/upload-intents [post]
+func (h *DocumentHandler) CreateUploadIntent(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == "" {
+		middleware.WriteError(c, h.logger, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
+		return
+	}
+
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return
+	}
+
+	var req models.UploadIntentCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Invalid request body", err))
+		return
+	}
+	req.FileName = strings.TrimSpace(req.FileName)
+	if err := validateStruct(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Validation failed", err))
+		return
+	}
+
+	intent, err := h.documentService.CreateUploadIntent(c.Request.Context(), c.Param("id"), req, userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, intent)
+}
+
+// ConfirmUpload finishes a direct upload
+// @Summary Confirm a direct upload
+// @Description Check the file stored for a direct upload against the size, MIME type and SHA-256 checksum declared for it, and submit the document for processing. Fails with 409 and AETHER-UPLOAD-005 when the file has not been stored yet, and with 400 and AETHER-UPLOAD-006 when it does not match; the mismatched file is deleted and can be stored again while the upload URL is valid. Fails with 409 and AETHER-UPLOAD-002 when the upload was confirmed already or has expired.
+// @Tags documents
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Document ID"
+// @Success 200 {object} models.DocumentResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 409 {object} errors.APIError
+// @Failure 502 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/documents/{id}/confirm-upload [post]
+func (h *DocumentHandler) ConfirmUpload(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == "" {
+		middleware.WriteError(c, h.logger, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
+		return
+	}
+
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return
+	}
+
+	document, err := h.documentService.ConfirmUpload(c.Request.Context(), c.Param("id"), userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, document.ToResponse())
+}
@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// UploadSessionHandler runs resumable uploads of large documents
+type UploadSessionHandler struct {
+	uploadService *services.UploadSessionService
+	logger        *logger.Logger
+}
+
+// NewUploadSessionHandler creates a new resumable upload handler
+func NewUploadSessionHandler(uploadService *services.UploadSessionService, log *logger.Logger) *UploadSessionHandler {
+	return &UploadSessionHandler{
+		uploadService: uploadService,
+		logger:        log.WithService("upload_session_handler"),
+	}
+}
+
+// InitiateUpload starts a resumable upload into a notebook
+// @Summary Start a resumable upload
+// @Description Start a resumable upload of a file too large to send in one request, up to MAX_RESUMABLE_UPLOAD_BYTES. The document is created in status uploading. Send the file as part_count parts of part_size bytes, the last one shorter, with PUT /uploads/{id}/parts/{number}, then complete the upload. Uploads not completed within 24 hours are aborted. The declared MIME type and size are validated as for direct uploads, failing with 422 and AETHER-FILE-001 or 415 and AETHER-FILE-002.
+// @Tags documents
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Notebook ID"
+// @Param request body models.UploadSessionCreateRequest true "File to upload"
+// @Success 201 {object} models.UploadSession
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 402 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 415 {object} errors.APIError
+// @Failure 422 {object} errors.APIError
+// @Failure 502 {object} errors.APIError
+// @Router /api/v1/notebooks/{id}/uploads [post]
+func (h *UploadSessionHandler) InitiateUpload(c *gin.Context) {
+	userID, spaceContext, ok := requireUserAndSpace(c, h.logger)
+	if !ok {
+		return
+	}
+
+	var req models.UploadSessionCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Invalid request body", err))
+		return
+	}
+	req.FileName = strings.TrimSpace(req.FileName)
+	if err := validateStruct(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Validation failed", err))
+		return
+	}
+
+	session, err := h.uploadService.Initiate(c.Request.Context(), c.Param("id"), req, userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, session)
+}
+
+// GetUpload returns a resumable upload
+// @Summary Get a resumable upload
+// @Description Get a resumable upload and the parts stored so far. An interrupted upload resumes by sending the parts not listed.
+// @Tags documents
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Upload ID"
+// @Success 200 {object} models.UploadSession
+// @Failure 401 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/uploads/{id} [get]
+func (h *UploadSessionHandler) GetUpload(c *gin.Context) {
+	userID, spaceContext, ok := requireUserAndSpace(c, h.logger)
+	if !ok {
+		return
+	}
+
+	session, err := h.uploadService.GetSession(c.Request.Context(), c.Param("id"), userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// UploadPart stores a part of a resumable upload
+// @Summary Upload a part
+// @Description Store a part of a resumable upload, sent as the raw request body. Parts are numbered from 1; every part is part_size bytes except the last. Parts may be sent in any order and in parallel, and sending a part again replaces it. The content of part 1 is checked against the declared MIME type and file name, failing with 422 and AETHER-FILE-001 when it contradicts them.
+// @Tags documents
+// @Accept application/octet-stream
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Upload ID"
+// @Param number path int true "Part number"
+// @Param part body string true "Part data"
+// @Success 200 {object} models.UploadSessionPart
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 409 {object} errors.APIError
+// @Failure 413 {object} errors.APIError
+// @Failure 422 {object} errors.APIError
+// @Failure 502 {object} errors.APIError
+// @Router /api/v1/uploads/{id}/parts/{number} [put]
+func (h *UploadSessionHandler) UploadPart(c *gin.Context) {
+	userID, spaceContext, ok := requireUserAndSpace(c, h.logger)
+	if !ok {
+		return
+	}
+
+	number, err := strconv.Atoi(c.Param("number"))
+	if err != nil {
+		middleware.WriteError(c, h.logger, errors.ValidationWithDetails("Invalid part number", map[string]interface{}{
+			"param": "number",
+		}).WithErrorCode(errors.CodeInvalidUploadPart))
+		return
+	}
+
+	// The body limit of this route is the part size, so a part is held in
+	// memory at most once
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		if middleware.IsBodyTooLarge(err) {
+			middleware.WriteError(c, h.logger, err)
+			return
+		}
+		h.logger.Error("Failed to read upload part", zap.String("upload_id", c.Param("id")), zap.Error(err))
+		middleware.WriteError(c, h.logger, errors.BadRequest("Failed to read upload part"))
+		return
+	}
+
+	part, err := h.uploadService.UploadPart(c.Request.Context(), c.Param("id"), number, data, userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, part)
+}
+
+// CompleteUpload completes a resumable upload
+// @Summary Complete a resumable upload
+// @Description Assemble the parts of a resumable upload into the document's file and submit the document for processing. Fails with 409 and AETHER-UPLOAD-004 while parts are missing, listing them in details.missing_parts.
+// @Tags documents
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Upload ID"
+// @Success 201 {object} models.DocumentResponse
+// @Failure 401 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 409 {object} errors.APIError
+// @Failure 502 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/uploads/{id}/complete [post]
+func (h *UploadSessionHandler) CompleteUpload(c *gin.Context) {
+	userID, spaceContext, ok := requireUserAndSpace(c, h.logger)
+	if !ok {
+		return
+	}
+
+	document, err := h.uploadService.Complete(c.Request.Context(), c.Param("id"), userID, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, document.ToResponse())
+}
+
+// AbortUpload cancels a resumable upload
+// @Summary Abort a resumable upload
+// @Description Cancel a resumable upload, discarding its stored parts and its document.
+// @Tags documents
+// @Security Bearer
+// @Param id path string true "Upload ID"
+// @Success 204
+// @Failure 401 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 409 {object} errors.APIError
+// @Router /api/v1/uploads/{id} [delete]
+func (h *UploadSessionHandler) AbortUpload(c *gin.Context) {
+	userID, spaceContext, ok := requireUserAndSpace(c, h.logger)
+	if !ok {
+		return
+	}
+
+	if err := h.uploadService.Abort(c.Request.Context(), c.Param("id"), userID, spaceContext); err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
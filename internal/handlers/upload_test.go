@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newBulkUploadRequest builds a bulk upload of files, keyed by file name
+func newBulkUploadRequest(t *testing.T, files ...string) *http.Request {
+	t.Helper()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	require.NoError(t, writer.WriteField("tags", "a, b"))
+	for _, name := range files {
+		part, err := writer.CreateFormFile("files", name)
+		require.NoError(t, err)
+		_, err = part.Write([]byte(name + " contents"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/notebooks/nb-1/documents/bulk", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestReadBulkUploadForm(t *testing.T) {
+	form, err := readBulkUploadForm(newBulkUploadRequest(t, "a.txt", "a-much-longer-name.txt"), 20, 5)
+	require.NoError(t, err)
+	assert.Equal(t, "a, b", form.fields["tags"])
+	require.Len(t, form.files, 2)
+	assert.Equal(t, "a.txt contents", string(form.files[0].data))
+	assert.True(t, form.files[1].tooLarge, "a file over the limit is reported, not fatal")
+	assert.Nil(t, form.files[1].data)
+
+	_, err = readBulkUploadForm(newBulkUploadRequest(t, "a.txt", "b.txt", "c.txt"), 20, 2)
+	assert.Equal(t, errTooManyFiles, err)
+
+	_, err = readBulkUploadForm(newBulkUploadRequest(t), 20, 2)
+	assert.Equal(t, http.ErrMissingFile, err)
+}
@@ -8,6 +8,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
 	"github.com/Tributary-ai-services/aether-be/internal/models"
 	"github.com/Tributary-ai-services/aether-be/internal/services"
 	"github.com/Tributary-ai-services/aether-be/pkg/errors"
@@ -50,7 +51,7 @@ func NewUserHandler(
 func (h *UserHandler) GetCurrentUser(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
@@ -92,12 +93,12 @@ func (h *UserHandler) GetCurrentUser(c *gin.Context) {
 					user, err = h.userService.GetUserByKeycloakID(c.Request.Context(), userID)
 					if err != nil {
 						h.logger.Error("Failed to fetch existing user after conflict", zap.String("keycloak_id", userID), zap.Error(err))
-						c.JSON(http.StatusInternalServerError, errors.Internal("Failed to retrieve user profile"))
+						middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to retrieve user profile", err))
 						return
 					}
 				} else {
 					h.logger.Error("Failed to create user from JWT token", zap.String("keycloak_id", userID), zap.Error(err))
-					c.JSON(http.StatusInternalServerError, errors.Internal("Failed to create user profile"))
+					middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to create user profile", err))
 					return
 				}
 			} else {
@@ -123,7 +124,7 @@ func (h *UserHandler) GetCurrentUser(c *gin.Context) {
 			}
 		} else {
 			h.logger.Error("Failed to get current user", zap.String("user_id", userID), zap.Error(err))
-			c.JSON(http.StatusInternalServerError, errors.Internal("Failed to retrieve user"))
+			middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to retrieve user", err))
 			return
 		}
 	}
@@ -147,7 +148,7 @@ func (h *UserHandler) GetCurrentUser(c *gin.Context) {
 func (h *UserHandler) UpdateCurrentUser(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
@@ -170,7 +171,7 @@ func (h *UserHandler) UpdateCurrentUser(c *gin.Context) {
 	user, err := h.userService.UpdateUser(c.Request.Context(), userID, sanitizedReq)
 	if err != nil {
 		h.logger.Error("Failed to update user", zap.String("user_id", userID), zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -201,7 +202,7 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 	user, err := h.userService.GetUserByID(c.Request.Context(), userID)
 	if err != nil {
 		h.logger.Error("Failed to get user by ID", zap.String("user_id", userID), zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -259,7 +260,7 @@ func (h *UserHandler) SearchUsers(c *gin.Context) {
 	response, err := h.userService.SearchUsers(c.Request.Context(), req)
 	if err != nil {
 		h.logger.Error("Failed to search users", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -279,10 +280,11 @@ func (h *UserHandler) SearchUsers(c *gin.Context) {
 // @Failure 401 {object} errors.APIError
 // @Failure 500 {object} errors.APIError
 // @Router /api/v1/users/me/preferences [put]
+// @Router /api/v1/users/me/preferences [get]
 func (h *UserHandler) UpdateUserPreferences(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
@@ -296,7 +298,7 @@ func (h *UserHandler) UpdateUserPreferences(c *gin.Context) {
 	updatedPrefs, err := h.userService.UpdateUserPreferences(c.Request.Context(), userID, preferences)
 	if err != nil {
 		h.logger.Error("Failed to update user preferences", zap.String("user_id", userID), zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -317,14 +319,14 @@ func (h *UserHandler) UpdateUserPreferences(c *gin.Context) {
 func (h *UserHandler) GetUserStats(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	stats, err := h.userService.GetUserStats(c.Request.Context(), userID)
 	if err != nil {
 		h.logger.Error("Failed to get user stats", zap.String("user_id", userID), zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -345,14 +347,14 @@ func (h *UserHandler) GetUserStats(c *gin.Context) {
 func (h *UserHandler) DeleteCurrentUser(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	err := h.userService.DeleteUser(c.Request.Context(), userID)
 	if err != nil {
 		h.logger.Error("Failed to delete user", zap.String("user_id", userID), zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -373,7 +375,7 @@ func (h *UserHandler) DeleteCurrentUser(c *gin.Context) {
 func (h *UserHandler) GetUserSpaces(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
@@ -415,12 +417,12 @@ func (h *UserHandler) GetUserSpaces(c *gin.Context) {
 					user, err = h.userService.GetUserByKeycloakID(c.Request.Context(), userID)
 					if err != nil {
 						h.logger.Error("Failed to fetch existing user after conflict", zap.String("keycloak_id", userID), zap.Error(err))
-						c.JSON(http.StatusInternalServerError, errors.Internal("Failed to retrieve user profile"))
+						middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to retrieve user profile", err))
 						return
 					}
 				} else {
 					h.logger.Error("Failed to create user from JWT token", zap.String("keycloak_id", userID), zap.Error(err))
-					c.JSON(http.StatusInternalServerError, errors.Internal("Failed to create user profile"))
+					middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to create user profile", err))
 					return
 				}
 			} else {
@@ -446,7 +448,7 @@ func (h *UserHandler) GetUserSpaces(c *gin.Context) {
 			}
 		} else {
 			h.logger.Error("Failed to get user", zap.String("keycloak_id", userID), zap.Error(err))
-			handleServiceError(c, err)
+			middleware.WriteError(c, h.logger, err)
 			return
 		}
 	}
@@ -455,7 +457,7 @@ func (h *UserHandler) GetUserSpaces(c *gin.Context) {
 	spaces, err := h.spaceService.GetUserSpaces(c.Request.Context(), userID)
 	if err != nil {
 		h.logger.Error("Failed to get user spaces", zap.String("keycloak_id", userID), zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -476,7 +478,7 @@ func (h *UserHandler) GetUserSpaces(c *gin.Context) {
 func (h *UserHandler) GetOnboardingStatus(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
@@ -484,7 +486,7 @@ func (h *UserHandler) GetOnboardingStatus(c *gin.Context) {
 	user, err := h.userService.GetUserByKeycloakID(c.Request.Context(), userID)
 	if err != nil {
 		h.logger.Error("Failed to get user for onboarding status", zap.String("keycloak_id", userID), zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -495,7 +497,7 @@ func (h *UserHandler) GetOnboardingStatus(c *gin.Context) {
 			zap.String("user_id", user.ID),
 			zap.Error(err),
 		)
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -516,7 +518,7 @@ func (h *UserHandler) GetOnboardingStatus(c *gin.Context) {
 func (h *UserHandler) MarkTutorialComplete(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
@@ -524,7 +526,7 @@ func (h *UserHandler) MarkTutorialComplete(c *gin.Context) {
 	user, err := h.userService.GetUserByKeycloakID(c.Request.Context(), userID)
 	if err != nil {
 		h.logger.Error("Failed to get user for tutorial completion", zap.String("keycloak_id", userID), zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -535,7 +537,7 @@ func (h *UserHandler) MarkTutorialComplete(c *gin.Context) {
 			zap.String("user_id", user.ID),
 			zap.Error(err),
 		)
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -559,7 +561,7 @@ func (h *UserHandler) MarkTutorialComplete(c *gin.Context) {
 func (h *UserHandler) ResetTutorial(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
@@ -567,7 +569,7 @@ func (h *UserHandler) ResetTutorial(c *gin.Context) {
 	user, err := h.userService.GetUserByKeycloakID(c.Request.Context(), userID)
 	if err != nil {
 		h.logger.Error("Failed to get user for tutorial reset", zap.String("keycloak_id", userID), zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -578,7 +580,7 @@ func (h *UserHandler) ResetTutorial(c *gin.Context) {
 			zap.String("user_id", user.ID),
 			zap.Error(err),
 		)
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
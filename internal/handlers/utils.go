@@ -8,8 +8,11 @@ import (
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
+	"github.com/Tributary-ai-services/aether-be/internal/config"
 	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
 	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
 	"github.com/Tributary-ai-services/aether-be/internal/services"
 	"github.com/Tributary-ai-services/aether-be/internal/validation"
 	"github.com/Tributary-ai-services/aether-be/pkg/errors"
@@ -27,12 +30,50 @@ func getUserID(c *gin.Context) string {
 	return ""
 }
 
+// requireUserAndSpace returns the user and space of a request, writing the
+// error response when either is missing
+func requireUserAndSpace(c *gin.Context, log *logger.Logger) (string, *models.SpaceContext, bool) {
+	userID := getUserID(c)
+	if userID == "" {
+		middleware.WriteError(c, log, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
+		return "", nil, false
+	}
+
+	spaceContext, ok := requireSpaceContext(c, log)
+	return userID, spaceContext, ok
+}
+
+// requireInternalUserAndSpace is requireUserAndSpace for handlers that need
+// the internal ID of the user, creating the user on first use
+func requireInternalUserAndSpace(c *gin.Context, userService *services.UserService, log *logger.Logger) (string, *models.SpaceContext, bool) {
+	userID, err := ensureUserExists(c, userService, log)
+	if err != nil {
+		log.Error("Failed to resolve user", zap.Error(err))
+		middleware.WriteError(c, log, err)
+		return "", nil, false
+	}
+
+	spaceContext, ok := requireSpaceContext(c, log)
+	return userID, spaceContext, ok
+}
+
+// requireSpaceContext returns the space of a request, writing the error
+// response when it is missing
+func requireSpaceContext(c *gin.Context, log *logger.Logger) (*models.SpaceContext, bool) {
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		middleware.WriteError(c, log, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return nil, false
+	}
+	return spaceContext, true
+}
+
 // ensureUserExists ensures that the user exists in Neo4j database
 // This is needed because users authenticate via Keycloak but might not exist in Neo4j yet
 func ensureUserExists(c *gin.Context, userService *services.UserService, logger *logger.Logger) (string, error) {
 	keycloakID := getUserID(c)
 	if keycloakID == "" {
-		return "", errors.Unauthorized("User not authenticated")
+		return "", errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated)
 	}
 
 	// Try to find user by Keycloak ID
@@ -111,8 +152,6 @@ func getUserGroups(c *gin.Context) []string {
 }
 
 // getRequestID extracts request ID from the Gin context
-//
-//nolint:unused // Used internally by addResponseMetadata
 func getRequestID(c *gin.Context) string {
 	if requestID, exists := c.Get("request_id"); exists {
 		if id, ok := requestID.(string); ok {
@@ -127,35 +166,6 @@ func validateStruct(s interface{}) error {
 	return validation.Validate(s)
 }
 
-// handleServiceError converts service errors to appropriate HTTP responses
-func handleServiceError(c *gin.Context, err error) {
-	// Check if it's already an API error
-	if apiErr, ok := err.(*errors.APIError); ok {
-		c.JSON(apiErr.StatusCode, apiErr)
-		return
-	}
-
-	// Handle specific error types
-	switch {
-	case errors.IsNotFound(err):
-		c.JSON(http.StatusNotFound, errors.NotFound(err.Error()))
-	case errors.IsForbidden(err):
-		c.JSON(http.StatusForbidden, errors.Forbidden(err.Error()))
-	case errors.IsUnauthorized(err):
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized(err.Error()))
-	case errors.IsValidation(err):
-		c.JSON(http.StatusBadRequest, errors.Validation(err.Error(), err))
-	case errors.IsConflict(err):
-		c.JSON(http.StatusConflict, errors.Conflict(err.Error()))
-	case errors.IsDatabase(err):
-		c.JSON(http.StatusInternalServerError, errors.Internal("Database operation failed"))
-	case errors.IsExternalService(err):
-		c.JSON(http.StatusBadGateway, errors.ExternalService("External service error", err))
-	default:
-		c.JSON(http.StatusInternalServerError, errors.Internal("Internal server error"))
-	}
-}
-
 // sanitizeAndValidate sanitizes and validates a struct
 func sanitizeAndValidate(obj interface{}) (interface{}, error) {
 	// First sanitize based on field types and tags
@@ -262,7 +272,7 @@ func sanitizeBasedOnFieldName(value, fieldName string) string {
 func requireAuth(c *gin.Context) (string, bool) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return "", false
 	}
 	return userID, true
@@ -338,70 +348,20 @@ func addResponseMetadata(c *gin.Context, response interface{}) interface{} {
 }
 
 // PaginationParams represents common pagination parameters
-type PaginationParams struct {
-	Limit  int `json:"limit" validate:"min=1,max=100"`
-	Offset int `json:"offset" validate:"min=0"`
-}
-
-// parsePaginationParams parses limit and offset from query parameters
-//
-//nolint:unused // Utility function for pagination
-func parsePaginationParams(c *gin.Context) PaginationParams {
-	params := PaginationParams{
-		Limit:  20, // default
-		Offset: 0,  // default
-	}
-
-	if limitStr := c.Query("limit"); limitStr != "" {
-		if limit, err := parseIntQueryParam(limitStr, 1, 100); err == nil {
-			params.Limit = limit
-		}
-	}
-
-	if offsetStr := c.Query("offset"); offsetStr != "" {
-		if offset, err := parseIntQueryParam(offsetStr, 0, -1); err == nil {
-			params.Offset = offset
-		}
-	}
-
-	return params
-}
+type PaginationParams = pagination.Params
 
-// parseIntQueryParam parses an integer query parameter with min/max validation
-//
-//nolint:unused // Used internally by parsePaginationParams
-func parseIntQueryParam(value string, min, max int) (int, error) {
-	// Implementation would parse string to int and validate bounds
-	// Simplified for now
-	return 20, nil
+// parsePaginationParams parses limit and offset from query parameters,
+// using defaultLimit when no valid limit is given
+func parsePaginationParams(c *gin.Context, defaultLimit int) PaginationParams {
+	return pagination.FromQuery(c, defaultLimit)
 }
 
 // corsMiddleware adds CORS headers (if needed)
-// customRecoveryMiddleware creates a recovery middleware with detailed panic logging
-func customRecoveryMiddleware(log *logger.Logger) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		defer func() {
-			if err := recover(); err != nil {
-				log.Error("=== PANIC RECOVERED IN REQUEST HANDLER ===",
-					zap.Any("error", err),
-					zap.String("method", c.Request.Method),
-					zap.String("path", c.Request.URL.Path),
-					zap.String("client_ip", c.ClientIP()),
-					zap.Stack("stack"))
-					
-				// Return 502 like Gin's default recovery
-				c.AbortWithStatus(http.StatusBadGateway)
-			}
-		}()
-		c.Next()
-	}
-}
-
 func corsMiddleware() gin.HandlerFunc {
 	return gin.HandlerFunc(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Credentials", "true")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Space-Type, X-Space-ID, X-Tenant-ID")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Space-Type, X-Space-ID, X-Tenant-ID, Idempotency-Key")
 		c.Header("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
@@ -416,6 +376,7 @@ func corsMiddleware() gin.HandlerFunc {
 // debugRequestMiddleware logs ALL incoming requests at the very first stage
 func debugRequestMiddleware(log *logger.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		log := log.FromContext(c.Request.Context())
 		log.Info("=== RAW REQUEST DEBUG ===",
 			zap.String("method", c.Request.Method),
 			zap.String("url", c.Request.URL.String()),
@@ -437,9 +398,28 @@ func debugRequestMiddleware(log *logger.Logger) gin.HandlerFunc {
 	}
 }
 
-// requestLoggingMiddleware logs HTTP requests
-func requestLoggingMiddleware() gin.HandlerFunc {
-	return gin.LoggerWithConfig(gin.LoggerConfig{
-		SkipPaths: []string{"/health/live", "/health/ready"},
-	})
+// newAccessLogExporter returns the exporter for the configured access log
+// export target, or nil when export is off or the target is unavailable
+func newAccessLogExporter(cfg *config.Config, kafkaService *services.KafkaService, storageService *services.S3StorageService, log *logger.Logger) *services.AccessLogExporter {
+	var sink services.AccessLogSink
+	switch cfg.AccessLog.Export {
+	case "kafka":
+		if kafkaService == nil {
+			log.Warn("Access log export to Kafka configured but Kafka is unavailable")
+			return nil
+		}
+		sink = services.NewKafkaAccessLogSink(kafkaService, cfg.AccessLog.KafkaTopic)
+	case "s3":
+		if storageService == nil {
+			log.Warn("Access log export to S3 configured but storage is unavailable")
+			return nil
+		}
+		sink = services.NewS3AccessLogSink(storageService, cfg.AccessLog.S3Prefix, cfg.Cluster.InstanceID)
+	default:
+		return nil
+	}
+
+	exporter := services.NewAccessLogExporter(sink, cfg.AccessLog, log)
+	exporter.Start()
+	return exporter
 }
@@ -27,6 +27,7 @@ type VectorSearchHandler struct {
 	documentService *services.DocumentService
 	userService     *services.UserService
 	deeplakeConfig  *config.DeepLakeConfig
+	semantic        *services.VectorSearchService
 	httpClient      *http.Client
 	logger          *logger.Logger
 }
@@ -45,12 +46,19 @@ func NewVectorSearchHandler(
 		userService:     userService,
 		deeplakeConfig:  deeplakeConfig,
 		httpClient: &http.Client{
-			Timeout: time.Duration(deeplakeConfig.TimeoutSeconds) * time.Second,
+			Timeout:   time.Duration(deeplakeConfig.TimeoutSeconds) * time.Second,
+			Transport: logger.NewRequestIDTransport(nil),
 		},
 		logger: log.WithService("vector_search_handler"),
 	}
 }
 
+// SetVectorSearchService enables space-wide semantic search; without it
+// it responds 503
+func (h *VectorSearchHandler) SetVectorSearchService(semantic *services.VectorSearchService) {
+	h.semantic = semantic
+}
+
 // TextSearchRequest represents a text-based vector search request
 type TextSearchRequest struct {
 	QueryText string        `json:"query_text" binding:"required"`
@@ -121,7 +129,7 @@ func (h *VectorSearchHandler) TextSearch(c *gin.Context) {
 	// Get space context for tenant ID
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -129,14 +137,14 @@ func (h *VectorSearchHandler) TextSearch(c *gin.Context) {
 	userID, err := ensureUserExists(c, h.userService, h.logger)
 	if err != nil {
 		h.logger.Error("Failed to resolve user", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
 	notebook, err := h.notebookService.GetNotebookByID(c.Request.Context(), notebookID, userID, spaceContext)
 	if err != nil {
 		h.logger.Error("Failed to get notebook", zap.Error(err), zap.String("notebook_id", notebookID))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -224,7 +232,7 @@ func (h *VectorSearchHandler) TextSearch(c *gin.Context) {
 			zap.String("notebook_id", notebookID),
 			zap.String("dataset_id", datasetID),
 		)
-		c.JSON(http.StatusInternalServerError, errors.InternalWithCause("Vector search failed", err))
+		middleware.WriteError(c, h.logger, errors.InternalWithCause("Vector search failed", err))
 		return
 	}
 
@@ -263,7 +271,7 @@ func (h *VectorSearchHandler) HybridSearch(c *gin.Context) {
 	// Get space context for tenant ID
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -271,14 +279,14 @@ func (h *VectorSearchHandler) HybridSearch(c *gin.Context) {
 	userID, err := ensureUserExists(c, h.userService, h.logger)
 	if err != nil {
 		h.logger.Error("Failed to resolve user", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
 	_, err = h.notebookService.GetNotebookByID(c.Request.Context(), notebookID, userID, spaceContext)
 	if err != nil {
 		h.logger.Error("Failed to get notebook", zap.Error(err), zap.String("notebook_id", notebookID))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -369,7 +377,7 @@ func (h *VectorSearchHandler) HybridSearch(c *gin.Context) {
 			zap.String("notebook_id", notebookID),
 			zap.String("dataset_id", datasetID),
 		)
-		c.JSON(http.StatusInternalServerError, errors.InternalWithCause("Hybrid search failed", err))
+		middleware.WriteError(c, h.logger, errors.InternalWithCause("Hybrid search failed", err))
 		return
 	}
 
@@ -400,7 +408,7 @@ func (h *VectorSearchHandler) GetVectorSearchInfo(c *gin.Context) {
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -408,14 +416,14 @@ func (h *VectorSearchHandler) GetVectorSearchInfo(c *gin.Context) {
 	userID, err := ensureUserExists(c, h.userService, h.logger)
 	if err != nil {
 		h.logger.Error("Failed to resolve user", zap.Error(err))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
 	notebook, err := h.notebookService.GetNotebookByID(c.Request.Context(), notebookID, userID, spaceContext)
 	if err != nil {
 		h.logger.Error("Failed to get notebook", zap.Error(err), zap.String("notebook_id", notebookID))
-		handleServiceError(c, err)
+		middleware.WriteError(c, h.logger, err)
 		return
 	}
 
@@ -682,3 +690,53 @@ func isDatasetNotFoundError(err error) bool {
 		strings.Contains(errStr, "Dataset not found") ||
 		strings.Contains(errStr, "not found")
 }
+
+// SemanticSearch searches the chunks of all documents in a space
+// @Summary Semantic search in a space
+// @Description Search the chunks indexed in the space's DeepLake namespace by meaning. Mode vector ranks by embedding similarity; hybrid blends it with keyword matches using vector_weight. Each hit carries the chunk text and the document it belongs to; chunks of deleted documents, or of notebooks outside notebook_ids, are left out.
+// @Tags vector-search
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Space ID"
+// @Param request body models.SemanticSearchRequest true "Search request"
+// @Success 200 {object} models.SemanticSearchResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 502 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/spaces/{id}/search/semantic [post]
+func (h *VectorSearchHandler) SemanticSearch(c *gin.Context) {
+	if h.semantic == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Vector search is disabled").WithErrorCode(errors.CodeVectorSearchDisabled))
+		return
+	}
+
+	userID, err := ensureUserExists(c, h.userService, h.logger)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	var req models.SemanticSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Invalid request body", err))
+		return
+	}
+	req.Query = strings.TrimSpace(req.Query)
+	if err := validateStruct(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Validation failed", err))
+		return
+	}
+
+	response, err := h.semantic.SemanticSearch(c.Request.Context(), c.Param("id"), userID, req)
+	if err != nil {
+		h.logger.Error("Semantic search failed", zap.String("space_id", c.Param("id")), zap.Error(err))
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// GetDocumentVectorSync returns how far a document's chunk embeddings have
+// been pushed to DeepLake
+// @Summary Get document vector sync status
+// @Description Get whether the chunk embeddings of a document are stored in its space's DeepLake namespace. Status is none (not queued), pending, syncing, synced, failed (attempts used up) or deleting.
+// @Tags documents
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Document ID"
+// @Success 200 {object} models.DocumentVectorSync
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/documents/{id}/vector-sync [get]
+func (h *DocumentHandler) GetDocumentVectorSync(c *gin.Context) {
+	h.handleVectorSync(c, false)
+}
+
+// ResyncDocumentVectors queues a document's chunk embeddings to be pushed
+// to DeepLake again
+// @Summary Resync document vectors
+// @Description Queue a processed document for a vector sync due now, for example after it failed. The sync runs in the background; poll GET /documents/{id}/vector-sync for the outcome.
+// @Tags documents
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Document ID"
+// @Success 202 {object} models.DocumentVectorSync
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 409 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/documents/{id}/vector-sync [post]
+func (h *DocumentHandler) ResyncDocumentVectors(c *gin.Context) {
+	h.handleVectorSync(c, true)
+}
+
+// handleVectorSync serves both vector sync endpoints, queueing a sync
+// first when resync is set
+func (h *DocumentHandler) handleVectorSync(c *gin.Context, resync bool) {
+	if h.vectorSync == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Vector sync is disabled").WithErrorCode(errors.CodeVectorSyncDisabled))
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == "" {
+		middleware.WriteError(c, h.logger, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
+		return
+	}
+
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return
+	}
+
+	var status *models.DocumentVectorSync
+	if resync {
+		status, err = h.vectorSync.ResyncDocument(c.Request.Context(), c.Param("id"), userID, spaceContext)
+	} else {
+		status, err = h.vectorSync.GetDocumentSync(c.Request.Context(), c.Param("id"), userID, spaceContext)
+	}
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	if resync {
+		c.JSON(http.StatusAccepted, status)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
@@ -0,0 +1,257 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
+	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// WebhookSubscriptionHandler manages the outbound webhooks of spaces
+type WebhookSubscriptionHandler struct {
+	webhookService *services.WebhookService
+	logger         *logger.Logger
+}
+
+// NewWebhookSubscriptionHandler creates a new webhook subscription handler
+func NewWebhookSubscriptionHandler(webhookService *services.WebhookService, log *logger.Logger) *WebhookSubscriptionHandler {
+	return &WebhookSubscriptionHandler{
+		webhookService: webhookService,
+		logger:         log.WithService("webhook_subscription_handler"),
+	}
+}
+
+// spaceContext returns the space of a request, writing the error response
+// when the user or space is missing
+func (h *WebhookSubscriptionHandler) spaceContext(c *gin.Context) (*models.SpaceContext, bool) {
+	if getUserID(c) == "" {
+		middleware.WriteError(c, h.logger, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
+		return nil, false
+	}
+
+	spaceContext, err := middleware.GetSpaceContext(c)
+	if err != nil {
+		middleware.WriteError(c, h.logger, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
+		return nil, false
+	}
+	return spaceContext, true
+}
+
+// CreateWebhookSubscription subscribes a URL to events of the current space
+// @Summary Create a webhook subscription
+// @Description Subscribe a URL to events of the current space: document.processed, document.failed, member.added, agent.executed and quota.threshold_reached. Each event is POSTed as JSON with X-Webhook-ID, X-Webhook-Event, X-Webhook-Timestamp and X-Webhook-Signature headers; the signature is "v1=" and the hex HMAC-SHA256 of "<id>.<timestamp>.<body>" keyed with the subscription's secret, which is only returned here. A delivery not answered with 2xx is retried with exponential backoff from 30 seconds up to an hour, keeping its X-Webhook-ID. The URL must be http or https and, unless the server allows private targets, resolve to public addresses only; it fails with 400 and AETHER-HOOK-005 otherwise. A space has at most 20 subscriptions. Requires the owner or admin role.
+// @Tags spaces
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body models.WebhookSubscriptionCreateRequest true "Webhook subscription"
+// @Success 201 {object} models.WebhookSubscription
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Router /api/v1/webhook-subscriptions [post]
+func (h *WebhookSubscriptionHandler) CreateWebhookSubscription(c *gin.Context) {
+	spaceContext, ok := h.spaceContext(c)
+	if !ok {
+		return
+	}
+
+	var req models.WebhookSubscriptionCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Invalid request body", err))
+		return
+	}
+	if err := validateStruct(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Validation failed", err))
+		return
+	}
+
+	subscription, err := h.webhookService.CreateSubscription(c.Request.Context(), req, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, subscription)
+}
+
+// ListWebhookSubscriptions lists the webhook subscriptions of the current
+// space
+// @Summary List webhook subscriptions
+// @Description List the webhook subscriptions of the current space, oldest first, with the outcome of their latest delivery. Secrets are not returned. Requires the owner or admin role.
+// @Tags spaces
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.WebhookSubscriptionList
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Router /api/v1/webhook-subscriptions [get]
+func (h *WebhookSubscriptionHandler) ListWebhookSubscriptions(c *gin.Context) {
+	spaceContext, ok := h.spaceContext(c)
+	if !ok {
+		return
+	}
+
+	list, err := h.webhookService.ListSubscriptions(c.Request.Context(), spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// GetWebhookSubscription returns a webhook subscription
+// @Summary Get a webhook subscription
+// @Description Get a webhook subscription of the current space. Fails with 404 and AETHER-HOOK-004 when the subscription does not exist. Requires the owner or admin role.
+// @Tags spaces
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Webhook subscription ID"
+// @Success 200 {object} models.WebhookSubscription
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/webhook-subscriptions/{id} [get]
+func (h *WebhookSubscriptionHandler) GetWebhookSubscription(c *gin.Context) {
+	spaceContext, ok := h.spaceContext(c)
+	if !ok {
+		return
+	}
+
+	subscription, err := h.webhookService.GetSubscription(c.Request.Context(), c.Param("id"), spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, subscription)
+}
+
+// UpdateWebhookSubscription changes a webhook subscription
+// @Summary Update a webhook subscription
+// @Description Change the URL, events, description or state of a webhook subscription of the current space; omitted fields are unchanged. A paused subscription (active false) gets no new deliveries, and the ones already queued are sent once it is active again. Fails with 404 and AETHER-HOOK-004 when the subscription does not exist, and with 400 and AETHER-HOOK-005 when the URL is not allowed. Requires the owner or admin role.
+// @Tags spaces
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Webhook subscription ID"
+// @Param request body models.WebhookSubscriptionUpdateRequest true "Changes"
+// @Success 200 {object} models.WebhookSubscription
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/webhook-subscriptions/{id} [put]
+func (h *WebhookSubscriptionHandler) UpdateWebhookSubscription(c *gin.Context) {
+	spaceContext, ok := h.spaceContext(c)
+	if !ok {
+		return
+	}
+
+	var req models.WebhookSubscriptionUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Invalid request body", err))
+		return
+	}
+	if err := validateStruct(&req); err != nil {
+		middleware.WriteError(c, h.logger, errors.Validation("Validation failed", err))
+		return
+	}
+
+	subscription, err := h.webhookService.UpdateSubscription(c.Request.Context(), c.Param("id"), req, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, subscription)
+}
+
+// DeleteWebhookSubscription deletes a webhook subscription
+// @Summary Delete a webhook subscription
+// @Description Delete a webhook subscription of the current space with its delivery log; queued deliveries are not sent. Fails with 404 and AETHER-HOOK-004 when the subscription does not exist. Requires the owner or admin role.
+// @Tags spaces
+// @Security Bearer
+// @Param id path string true "Webhook subscription ID"
+// @Success 204
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/webhook-subscriptions/{id} [delete]
+func (h *WebhookSubscriptionHandler) DeleteWebhookSubscription(c *gin.Context) {
+	spaceContext, ok := h.spaceContext(c)
+	if !ok {
+		return
+	}
+
+	if err := h.webhookService.DeleteSubscription(c.Request.Context(), c.Param("id"), spaceContext); err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListWebhookDeliveries lists the deliveries of a webhook subscription
+// @Summary List webhook deliveries
+// @Description List one page of a subscription's deliveries, newest first, with the subscriber's answer to the latest attempt (its body cut at 1 KiB). Deliveries that succeeded or failed are kept for 30 days. Fails with 404 and AETHER-HOOK-004 when the subscription does not exist. Requires the owner or admin role.
+// @Tags spaces
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Webhook subscription ID"
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param offset query int false "Page offset" default(0)
+// @Success 200 {object} models.WebhookDeliveryList
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/webhook-subscriptions/{id}/deliveries [get]
+func (h *WebhookSubscriptionHandler) ListWebhookDeliveries(c *gin.Context) {
+	spaceContext, ok := h.spaceContext(c)
+	if !ok {
+		return
+	}
+
+	page := parsePaginationParams(c, pagination.DefaultLimit)
+	list, err := h.webhookService.ListDeliveries(c.Request.Context(), c.Param("id"), page.Limit, page.Offset, spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// TestWebhookSubscription sends a test event to a webhook subscription
+// @Summary Send a test webhook
+// @Description Send a webhook.test event to a subscription of the current space once, whether it is active or paused, and return the recorded delivery with the subscriber's answer. The test is not retried; a subscriber that does not answer 2xx yields a delivery with status failed. Fails with 404 and AETHER-HOOK-004 when the subscription does not exist. Requires the owner or admin role.
+// @Tags spaces
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Webhook subscription ID"
+// @Success 200 {object} models.WebhookDelivery
+// @Failure 401 {object} errors.APIError
+// @Failure 403 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/webhook-subscriptions/{id}/test [post]
+func (h *WebhookSubscriptionHandler) TestWebhookSubscription(c *gin.Context) {
+	spaceContext, ok := h.spaceContext(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.TestSubscription(c.Request.Context(), c.Param("id"), spaceContext)
+	if err != nil {
+		middleware.WriteError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
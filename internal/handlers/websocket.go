@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
@@ -11,8 +12,10 @@ import (
 	"github.com/gorilla/websocket"
 	"go.uber.org/zap"
 
+	"github.com/Tributary-ai-services/aether-be/internal/i18n"
 	"github.com/Tributary-ai-services/aether-be/internal/logger"
 	"github.com/Tributary-ai-services/aether-be/internal/middleware"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
 	"github.com/Tributary-ai-services/aether-be/internal/services"
 	"github.com/Tributary-ai-services/aether-be/pkg/errors"
 )
@@ -21,10 +24,14 @@ import (
 type WebSocketHandler struct {
 	documentService   *services.DocumentService
 	audiModalService  *services.AudiModalService
+	hub               *services.EventHub
+	spaceContexts     spaceContextResolver
+	teams             userTeamsLookup
 	logger           *logger.Logger
 	upgrader         websocket.Upgrader
 	connections      map[string]*WebSocketConnection // jobID -> connection
 	connectionsMux   sync.RWMutex
+	sessions         *eventSessions // Unified event stream sessions
 }
 
 // WebSocketConnection represents a WebSocket connection tracking a specific job
@@ -63,15 +70,31 @@ func NewWebSocketHandler(documentService *services.DocumentService, audiModalSer
 			WriteBufferSize: 1024,
 		},
 		connections: make(map[string]*WebSocketConnection),
+		sessions:    newEventSessions(),
 	}
 }
 
+// SetEventHub makes document status streams push changes as they are
+// published instead of polling Neo4j, and serves the unified event stream
+func (h *WebSocketHandler) SetEventHub(hub *services.EventHub) {
+	h.hub = hub
+}
+
+// SetSubscriptionServices sets the services the unified event stream
+// checks each subscription against
+func (h *WebSocketHandler) SetSubscriptionServices(spaceContexts spaceContextResolver, teams userTeamsLookup) {
+	h.spaceContexts = spaceContexts
+	h.teams = teams
+}
+
 // StreamJobStatus handles WebSocket connections for real-time job status updates
 // @Summary Stream job status updates
 // @Description Get real-time status updates for a job via WebSocket
 // @Tags websocket
 // @Security Bearer
 // @Param id path string true "Job ID"
+// @Success 101 "Switching Protocols"
+// @Failure 401 {object} errors.APIError
 // @Router /api/v1/jobs/{id}/stream [get]
 func (h *WebSocketHandler) StreamJobStatus(c *gin.Context) {
 	jobID := c.Param("id")
@@ -82,14 +105,14 @@ func (h *WebSocketHandler) StreamJobStatus(c *gin.Context) {
 
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -242,24 +265,27 @@ func (h *WebSocketHandler) getJobStatus(ctx context.Context, jobID string, tenan
 // @Tags websocket
 // @Security Bearer
 // @Param id path string true "Document ID"
+// @Success 101 "Switching Protocols"
+// @Failure 401 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
 // @Router /api/v1/documents/{id}/stream [get]
 func (h *WebSocketHandler) StreamDocumentStatus(c *gin.Context) {
 	documentID := c.Param("id")
 	if documentID == "" {
-		c.JSON(http.StatusBadRequest, errors.Validation("Document ID is required", nil))
+		c.JSON(http.StatusBadRequest, errors.Validation("Document ID is required", nil).WithErrorCode(errors.CodeDocumentIDRequired))
 		return
 	}
 
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -267,10 +293,15 @@ func (h *WebSocketHandler) StreamDocumentStatus(c *gin.Context) {
 		zap.String("document_id", documentID), 
 		zap.String("user_id", userID))
 
+	if h.hub != nil {
+		h.streamDocumentEvents(c, documentID, userID, spaceContext)
+		return
+	}
+
 	// Get document to find processing job ID
 	document, err := h.documentService.GetDocumentByID(c.Request.Context(), documentID, userID, spaceContext)
 	if err != nil {
-		c.JSON(http.StatusNotFound, errors.NotFound("Document not found"))
+		c.JSON(http.StatusNotFound, errors.NotFound("Document not found").WithErrorCode(errors.CodeDocumentNotFound))
 		return
 	}
 
@@ -330,4 +361,453 @@ func (h *WebSocketHandler) StreamDocumentStatus(c *gin.Context) {
 			}
 		}
 	}
+}
+// streamDocumentEvents streams a document's status from the event hub. The
+// document is read once after subscribing, so a change made in between is
+// either in that snapshot or delivered as an event; it is read again only
+// when processing finishes, to report its outcome.
+func (h *WebSocketHandler) streamDocumentEvents(c *gin.Context, documentID, userID string, spaceContext *models.SpaceContext) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	events, err := h.hub.Subscribe(ctx, spaceContext.TenantID, services.HubTopicDocumentStatus)
+	if err != nil {
+		h.logger.Error("Failed to subscribe to document status events", zap.Error(err))
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Document status updates are unavailable"))
+		return
+	}
+
+	document, err := h.documentService.GetDocumentByID(ctx, documentID, userID, spaceContext)
+	if err != nil {
+		c.JSON(http.StatusNotFound, errors.NotFound("Document not found").WithErrorCode(errors.CodeDocumentNotFound))
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		h.logger.Error("Failed to upgrade WebSocket connection", zap.Error(err))
+		return
+	}
+	defer conn.Close()
+
+	// The request context outlives a hijacked connection; reading is how a
+	// client disconnect is noticed
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	if err := conn.WriteJSON(documentStatusMessage(document)); err != nil || isFinalDocumentStatus(document.Status) {
+		return
+	}
+
+	for event := range events {
+		if event.ResourceID != documentID {
+			continue
+		}
+
+		if isFinalDocumentStatus(event.Status) {
+			if document, err := h.documentService.GetDocumentByID(ctx, documentID, userID, spaceContext); err == nil {
+				conn.WriteJSON(documentStatusMessage(document))
+				return
+			}
+		}
+
+		message := WebSocketMessage{
+			Type:      "document_status_update",
+			JobID:     documentID,
+			Status:    event.Status,
+			Progress:  calculateProgress(event.Status),
+			Data:      map[string]interface{}{"document_id": documentID, "status": event.Status},
+			Timestamp: event.Timestamp,
+		}
+		if errorMsg, ok := event.Data["error"]; ok {
+			message.Error, _ = errorMsg.(string)
+		}
+		if err := conn.WriteJSON(message); err != nil || isFinalDocumentStatus(event.Status) {
+			return
+		}
+	}
+}
+
+// documentStatusMessage describes a document's stored status
+func documentStatusMessage(doc *models.Document) WebSocketMessage {
+	return WebSocketMessage{
+		Type:     "document_status_update",
+		JobID:    doc.ID,
+		Status:   doc.Status,
+		Progress: calculateProgress(doc.Status),
+		Data: map[string]interface{}{
+			"document_id":      doc.ID,
+			"status":           doc.Status,
+			"chunk_count":      doc.ChunkCount,
+			"processing_time":  doc.ProcessingTime,
+			"confidence_score": doc.ConfidenceScore,
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// isFinalDocumentStatus reports whether processing of a document in status
+// has finished, after which its stream closes
+func isFinalDocumentStatus(status string) bool {
+	return status == "processed" || status == "failed"
+}
+
+// spaceContextResolver resolves the space a subscription names for the
+// connected user
+type spaceContextResolver interface {
+	ResolveSpaceContext(ctx context.Context, userID string, req models.SpaceContextRequest) (*models.SpaceContext, error)
+}
+
+// userTeamsLookup finds the teams whose agents a user may see
+type userTeamsLookup interface {
+	GetUserTeamIDs(ctx context.Context, userID string) ([]string, error)
+}
+
+// Unified event stream topics and the event hub topics they carry
+var eventStreamTopics = map[string]string{
+	"documents":     services.HubTopicDocumentStatus,
+	"notebooks":     services.HubTopicNotebookChanged,
+	"agents":        services.HubTopicAgentChanged,
+	"streams":       services.HubTopicStreamEvent,
+	"jobs":          services.HubTopicJobProgress,
+	"notifications": services.HubTopicNotification,
+}
+
+// Unified event stream limits
+const (
+	eventStreamPingInterval = 30 * time.Second
+	eventStreamPongWait     = 60 * time.Second
+	eventStreamWriteWait    = 10 * time.Second
+	eventStreamSendBuffer   = 64
+	maxEventSubscriptions   = 20
+)
+
+// Close code sent when the token the connection was opened with expires
+const closeTokenExpired = 4001
+
+// EventControlMessage is a command sent by a client of the unified event
+// stream
+type EventControlMessage struct {
+	Action    string `json:"action"`           // subscribe, unsubscribe or ping
+	ID        string `json:"id,omitempty"`     // Echoed in the reply
+	Topic     string `json:"topic,omitempty"`  // documents, notebooks, agents, streams, jobs or notifications
+	SpaceType string `json:"space_type,omitempty"`
+	SpaceID   string `json:"space_id,omitempty"`
+}
+
+// EventStreamMessage is sent to clients of the unified event stream
+type EventStreamMessage struct {
+	Type         string             `json:"type"` // session, subscribed, unsubscribed, event, heartbeat, pong or error
+	ID           string             `json:"id,omitempty"`
+	Seq          int64              `json:"seq,omitempty"` // Number of an event; of the latest event in a session message
+	Topic        string             `json:"topic,omitempty"`
+	SpaceID      string             `json:"space_id,omitempty"`
+	Event        *services.HubEvent `json:"event,omitempty"`
+	ResumeToken  string             `json:"resume_token,omitempty"`
+	Resumed      bool               `json:"resumed,omitempty"`
+	EventsMissed bool               `json:"events_missed,omitempty"` // Some events since last_seq are no longer kept
+	Error        string             `json:"error,omitempty"`
+	ErrorCode    string             `json:"error_code,omitempty"`
+	Timestamp    time.Time          `json:"timestamp"`
+}
+
+// eventSubscription is one topic of one space a connection receives
+type eventSubscription struct {
+	topic    string
+	spaceCtx *models.SpaceContext
+	teams    []string // Teams of the user, for agent visibility
+	locale   string   // Locale notifications are delivered in
+}
+
+// SubscribeEvents serves the unified event stream
+// @Summary Stream real-time events
+// @Description One WebSocket for all real-time updates. The first message is a session message with a resume_token. Send {"action":"subscribe","topic":"documents","space_type":"personal","space_id":"..."} to receive a space's events; topics are documents (processing status), notebooks and agents (created, updated, deleted), streams (live events), jobs (processing job progress) and notifications (addressed to the user, such as a document of theirs finishing processing). Send unsubscribe with the same topic and space to stop, or ping to get a pong. Each subscription is checked against the user's access to the space. Events are numbered by seq. A client that reconnects within 2 minutes with resume_token and the last seq it saw keeps its subscriptions and receives the events it missed, with events_missed set when some are no longer kept; otherwise resumed is false and it must subscribe again. Browsers that cannot set headers may pass the token as the access_token query parameter. The server sends a heartbeat message and a ping every 30 seconds and closes the connection with code 4001 when the token expires.
+// @Tags websocket
+// @Security Bearer
+// @Param access_token query string false "Bearer token, for clients that cannot set the Authorization header"
+// @Param resume_token query string false "Resume token of the session to resume"
+// @Param last_seq query int false "Last event seq received in the resumed session"
+// @Success 101 "Switching Protocols"
+// @Failure 400 {object} errors.APIError
+// @Failure 401 {object} errors.APIError
+// @Failure 503 {object} errors.APIError
+// @Router /api/v1/ws/events [get]
+func (h *WebSocketHandler) SubscribeEvents(c *gin.Context) {
+	if h.hub == nil || h.spaceContexts == nil {
+		middleware.WriteError(c, h.logger, errors.ServiceUnavailable("Real-time events are unavailable"))
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == "" {
+		middleware.WriteError(c, h.logger, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
+		return
+	}
+
+	var lastSeq int64
+	if value := c.Query("last_seq"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed < 0 {
+			middleware.WriteError(c, h.logger, errors.ValidationWithDetails("Invalid last_seq", map[string]interface{}{
+				"param": "last_seq",
+			}))
+			return
+		}
+		lastSeq = parsed
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	if claims, ok := middleware.GetUserClaims(c); ok && claims.Exp > 0 {
+		var cancelAtExpiry context.CancelFunc
+		ctx, cancelAtExpiry = context.WithDeadline(ctx, time.Unix(claims.Exp, 0))
+		defer cancelAtExpiry()
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		h.logger.Error("Failed to upgrade WebSocket connection", zap.Error(err))
+		return
+	}
+	defer conn.Close()
+
+	session, resumed := h.sessions.open(c.Request.Context(), userID, c.Query("resume_token"))
+	h.logger.Info("Unified event stream connected", zap.String("user_id", userID), zap.Bool("resumed", resumed))
+	defer h.logger.Info("Unified event stream closed", zap.String("user_id", userID))
+
+	send := make(chan EventStreamMessage, eventStreamSendBuffer)
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		h.writeEventStream(ctx, c.Request.Context(), cancel, conn, send)
+	}()
+
+	session.attach(send, ctx.Done(), cancel, resumed, lastSeq)
+	h.readEventStream(ctx, conn, session, send)
+	cancel()
+	<-written
+	h.sessions.detach(session, send)
+}
+
+// writeEventStream is the only writer of conn. It sends queued messages,
+// and pings with a heartbeat message for clients that cannot see pings,
+// until ctx is done, then closes the connection with a reason:
+// the token expired, the server is draining (request ended) or the client
+// went away.
+func (h *WebSocketHandler) writeEventStream(ctx, request context.Context, cancel context.CancelFunc, conn *websocket.Conn, send <-chan EventStreamMessage) {
+	ping := time.NewTicker(eventStreamPingInterval)
+	defer ping.Stop()
+	// Closing the connection ends the read loop
+	defer conn.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			code, reason := websocket.CloseNormalClosure, ""
+			switch {
+			case ctx.Err() == context.DeadlineExceeded:
+				code, reason = closeTokenExpired, "token expired"
+			case request.Err() != nil:
+				code, reason = websocket.CloseGoingAway, "server shutting down"
+			}
+			_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason),
+				time.Now().Add(eventStreamWriteWait))
+			return
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(eventStreamWriteWait)); err != nil {
+				cancel()
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(eventStreamWriteWait))
+			if err := conn.WriteJSON(EventStreamMessage{Type: "heartbeat", Timestamp: time.Now()}); err != nil {
+				cancel()
+				return
+			}
+		case message := <-send:
+			conn.SetWriteDeadline(time.Now().Add(eventStreamWriteWait))
+			if err := conn.WriteJSON(message); err != nil {
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// readEventStream handles control messages until the client goes away or
+// ctx is done. Subscriptions belong to the session and outlive it.
+func (h *WebSocketHandler) readEventStream(ctx context.Context, conn *websocket.Conn, session *eventSession, send chan<- EventStreamMessage) {
+	userID := session.userID
+	conn.SetReadDeadline(time.Now().Add(eventStreamPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(eventStreamPongWait))
+	})
+
+	reply := func(message EventStreamMessage) bool {
+		message.Timestamp = time.Now()
+		select {
+		case send <- message:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	for {
+		var control EventControlMessage
+		if err := conn.ReadJSON(&control); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure, websocket.CloseNoStatusReceived) && ctx.Err() == nil {
+				h.logger.Debug("Unified event stream read failed", zap.String("user_id", userID), zap.Error(err))
+			}
+			return
+		}
+		conn.SetReadDeadline(time.Now().Add(eventStreamPongWait))
+
+		key := control.Topic + "\x00" + control.SpaceID
+		response := EventStreamMessage{ID: control.ID, Topic: control.Topic, SpaceID: control.SpaceID}
+		switch control.Action {
+		case "ping":
+			response.Type = "pong"
+		case "unsubscribe":
+			session.unsubscribe(key)
+			response.Type = "unsubscribed"
+		case "subscribe":
+			if session.subscribed(key) {
+				response.Type = "subscribed"
+				break
+			}
+			if session.subscriptionCount() >= maxEventSubscriptions {
+				response.Type, response.Error = "error", fmt.Sprintf("At most %d subscriptions per connection", maxEventSubscriptions)
+				break
+			}
+			subscription, err := h.resolveSubscription(ctx, userID, control)
+			if err != nil {
+				apiErr := i18n.LocalizeError(i18n.FromContext(ctx), errors.Normalize(err))
+				response.Type, response.Error, response.ErrorCode = "error", apiErr.Message, apiErr.ErrorCode
+				break
+			}
+			subCtx, unsubscribe := context.WithCancel(session.ctx)
+			if err := h.forwardSubscription(subCtx, subscription, control, session); err != nil {
+				unsubscribe()
+				h.logger.Error("Failed to subscribe to event hub", zap.String("topic", control.Topic), zap.Error(err))
+				response.Type, response.Error = "error", "Real-time events are unavailable"
+				break
+			}
+			session.subscribe(key, unsubscribe)
+			response.Type = "subscribed"
+		default:
+			response.Type, response.Error = "error", "Unknown action "+control.Action
+		}
+		if !reply(response) {
+			return
+		}
+	}
+}
+
+// resolveSubscription checks the user may read the space a subscription
+// names. The space decides the tenant whose events are received.
+func (h *WebSocketHandler) resolveSubscription(ctx context.Context, userID string, control EventControlMessage) (*eventSubscription, error) {
+	if _, ok := eventStreamTopics[control.Topic]; !ok {
+		return nil, errors.BadRequest("Unknown topic " + control.Topic + "; use documents, notebooks, agents, streams, jobs or notifications")
+	}
+	if control.SpaceType == "" || control.SpaceID == "" {
+		return nil, errors.BadRequest("space_type and space_id are required").WithErrorCode(errors.CodeSpaceContextRequired)
+	}
+
+	spaceCtx, err := h.spaceContexts.ResolveSpaceContext(ctx, userID, models.SpaceContextRequest{
+		SpaceType: models.SpaceType(control.SpaceType),
+		SpaceID:   control.SpaceID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !spaceCtx.CanRead() {
+		return nil, errors.Forbidden("Access to space denied").WithErrorCode(errors.CodeSpaceAccessDenied)
+	}
+
+	subscription := &eventSubscription{topic: control.Topic, spaceCtx: spaceCtx, locale: i18n.FromContext(ctx)}
+	if control.Topic == "agents" && h.teams != nil {
+		// Without teams the user still sees their own and public agents
+		subscription.teams, err = h.teams.GetUserTeamIDs(ctx, spaceCtx.UserID)
+		if err != nil {
+			h.logger.Warn("Failed to get user team IDs for agent events", zap.String("user_id", spaceCtx.UserID), zap.Error(err))
+		}
+	}
+	return subscription, nil
+}
+
+// forwardSubscription delivers the events of a subscription the user may
+// see to the session until ctx is done
+func (h *WebSocketHandler) forwardSubscription(ctx context.Context, subscription *eventSubscription, control EventControlMessage, session *eventSession) error {
+	events, err := h.hub.Subscribe(ctx, subscription.spaceCtx.TenantID, eventStreamTopics[subscription.topic])
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for event := range events {
+			if !subscription.allows(event) {
+				continue
+			}
+			subscription.localize(event)
+			session.deliver(EventStreamMessage{Type: "event", Topic: control.Topic, SpaceID: control.SpaceID, Event: event, Timestamp: time.Now()})
+		}
+	}()
+	return nil
+}
+
+// allows reports whether an event of the subscription's tenant may be
+// delivered: it must belong to the subscribed space, a notification must
+// be addressed to the user, and an agent must be one the user can access
+func (s *eventSubscription) allows(event *services.HubEvent) bool {
+	if event.TenantID != s.spaceCtx.TenantID {
+		return false
+	}
+	if spaceID, ok := event.Data["space_id"].(string); ok && spaceID != "" && spaceID != s.spaceCtx.SpaceID {
+		return false
+	}
+	if s.topic == "notifications" {
+		userID, _ := event.Data["user_id"].(string)
+		return userID == s.spaceCtx.UserID
+	}
+	if s.topic != "agents" {
+		return true
+	}
+
+	ownerID, _ := event.Data["owner_id"].(string)
+	teamID, _ := event.Data["team_id"].(string)
+	spaceType, _ := event.Data["space_type"].(string)
+	isPublic, _ := event.Data["is_public"].(bool)
+	agent := &models.Agent{OwnerID: ownerID, TeamID: teamID, SpaceType: models.SpaceType(spaceType), IsPublic: isPublic}
+	return agent.CanBeAccessedBy(s.spaceCtx.UserID, s.teams)
+}
+
+// localize rewrites the message of a notification in the locale the
+// subscription was made in, from the arguments it was published with.
+// Notifications without arguments keep their English message.
+func (s *eventSubscription) localize(event *services.HubEvent) {
+	if s.topic != "notifications" || s.locale == i18n.DefaultLocale {
+		return
+	}
+	var args map[string]string
+	switch raw := event.Data["message_args"].(type) {
+	case map[string]string:
+		args = raw
+	case map[string]interface{}:
+		// Decoded from JSON
+		args = make(map[string]string, len(raw))
+		for name, value := range raw {
+			if text, ok := value.(string); ok {
+				args[name] = text
+			}
+		}
+	default:
+		return
+	}
+	event.Data["message"] = i18n.Notification(s.locale, event.Status, args)
 }
\ No newline at end of file
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Unified event stream sessions
+const (
+	// eventSessionResumeWindow is how long the subscriptions of a client
+	// that went away are kept for it to resume
+	eventSessionResumeWindow = 2 * time.Minute
+	// eventSessionHistory is how many recent events a session keeps to
+	// replay to a client that resumes
+	eventSessionHistory = 256
+)
+
+// eventSession holds the subscriptions of a unified event stream client
+// across reconnects. Events are numbered in the order they are delivered
+// and the latest are kept, so a client that reconnects with the session's
+// resume token and the last number it saw receives what it missed.
+// Sessions live in the memory of the replica that opened them.
+type eventSession struct {
+	token  string
+	userID string
+	ctx    context.Context // Ends with the session, ending its subscriptions
+	cancel context.CancelFunc
+
+	mu            sync.Mutex
+	subscriptions map[string]context.CancelFunc
+	seq           int64
+	history       []EventStreamMessage      // Latest events, oldest first
+	send          chan<- EventStreamMessage // Of the attached connection; nil while detached
+	done          <-chan struct{}           // Closed when the attached connection ends
+	closeAttached context.CancelFunc        // Ends the attached connection
+	generation    int                       // Bumped on every attach, voiding pending expiries
+	expired       bool
+}
+
+// eventSessions are the unified event stream sessions of this replica
+type eventSessions struct {
+	mu       sync.Mutex
+	sessions map[string]*eventSession
+}
+
+func newEventSessions() *eventSessions {
+	return &eventSessions{sessions: make(map[string]*eventSession)}
+}
+
+// open returns the session of token when the user can resume it, or a new
+// session. Resuming a session still attached to a connection takes it over
+// from that connection, which a client that reconnects before the server
+// noticed it went away relies on.
+func (r *eventSessions) open(ctx context.Context, userID, token string) (*eventSession, bool) {
+	if token != "" {
+		r.mu.Lock()
+		session := r.sessions[token]
+		r.mu.Unlock()
+		if session != nil && session.userID == userID && session.claim() {
+			return session, true
+		}
+	}
+
+	sessionCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	session := &eventSession{
+		token:         uuid.New().String(),
+		userID:        userID,
+		ctx:           sessionCtx,
+		cancel:        cancel,
+		subscriptions: make(map[string]context.CancelFunc),
+	}
+	session.claim()
+
+	r.mu.Lock()
+	r.sessions[session.token] = session
+	r.mu.Unlock()
+	return session, false
+}
+
+// detach ends the attachment of the connection sending on send, keeping
+// the session for eventSessionResumeWindow
+func (r *eventSessions) detach(session *eventSession, send chan<- EventStreamMessage) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if session.send != send {
+		// Another connection took the session over
+		return
+	}
+	session.send, session.done, session.closeAttached = nil, nil, nil
+
+	generation := session.generation
+	time.AfterFunc(eventSessionResumeWindow, func() {
+		r.expire(session, generation)
+	})
+}
+
+// expire ends a session no connection attached to since generation
+func (r *eventSessions) expire(session *eventSession, generation int) {
+	session.mu.Lock()
+	if session.generation != generation || session.expired {
+		session.mu.Unlock()
+		return
+	}
+	session.expired = true
+	session.mu.Unlock()
+
+	r.mu.Lock()
+	delete(r.sessions, session.token)
+	r.mu.Unlock()
+	session.cancel()
+}
+
+// claim reserves a session for a connection about to attach, reporting
+// false when it already expired
+func (s *eventSession) claim() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.expired {
+		return false
+	}
+	s.generation++
+	return true
+}
+
+// attach makes the connection sending on send receive the session's
+// events until done is closed. It first sends the session message and, to
+// a resuming connection, the kept events after lastSeq; closeConn ends the
+// connection when another one takes the session over.
+func (s *eventSession) attach(send chan<- EventStreamMessage, done <-chan struct{}, closeConn context.CancelFunc, resumed bool, lastSeq int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closeAttached != nil {
+		s.closeAttached()
+	}
+	// Attached first, so detach expires the session even if the connection
+	// ends before the messages are sent
+	s.send, s.done, s.closeAttached = send, done, closeConn
+
+	messages := []EventStreamMessage{{
+		Type:        "session",
+		ResumeToken: s.token,
+		Resumed:     resumed,
+		Seq:         s.seq,
+		Timestamp:   time.Now(),
+	}}
+	if resumed && lastSeq < s.seq {
+		if len(s.history) == 0 || s.history[0].Seq > lastSeq+1 {
+			messages[0].EventsMissed = true
+		}
+		for _, message := range s.history {
+			if message.Seq > lastSeq {
+				messages = append(messages, message)
+			}
+		}
+	}
+	for _, message := range messages {
+		select {
+		case send <- message:
+		case <-done:
+			return
+		}
+	}
+}
+
+// deliver numbers and keeps an event and sends it to the attached
+// connection, if any. Sending under the lock keeps events in order.
+func (s *eventSession) deliver(message EventStreamMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq++
+	message.Seq = s.seq
+	s.history = append(s.history, message)
+	if len(s.history) > eventSessionHistory {
+		s.history = s.history[len(s.history)-eventSessionHistory:]
+	}
+	if s.send == nil {
+		return
+	}
+	select {
+	case s.send <- message:
+	case <-s.done:
+	}
+}
+
+// subscribed reports whether the session has the subscription of key
+func (s *eventSession) subscribed(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.subscriptions[key]
+	return ok
+}
+
+// subscriptionCount returns how many subscriptions the session has
+func (s *eventSession) subscriptionCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.subscriptions)
+}
+
+// subscribe keeps the subscription of key, which unsubscribe ends
+func (s *eventSession) subscribe(key string, unsubscribe context.CancelFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if previous, ok := s.subscriptions[key]; ok {
+		previous()
+	}
+	s.subscriptions[key] = unsubscribe
+}
+
+// unsubscribe ends the subscription of key
+func (s *eventSession) unsubscribe(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if unsubscribe, ok := s.subscriptions[key]; ok {
+		unsubscribe()
+		delete(s.subscriptions, key)
+	}
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/services"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// fakeSpaces lets user-1 read space-1 of tenant-1 only
+type fakeSpaces struct{}
+
+func (fakeSpaces) ResolveSpaceContext(ctx context.Context, userID string, req models.SpaceContextRequest) (*models.SpaceContext, error) {
+	if req.SpaceID != "space-1" {
+		return nil, errors.Forbidden("Access to space denied").WithErrorCode(errors.CodeSpaceAccessDenied)
+	}
+	return &models.SpaceContext{SpaceType: req.SpaceType, SpaceID: req.SpaceID, TenantID: "tenant-1", UserID: "user-1", UserRole: "member", Permissions: []string{"read"}}, nil
+}
+
+func newEventStream(t *testing.T) (*services.EventHub, *websocket.Conn) {
+	t.Helper()
+	hub, dial := newEventStreamServer(t)
+	conn, session := dial("")
+	assert.Equal(t, "session", session.Type)
+	assert.NotEmpty(t, session.ResumeToken)
+	assert.False(t, session.Resumed)
+	return hub, conn
+}
+
+// newEventStreamServer serves the unified event stream; dial connects to
+// it with a query and returns the connection and its session message
+func newEventStreamServer(t *testing.T) (*services.EventHub, func(query string) (*websocket.Conn, EventStreamMessage)) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	log, err := logger.New(logger.Config{Level: "error", Format: "json"})
+	require.NoError(t, err)
+
+	hub := services.NewEventHub(nil, log)
+	handler := NewWebSocketHandler(nil, nil, log)
+	handler.SetEventHub(hub)
+	handler.SetSubscriptionServices(fakeSpaces{}, nil)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) { c.Set("user_id", "user-1") })
+	router.GET("/api/v1/ws/events", handler.SubscribeEvents)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	return hub, func(query string) (*websocket.Conn, EventStreamMessage) {
+		t.Helper()
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/api/v1/ws/events"+query, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		return conn, readStreamMessage(t, conn)
+	}
+}
+
+func sendControl(t *testing.T, conn *websocket.Conn, control EventControlMessage) EventStreamMessage {
+	t.Helper()
+	require.NoError(t, conn.WriteJSON(control))
+	return readStreamMessage(t, conn)
+}
+
+func readStreamMessage(t *testing.T, conn *websocket.Conn) EventStreamMessage {
+	t.Helper()
+	var message EventStreamMessage
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	require.NoError(t, conn.ReadJSON(&message))
+	return message
+}
+
+func TestStreamEventsSubscriptions(t *testing.T) {
+	hub, conn := newEventStream(t)
+	ctx := context.Background()
+
+	reply := sendControl(t, conn, EventControlMessage{Action: "subscribe", ID: "1", Topic: "documents", SpaceType: "personal", SpaceID: "space-1"})
+	assert.Equal(t, "subscribed", reply.Type)
+	assert.Equal(t, "1", reply.ID)
+
+	// Events of other tenants never reach the subscription
+	hub.PublishDocumentStatus(ctx, "tenant-2", "doc-other", "processed", "")
+	hub.PublishDocumentStatus(ctx, "tenant-1", "doc-1", "processing", "")
+	message := readStreamMessage(t, conn)
+	assert.Equal(t, "event", message.Type)
+	assert.Equal(t, "documents", message.Topic)
+	require.NotNil(t, message.Event)
+	assert.Equal(t, "doc-1", message.Event.ResourceID)
+	assert.Equal(t, "processing", message.Event.Status)
+
+	reply = sendControl(t, conn, EventControlMessage{Action: "subscribe", Topic: "documents", SpaceType: "organization", SpaceID: "space-2"})
+	assert.Equal(t, "error", reply.Type)
+	assert.Equal(t, errors.CodeSpaceAccessDenied, reply.ErrorCode)
+
+	reply = sendControl(t, conn, EventControlMessage{Action: "subscribe", Topic: "billing", SpaceType: "personal", SpaceID: "space-1"})
+	assert.Equal(t, "error", reply.Type)
+
+	reply = sendControl(t, conn, EventControlMessage{Action: "unsubscribe", Topic: "documents", SpaceID: "space-1"})
+	assert.Equal(t, "unsubscribed", reply.Type)
+	hub.PublishDocumentStatus(ctx, "tenant-1", "doc-1", "processed", "")
+	reply = sendControl(t, conn, EventControlMessage{Action: "ping", ID: "2"})
+	assert.Equal(t, "pong", reply.Type, "no event follows an unsubscribe")
+	assert.Equal(t, "2", reply.ID)
+}
+
+func TestStreamEventsResume(t *testing.T) {
+	hub, dial := newEventStreamServer(t)
+	ctx := context.Background()
+
+	conn, session := dial("")
+	reply := sendControl(t, conn, EventControlMessage{Action: "subscribe", Topic: "jobs", SpaceType: "personal", SpaceID: "space-1"})
+	require.Equal(t, "subscribed", reply.Type)
+
+	hub.PublishJobProgress(ctx, &models.ProcessingJob{ID: "job-1", TenantID: "tenant-1", DocumentID: "doc-1", Status: "processing", Progress: 50})
+	message := readStreamMessage(t, conn)
+	require.Equal(t, "event", message.Type)
+	assert.Equal(t, int64(1), message.Seq)
+	assert.Equal(t, 50.0, message.Event.Data["progress"])
+
+	// Events published while the client is away are replayed on resume
+	require.NoError(t, conn.Close())
+	time.Sleep(100 * time.Millisecond)
+	hub.PublishJobProgress(ctx, &models.ProcessingJob{ID: "job-1", TenantID: "tenant-1", DocumentID: "doc-1", Status: "completed", Progress: 100})
+
+	resumedConn, resumed := dial("?resume_token=" + session.ResumeToken + "&last_seq=1")
+	assert.Equal(t, "session", resumed.Type)
+	assert.True(t, resumed.Resumed)
+	assert.False(t, resumed.EventsMissed)
+	assert.Equal(t, session.ResumeToken, resumed.ResumeToken)
+	message = readStreamMessage(t, resumedConn)
+	assert.Equal(t, int64(2), message.Seq)
+	assert.Equal(t, "completed", message.Event.Status)
+
+	// The subscription survived the reconnect
+	reply = sendControl(t, resumedConn, EventControlMessage{Action: "subscribe", Topic: "jobs", SpaceType: "personal", SpaceID: "space-1"})
+	assert.Equal(t, "subscribed", reply.Type)
+
+	_, fresh := dial("?resume_token=unknown")
+	assert.False(t, fresh.Resumed, "an unknown token starts a new session")
+	assert.NotEqual(t, session.ResumeToken, fresh.ResumeToken)
+}
+
+func TestEventSubscriptionAllowsNotificationsOfUser(t *testing.T) {
+	subscription := &eventSubscription{
+		topic:    "notifications",
+		spaceCtx: &models.SpaceContext{TenantID: "tenant-1", SpaceID: "space-1", UserID: "user-1"},
+	}
+	notification := func(userID string) *services.HubEvent {
+		return &services.HubEvent{Topic: services.HubTopicNotification, TenantID: "tenant-1", Data: map[string]interface{}{"user_id": userID}}
+	}
+
+	assert.True(t, subscription.allows(notification("user-1")))
+	assert.False(t, subscription.allows(notification("user-2")))
+}
+
+func TestEventSubscriptionLocalizesNotifications(t *testing.T) {
+	subscription := &eventSubscription{
+		topic:    "notifications",
+		spaceCtx: &models.SpaceContext{TenantID: "tenant-1", SpaceID: "space-1", UserID: "user-1"},
+		locale:   "de",
+	}
+	event := &services.HubEvent{Topic: services.HubTopicNotification, TenantID: "tenant-1", Status: services.NotificationReportReady, Data: map[string]interface{}{
+		"user_id":      "user-1",
+		"message":      "Report Weekly is ready",
+		"message_args": map[string]interface{}{"name": "Weekly"},
+	}}
+	subscription.localize(event)
+	assert.Equal(t, "Der Bericht Weekly ist fertig", event.Data["message"])
+
+	legacy := &services.HubEvent{Topic: services.HubTopicNotification, TenantID: "tenant-1", Status: services.NotificationReportReady, Data: map[string]interface{}{
+		"user_id": "user-1",
+		"message": "Report Weekly is ready",
+	}}
+	subscription.localize(legacy)
+	assert.Equal(t, "Report Weekly is ready", legacy.Data["message"], "notifications without arguments stay in English")
+}
+
+func TestEventSubscriptionAllowsAgents(t *testing.T) {
+	subscription := &eventSubscription{
+		topic:    "agents",
+		spaceCtx: &models.SpaceContext{TenantID: "tenant-1", SpaceID: "space-1", UserID: "user-1"},
+		teams:    []string{"team-1"},
+	}
+	agentEvent := func(data map[string]interface{}) *services.HubEvent {
+		data["space_id"] = "space-1"
+		return &services.HubEvent{Topic: services.HubTopicAgentChanged, TenantID: "tenant-1", Data: data}
+	}
+
+	assert.True(t, subscription.allows(agentEvent(map[string]interface{}{"owner_id": "user-1"})))
+	assert.True(t, subscription.allows(agentEvent(map[string]interface{}{"owner_id": "user-2", "is_public": true})))
+	assert.True(t, subscription.allows(agentEvent(map[string]interface{}{"owner_id": "user-2", "space_type": "organization", "team_id": "team-1"})))
+	assert.False(t, subscription.allows(agentEvent(map[string]interface{}{"owner_id": "user-2", "space_type": "organization", "team_id": "team-2"})))
+
+	other := agentEvent(map[string]interface{}{"owner_id": "user-1"})
+	other.Data["space_id"] = "space-2"
+	assert.False(t, subscription.allows(other), "events of another space are dropped")
+}
@@ -50,14 +50,14 @@ func (h *WorkflowHandler) CreateWorkflow(c *gin.Context) {
 
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -69,7 +69,7 @@ func (h *WorkflowHandler) CreateWorkflow(c *gin.Context) {
 	workflow, err := h.workflowService.CreateWorkflow(c.Request.Context(), req, userID, spaceContext)
 	if err != nil {
 		h.logger.Error("Failed to create workflow", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, errors.Internal("Failed to create workflow"))
+		middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to create workflow", err))
 		return
 	}
 
@@ -91,14 +91,14 @@ func (h *WorkflowHandler) CreateWorkflow(c *gin.Context) {
 func (h *WorkflowHandler) GetWorkflows(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -124,7 +124,7 @@ func (h *WorkflowHandler) GetWorkflows(c *gin.Context) {
 	workflows, total, err := h.workflowService.GetWorkflows(c.Request.Context(), spaceContext, limit, offset)
 	if err != nil {
 		h.logger.Error("Failed to get workflows", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, errors.Internal("Failed to get workflows"))
+		middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to get workflows", err))
 		return
 	}
 
@@ -159,14 +159,14 @@ func (h *WorkflowHandler) GetWorkflow(c *gin.Context) {
 
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -175,7 +175,7 @@ func (h *WorkflowHandler) GetWorkflow(c *gin.Context) {
 	workflow, err := h.workflowService.GetWorkflowByID(c.Request.Context(), workflowID, spaceContext)
 	if err != nil {
 		h.logger.Error("Failed to get workflow", zap.String("workflow_id", workflowID), zap.Error(err))
-		c.JSON(http.StatusNotFound, errors.NotFound("Workflow not found"))
+		c.JSON(http.StatusNotFound, errors.NotFound("Workflow not found").WithErrorCode(errors.CodeWorkflowNotFound))
 		return
 	}
 
@@ -212,14 +212,14 @@ func (h *WorkflowHandler) UpdateWorkflow(c *gin.Context) {
 
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -229,9 +229,9 @@ func (h *WorkflowHandler) UpdateWorkflow(c *gin.Context) {
 	if err != nil {
 		h.logger.Error("Failed to update workflow", zap.String("workflow_id", workflowID), zap.Error(err))
 		if err.Error() == "workflow not found" {
-			c.JSON(http.StatusNotFound, errors.NotFound("Workflow not found"))
+			c.JSON(http.StatusNotFound, errors.NotFound("Workflow not found").WithErrorCode(errors.CodeWorkflowNotFound))
 		} else {
-			c.JSON(http.StatusInternalServerError, errors.Internal("Failed to update workflow"))
+			middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to update workflow", err))
 		}
 		return
 	}
@@ -259,14 +259,14 @@ func (h *WorkflowHandler) DeleteWorkflow(c *gin.Context) {
 
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -276,9 +276,9 @@ func (h *WorkflowHandler) DeleteWorkflow(c *gin.Context) {
 	if err != nil {
 		h.logger.Error("Failed to delete workflow", zap.String("workflow_id", workflowID), zap.Error(err))
 		if err.Error() == "workflow not found" {
-			c.JSON(http.StatusNotFound, errors.NotFound("Workflow not found"))
+			c.JSON(http.StatusNotFound, errors.NotFound("Workflow not found").WithErrorCode(errors.CodeWorkflowNotFound))
 		} else {
-			c.JSON(http.StatusInternalServerError, errors.Internal("Failed to delete workflow"))
+			middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to delete workflow", err))
 		}
 		return
 	}
@@ -316,14 +316,14 @@ func (h *WorkflowHandler) ExecuteWorkflow(c *gin.Context) {
 
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -336,11 +336,11 @@ func (h *WorkflowHandler) ExecuteWorkflow(c *gin.Context) {
 	if err != nil {
 		h.logger.Error("Failed to execute workflow", zap.String("workflow_id", workflowID), zap.Error(err))
 		if err.Error() == "workflow not found" {
-			c.JSON(http.StatusNotFound, errors.NotFound("Workflow not found"))
+			c.JSON(http.StatusNotFound, errors.NotFound("Workflow not found").WithErrorCode(errors.CodeWorkflowNotFound))
 		} else if err.Error() == "workflow is not active" {
 			c.JSON(http.StatusBadRequest, errors.BadRequest("Workflow is not active"))
 		} else {
-			c.JSON(http.StatusInternalServerError, errors.Internal("Failed to execute workflow"))
+			middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to execute workflow", err))
 		}
 		return
 	}
@@ -371,14 +371,14 @@ func (h *WorkflowHandler) GetWorkflowExecutions(c *gin.Context) {
 
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -405,7 +405,7 @@ func (h *WorkflowHandler) GetWorkflowExecutions(c *gin.Context) {
 	executions, total, err := h.workflowService.GetWorkflowExecutions(c.Request.Context(), workflowID, spaceContext, limit, offset)
 	if err != nil {
 		h.logger.Error("Failed to get workflow executions", zap.String("workflow_id", workflowID), zap.Error(err))
-		c.JSON(http.StatusInternalServerError, errors.Internal("Failed to get workflow executions"))
+		middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to get workflow executions", err))
 		return
 	}
 
@@ -451,14 +451,14 @@ func (h *WorkflowHandler) UpdateWorkflowStatus(c *gin.Context) {
 
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -475,9 +475,9 @@ func (h *WorkflowHandler) UpdateWorkflowStatus(c *gin.Context) {
 	if err != nil {
 		h.logger.Error("Failed to update workflow status", zap.String("workflow_id", workflowID), zap.Error(err))
 		if err.Error() == "workflow not found" {
-			c.JSON(http.StatusNotFound, errors.NotFound("Workflow not found"))
+			c.JSON(http.StatusNotFound, errors.NotFound("Workflow not found").WithErrorCode(errors.CodeWorkflowNotFound))
 		} else {
-			c.JSON(http.StatusInternalServerError, errors.Internal("Failed to update workflow status"))
+			middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to update workflow status", err))
 		}
 		return
 	}
@@ -499,14 +499,14 @@ func (h *WorkflowHandler) UpdateWorkflowStatus(c *gin.Context) {
 func (h *WorkflowHandler) GetWorkflowAnalytics(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+		c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 		return
 	}
 
 	// Get space context
 	spaceContext, err := middleware.GetSpaceContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required"))
+		c.JSON(http.StatusBadRequest, errors.BadRequest("Space context is required").WithErrorCode(errors.CodeSpaceContextRequired))
 		return
 	}
 
@@ -520,7 +520,7 @@ func (h *WorkflowHandler) GetWorkflowAnalytics(c *gin.Context) {
 	analytics, err := h.workflowService.GetWorkflowAnalytics(c.Request.Context(), spaceContext, period)
 	if err != nil {
 		h.logger.Error("Failed to get workflow analytics", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, errors.Internal("Failed to get workflow analytics"))
+		middleware.WriteError(c, h.logger, errors.InternalWithCause("Failed to get workflow analytics", err))
 		return
 	}
 
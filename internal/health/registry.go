@@ -0,0 +1,192 @@
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Dependency and overall status values
+const (
+	StatusHealthy   = "healthy"
+	StatusDegraded  = "degraded"
+	StatusUnhealthy = "unhealthy"
+	StatusUnknown   = "unknown"
+)
+
+// DefaultCheckTimeout bounds a single dependency check
+const DefaultCheckTimeout = 5 * time.Second
+
+// CheckFunc reports whether a dependency is reachable
+type CheckFunc func(ctx context.Context) error
+
+// Recorder receives check results. It is implemented by metrics.Metrics.
+type Recorder interface {
+	SetDependencyHealth(dependency string, healthy bool, latency time.Duration)
+}
+
+// DependencyStatus is the latest known state of a dependency
+type DependencyStatus struct {
+	Name          string     `json:"name"`
+	Status        string     `json:"status"`
+	Critical      bool       `json:"critical"`
+	LatencyMs     float64    `json:"latency_ms"`
+	LastCheckedAt *time.Time `json:"last_checked_at,omitempty"`
+	LastHealthyAt *time.Time `json:"last_healthy_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	LastErrorAt   *time.Time `json:"last_error_at,omitempty"`
+}
+
+type dependency struct {
+	critical bool
+	check    CheckFunc
+	status   DependencyStatus
+}
+
+// Registry runs dependency checks and remembers their latest results.
+// Critical dependencies decide readiness; the others only degrade status.
+type Registry struct {
+	mu           sync.RWMutex
+	dependencies map[string]*dependency
+	timeout      time.Duration
+	recorder     Recorder
+}
+
+// NewRegistry creates an empty registry. A non-positive timeout uses DefaultCheckTimeout.
+func NewRegistry(timeout time.Duration) *Registry {
+	if timeout <= 0 {
+		timeout = DefaultCheckTimeout
+	}
+	return &Registry{
+		dependencies: make(map[string]*dependency),
+		timeout:      timeout,
+	}
+}
+
+// Register adds a dependency check, replacing any check with the same name
+func (r *Registry) Register(name string, critical bool, check CheckFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.dependencies[name] = &dependency{
+		critical: critical,
+		check:    check,
+		status: DependencyStatus{
+			Name:     name,
+			Status:   StatusUnknown,
+			Critical: critical,
+		},
+	}
+}
+
+// SetRecorder attaches a metrics recorder
+func (r *Registry) SetRecorder(recorder Recorder) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.recorder = recorder
+}
+
+// Check runs the registered checks concurrently and returns their results
+// sorted by name. When criticalOnly is set, non-critical checks are skipped.
+func (r *Registry) Check(ctx context.Context, criticalOnly bool) []DependencyStatus {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.dependencies))
+	for name, dep := range r.dependencies {
+		if criticalOnly && !dep.critical {
+			continue
+		}
+		names = append(names, name)
+	}
+	r.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			r.run(ctx, name)
+		}(name)
+	}
+	wg.Wait()
+
+	return r.statuses(names)
+}
+
+// Snapshot returns the latest results without running any checks
+func (r *Registry) Snapshot() []DependencyStatus {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.dependencies))
+	for name := range r.dependencies {
+		names = append(names, name)
+	}
+	r.mu.RUnlock()
+
+	return r.statuses(names)
+}
+
+// run executes one check and stores its result
+func (r *Registry) run(parent context.Context, name string) {
+	r.mu.RLock()
+	dep := r.dependencies[name]
+	r.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(parent, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := dep.check(ctx)
+	latency := time.Since(start)
+	now := time.Now()
+
+	r.mu.Lock()
+	status := &dep.status
+	status.LatencyMs = float64(latency.Microseconds()) / 1000
+	status.LastCheckedAt = &now
+	if err != nil {
+		status.Status = StatusUnhealthy
+		status.LastError = err.Error()
+		status.LastErrorAt = &now
+	} else {
+		status.Status = StatusHealthy
+		status.LastHealthyAt = &now
+	}
+	recorder := r.recorder
+	r.mu.Unlock()
+
+	if recorder != nil {
+		recorder.SetDependencyHealth(name, err == nil, latency)
+	}
+}
+
+// statuses copies the stored results for names, sorted by name
+func (r *Registry) statuses(names []string) []DependencyStatus {
+	sort.Strings(names)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]DependencyStatus, 0, len(names))
+	for _, name := range names {
+		if dep, ok := r.dependencies[name]; ok {
+			result = append(result, dep.status)
+		}
+	}
+	return result
+}
+
+// Overall summarizes dependency results: unhealthy if a critical dependency
+// is down, degraded if only non-critical ones are, healthy otherwise
+func Overall(statuses []DependencyStatus) string {
+	overall := StatusHealthy
+	for _, status := range statuses {
+		if status.Status == StatusHealthy {
+			continue
+		}
+		if status.Critical {
+			return StatusUnhealthy
+		}
+		overall = StatusDegraded
+	}
+	return overall
+}
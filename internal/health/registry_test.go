@@ -0,0 +1,77 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedHealth struct {
+	mu      sync.Mutex
+	healthy map[string]bool
+}
+
+func (r *recordedHealth) SetDependencyHealth(dependency string, healthy bool, _ time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.healthy[dependency] = healthy
+}
+
+func TestRegistryCheck(t *testing.T) {
+	registry := NewRegistry(time.Second)
+	recorder := &recordedHealth{healthy: map[string]bool{}}
+	registry.SetRecorder(recorder)
+
+	registry.Register("neo4j", true, func(context.Context) error { return nil })
+	registry.Register("audimodal", false, func(context.Context) error { return errors.New("connection refused") })
+
+	statuses := registry.Check(context.Background(), false)
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "audimodal", statuses[0].Name)
+	assert.Equal(t, StatusUnhealthy, statuses[0].Status)
+	assert.Equal(t, "connection refused", statuses[0].LastError)
+	assert.NotNil(t, statuses[0].LastErrorAt)
+	assert.Equal(t, StatusHealthy, statuses[1].Status)
+	assert.Equal(t, StatusDegraded, Overall(statuses))
+	assert.Equal(t, map[string]bool{"neo4j": true, "audimodal": false}, recorder.healthy)
+
+	critical := registry.Check(context.Background(), true)
+	require.Len(t, critical, 1)
+	assert.Equal(t, StatusHealthy, Overall(critical))
+}
+
+func TestRegistryKeepsLastError(t *testing.T) {
+	registry := NewRegistry(time.Second)
+	fail := true
+	registry.Register("kafka", true, func(context.Context) error {
+		if fail {
+			return errors.New("broker unavailable")
+		}
+		return nil
+	})
+
+	assert.Equal(t, StatusUnhealthy, Overall(registry.Check(context.Background(), false)))
+
+	fail = false
+	statuses := registry.Check(context.Background(), false)
+	assert.Equal(t, StatusHealthy, statuses[0].Status)
+	assert.Equal(t, "broker unavailable", statuses[0].LastError)
+	assert.NotNil(t, statuses[0].LastHealthyAt)
+}
+
+func TestRegistryCheckTimeout(t *testing.T) {
+	registry := NewRegistry(10 * time.Millisecond)
+	registry.Register("keycloak", false, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	statuses := registry.Check(context.Background(), false)
+	assert.Equal(t, StatusUnhealthy, statuses[0].Status)
+	assert.Contains(t, statuses[0].LastError, "deadline exceeded")
+}
@@ -0,0 +1,245 @@
+// Package i18n localizes user-facing text: error messages, notification
+// texts and the labels of enumerations such as statuses and roles. Texts
+// are looked up by key in the catalogues embedded from locales/*.json, one
+// per locale. English is the source language: a request's locale is
+// negotiated from its Accept-Language header, and a text missing from its
+// catalogue falls back to the base language's (de for de-AT), then to
+// English.
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// DefaultLocale is the source language, used when a request accepts no
+// supported locale
+const DefaultLocale = "en"
+
+// Key prefixes of the catalogues
+const (
+	errorPrefix        = "error."
+	notificationPrefix = "notification."
+	enumPrefix         = "enum."
+)
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// catalogues maps each supported locale to its texts by key
+var catalogues = loadCatalogues()
+
+func loadCatalogues() map[string]map[string]string {
+	files, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic("i18n: " + err.Error())
+	}
+	loaded := make(map[string]map[string]string, len(files))
+	for _, file := range files {
+		data, err := localeFiles.ReadFile(path.Join("locales", file.Name()))
+		if err != nil {
+			panic("i18n: " + err.Error())
+		}
+		var texts map[string]string
+		if err := json.Unmarshal(data, &texts); err != nil {
+			panic("i18n: invalid catalogue " + file.Name() + ": " + err.Error())
+		}
+		loaded[strings.TrimSuffix(file.Name(), ".json")] = texts
+	}
+	return loaded
+}
+
+// Supported returns the supported locales, sorted
+func Supported() []string {
+	locales := make([]string, 0, len(catalogues))
+	for locale := range catalogues {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Negotiate returns the supported locale a client prefers, given its
+// Accept-Language header: the first language range, by quality, that is a
+// supported locale or whose base language is one. Ranges with quality 0
+// are refused. It returns DefaultLocale when none is supported.
+func Negotiate(acceptLanguage string) string {
+	for _, tag := range parseAcceptLanguage(acceptLanguage) {
+		for _, locale := range baseLocales(tag) {
+			if _, ok := catalogues[locale]; ok {
+				return locale
+			}
+		}
+	}
+	return DefaultLocale
+}
+
+// parseAcceptLanguage returns the language ranges of an Accept-Language
+// header in order of preference, lowercased, without "*" and refused ones
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag     string
+		quality float64
+	}
+	var ranges []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range strings.Split(params, ";") {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.TrimSpace(name) != "q" {
+				continue
+			}
+			q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil || q < 0 || q > 1 {
+				q = 0
+			}
+			quality = q
+		}
+		if quality > 0 {
+			ranges = append(ranges, weighted{tag: tag, quality: quality})
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].quality > ranges[j].quality })
+
+	tags := make([]string, len(ranges))
+	for i, r := range ranges {
+		tags[i] = r.tag
+	}
+	return tags
+}
+
+// baseLocales returns a locale followed by its base languages, most
+// specific first: pt-br, pt. Underscores are read as hyphens.
+func baseLocales(locale string) []string {
+	locale = strings.ReplaceAll(strings.ToLower(locale), "_", "-")
+	chain := []string{locale}
+	for i := strings.LastIndex(locale, "-"); i > 0; i = strings.LastIndex(locale, "-") {
+		locale = locale[:i]
+		chain = append(chain, locale)
+	}
+	return chain
+}
+
+// fallbacks returns the locales a text of locale is looked up in: its
+// base locales, then DefaultLocale
+func fallbacks(locale string) []string {
+	chain := baseLocales(locale)
+	if chain[len(chain)-1] != DefaultLocale {
+		chain = append(chain, DefaultLocale)
+	}
+	return chain
+}
+
+// lookup returns the text of key in locale or the locales it falls back
+// to
+func lookup(locale, key string) (string, bool) {
+	for _, fallback := range fallbacks(locale) {
+		if text, ok := catalogues[fallback][key]; ok {
+			return text, true
+		}
+	}
+	return "", false
+}
+
+// format replaces each {name} placeholder of text with its argument
+func format(text string, args map[string]string) string {
+	if len(args) == 0 {
+		return text
+	}
+	pairs := make([]string, 0, 2*len(args))
+	for name, value := range args {
+		pairs = append(pairs, "{"+name+"}", value)
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+// Notification returns the text of a notification kind in locale, with
+// its {name} placeholders filled from args. Kinds without a text return
+// the kind.
+func Notification(locale, kind string, args map[string]string) string {
+	text, ok := lookup(locale, notificationPrefix+kind)
+	if !ok {
+		return kind
+	}
+	return format(text, args)
+}
+
+// EnumLabel returns the label of a value of an enumeration in locale, or
+// the value when the enumeration has no label for it
+func EnumLabel(locale, enum, value string) string {
+	if label, ok := lookup(locale, enumPrefix+enum+"."+value); ok {
+		return label
+	}
+	return value
+}
+
+// Enumerations returns the labels of every enumeration in locale, by
+// enumeration and value. The English catalogue defines the values.
+func Enumerations(locale string) map[string]map[string]string {
+	enums := make(map[string]map[string]string)
+	for key := range catalogues[DefaultLocale] {
+		name, ok := strings.CutPrefix(key, enumPrefix)
+		if !ok {
+			continue
+		}
+		enum, value, ok := strings.Cut(name, ".")
+		if !ok {
+			continue
+		}
+		if enums[enum] == nil {
+			enums[enum] = make(map[string]string)
+		}
+		enums[enum][value] = EnumLabel(locale, enum, value)
+	}
+	return enums
+}
+
+// LocalizeError returns a copy of an API error whose message is the
+// translation of its catalogue code in locale. English messages are
+// written for the occasion and kept as they are; so are messages of the
+// general codes, whose translation would say less than the English
+// message, and of codes the locale has no translation for.
+func LocalizeError(locale string, apiErr *errors.APIError) *errors.APIError {
+	if apiErr == nil || apiErr.ErrorCode == "" {
+		return apiErr
+	}
+	for _, fallback := range fallbacks(locale) {
+		if fallback == DefaultLocale {
+			break
+		}
+		if message, ok := catalogues[fallback][errorPrefix+apiErr.ErrorCode]; ok {
+			localized := *apiErr
+			localized.Message = message
+			return &localized
+		}
+	}
+	return apiErr
+}
+
+type contextKey struct{}
+
+// WithLocale returns a context carrying the locale of a request
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, contextKey{}, locale)
+}
+
+// FromContext returns the locale of a request, DefaultLocale when it has
+// none
+func FromContext(ctx context.Context) string {
+	if locale, ok := ctx.Value(contextKey{}).(string); ok && locale != "" {
+		return locale
+	}
+	return DefaultLocale
+}
@@ -0,0 +1,100 @@
+package i18n
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", "en"},
+		{"de", "de"},
+		{"de-AT", "de"},
+		{"FR_ca", "fr"},
+		{"ja, de;q=0.5", "de"},
+		{"en;q=0.4, es;q=0.8", "es"},
+		{"de;q=0, fr;q=0.1", "fr"},
+		{"de;q=0", "en"},
+		{"ja, *", "en"},
+		{"de;q=abc, es", "es"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.want, Negotiate(tt.header))
+		})
+	}
+}
+
+func TestNotification(t *testing.T) {
+	args := map[string]string{"name": "Q3 report"}
+	assert.Equal(t, "Report Q3 report is ready", Notification("en", "report.ready", args))
+	assert.Equal(t, "Der Bericht Q3 report ist fertig", Notification("de-CH", "report.ready", args))
+	assert.Equal(t, "Report Q3 report is ready", Notification("ja", "report.ready", args), "unsupported locales fall back to English")
+	assert.Equal(t, "unknown.kind", Notification("de", "unknown.kind", args))
+}
+
+func TestEnumerations(t *testing.T) {
+	assert.Equal(t, "Verarbeitet", EnumLabel("de", "document_status", "processed"))
+	assert.Equal(t, "mystery", EnumLabel("de", "document_status", "mystery"))
+
+	english := Enumerations("en")
+	for _, locale := range Supported() {
+		enums := Enumerations(locale)
+		for enum, values := range english {
+			assert.Len(t, enums[enum], len(values), "%s %s", locale, enum)
+		}
+	}
+}
+
+func TestLocalizeError(t *testing.T) {
+	apiErr := errors.NotFound("Document abc not found").WithErrorCode(errors.CodeDocumentNotFound)
+
+	assert.Same(t, apiErr, LocalizeError("en", apiErr), "English messages are kept")
+
+	localized := LocalizeError("de-DE", apiErr)
+	assert.NotEqual(t, apiErr.Message, localized.Message)
+	assert.Equal(t, apiErr.ErrorCode, localized.ErrorCode)
+	assert.Equal(t, apiErr.StatusCode, localized.StatusCode)
+	assert.Equal(t, "Document abc not found", apiErr.Message, "the original is not changed")
+
+	general := errors.Validation("Invalid request body", nil)
+	assert.Equal(t, general.Message, LocalizeError("de", general).Message, "general codes keep their message")
+	assert.Nil(t, LocalizeError("de", nil))
+}
+
+func TestCataloguesAreConsistent(t *testing.T) {
+	codes := make(map[string]bool)
+	for _, entry := range errors.Catalogue() {
+		codes[entry.Code] = true
+	}
+
+	for locale, texts := range catalogues {
+		for key, text := range texts {
+			if code, ok := strings.CutPrefix(key, errorPrefix); ok {
+				assert.True(t, codes[code], "%s: %s is not a catalogue code", locale, key)
+				assert.NotContains(t, code, "-GEN-", "%s: general codes are not translated", locale)
+				continue
+			}
+			english, ok := catalogues[DefaultLocale][key]
+			if !assert.True(t, ok, "%s: %s is missing from the English catalogue", locale, key) {
+				continue
+			}
+			for _, placeholder := range []string{"{name}"} {
+				assert.Equal(t, strings.Contains(english, placeholder), strings.Contains(text, placeholder), "%s: %s placeholders", locale, key)
+			}
+		}
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, DefaultLocale, FromContext(context.Background()))
+	assert.Equal(t, "fr", FromContext(WithLocale(context.Background(), "fr")))
+}
@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// RequestIDHeader is the header used to carry the correlation ID across
+// HTTP hops and Kafka messages
+const RequestIDHeader = "X-Request-ID"
+
+// KafkaRequestIDHeader is the Kafka message header carrying the correlation ID
+const KafkaRequestIDHeader = "request-id"
+
+// MaxRequestIDLength is the longest request ID accepted from a caller
+const MaxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// ValidRequestID reports whether a request ID received from a caller is
+// safe to log and forward: at most MaxRequestIDLength letters, digits,
+// dots, underscores and hyphens
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > MaxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		switch ch := id[i]; {
+		case ch >= 'a' && ch <= 'z', ch >= 'A' && ch <= 'Z', ch >= '0' && ch <= '9':
+		case ch == '.', ch == '_', ch == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// ContextWithRequestID returns a copy of ctx carrying the given request ID
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	if requestID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID stored in ctx, if any
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+		return id
+	}
+	return ""
+}
+
+// EnsureRequestID returns ctx with a request ID, generating one if absent.
+// Used by background work (Kafka consumers, workers) that has no inbound request.
+func EnsureRequestID(ctx context.Context) (context.Context, string) {
+	if id := RequestIDFromContext(ctx); id != "" {
+		return ctx, id
+	}
+	id := uuid.New().String()
+	return ContextWithRequestID(ctx, id), id
+}
+
+// FromContext returns a logger annotated with the request ID found in ctx
+func (l *Logger) FromContext(ctx context.Context) *Logger {
+	if id := RequestIDFromContext(ctx); id != "" {
+		return l.WithContext(zap.String("request_id", id))
+	}
+	return l
+}
+
+// requestIDTransport injects the context request ID into outbound HTTP requests
+type requestIDTransport struct {
+	base http.RoundTripper
+}
+
+// NewRequestIDTransport wraps base so every outbound request carries the
+// X-Request-ID header of the request context. A nil base uses http.DefaultTransport.
+func NewRequestIDTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &requestIDTransport{base: base}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *requestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	id := RequestIDFromContext(req.Context())
+	if id == "" || req.Header.Get(RequestIDHeader) != "" {
+		return t.base.RoundTrip(req)
+	}
+
+	// RoundTrippers must not modify the original request
+	clone := req.Clone(req.Context())
+	clone.Header.Set(RequestIDHeader, id)
+	return t.base.RoundTrip(clone)
+}
@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestIDContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Empty(t, RequestIDFromContext(ctx))
+	assert.Equal(t, ctx, ContextWithRequestID(ctx, ""), "an empty ID leaves the context alone")
+
+	ctx = ContextWithRequestID(ctx, "req-1")
+	assert.Equal(t, "req-1", RequestIDFromContext(ctx))
+
+	kept, id := EnsureRequestID(ctx)
+	assert.Equal(t, "req-1", id)
+	assert.Equal(t, ctx, kept, "an existing ID is kept")
+
+	generated, id := EnsureRequestID(context.Background())
+	_, err := uuid.Parse(id)
+	assert.NoError(t, err)
+	assert.Equal(t, id, RequestIDFromContext(generated))
+}
+
+func TestValidRequestID(t *testing.T) {
+	for _, id := range []string{"req-1", "5f1b0c9e-3c1a-4f6e-9a53-2b1e6f0c7d11", "trace.abc_DEF-1", strings.Repeat("a", MaxRequestIDLength)} {
+		assert.True(t, ValidRequestID(id), id)
+	}
+	for _, id := range []string{"", "req 1", "req\n1", "req\"1", "req/1", "ïd", strings.Repeat("a", MaxRequestIDLength+1)} {
+		assert.False(t, ValidRequestID(id), id)
+	}
+}
+
+func TestRequestIDTransport(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get(RequestIDHeader))
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: NewRequestIDTransport(nil)}
+
+	send := func(ctx context.Context, header string) *http.Request {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		if header != "" {
+			req.Header.Set(RequestIDHeader, header)
+		}
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return req
+	}
+
+	req := send(ContextWithRequestID(context.Background(), "req-1"), "")
+	assert.Empty(t, req.Header.Get(RequestIDHeader), "the caller's request is not modified")
+	send(ContextWithRequestID(context.Background(), "req-1"), "req-explicit")
+	send(context.Background(), "")
+
+	assert.Equal(t, []string{"req-1", "req-explicit", ""}, received)
+}
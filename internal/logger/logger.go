@@ -10,12 +10,22 @@ import (
 // Logger wraps zap.Logger with additional functionality
 type Logger struct {
 	*zap.Logger
+
+	// level is shared by all loggers derived from the same root so the
+	// log level can be changed at runtime
+	level zap.AtomicLevel
 }
 
 // Config holds logger configuration
 type Config struct {
 	Level  string
 	Format string // "json" or "console"
+
+	// Credentials, document text, email addresses, file names and storage
+	// keys are redacted from every entry unless DisableRedaction is set.
+	// RedactFields names further field keys whose values are dropped.
+	DisableRedaction bool
+	RedactFields     []string
 }
 
 // New creates a new logger instance
@@ -38,7 +48,8 @@ func New(config Config) (*Logger, error) {
 		zapConfig.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
 	}
 
-	zapConfig.Level = zap.NewAtomicLevelAt(level)
+	atomicLevel := zap.NewAtomicLevelAt(level)
+	zapConfig.Level = atomicLevel
 
 	// Add caller information for development
 	if config.Format == "console" {
@@ -47,15 +58,23 @@ func New(config Config) (*Logger, error) {
 		zapConfig.EncoderConfig.EncodeCaller = zapcore.ShortCallerEncoder
 	}
 
-	logger, err := zapConfig.Build(
+	options := []zap.Option{
 		zap.AddCallerSkip(1), // Skip one level to show actual caller
 		zap.AddStacktrace(zapcore.ErrorLevel),
-	)
+	}
+	if !config.DisableRedaction {
+		redactor := newRedactor(config.RedactFields)
+		options = append(options, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return &redactingCore{Core: core, redactor: redactor}
+		}))
+	}
+
+	logger, err := zapConfig.Build(options...)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Logger{Logger: logger}, nil
+	return &Logger{Logger: logger, level: atomicLevel}, nil
 }
 
 // NewDefault creates a logger with default configuration
@@ -74,9 +93,25 @@ func NewDefault() (*Logger, error) {
 	return New(config)
 }
 
+// Level returns the current log level
+func (l *Logger) Level() string {
+	return l.level.Level().String()
+}
+
+// SetLevel changes the log level of this logger and every logger derived
+// from the same root
+func (l *Logger) SetLevel(level string) error {
+	parsed, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	l.level.SetLevel(parsed)
+	return nil
+}
+
 // WithContext adds context fields to the logger
 func (l *Logger) WithContext(fields ...zap.Field) *Logger {
-	return &Logger{Logger: l.Logger.With(fields...)}
+	return &Logger{Logger: l.Logger.With(fields...), level: l.level}
 }
 
 // WithRequestID adds request ID to the logger
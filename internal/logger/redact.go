@@ -0,0 +1,195 @@
+package logger
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Redacted replaces values that must never be logged
+const Redacted = "[REDACTED]"
+
+// How a field is redacted, chosen by its key
+type redaction int
+
+const (
+	redactNone     redaction = iota
+	redactSecret             // Credentials: replaced entirely
+	redactContent            // Document text and previews: replaced, length kept
+	redactEmail              // Email addresses: local part masked
+	redactFilename           // File names: name masked, extension kept
+	redactPath               // Storage keys: final path segment masked as a file name
+)
+
+// defaultRedactedFields maps field keys to their redaction. Keys are
+// matched case-insensitively.
+var defaultRedactedFields = map[string]redaction{
+	"password":       redactSecret,
+	"secret":         redactSecret,
+	"client_secret":  redactSecret,
+	"token":          redactSecret,
+	"access_token":   redactSecret,
+	"refresh_token":  redactSecret,
+	"id_token":       redactSecret,
+	"api_key":        redactSecret,
+	"apikey":         redactSecret,
+	"authorization":  redactSecret,
+	"cookie":         redactSecret,
+	"content":        redactContent,
+	"file_content":   redactContent,
+	"text":           redactContent,
+	"extracted_text": redactContent,
+	"text_preview":   redactContent,
+	"preview":        redactContent,
+	"body":           redactContent,
+	"email":          redactEmail,
+	"user_email":     redactEmail,
+	"filename":       redactFilename,
+	"file_name":      redactFilename,
+	"original_name":  redactFilename,
+	"key":            redactPath,
+	"storage_key":    redactPath,
+	"storage_path":   redactPath,
+	"source_key":     redactPath,
+	"dest_key":       redactPath,
+	"s3_key":         redactPath,
+}
+
+var (
+	emailPattern  = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	bearerPattern = regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]+=*`)
+	jwtPattern    = regexp.MustCompile(`\beyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+`)
+)
+
+// redactor scrubs sensitive data from log fields and messages
+type redactor struct {
+	fields map[string]redaction
+}
+
+// newRedactor creates a redactor for the default fields plus extra field
+// keys, which are redacted entirely
+func newRedactor(extra []string) *redactor {
+	fields := make(map[string]redaction, len(defaultRedactedFields)+len(extra))
+	for key, r := range defaultRedactedFields {
+		fields[key] = r
+	}
+	for _, key := range extra {
+		if key = strings.ToLower(strings.TrimSpace(key)); key != "" {
+			fields[key] = redactSecret
+		}
+	}
+	return &redactor{fields: fields}
+}
+
+// scrub masks email addresses and bearer tokens found in free text
+func (r *redactor) scrub(s string) string {
+	if strings.Contains(s, "@") {
+		s = emailPattern.ReplaceAllStringFunc(s, maskEmail)
+	}
+	s = bearerPattern.ReplaceAllString(s, "Bearer "+Redacted)
+	return jwtPattern.ReplaceAllString(s, Redacted)
+}
+
+// field returns the field with sensitive data removed, and whether it
+// changed. Unchanged fields keep their original encoding.
+func (r *redactor) field(f zap.Field) (zap.Field, bool) {
+	switch r.fields[strings.ToLower(f.Key)] {
+	case redactSecret:
+		return zap.String(f.Key, Redacted), true
+	case redactContent:
+		if f.Type == zapcore.StringType {
+			return zap.String(f.Key, fmt.Sprintf("%s len=%d", Redacted, len(f.String))), true
+		}
+		return zap.String(f.Key, Redacted), true
+	case redactEmail:
+		if f.Type == zapcore.StringType {
+			return zap.String(f.Key, maskEmail(f.String)), true
+		}
+	case redactFilename:
+		if f.Type == zapcore.StringType {
+			return zap.String(f.Key, maskFilename(f.String)), true
+		}
+	case redactPath:
+		if f.Type == zapcore.StringType && strings.Contains(f.String, "/") {
+			dir, file := path.Split(f.String)
+			return zap.String(f.Key, dir+maskFilename(file)), true
+		}
+	}
+
+	switch f.Type {
+	case zapcore.StringType:
+		if scrubbed := r.scrub(f.String); scrubbed != f.String {
+			return zap.String(f.Key, scrubbed), true
+		}
+	case zapcore.ErrorType:
+		if err, ok := f.Interface.(error); ok && err != nil {
+			msg := err.Error()
+			if scrubbed := r.scrub(msg); scrubbed != msg {
+				return zap.String(f.Key, scrubbed), true
+			}
+		}
+	}
+	return f, false
+}
+
+// fieldList redacts a list of fields, copying it only when a field changes
+func (r *redactor) fieldList(fields []zapcore.Field) []zapcore.Field {
+	var out []zapcore.Field
+	for i, f := range fields {
+		redacted, changed := r.field(f)
+		if changed && out == nil {
+			out = make([]zapcore.Field, len(fields))
+			copy(out, fields[:i])
+		}
+		if out != nil {
+			out[i] = redacted
+		}
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+// maskEmail keeps the first character of the local part and the domain
+func maskEmail(email string) string {
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok || local == "" {
+		return Redacted
+	}
+	return local[:1] + "***@" + domain
+}
+
+// maskFilename keeps only the extension of a file name
+func maskFilename(name string) string {
+	if name == "" {
+		return name
+	}
+	return Redacted + path.Ext(name)
+}
+
+// redactingCore redacts fields before handing them to the wrapped core
+type redactingCore struct {
+	zapcore.Core
+	redactor *redactor
+}
+
+func (c *redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactingCore{Core: c.Core.With(c.redactor.fieldList(fields)), redactor: c.redactor}
+}
+
+func (c *redactingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *redactingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Message = c.redactor.scrub(entry.Message)
+	return c.Core.Write(entry, c.redactor.fieldList(fields))
+}
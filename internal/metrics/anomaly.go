@@ -0,0 +1,235 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+// Smallest standard deviation a z-score is computed against, per signal.
+// Flat baselines would otherwise turn any change into an infinite z-score.
+var anomalyMinStdDev = map[string]float64{
+	models.UsageSignalUploads:   1,
+	models.UsageSignalAgentCost: 0.01,
+	models.UsageSignalErrorRate: 0.01,
+}
+
+// anomalyMinErrorRate is the smallest error rate reported as an anomaly
+const anomalyMinErrorRate = 0.05
+
+// anomalyIdleIntervals is how many intervals without activity a tenant's
+// baselines are kept for
+const anomalyIdleIntervals = 288
+
+// AnomalyThresholds configures the usage anomaly detector
+type AnomalyThresholds struct {
+	Alpha           float64 // EWMA smoothing factor; higher adapts faster
+	ZThreshold      float64 // Deviation, in standard deviations, that is anomalous
+	WarmupIntervals int     // Intervals of history a baseline needs before it is used
+	MaxTenants      int     // Tenants tracked at once; further tenants are ignored
+	MinUploads      float64 // Uploads in an interval below which spikes are ignored
+	MinAgentCostUSD float64 // Agent cost in an interval below which surges are ignored
+	MinRequests     int64   // Requests in an interval needed to judge the error rate
+}
+
+// usageCounts is a tenant's activity in the current interval
+type usageCounts struct {
+	uploads  int64
+	costUSD  float64
+	requests int64
+	errors   int64
+}
+
+// ewma is an exponentially weighted moving mean and variance
+type ewma struct {
+	mean     float64
+	variance float64
+	n        int
+}
+
+func (e *ewma) update(x, alpha float64) {
+	if e.n == 0 {
+		e.mean = x
+	} else {
+		diff := x - e.mean
+		increment := alpha * diff
+		e.mean += increment
+		e.variance = (1 - alpha) * (e.variance + diff*increment)
+	}
+	e.n++
+}
+
+type tenantUsage struct {
+	current   usageCounts
+	baselines map[string]*ewma
+	idle      int
+}
+
+// UsageAnomalyDetector rolls tenant activity up into fixed intervals and
+// compares each interval with an EWMA baseline of the tenant's previous
+// intervals. Like the SLO tracker, state is kept in memory per replica, so
+// each replica judges the traffic it serves.
+type UsageAnomalyDetector struct {
+	thresholds AnomalyThresholds
+
+	mu      sync.Mutex
+	tenants map[string]*tenantUsage
+}
+
+// NewUsageAnomalyDetector creates a detector with the given thresholds
+func NewUsageAnomalyDetector(thresholds AnomalyThresholds) *UsageAnomalyDetector {
+	return &UsageAnomalyDetector{
+		thresholds: thresholds,
+		tenants:    make(map[string]*tenantUsage),
+	}
+}
+
+// tenant returns the usage of a tenant, or nil when it cannot be tracked.
+// The caller holds d.mu.
+func (d *UsageAnomalyDetector) tenant(tenantID string) *tenantUsage {
+	if tenantID == "" {
+		return nil
+	}
+	usage, ok := d.tenants[tenantID]
+	if !ok {
+		if len(d.tenants) >= d.thresholds.MaxTenants {
+			return nil
+		}
+		usage = &tenantUsage{baselines: make(map[string]*ewma)}
+		d.tenants[tenantID] = usage
+	}
+	return usage
+}
+
+// RecordUpload counts a file uploaded by a tenant
+func (d *UsageAnomalyDetector) RecordUpload(tenantID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if usage := d.tenant(tenantID); usage != nil {
+		usage.current.uploads++
+	}
+}
+
+// RecordAgentCost adds to a tenant's agent execution cost
+func (d *UsageAnomalyDetector) RecordAgentCost(tenantID string, costUSD float64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if usage := d.tenant(tenantID); usage != nil {
+		usage.current.costUSD += costUSD
+	}
+}
+
+// RecordRequest counts an API request made in a tenant's space
+func (d *UsageAnomalyDetector) RecordRequest(tenantID string, statusCode int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if usage := d.tenant(tenantID); usage != nil {
+		usage.current.requests++
+		if statusCode >= 500 {
+			usage.current.errors++
+		}
+	}
+}
+
+// TrackedTenants returns the number of tenants with baselines
+func (d *UsageAnomalyDetector) TrackedTenants() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.tenants)
+}
+
+// Close ends the current interval at end. It returns the signals that
+// deviated from their baselines, ordered by tenant and signal, then folds
+// the interval into the baselines and starts a new one.
+func (d *UsageAnomalyDetector) Close(end time.Time) []models.UsageAnomaly {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var anomalies []models.UsageAnomaly
+	for tenantID, usage := range d.tenants {
+		counts := usage.current
+		usage.current = usageCounts{}
+
+		if counts == (usageCounts{}) {
+			if usage.idle++; usage.idle >= anomalyIdleIntervals {
+				delete(d.tenants, tenantID)
+				continue
+			}
+		} else {
+			usage.idle = 0
+		}
+
+		values := map[string]float64{
+			models.UsageSignalUploads:   float64(counts.uploads),
+			models.UsageSignalAgentCost: counts.costUSD,
+		}
+		floors := map[string]float64{
+			models.UsageSignalUploads:   d.thresholds.MinUploads,
+			models.UsageSignalAgentCost: d.thresholds.MinAgentCostUSD,
+			models.UsageSignalErrorRate: anomalyMinErrorRate,
+		}
+		// Too few requests make the error rate noise; the interval is skipped
+		if counts.requests >= d.thresholds.MinRequests && counts.requests > 0 {
+			values[models.UsageSignalErrorRate] = float64(counts.errors) / float64(counts.requests)
+		}
+
+		for signal, value := range values {
+			baseline, ok := usage.baselines[signal]
+			if !ok {
+				baseline = &ewma{}
+				usage.baselines[signal] = baseline
+			}
+			if anomaly, ok := d.check(baseline, signal, value, floors[signal]); ok {
+				anomaly.TenantID = tenantID
+				anomaly.IntervalEnd = end
+				anomalies = append(anomalies, anomaly)
+				// Anomalous values are clipped to the threshold, so a surge
+				// shifts the baseline gradually and stays flagged while it lasts
+				value = anomaly.Baseline + d.thresholds.ZThreshold*anomaly.StdDev
+			}
+			baseline.update(value, d.thresholds.Alpha)
+		}
+	}
+
+	sort.Slice(anomalies, func(i, j int) bool {
+		if anomalies[i].TenantID != anomalies[j].TenantID {
+			return anomalies[i].TenantID < anomalies[j].TenantID
+		}
+		return anomalies[i].Signal < anomalies[j].Signal
+	})
+	return anomalies
+}
+
+// check compares a value with its baseline. Only increases are anomalous:
+// a drop in uploads, cost or errors is not something operators act on.
+func (d *UsageAnomalyDetector) check(baseline *ewma, signal string, value, floor float64) (models.UsageAnomaly, bool) {
+	if baseline.n < d.thresholds.WarmupIntervals || value < floor {
+		return models.UsageAnomaly{}, false
+	}
+
+	stdDev := math.Max(math.Sqrt(baseline.variance), anomalyMinStdDev[signal])
+	z := (value - baseline.mean) / stdDev
+	if z < d.thresholds.ZThreshold {
+		return models.UsageAnomaly{}, false
+	}
+	return models.UsageAnomaly{
+		Signal:   signal,
+		Value:    value,
+		Baseline: baseline.mean,
+		StdDev:   stdDev,
+		ZScore:   z,
+	}, true
+}
+
+// SetUsageAnomalyDetector enables usage anomaly detection
+func (m *Metrics) SetUsageAnomalyDetector(d *UsageAnomalyDetector) {
+	m.anomalyDetector.Store(d)
+}
+
+// UsageAnomalyDetector returns the detector, or nil when detection is off
+func (m *Metrics) UsageAnomalyDetector() *UsageAnomalyDetector {
+	return m.anomalyDetector.Load()
+}
@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+func newTestAnomalyDetector() *UsageAnomalyDetector {
+	return NewUsageAnomalyDetector(AnomalyThresholds{
+		Alpha:           0.1,
+		ZThreshold:      4,
+		WarmupIntervals: 5,
+		MaxTenants:      2,
+		MinUploads:      20,
+		MinAgentCostUSD: 1,
+		MinRequests:     50,
+	})
+}
+
+// closeIntervals records the same activity for a tenant over n intervals
+func closeIntervals(d *UsageAnomalyDetector, n int, record func()) []models.UsageAnomaly {
+	var anomalies []models.UsageAnomaly
+	end := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < n; i++ {
+		record()
+		end = end.Add(5 * time.Minute)
+		anomalies = append(anomalies, d.Close(end)...)
+	}
+	return anomalies
+}
+
+func TestUsageAnomalyDetectorFlagsUploadSpike(t *testing.T) {
+	d := newTestAnomalyDetector()
+	uploads := func(n int) func() {
+		return func() {
+			for i := 0; i < n; i++ {
+				d.RecordUpload("tenant-a")
+			}
+		}
+	}
+
+	assert.Empty(t, closeIntervals(d, 10, uploads(10)))
+
+	anomalies := closeIntervals(d, 1, uploads(200))
+	require.Len(t, anomalies, 1)
+	assert.Equal(t, "tenant-a", anomalies[0].TenantID)
+	assert.Equal(t, models.UsageSignalUploads, anomalies[0].Signal)
+	assert.Equal(t, float64(200), anomalies[0].Value)
+	assert.InDelta(t, 10, anomalies[0].Baseline, 0.01)
+	assert.Greater(t, anomalies[0].ZScore, 4.0)
+}
+
+func TestUsageAnomalyDetectorIgnoresSmallAndEarlyChanges(t *testing.T) {
+	t.Run("below minimum", func(t *testing.T) {
+		d := newTestAnomalyDetector()
+		closeIntervals(d, 10, func() { d.RecordUpload("tenant-a") })
+		// Far above a flat baseline, but under MinUploads
+		anomalies := closeIntervals(d, 1, func() {
+			for i := 0; i < 15; i++ {
+				d.RecordUpload("tenant-a")
+			}
+		})
+		assert.Empty(t, anomalies)
+	})
+
+	t.Run("during warmup", func(t *testing.T) {
+		d := newTestAnomalyDetector()
+		closeIntervals(d, 2, func() { d.RecordUpload("tenant-a") })
+		anomalies := closeIntervals(d, 1, func() {
+			for i := 0; i < 500; i++ {
+				d.RecordUpload("tenant-a")
+			}
+		})
+		assert.Empty(t, anomalies)
+	})
+}
+
+func TestUsageAnomalyDetectorErrorRate(t *testing.T) {
+	d := newTestAnomalyDetector()
+	requests := func(total, failed int) func() {
+		return func() {
+			for i := 0; i < total; i++ {
+				status := http.StatusOK
+				if i < failed {
+					status = http.StatusInternalServerError
+				}
+				d.RecordRequest("tenant-a", status)
+			}
+		}
+	}
+
+	assert.Empty(t, closeIntervals(d, 10, requests(100, 1)))
+
+	// Too few requests to judge
+	assert.Empty(t, closeIntervals(d, 1, requests(10, 10)))
+
+	anomalies := closeIntervals(d, 1, requests(100, 40))
+	require.Len(t, anomalies, 1)
+	assert.Equal(t, models.UsageSignalErrorRate, anomalies[0].Signal)
+	assert.InDelta(t, 0.4, anomalies[0].Value, 0.001)
+}
+
+func TestUsageAnomalyDetectorBoundsTenants(t *testing.T) {
+	d := newTestAnomalyDetector()
+	d.RecordUpload("tenant-a")
+	d.RecordUpload("tenant-b")
+	d.RecordUpload("tenant-c")
+	d.RecordUpload("")
+	assert.Equal(t, 2, d.TrackedTenants())
+
+	// Idle tenants are forgotten, freeing room for new ones
+	closeIntervals(d, anomalyIdleIntervals+1, func() {})
+	assert.Equal(t, 0, d.TrackedTenants())
+}
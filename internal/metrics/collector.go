@@ -8,6 +8,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/health"
 	"github.com/Tributary-ai-services/aether-be/internal/logger"
 )
 
@@ -32,6 +33,7 @@ type MetricsCollector struct {
 	systemCollector     *SystemMetricsCollector
 	businessCollector   *BusinessMetricsCollector
 	connectionCollector *ConnectionMetricsCollector
+	healthCollector     *DependencyHealthCollector
 
 	// Database clients for metrics collection
 	neo4j *database.Neo4jClient
@@ -57,6 +59,13 @@ func NewMetricsCollector(
 	}
 }
 
+// SetHealthRegistry enables periodic dependency health checks. The results
+// are exported as metrics and served by the system status endpoint.
+func (mc *MetricsCollector) SetHealthRegistry(registry *health.Registry) {
+	registry.SetRecorder(mc.metrics)
+	mc.healthCollector = NewDependencyHealthCollector(registry, mc.logger)
+}
+
 // Start starts all metric collection processes
 func (mc *MetricsCollector) Start(ctx context.Context) {
 	mc.logger.Info("Starting metrics collection")
@@ -70,6 +79,11 @@ func (mc *MetricsCollector) Start(ctx context.Context) {
 	// Start connection metrics collection
 	go mc.connectionCollector.Start(ctx)
 
+	// Start dependency health checks
+	if mc.healthCollector != nil {
+		go mc.healthCollector.Start(ctx)
+	}
+
 	mc.logger.Info("All metrics collectors started")
 }
 
@@ -87,6 +101,9 @@ func (mc *MetricsCollector) Stop() {
 	if mc.connectionCollector != nil {
 		close(mc.connectionCollector.done)
 	}
+	if mc.healthCollector != nil {
+		close(mc.healthCollector.done)
+	}
 
 	close(mc.done)
 	mc.logger.Info("All metrics collectors stopped")
@@ -273,4 +290,57 @@ func (cmc *ConnectionMetricsCollector) collectConnectionMetrics(ctx context.Cont
 	cmc.logger.Debug("Connection metrics collected")
 }
 
+// DependencyHealthCollector periodically checks external dependencies
+type DependencyHealthCollector struct {
+	registry *health.Registry
+	logger   *logger.Logger
+	done     chan struct{}
+}
+
+// NewDependencyHealthCollector creates a new dependency health collector
+func NewDependencyHealthCollector(registry *health.Registry, log *logger.Logger) *DependencyHealthCollector {
+	return &DependencyHealthCollector{
+		registry: registry,
+		logger:   log.WithService("dependency_health"),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start starts the dependency health checks
+func (dhc *DependencyHealthCollector) Start(ctx context.Context) {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	dhc.logger.Info("Starting dependency health checks")
+
+	// Check once at startup so status is available immediately
+	dhc.collectDependencyHealth(ctx)
+
+	for {
+		select {
+		case <-ticker.C:
+			dhc.collectDependencyHealth(ctx)
+		case <-ctx.Done():
+			dhc.logger.Info("Dependency health checks stopped by context")
+			return
+		case <-dhc.done:
+			dhc.logger.Info("Dependency health checks stopped")
+			return
+		}
+	}
+}
+
+// collectDependencyHealth runs all dependency checks and logs failures
+func (dhc *DependencyHealthCollector) collectDependencyHealth(ctx context.Context) {
+	for _, status := range dhc.registry.Check(ctx, false) {
+		if status.Status != health.StatusHealthy {
+			dhc.logger.Warn("Dependency unhealthy",
+				zap.String("dependency", status.Name),
+				zap.Bool("critical", status.Critical),
+				zap.String("error", status.LastError),
+			)
+		}
+	}
+}
+
 // Example methods that would be implemented for real business metrics
@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ExternalTransportOptions customise how an ExternalTransport labels requests
+type ExternalTransportOptions struct {
+	// Service names the dependency a request goes to; defaults to the
+	// service passed to NewExternalTransport
+	Service func(*http.Request) string
+	// Operation names the called endpoint; defaults to the method and the
+	// path with IDs replaced
+	Operation func(*http.Request) string
+	// Attempt returns the 1-based attempt number of a request; requests
+	// after the first are counted as retries. Defaults to 1.
+	Attempt func(*http.Request) int
+}
+
+// ExternalTransport is an http.RoundTripper that records latency, status
+// codes and retries of calls to an external service. Clients create it
+// when they build their HTTP client; nothing is recorded until SetMetrics
+// attaches the metrics. No circuit breaker guards these calls, so there is
+// no breaker state to export; a failing dependency shows as errors in
+// external_requests_total and as down in dependency_up.
+type ExternalTransport struct {
+	service string
+	next    http.RoundTripper
+	opts    ExternalTransportOptions
+	metrics atomic.Pointer[Metrics]
+}
+
+// NewExternalTransport wraps next (http.DefaultTransport when nil) with
+// metrics for calls to service
+func NewExternalTransport(service string, next http.RoundTripper, opts ExternalTransportOptions) *ExternalTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &ExternalTransport{service: service, next: next, opts: opts}
+}
+
+// SetMetrics attaches the metrics requests are recorded to
+func (t *ExternalTransport) SetMetrics(m *Metrics) {
+	t.metrics.Store(m)
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *ExternalTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	m := t.metrics.Load()
+	if m == nil {
+		return t.next.RoundTrip(req)
+	}
+
+	service := t.service
+	if t.opts.Service != nil {
+		service = t.opts.Service(req)
+	}
+	operation := req.Method + " " + cleanPathForMetrics(req.URL.Path)
+	if t.opts.Operation != nil {
+		operation = t.opts.Operation(req)
+	}
+	if t.opts.Attempt != nil && t.opts.Attempt(req) > 1 {
+		m.RecordExternalRetry(service, operation)
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	duration := time.Since(start)
+
+	status := "success"
+	if err != nil || resp.StatusCode >= http.StatusInternalServerError {
+		status = "error"
+	}
+	m.RecordExternalRequest(service, operation, status, duration)
+
+	statusCode := "error"
+	if err == nil {
+		statusCode = strconv.Itoa(resp.StatusCode)
+	}
+	m.externalResponsesTotal.WithLabelValues(service, operation, statusCode).Inc()
+
+	return resp, err
+}
+
+// RecordExternalRetry counts a retried call to an external service
+func (m *Metrics) RecordExternalRetry(service, operation string) {
+	m.externalRequestRetries.WithLabelValues(service, operation).Inc()
+}
+
+// AWSAttempt returns the attempt number the AWS SDK sets in the
+// amz-sdk-request header ("attempt=2; max=3"), for use as
+// ExternalTransportOptions.Attempt
+func AWSAttempt(req *http.Request) int {
+	for _, part := range strings.Split(req.Header.Get("amz-sdk-request"), ";") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(part), "attempt="); ok {
+			if attempt, err := strconv.Atoi(value); err == nil {
+				return attempt
+			}
+		}
+	}
+	return 1
+}
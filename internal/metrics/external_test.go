@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+)
+
+func TestExternalTransportRecordsCalls(t *testing.T) {
+	log, err := logger.New(logger.Config{Level: "error", Format: "json"})
+	require.NoError(t, err)
+	m := NewMetrics(log)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	transport := NewExternalTransport("audimodal", nil, ExternalTransportOptions{
+		Attempt: AWSAttempt,
+	})
+	client := &http.Client{Transport: transport}
+	get := func(path, attempt string) {
+		req, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		require.NoError(t, err)
+		if attempt != "" {
+			req.Header.Set("amz-sdk-request", attempt)
+		}
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	// Nothing is recorded before the metrics are attached
+	get("/fail", "")
+	assert.Equal(t, 0, testutil.CollectAndCount(m.externalResponsesTotal))
+
+	transport.SetMetrics(m)
+	get("/api/v1/tenants/5f1b0c9e-3c1a-4f6e-9a53-2b1e6f0c7d11/files", "")
+	get("/api/v1/tenants/7c2d4e6f-8a9b-4c1d-8e2f-3a4b5c6d7e8f/files", "attempt=2; max=3")
+	get("/fail", "attempt=1; max=3")
+
+	const filesOp = "GET /api/v1/tenants/:id/files"
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.externalResponsesTotal.WithLabelValues("audimodal", filesOp, "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.externalResponsesTotal.WithLabelValues("audimodal", "GET /fail", "502")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.externalRequestsTotal.WithLabelValues("audimodal", "GET /fail", "error")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.externalRequestRetries.WithLabelValues("audimodal", filesOp)))
+}
+
+func TestCleanPathForMetrics(t *testing.T) {
+	tests := map[string]string{
+		"/api/v1/agents/5f1b0c9e-3c1a-4f6e-9a53-2b1e6f0c7d11/execute": "/api/v1/agents/:id/execute",
+		"/api/v1/jobs/42":                                 "/api/v1/jobs/:id",
+		"/api/v1/files/a1b2c3d4e5f6a7b8c9d0":              "/api/v1/files/:id",
+		"/realms/aether/protocol/openid-connect/userinfo": "/realms/aether/protocol/openid-connect/userinfo",
+		"/api/v1/documents/upload-base64":                 "/api/v1/documents/upload-base64",
+	}
+	for path, want := range tests {
+		assert.Equal(t, want, cleanPathForMetrics(path), path)
+	}
+}
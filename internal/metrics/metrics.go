@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -22,11 +23,28 @@ type Metrics struct {
 	httpRequestSize      *prometheus.HistogramVec
 	httpResponseSize     *prometheus.HistogramVec
 
+	// Compression metrics
+	httpCompressedMessages *prometheus.CounterVec
+	httpUncompressedBytes  *prometheus.CounterVec
+	httpCompressedBytes    *prometheus.CounterVec
+
+	// Concurrency limit metrics
+	httpConcurrencyInUse      *prometheus.GaugeVec
+	httpConcurrencyQueued     *prometheus.GaugeVec
+	httpConcurrencyWait       *prometheus.HistogramVec
+	httpConcurrencyRejections *prometheus.CounterVec
+
 	// Database metrics
 	dbConnectionsActive prometheus.Gauge
 	dbConnectionsIdle   prometheus.Gauge
 	dbQueriesTotal      *prometheus.CounterVec
 	dbQueryDuration     *prometheus.HistogramVec
+	dbSlowQueriesTotal  *prometheus.CounterVec
+	dbPoolSlotsInUse    prometheus.Gauge
+	dbPoolSlotsCapacity prometheus.Gauge
+	dbPoolWaiting       prometheus.Gauge
+	dbPoolWaitDuration  prometheus.Histogram
+	dbPoolRejections    prometheus.Counter
 
 	// Redis metrics
 	redisConnectionsActive prometheus.Gauge
@@ -48,6 +66,26 @@ type Metrics struct {
 	// External service metrics
 	externalRequestsTotal   *prometheus.CounterVec
 	externalRequestDuration *prometheus.HistogramVec
+	externalResponsesTotal  *prometheus.CounterVec
+	externalRequestRetries  *prometheus.CounterVec
+	dependencyUp            *prometheus.GaugeVec
+	dependencyCheckDuration *prometheus.GaugeVec
+	syntheticProbeUp        *prometheus.GaugeVec
+	syntheticProbeDuration  *prometheus.GaugeVec
+	syntheticProbeRuns      *prometheus.CounterVec
+
+	// Tenant metrics, labelled through tenantLabels
+	tenantRequestsTotal  *prometheus.CounterVec
+	tenantStorageBytes   *prometheus.CounterVec
+	tenantProcessingJobs *prometheus.CounterVec
+	tenantAgentCost      *prometheus.CounterVec
+	tenantLabels         atomic.Pointer[TenantLabeler]
+
+	// SLO tracking, when enabled
+	sloTracker atomic.Pointer[SLOTracker]
+
+	// Usage anomaly detection, when enabled
+	anomalyDetector atomic.Pointer[UsageAnomalyDetector]
 
 	// System metrics
 	goroutinesActive prometheus.Gauge
@@ -100,6 +138,60 @@ func NewMetrics(log *logger.Logger) *Metrics {
 			[]string{"method", "endpoint"},
 		),
 
+		// Compression metrics
+		httpCompressedMessages: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "http_compressed_messages_total",
+				Help: "Compressed request and response bodies, by direction and content encoding",
+			},
+			[]string{"direction", "encoding"},
+		),
+		httpUncompressedBytes: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "http_compression_uncompressed_bytes_total",
+				Help: "Size before compression of compressed request and response bodies",
+			},
+			[]string{"direction", "encoding"},
+		),
+		httpCompressedBytes: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "http_compression_compressed_bytes_total",
+				Help: "Size on the wire of compressed request and response bodies",
+			},
+			[]string{"direction", "encoding"},
+		),
+
+		// Concurrency limit metrics
+		httpConcurrencyInUse: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "http_concurrency_in_use",
+				Help: "API requests being served, by concurrency class",
+			},
+			[]string{"class"},
+		),
+		httpConcurrencyQueued: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "http_concurrency_queued",
+				Help: "API requests waiting for a concurrency slot, by class",
+			},
+			[]string{"class"},
+		),
+		httpConcurrencyWait: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "http_concurrency_wait_duration_seconds",
+				Help:    "Time API requests waited for a concurrency slot, by class",
+				Buckets: []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5},
+			},
+			[]string{"class"},
+		),
+		httpConcurrencyRejections: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "http_concurrency_rejections_total",
+				Help: "API requests rejected for want of a concurrency slot, by class",
+			},
+			[]string{"class"},
+		),
+
 		// Database metrics
 		dbConnectionsActive: prometheus.NewGauge(
 			prometheus.GaugeOpts{
@@ -128,6 +220,44 @@ func NewMetrics(log *logger.Logger) *Metrics {
 			},
 			[]string{"database", "operation"},
 		),
+		dbSlowQueriesTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "database_slow_queries_total",
+				Help: "Total number of queries exceeding the slow query threshold, per query name",
+			},
+			[]string{"database", "query"},
+		),
+		dbPoolSlotsInUse: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "database_pool_slots_in_use",
+				Help: "Number of database operations currently admitted by the pool gate",
+			},
+		),
+		dbPoolSlotsCapacity: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "database_pool_slots_capacity",
+				Help: "Maximum number of database operations the pool gate admits at once",
+			},
+		),
+		dbPoolWaiting: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "database_pool_waiting",
+				Help: "Number of operations queued for a database connection",
+			},
+		),
+		dbPoolWaitDuration: prometheus.NewHistogram(
+			prometheus.HistogramOpts{
+				Name:    "database_pool_wait_duration_seconds",
+				Help:    "Time spent waiting for a database connection",
+				Buckets: []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5},
+			},
+		),
+		dbPoolRejections: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "database_pool_rejections_total",
+				Help: "Total number of operations shed because the database pool was saturated",
+			},
+		),
 
 		// Redis metrics
 		redisConnectionsActive: prometheus.NewGauge(
@@ -229,6 +359,85 @@ func NewMetrics(log *logger.Logger) *Metrics {
 			},
 			[]string{"service", "operation"},
 		),
+		externalResponsesTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "external_responses_total",
+				Help: "Total number of external service responses by HTTP status code",
+			},
+			[]string{"service", "operation", "status_code"},
+		),
+		externalRequestRetries: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "external_request_retries_total",
+				Help: "Total number of retried external service requests",
+			},
+			[]string{"service", "operation"},
+		),
+		dependencyUp: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "dependency_up",
+				Help: "Whether the last health check of a dependency succeeded (1) or failed (0)",
+			},
+			[]string{"dependency"},
+		),
+		dependencyCheckDuration: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "dependency_check_duration_seconds",
+				Help: "Duration of the last health check of a dependency in seconds",
+			},
+			[]string{"dependency"},
+		),
+		syntheticProbeUp: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "synthetic_probe_up",
+				Help: "Whether the last run of a synthetic probe succeeded (1) or failed (0)",
+			},
+			[]string{"probe"},
+		),
+		syntheticProbeDuration: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "synthetic_probe_duration_seconds",
+				Help: "Duration of the last run of a synthetic probe in seconds",
+			},
+			[]string{"probe"},
+		),
+		syntheticProbeRuns: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "synthetic_probe_runs_total",
+				Help: "Total number of synthetic probe runs by result",
+			},
+			[]string{"probe", "result"},
+		),
+
+		// Tenant metrics
+		tenantRequestsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "tenant_http_requests_total",
+				Help: "Total number of API requests per tenant",
+			},
+			[]string{"tenant", "status_class"},
+		),
+		tenantStorageBytes: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "tenant_storage_bytes_total",
+				Help: "Total bytes uploaded to or downloaded from storage per tenant",
+			},
+			[]string{"tenant", "operation"},
+		),
+		tenantProcessingJobs: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "tenant_processing_jobs_total",
+				Help: "Total number of document processing jobs per tenant by status",
+			},
+			[]string{"tenant", "status"},
+		),
+		tenantAgentCost: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "tenant_agent_cost_usd_total",
+				Help: "Total agent execution cost in USD per tenant",
+			},
+			[]string{"tenant"},
+		),
 
 		// System metrics
 		goroutinesActive: prometheus.NewGauge(
@@ -252,10 +461,23 @@ func NewMetrics(log *logger.Logger) *Metrics {
 		m.httpRequestsInFlight,
 		m.httpRequestSize,
 		m.httpResponseSize,
+		m.httpCompressedMessages,
+		m.httpUncompressedBytes,
+		m.httpCompressedBytes,
+		m.httpConcurrencyInUse,
+		m.httpConcurrencyQueued,
+		m.httpConcurrencyWait,
+		m.httpConcurrencyRejections,
 		m.dbConnectionsActive,
 		m.dbConnectionsIdle,
 		m.dbQueriesTotal,
 		m.dbQueryDuration,
+		m.dbSlowQueriesTotal,
+		m.dbPoolSlotsInUse,
+		m.dbPoolSlotsCapacity,
+		m.dbPoolWaiting,
+		m.dbPoolWaitDuration,
+		m.dbPoolRejections,
 		m.redisConnectionsActive,
 		m.redisOperationsTotal,
 		m.redisOperationDuration,
@@ -269,10 +491,24 @@ func NewMetrics(log *logger.Logger) *Metrics {
 		m.storageBytesTotal,
 		m.externalRequestsTotal,
 		m.externalRequestDuration,
+		m.externalResponsesTotal,
+		m.externalRequestRetries,
+		m.dependencyUp,
+		m.dependencyCheckDuration,
+		m.syntheticProbeUp,
+		m.syntheticProbeDuration,
+		m.syntheticProbeRuns,
+		m.tenantRequestsTotal,
+		m.tenantStorageBytes,
+		m.tenantProcessingJobs,
+		m.tenantAgentCost,
 		m.goroutinesActive,
 		m.memoryUsage,
 	)
 
+	// Tenants are aggregated until a labeler is configured
+	m.SetTenantLabeler(NewTenantLabeler(nil, 0))
+
 	m.logger.Info("Prometheus metrics initialized")
 	return m
 }
@@ -289,6 +525,31 @@ func (m *Metrics) RecordHTTPRequest(method, path string, statusCode int, duratio
 	m.httpResponseSize.WithLabelValues(method, path).Observe(float64(responseSize))
 }
 
+// RecordCompression records a compressed request (direction "request") or
+// response ("response") body, before and after compression
+func (m *Metrics) RecordCompression(direction, encoding string, uncompressedBytes, compressedBytes int64) {
+	m.httpCompressedMessages.WithLabelValues(direction, encoding).Inc()
+	m.httpUncompressedBytes.WithLabelValues(direction, encoding).Add(float64(uncompressedBytes))
+	m.httpCompressedBytes.WithLabelValues(direction, encoding).Add(float64(compressedBytes))
+}
+
+// SetConcurrencyUsage records the requests of a concurrency class being
+// served and waiting
+func (m *Metrics) SetConcurrencyUsage(class string, inUse, queued int) {
+	m.httpConcurrencyInUse.WithLabelValues(class).Set(float64(inUse))
+	m.httpConcurrencyQueued.WithLabelValues(class).Set(float64(queued))
+}
+
+// RecordConcurrencyWait records how long a request waited for a slot of
+// its concurrency class, or that it was rejected
+func (m *Metrics) RecordConcurrencyWait(class string, wait time.Duration, rejected bool) {
+	if rejected {
+		m.httpConcurrencyRejections.WithLabelValues(class).Inc()
+		return
+	}
+	m.httpConcurrencyWait.WithLabelValues(class).Observe(wait.Seconds())
+}
+
 // IncHTTPRequestsInFlight increments the in-flight requests counter
 func (m *Metrics) IncHTTPRequestsInFlight() {
 	m.httpRequestsInFlight.Inc()
@@ -313,6 +574,29 @@ func (m *Metrics) RecordDBQuery(database, operation, status string, duration tim
 	m.dbQueryDuration.WithLabelValues(database, operation).Observe(duration.Seconds())
 }
 
+// RecordSlowQuery increments the slow query counter for a named query
+func (m *Metrics) RecordSlowQuery(database, query string) {
+	m.dbSlowQueriesTotal.WithLabelValues(database, query).Inc()
+}
+
+// SetDBPoolUsage sets the pool gate gauges. Gate slots bound concurrent
+// operations; they are not driver connections.
+func (m *Metrics) SetDBPoolUsage(inUse, capacity, waiting int) {
+	m.dbPoolSlotsInUse.Set(float64(inUse))
+	m.dbPoolSlotsCapacity.Set(float64(capacity))
+	m.dbPoolWaiting.Set(float64(waiting))
+}
+
+// RecordDBPoolWait records how long an operation waited for a connection,
+// or a rejection when it was shed
+func (m *Metrics) RecordDBPoolWait(wait time.Duration, rejected bool) {
+	if rejected {
+		m.dbPoolRejections.Inc()
+		return
+	}
+	m.dbPoolWaitDuration.Observe(wait.Seconds())
+}
+
 // Redis Metrics methods
 
 // SetRedisConnections sets the number of active Redis connections
@@ -372,6 +656,27 @@ func (m *Metrics) RecordExternalRequest(service, operation, status string, durat
 	m.externalRequestDuration.WithLabelValues(service, operation).Observe(duration.Seconds())
 }
 
+// SetDependencyHealth records the result of a dependency health check
+func (m *Metrics) SetDependencyHealth(dependency string, healthy bool, latency time.Duration) {
+	up := 0.0
+	if healthy {
+		up = 1
+	}
+	m.dependencyUp.WithLabelValues(dependency).Set(up)
+	m.dependencyCheckDuration.WithLabelValues(dependency).Set(latency.Seconds())
+}
+
+// RecordSyntheticProbe records the result of a synthetic probe run
+func (m *Metrics) RecordSyntheticProbe(probe string, success bool, duration time.Duration) {
+	up, result := 0.0, "failure"
+	if success {
+		up, result = 1, "success"
+	}
+	m.syntheticProbeUp.WithLabelValues(probe).Set(up)
+	m.syntheticProbeDuration.WithLabelValues(probe).Set(duration.Seconds())
+	m.syntheticProbeRuns.WithLabelValues(probe, result).Inc()
+}
+
 // System Metrics methods
 
 // SetGoroutines sets the number of active goroutines
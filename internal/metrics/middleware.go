@@ -1,9 +1,11 @@
 package metrics
 
 import (
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 
 	"github.com/Tributary-ai-services/aether-be/internal/logger"
@@ -48,6 +50,8 @@ func HTTPMetricsMiddleware(metrics *Metrics, log *logger.Logger) gin.HandlerFunc
 			requestSize,
 			responseSize,
 		)
+		recordTenantRequest(metrics, c)
+		recordSLORequest(metrics, c, duration)
 
 		// Log slow requests
 		if duration > 5*time.Second {
@@ -74,16 +78,35 @@ func getCleanPath(routePath, requestPath string) string {
 	return cleanPathForMetrics(requestPath)
 }
 
-// cleanPathForMetrics cleans up the path for metrics by replacing common variable parts
+// cleanPathForMetrics replaces path segments that look like IDs with :id so
+// each endpoint maps to one label value
 func cleanPathForMetrics(path string) string {
-	// This is a simple implementation - could be enhanced with regex
-	// Replace common UUID patterns with placeholders
-	cleaned := path
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if isIDSegment(segment) {
+			segments[i] = ":id"
+		}
+	}
+	return strings.Join(segments, "/")
+}
 
-	// Replace UUIDs in path with :id placeholder
-	// This is a simple approach - in production, you might want more sophisticated path normalization
+// isIDSegment reports whether a path segment is a UUID, a number or another
+// opaque identifier rather than a fixed part of the route
+func isIDSegment(segment string) bool {
+	if segment == "" {
+		return false
+	}
+	if _, err := uuid.Parse(segment); err == nil {
+		return true
+	}
 
-	return cleaned
+	digits := 0
+	for _, r := range segment {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	return digits == len(segment) || (len(segment) >= 16 && digits > 0)
 }
 
 // Note: SystemMetricsCollector and BusinessMetricsCollector are now in collector.go
@@ -0,0 +1,351 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+// sloLatencyBucketsMs bounds the latency histogram p95 is estimated from
+var sloLatencyBucketsMs = []float64{10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000, 60000}
+
+// sloLatencyPercentile is the fraction of requests a latency objective
+// covers, so 5% of requests may exceed the threshold
+const sloLatencyPercentile = 0.95
+
+// sloMinAlertEvents is the fewest events a burn rate window needs before it
+// can alert, so a single failure on an idle replica does not page anyone
+const sloMinAlertEvents = 20
+
+// SLOObjectives holds the targets an SLOTracker reports against
+type SLOObjectives struct {
+	WindowDays         int
+	AvailabilityTarget float64
+	LatencyP95Ms       int
+	LatencyOverrides   map[string]int // p95 objective by route group, in milliseconds
+	ProcessingTarget   float64
+	FastBurnRate       float64 // 1h burn rate that alerts
+	SlowBurnRate       float64 // 6h burn rate that alerts
+}
+
+// latencyThreshold returns the p95 objective of a route group
+func (o SLOObjectives) latencyThreshold(group string) time.Duration {
+	if ms, ok := o.LatencyOverrides[group]; ok {
+		return time.Duration(ms) * time.Millisecond
+	}
+	return time.Duration(o.LatencyP95Ms) * time.Millisecond
+}
+
+// sloCounts counts events and the bad ones among them. Latency series also
+// keep a histogram over sloLatencyBucketsMs plus an overflow bucket.
+type sloCounts struct {
+	total   int64
+	bad     int64
+	latency []int64
+}
+
+func (c *sloCounts) add(other sloCounts) {
+	c.total += other.total
+	c.bad += other.bad
+	for i, n := range other.latency {
+		c.latency[i] += n
+	}
+}
+
+type sloSlot struct {
+	index  int64
+	counts sloCounts
+}
+
+// sloRing keeps counts in fixed-width time slots, reusing the oldest slot
+// once the ring wraps
+type sloRing struct {
+	width time.Duration
+	slots []sloSlot
+}
+
+func newSLORing(width time.Duration, size int, latency bool) *sloRing {
+	r := &sloRing{width: width, slots: make([]sloSlot, size)}
+	if latency {
+		for i := range r.slots {
+			r.slots[i].counts.latency = make([]int64, len(sloLatencyBucketsMs)+1)
+		}
+	}
+	return r
+}
+
+// slot returns the counts of the slot covering now
+func (r *sloRing) slot(now time.Time) *sloCounts {
+	index := now.UnixNano() / int64(r.width)
+	s := &r.slots[index%int64(len(r.slots))]
+	if s.index != index {
+		s.index = index
+		s.counts.total, s.counts.bad = 0, 0
+		for i := range s.counts.latency {
+			s.counts.latency[i] = 0
+		}
+	}
+	return &s.counts
+}
+
+// sum adds up the slots covering the span ending at now
+func (r *sloRing) sum(now time.Time, span time.Duration) sloCounts {
+	current := now.UnixNano() / int64(r.width)
+	oldest := current - int64(span/r.width) + 1
+
+	var total sloCounts
+	if r.slots[0].counts.latency != nil {
+		total.latency = make([]int64, len(sloLatencyBucketsMs)+1)
+	}
+	for _, s := range r.slots {
+		if s.index >= oldest && s.index <= current {
+			total.add(s.counts)
+		}
+	}
+	return total
+}
+
+// sloSeries records one SLI at minute resolution for burn rates and at
+// hour resolution for the compliance window
+type sloSeries struct {
+	recent *sloRing
+	window *sloRing
+}
+
+func newSLOSeries(windowDays int, latency bool) *sloSeries {
+	return &sloSeries{
+		recent: newSLORing(time.Minute, 6*60, latency),
+		window: newSLORing(time.Hour, windowDays*24, latency),
+	}
+}
+
+func (s *sloSeries) record(now time.Time, bad bool, bucket int) {
+	for _, r := range []*sloRing{s.recent, s.window} {
+		counts := r.slot(now)
+		counts.total++
+		if bad {
+			counts.bad++
+		}
+		if bucket >= 0 {
+			counts.latency[bucket]++
+		}
+	}
+}
+
+// SLOTracker computes service level indicators from the requests and
+// processing jobs this replica handles. Like the rate limits, the counts
+// are kept in memory per replica and start empty after a restart.
+type SLOTracker struct {
+	objectives SLOObjectives
+	now        func() time.Time
+
+	mu           sync.Mutex
+	availability *sloSeries
+	processing   *sloSeries
+	latency      map[string]*sloSeries
+}
+
+// NewSLOTracker creates a tracker reporting against the given objectives
+func NewSLOTracker(objectives SLOObjectives) *SLOTracker {
+	return &SLOTracker{
+		objectives:   objectives,
+		now:          time.Now,
+		availability: newSLOSeries(objectives.WindowDays, false),
+		processing:   newSLOSeries(objectives.WindowDays, false),
+		latency:      make(map[string]*sloSeries),
+	}
+}
+
+// RecordRequest counts an API request against availability and the latency
+// objective of its route group. route is the registered route pattern, so
+// the set of groups is bounded by the routes.
+func (t *SLOTracker) RecordRequest(route string, statusCode int, duration time.Duration) {
+	group := routeGroup(route)
+	if group == "" {
+		return
+	}
+	now := t.now()
+	slow := duration > t.objectives.latencyThreshold(group)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.availability.record(now, statusCode >= 500, -1)
+
+	series, ok := t.latency[group]
+	if !ok {
+		series = newSLOSeries(t.objectives.WindowDays, true)
+		t.latency[group] = series
+	}
+	series.record(now, slow, latencyBucket(duration))
+}
+
+// RecordProcessing counts the outcome of a document processing job
+func (t *SLOTracker) RecordProcessing(success bool) {
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.processing.record(now, !success, -1)
+}
+
+// Report returns the current compliance and burn rate of every objective.
+// Latency objectives are listed for the route groups that have traffic.
+func (t *SLOTracker) Report() *models.SLOReport {
+	now := t.now()
+	o := t.objectives
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	report := &models.SLOReport{
+		GeneratedAt: now.UTC(),
+		WindowDays:  o.WindowDays,
+	}
+	report.Objectives = append(report.Objectives,
+		t.status(now, t.availability, models.SLOAvailability, o.AvailabilityTarget))
+
+	groups := make([]string, 0, len(t.latency))
+	for group := range t.latency {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	for _, group := range groups {
+		status := t.status(now, t.latency[group], models.SLOLatency, sloLatencyPercentile)
+		status.RouteGroup = group
+		status.LatencyThresholdMs = int(o.latencyThreshold(group) / time.Millisecond)
+		status.P95LatencyMs = percentileMs(t.latency[group].window.sum(now, t.windowSpan()).latency, sloLatencyPercentile)
+		status.Compliant = status.P95LatencyMs <= float64(status.LatencyThresholdMs)
+		report.Objectives = append(report.Objectives, status)
+	}
+
+	report.Objectives = append(report.Objectives,
+		t.status(now, t.processing, models.SLOProcessingSuccess, o.ProcessingTarget))
+	return report
+}
+
+func (t *SLOTracker) windowSpan() time.Duration {
+	return time.Duration(t.objectives.WindowDays) * 24 * time.Hour
+}
+
+// status computes an objective's SLI, remaining budget and burn rates
+func (t *SLOTracker) status(now time.Time, series *sloSeries, objective string, target float64) models.SLOStatus {
+	budget := 1 - target
+	window := series.window.sum(now, t.windowSpan())
+	lastHour := series.recent.sum(now, time.Hour)
+	lastSixHours := series.recent.sum(now, 6*time.Hour)
+
+	status := models.SLOStatus{
+		Objective:            objective,
+		Target:               target,
+		SLI:                  1,
+		Events:               window.total,
+		BadEvents:            window.bad,
+		ErrorBudgetRemaining: 1,
+		BurnRate1h:           burnRate(lastHour, budget),
+		BurnRate6h:           burnRate(lastSixHours, budget),
+	}
+	if window.total > 0 {
+		status.SLI = 1 - float64(window.bad)/float64(window.total)
+		status.ErrorBudgetRemaining = 1 - burnRate(window, budget)
+	}
+	status.Compliant = status.SLI >= target
+	status.Alerting = (lastHour.total >= sloMinAlertEvents && status.BurnRate1h > t.objectives.FastBurnRate) ||
+		(lastSixHours.total >= sloMinAlertEvents && status.BurnRate6h > t.objectives.SlowBurnRate)
+	return status
+}
+
+// burnRate is the bad-event rate relative to the rate the budget allows
+func burnRate(counts sloCounts, budget float64) float64 {
+	if counts.total == 0 || budget <= 0 {
+		return 0
+	}
+	return float64(counts.bad) / float64(counts.total) / budget
+}
+
+// latencyBucket returns the histogram bucket of a duration
+func latencyBucket(d time.Duration) int {
+	ms := float64(d) / float64(time.Millisecond)
+	return sort.SearchFloat64s(sloLatencyBucketsMs, ms)
+}
+
+// percentileMs estimates a percentile from a latency histogram by linear
+// interpolation within the bucket it falls in. Values in the overflow
+// bucket are reported as the largest bound.
+func percentileMs(buckets []int64, percentile float64) float64 {
+	var total int64
+	for _, n := range buckets {
+		total += n
+	}
+	if total == 0 {
+		return 0
+	}
+
+	rank := percentile * float64(total)
+	var seen int64
+	for i, n := range buckets {
+		if n == 0 || float64(seen+n) < rank {
+			seen += n
+			continue
+		}
+		if i == len(sloLatencyBucketsMs) {
+			return sloLatencyBucketsMs[i-1]
+		}
+		lower := 0.0
+		if i > 0 {
+			lower = sloLatencyBucketsMs[i-1]
+		}
+		fraction := (rank - float64(seen)) / float64(n)
+		return math.Round(lower + fraction*(sloLatencyBucketsMs[i]-lower))
+	}
+	return sloLatencyBucketsMs[len(sloLatencyBucketsMs)-1]
+}
+
+// routeGroup returns the path segment after /api/v1/ of a route pattern.
+// Routes outside the API, such as health probes, have no group.
+func routeGroup(route string) string {
+	rest, ok := strings.CutPrefix(route, "/api/v1/")
+	if !ok {
+		return ""
+	}
+	group, _, _ := strings.Cut(rest, "/")
+	if group == "" || strings.HasPrefix(group, ":") {
+		return ""
+	}
+	return group
+}
+
+// SetSLOTracker starts feeding HTTP requests and processing outcomes into
+// an SLO tracker
+func (m *Metrics) SetSLOTracker(t *SLOTracker) {
+	m.sloTracker.Store(t)
+}
+
+// SLOTracker returns the SLO tracker, or nil when SLOs are not tracked
+func (m *Metrics) SLOTracker() *SLOTracker {
+	return m.sloTracker.Load()
+}
+
+// RecordProcessingOutcome counts a finished processing job against the
+// processing success objective
+func (m *Metrics) RecordProcessingOutcome(success bool) {
+	if t := m.sloTracker.Load(); t != nil {
+		t.RecordProcessing(success)
+	}
+}
+
+// recordSLORequest counts a request against the SLOs. Unmatched routes are
+// skipped; they cannot be attributed to a route group.
+func recordSLORequest(m *Metrics, c *gin.Context, duration time.Duration) {
+	t := m.sloTracker.Load()
+	if t == nil || c.FullPath() == "" {
+		return
+	}
+	t.RecordRequest(c.FullPath(), c.Writer.Status(), duration)
+}
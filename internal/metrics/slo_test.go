@@ -0,0 +1,128 @@
+package metrics
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+func newTestSLOTracker(now *time.Time) *SLOTracker {
+	t := NewSLOTracker(SLOObjectives{
+		WindowDays:         1,
+		AvailabilityTarget: 0.99,
+		LatencyP95Ms:       500,
+		LatencyOverrides:   map[string]int{"documents": 5000},
+		ProcessingTarget:   0.9,
+		FastBurnRate:       14.4,
+		SlowBurnRate:       6,
+	})
+	t.now = func() time.Time { return *now }
+	return t
+}
+
+func findObjective(report *models.SLOReport, objective, group string) models.SLOStatus {
+	for _, status := range report.Objectives {
+		if status.Objective == objective && status.RouteGroup == group {
+			return status
+		}
+	}
+	return models.SLOStatus{}
+}
+
+func TestSLOTrackerAvailabilityBurnRate(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	tracker := newTestSLOTracker(&now)
+
+	for i := 0; i < 100; i++ {
+		tracker.RecordRequest("/api/v1/notebooks/:id", http.StatusOK, 10*time.Millisecond)
+	}
+	tracker.RecordRequest("/api/v1/notebooks", http.StatusInternalServerError, 10*time.Millisecond)
+	tracker.RecordRequest("/health/live", http.StatusServiceUnavailable, time.Millisecond)
+
+	status := findObjective(tracker.Report(), models.SLOAvailability, "")
+	assert.Equal(t, int64(101), status.Events)
+	assert.Equal(t, int64(1), status.BadEvents)
+	assert.True(t, status.Compliant)
+	assert.InDelta(t, 0.99, status.BurnRate1h, 0.01)
+	assert.InDelta(t, 0.01, status.ErrorBudgetRemaining, 0.01)
+	assert.False(t, status.Alerting)
+
+	// A burst of failures burns the budget fast enough to alert
+	now = now.Add(30 * time.Minute)
+	for i := 0; i < 30; i++ {
+		tracker.RecordRequest("/api/v1/notebooks", http.StatusBadGateway, 10*time.Millisecond)
+	}
+	status = findObjective(tracker.Report(), models.SLOAvailability, "")
+	assert.False(t, status.Compliant)
+	assert.True(t, status.Alerting)
+
+	// Once the burst leaves the burn rate windows only the budget remembers it
+	now = now.Add(7 * time.Hour)
+	status = findObjective(tracker.Report(), models.SLOAvailability, "")
+	assert.Zero(t, status.BurnRate1h)
+	assert.Zero(t, status.BurnRate6h)
+	assert.False(t, status.Alerting)
+	assert.Equal(t, int64(31), status.BadEvents)
+
+	// And the compliance window eventually forgets it too
+	now = now.Add(24 * time.Hour)
+	status = findObjective(tracker.Report(), models.SLOAvailability, "")
+	assert.Zero(t, status.Events)
+	assert.Equal(t, float64(1), status.SLI)
+}
+
+func TestSLOTrackerLatencyPerRouteGroup(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	tracker := newTestSLOTracker(&now)
+
+	for i := 0; i < 100; i++ {
+		tracker.RecordRequest("/api/v1/notebooks", http.StatusOK, 80*time.Millisecond)
+		tracker.RecordRequest("/api/v1/documents/upload", http.StatusCreated, 2*time.Second)
+	}
+	for i := 0; i < 10; i++ {
+		tracker.RecordRequest("/api/v1/notebooks", http.StatusOK, 3*time.Second)
+	}
+
+	report := tracker.Report()
+	require.Len(t, report.Objectives, 4)
+
+	notebooks := findObjective(report, models.SLOLatency, "notebooks")
+	assert.Equal(t, 500, notebooks.LatencyThresholdMs)
+	assert.Equal(t, int64(10), notebooks.BadEvents)
+	assert.Greater(t, notebooks.P95LatencyMs, 500.0)
+	assert.False(t, notebooks.Compliant)
+
+	documents := findObjective(report, models.SLOLatency, "documents")
+	assert.Equal(t, 5000, documents.LatencyThresholdMs)
+	assert.Zero(t, documents.BadEvents)
+	assert.InDelta(t, 2500, documents.P95LatencyMs, 500)
+	assert.True(t, documents.Compliant)
+}
+
+func TestSLOTrackerProcessing(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	tracker := newTestSLOTracker(&now)
+
+	for i := 0; i < 19; i++ {
+		tracker.RecordProcessing(true)
+	}
+	tracker.RecordProcessing(false)
+
+	status := findObjective(tracker.Report(), models.SLOProcessingSuccess, "")
+	assert.Equal(t, 0.95, status.SLI)
+	assert.True(t, status.Compliant)
+	assert.InDelta(t, 0.5, status.BurnRate1h, 0.001)
+	assert.InDelta(t, 0.5, status.ErrorBudgetRemaining, 0.001)
+}
+
+func TestRouteGroup(t *testing.T) {
+	assert.Equal(t, "documents", routeGroup("/api/v1/documents/:id/download"))
+	assert.Equal(t, "users", routeGroup("/api/v1/users"))
+	assert.Equal(t, "", routeGroup("/health/ready"))
+	assert.Equal(t, "", routeGroup("/api/v1/"))
+}
@@ -0,0 +1,144 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+// Tenant label values shared by tenants that do not get their own
+const (
+	TenantOther   = "other"
+	TenantUnknown = "unknown"
+)
+
+// spaceContextKey is where middleware.SpaceContextMiddleware stores the
+// resolved space context
+const spaceContextKey = "space_context"
+
+// TenantLabeler maps tenant IDs to label values, bounding the number of
+// series tenant metrics create. With an allowlist only the listed tenants
+// are labelled individually; otherwise the first limit tenants seen are,
+// until restart. Every other tenant is aggregated under "other".
+type TenantLabeler struct {
+	allowlist map[string]bool
+	limit     int
+
+	mu   sync.RWMutex
+	seen map[string]bool
+}
+
+// NewTenantLabeler creates a labeler. A zero limit with no allowlist
+// aggregates all tenants.
+func NewTenantLabeler(allowlist []string, limit int) *TenantLabeler {
+	l := &TenantLabeler{limit: limit, seen: make(map[string]bool)}
+	if len(allowlist) > 0 {
+		l.allowlist = make(map[string]bool, len(allowlist))
+		for _, tenantID := range allowlist {
+			if tenantID = strings.TrimSpace(tenantID); tenantID != "" {
+				l.allowlist[tenantID] = true
+			}
+		}
+		if len(l.allowlist) == 0 {
+			l.allowlist = nil
+		}
+	}
+	return l
+}
+
+// Label returns the label value for a tenant
+func (l *TenantLabeler) Label(tenantID string) string {
+	if tenantID == "" {
+		return TenantUnknown
+	}
+	if l.allowlist != nil {
+		if l.allowlist[tenantID] {
+			return tenantID
+		}
+		return TenantOther
+	}
+
+	l.mu.RLock()
+	seen, full := l.seen[tenantID], len(l.seen) >= l.limit
+	l.mu.RUnlock()
+	if seen {
+		return tenantID
+	}
+	if full {
+		return TenantOther
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.seen) >= l.limit {
+		return TenantOther
+	}
+	l.seen[tenantID] = true
+	return tenantID
+}
+
+// SetTenantLabeler replaces the labeler used by tenant metrics
+func (m *Metrics) SetTenantLabeler(l *TenantLabeler) {
+	m.tenantLabels.Store(l)
+}
+
+// tenantLabel returns the label value for a tenant
+func (m *Metrics) tenantLabel(tenantID string) string {
+	return m.tenantLabels.Load().Label(tenantID)
+}
+
+// RecordTenantRequest counts an API request made in a tenant's space
+func (m *Metrics) RecordTenantRequest(tenantID string, statusCode int) {
+	statusClass := strconv.Itoa(statusCode/100) + "xx"
+	m.tenantRequestsTotal.WithLabelValues(m.tenantLabel(tenantID), statusClass).Inc()
+	if d := m.anomalyDetector.Load(); d != nil {
+		d.RecordRequest(tenantID, statusCode)
+	}
+}
+
+// RecordTenantStorageBytes counts bytes a tenant moved to or from storage.
+// Each upload also counts towards the tenant's upload anomaly signal.
+func (m *Metrics) RecordTenantStorageBytes(tenantID, operation string, bytes int64) {
+	if bytes > 0 {
+		m.tenantStorageBytes.WithLabelValues(m.tenantLabel(tenantID), operation).Add(float64(bytes))
+	}
+	if d := m.anomalyDetector.Load(); d != nil && operation == "upload" {
+		d.RecordUpload(tenantID)
+	}
+}
+
+// IncTenantProcessingJobs counts a tenant's processing jobs by status
+func (m *Metrics) IncTenantProcessingJobs(tenantID, status string) {
+	m.tenantProcessingJobs.WithLabelValues(m.tenantLabel(tenantID), status).Inc()
+}
+
+// AddTenantAgentCost adds to a tenant's agent execution cost
+func (m *Metrics) AddTenantAgentCost(tenantID string, costUSD float64) {
+	if costUSD > 0 {
+		m.tenantAgentCost.WithLabelValues(m.tenantLabel(tenantID)).Add(costUSD)
+		if d := m.anomalyDetector.Load(); d != nil {
+			d.RecordAgentCost(tenantID, costUSD)
+		}
+	}
+}
+
+// recordTenantRequest counts the request against the tenant of its space,
+// if one was resolved
+func recordTenantRequest(m *Metrics, c *gin.Context) {
+	value, ok := c.Get(spaceContextKey)
+	if !ok {
+		return
+	}
+	if spaceContext, ok := value.(*models.SpaceContext); ok && spaceContext != nil {
+		status := c.Writer.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		m.RecordTenantRequest(spaceContext.TenantID, status)
+	}
+}
@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantLabelerAllowlist(t *testing.T) {
+	l := NewTenantLabeler([]string{"tenant_1", " tenant_2"}, 1)
+
+	assert.Equal(t, "tenant_1", l.Label("tenant_1"))
+	assert.Equal(t, "tenant_2", l.Label("tenant_2"))
+	assert.Equal(t, TenantOther, l.Label("tenant_3"))
+	assert.Equal(t, TenantUnknown, l.Label(""))
+}
+
+func TestTenantLabelerLimit(t *testing.T) {
+	l := NewTenantLabeler(nil, 3)
+
+	for i := 0; i < 3; i++ {
+		tenantID := fmt.Sprintf("tenant_%d", i)
+		assert.Equal(t, tenantID, l.Label(tenantID))
+	}
+	assert.Equal(t, TenantOther, l.Label("tenant_3"))
+
+	// Tenants labelled before the limit was reached keep their label
+	assert.Equal(t, "tenant_0", l.Label("tenant_0"))
+
+	assert.Equal(t, TenantOther, NewTenantLabeler(nil, 0).Label("tenant_0"))
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+// AccessLogExporter receives logged access entries for export
+type AccessLogExporter interface {
+	Export(entry *models.AccessLogEntry)
+}
+
+// AccessLog writes a structured access log entry for each sampled request,
+// apart from application logs, and hands it to exporter when set. Errors
+// are kept regardless of sampling when cfg.KeepErrors is set; entries
+// carry the sample rate so counts can be scaled back up.
+func AccessLog(log *logger.Logger, cfg config.AccessLogConfig, exporter AccessLogExporter) gin.HandlerFunc {
+	accessLog := log.WithContext(zap.String("log_type", "access"))
+	skip := make(map[string]bool, len(cfg.SkipPaths))
+	for _, path := range cfg.SkipPaths {
+		skip[path] = true
+	}
+
+	return func(c *gin.Context) {
+		if skip[c.Request.URL.Path] {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		keep := cfg.KeepErrors && status >= http.StatusBadRequest
+		if !keep && !sampled(cfg.SampleRate) {
+			return
+		}
+
+		entry := newAccessLogEntry(c, start, status, cfg.SampleRate)
+		if keep {
+			entry.SampleRate = 1
+		}
+
+		accessLog.Info("access",
+			zap.String("request_id", entry.RequestID),
+			zap.String("method", entry.Method),
+			zap.String("route", entry.Route),
+			zap.String("path", entry.Path),
+			zap.Int("status", entry.Status),
+			zap.Float64("latency_ms", entry.LatencyMs),
+			zap.Int64("bytes_in", entry.BytesIn),
+			zap.Int64("bytes_out", entry.BytesOut),
+			zap.String("tenant_id", entry.TenantID),
+			zap.String("space_id", entry.SpaceID),
+			zap.String("user_id", entry.UserID),
+			zap.String("client_ip", entry.ClientIP),
+			zap.String("user_agent", entry.UserAgent),
+			zap.Float64("sample_rate", entry.SampleRate),
+		)
+
+		if exporter != nil {
+			exporter.Export(entry)
+		}
+	}
+}
+
+// sampled reports whether a request falls in the sample
+func sampled(rate float64) bool {
+	return rate >= 1 || (rate > 0 && rand.Float64() < rate)
+}
+
+// newAccessLogEntry describes the finished request
+func newAccessLogEntry(c *gin.Context, start time.Time, status int, sampleRate float64) *models.AccessLogEntry {
+	route := c.FullPath()
+	if route == "" {
+		route = "unmatched"
+	}
+
+	entry := &models.AccessLogEntry{
+		Timestamp:  start.UTC(),
+		RequestID:  requestIDFromGin(c),
+		Method:     c.Request.Method,
+		Route:      route,
+		Path:       c.Request.URL.Path,
+		Status:     status,
+		LatencyMs:  float64(time.Since(start).Microseconds()) / 1000,
+		BytesIn:    max(c.Request.ContentLength, 0),
+		BytesOut:   int64(max(c.Writer.Size(), 0)),
+		UserID:     c.GetString("user_id"),
+		ClientIP:   c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+		SampleRate: sampleRate,
+	}
+
+	if value, ok := c.Get(SpaceContextKey); ok {
+		if spaceContext, ok := value.(*models.SpaceContext); ok && spaceContext != nil {
+			entry.TenantID = spaceContext.TenantID
+			entry.SpaceID = spaceContext.SpaceID
+		}
+	}
+
+	return entry
+}
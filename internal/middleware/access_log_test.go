@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+type recordingExporter struct {
+	entries []*models.AccessLogEntry
+}
+
+func (e *recordingExporter) Export(entry *models.AccessLogEntry) {
+	e.entries = append(e.entries, entry)
+}
+
+func TestAccessLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log, err := logger.New(logger.Config{Level: "error", Format: "json"})
+	require.NoError(t, err)
+
+	newRouter := func(cfg config.AccessLogConfig, exporter *recordingExporter) *gin.Engine {
+		router := gin.New()
+		router.Use(AccessLog(log, cfg, exporter))
+		router.POST("/api/v1/notebooks/:id", func(c *gin.Context) {
+			c.Set("user_id", "user-1")
+			c.Set(SpaceContextKey, &models.SpaceContext{TenantID: "tenant_1", SpaceID: "space-1"})
+			c.String(http.StatusCreated, "created")
+		})
+		router.GET("/health/live", func(c *gin.Context) { c.Status(http.StatusOK) })
+		return router
+	}
+	send := func(router *gin.Engine, method, path string) {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, path, strings.NewReader("body")))
+	}
+
+	exporter := &recordingExporter{}
+	router := newRouter(config.AccessLogConfig{SampleRate: 1, SkipPaths: []string{"/health/live"}}, exporter)
+	send(router, http.MethodPost, "/api/v1/notebooks/nb-1")
+	send(router, http.MethodGet, "/health/live")
+
+	require.Len(t, exporter.entries, 1)
+	entry := exporter.entries[0]
+	assert.Equal(t, "/api/v1/notebooks/:id", entry.Route)
+	assert.Equal(t, "/api/v1/notebooks/nb-1", entry.Path)
+	assert.Equal(t, http.StatusCreated, entry.Status)
+	assert.Equal(t, "tenant_1", entry.TenantID)
+	assert.Equal(t, "space-1", entry.SpaceID)
+	assert.Equal(t, "user-1", entry.UserID)
+	assert.Equal(t, int64(4), entry.BytesIn)
+	assert.Equal(t, int64(7), entry.BytesOut)
+	assert.Equal(t, 1.0, entry.SampleRate)
+
+	// Nothing is sampled at rate 0 except errors, which are kept at full weight
+	exporter = &recordingExporter{}
+	router = newRouter(config.AccessLogConfig{SampleRate: 0, KeepErrors: true}, exporter)
+	send(router, http.MethodPost, "/api/v1/notebooks/nb-1")
+	send(router, http.MethodGet, "/missing")
+
+	require.Len(t, exporter.entries, 1)
+	assert.Equal(t, "unmatched", exporter.entries[0].Route)
+	assert.Equal(t, http.StatusNotFound, exporter.entries[0].Status)
+	assert.Equal(t, 1.0, exporter.entries[0].SampleRate)
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tributary-ai-services/aether-be/internal/apiversion"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// APIVersion answers the version negotiated by versions.Handler. Responses
+// name the requested version in the API-Version header; deprecated
+// versions also get Deprecation, Sunset and Link headers (RFC 9745 and
+// RFC 8594). Unknown versions are answered with 404 and versions past
+// their sunset with 410. Requests that did not pass through the handler
+// are left alone.
+func APIVersion(versions *apiversion.Set, log *logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name, ok := apiversion.Requested(c.Request.Context())
+		if !ok {
+			c.Next()
+			return
+		}
+
+		version, known := versions.Lookup(name)
+		if !known {
+			WriteError(c, log, errors.NotFoundWithDetails("API version not supported", map[string]interface{}{
+				"version":   name,
+				"supported": versions.Names(),
+			}).WithErrorCode(errors.CodeAPIVersionUnsupported))
+			return
+		}
+
+		header := c.Writer.Header()
+		header.Set(apiversion.Header, version.Name)
+		if !version.Deprecated.IsZero() {
+			header.Set("Deprecation", "@"+strconv.FormatInt(version.Deprecated.Unix(), 10))
+		}
+		if !version.Sunset.IsZero() {
+			header.Set("Sunset", version.Sunset.UTC().Format(http.TimeFormat))
+		}
+		if version.Link != "" && (!version.Deprecated.IsZero() || !version.Sunset.IsZero()) {
+			header.Add("Link", fmt.Sprintf(`<%s>; rel="deprecation"`, version.Link))
+		}
+
+		if version.Gone(time.Now()) {
+			WriteError(c, log, errors.GoneWithDetails("API version is no longer served", map[string]interface{}{
+				"version": version.Name,
+				"sunset":  version.Sunset.UTC().Format(time.RFC3339),
+			}).WithErrorCode(errors.CodeAPIVersionSunset))
+			return
+		}
+		c.Next()
+	}
+}
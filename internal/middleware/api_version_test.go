@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/apiversion"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+func TestAPIVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log, err := logger.New(logger.Config{Level: "error", Format: "json"})
+	require.NoError(t, err)
+
+	deprecated := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	versions := apiversion.NewSet("v2",
+		apiversion.Version{Name: "v1", Deprecated: deprecated, Sunset: time.Now().Add(24 * time.Hour), Link: "https://example.com/v2"},
+		apiversion.Version{Name: "v2"},
+		apiversion.Version{Name: "v0", Deprecated: deprecated, Sunset: deprecated},
+	)
+
+	router := gin.New()
+	router.Use(APIVersion(versions, log))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/api/v0/notes", ok)
+	router.GET("/api/v1/notes", ok)
+	router.GET("/api/v2/notes", ok)
+	router.GET("/health", ok)
+	for _, r := range router.Routes() {
+		versions.Register(r.Method, r.Path)
+	}
+	handler := versions.Handler(router)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+	errorCode := func(w *httptest.ResponseRecorder) string {
+		var apiErr errors.APIError
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &apiErr))
+		return apiErr.ErrorCode
+	}
+
+	w := get("/api/notes")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "v2", w.Header().Get(apiversion.Header))
+	assert.Empty(t, w.Header().Get("Deprecation"))
+
+	w = get("/api/v1/notes")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "v1", w.Header().Get(apiversion.Header))
+	assert.Equal(t, "@1767225600", w.Header().Get("Deprecation"))
+	assert.NotEmpty(t, w.Header().Get("Sunset"))
+	assert.Equal(t, `<https://example.com/v2>; rel="deprecation"`, w.Header().Get("Link"))
+
+	w = get("/api/v0/notes")
+	assert.Equal(t, http.StatusGone, w.Code)
+	assert.Equal(t, errors.CodeAPIVersionSunset, errorCode(w))
+	assert.Equal(t, "Thu, 01 Jan 2026 00:00:00 GMT", w.Header().Get("Sunset"))
+
+	w = get("/api/v3/notes")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, errors.CodeAPIVersionUnsupported, errorCode(w))
+
+	w = get("/health")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get(apiversion.Header))
+}
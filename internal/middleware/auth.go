@@ -7,6 +7,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
 	"go.uber.org/zap"
 
 	"github.com/Tributary-ai-services/aether-be/internal/auth"
@@ -17,8 +18,15 @@ import (
 // AuthMiddleware handles JWT token validation
 func AuthMiddleware(keycloakClient *auth.KeycloakClient, log *logger.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Extract token from Authorization header
+		// Extract token from Authorization header. Browsers cannot set
+		// headers on WebSocket upgrades, so those may pass the token as the
+		// access_token query parameter instead.
 		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" && websocket.IsWebSocketUpgrade(c.Request) {
+			if token := c.Query("access_token"); token != "" {
+				authHeader = "Bearer " + token
+			}
+		}
 		if authHeader == "" {
 			log.Warn("Missing authorization header")
 			c.JSON(http.StatusUnauthorized, errors.NewAPIError(
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// BodyLimit caps request bodies at routeLimits[route], keyed by the route
+// pattern as registered, or at defaultLimit for other routes. Requests
+// declaring a larger Content-Length are rejected before the body is read;
+// bodies without one fail with a MaxBytesError once they pass the limit.
+func BodyLimit(log *logger.Logger, defaultLimit int64, routeLimits map[string]int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := defaultLimit
+		if routeLimit, ok := routeLimits[c.FullPath()]; ok {
+			limit = routeLimit
+		}
+
+		if c.Request.ContentLength > limit {
+			WriteError(c, log, bodyTooLarge(limit))
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
+
+// IsBodyTooLarge reports whether err came from reading past the body limit
+func IsBodyTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return stderrors.As(err, &maxBytesErr)
+}
+
+// bodyTooLarge returns the error for a body over limit bytes
+func bodyTooLarge(limit int64) *errors.APIError {
+	return errors.PayloadTooLarge(fmt.Sprintf("Request body exceeds the limit of %d bytes", limit))
+}
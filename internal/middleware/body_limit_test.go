@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+func TestBodyLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log, err := logger.New(logger.Config{Level: "error", Format: "json"})
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(BodyLimit(log, 16, map[string]int64{"/uploads/:id": 64}))
+	read := func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			WriteError(c, log, err)
+			return
+		}
+		c.String(http.StatusOK, "%d", len(body))
+	}
+	router.POST("/notes", read)
+	router.POST("/uploads/:id", read)
+
+	send := func(path string, size int, chunked bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(strings.Repeat("a", size)))
+		if chunked {
+			req.ContentLength = -1
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, send("/notes", 16, false).Code)
+	assert.Equal(t, http.StatusOK, send("/uploads/1", 64, false).Code)
+
+	// Declared lengths are rejected up front, undeclared ones once read past the limit
+	for _, chunked := range []bool{false, true} {
+		w := send("/notes", 17, chunked)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+		var apiErr errors.APIError
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &apiErr))
+		assert.Equal(t, errors.CodePayloadTooLarge, apiErr.ErrorCode)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, send("/uploads/1", 65, chunked).Code)
+	}
+}
+
+func TestValidationMiddlewareStreamsJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log, err := logger.New(logger.Config{Level: "error", Format: "json"})
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(BodyLimit(log, 32, nil))
+	router.Use(ValidationMiddleware(log))
+	router.POST("/notes", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	})
+
+	send := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/notes", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.ContentLength = -1
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(`{"name":"notes"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"name":"notes"}`, w.Body.String())
+
+	assert.Equal(t, http.StatusOK, send("").Code)
+	assert.Equal(t, http.StatusBadRequest, send(`{"name":"a"} {"name":"b"}`).Code)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, send(`{"name":"`+strings.Repeat("a", 40)+`"}`).Code)
+}
@@ -0,0 +1,442 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// Directions of a compressed body, as recorded
+const (
+	CompressionRequest  = "request"
+	CompressionResponse = "response"
+)
+
+// maxZstdWindow caps the memory a zstd request body may ask the decoder
+// for; it covers every compression level short of long-distance matching
+const maxZstdWindow = 8 << 20
+
+// CompressionRecorder records the size of compressed bodies before and
+// after compression
+type CompressionRecorder interface {
+	RecordCompression(direction, encoding string, uncompressedBytes, compressedBytes int64)
+}
+
+// Compression decompresses request bodies sent with Content-Encoding gzip
+// or zstd and, when cfg.Enabled, compresses responses with the first of
+// cfg's encodings the client accepts. Only text-like responses of at least
+// cfg.MinBytes are compressed; range, already encoded, streamed (SSE) and
+// WebSocket responses are left alone. It must run before BodyLimit so that
+// limits apply to decompressed bodies.
+func Compression(log *logger.Logger, cfg config.CompressionConfig, recorder CompressionRecorder) gin.HandlerFunc {
+	encodings, _ := cfg.EncodingList()
+	if !cfg.Enabled {
+		encodings = nil
+	}
+	pools := encoderPools(cfg.GzipLevel)
+
+	return func(c *gin.Context) {
+		body, err := decompressRequest(c.Request)
+		if err != nil {
+			WriteError(c, log, err)
+			return
+		}
+
+		var writer *compressWriter
+		if encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"), encodings); encoding != "" && responseCompressible(c) {
+			writer = &compressWriter{
+				ResponseWriter: c.Writer,
+				encoding:       encoding,
+				pool:           pools[encoding],
+				minBytes:       cfg.MinBytes,
+			}
+			c.Writer = writer
+		}
+
+		c.Next()
+
+		if writer != nil {
+			writer.finish(recorder)
+			c.Writer = writer.ResponseWriter
+		}
+		if body != nil {
+			body.finish(recorder)
+		}
+	}
+}
+
+// negotiateEncoding returns the first of encodings that an Accept-Encoding
+// header accepts with the highest weight, "" for none. "*" stands for
+// encodings the header does not name, and q=0 refuses an encoding.
+func negotiateEncoding(accept string, encodings []string) string {
+	if accept == "" || len(encodings) == 0 {
+		return ""
+	}
+
+	weights := make(map[string]float64)
+	wildcard := 0.0
+	for _, part := range strings.Split(accept, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		weight := 1.0
+		for _, param := range strings.Split(params, ";") {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if q, err := strconv.ParseFloat(value, 64); err == nil {
+					weight = q
+				}
+			}
+		}
+		if name == "*" {
+			wildcard = weight
+		} else if name != "" {
+			weights[name] = weight
+		}
+	}
+
+	best, bestWeight := "", 0.0
+	for _, encoding := range encodings {
+		weight, ok := weights[encoding]
+		if !ok {
+			weight = wildcard
+		}
+		if weight > bestWeight {
+			best, bestWeight = encoding, weight
+		}
+	}
+	return best
+}
+
+// responseCompressible reports whether the response to a request may be
+// compressed, before anything is known of the response itself
+func responseCompressible(c *gin.Context) bool {
+	return c.Request.Method != http.MethodHead &&
+		c.GetHeader("Range") == "" &&
+		!c.IsWebsocket()
+}
+
+// compressibleType reports whether a content type is text-like enough to
+// gain from compression; images, archives and media are compressed already
+func compressibleType(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+
+	switch {
+	case mediaType == "text/event-stream":
+		// Events must reach the client as they are written
+		return false
+	case strings.HasPrefix(mediaType, "text/"),
+		strings.HasSuffix(mediaType, "+json"),
+		strings.HasSuffix(mediaType, "+xml"):
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/x-ndjson", "application/xml",
+		"application/javascript", "application/yaml", "application/x-yaml":
+		return true
+	}
+	return false
+}
+
+// encoder is a compressor that can be reused for another response
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// encoderPools returns a pool of encoders for each supported encoding
+func encoderPools(gzipLevel int) map[string]*sync.Pool {
+	return map[string]*sync.Pool{
+		"gzip": {New: func() any {
+			w, err := gzip.NewWriterLevel(nil, gzipLevel)
+			if err != nil {
+				w = gzip.NewWriter(nil)
+			}
+			return w
+		}},
+		"zstd": {New: func() any {
+			// Only options are checked, and these are valid
+			w, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+			return w
+		}},
+	}
+}
+
+// compressWriter compresses a response once it is known to be compressible
+// and at least minBytes long. Writes are buffered until then; a response
+// that ends or is flushed first is sent as it is, or compressed if flushed.
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding string
+	pool     *sync.Pool
+	minBytes int
+
+	buf     []byte
+	started bool
+	encoder encoder
+	wire    *countingWriter
+	size    int64 // Bytes written by the handler, before compression
+}
+
+// compressible reports whether the response, as its headers stand, may be
+// compressed
+func (w *compressWriter) compressible() bool {
+	header := w.Header()
+	status := w.Status()
+	return status >= http.StatusOK &&
+		status != http.StatusNoContent &&
+		status != http.StatusPartialContent &&
+		status != http.StatusNotModified &&
+		header.Get("Content-Encoding") == "" &&
+		header.Get("Content-Range") == "" &&
+		compressibleType(header.Get("Content-Type"))
+}
+
+// start sends the headers, announcing the encoding when compress is set,
+// and the body buffered so far
+func (w *compressWriter) start(compress bool) error {
+	w.started = true
+	if w.compressible() {
+		// Whether the body is compressed depends on Accept-Encoding
+		w.Header().Add("Vary", "Accept-Encoding")
+	}
+
+	if compress {
+		w.Header().Set("Content-Encoding", w.encoding)
+		w.Header().Del("Content-Length")
+		w.Header().Del("Accept-Ranges")
+		w.wire = &countingWriter{w: w.ResponseWriter}
+		w.encoder = w.pool.Get().(encoder)
+		w.encoder.Reset(w.wire)
+	}
+
+	buffered := w.buf
+	w.buf = nil
+	if len(buffered) == 0 {
+		return nil
+	}
+	if w.encoder != nil {
+		_, err := w.encoder.Write(buffered)
+		return err
+	}
+	_, err := w.ResponseWriter.Write(buffered)
+	return err
+}
+
+// Write compresses data, or buffers it until the response is long enough
+// to compress
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if !w.started {
+		compress := w.compressible()
+		if compress && len(w.buf)+len(data) < w.minBytes {
+			w.buf = append(w.buf, data...)
+			w.size += int64(len(data))
+			return len(data), nil
+		}
+		if err := w.start(compress); err != nil {
+			return 0, err
+		}
+	}
+
+	w.size += int64(len(data))
+	if w.encoder != nil {
+		return w.encoder.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString writes s as Write does
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// WriteHeaderNow sends the headers of a response that has no body
+func (w *compressWriter) WriteHeaderNow() {
+	if !w.started && len(w.buf) == 0 {
+		_ = w.start(false)
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Flush sends what was written so far; a flushed response is compressed
+// whatever its length, since more is expected to follow
+func (w *compressWriter) Flush() {
+	if !w.started {
+		_ = w.start(w.compressible())
+	}
+	if w.encoder != nil {
+		_ = w.encoder.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// Size returns the bytes written by the handler, before compression
+func (w *compressWriter) Size() int {
+	if w.size == 0 {
+		return w.ResponseWriter.Size()
+	}
+	return int(w.size)
+}
+
+// Written reports whether the handler has written a body or the headers
+func (w *compressWriter) Written() bool {
+	return len(w.buf) > 0 || w.ResponseWriter.Written()
+}
+
+// finish sends a response too short to compress as it is, or completes
+// the compressed one and records its compression
+func (w *compressWriter) finish(recorder CompressionRecorder) {
+	if !w.started {
+		if len(w.buf) == 0 {
+			// Nothing was written; gin sends the headers
+			return
+		}
+		_ = w.start(false)
+	}
+	if w.encoder == nil {
+		return
+	}
+
+	_ = w.encoder.Close()
+	w.encoder.Reset(nil)
+	w.pool.Put(w.encoder)
+	w.encoder = nil
+	if recorder != nil {
+		recorder.RecordCompression(CompressionResponse, w.encoding, w.size, w.wire.n)
+	}
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// decompressRequest replaces the body of a request sent with a supported
+// Content-Encoding with its decompressed form, returning nil when the body
+// is not encoded
+func decompressRequest(req *http.Request) (*decompressingBody, error) {
+	encoding := strings.ToLower(strings.TrimSpace(req.Header.Get("Content-Encoding")))
+	switch encoding {
+	case "", "identity":
+		return nil, nil
+	case "gzip", "zstd":
+	default:
+		return nil, errors.UnsupportedMediaType(fmt.Sprintf("Content-Encoding %q is not supported", encoding)).
+			WithErrorCode(errors.CodeContentEncodingUnsupported)
+	}
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body := &decompressingBody{
+		encoding: encoding,
+		source:   req.Body,
+		wire:     &countingReader{r: req.Body},
+	}
+	req.Body = body
+	// Handlers see the body as if it had been sent decompressed; its length
+	// is only known once read
+	req.Header.Del("Content-Encoding")
+	req.Header.Del("Content-Length")
+	req.ContentLength = -1
+	return body, nil
+}
+
+// decompressingBody decompresses a request body as it is read. The decoder
+// is created on the first read so that an empty body reads as empty.
+type decompressingBody struct {
+	encoding string
+	source   io.ReadCloser
+	wire     *countingReader
+	decoder  io.Reader
+	close    func()
+	err      error
+	n        int64 // Decompressed bytes read
+}
+
+func (b *decompressingBody) Read(p []byte) (int, error) {
+	if b.decoder == nil && b.err == nil {
+		b.open()
+	}
+	if b.err != nil {
+		return 0, b.err
+	}
+	n, err := b.decoder.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+// open creates the decoder of the body's encoding
+func (b *decompressingBody) open() {
+	switch b.encoding {
+	case "gzip":
+		reader, err := gzip.NewReader(b.wire)
+		if err != nil {
+			b.err = fmt.Errorf("invalid gzip request body: %w", err)
+			return
+		}
+		b.decoder, b.close = reader, func() { _ = reader.Close() }
+	case "zstd":
+		reader, err := zstd.NewReader(b.wire,
+			zstd.WithDecoderConcurrency(1),
+			zstd.WithDecoderLowmem(true),
+			zstd.WithDecoderMaxWindow(maxZstdWindow),
+		)
+		if err != nil {
+			b.err = fmt.Errorf("invalid zstd request body: %w", err)
+			return
+		}
+		b.decoder, b.close = reader, reader.Close
+	}
+}
+
+// Close releases the decoder and closes the original body
+func (b *decompressingBody) Close() error {
+	if b.close != nil {
+		b.close()
+		b.close = nil
+	}
+	return b.source.Close()
+}
+
+// finish releases the decoder and records the decompression of what the
+// handlers read
+func (b *decompressingBody) finish(recorder CompressionRecorder) {
+	if b.close != nil {
+		b.close()
+		b.close = nil
+	}
+	if recorder != nil && b.n > 0 {
+		recorder.RecordCompression(CompressionRequest, b.encoding, b.n, b.wire.n)
+	}
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,187 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+)
+
+type compressionRecord struct {
+	direction, encoding           string
+	uncompressedBytes, compressed int64
+}
+
+type fakeCompressionRecorder struct {
+	records []compressionRecord
+}
+
+func (r *fakeCompressionRecorder) RecordCompression(direction, encoding string, uncompressedBytes, compressedBytes int64) {
+	r.records = append(r.records, compressionRecord{direction, encoding, uncompressedBytes, compressedBytes})
+}
+
+var largeJSON = `{"chunks":"` + strings.Repeat("chunk text ", 500) + `"}`
+
+func newCompressionRouter(t *testing.T, recorder CompressionRecorder) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	log, err := logger.New(logger.Config{Level: "error", Format: "json"})
+	require.NoError(t, err)
+
+	router := gin.New()
+	cfg := config.CompressionConfig{Enabled: true, Encodings: "zstd,gzip", MinBytes: 1024, GzipLevel: 5}
+	router.Use(Compression(log, cfg, recorder))
+	router.Use(BodyLimit(log, 64<<10, nil))
+	router.GET("/large", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json", []byte(largeJSON))
+	})
+	router.GET("/small", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+	router.GET("/image", func(c *gin.Context) {
+		c.Data(http.StatusOK, "image/png", bytes.Repeat([]byte{1}, 4096))
+	})
+	router.POST("/echo", func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			WriteError(c, log, err)
+			return
+		}
+		c.Data(http.StatusOK, "text/plain", body)
+	})
+	return router
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	encodings := []string{"zstd", "gzip"}
+
+	assert.Equal(t, "zstd", negotiateEncoding("gzip, deflate, br, zstd", encodings))
+	assert.Equal(t, "gzip", negotiateEncoding("gzip", encodings))
+	assert.Equal(t, "gzip", negotiateEncoding("zstd;q=0.5, gzip;q=0.8", encodings))
+	assert.Equal(t, "gzip", negotiateEncoding("zstd;q=0, *", encodings))
+	assert.Equal(t, "zstd", negotiateEncoding("*", encodings))
+	assert.Empty(t, negotiateEncoding("br, identity", encodings))
+	assert.Empty(t, negotiateEncoding("", encodings))
+	assert.Empty(t, negotiateEncoding("gzip", nil))
+}
+
+func TestCompressionCompressesLargeResponses(t *testing.T) {
+	recorder := &fakeCompressionRecorder{}
+	router := newCompressionRouter(t, recorder)
+
+	for _, encoding := range []string{"gzip", "zstd"} {
+		req := httptest.NewRequest(http.MethodGet, "/large", nil)
+		req.Header.Set("Accept-Encoding", encoding)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, encoding, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+		assert.Less(t, w.Body.Len(), len(largeJSON))
+
+		var reader io.Reader
+		if encoding == "gzip" {
+			gz, err := gzip.NewReader(w.Body)
+			require.NoError(t, err)
+			reader = gz
+		} else {
+			zr, err := zstd.NewReader(w.Body)
+			require.NoError(t, err)
+			defer zr.Close()
+			reader = zr
+		}
+		body, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, largeJSON, string(body))
+	}
+
+	require.Len(t, recorder.records, 2)
+	assert.Equal(t, CompressionResponse, recorder.records[0].direction)
+	assert.Equal(t, int64(len(largeJSON)), recorder.records[0].uncompressedBytes)
+	assert.Less(t, recorder.records[0].compressed, recorder.records[0].uncompressedBytes)
+}
+
+func TestCompressionLeavesSmallAndBinaryResponses(t *testing.T) {
+	recorder := &fakeCompressionRecorder{}
+	router := newCompressionRouter(t, recorder)
+
+	for _, path := range []string{"/small", "/image"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code, path)
+		assert.Empty(t, w.Header().Get("Content-Encoding"), path)
+	}
+	assert.Empty(t, recorder.records)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/large", nil))
+	assert.Empty(t, w.Header().Get("Content-Encoding"), "the client accepts no encoding")
+	assert.Equal(t, largeJSON, w.Body.String())
+}
+
+func TestCompressionDecompressesRequests(t *testing.T) {
+	recorder := &fakeCompressionRecorder{}
+	router := newCompressionRouter(t, recorder)
+	payload := strings.Repeat(`{"type":"event"}`, 200)
+
+	var gzipped bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	_, _ = gz.Write([]byte(payload))
+	require.NoError(t, gz.Close())
+
+	encoder, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	zstdBody := encoder.EncodeAll([]byte(payload), nil)
+
+	for encoding, body := range map[string][]byte{"gzip": gzipped.Bytes(), "zstd": zstdBody} {
+		req := httptest.NewRequest(http.MethodPost, "/echo", bytes.NewReader(body))
+		req.Header.Set("Content-Encoding", encoding)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code, encoding)
+		assert.Equal(t, payload, w.Body.String(), encoding)
+	}
+
+	require.Len(t, recorder.records, 2)
+	assert.Equal(t, CompressionRequest, recorder.records[0].direction)
+	assert.Equal(t, int64(len(payload)), recorder.records[0].uncompressedBytes)
+}
+
+func TestCompressionRejectsUnsupportedAndOversizedRequests(t *testing.T) {
+	router := newCompressionRouter(t, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader("data"))
+	req.Header.Set("Content-Encoding", "br")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	assert.Contains(t, w.Body.String(), "AETHER-API-003")
+
+	// A small body that decompresses past the body limit
+	var bomb bytes.Buffer
+	gz := gzip.NewWriter(&bomb)
+	_, _ = gz.Write(make([]byte, 1<<20))
+	require.NoError(t, gz.Close())
+	require.Less(t, bomb.Len(), 64<<10)
+
+	req = httptest.NewRequest(http.MethodPost, "/echo", &bomb)
+	req.Header.Set("Content-Encoding", "gzip")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
@@ -0,0 +1,204 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// ConcurrencyRecorder records the use of concurrency slots.
+// *metrics.Metrics implements it.
+type ConcurrencyRecorder interface {
+	SetConcurrencyUsage(class string, inUse, queued int)
+	RecordConcurrencyWait(class string, wait time.Duration, rejected bool)
+}
+
+// concurrencyWaiter is a request waiting for a slot. ready is closed once
+// it is admitted.
+type concurrencyWaiter struct {
+	ready    chan struct{}
+	admitted bool
+}
+
+// concurrencyClass is the slots used by one class of routes and the
+// requests waiting for one, in arrival order
+type concurrencyClass struct {
+	name  string
+	limit int
+	inUse int
+	queue []*concurrencyWaiter
+}
+
+// concurrencyLimiter admits at most capacity requests at once, and at most
+// its limit for each class. Classes are held in order of priority: a freed
+// slot goes to the first waiting request of the highest priority class that
+// is under its limit. Limits are per replica.
+type concurrencyLimiter struct {
+	mu       sync.Mutex
+	capacity int
+	inUse    int
+	maxQueue int
+	classes  []*concurrencyClass
+	byName   map[string]*concurrencyClass
+	recorder ConcurrencyRecorder
+}
+
+func newConcurrencyLimiter(cfg config.ConcurrencyConfig, recorder ConcurrencyRecorder) *concurrencyLimiter {
+	limits, err := cfg.Limits()
+	if err != nil {
+		// Validate rejects bad limits at startup; fall back to the total
+		limits, _ = config.ConcurrencyConfig{MaxRequests: cfg.MaxRequests}.Limits()
+	}
+
+	l := &concurrencyLimiter{
+		capacity: cfg.MaxRequests,
+		maxQueue: cfg.MaxQueue,
+		byName:   make(map[string]*concurrencyClass, len(config.ConcurrencyClasses)),
+		recorder: recorder,
+	}
+	for _, name := range config.ConcurrencyClasses {
+		class := &concurrencyClass{name: name, limit: limits[name]}
+		l.classes = append(l.classes, class)
+		l.byName[name] = class
+	}
+	return l
+}
+
+// acquire takes a slot of class, waiting up to timeout when none is free.
+// It reports whether the request was admitted; the caller must then call
+// release once it is served.
+func (l *concurrencyLimiter) acquire(ctx context.Context, class *concurrencyClass, timeout time.Duration) bool {
+	l.mu.Lock()
+	if l.admissible(class) {
+		l.admit(class)
+		l.mu.Unlock()
+		return true
+	}
+	if len(class.queue) >= l.maxQueue {
+		l.mu.Unlock()
+		l.recordWait(class, 0, true)
+		return false
+	}
+	waiter := &concurrencyWaiter{ready: make(chan struct{})}
+	class.queue = append(class.queue, waiter)
+	l.report(class)
+	l.mu.Unlock()
+
+	start := time.Now()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-waiter.ready:
+		l.recordWait(class, time.Since(start), false)
+		return true
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// The slot may have been handed over while the wait ended
+	if waiter.admitted {
+		l.recordWait(class, time.Since(start), false)
+		return true
+	}
+	for i, queued := range class.queue {
+		if queued == waiter {
+			class.queue = append(class.queue[:i], class.queue[i+1:]...)
+			break
+		}
+	}
+	l.report(class)
+	l.recordWait(class, time.Since(start), true)
+	return false
+}
+
+// release frees the slot of a served request and hands free slots to the
+// waiting requests, by priority
+func (l *concurrencyLimiter) release(class *concurrencyClass) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inUse--
+	class.inUse--
+	l.report(class)
+
+	for _, waiting := range l.classes {
+		for len(waiting.queue) > 0 && l.admissible(waiting) {
+			waiter := waiting.queue[0]
+			waiting.queue = waiting.queue[1:]
+			waiter.admitted = true
+			close(waiter.ready)
+			l.admit(waiting)
+		}
+		if l.inUse >= l.capacity {
+			return
+		}
+	}
+}
+
+// admissible reports whether a request of class can be served now. Callers
+// hold mu.
+func (l *concurrencyLimiter) admissible(class *concurrencyClass) bool {
+	return l.inUse < l.capacity && class.inUse < class.limit
+}
+
+// admit takes a slot for a request of class. Callers hold mu.
+func (l *concurrencyLimiter) admit(class *concurrencyClass) {
+	l.inUse++
+	class.inUse++
+	l.report(class)
+}
+
+// report records the usage of class. Callers hold mu.
+func (l *concurrencyLimiter) report(class *concurrencyClass) {
+	if l.recorder != nil {
+		l.recorder.SetConcurrencyUsage(class.name, class.inUse, len(class.queue))
+	}
+}
+
+func (l *concurrencyLimiter) recordWait(class *concurrencyClass, wait time.Duration, rejected bool) {
+	if l.recorder != nil {
+		l.recorder.RecordConcurrencyWait(class.name, wait, rejected)
+	}
+}
+
+// ConcurrencyLimit caps the requests served at once, in total and for each
+// class of routes as classify assigns them (one of config.ConcurrencyClasses,
+// or "" to leave a request unlimited). A request over a limit waits for a
+// slot up to cfg.QueueTimeoutMs and is then rejected with 429, so that
+// expensive routes such as uploads cannot starve reads. WebSocket
+// connections are not limited since they stay open as long as the client
+// listens.
+func ConcurrencyLimit(cfg config.ConcurrencyConfig, classify func(c *gin.Context) string, recorder ConcurrencyRecorder) gin.HandlerFunc {
+	if !cfg.Enabled {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+	limiter := newConcurrencyLimiter(cfg, recorder)
+	timeout := time.Duration(cfg.QueueTimeoutMs) * time.Millisecond
+
+	return func(c *gin.Context) {
+		class := limiter.byName[classify(c)]
+		if class == nil || c.IsWebsocket() {
+			c.Next()
+			return
+		}
+
+		if !limiter.acquire(c.Request.Context(), class, timeout) {
+			apiErr := errors.TooManyRequests("Server is busy, please retry shortly").WithRequestID(requestIDFromGin(c))
+			c.Header("Retry-After", "1")
+			c.AbortWithStatusJSON(apiErr.StatusCode, apiErr)
+			return
+		}
+		defer limiter.release(class)
+		c.Next()
+	}
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+)
+
+func TestConcurrencyLimitOfClass(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := config.ConcurrencyConfig{Enabled: true, MaxRequests: 4, ClassLimits: "bulk=1", MaxQueue: 1, QueueTimeoutMs: 20}
+	classify := func(c *gin.Context) string {
+		if c.FullPath() == "/upload" {
+			return config.ConcurrencyBulk
+		}
+		return config.ConcurrencyRead
+	}
+
+	started, unblock := make(chan struct{}), make(chan struct{})
+	router := gin.New()
+	router.Use(ConcurrencyLimit(cfg, classify, nil))
+	router.POST("/upload", func(c *gin.Context) {
+		if c.Query("block") != "" {
+			close(started)
+			<-unblock
+		}
+		c.Status(http.StatusOK)
+	})
+	router.GET("/items", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	done := make(chan int)
+	go func() {
+		done <- serve(http.MethodPost, "/upload?block=1").Code
+	}()
+	<-started
+
+	// The upload holds the only bulk slot; reads are unaffected
+	w := serve(http.MethodPost, "/upload")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/items").Code)
+
+	close(unblock)
+	assert.Equal(t, http.StatusOK, <-done)
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, "/upload").Code)
+}
+
+func TestConcurrencyLimiterAdmitsByPriority(t *testing.T) {
+	cfg := config.ConcurrencyConfig{MaxRequests: 1, MaxQueue: 4}
+	limiter := newConcurrencyLimiter(cfg, nil)
+	read, bulk := limiter.byName[config.ConcurrencyRead], limiter.byName[config.ConcurrencyBulk]
+	ctx := context.Background()
+
+	require.True(t, limiter.acquire(ctx, bulk, time.Second))
+
+	admitted := make(chan string, 2)
+	wait := func(class *concurrencyClass) {
+		if limiter.acquire(ctx, class, time.Second) {
+			admitted <- class.name
+		}
+	}
+	go wait(bulk)
+	require.Eventually(t, func() bool { return queued(limiter, bulk) == 1 }, time.Second, time.Millisecond)
+	go wait(read)
+	require.Eventually(t, func() bool { return queued(limiter, read) == 1 }, time.Second, time.Millisecond)
+
+	// The read queued last is admitted first
+	limiter.release(bulk)
+	assert.Equal(t, config.ConcurrencyRead, <-admitted)
+	limiter.release(read)
+	assert.Equal(t, config.ConcurrencyBulk, <-admitted)
+	limiter.release(bulk)
+	assert.Zero(t, limiter.inUse)
+}
+
+func TestConcurrencyLimiterQueueTimeout(t *testing.T) {
+	cfg := config.ConcurrencyConfig{MaxRequests: 1, MaxQueue: 1}
+	limiter := newConcurrencyLimiter(cfg, nil)
+	write := limiter.byName[config.ConcurrencyWrite]
+	ctx := context.Background()
+
+	require.True(t, limiter.acquire(ctx, write, time.Second))
+	assert.False(t, limiter.acquire(ctx, write, 10*time.Millisecond))
+	assert.Zero(t, queued(limiter, write), "a request that gave up leaves the queue")
+
+	limiter.release(write)
+	assert.True(t, limiter.acquire(ctx, write, time.Second))
+}
+
+func queued(l *concurrencyLimiter, class *concurrencyClass) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(class.queue)
+}
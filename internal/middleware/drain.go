@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// Drainer coordinates graceful shutdown for long-lived connections. Once
+// draining starts, new WebSocket upgrades are rejected and the request
+// context of open WebSocket streams is cancelled so their loops exit.
+type Drainer struct {
+	draining atomic.Bool
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+// NewDrainer creates a drainer in the accepting state
+func NewDrainer() *Drainer {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Drainer{ctx: ctx, cancel: cancel}
+}
+
+// Start begins draining. It is safe to call more than once.
+func (d *Drainer) Start() {
+	d.draining.Store(true)
+	d.cancel()
+}
+
+// Draining reports whether shutdown has begun
+func (d *Drainer) Draining() bool {
+	return d.draining.Load()
+}
+
+// Middleware rejects WebSocket upgrades while draining and ties the request
+// context of accepted WebSocket connections to the drainer. Hijacked
+// connections are not tracked by http.Server.Shutdown, so this is what ends them.
+func (d *Drainer) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !websocket.IsWebSocketUpgrade(c.Request) {
+			c.Next()
+			return
+		}
+
+		if d.Draining() {
+			apiErr := errors.ServiceUnavailable("Server is shutting down").WithRequestID(requestIDFromGin(c))
+			c.Header("Retry-After", "5")
+			c.AbortWithStatusJSON(apiErr.StatusCode, apiErr)
+			return
+		}
+
+		ctx, cancel := context.WithCancel(c.Request.Context())
+		stop := context.AfterFunc(d.ctx, cancel)
+		defer func() {
+			stop()
+			cancel()
+		}()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
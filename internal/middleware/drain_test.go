@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDrainerRejectsWebSocketUpgradesWhileDraining(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	drainer := NewDrainer()
+	router := gin.New()
+	router.Use(drainer.Middleware())
+	router.GET("/ws", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	upgrade := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+		req.Header.Set("Connection", "Upgrade")
+		req.Header.Set("Upgrade", "websocket")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, upgrade().Code)
+
+	drainer.Start()
+	w := upgrade()
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "5", w.Header().Get("Retry-After"))
+
+	// Plain requests are left to http.Server.Shutdown
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ws", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
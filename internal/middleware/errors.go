@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/i18n"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// requestIDFromGin returns the request ID set by RequestIDMiddleware
+func requestIDFromGin(c *gin.Context) string {
+	if id, ok := c.Get("request_id"); ok {
+		if s, ok := id.(string); ok {
+			return s
+		}
+	}
+	return logger.RequestIDFromContext(c.Request.Context())
+}
+
+// WriteError writes err as the standard error envelope
+// ({code, message, details, request_id}) and aborts the request.
+// Server-side causes are logged but never serialized.
+func WriteError(c *gin.Context, log *logger.Logger, err error) {
+	// Operations shed by the database pool gate become 429 so clients back off
+	if stderrors.Is(err, database.ErrPoolSaturated) {
+		err = errors.TooManyRequests("Server is busy, please retry shortly")
+		c.Header("Retry-After", "1")
+	}
+
+	// Bodies cut off by BodyLimit become 413 wherever they were read
+	var maxBytesErr *http.MaxBytesError
+	if stderrors.As(err, &maxBytesErr) {
+		err = bodyTooLarge(maxBytesErr.Limit)
+	}
+
+	apiErr := errors.Normalize(err).WithRequestID(requestIDFromGin(c))
+	apiErr = i18n.LocalizeError(i18n.FromContext(c.Request.Context()), apiErr)
+
+	if apiErr.StatusCode >= http.StatusInternalServerError {
+		log.FromContext(c.Request.Context()).Error("Request failed",
+			zap.String("code", apiErr.Code),
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.FullPath()),
+			zap.Error(err),
+		)
+	}
+
+	c.AbortWithStatusJSON(apiErr.StatusCode, apiErr)
+}
+
+// Recovery recovers from panics in handlers, logs the stack trace with the
+// request ID and responds with a generic internal error envelope
+func Recovery(log *logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				log.FromContext(c.Request.Context()).Error("Panic recovered in request handler",
+					zap.Any("panic", recovered),
+					zap.String("method", c.Request.Method),
+					zap.String("path", c.Request.URL.Path),
+					zap.String("client_ip", c.ClientIP()),
+					zap.Stack("stack"),
+				)
+
+				// Headers may already be sent if the handler panicked mid-response
+				if c.Writer.Written() {
+					c.Abort()
+					return
+				}
+
+				apiErr := errors.Internal("Internal server error").WithRequestID(requestIDFromGin(c))
+				apiErr.Cause = fmt.Errorf("panic: %v", recovered)
+				c.AbortWithStatusJSON(apiErr.StatusCode, apiErr)
+			}
+		}()
+		c.Next()
+	}
+}
+
+// ErrorHandler renders errors attached with c.Error() when the handler did
+// not write a response itself, so handlers can simply `c.Error(err); return`
+func ErrorHandler(log *logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		WriteError(c, log, c.Errors.Last().Err)
+	}
+}
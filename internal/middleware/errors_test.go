@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+func newErrorTestRouter(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	log, err := logger.New(logger.Config{Level: "error", Format: "json"})
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(RequestIDMiddleware())
+	router.Use(Recovery(log))
+	router.Use(ErrorHandler(log))
+	return router
+}
+
+func TestRecoveryReturnsEnvelope(t *testing.T) {
+	router := newErrorTestRouter(t)
+	router.GET("/panic", func(c *gin.Context) {
+		panic("secret connection string")
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	req.Header.Set("X-Request-ID", "req-123")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "secret")
+
+	var body errors.APIError
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, errors.ErrInternal, body.Code)
+	assert.Equal(t, "req-123", body.RequestID)
+}
+
+func TestErrorHandlerMapsAttachedErrors(t *testing.T) {
+	router := newErrorTestRouter(t)
+	router.GET("/missing", func(c *gin.Context) {
+		_ = c.Error(fmt.Errorf("lookup: %w", errors.NotFound("Document not found")))
+	})
+	router.GET("/raw", func(c *gin.Context) {
+		_ = c.Error(fmt.Errorf("neo4j: connection reset by peer"))
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "Document not found")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/raw", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "connection reset")
+}
+
+func TestWriteErrorEnvelope(t *testing.T) {
+	router := newErrorTestRouter(t)
+	log, err := logger.New(logger.Config{Level: "error", Format: "json"})
+	require.NoError(t, err)
+	router.GET("/fail", func(c *gin.Context) {
+		WriteError(c, log, errors.InternalWithCause("Failed to create workflow", fmt.Errorf("neo4j: bolt://10.0.0.5")))
+	})
+	router.GET("/busy", func(c *gin.Context) {
+		WriteError(c, log, fmt.Errorf("list documents: %w", database.ErrPoolSaturated))
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/fail", nil)
+	req.Header.Set("X-Request-ID", "req-456")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "10.0.0.5")
+	var body errors.APIError
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, errors.ErrInternal, body.Code)
+	assert.Equal(t, "req-456", body.RequestID)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/busy", nil))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+}
+
+func TestWriteErrorLocalizesMessage(t *testing.T) {
+	router := newErrorTestRouter(t)
+	router.Use(Locale())
+	log, err := logger.New(logger.Config{Level: "error", Format: "json"})
+	require.NoError(t, err)
+	router.GET("/missing", func(c *gin.Context) {
+		WriteError(c, log, errors.NotFound("Document abc not found").WithErrorCode(errors.CodeDocumentNotFound))
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/missing", nil)
+	req.Header.Set("Accept-Language", "de-AT, en;q=0.5")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "de", w.Header().Get("Content-Language"))
+	assert.Contains(t, w.Header().Values("Vary"), "Accept-Language")
+	var body errors.APIError
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "Das Dokument existiert nicht", body.Message)
+	assert.Equal(t, errors.CodeDocumentNotFound, body.ErrorCode)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	assert.Equal(t, "en", w.Header().Get("Content-Language"))
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "Document abc not found", body.Message, "English messages are kept")
+}
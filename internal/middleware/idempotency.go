@@ -0,0 +1,422 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/i18n"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// IdempotencyStore keeps the responses of idempotent requests.
+// database.RedisClient implements it.
+type IdempotencyStore interface {
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error)
+	// Get returns the value of key, or "" when it is not set
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Idempotency headers
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks a response replayed for a retry
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+const (
+	idempotencyKeyPrefix = "aether-be:idempotency:"
+	idempotencyMaxKey    = 255
+	// idempotencyPendingTTL is how long a key stays taken by a request
+	// that has not finished, such as one whose replica stopped
+	idempotencyPendingTTL = 10 * time.Minute
+	// idempotencyMaxHashedBody is the largest body hashed before the
+	// request is handled; larger bodies, such as uploads, are hashed as the
+	// handler reads them
+	idempotencyMaxHashedBody = 1 << 20
+)
+
+// idempotencyReplayedHeaders are the response headers replayed with a kept
+// response
+var idempotencyReplayedHeaders = []string{"Content-Type", "Location", "ETag", "Last-Modified"}
+
+// idempotentResponse is what is kept under a key: the fingerprint of the
+// request and, once it finished, its response. For a large body, BodyDigest
+// hashes the BodyBytes bytes of it the handler read.
+type idempotentResponse struct {
+	Fingerprint string              `json:"fingerprint"`
+	BodyDigest  string              `json:"body_digest,omitempty"`
+	BodyBytes   int64               `json:"body_bytes,omitempty"`
+	Status      int                 `json:"status,omitempty"` // 0 while the request is handled
+	Header      map[string][]string `json:"header,omitempty"`
+	Body        []byte              `json:"body,omitempty"`
+}
+
+// Idempotency answers retries of a POST request sent with an
+// Idempotency-Key header with the response of the first request, so that
+// a client retrying after a timeout does not create a second document or
+// pay for processing twice. Keys are scoped to the space the request names,
+// and so to its tenant, and to the authenticated user. A retry while the
+// first request is handled fails with 409, and a key reused for a
+// different method, path or body with 422. Responses are kept for
+// cfg.TTLSeconds, except server errors, 429s, responses over
+// cfg.MaxResponseBytes and handlers that panic, which release the key so
+// the request can be retried. store may be nil, in which case responses
+// are kept in memory by each replica. Failures of the store let the
+// request through without idempotency.
+func Idempotency(store IdempotencyStore, cfg config.IdempotencyConfig, log *logger.Logger) gin.HandlerFunc {
+	if store == nil {
+		store = newLocalIdempotencyStore()
+	}
+	ttl := time.Duration(cfg.TTLSeconds) * time.Second
+
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+		if !validIdempotencyKey(key) {
+			abortIdempotency(c, errors.BadRequest("Invalid Idempotency-Key header").WithErrorCode(errors.CodeInvalidIdempotencyKey), 0)
+			return
+		}
+
+		fingerprint, bodyHash, err := requestFingerprint(c.Request)
+		if err != nil {
+			WriteError(c, log, errors.BadRequest("Failed to read request body"))
+			return
+		}
+		storeKey := idempotencyKeyPrefix + idempotencyScope(c) + ":" + key
+		ctx := context.WithoutCancel(c.Request.Context())
+
+		pending, _ := json.Marshal(idempotentResponse{Fingerprint: fingerprint})
+		taken, err := store.SetNX(ctx, storeKey, pending, idempotencyPendingTTL)
+		if err != nil {
+			log.Warn("Idempotency store failed; handling the request without it", zap.Error(err))
+			c.Next()
+			return
+		}
+		if !taken {
+			replayIdempotent(c, store, storeKey, fingerprint, log)
+			return
+		}
+
+		var body *hashingBody
+		if bodyHash {
+			body = &hashingBody{ReadCloser: c.Request.Body, hash: sha256.New()}
+			c.Request.Body = body
+		}
+		writer := &idempotencyWriter{ResponseWriter: c.Writer, limit: cfg.MaxResponseBytes}
+		c.Writer = writer
+
+		// Recovery runs outside this middleware, so the key is released
+		// here when the handler panics rather than left taken until
+		// idempotencyPendingTTL
+		finished := false
+		defer func() {
+			status := writer.Status()
+			if !finished || status >= http.StatusInternalServerError || status == http.StatusTooManyRequests || writer.overflow {
+				if err := store.Delete(ctx, storeKey); err != nil {
+					log.Warn("Failed to release idempotency key", zap.Error(err))
+				}
+				return
+			}
+			response := idempotentResponse{
+				Fingerprint: fingerprint,
+				Status:      status,
+				Header:      make(map[string][]string),
+				Body:        writer.body.Bytes(),
+			}
+			if body != nil {
+				response.BodyDigest, response.BodyBytes = body.digest(), body.n
+			}
+			for _, name := range idempotencyReplayedHeaders {
+				if values := writer.Header().Values(name); len(values) > 0 {
+					response.Header[name] = values
+				}
+			}
+			kept, _ := json.Marshal(response)
+			if err := store.Set(ctx, storeKey, kept, ttl); err != nil {
+				log.Warn("Failed to keep idempotent response", zap.Error(err))
+			}
+		}()
+		c.Next()
+		finished = true
+	}
+}
+
+// idempotencyScope returns what a key is scoped to: the space the request
+// names, and so its tenant, and the authenticated user or, without one, the
+// client's address
+func idempotencyScope(c *gin.Context) string {
+	scope := "ip:" + c.ClientIP()
+	if userID := c.GetString("user_id"); userID != "" {
+		scope = "user:" + userID
+	}
+	if spaceType, spaceID, err := extractSpaceInfo(c); err == nil && spaceID != "" {
+		scope = "space:" + spaceType + ":" + spaceID + ":" + scope
+	}
+	return scope
+}
+
+// replayIdempotent answers a request whose key is taken: with the kept
+// response when the first request finished, or an error
+func replayIdempotent(c *gin.Context, store IdempotencyStore, storeKey, fingerprint string, log *logger.Logger) {
+	value, err := store.Get(c.Request.Context(), storeKey)
+	if err != nil {
+		WriteError(c, log, errors.ServiceUnavailable("Failed to read idempotent response"))
+		return
+	}
+	var kept idempotentResponse
+	if value == "" || json.Unmarshal([]byte(value), &kept) != nil {
+		// Released or expired in between; the client can retry
+		abortIdempotency(c, errors.Conflict("A request with this Idempotency-Key is in progress").WithErrorCode(errors.CodeIdempotencyKeyInProgress), time.Second)
+		return
+	}
+	if kept.Fingerprint != fingerprint {
+		abortIdempotency(c, errors.NewAPIError(errors.ErrUnprocessableEntity, "The Idempotency-Key was used for a different request", nil).WithErrorCode(errors.CodeIdempotencyKeyReused), 0)
+		return
+	}
+	if kept.Status == 0 {
+		abortIdempotency(c, errors.Conflict("A request with this Idempotency-Key is in progress").WithErrorCode(errors.CodeIdempotencyKeyInProgress), time.Second)
+		return
+	}
+	if kept.BodyDigest != "" {
+		// The retry's body must match the part of the first body the
+		// handler read, so another upload is never answered with the
+		// document created for the first
+		same, err := sameBodyPrefix(c.Request.Body, kept.BodyBytes, kept.BodyDigest)
+		if err != nil {
+			if IsBodyTooLarge(err) {
+				WriteError(c, log, err)
+			} else {
+				WriteError(c, log, errors.BadRequest("Failed to read request body"))
+			}
+			return
+		}
+		if !same {
+			abortIdempotency(c, errors.NewAPIError(errors.ErrUnprocessableEntity, "The Idempotency-Key was used for a different request", nil).WithErrorCode(errors.CodeIdempotencyKeyReused), 0)
+			return
+		}
+	}
+
+	for name, values := range kept.Header {
+		for _, v := range values {
+			c.Writer.Header().Add(name, v)
+		}
+	}
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Status(kept.Status)
+	c.Writer.Write(kept.Body)
+	c.Abort()
+}
+
+// abortIdempotency rejects a request, with a Retry-After when retryAfter is
+// set
+func abortIdempotency(c *gin.Context, apiErr *errors.APIError, retryAfter time.Duration) {
+	apiErr = i18n.LocalizeError(i18n.FromContext(c.Request.Context()), apiErr.WithRequestID(requestIDFromGin(c)))
+	if retryAfter > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+	}
+	c.AbortWithStatusJSON(apiErr.StatusCode, apiErr)
+}
+
+// validIdempotencyKey reports whether a key is at most 255 characters of
+// printable ASCII
+func validIdempotencyKey(key string) bool {
+	if len(key) > idempotencyMaxKey {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x21 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// requestFingerprint hashes the method, path, query, media type, length
+// and, when it is small enough, body of a request. A body it reads is put
+// back for the handler. bodyHash reports that the body was too large, or
+// of unknown length, and has to be hashed as the handler reads it.
+func requestFingerprint(r *http.Request) (fingerprint string, bodyHash bool, err error) {
+	hash := sha256.New()
+	io.WriteString(hash, r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery+"\n")
+	// The multipart boundary differs between retries, so only the media
+	// type is hashed; the parts, with their headers, are in the body
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	io.WriteString(hash, mediaType+"\n"+strconv.FormatInt(r.ContentLength, 10)+"\n")
+
+	if r.Body == nil || r.Body == http.NoBody {
+		return hex.EncodeToString(hash.Sum(nil)), false, nil
+	}
+	if r.ContentLength < 0 || r.ContentLength > idempotencyMaxHashedBody {
+		return hex.EncodeToString(hash.Sum(nil)), true, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, idempotencyMaxHashedBody+1))
+	if err != nil {
+		return "", false, err
+	}
+	if len(body) > idempotencyMaxHashedBody {
+		// Longer than declared; hash it as the handler reads it
+		r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+		return hex.EncodeToString(hash.Sum(nil)), true, nil
+	}
+	hash.Write(body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return hex.EncodeToString(hash.Sum(nil)), false, nil
+}
+
+// hashingBody hashes a request body as the handler reads it
+type hashingBody struct {
+	io.ReadCloser
+	hash hash.Hash
+	n    int64
+}
+
+func (b *hashingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.hash.Write(p[:n])
+	b.n += int64(n)
+	return n, err
+}
+
+func (b *hashingBody) digest() string {
+	return hex.EncodeToString(b.hash.Sum(nil))
+}
+
+// sameBodyPrefix reports whether the first n bytes of body hash to digest
+func sameBodyPrefix(body io.Reader, n int64, digest string) (bool, error) {
+	hash := sha256.New()
+	if body != nil {
+		if _, err := io.CopyN(hash, body, n); err != nil && err != io.EOF {
+			return false, err
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)) == digest, nil
+}
+
+// idempotencyWriter keeps a copy of the response body up to limit bytes
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	limit    int
+	overflow bool
+}
+
+func (w *idempotencyWriter) Write(data []byte) (int, error) {
+	w.keep(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyWriter) WriteString(s string) (int, error) {
+	w.keep([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *idempotencyWriter) keep(data []byte) {
+	if w.overflow {
+		return
+	}
+	if w.body.Len()+len(data) > w.limit {
+		w.overflow = true
+		w.body.Reset()
+		return
+	}
+	w.body.Write(data)
+}
+
+// localIdempotencyStore keeps idempotent responses in memory, for a
+// replica that runs without Redis
+type localIdempotencyStore struct {
+	mu        sync.Mutex
+	values    map[string]localIdempotencyValue
+	lastSweep time.Time
+}
+
+type localIdempotencyValue struct {
+	value     string
+	expiresAt time.Time
+}
+
+func newLocalIdempotencyStore() *localIdempotencyStore {
+	return &localIdempotencyStore{values: make(map[string]localIdempotencyValue)}
+}
+
+func (s *localIdempotencyStore) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	s.sweep(now)
+	if current, ok := s.values[key]; ok && now.Before(current.expiresAt) {
+		return false, nil
+	}
+	s.values[key] = localIdempotencyValue{value: localValue(value), expiresAt: now.Add(expiration)}
+	return true, nil
+}
+
+func (s *localIdempotencyStore) Get(ctx context.Context, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if current, ok := s.values[key]; ok && time.Now().Before(current.expiresAt) {
+		return current.value, nil
+	}
+	return "", nil
+}
+
+func (s *localIdempotencyStore) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = localIdempotencyValue{value: localValue(value), expiresAt: time.Now().Add(expiration)}
+	return nil
+}
+
+func (s *localIdempotencyStore) Delete(ctx context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range keys {
+		delete(s.values, key)
+	}
+	return nil
+}
+
+// sweep drops expired values, at most once per rateLimitSweepInterval
+func (s *localIdempotencyStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, value := range s.values {
+		if now.After(value.expiresAt) {
+			delete(s.values, key)
+		}
+	}
+}
+
+// localValue stores a value the way Redis returns it, as a string
+func localValue(value interface{}) string {
+	if data, ok := value.([]byte); ok {
+		return string(data)
+	}
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
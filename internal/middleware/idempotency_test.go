@@ -0,0 +1,215 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+)
+
+// idempotencyHandler counts the documents it creates
+type idempotencyHandler struct {
+	mu      sync.Mutex
+	created int
+	status  int
+	panics  bool
+	started chan struct{}
+	release chan struct{}
+}
+
+func (h *idempotencyHandler) create(c *gin.Context) {
+	if h.started != nil {
+		close(h.started)
+		<-h.release
+	}
+	if h.panics {
+		panic("handler failed")
+	}
+	// Uploads are read to the end
+	io.Copy(io.Discard, c.Request.Body)
+	h.mu.Lock()
+	h.created++
+	id := h.created
+	h.mu.Unlock()
+	if h.status != 0 {
+		c.JSON(h.status, gin.H{"code": "INTERNAL_ERROR"})
+		return
+	}
+	c.Header("Location", "/documents/doc_"+strconv.Itoa(id))
+	c.JSON(http.StatusCreated, gin.H{"id": "doc_" + strconv.Itoa(id)})
+}
+
+func newIdempotencyRouter(t *testing.T, h *idempotencyHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	log, err := logger.New(logger.Config{Level: "error", Format: "json"})
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(Recovery(log))
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", c.GetHeader("X-Test-User"))
+		c.Next()
+	})
+	router.Use(Idempotency(nil, config.IdempotencyConfig{TTLSeconds: 60, MaxResponseBytes: 1 << 10}, log))
+	router.POST("/documents", h.create)
+	return router
+}
+
+func postIdempotent(router *gin.Engine, user, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/documents", strings.NewReader(body))
+	req.Header.Set("X-Test-User", user)
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotencyReplaysResponse(t *testing.T) {
+	h := &idempotencyHandler{}
+	router := newIdempotencyRouter(t, h)
+
+	first := postIdempotent(router, "user_1", "key-1", `{"name":"a"}`)
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Empty(t, first.Header().Get(IdempotentReplayedHeader))
+
+	retry := postIdempotent(router, "user_1", "key-1", `{"name":"a"}`)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, "true", retry.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+	assert.Equal(t, "/documents/doc_1", retry.Header().Get("Location"))
+	assert.Equal(t, 1, h.created, "a retry is not handled again")
+
+	assert.Equal(t, http.StatusCreated, postIdempotent(router, "user_2", "key-1", `{"name":"a"}`).Code)
+	assert.Equal(t, 2, h.created, "keys are scoped to the user")
+
+	postIdempotent(router, "user_1", "", `{"name":"a"}`)
+	assert.Equal(t, 3, h.created, "requests without a key are not affected")
+}
+
+func TestIdempotencyScopedToSpace(t *testing.T) {
+	h := &idempotencyHandler{}
+	router := newIdempotencyRouter(t, h)
+
+	post := func(spaceID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/documents", strings.NewReader(`{"name":"a"}`))
+		req.Header.Set("X-Test-User", "user_1")
+		req.Header.Set("X-Space-Type", "organization")
+		req.Header.Set("X-Space-ID", spaceID)
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusCreated, post("space_1").Code)
+	assert.Equal(t, http.StatusCreated, post("space_2").Code)
+	assert.Equal(t, 2, h.created, "keys are scoped to the space")
+
+	assert.Equal(t, "true", post("space_1").Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, 2, h.created)
+}
+
+func TestIdempotencyRejectsReusedKey(t *testing.T) {
+	h := &idempotencyHandler{}
+	router := newIdempotencyRouter(t, h)
+
+	postIdempotent(router, "user_1", "key-1", `{"name":"a"}`)
+	w := postIdempotent(router, "user_1", "key-1", `{"name":"b"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "AETHER-IDEM-002")
+	assert.Equal(t, 1, h.created)
+}
+
+func TestIdempotencyRejectsInvalidKey(t *testing.T) {
+	h := &idempotencyHandler{}
+	router := newIdempotencyRouter(t, h)
+
+	for _, key := range []string{"key with spaces", strings.Repeat("k", 256)} {
+		w := postIdempotent(router, "user_1", key, `{}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code, key)
+		assert.Contains(t, w.Body.String(), "AETHER-IDEM-003")
+	}
+	assert.Equal(t, 0, h.created)
+}
+
+func TestIdempotencyRejectsConcurrentRetry(t *testing.T) {
+	h := &idempotencyHandler{started: make(chan struct{}), release: make(chan struct{})}
+	router := newIdempotencyRouter(t, h)
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		done <- postIdempotent(router, "user_1", "key-1", `{}`)
+	}()
+	<-h.started
+
+	w := postIdempotent(router, "user_1", "key-1", `{}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "AETHER-IDEM-001")
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+	close(h.release)
+	assert.Equal(t, http.StatusCreated, (<-done).Code)
+	assert.Equal(t, 1, h.created)
+}
+
+func TestIdempotencyReleasesKeyOnServerError(t *testing.T) {
+	h := &idempotencyHandler{status: http.StatusInternalServerError}
+	router := newIdempotencyRouter(t, h)
+
+	assert.Equal(t, http.StatusInternalServerError, postIdempotent(router, "user_1", "key-1", `{}`).Code)
+	h.status = 0
+	w := postIdempotent(router, "user_1", "key-1", `{}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Empty(t, w.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, 2, h.created, "the retry is handled again")
+}
+
+func TestIdempotencyReleasesKeyOnPanic(t *testing.T) {
+	h := &idempotencyHandler{panics: true}
+	router := newIdempotencyRouter(t, h)
+
+	assert.Equal(t, http.StatusInternalServerError, postIdempotent(router, "user_1", "key-1", `{}`).Code)
+	h.panics = false
+	w := postIdempotent(router, "user_1", "key-1", `{}`)
+	assert.Equal(t, http.StatusCreated, w.Code, "the key is not left taken")
+	assert.Empty(t, w.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, 1, h.created)
+}
+
+func TestIdempotencyFingerprintsLargeBodies(t *testing.T) {
+	h := &idempotencyHandler{}
+	router := newIdempotencyRouter(t, h)
+
+	upload := func(fill byte, contentType string) *httptest.ResponseRecorder {
+		body := bytes.Repeat([]byte{fill}, idempotencyMaxHashedBody+1)
+		req := httptest.NewRequest(http.MethodPost, "/documents", bytes.NewReader(body))
+		req.Header.Set("X-Test-User", "user_1")
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusCreated, upload('a', "application/pdf").Code)
+	assert.Equal(t, "true", upload('a', "application/pdf").Header().Get(IdempotentReplayedHeader))
+
+	w := upload('b', "application/pdf")
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code, "another file of the same size is not answered with the first document")
+	assert.Contains(t, w.Body.String(), "AETHER-IDEM-002")
+	assert.Equal(t, http.StatusUnprocessableEntity, upload('a', "image/png").Code)
+	assert.Equal(t, 1, h.created)
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// SaturationProbe reports whether a backing resource can take more work.
+// database.Neo4jClient implements it.
+type SaturationProbe interface {
+	Saturated() bool
+}
+
+// LoadShedding rejects requests with 429 while probe reports saturation.
+// Failing fast keeps latency bounded for the requests already admitted and
+// tells clients to back off, instead of queueing until everything times out.
+func LoadShedding(probe SaturationProbe) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if probe != nil && probe.Saturated() {
+			apiErr := errors.TooManyRequests("Server is busy, please retry shortly").WithRequestID(requestIDFromGin(c))
+			c.Header("Retry-After", "1")
+			c.AbortWithStatusJSON(apiErr.StatusCode, apiErr)
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeProbe struct {
+	saturated atomic.Bool
+}
+
+func (p *fakeProbe) Saturated() bool {
+	return p.saturated.Load()
+}
+
+func TestLoadSheddingRejectsWhileSaturated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	probe := &fakeProbe{}
+	router := gin.New()
+	router.Use(LoadShedding(probe))
+	router.GET("/items", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	request := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items", nil))
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, request().Code)
+
+	probe.saturated.Store(true)
+	w := request()
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), "TOO_MANY_REQUESTS")
+
+	probe.saturated.Store(false)
+	assert.Equal(t, http.StatusOK, request().Code)
+}
+
+func TestLoadSheddingWithoutProbe(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(LoadShedding(nil))
+	router.GET("/items", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tributary-ai-services/aether-be/internal/i18n"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// MaintenanceSource reports the maintenance window that applies to a
+// tenant, or the system-wide one for an empty tenant ID.
+// services.MaintenanceService implements it.
+type MaintenanceSource interface {
+	Window(tenantID string) *models.MaintenanceWindow
+}
+
+// MaintenanceHeader names the scope of the maintenance in progress on
+// every response it applies to, so clients can show a banner
+const MaintenanceHeader = "X-Maintenance-Mode"
+
+// maintenanceRetryAfter is the Retry-After of rejected writes when the
+// maintenance has no expected end, or it has passed
+const maintenanceRetryAfter = time.Minute
+
+// maintenanceKey is the gin context key of the request's maintenanceCheck
+const maintenanceKey = "maintenance_check"
+
+// maintenanceCheck is what a request needs to apply maintenance once its
+// tenant is known
+type maintenanceCheck struct {
+	source MaintenanceSource
+	exempt map[string]bool
+}
+
+// Maintenance rejects writes with 503 and AETHER-MAINT-001 while
+// system-wide maintenance is in progress; reads, downloads and WebSocket
+// streams keep working. Writes are requests other than GET, HEAD and
+// OPTIONS whose route pattern, as registered, is not in exempt, which
+// lists POST routes that only read and the routes that end maintenance.
+// The maintenance of a tenant applies once SpaceContextMiddleware resolved
+// the request's space; see CheckTenantMaintenance.
+func Maintenance(source MaintenanceSource, exempt map[string]bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if source == nil {
+			c.Next()
+			return
+		}
+
+		check := &maintenanceCheck{source: source, exempt: exempt}
+		c.Set(maintenanceKey, check)
+		if check.reject(c, source.Window("")) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// CheckTenantMaintenance applies the maintenance of the tenant a request's
+// space belongs to. It reports whether the request was rejected, in which
+// case the response has been written.
+func CheckTenantMaintenance(c *gin.Context, tenantID string) bool {
+	value, ok := c.Get(maintenanceKey)
+	if !ok || tenantID == "" {
+		return false
+	}
+	check := value.(*maintenanceCheck)
+	return check.reject(c, check.source.Window(tenantID))
+}
+
+// reject marks the response with the window and rejects the request when
+// it is a write
+func (m *maintenanceCheck) reject(c *gin.Context, window *models.MaintenanceWindow) bool {
+	if window == nil {
+		return false
+	}
+	c.Header(MaintenanceHeader, window.Scope)
+
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	if m.exempt[c.FullPath()] {
+		return false
+	}
+
+	details := map[string]interface{}{
+		"scope":      window.Scope,
+		"message":    window.Message,
+		"started_at": window.StartedAt,
+	}
+	if window.TenantID != "" {
+		details["tenant_id"] = window.TenantID
+	}
+	retryAfter := maintenanceRetryAfter
+	if window.ExpectedEndAt != nil {
+		details["expected_end_at"] = *window.ExpectedEndAt
+		if until := time.Until(*window.ExpectedEndAt); until > retryAfter {
+			retryAfter = until
+		}
+	}
+
+	apiErr := errors.NewAPIError(errors.ErrServiceUnavailable, "Writes are unavailable during maintenance", details).
+		WithErrorCode(errors.CodeMaintenanceInProgress).
+		WithRequestID(requestIDFromGin(c))
+	apiErr = i18n.LocalizeError(i18n.FromContext(c.Request.Context()), apiErr)
+	c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+	c.AbortWithStatusJSON(apiErr.StatusCode, apiErr)
+	return true
+}
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+type fakeMaintenance struct {
+	system  *models.MaintenanceWindow
+	tenants map[string]*models.MaintenanceWindow
+}
+
+func (m *fakeMaintenance) Window(tenantID string) *models.MaintenanceWindow {
+	if m.system != nil || tenantID == "" {
+		return m.system
+	}
+	return m.tenants[tenantID]
+}
+
+func newMaintenanceRouter(source MaintenanceSource) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Maintenance(source, map[string]bool{"/search": true}))
+	ok := func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	}
+	router.GET("/items", ok)
+	router.POST("/items", ok)
+	router.POST("/search", ok)
+	router.POST("/tenants/:tenant_id/items", func(c *gin.Context) {
+		if CheckTenantMaintenance(c, c.Param("tenant_id")) {
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func serveMaintenance(router *gin.Engine, method, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	return w
+}
+
+func TestMaintenanceRejectsWrites(t *testing.T) {
+	end := time.Now().Add(time.Hour)
+	source := &fakeMaintenance{system: &models.MaintenanceWindow{
+		Scope:         models.MaintenanceScopeSystem,
+		Message:       "Database migration",
+		StartedAt:     time.Now(),
+		ExpectedEndAt: &end,
+	}}
+	router := newMaintenanceRouter(source)
+
+	w := serveMaintenance(router, http.MethodGet, "/items")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, models.MaintenanceScopeSystem, w.Header().Get(MaintenanceHeader))
+
+	w = serveMaintenance(router, http.MethodPost, "/items")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), "AETHER-MAINT-001")
+	assert.Contains(t, w.Body.String(), "Database migration")
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	assert.NotEqual(t, "60", w.Header().Get("Retry-After"), "the expected end sets Retry-After")
+
+	assert.Equal(t, http.StatusOK, serveMaintenance(router, http.MethodPost, "/search").Code)
+}
+
+func TestMaintenanceOfTenant(t *testing.T) {
+	source := &fakeMaintenance{tenants: map[string]*models.MaintenanceWindow{
+		"tenant-1": {Scope: models.MaintenanceScopeTenant, TenantID: "tenant-1", Message: "Tenant migration"},
+	}}
+	router := newMaintenanceRouter(source)
+
+	assert.Equal(t, http.StatusOK, serveMaintenance(router, http.MethodPost, "/items").Code)
+
+	w := serveMaintenance(router, http.MethodPost, "/tenants/tenant-1/items")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+	assert.Equal(t, models.MaintenanceScopeTenant, w.Header().Get(MaintenanceHeader))
+
+	w = serveMaintenance(router, http.MethodPost, "/tenants/tenant-2/items")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get(MaintenanceHeader))
+}
+
+func TestMaintenanceWithoutSource(t *testing.T) {
+	router := newMaintenanceRouter(nil)
+
+	assert.Equal(t, http.StatusOK, serveMaintenance(router, http.MethodPost, "/items").Code)
+	assert.Equal(t, http.StatusOK, serveMaintenance(router, http.MethodPost, "/tenants/tenant-1/items").Code)
+}
@@ -0,0 +1,275 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/i18n"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// RateLimitSource supplies the current rate limit settings.
+// config.RuntimeStore implements it.
+type RateLimitSource interface {
+	RateLimit() config.RateLimitConfig
+}
+
+// RateLimitStore keeps token buckets shared by every replica.
+// database.RedisClient implements it.
+type RateLimitStore interface {
+	TakeToken(ctx context.Context, key string, perSecond float64, burst, overdraft int, debtCost float64) (database.TokenBucketResult, error)
+}
+
+// Rate limit headers of every limited response
+const (
+	RateLimitLimitHeader     = "X-RateLimit-Limit"
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+	RateLimitResetHeader     = "X-RateLimit-Reset"
+	// RateLimitDebtHeader is set while a bucket owes tokens taken on
+	// overdraft
+	RateLimitDebtHeader = "X-RateLimit-Debt"
+)
+
+// Idle buckets are dropped after rateLimitIdleTTL; sweeps run at most once
+// per rateLimitSweepInterval
+const (
+	rateLimitIdleTTL       = 10 * time.Minute
+	rateLimitSweepInterval = time.Minute
+)
+
+// rateLimitKey is the gin context key of the request's rateLimitCheck
+const rateLimitKey = "rate_limit_check"
+
+// tokenBucket tracks the remaining requests of one client
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// rateLimiter keeps a token bucket per client. Limits are per replica.
+type rateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{buckets: make(map[string]*tokenBucket)}
+}
+
+// take takes a token from key's bucket, which rule refills, or debtCost
+// tokens on credit when it is empty and rule allows an overdraft
+func (l *rateLimiter) take(key string, rule config.RateLimitRule, debtCost float64, now time.Time) database.TokenBucketResult {
+	rate := float64(rule.RequestsPerMinute) / 60
+	burst := ruleBurst(rule)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(burst), updated: now}
+		l.buckets[key] = bucket
+	}
+	bucket.tokens = math.Min(float64(burst), bucket.tokens+now.Sub(bucket.updated).Seconds()*rate)
+	bucket.updated = now
+
+	allowed := true
+	switch {
+	case bucket.tokens >= 1:
+		bucket.tokens--
+	case bucket.tokens-debtCost >= -float64(rule.Overdraft):
+		bucket.tokens -= debtCost
+	default:
+		allowed = false
+	}
+	return database.NewTokenBucketResult(allowed, bucket.tokens, rate, burst, rule.Overdraft, debtCost)
+}
+
+// sweep drops buckets of clients that have been idle for rateLimitIdleTTL
+// and owe no tokens
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, bucket := range l.buckets {
+		if now.Sub(bucket.updated) > rateLimitIdleTTL && bucket.tokens >= 0 {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// ruleBurst is the size of rule's buckets; a bucket holds a token at least
+func ruleBurst(rule config.RateLimitRule) int {
+	if rule.Burst < 1 {
+		return 1
+	}
+	return rule.Burst
+}
+
+// ruleDebtCost is the tokens a request taken on overdraft costs
+func ruleDebtCost(limits config.RateLimitConfig) float64 {
+	return math.Max(1, limits.DebtCost)
+}
+
+// rateLimitCheck is what a request needs to apply the limit of its tenant
+// once the tenant is known
+type rateLimitCheck struct {
+	buckets *rateLimitBuckets
+	limits  config.RateLimitConfig
+	class   string
+	// tightest is the result reported in the headers so far
+	tightest *database.TokenBucketResult
+	// tenantChecked and processingChecked are set once the tenant's
+	// tokens were taken, so that a space resolved twice costs it a single
+	// token
+	tenantChecked     bool
+	processingChecked bool
+}
+
+// rateLimitBuckets takes tokens from the shared store, or from the
+// replica's own buckets when there is no store or it failed
+type rateLimitBuckets struct {
+	store  RateLimitStore
+	local  *rateLimiter
+	logger *logger.Logger
+}
+
+func (l *rateLimitBuckets) take(ctx context.Context, key string, rule config.RateLimitRule, debtCost float64) database.TokenBucketResult {
+	if l.store != nil {
+		result, err := l.store.TakeToken(ctx, key, float64(rule.RequestsPerMinute)/60, ruleBurst(rule), rule.Overdraft, debtCost)
+		if err == nil {
+			return result
+		}
+		l.logger.Warn("Rate limit store failed; limiting on this replica", zap.Error(err))
+	}
+	return l.local.take(key, rule, debtCost, time.Now())
+}
+
+// RateLimit limits the requests of each authenticated user, or of each
+// client IP when no user is known, per route class: classOf returns the
+// class of a request, or "" for requests that are not limited. With a
+// store the limits are shared by every replica. Settings are read from
+// source on every request so changes made by a runtime config reload apply
+// immediately. The limit of a tenant applies once SpaceContextMiddleware
+// resolved the request's space; see CheckTenantRateLimit.
+func RateLimit(source RateLimitSource, store RateLimitStore, classOf func(*gin.Context) string, log *logger.Logger) gin.HandlerFunc {
+	buckets := &rateLimitBuckets{store: store, local: newRateLimiter(), logger: log}
+
+	return func(c *gin.Context) {
+		limits := source.RateLimit()
+		class := classOf(c)
+		if !limits.Enabled || class == "" {
+			c.Next()
+			return
+		}
+
+		check := &rateLimitCheck{buckets: buckets, limits: limits, class: class}
+		c.Set(rateLimitKey, check)
+
+		rule := limits.UserRule(class)
+		if rule.RequestsPerMinute <= 0 {
+			c.Next()
+			return
+		}
+		key := "ip:" + c.ClientIP()
+		if userID := c.GetString("user_id"); userID != "" {
+			key = "user:" + userID
+		}
+		if check.apply(c, key+":"+class, rule, errors.CodeUserRateLimitExceeded, "user") {
+			return
+		}
+		c.Next()
+	}
+}
+
+// CheckTenantRateLimit applies the limit of the tenant a request's space
+// belongs to, which all its users share. It reports whether the request was
+// rejected, in which case the response has been written.
+func CheckTenantRateLimit(c *gin.Context, tenantID string) bool {
+	value, ok := c.Get(rateLimitKey)
+	if !ok || tenantID == "" {
+		return false
+	}
+	check := value.(*rateLimitCheck)
+	rule := check.limits.TenantRule(check.class)
+	if check.tenantChecked || rule.RequestsPerMinute <= 0 {
+		return false
+	}
+	check.tenantChecked = true
+	return check.apply(c, "tenant:"+tenantID+":"+check.class, rule, errors.CodeTenantRateLimitExceeded, "tenant")
+}
+
+// ProcessingRateLimit limits the requests that submit documents for
+// processing by the tenant of their space, with the rule of the tenant's
+// billing plan. Plans may allow an overdraft, so a short spike over the
+// limit goes through and is paid back by throttling the tenant's later
+// submissions. It must run after SpaceContextMiddleware, and only limits
+// while rate limiting is enabled.
+func ProcessingRateLimit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, ok := c.Get(rateLimitKey)
+		spaceContext, err := GetSpaceContext(c)
+		if !ok || err != nil || spaceContext.TenantID == "" {
+			c.Next()
+			return
+		}
+		check := value.(*rateLimitCheck)
+		rule := check.limits.ProcessingRule(spaceContext.Plan)
+		if check.processingChecked || rule.RequestsPerMinute <= 0 {
+			c.Next()
+			return
+		}
+		check.processingChecked = true
+		if check.apply(c, "processing:tenant:"+spaceContext.TenantID, rule, errors.CodeProcessingRateLimitExceeded, "processing") {
+			return
+		}
+		c.Next()
+	}
+}
+
+// apply takes a token from key's bucket, reports the tightest limit in the
+// response headers, and rejects the request with 429 when no token was left
+func (r *rateLimitCheck) apply(c *gin.Context, key string, rule config.RateLimitRule, code, scope string) bool {
+	result := r.buckets.take(c.Request.Context(), key, rule, ruleDebtCost(r.limits))
+
+	if r.tightest == nil || result.Remaining < r.tightest.Remaining || result.Debt > r.tightest.Debt || !result.Allowed {
+		r.tightest = &result
+		c.Header(RateLimitLimitHeader, strconv.Itoa(ruleBurst(rule)))
+		c.Header(RateLimitRemainingHeader, strconv.Itoa(result.Remaining))
+		c.Header(RateLimitResetHeader, strconv.FormatInt(time.Now().Add(result.ResetAfter).Unix(), 10))
+		if result.Debt > 0 {
+			c.Header(RateLimitDebtHeader, strconv.Itoa(result.Debt))
+		} else {
+			c.Writer.Header().Del(RateLimitDebtHeader)
+		}
+	}
+	if result.Allowed {
+		return false
+	}
+
+	retryAfter := int(math.Ceil(result.RetryAfter.Seconds()))
+	apiErr := errors.NewAPIError(errors.ErrTooManyRequests, "Rate limit exceeded, please retry later", map[string]interface{}{
+		"scope":       scope,
+		"class":       r.class,
+		"retry_after": retryAfter,
+	}).
+		WithErrorCode(code).
+		WithRequestID(requestIDFromGin(c))
+	apiErr = i18n.LocalizeError(i18n.FromContext(c.Request.Context()), apiErr)
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.AbortWithStatusJSON(apiErr.StatusCode, apiErr)
+	return true
+}
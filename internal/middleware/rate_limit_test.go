@@ -0,0 +1,251 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+type fakeRateLimitSource struct {
+	mu     sync.Mutex
+	limits config.RateLimitConfig
+}
+
+func (s *fakeRateLimitSource) RateLimit() config.RateLimitConfig {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.limits
+}
+
+func (s *fakeRateLimitSource) set(limits config.RateLimitConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limits = limits
+}
+
+// failingRateLimitStore stands in for Redis being unavailable
+type failingRateLimitStore struct{}
+
+func (failingRateLimitStore) TakeToken(ctx context.Context, key string, perSecond float64, burst, overdraft int, debtCost float64) (database.TokenBucketResult, error) {
+	return database.TokenBucketResult{}, fmt.Errorf("connection refused")
+}
+
+func TestRateLimiterRefills(t *testing.T) {
+	limiter := newRateLimiter()
+	rule := config.RateLimitRule{RequestsPerMinute: 60, Burst: 2}
+	now := time.Now()
+
+	result := limiter.take("user:a", rule, 1, now)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Remaining)
+	assert.Equal(t, time.Second, result.ResetAfter)
+	result = limiter.take("user:a", rule, 1, now)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	result = limiter.take("user:a", rule, 1, now)
+	assert.False(t, result.Allowed, "burst exhausted")
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Equal(t, 2*time.Second, result.ResetAfter)
+
+	result = limiter.take("user:b", rule, 1, now)
+	assert.True(t, result.Allowed, "clients have separate buckets")
+
+	result = limiter.take("user:a", rule, 1, now.Add(time.Second))
+	assert.True(t, result.Allowed, "one token is refilled per second at 60 rpm")
+}
+
+func TestRateLimiterLendsOverdraft(t *testing.T) {
+	limiter := newRateLimiter()
+	rule := config.RateLimitRule{RequestsPerMinute: 60, Burst: 2, Overdraft: 4}
+	now := time.Now()
+
+	limiter.take("tenant:a", rule, 2, now)
+	result := limiter.take("tenant:a", rule, 2, now)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	// Two requests over the burst cost two tokens each
+	result = limiter.take("tenant:a", rule, 2, now)
+	assert.True(t, result.Allowed, "taken on overdraft")
+	assert.Equal(t, 2, result.Debt)
+	result = limiter.take("tenant:a", rule, 2, now)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 4, result.Debt)
+
+	result = limiter.take("tenant:a", rule, 2, now)
+	assert.False(t, result.Allowed, "overdraft exhausted")
+	assert.Equal(t, 2*time.Second, result.RetryAfter)
+	assert.Equal(t, 6*time.Second, result.ResetAfter, "the debt is repaid before the bucket refills")
+
+	result = limiter.take("tenant:a", rule, 2, now.Add(time.Second))
+	assert.False(t, result.Allowed, "still paying back the spike")
+	result = limiter.take("tenant:a", rule, 2, now.Add(2*time.Second))
+	assert.True(t, result.Allowed)
+}
+
+func TestRateLimiterDropsIdleBuckets(t *testing.T) {
+	limiter := newRateLimiter()
+	rule := config.RateLimitRule{RequestsPerMinute: 60, Burst: 1}
+	now := time.Now()
+
+	limiter.take("user:a", rule, 1, now)
+	limiter.take("user:b", rule, 1, now.Add(rateLimitIdleTTL+2*rateLimitSweepInterval))
+	assert.Len(t, limiter.buckets, 1)
+}
+
+func newRateLimitRouter(t *testing.T, source RateLimitSource, store RateLimitStore) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	log, err := logger.New(logger.Config{Level: "error", Format: "json"})
+	require.NoError(t, err)
+
+	classOf := func(c *gin.Context) string {
+		if c.Request.Method == http.MethodGet {
+			return config.RateLimitRead
+		}
+		return config.RateLimitUpload
+	}
+	router := gin.New()
+	router.Use(RateLimit(source, store, classOf, log))
+	ok := func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	}
+	router.GET("/items", ok)
+	router.POST("/items", ok)
+	router.GET("/tenants/:tenant_id/items", func(c *gin.Context) {
+		if CheckTenantRateLimit(c, c.Param("tenant_id")) {
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+	setSpace := func(c *gin.Context) {
+		c.Set(SpaceContextKey, &models.SpaceContext{TenantID: c.Param("tenant_id"), Plan: c.Param("plan")})
+	}
+	router.POST("/tenants/:tenant_id/plans/:plan/documents", setSpace, ProcessingRateLimit(), ok)
+	return router
+}
+
+func serveRateLimited(router *gin.Engine, method, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	return w
+}
+
+func TestRateLimitFollowsSourceChanges(t *testing.T) {
+	source := &fakeRateLimitSource{}
+	router := newRateLimitRouter(t, source, nil)
+
+	// Disabled: no limit applies
+	for i := 0; i < 3; i++ {
+		w := serveRateLimited(router, http.MethodGet, "/items")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get(RateLimitLimitHeader))
+	}
+
+	source.set(config.RateLimitConfig{Enabled: true, RequestsPerMinute: 1, Burst: 1})
+	w := serveRateLimited(router, http.MethodGet, "/items")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1", w.Header().Get(RateLimitLimitHeader))
+	assert.Equal(t, "0", w.Header().Get(RateLimitRemainingHeader))
+	reset, err := strconv.ParseInt(w.Header().Get(RateLimitResetHeader), 10, 64)
+	require.NoError(t, err)
+	assert.InDelta(t, time.Now().Add(time.Minute).Unix(), reset, 2)
+
+	w = serveRateLimited(router, http.MethodGet, "/items")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), "TOO_MANY_REQUESTS")
+	assert.Contains(t, w.Body.String(), "AETHER-RATE-001")
+
+	source.set(config.RateLimitConfig{Enabled: false})
+	assert.Equal(t, http.StatusOK, serveRateLimited(router, http.MethodGet, "/items").Code)
+}
+
+func TestRateLimitAppliesClassRules(t *testing.T) {
+	source := &fakeRateLimitSource{limits: config.RateLimitConfig{
+		Enabled:           true,
+		RequestsPerMinute: 600,
+		Burst:             100,
+		Classes:           map[string]config.RateLimitRule{config.RateLimitUpload: {RequestsPerMinute: 1, Burst: 1}},
+	}}
+	// The store failing falls back to the replica's own buckets
+	router := newRateLimitRouter(t, source, failingRateLimitStore{})
+
+	assert.Equal(t, http.StatusOK, serveRateLimited(router, http.MethodPost, "/items").Code)
+	assert.Equal(t, http.StatusTooManyRequests, serveRateLimited(router, http.MethodPost, "/items").Code)
+
+	w := serveRateLimited(router, http.MethodGet, "/items")
+	assert.Equal(t, http.StatusOK, w.Code, "uploads do not use up reads")
+	assert.Equal(t, "100", w.Header().Get(RateLimitLimitHeader))
+	assert.Equal(t, "99", w.Header().Get(RateLimitRemainingHeader))
+}
+
+func TestRateLimitLimitsTenants(t *testing.T) {
+	source := &fakeRateLimitSource{limits: config.RateLimitConfig{
+		Enabled:                 true,
+		RequestsPerMinute:       600,
+		Burst:                   100,
+		TenantRequestsPerMinute: 60,
+		TenantBurst:             2,
+	}}
+	router := newRateLimitRouter(t, source, nil)
+
+	w := serveRateLimited(router, http.MethodGet, "/tenants/tenant_1/items")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get(RateLimitLimitHeader), "the tighter limit is reported")
+	assert.Equal(t, "1", w.Header().Get(RateLimitRemainingHeader))
+
+	assert.Equal(t, http.StatusOK, serveRateLimited(router, http.MethodGet, "/tenants/tenant_1/items").Code)
+	w = serveRateLimited(router, http.MethodGet, "/tenants/tenant_1/items")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Contains(t, w.Body.String(), "AETHER-RATE-002")
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusOK, serveRateLimited(router, http.MethodGet, "/tenants/tenant_2/items").Code, "tenants have separate buckets")
+}
+
+func TestProcessingRateLimitFollowsPlan(t *testing.T) {
+	source := &fakeRateLimitSource{limits: config.RateLimitConfig{
+		Enabled:           true,
+		RequestsPerMinute: 600,
+		Burst:             100,
+		ProcessingPlans: map[string]config.RateLimitRule{
+			"free":                       {RequestsPerMinute: 1, Burst: 1, Overdraft: 2},
+			config.DefaultProcessingPlan: {RequestsPerMinute: 60, Burst: 10},
+		},
+		DebtCost: 2,
+	}}
+	router := newRateLimitRouter(t, source, nil)
+
+	w := serveRateLimited(router, http.MethodPost, "/tenants/tenant_1/plans/free/documents")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1", w.Header().Get(RateLimitLimitHeader), "the plan's limit is the tighter")
+	assert.Empty(t, w.Header().Get(RateLimitDebtHeader))
+
+	w = serveRateLimited(router, http.MethodPost, "/tenants/tenant_1/plans/free/documents")
+	assert.Equal(t, http.StatusOK, w.Code, "a spike over the limit goes through on overdraft")
+	assert.Equal(t, "2", w.Header().Get(RateLimitDebtHeader))
+
+	w = serveRateLimited(router, http.MethodPost, "/tenants/tenant_1/plans/free/documents")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Contains(t, w.Body.String(), "AETHER-RATE-003")
+	assert.Equal(t, "120", w.Header().Get("Retry-After"), "the debt is paid back first")
+
+	w = serveRateLimited(router, http.MethodPost, "/tenants/tenant_2/plans/pro/documents")
+	assert.Equal(t, http.StatusOK, w.Code, "plans without a rule use the default")
+	assert.Equal(t, "10", w.Header().Get(RateLimitLimitHeader))
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tributary-ai-services/aether-be/internal/i18n"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// RegionSource reports where tenants are served in a multi-region
+// deployment. services.RegionService implements it.
+type RegionSource interface {
+	LocalRegion() string
+	RegionURL(region string) string
+	Pin(tenantID string) *models.TenantRegion
+}
+
+// RegionHeader names the region that answered, on every response
+const RegionHeader = "X-Aether-Region"
+
+// regionKey is the gin context key of the request's RegionSource
+const regionKey = "region_source"
+
+// Region names the answering region on every response. Requests in the
+// spaces of a tenant pinned to another region are rejected once
+// SpaceContextMiddleware resolved the space; see CheckTenantRegion.
+func Region(source RegionSource) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if source == nil {
+			c.Next()
+			return
+		}
+
+		c.Set(regionKey, source)
+		c.Header(RegionHeader, source.LocalRegion())
+		c.Next()
+	}
+}
+
+// CheckTenantRegion rejects with 421 and AETHER-REGION-001 a request in
+// the space of a tenant whose active region is another one, naming the
+// region's URL so the client or the edge can retry there. It reports
+// whether the request was rejected, in which case the response has been
+// written.
+func CheckTenantRegion(c *gin.Context, tenantID string) bool {
+	value, ok := c.Get(regionKey)
+	if !ok || tenantID == "" {
+		return false
+	}
+	source := value.(RegionSource)
+	pin := source.Pin(tenantID)
+	if pin == nil || pin.ActiveRegion == source.LocalRegion() {
+		return false
+	}
+
+	apiErr := errors.NewAPIError(errors.ErrMisdirectedRequest, "Tenant is served by another region", map[string]interface{}{
+		"tenant_id":  tenantID,
+		"region":     pin.ActiveRegion,
+		"region_url": source.RegionURL(pin.ActiveRegion),
+	}).WithErrorCode(errors.CodeTenantInOtherRegion).
+		WithRequestID(requestIDFromGin(c))
+	apiErr = i18n.LocalizeError(i18n.FromContext(c.Request.Context()), apiErr)
+	c.AbortWithStatusJSON(apiErr.StatusCode, apiErr)
+	return true
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+type fakeRegions struct {
+	pins map[string]*models.TenantRegion
+}
+
+func (r *fakeRegions) LocalRegion() string {
+	return "eu-west"
+}
+
+func (r *fakeRegions) RegionURL(region string) string {
+	return "https://" + region + ".aether.example.com"
+}
+
+func (r *fakeRegions) Pin(tenantID string) *models.TenantRegion {
+	return r.pins[tenantID]
+}
+
+func newRegionRouter(source RegionSource) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Region(source))
+	router.GET("/tenants/:tenant_id/items", func(c *gin.Context) {
+		if CheckTenantRegion(c, c.Param("tenant_id")) {
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestRegionRejectsTenantOfOtherRegion(t *testing.T) {
+	router := newRegionRouter(&fakeRegions{pins: map[string]*models.TenantRegion{
+		"tenant-1": {TenantID: "tenant-1", ActiveRegion: "eu-west", PassiveRegion: "us-east"},
+		"tenant-2": {TenantID: "tenant-2", ActiveRegion: "us-east", PassiveRegion: "eu-west"},
+	}})
+
+	for _, tenantID := range []string{"tenant-1", "tenant-3"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tenants/"+tenantID+"/items", nil))
+		assert.Equal(t, http.StatusOK, w.Code, tenantID)
+		assert.Equal(t, "eu-west", w.Header().Get(RegionHeader))
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tenants/tenant-2/items", nil))
+	assert.Equal(t, http.StatusMisdirectedRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "AETHER-REGION-001")
+	assert.Contains(t, w.Body.String(), "https://us-east.aether.example.com")
+}
+
+func TestRegionWithoutSource(t *testing.T) {
+	router := newRegionRouter(nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tenants/tenant-1/items", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get(RegionHeader))
+}
@@ -3,14 +3,18 @@ package middleware
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
 )
 
-// RequestIDMiddleware adds a unique request ID to each request
+// RequestIDMiddleware adds a unique request ID to each request, keeping
+// the caller's X-Request-ID when logger.ValidRequestID accepts it
 func RequestIDMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Check if request ID is already set (e.g., from load balancer)
-		requestID := c.GetHeader("X-Request-ID")
-		if requestID == "" {
+		// Check if request ID is already set (e.g., from load balancer); an
+		// ID that is missing or unsafe to log and forward is replaced
+		requestID := c.GetHeader(logger.RequestIDHeader)
+		if !logger.ValidRequestID(requestID) {
 			// Generate new UUID for request ID
 			requestID = uuid.New().String()
 		}
@@ -18,8 +22,12 @@ func RequestIDMiddleware() gin.HandlerFunc {
 		// Set request ID in context for use by handlers and other middleware
 		c.Set("request_id", requestID)
 
+		// Propagate into the request context so services, database queries,
+		// outbound HTTP calls and Kafka messages can pick it up
+		c.Request = c.Request.WithContext(logger.ContextWithRequestID(c.Request.Context(), requestID))
+
 		// Add request ID to response headers
-		c.Header("X-Request-ID", requestID)
+		c.Header(logger.RequestIDHeader, requestID)
 
 		c.Next()
 	}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestIDMiddleware())
+	router.GET("/ping", func(c *gin.Context) {
+		// Handlers and the services they call see the same ID
+		assert.Equal(t, c.GetString("request_id"), logger.RequestIDFromContext(c.Request.Context()))
+		c.String(http.StatusOK, logger.RequestIDFromContext(c.Request.Context()))
+	})
+
+	send := func(header string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		if header != "" {
+			req.Header.Set(logger.RequestIDHeader, header)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send("lb-req.42")
+	assert.Equal(t, "lb-req.42", w.Body.String(), "a valid inbound ID is kept")
+	assert.Equal(t, "lb-req.42", w.Header().Get(logger.RequestIDHeader))
+
+	for _, header := range []string{"", "evil\"id, injected", strings.Repeat("a", logger.MaxRequestIDLength+1)} {
+		w := send(header)
+		_, err := uuid.Parse(w.Body.String())
+		assert.NoError(t, err, "a missing or invalid ID %q is replaced", header)
+		assert.Equal(t, w.Body.String(), w.Header().Get(logger.RequestIDHeader))
+	}
+}
@@ -14,7 +14,7 @@ func RequireRole(requiredRole string) gin.HandlerFunc {
 		// Get user roles from context (set by auth middleware)
 		roles, exists := c.Get("user_roles")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+			c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 			c.Abort()
 			return
 		}
@@ -51,7 +51,7 @@ func RequireAnyRole(requiredRoles ...string) gin.HandlerFunc {
 		// Get user roles from context (set by auth middleware)
 		roles, exists := c.Get("user_roles")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+			c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 			c.Abort()
 			return
 		}
@@ -93,7 +93,7 @@ func RequireGroup(requiredGroup string) gin.HandlerFunc {
 		// Get user groups from context (set by auth middleware)
 		groups, exists := c.Get("user_groups")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated"))
+			c.JSON(http.StatusUnauthorized, errors.Unauthorized("User not authenticated").WithErrorCode(errors.CodeNotAuthenticated))
 			c.Abort()
 			return
 		}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// IncludeDeletedParam is the query parameter that asks reads to return
+// soft-deleted records as well
+const IncludeDeletedParam = "include_deleted"
+
+// IncludeDeleted honours include_deleted=true for admins by marking the
+// request context (see database.SoftDeleteFilter). Other users get 403
+// rather than a silently filtered result. It must run after authentication.
+func IncludeDeleted() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		include, _ := strconv.ParseBool(c.Query(IncludeDeletedParam))
+		if !include {
+			c.Next()
+			return
+		}
+
+		roles, _ := c.Get("user_roles")
+		userRoles, _ := roles.([]string)
+		isAdmin := false
+		for _, role := range userRoles {
+			if role == "admin" {
+				isAdmin = true
+				break
+			}
+		}
+		if !isAdmin {
+			apiErr := errors.Forbidden("Only administrators may include deleted records").WithRequestID(requestIDFromGin(c))
+			c.AbortWithStatusJSON(http.StatusForbidden, apiErr)
+			return
+		}
+
+		c.Request = c.Request.WithContext(database.WithIncludeDeleted(c.Request.Context()))
+		c.Next()
+	}
+}
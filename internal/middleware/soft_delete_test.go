@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+)
+
+func TestIncludeDeleted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	request := func(roles []string, query string) (*httptest.ResponseRecorder, bool) {
+		included := false
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			c.Set("user_roles", roles)
+			c.Next()
+		})
+		router.Use(IncludeDeleted())
+		router.GET("/items", func(c *gin.Context) {
+			included = database.IncludeDeleted(c.Request.Context())
+			c.Status(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items"+query, nil))
+		return w, included
+	}
+
+	w, included := request([]string{"user"}, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, included)
+
+	w, included = request([]string{"user"}, "?include_deleted=false")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, included)
+
+	w, _ = request([]string{"user"}, "?include_deleted=true")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w, included = request([]string{"user", "admin"}, "?include_deleted=true")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, included)
+}
@@ -98,6 +98,19 @@ func SpaceContextMiddleware(spaceService *services.SpaceContextService, log *log
 		// Store space context in gin context
 		c.Set(SpaceContextKey, spaceContext)
 
+		// Requests for a tenant served by another region, writes to a
+		// tenant under maintenance and requests over the tenant's rate limit
+		// are rejected once its space is known
+		if CheckTenantRegion(c, spaceContext.TenantID) {
+			return
+		}
+		if CheckTenantMaintenance(c, spaceContext.TenantID) {
+			return
+		}
+		if CheckTenantRateLimit(c, spaceContext.TenantID) {
+			return
+		}
+
 		logger.Debug("Space context resolved",
 			zap.String("user_id", userID.(string)),
 			zap.String("space_type", string(spaceContext.SpaceType)),
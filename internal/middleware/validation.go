@@ -39,28 +39,30 @@ func ValidationMiddleware(log *logger.Logger) gin.HandlerFunc {
 			return
 		}
 
-		// Read the request body
-		body, err := io.ReadAll(c.Request.Body)
-		if err != nil {
-			log.Error("Failed to read request body", zap.Error(err))
-			c.JSON(http.StatusBadRequest, errors.BadRequest("Failed to read request body"))
+		// Decode the body as it streams in so oversized bodies fail at the limit
+		var jsonData interface{}
+		decoder := json.NewDecoder(c.Request.Body)
+		if err := decoder.Decode(&jsonData); err != nil {
+			if err == io.EOF {
+				// Skip empty bodies
+				c.Request.Body = http.NoBody
+				c.Next()
+				return
+			}
+			if IsBodyTooLarge(err) {
+				WriteError(c, log, err)
+				return
+			}
+			log.Error("Invalid JSON in request body", zap.Error(err))
+			c.JSON(http.StatusBadRequest, errors.BadRequest("Invalid JSON format"))
 			c.Abort()
 			return
 		}
-
-		// Restore the request body for downstream handlers
-		c.Request.Body = io.NopCloser(bytes.NewBuffer(body))
-
-		// Skip empty bodies
-		if len(body) == 0 {
-			c.Next()
-			return
-		}
-
-		// Parse JSON to detect the structure
-		var jsonData interface{}
-		if err := json.Unmarshal(body, &jsonData); err != nil {
-			log.Error("Invalid JSON in request body", zap.Error(err))
+		if _, err := decoder.Token(); err != io.EOF {
+			if IsBodyTooLarge(err) {
+				WriteError(c, log, err)
+				return
+			}
 			c.JSON(http.StatusBadRequest, errors.BadRequest("Invalid JSON format"))
 			c.Abort()
 			return
@@ -280,32 +282,6 @@ func convertValidationErrors(validationErrors validation.ValidationErrors) []err
 	return apiErrors
 }
 
-// RequestSizeLimit creates middleware to limit request body size
-func RequestSizeLimit(maxSize int64) gin.HandlerFunc {
-	log, _ := logger.New(logger.Config{Level: "debug"})
-	return func(c *gin.Context) {
-		log.Info("=== REQUEST SIZE LIMIT MIDDLEWARE ===", 
-			zap.String("method", c.Request.Method),
-			zap.String("path", c.Request.URL.Path),
-			zap.Int64("content_length", c.Request.ContentLength),
-			zap.Int64("max_size", maxSize))
-			
-		if c.Request.ContentLength > maxSize {
-			log.Error("Request body too large", 
-				zap.Int64("content_length", c.Request.ContentLength),
-				zap.Int64("max_size", maxSize))
-			c.JSON(http.StatusRequestEntityTooLarge, errors.BadRequest("Request body too large"))
-			c.Abort()
-			return
-		}
-
-		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize)
-		log.Info("Request size check passed, proceeding to next middleware")
-		c.Next()
-		log.Info("Request size middleware completed")
-	}
-}
-
 // SecurityHeaders adds security headers to responses
 func SecurityHeaders() gin.HandlerFunc {
 	return func(c *gin.Context) {
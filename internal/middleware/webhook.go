@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"bytes"
+	stderrors "errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/webhooks"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// WebhookBodyKey is the context key of a verified webhook body
+const WebhookBodyKey = "webhook_body"
+
+// VerifyWebhook admits deliveries the verifier accepts. Replayed
+// deliveries are acknowledged with 200 without reaching the handler; a
+// delivery the handler fails is released so the sender's retry is
+// processed. The verified body is stored under WebhookBodyKey and also
+// left readable on the request.
+func VerifyWebhook(verifier *webhooks.Verifier, log *logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := log.FromContext(c.Request.Context()).WithContext(zap.String("integration", verifier.Name()))
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			if !IsBodyTooLarge(err) {
+				err = errors.BadRequest("Failed to read webhook body")
+			}
+			WriteError(c, log, err)
+			return
+		}
+
+		delivery, err := verifier.Verify(c.Request.Header, body)
+		switch {
+		case stderrors.Is(err, webhooks.ErrNotConfigured):
+			log.Error("Webhook received but no secret is configured")
+			WriteError(c, log, errors.ServiceUnavailable("Webhook integration is not configured").WithErrorCode(errors.CodeWebhookNotConfigured))
+			return
+		case stderrors.Is(err, webhooks.ErrExpired):
+			log.Warn("Rejected webhook outside the timestamp tolerance")
+			WriteError(c, log, errors.Unauthorized("Webhook timestamp is outside the accepted window").WithErrorCode(errors.CodeWebhookExpired))
+			return
+		case err != nil:
+			log.Warn("Rejected webhook with an invalid signature", zap.Error(err))
+			WriteError(c, log, errors.Unauthorized("Webhook signature is missing or invalid").WithErrorCode(errors.CodeWebhookSignatureInvalid))
+			return
+		}
+
+		owner := uuid.New().String()
+		claimed, err := verifier.Claim(c.Request.Context(), delivery, owner)
+		if err != nil {
+			WriteError(c, log, errors.ServiceUnavailable("Failed to check webhook delivery"))
+			return
+		}
+		if !claimed {
+			log.Info("Ignored repeated webhook delivery", zap.String("delivery_id", delivery.ID))
+			c.AbortWithStatusJSON(http.StatusOK, gin.H{"message": "Delivery already received", "delivery_id": delivery.ID})
+			return
+		}
+
+		c.Set(WebhookBodyKey, body)
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+
+		if c.Writer.Status() >= http.StatusMultipleChoices {
+			if err := verifier.Release(c.Request.Context(), delivery, owner); err != nil {
+				log.Warn("Failed to release failed webhook delivery", zap.String("delivery_id", delivery.ID), zap.Error(err))
+			}
+		}
+	}
+}
+
+// WebhookBody returns the body verified by VerifyWebhook
+func WebhookBody(c *gin.Context) []byte {
+	body, _ := c.Get(WebhookBodyKey)
+	data, _ := body.([]byte)
+	return data
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/webhooks"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// memoryReplayStore is a webhooks.ReplayStore without expiry
+type memoryReplayStore struct {
+	mu    sync.Mutex
+	locks map[string]string
+}
+
+func (m *memoryReplayStore) AcquireLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, held := m.locks[key]; held {
+		return false, nil
+	}
+	m.locks[key] = owner
+	return true, nil
+}
+
+func (m *memoryReplayStore) ReleaseLock(ctx context.Context, key, owner string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.locks[key] == owner {
+		delete(m.locks, key)
+	}
+	return nil
+}
+
+func TestVerifyWebhook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log, err := logger.New(logger.Config{Level: "error", Format: "json"})
+	require.NoError(t, err)
+
+	verifier := webhooks.NewVerifier("test", "secret-1", webhooks.HMACScheme{}, 5*time.Minute, &memoryReplayStore{locks: map[string]string{}})
+	var calls int
+	var failNext bool
+	router := gin.New()
+	router.POST("/webhooks/test", VerifyWebhook(verifier, log), func(c *gin.Context) {
+		calls++
+		if failNext {
+			failNext = false
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		// Handlers can read the body either way
+		body, _ := io.ReadAll(c.Request.Body)
+		assert.Equal(t, string(WebhookBody(c)), string(body))
+		c.String(http.StatusOK, "%s", body)
+	})
+
+	send := func(id, body, secret string) *httptest.ResponseRecorder {
+		now := time.Now()
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/test", strings.NewReader(body))
+		req.Header.Set(webhooks.HeaderID, id)
+		req.Header.Set(webhooks.HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+		req.Header.Set(webhooks.HeaderSignature, webhooks.Sign(secret, id, now, []byte(body)))
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	recorder := send("delivery-1", `{"n":1}`, "secret-1")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, `{"n":1}`, recorder.Body.String())
+	assert.Equal(t, 1, calls)
+
+	// A replay is acknowledged without reaching the handler
+	recorder = send("delivery-1", `{"n":1}`, "secret-1")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Delivery already received")
+	assert.Equal(t, 1, calls)
+
+	// A failed delivery is released so the sender's retry is processed
+	failNext = true
+	assert.Equal(t, http.StatusInternalServerError, send("delivery-2", `{"n":2}`, "secret-1").Code)
+	assert.Equal(t, http.StatusOK, send("delivery-2", `{"n":2}`, "secret-1").Code)
+	assert.Equal(t, 3, calls)
+
+	recorder = send("delivery-3", `{"n":3}`, "secret-2")
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	var apiErr errors.APIError
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &apiErr))
+	assert.Equal(t, errors.CodeWebhookSignatureInvalid, apiErr.ErrorCode)
+	assert.Equal(t, 3, calls)
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// AccessLogEntry records one API request for traffic analysis and billing
+type AccessLogEntry struct {
+	Timestamp  time.Time `json:"timestamp"`
+	RequestID  string    `json:"request_id,omitempty"`
+	Method     string    `json:"method"`
+	Route      string    `json:"route"` // Route pattern, e.g. /api/v1/documents/:id
+	Path       string    `json:"path"`
+	Status     int       `json:"status"`
+	LatencyMs  float64   `json:"latency_ms"`
+	BytesIn    int64     `json:"bytes_in"`
+	BytesOut   int64     `json:"bytes_out"`
+	TenantID   string    `json:"tenant_id,omitempty"`
+	SpaceID    string    `json:"space_id,omitempty"`
+	UserID     string    `json:"user_id,omitempty"`
+	ClientIP   string    `json:"client_ip"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	SampleRate float64   `json:"sample_rate"` // Weight each entry by 1/sample_rate when counting
+}
@@ -403,6 +403,10 @@ type AgentExecuteRequest struct {
 	Template       *string                  `json:"template,omitempty"`
 	TemplateParams map[string]interface{}   `json:"template_params,omitempty"`
 	OutputFormat   *string                  `json:"output_format,omitempty" validate:"omitempty,oneof=text markdown html json"`
+
+	// DryRun resolves the agent and prepares the execution without running
+	// it, so nothing is sent to an LLM or billed
+	DryRun bool `json:"dry_run,omitempty"`
 }
 
 // ConversationMessage represents a message in a conversation
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Usage signals watched for anomalies, per tenant and interval
+const (
+	UsageSignalUploads   = "uploads"        // Files uploaded to storage
+	UsageSignalAgentCost = "agent_cost_usd" // Agent execution cost
+	UsageSignalErrorRate = "error_rate"     // Fraction of API requests answered with a 5xx
+)
+
+// UsageAnomaly is a tenant signal that deviated from its baseline in one
+// interval. The baseline is an exponentially weighted moving average of the
+// previous intervals; the z-score is the deviation in standard deviations.
+type UsageAnomaly struct {
+	TenantID    string    `json:"tenant_id"`
+	Signal      string    `json:"signal"`
+	Value       float64   `json:"value"`
+	Baseline    float64   `json:"baseline"`
+	StdDev      float64   `json:"std_dev"`
+	ZScore      float64   `json:"z_score"`
+	IntervalEnd time.Time `json:"interval_end"`
+}
+
+// UsageAnomalyReport lists the anomalies seen by one replica: those active
+// in the last interval and the most recent ones detected
+type UsageAnomalyReport struct {
+	InstanceID      string         `json:"instance_id"`
+	IntervalSeconds int            `json:"interval_seconds"`
+	TrackedTenants  int            `json:"tracked_tenants"`
+	Active          []UsageAnomaly `json:"active"`
+	Recent          []UsageAnomaly `json:"recent"`
+}
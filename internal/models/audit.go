@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// AuditEvent is an immutable audit log entry. Entries form a hash chain:
+// each hash covers the entry and the hash of the entry before it, so
+// altering or removing an entry breaks the chain from that point on.
+type AuditEvent struct {
+	ID             string                 `json:"id"`
+	Sequence       int64                  `json:"sequence"`
+	Action         string                 `json:"action"`
+	ResourceType   string                 `json:"resource_type"`
+	ResourceID     string                 `json:"resource_id,omitempty"`
+	ActorID        string                 `json:"actor_id,omitempty"`
+	SpaceID        string                 `json:"space_id,omitempty"`
+	TenantID       string                 `json:"tenant_id,omitempty"`
+	OrganizationID string                 `json:"organization_id,omitempty"`
+	Source         string                 `json:"source,omitempty"`
+	Details        map[string]interface{} `json:"details,omitempty"`
+	CreatedAt      time.Time              `json:"created_at"`
+	PrevHash       string                 `json:"prev_hash"`
+	Hash           string                 `json:"hash"`
+}
+
+// AuditFilter selects audit events. Empty fields match everything; the
+// time range includes From and excludes To.
+type AuditFilter struct {
+	ActorID        string
+	ResourceType   string
+	ResourceID     string
+	ResourceIDs    []string // Events of any of these resources
+	NotebookID     string   // Events of the notebook and of its documents
+	Action         string
+	SpaceIDs       []string
+	OrganizationID string
+	From           *time.Time
+	To             *time.Time
+	Limit          int
+	Offset         int
+}
+
+// AuditEventListResponse is a page of audit events, newest first
+type AuditEventListResponse struct {
+	Events  []*AuditEvent `json:"events"`
+	Limit   int           `json:"limit"`
+	Offset  int           `json:"offset"`
+	HasMore bool          `json:"has_more"`
+}
+
+// AuditVerifyResponse reports the result of checking the audit hash chain
+type AuditVerifyResponse struct {
+	Valid        bool   `json:"valid"`
+	Checked      int64  `json:"checked"`
+	LastSequence int64  `json:"last_sequence"`
+	BrokenAt     *int64 `json:"broken_at,omitempty"` // Sequence of the first entry that fails verification
+	Reason       string `json:"reason,omitempty"`
+}
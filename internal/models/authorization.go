@@ -0,0 +1,192 @@
+package models
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Actions a role grants, named "<resource type>:<verb>". A role may also
+// grant "<resource type>:*", every action on a resource type, or "*".
+const (
+	ActionDocumentRead   = "document:read"
+	ActionDocumentCreate = "document:create"
+	ActionDocumentUpdate = "document:update"
+	ActionDocumentDelete = "document:delete"
+
+	ActionNotebookRead   = "notebook:read"
+	ActionNotebookCreate = "notebook:create"
+	ActionNotebookUpdate = "notebook:update"
+	ActionNotebookDelete = "notebook:delete"
+	ActionNotebookShare  = "notebook:share"
+
+	ActionAgentRead    = "agent:read"
+	ActionAgentCreate  = "agent:create"
+	ActionAgentUpdate  = "agent:update"
+	ActionAgentDelete  = "agent:delete"
+	ActionAgentExecute = "agent:execute"
+
+	ActionSpaceCreate  = "space:create"
+	ActionSpaceUpdate  = "space:update"
+	ActionSpaceDelete  = "space:delete"
+	ActionSpaceMembers = "space:members"
+	ActionSpaceAudit   = "space:audit"
+
+	ActionOrganizationUpdate  = "organization:update"
+	ActionOrganizationDelete  = "organization:delete"
+	ActionOrganizationMembers = "organization:members"
+	ActionOrganizationRoles   = "organization:roles"
+	ActionOrganizationBilling = "organization:billing"
+	ActionOrganizationAudit   = "organization:audit"
+)
+
+// Actions lists every action a role can grant
+var Actions = []string{
+	ActionDocumentRead, ActionDocumentCreate, ActionDocumentUpdate, ActionDocumentDelete,
+	ActionNotebookRead, ActionNotebookCreate, ActionNotebookUpdate, ActionNotebookDelete, ActionNotebookShare,
+	ActionAgentRead, ActionAgentCreate, ActionAgentUpdate, ActionAgentDelete, ActionAgentExecute,
+	ActionSpaceCreate, ActionSpaceUpdate, ActionSpaceDelete, ActionSpaceMembers, ActionSpaceAudit,
+	ActionOrganizationUpdate, ActionOrganizationDelete, ActionOrganizationMembers,
+	ActionOrganizationRoles, ActionOrganizationBilling, ActionOrganizationAudit,
+}
+
+// Resource types authorized
+const (
+	ResourceDocument     = "document"
+	ResourceNotebook     = "notebook"
+	ResourceAgent        = "agent"
+	ResourceSpace        = "space"
+	ResourceOrganization = "organization"
+)
+
+// Roles held through a relationship with one resource rather than a
+// membership: the role of a notebook share and team administration. They
+// cannot be assigned to members.
+const (
+	RoleNotebookViewer    = "notebook:" + NotebookRoleViewer
+	RoleNotebookCommenter = "notebook:" + NotebookRoleCommenter
+	RoleNotebookEditor    = "notebook:" + NotebookRoleEditor
+	RoleTeamAdmin         = "team:admin"
+)
+
+var readActions = []string{ActionDocumentRead, ActionNotebookRead, ActionAgentRead}
+
+// BuiltInRoles are the actions granted by the roles every organization
+// has, and by the relationship roles
+var BuiltInRoles = map[string][]string{
+	"owner": {"*"},
+	"admin": {
+		"document:*", "notebook:*", "agent:*",
+		ActionSpaceCreate, ActionSpaceUpdate, ActionSpaceMembers, ActionSpaceAudit,
+		ActionOrganizationUpdate, ActionOrganizationMembers, ActionOrganizationRoles, ActionOrganizationAudit,
+	},
+	"member": {
+		ActionDocumentRead, ActionDocumentCreate, ActionDocumentUpdate,
+		ActionNotebookRead, ActionNotebookCreate, ActionNotebookUpdate, ActionNotebookShare,
+		ActionAgentRead, ActionAgentCreate, ActionAgentExecute,
+	},
+	"viewer":  readActions,
+	"billing": append([]string{ActionOrganizationBilling}, readActions...),
+
+	RoleNotebookViewer:    {ActionDocumentRead, ActionNotebookRead},
+	RoleNotebookCommenter: {ActionDocumentRead, ActionNotebookRead},
+	RoleNotebookEditor:    {ActionDocumentRead, ActionDocumentCreate, ActionDocumentUpdate, ActionNotebookRead, ActionNotebookUpdate},
+	RoleTeamAdmin:         {"agent:*"},
+}
+
+// MemberRoles are the built-in roles that can be assigned to organization
+// members, in decreasing order of privilege
+var MemberRoles = []string{"owner", "admin", "member", "viewer", "billing"}
+
+// IsBuiltInRole reports whether name is a built-in or relationship role,
+// which custom roles cannot be named after
+func IsBuiltInRole(name string) bool {
+	_, ok := BuiltInRoles[name]
+	return ok
+}
+
+// Grants reports whether permissions, as held by a role, grant action
+func Grants(permissions []string, action string) bool {
+	resourceType, _, _ := strings.Cut(action, ":")
+	for _, permission := range permissions {
+		if permission == action || permission == "*" || permission == resourceType+":*" {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAction reports whether permission names an action, every action on a
+// resource type, or "*"
+func IsAction(permission string) bool {
+	if permission == "*" {
+		return true
+	}
+	for _, action := range Actions {
+		resourceType, _, _ := strings.Cut(action, ":")
+		if permission == action || permission == resourceType+":*" {
+			return true
+		}
+	}
+	return false
+}
+
+// Subject is who an action is authorized for: a user and the roles they
+// hold in an organization, or on the resource itself
+type Subject struct {
+	UserID         string
+	OrganizationID string   // Organization whose custom roles apply; empty outside organizations
+	Roles          []string // Built-in, custom or relationship roles
+}
+
+// Resource is what an action is authorized on
+type Resource struct {
+	Type    string
+	ID      string
+	OwnerID string // Owners are granted every action on what they own
+}
+
+// Authorizer decides whether a subject may perform an action on a
+// resource; services.AuthorizationService implements it
+type Authorizer interface {
+	Authorize(ctx context.Context, subject Subject, action string, resource Resource) error
+}
+
+// Role is a built-in role or a custom role of an organization, with the
+// actions it grants
+type Role struct {
+	Name           string     `json:"name"`
+	Description    string     `json:"description,omitempty"`
+	Permissions    []string   `json:"permissions"`
+	BuiltIn        bool       `json:"built_in"`
+	OrganizationID string     `json:"organization_id,omitempty"`
+	CreatedBy      string     `json:"created_by,omitempty"`
+	CreatedAt      *time.Time `json:"created_at,omitempty"`
+	UpdatedAt      *time.Time `json:"updated_at,omitempty"`
+}
+
+// RoleCreateRequest defines a custom role of an organization
+type RoleCreateRequest struct {
+	Name        string   `json:"name" validate:"required,min=2,max=50"`
+	Description string   `json:"description,omitempty" validate:"omitempty,max=500"`
+	Permissions []string `json:"permissions" validate:"required,min=1"`
+}
+
+// RoleUpdateRequest changes a custom role; omitted fields keep their value
+type RoleUpdateRequest struct {
+	Description *string  `json:"description,omitempty" validate:"omitempty,max=500"`
+	Permissions []string `json:"permissions,omitempty" validate:"omitempty,min=1"`
+}
+
+// RoleList lists the roles of an organization and the actions roles can
+// grant
+type RoleList struct {
+	Roles   []*Role  `json:"roles"`
+	Actions []string `json:"actions"`
+}
+
+// Subject returns the user acting in a space context, holding their role
+// in the space
+func (sc *SpaceContext) Subject() Subject {
+	return Subject{UserID: sc.UserID, OrganizationID: sc.OrganizationID, Roles: []string{sc.UserRole}}
+}
@@ -0,0 +1,32 @@
+package models
+
+// Bulk upload file statuses
+const (
+	BulkUploadCreated = "created"
+	BulkUploadFailed  = "failed"
+)
+
+// BulkUploadFile is one file of a bulk upload
+type BulkUploadFile struct {
+	FileName string
+	MimeType string
+	Data     []byte
+}
+
+// BulkUploadResult is the outcome of one file of a bulk upload: the
+// created document, or the error the file failed with
+type BulkUploadResult struct {
+	FileName string            `json:"file_name"`
+	Status   string            `json:"status"`
+	Document *DocumentResponse `json:"document,omitempty"`
+	Error    *JobError         `json:"error,omitempty"`
+}
+
+// BulkUploadResponse reports every file of a bulk upload, in the order
+// the files were sent
+type BulkUploadResponse struct {
+	NotebookID string              `json:"notebook_id"`
+	Results    []*BulkUploadResult `json:"results"`
+	Succeeded  int                 `json:"succeeded"`
+	Failed     int                 `json:"failed"`
+}
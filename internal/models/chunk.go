@@ -150,11 +150,12 @@ type ChunkResponse struct {
 
 // ChunkListResponse represents a paginated list of chunks
 type ChunkListResponse struct {
-	Chunks  []*ChunkResponse `json:"chunks"`
-	Total   int              `json:"total"`
-	Limit   int              `json:"limit"`
-	Offset  int              `json:"offset"`
-	HasMore bool             `json:"has_more"`
+	Chunks   []*ChunkResponse `json:"chunks"`
+	Total    int              `json:"total"`
+	Limit    int              `json:"limit"`
+	Offset   int              `json:"offset"`
+	HasMore  bool             `json:"has_more"`
+	Redacted int              `json:"redacted,omitempty"` // Chunks of the page left out by redaction rules
 }
 
 // ChunkSearchRequest represents a request to search for chunks
@@ -171,13 +172,65 @@ type ChunkSearchRequest struct {
 	Offset          int    `json:"offset,omitempty" validate:"min=0"`
 }
 
+// Processing job statuses. A job moves from pending to processing and ends
+// completed, failed or cancelled.
+const (
+	ProcessingJobPending    = "pending"
+	ProcessingJobProcessing = "processing"
+	ProcessingJobCompleted  = "completed"
+	ProcessingJobFailed     = "failed"
+	ProcessingJobCancelled  = "cancelled"
+)
+
+// processingJobTransitions lists the statuses a job may move to from each
+// unfinished status
+var processingJobTransitions = map[string][]string{
+	ProcessingJobPending:    {ProcessingJobProcessing, ProcessingJobCompleted, ProcessingJobFailed, ProcessingJobCancelled},
+	ProcessingJobProcessing: {ProcessingJobCompleted, ProcessingJobFailed, ProcessingJobCancelled},
+}
+
+// ProcessingJobCanTransition reports whether a job may move from one status
+// to another. Staying in a status is allowed so progress can be updated.
+func ProcessingJobCanTransition(from, to string) bool {
+	if from == to {
+		return true
+	}
+	for _, next := range processingJobTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ProcessingJobSources lists the statuses a job may move to status from,
+// including status itself
+func ProcessingJobSources(status string) []string {
+	sources := []string{status}
+	for _, from := range []string{ProcessingJobPending, ProcessingJobProcessing} {
+		if from != status && ProcessingJobCanTransition(from, status) {
+			sources = append(sources, from)
+		}
+	}
+	return sources
+}
+
+// ProcessingJobFinished reports whether status ends a job
+func ProcessingJobFinished(status string) bool {
+	return status == ProcessingJobCompleted || status == ProcessingJobFailed || status == ProcessingJobCancelled
+}
+
 // ProcessingJob represents a document processing job
 type ProcessingJob struct {
 	ID          string                 `json:"id" validate:"required,uuid"`
 	DocumentID  string                 `json:"document_id" validate:"required,uuid"`
+	TenantID    string                 `json:"tenant_id,omitempty"`
 	Type        string                 `json:"type" validate:"required"`
 	Status      string                 `json:"status" validate:"required,oneof=pending processing completed failed cancelled"`
 	Priority    int                    `json:"priority" validate:"min=0,max=10"`
+	// PriorityTier is the processing priority tier the job was submitted
+	// with, which Priority is the queue level of
+	PriorityTier string `json:"priority_tier,omitempty"`
 	Progress    float64                `json:"progress" validate:"min=0,max=100"`
 	Config      map[string]interface{} `json:"config,omitempty"`
 	Result      map[string]interface{} `json:"result,omitempty"`
@@ -188,6 +241,14 @@ type ProcessingJob struct {
 	CompletedAt *time.Time             `json:"completed_at,omitempty"`
 }
 
+// ProcessingJobListResponse lists a tenant's processing jobs, newest first
+type ProcessingJobListResponse struct {
+	Jobs    []*ProcessingJob `json:"jobs"`
+	Limit   int              `json:"limit"`
+	Offset  int              `json:"offset"`
+	HasMore bool             `json:"has_more"`
+}
+
 // NewChunk creates a new chunk instance
 func NewChunk(req ChunkCreateRequest, tenantID string) *Chunk {
 	now := time.Now()
@@ -0,0 +1,121 @@
+package models
+
+import "time"
+
+// Classification condition fields, read from a document and its processing
+// result
+const (
+	// ClassificationFieldCategory matches the AudiModal content category and
+	// classification categories
+	ClassificationFieldCategory    = "category"
+	ClassificationFieldContentType = "content_type"
+	ClassificationFieldMimeType    = "mime_type"
+	ClassificationFieldExtension   = "extension"
+	ClassificationFieldLanguage    = "language"
+	ClassificationFieldPIIDetected = "pii_detected" // "true" or "false"
+	ClassificationFieldName        = "name"
+	ClassificationFieldTag         = "tag"
+)
+
+// Classification condition operators. A field may have several values,
+// such as a document's categories; a condition holds when any value
+// matches, or for not_equals when none does. Comparisons ignore case.
+const (
+	ClassificationOpEquals    = "equals"
+	ClassificationOpNotEquals = "not_equals"
+	ClassificationOpContains  = "contains"
+	ClassificationOpIn        = "in"
+)
+
+// ClassificationCondition is a test of one field of a processed document
+type ClassificationCondition struct {
+	Field    string   `json:"field" validate:"required,oneof=category content_type mime_type extension language pii_detected name tag"`
+	Operator string   `json:"operator" validate:"required,oneof=equals not_equals contains in"`
+	Value    string   `json:"value,omitempty" validate:"max=255"`
+	Values   []string `json:"values,omitempty" validate:"omitempty,max=50,dive,max=255"` // For in
+}
+
+// ClassificationActions are applied to a document its policy matches
+type ClassificationActions struct {
+	MoveToNotebookID string   `json:"move_to_notebook_id,omitempty" validate:"omitempty,uuid"`
+	AddTags          []string `json:"add_tags,omitempty" validate:"omitempty,max=20,dive,tag,min=1,max=50"`
+	// RestrictAccess limits the document to its owner and the space's
+	// owners and admins
+	RestrictAccess bool `json:"restrict_access,omitempty"`
+}
+
+// IsEmpty reports whether the actions do nothing
+func (a ClassificationActions) IsEmpty() bool {
+	return a.MoveToNotebookID == "" && len(a.AddTags) == 0 && !a.RestrictAccess
+}
+
+// ClassificationPolicy routes a space's documents once they are processed:
+// when every condition holds, its actions are applied. Policies run in
+// priority order, lowest first; the tags and restriction of every matching
+// policy apply, and the document moves to the notebook of the first that
+// names one.
+type ClassificationPolicy struct {
+	ID          string                    `json:"id"`
+	SpaceID     string                    `json:"space_id"`
+	TenantID    string                    `json:"-"`
+	Name        string                    `json:"name"`
+	Description string                    `json:"description,omitempty"`
+	Enabled     bool                      `json:"enabled"`
+	Priority    int                       `json:"priority"`
+	Conditions  []ClassificationCondition `json:"conditions"`
+	Actions     ClassificationActions     `json:"actions"`
+	CreatedBy   string                    `json:"created_by"`
+	CreatedAt   time.Time                 `json:"created_at"`
+	UpdatedAt   time.Time                 `json:"updated_at"`
+}
+
+// ClassificationPolicyCreateRequest creates a classification policy
+type ClassificationPolicyCreateRequest struct {
+	Name        string                    `json:"name" validate:"required,min=1,max=255"`
+	Description string                    `json:"description,omitempty" validate:"max=1000"`
+	Enabled     *bool                     `json:"enabled,omitempty"` // Default true
+	Priority    int                       `json:"priority" validate:"min=0,max=1000"`
+	Conditions  []ClassificationCondition `json:"conditions" validate:"required,min=1,max=20,dive"`
+	Actions     ClassificationActions     `json:"actions"`
+}
+
+// ClassificationPolicyUpdateRequest changes the given fields of a
+// classification policy; conditions and actions are replaced whole
+type ClassificationPolicyUpdateRequest struct {
+	Name        *string                   `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
+	Description *string                   `json:"description,omitempty" validate:"omitempty,max=1000"`
+	Enabled     *bool                     `json:"enabled,omitempty"`
+	Priority    *int                      `json:"priority,omitempty" validate:"omitempty,min=0,max=1000"`
+	Conditions  []ClassificationCondition `json:"conditions,omitempty" validate:"omitempty,min=1,max=20,dive"`
+	Actions     *ClassificationActions    `json:"actions,omitempty"`
+}
+
+// ClassificationPolicyListResponse lists a space's classification policies
+// in the order they run
+type ClassificationPolicyListResponse struct {
+	Policies []*ClassificationPolicy `json:"policies"`
+}
+
+// ClassificationEvaluateRequest evaluates policies against documents
+// without applying them. Without a policy the space's enabled policies are
+// evaluated; with one, only that unsaved policy is.
+type ClassificationEvaluateRequest struct {
+	DocumentIDs []string                           `json:"document_ids,omitempty" validate:"omitempty,max=100,dive,uuid"`
+	NotebookID  string                             `json:"notebook_id,omitempty" validate:"omitempty,uuid"` // Its processed documents, up to 100
+	Policy      *ClassificationPolicyCreateRequest `json:"policy,omitempty"`
+}
+
+// ClassificationOutcome is what the policies do to one document
+type ClassificationOutcome struct {
+	DocumentID   string                `json:"document_id"`
+	DocumentName string                `json:"document_name"`
+	NotebookID   string                `json:"notebook_id"`
+	PolicyIDs    []string              `json:"policy_ids"` // Of the matching policies, in the order they ran
+	Actions      ClassificationActions `json:"actions"`    // Combined, leaving out those with no effect
+}
+
+// ClassificationEvaluateResponse is the outcome of a dry run
+type ClassificationEvaluateResponse struct {
+	Outcomes []*ClassificationOutcome `json:"outcomes"`
+	Matched  int                      `json:"matched"` // Documents at least one policy matches
+}
@@ -44,6 +44,20 @@ type Document struct {
 	SearchText string   `json:"search_text,omitempty"`
 	Tags       []string `json:"tags,omitempty"`
 
+	// Restricted documents are visible to their owner and the space's
+	// owners and admins only; classification policies set it
+	Restricted bool `json:"restricted,omitempty"`
+
+	// TextEncrypted is set when the extracted text is stored encrypted
+	// because the document's space encrypts text at rest. ExtractedText is
+	// then decrypted, and left out of the search text.
+	TextEncrypted bool `json:"text_encrypted,omitempty"`
+
+	// ModerationStatus is set on documents of public notebooks. Pending and
+	// blocked documents are held, like restricted ones, from everyone but
+	// their owner and the space's owners and admins.
+	ModerationStatus ModerationStatus `json:"moderation_status,omitempty"`
+
 	// Processing information
 	ProcessingJobID      string     `json:"processing_job_id,omitempty"`
 	ProcessedAt          *time.Time `json:"processed_at,omitempty"`
@@ -51,6 +65,15 @@ type Document struct {
 	ChunkCount           int        `json:"chunk_count" validate:"min=0"`    // Number of chunks created
 	AverageChunkSize     int64      `json:"average_chunk_size,omitempty" validate:"min=0"` // Average chunk size in bytes
 	ChunkQualityScore    *float64   `json:"chunk_quality_score,omitempty" validate:"omitempty,min=0,max=1"` // Average quality across all chunks
+	// OCRSettings are the OCR settings the document's text was extracted
+	// with, recorded when it was last submitted for processing
+	OCRSettings *OCRSettings `json:"ocr_settings,omitempty"`
+	// ProcessingProvider processed the document's current file and keeps
+	// its processed copy
+	ProcessingProvider string `json:"processing_provider,omitempty"`
+	// ProcessingPriority is the priority tier the document was last
+	// submitted for processing with, kept for its retries
+	ProcessingPriority string `json:"processing_priority,omitempty"`
 
 	// Timestamps
 	CreatedAt time.Time `json:"created_at"`
@@ -64,6 +87,9 @@ type DocumentCreateRequest struct {
 	NotebookID  string                 `json:"notebook_id" validate:"required,uuid"`
 	Tags        []string               `json:"tags,omitempty" validate:"dive,tag,min=1,max=50"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	// Priority is the tier the document is processed with, up to the cap
+	// of the space's billing plan; the deployment's default when empty
+	Priority string `json:"priority,omitempty" validate:"omitempty,oneof=low normal high realtime"`
 }
 
 // DocumentUpdateRequest represents a request to update a document
@@ -93,11 +119,16 @@ type DocumentResponse struct {
 	NotebookID           string                 `json:"notebook_id"`
 	OwnerID              string                 `json:"owner_id"`
 	Tags                 []string               `json:"tags,omitempty"`
+	Restricted           bool                   `json:"restricted,omitempty"`
+	ModerationStatus     ModerationStatus       `json:"moderation_status,omitempty"`
 	ProcessedAt          *time.Time             `json:"processed_at,omitempty"`
 	ChunkingStrategy     string                 `json:"chunking_strategy,omitempty"`
 	ChunkCount           int                    `json:"chunk_count"`
 	AverageChunkSize     int64                  `json:"average_chunk_size,omitempty"`
 	ChunkQualityScore    *float64               `json:"chunk_quality_score,omitempty"`
+	OCRSettings          *OCRSettings           `json:"ocr_settings,omitempty"`
+	ProcessingProvider   string                 `json:"processing_provider,omitempty"`
+	ProcessingPriority   string                 `json:"processing_priority,omitempty"`
 	CreatedAt            time.Time              `json:"created_at"`
 	UpdatedAt            time.Time              `json:"updated_at"`
 
@@ -124,6 +155,7 @@ type DocumentSearchRequest struct {
 	Status     string   `json:"status,omitempty" validate:"omitempty,oneof=uploading processing processed failed archived deleted"`
 	Tags       []string `json:"tags,omitempty" validate:"dive,min=1,max=50"`
 	MimeType   string   `json:"mime_type,omitempty"`
+	Filter     string   `json:"filter,omitempty" validate:"omitempty,max=500"` // Filter expression, e.g. tag:contract AND created:>2024-01-01
 	Limit      int      `json:"limit,omitempty" validate:"omitempty,min=1,max=100"`
 	Offset     int      `json:"offset,omitempty" validate:"omitempty,min=0"`
 }
@@ -189,25 +221,30 @@ type FileInfo struct {
 // ToResponse converts a Document to DocumentResponse
 func (d *Document) ToResponse() *DocumentResponse {
 	return &DocumentResponse{
-		ID:               d.ID,
-		Name:             d.Name,
-		Description:      d.Description,
-		Type:             d.Type,
-		Status:           d.Status,
-		OriginalName:     d.OriginalName,
-		MimeType:         d.MimeType,
-		SizeBytes:        d.SizeBytes,
-		ExtractedText:    d.ExtractedText,
-		ProcessingResult: d.ProcessingResult,
-		ProcessingTime:   d.ProcessingTime,
-		ConfidenceScore:  d.ConfidenceScore,
-		Metadata:         d.Metadata,
-		NotebookID:       d.NotebookID,
-		OwnerID:          d.OwnerID,
-		Tags:             d.Tags,
-		ProcessedAt:      d.ProcessedAt,
-		CreatedAt:        d.CreatedAt,
-		UpdatedAt:        d.UpdatedAt,
+		ID:                 d.ID,
+		Name:               d.Name,
+		Description:        d.Description,
+		Type:               d.Type,
+		Status:             d.Status,
+		OriginalName:       d.OriginalName,
+		MimeType:           d.MimeType,
+		SizeBytes:          d.SizeBytes,
+		ExtractedText:      d.ExtractedText,
+		ProcessingResult:   d.ProcessingResult,
+		ProcessingTime:     d.ProcessingTime,
+		ConfidenceScore:    d.ConfidenceScore,
+		Metadata:           d.Metadata,
+		NotebookID:         d.NotebookID,
+		OwnerID:            d.OwnerID,
+		Tags:               d.Tags,
+		Restricted:         d.Restricted,
+		ModerationStatus:   d.ModerationStatus,
+		OCRSettings:        d.OCRSettings,
+		ProcessingProvider: d.ProcessingProvider,
+		ProcessingPriority: d.ProcessingPriority,
+		ProcessedAt:        d.ProcessedAt,
+		CreatedAt:          d.CreatedAt,
+		UpdatedAt:          d.UpdatedAt,
 	}
 }
 
@@ -245,7 +282,7 @@ func (d *Document) UpdateProcessingStatus(status string, result map[string]inter
 	if status == "processed" && result != nil {
 		if extractedText, ok := result["extracted_text"].(string); ok {
 			d.ExtractedText = extractedText
-			d.SearchText = buildSearchText(d.Name, d.Description, d.Tags) + " " + extractedText
+			d.RefreshSearchText()
 		}
 		now := time.Now()
 		d.ProcessedAt = &now
@@ -286,10 +323,7 @@ func (d *Document) AddTag(tag string) {
 	}
 
 	d.Tags = append(d.Tags, tag)
-	d.SearchText = buildSearchText(d.Name, d.Description, d.Tags)
-	if d.ExtractedText != "" {
-		d.SearchText += " " + d.ExtractedText
-	}
+	d.RefreshSearchText()
 	d.UpdatedAt = time.Now()
 }
 
@@ -302,10 +336,7 @@ func (d *Document) RemoveTag(tag string) {
 		}
 	}
 
-	d.SearchText = buildSearchText(d.Name, d.Description, d.Tags)
-	if d.ExtractedText != "" {
-		d.SearchText += " " + d.ExtractedText
-	}
+	d.RefreshSearchText()
 	d.UpdatedAt = time.Now()
 }
 
@@ -365,6 +396,15 @@ func determineDocumentType(mimeType string) string {
 	}
 }
 
+// RefreshSearchText rebuilds the search text from the name, description,
+// tags and extracted text, leaving the text out when it is encrypted
+func (d *Document) RefreshSearchText() {
+	d.SearchText = buildSearchText(d.Name, d.Description, d.Tags)
+	if d.ExtractedText != "" && !d.TextEncrypted {
+		d.SearchText += " " + d.ExtractedText
+	}
+}
+
 // buildSearchText creates a searchable text field
 func buildSearchText(name, description string, tags []string) string {
 	searchText := name
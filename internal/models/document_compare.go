@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+// Diff line operations
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+// Diff context lines around each change
+const (
+	DefaultDiffContextLines = 3
+	MaxDiffContextLines     = 20
+)
+
+// DocumentCompareSide names one text of a comparison: the extracted text
+// of a document, or of one of its versions
+type DocumentCompareSide struct {
+	DocumentID string `json:"document_id" validate:"required,uuid"`
+	// Version is the version whose text is compared; 0 is the current one
+	Version int `json:"version,omitempty" validate:"omitempty,min=1"`
+}
+
+// DocumentCompareRequest asks for the difference between two texts. Base
+// and Target may be two documents or two versions of the same document.
+type DocumentCompareRequest struct {
+	Base   DocumentCompareSide `json:"base"`
+	Target DocumentCompareSide `json:"target"`
+	// ContextLines are the unchanged lines shown around each change,
+	// DefaultDiffContextLines when unset
+	ContextLines *int `json:"context_lines,omitempty" validate:"omitempty,min=0,max=20"`
+}
+
+// DocumentCompareText describes one text of a comparison. Lines and
+// Characters count the text compared.
+type DocumentCompareText struct {
+	DocumentID string `json:"document_id"`
+	Name       string `json:"name"`
+	Version    int    `json:"version"`
+	Lines      int    `json:"lines"`
+	Characters int    `json:"characters"`
+	// Redacted is set when the notebook's redaction rules apply to the
+	// user; the text compared is then that of the chunks the rules keep
+	Redacted bool `json:"redacted,omitempty"`
+}
+
+// DiffLine is a line of a diff hunk
+type DiffLine struct {
+	Op   string `json:"op"` // equal, insert or delete
+	Text string `json:"text"`
+}
+
+// DiffHunk is a run of changes with the unchanged lines around them. Starts
+// are 1-based line numbers; a side with no lines starts after the line it
+// follows, as in a unified diff.
+type DiffHunk struct {
+	BaseStart   int        `json:"base_start"`
+	BaseLines   int        `json:"base_lines"`
+	TargetStart int        `json:"target_start"`
+	TargetLines int        `json:"target_lines"`
+	Lines       []DiffLine `json:"lines"`
+}
+
+// DocumentCompareResponse is the difference between two texts. Similarity
+// is from 0 to 1, the share of their characters the texts have in common.
+type DocumentCompareResponse struct {
+	Base       *DocumentCompareText `json:"base"`
+	Target     *DocumentCompareText `json:"target"`
+	Similarity float64              `json:"similarity"`
+	Identical  bool                 `json:"identical"`
+	Insertions int                  `json:"insertions"` // Lines only the target has
+	Deletions  int                  `json:"deletions"`  // Lines only the base has
+	Hunks      []*DiffHunk          `json:"hunks"`
+	// ComputedAt is when the diff was computed; diffs are cached
+	ComputedAt time.Time `json:"computed_at"`
+}
@@ -0,0 +1,19 @@
+package models
+
+// DocumentContentPage is one page of the extracted text of a document.
+// Offsets and lengths count characters (Unicode code points), so a page
+// never splits one.
+type DocumentContentPage struct {
+	DocumentID  string `json:"document_id"`
+	Text        string `json:"text"`
+	Offset      int    `json:"offset"`
+	Length      int    `json:"length"`
+	TotalLength int    `json:"total_length"`
+	HasMore     bool   `json:"has_more"`
+	// NextOffset is the offset of the next page, when there is one
+	NextOffset *int `json:"next_offset,omitempty"`
+	// Redacted is set when the notebook's redaction rules apply to the
+	// user; the text is then that of the chunks the rules keep
+	Redacted       bool `json:"redacted,omitempty"`
+	RedactedChunks int  `json:"redacted_chunks,omitempty"`
+}
@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// Document deletion statuses
+const (
+	DocumentDeletionPending   = "pending"   // Targets are left to delete
+	DocumentDeletionCompleted = "completed" // Every target is deleted
+	DocumentDeletionFailed    = "failed"    // A target gave up after its attempts
+)
+
+// Document deletion reasons: why a document's copies are deleted
+const (
+	// DocumentDeletionReasonDeleted deletes every copy of a purged document
+	DocumentDeletionReasonDeleted = "deleted"
+	// DocumentDeletionReasonReprocessed deletes what an earlier processing
+	// of a document left behind when it is reprocessed or given a new file
+	DocumentDeletionReasonReprocessed = "reprocessed"
+)
+
+// Document deletion targets: the services holding copies of a document
+const (
+	DeletionTargetAudiModal = "audimodal" // The processed file and its chunks
+	DeletionTargetVectors   = "vectors"   // Chunk embeddings in the space's vector namespace
+	DeletionTargetStorage   = "storage"   // The files of every version in object storage
+)
+
+// Deletion target statuses
+const (
+	DeletionTargetPending = "pending"
+	DeletionTargetDeleted = "deleted"
+	DeletionTargetFailed  = "failed"
+	// DeletionTargetSkipped copies were still in use, as the processed
+	// file of a reprocessed document that kept it, and were left alone
+	DeletionTargetSkipped = "skipped"
+)
+
+// DocumentDeletion records the removal of a document's copies from the
+// services other than Neo4j, and the outcome for each: all of them when
+// the document is deleted, or those of its previous processing when it is
+// reprocessed
+type DocumentDeletion struct {
+	ID            string            `json:"id"`
+	DocumentID    string            `json:"document_id"`
+	TenantID      string            `json:"tenant_id"`
+	Reason        string            `json:"reason"`
+	FileID        string            `json:"file_id,omitempty"` // Processed file deleted from the processing provider
+	Status        string            `json:"status"`
+	Targets       []*DeletionTarget `json:"targets"`
+	NextAttemptAt *time.Time        `json:"next_attempt_at,omitempty"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+	CompletedAt   *time.Time        `json:"completed_at,omitempty"`
+}
+
+// DeletionTarget is the outcome of deleting a document's copies from one
+// service
+type DeletionTarget struct {
+	Name      string     `json:"name"`
+	Status    string     `json:"status"`
+	Attempts  int        `json:"attempts"` // Failed attempts
+	Error     string     `json:"error,omitempty"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// DocumentDeletionListResponse lists document deletions, most recently
+// updated first
+type DocumentDeletionListResponse struct {
+	Deletions []*DocumentDeletion `json:"deletions"`
+	Limit     int                 `json:"limit"`
+	Offset    int                 `json:"offset"`
+	HasMore   bool                `json:"has_more"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// DocumentVersion is one of the files a document has had. Uploading a new
+// file for a document adds a version and makes it current; the files of
+// earlier versions are kept, so any of them can be downloaded or restored.
+type DocumentVersion struct {
+	DocumentID    string    `json:"document_id"`
+	Version       int       `json:"version"` // From 1, the document's first file
+	OriginalName  string    `json:"original_name"`
+	MimeType      string    `json:"mime_type"`
+	SizeBytes     int64     `json:"size_bytes"`
+	Checksum      string    `json:"checksum,omitempty"`
+	StoragePath   string    `json:"-"`
+	StorageBucket string    `json:"-"`
+	Current       bool      `json:"current"`
+	CreatedBy     string    `json:"created_by"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// DocumentVersionListResponse lists a document's versions, newest first
+type DocumentVersionListResponse struct {
+	Versions       []*DocumentVersion `json:"versions"`
+	CurrentVersion int                `json:"current_version"`
+}
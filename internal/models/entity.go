@@ -0,0 +1,99 @@
+package models
+
+import "time"
+
+// EntityType is the kind of an entity detected in documents
+type EntityType string
+
+const (
+	EntityTypePerson       EntityType = "person"
+	EntityTypeOrganization EntityType = "organization"
+	EntityTypeTopic        EntityType = "topic"
+	EntityTypeLocation     EntityType = "location"
+	EntityTypeDate         EntityType = "date"
+)
+
+// EntityTypes are the entity types, in the order they are listed
+var EntityTypes = []EntityType{EntityTypePerson, EntityTypeOrganization, EntityTypeTopic, EntityTypeLocation, EntityTypeDate}
+
+// entityLabels are the Neo4j labels entities carry besides Entity
+var entityLabels = map[EntityType]string{
+	EntityTypePerson:       "Person",
+	EntityTypeOrganization: "Organization",
+	EntityTypeTopic:        "Topic",
+	EntityTypeLocation:     "Location",
+	EntityTypeDate:         "Date",
+}
+
+// Label returns the Neo4j label of an entity type, or "" when the type is
+// not known
+func (t EntityType) Label() string {
+	return entityLabels[t]
+}
+
+// Entity is a person, organization, topic, location or date mentioned by a
+// tenant's documents; dates are named in ISO form. Entities detected under
+// different spellings are merged into one; Aliases keeps the other
+// spellings.
+type Entity struct {
+	ID            string     `json:"id"`
+	TenantID      string     `json:"tenant_id"`
+	Type          EntityType `json:"type"`
+	Name          string     `json:"name"`
+	Aliases       []string   `json:"aliases,omitempty"`
+	DocumentCount int        `json:"document_count"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// ExtractedEntity is an entity detected in one document's text
+type ExtractedEntity struct {
+	Type     EntityType
+	Name     string
+	Mentions int
+}
+
+// EntityListResponse is one page of entities, most mentioned first. For
+// the entities of a notebook, NotebookID is set and DocumentCount counts
+// the notebook's documents.
+type EntityListResponse struct {
+	NotebookID string    `json:"notebook_id,omitempty"`
+	Entities   []*Entity `json:"entities"`
+	Limit      int       `json:"limit"`
+	Offset     int       `json:"offset"`
+	HasMore    bool      `json:"has_more"`
+}
+
+// EntityDocument is a document mentioning an entity
+type EntityDocument struct {
+	Document *SemanticSearchDocument `json:"document"`
+	Mentions int                     `json:"mentions"`
+}
+
+// EntityDocumentsResponse is one page of the documents mentioning an
+// entity, most mentions first
+type EntityDocumentsResponse struct {
+	EntityID  string            `json:"entity_id"`
+	Documents []*EntityDocument `json:"documents"`
+	Limit     int               `json:"limit"`
+	Offset    int               `json:"offset"`
+	HasMore   bool              `json:"has_more"`
+}
+
+// RelatedEntity is an entity mentioned together with another
+type RelatedEntity struct {
+	Entity          *Entity `json:"entity"`
+	SharedDocuments int     `json:"shared_documents"`
+}
+
+// RelatedEntitiesResponse lists the entities most often mentioned by the
+// same documents as an entity
+type RelatedEntitiesResponse struct {
+	EntityID string           `json:"entity_id"`
+	Related  []*RelatedEntity `json:"related"`
+}
+
+// EntityMergeRequest merges an entity into the target entity
+type EntityMergeRequest struct {
+	TargetID string `json:"target_id" validate:"required"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// FeedType identifies what a feed token grants access to
+type FeedType string
+
+// Feed types
+const (
+	// FeedTypeNotebookActivity is the RSS/Atom feed of a notebook's activity
+	FeedTypeNotebookActivity FeedType = "notebook_activity"
+	// FeedTypeScheduledJobs is the iCal feed of the scheduler's jobs, which
+	// only admins may create
+	FeedTypeScheduledJobs FeedType = "scheduled_jobs"
+)
+
+// FeedToken lets feed readers, which cannot sign in, read one feed on
+// behalf of the user who created the token. The token itself is only
+// returned when it is created; Aether stores its hash.
+type FeedToken struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	Feed       FeedType   `json:"feed"`
+	ResourceID string     `json:"resource_id,omitempty"` // The notebook of a notebook activity feed
+	SpaceID    string     `json:"space_id,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// FeedTokenCreateResponse returns a new feed token and the URLs to
+// subscribe to, which embed it
+type FeedTokenCreateResponse struct {
+	FeedToken
+	Token string            `json:"token"`
+	URLs  map[string]string `json:"urls"` // By format: rss, atom or ical
+}
+
+// FeedTokenListResponse lists the caller's feed tokens, newest first
+type FeedTokenListResponse struct {
+	Tokens []*FeedToken `json:"tokens"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import "encoding/json"
+
+// FileValidationSettingsKey is the key of a space's upload restrictions in
+// its settings
+const FileValidationSettingsKey = "file_validation"
+
+// FileValidationSettings restrict the files uploaded to a space, on top of
+// the deployment's own restrictions
+type FileValidationSettings struct {
+	// AllowedTypes are the MIME types the space accepts, such as
+	// "application/pdf", or all subtypes of a type, as "image/*". Empty
+	// accepts every type the deployment does.
+	AllowedTypes []string `json:"allowed_types,omitempty" validate:"max=100,dive,min=3,max=255"`
+	// MaxSizeBytes is the largest file the space accepts; 0 leaves the
+	// deployment's upload limits
+	MaxSizeBytes int64 `json:"max_size_bytes,omitempty" validate:"min=0"`
+}
+
+// SpaceFileValidationSettings returns the upload restrictions in a space's
+// settings, empty when it has none
+func SpaceFileValidationSettings(settings map[string]interface{}) *FileValidationSettings {
+	validation := &FileValidationSettings{}
+	value, ok := settings[FileValidationSettingsKey]
+	if !ok || value == nil {
+		return validation
+	}
+	// The settings are decoded from JSON, so the restrictions are a map
+	data, err := json.Marshal(value)
+	if err != nil {
+		return validation
+	}
+	if err := json.Unmarshal(data, validation); err != nil {
+		return &FileValidationSettings{}
+	}
+	return validation
+}
@@ -0,0 +1,103 @@
+package models
+
+import "time"
+
+// DocumentGraphNode is a node reachable from a document. Distance is the
+// number of relationships on the shortest path to it.
+type DocumentGraphNode struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Name     string `json:"name,omitempty"`
+	Distance int    `json:"distance"`
+}
+
+// DocumentGraphResponse is one page of the nodes around a document, nearest
+// first
+type DocumentGraphResponse struct {
+	DocumentID string               `json:"document_id"`
+	Depth      int                  `json:"depth"`
+	Nodes      []*DocumentGraphNode `json:"nodes"`
+	Limit      int                  `json:"limit"`
+	Offset     int                  `json:"offset"`
+	HasMore    bool                 `json:"has_more"`
+}
+
+// SimilarDocument is a document related to another and the signals that
+// relate them. Score weighs them into a value between 0 and 1.
+type SimilarDocument struct {
+	Document         *SemanticSearchDocument `json:"document"`
+	Score            float64                 `json:"score"`
+	VectorScore      float64                 `json:"vector_score"`
+	SharedTags       []string                `json:"shared_tags,omitempty"`
+	SameNotebook     bool                    `json:"same_notebook"`
+	SharedReferences int                     `json:"shared_references"`
+}
+
+// SimilarDocumentsResponse lists the documents most related to a document,
+// best first. GeneratedAt is when the list was computed; lists are cached.
+type SimilarDocumentsResponse struct {
+	DocumentID  string             `json:"document_id"`
+	Documents   []*SimilarDocument `json:"documents"`
+	GeneratedAt time.Time          `json:"generated_at"`
+}
+
+// RelatedDocument is a document reached from another through SIMILAR_TO
+// relationships. Distance is the number of relationships followed, and
+// Score the product of their similarity scores along the best path.
+type RelatedDocument struct {
+	Document *SemanticSearchDocument `json:"document"`
+	Score    float64                 `json:"score"`
+	Distance int                     `json:"distance"`
+}
+
+// RelatedDocumentsResponse lists the documents related to a document, best
+// first. LinkedAt is when the document's SIMILAR_TO relationships were last
+// computed.
+type RelatedDocumentsResponse struct {
+	DocumentID string             `json:"document_id"`
+	Depth      int                `json:"depth"`
+	Documents  []*RelatedDocument `json:"documents"`
+	LinkedAt   time.Time          `json:"linked_at"`
+}
+
+// GraphRootType is the kind of node a knowledge graph view is centred on
+type GraphRootType string
+
+const (
+	GraphRootDocument GraphRootType = "document"
+	GraphRootNotebook GraphRootType = "notebook"
+	GraphRootEntity   GraphRootType = "entity"
+)
+
+// GraphNode is a node of a knowledge graph view. Type is document,
+// notebook or the entity type. ExpandToken requests the view centred on
+// the node.
+type GraphNode struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	Label       string `json:"label"`
+	Distance    int    `json:"distance"`
+	ExpandToken string `json:"expand_token,omitempty"`
+}
+
+// GraphEdge is a relationship between two nodes of a knowledge graph view.
+// Weight is how often a document mentions an entity, and Score how similar
+// the documents of a SIMILAR_TO edge are.
+type GraphEdge struct {
+	Source string  `json:"source"`
+	Target string  `json:"target"`
+	Type   string  `json:"type"`
+	Weight int     `json:"weight,omitempty"`
+	Score  float64 `json:"score,omitempty"`
+}
+
+// GraphResponse is one page of the subgraph around a root node, nearest
+// nodes first, with the edges between the root and the page's nodes.
+// NextPageToken requests the next page.
+type GraphResponse struct {
+	Root          *GraphNode   `json:"root"`
+	Depth         int          `json:"depth"`
+	Nodes         []*GraphNode `json:"nodes"`
+	Edges         []*GraphEdge `json:"edges"`
+	NextPageToken string       `json:"next_page_token,omitempty"`
+}
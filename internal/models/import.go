@@ -0,0 +1,42 @@
+package models
+
+// Formats of the export archives an import accepts
+const (
+	ImportFormatNotion      = "notion"       // Notion "Markdown & CSV" workspace or page export
+	ImportFormatGoogleDrive = "google_drive" // Google Takeout archive of Drive, or a zipped Drive folder
+	ImportFormatSharePoint  = "sharepoint"   // Zipped document library with its "Export to CSV" listing at the root
+)
+
+// ImportFormats lists the accepted import formats
+var ImportFormats = []string{ImportFormatNotion, ImportFormatGoogleDrive, ImportFormatSharePoint}
+
+// Import item statuses
+const (
+	ImportItemCreated = "created"
+	ImportItemFailed  = "failed"
+	ImportItemSkipped = "skipped"
+)
+
+// ImportItemResult is the outcome of one entry of an import archive: the
+// document it became, why it was skipped, or the error it failed with
+type ImportItemResult struct {
+	Path       string    `json:"path"` // Path of the entry in the archive
+	Status     string    `json:"status"`
+	NotebookID string    `json:"notebook_id,omitempty"`
+	DocumentID string    `json:"document_id,omitempty"`
+	Reason     string    `json:"reason,omitempty"` // Why the entry was skipped
+	Error      *JobError `json:"error,omitempty"`
+}
+
+// ImportResult is the result of an import job. The archive's folders
+// become notebooks under NotebookID, created for the import; its files
+// become documents carrying their source path and metadata.
+type ImportResult struct {
+	Format     string              `json:"format"`
+	NotebookID string              `json:"notebook_id"`
+	Notebooks  int                 `json:"notebooks"` // Created, including NotebookID
+	Documents  int                 `json:"documents"`
+	Skipped    int                 `json:"skipped"`
+	Failed     int                 `json:"failed"`
+	Items      []*ImportItemResult `json:"items"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// InboundEmailRequest creates the inbound email address of a notebook
+type InboundEmailRequest struct {
+	// Senders allowed to mail the notebook, as addresses or "@domain"; when
+	// empty anyone may
+	AllowedSenders []string `json:"allowed_senders,omitempty" validate:"max=50,dive,min=3,max=254"`
+}
+
+// InboundEmailAddress is the address mail is sent to for adding its body
+// and attachments to a notebook as documents
+type InboundEmailAddress struct {
+	NotebookID     string    `json:"notebook_id"`
+	Address        string    `json:"address"`
+	AllowedSenders []string  `json:"allowed_senders,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Outcomes of a received email
+const (
+	InboundEmailAccepted = "accepted"
+	InboundEmailRejected = "rejected"
+)
+
+// Reasons a received email, or a part of it, is not added
+const (
+	InboundEmailUnknownRecipient = "unknown_recipient"
+	InboundEmailSpam             = "spam"
+	InboundEmailVirus            = "virus"
+	InboundEmailTooLarge         = "too_large"
+	InboundEmailTooSmall         = "too_small"
+	InboundEmailSenderNotAllowed = "sender_not_allowed"
+	InboundEmailInvalid          = "invalid_message"
+	InboundEmailEmpty            = "nothing_to_add"
+	InboundEmailUploadFailed     = "upload_failed"
+)
+
+// InboundEmailResult is the outcome of a received email
+type InboundEmailResult struct {
+	Status     string                  `json:"status"`
+	Reason     string                  `json:"reason,omitempty"` // Why the email was rejected
+	MessageID  string                  `json:"message_id,omitempty"`
+	Deliveries []*InboundEmailDelivery `json:"deliveries,omitempty"`
+}
+
+// InboundEmailDelivery is what a received email added to one of the
+// notebooks it was addressed to
+type InboundEmailDelivery struct {
+	NotebookID  string              `json:"notebook_id"`
+	Reason      string              `json:"reason,omitempty"` // Why nothing was added to the notebook
+	DocumentIDs []string            `json:"document_ids"`
+	Skipped     []*InboundEmailSkip `json:"skipped,omitempty"`
+}
+
+// InboundEmailSkip is a part of an email that was not added
+type InboundEmailSkip struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
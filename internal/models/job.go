@@ -0,0 +1,123 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// JobType identifies the work an async job does
+type JobType string
+
+// Async job types
+const (
+	JobTypeBatch              JobType = "batch"
+	JobTypeNotebookExport     JobType = "notebook_export"
+	JobTypeOrganizationDelete JobType = "organization_delete"
+	JobTypeTenantExport       JobType = "tenant_export"
+	JobTypeImport             JobType = "import"
+	JobTypeLegalHoldExport    JobType = "legal_hold_export"
+
+	// JobTypeDocumentProcessing reports AudiModal processing jobs, which are
+	// tracked by AudiModal rather than stored as jobs
+	JobTypeDocumentProcessing JobType = "document_processing"
+)
+
+// JobStatus is the state of an async job
+type JobStatus string
+
+// Async job statuses. Jobs wait queued while the replica runs as many jobs
+// as it allows and end succeeded or failed.
+const (
+	JobStatusQueued    JobStatus = "queued"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusSucceeded JobStatus = "succeeded"
+	JobStatusFailed    JobStatus = "failed"
+)
+
+// JobProgress counts the items a job has worked through. Total is 0 until
+// the job knows how many items it has.
+type JobProgress struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+}
+
+// JobError describes why a job failed, like an error response
+type JobError struct {
+	Code      string `json:"code"`
+	ErrorCode string `json:"error_code,omitempty"`
+	Message   string `json:"message"`
+}
+
+// Job is a long-running operation. Endpoints that start one respond 202
+// with the job and a Location header; clients poll GET /api/v1/jobs/{id}
+// until it has finished. Finished jobs are kept for the configured
+// retention.
+type Job struct {
+	ID          string                 `json:"id"`
+	Type        JobType                `json:"type"`
+	Status      JobStatus              `json:"status"`
+	TenantID    string                 `json:"tenant_id,omitempty"`
+	CreatedBy   string                 `json:"created_by"`
+	Progress    JobProgress            `json:"progress"`
+	Result      map[string]interface{} `json:"result,omitempty"` // Set when the job succeeded; its fields depend on the type
+	Error       *JobError              `json:"error,omitempty"`  // Set when the job failed
+	CreatedAt   time.Time              `json:"created_at"`
+	StartedAt   *time.Time             `json:"started_at,omitempty"`
+	CompletedAt *time.Time             `json:"completed_at,omitempty"`
+}
+
+// Finished reports whether the job has succeeded or failed
+func (j *Job) Finished() bool {
+	return j.Status == JobStatusSucceeded || j.Status == JobStatusFailed
+}
+
+// JobListResponse lists the caller's jobs, newest first
+type JobListResponse struct {
+	Jobs    []*Job `json:"jobs"`
+	Limit   int    `json:"limit"`
+	Offset  int    `json:"offset"`
+	HasMore bool   `json:"has_more"`
+}
+
+// BatchOperation is one API request of a batch. Path is relative to the
+// server, e.g. /api/v1/notebooks/{id}, and may include a query string.
+type BatchOperation struct {
+	ID     string          `json:"id,omitempty" validate:"omitempty,max=100"` // Echoed in the result to match it up
+	Method string          `json:"method" validate:"required,oneof=GET POST PUT PATCH DELETE"`
+	Path   string          `json:"path" validate:"required,startswith=/api/,max=2048"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// BatchRequest runs several API requests in one call. Operations run in
+// order with the caller's credentials and space headers; a failed
+// operation does not stop the ones after it.
+type BatchRequest struct {
+	Operations []BatchOperation `json:"operations" validate:"required,min=1,dive"`
+	Async      bool             `json:"async,omitempty"` // Run as a job and respond 202 instead of waiting
+}
+
+// BatchResult is the response to one operation of a batch
+type BatchResult struct {
+	ID     string          `json:"id,omitempty"`
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// BatchResponse holds the results of a batch in operation order. An async
+// batch job has the same fields in its result.
+type BatchResponse struct {
+	Results   []BatchResult `json:"results"`
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
+}
+
+// NotebookExport is the result of a notebook_export job: the notebook's
+// document metadata as newline-delimited JSON in object storage
+type NotebookExport struct {
+	NotebookID  string    `json:"notebook_id"`
+	Documents   int       `json:"documents"`
+	Bytes       int       `json:"bytes"`
+	DownloadURL string    `json:"download_url"` // Presigned; valid until ExpiresAt
+	ExpiresAt   time.Time `json:"expires_at"`
+}
@@ -0,0 +1,144 @@
+package models
+
+import "time"
+
+// LegalHoldStatus is whether a legal hold preserves its content
+type LegalHoldStatus string
+
+const (
+	// LegalHoldActive holds keep their content from being edited or deleted
+	LegalHoldActive LegalHoldStatus = "active"
+	// LegalHoldReleased holds no longer preserve anything; they are kept
+	// for the record
+	LegalHoldReleased LegalHoldStatus = "released"
+)
+
+// LegalHoldExportManifestVersion is the version of the legal hold export
+// bundle layout described by LegalHoldExportManifest
+const LegalHoldExportManifestVersion = 1
+
+// LegalHold preserves the content of users and notebooks of a tenant for
+// e-discovery. While it is active, the documents of its notebooks and the
+// documents and notebooks its users own cannot be edited or deleted.
+type LegalHold struct {
+	ID            string          `json:"id"`
+	TenantID      string          `json:"tenant_id"`
+	Name          string          `json:"name"`
+	Matter        string          `json:"matter,omitempty"` // Case or matter reference
+	Description   string          `json:"description,omitempty"`
+	Status        LegalHoldStatus `json:"status"`
+	UserIDs       []string        `json:"user_ids"`     // Custodians
+	NotebookIDs   []string        `json:"notebook_ids"` // Held notebooks
+	CreatedBy     string          `json:"created_by"`
+	CreatedAt     time.Time       `json:"created_at"`
+	ReleasedBy    string          `json:"released_by,omitempty"`
+	ReleasedAt    *time.Time      `json:"released_at,omitempty"`
+	ReleaseReason string          `json:"release_reason,omitempty"`
+}
+
+// LegalHoldCreateRequest places users and notebooks of a tenant under a
+// legal hold. At least one user or notebook is required.
+type LegalHoldCreateRequest struct {
+	TenantID    string   `json:"tenant_id" validate:"required"`
+	Name        string   `json:"name" validate:"required,min=1,max=255"`
+	Matter      string   `json:"matter,omitempty" validate:"max=255"`
+	Description string   `json:"description,omitempty" validate:"max=2000"`
+	UserIDs     []string `json:"user_ids,omitempty" validate:"max=100,dive,required"`
+	NotebookIDs []string `json:"notebook_ids,omitempty" validate:"max=100,dive,required"`
+}
+
+// LegalHoldReleaseRequest releases a legal hold, saying why
+type LegalHoldReleaseRequest struct {
+	Reason string `json:"reason" validate:"required,max=1000"`
+}
+
+// LegalHoldListResponse is one page of legal holds, newest first
+type LegalHoldListResponse struct {
+	Holds   []*LegalHold `json:"holds"`
+	Limit   int          `json:"limit"`
+	Offset  int          `json:"offset"`
+	HasMore bool         `json:"has_more"`
+}
+
+// LegalHoldCriteria selects the held documents a collection returns.
+// Documents match any of the keywords, as whole words in their name,
+// description, tags or extracted text, and were uploaded in the time
+// range, which includes From and excludes To. Empty criteria match every
+// held document.
+type LegalHoldCriteria struct {
+	Keywords []string   `json:"keywords,omitempty" validate:"max=50,dive,min=1,max=200"`
+	From     *time.Time `json:"from,omitempty"`
+	To       *time.Time `json:"to,omitempty"`
+}
+
+// LegalHoldDocument is a held document a collection found
+type LegalHoldDocument struct {
+	DocumentID string    `json:"document_id"`
+	Name       string    `json:"name"`
+	NotebookID string    `json:"notebook_id"`
+	OwnerID    string    `json:"owner_id"`
+	Status     string    `json:"status"` // deleted for documents in the trash
+	MimeType   string    `json:"mime_type,omitempty"`
+	SizeBytes  int64     `json:"size_bytes"`
+	Checksum   string    `json:"checksum,omitempty"` // Recorded when the file was uploaded
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	// MatchedKeywords are the criteria's keywords the document contains
+	MatchedKeywords []string `json:"matched_keywords,omitempty"`
+}
+
+// LegalHoldCollection is one page of the held documents matching some
+// criteria, oldest first
+type LegalHoldCollection struct {
+	HoldID    string               `json:"hold_id"`
+	Criteria  LegalHoldCriteria    `json:"criteria"`
+	Documents []*LegalHoldDocument `json:"documents"`
+	Total     int                  `json:"total"`
+	Limit     int                  `json:"limit"`
+	Offset    int                  `json:"offset"`
+	HasMore   bool                 `json:"has_more"`
+}
+
+// LegalHoldExport is the result of a legal_hold_export job: a bundle in
+// object storage holding the collected documents, their stored files and
+// extracted text and their audit events, described by a manifest that
+// records their chain of custody
+type LegalHoldExport struct {
+	HoldID         string    `json:"hold_id"`
+	Prefix         string    `json:"prefix"` // Storage key prefix of every file in the bundle
+	ManifestKey    string    `json:"manifest_key"`
+	ManifestSHA256 string    `json:"manifest_sha256"`
+	Documents      int       `json:"documents"`
+	Files          int       `json:"files"`
+	MissingFiles   int       `json:"missing_files"` // Stored files that could not be read
+	AuditEvents    int       `json:"audit_events"`
+	Bytes          int64     `json:"bytes"`
+	ManifestURL    string    `json:"manifest_url"` // Presigned; valid until ExpiresAt
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+// LegalHoldExportManifest describes a legal hold export bundle: the hold
+// and criteria it was collected under, who collected it and when, and
+// every file with its SHA-256 hash, so the bundle can be shown unaltered
+// since collection
+type LegalHoldExportManifest struct {
+	Version     int                `json:"version"`
+	Hold        *LegalHold         `json:"hold"`
+	Criteria    LegalHoldCriteria  `json:"criteria"`
+	CollectedBy string             `json:"collected_by"`
+	CollectedAt time.Time          `json:"collected_at"`
+	CompletedAt time.Time          `json:"completed_at"`
+	Counts      map[string]int     `json:"counts"`
+	Files       []TenantExportFile `json:"files"`
+	Missing     []TenantExportFile `json:"missing,omitempty"` // Stored files that could not be read; they have no hash
+	// Custody lists what happened to the collected content, oldest first
+	Custody []LegalHoldCustodyEntry `json:"custody"`
+}
+
+// LegalHoldCustodyEntry is a step in the chain of custody of an export
+type LegalHoldCustodyEntry struct {
+	At      time.Time `json:"at"`
+	ActorID string    `json:"actor_id"`
+	Action  string    `json:"action"` // collected, copied or sealed
+	Detail  string    `json:"detail,omitempty"`
+}
@@ -0,0 +1,104 @@
+package models
+
+import "time"
+
+// Processing retry job statuses
+const (
+	RetryJobStatusScheduled  = "scheduled"
+	RetryJobStatusInProgress = "in_progress"
+	RetryJobStatusCompleted  = "completed"
+	RetryJobStatusFailed     = "failed"
+)
+
+// ProcessingRetryJob is a scheduled reprocessing of a document. Failed
+// jobs are not retried again and form the dead-letter queue operators
+// inspect and requeue.
+type ProcessingRetryJob struct {
+	ID           string    `json:"id"`
+	DocumentID   string    `json:"document_id"`
+	TenantID     string    `json:"tenant_id"`
+	CampaignID   string    `json:"campaign_id,omitempty"` // Set for jobs of a reprocessing campaign
+	RetryAttempt int64     `json:"retry_attempt"`
+	Status       string    `json:"status"`
+	RetryAt      time.Time `json:"retry_at"`
+	LastError    string    `json:"last_error,omitempty"` // Why the last run failed
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// DeadLetterListResponse lists failed processing retry jobs, most recently
+// failed first
+type DeadLetterListResponse struct {
+	Jobs    []*ProcessingRetryJob `json:"jobs"`
+	Limit   int                   `json:"limit"`
+	Offset  int                   `json:"offset"`
+	HasMore bool                  `json:"has_more"`
+}
+
+// ReprocessCampaignRequest selects documents to reprocess in bulk. The
+// documents are queued as processing retry jobs, so the retry poller
+// bounds how fast they are resubmitted.
+type ReprocessCampaignRequest struct {
+	TenantID     string     `json:"tenant_id,omitempty"`
+	Status       string     `json:"status,omitempty" validate:"omitempty,oneof=failed processing processed"` // Defaults to failed
+	CreatedAfter *time.Time `json:"created_after,omitempty"`
+	Limit        int        `json:"limit,omitempty" validate:"omitempty,min=1,max=1000"` // Defaults to 100
+	DryRun       bool       `json:"dry_run,omitempty"`
+}
+
+// ReprocessCampaignResponse reports the documents a campaign queued, or
+// would queue in a dry run. Documents that already have a pending retry
+// job are skipped.
+type ReprocessCampaignResponse struct {
+	CampaignID  string   `json:"campaign_id,omitempty"`
+	DryRun      bool     `json:"dry_run"`
+	Queued      int      `json:"queued"`
+	DocumentIDs []string `json:"document_ids"`
+}
+
+// TenantProvisionRequest provisions an organization tenant for an existing
+// user: the organization, with the user as owner, and its first space
+type TenantProvisionRequest struct {
+	OwnerID      string                    `json:"owner_id" validate:"required"`
+	Organization OrganizationCreateRequest `json:"organization" validate:"required"`
+	SpaceName    string                    `json:"space_name,omitempty" validate:"omitempty,min=1,max=100"` // Defaults to the organization name
+}
+
+// TenantProvisionResponse describes a provisioned tenant
+type TenantProvisionResponse struct {
+	Organization *OrganizationResponse `json:"organization"`
+	Space        *SpaceFullResponse    `json:"space"`
+}
+
+// Scopes of a maintenance window
+const (
+	MaintenanceScopeSystem = "system" // Every tenant
+	MaintenanceScopeTenant = "tenant" // One tenant
+)
+
+// MaintenanceRequest starts a maintenance window, or changes the banner of
+// the one in progress
+type MaintenanceRequest struct {
+	// Message is shown to users in the maintenance banner
+	Message string `json:"message" validate:"required,max=500"`
+	// ExpectedEndAt is when the maintenance is expected to end. It is only
+	// shown to users; the window lasts until it is ended.
+	ExpectedEndAt *time.Time `json:"expected_end_at,omitempty"`
+}
+
+// MaintenanceWindow is a maintenance in progress, during which writes are
+// rejected system-wide or for one tenant
+type MaintenanceWindow struct {
+	Scope         string     `json:"scope"`
+	TenantID      string     `json:"tenant_id,omitempty"` // Set when the scope is tenant
+	Message       string     `json:"message"`
+	StartedAt     time.Time  `json:"started_at"`
+	ExpectedEndAt *time.Time `json:"expected_end_at,omitempty"`
+	StartedBy     string     `json:"started_by,omitempty"`
+}
+
+// MaintenanceStatus lists the maintenance windows in progress
+type MaintenanceStatus struct {
+	Active  bool                 `json:"active"`
+	Windows []*MaintenanceWindow `json:"windows"`
+}
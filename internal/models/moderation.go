@@ -0,0 +1,83 @@
+package models
+
+import "time"
+
+// ModerationStatus is where a document of a public notebook stands with
+// content moderation
+type ModerationStatus string
+
+const (
+	// ModerationPending documents are held until they are scanned
+	ModerationPending ModerationStatus = "pending"
+	// ModerationApproved documents are readable by the whole space
+	ModerationApproved ModerationStatus = "approved"
+	// ModerationFlagged documents are readable but listed for review
+	ModerationFlagged ModerationStatus = "flagged"
+	// ModerationBlocked documents are held until a reviewer approves them
+	ModerationBlocked ModerationStatus = "blocked"
+)
+
+// Held reports whether documents of the status are hidden from everyone
+// but their owner and the space's owners and admins
+func (s ModerationStatus) Held() bool {
+	return s == ModerationPending || s == ModerationBlocked
+}
+
+// Moderation actions a provider decides on
+const (
+	ModerationAllow = "allow"
+	ModerationFlag  = "flag"
+	ModerationBlock = "block"
+)
+
+// ModerationVerdict is a moderation provider's decision on some text
+type ModerationVerdict struct {
+	Action     string   `json:"action"` // allow, flag or block
+	Categories []string `json:"categories,omitempty"`
+	Reason     string   `json:"reason,omitempty"`
+}
+
+// Status returns the moderation status a document gets from the verdict
+func (v ModerationVerdict) Status() ModerationStatus {
+	switch v.Action {
+	case ModerationBlock:
+		return ModerationBlocked
+	case ModerationFlag:
+		return ModerationFlagged
+	default:
+		return ModerationApproved
+	}
+}
+
+// ModerationReviewRequest is a reviewer's decision on a moderated document,
+// overriding the provider's
+type ModerationReviewRequest struct {
+	Decision ModerationStatus `json:"decision" validate:"required,oneof=approved blocked"`
+	Note     string           `json:"note,omitempty" validate:"max=1000"`
+}
+
+// ModeratedDocument is a document of a public notebook as moderation sees
+// it
+type ModeratedDocument struct {
+	DocumentID  string           `json:"document_id"`
+	Name        string           `json:"name"`
+	NotebookID  string           `json:"notebook_id"`
+	OwnerID     string           `json:"owner_id"`
+	Status      ModerationStatus `json:"status"`
+	Provider    string           `json:"provider,omitempty"`
+	Categories  []string         `json:"categories,omitempty"`
+	Reason      string           `json:"reason,omitempty"`
+	ModeratedAt *time.Time       `json:"moderated_at,omitempty"`
+	ReviewedBy  string           `json:"reviewed_by,omitempty"`
+	ReviewNote  string           `json:"review_note,omitempty"`
+	ReviewedAt  *time.Time       `json:"reviewed_at,omitempty"`
+}
+
+// ModeratedDocumentListResponse is one page of moderated documents, most
+// recently moderated first
+type ModeratedDocumentListResponse struct {
+	Documents []*ModeratedDocument `json:"documents"`
+	Limit     int                  `json:"limit"`
+	Offset    int                  `json:"offset"`
+	HasMore   bool                 `json:"has_more"`
+}
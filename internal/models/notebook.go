@@ -94,6 +94,21 @@ type NotebookListResponse struct {
 	HasMore   bool                `json:"hasMore"`
 }
 
+// Pagination describes the page of a v2 list response
+type Pagination struct {
+	Total   int  `json:"total"`
+	Limit   int  `json:"limit"`
+	Offset  int  `json:"offset"`
+	HasMore bool `json:"has_more"`
+}
+
+// NotebookListResponseV2 is the v2 notebook list, which uses the data and
+// pagination envelope shared by v2 list endpoints
+type NotebookListResponseV2 struct {
+	Data       []*NotebookResponse `json:"data"`
+	Pagination Pagination          `json:"pagination"`
+}
+
 // NotebookSearchRequest represents a notebook search request
 type NotebookSearchRequest struct {
 	Query      string   `json:"query,omitempty" validate:"omitempty,safe_string,min=2,max=100"`
@@ -105,23 +120,6 @@ type NotebookSearchRequest struct {
 	Offset     int      `json:"offset,omitempty" validate:"omitempty,min=0"`
 }
 
-// NotebookShareRequest represents a request to share a notebook
-type NotebookShareRequest struct {
-	UserIDs     []string `json:"user_ids,omitempty" validate:"dive,uuid"`
-	GroupIDs    []string `json:"group_ids,omitempty"`
-	Permissions []string `json:"permissions" validate:"required,dive,oneof=read write admin"`
-}
-
-// NotebookPermission represents notebook permissions
-type NotebookPermission struct {
-	NotebookID string    `json:"notebook_id"`
-	UserID     string    `json:"user_id,omitempty"`
-	GroupID    string    `json:"group_id,omitempty"`
-	Permission string    `json:"permission" validate:"oneof=read write admin"`
-	GrantedBy  string    `json:"granted_by"`
-	GrantedAt  time.Time `json:"granted_at"`
-}
-
 // NotebookActivity represents notebook activity
 type NotebookActivity struct {
 	ID         string                 `json:"id"`
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// NotebookDigestSubscribeRequest subscribes the current user to a digest of
+// a notebook's changes, or changes how often it is sent
+type NotebookDigestSubscribeRequest struct {
+	// Cadence is daily, covering the previous UTC day, or weekly, covering
+	// the previous week from Monday
+	Cadence string `json:"cadence" validate:"required,oneof=daily weekly"`
+}
+
+// NotebookDigestSubscription sends a user a summary of the documents added
+// to and changed in a notebook at the end of every period
+type NotebookDigestSubscription struct {
+	ID           string     `json:"id"`
+	NotebookID   string     `json:"notebook_id"`
+	NotebookName string     `json:"notebook_name,omitempty"`
+	UserID       string     `json:"user_id"`
+	Cadence      string     `json:"cadence"`
+	NextRunAt    time.Time  `json:"next_run_at"`
+	LastSentAt   *time.Time `json:"last_sent_at,omitempty"`
+	LastError    string     `json:"last_error,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// NotebookDigestSubscriptionList is the digest subscriptions of a user
+type NotebookDigestSubscriptionList struct {
+	Subscriptions []*NotebookDigestSubscription `json:"subscriptions"`
+}
+
+// NotebookDigestDocument is a document listed in a digest
+type NotebookDigestDocument struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	ChangedAt time.Time `json:"changed_at"` // Time of the document's last change in the period
+}
+
+// NotebookDigest summarizes the changes to a notebook's documents in a
+// period; the end is exclusive
+type NotebookDigest struct {
+	NotebookID   string                    `json:"notebook_id"`
+	NotebookName string                    `json:"notebook_name"`
+	PeriodStart  time.Time                 `json:"period_start"`
+	PeriodEnd    time.Time                 `json:"period_end"`
+	New          []*NotebookDigestDocument `json:"new"`     // Uploaded in the period
+	Changed      []*NotebookDigestDocument `json:"changed"` // Uploaded before the period, then updated or restored from the trash
+}
+
+// Empty reports whether nothing changed in the period
+func (d *NotebookDigest) Empty() bool {
+	return len(d.New) == 0 && len(d.Changed) == 0
+}
@@ -0,0 +1,32 @@
+package models
+
+// NotebookAskRequest asks a question about a notebook's documents
+type NotebookAskRequest struct {
+	Question string `json:"question" validate:"required,min=2,max=2000"`
+}
+
+// NotebookAskSource is a chunk an answer was drawn from. Index is the
+// number the answer cites it by, as [1], [2], ... ChunkNumber and Page
+// locate the chunk in its document when they are known.
+type NotebookAskSource struct {
+	Index        int     `json:"index"`
+	DocumentID   string  `json:"document_id"`
+	DocumentName string  `json:"document_name"`
+	ChunkID      string  `json:"chunk_id"`
+	ChunkNumber  int     `json:"chunk_number,omitempty"`
+	Page         int     `json:"page,omitempty"`
+	Score        float64 `json:"score"`
+	Excerpt      string  `json:"excerpt"`
+}
+
+// NotebookAskResponse is a one-off answer to a question about a notebook.
+// Retrieval is "vector" or, when vector search is unavailable, "keyword".
+type NotebookAskResponse struct {
+	NotebookID string               `json:"notebook_id"`
+	Question   string               `json:"question"`
+	Answer     string               `json:"answer"`
+	Sources    []*NotebookAskSource `json:"sources"`
+	Retrieval  string               `json:"retrieval"`
+	Model      string               `json:"model"`
+	TokensUsed int                  `json:"tokens_used"`
+}
@@ -0,0 +1,89 @@
+package models
+
+import "time"
+
+// Roles a notebook is shared with. Viewers read the notebook and its
+// documents and editors also change the documents. Commenters may also
+// comment; until comments exist they have the access of viewers.
+const (
+	NotebookRoleViewer    = "viewer"
+	NotebookRoleCommenter = "commenter"
+	NotebookRoleEditor    = "editor"
+)
+
+// notebookRoleRanks orders the share roles, each granting what the ones
+// before it grant
+var notebookRoleRanks = map[string]int{
+	NotebookRoleViewer:    1,
+	NotebookRoleCommenter: 2,
+	NotebookRoleEditor:    3,
+}
+
+// NotebookRoleAtLeast reports whether role grants what minimum grants.
+// The empty role, no share, grants nothing.
+func NotebookRoleAtLeast(role, minimum string) bool {
+	return role != "" && notebookRoleRanks[role] >= notebookRoleRanks[minimum]
+}
+
+// HighestNotebookRole returns the role granting the most of roles, such as
+// the roles a user holds directly and through their teams
+func HighestNotebookRole(roles []string) string {
+	highest := ""
+	for _, role := range roles {
+		if notebookRoleRanks[role] > notebookRoleRanks[highest] {
+			highest = role
+		}
+	}
+	return highest
+}
+
+// Who a notebook is shared with
+const (
+	NotebookShareUser = "user"
+	NotebookShareTeam = "team"
+)
+
+// NotebookShareRequest shares a notebook with users and teams, all with
+// the same role. Sharing again with the same user or team changes its role.
+type NotebookShareRequest struct {
+	UserIDs []string `json:"user_ids,omitempty" validate:"dive,uuid"`
+	TeamIDs []string `json:"team_ids,omitempty" validate:"dive,uuid"`
+	Role    string   `json:"role" validate:"required,oneof=viewer commenter editor"`
+}
+
+// NotebookShare is a user or team a notebook is shared with
+type NotebookShare struct {
+	NotebookID string    `json:"notebook_id"`
+	ShareType  string    `json:"share_type"` // user or team
+	ShareID    string    `json:"share_id"`   // ID of the user or team
+	Name       string    `json:"name,omitempty"`
+	Role       string    `json:"role"`
+	SharedBy   string    `json:"shared_by"`
+	SharedAt   time.Time `json:"shared_at"`
+}
+
+// NotebookShareListResponse lists the shares of a notebook
+type NotebookShareListResponse struct {
+	Shares []*NotebookShare `json:"shares"`
+}
+
+// SharedNotebook is a notebook shared with the user, directly or through
+// one of their teams, with the highest role they were given
+type SharedNotebook struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	Description   string    `json:"description,omitempty"`
+	OwnerID       string    `json:"owner_id"`
+	DocumentCount int       `json:"document_count"`
+	Role          string    `json:"role"`
+	SharedAt      time.Time `json:"shared_at"` // When the latest share was made
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// SharedNotebookListResponse is a page of the notebooks shared with the user
+type SharedNotebookListResponse struct {
+	Notebooks []*SharedNotebook `json:"notebooks"`
+	Limit     int               `json:"limit"`
+	Offset    int               `json:"offset"`
+	HasMore   bool              `json:"has_more"`
+}
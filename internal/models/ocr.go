@@ -0,0 +1,57 @@
+package models
+
+import "encoding/json"
+
+// OCRSettingsKey is the key of a space's OCR settings in its settings
+const OCRSettingsKey = "ocr"
+
+// Default OCR settings, used by spaces that have not set their own
+const (
+	DefaultOCRLanguage = "eng"
+	DefaultOCRDPI      = 300
+)
+
+// OCRSettings control AudiModal's OCR fallback, which extracts the text of
+// images and of PDFs without a text layer. Languages are Tesseract
+// language pack codes and must be among the packs AudiModal has installed.
+type OCRSettings struct {
+	Languages []string `json:"languages" validate:"required,min=1,max=10,dive,min=3,max=20"`
+	DPI       int      `json:"dpi" validate:"required,min=72,max=600"`
+	// ForceOCR runs OCR on scanned PDFs even when they carry a text
+	// layer, which is often poor on scans
+	ForceOCR bool `json:"force_ocr"`
+}
+
+// DefaultOCRSettings returns the OCR settings of a space that has not set
+// its own
+func DefaultOCRSettings() *OCRSettings {
+	return &OCRSettings{
+		Languages: []string{DefaultOCRLanguage},
+		DPI:       DefaultOCRDPI,
+	}
+}
+
+// SpaceOCRSettings returns the OCR settings in a space's settings, or the
+// defaults when it has none
+func SpaceOCRSettings(settings map[string]interface{}) *OCRSettings {
+	value, ok := settings[OCRSettingsKey]
+	if !ok || value == nil {
+		return DefaultOCRSettings()
+	}
+	// The settings are decoded from JSON, so the OCR settings are a map
+	data, err := json.Marshal(value)
+	if err != nil {
+		return DefaultOCRSettings()
+	}
+	ocr := DefaultOCRSettings()
+	if err := json.Unmarshal(data, ocr); err != nil {
+		return DefaultOCRSettings()
+	}
+	if len(ocr.Languages) == 0 {
+		ocr.Languages = []string{DefaultOCRLanguage}
+	}
+	if ocr.DPI == 0 {
+		ocr.DPI = DefaultOCRDPI
+	}
+	return ocr
+}
@@ -122,14 +122,14 @@ type OrganizationMemberResponse struct {
 // OrganizationInviteRequest represents a request to invite an organization member
 type OrganizationInviteRequest struct {
 	Email      string `json:"email" validate:"required,email,max=254"`
-	Role       string `json:"role" validate:"required,oneof=admin member billing"`
+	Role       string `json:"role" validate:"required,max=50"` // A built-in role other than owner, or a custom role of the organization
 	Title      string `json:"title,omitempty" validate:"omitempty,safe_string,max=100"`
 	Department string `json:"department,omitempty" validate:"omitempty,safe_string,max=100"`
 }
 
 // OrganizationMemberRoleUpdateRequest represents a request to update an organization member's role
 type OrganizationMemberRoleUpdateRequest struct {
-	Role       string `json:"role" validate:"required,oneof=admin member billing"`
+	Role       string `json:"role" validate:"required,max=50"` // A built-in role other than owner, or a custom role of the organization
 	Title      string `json:"title,omitempty" validate:"omitempty,safe_string,max=100"`
 	Department string `json:"department,omitempty" validate:"omitempty,safe_string,max=100"`
 }
@@ -234,31 +234,20 @@ func (o *Organization) Update(req OrganizationUpdateRequest) {
 	o.UpdatedAt = time.Now()
 }
 
-// CanUserModify checks if a user can modify the organization based on their role
-func (o *Organization) CanUserModify(userRole string) bool {
-	return userRole == "owner" || userRole == "admin"
-}
-
-// CanUserDelete checks if a user can delete the organization
-func (o *Organization) CanUserDelete(userRole string) bool {
-	return userRole == "owner"
-}
-
-// CanUserInvite checks if a user can invite members to the organization
-func (o *Organization) CanUserInvite(userRole string) bool {
-	return userRole == "owner" || userRole == "admin"
-}
-
-// CanUserManageBilling checks if a user can manage billing for the organization
-func (o *Organization) CanUserManageBilling(userRole string) bool {
-	return userRole == "owner" || userRole == "billing"
-}
-
 // HasTenant checks if the organization has an associated tenant
 func (o *Organization) HasTenant() bool {
 	return o.TenantID != "" && o.TenantAPIKey != ""
 }
 
+// BillingPlan returns the organization's billing plan, "free" when none is
+// recorded
+func (o *Organization) BillingPlan() string {
+	if plan, ok := o.Billing["plan"].(string); ok && plan != "" {
+		return plan
+	}
+	return "free"
+}
+
 // GetTenantInfo returns tenant information (without exposing API key)
 func (o *Organization) GetTenantInfo() map[string]interface{} {
 	return map[string]interface{}{
@@ -289,6 +278,19 @@ func DefaultOrganizationSettings() map[string]interface{} {
 	}
 }
 
+// BillingSubscription is the subscription a billing provider reports for an
+// organization
+type BillingSubscription struct {
+	OrganizationID   string
+	CustomerID       string
+	SubscriptionID   string
+	Status           string // Provider status, e.g. active, past_due or canceled
+	Plan             string
+	Seats            int        // 0 leaves the seats unchanged
+	CurrentPeriodEnd *time.Time // Next billing date
+	ChangedAt        time.Time  // When the provider changed the subscription
+}
+
 // DefaultOrganizationBilling returns default billing information for a new organization
 func DefaultOrganizationBilling(billingEmail string) map[string]interface{} {
 	return map[string]interface{}{
@@ -346,6 +348,15 @@ type OrganizationSearchResponse struct {
 	HasMore       bool                    `json:"hasMore"`
 }
 
+// OrganizationMemberListResponse represents a paginated list of organization members
+type OrganizationMemberListResponse struct {
+	Members []*OrganizationMemberResponse `json:"members"`
+	Total   int                           `json:"total"`
+	Limit   int                           `json:"limit"`
+	Offset  int                           `json:"offset"`
+	HasMore bool                          `json:"hasMore"`
+}
+
 // OrganizationListResponse represents a paginated list of organizations
 type OrganizationListResponse struct {
 	Organizations []*OrganizationResponse `json:"organizations"`
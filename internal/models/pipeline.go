@@ -0,0 +1,105 @@
+package models
+
+import "time"
+
+// Processing pipeline stages, in order
+const (
+	PipelineStageUpload     = "upload"     // File stored in the tenant bucket
+	PipelineStageSubmit     = "submit"     // Processing job accepted by AudiModal
+	PipelineStageProcessing = "processing" // AudiModal finished extracting the document
+	PipelineStageIndexing   = "indexing"   // Chunks and embeddings created
+)
+
+// PipelineStages lists the pipeline stages in order
+var PipelineStages = []string{
+	PipelineStageUpload,
+	PipelineStageSubmit,
+	PipelineStageProcessing,
+	PipelineStageIndexing,
+}
+
+// PipelineTimestamps records when a document reached each pipeline
+// milestone. Milestones not reached yet are nil.
+type PipelineTimestamps struct {
+	CreatedAt       time.Time  `json:"created_at"`
+	StoredAt        *time.Time `json:"stored_at,omitempty"`
+	SubmitStartedAt *time.Time `json:"submit_started_at,omitempty"`
+	SubmittedAt     *time.Time `json:"submitted_at,omitempty"`
+	ProcessedAt     *time.Time `json:"processed_at,omitempty"`
+	IndexedAt       *time.Time `json:"indexed_at,omitempty"`
+}
+
+// PipelineStageTiming is the duration of one stage; stages still in
+// progress or skipped have no duration
+type PipelineStageTiming struct {
+	Stage      string `json:"stage"`
+	DurationMs *int64 `json:"duration_ms,omitempty"`
+}
+
+// DocumentPipeline reports how long a document spent in each stage
+type DocumentPipeline struct {
+	DocumentID string                `json:"document_id"`
+	TenantID   string                `json:"tenant_id,omitempty"`
+	Status     string                `json:"status"`
+	Timestamps PipelineTimestamps    `json:"timestamps"`
+	Stages     []PipelineStageTiming `json:"stages"`
+	TotalMs    int64                 `json:"total_ms"` // Sum of the completed stages
+}
+
+// NewDocumentPipeline computes the stage durations from a document's
+// pipeline timestamps
+func NewDocumentPipeline(documentID, tenantID, status string, ts PipelineTimestamps) *DocumentPipeline {
+	created := ts.CreatedAt
+	bounds := map[string][2]*time.Time{
+		PipelineStageUpload:     {&created, ts.StoredAt},
+		PipelineStageSubmit:     {ts.SubmitStartedAt, ts.SubmittedAt},
+		PipelineStageProcessing: {ts.SubmittedAt, ts.ProcessedAt},
+		PipelineStageIndexing:   {ts.ProcessedAt, ts.IndexedAt},
+	}
+
+	pipeline := &DocumentPipeline{
+		DocumentID: documentID,
+		TenantID:   tenantID,
+		Status:     status,
+		Timestamps: ts,
+		Stages:     make([]PipelineStageTiming, 0, len(PipelineStages)),
+	}
+	for _, stage := range PipelineStages {
+		timing := PipelineStageTiming{Stage: stage}
+		start, end := bounds[stage][0], bounds[stage][1]
+		if start != nil && end != nil && !start.IsZero() {
+			// Some milestones are stored with second precision, so a
+			// stage finishing within a second can appear to end early
+			ms := max(end.Sub(*start).Milliseconds(), 0)
+			timing.DurationMs = &ms
+			pipeline.TotalMs += ms
+		}
+		pipeline.Stages = append(pipeline.Stages, timing)
+	}
+	return pipeline
+}
+
+// PipelineStageSummary summarizes one stage's durations, in milliseconds
+type PipelineStageSummary struct {
+	Stage     string  `json:"stage"`
+	Documents int64   `json:"documents"` // Documents that completed the stage
+	P50Ms     float64 `json:"p50_ms"`
+	P95Ms     float64 `json:"p95_ms"`
+	P99Ms     float64 `json:"p99_ms"`
+	MaxMs     float64 `json:"max_ms"`
+}
+
+// TenantPipelineSummary summarizes the pipeline of a tenant's documents
+type TenantPipelineSummary struct {
+	TenantID  string                 `json:"tenant_id"`
+	Documents int64                  `json:"documents"`
+	Stages    []PipelineStageSummary `json:"stages"`
+}
+
+// PipelineSummaryResponse summarizes pipeline timings of the documents
+// uploaded since a point in time, with the slowest documents for drilling in
+type PipelineSummaryResponse struct {
+	Since   time.Time               `json:"since"`
+	Tenants []TenantPipelineSummary `json:"tenants"`
+	Slowest []*DocumentPipeline     `json:"slowest"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import "encoding/json"
+
+// ProcessingSettingsKey is the key of a space's processing settings in its
+// settings
+const ProcessingSettingsKey = "processing"
+
+// ProcessingSettings choose how a space's documents are processed
+type ProcessingSettings struct {
+	// Provider is the registered processing provider that extracts the
+	// text of the space's documents; empty uses the default provider
+	Provider string `json:"provider" validate:"max=50"`
+}
+
+// SpaceProcessingSettings returns the processing settings in a space's
+// settings, empty when it has none
+func SpaceProcessingSettings(settings map[string]interface{}) *ProcessingSettings {
+	processing := &ProcessingSettings{}
+	value, ok := settings[ProcessingSettingsKey]
+	if !ok || value == nil {
+		return processing
+	}
+	// The settings are decoded from JSON, so the processing settings are
+	// a map
+	data, err := json.Marshal(value)
+	if err != nil {
+		return processing
+	}
+	if err := json.Unmarshal(data, processing); err != nil {
+		return &ProcessingSettings{}
+	}
+	return processing
+}
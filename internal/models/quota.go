@@ -0,0 +1,91 @@
+package models
+
+import "time"
+
+// Quotas of a space that are enforced
+const (
+	QuotaDocuments       = "documents"        // Documents held, outside the trash
+	QuotaStorageBytes    = "storage_bytes"    // Bytes of the files of those documents, every version included
+	QuotaAgentExecutions = "agent_executions" // Agent executions this month
+	QuotaStreamEvents    = "stream_events"    // Stream events ingested this month
+)
+
+// QuotaUsage is a space's use of one quota
+type QuotaUsage struct {
+	Quota     string  `json:"quota"`
+	Used      int64   `json:"used"`
+	Limit     int64   `json:"limit"`
+	Remaining int64   `json:"remaining"`
+	Percent   float64 `json:"percent"` // Used as a percentage of the limit; over 100 once exceeded
+	Monthly   bool    `json:"monthly"` // Counted per calendar month, reset at ResetsAt
+}
+
+// SpaceQuotaUsage is a space's use of its quotas. Monthly quotas count the
+// current calendar month (UTC).
+type SpaceQuotaUsage struct {
+	SpaceID  string        `json:"space_id"`
+	Period   string        `json:"period"` // YYYY-MM of the monthly quotas
+	ResetsAt time.Time     `json:"resets_at"`
+	Enforced bool          `json:"enforced"` // Whether writes over a quota are refused
+	Quotas   []*QuotaUsage `json:"quotas"`
+}
+
+// Quota returns the usage of a quota, nil when it is not tracked
+func (u *SpaceQuotaUsage) Quota(quota string) *QuotaUsage {
+	for _, usage := range u.Quotas {
+		if usage.Quota == quota {
+			return usage
+		}
+	}
+	return nil
+}
+
+// NewQuotaUsage returns the usage of a quota with its remaining amount and
+// percentage
+func NewQuotaUsage(quota string, used, limit int64, monthly bool) *QuotaUsage {
+	usage := &QuotaUsage{Quota: quota, Used: used, Limit: limit, Monthly: monthly}
+	if remaining := limit - used; remaining > 0 {
+		usage.Remaining = remaining
+	}
+	if limit > 0 {
+		usage.Percent = float64(used) * 100 / float64(limit)
+	}
+	return usage
+}
+
+// SpaceQuotasUpdateRequest changes the limits of a space's quotas; omitted
+// limits keep their value
+type SpaceQuotasUpdateRequest struct {
+	MaxNotebooks       *int   `json:"max_notebooks,omitempty" validate:"omitempty,min=1"`
+	MaxDocuments       *int   `json:"max_documents,omitempty" validate:"omitempty,min=1"`
+	MaxStorageBytes    *int64 `json:"max_storage_bytes,omitempty" validate:"omitempty,min=1"`
+	MaxMembersCount    *int   `json:"max_members_count,omitempty" validate:"omitempty,min=1"`
+	MaxTeamsCount      *int   `json:"max_teams_count,omitempty" validate:"omitempty,min=0"`
+	MaxAgentExecutions *int64 `json:"max_agent_executions,omitempty" validate:"omitempty,min=1"`
+	MaxStreamEvents    *int64 `json:"max_stream_events,omitempty" validate:"omitempty,min=1"`
+}
+
+// Apply sets the limits the request names
+func (r *SpaceQuotasUpdateRequest) Apply(quotas *SpaceQuotas) {
+	if r.MaxNotebooks != nil {
+		quotas.MaxNotebooks = *r.MaxNotebooks
+	}
+	if r.MaxDocuments != nil {
+		quotas.MaxDocuments = *r.MaxDocuments
+	}
+	if r.MaxStorageBytes != nil {
+		quotas.MaxStorageBytes = *r.MaxStorageBytes
+	}
+	if r.MaxMembersCount != nil {
+		quotas.MaxMembersCount = *r.MaxMembersCount
+	}
+	if r.MaxTeamsCount != nil {
+		quotas.MaxTeamsCount = *r.MaxTeamsCount
+	}
+	if r.MaxAgentExecutions != nil {
+		quotas.MaxAgentExecutions = *r.MaxAgentExecutions
+	}
+	if r.MaxStreamEvents != nil {
+		quotas.MaxStreamEvents = *r.MaxStreamEvents
+	}
+}
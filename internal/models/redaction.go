@@ -0,0 +1,77 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// RedactionRules hide parts of a notebook's documents from the users it is
+// shared with at restricted roles: the extracted text and chunks they read
+// leave out the chunks the rules omit
+type RedactionRules struct {
+	NotebookID    string    `json:"notebook_id"`
+	Roles         []string  `json:"roles"`              // Share roles the rules apply to
+	OmitPIIChunks bool      `json:"omit_pii_chunks"`    // Omit chunks in which PII was detected
+	Sections      []string  `json:"sections,omitempty"` // Omit the chunks of these sections, by title
+	UpdatedBy     string    `json:"updated_by"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// RedactionRulesRequest sets the redaction rules of a notebook, replacing
+// the ones it had
+type RedactionRulesRequest struct {
+	// Roles defaults to viewer and commenter
+	Roles         []string `json:"roles,omitempty" validate:"omitempty,max=3,dive,oneof=viewer commenter editor"`
+	OmitPIIChunks bool     `json:"omit_pii_chunks"`
+	Sections      []string `json:"sections,omitempty" validate:"omitempty,max=50,dive,min=1,max=255"`
+}
+
+// DefaultRedactionRoles are the share roles rules apply to when a request
+// names none
+var DefaultRedactionRoles = []string{NotebookRoleViewer, NotebookRoleCommenter}
+
+// chunkSectionKeys are the keys of a chunk's context or metadata that may
+// name the section it belongs to
+var chunkSectionKeys = []string{"section", "section_title", "heading"}
+
+// ChunkSection returns the title of the section a chunk belongs to, read
+// from its context or metadata, or "" when the processor did not record one
+func ChunkSection(context map[string]string, metadata map[string]interface{}) string {
+	for _, key := range chunkSectionKeys {
+		if section := strings.TrimSpace(context[key]); section != "" {
+			return section
+		}
+		if section, ok := metadata[key].(string); ok && strings.TrimSpace(section) != "" {
+			return strings.TrimSpace(section)
+		}
+	}
+	return ""
+}
+
+// AppliesTo reports whether the rules apply to a share role
+func (r *RedactionRules) AppliesTo(role string) bool {
+	for _, redacted := range r.Roles {
+		if role == redacted {
+			return true
+		}
+	}
+	return false
+}
+
+// Omits reports whether the rules omit a chunk with the given PII flag and
+// section. Section titles are compared ignoring case and surrounding space.
+func (r *RedactionRules) Omits(piiDetected bool, section string) bool {
+	if r.OmitPIIChunks && piiDetected {
+		return true
+	}
+	section = strings.TrimSpace(section)
+	if section == "" {
+		return false
+	}
+	for _, omitted := range r.Sections {
+		if strings.EqualFold(strings.TrimSpace(omitted), section) {
+			return true
+		}
+	}
+	return false
+}
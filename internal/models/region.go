@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// TenantRegion pins a tenant to the region that serves it. Requests made
+// in the tenant's spaces are only served by the active region; the passive
+// region holds the replicas a failover promotes.
+type TenantRegion struct {
+	TenantID      string     `json:"tenant_id"`
+	ActiveRegion  string     `json:"active_region"`
+	PassiveRegion string     `json:"passive_region,omitempty"`
+	PinnedAt      time.Time  `json:"pinned_at"`
+	PinnedBy      string     `json:"pinned_by"`
+	FailedOverAt  *time.Time `json:"failed_over_at,omitempty"`
+	Failovers     int64      `json:"failovers"`
+}
+
+// TenantRegionRequest pins a tenant to a region
+type TenantRegionRequest struct {
+	ActiveRegion  string `json:"active_region" validate:"required"`
+	PassiveRegion string `json:"passive_region,omitempty"`
+}
+
+// TenantFailoverRequest fails a tenant over to another region
+type TenantFailoverRequest struct {
+	TargetRegion string `json:"target_region,omitempty"` // Default the tenant's passive region
+	Force        bool   `json:"force,omitempty"`         // Fail over even when a replication hook fails
+}
+
+// TenantFailover is the outcome of a failover
+type TenantFailover struct {
+	Tenant     *TenantRegion            `json:"tenant"`
+	FromRegion string                   `json:"from_region"`
+	ToRegion   string                   `json:"to_region"`
+	Hooks      []*ReplicationHookResult `json:"hooks"`
+}
+
+// ReplicationHookResult is the outcome of a replication hook called before
+// a failover
+type ReplicationHookResult struct {
+	Name       string `json:"name"`
+	Status     int    `json:"status,omitempty"` // HTTP status the hook answered
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// Succeeded reports whether the hook answered with a 2xx status
+func (r *ReplicationHookResult) Succeeded() bool {
+	return r.Error == "" && r.Status >= 200 && r.Status < 300
+}
+
+// Region is a region of a multi-region deployment
+type Region struct {
+	Name           string `json:"name"`
+	URL            string `json:"url"`
+	Local          bool   `json:"local"` // Whether it is the region answering
+	ActiveTenants  int64  `json:"active_tenants"`
+	PassiveTenants int64  `json:"passive_tenants"`
+}
+
+// RegionStatus lists the regions of a deployment
+type RegionStatus struct {
+	Region  string    `json:"region"` // Region answering
+	Regions []*Region `json:"regions"`
+}
@@ -0,0 +1,96 @@
+package models
+
+import "time"
+
+// Kinds of space report
+const (
+	// ReportProcessingSummary counts the documents uploaded, processed and
+	// failed in the period, per notebook
+	ReportProcessingSummary = "processing_summary"
+	// ReportStorageUsage lists the documents and bytes stored per notebook
+	// at the end of the period
+	ReportStorageUsage = "storage_usage"
+	// ReportAgentCost lists the executions and cost of each agent in the
+	// period
+	ReportAgentCost = "agent_cost"
+)
+
+// Formats of a rendered report
+const (
+	ReportFormatPDF = "pdf"
+	ReportFormatCSV = "csv"
+)
+
+// How often a report is generated. Each covers the previous UTC day, the
+// previous week from Monday, or the previous calendar month.
+const (
+	ReportCadenceDaily   = "daily"
+	ReportCadenceWeekly  = "weekly"
+	ReportCadenceMonthly = "monthly"
+)
+
+// Outcomes of a report run
+const (
+	ReportRunSucceeded = "succeeded"
+	ReportRunFailed    = "failed"
+)
+
+// ReportScheduleCreateRequest schedules a report of the current space
+type ReportScheduleCreateRequest struct {
+	Name    string `json:"name" validate:"required,min=1,max=255"`
+	Kind    string `json:"kind" validate:"required,oneof=processing_summary storage_usage agent_cost"`
+	Format  string `json:"format" validate:"required,oneof=pdf csv"`
+	Cadence string `json:"cadence" validate:"required,oneof=daily weekly monthly"`
+	// EmailTo receives each report as an attachment, when email is
+	// configured
+	EmailTo []string `json:"email_to,omitempty" validate:"max=20,dive,email,max=254"`
+	// NotifyUserIDs are told in the app when a report is ready; the
+	// creator when omitted
+	NotifyUserIDs []string `json:"notify_user_ids,omitempty" validate:"omitempty,max=20,dive,uuid"`
+}
+
+// ReportSchedule generates a report of a space at the end of every period
+type ReportSchedule struct {
+	ID            string     `json:"id"`
+	SpaceID       string     `json:"space_id"`
+	SpaceType     SpaceType  `json:"space_type"`
+	TenantID      string     `json:"-"`
+	Name          string     `json:"name"`
+	Kind          string     `json:"kind"`
+	Format        string     `json:"format"`
+	Cadence       string     `json:"cadence"`
+	EmailTo       []string   `json:"email_to,omitempty"`
+	NotifyUserIDs []string   `json:"notify_user_ids"`
+	NextRunAt     time.Time  `json:"next_run_at"`
+	LastRunAt     *time.Time `json:"last_run_at,omitempty"`
+	LastStatus    string     `json:"last_status,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	CreatedBy     string     `json:"created_by"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// ReportScheduleList is the report schedules of a space
+type ReportScheduleList struct {
+	Schedules []*ReportSchedule `json:"schedules"`
+}
+
+// ReportRun is one generated report. Failed runs have no file.
+type ReportRun struct {
+	ID          string    `json:"id"`
+	ScheduleID  string    `json:"schedule_id"`
+	Kind        string    `json:"kind"`
+	Format      string    `json:"format"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	Status      string    `json:"status"`
+	Error       string    `json:"error,omitempty"`
+	FileName    string    `json:"file_name,omitempty"`
+	SizeBytes   int64     `json:"size_bytes,omitempty"`
+	EmailedTo   int       `json:"emailed_to"` // Recipients the report was emailed to
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ReportRunList is the runs of a report schedule, newest first
+type ReportRunList struct {
+	Runs []*ReportRun `json:"runs"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// ReportingStatus describes the state of the PostgreSQL reporting projection
+type ReportingStatus struct {
+	Events      int64      `json:"events"`
+	LastEventAt *time.Time `json:"last_event_at,omitempty"`
+	Documents   int64      `json:"documents"`
+	Notebooks   int64      `json:"notebooks"`
+}
+
+// ReportingRebuildResponse reports the outcome of a read-model rebuild
+type ReportingRebuildResponse struct {
+	EventsReplayed int   `json:"events_replayed"`
+	DurationMs     int64 `json:"duration_ms"`
+}
+
+// SpaceUsage totals the daily usage of a space over a report's period
+type SpaceUsage struct {
+	SpaceID            string `json:"space_id,omitempty"`
+	DocumentsUploaded  int64  `json:"documents_uploaded"`
+	BytesUploaded      int64  `json:"bytes_uploaded"`
+	DocumentsProcessed int64  `json:"documents_processed"`
+	DocumentsFailed    int64  `json:"documents_failed"`
+	DocumentsDeleted   int64  `json:"documents_deleted"`
+}
+
+// UsageReport totals the projected daily usage per space for the days
+// from From up to but excluding To
+type UsageReport struct {
+	From   time.Time    `json:"from"`
+	To     time.Time    `json:"to"`
+	Spaces []SpaceUsage `json:"spaces"`
+	Total  SpaceUsage   `json:"total"`
+}
@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+// How an S3 watcher learns of new objects
+const (
+	// S3WatchModeSQS reads the bucket's event notifications from an SQS
+	// queue
+	S3WatchModeSQS = "sqs"
+	// S3WatchModePoll lists the prefix for objects modified since the
+	// last run
+	S3WatchModePoll = "poll"
+)
+
+// Statuses of an S3 watcher
+const (
+	S3WatcherActive  = "active"
+	S3WatcherFailing = "failing" // The last run failed; it is retried on the next
+)
+
+// Outcomes of a watched object
+const (
+	S3ObjectIngested = "ingested"
+	S3ObjectSkipped  = "skipped" // Empty, too large or a folder marker
+)
+
+// S3WatcherCreateRequest creates an S3 watcher. Objects under Prefix are
+// ingested when their key, relative to the prefix, matches an Include
+// pattern (any when none) and no Exclude pattern. Patterns are globs where
+// "*" and "?" stay within a path segment and "**" spans segments.
+type S3WatcherCreateRequest struct {
+	Bucket string `json:"bucket" validate:"required,min=3,max=63"`
+	Prefix string `json:"prefix,omitempty" validate:"max=1024"`
+	Region string `json:"region" validate:"required,min=2,max=32"`
+	// RoleARN is the role in the customer's account the watcher assumes,
+	// with the tenant's external ID; without it the deployment's own
+	// credentials are used
+	RoleARN string `json:"role_arn,omitempty" validate:"omitempty,min=20,max=2048"`
+	// QueueURL is the SQS queue receiving the bucket's ObjectCreated
+	// notifications; without it the prefix is polled
+	QueueURL string   `json:"queue_url,omitempty" validate:"omitempty,url,max=2048"`
+	Include  []string `json:"include,omitempty" validate:"max=50,dive,min=1,max=256"`
+	Exclude  []string `json:"exclude,omitempty" validate:"max=50,dive,min=1,max=256"`
+}
+
+// S3Watcher ingests the new objects of an S3 prefix into a notebook, as
+// documents owned by the notebook's owner
+type S3Watcher struct {
+	ID            string     `json:"id"`
+	NotebookID    string     `json:"notebook_id"`
+	Bucket        string     `json:"bucket"`
+	Prefix        string     `json:"prefix,omitempty"`
+	Region        string     `json:"region"`
+	RoleARN       string     `json:"role_arn,omitempty"`
+	ExternalID    string     `json:"external_id,omitempty"`
+	QueueURL      string     `json:"queue_url,omitempty"`
+	Mode          string     `json:"mode"`
+	Include       []string   `json:"include,omitempty"`
+	Exclude       []string   `json:"exclude,omitempty"`
+	Status        string     `json:"status"`
+	LastError     string     `json:"last_error,omitempty"`
+	LastRunAt     *time.Time `json:"last_run_at,omitempty"`
+	IngestedCount int64      `json:"ingested_count"`
+	CreatedBy     string     `json:"created_by"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// S3WatcherList is the S3 watchers of a notebook
+type S3WatcherList struct {
+	Watchers []*S3Watcher `json:"watchers"`
+}
+
+// S3WatchSetup is what a customer puts in the trust policy of the role
+// watchers assume
+type S3WatchSetup struct {
+	PrincipalARN string `json:"principal_arn"`
+	ExternalID   string `json:"external_id"`
+}
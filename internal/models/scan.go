@@ -0,0 +1,42 @@
+package models
+
+import "encoding/json"
+
+// ScanSettingsKey is the key of a space's malware scanning settings in its
+// settings
+const ScanSettingsKey = "scanning"
+
+// ScanSettings choose whether the files uploaded to a space are scanned
+// for malware before they are stored
+type ScanSettings struct {
+	// Enabled turns scanning on or off for the space; nil uses the
+	// deployment's default
+	Enabled *bool `json:"enabled"`
+}
+
+// SpaceScanSettings returns the scanning settings in a space's settings,
+// empty when it has none
+func SpaceScanSettings(settings map[string]interface{}) *ScanSettings {
+	scanning := &ScanSettings{}
+	value, ok := settings[ScanSettingsKey]
+	if !ok || value == nil {
+		return scanning
+	}
+	// The settings are decoded from JSON, so the scanning settings are a
+	// map
+	data, err := json.Marshal(value)
+	if err != nil {
+		return scanning
+	}
+	if err := json.Unmarshal(data, scanning); err != nil {
+		return &ScanSettings{}
+	}
+	return scanning
+}
+
+// ScanResult is a scanner's verdict on a file
+type ScanResult struct {
+	Scanner  string   `json:"scanner"`
+	Infected bool     `json:"infected"`
+	Threats  []string `json:"threats,omitempty"` // Names of the malware found
+}
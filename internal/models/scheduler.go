@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Scheduled job run statuses
+const (
+	JobRunStatusRunning   = "running"
+	JobRunStatusSucceeded = "succeeded"
+	JobRunStatusFailed    = "failed"
+)
+
+// ScheduledJob describes a background job registered with the scheduler
+type ScheduledJob struct {
+	Name       string     `json:"name" neo4j:"name"`
+	Schedule   string     `json:"schedule" neo4j:"schedule"`
+	Running    bool       `json:"running"`
+	NextRunAt  *time.Time `json:"next_run_at,omitempty" neo4j:"next_run_at"`
+	LastRunAt  *time.Time `json:"last_run_at,omitempty" neo4j:"last_run_at"`
+	LastStatus string     `json:"last_status,omitempty" neo4j:"last_status"`
+	LastError  string     `json:"last_error,omitempty" neo4j:"last_error"`
+}
+
+// JobRun records one execution of a scheduled job
+type JobRun struct {
+	ID         string     `json:"id" neo4j:"id"`
+	JobName    string     `json:"job_name" neo4j:"job_name"`
+	InstanceID string     `json:"instance_id" neo4j:"instance_id"`
+	Status     string     `json:"status" neo4j:"status"` // running, succeeded, failed
+	Error      string     `json:"error,omitempty" neo4j:"error"`
+	StartedAt  time.Time  `json:"started_at" neo4j:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty" neo4j:"finished_at"`
+	DurationMs int64      `json:"duration_ms" neo4j:"duration_ms"`
+}
+
+// ScheduledJobListResponse lists the registered jobs
+type ScheduledJobListResponse struct {
+	Jobs []*ScheduledJob `json:"jobs"`
+}
+
+// JobRunListResponse lists recent runs of a job, newest first
+type JobRunListResponse struct {
+	Runs []*JobRun `json:"runs"`
+}
@@ -0,0 +1,128 @@
+package models
+
+import "time"
+
+// SearchType is an entity type covered by the unified search
+type SearchType string
+
+// Search types
+const (
+	SearchTypeDocument SearchType = "document"
+	SearchTypeNotebook SearchType = "notebook"
+	SearchTypeAgent    SearchType = "agent"
+	SearchTypeEvent    SearchType = "event" // Live stream events
+)
+
+// SearchTypes lists every searchable type
+var SearchTypes = []SearchType{SearchTypeDocument, SearchTypeNotebook, SearchTypeAgent, SearchTypeEvent}
+
+// UnifiedSearchRequest represents a search across entity types
+type UnifiedSearchRequest struct {
+	Query string       `json:"q" validate:"required,min=2,max=100"`
+	Types []SearchType `json:"types,omitempty" validate:"omitempty,dive,oneof=document notebook agent event"` // All types when empty
+	Limit int          `json:"limit,omitempty" validate:"omitempty,min=1,max=20"`                             // Hits per type
+}
+
+// SearchHit is one matching entity
+type SearchHit struct {
+	Type       SearchType `json:"type"`
+	ID         string     `json:"id"`
+	Title      string     `json:"title"`
+	Snippet    string     `json:"snippet,omitempty"`
+	Kind       string     `json:"kind,omitempty"`        // Document type, agent type or event type
+	NotebookID string     `json:"notebook_id,omitempty"` // Notebook of a document
+	Score      float64    `json:"score"`                 // Comparable across types; higher is better
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// SearchGroup holds the hits of one type, best first
+type SearchGroup struct {
+	Type    SearchType   `json:"type"`
+	Hits    []*SearchHit `json:"hits"`
+	HasMore bool         `json:"has_more"`
+}
+
+// UnifiedSearchResponse returns the hits of a unified search both ranked
+// together and grouped by type. Groups are ordered by their best hit.
+type UnifiedSearchResponse struct {
+	Query       string         `json:"query"`
+	Results     []*SearchHit   `json:"results"`
+	Groups      []*SearchGroup `json:"groups"`
+	Unavailable []SearchType   `json:"unavailable,omitempty"` // Types that could not be searched
+}
+
+// SemanticSearchMode selects how chunks are matched
+type SemanticSearchMode string
+
+// Semantic search modes
+const (
+	// SemanticSearchVector ranks chunks by embedding similarity alone
+	SemanticSearchVector SemanticSearchMode = "vector"
+	// SemanticSearchHybrid blends embedding similarity with keyword matches
+	SemanticSearchHybrid SemanticSearchMode = "hybrid"
+)
+
+// SemanticSearchRequest represents a semantic search of a space's chunks
+type SemanticSearchRequest struct {
+	Query        string             `json:"query" validate:"required,min=2,max=1000"`
+	Mode         SemanticSearchMode `json:"mode,omitempty" validate:"omitempty,oneof=vector hybrid"` // Default vector
+	TopK         int                `json:"top_k,omitempty" validate:"omitempty,min=1,max=100"`      // Default 10
+	MinScore     float64            `json:"min_score,omitempty" validate:"omitempty,min=0,max=1"`
+	NotebookIDs  []string           `json:"notebook_ids,omitempty" validate:"omitempty,max=50"`       // Only chunks of documents in these notebooks
+	VectorWeight float64            `json:"vector_weight,omitempty" validate:"omitempty,min=0,max=1"` // Hybrid only; keywords get the rest. Default 0.7
+}
+
+// SemanticSearchDocument is the document a chunk hit belongs to
+type SemanticSearchDocument struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	MimeType   string `json:"mime_type,omitempty"`
+	NotebookID string `json:"notebook_id"`
+}
+
+// SemanticSearchHit is a matching chunk and its document
+type SemanticSearchHit struct {
+	ChunkID  string                  `json:"chunk_id"`
+	Score    float64                 `json:"score"`
+	Content  string                  `json:"content,omitempty"`
+	Document *SemanticSearchDocument `json:"document"`
+}
+
+// SemanticSearchResponse lists chunk hits, best first. Chunks whose
+// document the caller cannot read, or which no longer exists, are left out.
+type SemanticSearchResponse struct {
+	Query       string               `json:"query"`
+	Mode        SemanticSearchMode   `json:"mode"`
+	Hits        []*SemanticSearchHit `json:"hits"`
+	QueryTimeMS float64              `json:"query_time_ms"`
+}
+
+// SuggestionType is the kind of entity a typeahead suggestion completes to
+type SuggestionType string
+
+// Suggestion types
+const (
+	SuggestionDocument SuggestionType = "document"
+	SuggestionNotebook SuggestionType = "notebook"
+	SuggestionTag      SuggestionType = "tag" // A document tag used in the space
+)
+
+// SearchSuggestRequest asks for completions of what the user is typing
+type SearchSuggestRequest struct {
+	Query string `json:"q" validate:"required,min=1,max=100"`
+	Limit int    `json:"limit,omitempty" validate:"omitempty,min=1,max=20"`
+}
+
+// SearchSuggestion is one typeahead completion
+type SearchSuggestion struct {
+	Type SuggestionType `json:"type"`
+	ID   string         `json:"id,omitempty"` // Document or notebook ID; empty for tags
+	Text string         `json:"text"`
+}
+
+// SearchSuggestResponse returns completions in alphabetical order of the
+// text they matched
+type SearchSuggestResponse struct {
+	Query       string              `json:"query"`
+	Suggestions []*SearchSuggestion `json:"suggestions"`
+}
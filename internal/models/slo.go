@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// SLO objective names
+const (
+	SLOAvailability      = "availability"
+	SLOLatency           = "latency_p95"
+	SLOProcessingSuccess = "processing_success"
+)
+
+// SLOReport describes compliance with the service level objectives as seen
+// by one replica
+type SLOReport struct {
+	InstanceID  string      `json:"instance_id"`
+	GeneratedAt time.Time   `json:"generated_at"`
+	WindowDays  int         `json:"window_days"`
+	Objectives  []SLOStatus `json:"objectives"`
+}
+
+// SLOStatus reports one objective over the compliance window. The SLI is
+// the fraction of good events; burn rates compare the recent bad-event rate
+// with the rate the error budget allows.
+type SLOStatus struct {
+	Objective            string  `json:"objective"`
+	RouteGroup           string  `json:"route_group,omitempty"`
+	Target               float64 `json:"target"`
+	LatencyThresholdMs   int     `json:"latency_threshold_ms,omitempty"`
+	SLI                  float64 `json:"sli"`
+	P95LatencyMs         float64 `json:"p95_latency_ms,omitempty"`
+	Events               int64   `json:"events"`
+	BadEvents            int64   `json:"bad_events"`
+	Compliant            bool    `json:"compliant"`
+	ErrorBudgetRemaining float64 `json:"error_budget_remaining"`
+	BurnRate1h           float64 `json:"burn_rate_1h"`
+	BurnRate6h           float64 `json:"burn_rate_6h"`
+	Alerting             bool    `json:"alerting"`
+}
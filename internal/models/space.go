@@ -67,6 +67,9 @@ type SpaceQuotas struct {
 	UsedNotebooks    int   `json:"used_notebooks"`     // Current notebook count
 	UsedDocuments    int   `json:"used_documents"`     // Current document count
 	UsedStorageBytes int64 `json:"used_storage_bytes"` // Current storage usage
+
+	MaxAgentExecutions int64 `json:"max_agent_executions"` // Agent executions per calendar month
+	MaxStreamEvents    int64 `json:"max_stream_events"`    // Stream events ingested per calendar month
 }
 
 // SpaceMemberDTO represents space membership for API responses
@@ -144,6 +147,27 @@ type SpaceMembersListResponse struct {
 	HasMore bool                   `json:"hasMore"`
 }
 
+// TenantLookupKey is the identifier a tenant is looked up by
+type TenantLookupKey string
+
+const (
+	TenantLookupByTenantID          TenantLookupKey = "tenant_id"
+	TenantLookupBySpaceID           TenantLookupKey = "space_id"
+	TenantLookupByAudimodalTenantID TenantLookupKey = "audimodal_tenant_id"
+)
+
+// TenantInfo identifies a tenant across aether, AudiModal and DeepLake
+type TenantInfo struct {
+	TenantID          string      `json:"tenant_id"`
+	SpaceID           string      `json:"space_id"`
+	SpaceType         SpaceType   `json:"space_type"`
+	Name              string      `json:"name"`
+	OwnerID           string      `json:"owner_id"`
+	AudimodalTenantID string      `json:"audimodal_tenant_id,omitempty"`
+	DeeplakeNamespace string      `json:"deeplake_namespace,omitempty"`
+	Status            SpaceStatus `json:"status"`
+}
+
 // NewSpace creates a new Space with default values
 func NewSpace(name, description string, spaceType SpaceType, ownerID string, ownerType SpaceOwnerType) *Space {
 	now := time.Now()
@@ -188,20 +212,24 @@ func NewOrganizationSpace(orgID, orgName string) *Space {
 func DefaultSpaceQuotas(spaceType SpaceType) *SpaceQuotas {
 	if spaceType == SpaceTypePersonal {
 		return &SpaceQuotas{
-			MaxNotebooks:    100,
-			MaxDocuments:    1000,
-			MaxStorageBytes: 5 * 1024 * 1024 * 1024, // 5 GB
-			MaxMembersCount: 1,                      // Personal space is single-user
-			MaxTeamsCount:   0,
+			MaxNotebooks:       100,
+			MaxDocuments:       1000,
+			MaxStorageBytes:    5 * 1024 * 1024 * 1024, // 5 GB
+			MaxMembersCount:    1,                      // Personal space is single-user
+			MaxTeamsCount:      0,
+			MaxAgentExecutions: 1000,
+			MaxStreamEvents:    100000,
 		}
 	}
 	// Organization space defaults
 	return &SpaceQuotas{
-		MaxNotebooks:    1000,
-		MaxDocuments:    10000,
-		MaxStorageBytes: 50 * 1024 * 1024 * 1024, // 50 GB
-		MaxMembersCount: 100,
-		MaxTeamsCount:   50,
+		MaxNotebooks:       1000,
+		MaxDocuments:       10000,
+		MaxStorageBytes:    50 * 1024 * 1024 * 1024, // 50 GB
+		MaxMembersCount:    100,
+		MaxTeamsCount:      50,
+		MaxAgentExecutions: 20000,
+		MaxStreamEvents:    2000000,
 	}
 }
 
@@ -248,6 +276,30 @@ func (s *Space) Update(req SpaceUpdateRequest) {
 	if req.Visibility != nil {
 		s.Visibility = *req.Visibility
 	}
+	if req.OCR != nil {
+		if s.Settings == nil {
+			s.Settings = make(map[string]interface{})
+		}
+		s.Settings[OCRSettingsKey] = req.OCR
+	}
+	if req.Processing != nil {
+		if s.Settings == nil {
+			s.Settings = make(map[string]interface{})
+		}
+		s.Settings[ProcessingSettingsKey] = req.Processing
+	}
+	if req.Scanning != nil {
+		if s.Settings == nil {
+			s.Settings = make(map[string]interface{})
+		}
+		s.Settings[ScanSettingsKey] = req.Scanning
+	}
+	if req.FileValidation != nil {
+		if s.Settings == nil {
+			s.Settings = make(map[string]interface{})
+		}
+		s.Settings[FileValidationSettingsKey] = req.FileValidation
+	}
 	s.UpdatedAt = time.Now()
 }
 
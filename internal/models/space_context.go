@@ -1,6 +1,7 @@
 package models
 
 import (
+	"context"
 	"time"
 )
 
@@ -22,6 +23,12 @@ type SpaceContext struct {
 	TenantID string `json:"tenant_id"`
 	APIKey   string `json:"-"` // Not serialized
 
+	// Billing plan of the tenant; processing rate limits are set per plan
+	Plan string `json:"plan,omitempty"`
+
+	// Organization whose custom roles apply; empty for personal spaces
+	OrganizationID string `json:"organization_id,omitempty"`
+
 	// User context within the space
 	UserID   string `json:"user_id"`
 	UserRole string `json:"user_role"` // "owner" for personal, org role for organization
@@ -30,6 +37,10 @@ type SpaceContext struct {
 	SpaceName   string    `json:"space_name"`
 	ResolvedAt  time.Time `json:"resolved_at"`
 	Permissions []string  `json:"permissions"`
+
+	// Authorizer decides what UserRole grants in the space, custom roles
+	// included; without one only the built-in roles grant actions
+	Authorizer Authorizer `json:"-"`
 }
 
 // SpaceContextRequest represents a request to resolve a space context
@@ -58,24 +69,45 @@ func (sc *SpaceContext) HasPermission(permission string) bool {
 	return false
 }
 
+// can reports whether the user's role grants action in the space
+func (sc *SpaceContext) can(action string) bool {
+	subject := sc.Subject()
+	if sc.Authorizer == nil {
+		for _, role := range subject.Roles {
+			if Grants(BuiltInRoles[role], action) {
+				return true
+			}
+		}
+		return false
+	}
+	resource := Resource{Type: ResourceSpace, ID: sc.SpaceID}
+	return sc.Authorizer.Authorize(context.Background(), subject, action, resource) == nil
+}
+
 // CanCreate checks if the context allows creating resources
 func (sc *SpaceContext) CanCreate() bool {
-	return sc.HasPermission("create") || sc.HasPermission("write") || sc.UserRole == "owner" || sc.UserRole == "admin"
+	return sc.can(ActionDocumentCreate)
 }
 
 // CanRead checks if the context allows reading resources
 func (sc *SpaceContext) CanRead() bool {
-	return sc.HasPermission("read") || sc.CanCreate()
+	return sc.can(ActionDocumentRead)
 }
 
 // CanUpdate checks if the context allows updating resources
 func (sc *SpaceContext) CanUpdate() bool {
-	return sc.HasPermission("update") || sc.HasPermission("write") || sc.UserRole == "owner" || sc.UserRole == "admin"
+	return sc.can(ActionDocumentUpdate)
+}
+
+// CanManage checks if the context allows managing the space's settings,
+// such as its classification policies
+func (sc *SpaceContext) CanManage() bool {
+	return sc.can(ActionSpaceUpdate)
 }
 
 // CanDelete checks if the context allows deleting resources
 func (sc *SpaceContext) CanDelete() bool {
-	return sc.HasPermission("delete") || sc.UserRole == "owner" || sc.UserRole == "admin"
+	return sc.can(ActionDocumentDelete)
 }
 
 // GetTenantInfo returns the tenant ID and API key for this space
@@ -127,6 +159,18 @@ type SpaceUpdateRequest struct {
 	Name        *string `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
 	Description *string `json:"description,omitempty" validate:"omitempty,max=500"`
 	Visibility  *string `json:"visibility,omitempty" validate:"omitempty,oneof=private team organization public"`
+	// OCR replaces the space's OCR settings, used for documents uploaded
+	// or reprocessed afterwards
+	OCR *OCRSettings `json:"ocr,omitempty"`
+	// Processing replaces the space's processing settings, used for
+	// documents uploaded or reprocessed afterwards
+	Processing *ProcessingSettings `json:"processing,omitempty"`
+	// Scanning replaces the space's malware scanning settings, used for
+	// files uploaded afterwards
+	Scanning *ScanSettings `json:"scanning,omitempty"`
+	// FileValidation replaces the space's upload restrictions, used for
+	// files uploaded afterwards
+	FileValidation *FileValidationSettings `json:"file_validation,omitempty"`
 }
 
 // SpaceResponse represents a space creation/update response
@@ -0,0 +1,20 @@
+package models
+
+// SpaceEncryptionUpdateRequest turns the encryption at rest of the
+// extracted text of a space's documents on or off
+type SpaceEncryptionUpdateRequest struct {
+	Enabled *bool `json:"enabled" validate:"required"`
+}
+
+// SpaceEncryption is the encryption at rest of the extracted text of a
+// space's documents, with the progress of migrating them to the setting
+type SpaceEncryption struct {
+	SpaceID string `json:"space_id"`
+	Enabled bool   `json:"enabled"`
+	// Migrated counts the documents whose text the request encrypted, or
+	// decrypted when encryption was turned off
+	Migrated int `json:"migrated"`
+	// Remaining counts the documents still to migrate; repeating the
+	// request carries on with them
+	Remaining int `json:"remaining"`
+}
@@ -89,6 +89,7 @@ type StreamFilters struct {
 	Sentiments   []string `json:"sentiments,omitempty"`
 	Providers    []string `json:"providers,omitempty"`
 	MinConfidence float64  `json:"min_confidence,omitempty"`
+	Filter       string   `json:"filter,omitempty"` // Filter expression, e.g. type:alert AND confidence:>=0.8
 }
 
 // Request models for API endpoints
@@ -108,6 +109,14 @@ type UpdateStreamSourceRequest struct {
 	Configuration map[string]interface{} `json:"configuration"`
 }
 
+// StreamWebhookEvent is an event a stream source pushes to its webhook
+type StreamWebhookEvent struct {
+	EventType string                 `json:"event_type" validate:"required,max=100"`
+	Content   string                 `json:"content" validate:"required"`
+	MediaType string                 `json:"media_type" validate:"required,oneof=text image video audio document"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+}
+
 // StreamEventWebSocketMessage represents a real-time event message sent via WebSocket
 type StreamEventWebSocketMessage struct {
 	Type      string     `json:"type"`      // "live_event", "analytics_update", "stream_status"
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// SummarySubjectType is what a summary summarizes
+type SummarySubjectType string
+
+// Summary subjects
+const (
+	// SummaryDocument is a document abstract, written from its extracted text
+	SummaryDocument SummarySubjectType = "document"
+	// SummaryNotebook is a notebook digest, written from the abstracts, or
+	// the start of the text, of its processed documents
+	SummaryNotebook SummarySubjectType = "notebook"
+)
+
+// Summary is an LLM-written summary of a document or notebook. It is cached
+// on the subject's node and only written again when refreshed, or, for a
+// document summarized automatically, when the document is reprocessed.
+type Summary struct {
+	SubjectType SummarySubjectType `json:"subject_type"`
+	SubjectID   string             `json:"subject_id"`
+	Summary     string             `json:"summary"`
+	Model       string             `json:"model"`
+	TokensUsed  int64              `json:"tokens_used"` // Spent writing this summary
+	GeneratedAt time.Time          `json:"generated_at"`
+	// Stale is set when the document was reprocessed, or the notebook's
+	// documents changed, after the summary was written
+	Stale  bool `json:"stale"`
+	Cached bool `json:"cached"` // Served from the cache without calling the model
+}
+
+// SummaryUsage is the tokens a space has spent on summaries in a calendar
+// month (UTC)
+type SummaryUsage struct {
+	SpaceID     string    `json:"space_id"`
+	Period      string    `json:"period"` // YYYY-MM
+	TokensUsed  int64     `json:"tokens_used"`
+	Summaries   int64     `json:"summaries"`
+	TokenBudget int64     `json:"token_budget"` // 0 is unlimited
+	ResetsAt    time.Time `json:"resets_at"`
+}
+
+// Exhausted reports whether the budget leaves no tokens for another summary
+func (u *SummaryUsage) Exhausted() bool {
+	return u.TokenBudget > 0 && u.TokensUsed >= u.TokenBudget
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Synthetic probes, each exercising one critical path of the API
+const (
+	SyntheticProbeLogin       = "login"         // Password login against Keycloak
+	SyntheticProbeUpload      = "upload"        // Upload of a small document, deleted again
+	SyntheticProbeSearch      = "search"        // Document search
+	SyntheticProbeAgentDryRun = "agent_dry_run" // Agent execution without calling the LLM
+)
+
+// SyntheticProbeResult is the outcome of the last run of a probe
+type SyntheticProbeResult struct {
+	Probe      string    `json:"probe"`
+	Success    bool      `json:"success"`
+	Skipped    bool      `json:"skipped,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	CheckedAt  time.Time `json:"checked_at"`
+}
+
+// SyntheticReport lists the last result of every synthetic probe
+type SyntheticReport struct {
+	InstanceID string                 `json:"instance_id"`
+	LastRunAt  *time.Time             `json:"last_run_at,omitempty"`
+	Probes     []SyntheticProbeResult `json:"probes"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// TenantExportManifestVersion is the version of the tenant export bundle
+// layout described by TenantExportManifest
+const TenantExportManifestVersion = 1
+
+// TenantExport is the result of a tenant_export job: a bundle in object
+// storage holding every space, notebook, document, stored file and audit
+// event of a tenant, described by its manifest
+type TenantExport struct {
+	TenantID       string    `json:"tenant_id"`
+	Prefix         string    `json:"prefix"` // Storage key prefix of every file in the bundle
+	ManifestKey    string    `json:"manifest_key"`
+	ManifestSHA256 string    `json:"manifest_sha256"`
+	Spaces         int       `json:"spaces"`
+	Notebooks      int       `json:"notebooks"`
+	Documents      int       `json:"documents"`
+	Files          int       `json:"files"`
+	MissingFiles   int       `json:"missing_files"` // Stored files that could not be read
+	AuditEvents    int       `json:"audit_events"`
+	Bytes          int64     `json:"bytes"`
+	ManifestURL    string    `json:"manifest_url"` // Presigned; valid until ExpiresAt
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+// TenantExportManifest lists the files of a tenant export bundle with
+// their SHA-256 hashes, so the bundle can be checked once copied
+type TenantExportManifest struct {
+	Version   int                `json:"version"`
+	TenantID  string             `json:"tenant_id"`
+	CreatedAt time.Time          `json:"created_at"`
+	Counts    map[string]int     `json:"counts"`
+	Files     []TenantExportFile `json:"files"`
+	Missing   []TenantExportFile `json:"missing,omitempty"` // Stored files that could not be read; they have no hash
+}
+
+// TenantExportFile is a file of a tenant export bundle. Path is relative
+// to the bundle's prefix; document files give the document and the key
+// they were copied from.
+type TenantExportFile struct {
+	Path       string `json:"path"`
+	SHA256     string `json:"sha256,omitempty"`
+	Bytes      int64  `json:"bytes"`
+	DocumentID string `json:"document_id,omitempty"`
+	SourceKey  string `json:"source_key,omitempty"`
+	Error      string `json:"error,omitempty"` // Why a missing file could not be read
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Types of the items in the trash
+const (
+	TrashItemDocument = "document"
+	TrashItemNotebook = "notebook"
+)
+
+// TrashItem is a deleted document or notebook that can still be restored.
+// It is purged, with its files, at PurgeAt.
+type TrashItem struct {
+	Type       string    `json:"type"`
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	NotebookID string    `json:"notebook_id,omitempty"` // Documents only
+	SizeBytes  int64     `json:"size_bytes,omitempty"`  // Documents only
+	DeletedAt  time.Time `json:"deleted_at"`
+	DeletedBy  string    `json:"deleted_by,omitempty"`
+	PurgeAt    time.Time `json:"purge_at"`
+}
+
+// TrashListResponse is a page of the trash of a space, most recently
+// deleted first
+type TrashListResponse struct {
+	Items         []*TrashItem `json:"items"`
+	RetentionDays int          `json:"retention_days"`
+	Limit         int          `json:"limit"`
+	Offset        int          `json:"offset"`
+	HasMore       bool         `json:"has_more"`
+}
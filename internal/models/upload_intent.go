@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// UploadIntentCreateRequest describes a file the client will store itself,
+// with a presigned URL, instead of sending it through the API
+type UploadIntentCreateRequest struct {
+	FileName       string   `json:"file_name" validate:"required,min=1,max=255"`
+	MimeType       string   `json:"mime_type" validate:"required,max=255"`
+	SizeBytes      int64    `json:"size_bytes" validate:"required,min=1"`
+	ChecksumSHA256 string   `json:"checksum_sha256" validate:"required,len=64,hexadecimal"` // Of the whole file, hex encoded
+	Name           string   `json:"name,omitempty" validate:"omitempty,max=255"`            // Default the file name
+	Description    string   `json:"description,omitempty" validate:"omitempty,max=1000"`
+	Tags           []string `json:"tags,omitempty" validate:"omitempty,dive,tag,min=1,max=50"`
+	Priority       string   `json:"priority,omitempty" validate:"omitempty,oneof=low normal high realtime"` // Processing priority tier
+}
+
+// UploadIntent is where and how to store the file of a direct upload. The
+// document stays in status uploading until the upload is confirmed.
+type UploadIntent struct {
+	Document  *DocumentResponse `json:"document"`
+	UploadURL string            `json:"upload_url"`
+	Method    string            `json:"method"`  // Always PUT
+	Headers   map[string]string `json:"headers"` // To send with the file
+	ExpiresAt time.Time         `json:"expires_at"`
+}
@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// UploadSessionStatus is the state of a resumable upload
+type UploadSessionStatus string
+
+// Upload session statuses
+const (
+	// UploadSessionActive accepts parts until it is completed, aborted or
+	// expires
+	UploadSessionActive UploadSessionStatus = "active"
+	// UploadSessionCompleted has assembled its parts into the document's
+	// file and submitted it for processing
+	UploadSessionCompleted UploadSessionStatus = "completed"
+	// UploadSessionAborted was cancelled, or expired before it completed
+	UploadSessionAborted UploadSessionStatus = "aborted"
+)
+
+// UploadSessionCreateRequest starts a resumable upload of one file into a
+// notebook
+type UploadSessionCreateRequest struct {
+	FileName    string   `json:"file_name" validate:"required,min=1,max=255"`
+	MimeType    string   `json:"mime_type,omitempty" validate:"omitempty,max=255"`
+	SizeBytes   int64    `json:"size_bytes" validate:"required,min=1"`
+	Name        string   `json:"name,omitempty" validate:"omitempty,max=255"` // Default the file name
+	Description string   `json:"description,omitempty" validate:"omitempty,max=1000"`
+	Tags        []string `json:"tags,omitempty" validate:"omitempty,dive,tag,min=1,max=50"`
+	Priority    string   `json:"priority,omitempty" validate:"omitempty,oneof=low normal high realtime"` // Processing priority tier
+}
+
+// UploadSessionPart is a part of a resumable upload that has been stored
+type UploadSessionPart struct {
+	Number    int    `json:"number"`
+	SizeBytes int64  `json:"size_bytes"`
+	ETag      string `json:"etag"`
+}
+
+// UploadSession is a resumable upload of one file. The file is sent as
+// PartCount parts of PartSize bytes, the last one possibly shorter, in any
+// order and each as often as needed; Parts lists those stored so far, so
+// an interrupted upload resumes by sending the others.
+type UploadSession struct {
+	ID         string               `json:"id"`
+	DocumentID string               `json:"document_id"`
+	NotebookID string               `json:"notebook_id"`
+	SpaceID    string               `json:"space_id"`
+	TenantID   string               `json:"-"`
+	OwnerID    string               `json:"owner_id"`
+	FileName   string               `json:"file_name"`
+	MimeType   string               `json:"mime_type"`
+	SizeBytes  int64                `json:"size_bytes"`
+	PartSize   int64                `json:"part_size"`
+	PartCount  int                  `json:"part_count"`
+	Status     UploadSessionStatus  `json:"status"`
+	Parts      []*UploadSessionPart `json:"parts"`
+	StorageKey string               `json:"-"`
+	UploadID   string               `json:"-"` // Of the S3 multipart upload
+	CreatedAt  time.Time            `json:"created_at"`
+	UpdatedAt  time.Time            `json:"updated_at"`
+	ExpiresAt  time.Time            `json:"expires_at"`
+}
+
+// PartLength returns the length the given part must have
+func (u *UploadSession) PartLength(number int) int64 {
+	if number < u.PartCount {
+		return u.PartSize
+	}
+	return u.SizeBytes - int64(u.PartCount-1)*u.PartSize
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Vector sync statuses of a document
+const (
+	VectorSyncNone     = "none"     // Not queued since it last finished processing with sync enabled
+	VectorSyncPending  = "pending"  // Waiting to be pushed to DeepLake
+	VectorSyncSyncing  = "syncing"  // Being pushed by the leader replica
+	VectorSyncSynced   = "synced"   // DeepLake holds the document's current chunks
+	VectorSyncFailed   = "failed"   // Gave up after the configured attempts
+	VectorSyncDeleting = "deleting" // The document is gone; its vectors are being removed
+)
+
+// DocumentVectorSync reports how far a document's chunk embeddings have
+// been pushed into its space's DeepLake namespace
+type DocumentVectorSync struct {
+	DocumentID    string     `json:"document_id"`
+	Status        string     `json:"status"`
+	VectorCount   int64      `json:"vector_count"` // Vectors stored by the last successful sync
+	Attempts      int64      `json:"attempts"`     // Failed attempts since the last success
+	Error         string     `json:"error,omitempty"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	SyncedAt      *time.Time `json:"synced_at,omitempty"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
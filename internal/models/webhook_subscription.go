@@ -0,0 +1,115 @@
+package models
+
+import "time"
+
+// Events a webhook subscription can receive
+const (
+	WebhookEventDocumentProcessed = "document.processed"
+	WebhookEventDocumentFailed    = "document.failed"
+	WebhookEventMemberAdded       = "member.added"
+	WebhookEventAgentExecuted     = "agent.executed"
+	WebhookEventQuotaThreshold    = "quota.threshold_reached"
+	// WebhookEventTest is sent by the test delivery endpoint only
+	WebhookEventTest = "webhook.test"
+)
+
+// WebhookEvents are the events subscriptions can choose from
+var WebhookEvents = []string{
+	WebhookEventDocumentProcessed,
+	WebhookEventDocumentFailed,
+	WebhookEventMemberAdded,
+	WebhookEventAgentExecuted,
+	WebhookEventQuotaThreshold,
+}
+
+// Statuses of a webhook delivery
+const (
+	WebhookDeliveryPending    = "pending"    // Waiting for its next attempt
+	WebhookDeliveryDelivering = "delivering" // Being sent by the leader replica
+	WebhookDeliverySucceeded  = "succeeded"  // The subscriber answered 2xx
+	WebhookDeliveryFailed     = "failed"     // Gave up after the configured attempts
+)
+
+// WebhookSubscriptionCreateRequest subscribes a URL to events of the
+// current space
+type WebhookSubscriptionCreateRequest struct {
+	URL         string   `json:"url" validate:"required,url,max=2048"`
+	Events      []string `json:"events" validate:"required,min=1,max=5,dive,oneof=document.processed document.failed member.added agent.executed quota.threshold_reached"`
+	Description string   `json:"description,omitempty" validate:"max=500"`
+}
+
+// WebhookSubscriptionUpdateRequest changes a webhook subscription. Omitted
+// fields are left as they are.
+type WebhookSubscriptionUpdateRequest struct {
+	URL         *string  `json:"url,omitempty" validate:"omitempty,url,max=2048"`
+	Events      []string `json:"events,omitempty" validate:"omitempty,min=1,max=5,dive,oneof=document.processed document.failed member.added agent.executed quota.threshold_reached"`
+	Description *string  `json:"description,omitempty" validate:"omitempty,max=500"`
+	// Active false pauses the subscription: new events are not queued for
+	// it, and queued ones wait until it is active again
+	Active *bool `json:"active,omitempty"`
+}
+
+// WebhookSubscription delivers events of a space to a URL, signed with
+// the subscription's secret
+type WebhookSubscription struct {
+	ID          string   `json:"id"`
+	SpaceID     string   `json:"space_id"`
+	TenantID    string   `json:"-"`
+	URL         string   `json:"url"`
+	Events      []string `json:"events"`
+	Description string   `json:"description,omitempty"`
+	Active      bool     `json:"active"`
+	// Secret signs deliveries. It is only returned when the subscription
+	// is created.
+	Secret         string     `json:"secret,omitempty"`
+	LastDeliveryAt *time.Time `json:"last_delivery_at,omitempty"`
+	LastStatus     string     `json:"last_status,omitempty"`
+	CreatedBy      string     `json:"created_by"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// WebhookSubscriptionList is the webhook subscriptions of a space
+type WebhookSubscriptionList struct {
+	Subscriptions []*WebhookSubscription `json:"subscriptions"`
+}
+
+// WebhookDelivery is one event sent, or to be sent, to a subscription,
+// with the outcome of its latest attempt
+type WebhookDelivery struct {
+	ID             string `json:"id"` // Sent as X-Webhook-ID; the same on every attempt
+	SubscriptionID string `json:"subscription_id"`
+	EventID        string `json:"event_id"`
+	Event          string `json:"event"`
+	Status         string `json:"status"`
+	Test           bool   `json:"test,omitempty"`
+	Attempts       int64  `json:"attempts"`
+	// ResponseStatus and ResponseBody are the subscriber's answer to the
+	// latest attempt; the body is cut at 1 KiB
+	ResponseStatus int64      `json:"response_status,omitempty"`
+	ResponseBody   string     `json:"response_body,omitempty"`
+	Error          string     `json:"error,omitempty"`
+	DurationMs     int64      `json:"duration_ms,omitempty"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	LastAttemptAt  *time.Time `json:"last_attempt_at,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// WebhookDeliveryList is a page of a subscription's deliveries, newest
+// first
+type WebhookDeliveryList struct {
+	Deliveries []*WebhookDelivery `json:"deliveries"`
+	Limit      int                `json:"limit"`
+	Offset     int                `json:"offset"`
+	HasMore    bool               `json:"has_more"`
+}
+
+// WebhookPayload is the JSON body of a delivery
+type WebhookPayload struct {
+	ID        string                 `json:"id"` // Event ID; the same for every subscription the event is delivered to
+	Event     string                 `json:"event"`
+	SpaceID   string                 `json:"space_id"`
+	Timestamp time.Time              `json:"timestamp"`
+	Data      map[string]interface{} `json:"data"`
+}
@@ -0,0 +1,508 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ClientFile is where the generated client is written, relative to the
+// repository root
+const ClientFile = "pkg/client/api_gen.go"
+
+// initialisms are the words written in capitals in Go identifiers
+var initialisms = map[string]bool{
+	"api": true, "csv": true, "dlp": true, "html": true, "http": true, "id": true,
+	"ids": true, "ip": true, "json": true, "llm": true, "ml": true, "pii": true,
+	"sql": true, "ttl": true, "ui": true, "uri": true, "url": true, "urls": true,
+	"uuid": true,
+}
+
+// goKeywords cannot be used as parameter names
+var goKeywords = map[string]bool{
+	"break": true, "case": true, "chan": true, "const": true, "continue": true,
+	"default": true, "defer": true, "else": true, "fallthrough": true, "for": true,
+	"func": true, "go": true, "goto": true, "if": true, "import": true,
+	"interface": true, "map": true, "package": true, "range": true, "return": true,
+	"select": true, "struct": true, "switch": true, "type": true, "var": true,
+}
+
+// GenerateClient writes the Go client of the document: a type for each
+// component schema and a method on Client for each operation. Multipart
+// uploads and WebSocket streams have no generated method; the client
+// package implements them by hand.
+func GenerateClient(doc *Document) ([]byte, error) {
+	g := &clientGenerator{doc: doc, names: make(map[string]string), imports: map[string]bool{"context": true}}
+	for key := range doc.Components.Schemas {
+		_, name, _ := strings.Cut(key, ".")
+		g.names[key] = exportedName(name)
+	}
+
+	g.printf("// APIVersion is the version of the API the client was generated from\n")
+	g.printf("const APIVersion = %q\n\n", doc.Info.Version)
+
+	keys := make([]string, 0, len(doc.Components.Schemas))
+	for key := range doc.Components.Schemas {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return g.names[keys[i]] < g.names[keys[j]] })
+	for _, key := range keys {
+		if err := g.schemaType(key, doc.Components.Schemas[key]); err != nil {
+			return nil, err
+		}
+	}
+
+	type route struct {
+		path, method string
+		op           *Operation
+	}
+	var routes []route
+	for path, item := range doc.Paths {
+		for method, op := range item {
+			routes = append(routes, route{path, method, op})
+		}
+	}
+	sort.Slice(routes, func(i, j int) bool { return routes[i].op.OperationID < routes[j].op.OperationID })
+	for _, r := range routes {
+		if err := g.operation(r.path, r.method, r.op); err != nil {
+			return nil, fmt.Errorf("%s %s: %w", strings.ToUpper(r.method), r.path, err)
+		}
+	}
+
+	var file bytes.Buffer
+	file.WriteString("// Code generated by cmd/openapi from the handler annotations. DO NOT EDIT.\n\n")
+	file.WriteString("package client\n\nimport (\n")
+	imports := make([]string, 0, len(g.imports))
+	for path := range g.imports {
+		imports = append(imports, path)
+	}
+	sort.Strings(imports)
+	for _, path := range imports {
+		fmt.Fprintf(&file, "\t%q\n", path)
+	}
+	file.WriteString(")\n\n")
+	file.Write(g.buf.Bytes())
+
+	source, err := format.Source(file.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format client: %w", err)
+	}
+	return source, nil
+}
+
+type clientGenerator struct {
+	doc     *Document
+	names   map[string]string // Component schema keys to Go type names
+	imports map[string]bool
+	buf     bytes.Buffer
+}
+
+func (g *clientGenerator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// schemaType declares the Go type of a component schema
+func (g *clientGenerator) schemaType(key string, schema *Schema) error {
+	name := g.names[key]
+	if schema.Description != "" && strings.HasPrefix(schema.Description, name+" ") {
+		g.comment("", schema.Description)
+	} else {
+		g.comment("", fmt.Sprintf("%s is the %s schema. %s", name, key, schema.Description))
+	}
+
+	if schema.Type == "string" && len(schema.Enum) > 0 {
+		g.printf("type %s string\n\n", name)
+		g.printf("const (\n")
+		used := make(map[string]bool)
+		for i, value := range schema.Enum {
+			constName := name + exportedName(value)
+			if constName == name || used[constName] {
+				constName = fmt.Sprintf("%sValue%d", name, i)
+			}
+			used[constName] = true
+			g.printf("\t%s %s = %q\n", constName, name, value)
+		}
+		g.printf(")\n\n")
+		return nil
+	}
+
+	goType, err := g.goType(schema, false)
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	g.printf("type %s %s\n\n", name, goType)
+	return nil
+}
+
+// goType returns the Go type of a schema. Structs referenced from fields,
+// slices and maps are pointers, as in the models.
+func (g *clientGenerator) goType(schema *Schema, optional bool) (string, error) {
+	if schema.Ref != "" {
+		key := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		name, ok := g.names[key]
+		if !ok {
+			return "", fmt.Errorf("unknown schema %s", schema.Ref)
+		}
+		if g.isStruct(g.doc.Components.Schemas[key]) {
+			return "*" + name, nil
+		}
+		return name, nil
+	}
+
+	switch schema.Type {
+	case "string":
+		switch schema.Format {
+		case "date-time":
+			g.imports["time"] = true
+			if optional {
+				return "*time.Time", nil
+			}
+			return "time.Time", nil
+		case "byte", "binary":
+			return "[]byte", nil
+		}
+		return "string", nil
+	case "boolean":
+		return "bool", nil
+	case "integer":
+		if schema.Format == "int64" {
+			return "int64", nil
+		}
+		return "int", nil
+	case "number":
+		if schema.Format == "float" {
+			return "float32", nil
+		}
+		return "float64", nil
+	case "array":
+		if schema.Items == nil {
+			return "[]interface{}", nil
+		}
+		items, err := g.goType(schema.Items, false)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + items, nil
+	case "object":
+		if len(schema.Properties) > 0 {
+			return g.structType(schema)
+		}
+		if schema.AdditionalProperties != nil {
+			values, err := g.goType(schema.AdditionalProperties, false)
+			if err != nil {
+				return "", err
+			}
+			return "map[string]" + values, nil
+		}
+		return "map[string]interface{}", nil
+	case "":
+		return "interface{}", nil
+	}
+	return "", fmt.Errorf("unsupported schema type %q", schema.Type)
+}
+
+func (g *clientGenerator) isStruct(schema *Schema) bool {
+	return schema != nil && schema.Type == "object" && len(schema.Properties) > 0
+}
+
+// structType returns a struct with a field per property, in name order
+func (g *clientGenerator) structType(schema *Schema) (string, error) {
+	required := make(map[string]bool, len(schema.Required))
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("struct {\n")
+	used := make(map[string]bool)
+	for _, jsonName := range names {
+		property := schema.Properties[jsonName]
+		fieldType, err := g.goType(property, !required[jsonName])
+		if err != nil {
+			return "", fmt.Errorf("property %s: %w", jsonName, err)
+		}
+		field := exportedName(jsonName)
+		for n := 2; used[field]; n++ {
+			field = fmt.Sprintf("%s%d", exportedName(jsonName), n)
+		}
+		used[field] = true
+
+		tag := jsonName
+		if !required[jsonName] {
+			tag += ",omitempty"
+		}
+		if property.Description != "" {
+			for _, line := range wrap(property.Description, 72) {
+				b.WriteString("\t// " + line + "\n")
+			}
+		}
+		fmt.Fprintf(&b, "\t%s %s `json:%q`\n", field, fieldType, tag)
+	}
+	b.WriteString("}")
+	return b.String(), nil
+}
+
+// operation writes the method calling an operation, and the struct of its
+// query parameters
+func (g *clientGenerator) operation(path, method string, op *Operation) error {
+	response, kind, err := g.successResponse(op)
+	if err != nil {
+		return err
+	}
+	if kind == "" {
+		// WebSocket upgrades document no response the client can decode
+		return nil
+	}
+	if op.RequestBody != nil {
+		if _, ok := op.RequestBody.Content["application/json"]; !ok {
+			// Multipart uploads are implemented by hand
+			return nil
+		}
+	}
+
+	name := op.OperationID
+	args := []string{"ctx context.Context"}
+	used := map[string]bool{"ctx": true, "params": true, "body": true, "out": true, "resp": true, "err": true}
+	pathExpr := strconv.Quote(path)
+	var query []*Parameter
+	for _, param := range op.Parameters {
+		switch param.In {
+		case "path":
+			arg := unexportedName(param.Name)
+			for used[arg] || goKeywords[arg] {
+				arg += "Param"
+			}
+			used[arg] = true
+			args = append(args, arg+" string")
+			pathExpr = strings.Replace(pathExpr, "{"+param.Name+"}", `"+url.PathEscape(`+arg+`)+"`, 1)
+			g.imports["net/url"] = true
+		case "query":
+			query = append(query, param)
+		}
+	}
+	pathExpr = strings.TrimSuffix(pathExpr, `+""`)
+
+	paramsArg := "nil"
+	if len(query) > 0 {
+		if err := g.paramsType(name, query); err != nil {
+			return err
+		}
+		args = append(args, "params *"+name+"Params")
+		paramsArg = "params"
+	}
+
+	bodyArg := "nil"
+	if op.RequestBody != nil {
+		bodyType, err := g.goType(op.RequestBody.Content["application/json"].Schema, false)
+		if err != nil {
+			return fmt.Errorf("request body: %w", err)
+		}
+		args = append(args, "body "+strings.TrimPrefix(bodyType, "*"))
+		bodyArg = "body"
+	}
+
+	doc := fmt.Sprintf("%s calls %s %s.", name, strings.ToUpper(method), path)
+	if summary := strings.TrimSuffix(op.Summary, "."); summary != "" {
+		doc += "\n\n" + summary + "."
+		if op.Description != "" && op.Description != op.Summary {
+			doc += " " + op.Description
+		}
+	}
+	g.comment("", doc)
+
+	g.imports["net/http"] = true
+	methodConst := "http.Method" + strings.ToUpper(method[:1]) + method[1:]
+	signature := fmt.Sprintf("func (c *Client) %s(%s)", name, strings.Join(args, ", "))
+	call := fmt.Sprintf("%s, %s, %s", methodConst, pathExpr, paramsArg)
+
+	switch kind {
+	case "none":
+		g.printf("%s error {\n", signature)
+		g.printf("\treturn c.do(ctx, %s, %s, nil)\n}\n\n", call, bodyArg)
+	case "json":
+		goType, err := g.goType(response, false)
+		if err != nil {
+			return fmt.Errorf("response: %w", err)
+		}
+		if strings.HasPrefix(goType, "*") {
+			g.printf("%s (%s, error) {\n", signature, goType)
+			g.printf("\tout := new(%s)\n", goType[1:])
+			g.printf("\tif err := c.do(ctx, %s, %s, out); err != nil {\n\t\treturn nil, err\n\t}\n", call, bodyArg)
+			g.printf("\treturn out, nil\n}\n\n")
+		} else {
+			g.printf("%s (%s, error) {\n", signature, goType)
+			g.printf("\tvar out %s\n", goType)
+			g.printf("\tif err := c.do(ctx, %s, %s, &out); err != nil {\n\t\treturn nil, err\n\t}\n", call, bodyArg)
+			g.printf("\treturn out, nil\n}\n\n")
+		}
+	case "ndjson":
+		goType, err := g.goType(response, false)
+		if err != nil {
+			return fmt.Errorf("response: %w", err)
+		}
+		itemType := strings.TrimPrefix(goType, "*")
+		g.printf("%s (*NDJSONStream[%s], error) {\n", signature, itemType)
+		g.printf("\tresp, err := c.stream(ctx, %s)\n\tif err != nil {\n\t\treturn nil, err\n\t}\n", call)
+		g.printf("\treturn newNDJSONStream[%s](resp.Body), nil\n}\n\n", itemType)
+	case "raw":
+		g.imports["io"] = true
+		g.printf("%s (io.ReadCloser, error) {\n", signature)
+		g.printf("\tresp, err := c.stream(ctx, %s)\n\tif err != nil {\n\t\treturn nil, err\n\t}\n", call)
+		g.printf("\treturn resp.Body, nil\n}\n\n")
+	}
+	return nil
+}
+
+// successResponse returns the schema of the first 2xx response and how its
+// body is read: "json", "ndjson", "raw" for other content, "none" without
+// a body, or "" when no 2xx response is documented. A 202 is only used
+// when it is the sole success: the client calls operations that can also
+// run as async jobs synchronously.
+func (g *clientGenerator) successResponse(op *Operation) (*Schema, string, error) {
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		return nil, "", nil
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		if (codes[i] == "202") != (codes[j] == "202") {
+			return codes[j] == "202"
+		}
+		return codes[i] < codes[j]
+	})
+	response := op.Responses[codes[0]]
+	if len(response.Content) == 0 {
+		return nil, "none", nil
+	}
+	if media, ok := response.Content["application/json"]; ok {
+		return media.Schema, "json", nil
+	}
+	if media, ok := response.Content["application/x-ndjson"]; ok && len(response.Content) == 1 {
+		return media.Schema, "ndjson", nil
+	}
+	return nil, "raw", nil
+}
+
+// paramsType declares the struct of the query parameters of an operation
+func (g *clientGenerator) paramsType(name string, params []*Parameter) error {
+	g.comment("", fmt.Sprintf("%sParams are the query parameters of %s. Zero values are not sent unless the parameter is required.", name, name))
+	g.printf("type %sParams struct {\n", name)
+	for _, param := range params {
+		goType, err := g.goType(param.Schema, false)
+		if err != nil {
+			return fmt.Errorf("parameter %s: %w", param.Name, err)
+		}
+		if param.Description != "" {
+			g.comment("\t", param.Description)
+		}
+		tag := param.Name
+		if param.Required {
+			tag += ",required"
+		}
+		g.printf("\t%s %s `query:%q`\n", exportedName(param.Name), goType, tag)
+	}
+	g.printf("}\n\n")
+	return nil
+}
+
+// comment writes text as a comment, wrapped and with paragraphs kept
+func (g *clientGenerator) comment(indent, text string) {
+	for _, paragraph := range strings.Split(strings.TrimSpace(text), "\n") {
+		if paragraph == "" {
+			g.printf("%s//\n", indent)
+			continue
+		}
+		for _, line := range wrap(paragraph, 76-len(indent)) {
+			g.printf("%s// %s\n", indent, line)
+		}
+	}
+}
+
+// wrap splits text into lines of at most width characters
+func wrap(text string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && len(line)+1+len(word) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// exportedName turns a JSON, parameter or enum name into an exported Go
+// identifier, e.g. notebook_id and notebookId become NotebookID
+func exportedName(name string) string {
+	var b strings.Builder
+	for _, word := range splitWords(name) {
+		if initialisms[strings.ToLower(word)] {
+			word = strings.ToUpper(word)
+			if word == "IDS" || word == "URLS" {
+				word = word[:len(word)-1] + "s"
+			}
+		} else {
+			word = strings.ToUpper(word[:1]) + word[1:]
+		}
+		b.WriteString(word)
+	}
+	if b.Len() == 0 || unicode.IsDigit(rune(b.String()[0])) {
+		return "N" + b.String()
+	}
+	return b.String()
+}
+
+// unexportedName is exportedName with a lowercase first word
+func unexportedName(name string) string {
+	words := splitWords(name)
+	if len(words) == 0 {
+		return "param"
+	}
+	first := strings.ToLower(words[0])
+	return first + strings.TrimPrefix(exportedName(name), exportedName(words[0]))
+}
+
+// splitWords splits a name at separators and lower-to-upper case changes
+func splitWords(name string) []string {
+	var words []string
+	var word []rune
+	runes := []rune(name)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(word) > 0 {
+				words = append(words, string(word))
+				word = nil
+			}
+			continue
+		}
+		if unicode.IsUpper(r) && len(word) > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])) {
+			words = append(words, string(word))
+			word = nil
+		}
+		word = append(word, r)
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+	return words
+}
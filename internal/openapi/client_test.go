@@ -0,0 +1,96 @@
+package openapi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClientIsUpToDate fails when the spec changed without regenerating
+// the Go client
+func TestClientIsUpToDate(t *testing.T) {
+	doc, err := Generate("../..")
+	require.NoError(t, err)
+	source, err := GenerateClient(doc)
+	require.NoError(t, err)
+	current, err := os.ReadFile(filepath.Join("../..", ClientFile))
+	require.NoError(t, err)
+	assert.True(t, string(source) == string(current), "%s is out of date; run make generate", ClientFile)
+}
+
+func TestGenerateClientWritesTypesAndMethods(t *testing.T) {
+	doc := &Document{
+		Info: Info{Version: "2.0"},
+		Paths: map[string]map[string]*Operation{
+			"/api/v1/widgets/{id}": {
+				"get": &Operation{
+					OperationID: "GetWidget",
+					Summary:     "Get widget",
+					Parameters: []*Parameter{
+						{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "string"}},
+						{Name: "include_parts", In: "query", Schema: &Schema{Type: "boolean"}},
+					},
+					Responses: map[string]*Response{
+						"200": {Content: map[string]MediaType{"application/json": {Schema: &Schema{Ref: "#/components/schemas/models.Widget"}}}},
+					},
+				},
+				"delete": &Operation{
+					OperationID: "DeleteWidget",
+					Parameters:  []*Parameter{{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "string"}}},
+					Responses: map[string]*Response{
+						"202": {Content: map[string]MediaType{"application/json": {Schema: &Schema{Ref: "#/components/schemas/models.Widget"}}}},
+						"204": {},
+					},
+				},
+			},
+			"/api/v1/widgets/{id}/stream": {
+				"get": &Operation{OperationID: "StreamWidget", Responses: map[string]*Response{"101": {}}},
+			},
+		},
+		Components: Components{Schemas: map[string]*Schema{
+			"models.Widget": {
+				Type:     "object",
+				Required: []string{"id"},
+				Properties: map[string]*Schema{
+					"id":         {Type: "string"},
+					"status":     {Ref: "#/components/schemas/models.WidgetStatus"},
+					"created_at": {Type: "string", Format: "date-time"},
+				},
+			},
+			"models.WidgetStatus": {Type: "string", Enum: []string{"active", "archived"}},
+		}},
+	}
+
+	source, err := GenerateClient(doc)
+	require.NoError(t, err)
+	code := string(source)
+
+	assert.Contains(t, code, `const APIVersion = "2.0"`)
+	assert.Contains(t, code, "WidgetStatusActive   WidgetStatus = \"active\"")
+	assert.Contains(t, code, "CreatedAt *time.Time   `json:\"created_at,omitempty\"`")
+	assert.Contains(t, code, "ID        string       `json:\"id\"`")
+	assert.Contains(t, code, "IncludeParts bool `query:\"include_parts\"`")
+	assert.Contains(t, code, "func (c *Client) GetWidget(ctx context.Context, id string, params *GetWidgetParams) (*Widget, error) {")
+	assert.Contains(t, code, `c.do(ctx, http.MethodGet, "/api/v1/widgets/"+url.PathEscape(id), params, nil, out)`)
+	// The async variant of an operation is not what the client waits for
+	assert.Contains(t, code, "func (c *Client) DeleteWidget(ctx context.Context, id string) error {")
+	// WebSocket upgrades are written by hand
+	assert.NotContains(t, code, "StreamWidget")
+}
+
+func TestExportedName(t *testing.T) {
+	for name, want := range map[string]string{
+		"notebook_id":  "NotebookID",
+		"notebookId":   "NotebookID",
+		"document_ids": "DocumentIDs",
+		"pii_detected": "PIIDetected",
+		"Content-Type": "ContentType",
+		"2fa":          "N2fa",
+	} {
+		assert.Equal(t, want, exportedName(name), name)
+	}
+	assert.Equal(t, "userID", unexportedName("userId"))
+}
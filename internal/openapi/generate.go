@@ -0,0 +1,786 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// packageDirs maps the package names used in annotations to their
+// directories, relative to the repository root. Unqualified type names refer
+// to the handlers package.
+var packageDirs = map[string]string{
+	"handlers": "internal/handlers",
+	"models":   "internal/models",
+	"services": "internal/services",
+	"config":   "internal/config",
+	"errors":   "pkg/errors",
+}
+
+const (
+	handlersPackage = "handlers"
+	mainFile        = "cmd/server/main.go"
+)
+
+// mimeTypes expands the short names accepted by @Accept and @Produce
+var mimeTypes = map[string]string{
+	"json":                  "application/json",
+	"plain":                 "text/plain",
+	"html":                  "text/html",
+	"mpfd":                  "multipart/form-data",
+	"x-www-form-urlencoded": "application/x-www-form-urlencoded",
+	"octet-stream":          "application/octet-stream",
+}
+
+var (
+	routerPattern    = regexp.MustCompile(`^(\S+)\s+\[(\w+)\]$`)
+	paramPattern     = regexp.MustCompile(`^(\S+)\s+(\w+)\s+(\S+)\s+(true|false)(?:\s+"([^"]*)")?`)
+	responsePattern  = regexp.MustCompile(`^(\d{3})(?:\s+\{(\w+)\}\s+(\S+))?(?:\s+"([^"]*)")?`)
+	pathParamPattern = regexp.MustCompile(`\{(\w+)\}`)
+)
+
+// Generate builds the OpenAPI document from the swag-style annotations on
+// the handlers in the repository rooted at root. Request and response types
+// named in the annotations are turned into schemas from their Go
+// definitions, so the document follows the models as they change.
+func Generate(root string) (*Document, error) {
+	g := &generator{
+		root:     root,
+		fset:     token.NewFileSet(),
+		packages: make(map[string]*typeIndex),
+		doc: &Document{
+			OpenAPI: "3.1.0",
+			Servers: []Server{{URL: "/"}},
+			Paths:   make(map[string]map[string]*Operation),
+			Components: Components{
+				Schemas: make(map[string]*Schema),
+			},
+		},
+		operationIDs: make(map[string]bool),
+	}
+
+	if err := g.parseGeneralInfo(); err != nil {
+		return nil, err
+	}
+	if err := g.parseOperations(); err != nil {
+		return nil, err
+	}
+	return g.doc, nil
+}
+
+// Marshal encodes the document as indented JSON ending in a newline
+func Marshal(doc *Document) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type generator struct {
+	root         string
+	fset         *token.FileSet
+	packages     map[string]*typeIndex
+	doc          *Document
+	operationIDs map[string]bool
+}
+
+// typeIndex holds the type declarations of one package and the string
+// constants declared for each type
+type typeIndex struct {
+	types map[string]*ast.TypeSpec
+	docs  map[string]string
+	enums map[string][]string
+}
+
+// parseGeneralInfo reads the API title, version and security schemes from
+// the annotations in the server's main file
+func (g *generator) parseGeneralInfo() error {
+	file, err := parser.ParseFile(g.fset, filepath.Join(g.root, mainFile), nil, parser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", mainFile, err)
+	}
+
+	var scheme *SecurityScheme
+	for _, group := range file.Comments {
+		for _, line := range annotationLines(group) {
+			key, value := splitAnnotation(line)
+			switch key {
+			case "@title":
+				g.doc.Info.Title = value
+			case "@version":
+				g.doc.Info.Version = value
+			case "@description":
+				target := &g.doc.Info.Description
+				if scheme != nil {
+					target = &scheme.Description
+				}
+				*target = strings.TrimSpace(*target + " " + value)
+			case "@securityDefinitions.apikey":
+				scheme = &SecurityScheme{Type: "apiKey"}
+				if g.doc.Components.SecuritySchemes == nil {
+					g.doc.Components.SecuritySchemes = make(map[string]*SecurityScheme)
+				}
+				g.doc.Components.SecuritySchemes[value] = scheme
+			case "@in":
+				if scheme != nil {
+					scheme.In = value
+				}
+			case "@name":
+				if scheme != nil {
+					scheme.Name = value
+				}
+			}
+		}
+	}
+
+	if g.doc.Info.Title == "" || g.doc.Info.Version == "" {
+		return fmt.Errorf("%s must declare @title and @version", mainFile)
+	}
+	return nil
+}
+
+// parseOperations reads every annotated handler function
+func (g *generator) parseOperations() error {
+	files, err := g.parseDir(packageDirs[handlersPackage])
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		attached := make(map[*ast.CommentGroup]bool)
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Doc == nil {
+				continue
+			}
+			lines := annotationLines(fn.Doc)
+			if !hasAnnotation(lines, "@Router") {
+				continue
+			}
+			attached[fn.Doc] = true
+			pos := g.fset.Position(fn.Pos())
+			if err := g.parseOperation(fn.Name.Name, lines); err != nil {
+				return fmt.Errorf("%s:%d: %s: %w", filepath.Base(pos.Filename), pos.Line, fn.Name.Name, err)
+			}
+		}
+
+		// A blank line between the annotations and the function detaches
+		// them, which would silently drop the route
+		for _, group := range file.Comments {
+			if !attached[group] && hasAnnotation(annotationLines(group), "@Router") {
+				pos := g.fset.Position(group.Pos())
+				return fmt.Errorf("%s:%d: @Router annotations are not attached to a function", filepath.Base(pos.Filename), pos.Line)
+			}
+		}
+	}
+
+	tags := make(map[string]bool)
+	for _, item := range g.doc.Paths {
+		for _, op := range item {
+			for _, tag := range op.Tags {
+				tags[tag] = true
+			}
+		}
+	}
+	for tag := range tags {
+		g.doc.Tags = append(g.doc.Tags, Tag{Name: tag})
+	}
+	sort.Slice(g.doc.Tags, func(i, j int) bool { return g.doc.Tags[i].Name < g.doc.Tags[j].Name })
+	return nil
+}
+
+// parseOperation turns the annotations of one handler into an operation
+// for each of its @Router lines
+func (g *generator) parseOperation(name string, lines []string) error {
+	op := &Operation{Responses: make(map[string]*Response)}
+	accept := []string{"application/json"}
+	produce := []string{"application/json"}
+	var params, responses, routes []string
+
+	for _, line := range lines {
+		key, value := splitAnnotation(line)
+		switch key {
+		case "@Summary":
+			op.Summary = value
+		case "@Description":
+			op.Description = strings.TrimSpace(op.Description + " " + value)
+		case "@Tags":
+			op.Tags = splitList(value)
+		case "@Accept":
+			accept = mimeList(value)
+		case "@Produce":
+			produce = mimeList(value)
+		case "@Security":
+			op.Security = append(op.Security, map[string][]string{value: {}})
+		case "@Param":
+			params = append(params, value)
+		case "@Success", "@Failure":
+			responses = append(responses, value)
+		case "@Router":
+			routes = append(routes, value)
+		}
+	}
+
+	form := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for _, value := range params {
+		if err := g.parseParam(op, form, accept, value); err != nil {
+			return err
+		}
+	}
+	if len(form.Properties) > 0 {
+		formType := "multipart/form-data"
+		for _, mime := range accept {
+			if mime == "application/x-www-form-urlencoded" {
+				formType = mime
+			}
+		}
+		op.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{formType: {Schema: form}}}
+	}
+
+	for _, value := range responses {
+		if err := g.parseResponse(op, produce, value); err != nil {
+			return err
+		}
+	}
+	if len(op.Responses) == 0 {
+		return fmt.Errorf("no @Success or @Failure responses")
+	}
+
+	for _, value := range routes {
+		match := routerPattern.FindStringSubmatch(value)
+		if match == nil {
+			return fmt.Errorf("invalid @Router %q", value)
+		}
+		path, method := match[1], strings.ToLower(match[2])
+		if err := checkPathParams(path, op.Parameters); err != nil {
+			return err
+		}
+
+		routeOp := *op
+		routeOp.OperationID = g.operationID(name)
+		if g.doc.Paths[path] == nil {
+			g.doc.Paths[path] = make(map[string]*Operation)
+		}
+		if _, exists := g.doc.Paths[path][method]; exists {
+			return fmt.Errorf("duplicate route %s %s", strings.ToUpper(method), path)
+		}
+		g.doc.Paths[path][method] = &routeOp
+	}
+	return nil
+}
+
+// parseParam adds a parameter, the request body or a form field
+func (g *generator) parseParam(op *Operation, form *Schema, accept []string, value string) error {
+	match := paramPattern.FindStringSubmatch(value)
+	if match == nil {
+		return fmt.Errorf("invalid @Param %q", value)
+	}
+	name, in, typeName, description := match[1], match[2], match[3], match[5]
+	required := match[4] == "true"
+
+	schema, err := g.annotationSchema(typeName)
+	if err != nil {
+		return fmt.Errorf("@Param %s: %w", name, err)
+	}
+
+	switch in {
+	case "path", "query", "header":
+		op.Parameters = append(op.Parameters, &Parameter{
+			Name:        name,
+			In:          in,
+			Description: description,
+			Required:    required || in == "path",
+			Schema:      schema,
+		})
+	case "body":
+		content := make(map[string]MediaType, len(accept))
+		for _, mime := range accept {
+			content[mime] = MediaType{Schema: schema}
+		}
+		op.RequestBody = &RequestBody{Description: description, Required: required, Content: content}
+	case "formData":
+		if description != "" && schema.Ref == "" {
+			schema.Description = description
+		}
+		form.Properties[name] = schema
+		if required {
+			form.Required = append(form.Required, name)
+		}
+	default:
+		return fmt.Errorf("@Param %s: unknown location %q", name, in)
+	}
+	return nil
+}
+
+// parseResponse adds a response. The first response declared for a status
+// code wins.
+func (g *generator) parseResponse(op *Operation, produce []string, value string) error {
+	match := responsePattern.FindStringSubmatch(value)
+	if match == nil {
+		return fmt.Errorf("invalid response %q", value)
+	}
+	code, kind, typeName, description := match[1], match[2], match[3], match[4]
+	if _, exists := op.Responses[code]; exists {
+		return nil
+	}
+
+	if description == "" {
+		status, _ := strconv.Atoi(code)
+		description = http.StatusText(status)
+	}
+	response := &Response{Description: description}
+
+	if kind != "" {
+		var schema *Schema
+		switch kind {
+		case "file":
+			schema = &Schema{Type: "string", Format: "binary"}
+		case "object", "string", "array":
+			var err error
+			if schema, err = g.annotationSchema(typeName); err != nil {
+				return fmt.Errorf("response %s: %w", code, err)
+			}
+			if kind == "array" {
+				schema = &Schema{Type: "array", Items: schema}
+			}
+		default:
+			return fmt.Errorf("response %s: unknown kind {%s}", code, kind)
+		}
+		response.Content = make(map[string]MediaType, len(produce))
+		for _, mime := range produce {
+			response.Content[mime] = MediaType{Schema: schema}
+		}
+	}
+
+	op.Responses[code] = response
+	return nil
+}
+
+// operationID returns the handler name, numbered when the same name was
+// already used by another route
+func (g *generator) operationID(name string) string {
+	id := name
+	for n := 2; g.operationIDs[id]; n++ {
+		id = fmt.Sprintf("%s%d", name, n)
+	}
+	g.operationIDs[id] = true
+	return id
+}
+
+// checkPathParams reports path variables without a path @Param
+func checkPathParams(path string, params []*Parameter) error {
+	for _, match := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		found := false
+		for _, param := range params {
+			if param.In == "path" && param.Name == match[1] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("path %s has no @Param for %q", path, match[1])
+		}
+	}
+	return nil
+}
+
+// annotationSchema returns the schema of a type written in an annotation,
+// such as models.NotebookResponse, []string or map[string]interface{}
+func (g *generator) annotationSchema(typeName string) (*Schema, error) {
+	switch typeName {
+	case "object":
+		return &Schema{Type: "object"}, nil
+	case "file", "binary":
+		return &Schema{Type: "string", Format: "binary"}, nil
+	}
+	expr, err := parser.ParseExpr(typeName)
+	if err != nil {
+		return nil, fmt.Errorf("invalid type %q", typeName)
+	}
+	return g.schemaFor(handlersPackage, expr)
+}
+
+// schemaFor returns the schema of a Go type expression appearing in pkg
+func (g *generator) schemaFor(pkg string, expr ast.Expr) (*Schema, error) {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if schema := primitiveSchema(t.Name); schema != nil {
+			return schema, nil
+		}
+		return g.ref(pkg, t.Name)
+	case *ast.SelectorExpr:
+		qualifier, ok := t.X.(*ast.Ident)
+		if !ok {
+			return nil, fmt.Errorf("unsupported type %T", t.X)
+		}
+		if _, known := packageDirs[qualifier.Name]; known {
+			return g.ref(qualifier.Name, t.Sel.Name)
+		}
+		return externalSchema(qualifier.Name + "." + t.Sel.Name), nil
+	case *ast.StarExpr:
+		return g.schemaFor(pkg, t.X)
+	case *ast.ArrayType:
+		if elt, ok := t.Elt.(*ast.Ident); ok && elt.Name == "byte" {
+			return &Schema{Type: "string", Format: "byte"}, nil
+		}
+		items, err := g.schemaFor(pkg, t.Elt)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items}, nil
+	case *ast.MapType:
+		values, err := g.schemaFor(pkg, t.Value)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "object", AdditionalProperties: values}, nil
+	case *ast.InterfaceType:
+		return &Schema{}, nil
+	case *ast.StructType:
+		return g.structSchema(pkg, t)
+	default:
+		return nil, fmt.Errorf("unsupported type %T", expr)
+	}
+}
+
+// ref defines the named type as a component schema and returns a reference
+// to it
+func (g *generator) ref(pkg, name string) (*Schema, error) {
+	key := pkg + "." + name
+	if _, defined := g.doc.Components.Schemas[key]; !defined {
+		index, err := g.index(pkg)
+		if err != nil {
+			return nil, err
+		}
+		spec, ok := index.types[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown type %s", key)
+		}
+
+		// Reserve the name first so recursive types terminate
+		g.doc.Components.Schemas[key] = &Schema{}
+		schema, err := g.schemaFor(pkg, spec.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		schema.Description = index.docs[name]
+		if schema.Type == "string" {
+			schema.Enum = index.enums[name]
+		}
+		g.doc.Components.Schemas[key] = schema
+	}
+	return &Schema{Ref: "#/components/schemas/" + key}, nil
+}
+
+// structSchema returns the object schema of a struct, following the
+// encoding/json rules for field names and embedded structs
+func (g *generator) structSchema(pkg string, st *ast.StructType) (*Schema, error) {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for _, field := range st.Fields.List {
+		var tag reflect.StructTag
+		if field.Tag != nil {
+			tag = reflect.StructTag(strings.Trim(field.Tag.Value, "`"))
+		}
+		jsonName, _, _ := strings.Cut(tag.Get("json"), ",")
+		if jsonName == "-" {
+			continue
+		}
+
+		if len(field.Names) == 0 && jsonName == "" {
+			embedded, err := g.embeddedStruct(pkg, field.Type)
+			if err != nil {
+				return nil, err
+			}
+			if embedded != nil {
+				for name, property := range embedded.Properties {
+					schema.Properties[name] = property
+				}
+				schema.Required = append(schema.Required, embedded.Required...)
+				continue
+			}
+		}
+
+		names := field.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{embeddedName(field.Type)}
+		}
+		for _, ident := range names {
+			if ident == nil || !ident.IsExported() {
+				continue
+			}
+			property, err := g.schemaFor(pkg, field.Type)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", ident.Name, err)
+			}
+			// Only trailing comments: comments above a field often head a
+			// group of fields rather than describe the one below
+			if description := commentText(field.Comment); description != "" {
+				property = withDescription(property, description)
+			}
+			name := jsonName
+			if name == "" {
+				name = ident.Name
+			}
+			schema.Properties[name] = property
+			if isRequired(tag) {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+	}
+	sort.Strings(schema.Required)
+	return schema, nil
+}
+
+// embeddedStruct returns the schema of an embedded struct whose fields are
+// promoted, or nil when the embedded type is not a struct
+func (g *generator) embeddedStruct(pkg string, expr ast.Expr) (*Schema, error) {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	name := ""
+	switch t := expr.(type) {
+	case *ast.Ident:
+		name = t.Name
+	case *ast.SelectorExpr:
+		qualifier, ok := t.X.(*ast.Ident)
+		if !ok {
+			return nil, nil
+		}
+		if _, known := packageDirs[qualifier.Name]; !known {
+			return nil, nil
+		}
+		pkg, name = qualifier.Name, t.Sel.Name
+	default:
+		return nil, nil
+	}
+
+	index, err := g.index(pkg)
+	if err != nil {
+		return nil, err
+	}
+	spec, ok := index.types[name]
+	if !ok {
+		return nil, nil
+	}
+	st, ok := spec.Type.(*ast.StructType)
+	if !ok {
+		return nil, nil
+	}
+	return g.structSchema(pkg, st)
+}
+
+// index parses the type declarations of a package once
+func (g *generator) index(pkg string) (*typeIndex, error) {
+	if index, ok := g.packages[pkg]; ok {
+		return index, nil
+	}
+	files, err := g.parseDir(packageDirs[pkg])
+	if err != nil {
+		return nil, err
+	}
+
+	index := &typeIndex{
+		types: make(map[string]*ast.TypeSpec),
+		docs:  make(map[string]string),
+		enums: make(map[string][]string),
+	}
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					index.types[s.Name.Name] = s
+					doc := s.Doc
+					if doc == nil && len(gen.Specs) == 1 {
+						doc = gen.Doc
+					}
+					index.docs[s.Name.Name] = commentText(doc, nil)
+				case *ast.ValueSpec:
+					typeName, ok := s.Type.(*ast.Ident)
+					if !ok || gen.Tok != token.CONST {
+						continue
+					}
+					for _, value := range s.Values {
+						if lit, ok := value.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+							if text, err := strconv.Unquote(lit.Value); err == nil {
+								index.enums[typeName.Name] = append(index.enums[typeName.Name], text)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	g.packages[pkg] = index
+	return index, nil
+}
+
+// parseDir parses the non-test Go files of a directory in name order
+func (g *generator) parseDir(dir string) ([]*ast.File, error) {
+	entries, err := os.ReadDir(filepath.Join(g.root, dir))
+	if err != nil {
+		return nil, err
+	}
+	var files []*ast.File
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(g.fset, filepath.Join(g.root, dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", name, err)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// primitiveSchema maps Go and annotation primitive type names
+func primitiveSchema(name string) *Schema {
+	switch name {
+	case "string":
+		return &Schema{Type: "string"}
+	case "bool", "boolean":
+		return &Schema{Type: "boolean"}
+	case "int", "int8", "int16", "int32", "uint", "uint8", "uint16", "uint32", "integer":
+		return &Schema{Type: "integer"}
+	case "int64", "uint64":
+		return &Schema{Type: "integer", Format: "int64"}
+	case "float32":
+		return &Schema{Type: "number", Format: "float"}
+	case "float64", "number":
+		return &Schema{Type: "number", Format: "double"}
+	case "any":
+		return &Schema{}
+	case "error":
+		return &Schema{Type: "string"}
+	}
+	return nil
+}
+
+// externalSchema maps types from packages outside the repository. Unknown
+// types accept any value.
+func externalSchema(name string) *Schema {
+	switch name {
+	case "time.Time":
+		return &Schema{Type: "string", Format: "date-time"}
+	case "time.Duration":
+		return &Schema{Type: "integer", Format: "int64", Description: "Duration in nanoseconds"}
+	case "uuid.UUID":
+		return &Schema{Type: "string", Format: "uuid"}
+	}
+	return &Schema{}
+}
+
+// withDescription attaches a description without changing a shared schema
+func withDescription(schema *Schema, description string) *Schema {
+	copied := *schema
+	copied.Description = description
+	return &copied
+}
+
+func embeddedName(expr ast.Expr) *ast.Ident {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t
+	case *ast.StarExpr:
+		return embeddedName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel
+	}
+	return nil
+}
+
+func isRequired(tag reflect.StructTag) bool {
+	for _, key := range []string{"binding", "validate"} {
+		for _, rule := range strings.Split(tag.Get(key), ",") {
+			if rule == "required" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// commentText joins comment lines into one line
+func commentText(groups ...*ast.CommentGroup) string {
+	for _, group := range groups {
+		if group == nil {
+			continue
+		}
+		if text := strings.Join(strings.Fields(group.Text()), " "); text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+// annotationLines returns the lines of a comment starting with @
+func annotationLines(group *ast.CommentGroup) []string {
+	var lines []string
+	for _, line := range strings.Split(group.Text(), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "@") {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func hasAnnotation(lines []string, key string) bool {
+	for _, line := range lines {
+		if k, _ := splitAnnotation(line); k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func splitAnnotation(line string) (string, string) {
+	end := strings.IndexFunc(line, unicode.IsSpace)
+	if end < 0 {
+		return line, ""
+	}
+	return line[:end], strings.TrimSpace(line[end:])
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func mimeList(value string) []string {
+	items := splitList(value)
+	for i, item := range items {
+		if mime, ok := mimeTypes[item]; ok {
+			items[i] = mime
+		}
+	}
+	return items
+}
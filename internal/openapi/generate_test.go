@@ -0,0 +1,157 @@
+package openapi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSpecIsUpToDate fails when annotations or models changed without
+// regenerating openapi.json
+func TestSpecIsUpToDate(t *testing.T) {
+	doc, err := Generate("../..")
+	require.NoError(t, err)
+	data, err := Marshal(doc)
+	require.NoError(t, err)
+	assert.True(t, string(data) == string(Spec()), "openapi.json is out of date; run make generate")
+}
+
+// writeTree creates a repository with the given files
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return root
+}
+
+const testMain = `package main
+
+// @title Test API
+// @version 1.0
+func main() {}
+`
+
+func TestGenerateBuildsSchemasFromModels(t *testing.T) {
+	root := writeTree(t, map[string]string{
+		mainFile: testMain,
+		"internal/models/widget.go": `package models
+
+import "time"
+
+// WidgetStatus is the lifecycle state of a widget
+type WidgetStatus string
+
+const (
+	WidgetActive   WidgetStatus = "active"
+	WidgetArchived WidgetStatus = "archived"
+)
+
+type Base struct {
+	ID        string    ` + "`json:\"id\"`" + `
+	CreatedAt time.Time ` + "`json:\"created_at\"`" + `
+}
+
+// Widget is a widget
+type Widget struct {
+	Base
+	Name   string       ` + "`json:\"name\" validate:\"required\"`" + ` // Display name
+	Status WidgetStatus ` + "`json:\"status\"`" + `
+	Parts  []*Widget    ` + "`json:\"parts,omitempty\"`" + `
+	secret string
+	Hidden string ` + "`json:\"-\"`" + `
+}
+`,
+		"internal/handlers/widget.go": `package handlers
+
+// GetWidget returns a widget
+// @Summary Get widget
+// @Tags widgets
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Widget ID"
+// @Param expand query []string false "Relations to expand"
+// @Success 200 {object} models.Widget
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/widgets/{id} [get]
+func GetWidget() {}
+`,
+	})
+
+	doc, err := Generate(root)
+	require.NoError(t, err)
+	assert.Equal(t, Info{Title: "Test API", Version: "1.0"}, doc.Info)
+
+	op := doc.Paths["/api/v1/widgets/{id}"]["get"]
+	require.NotNil(t, op)
+	assert.Equal(t, "GetWidget", op.OperationID)
+	assert.Equal(t, []string{"widgets"}, op.Tags)
+	require.Len(t, op.Parameters, 2)
+	assert.True(t, op.Parameters[0].Required)
+	assert.Equal(t, &Schema{Type: "array", Items: &Schema{Type: "string"}}, op.Parameters[1].Schema)
+	assert.Equal(t, "#/components/schemas/models.Widget", op.Responses["200"].Content["application/json"].Schema.Ref)
+	assert.Equal(t, "Not Found", op.Responses["404"].Description)
+
+	widget := doc.Components.Schemas["models.Widget"]
+	require.NotNil(t, widget)
+	assert.Equal(t, "Widget is a widget", widget.Description)
+	assert.ElementsMatch(t, []string{"id", "created_at", "name", "status", "parts"}, keys(widget.Properties))
+	assert.Equal(t, []string{"name"}, widget.Required)
+	assert.Equal(t, "Display name", widget.Properties["name"].Description)
+	assert.Equal(t, "date-time", widget.Properties["created_at"].Format)
+	assert.Equal(t, "#/components/schemas/models.Widget", widget.Properties["parts"].Items.Ref)
+	assert.Equal(t, []string{"active", "archived"}, doc.Components.Schemas["models.WidgetStatus"].Enum)
+}
+
+func TestGenerateRejectsBrokenAnnotations(t *testing.T) {
+	tests := map[string]struct {
+		handler string
+		err     string
+	}{
+		"unknown type": {
+			handler: `// @Success 200 {object} models.Missing
+// @Router /widgets [get]
+func List() {}`,
+			err: "unknown type models.Missing",
+		},
+		"undeclared path parameter": {
+			handler: `// @Success 204
+// @Router /widgets/{id} [delete]
+func Delete() {}`,
+			err: `path /widgets/{id} has no @Param for "id"`,
+		},
+		"detached annotations": {
+			handler: `// @Success 204
+// @Router /widgets [post]
+
+func Create() {}`,
+			err: "not attached to a function",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			root := writeTree(t, map[string]string{
+				mainFile:                 testMain,
+				"internal/models/doc.go": "package models\n",
+				"internal/handlers/h.go": "package handlers\n\n" + tt.handler + "\n",
+			})
+			_, err := Generate(root)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func keys(m map[string]*Schema) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
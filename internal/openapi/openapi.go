@@ -0,0 +1,21 @@
+// Package openapi generates the OpenAPI document of the API from the
+// swag-style annotations on the handlers and embeds the result. The Go
+// client in pkg/client is generated from the same document.
+//
+// Regenerate openapi.json and the client after changing a handler
+// annotation or a model used by one:
+//
+//	make generate
+package openapi
+
+import _ "embed"
+
+//go:generate go run ../../cmd/openapi -root ../.. -out openapi.json -client ../../pkg/client/api_gen.go
+
+//go:embed openapi.json
+var spec []byte
+
+// Spec returns the generated OpenAPI document as JSON
+func Spec() []byte {
+	return spec
+}
@@ -0,0 +1,64 @@
+package pagination
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Limits shared by every list endpoint
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// Params holds normalized pagination parameters
+type Params struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// Clamp applies DefaultLimit to missing limits, caps limits at MaxLimit and
+// forbids negative offsets
+func Clamp(limit, offset int) (int, int) {
+	return clamp(limit, offset, DefaultLimit)
+}
+
+func clamp(limit, offset, defaultLimit int) (int, int) {
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+// FromQuery parses limit and offset query parameters. Missing or invalid
+// limits fall back to defaultLimit so each endpoint keeps its own default.
+func FromQuery(c *gin.Context, defaultLimit int) Params {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	offset, _ := strconv.Atoi(c.Query("offset"))
+
+	var params Params
+	params.Limit, params.Offset = clamp(limit, offset, defaultLimit)
+	return params
+}
+
+// HasMore reports whether more items exist past the current page when the
+// total count is known
+func HasMore(offset, returned, total int) bool {
+	return offset+returned < total
+}
+
+// Trim cuts a result fetched with one extra record back to limit items and
+// reports whether the extra record was present
+func Trim[T any](items []T, limit int) ([]T, bool) {
+	if limit >= 0 && len(items) > limit {
+		return items[:limit], true
+	}
+	return items, false
+}
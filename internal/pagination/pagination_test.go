@@ -0,0 +1,53 @@
+package pagination
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClamp(t *testing.T) {
+	limit, offset := Clamp(0, -5)
+	assert.Equal(t, DefaultLimit, limit)
+	assert.Equal(t, 0, offset)
+
+	limit, _ = Clamp(500, 0)
+	assert.Equal(t, MaxLimit, limit)
+
+	limit, offset = Clamp(50, 10)
+	assert.Equal(t, 50, limit)
+	assert.Equal(t, 10, offset)
+}
+
+func TestFromQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/?limit=abc&offset=5", nil)
+
+	params := FromQuery(c, 50)
+	assert.Equal(t, 50, params.Limit)
+	assert.Equal(t, 5, params.Offset)
+
+	c, _ = gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/?limit=250&offset=-1", nil)
+	params = FromQuery(c, 50)
+	assert.Equal(t, MaxLimit, params.Limit)
+	assert.Equal(t, 0, params.Offset)
+}
+
+func TestHasMore(t *testing.T) {
+	assert.True(t, HasMore(0, 20, 21))
+	assert.False(t, HasMore(20, 1, 21))
+}
+
+func TestTrim(t *testing.T) {
+	items, more := Trim([]int{1, 2, 3}, 2)
+	assert.Equal(t, []int{1, 2}, items)
+	assert.True(t, more)
+
+	items, more = Trim([]int{1}, 2)
+	assert.Equal(t, []int{1}, items)
+	assert.False(t, more)
+}
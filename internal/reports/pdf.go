@@ -0,0 +1,306 @@
+package reports
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// A4 page layout, in points
+const (
+	pageWidth    = 595.0
+	pageHeight   = 842.0
+	pageMargin   = 40.0
+	titleSize    = 16.0
+	subtitleSize = 10.0
+	bodySize     = 9.0
+	lineHeight   = 13.0
+	cellPadding  = 6.0
+	// charWidth is the average width of a Helvetica character as a
+	// fraction of the font size, used to fit cells to their columns
+	charWidth = 0.52
+)
+
+// Fonts of the page resources
+const (
+	fontRegular = "F1"
+	fontBold    = "F2"
+)
+
+// WritePDF writes a report as a PDF of A4 pages, using the standard
+// Helvetica fonts so nothing is embedded. Text outside Latin-1 is shown as
+// "?", and cells too wide for their column are cut short with "...". The
+// table header is repeated on every page.
+func WritePDF(w io.Writer, r Report) error {
+	layout := newPDFLayout(r.Columns, r.Rows)
+	page := &pdfPage{}
+	pages := []*pdfPage{page}
+	y := pageHeight - pageMargin
+
+	newPage := func() {
+		page = &pdfPage{}
+		pages = append(pages, page)
+		y = pageHeight - pageMargin
+	}
+	// ensure starts a new page unless height fits above the footer
+	ensure := func(height float64) bool {
+		if y-height < pageMargin+lineHeight {
+			newPage()
+			return true
+		}
+		return false
+	}
+
+	y -= titleSize
+	page.text(fontBold, titleSize, pageMargin, y, r.Title)
+	y -= lineHeight + 2
+	if r.Subtitle != "" {
+		page.text(fontRegular, subtitleSize, pageMargin, y, r.Subtitle)
+		y -= lineHeight
+	}
+	if !r.GeneratedAt.IsZero() {
+		page.text(fontRegular, bodySize, pageMargin, y, "Generated "+r.GeneratedAt.UTC().Format("2006-01-02 15:04 UTC"))
+		y -= lineHeight
+	}
+	y -= lineHeight / 2
+
+	for _, figure := range r.Figures {
+		ensure(lineHeight)
+		page.text(fontBold, bodySize, pageMargin, y, figure.Label+":")
+		page.text(fontRegular, bodySize, pageMargin+180, y, figure.Value)
+		y -= lineHeight
+	}
+	if len(r.Figures) > 0 {
+		y -= lineHeight / 2
+	}
+
+	if len(r.Columns) > 0 {
+		ensure(2 * lineHeight)
+		layout.header(page, y)
+		y -= lineHeight + 4
+		for _, row := range r.Rows {
+			if ensure(lineHeight) {
+				layout.header(page, y)
+				y -= lineHeight + 4
+			}
+			layout.row(page, y, row)
+			y -= lineHeight
+		}
+		if len(r.Rows) == 0 {
+			page.text(fontRegular, bodySize, pageMargin, y, "No data for this period.")
+			y -= lineHeight
+		}
+	}
+
+	if len(r.Notes) > 0 {
+		y -= lineHeight / 2
+	}
+	for _, note := range r.Notes {
+		ensure(lineHeight)
+		page.text(fontRegular, bodySize, pageMargin, y, note)
+		y -= lineHeight
+	}
+
+	for i, p := range pages {
+		footer := fmt.Sprintf("Page %d of %d", i+1, len(pages))
+		p.text(fontRegular, bodySize, pageWidth-pageMargin-textWidth(footer, bodySize), pageMargin/2, footer)
+	}
+	return writePDFDocument(w, pages)
+}
+
+// pdfLayout places the columns of a table across the page
+type pdfLayout struct {
+	columns []string
+	x       []float64
+	widths  []float64
+	numeric []bool
+}
+
+// newPDFLayout shares the page width between columns by the length of
+// their longest cell, so short numeric columns stay narrow. Columns whose
+// cells all look like numbers are right aligned.
+func newPDFLayout(columns []string, rows [][]string) *pdfLayout {
+	layout := &pdfLayout{
+		columns: columns,
+		x:       make([]float64, len(columns)),
+		widths:  make([]float64, len(columns)),
+		numeric: make([]bool, len(columns)),
+	}
+	if len(columns) == 0 {
+		return layout
+	}
+
+	lengths := make([]float64, len(columns))
+	total := 0.0
+	for i, column := range columns {
+		longest := utf8.RuneCountInString(column)
+		layout.numeric[i] = len(rows) > 0
+		for _, row := range rows {
+			if i >= len(row) {
+				continue
+			}
+			if n := utf8.RuneCountInString(row[i]); n > longest {
+				longest = n
+			}
+			if !looksNumeric(row[i]) {
+				layout.numeric[i] = false
+			}
+		}
+		// Keep long columns from crowding out the others
+		if longest > 48 {
+			longest = 48
+		}
+		if longest < 4 {
+			longest = 4
+		}
+		lengths[i] = float64(longest)
+		total += lengths[i]
+	}
+
+	x := pageMargin
+	available := pageWidth - 2*pageMargin
+	for i := range columns {
+		layout.x[i] = x
+		layout.widths[i] = available * lengths[i] / total
+		x += layout.widths[i]
+	}
+	return layout
+}
+
+func (l *pdfLayout) header(page *pdfPage, y float64) {
+	for i, column := range l.columns {
+		l.cell(page, fontBold, y, i, column)
+	}
+	page.line(pageMargin, y-4, pageWidth-pageMargin, y-4)
+}
+
+func (l *pdfLayout) row(page *pdfPage, y float64, row []string) {
+	for i := range l.columns {
+		if i < len(row) {
+			l.cell(page, fontRegular, y, i, row[i])
+		}
+	}
+}
+
+func (l *pdfLayout) cell(page *pdfPage, font string, y float64, column int, value string) {
+	value = fitText(value, l.widths[column]-cellPadding, bodySize)
+	x := l.x[column]
+	if l.numeric[column] {
+		x += l.widths[column] - cellPadding - textWidth(value, bodySize)
+	}
+	page.text(font, bodySize, x, y, value)
+}
+
+// looksNumeric reports whether a cell is a number, possibly formatted
+// with a currency, separators, a percentage or a unit
+func looksNumeric(s string) bool {
+	digits := 0
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case strings.ContainsRune("$.,-% ", r):
+		case r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z':
+			// Units such as "MB" after the number
+			if digits == 0 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return digits > 0
+}
+
+// textWidth estimates the width of text in Helvetica
+func textWidth(s string, size float64) float64 {
+	return float64(utf8.RuneCountInString(s)) * size * charWidth
+}
+
+// fitText cuts text short with "..." to fit width
+func fitText(s string, width, size float64) string {
+	if textWidth(s, size) <= width {
+		return s
+	}
+	fits := int(width/(size*charWidth)) - 3
+	if fits < 1 {
+		return "..."
+	}
+	runes := []rune(s)
+	return string(runes[:fits]) + "..."
+}
+
+// pdfPage is the content stream of one page
+type pdfPage struct {
+	content bytes.Buffer
+}
+
+func (p *pdfPage) text(font string, size, x, y float64, s string) {
+	fmt.Fprintf(&p.content, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, pdfString(s))
+}
+
+func (p *pdfPage) line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(&p.content, "0.5 w %.2f %.2f m %.2f %.2f l S\n", x1, y1, x2, y2)
+}
+
+// pdfString escapes text for a PDF literal string in WinAnsiEncoding,
+// which matches Latin-1 apart from the range 0x80-0x9F
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\t' || r == '\n' || r == '\r':
+			b.WriteByte(' ')
+		case r < 0x20 || (r >= 0x7F && r < 0xA0) || r > 0xFF:
+			b.WriteByte('?')
+		case r < 0x80:
+			b.WriteByte(byte(r))
+		default:
+			fmt.Fprintf(&b, "\\%03o", r)
+		}
+	}
+	return b.String()
+}
+
+// writePDFDocument writes the objects of a document of pages: the catalog,
+// the page tree, the two fonts, then each page and its content stream
+func writePDFDocument(w io.Writer, pages []*pdfPage) error {
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, page := range pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] "+
+			"/Resources << /Font << /%s 3 0 R /%s 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, fontRegular, fontBold, 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.content.Len(), page.content.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
@@ -0,0 +1,138 @@
+package reports
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// Rendition page layout, in points
+const (
+	renditionBodySize      = 10.0
+	renditionLineHeight    = 14.0
+	renditionWatermarkSize = 30.0
+	// renditionWatermarkGray is the fill of the watermark, light enough
+	// for the text drawn over it to stay readable
+	renditionWatermarkGray = 0.82
+)
+
+// Rendition is the text of a document shown in place of its original file,
+// stamped on every page with whom it was rendered for and when
+type Rendition struct {
+	Title      string
+	Text       string
+	Watermark  string // Such as the email of the user it was rendered for
+	RenderedAt time.Time
+}
+
+// WatermarkText returns the line stamped on the pages of a rendition
+func (r Rendition) WatermarkText() string {
+	return r.Watermark + " - " + r.RenderedAt.UTC().Format("2006-01-02 15:04 UTC")
+}
+
+// WriteRenditionPDF writes a rendition as a PDF of A4 pages. Its text is
+// wrapped to the page width, keeping paragraphs, and each page carries the
+// watermark diagonally across it and in its footer. Like WritePDF it uses
+// the standard Helvetica fonts, so text outside Latin-1 is shown as "?".
+func WriteRenditionPDF(w io.Writer, r Rendition) error {
+	width := pageWidth - 2*pageMargin
+	page := &pdfPage{}
+	pages := []*pdfPage{page}
+	y := pageHeight - pageMargin
+
+	line := func(font string, size float64, s string) {
+		if y-size < pageMargin+lineHeight {
+			page = &pdfPage{}
+			pages = append(pages, page)
+			y = pageHeight - pageMargin
+		}
+		y -= size
+		page.text(font, size, pageMargin, y, s)
+		y -= renditionLineHeight - size
+	}
+
+	if r.Title != "" {
+		for _, title := range wrapText(r.Title, width, titleSize) {
+			line(fontBold, titleSize, title)
+		}
+		y -= renditionLineHeight / 2
+	}
+	for _, paragraph := range strings.Split(strings.ReplaceAll(r.Text, "\r\n", "\n"), "\n") {
+		lines := wrapText(paragraph, width, renditionBodySize)
+		if len(lines) == 0 {
+			lines = []string{""}
+		}
+		for _, text := range lines {
+			line(fontRegular, renditionBodySize, text)
+		}
+	}
+
+	watermark := r.WatermarkText()
+	for i, p := range pages {
+		// The content is drawn over the watermark, so it goes first
+		var content strings.Builder
+		content.WriteString(watermarkStream(watermark))
+		content.Write(p.content.Bytes())
+		p.content.Reset()
+		p.content.WriteString(content.String())
+
+		footer := fmt.Sprintf("%s - Page %d of %d", watermark, i+1, len(pages))
+		p.text(fontRegular, bodySize, pageWidth-pageMargin-textWidth(footer, bodySize), pageMargin/2, footer)
+	}
+	return writePDFDocument(w, pages)
+}
+
+// watermarkStream draws the watermark across the diagonal of a page, in
+// gray, shrinking it to fit
+func watermarkStream(watermark string) string {
+	diagonal := math.Hypot(pageWidth, pageHeight) - 4*pageMargin
+	size := renditionWatermarkSize
+	if width := textWidth(watermark, size); width > diagonal {
+		size *= diagonal / width
+	}
+	angle := math.Atan2(pageHeight, pageWidth)
+	cos, sin := math.Cos(angle), math.Sin(angle)
+	half := textWidth(watermark, size) / 2
+	x := pageWidth/2 - half*cos
+	y := pageHeight/2 - half*sin
+	return fmt.Sprintf("q %.2f g BT /%s %.1f Tf %.4f %.4f %.4f %.4f %.2f %.2f Tm (%s) Tj ET Q\n",
+		renditionWatermarkGray, fontBold, size, cos, sin, -sin, cos, x, y, pdfString(watermark))
+}
+
+// wrapText breaks text into lines that fit width, between words where it
+// can. A paragraph of only spaces has no lines.
+func wrapText(s string, width, size float64) []string {
+	limit := int(width / (size * charWidth))
+	if limit < 1 {
+		limit = 1
+	}
+	var lines []string
+	var current []rune
+	for _, word := range strings.Fields(s) {
+		runes := []rune(word)
+		for len(runes) > limit {
+			// A word longer than a line is cut where the line ends
+			if len(current) > 0 {
+				lines = append(lines, string(current))
+				current = nil
+			}
+			lines = append(lines, string(runes[:limit]))
+			runes = runes[limit:]
+		}
+		switch {
+		case len(current) == 0:
+			current = runes
+		case len(current)+1+len(runes) <= limit:
+			current = append(append(current, ' '), runes...)
+		default:
+			lines = append(lines, string(current))
+			current = runes
+		}
+	}
+	if len(current) > 0 {
+		lines = append(lines, string(current))
+	}
+	return lines
+}
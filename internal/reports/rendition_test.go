@@ -0,0 +1,51 @@
+package reports
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteRenditionPDF(t *testing.T) {
+	rendition := Rendition{
+		Title:      "Contract (draft)",
+		Text:       "First paragraph.\r\n\r\nSecond paragraph.",
+		Watermark:  "viewer@example.com",
+		RenderedAt: time.Date(2026, 10, 17, 9, 30, 0, 0, time.FixedZone("CEST", 2*3600)),
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteRenditionPDF(&buf, rendition))
+	pdf := buf.String()
+
+	assert.Equal(t, "viewer@example.com - 2026-10-17 07:30 UTC", rendition.WatermarkText())
+	assert.Contains(t, pdf, `(Contract \(draft\)) Tj`)
+	assert.Contains(t, pdf, "(Second paragraph.) Tj")
+	assert.Contains(t, pdf, "Tm (viewer@example.com - 2026-10-17 07:30 UTC) Tj ET Q")
+	assert.Contains(t, pdf, "(viewer@example.com - 2026-10-17 07:30 UTC - Page 1 of 1) Tj")
+	assert.Less(t, strings.Index(pdf, " Tm ("), strings.Index(pdf, "(First paragraph.) Tj"), "the watermark is under the text")
+	assertXrefValid(t, pdf)
+}
+
+func TestWriteRenditionPDFPages(t *testing.T) {
+	rendition := Rendition{Text: strings.Repeat("line\n", 150), Watermark: "viewer@example.com"}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteRenditionPDF(&buf, rendition))
+	pdf := buf.String()
+
+	assert.Contains(t, pdf, "/Count 3 >>")
+	assert.Equal(t, 3, strings.Count(pdf, " Tm ("), "every page is watermarked")
+	assert.Contains(t, pdf, "Page 3 of 3) Tj")
+	assertXrefValid(t, pdf)
+}
+
+func TestWrapText(t *testing.T) {
+	// 10pt Helvetica fits 10 characters in 52 points
+	assert.Equal(t, []string{"aaa bbb", "cccccc", "dddddddddd", "dd e"}, wrapText("aaa bbb  cccccc dddddddddddd e", 52, 10))
+	assert.Nil(t, wrapText("   ", 52, 10))
+}
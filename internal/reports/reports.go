@@ -0,0 +1,46 @@
+// Package reports renders tabular reports as PDF and CSV, and the
+// watermarked PDF renditions of documents. It only formats; ReportService
+// decides what a report holds and who receives it, and WatermarkService
+// what a rendition shows.
+package reports
+
+import (
+	"encoding/csv"
+	"io"
+	"time"
+)
+
+// Content types of the rendered reports
+const (
+	ContentTypePDF = "application/pdf"
+	ContentTypeCSV = "text/csv; charset=utf-8"
+)
+
+// Report is a titled table with headline figures
+type Report struct {
+	Title       string
+	Subtitle    string // Such as the space and period covered
+	GeneratedAt time.Time
+	Figures     []Figure // Shown above the table; CSV omits them
+	Columns     []string
+	Rows        [][]string
+	Notes       []string // Shown below the table; CSV omits them
+}
+
+// Figure is a headline number of a report
+type Figure struct {
+	Label string
+	Value string
+}
+
+// WriteCSV writes the table of a report, with its columns as the header
+func WriteCSV(w io.Writer, r Report) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(r.Columns); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(r.Rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
@@ -0,0 +1,97 @@
+package reports
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testReport = Report{
+	Title:       "Storage usage",
+	Subtitle:    "Research (organization) - as of 2026-10-12",
+	GeneratedAt: time.Date(2026, 10, 12, 0, 5, 0, 0, time.UTC),
+	Figures:     []Figure{{Label: "Documents", Value: "3"}},
+	Columns:     []string{"Notebook", "Documents", "Size"},
+	Rows: [][]string{
+		{"Contracts (2026)", "2", "1.5 MB"},
+		{"Café, \"notes\"", "1", "12 KB"},
+	},
+	Notes: []string{"Deleted documents are not counted."},
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, testReport))
+	assert.Equal(t, "Notebook,Documents,Size\nContracts (2026),2,1.5 MB\n\"Café, \"\"notes\"\"\",1,12 KB\n", buf.String())
+}
+
+func TestWritePDF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WritePDF(&buf, testReport))
+	pdf := buf.String()
+
+	assert.True(t, strings.HasPrefix(pdf, "%PDF-1.4\n"))
+	assert.True(t, strings.HasSuffix(pdf, "%%EOF\n"))
+	assert.Contains(t, pdf, `(Contracts \(2026\)) Tj`, "parentheses are escaped")
+	assert.Contains(t, pdf, `(Caf\351, "notes") Tj`, "Latin-1 is written in octal")
+	assert.Contains(t, pdf, "(Page 1 of 1) Tj")
+	assertXrefValid(t, pdf)
+}
+
+func TestWritePDFPages(t *testing.T) {
+	report := Report{Title: "Agent cost", Columns: []string{"Agent", "Cost (USD)"}}
+	for i := 0; i < 150; i++ {
+		report.Rows = append(report.Rows, []string{fmt.Sprintf("Agent %d", i), "$1.00"})
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WritePDF(&buf, report))
+	pdf := buf.String()
+
+	assert.Contains(t, pdf, "/Count 3 >>")
+	assert.Contains(t, pdf, "(Page 3 of 3) Tj")
+	assert.Equal(t, 3, strings.Count(pdf, "/F2 9.0 Tf 40.00"), "the header starts every page")
+	assertXrefValid(t, pdf)
+}
+
+func TestFitText(t *testing.T) {
+	assert.Equal(t, "short", fitText("short", 100, bodySize))
+	fitted := fitText(strings.Repeat("x", 100), 60, bodySize)
+	assert.True(t, strings.HasSuffix(fitted, "..."))
+	assert.LessOrEqual(t, textWidth(fitted, bodySize), 60.0)
+}
+
+func TestLooksNumeric(t *testing.T) {
+	for _, s := range []string{"12", "1,024", "$3.50", "12.5 MB", "-4%"} {
+		assert.True(t, looksNumeric(s), s)
+	}
+	for _, s := range []string{"", "Contracts", "MB 12", "2026-10-12T00:00"} {
+		assert.False(t, looksNumeric(s), s)
+	}
+}
+
+// assertXrefValid checks that every cross-reference entry points at its
+// object
+func assertXrefValid(t *testing.T, pdf string) {
+	t.Helper()
+	start := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(pdf)
+	require.NotNil(t, start)
+	xref, err := strconv.Atoi(start[1])
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(pdf[xref:], "xref\n"))
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(pdf[xref:], -1)
+	require.NotEmpty(t, entries)
+	for i, entry := range entries {
+		offset, err := strconv.Atoi(entry[1])
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(pdf[offset:], fmt.Sprintf("%d 0 obj\n", i+1)), "object %d", i+1)
+	}
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+// AccessLogSink stores a batch of access log entries
+type AccessLogSink interface {
+	Write(ctx context.Context, entries []*models.AccessLogEntry) error
+}
+
+// KafkaAccessLogSink publishes access log entries to a Kafka topic, keyed
+// by tenant so a tenant's entries stay ordered
+type KafkaAccessLogSink struct {
+	kafka *KafkaService
+	topic string
+}
+
+// NewKafkaAccessLogSink creates a sink publishing to topic
+func NewKafkaAccessLogSink(kafka *KafkaService, topic string) *KafkaAccessLogSink {
+	return &KafkaAccessLogSink{kafka: kafka, topic: topic}
+}
+
+// Write publishes the entries in a single write
+func (s *KafkaAccessLogSink) Write(ctx context.Context, entries []*models.AccessLogEntry) error {
+	msgs := make([]Message, len(entries))
+	for i, entry := range entries {
+		msgs[i] = Message{
+			Topic:     s.topic,
+			Key:       entry.TenantID,
+			Value:     entry,
+			Timestamp: entry.Timestamp,
+		}
+	}
+	return s.kafka.PublishMessages(ctx, msgs)
+}
+
+// S3AccessLogSink writes each batch of access log entries as a
+// newline-delimited JSON object under prefix/yyyy/mm/dd/
+type S3AccessLogSink struct {
+	storage    StorageService
+	prefix     string
+	instanceID string
+}
+
+// NewS3AccessLogSink creates a sink writing objects under prefix. The
+// instance ID keeps object keys of different replicas apart.
+func NewS3AccessLogSink(storage StorageService, prefix, instanceID string) *S3AccessLogSink {
+	return &S3AccessLogSink{storage: storage, prefix: prefix, instanceID: instanceID}
+}
+
+// Write stores the entries as one object
+func (s *S3AccessLogSink) Write(ctx context.Context, entries []*models.AccessLogEntry) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return fmt.Errorf("failed to encode access log entry: %w", err)
+		}
+	}
+
+	now := time.Now().UTC()
+	key := fmt.Sprintf("%s/%s/%s-%d.ndjson", s.prefix, now.Format("2006/01/02"), s.instanceID, now.UnixNano())
+	_, err := s.storage.UploadFile(ctx, key, buf.Bytes(), "application/x-ndjson")
+	return err
+}
+
+// AccessLogExporter queues access log entries and writes them to a sink in
+// batches, flushing when a batch is full or the flush interval elapses.
+// Entries arriving while the queue is full are dropped rather than slowing
+// down requests.
+type AccessLogExporter struct {
+	sink          AccessLogSink
+	queue         chan *models.AccessLogEntry
+	batchSize     int
+	flushInterval time.Duration
+	dropped       atomic.Int64
+	logger        *logger.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewAccessLogExporter creates an exporter writing to sink; call Start to
+// begin exporting
+func NewAccessLogExporter(sink AccessLogSink, cfg config.AccessLogConfig, log *logger.Logger) *AccessLogExporter {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &AccessLogExporter{
+		sink:          sink,
+		queue:         make(chan *models.AccessLogEntry, cfg.QueueSize),
+		batchSize:     cfg.BatchSize,
+		flushInterval: time.Duration(cfg.FlushSeconds) * time.Second,
+		logger:        log.WithService("access_log_exporter"),
+		ctx:           ctx,
+		cancel:        cancel,
+		done:          make(chan struct{}),
+	}
+}
+
+// Export queues an entry without blocking
+func (e *AccessLogExporter) Export(entry *models.AccessLogEntry) {
+	select {
+	case e.queue <- entry:
+	default:
+		if e.dropped.Add(1)%1000 == 1 {
+			e.logger.Warn("Access log export queue full, dropping entries",
+				zap.Int64("dropped_total", e.dropped.Load()))
+		}
+	}
+}
+
+// Start begins exporting queued entries
+func (e *AccessLogExporter) Start() {
+	go e.run()
+}
+
+// Shutdown stops exporting and waits until queued entries are written or
+// ctx is done
+func (e *AccessLogExporter) Shutdown(ctx context.Context) error {
+	e.cancel()
+
+	select {
+	case <-e.done:
+		return nil
+	case <-ctx.Done():
+		e.logger.Warn("Timed out flushing access logs", zap.Int("queued", len(e.queue)))
+		return ctx.Err()
+	}
+}
+
+// run batches queued entries until Shutdown is called, then writes what is
+// still queued
+func (e *AccessLogExporter) run() {
+	defer close(e.done)
+
+	ticker := time.NewTicker(e.flushInterval)
+	defer ticker.Stop()
+
+	pending := make([]*models.AccessLogEntry, 0, e.batchSize)
+	flush := func(ctx context.Context) {
+		if len(pending) == 0 {
+			return
+		}
+		e.write(ctx, pending)
+		pending = make([]*models.AccessLogEntry, 0, e.batchSize)
+	}
+
+	for {
+		select {
+		case <-e.ctx.Done():
+		drain:
+			for {
+				select {
+				case entry := <-e.queue:
+					pending = append(pending, entry)
+				default:
+					break drain
+				}
+			}
+			// The exporter context is cancelled, so write with fresh deadlines
+			for len(pending) > 0 {
+				n := min(len(pending), e.batchSize)
+				e.write(context.Background(), pending[:n])
+				pending = pending[n:]
+			}
+			return
+		case entry := <-e.queue:
+			pending = append(pending, entry)
+			if len(pending) >= e.batchSize {
+				flush(e.ctx)
+			}
+		case <-ticker.C:
+			flush(e.ctx)
+		}
+	}
+}
+
+// write stores one batch; failed batches are logged and dropped, as access
+// logs are also written to the log output
+func (e *AccessLogExporter) write(ctx context.Context, entries []*models.AccessLogEntry) {
+	writeCtx, cancel := context.WithTimeout(ctx, defaultEventProcessTimeout)
+	defer cancel()
+
+	if err := e.sink.Write(writeCtx, entries); err != nil {
+		e.logger.Error("Failed to export access logs", zap.Int("count", len(entries)), zap.Error(err))
+	}
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+type recordingAccessLogSink struct {
+	mu      sync.Mutex
+	batches [][]*models.AccessLogEntry
+}
+
+func (s *recordingAccessLogSink) Write(ctx context.Context, entries []*models.AccessLogEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = append(s.batches, append([]*models.AccessLogEntry(nil), entries...))
+	return nil
+}
+
+func (s *recordingAccessLogSink) sizes() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sizes := make([]int, len(s.batches))
+	for i, batch := range s.batches {
+		sizes[i] = len(batch)
+	}
+	return sizes
+}
+
+func TestAccessLogExporterBatches(t *testing.T) {
+	sink := &recordingAccessLogSink{}
+	exporter := NewAccessLogExporter(sink, config.AccessLogConfig{
+		BatchSize:    2,
+		FlushSeconds: 3600,
+		QueueSize:    10,
+	}, setupTestLogger(t))
+	exporter.Start()
+
+	for i := 0; i < 5; i++ {
+		exporter.Export(&models.AccessLogEntry{Status: 200})
+	}
+	assert.Eventually(t, func() bool { return len(sink.sizes()) == 2 }, time.Second, 10*time.Millisecond)
+
+	// The last entry is written at shutdown instead of waiting for the flush interval
+	require.NoError(t, exporter.Shutdown(context.Background()))
+	assert.Equal(t, []int{2, 2, 1}, sink.sizes())
+}
+
+func TestAccessLogExporterDropsWhenFull(t *testing.T) {
+	sink := &recordingAccessLogSink{}
+	exporter := NewAccessLogExporter(sink, config.AccessLogConfig{
+		BatchSize:    10,
+		FlushSeconds: 3600,
+		QueueSize:    2,
+	}, setupTestLogger(t))
+
+	// Not started, so the queue is not consumed
+	for i := 0; i < 3; i++ {
+		exporter.Export(&models.AccessLogEntry{Status: 200})
+	}
+	assert.Equal(t, int64(1), exporter.dropped.Load())
+
+	exporter.Start()
+	require.NoError(t, exporter.Shutdown(context.Background()))
+	assert.Equal(t, []int{2}, sink.sizes())
+}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/Tributary-ai-services/aether-be/internal/database"
 	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/metrics"
 	"github.com/Tributary-ai-services/aether-be/internal/models"
 	"github.com/Tributary-ai-services/aether-be/pkg/errors"
 )
@@ -30,6 +31,12 @@ type AgentService struct {
 	teamService    *TeamService
 	agentBuilderURL string
 	httpClient     *http.Client
+	transport      *metrics.ExternalTransport
+	metrics        *metrics.Metrics
+	hub            *EventHub
+	events         DomainEventPublisher
+	quotas         *QuotaService
+	authz          *AuthorizationService
 	logger         *logger.Logger
 }
 
@@ -49,19 +56,128 @@ func NewAgentService(
 	agentBuilderURL string,
 	log *logger.Logger,
 ) *AgentService {
+	agentBuilderURL = strings.TrimSuffix(agentBuilderURL, "/")
+
+	// The client also carries direct execution calls to the router service
+	var builderHost string
+	if u, err := url.Parse(agentBuilderURL); err == nil {
+		builderHost = u.Host
+	}
+	transport := metrics.NewExternalTransport("agent-builder", logger.NewRequestIDTransport(nil), metrics.ExternalTransportOptions{
+		Service: func(req *http.Request) string {
+			if req.URL.Host == builderHost {
+				return "agent-builder"
+			}
+			return "router"
+		},
+	})
+
 	return &AgentService{
 		neo4j:           neo4j,
 		userService:     userService,
 		notebookService: notebookService,
 		teamService:     teamService,
-		agentBuilderURL: strings.TrimSuffix(agentBuilderURL, "/"),
+		agentBuilderURL: agentBuilderURL,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   defaultExternalHTTPTimeout,
+			Transport: transport,
 		},
-		logger: log.WithService("agent_service"),
+		transport: transport,
+		logger:    log.WithService("agent_service"),
 	}
 }
 
+// SetMetrics records metrics for calls to agent-builder and the router and
+// per-tenant agent cost
+func (s *AgentService) SetMetrics(m *metrics.Metrics) {
+	s.transport.SetMetrics(m)
+	s.metrics = m
+}
+
+// SetEventHub sets the hub that announces agent changes to real-time
+// clients
+func (s *AgentService) SetEventHub(hub *EventHub) {
+	s.hub = hub
+}
+
+// SetEventPublisher sets the publisher notified of agent executions
+func (s *AgentService) SetEventPublisher(events DomainEventPublisher) {
+	s.events = events
+}
+
+// SetQuotaService sets the service that counts executions against the
+// monthly quota of the agent's space
+func (s *AgentService) SetQuotaService(quotas *QuotaService) {
+	s.quotas = quotas
+}
+
+// SetAuthorizationService sets the service deciding who may change an
+// agent; without it the built-in roles apply
+func (s *AgentService) SetAuthorizationService(authz *AuthorizationService) {
+	s.authz = authz
+}
+
+// publishExecuted publishes a completed execution of an agent
+func (s *AgentService) publishExecuted(ctx context.Context, agent *models.Agent, response *models.AgentExecuteResponse, userID string) {
+	publishDomainEvent(ctx, s.events, s.logger, Event{
+		Type:    EventAgentExecuted,
+		Subject: agent.ID,
+		UserID:  userID,
+		Data: map[string]interface{}{
+			"space_id":         agent.SpaceID,
+			"tenant_id":        agent.TenantID,
+			"agent_id":         agent.ID,
+			"agent_type":       string(agent.Type),
+			"execution_id":     response.ExecutionID,
+			"tokens_used":      response.TokensUsed,
+			"cost_usd":         response.CostUSD,
+			"response_time_ms": response.ResponseTimeMs,
+		},
+	})
+}
+
+// publishChange announces an agent change with what subscribers need to
+// tell whether the user may see the agent
+func (s *AgentService) publishChange(ctx context.Context, agent *models.Agent, action string) {
+	if s.hub == nil {
+		return
+	}
+	s.hub.PublishChange(ctx, HubTopicAgentChanged, agent.TenantID, agent.ID, action, map[string]interface{}{
+		"space_id":   agent.SpaceID,
+		"space_type": string(agent.SpaceType),
+		"owner_id":   agent.OwnerID,
+		"team_id":    agent.TeamID,
+		"is_public":  agent.IsPublic,
+	})
+}
+
+// SetHTTPTimeout overrides the timeout for calls to agent-builder and the router
+func (s *AgentService) SetHTTPTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		s.httpClient.Timeout = timeout
+	}
+}
+
+// HealthCheck checks that agent-builder is reachable
+func (s *AgentService) HealthCheck(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.agentBuilderURL+"/health", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create health check request: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("agent-builder health check failed with status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
 // CreateAgent creates a new agent by:
 // 1. Creating the agent in agent-builder (PostgreSQL)
 // 2. Creating the agent metadata in Neo4j for relationship management
@@ -102,6 +218,7 @@ func (s *AgentService) CreateAgent(ctx context.Context, req models.AgentCreateRe
 		}
 	}
 
+	s.publishChange(ctx, agent, HubActionCreated)
 	return s.buildAgentResponse(ctx, agent)
 }
 
@@ -114,33 +231,34 @@ func (s *AgentService) GetAgent(ctx context.Context, agentID string, userID stri
 
 	// Check access permissions
 	if !agent.CanBeAccessedBy(userID, userTeams) {
-		return nil, errors.NotFound("Agent not found")
+		return nil, errors.NotFound("Agent not found").WithErrorCode(errors.CodeAgentNotFound)
 	}
 
 	return s.buildAgentResponse(ctx, agent)
 }
 
-// canUserModifyAgent checks if a user can modify an agent (owner or team admin)
-func (s *AgentService) canUserModifyAgent(ctx context.Context, agent *models.Agent, userID string) (bool, error) {
-	// Owner can always modify
-	if agent.OwnerID == userID {
-		return true, nil
-	}
-	
-	// For organization agents, check if user is team admin
-	if agent.IsOrganizationAgent() && agent.TeamID != "" {
+// authorizeAgent checks that a user may perform action on an agent: its
+// owner, or for organization agents an admin of the agent's team
+func (s *AgentService) authorizeAgent(ctx context.Context, agent *models.Agent, userID, action string) error {
+	subject := models.Subject{UserID: userID}
+	if agent.OwnerID != userID && agent.IsOrganizationAgent() && agent.TeamID != "" {
 		isAdmin, err := s.teamService.IsUserTeamAdmin(ctx, userID, agent.TeamID)
 		if err != nil {
-			s.logger.Error("Failed to check team admin status", 
+			s.logger.Error("Failed to check team admin status",
 				zap.String("user_id", userID),
 				zap.String("team_id", agent.TeamID),
 				zap.Error(err))
-			return false, err
+			return err
+		}
+		if isAdmin {
+			subject.Roles = []string{models.RoleTeamAdmin}
 		}
-		return isAdmin, nil
 	}
-	
-	return false, nil
+	return s.authz.Authorize(ctx, subject, action, models.Resource{
+		Type:    models.ResourceAgent,
+		ID:      agent.ID,
+		OwnerID: agent.OwnerID,
+	})
 }
 
 // UpdateAgent updates an agent by updating both agent-builder and Neo4j
@@ -152,13 +270,9 @@ func (s *AgentService) UpdateAgent(ctx context.Context, agentID string, req mode
 	}
 
 	// Check if user can modify this agent (owner or team admin)
-	canModify, err := s.canUserModifyAgent(ctx, agent, userID)
-	if err != nil {
+	if err := s.authorizeAgent(ctx, agent, userID, models.ActionAgentUpdate); err != nil {
 		return nil, err
 	}
-	if !canModify {
-		return nil, errors.Forbidden("Insufficient permissions to update agent")
-	}
 
 	// Step 1: Update agent in agent-builder
 	if err := s.updateAgentInBuilder(ctx, agent.AgentBuilderID, req, authToken); err != nil {
@@ -173,6 +287,7 @@ func (s *AgentService) UpdateAgent(ctx context.Context, agentID string, req mode
 		return nil, err
 	}
 
+	s.publishChange(ctx, agent, HubActionUpdated)
 	return s.buildAgentResponse(ctx, agent)
 }
 
@@ -184,13 +299,9 @@ func (s *AgentService) DeleteAgent(ctx context.Context, agentID string, userID s
 	}
 
 	// Check if user can modify this agent (owner or team admin)
-	canModify, err := s.canUserModifyAgent(ctx, agent, userID)
-	if err != nil {
+	if err := s.authorizeAgent(ctx, agent, userID, models.ActionAgentDelete); err != nil {
 		return err
 	}
-	if !canModify {
-		return errors.Forbidden("Insufficient permissions to delete agent")
-	}
 
 	// Step 1: Delete agent in agent-builder
 	if err := s.deleteAgentInBuilder(ctx, agent.AgentBuilderID, authToken); err != nil {
@@ -204,6 +315,7 @@ func (s *AgentService) DeleteAgent(ctx context.Context, agentID string, userID s
 		return err
 	}
 
+	s.publishChange(ctx, agent, HubActionDeleted)
 	return nil
 }
 
@@ -264,8 +376,7 @@ func (s *AgentService) ListAgents(ctx context.Context, req models.AgentSearchReq
 	httpReq.Header.Set("Authorization", "Bearer "+authToken)
 	httpReq.Header.Set("Content-Type", "application/json")
 	
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(httpReq)
+	resp, err := s.httpClient.Do(httpReq)
 	if err != nil {
 		return nil, errors.ExternalService("Agent-builder service unavailable", err)
 	}
@@ -277,7 +388,10 @@ func (s *AgentService) ListAgents(ctx context.Context, req models.AgentSearchReq
 	}
 	
 	if resp.StatusCode != http.StatusOK {
-		return nil, errors.ExternalService(fmt.Sprintf("Agent-builder error: %s", string(bodyBytes)), nil)
+		s.logger.Error("Agent-builder request failed",
+			zap.Int("status", resp.StatusCode),
+			zap.String("body", string(bodyBytes)))
+		return nil, errors.ExternalService("Agent-builder service error", fmt.Errorf("status: %d", resp.StatusCode))
 	}
 	
 	var builderResp map[string]interface{}
@@ -391,7 +505,7 @@ func (s *AgentService) AddKnowledgeSource(ctx context.Context, agentID, notebook
 	// Verify notebook access
 	notebook, err := s.notebookService.GetNotebookByID(ctx, notebookID, userID, nil)
 	if err != nil {
-		return errors.NotFound("Notebook not found or access denied")
+		return errors.NotFound("Notebook not found or access denied").WithErrorCode(errors.CodeNotebookNotFound)
 	}
 
 	// Create SEARCHES_IN relationship
@@ -511,17 +625,44 @@ func (s *AgentService) GetAgentKnowledgeSources(ctx context.Context, agentID, us
 	return sources, nil
 }
 
+// GetKnowledgeSourceIDs returns the notebook IDs each agent searches in,
+// highest search weight first, keyed by agent ID. It does not check access
+// to the agents.
+func (s *AgentService) GetKnowledgeSourceIDs(ctx context.Context, agentIDs []string) (map[string][]string, error) {
+	query := `
+		MATCH (a:Agent)-[r:SEARCHES_IN]->(n:Notebook)
+		WHERE a.id IN $agent_ids
+		RETURN a.id AS agent_id, n.id AS notebook_id
+		ORDER BY a.id, r.search_weight DESC
+	`
+
+	result, err := s.neo4j.ExecuteQueryWithLogging(ctx, query, map[string]interface{}{
+		"agent_ids": agentIDs,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to get agent knowledge sources", err)
+	}
+
+	sources := make(map[string][]string, len(agentIDs))
+	for _, record := range result.Records {
+		agentID, _ := record.Values[0].(string)
+		notebookID, _ := record.Values[1].(string)
+		sources[agentID] = append(sources[agentID], notebookID)
+	}
+	return sources, nil
+}
+
 // ExecuteAgent executes an agent with the provided input and handles type-specific logic
 func (s *AgentService) ExecuteAgent(ctx context.Context, agentID string, req models.AgentExecuteRequest, userID string, userTeams []string, authToken string) (*models.AgentExecuteResponse, error) {
 	// Get agent from agent-builder service (where agents actually live)
 	agent, err := s.getAgentFromBuilder(ctx, agentID, authToken)
 	if err != nil {
-		return nil, errors.NotFound("Agent not found")
+		return nil, errors.NotFound("Agent not found").WithErrorCode(errors.CodeAgentNotFound)
 	}
 
 	// Check access permissions based on space and ownership
 	if !s.canUserAccessAgent(agent, userID, userTeams) {
-		return nil, errors.NotFound("Agent not found")
+		return nil, errors.NotFound("Agent not found").WithErrorCode(errors.CodeAgentNotFound)
 	}
 
 	// Prepare execution request for agent-builder
@@ -531,6 +672,24 @@ func (s *AgentService) ExecuteAgent(ctx context.Context, agentID string, req mod
 		return nil, err
 	}
 
+	if req.DryRun {
+		now := time.Now()
+		return &models.AgentExecuteResponse{
+			AgentID:     agentID,
+			AgentType:   agent.Type,
+			Metadata:    map[string]interface{}{"dry_run": true},
+			StartedAt:   now,
+			CompletedAt: now,
+		}, nil
+	}
+
+	// The execution counts whether or not it succeeds
+	if s.quotas != nil {
+		if err := s.quotas.ReserveAgentExecution(ctx, agent.SpaceID); err != nil {
+			return nil, err
+		}
+	}
+
 	// Execute agent in agent-builder with fallback to direct execution
 	startTime := time.Now()
 	builderResp, err := s.executeAgentInBuilder(ctx, agent.AgentBuilderID, builderReq, authToken)
@@ -550,6 +709,7 @@ func (s *AgentService) ExecuteAgent(ctx context.Context, agentID string, req mod
 		if err := s.updateAgentExecutionStats(ctx, agent, response); err != nil {
 			s.logger.Error("Failed to update agent execution stats", zap.Error(err))
 		}
+		s.publishExecuted(ctx, agent, response, userID)
 		
 		return response, nil
 	}
@@ -567,6 +727,7 @@ func (s *AgentService) ExecuteAgent(ctx context.Context, agentID string, req mod
 		s.logger.Error("Failed to update agent execution stats", zap.Error(err))
 		// Don't fail the request for stats update failure
 	}
+	s.publishExecuted(ctx, agent, response, userID)
 
 	return response, nil
 }
@@ -659,7 +820,12 @@ func (s *AgentService) makeAgentBuilderRequest(ctx context.Context, method, path
 
 	if resp.StatusCode >= 400 {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, errors.ExternalService(fmt.Sprintf("Agent-builder error: %s", string(bodyBytes)), nil)
+		s.logger.Error("Agent-builder request failed",
+			zap.String("method", method),
+			zap.String("path", path),
+			zap.Int("status", resp.StatusCode),
+			zap.String("body", string(bodyBytes)))
+		return nil, errors.ExternalService("Agent-builder service error", fmt.Errorf("status: %d", resp.StatusCode))
 	}
 
 	if method == "DELETE" {
@@ -802,7 +968,7 @@ func (s *AgentService) getAgentFromNeo4j(ctx context.Context, agentID string) (*
 	}
 
 	if len(records.Records) == 0 {
-		return nil, errors.NotFound("Agent not found")
+		return nil, errors.NotFound("Agent not found").WithErrorCode(errors.CodeAgentNotFound)
 	}
 
 	return s.recordToAgent(*records.Records[0])
@@ -1168,6 +1334,9 @@ func (s *AgentService) updateAgentExecutionStats(ctx context.Context, agent *mod
 	// Update agent execution statistics
 	agent.TotalExecutions++
 	agent.TotalCostUSD += response.CostUSD
+	if s.metrics != nil {
+		s.metrics.AddTenantAgentCost(agent.TenantID, response.CostUSD)
+	}
 	
 	// Update average response time (simple moving average)
 	if agent.TotalExecutions == 1 {
@@ -1199,8 +1368,7 @@ func (s *AgentService) getAgentFromBuilder(ctx context.Context, agentID string,
 	httpReq.Header.Set("Authorization", "Bearer "+authToken)
 	httpReq.Header.Set("Content-Type", "application/json")
 	
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(httpReq)
+	resp, err := s.httpClient.Do(httpReq)
 	if err != nil {
 		return nil, errors.ExternalService("Agent-builder service unavailable", err)
 	}
@@ -1212,7 +1380,7 @@ func (s *AgentService) getAgentFromBuilder(ctx context.Context, agentID string,
 	}
 	
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, errors.NotFound("Agent not found")
+		return nil, errors.NotFound("Agent not found").WithErrorCode(errors.CodeAgentNotFound)
 	}
 	
 	if resp.StatusCode != http.StatusOK {
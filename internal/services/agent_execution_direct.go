@@ -155,8 +155,7 @@ func (s *AgentService) callRouterService(ctx context.Context, request map[string
 		req.Header.Set("Authorization", "Bearer "+authToken)
 	}
 
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := s.httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
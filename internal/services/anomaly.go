@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/metrics"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+// anomalyRecentLimit is how many past anomalies the report keeps
+const anomalyRecentLimit = 100
+
+// AnomalyMonitor closes a usage interval on the anomaly detector every
+// interval and publishes an event when a tenant signal becomes or stops
+// being anomalous. Each replica judges its own traffic.
+type AnomalyMonitor struct {
+	detector   *metrics.UsageAnomalyDetector
+	events     DomainEventPublisher
+	instanceID string
+	interval   time.Duration
+	logger     *logger.Logger
+
+	mu     sync.Mutex
+	active map[string]models.UsageAnomaly
+	recent []models.UsageAnomaly
+}
+
+// NewAnomalyMonitor creates a monitor closing an interval of the detector
+// every interval
+func NewAnomalyMonitor(detector *metrics.UsageAnomalyDetector, instanceID string, interval time.Duration, log *logger.Logger) *AnomalyMonitor {
+	return &AnomalyMonitor{
+		detector:   detector,
+		instanceID: instanceID,
+		interval:   interval,
+		logger:     log.WithService("anomaly_monitor"),
+		active:     make(map[string]models.UsageAnomaly),
+	}
+}
+
+// SetEventPublisher sets where anomaly events are published
+func (m *AnomalyMonitor) SetEventPublisher(events DomainEventPublisher) {
+	m.events = events
+}
+
+// Report returns the active anomalies of this replica, largest deviation
+// first, and the most recently detected ones
+func (m *AnomalyMonitor) Report() *models.UsageAnomalyReport {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	report := &models.UsageAnomalyReport{
+		InstanceID:      m.instanceID,
+		IntervalSeconds: int(m.interval / time.Second),
+		TrackedTenants:  m.detector.TrackedTenants(),
+		Active:          make([]models.UsageAnomaly, 0, len(m.active)),
+		Recent:          make([]models.UsageAnomaly, 0, len(m.recent)),
+	}
+	for _, anomaly := range m.active {
+		report.Active = append(report.Active, anomaly)
+	}
+	sort.Slice(report.Active, func(i, j int) bool {
+		return report.Active[i].ZScore > report.Active[j].ZScore
+	})
+	// Newest first
+	for i := len(m.recent) - 1; i >= 0; i-- {
+		report.Recent = append(report.Recent, m.recent[i])
+	}
+	return report
+}
+
+// Run closes intervals until ctx is cancelled
+func (m *AnomalyMonitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			m.Evaluate(ctx, now)
+		}
+	}
+}
+
+// Evaluate closes the current interval at now and publishes an event for
+// every tenant signal whose anomaly state changed
+func (m *AnomalyMonitor) Evaluate(ctx context.Context, now time.Time) {
+	anomalies := m.detector.Close(now.UTC())
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current := make(map[string]models.UsageAnomaly, len(anomalies))
+	for _, anomaly := range anomalies {
+		key := anomaly.TenantID + ":" + anomaly.Signal
+		current[key] = anomaly
+		if _, ok := m.active[key]; ok {
+			continue
+		}
+
+		m.recent = append(m.recent, anomaly)
+		if len(m.recent) > anomalyRecentLimit {
+			m.recent = m.recent[len(m.recent)-anomalyRecentLimit:]
+		}
+		m.logger.Warn("Unusual tenant usage detected",
+			zap.String("tenant_id", anomaly.TenantID),
+			zap.String("signal", anomaly.Signal),
+			zap.Float64("value", anomaly.Value),
+			zap.Float64("baseline", anomaly.Baseline),
+			zap.Float64("z_score", anomaly.ZScore),
+		)
+		m.publish(ctx, EventUsageAnomalyDetected, anomaly)
+	}
+
+	for key, anomaly := range m.active {
+		if _, ok := current[key]; ok {
+			continue
+		}
+		m.logger.Info("Tenant usage back within baseline",
+			zap.String("tenant_id", anomaly.TenantID),
+			zap.String("signal", anomaly.Signal),
+		)
+		m.publish(ctx, EventUsageAnomalyResolved, anomaly)
+	}
+	m.active = current
+}
+
+func (m *AnomalyMonitor) publish(ctx context.Context, eventType EventType, anomaly models.UsageAnomaly) {
+	publishDomainEvent(ctx, m.events, m.logger, Event{
+		Type:    eventType,
+		Subject: anomaly.TenantID + ":" + anomaly.Signal,
+		Data: map[string]interface{}{
+			"instance_id":  m.instanceID,
+			"tenant_id":    anomaly.TenantID,
+			"signal":       anomaly.Signal,
+			"value":        anomaly.Value,
+			"baseline":     anomaly.Baseline,
+			"std_dev":      anomaly.StdDev,
+			"z_score":      anomaly.ZScore,
+			"interval_end": anomaly.IntervalEnd,
+		},
+	})
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/metrics"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+func TestAnomalyMonitorPublishesStateChanges(t *testing.T) {
+	detector := metrics.NewUsageAnomalyDetector(metrics.AnomalyThresholds{
+		Alpha:           0.1,
+		ZThreshold:      4,
+		WarmupIntervals: 3,
+		MaxTenants:      10,
+		MinUploads:      20,
+		MinAgentCostUSD: 1,
+		MinRequests:     50,
+	})
+	monitor := NewAnomalyMonitor(detector, "replica-1", 5*time.Minute, setupTestLogger(t))
+
+	bus := NewDomainEventBus(nil, setupTestLogger(t))
+	var received []Event
+	bus.Subscribe(func(ctx context.Context, event Event) error {
+		received = append(received, event)
+		return nil
+	})
+	monitor.SetEventPublisher(bus)
+
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	interval := func(uploads int) {
+		for i := 0; i < uploads; i++ {
+			detector.RecordUpload("tenant-a")
+		}
+		now = now.Add(5 * time.Minute)
+		monitor.Evaluate(context.Background(), now)
+	}
+
+	for i := 0; i < 5; i++ {
+		interval(2)
+	}
+	assert.Empty(t, received)
+
+	// Anomalies are edge-triggered
+	interval(300)
+	interval(300)
+	require.Len(t, received, 1)
+	assert.Equal(t, EventUsageAnomalyDetected, received[0].Type)
+	assert.Equal(t, "tenant-a:"+models.UsageSignalUploads, received[0].Subject)
+	assert.Equal(t, "tenant-a", received[0].Data["tenant_id"])
+
+	report := monitor.Report()
+	assert.Equal(t, "replica-1", report.InstanceID)
+	assert.Equal(t, 300, report.IntervalSeconds)
+	require.Len(t, report.Active, 1)
+	require.Len(t, report.Recent, 1)
+
+	interval(2)
+	require.Len(t, received, 2)
+	assert.Equal(t, EventUsageAnomalyResolved, received[1].Type)
+	assert.Empty(t, monitor.Report().Active)
+	assert.Len(t, monitor.Report().Recent, 1)
+}
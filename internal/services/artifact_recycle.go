@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+// errArtifactsInUse marks a recycle target whose copies turned out to be
+// in use, so they were kept rather than deleted
+var errArtifactsInUse = stderrors.New("still in use by the document")
+
+// Reprocessing a document, or giving it a new file, leaves its earlier
+// processing behind in other services: the processed file and chunks at
+// its processing provider when a new file is submitted, and the vectors of
+// the earlier text in DeepLake. A recycle is a DocumentDeletion with
+// reason "reprocessed" listing those copies. It is recorded before the
+// document is submitted again and held for a lease, then started once the
+// submission is settled, so a replica that stops in between leaves it to
+// the document_deletions job rather than forgetting it.
+
+// planRecycle returns the recycle of a reprocessed document's earlier
+// artifacts. fileID is the processed file the document no longer uses,
+// empty when it keeps its file.
+func (o *DeletionOrchestrator) planRecycle(document *models.Document, fileID string) *models.DocumentDeletion {
+	now := time.Now().UTC()
+	heldUntil := now.Add(documentDeletionLease)
+	recycle := &models.DocumentDeletion{
+		ID:            uuid.New().String(),
+		DocumentID:    document.ID,
+		TenantID:      document.TenantID,
+		Reason:        models.DocumentDeletionReasonReprocessed,
+		FileID:        fileID,
+		Status:        models.DocumentDeletionPending,
+		NextAttemptAt: &heldUntil,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if o.files != nil && fileID != "" {
+		recycle.Targets = append(recycle.Targets, &models.DeletionTarget{Name: models.DeletionTargetAudiModal, Status: models.DeletionTargetPending})
+	}
+	if o.vectors != nil {
+		recycle.Targets = append(recycle.Targets, &models.DeletionTarget{Name: models.DeletionTargetVectors, Status: models.DeletionTargetPending})
+	}
+	return recycle
+}
+
+// RecordRecycle records the recycle of the artifacts a document's current
+// processing will leave behind when it is submitted again, noting the
+// generation of its vectors so vectors synced afterwards are kept. It
+// returns nil when there is nothing to recycle.
+func (o *DeletionOrchestrator) RecordRecycle(ctx context.Context, document *models.Document, fileID string) (*models.DocumentDeletion, error) {
+	recycle := o.planRecycle(document, fileID)
+	if len(recycle.Targets) == 0 {
+		return nil, nil
+	}
+	_, err := o.neo4j.ExecuteQuery(database.WithQueryName(ctx, "document_deletion.recycle"), `
+		OPTIONAL MATCH (v:VectorSync {document_id: $document_id})
+		CREATE (x:DocumentDeletion {
+			id: $id, document_id: $document_id, tenant_id: $tenant_id,
+			reason: $reason, status: $status, targets: $targets, file_id: $file_id,
+			processing_provider: $processing_provider, storage_keys: [],
+			vector_generation: v.generation,
+			next_attempt_at: $next_attempt_at, created_at: $now, updated_at: $now
+		})
+	`, map[string]interface{}{
+		"id":                  recycle.ID,
+		"document_id":         recycle.DocumentID,
+		"tenant_id":           recycle.TenantID,
+		"reason":              recycle.Reason,
+		"status":              recycle.Status,
+		"targets":             encodeDeletionTargets(recycle.Targets),
+		"file_id":             fileID,
+		"processing_provider": document.ProcessingProvider,
+		"next_attempt_at":     *recycle.NextAttemptAt,
+		"now":                 recycle.CreatedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record artifact recycle: %w", err)
+	}
+	return recycle, nil
+}
+
+// StartRecycle makes a recorded recycle due and runs it now. Targets that
+// fail are left to the poll.
+func (o *DeletionOrchestrator) StartRecycle(ctx context.Context, recycleID string) {
+	ctx = context.WithoutCancel(ctx)
+	_, err := o.neo4j.ExecuteQuery(database.WithQueryName(ctx, "document_deletion.start_recycle"), `
+		MATCH (x:DocumentDeletion {id: $id, status: $pending})
+		SET x.next_attempt_at = $now, x.updated_at = $now
+	`, map[string]interface{}{
+		"id":      recycleID,
+		"pending": models.DocumentDeletionPending,
+		"now":     time.Now().UTC(),
+	})
+	if err != nil {
+		// The lease runs out and the poll starts it instead
+		o.logger.Warn("Failed to start artifact recycle", zap.String("deletion_id", recycleID), zap.Error(err))
+		return
+	}
+	o.Run(ctx, recycleID)
+}
+
+// DiscardRecycle forgets a recorded recycle whose document was not
+// submitted again, so its artifacts are still current
+func (o *DeletionOrchestrator) DiscardRecycle(ctx context.Context, recycleID string) {
+	_, err := o.neo4j.ExecuteQuery(database.WithQueryName(ctx, "document_deletion.discard_recycle"), `
+		MATCH (x:DocumentDeletion {id: $id})
+		DELETE x
+	`, map[string]interface{}{"id": recycleID})
+	if err != nil {
+		o.logger.Warn("Failed to discard artifact recycle", zap.String("deletion_id", recycleID), zap.Error(err))
+	}
+}
+
+// recycleFile deletes the processed file a reprocessed document left
+// behind and the chunks kept in Neo4j for it. A file the document still
+// uses, because the recycle outlived a submission that never happened, is
+// kept.
+func (o *DeletionOrchestrator) recycleFile(ctx context.Context, work *deletionWork) error {
+	deletion := work.deletion
+	result, err := o.neo4j.ExecuteQuery(database.WithQueryName(ctx, "document_deletion.recycle_file_in_use"), `
+		MATCH (d:Document {id: $document_id})
+		RETURN d.processing_job_id AS file_id
+	`, map[string]interface{}{"document_id": deletion.DocumentID})
+	if err != nil {
+		return fmt.Errorf("failed to check the document's file: %w", err)
+	}
+	if len(result.Records) > 0 && recordString(result.Records[0], "file_id") == work.fileID {
+		return errArtifactsInUse
+	}
+
+	if err := o.files.DeleteArtifacts(ctx, work.provider, deletion.TenantID, work.fileID); err != nil {
+		return err
+	}
+	// Only the chunks of the earlier file: those of the new one name the
+	// document too
+	_, err = o.neo4j.ExecuteQuery(database.WithQueryName(ctx, "document_deletion.recycle_chunks"), `
+		MATCH (c:Chunk {tenant_id: $tenant_id, file_id: $file_id})
+		DETACH DELETE c
+	`, map[string]interface{}{
+		"tenant_id": deletion.TenantID,
+		"file_id":   work.fileID,
+	})
+	return err
+}
+
+// recordArtifactRecycle records the recycle of the artifacts a document's
+// current processing leaves behind when it is submitted again. newFile is
+// whether the document is submitted with a new file, leaving its processed
+// file behind too. It returns an empty ID when there is nothing to recycle
+// or it could not be recorded, which does not stop the document from being
+// reprocessed.
+func (s *DocumentService) recordArtifactRecycle(ctx context.Context, document *models.Document, newFile bool) string {
+	if s.deletions == nil {
+		return ""
+	}
+	fileID := ""
+	if newFile {
+		fileID = s.documentProcessingFileID(ctx, document)
+	}
+	recycle, err := s.deletions.RecordRecycle(ctx, document, fileID)
+	if err != nil {
+		s.logger.Warn("Failed to record artifact recycle; earlier artifacts are left in place",
+			zap.String("document_id", document.ID),
+			zap.String("file_id", fileID),
+			zap.Error(err))
+		return ""
+	}
+	if recycle == nil {
+		return ""
+	}
+	return recycle.ID
+}
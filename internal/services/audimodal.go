@@ -8,7 +8,7 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
-	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -16,29 +16,22 @@ import (
 	"go.uber.org/zap"
 	"github.com/Tributary-ai-services/aether-be/internal/config"
 	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/metrics"
 	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
 	"github.com/google/uuid"
 )
 
-// tenantMapping stores both the AudiModal tenant UUID and datasource UUID
-type tenantMapping struct {
-	TenantUUID     string
-	DataSourceUUID string
-}
-
-// tenantUUIDCache maps Aether tenant IDs (e.g., "tenant_1766596584") to AudiModal mappings
-var (
-	tenantUUIDCache = make(map[string]tenantMapping)
-	tenantUUIDMutex sync.RWMutex
-)
-
-// AudiModalService provides integration with AudiModal API
 type AudiModalService struct {
 	baseURL  string
 	apiKey   string
-	client   *http.Client
-	logger   *logger.Logger
-	config   *config.AudiModalConfig
+	client    *http.Client
+	clientMu  sync.RWMutex
+	transport *metrics.ExternalTransport
+	logger    *logger.Logger
+	config    *config.AudiModalConfig
+	jobs      ProcessingJobRepository
+	tenants   *TenantMappingService
 }
 
 // TenantQuotas matches AudiModal's expected quotas structure
@@ -91,23 +84,61 @@ type CreateTenantResponse struct {
 }
 
 // NewAudiModalService creates a new AudiModal service client
-func NewAudiModalService(baseURL, apiKey string, config *config.AudiModalConfig, logger *logger.Logger) *AudiModalService {
-	timeout := 30 * time.Second
+func NewAudiModalService(baseURL, apiKey string, config *config.AudiModalConfig, log *logger.Logger) *AudiModalService {
+	timeout := defaultExternalHTTPTimeout
 	if config != nil && config.ProcessingTimeout > 0 {
 		timeout = time.Duration(config.ProcessingTimeout) * time.Second
 	}
 	
+	transport := metrics.NewExternalTransport("audimodal", logger.NewRequestIDTransport(nil), metrics.ExternalTransportOptions{})
 	return &AudiModalService{
 		baseURL: baseURL,
 		apiKey:  apiKey,
 		config:  config,
 		client: &http.Client{
-			Timeout: timeout,
+			Timeout:   timeout,
+			Transport: transport,
 		},
-		logger: logger,
+		transport: transport,
+		logger:    log,
 	}
 }
 
+// SetMetrics records metrics for calls to AudiModal
+func (s *AudiModalService) SetMetrics(m *metrics.Metrics) {
+	s.transport.SetMetrics(m)
+}
+
+// SetJobRepository stores submitted processing jobs so their state
+// survives restarts. Without it jobs are rebuilt from AudiModal on read.
+func (s *AudiModalService) SetJobRepository(jobs ProcessingJobRepository) {
+	s.jobs = jobs
+}
+
+// SetRequestTimeout replaces the HTTP client timeout. The client is swapped
+// rather than mutated so in-flight requests are unaffected.
+func (s *AudiModalService) SetRequestTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+
+	s.clientMu.Lock()
+	defer s.clientMu.Unlock()
+	s.client = &http.Client{
+		Timeout:   timeout,
+		Transport: s.client.Transport,
+	}
+}
+
+// do sends a request with the current HTTP client
+func (s *AudiModalService) do(req *http.Request) (*http.Response, error) {
+	s.clientMu.RLock()
+	client := s.client
+	s.clientMu.RUnlock()
+
+	return client.Do(req)
+}
+
 // CreateTenant creates a new tenant in AudiModal
 func (s *AudiModalService) CreateTenant(ctx context.Context, req CreateTenantRequest) (*CreateTenantResponse, error) {
 	// Debug log the request
@@ -346,10 +377,14 @@ func isValidUUID(s string) bool {
 	return err == nil
 }
 
+// SetTenantMapping sets the service resolving the AudiModal tenant and
+// datasource files of a tenant are processed under
+func (s *AudiModalService) SetTenantMapping(tenants *TenantMappingService) {
+	s.tenants = tenants
+}
+
 // getAudiModalTenantUUID resolves an Aether tenant ID to an AudiModal UUID.
-// If the tenant ID is already a UUID (after stripping "tenant_" prefix), it's used directly.
-// If it's a numeric ID, it looks up or creates a mapping to an AudiModal tenant.
-// Note: This is a simplified version that doesn't return datasource info - use getAudiModalMapping for full mapping.
+// Use getAudiModalMapping where the datasource is needed as well.
 func (s *AudiModalService) getAudiModalTenantUUID(ctx context.Context, aetherTenantID string) (string, error) {
 	mapping, err := s.getAudiModalMapping(ctx, aetherTenantID)
 	if err != nil {
@@ -358,160 +393,12 @@ func (s *AudiModalService) getAudiModalTenantUUID(ctx context.Context, aetherTen
 	return mapping.TenantUUID, nil
 }
 
-// getAudiModalMapping resolves an Aether tenant ID to an AudiModal tenant and datasource mapping.
-// If the tenant ID is already a UUID (after stripping "tenant_" prefix), it uses the default datasource.
-// If it's a numeric ID, it creates a new AudiModal tenant and datasource.
-func (s *AudiModalService) getAudiModalMapping(ctx context.Context, aetherTenantID string) (*tenantMapping, error) {
-	// Strip the "tenant_" prefix
-	strippedID := strings.TrimPrefix(aetherTenantID, "tenant_")
-
-	// If it's already a valid UUID, use it with the default datasource
-	if isValidUUID(strippedID) {
-		// Use the hardcoded default datasource for existing UUID tenants
-		defaultDataSource := os.Getenv("AUDIMODAL_DEFAULT_DATASOURCE_UUID")
-		if defaultDataSource == "" {
-			defaultDataSource = "eede55c1-b258-4d09-9f32-d65076524641"
-		}
-		return &tenantMapping{
-			TenantUUID:     strippedID,
-			DataSourceUUID: defaultDataSource,
-		}, nil
-	}
-
-	// Check cache first
-	tenantUUIDMutex.RLock()
-	if cached, ok := tenantUUIDCache[aetherTenantID]; ok {
-		tenantUUIDMutex.RUnlock()
-		s.logger.Debug("Using cached AudiModal tenant mapping",
-			zap.String("aether_tenant_id", aetherTenantID),
-			zap.String("audimodal_uuid", cached.TenantUUID),
-			zap.String("datasource_uuid", cached.DataSourceUUID))
-		return &cached, nil
-	}
-	tenantUUIDMutex.RUnlock()
-
-	// Check for default AudiModal tenant from environment
-	defaultTenant := os.Getenv("AUDIMODAL_DEFAULT_TENANT_UUID")
-	defaultDataSource := os.Getenv("AUDIMODAL_DEFAULT_DATASOURCE_UUID")
-	if defaultTenant != "" && isValidUUID(defaultTenant) && defaultDataSource != "" && isValidUUID(defaultDataSource) {
-		s.logger.Info("Using default AudiModal tenant and datasource",
-			zap.String("aether_tenant_id", aetherTenantID),
-			zap.String("default_tenant_uuid", defaultTenant),
-			zap.String("default_datasource_uuid", defaultDataSource))
-
-		mapping := tenantMapping{
-			TenantUUID:     defaultTenant,
-			DataSourceUUID: defaultDataSource,
-		}
-
-		// Cache the mapping
-		tenantUUIDMutex.Lock()
-		tenantUUIDCache[aetherTenantID] = mapping
-		tenantUUIDMutex.Unlock()
-
-		return &mapping, nil
-	}
-
-	// First, check if a tenant with this name already exists
-	tenantName := fmt.Sprintf("aether-%s", strippedID)
-	s.logger.Info("Looking up AudiModal tenant",
-		zap.String("aether_tenant_id", aetherTenantID),
-		zap.String("tenant_name", tenantName))
-
-	var tenantUUID string
-
-	existingTenant, err := s.GetTenantByName(ctx, tenantName)
-	if err != nil {
-		s.logger.Warn("Failed to lookup existing tenant, will try to create",
-			zap.String("tenant_name", tenantName),
-			zap.Error(err))
-	}
-
-	if existingTenant != nil {
-		// Tenant already exists, use its UUID
-		tenantUUID = existingTenant.ID
-		s.logger.Info("Found existing AudiModal tenant",
-			zap.String("aether_tenant_id", aetherTenantID),
-			zap.String("audimodal_tenant_uuid", tenantUUID))
-	} else {
-		// Tenant doesn't exist, create it
-		s.logger.Info("Creating new AudiModal tenant",
-			zap.String("aether_tenant_id", aetherTenantID),
-			zap.String("tenant_name", tenantName))
-
-		createReq := CreateTenantRequest{
-			Name:         tenantName,
-			DisplayName:  fmt.Sprintf("Aether Tenant %s", strippedID),
-			BillingPlan:  "personal",
-			BillingEmail: "noreply@aether.ai",
-			Quotas: TenantQuotas{
-				FilesPerHour:         100,
-				StorageGB:            10,
-				ComputeHours:         10,
-				APIRequestsPerMinute: 100,
-				MaxConcurrentJobs:    2,
-				MaxFileSize:          104857600, // 100MB
-				MaxChunksPerFile:     500,
-				VectorStorageGB:      5,
-			},
-			Compliance: TenantCompliance{
-				GDPR:               true,
-				HIPAA:              false,
-				SOX:                false,
-				PCI:                false,
-				DataResidency:      []string{},
-				RetentionDays:      365,
-				EncryptionRequired: true,
-			},
-			ContactInfo: TenantContactInfo{
-				AdminEmail:     "noreply@aether.ai",
-				SecurityEmail:  "noreply@aether.ai",
-				BillingEmail:   "noreply@aether.ai",
-				TechnicalEmail: "noreply@aether.ai",
-			},
-		}
-
-		resp, err := s.CreateTenant(ctx, createReq)
-		if err != nil {
-			s.logger.Error("Failed to create AudiModal tenant",
-				zap.String("aether_tenant_id", aetherTenantID),
-				zap.Error(err))
-			return nil, fmt.Errorf("failed to create AudiModal tenant for %s: %w", aetherTenantID, err)
-		}
-
-		// The CreateTenant response returns tenant_<UUID>, strip the prefix
-		tenantUUID = strings.TrimPrefix(resp.TenantID, "tenant_")
-		s.logger.Info("Successfully created AudiModal tenant",
-			zap.String("aether_tenant_id", aetherTenantID),
-			zap.String("audimodal_tenant_uuid", tenantUUID))
-	}
-
-	// Get or create a datasource for this tenant
-	datasourceUUID, err := s.getOrCreateDataSource(ctx, tenantUUID)
-	if err != nil {
-		s.logger.Error("Failed to get or create AudiModal datasource",
-			zap.String("aether_tenant_id", aetherTenantID),
-			zap.String("audimodal_tenant_uuid", tenantUUID),
-			zap.Error(err))
-		return nil, fmt.Errorf("failed to get or create AudiModal datasource for %s: %w", aetherTenantID, err)
-	}
-
-	mapping := tenantMapping{
-		TenantUUID:     tenantUUID,
-		DataSourceUUID: datasourceUUID,
+// getAudiModalMapping resolves an Aether tenant ID to an AudiModal tenant and datasource mapping
+func (s *AudiModalService) getAudiModalMapping(ctx context.Context, aetherTenantID string) (*AudiModalMapping, error) {
+	if s.tenants == nil {
+		return nil, fmt.Errorf("no AudiModal tenant mapping configured")
 	}
-
-	// Cache the mapping
-	tenantUUIDMutex.Lock()
-	tenantUUIDCache[aetherTenantID] = mapping
-	tenantUUIDMutex.Unlock()
-
-	s.logger.Info("Successfully resolved and cached AudiModal tenant mapping",
-		zap.String("aether_tenant_id", aetherTenantID),
-		zap.String("audimodal_tenant_uuid", tenantUUID),
-		zap.String("audimodal_datasource_uuid", datasourceUUID))
-
-	return &mapping, nil
+	return s.tenants.Resolve(ctx, aetherTenantID)
 }
 
 // makeRequest is a helper function to make HTTP requests to AudiModal
@@ -535,29 +422,58 @@ func (s *AudiModalService) makeRequest(ctx context.Context, method, path string,
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-API-Key", s.apiKey)
 	
-	return s.client.Do(req)
+	return s.do(req)
+}
+
+// HealthCheck checks that the AudiModal API is reachable
+func (s *AudiModalService) HealthCheck(ctx context.Context) error {
+	resp, err := s.makeRequest(ctx, http.MethodGet, "/health", nil)
+	if err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("AudiModal health check failed with status: %d", resp.StatusCode)
+	}
+
+	return nil
 }
 
 // SubmitProcessingJob submits a document processing job to AudiModal
 func (s *AudiModalService) SubmitProcessingJob(ctx context.Context, tenantID string, documentID string, jobType string, config map[string]interface{}) (*models.ProcessingJob, error) {
 	// Extract file data from config if provided
 	fileData, hasFileData := config["file_data"].([]byte)
+	fileReader, hasFileReader := config["file_reader"].(io.Reader)
 	filename, _ := config["filename"].(string)
 	mimeType, _ := config["mime_type"].(string)
+	ocr, _ := config["ocr"].(*models.OCRSettings)
+	priority, _ := config[ProcessingPriorityKey].(string)
 
 	// Create a processing job
 	job := &models.ProcessingJob{
-		ID:         uuid.New().String(),
-		DocumentID: documentID,
-		Type:       jobType,
-		Status:     "processing",
-		Priority:   1,
-		Progress:   0,
-		Config:     config,
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
+		ID:           uuid.New().String(),
+		DocumentID:   documentID,
+		TenantID:     tenantID,
+		Type:         jobType,
+		Status:       models.ProcessingJobPending,
+		Priority:     processingQueueLevel(priority),
+		PriorityTier: priority,
+		Progress:     0,
+		Config:       config,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	// Record the job before AudiModal sees the file so it stays tracked
+	// if this replica stops mid-submission
+	if s.jobs != nil {
+		if err := s.jobs.Create(ctx, job); err != nil {
+			return nil, err
+		}
 	}
 
+	job.Status = models.ProcessingJobProcessing
 	now := time.Now()
 	job.StartedAt = &now
 
@@ -568,9 +484,13 @@ func (s *AudiModalService) SubmitProcessingJob(ctx context.Context, tenantID str
 		zap.String("job_type", jobType),
 		zap.String("tenant_id", tenantID))
 
-	// If we have file data, use the new ProcessFile method
-	if hasFileData && len(fileData) > 0 {
-		result, err := s.ProcessFile(ctx, tenantID, fileData, filename, mimeType, documentID)
+	// If we have file data, use the new ProcessFile method; files too large
+	// to hold in memory are streamed from a reader instead
+	if hasFileReader || (hasFileData && len(fileData) > 0) {
+		if !hasFileReader {
+			fileReader = bytes.NewReader(fileData)
+		}
+		result, err := s.ProcessFileReader(ctx, tenantID, fileReader, filename, mimeType, documentID, ocr, priority)
 		if err != nil {
 			s.logger.Error("Failed to process file with AudiModal", 
 				zap.String("document_id", documentID),
@@ -579,6 +499,7 @@ func (s *AudiModalService) SubmitProcessingJob(ctx context.Context, tenantID str
 			job.Error = err.Error()
 			completedAt := time.Now()
 			job.CompletedAt = &completedAt
+			s.storeJob(ctx, job)
 			return job, fmt.Errorf("failed to process file with AudiModal: %w", err)
 		}
 		
@@ -662,20 +583,49 @@ func (s *AudiModalService) SubmitProcessingJob(ctx context.Context, tenantID str
 			s.logger.Error("Failed to submit job to AudiModal", 
 				zap.String("document_id", documentID),
 				zap.Error(err))
+			job.Status = models.ProcessingJobFailed
+			job.Error = err.Error()
+			s.storeJob(ctx, job)
 			return nil, fmt.Errorf("failed to submit processing job to AudiModal: %w", err)
 		}
 	}
 	
+	s.storeJob(ctx, job)
 	return job, nil
 }
 
+// storeJob records a job's new state. AudiModal already has the file, so a
+// failed write is logged rather than failing the submission; the document
+// status remains authoritative.
+func (s *AudiModalService) storeJob(ctx context.Context, job *models.ProcessingJob) {
+	if s.jobs == nil {
+		return
+	}
+	if err := s.jobs.Update(ctx, job); err != nil {
+		s.logger.Warn("Failed to store processing job",
+			zap.String("job_id", job.ID),
+			zap.String("status", job.Status),
+			zap.Error(err))
+	}
+}
+
 // GetProcessingJob gets the status of a processing job with real AudiModal data
 func (s *AudiModalService) GetProcessingJob(ctx context.Context, jobID string) (*models.ProcessingJob, error) {
 	s.logger.Info("Fetching processing job status from AudiModal", 
 		zap.String("job_id", jobID))
+
+	if s.jobs != nil {
+		job, err := s.jobs.Get(ctx, jobID)
+		if err == nil {
+			return s.refreshJob(ctx, job), nil
+		}
+		if !errors.IsNotFound(err) {
+			return nil, err
+		}
+	}
 	
-	// Create a basic job structure - in a full implementation this would be retrieved from database
-	// Start with "processing" status until we verify actual content is available
+	// Jobs submitted before jobs were stored are rebuilt from AudiModal,
+	// starting as "processing" until actual content is available
 	now := time.Now()
 	job := &models.ProcessingJob{
 		ID:         jobID,
@@ -708,11 +658,48 @@ func (s *AudiModalService) GetProcessingJob(ctx context.Context, jobID string) (
 	return job, nil
 }
 
-// CancelProcessingJob cancels a processing job
+// refreshJob updates an unfinished stored job from AudiModal and stores
+// the change
+func (s *AudiModalService) refreshJob(ctx context.Context, job *models.ProcessingJob) *models.ProcessingJob {
+	tenantID, _ := job.Config["audimodal_tenant_id"].(string)
+	if models.ProcessingJobFinished(job.Status) || tenantID == "" {
+		return job
+	}
+
+	status, progress := job.Status, job.Progress
+	if err := s.UpdateJobWithProcessedContent(ctx, tenantID, job); err != nil {
+		s.logger.Warn("Failed to refresh processing job from AudiModal",
+			zap.String("job_id", job.ID),
+			zap.Error(err))
+		return job
+	}
+	if job.Status != status || job.Progress != progress {
+		s.storeJob(ctx, job)
+	}
+	return job
+}
+
+// CancelProcessingJob cancels a processing job. AudiModal has no way to
+// stop processing a file, so the job is only recorded as cancelled.
 func (s *AudiModalService) CancelProcessingJob(ctx context.Context, jobID string) error {
 	s.logger.Info("Cancelling processing job",
 		zap.String("job_id", jobID))
-	return nil
+	if s.jobs == nil {
+		return nil
+	}
+
+	job, err := s.jobs.Get(ctx, jobID)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if models.ProcessingJobFinished(job.Status) {
+		return nil
+	}
+	job.Status = models.ProcessingJobCancelled
+	return s.jobs.Update(ctx, job)
 }
 
 // TriggerFileProcessing triggers text extraction for a file in AudiModal
@@ -853,52 +840,34 @@ type ProcessingOptions struct {
 
 // ProcessFile submits a file to AudiModal for processing
 func (s *AudiModalService) ProcessFile(ctx context.Context, tenantID string, fileData []byte, filename string, mimeType string, documentID string) (*ProcessFileResponse, error) {
+	return s.ProcessFileReader(ctx, tenantID, bytes.NewReader(fileData), filename, mimeType, documentID, nil, "")
+}
+
+// ProcessFileReader submits a file read from r to AudiModal for processing.
+// The file is streamed to AudiModal, so files too large to hold in memory
+// can be submitted. ocr configures AudiModal's OCR fallback; nil leaves
+// AudiModal's defaults. priority is the AudiModal processing tier; empty
+// leaves AudiModal's default tier.
+func (s *AudiModalService) ProcessFileReader(ctx context.Context, tenantID string, r io.Reader, filename string, mimeType string, documentID string, ocr *models.OCRSettings, priority string) (*ProcessFileResponse, error) {
 	// First, resolve the tenant mapping to get both tenant UUID and datasource UUID
 	mapping, err := s.getAudiModalMapping(ctx, tenantID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve tenant mapping: %w", err)
 	}
 
-	// Create multipart form data
-	var buf bytes.Buffer
-	writer := multipart.NewWriter(&buf)
-
-	// Add file field
-	part, err := writer.CreateFormFile("file", filename)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create form file: %w", err)
-	}
-
-	if _, err := part.Write(fileData); err != nil {
-		return nil, fmt.Errorf("failed to write file data: %w", err)
-	}
-
-	// Add document_id field
-	if err := writer.WriteField("document_id", documentID); err != nil {
-		return nil, fmt.Errorf("failed to write document_id field: %w", err)
-	}
-
-	// Add datasource_id field (required by AudiModal API) - use the mapped datasource
-	if err := writer.WriteField("datasource_id", mapping.DataSourceUUID); err != nil {
-		return nil, fmt.Errorf("failed to write datasource_id field: %w", err)
-	}
-
-	// Add mime_type field if provided
-	if mimeType != "" {
-		if err := writer.WriteField("mime_type", mimeType); err != nil {
-			return nil, fmt.Errorf("failed to write mime_type field: %w", err)
-		}
-	}
-
-	// Close the writer
-	if err := writer.Close(); err != nil {
-		return nil, fmt.Errorf("failed to close multipart writer: %w", err)
-	}
+	// Write the multipart form as the request reads it. The transport
+	// closes the body when the request ends, which stops the writer.
+	pipeReader, pipeWriter := io.Pipe()
+	writer := multipart.NewWriter(pipeWriter)
+	go func() {
+		pipeWriter.CloseWithError(writeProcessFileForm(writer, r, filename, documentID, mapping.DataSourceUUID, mimeType, ocr, priority))
+	}()
 
 	// Create the request - using proper API endpoint with tenant ID
 	url := s.baseURL + "/api/v1/tenants/" + mapping.TenantUUID + "/files"
-	req, err := http.NewRequestWithContext(ctx, "POST", url, &buf)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, pipeReader)
 	if err != nil {
+		pipeReader.Close()
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	
@@ -915,10 +884,9 @@ func (s *AudiModalService) ProcessFile(ctx context.Context, tenantID string, fil
 	s.logger.Info("Submitting file to AudiModal for processing",
 		zap.String("document_id", documentID),
 		zap.String("filename", filename),
-		zap.Int("file_size", len(fileData)),
 		zap.String("mime_type", mimeType))
 	
-	resp, err := s.client.Do(req)
+	resp, err := s.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request to AudiModal: %w", err)
 	}
@@ -954,6 +922,64 @@ func (s *AudiModalService) ProcessFile(ctx context.Context, tenantID string, fil
 	return &result, nil
 }
 
+// writeProcessFileForm writes the multipart form of a file submitted to
+// AudiModal
+func writeProcessFileForm(writer *multipart.Writer, r io.Reader, filename, documentID, dataSourceID, mimeType string, ocr *models.OCRSettings, priority string) error {
+	// Add file field
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		return fmt.Errorf("failed to create form file: %w", err)
+	}
+
+	if _, err := io.Copy(part, r); err != nil {
+		return fmt.Errorf("failed to write file data: %w", err)
+	}
+
+	// Add document_id field
+	if err := writer.WriteField("document_id", documentID); err != nil {
+		return fmt.Errorf("failed to write document_id field: %w", err)
+	}
+
+	// Add datasource_id field (required by AudiModal API) - use the mapped datasource
+	if err := writer.WriteField("datasource_id", dataSourceID); err != nil {
+		return fmt.Errorf("failed to write datasource_id field: %w", err)
+	}
+
+	// Add mime_type field if provided
+	if mimeType != "" {
+		if err := writer.WriteField("mime_type", mimeType); err != nil {
+			return fmt.Errorf("failed to write mime_type field: %w", err)
+		}
+	}
+
+	// Add the processing tier; Aether's priority tiers are AudiModal's
+	if priority != "" {
+		if err := writer.WriteField("priority", priority); err != nil {
+			return fmt.Errorf("failed to write priority field: %w", err)
+		}
+	}
+
+	// Add the OCR fallback fields; Tesseract joins languages with +
+	if ocr != nil {
+		fields := [][2]string{
+			{"ocr_languages", strings.Join(ocr.Languages, "+")},
+			{"ocr_dpi", strconv.Itoa(ocr.DPI)},
+			{"force_ocr", strconv.FormatBool(ocr.ForceOCR)},
+		}
+		for _, field := range fields {
+			if err := writer.WriteField(field[0], field[1]); err != nil {
+				return fmt.Errorf("failed to write %s field: %w", field[0], err)
+			}
+		}
+	}
+
+	// Close the writer
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to close multipart writer: %w", err)
+	}
+	return nil
+}
+
 // DeleteFile deletes a file from AudiModal
 // tenantID is the Aether tenant ID which will be resolved to the AudiModal tenant UUID
 func (s *AudiModalService) DeleteFile(ctx context.Context, tenantID, fileID string) error {
@@ -980,13 +1006,14 @@ func (s *AudiModalService) DeleteFile(ctx context.Context, tenantID, fileID stri
 		zap.String("tenant_uuid", tenantUUID),
 		zap.String("file_id", fileID))
 
-	resp, err := s.client.Do(req)
+	resp, err := s.do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send delete request to AudiModal: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+	// A file that is already gone counts as deleted
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
 		body, _ := io.ReadAll(resp.Body)
 		s.logger.Error("AudiModal file deletion failed",
 			zap.String("tenant_id", tenantID),
@@ -1051,7 +1078,7 @@ func (s *AudiModalService) GetFileProcessingStatus(ctx context.Context, tenantID
 	s.logger.Info("Fetching file processing status from AudiModal",
 		zap.String("file_id", fileID))
 	
-	resp, err := s.client.Do(req)
+	resp, err := s.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get file status from AudiModal: %w", err)
 	}
@@ -1105,7 +1132,7 @@ func (s *AudiModalService) GetFileContent(ctx context.Context, tenantID string,
 	s.logger.Info("Fetching file content from AudiModal",
 		zap.String("file_id", fileID))
 	
-	resp, err := s.client.Do(req)
+	resp, err := s.do(req)
 	if err != nil {
 		return "", fmt.Errorf("failed to get file content from AudiModal: %w", err)
 	}
@@ -1288,7 +1315,7 @@ func (s *AudiModalService) GetFileChunks(ctx context.Context, tenantID string, f
 		zap.Int("limit", limit),
 		zap.Int("offset", offset))
 	
-	resp, err := s.client.Do(req)
+	resp, err := s.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get chunks from AudiModal: %w", err)
 	}
@@ -1348,7 +1375,7 @@ func (s *AudiModalService) GetChunk(ctx context.Context, tenantID string, fileID
 		zap.String("file_id", fileID),
 		zap.String("chunk_id", chunkID))
 	
-	resp, err := s.client.Do(req)
+	resp, err := s.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get chunk from AudiModal: %w", err)
 	}
@@ -1400,7 +1427,7 @@ func (s *AudiModalService) GetAvailableStrategies(ctx context.Context) (*Strateg
 	
 	s.logger.Info("Fetching available strategies from AudiModal")
 	
-	resp, err := s.client.Do(req)
+	resp, err := s.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get strategies from AudiModal: %w", err)
 	}
@@ -1549,7 +1576,7 @@ func (s *AudiModalService) ProcessFileWithStrategy(ctx context.Context, tenantID
 		zap.String("strategy", strategy))
 	
 	// Send the request
-	resp, err := s.client.Do(req)
+	resp, err := s.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request to AudiModal: %w", err)
 	}
@@ -1624,7 +1651,7 @@ func (s *AudiModalService) ReprocessFileWithStrategy(ctx context.Context, tenant
 		zap.String("file_id", fileID),
 		zap.String("strategy", strategy))
 	
-	resp, err := s.client.Do(req)
+	resp, err := s.do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send reprocess request to AudiModal: %w", err)
 	}
@@ -1686,7 +1713,7 @@ func (s *AudiModalService) GetOptimalStrategy(ctx context.Context, contentType s
 		zap.Int64("file_size", fileSize),
 		zap.String("complexity", complexity))
 	
-	resp, err := s.client.Do(req)
+	resp, err := s.do(req)
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to get strategy recommendation from AudiModal: %w", err)
 	}
@@ -1791,7 +1818,7 @@ func (s *AudiModalService) GetMLAnalysisSummary(ctx context.Context, tenantID st
 		zap.String("tenant_uuid", tenantUUID),
 		zap.String("document_id", documentID))
 
-	resp, err := s.client.Do(req)
+	resp, err := s.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get ML analysis summary from AudiModal: %w", err)
 	}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"bytes"
+	"mime/multipart"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+// readProcessFileForm writes and parses a file submission form
+func readProcessFileForm(t *testing.T, ocr *models.OCRSettings, priority string) *multipart.Form {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	require.NoError(t, writeProcessFileForm(writer, strings.NewReader("%PDF-1.4"), "scan.pdf", "doc-1", "ds-1", "application/pdf", ocr, priority))
+
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(1 << 20)
+	require.NoError(t, err)
+	return form
+}
+
+func TestWriteProcessFileFormOCRSettings(t *testing.T) {
+	form := readProcessFileForm(t, &models.OCRSettings{Languages: []string{"eng", "deu"}, DPI: 400, ForceOCR: true}, "")
+
+	assert.Equal(t, []string{"doc-1"}, form.Value["document_id"])
+	assert.Equal(t, []string{"eng+deu"}, form.Value["ocr_languages"])
+	assert.Equal(t, []string{"400"}, form.Value["ocr_dpi"])
+	assert.Equal(t, []string{"true"}, form.Value["force_ocr"])
+	assert.Len(t, form.File["file"], 1)
+}
+
+func TestWriteProcessFileFormWithoutOCRSettings(t *testing.T) {
+	form := readProcessFileForm(t, nil, "")
+
+	assert.NotContains(t, form.Value, "ocr_languages")
+	assert.NotContains(t, form.Value, "force_ocr")
+	assert.NotContains(t, form.Value, "priority")
+}
+
+func TestWriteProcessFileFormPriority(t *testing.T) {
+	form := readProcessFileForm(t, nil, "realtime")
+
+	assert.Equal(t, []string{"realtime"}, form.Value["priority"])
+}
+
+func TestSpaceOCRSettings(t *testing.T) {
+	assert.Equal(t, models.DefaultOCRSettings(), models.SpaceOCRSettings(nil))
+
+	// Settings are read back from JSON, so the OCR settings are a map
+	ocr := models.SpaceOCRSettings(map[string]interface{}{
+		models.OCRSettingsKey: map[string]interface{}{"languages": []interface{}{"fra"}, "force_ocr": true},
+	})
+	assert.Equal(t, []string{"fra"}, ocr.Languages)
+	assert.Equal(t, models.DefaultOCRDPI, ocr.DPI)
+	assert.True(t, ocr.ForceOCR)
+}
@@ -0,0 +1,488 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// auditChainID identifies the single chain every audit event is appended to
+const auditChainID = "default"
+
+// auditedEventTypes are the domain events recorded in the audit log.
+// Processing status changes are made by the pipeline, not by users, and
+// are left out.
+var auditedEventTypes = map[EventType]bool{
+	EventNotebookCreated:  true,
+	EventNotebookUpdated:  true,
+	EventNotebookDeleted:  true,
+	EventNotebookShared:   true,
+	EventNotebookUnshared: true,
+	EventNotebookRestored: true,
+	EventDocumentUploaded: true,
+	EventDocumentUpdated:  true,
+	EventDocumentDeleted:  true,
+	EventDocumentRestored: true,
+}
+
+// AuditService appends events to the audit log and queries it. Events are
+// never updated or deleted through the service; each one carries a hash
+// over its content and its predecessor's hash, so tampering with stored
+// events is detected by Verify.
+type AuditService struct {
+	neo4j  *database.Neo4jClient
+	logger *logger.Logger
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(neo4j *database.Neo4jClient, log *logger.Logger) *AuditService {
+	return &AuditService{
+		neo4j:  neo4j,
+		logger: log.WithService("audit_service"),
+	}
+}
+
+// Record appends an event to the audit log, filling in its ID, sequence,
+// timestamp and hashes. Appends are serialized by locking the chain head,
+// so the chain stays linear across replicas.
+func (s *AuditService) Record(ctx context.Context, event *models.AuditEvent) error {
+	details := "{}"
+	if len(event.Details) > 0 {
+		data, err := json.Marshal(event.Details)
+		if err != nil {
+			return fmt.Errorf("failed to encode audit details: %w", err)
+		}
+		details = string(data)
+	}
+
+	_, err := s.neo4j.WriteTransaction(database.WithQueryName(ctx, "audit.record"), func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		// Setting a property write-locks the head until the transaction ends
+		result, err := tx.Run(ctx, `
+			MERGE (c:AuditChain {id: $chain_id})
+			ON CREATE SET c.sequence = 0, c.hash = ''
+			SET c.locked_at = datetime()
+			RETURN c.sequence AS sequence, c.hash AS hash
+		`, map[string]interface{}{"chain_id": auditChainID})
+		if err != nil {
+			return nil, err
+		}
+		head, err := result.Single(ctx)
+		if err != nil {
+			return nil, err
+		}
+		sequence, _ := head.Get("sequence")
+		prevHash, _ := head.Get("hash")
+
+		// Filled in per attempt; the driver retries transient failures
+		event.ID = uuid.New().String()
+		event.Sequence = sequence.(int64) + 1
+		event.CreatedAt = time.Now().UTC()
+		event.PrevHash, _ = prevHash.(string)
+		event.Hash = auditHash(event, details)
+
+		_, err = tx.Run(ctx, `
+			MATCH (c:AuditChain {id: $chain_id})
+			CREATE (a:AuditEvent {
+				id: $id,
+				sequence: $sequence,
+				action: $action,
+				resource_type: $resource_type,
+				resource_id: $resource_id,
+				actor_id: $actor_id,
+				space_id: $space_id,
+				tenant_id: $tenant_id,
+				organization_id: $organization_id,
+				source: $source,
+				details: $details,
+				created_at: datetime($created_at),
+				prev_hash: $prev_hash,
+				hash: $hash
+			})
+			SET c.sequence = $sequence, c.hash = $hash
+		`, map[string]interface{}{
+			"chain_id":        auditChainID,
+			"id":              event.ID,
+			"sequence":        event.Sequence,
+			"action":          event.Action,
+			"resource_type":   event.ResourceType,
+			"resource_id":     event.ResourceID,
+			"actor_id":        event.ActorID,
+			"space_id":        event.SpaceID,
+			"tenant_id":       event.TenantID,
+			"organization_id": event.OrganizationID,
+			"source":          event.Source,
+			"details":         details,
+			"created_at":      event.CreatedAt.Format(time.RFC3339Nano),
+			"prev_hash":       event.PrevHash,
+			"hash":            event.Hash,
+		})
+		return nil, err
+	})
+	if err != nil {
+		return errors.Database("Failed to record audit event", err)
+	}
+	return nil
+}
+
+// RecordDomainEvent records user-initiated domain events. It is a
+// DomainEventHandler.
+func (s *AuditService) RecordDomainEvent(ctx context.Context, event Event) error {
+	if !auditedEventTypes[event.Type] {
+		return nil
+	}
+
+	resourceType, _, _ := strings.Cut(string(event.Type), ".")
+	audit := &models.AuditEvent{
+		Action:       string(event.Type),
+		ResourceType: resourceType,
+		ResourceID:   event.Subject,
+		ActorID:      event.UserID,
+		Source:       event.Source,
+		Details:      event.Data,
+	}
+	if spaceID, ok := event.Data["space_id"].(string); ok {
+		audit.SpaceID = spaceID
+	}
+	if tenantID, ok := event.Data["tenant_id"].(string); ok {
+		audit.TenantID = tenantID
+	}
+	return s.Record(ctx, audit)
+}
+
+// auditFilterClause builds the WHERE clause and parameters of a filter
+func (s *AuditService) auditFilterClause(ctx context.Context, filter models.AuditFilter) (string, map[string]interface{}, error) {
+	conditions := []string{"true"}
+	params := map[string]interface{}{}
+
+	equals := map[string]string{
+		"actor_id":      filter.ActorID,
+		"resource_type": filter.ResourceType,
+		"resource_id":   filter.ResourceID,
+		"action":        filter.Action,
+	}
+	for _, field := range []string{"actor_id", "resource_type", "resource_id", "action"} {
+		if equals[field] != "" {
+			conditions = append(conditions, fmt.Sprintf("a.%[1]s = $%[1]s", field))
+			params[field] = equals[field]
+		}
+	}
+
+	if len(filter.ResourceIDs) > 0 {
+		conditions = append(conditions, "a.resource_id IN $resource_ids")
+		params["resource_ids"] = filter.ResourceIDs
+	}
+
+	// Document events carry their notebook in the details, which are stored
+	// as JSON with the keys sorted and no spaces
+	if filter.NotebookID != "" {
+		marker, _ := json.Marshal(filter.NotebookID)
+		conditions = append(conditions, "((a.resource_type = 'notebook' AND a.resource_id = $notebook_id) OR (a.resource_type = 'document' AND a.details CONTAINS $notebook_marker))")
+		params["notebook_id"] = filter.NotebookID
+		params["notebook_marker"] = `"notebook_id":` + string(marker)
+	}
+
+	// An organization covers its own events and those of its spaces
+	if filter.OrganizationID != "" {
+		orgSpaceIDs, err := s.organizationSpaceIDs(ctx, filter.OrganizationID)
+		if err != nil {
+			return "", nil, err
+		}
+		conditions = append(conditions, "(a.organization_id = $organization_id OR a.space_id IN $org_space_ids)")
+		params["organization_id"] = filter.OrganizationID
+		params["org_space_ids"] = orgSpaceIDs
+	}
+	if len(filter.SpaceIDs) > 0 {
+		conditions = append(conditions, "a.space_id IN $space_ids")
+		params["space_ids"] = filter.SpaceIDs
+	}
+
+	if filter.From != nil {
+		conditions = append(conditions, "a.created_at >= datetime($from)")
+		params["from"] = filter.From.UTC().Format(time.RFC3339Nano)
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "a.created_at < datetime($to)")
+		params["to"] = filter.To.UTC().Format(time.RFC3339Nano)
+	}
+	return strings.Join(conditions, " AND "), params, nil
+}
+
+// organizationSpaceIDs returns the IDs of an organization's spaces
+func (s *AuditService) organizationSpaceIDs(ctx context.Context, organizationID string) ([]string, error) {
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (:Organization {id: $organization_id})-[:HAS_SPACE]->(sp:Space)
+		RETURN collect(sp.id) AS space_ids
+	`, map[string]interface{}{"organization_id": organizationID})
+	if err != nil {
+		return nil, errors.Database("Failed to resolve organization spaces", err)
+	}
+
+	spaceIDs := []string{}
+	if len(result.Records) > 0 {
+		values, _ := result.Records[0].Get("space_ids")
+		list, _ := values.([]interface{})
+		for _, value := range list {
+			if id, ok := value.(string); ok {
+				spaceIDs = append(spaceIDs, id)
+			}
+		}
+	}
+	return spaceIDs, nil
+}
+
+// auditEventFields is the RETURN clause shared by audit queries
+const auditEventFields = `
+	a.id AS id, a.sequence AS sequence, a.action AS action,
+	a.resource_type AS resource_type, a.resource_id AS resource_id,
+	a.actor_id AS actor_id, a.space_id AS space_id, a.tenant_id AS tenant_id,
+	a.organization_id AS organization_id, a.source AS source,
+	coalesce(a.details, a.changes) AS details, a.created_at AS created_at,
+	a.prev_hash AS prev_hash, a.hash AS hash
+`
+
+// Query returns a page of audit events matching the filter, newest first
+func (s *AuditService) Query(ctx context.Context, filter models.AuditFilter) (*models.AuditEventListResponse, error) {
+	filter.Limit, filter.Offset = pagination.Clamp(filter.Limit, filter.Offset)
+
+	where, params, err := s.auditFilterClause(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	params["skip"] = filter.Offset
+	params["limit"] = filter.Limit + 1
+
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "audit.query"), `
+		MATCH (a:AuditEvent)
+		WHERE `+where+`
+		RETURN `+auditEventFields+`
+		ORDER BY a.created_at DESC, a.sequence DESC
+		SKIP $skip
+		LIMIT $limit
+	`, params)
+	if err != nil {
+		return nil, errors.Database("Failed to query audit log", err)
+	}
+
+	events := make([]*models.AuditEvent, 0, len(result.Records))
+	for _, record := range result.Records {
+		event, _ := recordToAuditEvent(record)
+		events = append(events, event)
+	}
+	events, hasMore := pagination.Trim(events, filter.Limit)
+
+	return &models.AuditEventListResponse{
+		Events:  events,
+		Limit:   filter.Limit,
+		Offset:  filter.Offset,
+		HasMore: hasMore,
+	}, nil
+}
+
+// Export streams every audit event matching the filter to fn, oldest
+// first, ignoring the filter's pagination. It returns the number of events
+// exported; fn returning an error stops the export.
+func (s *AuditService) Export(ctx context.Context, filter models.AuditFilter, fn func(*models.AuditEvent) error) (int, error) {
+	where, params, err := s.auditFilterClause(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	exported := 0
+	_, err = s.neo4j.StreamQuery(database.WithQueryName(ctx, "audit.export"), `
+		MATCH (a:AuditEvent)
+		WHERE `+where+`
+		RETURN `+auditEventFields+`
+		ORDER BY a.created_at, a.sequence
+	`, params, func(record *neo4j.Record) error {
+		event, _ := recordToAuditEvent(record)
+		if err := fn(event); err != nil {
+			return err
+		}
+		exported++
+		return nil
+	})
+	if err != nil {
+		return exported, errors.Database("Failed to export audit log", err)
+	}
+	return exported, nil
+}
+
+// Verify walks the hash chain in sequence order and checks that sequences
+// are contiguous, that each entry links to its predecessor's hash, that
+// each hash matches the entry's content, and that the chain ends at the
+// recorded head.
+func (s *AuditService) Verify(ctx context.Context) (*models.AuditVerifyResponse, error) {
+	verifier := &auditChainVerifier{}
+	_, err := s.neo4j.StreamQuery(database.WithQueryName(ctx, "audit.verify"), `
+		MATCH (a:AuditEvent)
+		WHERE a.sequence IS NOT NULL
+		RETURN `+auditEventFields+`
+		ORDER BY a.sequence
+	`, nil, func(record *neo4j.Record) error {
+		event, details := recordToAuditEvent(record)
+		verifier.check(event, details)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to verify audit log", err)
+	}
+
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (c:AuditChain {id: $chain_id})
+		RETURN c.sequence AS sequence, c.hash AS hash
+	`, map[string]interface{}{"chain_id": auditChainID})
+	if err != nil {
+		return nil, errors.Database("Failed to read audit chain head", err)
+	}
+	var headSequence int64
+	var headHash string
+	if len(result.Records) > 0 {
+		value, _ := result.Records[0].Get("sequence")
+		headSequence, _ = value.(int64)
+		value, _ = result.Records[0].Get("hash")
+		headHash, _ = value.(string)
+	}
+	return verifier.finish(headSequence, headHash), nil
+}
+
+// auditChainVerifier checks audit events presented in sequence order
+type auditChainVerifier struct {
+	response models.AuditVerifyResponse
+	prevHash string
+	broken   bool
+}
+
+func (v *auditChainVerifier) fail(sequence int64, reason string) {
+	v.broken = true
+	v.response.BrokenAt = &sequence
+	v.response.Reason = reason
+}
+
+func (v *auditChainVerifier) check(event *models.AuditEvent, details string) {
+	if v.broken {
+		return
+	}
+	v.response.Checked++
+
+	expected := v.response.LastSequence + 1
+	switch {
+	case event.Sequence != expected:
+		v.fail(expected, fmt.Sprintf("entry %d is missing", expected))
+	case event.PrevHash != v.prevHash:
+		v.fail(event.Sequence, "entry does not link to the previous entry's hash")
+	case event.Hash != auditHash(event, details):
+		v.fail(event.Sequence, "entry content does not match its hash")
+	default:
+		v.response.LastSequence = event.Sequence
+		v.prevHash = event.Hash
+	}
+}
+
+func (v *auditChainVerifier) finish(headSequence int64, headHash string) *models.AuditVerifyResponse {
+	if !v.broken && (v.response.LastSequence != headSequence || v.prevHash != headHash) {
+		v.fail(v.response.LastSequence+1, fmt.Sprintf("chain ends at entry %d but the head records entry %d", v.response.LastSequence, headSequence))
+	}
+	v.response.Valid = !v.broken
+	return &v.response
+}
+
+// auditHash hashes an event's content and its predecessor's hash. details
+// is the stored JSON so verification hashes exactly what was written.
+func auditHash(event *models.AuditEvent, details string) string {
+	h := sha256.New()
+	for _, field := range []string{
+		strconv.FormatInt(event.Sequence, 10),
+		event.ID,
+		event.Action,
+		event.ResourceType,
+		event.ResourceID,
+		event.ActorID,
+		event.SpaceID,
+		event.TenantID,
+		event.OrganizationID,
+		event.Source,
+		details,
+		event.CreatedAt.UTC().Format(time.RFC3339Nano),
+		event.PrevHash,
+	} {
+		// Length-prefixed so field boundaries cannot be shifted
+		fmt.Fprintf(h, "%d:%s|", len(field), field)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recordToAuditEvent converts a record returned with auditEventFields. It
+// also returns the stored details JSON for hash verification.
+func recordToAuditEvent(record *neo4j.Record) (*models.AuditEvent, string) {
+	text := func(key string) string {
+		value, _ := record.Get(key)
+		s, _ := value.(string)
+		return s
+	}
+
+	event := &models.AuditEvent{
+		ID:             text("id"),
+		Action:         text("action"),
+		ResourceType:   text("resource_type"),
+		ResourceID:     text("resource_id"),
+		ActorID:        text("actor_id"),
+		SpaceID:        text("space_id"),
+		TenantID:       text("tenant_id"),
+		OrganizationID: text("organization_id"),
+		Source:         text("source"),
+		PrevHash:       text("prev_hash"),
+		Hash:           text("hash"),
+	}
+	if value, _ := record.Get("sequence"); value != nil {
+		event.Sequence, _ = value.(int64)
+	}
+	if value, _ := record.Get("created_at"); value != nil {
+		event.CreatedAt, _ = value.(time.Time)
+	}
+
+	details := text("details")
+	if details != "" && details != "{}" {
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(details), &decoded); err == nil {
+			// Entries written before the audit log existed stored a list of changes
+			if fields, ok := decoded.(map[string]interface{}); ok {
+				event.Details = fields
+			} else {
+				event.Details = map[string]interface{}{"changes": decoded}
+			}
+		}
+	}
+	return event, details
+}
+
+// recordAuditEvent records an audit event on behalf of a service. The
+// audited change has already been applied, so a failure is logged rather
+// than returned to the caller.
+func recordAuditEvent(ctx context.Context, audit *AuditService, log *logger.Logger, event *models.AuditEvent) {
+	if audit == nil {
+		return
+	}
+	if err := audit.Record(ctx, event); err != nil {
+		log.Error("Failed to record audit event",
+			zap.String("action", event.Action),
+			zap.String("resource_type", event.ResourceType),
+			zap.String("resource_id", event.ResourceID),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+// auditChain builds a valid chain of n events, returning each event's
+// stored details alongside it
+func auditChain(n int) ([]*models.AuditEvent, []string) {
+	events := make([]*models.AuditEvent, 0, n)
+	details := make([]string, 0, n)
+	prevHash := ""
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i := 1; i <= n; i++ {
+		event := &models.AuditEvent{
+			ID:           "event-" + string(rune('a'+i)),
+			Sequence:     int64(i),
+			Action:       "notebook.updated",
+			ResourceType: "notebook",
+			ResourceID:   "nb-1",
+			ActorID:      "user-1",
+			SpaceID:      "space-1",
+			CreatedAt:    createdAt.Add(time.Duration(i) * time.Second),
+			PrevHash:     prevHash,
+		}
+		event.Hash = auditHash(event, `{"name":"x"}`)
+		prevHash = event.Hash
+		events = append(events, event)
+		details = append(details, `{"name":"x"}`)
+	}
+	return events, details
+}
+
+func verifyAuditChain(events []*models.AuditEvent, details []string, headSequence int64, headHash string) *models.AuditVerifyResponse {
+	verifier := &auditChainVerifier{}
+	for i, event := range events {
+		verifier.check(event, details[i])
+	}
+	return verifier.finish(headSequence, headHash)
+}
+
+func TestAuditHashCoversContentAndPredecessor(t *testing.T) {
+	events, _ := auditChain(1)
+	event := events[0]
+	original := auditHash(event, "{}")
+
+	assert.Equal(t, original, auditHash(event, "{}"))
+	assert.NotEqual(t, original, auditHash(event, `{"a":1}`))
+
+	altered := *event
+	altered.ActorID = "user-2"
+	assert.NotEqual(t, original, auditHash(&altered, "{}"))
+
+	altered = *event
+	altered.PrevHash = "abc"
+	assert.NotEqual(t, original, auditHash(&altered, "{}"))
+
+	// Moving text between fields changes the hash
+	altered = *event
+	altered.ResourceType, altered.ResourceID = "notebooknb-1", ""
+	assert.NotEqual(t, original, auditHash(&altered, "{}"))
+}
+
+func TestAuditChainVerifier(t *testing.T) {
+	t.Run("valid chain", func(t *testing.T) {
+		events, details := auditChain(3)
+		result := verifyAuditChain(events, details, 3, events[2].Hash)
+		assert.True(t, result.Valid)
+		assert.Equal(t, int64(3), result.Checked)
+		assert.Equal(t, int64(3), result.LastSequence)
+		assert.Nil(t, result.BrokenAt)
+	})
+
+	t.Run("empty chain", func(t *testing.T) {
+		result := verifyAuditChain(nil, nil, 0, "")
+		assert.True(t, result.Valid)
+	})
+
+	t.Run("altered entry", func(t *testing.T) {
+		events, details := auditChain(3)
+		events[1].ActorID = "someone-else"
+		result := verifyAuditChain(events, details, 3, events[2].Hash)
+		assert.False(t, result.Valid)
+		require.NotNil(t, result.BrokenAt)
+		assert.Equal(t, int64(2), *result.BrokenAt)
+	})
+
+	t.Run("altered details", func(t *testing.T) {
+		events, details := auditChain(3)
+		details[0] = `{"name":"y"}`
+		result := verifyAuditChain(events, details, 3, events[2].Hash)
+		assert.False(t, result.Valid)
+		require.NotNil(t, result.BrokenAt)
+		assert.Equal(t, int64(1), *result.BrokenAt)
+	})
+
+	t.Run("deleted entry", func(t *testing.T) {
+		events, details := auditChain(3)
+		result := verifyAuditChain([]*models.AuditEvent{events[0], events[2]}, []string{details[0], details[2]}, 3, events[2].Hash)
+		assert.False(t, result.Valid)
+		require.NotNil(t, result.BrokenAt)
+		assert.Equal(t, int64(2), *result.BrokenAt)
+	})
+
+	t.Run("truncated tail", func(t *testing.T) {
+		events, details := auditChain(3)
+		result := verifyAuditChain(events[:2], details[:2], 3, events[2].Hash)
+		assert.False(t, result.Valid)
+		require.NotNil(t, result.BrokenAt)
+		assert.Equal(t, int64(3), *result.BrokenAt)
+	})
+}
+
+func TestAuditRecordDomainEventSkipsPipelineEvents(t *testing.T) {
+	// The service has no database; recording would fail
+	audit := NewAuditService(nil, setupTestLogger(t))
+	for _, eventType := range []EventType{EventDocumentStatusChanged, EventDocumentProcessed, EventDocumentFailed} {
+		assert.NoError(t, audit.RecordDomainEvent(context.Background(), Event{Type: eventType, Subject: "doc-1"}))
+	}
+}
@@ -0,0 +1,404 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// organizationRoleFields are the columns recordToOrganizationRole reads
+const organizationRoleFields = `r.organization_id AS organization_id, r.name AS name, r.description AS description,
+	       r.permissions AS permissions, r.created_by AS created_by, r.created_at AS created_at, r.updated_at AS updated_at`
+
+// roleNamePattern is the form of custom role names; it leaves out the ":"
+// of relationship roles
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+// AuthorizationService decides whether a subject may perform an action on
+// a resource, from the actions its roles grant. Every organization has the
+// built-in roles of models.BuiltInRoles and may define custom roles, stored
+// as OrganizationRole nodes; each replica keeps them in memory, re-read on
+// an interval, so authorizing costs no query.
+//
+// A nil *AuthorizationService applies the built-in roles only, so services
+// created without one authorize as before custom roles existed.
+type AuthorizationService struct {
+	neo4j    *database.Neo4jClient
+	audit    *AuditService
+	interval time.Duration
+	roles    atomic.Pointer[map[string]map[string]*models.Role] // By organization, then name
+	logger   *logger.Logger
+}
+
+// NewAuthorizationService creates an authorization service that re-reads
+// the custom roles every interval once Run is started. audit may be nil, in
+// which case role changes are only logged.
+func NewAuthorizationService(neo4j *database.Neo4jClient, audit *AuditService, interval time.Duration, log *logger.Logger) *AuthorizationService {
+	s := &AuthorizationService{
+		neo4j:    neo4j,
+		audit:    audit,
+		interval: interval,
+		logger:   log.WithService("authorization_service"),
+	}
+	s.roles.Store(&map[string]map[string]*models.Role{})
+	return s
+}
+
+// Run reads the custom roles now and then every interval until ctx is
+// cancelled
+func (s *AuthorizationService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Refresh(ctx); err != nil && ctx.Err() == nil {
+			// The roles last read stay in effect
+			s.logger.Warn("Failed to read custom roles", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh re-reads the custom roles from Neo4j
+func (s *AuthorizationService) Refresh(ctx context.Context) error {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "roles.all"), `
+		MATCH (r:OrganizationRole)
+		RETURN `+organizationRoleFields, nil)
+	if err != nil {
+		return err
+	}
+
+	roles := make(map[string]map[string]*models.Role)
+	for _, record := range result.Records {
+		role := recordToOrganizationRole(record)
+		if roles[role.OrganizationID] == nil {
+			roles[role.OrganizationID] = make(map[string]*models.Role)
+		}
+		roles[role.OrganizationID][role.Name] = role
+	}
+	s.roles.Store(&roles)
+	return nil
+}
+
+// Authorize returns nil when one of the subject's roles grants action on
+// resource, or the subject owns it, and a 403 with AETHER-AUTH-002
+// otherwise
+func (s *AuthorizationService) Authorize(ctx context.Context, subject models.Subject, action string, resource models.Resource) error {
+	if resource.OwnerID != "" && resource.OwnerID == subject.UserID {
+		return nil
+	}
+	for _, role := range subject.Roles {
+		if models.Grants(s.Permissions(subject.OrganizationID, role), action) {
+			return nil
+		}
+	}
+
+	if s != nil {
+		s.logger.FromContext(ctx).Debug("Action denied",
+			zap.String("user_id", subject.UserID),
+			zap.Strings("roles", subject.Roles),
+			zap.String("action", action),
+			zap.String("resource_type", resource.Type),
+			zap.String("resource_id", resource.ID),
+		)
+	}
+	details := map[string]interface{}{
+		"action":        action,
+		"resource_type": resource.Type,
+	}
+	if resource.ID != "" {
+		details["resource_id"] = resource.ID
+	}
+	return errors.ForbiddenWithDetails("You do not have permission to perform this action", details).
+		WithErrorCode(errors.CodePermissionDenied)
+}
+
+// Permissions returns the actions a role grants: a built-in role, or a
+// custom role of the organization. A member role that no longer exists,
+// such as one assigned before custom roles, grants what viewers may do.
+func (s *AuthorizationService) Permissions(orgID, role string) []string {
+	if permissions, ok := models.BuiltInRoles[role]; ok {
+		return permissions
+	}
+	if custom := s.customRole(orgID, role); custom != nil {
+		return custom.Permissions
+	}
+	return models.BuiltInRoles["viewer"]
+}
+
+// RoleExists reports whether role can be assigned to the members of an
+// organization
+func (s *AuthorizationService) RoleExists(orgID, role string) bool {
+	for _, name := range models.MemberRoles {
+		if name == role {
+			return true
+		}
+	}
+	return s.customRole(orgID, role) != nil
+}
+
+// SpacePermissions returns the permissions of a space context held with a
+// role, as listed in SpaceContext.Permissions
+func (s *AuthorizationService) SpacePermissions(orgID, role string) []string {
+	actions := s.Permissions(orgID, role)
+	permissions := make([]string, 0, 6)
+	grants := func(action string) bool {
+		return models.Grants(actions, action)
+	}
+	if grants(models.ActionDocumentRead) {
+		permissions = append(permissions, "read")
+	}
+	if grants(models.ActionDocumentCreate) || grants(models.ActionDocumentUpdate) {
+		permissions = append(permissions, "write")
+	}
+	if grants(models.ActionDocumentCreate) {
+		permissions = append(permissions, "create")
+	}
+	if grants(models.ActionDocumentUpdate) {
+		permissions = append(permissions, "update")
+	}
+	if grants(models.ActionDocumentDelete) {
+		permissions = append(permissions, "delete")
+	}
+	if grants(models.ActionSpaceUpdate) {
+		permissions = append(permissions, "admin")
+	}
+	return permissions
+}
+
+// customRole returns a custom role of an organization as last read, nil
+// when there is none
+func (s *AuthorizationService) customRole(orgID, name string) *models.Role {
+	if s == nil || orgID == "" {
+		return nil
+	}
+	return (*s.roles.Load())[orgID][name]
+}
+
+// ListRoles returns the built-in member roles and the custom roles of an
+// organization, read from Neo4j rather than from this replica's copy
+func (s *AuthorizationService) ListRoles(ctx context.Context, orgID string) (*models.RoleList, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "roles.list"), `
+		MATCH (r:OrganizationRole {organization_id: $organization_id})
+		RETURN `+organizationRoleFields+`
+		ORDER BY r.name`, map[string]interface{}{"organization_id": orgID})
+	if err != nil {
+		return nil, errors.Database("Failed to list roles", err)
+	}
+
+	list := &models.RoleList{Roles: make([]*models.Role, 0, len(models.MemberRoles)+len(result.Records)), Actions: models.Actions}
+	for _, name := range models.MemberRoles {
+		list.Roles = append(list.Roles, &models.Role{Name: name, Permissions: models.BuiltInRoles[name], BuiltIn: true})
+	}
+	for _, record := range result.Records {
+		list.Roles = append(list.Roles, recordToOrganizationRole(record))
+	}
+	return list, nil
+}
+
+// CreateRole defines a custom role of an organization
+func (s *AuthorizationService) CreateRole(ctx context.Context, orgID string, req models.RoleCreateRequest, actorID string) (*models.Role, error) {
+	if !roleNamePattern.MatchString(req.Name) {
+		return nil, errors.ValidationWithDetails("Role names are lowercase letters, digits, '_' and '-', starting with a letter", map[string]interface{}{
+			"name": req.Name,
+		}).WithErrorCode(errors.CodeInvalidRole)
+	}
+	if models.IsBuiltInRole(req.Name) {
+		return nil, errors.ConflictWithDetails("A built-in role has that name", map[string]interface{}{
+			"name": req.Name,
+		}).WithErrorCode(errors.CodeRoleExists)
+	}
+	permissions, err := normalizePermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "roles.create"), `
+		OPTIONAL MATCH (existing:OrganizationRole {organization_id: $organization_id, name: $name})
+		WITH existing WHERE existing IS NULL
+		CREATE (r:OrganizationRole {
+			organization_id: $organization_id, name: $name, description: $description,
+			permissions: $permissions, created_by: $actor_id, created_at: $now, updated_at: $now
+		})
+		RETURN `+organizationRoleFields, map[string]interface{}{
+		"organization_id": orgID,
+		"name":            req.Name,
+		"description":     req.Description,
+		"permissions":     permissions,
+		"actor_id":        actorID,
+		"now":             time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to create role", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, errors.ConflictWithDetails("The organization already has a role of that name", map[string]interface{}{
+			"name": req.Name,
+		}).WithErrorCode(errors.CodeRoleExists)
+	}
+	role := recordToOrganizationRole(result.Records[0])
+
+	s.refreshNow(ctx)
+	s.recordAudit(ctx, "organization.role.create", role, actorID)
+	return role, nil
+}
+
+// UpdateRole changes the description or permissions of a custom role.
+// Members holding it are granted the new permissions within the refresh
+// interval.
+func (s *AuthorizationService) UpdateRole(ctx context.Context, orgID, name string, req models.RoleUpdateRequest, actorID string) (*models.Role, error) {
+	params := map[string]interface{}{
+		"organization_id": orgID,
+		"name":            name,
+		"description":     nil,
+		"permissions":     nil,
+		"now":             time.Now().UTC(),
+	}
+	if req.Description != nil {
+		params["description"] = *req.Description
+	}
+	if req.Permissions != nil {
+		permissions, err := normalizePermissions(req.Permissions)
+		if err != nil {
+			return nil, err
+		}
+		params["permissions"] = permissions
+	}
+
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "roles.update"), `
+		MATCH (r:OrganizationRole {organization_id: $organization_id, name: $name})
+		SET r.description = coalesce($description, r.description),
+		    r.permissions = coalesce($permissions, r.permissions),
+		    r.updated_at = $now
+		RETURN `+organizationRoleFields, params)
+	if err != nil {
+		return nil, errors.Database("Failed to update role", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, roleNotFound(name)
+	}
+	role := recordToOrganizationRole(result.Records[0])
+
+	s.refreshNow(ctx)
+	s.recordAudit(ctx, "organization.role.update", role, actorID)
+	return role, nil
+}
+
+// DeleteRole deletes a custom role no member holds
+func (s *AuthorizationService) DeleteRole(ctx context.Context, orgID, name, actorID string) error {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "roles.delete"), `
+		MATCH (r:OrganizationRole {organization_id: $organization_id, name: $name})
+		OPTIONAL MATCH (:User)-[m:MEMBER_OF {role: $name}]->(:Organization {id: $organization_id})
+		WITH r, count(m) AS members
+		FOREACH (_ IN CASE WHEN members = 0 THEN [1] ELSE [] END | DETACH DELETE r)
+		RETURN members`, map[string]interface{}{
+		"organization_id": orgID,
+		"name":            name,
+	})
+	if err != nil {
+		return errors.Database("Failed to delete role", err)
+	}
+	if len(result.Records) == 0 {
+		return roleNotFound(name)
+	}
+	if members := recordInt64(result.Records[0], "members"); members > 0 {
+		return errors.ConflictWithDetails("Members still hold the role", map[string]interface{}{
+			"name":    name,
+			"members": members,
+		}).WithErrorCode(errors.CodeRoleInUse)
+	}
+
+	s.refreshNow(ctx)
+	s.recordAudit(ctx, "organization.role.delete", &models.Role{Name: name, OrganizationID: orgID}, actorID)
+	return nil
+}
+
+// refreshNow applies a changed role on this replica at once; the others
+// apply it on their next read
+func (s *AuthorizationService) refreshNow(ctx context.Context) {
+	if err := s.Refresh(ctx); err != nil {
+		s.logger.Warn("Failed to read custom roles", zap.Error(err))
+	}
+}
+
+// recordAudit logs a role changed and records it in the audit log
+func (s *AuthorizationService) recordAudit(ctx context.Context, action string, role *models.Role, actorID string) {
+	s.logger.FromContext(ctx).Info("Organization role changed",
+		zap.Bool("audit", true),
+		zap.String("action", action),
+		zap.String("organization_id", role.OrganizationID),
+		zap.String("role", role.Name),
+		zap.Strings("permissions", role.Permissions),
+		zap.String("actor_id", actorID),
+	)
+
+	recordAuditEvent(ctx, s.audit, s.logger, &models.AuditEvent{
+		Action:         action,
+		ResourceType:   "organization_role",
+		ResourceID:     role.Name,
+		OrganizationID: role.OrganizationID,
+		ActorID:        actorID,
+		Details: map[string]interface{}{
+			"permissions": role.Permissions,
+		},
+	})
+}
+
+// normalizePermissions checks that every permission is a known action and
+// returns them sorted, without duplicates
+func normalizePermissions(permissions []string) ([]string, error) {
+	seen := make(map[string]bool, len(permissions))
+	normalized := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		if !models.IsAction(permission) {
+			return nil, errors.ValidationWithDetails("Unknown permission", map[string]interface{}{
+				"permission": permission,
+				"actions":    models.Actions,
+			}).WithErrorCode(errors.CodeInvalidRole)
+		}
+		if !seen[permission] {
+			seen[permission] = true
+			normalized = append(normalized, permission)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+func roleNotFound(name string) error {
+	return errors.NotFoundWithDetails("Role not found", map[string]interface{}{
+		"name": name,
+	}).WithErrorCode(errors.CodeRoleNotFound)
+}
+
+// recordToOrganizationRole reads the organizationRoleFields of a record
+func recordToOrganizationRole(record *neo4j.Record) *models.Role {
+	role := &models.Role{
+		Name:           recordString(record, "name"),
+		Description:    recordString(record, "description"),
+		Permissions:    recordStrings(record, "permissions"),
+		OrganizationID: recordString(record, "organization_id"),
+		CreatedBy:      recordString(record, "created_by"),
+	}
+	if at := recordTime(record, "created_at"); !at.IsZero() {
+		role.CreatedAt = &at
+	}
+	if at := recordTime(record, "updated_at"); !at.IsZero() {
+		role.UpdatedAt = &at
+	}
+	return role
+}
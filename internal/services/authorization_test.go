@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+func TestAuthorizeBuiltInRoles(t *testing.T) {
+	var authz *AuthorizationService // Built-in roles only
+	ctx := context.Background()
+	document := models.Resource{Type: models.ResourceDocument, ID: "doc-1", OwnerID: "owner-1"}
+
+	assert.NoError(t, authz.Authorize(ctx, models.Subject{UserID: "owner-1"}, models.ActionDocumentDelete, document), "owners may do anything with what they own")
+	assert.NoError(t, authz.Authorize(ctx, models.Subject{UserID: "user-1", Roles: []string{models.RoleNotebookEditor}}, models.ActionDocumentUpdate, document))
+	assert.NoError(t, authz.Authorize(ctx, models.Subject{UserID: "user-1", Roles: []string{"admin"}}, models.ActionDocumentDelete, document), "admins are granted document:*")
+
+	err := authz.Authorize(ctx, models.Subject{UserID: "user-1", Roles: []string{models.RoleNotebookViewer}}, models.ActionDocumentUpdate, document)
+	apiErr, ok := err.(*errors.APIError)
+	require.True(t, ok)
+	assert.Equal(t, errors.CodePermissionDenied, apiErr.ErrorCode)
+	assert.Equal(t, models.ActionDocumentUpdate, apiErr.Details["action"])
+	assert.Equal(t, "doc-1", apiErr.Details["resource_id"])
+
+	org := models.Resource{Type: models.ResourceOrganization, ID: "org-1"}
+	assert.NoError(t, authz.Authorize(ctx, models.Subject{UserID: "user-1", Roles: []string{"owner"}}, models.ActionOrganizationDelete, org))
+	assert.Error(t, authz.Authorize(ctx, models.Subject{UserID: "user-1", Roles: []string{"admin"}}, models.ActionOrganizationDelete, org))
+	assert.NoError(t, authz.Authorize(ctx, models.Subject{UserID: "user-1", Roles: []string{"billing"}}, models.ActionOrganizationBilling, org))
+	assert.Error(t, authz.Authorize(ctx, models.Subject{UserID: "user-1", Roles: []string{"unknown"}}, models.ActionDocumentCreate, document), "unknown roles grant what viewers may do")
+}
+
+func TestAuthorizeCustomRoles(t *testing.T) {
+	authz := NewAuthorizationService(nil, nil, time.Minute, setupTestLogger(t))
+	authz.roles.Store(&map[string]map[string]*models.Role{
+		"org-1": {"reviewer": {Name: "reviewer", OrganizationID: "org-1", Permissions: []string{models.ActionDocumentRead, "notebook:*"}}},
+	})
+	ctx := context.Background()
+	reviewer := models.Subject{UserID: "user-1", OrganizationID: "org-1", Roles: []string{"reviewer"}}
+
+	assert.NoError(t, authz.Authorize(ctx, reviewer, models.ActionNotebookShare, models.Resource{Type: models.ResourceNotebook}))
+	assert.Error(t, authz.Authorize(ctx, reviewer, models.ActionDocumentUpdate, models.Resource{Type: models.ResourceDocument}))
+
+	elsewhere := models.Subject{UserID: "user-1", OrganizationID: "org-2", Roles: []string{"reviewer"}}
+	assert.Error(t, authz.Authorize(ctx, elsewhere, models.ActionNotebookShare, models.Resource{Type: models.ResourceNotebook}), "custom roles only apply in their organization")
+
+	assert.True(t, authz.RoleExists("org-1", "reviewer"))
+	assert.True(t, authz.RoleExists("org-2", "member"))
+	assert.False(t, authz.RoleExists("org-2", "reviewer"))
+	assert.False(t, authz.RoleExists("org-1", models.RoleTeamAdmin), "relationship roles cannot be assigned")
+}
+
+func TestSpacePermissions(t *testing.T) {
+	var authz *AuthorizationService
+
+	assert.Equal(t, []string{"read", "write", "create", "update", "delete", "admin"}, authz.SpacePermissions("", "owner"))
+	assert.Equal(t, []string{"read", "write", "create", "update"}, authz.SpacePermissions("", "member"))
+	assert.Equal(t, []string{"read"}, authz.SpacePermissions("", "viewer"))
+}
+
+func TestSpaceContextAuthorizes(t *testing.T) {
+	authz := NewAuthorizationService(nil, nil, time.Minute, setupTestLogger(t))
+	authz.roles.Store(&map[string]map[string]*models.Role{
+		"org-1": {"curator": {Name: "curator", OrganizationID: "org-1", Permissions: []string{"document:*"}}},
+	})
+
+	curator := &models.SpaceContext{SpaceID: "org-1", OrganizationID: "org-1", UserRole: "curator", Authorizer: authz}
+	assert.True(t, curator.CanDelete(), "custom roles are resolved by the authorizer")
+	assert.True(t, curator.CanUpdate())
+	assert.False(t, curator.CanManage())
+
+	admin := &models.SpaceContext{SpaceID: "org-1", OrganizationID: "org-1", UserRole: "admin", Authorizer: authz}
+	assert.True(t, admin.CanManage())
+	viewer := &models.SpaceContext{SpaceID: "org-1", UserRole: "viewer", Permissions: []string{"read", "write", "delete"}, Authorizer: authz}
+	assert.True(t, viewer.CanRead())
+	assert.False(t, viewer.CanCreate(), "the role decides, not the listed permissions")
+	assert.False(t, viewer.CanDelete())
+
+	builtIn := &models.SpaceContext{SpaceID: "space-1", UserRole: "member"}
+	assert.True(t, builtIn.CanUpdate(), "without an authorizer the built-in roles apply")
+	assert.False(t, builtIn.CanDelete())
+	assert.False(t, (&models.SpaceContext{TenantID: "tenant-1"}).CanRead(), "a context without a role grants nothing")
+}
+
+func TestNormalizePermissions(t *testing.T) {
+	permissions, err := normalizePermissions([]string{"notebook:*", models.ActionDocumentRead, "notebook:*"})
+	require.NoError(t, err)
+	assert.Len(t, permissions, 2)
+
+	_, err = normalizePermissions([]string{"document:shred"})
+	apiErr, ok := err.(*errors.APIError)
+	require.True(t, ok)
+	assert.Equal(t, errors.CodeInvalidRole, apiErr.ErrorCode)
+}
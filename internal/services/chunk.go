@@ -12,6 +12,7 @@ import (
 	"github.com/Tributary-ai-services/aether-be/internal/database"
 	"github.com/Tributary-ai-services/aether-be/internal/logger"
 	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
 	"github.com/Tributary-ai-services/aether-be/pkg/errors"
 )
 
@@ -29,45 +30,65 @@ func NewChunkService(neo4j *database.Neo4jClient, logger *logger.Logger) *ChunkS
 	}
 }
 
+// createChunksQuery creates one Chunk node per row. Single creates and bulk
+// mirroring share it so both go through the batch writer.
+const createChunksQuery = `
+	UNWIND $rows AS row
+	CREATE (c:Chunk)
+	SET c = row,
+		c.processed_at = datetime(row.processed_at),
+		c.created_at = datetime(row.created_at),
+		c.updated_at = datetime(row.updated_at)
+`
+
 // CreateChunk creates a new chunk
 func (s *ChunkService) CreateChunk(ctx context.Context, req models.ChunkCreateRequest, tenantID string) (*models.Chunk, error) {
-	chunk := models.NewChunk(req, tenantID)
+	chunks, err := s.CreateChunks(ctx, []models.ChunkCreateRequest{req}, tenantID)
+	if err != nil {
+		return nil, err
+	}
 	
-	// Create chunk in Neo4j
-	query := `
-		CREATE (c:Chunk {
-			id: $id,
-			tenant_id: $tenant_id,
-			file_id: $file_id,
-			chunk_id: $chunk_id,
-			chunk_type: $chunk_type,
-			chunk_number: $chunk_number,
-			content: $content,
-			content_hash: $content_hash,
-			size_bytes: $size_bytes,
-			processed_at: datetime($processed_at),
-			processed_by: $processed_by,
-			processing_time: $processing_time,
-			quality: $quality,
-			language: $language,
-			language_confidence: $language_confidence,
-			content_category: $content_category,
-			sensitivity_level: $sensitivity_level,
-			classifications: $classifications,
-			embedding_status: $embedding_status,
-			pii_detected: $pii_detected,
-			compliance_flags: $compliance_flags,
-			dlp_scan_status: $dlp_scan_status,
-			context: $context,
-			schema_info: $schema_info,
-			metadata: $metadata,
-			created_at: datetime($created_at),
-			updated_at: datetime($updated_at)
-		})
-		RETURN c
-	`
+	chunk := chunks[0]
+	s.logger.Info("Chunk created successfully",
+		zap.String("chunk_id", chunk.ID),
+		zap.String("file_id", chunk.FileID),
+		zap.String("chunk_type", chunk.ChunkType))
 	
-	params := map[string]interface{}{
+	return chunk, nil
+}
+
+// CreateChunks creates chunks in bulk using batched UNWIND writes
+func (s *ChunkService) CreateChunks(ctx context.Context, reqs []models.ChunkCreateRequest, tenantID string) ([]*models.Chunk, error) {
+	chunks := make([]*models.Chunk, 0, len(reqs))
+	rows := make([]map[string]interface{}, 0, len(reqs))
+	for _, req := range reqs {
+		chunk := models.NewChunk(req, tenantID)
+		chunks = append(chunks, chunk)
+		rows = append(rows, chunkToRow(chunk))
+	}
+	
+	if len(rows) == 0 {
+		return chunks, nil
+	}
+	
+	result, err := s.neo4j.WriteBatch(database.WithQueryName(ctx, "chunk.create_batch"), createChunksQuery, rows, nil)
+	if err != nil {
+		s.logger.Error("Failed to create chunks",
+			zap.Int("count", len(rows)),
+			zap.Error(err))
+		return nil, errors.Database("Failed to create chunks", err)
+	}
+	
+	s.logger.Debug("Chunks created",
+		zap.Int("count", result.Rows),
+		zap.Int("batches", result.Batches))
+	
+	return chunks, nil
+}
+
+// chunkToRow converts a chunk into the property map written to Neo4j
+func chunkToRow(chunk *models.Chunk) map[string]interface{} {
+	return map[string]interface{}{
 		"id":                   chunk.ID,
 		"tenant_id":            chunk.TenantID,
 		"file_id":              chunk.FileID,
@@ -96,19 +117,6 @@ func (s *ChunkService) CreateChunk(ctx context.Context, req models.ChunkCreateRe
 		"created_at":           chunk.CreatedAt.Format(time.RFC3339),
 		"updated_at":           chunk.UpdatedAt.Format(time.RFC3339),
 	}
-	
-	_, err := s.neo4j.ExecuteQueryWithLogging(ctx, query, params)
-	if err != nil {
-		s.logger.Error("Failed to create chunk", zap.Error(err))
-		return nil, errors.Database("Failed to create chunk", err)
-	}
-	
-	s.logger.Info("Chunk created successfully",
-		zap.String("chunk_id", chunk.ID),
-		zap.String("file_id", chunk.FileID),
-		zap.String("chunk_type", chunk.ChunkType))
-	
-	return chunk, nil
 }
 
 // GetChunkByID retrieves a chunk by ID
@@ -147,13 +155,7 @@ func (s *ChunkService) GetChunkByID(ctx context.Context, chunkID string, tenantI
 
 // ListChunksByFile lists chunks for a specific file
 func (s *ChunkService) ListChunksByFile(ctx context.Context, fileID string, tenantID string, limit, offset int) (*models.ChunkListResponse, error) {
-	// Set defaults
-	if limit <= 0 || limit > 100 {
-		limit = 20
-	}
-	if offset < 0 {
-		offset = 0
-	}
+	limit, offset = pagination.Clamp(limit, offset)
 	
 	query := `
 		MATCH (c:Chunk {file_id: $file_id, tenant_id: $tenant_id})
@@ -184,14 +186,9 @@ func (s *ChunkService) ListChunksByFile(ctx context.Context, fileID string, tena
 	}
 	
 	chunks := make([]*models.ChunkResponse, 0, len(result.Records))
-	hasMore := false
-	
-	for i, record := range result.Records {
-		if i >= limit {
-			hasMore = true
-			break
-		}
-		
+	records, hasMore := pagination.Trim(result.Records, limit)
+
+	for _, record := range records {
 		chunk, err := s.recordToChunk(record)
 		if err != nil {
 			s.logger.Error("Failed to parse chunk record", zap.Error(err))
@@ -315,14 +312,9 @@ func (s *ChunkService) SearchChunks(ctx context.Context, req models.ChunkSearchR
 	}
 	
 	chunks := make([]*models.ChunkResponse, 0, len(result.Records))
-	hasMore := false
-	
-	for i, record := range result.Records {
-		if i >= req.Limit {
-			hasMore = true
-			break
-		}
-		
+	records, hasMore := pagination.Trim(result.Records, req.Limit)
+
+	for _, record := range records {
 		chunk, err := s.recordToChunk(record)
 		if err != nil {
 			s.logger.Error("Failed to parse chunk record", zap.Error(err))
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// chunkSyncPageSize is how many chunks are read from AudiModal at a time
+const chunkSyncPageSize = 100
+
+// syncChunksQuery upserts the mirrored chunks of a document by their
+// derived ID and links them to it, so a retried batch writes nothing twice
+const syncChunksQuery = `
+	MATCH (d:Document {id: $document_id, tenant_id: $tenant_id})
+	UNWIND $rows AS row
+	MERGE (c:Chunk {id: row.id})
+	SET c = row,
+		c.processed_at = datetime(row.processed_at),
+		c.created_at = datetime(row.created_at),
+		c.updated_at = datetime(row.updated_at),
+		c.synced_at = datetime(row.updated_at)
+	MERGE (d)-[:HAS_CHUNK]->(c)
+`
+
+// ChunkSyncService mirrors the chunks AudiModal made of processed documents
+// into Neo4j, as Chunk nodes their Document HAS_CHUNK, so answers can cite
+// chunks, with their page and position, without a request to AudiModal.
+// A document is synced again each time it is processed, and its chunks go
+// with it when it is deleted.
+type ChunkSyncService struct {
+	neo4j     *database.Neo4jClient
+	documents *DocumentService
+	chunks    ChunkSource
+	config    config.ChunkSyncConfig
+	logger    *logger.Logger
+}
+
+// NewChunkSyncService creates a chunk sync service
+func NewChunkSyncService(neo4j *database.Neo4jClient, documents *DocumentService, chunks ChunkSource, cfg config.ChunkSyncConfig, log *logger.Logger) *ChunkSyncService {
+	return &ChunkSyncService{
+		neo4j:     neo4j,
+		documents: documents,
+		chunks:    chunks,
+		config:    cfg,
+		logger:    log.WithService("chunk_sync_service"),
+	}
+}
+
+// HandleDomainEvent queues processed documents to have their chunks
+// mirrored. It is subscribed to the domain event bus.
+func (s *ChunkSyncService) HandleDomainEvent(ctx context.Context, event Event) error {
+	if event.Type != EventDocumentProcessed {
+		return nil
+	}
+	_, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "chunk_sync.queue"), `
+		MATCH (d:Document {id: $id})
+		SET d.chunk_sync_pending = true
+	`, map[string]interface{}{"id": event.Subject})
+	if err != nil {
+		return fmt.Errorf("failed to queue chunk sync: %w", err)
+	}
+	return nil
+}
+
+// SyncPending mirrors the chunks of queued documents. It is a singleton job
+// run by the leader replica; a document whose sync fails stays queued.
+func (s *ChunkSyncService) SyncPending(ctx context.Context) error {
+	ctx = database.WithQueryName(ctx, "chunk_sync.pending")
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (d:Document)
+		WHERE d.chunk_sync_pending = true AND `+database.NotDeleted("d")+`
+		RETURN d.id AS id, d.tenant_id AS tenant_id
+		ORDER BY d.updated_at
+		LIMIT $limit
+	`, map[string]interface{}{"limit": s.config.BatchSize})
+	if err != nil {
+		return fmt.Errorf("failed to list documents to sync: %w", err)
+	}
+
+	failed := 0
+	for _, record := range result.Records {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		documentID := recordString(record, "id")
+		document, err := s.documents.getDocumentByIDInternal(ctx, documentID, recordString(record, "tenant_id"))
+		if err == nil {
+			_, err = s.Sync(ctx, document)
+		}
+		if err != nil && !errors.IsNotFound(err) {
+			failed++
+			s.logger.Warn("Failed to sync document chunks", zap.String("document_id", documentID), zap.Error(err))
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d documents failed to sync", failed, len(result.Records))
+	}
+	return nil
+}
+
+// Sync replaces the mirrored chunks of a document with the ones AudiModal
+// holds for its processed file, returning how many were mirrored. Chunks
+// of an earlier processing that the new one no longer has are removed. A
+// file AudiModal has no chunks for leaves the document without any.
+func (s *ChunkSyncService) Sync(ctx context.Context, document *models.Document) (int, error) {
+	fileID := documentFileID(document)
+	now := time.Now().UTC()
+
+	var rows []map[string]interface{}
+	for offset := 0; len(rows) < s.config.MaxChunks; offset += chunkSyncPageSize {
+		page, err := s.chunks.GetFileChunks(ctx, document.TenantID, fileID, chunkSyncPageSize, offset)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				break
+			}
+			return 0, fmt.Errorf("failed to read the chunks of file %s: %w", fileID, err)
+		}
+		for _, data := range page.Data {
+			if len(rows) == s.config.MaxChunks {
+				break
+			}
+			rows = append(rows, s.chunkRow(document, fileID, data, now))
+		}
+		if len(page.Data) < chunkSyncPageSize || offset+len(page.Data) >= page.Total {
+			break
+		}
+	}
+
+	params := map[string]interface{}{
+		"document_id": document.ID,
+		"tenant_id":   document.TenantID,
+	}
+	if len(rows) > 0 {
+		if _, err := s.neo4j.WriteBatch(database.WithQueryName(ctx, "chunk_sync.write"), syncChunksQuery, rows, params); err != nil {
+			return 0, fmt.Errorf("failed to write the chunks of document %s: %w", document.ID, err)
+		}
+	}
+
+	ids := make([]string, len(rows))
+	for i, row := range rows {
+		ids[i] = row["id"].(string)
+	}
+	fileIDs := []string{document.ID, fileID}
+	if document.ProcessingJobID != "" {
+		fileIDs = append(fileIDs, document.ProcessingJobID)
+	}
+	params["ids"] = ids
+	params["file_ids"] = uniqueStrings(fileIDs)
+	params["count"] = len(rows)
+	params["now"] = now
+	// Chunks of an earlier processing are linked to the document or, when
+	// created through the chunk API, name one of its file IDs
+	_, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "chunk_sync.prune"), `
+		MATCH (d:Document {id: $document_id, tenant_id: $tenant_id})
+		SET d.chunks_synced_at = $now, d.synced_chunk_count = $count
+		REMOVE d.chunk_sync_pending
+		WITH d
+		OPTIONAL MATCH (d)-[:HAS_CHUNK]->(linked:Chunk)
+		WHERE NOT linked.id IN $ids
+		WITH d, collect(linked) AS linked
+		OPTIONAL MATCH (named:Chunk {tenant_id: $tenant_id})
+		WHERE named.file_id IN $file_ids AND NOT named.id IN $ids
+		WITH linked + collect(named) AS stale
+		FOREACH (c IN stale | DETACH DELETE c)
+	`, params)
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove the stale chunks of document %s: %w", document.ID, err)
+	}
+
+	s.logger.Debug("Synced document chunks", zap.String("document_id", document.ID), zap.Int("chunks", len(rows)))
+	return len(rows), nil
+}
+
+// chunkRow returns the properties of the Chunk node mirroring a chunk of a
+// document's processed file. Its ID is derived from the file and chunk
+// number, so syncing the same processing again updates the same node.
+func (s *ChunkSyncService) chunkRow(document *models.Document, fileID string, data ChunkData, now time.Time) map[string]interface{} {
+	processedAt, err := time.Parse(time.RFC3339, data.ProcessedAt)
+	if err != nil {
+		processedAt = now
+	}
+	content := data.Content
+	if !s.config.StoreContent {
+		content = ""
+	}
+	chunk := &models.Chunk{
+		ID:              syncedChunkID(document.TenantID, fileID, data.ChunkNumber),
+		TenantID:        document.TenantID,
+		FileID:          fileID,
+		ChunkID:         data.ID,
+		ChunkType:       data.ChunkType,
+		ChunkNumber:     data.ChunkNumber,
+		Content:         content,
+		ContentHash:     data.ContentHash,
+		SizeBytes:       data.SizeBytes,
+		ProcessedAt:     processedAt,
+		ProcessedBy:     data.ProcessedBy,
+		ProcessingTime:  data.ProcessingTime,
+		Language:        data.Language,
+		LanguageConf:    data.LanguageConf,
+		ContentCategory: data.ContentCategory,
+		Classifications: data.Classifications,
+		EmbeddingStatus: "pending",
+		PIIDetected:     data.PIIDetected,
+		DLPScanStatus:   data.DLPScanStatus,
+		Context:         data.Context,
+		SchemaInfo:      data.SchemaInfo,
+		Metadata:        data.Metadata,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	row := chunkToRow(chunk)
+	row["document_id"] = document.ID
+	if data.PageNumber != nil {
+		row["page_number"] = *data.PageNumber
+	}
+	if data.LineNumber != nil {
+		row["line_number"] = *data.LineNumber
+	}
+	if data.StartPosition != nil {
+		row["start_position"] = *data.StartPosition
+	}
+	if data.EndPosition != nil {
+		row["end_position"] = *data.EndPosition
+	}
+	return row
+}
+
+// syncedChunkID derives the ID of the Chunk node mirroring a chunk
+func syncedChunkID(tenantID, fileID string, chunkNumber int) string {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte("aether:chunk:"+tenantID+"/"+fileID+"/"+strconv.Itoa(chunkNumber))).String()
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+func TestChunkSyncRow(t *testing.T) {
+	s := &ChunkSyncService{config: config.ChunkSyncConfig{StoreContent: true}, logger: setupTestLogger(t)}
+	document := &models.Document{ID: "doc_1", TenantID: "tenant_1", ProcessingJobID: "file_1"}
+	page, start := 4, int64(1200)
+	data := ChunkData{
+		ID:            "am_chunk_7",
+		ChunkNumber:   7,
+		ChunkType:     "text",
+		Content:       "Revenue grew 12%.",
+		ProcessedAt:   "2026-10-17T08:00:00Z",
+		PageNumber:    &page,
+		StartPosition: &start,
+		PIIDetected:   true,
+	}
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+
+	row := s.chunkRow(document, "file_1", data, now)
+	assert.Equal(t, syncedChunkID("tenant_1", "file_1", 7), row["id"])
+	assert.Equal(t, "doc_1", row["document_id"])
+	assert.Equal(t, "file_1", row["file_id"])
+	assert.Equal(t, "am_chunk_7", row["chunk_id"])
+	assert.Equal(t, "Revenue grew 12%.", row["content"])
+	assert.Equal(t, 4, row["page_number"])
+	assert.Equal(t, int64(1200), row["start_position"])
+	assert.NotContains(t, row, "end_position")
+	assert.Equal(t, true, row["pii_detected"])
+	assert.Equal(t, "2026-10-17T08:00:00Z", row["processed_at"])
+
+	s.config.StoreContent = false
+	row = s.chunkRow(document, "file_1", data, now)
+	assert.Equal(t, "", row["content"], "only metadata is mirrored")
+}
+
+func TestSyncedChunkID(t *testing.T) {
+	id := syncedChunkID("tenant_1", "file_1", 1)
+	assert.Equal(t, id, syncedChunkID("tenant_1", "file_1", 1), "a resync updates the same node")
+	assert.NotEqual(t, id, syncedChunkID("tenant_1", "file_1", 2))
+	assert.NotEqual(t, id, syncedChunkID("tenant_1", "file_2", 1))
+	assert.NotEqual(t, id, syncedChunkID("tenant_2", "file_1", 1))
+}
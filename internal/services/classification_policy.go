@@ -0,0 +1,701 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// classificationEvaluateLimit bounds the documents a dry run evaluates
+const classificationEvaluateLimit = 100
+
+// classificationPolicyFields are the columns recordToClassificationPolicy
+// reads
+const classificationPolicyFields = `p.id AS id, p.space_id AS space_id, p.tenant_id AS tenant_id,
+	       p.name AS name, p.description AS description, p.enabled AS enabled,
+	       p.priority AS priority, p.conditions AS conditions, p.actions AS actions,
+	       p.created_by AS created_by, p.created_at AS created_at, p.updated_at AS updated_at`
+
+// classificationDocumentFields are the document columns policies are
+// evaluated on, named as recordToDocument reads them
+const classificationDocumentFields = `d.id, d.name, d.description, d.type, d.status, d.original_name,
+	       d.mime_type, d.size_bytes, d.notebook_id, d.owner_id, d.space_type, d.space_id,
+	       d.tenant_id, d.tags, d.restricted, d.extracted_text, d.processing_result,
+	       d.created_at, d.updated_at`
+
+// ClassificationPolicyService runs the classification policies of spaces:
+// rules on a processed document's AudiModal classification and metadata
+// that move it to another notebook, tag it or restrict access to it
+type ClassificationPolicyService struct {
+	neo4j     *database.Neo4jClient
+	documents *DocumentService
+	events    DomainEventPublisher
+	logger    *logger.Logger
+}
+
+// NewClassificationPolicyService creates a classification policy service
+func NewClassificationPolicyService(neo4j *database.Neo4jClient, documents *DocumentService, log *logger.Logger) *ClassificationPolicyService {
+	return &ClassificationPolicyService{
+		neo4j:     neo4j,
+		documents: documents,
+		logger:    log.WithService("classification_policy_service"),
+	}
+}
+
+// SetEventPublisher sets where the document changes policies make are
+// published
+func (s *ClassificationPolicyService) SetEventPublisher(events DomainEventPublisher) {
+	s.events = events
+}
+
+// CreatePolicy creates a classification policy in the current space
+func (s *ClassificationPolicyService) CreatePolicy(ctx context.Context, req models.ClassificationPolicyCreateRequest, userID string, spaceCtx *models.SpaceContext) (*models.ClassificationPolicy, error) {
+	if !spaceCtx.CanManage() {
+		return nil, errors.Forbidden("Only space owners and admins can manage classification policies")
+	}
+	if err := s.validatePolicy(ctx, req.Conditions, req.Actions, spaceCtx); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	policy := &models.ClassificationPolicy{
+		ID:          uuid.New().String(),
+		SpaceID:     spaceCtx.SpaceID,
+		TenantID:    spaceCtx.TenantID,
+		Name:        req.Name,
+		Description: req.Description,
+		Enabled:     req.Enabled == nil || *req.Enabled,
+		Priority:    req.Priority,
+		Conditions:  req.Conditions,
+		Actions:     req.Actions,
+		CreatedBy:   userID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.savePolicy(ctx, policy, "CREATE (p:ClassificationPolicy {id: $id})"); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Classification policy created",
+		zap.String("policy_id", policy.ID),
+		zap.String("space_id", policy.SpaceID))
+	return policy, nil
+}
+
+// ListPolicies returns the classification policies of the current space in
+// the order they run
+func (s *ClassificationPolicyService) ListPolicies(ctx context.Context, spaceCtx *models.SpaceContext) ([]*models.ClassificationPolicy, error) {
+	if !spaceCtx.CanRead() {
+		return nil, errors.Forbidden("Insufficient permissions to list classification policies")
+	}
+	return s.spacePolicies(ctx, spaceCtx.SpaceID, false)
+}
+
+// GetPolicy returns a classification policy of the current space
+func (s *ClassificationPolicyService) GetPolicy(ctx context.Context, policyID string, spaceCtx *models.SpaceContext) (*models.ClassificationPolicy, error) {
+	if !spaceCtx.CanRead() {
+		return nil, errors.Forbidden("Insufficient permissions to read classification policies")
+	}
+
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "classification_policy.get"), `
+		MATCH (p:ClassificationPolicy {id: $id, space_id: $space_id})
+		RETURN `+classificationPolicyFields, map[string]interface{}{
+		"id":       policyID,
+		"space_id": spaceCtx.SpaceID,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to get classification policy", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, errors.NotFoundWithDetails("Classification policy not found", map[string]interface{}{
+			"policy_id": policyID,
+		}).WithErrorCode(errors.CodeClassificationPolicyNotFound)
+	}
+	return recordToClassificationPolicy(result.Records[0]), nil
+}
+
+// UpdatePolicy changes a classification policy of the current space
+func (s *ClassificationPolicyService) UpdatePolicy(ctx context.Context, policyID string, req models.ClassificationPolicyUpdateRequest, spaceCtx *models.SpaceContext) (*models.ClassificationPolicy, error) {
+	if !spaceCtx.CanManage() {
+		return nil, errors.Forbidden("Only space owners and admins can manage classification policies")
+	}
+	policy, err := s.GetPolicy(ctx, policyID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		policy.Name = *req.Name
+	}
+	if req.Description != nil {
+		policy.Description = *req.Description
+	}
+	if req.Enabled != nil {
+		policy.Enabled = *req.Enabled
+	}
+	if req.Priority != nil {
+		policy.Priority = *req.Priority
+	}
+	if req.Conditions != nil {
+		policy.Conditions = req.Conditions
+	}
+	if req.Actions != nil {
+		policy.Actions = *req.Actions
+	}
+	if err := s.validatePolicy(ctx, policy.Conditions, policy.Actions, spaceCtx); err != nil {
+		return nil, err
+	}
+
+	policy.UpdatedAt = time.Now().UTC()
+	if err := s.savePolicy(ctx, policy, "MATCH (p:ClassificationPolicy {id: $id})"); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// DeletePolicy deletes a classification policy of the current space.
+// Documents it already routed stay where they are.
+func (s *ClassificationPolicyService) DeletePolicy(ctx context.Context, policyID string, spaceCtx *models.SpaceContext) error {
+	if !spaceCtx.CanManage() {
+		return errors.Forbidden("Only space owners and admins can manage classification policies")
+	}
+
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "classification_policy.delete"), `
+		MATCH (p:ClassificationPolicy {id: $id, space_id: $space_id})
+		DETACH DELETE p
+		RETURN count(*) AS deleted
+	`, map[string]interface{}{
+		"id":       policyID,
+		"space_id": spaceCtx.SpaceID,
+	})
+	if err != nil {
+		return errors.Database("Failed to delete classification policy", err)
+	}
+	if recordInt(result.Records, "deleted") == 0 {
+		return errors.NotFoundWithDetails("Classification policy not found", map[string]interface{}{
+			"policy_id": policyID,
+		}).WithErrorCode(errors.CodeClassificationPolicyNotFound)
+	}
+	return nil
+}
+
+// savePolicy writes every field of a policy to the node matched or created
+// by the given clause
+func (s *ClassificationPolicyService) savePolicy(ctx context.Context, policy *models.ClassificationPolicy, clause string) error {
+	conditions, err := json.Marshal(policy.Conditions)
+	if err != nil {
+		return errors.InternalWithCause("Failed to encode policy conditions", err)
+	}
+	actions, err := json.Marshal(policy.Actions)
+	if err != nil {
+		return errors.InternalWithCause("Failed to encode policy actions", err)
+	}
+
+	_, err = s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "classification_policy.save"), clause+`
+		SET p.space_id = $space_id, p.tenant_id = $tenant_id, p.name = $name,
+		    p.description = $description, p.enabled = $enabled, p.priority = $priority,
+		    p.conditions = $conditions, p.actions = $actions, p.created_by = $created_by,
+		    p.created_at = $created_at, p.updated_at = $updated_at
+	`, map[string]interface{}{
+		"id":          policy.ID,
+		"space_id":    policy.SpaceID,
+		"tenant_id":   policy.TenantID,
+		"name":        policy.Name,
+		"description": policy.Description,
+		"enabled":     policy.Enabled,
+		"priority":    policy.Priority,
+		"conditions":  string(conditions),
+		"actions":     string(actions),
+		"created_by":  policy.CreatedBy,
+		"created_at":  policy.CreatedAt,
+		"updated_at":  policy.UpdatedAt,
+	})
+	if err != nil {
+		s.logger.Error("Failed to save classification policy", zap.String("policy_id", policy.ID), zap.Error(err))
+		return errors.Database("Failed to save classification policy", err)
+	}
+	return nil
+}
+
+// validatePolicy checks what the request validation cannot: that every
+// condition has its value, that the policy does something and that its
+// target notebook is in the space
+func (s *ClassificationPolicyService) validatePolicy(ctx context.Context, conditions []models.ClassificationCondition, actions models.ClassificationActions, spaceCtx *models.SpaceContext) error {
+	invalid := func(reason string) error {
+		return errors.ValidationWithDetails("Invalid classification policy", map[string]interface{}{
+			"reason": reason,
+		}).WithErrorCode(errors.CodeInvalidClassificationPolicy)
+	}
+
+	for i, condition := range conditions {
+		switch {
+		case condition.Operator == models.ClassificationOpIn && len(condition.Values) == 0:
+			return invalid(fmt.Sprintf("condition %d: the in operator needs values", i+1))
+		case condition.Operator != models.ClassificationOpIn && condition.Value == "":
+			return invalid(fmt.Sprintf("condition %d: the %s operator needs a value", i+1, condition.Operator))
+		case condition.Field == models.ClassificationFieldPIIDetected && condition.Operator != models.ClassificationOpIn &&
+			condition.Value != "true" && condition.Value != "false":
+			return invalid(fmt.Sprintf("condition %d: pii_detected is true or false", i+1))
+		}
+	}
+	if actions.IsEmpty() {
+		return invalid("the policy has no actions")
+	}
+
+	if actions.MoveToNotebookID != "" {
+		ctx = database.WithQueryName(ctx, "classification_policy.target_notebook")
+		result, err := s.neo4j.ExecuteQuery(ctx, `
+			MATCH (n:Notebook {id: $id, space_id: $space_id})
+			WHERE `+database.SoftDeleteFilter(ctx, "n")+`
+			RETURN n.id AS id
+		`, map[string]interface{}{
+			"id":       actions.MoveToNotebookID,
+			"space_id": spaceCtx.SpaceID,
+		})
+		if err != nil {
+			return errors.Database("Failed to check target notebook", err)
+		}
+		if len(result.Records) == 0 {
+			return invalid("move_to_notebook_id is not a notebook of the space")
+		}
+	}
+	return nil
+}
+
+// spacePolicies returns the policies of a space in the order they run
+func (s *ClassificationPolicyService) spacePolicies(ctx context.Context, spaceID string, enabledOnly bool) ([]*models.ClassificationPolicy, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "classification_policy.list"), `
+		MATCH (p:ClassificationPolicy {space_id: $space_id})
+		WHERE p.enabled OR NOT $enabled_only
+		RETURN `+classificationPolicyFields+`
+		ORDER BY p.priority, p.created_at
+	`, map[string]interface{}{
+		"space_id":     spaceID,
+		"enabled_only": enabledOnly,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to list classification policies", err)
+	}
+
+	policies := make([]*models.ClassificationPolicy, 0, len(result.Records))
+	for _, record := range result.Records {
+		policies = append(policies, recordToClassificationPolicy(record))
+	}
+	return policies, nil
+}
+
+// Evaluate reports what policies would do to documents without applying
+// them: the space's enabled policies, or the unsaved policy of the request
+func (s *ClassificationPolicyService) Evaluate(ctx context.Context, req models.ClassificationEvaluateRequest, userID string, spaceCtx *models.SpaceContext) (*models.ClassificationEvaluateResponse, error) {
+	if !spaceCtx.CanManage() {
+		return nil, errors.Forbidden("Only space owners and admins can manage classification policies")
+	}
+	if len(req.DocumentIDs) == 0 && req.NotebookID == "" {
+		return nil, errors.ValidationWithDetails("Nothing to evaluate", map[string]interface{}{
+			"reason": "give document_ids or notebook_id",
+		})
+	}
+
+	var policies []*models.ClassificationPolicy
+	if req.Policy != nil {
+		if err := s.validatePolicy(ctx, req.Policy.Conditions, req.Policy.Actions, spaceCtx); err != nil {
+			return nil, err
+		}
+		policies = []*models.ClassificationPolicy{{
+			Name:       req.Policy.Name,
+			Enabled:    true,
+			Priority:   req.Policy.Priority,
+			Conditions: req.Policy.Conditions,
+			Actions:    req.Policy.Actions,
+		}}
+	} else {
+		var err error
+		if policies, err = s.spacePolicies(ctx, spaceCtx.SpaceID, true); err != nil {
+			return nil, err
+		}
+	}
+
+	documents, err := s.evaluationDocuments(ctx, req, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.ClassificationEvaluateResponse{Outcomes: make([]*models.ClassificationOutcome, 0, len(documents))}
+	for _, document := range documents {
+		outcome := classifyDocument(policies, document)
+		if len(outcome.PolicyIDs) > 0 {
+			response.Matched++
+		}
+		response.Outcomes = append(response.Outcomes, outcome)
+	}
+	return response, nil
+}
+
+// evaluationDocuments returns the documents of a dry run: those listed, or
+// the processed documents of the notebook
+func (s *ClassificationPolicyService) evaluationDocuments(ctx context.Context, req models.ClassificationEvaluateRequest, spaceCtx *models.SpaceContext) ([]*models.Document, error) {
+	ctx = database.WithQueryName(ctx, "classification_policy.evaluation_documents")
+	query := `
+		MATCH (d:Document {space_id: $space_id})
+		WHERE d.id IN $document_ids AND ` + database.SoftDeleteFilter(ctx, "d") + `
+		RETURN ` + classificationDocumentFields + `
+		ORDER BY d.name
+		LIMIT $limit
+	`
+	if len(req.DocumentIDs) == 0 {
+		query = `
+		MATCH (d:Document {space_id: $space_id, notebook_id: $notebook_id})
+		WHERE d.status = 'processed' AND ` + database.SoftDeleteFilter(ctx, "d") + `
+		RETURN ` + classificationDocumentFields + `
+		ORDER BY d.name
+		LIMIT $limit
+	`
+	}
+
+	result, err := s.neo4j.ExecuteQuery(ctx, query, map[string]interface{}{
+		"space_id":     spaceCtx.SpaceID,
+		"document_ids": req.DocumentIDs,
+		"notebook_id":  req.NotebookID,
+		"limit":        classificationEvaluateLimit,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to read documents to evaluate", err)
+	}
+
+	documents := make([]*models.Document, 0, len(result.Records))
+	for _, record := range result.Records {
+		document, err := s.documents.recordToDocument(record)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, document)
+	}
+	return documents, nil
+}
+
+// HandleDomainEvent applies the space's policies to documents once they are
+// processed, and again when they are reprocessed. It is subscribed to the
+// domain event bus.
+func (s *ClassificationPolicyService) HandleDomainEvent(ctx context.Context, event Event) error {
+	if event.Type == EventDocumentProcessed {
+		return s.ApplyPolicies(ctx, event.Subject)
+	}
+	return nil
+}
+
+// ApplyPolicies applies the enabled policies of a document's space to it
+func (s *ClassificationPolicyService) ApplyPolicies(ctx context.Context, documentID string) error {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "classification_policy.document"), `
+		MATCH (d:Document {id: $id})
+		WHERE `+database.NotDeleted("d")+`
+		RETURN `+classificationDocumentFields, map[string]interface{}{"id": documentID})
+	if err != nil {
+		return fmt.Errorf("failed to read document for classification: %w", err)
+	}
+	if len(result.Records) == 0 {
+		return nil
+	}
+	document, err := s.documents.recordToDocument(result.Records[0])
+	if err != nil {
+		return err
+	}
+
+	policies, err := s.spacePolicies(ctx, document.SpaceID, true)
+	if err != nil {
+		return err
+	}
+	outcome := classifyDocument(policies, document)
+	if len(outcome.PolicyIDs) == 0 {
+		return nil
+	}
+	return s.apply(ctx, document, outcome)
+}
+
+// apply makes the changes of an outcome to its document and publishes the
+// updated document
+func (s *ClassificationPolicyService) apply(ctx context.Context, document *models.Document, outcome *models.ClassificationOutcome) error {
+	for _, tag := range outcome.Actions.AddTags {
+		document.AddTag(tag)
+	}
+	if outcome.Actions.RestrictAccess {
+		document.Restricted = true
+	}
+	document.UpdatedAt = time.Now()
+
+	_, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "classification_policy.apply"), `
+		MATCH (d:Document {id: $id})
+		SET d.tags = $tags,
+		    d.search_text = $search_text,
+		    d.restricted = $restricted,
+		    d.classification_policy_ids = $policy_ids,
+		    d.classified_at = $classified_at,
+		    d.updated_at = datetime($updated_at)
+	`, map[string]interface{}{
+		"id":            document.ID,
+		"tags":          document.Tags,
+		"search_text":   document.SearchText,
+		"restricted":    document.Restricted,
+		"policy_ids":    outcome.PolicyIDs,
+		"classified_at": time.Now().UTC(),
+		"updated_at":    document.UpdatedAt.Format(time.RFC3339),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply classification policies: %w", err)
+	}
+
+	movedFrom := ""
+	if outcome.Actions.MoveToNotebookID != "" {
+		if movedFrom, err = s.move(ctx, document.ID, outcome.Actions.MoveToNotebookID); err != nil {
+			return err
+		}
+		if movedFrom != "" {
+			document.NotebookID = outcome.Actions.MoveToNotebookID
+		}
+	}
+
+	data := documentEventData(document)
+	data["classification_policy_ids"] = outcome.PolicyIDs
+	if movedFrom != "" {
+		data["moved_from_notebook_id"] = movedFrom
+	}
+	publishDomainEvent(ctx, s.events, s.logger, NewDocumentEvent(EventDocumentUpdated, document.ID, "", data))
+
+	s.logger.Info("Classification policies applied",
+		zap.String("document_id", document.ID),
+		zap.Strings("policy_ids", outcome.PolicyIDs),
+		zap.String("moved_from", movedFrom),
+		zap.Int("tags_added", len(outcome.Actions.AddTags)),
+		zap.Bool("restricted", outcome.Actions.RestrictAccess))
+	return nil
+}
+
+// move moves a document to another notebook of its space, keeping the
+// notebooks' counters, and returns the notebook it left. Nothing moves when
+// the target has been deleted since the policy was saved.
+func (s *ClassificationPolicyService) move(ctx context.Context, documentID, notebookID string) (string, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "classification_policy.move"), `
+		MATCH (d:Document {id: $id})
+		MATCH (target:Notebook {id: $notebook_id, space_id: d.space_id})
+		WHERE `+database.NotDeleted("target")+` AND d.notebook_id <> target.id
+		OPTIONAL MATCH (d)-[r:BELONGS_TO]->(old:Notebook)
+		DELETE r
+		MERGE (d)-[:BELONGS_TO]->(target)
+		SET d.notebook_id = target.id,
+		    target.document_count = COALESCE(target.document_count, 0) + 1,
+		    target.total_size_bytes = COALESCE(target.total_size_bytes, 0) + COALESCE(d.size_bytes, 0)
+		WITH d, old
+		FOREACH (n IN CASE WHEN old IS NULL THEN [] ELSE [old] END |
+		    SET n.document_count = COALESCE(n.document_count, 0) - 1,
+		        n.total_size_bytes = COALESCE(n.total_size_bytes, 0) - COALESCE(d.size_bytes, 0))
+		RETURN old.id AS moved_from
+	`, map[string]interface{}{
+		"id":          documentID,
+		"notebook_id": notebookID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to move classified document: %w", err)
+	}
+	if len(result.Records) == 0 {
+		s.logger.Warn("Classification target notebook not found, document not moved",
+			zap.String("document_id", documentID),
+			zap.String("notebook_id", notebookID))
+		return "", nil
+	}
+	return recordString(result.Records[0], "moved_from"), nil
+}
+
+// LiftRestriction makes a restricted document visible to the whole space
+// again. A policy that restricts it does so again if it is reprocessed.
+func (s *ClassificationPolicyService) LiftRestriction(ctx context.Context, documentID string, spaceCtx *models.SpaceContext) error {
+	if !spaceCtx.CanManage() {
+		return errors.Forbidden("Only space owners and admins can lift document restrictions")
+	}
+
+	ctx = database.WithQueryName(ctx, "classification_policy.lift_restriction")
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (d:Document {id: $id, space_id: $space_id})
+		WHERE `+database.SoftDeleteFilter(ctx, "d")+`
+		SET d.restricted = false
+		RETURN d.id AS id
+	`, map[string]interface{}{
+		"id":       documentID,
+		"space_id": spaceCtx.SpaceID,
+	})
+	if err != nil {
+		return errors.Database("Failed to lift document restriction", err)
+	}
+	if len(result.Records) == 0 {
+		return errors.NotFoundWithDetails("Document not found", map[string]interface{}{
+			"document_id": documentID,
+		}).WithErrorCode(errors.CodeDocumentNotFound)
+	}
+	return nil
+}
+
+// classifyDocument runs policies, in order, against a document. Actions
+// that would change nothing, such as a tag the document has, are left out.
+func classifyDocument(policies []*models.ClassificationPolicy, document *models.Document) *models.ClassificationOutcome {
+	outcome := &models.ClassificationOutcome{
+		DocumentID:   document.ID,
+		DocumentName: document.Name,
+		NotebookID:   document.NotebookID,
+		PolicyIDs:    []string{},
+	}
+
+	tags := make(map[string]bool, len(document.Tags))
+	for _, tag := range document.Tags {
+		tags[tag] = true
+	}
+	moveDecided := false
+	for _, policy := range policies {
+		if !policyMatches(policy, document) {
+			continue
+		}
+		outcome.PolicyIDs = append(outcome.PolicyIDs, policy.ID)
+
+		if target := policy.Actions.MoveToNotebookID; target != "" && !moveDecided {
+			moveDecided = true
+			if target != document.NotebookID {
+				outcome.Actions.MoveToNotebookID = target
+			}
+		}
+		for _, tag := range policy.Actions.AddTags {
+			if !tags[tag] {
+				tags[tag] = true
+				outcome.Actions.AddTags = append(outcome.Actions.AddTags, tag)
+			}
+		}
+		if policy.Actions.RestrictAccess && !document.Restricted {
+			outcome.Actions.RestrictAccess = true
+		}
+	}
+	return outcome
+}
+
+// policyMatches reports whether every condition of a policy holds for a
+// document
+func policyMatches(policy *models.ClassificationPolicy, document *models.Document) bool {
+	for _, condition := range policy.Conditions {
+		if !conditionHolds(condition, classificationValues(document, condition.Field)) {
+			return false
+		}
+	}
+	return len(policy.Conditions) > 0
+}
+
+// conditionHolds tests a condition against the values of its field
+func conditionHolds(condition models.ClassificationCondition, values []string) bool {
+	if condition.Operator == models.ClassificationOpNotEquals {
+		for _, value := range values {
+			if strings.EqualFold(value, condition.Value) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, value := range values {
+		switch condition.Operator {
+		case models.ClassificationOpEquals:
+			if strings.EqualFold(value, condition.Value) {
+				return true
+			}
+		case models.ClassificationOpContains:
+			if strings.Contains(strings.ToLower(value), strings.ToLower(condition.Value)) {
+				return true
+			}
+		case models.ClassificationOpIn:
+			for _, candidate := range condition.Values {
+				if strings.EqualFold(value, candidate) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// classificationValues returns the values of a condition field for a
+// document, read from the document and its AudiModal processing result
+func classificationValues(document *models.Document, field string) []string {
+	result := document.ProcessingResult
+	resultString := func(key string) []string {
+		if value, ok := result[key].(string); ok && value != "" {
+			return []string{value}
+		}
+		return nil
+	}
+
+	switch field {
+	case models.ClassificationFieldCategory:
+		values := resultString("content_category")
+		if classifications, ok := result["classifications"].(map[string]interface{}); ok {
+			if categories, ok := classifications["categories"].([]interface{}); ok {
+				for _, category := range categories {
+					if value, ok := category.(string); ok && value != "" {
+						values = append(values, value)
+					}
+				}
+			}
+		}
+		return values
+	case models.ClassificationFieldContentType:
+		return resultString("content_type")
+	case models.ClassificationFieldMimeType:
+		return []string{document.MimeType}
+	case models.ClassificationFieldExtension:
+		if values := resultString("extension"); values != nil {
+			return []string{strings.TrimPrefix(values[0], ".")}
+		}
+		if ext := filepath.Ext(document.OriginalName); ext != "" {
+			return []string{strings.TrimPrefix(ext, ".")}
+		}
+		return nil
+	case models.ClassificationFieldLanguage:
+		return resultString("language")
+	case models.ClassificationFieldPIIDetected:
+		detected, _ := result["pii_detected"].(bool)
+		return []string{fmt.Sprint(detected)}
+	case models.ClassificationFieldName:
+		return []string{document.Name}
+	case models.ClassificationFieldTag:
+		return document.Tags
+	}
+	return nil
+}
+
+// recordToClassificationPolicy reads the classificationPolicyFields of a
+// record
+func recordToClassificationPolicy(record *neo4j.Record) *models.ClassificationPolicy {
+	policy := &models.ClassificationPolicy{
+		ID:          recordString(record, "id"),
+		SpaceID:     recordString(record, "space_id"),
+		TenantID:    recordString(record, "tenant_id"),
+		Name:        recordString(record, "name"),
+		Description: recordString(record, "description"),
+		Priority:    int(recordInt64(record, "priority")),
+		Conditions:  []models.ClassificationCondition{},
+		CreatedBy:   recordString(record, "created_by"),
+		CreatedAt:   recordTime(record, "created_at"),
+		UpdatedAt:   recordTime(record, "updated_at"),
+	}
+	if value, _ := record.Get("enabled"); value != nil {
+		policy.Enabled, _ = value.(bool)
+	}
+	// Both were written by savePolicy, so a decoding error leaves them empty
+	_ = json.Unmarshal([]byte(recordString(record, "conditions")), &policy.Conditions)
+	_ = json.Unmarshal([]byte(recordString(record, "actions")), &policy.Actions)
+	return policy
+}
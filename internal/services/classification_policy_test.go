@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+func classifiedDocument() *models.Document {
+	return &models.Document{
+		ID:           "document-1",
+		Name:         "Q3 Invoice",
+		OriginalName: "q3-invoice.PDF",
+		MimeType:     "application/pdf",
+		NotebookID:   "inbox",
+		Tags:         []string{"finance"},
+		ProcessingResult: map[string]interface{}{
+			"content_category": "Invoice",
+			"classifications":  map[string]interface{}{"categories": []interface{}{"financial", "legal"}},
+			"language":         "en",
+			"pii_detected":     true,
+		},
+	}
+}
+
+func TestClassificationValues(t *testing.T) {
+	document := classifiedDocument()
+
+	assert.Equal(t, []string{"Invoice", "financial", "legal"}, classificationValues(document, models.ClassificationFieldCategory))
+	assert.Equal(t, []string{"PDF"}, classificationValues(document, models.ClassificationFieldExtension))
+	assert.Equal(t, []string{"true"}, classificationValues(document, models.ClassificationFieldPIIDetected))
+	assert.Nil(t, classificationValues(document, models.ClassificationFieldContentType))
+}
+
+func TestConditionHolds(t *testing.T) {
+	values := []string{"Invoice", "financial"}
+
+	assert.True(t, conditionHolds(models.ClassificationCondition{Operator: models.ClassificationOpEquals, Value: "invoice"}, values))
+	assert.True(t, conditionHolds(models.ClassificationCondition{Operator: models.ClassificationOpContains, Value: "NANC"}, values))
+	assert.True(t, conditionHolds(models.ClassificationCondition{Operator: models.ClassificationOpIn, Values: []string{"contract", "Financial"}}, values))
+	assert.False(t, conditionHolds(models.ClassificationCondition{Operator: models.ClassificationOpNotEquals, Value: "FINANCIAL"}, values))
+	assert.True(t, conditionHolds(models.ClassificationCondition{Operator: models.ClassificationOpNotEquals, Value: "contract"}, values))
+	assert.False(t, conditionHolds(models.ClassificationCondition{Operator: models.ClassificationOpEquals, Value: "invoice"}, nil))
+}
+
+func TestClassifyDocument(t *testing.T) {
+	policies := []*models.ClassificationPolicy{
+		{
+			ID:         "finance",
+			Conditions: []models.ClassificationCondition{{Field: models.ClassificationFieldCategory, Operator: models.ClassificationOpEquals, Value: "financial"}},
+			Actions:    models.ClassificationActions{MoveToNotebookID: "finance", AddTags: []string{"finance", "auto"}},
+		},
+		{
+			ID:         "contracts",
+			Conditions: []models.ClassificationCondition{{Field: models.ClassificationFieldCategory, Operator: models.ClassificationOpEquals, Value: "contract"}},
+			Actions:    models.ClassificationActions{MoveToNotebookID: "contracts"},
+		},
+		{
+			ID: "pii",
+			Conditions: []models.ClassificationCondition{
+				{Field: models.ClassificationFieldPIIDetected, Operator: models.ClassificationOpEquals, Value: "true"},
+				{Field: models.ClassificationFieldMimeType, Operator: models.ClassificationOpContains, Value: "pdf"},
+			},
+			Actions: models.ClassificationActions{MoveToNotebookID: "restricted", AddTags: []string{"auto", "pii"}, RestrictAccess: true},
+		},
+	}
+
+	outcome := classifyDocument(policies, classifiedDocument())
+
+	assert.Equal(t, []string{"finance", "pii"}, outcome.PolicyIDs)
+	assert.Equal(t, "finance", outcome.Actions.MoveToNotebookID, "the first matching policy with a notebook decides the move")
+	assert.Equal(t, []string{"auto", "pii"}, outcome.Actions.AddTags, "tags the document has are left out")
+	assert.True(t, outcome.Actions.RestrictAccess)
+}
+
+func TestClassifyDocumentAlreadyInTargetNotebook(t *testing.T) {
+	policies := []*models.ClassificationPolicy{
+		{
+			ID:         "stay",
+			Conditions: []models.ClassificationCondition{{Field: models.ClassificationFieldLanguage, Operator: models.ClassificationOpEquals, Value: "en"}},
+			Actions:    models.ClassificationActions{MoveToNotebookID: "inbox"},
+		},
+		{
+			ID:         "move",
+			Conditions: []models.ClassificationCondition{{Field: models.ClassificationFieldTag, Operator: models.ClassificationOpIn, Values: []string{"finance"}}},
+			Actions:    models.ClassificationActions{MoveToNotebookID: "finance"},
+		},
+	}
+
+	outcome := classifyDocument(policies, classifiedDocument())
+
+	assert.Equal(t, []string{"stay", "move"}, outcome.PolicyIDs)
+	assert.True(t, outcome.Actions.IsEmpty())
+}
+
+func TestClassificationPolicyValidation(t *testing.T) {
+	service := NewClassificationPolicyService(nil, nil, setupTestLogger(t))
+	owner := &models.SpaceContext{SpaceID: "space-1", UserRole: "owner"}
+
+	tests := []struct {
+		name       string
+		conditions []models.ClassificationCondition
+		actions    models.ClassificationActions
+	}{
+		{
+			name:       "in without values",
+			conditions: []models.ClassificationCondition{{Field: models.ClassificationFieldCategory, Operator: models.ClassificationOpIn}},
+			actions:    models.ClassificationActions{AddTags: []string{"x"}},
+		},
+		{
+			name:       "pii_detected not a boolean",
+			conditions: []models.ClassificationCondition{{Field: models.ClassificationFieldPIIDetected, Operator: models.ClassificationOpEquals, Value: "yes"}},
+			actions:    models.ClassificationActions{AddTags: []string{"x"}},
+		},
+		{
+			name:       "no actions",
+			conditions: []models.ClassificationCondition{{Field: models.ClassificationFieldLanguage, Operator: models.ClassificationOpEquals, Value: "en"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.validatePolicy(context.Background(), tt.conditions, tt.actions, owner)
+			require.Error(t, err)
+			apiErr, ok := err.(*errors.APIError)
+			require.True(t, ok)
+			assert.Equal(t, errors.CodeInvalidClassificationPolicy, apiErr.ErrorCode)
+		})
+	}
+}
+
+func TestClassificationPoliciesRequireManager(t *testing.T) {
+	service := NewClassificationPolicyService(nil, nil, setupTestLogger(t))
+	member := &models.SpaceContext{SpaceID: "space-1", UserRole: "member", Permissions: []string{"read", "write"}}
+
+	_, err := service.CreatePolicy(context.Background(), models.ClassificationPolicyCreateRequest{Name: "p"}, "user-1", member)
+	assert.True(t, errors.IsForbidden(err))
+	assert.True(t, errors.IsForbidden(service.LiftRestriction(context.Background(), "document-1", member)))
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"time"
 
 	"go.uber.org/zap"
@@ -69,7 +70,8 @@ func NewDeepLakeService(config *config.DeepLakeConfig, log *logger.Logger) *Deep
 	return &DeepLakeService{
 		baseURL: config.BaseURL,
 		httpClient: &http.Client{
-			Timeout: time.Duration(config.TimeoutSeconds) * time.Second,
+			Timeout:   time.Duration(config.TimeoutSeconds) * time.Second,
+			Transport: logger.NewRequestIDTransport(nil),
 		},
 		log:    log,
 		config: config,
@@ -353,5 +355,104 @@ func (s *DeepLakeService) createCollection(ctx context.Context, name string) err
 		return fmt.Errorf("create collection failed with status %d: %s", resp.StatusCode, string(body))
 	}
 
+	return nil
+}
+// deepLakeDatasets calls the dataset API, where every tenant namespace
+// (sent as X-Tenant-ID) holds its own datasets
+type deepLakeDatasets struct {
+	config     *config.DeepLakeConfig
+	httpClient *http.Client
+}
+
+func newDeepLakeDatasets(cfg *config.DeepLakeConfig) *deepLakeDatasets {
+	return &deepLakeDatasets{
+		config: cfg,
+		httpClient: &http.Client{
+			Timeout:   time.Duration(cfg.TimeoutSeconds) * time.Second,
+			Transport: logger.NewRequestIDTransport(nil),
+		},
+	}
+}
+
+// do sends a request for a path below the space dataset of a namespace and
+// decodes a successful response into out, which may be nil. It returns
+// the response status, so callers can tell a missing dataset (404) from
+// other failures.
+func (d *deepLakeDatasets) do(ctx context.Context, method, namespace, path string, payload, out interface{}) (int, error) {
+	var body io.Reader
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal DeepLake request: %w", err)
+		}
+		body = bytes.NewReader(encoded)
+	}
+
+	endpoint := fmt.Sprintf("%s/api/v1/datasets/%s%s", d.config.BaseURL, url.PathEscape(d.config.SpaceDataset), path)
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create DeepLake request: %w", err)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("X-Tenant-ID", namespace)
+	if d.config.APIKey != "" {
+		req.Header.Set("Authorization", "ApiKey "+d.config.APIKey)
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("DeepLake request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return resp.StatusCode, fmt.Errorf("DeepLake API error (status %d): %s", resp.StatusCode, detail)
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.StatusCode, fmt.Errorf("failed to decode DeepLake response: %w", err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// createDataset creates the space dataset in a namespace
+func (d *deepLakeDatasets) createDataset(ctx context.Context, namespace string) error {
+	payload := map[string]interface{}{
+		"name":        d.config.SpaceDataset,
+		"description": "Aether document chunks",
+		"dimensions":  d.config.VectorDimensions,
+		"metric_type": "cosine",
+		"index_type":  "default",
+	}
+	endpoint := fmt.Sprintf("%s/api/v1/datasets", d.config.BaseURL)
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dataset: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(encoded))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Tenant-ID", namespace)
+	if d.config.APIKey != "" {
+		req.Header.Set("Authorization", "ApiKey "+d.config.APIKey)
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to create dataset: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// A concurrent sync may have created it first
+	if resp.StatusCode >= 400 && resp.StatusCode != http.StatusConflict {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("create dataset failed with status %d: %s", resp.StatusCode, detail)
+	}
 	return nil
 }
\ No newline at end of file
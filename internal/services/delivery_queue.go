@@ -0,0 +1,298 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+)
+
+// Delivery queue stream and consumer group, shared by every replica
+const (
+	DeliveryQueueStream = "aether-be:deliveries"
+	DeliveryQueueGroup  = "aether-be"
+)
+
+// Kinds of delivery jobs
+const (
+	DeliveryKindNotification = "notification"
+	DeliveryKindWebhook      = "webhook"
+)
+
+const (
+	// deliveryQueueBatch bounds how many jobs a worker reads at a time
+	deliveryQueueBatch = 10
+	// deliveryQueueBlock is how long a worker waits for a job before
+	// reading again, which bounds how long shutdown waits for it
+	deliveryQueueBlock = 5 * time.Second
+	// deliveryQueueRetryDelay is how long a worker waits after Redis
+	// failed before reading again
+	deliveryQueueRetryDelay = 5 * time.Second
+	// localDeliveryBuffer is how many jobs the in-process queue holds
+	// before Enqueue handles them itself
+	localDeliveryBuffer = 1024
+)
+
+// QueueStore keeps the jobs of a stream until a consumer of a group has
+// handled them. database.RedisClient implements it with Redis Streams.
+type QueueStore interface {
+	StreamAdd(ctx context.Context, stream string, payload []byte, maxLen int64) (string, error)
+	StreamCreateGroup(ctx context.Context, stream, group string) error
+	StreamReadGroup(ctx context.Context, stream, group, consumer string, count int64, block time.Duration) ([]database.StreamEntry, error)
+	StreamClaimIdle(ctx context.Context, stream, group, consumer string, minIdle time.Duration, count int64) ([]database.StreamEntry, error)
+	StreamAck(ctx context.Context, stream, group string, ids ...string) error
+	StreamLength(ctx context.Context, stream string) (int64, error)
+}
+
+// DeliveryHandler handles a job of one kind. A job whose handler returns an
+// error is handed out again, so handlers must be idempotent.
+type DeliveryHandler func(ctx context.Context, payload json.RawMessage) error
+
+// deliveryJob is a queued job as stored in the stream
+type deliveryJob struct {
+	Kind       string          `json:"kind"`
+	Payload    json.RawMessage `json:"payload"`
+	EnqueuedAt time.Time       `json:"enqueued_at"`
+}
+
+// DeliveryQueue hands notifications and webhook deliveries to workers. With
+// a QueueStore the jobs are stored in a Redis stream read by a consumer
+// group of every replica: each job is handled by one worker, a job survives
+// restarts until it is acknowledged, and a job taken by a replica that
+// stopped is claimed by another once it has been idle for
+// DELIVERY_QUEUE_CLAIM_IDLE_SECONDS. Without a store the jobs go through an
+// in-process buffer and are lost on restart.
+type DeliveryQueue struct {
+	store    QueueStore
+	local    chan deliveryJob
+	consumer string
+	cfg      config.DeliveryQueueConfig
+
+	mu       sync.RWMutex
+	handlers map[string]DeliveryHandler
+	logger   *logger.Logger
+}
+
+// NewDeliveryQueue creates a delivery queue whose workers, once Run is
+// started, read as consumer; store may be nil
+func NewDeliveryQueue(store QueueStore, consumer string, cfg config.DeliveryQueueConfig, log *logger.Logger) *DeliveryQueue {
+	q := &DeliveryQueue{
+		store:    store,
+		consumer: consumer,
+		cfg:      cfg,
+		handlers: make(map[string]DeliveryHandler),
+		logger:   log.WithService("delivery_queue"),
+	}
+	if store == nil {
+		q.local = make(chan deliveryJob, localDeliveryBuffer)
+	}
+	return q
+}
+
+// Handle registers the handler of a kind of job
+func (q *DeliveryQueue) Handle(kind string, handler DeliveryHandler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[kind] = handler
+}
+
+// Enqueue queues a job of a kind, whose payload is encoded as JSON
+func (q *DeliveryQueue) Enqueue(ctx context.Context, kind string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s job: %w", kind, err)
+	}
+	job := deliveryJob{Kind: kind, Payload: data, EnqueuedAt: time.Now().UTC()}
+
+	if q.store == nil {
+		select {
+		case q.local <- job:
+		default:
+			// The workers fell behind; handle it here rather than drop it
+			q.handle(context.WithoutCancel(ctx), job, 1)
+		}
+		return nil
+	}
+
+	encoded, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s job: %w", kind, err)
+	}
+	if _, err := q.store.StreamAdd(ctx, DeliveryQueueStream, encoded, q.cfg.MaxLength); err != nil {
+		return fmt.Errorf("failed to queue %s job: %w", kind, err)
+	}
+	return nil
+}
+
+// Backlog returns how many jobs are waiting or being handled
+func (q *DeliveryQueue) Backlog(ctx context.Context) (int64, error) {
+	if q.store == nil {
+		return int64(len(q.local)), nil
+	}
+	return q.store.StreamLength(ctx, DeliveryQueueStream)
+}
+
+// Run handles jobs with DELIVERY_QUEUE_WORKERS workers until ctx is
+// cancelled, and claims the jobs other replicas left idle
+func (q *DeliveryQueue) Run(ctx context.Context) {
+	if q.store != nil && !q.createGroup(ctx) {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < q.cfg.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if q.store == nil {
+				q.runLocal(ctx)
+			} else {
+				q.runWorker(ctx)
+			}
+		}()
+	}
+	if q.store != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q.runClaimer(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+// createGroup creates the consumer group, retrying until Redis answers or
+// ctx is cancelled, and reports whether it exists
+func (q *DeliveryQueue) createGroup(ctx context.Context) bool {
+	for {
+		err := q.store.StreamCreateGroup(ctx, DeliveryQueueStream, DeliveryQueueGroup)
+		if err == nil {
+			return true
+		}
+		q.logger.Warn("Failed to create delivery queue group", zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(deliveryQueueRetryDelay):
+		}
+	}
+}
+
+// runLocal handles jobs of the in-process queue until ctx is cancelled
+func (q *DeliveryQueue) runLocal(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-q.local:
+			q.handle(ctx, job, 1)
+		}
+	}
+}
+
+// runWorker reads and handles jobs no worker has received until ctx is
+// cancelled
+func (q *DeliveryQueue) runWorker(ctx context.Context) {
+	for ctx.Err() == nil {
+		entries, err := q.store.StreamReadGroup(ctx, DeliveryQueueStream, DeliveryQueueGroup, q.consumer, deliveryQueueBatch, deliveryQueueBlock)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			q.logger.Warn("Failed to read delivery queue", zap.Error(err))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(deliveryQueueRetryDelay):
+			}
+			continue
+		}
+		q.handleEntries(ctx, entries)
+	}
+}
+
+// runClaimer takes over the jobs that workers of any replica received and
+// left unacknowledged for the claim idle time, such as the jobs of a
+// replica that stopped, until ctx is cancelled
+func (q *DeliveryQueue) runClaimer(ctx context.Context) {
+	idle := time.Duration(q.cfg.ClaimIdleSeconds) * time.Second
+	ticker := time.NewTicker(idle / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		entries, err := q.store.StreamClaimIdle(ctx, DeliveryQueueStream, DeliveryQueueGroup, q.consumer, idle, deliveryQueueBatch*int64(q.cfg.Workers))
+		if err != nil {
+			if ctx.Err() == nil {
+				q.logger.Warn("Failed to claim idle delivery jobs", zap.Error(err))
+			}
+			continue
+		}
+		q.handleEntries(ctx, entries)
+	}
+}
+
+// handleEntries handles jobs read from the stream and acknowledges those
+// handled, malformed or handed out too often. The others stay pending and
+// are claimed again once idle.
+func (q *DeliveryQueue) handleEntries(ctx context.Context, entries []database.StreamEntry) {
+	for _, entry := range entries {
+		var job deliveryJob
+		if err := json.Unmarshal(entry.Payload, &job); err != nil {
+			q.logger.Error("Dropping malformed delivery job", zap.String("entry_id", entry.ID), zap.Error(err))
+			q.ack(ctx, entry.ID)
+			continue
+		}
+		if q.handle(ctx, job, entry.Deliveries) || entry.Deliveries >= int64(q.cfg.MaxDeliveries) {
+			q.ack(ctx, entry.ID)
+		}
+	}
+}
+
+// handle runs the handler of a job and reports whether the job is done:
+// handled, or not worth handing out again
+func (q *DeliveryQueue) handle(ctx context.Context, job deliveryJob, deliveries int64) bool {
+	q.mu.RLock()
+	handler := q.handlers[job.Kind]
+	q.mu.RUnlock()
+	if handler == nil {
+		q.logger.Error("Dropping delivery job of unknown kind", zap.String("kind", job.Kind))
+		return true
+	}
+
+	err := handler(ctx, job.Payload)
+	if err == nil {
+		return true
+	}
+	fields := []zap.Field{
+		zap.String("kind", job.Kind),
+		zap.Int64("deliveries", deliveries),
+		zap.Duration("age", time.Since(job.EnqueuedAt)),
+		zap.Error(err),
+	}
+	if q.store == nil || deliveries >= int64(q.cfg.MaxDeliveries) {
+		q.logger.Error("Dropping failed delivery job", fields...)
+	} else {
+		q.logger.Warn("Delivery job failed; it is retried", fields...)
+	}
+	return false
+}
+
+// ack acknowledges a job. One whose acknowledgement failed is handled
+// again once idle, which handlers tolerate.
+func (q *DeliveryQueue) ack(ctx context.Context, id string) {
+	if err := q.store.StreamAck(context.WithoutCancel(ctx), DeliveryQueueStream, DeliveryQueueGroup, id); err != nil {
+		q.logger.Warn("Failed to acknowledge delivery job", zap.String("entry_id", id), zap.Error(err))
+	}
+}
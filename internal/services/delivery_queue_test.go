@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+)
+
+// fakeQueueStore is an in-memory stream with one consumer group
+type fakeQueueStore struct {
+	mu      sync.Mutex
+	next    int
+	entries map[string][]byte
+	unread  []string
+	pending map[string]int64 // Deliveries of entries read and not acknowledged
+}
+
+func newFakeQueueStore() *fakeQueueStore {
+	return &fakeQueueStore{entries: make(map[string][]byte), pending: make(map[string]int64)}
+}
+
+func (f *fakeQueueStore) StreamAdd(ctx context.Context, stream string, payload []byte, maxLen int64) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.next++
+	id := fmt.Sprintf("%d-0", f.next)
+	f.entries[id] = payload
+	f.unread = append(f.unread, id)
+	return id, nil
+}
+
+func (f *fakeQueueStore) StreamCreateGroup(ctx context.Context, stream, group string) error {
+	return nil
+}
+
+func (f *fakeQueueStore) StreamReadGroup(ctx context.Context, stream, group, consumer string, count int64, block time.Duration) ([]database.StreamEntry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var entries []database.StreamEntry
+	for len(f.unread) > 0 && int64(len(entries)) < count {
+		id := f.unread[0]
+		f.unread = f.unread[1:]
+		f.pending[id] = 1
+		entries = append(entries, database.StreamEntry{ID: id, Payload: f.entries[id], Deliveries: 1})
+	}
+	return entries, nil
+}
+
+func (f *fakeQueueStore) StreamClaimIdle(ctx context.Context, stream, group, consumer string, minIdle time.Duration, count int64) ([]database.StreamEntry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var entries []database.StreamEntry
+	for id := range f.pending {
+		f.pending[id]++
+		entries = append(entries, database.StreamEntry{ID: id, Payload: f.entries[id], Deliveries: f.pending[id]})
+	}
+	return entries, nil
+}
+
+func (f *fakeQueueStore) StreamAck(ctx context.Context, stream, group string, ids ...string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, id := range ids {
+		delete(f.pending, id)
+		delete(f.entries, id)
+	}
+	return nil
+}
+
+func (f *fakeQueueStore) StreamLength(ctx context.Context, stream string) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return int64(len(f.entries)), nil
+}
+
+var testDeliveryQueueConfig = config.DeliveryQueueConfig{Workers: 1, ClaimIdleSeconds: 60, MaxDeliveries: 3, MaxLength: 1000}
+
+func TestDeliveryQueueAcknowledgesHandledJobs(t *testing.T) {
+	store := newFakeQueueStore()
+	queue := NewDeliveryQueue(store, "replica-1", testDeliveryQueueConfig, setupTestLogger(t))
+	var received []string
+	queue.Handle(DeliveryKindWebhook, func(ctx context.Context, payload json.RawMessage) error {
+		var job webhookJob
+		require.NoError(t, json.Unmarshal(payload, &job))
+		received = append(received, job.DeliveryID)
+		return nil
+	})
+	ctx := context.Background()
+
+	require.NoError(t, queue.Enqueue(ctx, DeliveryKindWebhook, webhookJob{DeliveryID: "delivery-1"}))
+	require.NoError(t, queue.Enqueue(ctx, "unknown", map[string]string{}))
+	backlog, err := queue.Backlog(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), backlog)
+
+	entries, err := store.StreamReadGroup(ctx, DeliveryQueueStream, DeliveryQueueGroup, "replica-1", 10, 0)
+	require.NoError(t, err)
+	queue.handleEntries(ctx, entries)
+
+	assert.Equal(t, []string{"delivery-1"}, received)
+	backlog, err = queue.Backlog(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, backlog, "handled jobs and jobs of unknown kinds are acknowledged")
+}
+
+func TestDeliveryQueueRetriesFailedJobsUntilMaxDeliveries(t *testing.T) {
+	store := newFakeQueueStore()
+	queue := NewDeliveryQueue(store, "replica-1", testDeliveryQueueConfig, setupTestLogger(t))
+	attempts := 0
+	queue.Handle(DeliveryKindNotification, func(ctx context.Context, payload json.RawMessage) error {
+		attempts++
+		return fmt.Errorf("redis unavailable")
+	})
+	ctx := context.Background()
+	require.NoError(t, queue.Enqueue(ctx, DeliveryKindNotification, &HubEvent{TenantID: "tenant_1"}))
+
+	entries, err := store.StreamReadGroup(ctx, DeliveryQueueStream, DeliveryQueueGroup, "replica-1", 10, 0)
+	require.NoError(t, err)
+	queue.handleEntries(ctx, entries)
+	assert.Len(t, store.pending, 1, "a failed job stays pending")
+
+	for i := 0; i < 2; i++ {
+		entries, err = store.StreamClaimIdle(ctx, DeliveryQueueStream, DeliveryQueueGroup, "replica-2", time.Minute, 10)
+		require.NoError(t, err)
+		queue.handleEntries(ctx, entries)
+	}
+	assert.Equal(t, 3, attempts)
+	assert.Empty(t, store.pending, "a job handed out DELIVERY_QUEUE_MAX_DELIVERIES times is dropped")
+}
+
+func TestEventHubQueuesNotifications(t *testing.T) {
+	hub := NewEventHub(nil, setupTestLogger(t))
+	queue := NewDeliveryQueue(nil, "replica-1", testDeliveryQueueConfig, setupTestLogger(t))
+	hub.SetDeliveryQueue(queue)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go queue.Run(ctx)
+
+	events, err := hub.Subscribe(ctx, "tenant_1", HubTopicNotification)
+	require.NoError(t, err)
+
+	hub.PublishNotification(ctx, "tenant_1", "user_1", "doc", NotificationDocumentFailed, map[string]string{"name": "Report"})
+
+	event := receiveHubEvent(t, events)
+	assert.Equal(t, "doc", event.ResourceID)
+	assert.Equal(t, NotificationDocumentFailed, event.Status)
+	assert.Equal(t, "user_1", event.Data["user_id"])
+}
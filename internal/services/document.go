@@ -1,10 +1,11 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"math"
+	"io"
 	"reflect"
 	"strings"
 	"time"
@@ -13,9 +14,12 @@ import (
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"go.uber.org/zap"
 
+	"github.com/Tributary-ai-services/aether-be/internal/config"
 	"github.com/Tributary-ai-services/aether-be/internal/database"
 	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/metrics"
 	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
 	"github.com/Tributary-ai-services/aether-be/pkg/errors"
 )
 
@@ -23,11 +27,62 @@ import (
 type DocumentService struct {
 	neo4j           *database.Neo4jClient
 	notebookService *NotebookService
+	events          DomainEventPublisher
+	metrics         *metrics.Metrics
 	logger          *logger.Logger
 
 	// External services (will be injected)
 	storageService    StorageService
-	processingService ProcessingService
+	processors        *ProcessingProviderRegistry
+	processingJobs    ProcessingJobRepository
+
+	// sagas roll back uploads that fail part way
+	sagas *SagaCoordinator
+
+	// deletions remove deleted documents' copies from other services
+	deletions *DeletionOrchestrator
+
+	// quotas refuse documents over the space's quotas; nil enforces none
+	quotas *QuotaService
+
+	// authz decides who may change a document
+	authz *AuthorizationService
+
+	// encryption encrypts the extracted text of documents in spaces that
+	// encrypt text at rest; nil stores text as it is
+	encryption *TextEncryptionService
+
+	// legalHolds keeps held documents from being edited or deleted; nil
+	// holds none
+	legalHolds *LegalHoldService
+
+	// scanner scans uploaded files for malware before they are stored;
+	// nil scans none
+	scanner *ScannerService
+
+	// fileValidator checks uploaded files against their content; nil
+	// trusts the types clients declare
+	fileValidator *FileValidator
+
+	// priorities are the default processing priority and the highest
+	// each billing plan may ask for
+	priorities config.ProcessingPriorityConfig
+
+	// downloads sets which files are redirected to presigned URLs rather
+	// than streamed
+	downloads config.DownloadConfig
+
+	// maxDirectUploadBytes bounds the files of presigned direct uploads
+	maxDirectUploadBytes int64
+
+	// Background work (scheduled retries) is bound to this context so it
+	// stops on shutdown instead of outliving the server
+	backgroundCtx        context.Context
+	backgroundJobTimeout time.Duration
+	workers              *WorkerGroup
+
+	// instanceID owns the leases of the retry jobs this replica claims
+	instanceID string
 }
 
 // StorageService interface for file storage operations
@@ -39,21 +94,68 @@ type StorageService interface {
 	DeleteFile(ctx context.Context, key string) error
 	DeleteFileFromTenantBucket(ctx context.Context, tenantID, key string) error
 	GetFileURL(ctx context.Context, key string, expiration time.Duration) (string, error)
-}
 
-// ProcessingService interface for document processing operations
-type ProcessingService interface {
-	SubmitProcessingJob(ctx context.Context, tenantID string, documentID string, jobType string, config map[string]interface{}) (*models.ProcessingJob, error)
-	GetProcessingJob(ctx context.Context, jobID string) (*models.ProcessingJob, error)
-	CancelProcessingJob(ctx context.Context, jobID string) error
+	// Multipart uploads into a tenant's bucket, for files too large to
+	// hold in memory
+	CreateMultipartUpload(ctx context.Context, tenantID, key, contentType string) (string, error)
+	UploadPart(ctx context.Context, tenantID, key, uploadID string, partNumber int, data []byte) (string, error)
+	CompleteMultipartUpload(ctx context.Context, tenantID, key, uploadID string, parts []*models.UploadSessionPart) (string, error)
+	AbortMultipartUpload(ctx context.Context, tenantID, key, uploadID string) error
+	OpenFileFromTenantBucket(ctx context.Context, tenantID, key string) (io.ReadCloser, error)
+
+	// Direct uploads, where clients store files in a tenant's bucket
+	// themselves with a presigned URL
+	PresignTenantUpload(ctx context.Context, tenantID, key, contentType string, sizeBytes int64, expiration time.Duration) (string, error)
+	GetTenantFileInfo(ctx context.Context, tenantID, key string) (*FileMetadata, error)
+
+	// Downloads streamed from a tenant's bucket, whole or by byte range,
+	// or handed off to it with a presigned URL
+	ReadTenantFile(ctx context.Context, tenantID, key string, opts FileReadOptions) (*FileReader, error)
+	PresignTenantDownload(ctx context.Context, tenantID, key, fileName string, expiration time.Duration) (string, error)
 }
 
 // NewDocumentService creates a new document service
 func NewDocumentService(neo4j *database.Neo4jClient, notebookService *NotebookService, log *logger.Logger) *DocumentService {
-	return &DocumentService{
+	service := &DocumentService{
 		neo4j:           neo4j,
 		notebookService: notebookService,
 		logger:          log.WithService("document_service"),
+		sagas:           NewSagaCoordinator(neo4j, log),
+
+		backgroundCtx:        context.Background(),
+		backgroundJobTimeout: defaultBackgroundJobTimeout,
+		workers:              NewWorkerGroup(),
+		instanceID:           uuid.New().String(),
+	}
+	service.registerSagaCompensations()
+	return service
+}
+
+// SetInstanceID names this replica as the owner of the retry jobs it claims
+func (s *DocumentService) SetInstanceID(instanceID string) {
+	if instanceID != "" {
+		s.instanceID = instanceID
+	}
+}
+
+// SetBackgroundContext sets the context that bounds background goroutines.
+// Cancelling it (on shutdown) aborts retries that are still waiting to run.
+func (s *DocumentService) SetBackgroundContext(ctx context.Context) {
+	s.backgroundCtx = ctx
+}
+
+// SetWorkerGroup sets the group that tracks background goroutines so
+// shutdown can wait for retries that are already running
+func (s *DocumentService) SetWorkerGroup(workers *WorkerGroup) {
+	if workers != nil {
+		s.workers = workers
+	}
+}
+
+// SetBackgroundJobTimeout overrides the deadline for a single background job
+func (s *DocumentService) SetBackgroundJobTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		s.backgroundJobTimeout = timeout
 	}
 }
 
@@ -62,9 +164,87 @@ func (s *DocumentService) SetStorageService(storageService StorageService) {
 	s.storageService = storageService
 }
 
-// SetProcessingService sets the processing service dependency
-func (s *DocumentService) SetProcessingService(processingService ProcessingService) {
-	s.processingService = processingService
+// SetMaxDirectUploadBytes sets the largest file a direct upload accepts
+func (s *DocumentService) SetMaxDirectUploadBytes(maxBytes int64) {
+	s.maxDirectUploadBytes = maxBytes
+}
+
+// SetProcessingProviders sets the processing providers documents are
+// processed with, chosen per space
+func (s *DocumentService) SetProcessingProviders(processors *ProcessingProviderRegistry) {
+	s.processors = processors
+}
+
+// SetProcessingJobRepository sets the store of processing jobs, which are
+// finished when their document's processing outcome arrives
+func (s *DocumentService) SetProcessingJobRepository(jobs ProcessingJobRepository) {
+	s.processingJobs = jobs
+}
+
+// SetDeletionOrchestrator sets what removes the copies of deleted
+// documents held outside Neo4j
+func (s *DocumentService) SetDeletionOrchestrator(deletions *DeletionOrchestrator) {
+	s.deletions = deletions
+}
+
+// SetQuotaService sets the service that enforces space quotas on new
+// documents and versions
+func (s *DocumentService) SetQuotaService(quotas *QuotaService) {
+	s.quotas = quotas
+}
+
+// SetAuthorizationService sets the service deciding who may change a
+// document; without it the built-in roles apply
+func (s *DocumentService) SetAuthorizationService(authz *AuthorizationService) {
+	s.authz = authz
+}
+
+// SetTextEncryption sets the service encrypting the extracted text of
+// documents in spaces that turn encryption at rest on
+func (s *DocumentService) SetTextEncryption(encryption *TextEncryptionService) {
+	s.encryption = encryption
+}
+
+// SetLegalHolds sets the service keeping documents under legal hold from
+// being edited or deleted
+func (s *DocumentService) SetLegalHolds(legalHolds *LegalHoldService) {
+	s.legalHolds = legalHolds
+}
+
+// SetScanner sets the service scanning the files uploaded to spaces that
+// scan for malware
+func (s *DocumentService) SetScanner(scanner *ScannerService) {
+	s.scanner = scanner
+}
+
+// scanUpload scans a file uploaded to a space that scans its uploads for
+// malware, rejecting infected files
+func (s *DocumentService) scanUpload(ctx context.Context, spaceCtx *models.SpaceContext, userID string, fileInfo models.FileInfo, data []byte) error {
+	if s.scanner == nil || !s.scanner.Enabled(s.spaceSettings(ctx, spaceCtx.SpaceID)) {
+		return nil
+	}
+	return s.scanner.CheckUpload(ctx, spaceCtx, userID, fileInfo, data)
+}
+
+// SetEventPublisher sets the publisher notified of document changes
+func (s *DocumentService) SetEventPublisher(events DomainEventPublisher) {
+	s.events = events
+}
+
+// SetMetrics records per-tenant processing job metrics
+func (s *DocumentService) SetMetrics(m *metrics.Metrics) {
+	s.metrics = m
+}
+
+// countProcessingJob records a processing job status for a tenant and
+// counts finished jobs against the processing SLO
+func (s *DocumentService) countProcessingJob(tenantID, status string) {
+	if s.metrics != nil {
+		s.metrics.IncTenantProcessingJobs(tenantID, status)
+		if status == "processed" || status == "failed" {
+			s.metrics.RecordProcessingOutcome(status == "processed")
+		}
+	}
 }
 
 // CreateDocument creates a new document record (without file upload)
@@ -77,6 +257,13 @@ func (s *DocumentService) CreateDocument(ctx context.Context, req models.Documen
 		})
 	}
 
+	// A priority the space's plan does not allow is refused before
+	// anything is stored
+	priority, err := s.processingPriority(spaceCtx, req.Priority, "")
+	if err != nil {
+		return nil, err
+	}
+
 	// Verify notebook exists and belongs to the correct space
 	notebook, err := s.notebookService.GetNotebookByID(ctx, req.NotebookID, ownerID, spaceCtx)
 	if err != nil {
@@ -88,11 +275,18 @@ func (s *DocumentService) CreateDocument(ctx context.Context, req models.Documen
 		return nil, errors.ForbiddenWithDetails("Notebook not accessible in this space", map[string]interface{}{
 			"notebook_id": req.NotebookID,
 			"space_id":    spaceCtx.SpaceID,
-		})
+		}).WithErrorCode(errors.CodeNotebookNotAccessible)
+	}
+
+	if s.quotas != nil {
+		if err := s.quotas.CheckDocument(ctx, spaceCtx.SpaceID, fileInfo.SizeBytes); err != nil {
+			return nil, err
+		}
 	}
 
 	// Create new document
 	document := models.NewDocument(req, ownerID, fileInfo, spaceCtx)
+	document.ProcessingPriority = priority
 
 	// Create document in Neo4j
 	query := `
@@ -116,6 +310,7 @@ func (s *DocumentService) CreateDocument(ctx context.Context, req models.Documen
 			tags: $tags,
 			search_text: $search_text,
 			metadata: $metadata,
+			processing_priority: $processing_priority,
 			created_at: datetime($created_at),
 			updated_at: datetime($updated_at)
 		})
@@ -136,27 +331,28 @@ func (s *DocumentService) CreateDocument(ctx context.Context, req models.Documen
 	}
 
 	params := map[string]interface{}{
-		"id":             document.ID,
-		"name":           document.Name,
-		"description":    document.Description,
-		"type":           document.Type,
-		"status":         document.Status,
-		"original_name":  document.OriginalName,
-		"mime_type":      document.MimeType,
-		"size_bytes":     document.SizeBytes,
-		"checksum":       document.Checksum,
-		"storage_path":   document.StoragePath,
-		"storage_bucket": document.StorageBucket,
-		"notebook_id":    document.NotebookID,
-		"owner_id":       document.OwnerID,
-		"space_type":     string(document.SpaceType),
-		"space_id":       document.SpaceID,
-		"tenant_id":      document.TenantID,
-		"tags":           document.Tags,
-		"search_text":    document.SearchText,
-		"metadata":       metadataJSON,
-		"created_at":     document.CreatedAt.Format(time.RFC3339),
-		"updated_at":     document.UpdatedAt.Format(time.RFC3339),
+		"id":                  document.ID,
+		"name":                document.Name,
+		"description":         document.Description,
+		"type":                document.Type,
+		"status":              document.Status,
+		"original_name":       document.OriginalName,
+		"mime_type":           document.MimeType,
+		"size_bytes":          document.SizeBytes,
+		"checksum":            document.Checksum,
+		"storage_path":        document.StoragePath,
+		"storage_bucket":      document.StorageBucket,
+		"notebook_id":         document.NotebookID,
+		"owner_id":            document.OwnerID,
+		"space_type":          string(document.SpaceType),
+		"space_id":            document.SpaceID,
+		"tenant_id":           document.TenantID,
+		"tags":                document.Tags,
+		"search_text":         document.SearchText,
+		"metadata":            metadataJSON,
+		"processing_priority": document.ProcessingPriority,
+		"created_at":          document.CreatedAt.Format(time.RFC3339),
+		"updated_at":          document.UpdatedAt.Format(time.RFC3339),
 	}
 
 	_, err = s.neo4j.ExecuteQueryWithLogging(ctx, query, params)
@@ -171,6 +367,9 @@ func (s *DocumentService) CreateDocument(ctx context.Context, req models.Documen
 		// Don't fail the entire operation
 	}
 
+	publishDomainEvent(ctx, s.events, s.logger,
+		NewDocumentEvent(EventDocumentUploaded, document.ID, ownerID, documentEventData(document)))
+
 	s.logger.Info("Document created successfully",
 		zap.String("document_id", document.ID),
 		zap.String("name", document.Name),
@@ -187,11 +386,35 @@ func (s *DocumentService) UploadDocument(ctx context.Context, req models.Documen
 		return nil, errors.Internal("Storage service not configured")
 	}
 
-	// Use provided file info (MIME type from frontend)
+	// The declared MIME type is checked against the file's content, and
+	// the space's type and size restrictions applied
+	fileInfo, err := s.validateUpload(ctx, spaceCtx, fileInfo, req.FileData)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each step registers how it is undone, so a failure at any step, or a
+	// crash, leaves no record or file behind
+	saga, err := s.sagas.Begin(ctx, sagaKindUpload, map[string]string{"tenant_id": spaceCtx.TenantID})
+	if err != nil {
+		return nil, errors.Database("Failed to start upload", err)
+	}
 
 	// Create document record
 	document, err := s.CreateDocument(ctx, req.DocumentCreateRequest, ownerID, spaceCtx, fileInfo)
 	if err != nil {
+		s.abortSaga(ctx, saga, err)
+		return nil, err
+	}
+	if err := saga.Record(ctx, sagaStepDocumentRecord, map[string]string{"document_id": document.ID}); err != nil {
+		s.abortSaga(ctx, saga, err)
+		return nil, errors.Database("Failed to record upload", err)
+	}
+
+	// Infected files are rejected before they are stored; aborting the
+	// saga removes the record
+	if err := s.scanUpload(ctx, spaceCtx, ownerID, fileInfo, req.FileData); err != nil {
+		s.abortSaga(ctx, saga, err)
 		return nil, err
 	}
 
@@ -210,18 +433,22 @@ func (s *DocumentService) UploadDocument(ctx context.Context, req models.Documen
 		zap.String("mime_type", document.MimeType),
 		zap.Int("file_size", len(req.FileData)))
 	
+	// Recorded first, so a file stored before a crash is deleted too
+	if err := saga.Record(ctx, sagaStepStoredFile, map[string]string{"storage_key": storageKey}); err != nil {
+		s.abortSaga(ctx, saga, err)
+		return nil, errors.Database("Failed to record upload", err)
+	}
+
 	s.logger.Info("=== CALLING STORAGE SERVICE ===")
 	storagePath, err := s.storageService.UploadFileToTenantBucket(ctx, spaceCtx.TenantID, storageKey, req.FileData, document.MimeType)
+	storedAt := time.Now()
 	s.logger.Info("=== STORAGE SERVICE CALL COMPLETED ===", zap.Bool("has_error", err != nil))
 	if err != nil {
 		s.logger.Error("Failed to upload file to storage",
 			zap.String("document_id", document.ID),
 			zap.Error(err))
 
-		// Update document status to failed
-		if statusErr := s.updateDocumentStatus(ctx, document.ID, "failed", nil, "File upload failed"); statusErr != nil {
-			s.logger.Error("Failed to update document status", zap.Error(statusErr))
-		}
+		s.abortSaga(ctx, saga, err)
 		return nil, errors.ExternalService("Failed to upload file", err)
 	}
 
@@ -248,37 +475,149 @@ func (s *DocumentService) UploadDocument(ctx context.Context, req models.Documen
 	}
 
 	// Submit for processing if processing service is available
-	if s.processingService != nil {
+	if err := s.submitForProcessing(ctx, saga, document, spaceCtx, map[string]interface{}{"file_data": req.FileData}, storedAt); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Document uploaded successfully",
+		zap.String("document_id", document.ID),
+		zap.String("storage_path", storagePath),
+	)
+
+	return document, nil
+}
+
+// applyProcessingSettings returns the processing provider of the
+// document's space and applies it and the space's OCR settings to the
+// document with recordProcessingSettings. It returns nil when no provider
+// is configured.
+func (s *DocumentService) applyProcessingSettings(ctx context.Context, document *models.Document, spaceCtx *models.SpaceContext, config map[string]interface{}) ProcessingProvider {
+	settings := s.spaceSettings(ctx, spaceCtx.SpaceID)
+	provider := s.spaceProcessingProvider(settings, spaceCtx.SpaceID)
+	if provider == nil {
+		return nil
+	}
+	s.recordProcessingSettings(ctx, document, spaceCtx, config, provider, models.SpaceOCRSettings(settings))
+	return provider
+}
+
+// recordProcessingSettings adds OCR settings and the priority tier to a
+// document's processing config and records them and its provider on the
+// document, which shows how its text was extracted. A document without a
+// tier takes the default. A failure to record them is logged, as the
+// document is processed regardless.
+func (s *DocumentService) recordProcessingSettings(ctx context.Context, document *models.Document, spaceCtx *models.SpaceContext, config map[string]interface{}, provider ProcessingProvider, ocr *models.OCRSettings) {
+	// Without a requested tier there is nothing to refuse
+	document.ProcessingPriority, _ = s.processingPriority(spaceCtx, "", document.ProcessingPriority)
+	config["ocr"] = ocr
+	config[ProcessingPriorityKey] = document.ProcessingPriority
+	document.OCRSettings = ocr
+	document.ProcessingProvider = provider.Name()
+
+	ocrJSON, err := json.Marshal(ocr)
+	if err == nil {
+		_, err = s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "document.processing_settings"), `
+			MATCH (d:Document {id: $document_id, tenant_id: $tenant_id})
+			SET d.ocr_settings = $ocr_settings,
+			    d.processing_provider = $processing_provider,
+			    d.processing_priority = $processing_priority
+		`, map[string]interface{}{
+			"document_id":         document.ID,
+			"tenant_id":           spaceCtx.TenantID,
+			"ocr_settings":        string(ocrJSON),
+			"processing_provider": document.ProcessingProvider,
+			"processing_priority": document.ProcessingPriority,
+		})
+	}
+	if err != nil {
+		s.logger.Warn("Failed to record document processing settings", zap.String("document_id", document.ID), zap.Error(err))
+	}
+}
+
+// spaceSettings returns the settings of a space, nil when they cannot be
+// read so the defaults apply
+func (s *DocumentService) spaceSettings(ctx context.Context, spaceID string) map[string]interface{} {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "space.processing_settings"), `
+		MATCH (sp:Space {id: $space_id})
+		RETURN sp.settings AS settings
+	`, map[string]interface{}{"space_id": spaceID})
+	if err != nil {
+		s.logger.Warn("Failed to read space settings, using defaults", zap.String("space_id", spaceID), zap.Error(err))
+		return nil
+	}
+	var settings map[string]interface{}
+	if len(result.Records) > 0 {
+		if encoded := recordString(result.Records[0], "settings"); encoded != "" {
+			_ = json.Unmarshal([]byte(encoded), &settings)
+		}
+	}
+	return settings
+}
+
+// spaceProcessingProvider returns the provider a space's settings choose.
+// A provider that is no longer registered falls back to the default, so
+// the space's uploads keep working.
+func (s *DocumentService) spaceProcessingProvider(settings map[string]interface{}, spaceID string) ProcessingProvider {
+	name := models.SpaceProcessingSettings(settings).Provider
+	if provider, ok := s.processors.Get(name); ok {
+		return provider
+	}
+	if name != "" {
+		s.logger.Warn("Space processing provider is not registered, using the default",
+			zap.String("space_id", spaceID),
+			zap.String("provider", name))
+	}
+	provider, _ := s.processors.Get("")
+	return provider
+}
+
+// processingConfigured reports whether documents can be processed
+func (s *DocumentService) processingConfigured() bool {
+	return s.processors.Default() != ""
+}
+
+// documentProcessingProvider returns the provider that processed a
+// document's current file, nil when it is not registered. Documents
+// processed before providers were recorded were processed by AudiModal.
+func (s *DocumentService) documentProcessingProvider(document *models.Document) ProcessingProvider {
+	name := document.ProcessingProvider
+	if name == "" {
+		name = AudiModalProviderName
+	}
+	provider, _ := s.processors.Get(name)
+	return provider
+}
+
+// submitForProcessing submits a stored document for processing, the last
+// step of saga. file gives the processing service the file, as "file_data"
+// holding its bytes or "file_reader" streaming it. When the document cannot
+// be submitted the saga is aborted, removing its file and record.
+func (s *DocumentService) submitForProcessing(ctx context.Context, saga *Saga, document *models.Document, spaceCtx *models.SpaceContext, file map[string]interface{}, storedAt time.Time) error {
+	if s.processingConfigured() {
 		processingConfig := map[string]interface{}{
 			"extract_text":     true,
 			"extract_metadata": true,
-			"file_data":        req.FileData,
 			"filename":         document.OriginalName,
 			"mime_type":        document.MimeType,
 		}
+		for key, value := range file {
+			processingConfig[key] = value
+		}
+		provider := s.applyProcessingSettings(ctx, document, spaceCtx, processingConfig)
 
-		job, err := s.processingService.SubmitProcessingJob(ctx, spaceCtx.TenantID, document.ID, "extract", processingConfig)
+		submitStartedAt := time.Now()
+		job, err := provider.ProcessFile(ctx, spaceCtx.TenantID, document.ID, "extract", processingConfig)
+		submittedAt := time.Now()
 		if err != nil {
+			s.countProcessingJob(spaceCtx.TenantID, "submit_failed")
 			s.logger.Error("Failed to submit processing job - cleaning up document",
 				zap.String("document_id", document.ID),
 				zap.Error(err))
+			s.abortSaga(ctx, saga, err)
 			
-			// Clean up: delete the uploaded file from storage
-			if deleteErr := s.storageService.DeleteFileFromTenantBucket(ctx, spaceCtx.TenantID, keyPath); deleteErr != nil {
-				s.logger.Error("Failed to clean up file after processing failure",
-					zap.String("key", keyPath),
-					zap.Error(deleteErr))
-			}
-			
-			// Clean up: delete the document record from database
-			if deleteErr := s.deleteDocumentRecord(ctx, document.ID); deleteErr != nil {
-				s.logger.Error("Failed to clean up document record after processing failure",
-					zap.String("document_id", document.ID),
-					zap.Error(deleteErr))
-			}
-			
-			return nil, errors.ServiceUnavailable("Document processing service is currently unavailable. Please try again later.")
+			return errors.ServiceUnavailable("Document processing service is currently unavailable. Please try again later.")
 		} else {
+			s.countProcessingJob(spaceCtx.TenantID, "submitted")
 			document.ProcessingJobID = job.ID
 			
 			// Check if job completed immediately (AudiModal case)
@@ -319,65 +658,53 @@ func (s *DocumentService) UploadDocument(ctx context.Context, req models.Documen
 				document.Status = "processing"
 			}
 			
-			// Store AudiModal file ID as processing_job_id for webhook integration
-			audiModalFileID := job.ID // default to job ID
-			if job.Config != nil {
-				if fileID, ok := job.Config["audimodal_file_id"].(string); ok && fileID != "" {
-					audiModalFileID = fileID
-					document.ProcessingJobID = fileID // Update in memory
-					s.logger.Info("Using AudiModal file ID as processing_job_id",
-						zap.String("document_id", document.ID),
-						zap.String("audimodal_file_id", fileID),
-						zap.String("job_id", job.ID))
-				}
+			// Store the provider's file ID as processing_job_id for webhook integration
+			fileID := processingFileID(job)
+			document.ProcessingJobID = fileID // Update in memory
+			
+			// A crash from here on deletes the provider's file as well
+			if recordErr := saga.Record(ctx, sagaStepProcessingFile, map[string]string{
+				"processing_provider": provider.Name(),
+				"processing_file_id":  fileID,
+			}); recordErr != nil {
+				s.logger.Warn("Failed to record processing job of upload", zap.String("document_id", document.ID), zap.Error(recordErr))
 			}
 			
-			if statusErr := s.updateDocumentStatusWithJobID(ctx, document.ID, document.Status, job.Result, "", audiModalFileID); statusErr != nil {
+			if statusErr := s.updateDocumentStatusWithJobID(ctx, document.ID, document.Status, job.Result, "", fileID); statusErr != nil {
 				s.logger.Error("Failed to update document status", zap.Error(statusErr))
 			}
+			s.recordPipelineTimes(ctx, document.ID, spaceCtx.TenantID, map[string]time.Time{
+				pipelineStoredAt:        storedAt,
+				pipelineSubmitStartedAt: submitStartedAt,
+				pipelineSubmittedAt:     submittedAt,
+			})
+			saga.Finish(ctx)
 		}
 	} else {
 		// No processing service available - fail the upload
 		s.logger.Error("No processing service configured - cleaning up document",
 			zap.String("document_id", document.ID))
+		s.abortSaga(ctx, saga, fmt.Errorf("no processing service configured"))
 		
-		// Clean up: delete the uploaded file from storage
-		if deleteErr := s.storageService.DeleteFileFromTenantBucket(ctx, spaceCtx.TenantID, keyPath); deleteErr != nil {
-			s.logger.Error("Failed to clean up file after processing service unavailable",
-				zap.String("key", keyPath),
-				zap.Error(deleteErr))
-		}
-		
-		// Clean up: delete the document record from database
-		if deleteErr := s.deleteDocumentRecord(ctx, document.ID); deleteErr != nil {
-			s.logger.Error("Failed to clean up document record after processing service unavailable",
-				zap.String("document_id", document.ID),
-				zap.Error(deleteErr))
-		}
-		
-		return nil, errors.ServiceUnavailable("Document processing service is not configured. Please contact support.")
+		return errors.ServiceUnavailable("Document processing service is not configured. Please contact support.")
 	}
 
-	s.logger.Info("Document uploaded successfully",
-		zap.String("document_id", document.ID),
-		zap.String("storage_path", storagePath),
-	)
-
-	return document, nil
+	return nil
 }
 
 // GetDocumentByID retrieves a document by ID
 func (s *DocumentService) GetDocumentByID(ctx context.Context, documentID string, userID string, spaceCtx *models.SpaceContext) (*models.Document, error) {
 	query := `
 		MATCH (d:Document {id: $document_id, tenant_id: $tenant_id})
+		WHERE ` + database.SoftDeleteFilter(ctx, "d") + `
 		OPTIONAL MATCH (d)-[:BELONGS_TO]->(n:Notebook)
 		OPTIONAL MATCH (d)-[:OWNED_BY]->(owner:User)
 		RETURN d.id, d.name, d.description, d.type, d.status, d.original_name,
 		       d.mime_type, d.size_bytes, d.checksum, d.storage_path, d.storage_bucket,
 		       d.extracted_text, d.processing_result, d.processing_time, d.confidence_score, d.metadata, d.notebook_id, d.owner_id,
 		       d.space_type, d.space_id, d.tenant_id,
-		       d.tags, d.search_text, d.processing_job_id, d.processed_at,
-		       d.created_at, d.updated_at,
+		       d.tags, d.search_text, d.processing_job_id, d.processed_at, d.restricted,
+		       d.moderation_status, d.ocr_settings, d.processing_provider, d.processing_priority, d.created_at, d.updated_at,
 		       n.name as notebook_name, n.visibility as notebook_visibility,
 		       owner.username, owner.full_name, owner.avatar_url
 	`
@@ -396,7 +723,7 @@ func (s *DocumentService) GetDocumentByID(ctx context.Context, documentID string
 	if len(result.Records) == 0 {
 		return nil, errors.NotFoundWithDetails("Document not found", map[string]interface{}{
 			"document_id": documentID,
-		})
+		}).WithErrorCode(errors.CodeDocumentNotFound)
 	}
 
 	document, err := s.recordToDocument(result.Records[0])
@@ -416,10 +743,29 @@ func (s *DocumentService) GetDocumentByID(ctx context.Context, documentID string
 	if !spaceCtx.CanRead() {
 		return nil, errors.Forbidden("Insufficient permissions to read document")
 	}
+	if document.Restricted && document.OwnerID != userID && !spaceCtx.CanManage() {
+		return nil, errors.ForbiddenWithDetails("Document is restricted", map[string]interface{}{
+			"document_id": documentID,
+		})
+	}
+	if document.ModerationStatus.Held() && document.OwnerID != userID && !spaceCtx.CanManage() {
+		return nil, errors.ForbiddenWithDetails("Document is held for moderation", map[string]interface{}{
+			"document_id":       documentID,
+			"moderation_status": document.ModerationStatus,
+		}).WithErrorCode(errors.CodeDocumentHeldForModeration)
+	}
 
 	return document, nil
 }
 
+// restrictedDocumentFilter returns the predicate hiding restricted
+// documents, and documents held for moderation, from users other than
+// their owner and the space's managers. The query passes $user_id and
+// $can_manage_space.
+func restrictedDocumentFilter(alias string) string {
+	return fmt.Sprintf("((coalesce(%[1]s.restricted, false) = false AND NOT coalesce(%[1]s.moderation_status, '') IN ['pending', 'blocked']) OR %[1]s.owner_id = $user_id OR $can_manage_space)", alias)
+}
+
 // UpdateDocument updates a document
 func (s *DocumentService) UpdateDocument(ctx context.Context, documentID string, req models.DocumentUpdateRequest, userID string, spaceCtx *models.SpaceContext) (*models.Document, error) {
 	// Get current document and check permissions
@@ -429,8 +775,11 @@ func (s *DocumentService) UpdateDocument(ctx context.Context, documentID string,
 	}
 
 	// Check if user can write to document
-	if !s.canUserWriteDocument(ctx, document, userID) {
-		return nil, errors.Forbidden("Write access denied to document")
+	if err := s.authorizeDocument(ctx, document, userID, models.ActionDocumentUpdate); err != nil {
+		return nil, err
+	}
+	if err := s.legalHolds.CheckDocument(ctx, document); err != nil {
+		return nil, err
 	}
 
 	// Update document fields
@@ -467,6 +816,9 @@ func (s *DocumentService) UpdateDocument(ctx context.Context, documentID string,
 		return nil, errors.Database("Failed to update document", err)
 	}
 
+	publishDomainEvent(ctx, s.events, s.logger,
+		NewDocumentEvent(EventDocumentUpdated, documentID, userID, documentEventData(document)))
+
 	s.logger.Info("Document updated successfully",
 		zap.String("document_id", documentID),
 		zap.String("name", document.Name),
@@ -475,7 +827,9 @@ func (s *DocumentService) UpdateDocument(ctx context.Context, documentID string,
 	return document, nil
 }
 
-// DeleteDocument deletes a document (soft delete)
+// DeleteDocument moves a document to the trash. It is hidden from reads
+// and counts, and is restored with TrashService.Restore or purged with its
+// files once the trash retention has passed.
 func (s *DocumentService) DeleteDocument(ctx context.Context, documentID string, userID string, spaceCtx *models.SpaceContext) error {
 	// Get document and check permissions
 	document, err := s.GetDocumentByID(ctx, documentID, userID, spaceCtx)
@@ -498,22 +852,33 @@ func (s *DocumentService) DeleteDocument(ctx context.Context, documentID string,
 		)
 		return errors.Forbidden("You don't have permission to delete this document")
 	}
+	if err := s.legalHolds.CheckDocument(ctx, document); err != nil {
+		return err
+	}
 
-	// Hard delete: update notebook counts and fully remove document node
+	// Soft delete: keep the status to restore, and take the document out
+	// of its notebook's counts
 	query := `
 		MATCH (d:Document {id: $document_id, tenant_id: $tenant_id})
+		WHERE ` + database.NotDeleted("d") + `
 		OPTIONAL MATCH (d)-[:BELONGS_TO]->(n:Notebook {tenant_id: $tenant_id})
-		WITH d, n, d.size_bytes AS doc_size
 		SET n.document_count = COALESCE(n.document_count, 0) - 1,
-		    n.total_size_bytes = COALESCE(n.total_size_bytes, 0) - COALESCE(doc_size, 0),
+		    n.total_size_bytes = COALESCE(n.total_size_bytes, 0) - COALESCE(d.size_bytes, 0),
 		    n.updated_at = datetime()
-		WITH d
-		DETACH DELETE d
+		SET d.previous_status = d.status,
+		    d.status = $status,
+		    d.deleted_at = datetime($deleted_at),
+		    d.deleted_by = $deleted_by,
+		    d.updated_at = datetime($deleted_at)
 	`
 
+	now := time.Now().Format(time.RFC3339)
 	params := map[string]interface{}{
 		"document_id": documentID,
 		"tenant_id":   spaceCtx.TenantID,
+		"status":      database.StatusDeleted,
+		"deleted_at":  now,
+		"deleted_by":  userID,
 	}
 
 	_, err = s.neo4j.ExecuteQueryWithLogging(ctx, query, params)
@@ -522,48 +887,12 @@ func (s *DocumentService) DeleteDocument(ctx context.Context, documentID string,
 		return errors.Database("Failed to delete document", err)
 	}
 
-	// Cancel processing job if active
-	if document.ProcessingJobID != "" && s.processingService != nil {
-		// Try to delete the file from AudiModal
-		// The file ID can be stored in job.Config["audimodal_file_id"] or
-		// the processing_job_id itself may be the audimodal file ID
-		var audiModalFileID string
-
-		// First try to get the job to retrieve the AudiModal file ID from config
-		job, jobErr := s.processingService.GetProcessingJob(ctx, document.ProcessingJobID)
-		if jobErr == nil && job != nil && job.Config != nil {
-			if fileID, ok := job.Config["audimodal_file_id"].(string); ok && fileID != "" {
-				audiModalFileID = fileID
-			}
-		}
+	publishDomainEvent(ctx, s.events, s.logger,
+		NewDocumentEvent(EventDocumentDeleted, documentID, userID, map[string]interface{}{"notebook_id": document.NotebookID}))
 
-		// Fallback: use processing_job_id as the audimodal file ID
-		// This handles cases where the processing_job_id is the audimodal file UUID
-		if audiModalFileID == "" {
-			audiModalFileID = document.ProcessingJobID
-			s.logger.Info("Using processing_job_id as audimodal file ID",
-				zap.String("document_id", documentID),
-				zap.String("audimodal_file_id", audiModalFileID))
-		}
-
-		// Delete the file from AudiModal
-		if audiModalService, ok := s.processingService.(*AudiModalService); ok {
-			s.logger.Info("Deleting file from AudiModal",
-				zap.String("document_id", documentID),
-				zap.String("tenant_id", spaceCtx.TenantID),
-				zap.String("audimodal_file_id", audiModalFileID))
-
-			if deleteErr := audiModalService.DeleteFile(ctx, spaceCtx.TenantID, audiModalFileID); deleteErr != nil {
-				s.logger.Error("Failed to delete file from AudiModal",
-					zap.String("document_id", documentID),
-					zap.String("tenant_id", spaceCtx.TenantID),
-					zap.String("audimodal_file_id", audiModalFileID),
-					zap.Error(deleteErr))
-			}
-		}
-
-		// Cancel the processing job
-		if err := s.processingService.CancelProcessingJob(ctx, document.ProcessingJobID); err != nil {
+	// Cancel processing job if active
+	if provider := s.documentProcessingProvider(document); document.ProcessingJobID != "" && provider != nil {
+		if err := provider.CancelJob(ctx, document.ProcessingJobID); err != nil {
 			s.logger.Warn("Failed to cancel processing job",
 				zap.String("document_id", documentID),
 				zap.String("job_id", document.ProcessingJobID),
@@ -571,57 +900,111 @@ func (s *DocumentService) DeleteDocument(ctx context.Context, documentID string,
 		}
 	}
 
-	// Delete the actual file from storage if it exists and storage service is available
-	if s.storageService != nil && document.StoragePath != "" {
-		// Extract the key from storage path (supports both "bucket:key" and legacy "key" formats)
-		var key string
-		if strings.Contains(document.StoragePath, ":") {
-			// New format: "bucket:key"
-			parts := strings.SplitN(document.StoragePath, ":", 2)
-			if len(parts) == 2 {
-				key = parts[1]
-			} else {
-				s.logger.Error("Invalid storage path format during deletion", 
-					zap.String("document_id", documentID),
-					zap.String("storage_path", document.StoragePath))
-				key = document.StoragePath // fallback to full path
-			}
-		} else {
-			// Legacy format: just the key
-			key = document.StoragePath
-		}
+	s.logger.Info("Document moved to trash",
+		zap.String("document_id", documentID),
+		zap.String("name", document.Name),
+	)
 
-		s.logger.Info("Deleting file from storage",
-			zap.String("document_id", documentID),
-			zap.String("tenant_id", spaceCtx.TenantID),
-			zap.String("key", key))
+	return nil
+}
 
-		if err := s.storageService.DeleteFileFromTenantBucket(ctx, spaceCtx.TenantID, key); err != nil {
-			// Log the error but don't fail the entire delete operation since the database record is already marked as deleted
-			s.logger.Error("Failed to delete file from storage (database record already deleted)",
-				zap.String("document_id", documentID),
-				zap.String("tenant_id", spaceCtx.TenantID),
-				zap.String("key", key),
-				zap.Error(err))
-		} else {
-			s.logger.Info("File deleted from storage successfully",
-				zap.String("document_id", documentID),
-				zap.String("key", key))
+// purgeDocument permanently deletes a document and its versions. Its
+// copies at its processing provider, in the vector store and in storage
+// are removed by the deletion orchestrator, recorded in the same query
+// that deletes the document so none is forgotten. Notebook counts are left
+// alone: a trashed document is no longer counted.
+func (s *DocumentService) purgeDocument(ctx context.Context, document *models.Document) error {
+	fileID := s.documentProcessingFileID(ctx, document)
+	storageKey := document.StoragePath
+	if parts := strings.SplitN(storageKey, ":", 2); len(parts) == 2 {
+		storageKey = parts[1]
+	}
+
+	params := map[string]interface{}{
+		"document_id": document.ID,
+		"tenant_id":   document.TenantID,
+	}
+
+	var deletion *models.DocumentDeletion
+	recordDeletion := ""
+	if s.deletions != nil {
+		var deletionParams map[string]interface{}
+		deletion, deletionParams = s.deletions.plan(document, fileID, storageKey)
+		for key, value := range deletionParams {
+			params[key] = value
 		}
-	} else if document.StoragePath != "" {
-		s.logger.Warn("Storage service not available, cannot delete file from storage",
-			zap.String("document_id", documentID),
+		recordDeletion = createDocumentDeletionClause
+	}
+
+	query := `
+		MATCH (d:Document {id: $document_id, tenant_id: $tenant_id})
+		OPTIONAL MATCH (d)-[:HAS_VERSION]->(v:DocumentVersion)
+		WITH d, collect(v) AS versions, collect(v.storage_path) AS version_paths
+		FOREACH (version IN versions | DETACH DELETE version)
+		DETACH DELETE d
+	` + recordDeletion
+
+	if _, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "document.purge"), query, params); err != nil {
+		s.logger.Error("Failed to purge document", zap.String("document_id", document.ID), zap.Error(err))
+		return errors.Database("Failed to purge document", err)
+	}
+
+	if deletion != nil {
+		s.deletions.Run(ctx, deletion.ID)
+	} else {
+		s.logger.Warn("No deletion orchestrator; the document's files are left in place",
+			zap.String("document_id", document.ID),
 			zap.String("storage_path", document.StoragePath))
 	}
 
-	s.logger.Info("Document deleted successfully",
-		zap.String("document_id", documentID),
+	s.logger.Info("Document purged",
+		zap.String("document_id", document.ID),
 		zap.String("name", document.Name),
 	)
-
 	return nil
 }
 
+// documentProcessingFileID returns the processing provider's file of a
+// document's current version, empty when it was never submitted
+func (s *DocumentService) documentProcessingFileID(ctx context.Context, document *models.Document) string {
+	if document.ProcessingJobID == "" {
+		return ""
+	}
+	// The file ID can be stored in the job's config, or the processing
+	// job ID itself may be the provider's file ID
+	if provider := s.documentProcessingProvider(document); provider != nil {
+		job, err := provider.GetStatus(ctx, document.ProcessingJobID)
+		if err == nil && job != nil {
+			return processingFileID(job)
+		}
+	}
+	return document.ProcessingJobID
+}
+
+// GetExtractedText returns the text the processing provider extracted from
+// a document's current file
+func (s *DocumentService) GetExtractedText(ctx context.Context, document *models.Document) (string, error) {
+	provider := s.documentProcessingProvider(document)
+	if provider == nil {
+		return "", errors.ServiceUnavailable("Text extraction service not available")
+	}
+	return provider.GetContent(ctx, document.TenantID, documentFileID(document))
+}
+
+// documentFileID returns the processing provider's file ID of a document:
+// the one in its processing result, or the processing job ID
+func documentFileID(document *models.Document) string {
+	for _, key := range []string{ProcessingFileIDKey, "audimodal_file_id"} {
+		if id, ok := document.ProcessingResult[key].(string); ok && id != "" {
+			return id
+		}
+	}
+	if document.ProcessingJobID != "" {
+		return document.ProcessingJobID
+	}
+	return document.ID
+}
+
 // ListDocumentsByNotebook lists documents in a notebook
 func (s *DocumentService) ListDocumentsByNotebook(ctx context.Context, notebookID string, userID string, spaceCtx *models.SpaceContext, limit, offset int) (*models.DocumentListResponse, error) {
 	// Check if user has read permissions in the space
@@ -639,25 +1022,19 @@ func (s *DocumentService) ListDocumentsByNotebook(ctx context.Context, notebookI
 		return nil, errors.ForbiddenWithDetails("Notebook not accessible in this space", map[string]interface{}{
 			"notebook_id": notebookID,
 			"space_id":    spaceCtx.SpaceID,
-		})
+		}).WithErrorCode(errors.CodeNotebookNotAccessible)
 	}
 
-	// Set defaults
-	if limit <= 0 || limit > 100 {
-		limit = 20
-	}
-	if offset < 0 {
-		offset = 0
-	}
+	limit, offset = pagination.Clamp(limit, offset)
 
 	query := `
 		MATCH (d:Document {notebook_id: $notebook_id, tenant_id: $tenant_id})
-		WHERE d.status <> 'deleted'
+		WHERE ` + database.SoftDeleteFilter(ctx, "d") + ` AND ` + restrictedDocumentFilter("d") + `
 		OPTIONAL MATCH (d)-[:OWNED_BY]->(owner:User)
 		RETURN d.id, d.name, d.description, d.type, d.status, d.original_name,
 		       d.mime_type, d.size_bytes, d.notebook_id, d.owner_id, 
-		       d.space_type, d.space_id, d.tenant_id, d.tags,
-		       d.extracted_text, d.processing_time, d.confidence_score,
+		       d.space_type, d.space_id, d.tenant_id, d.tags, d.restricted,
+		       d.moderation_status, d.extracted_text, d.processing_time, d.confidence_score,
 		       d.processed_at, d.created_at, d.updated_at,
 		       owner.username, owner.full_name, owner.avatar_url
 		ORDER BY d.created_at DESC
@@ -666,10 +1043,12 @@ func (s *DocumentService) ListDocumentsByNotebook(ctx context.Context, notebookI
 	`
 
 	params := map[string]interface{}{
-		"notebook_id": notebookID,
-		"tenant_id":   spaceCtx.TenantID,
-		"limit":       limit + 1, // Get one extra to check if there are more
-		"offset":      offset,
+		"notebook_id":      notebookID,
+		"tenant_id":        spaceCtx.TenantID,
+		"user_id":          userID,
+		"can_manage_space": spaceCtx.CanManage(),
+		"limit":            limit + 1, // Get one extra to check if there are more
+		"offset":           offset,
 	}
 
 	result, err := s.neo4j.ExecuteQueryWithLogging(ctx, query, params)
@@ -679,14 +1058,9 @@ func (s *DocumentService) ListDocumentsByNotebook(ctx context.Context, notebookI
 	}
 
 	documents := make([]*models.DocumentResponse, 0, len(result.Records))
-	hasMore := false
-
-	for i, record := range result.Records {
-		if i >= limit {
-			hasMore = true
-			break
-		}
+	records, hasMore := pagination.Trim(result.Records, limit)
 
+	for _, record := range records {
 		document, err := s.recordToDocumentResponse(record)
 		if err != nil {
 			s.logger.Error("Failed to parse document record", zap.Error(err))
@@ -699,34 +1073,258 @@ func (s *DocumentService) ListDocumentsByNotebook(ctx context.Context, notebookI
 	// Get total count
 	countQuery := `
 		MATCH (d:Document {notebook_id: $notebook_id, tenant_id: $tenant_id})
-		WHERE d.status <> 'deleted'
+		WHERE ` + database.SoftDeleteFilter(ctx, "d") + ` AND ` + restrictedDocumentFilter("d") + `
 		RETURN count(d) as total
 	`
 
-	countResult, err := s.neo4j.ExecuteQueryWithLogging(ctx, countQuery, map[string]interface{}{
-		"notebook_id": notebookID,
-		"tenant_id":   spaceCtx.TenantID,
+	countResult, err := s.neo4j.ExecuteQueryWithLogging(ctx, countQuery, map[string]interface{}{
+		"notebook_id":      notebookID,
+		"tenant_id":        spaceCtx.TenantID,
+		"user_id":          userID,
+		"can_manage_space": spaceCtx.CanManage(),
+	})
+	if err != nil {
+		s.logger.Error("Failed to get document count", zap.Error(err))
+		return nil, errors.Database("Failed to get document count", err)
+	}
+
+	total := 0
+	if len(countResult.Records) > 0 {
+		if totalValue, found := countResult.Records[0].Get("total"); found {
+			if totalInt, ok := totalValue.(int64); ok {
+				total = int(totalInt)
+			}
+		}
+	}
+
+	return &models.DocumentListResponse{
+		Documents: documents,
+		Total:     total,
+		Limit:     limit,
+		Offset:    offset,
+		HasMore:   hasMore,
+	}, nil
+}
+
+// ListDocumentsByNotebooks returns one page of documents for each of several
+// notebooks, newest first, in two queries. It does not check access to the
+// notebooks: callers pass IDs of notebooks already read in the space. Only
+// documents of the space are returned.
+func (s *DocumentService) ListDocumentsByNotebooks(ctx context.Context, notebookIDs []string, spaceCtx *models.SpaceContext, limit, offset int) (map[string]*models.DocumentListResponse, error) {
+	if !spaceCtx.CanRead() {
+		return nil, errors.Forbidden("Insufficient permissions to list documents")
+	}
+
+	limit, offset = pagination.Clamp(limit, offset)
+
+	query := `
+		UNWIND $notebook_ids AS notebook_id
+		CALL {
+			WITH notebook_id
+			MATCH (d:Document {notebook_id: notebook_id, tenant_id: $tenant_id})
+			WHERE d.space_id = $space_id AND ` + database.SoftDeleteFilter(ctx, "d") + `
+			RETURN d
+			ORDER BY d.created_at DESC
+			SKIP $offset
+			LIMIT $limit
+		}
+		OPTIONAL MATCH (d)-[:OWNED_BY]->(owner:User)
+		RETURN notebook_id, d.id, d.name, d.description, d.type, d.status, d.original_name,
+		       d.mime_type, d.size_bytes, d.notebook_id, d.owner_id,
+		       d.space_type, d.space_id, d.tenant_id, d.tags,
+		       d.processing_time, d.confidence_score,
+		       d.processed_at, d.created_at, d.updated_at,
+		       owner.username, owner.full_name, owner.avatar_url
+		ORDER BY notebook_id, d.created_at DESC
+	`
+
+	params := map[string]interface{}{
+		"notebook_ids": notebookIDs,
+		"tenant_id":    spaceCtx.TenantID,
+		"space_id":     spaceCtx.SpaceID,
+		"limit":        limit + 1, // One extra per notebook to check if there are more
+		"offset":       offset,
+	}
+
+	result, err := s.neo4j.ExecuteQueryWithLogging(ctx, query, params)
+	if err != nil {
+		s.logger.Error("Failed to list documents by notebooks", zap.Int("notebooks", len(notebookIDs)), zap.Error(err))
+		return nil, errors.Database("Failed to list documents", err)
+	}
+
+	pages := make(map[string]*models.DocumentListResponse, len(notebookIDs))
+	for _, notebookID := range notebookIDs {
+		pages[notebookID] = &models.DocumentListResponse{
+			Documents: []*models.DocumentResponse{},
+			Limit:     limit,
+			Offset:    offset,
+		}
+	}
+	for _, record := range result.Records {
+		notebookID, _ := record.Values[0].(string)
+		page, ok := pages[notebookID]
+		if !ok {
+			continue
+		}
+		document, err := s.recordToDocumentResponse(record)
+		if err != nil {
+			s.logger.Error("Failed to parse document record", zap.Error(err))
+			continue
+		}
+		page.Documents = append(page.Documents, document)
+	}
+	for _, page := range pages {
+		page.Documents, page.HasMore = pagination.Trim(page.Documents, limit)
+	}
+
+	countQuery := `
+		MATCH (d:Document)
+		WHERE d.notebook_id IN $notebook_ids
+			AND d.tenant_id = $tenant_id
+			AND d.space_id = $space_id
+			AND ` + database.SoftDeleteFilter(ctx, "d") + `
+		RETURN d.notebook_id AS notebook_id, count(d) AS total
+	`
+
+	countResult, err := s.neo4j.ExecuteQueryWithLogging(ctx, countQuery, map[string]interface{}{
+		"notebook_ids": notebookIDs,
+		"tenant_id":    spaceCtx.TenantID,
+		"space_id":     spaceCtx.SpaceID,
+	})
+	if err != nil {
+		s.logger.Error("Failed to count documents by notebooks", zap.Error(err))
+		return nil, errors.Database("Failed to get document count", err)
+	}
+	for _, record := range countResult.Records {
+		notebookID, _ := record.Values[0].(string)
+		total, _ := record.Values[1].(int64)
+		if page, ok := pages[notebookID]; ok {
+			page.Total = int(total)
+		}
+	}
+
+	return pages, nil
+}
+
+// ExportNotebookDocuments streams the metadata of every document in a
+// notebook to fn without loading the notebook's documents into memory.
+// Extracted text is left out to keep rows small. It returns the number of
+// documents exported; fn returning an error stops the export.
+func (s *DocumentService) ExportNotebookDocuments(ctx context.Context, notebookID string, userID string, spaceCtx *models.SpaceContext, fn func(*models.DocumentResponse) error) (int, error) {
+	if err := s.CheckNotebookExport(ctx, notebookID, userID, spaceCtx); err != nil {
+		return 0, err
+	}
+
+	query := `
+		MATCH (d:Document {notebook_id: $notebook_id, tenant_id: $tenant_id})
+		WHERE ` + database.SoftDeleteFilter(ctx, "d") + `
+		OPTIONAL MATCH (d)-[:OWNED_BY]->(owner:User)
+		RETURN d.id, d.name, d.description, d.type, d.status, d.original_name,
+		       d.mime_type, d.size_bytes, d.notebook_id, d.owner_id,
+		       d.space_type, d.space_id, d.tenant_id, d.tags,
+		       d.processing_time, d.confidence_score,
+		       d.processed_at, d.created_at, d.updated_at,
+		       owner.username, owner.full_name, owner.avatar_url
+		ORDER BY d.created_at
+	`
+
+	params := map[string]interface{}{
+		"notebook_id": notebookID,
+		"tenant_id":   spaceCtx.TenantID,
+	}
+
+	exported := 0
+	_, err := s.neo4j.StreamQuery(database.WithQueryName(ctx, "document.export_notebook"), query, params, func(record *neo4j.Record) error {
+		document, err := s.recordToDocumentResponse(record)
+		if err != nil {
+			s.logger.Error("Failed to parse document record", zap.Error(err))
+			return nil
+		}
+		if err := fn(document); err != nil {
+			return err
+		}
+		exported++
+		return nil
+	})
+	if err != nil {
+		return exported, errors.Database("Failed to export documents", err)
+	}
+
+	s.logger.Info("Exported notebook documents",
+		zap.String("notebook_id", notebookID),
+		zap.Int("count", exported),
+	)
+	return exported, nil
+}
+
+// CheckNotebookExport checks that the user may export the documents of a
+// notebook in the space
+func (s *DocumentService) CheckNotebookExport(ctx context.Context, notebookID string, userID string, spaceCtx *models.SpaceContext) error {
+	resource := models.Resource{Type: models.ResourceNotebook, ID: notebookID}
+	if err := s.authz.Authorize(ctx, spaceCtx.Subject(), models.ActionDocumentRead, resource); err != nil {
+		return err
+	}
+
+	notebook, err := s.notebookService.GetNotebookByID(ctx, notebookID, userID, spaceCtx)
+	if err != nil {
+		return err
+	}
+
+	if notebook.TenantID != spaceCtx.TenantID || notebook.SpaceID != spaceCtx.SpaceID {
+		return errors.ForbiddenWithDetails("Notebook not accessible in this space", map[string]interface{}{
+			"notebook_id": notebookID,
+			"space_id":    spaceCtx.SpaceID,
+		}).WithErrorCode(errors.CodeNotebookNotAccessible)
+	}
+	return nil
+}
+
+// notebookExportURLExpiry is how long the download URL of a stored export
+// stays valid
+const notebookExportURLExpiry = 24 * time.Hour
+
+// StoreNotebookExport exports the metadata of every document in a notebook
+// as newline-delimited JSON to object storage under exports/ and returns
+// a download URL. It reports the number of documents exported so far
+// through progress. Stored exports are not deleted by Aether; a bucket
+// lifecycle rule on the exports/ prefix should expire them.
+func (s *DocumentService) StoreNotebookExport(ctx context.Context, notebookID string, userID string, spaceCtx *models.SpaceContext, progress func(exported int)) (*models.NotebookExport, error) {
+	if s.storageService == nil {
+		return nil, errors.ServiceUnavailable("Storage is not available for exports")
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	written := 0
+	count, err := s.ExportNotebookDocuments(ctx, notebookID, userID, spaceCtx, func(document *models.DocumentResponse) error {
+		if err := encoder.Encode(document); err != nil {
+			return err
+		}
+		written++
+		progress(written)
+		return nil
 	})
 	if err != nil {
-		s.logger.Error("Failed to get document count", zap.Error(err))
-		return nil, errors.Database("Failed to get document count", err)
+		return nil, err
 	}
+	progress(count)
 
-	total := 0
-	if len(countResult.Records) > 0 {
-		if totalValue, found := countResult.Records[0].Get("total"); found {
-			if totalInt, ok := totalValue.(int64); ok {
-				total = int(totalInt)
-			}
-		}
+	now := time.Now().UTC()
+	key := fmt.Sprintf("exports/%s/notebook-%s-%s.ndjson", spaceCtx.TenantID, notebookID, now.Format("20060102T150405Z"))
+	if _, err := s.storageService.UploadFile(ctx, key, buf.Bytes(), "application/x-ndjson"); err != nil {
+		return nil, errors.ExternalService("Failed to store export", err)
+	}
+	url, err := s.storageService.GetFileURL(ctx, key, notebookExportURLExpiry)
+	if err != nil {
+		return nil, errors.ExternalService("Failed to sign export URL", err)
 	}
 
-	return &models.DocumentListResponse{
-		Documents: documents,
-		Total:     total,
-		Limit:     limit,
-		Offset:    offset,
-		HasMore:   hasMore,
+	return &models.NotebookExport{
+		NotebookID:  notebookID,
+		Documents:   count,
+		Bytes:       buf.Len(),
+		DownloadURL: url,
+		ExpiresAt:   now.Add(notebookExportURLExpiry),
 	}, nil
 }
 
@@ -747,7 +1345,7 @@ func (s *DocumentService) SearchDocuments(ctx context.Context, req models.Docume
 
 	// Build query conditions - filter by space
 	whereConditions := []string{
-		"d.status <> 'deleted'",
+		database.SoftDeleteFilter(ctx, "d"),
 		"d.tenant_id = $tenant_id",
 		"d.space_id = $space_id",
 	}
@@ -795,6 +1393,14 @@ func (s *DocumentService) SearchDocuments(ctx context.Context, req models.Docume
 		params["tags"] = req.Tags
 	}
 
+	filter, err := compileFilter(req.Filter, documentFilterFields, "d", params)
+	if err != nil {
+		return nil, err
+	}
+	if filter != "" {
+		whereConditions = append(whereConditions, filter)
+	}
+
 	whereClause := "WHERE " + fmt.Sprintf("(%s)", whereConditions[0])
 	for i := 1; i < len(whereConditions); i++ {
 		whereClause += " AND " + fmt.Sprintf("(%s)", whereConditions[i])
@@ -822,14 +1428,9 @@ func (s *DocumentService) SearchDocuments(ctx context.Context, req models.Docume
 	}
 
 	documents := make([]*models.DocumentResponse, 0, len(result.Records))
-	hasMore := false
-
-	for i, record := range result.Records {
-		if i >= req.Limit {
-			hasMore = true
-			break
-		}
+	records, hasMore := pagination.Trim(result.Records, req.Limit)
 
+	for _, record := range records {
 		document, err := s.recordToDocumentResponse(record)
 		if err != nil {
 			s.logger.Error("Failed to parse document record", zap.Error(err))
@@ -866,7 +1467,7 @@ func (s *DocumentService) UpdateProcessingResult(ctx context.Context, documentID
 	}
 	
 	if len(tenantResult.Records) == 0 {
-		return errors.NotFound("Document not found")
+		return errors.NotFound("Document not found").WithErrorCode(errors.CodeDocumentNotFound)
 	}
 	
 	tenantID := ""
@@ -877,15 +1478,28 @@ func (s *DocumentService) UpdateProcessingResult(ctx context.Context, documentID
 	return s.updateProcessingResultWithTenant(ctx, documentID, tenantID, status, result, errorMsg)
 }
 
+// UpdateProcessingResultForTenant updates the processing results of a
+// document of a tenant. Callers that know the tenant, such as the internal
+// gRPC API, use it so a document of another tenant is reported as not
+// found rather than updated.
+func (s *DocumentService) UpdateProcessingResultForTenant(ctx context.Context, documentID string, tenantID string, status string, result map[string]interface{}, errorMsg string) error {
+	if _, err := s.getDocumentByIDInternal(ctx, documentID, tenantID); err != nil {
+		return err
+	}
+	return s.updateProcessingResultWithTenant(ctx, documentID, tenantID, status, result, errorMsg)
+}
+
 // updateProcessingResultWithTenant is the internal version that includes tenant_id
 func (s *DocumentService) updateProcessingResultWithTenant(ctx context.Context, documentID string, tenantID string, status string, result map[string]interface{}, errorMsg string) error {
 	query := `
 		MATCH (d:Document {id: $document_id, tenant_id: $tenant_id})
+		WHERE ` + database.NotDeleted("d") + `
 		SET d.status = $status,
 		    d.processing_result = $result,
 		    d.extracted_text = $extracted_text,
 		    d.search_text = $search_text,
-		    d.processed_at = CASE WHEN $status = 'processed' THEN datetime($processed_at) ELSE d.processed_at END,
+		    d.processed_at = CASE WHEN $status = 'processed' THEN coalesce(d.processed_at, datetime($processed_at)) ELSE d.processed_at END,
+		    d.indexed_at = CASE WHEN $indexed THEN datetime($processed_at) ELSE d.indexed_at END,
 		    d.updated_at = datetime($updated_at)
 		RETURN d
 	`
@@ -905,11 +1519,20 @@ func (s *DocumentService) updateProcessingResultWithTenant(ctx context.Context,
 		}
 	}
 
+	storedText, encrypted, err := s.encryption.Seal(ctx, documentID, tenantID, extractedText)
+	if err != nil {
+		return err
+	}
+
 	searchText := ""
 	if extractedText != "" {
 		// Get current document to build search text
 		doc, err := s.getDocumentByIDInternal(ctx, documentID, tenantID)
-		if err == nil {
+		if err == nil && encrypted {
+			doc.ExtractedText, doc.TextEncrypted = extractedText, true
+			doc.RefreshSearchText()
+			searchText = doc.SearchText
+		} else if err == nil {
 			searchText = fmt.Sprintf("%s %s", doc.SearchText, extractedText)
 		}
 	}
@@ -917,6 +1540,9 @@ func (s *DocumentService) updateProcessingResultWithTenant(ctx context.Context,
 	// Serialize result map to JSON string for Neo4j storage
 	resultJSON := ""
 	if result != nil {
+		if encrypted {
+			result = withoutExtractedText(result)
+		}
 		if jsonBytes, err := json.Marshal(result); err == nil {
 			resultJSON = string(jsonBytes)
 		}
@@ -927,13 +1553,16 @@ func (s *DocumentService) updateProcessingResultWithTenant(ctx context.Context,
 		"tenant_id":      tenantID,
 		"status":         status,
 		"result":         resultJSON,
-		"extracted_text": extractedText,
+		"extracted_text": storedText,
 		"search_text":    searchText,
 		"processed_at":   time.Now().Format(time.RFC3339),
 		"updated_at":     time.Now().Format(time.RFC3339),
+		// AudiModal reports processing complete once chunks and embeddings
+		// are created, which marks the end of indexing
+		"indexed": status == "processed" && resultCount(result, "embeddings_created") > 0,
 	}
 
-	_, err := s.neo4j.ExecuteQueryWithLogging(ctx, query, params)
+	_, err = s.neo4j.ExecuteQueryWithLogging(ctx, query, params)
 	if err != nil {
 		s.logger.Error("Failed to update processing result",
 			zap.String("document_id", documentID),
@@ -941,6 +1570,8 @@ func (s *DocumentService) updateProcessingResultWithTenant(ctx context.Context,
 		return errors.Database("Failed to update processing result", err)
 	}
 
+	s.publishStatusChange(ctx, documentID, tenantID, status, errorMsg)
+
 	// Monitor and log processing results for alerting/metrics
 	s.monitorProcessingResult(ctx, documentID, tenantID, status, extractedText, errorMsg)
 
@@ -959,7 +1590,7 @@ func (s *DocumentService) verifyNotebookAccess(ctx context.Context, notebookID,
 		MATCH (n:Notebook {id: $notebook_id})
 		WHERE n.visibility = 'public' OR 
 		      n.owner_id = $user_id OR 
-		      EXISTS((n)-[:SHARED_WITH]->(:User {id: $user_id}))
+		      ` + notebookSharedWith("n") + `
 		RETURN count(n) > 0 as has_access
 	`
 
@@ -1025,7 +1656,7 @@ func (s *DocumentService) updateDocumentStatusWithJobID(ctx context.Context, doc
 	}
 	
 	if len(tenantResult.Records) == 0 {
-		return errors.NotFound("Document not found")
+		return errors.NotFound("Document not found").WithErrorCode(errors.CodeDocumentNotFound)
 	}
 	
 	tenantID := ""
@@ -1054,18 +1685,25 @@ func (s *DocumentService) updateDocumentStatusWithJobID(ctx context.Context, doc
 	
 	// Add processing result if provided
 	if result != nil && len(result) > 0 {
+		// Extract text if available in result
+		if extractedText, ok := result["extracted_text"].(string); ok {
+			storedText, encrypted, err := s.encryption.Seal(ctx, documentID, tenantID, extractedText)
+			if err != nil {
+				return err
+			}
+			if encrypted {
+				result = withoutExtractedText(result)
+			}
+			setClauses = append(setClauses, "d.extracted_text = $extracted_text")
+			params["extracted_text"] = storedText
+		}
+
 		resultJSON, err := json.Marshal(result)
 		if err != nil {
 			return fmt.Errorf("failed to marshal processing result: %w", err)
 		}
 		setClauses = append(setClauses, "d.processing_result = $processing_result")
 		params["processing_result"] = string(resultJSON)
-		
-		// Extract text if available in result
-		if extractedText, ok := result["extracted_text"].(string); ok {
-			setClauses = append(setClauses, "d.extracted_text = $extracted_text")
-			params["extracted_text"] = extractedText
-		}
 	}
 	
 	// Add error message if provided
@@ -1077,9 +1715,10 @@ func (s *DocumentService) updateDocumentStatusWithJobID(ctx context.Context, doc
 	// Build and execute the update query
 	query := fmt.Sprintf(`
 		MATCH (d:Document {id: $document_id, tenant_id: $tenant_id})
+		WHERE %s
 		SET %s
 		RETURN d.id
-	`, strings.Join(setClauses, ", "))
+	`, database.NotDeleted("d"), strings.Join(setClauses, ", "))
 	
 	_, err = s.neo4j.ExecuteQueryWithLogging(ctx, query, params)
 	if err != nil {
@@ -1090,6 +1729,8 @@ func (s *DocumentService) updateDocumentStatusWithJobID(ctx context.Context, doc
 			zap.Error(err))
 		return errors.Database("Failed to update document status", err)
 	}
+
+	s.publishStatusChange(ctx, documentID, tenantID, status, errorMsg)
 	
 	s.logger.Info("Document status updated successfully",
 		zap.String("document_id", documentID),
@@ -1114,7 +1755,7 @@ func (s *DocumentService) updateDocumentStatus(ctx context.Context, documentID,
 	}
 	
 	if len(tenantResult.Records) == 0 {
-		return errors.NotFound("Document not found")
+		return errors.NotFound("Document not found").WithErrorCode(errors.CodeDocumentNotFound)
 	}
 	
 	tenantID := ""
@@ -1137,21 +1778,31 @@ func (s *DocumentService) updateDocumentStatus(ctx context.Context, documentID,
 	
 	// Add processing result if provided
 	if result != nil && len(result) > 0 {
-		resultJSON, err := json.Marshal(result)
-		if err != nil {
-			return fmt.Errorf("failed to marshal processing result: %w", err)
-		}
-		setClauses = append(setClauses, "d.processing_result = $processing_result")
-		params["processing_result"] = string(resultJSON)
-		
 		// Extract text if available in result
 		if extractedText, ok := result["extracted_text"].(string); ok {
+			storedText, encrypted, err := s.encryption.Seal(ctx, documentID, tenantID, extractedText)
+			if err != nil {
+				return err
+			}
+			if encrypted {
+				// Encrypted text stays out of the search text
+				result = withoutExtractedText(result)
+				extractedText = ""
+			}
 			setClauses = append(setClauses, "d.extracted_text = $extracted_text")
-			params["extracted_text"] = extractedText
+			params["extracted_text"] = storedText
 			
 			// Update search text with extracted content
-			setClauses = append(setClauses, "d.search_text = d.name + ' ' + COALESCE(d.description, '') + ' ' + $extracted_text")
+			setClauses = append(setClauses, "d.search_text = d.name + ' ' + COALESCE(d.description, '') + ' ' + $searchable_text")
+			params["searchable_text"] = extractedText
+		}
+
+		resultJSON, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("failed to marshal processing result: %w", err)
 		}
+		setClauses = append(setClauses, "d.processing_result = $processing_result")
+		params["processing_result"] = string(resultJSON)
 		
 		// Set processed_at for processed status
 		if status == "processed" {
@@ -1168,12 +1819,94 @@ func (s *DocumentService) updateDocumentStatus(ctx context.Context, documentID,
 	
 	query := fmt.Sprintf(`
 		MATCH (d:Document {id: $document_id, tenant_id: $tenant_id})
+		WHERE %s
 		SET %s
 		RETURN d
-	`, strings.Join(setClauses, ", "))
+	`, database.NotDeleted("d"), strings.Join(setClauses, ", "))
 
 	_, err = s.neo4j.ExecuteQueryWithLogging(ctx, query, params)
-	return err
+	if err != nil {
+		return err
+	}
+
+	s.publishStatusChange(ctx, documentID, tenantID, status, errorMsg)
+	return nil
+}
+
+// publishStatusChange publishes a document status change, with a dedicated
+// event type for the outcome of processing, counts that outcome for the
+// tenant and announces the change to real-time clients
+func (s *DocumentService) publishStatusChange(ctx context.Context, documentID, tenantID, status, errorMsg string) {
+	eventType := EventDocumentStatusChanged
+	switch status {
+	case "processed":
+		eventType = EventDocumentProcessed
+		s.countProcessingJob(tenantID, status)
+		s.finishProcessingJobs(ctx, tenantID, documentID, models.ProcessingJobCompleted, errorMsg)
+	case "failed":
+		eventType = EventDocumentFailed
+		s.countProcessingJob(tenantID, status)
+		s.finishProcessingJobs(ctx, tenantID, documentID, models.ProcessingJobFailed, errorMsg)
+	}
+
+	// The event hub announces the change to real-time clients, and tells
+	// the owner when processing finished
+	data := map[string]interface{}{
+		"status":    status,
+		"error":     errorMsg,
+		"tenant_id": tenantID,
+	}
+	if eventType != EventDocumentStatusChanged {
+		data["owner_id"], data["name"], data["space_id"] = s.documentOwner(ctx, documentID, tenantID)
+	}
+	publishDomainEvent(ctx, s.events, s.logger, NewDocumentEvent(eventType, documentID, "", data))
+}
+
+// documentOwner returns the owner, name and space of a document, all empty
+// when the document cannot be read
+func (s *DocumentService) documentOwner(ctx context.Context, documentID, tenantID string) (string, string, string) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "document.owner"), `
+		MATCH (d:Document {id: $id, tenant_id: $tenant_id})
+		RETURN d.owner_id AS owner_id, d.name AS name, d.space_id AS space_id
+	`, map[string]interface{}{"id": documentID, "tenant_id": tenantID})
+	if err != nil || len(result.Records) == 0 {
+		s.logger.Debug("Failed to read document owner",
+			zap.String("document_id", documentID),
+			zap.Error(err))
+		return "", "", ""
+	}
+	record := result.Records[0]
+	return recordString(record, "owner_id"), recordString(record, "name"), recordString(record, "space_id")
+}
+
+// finishProcessingJobs ends the stored processing jobs of a document whose
+// processing outcome arrived, e.g. by webhook
+func (s *DocumentService) finishProcessingJobs(ctx context.Context, tenantID, documentID, status, errorMsg string) {
+	if s.processingJobs == nil || tenantID == "" {
+		return
+	}
+	if _, err := s.processingJobs.FinishDocumentJobs(ctx, tenantID, documentID, status, errorMsg); err != nil {
+		s.logger.Warn("Failed to finish processing jobs",
+			zap.String("document_id", documentID),
+			zap.String("status", status),
+			zap.Error(err))
+	}
+}
+
+// ListProcessingJobs lists the processing jobs of the space's tenant,
+// newest first, optionally of one document or in one status
+func (s *DocumentService) ListProcessingJobs(ctx context.Context, documentID, status string, spaceCtx *models.SpaceContext, limit, offset int) ([]*models.ProcessingJob, bool, error) {
+	if !spaceCtx.CanRead() {
+		return nil, false, errors.Forbidden("Insufficient permissions to read processing jobs")
+	}
+	if s.processingJobs == nil {
+		return []*models.ProcessingJob{}, false, nil
+	}
+	return s.processingJobs.List(ctx, ProcessingJobFilter{
+		TenantID:   spaceCtx.TenantID,
+		DocumentID: documentID,
+		Status:     status,
+	}, limit, offset)
 }
 
 // RefreshProcessingResults checks AudiModal for updated processing results and updates documents
@@ -1188,7 +1921,7 @@ func (s *DocumentService) RefreshProcessingResults(ctx context.Context) error {
 		         d.extracted_text CONTAINS "Status: discovered" OR 
 		         d.processing_time = 100)))
 		AND d.processing_job_id IS NOT NULL
-		RETURN d.id, d.processing_job_id
+		RETURN d.id, d.processing_job_id, d.processing_provider
 		ORDER BY d.updated_at DESC
 		LIMIT 50
 	`
@@ -1213,9 +1946,11 @@ func (s *DocumentService) RefreshProcessingResults(ctx context.Context) error {
 			docID := documentID.(string)
 			processingJobID := jobID.(string)
 			
-			// Get updated job status from AudiModal
-			if s.processingService != nil {
-				job, jobErr := s.processingService.GetProcessingJob(ctx, processingJobID)
+			// Get updated job status from the document's processing provider
+			providerName, _ := record.Get("d.processing_provider")
+			name, _ := providerName.(string)
+			if provider := s.documentProcessingProvider(&models.Document{ProcessingProvider: name}); provider != nil {
+				job, jobErr := provider.GetStatus(ctx, processingJobID)
 				if jobErr == nil && job != nil && job.Status == "completed" && job.Result != nil {
 					// Extract processing results
 					extractedText := ""
@@ -1273,7 +2008,7 @@ func (s *DocumentService) updateDocumentWithProcessingResults(ctx context.Contex
 	}
 	
 	if len(tenantResult.Records) == 0 {
-		return errors.NotFound("Document not found")
+		return errors.NotFound("Document not found").WithErrorCode(errors.CodeDocumentNotFound)
 	}
 	
 	tenantID := ""
@@ -1284,20 +2019,31 @@ func (s *DocumentService) updateDocumentWithProcessingResults(ctx context.Contex
 	// Update document with AI processing results
 	query := `
 		MATCH (d:Document {id: $document_id, tenant_id: $tenant_id})
+		WHERE ` + database.NotDeleted("d") + `
 		SET d.extracted_text = $extracted_text,
 		    d.processing_time = $processing_time,
 		    d.confidence_score = $confidence_score,
 		    d.status = "processed",
 		    d.processed_at = datetime($processed_at),
 		    d.updated_at = datetime($updated_at),
-		    d.search_text = d.name + ' ' + COALESCE(d.description, '') + ' ' + $extracted_text
+		    d.search_text = d.name + ' ' + COALESCE(d.description, '') + ' ' + $searchable_text
 		RETURN d
 	`
+
+	storedText, encrypted, err := s.encryption.Seal(ctx, documentID, tenantID, extractedText)
+	if err != nil {
+		return err
+	}
+	searchableText := extractedText
+	if encrypted {
+		searchableText = ""
+	}
 	
 	params := map[string]interface{}{
 		"document_id":      documentID,
 		"tenant_id":        tenantID,
-		"extracted_text":   extractedText,
+		"extracted_text":   storedText,
+		"searchable_text":  searchableText,
 		"processing_time":  processingTime,
 		"confidence_score": confidenceScore,
 		"processed_at":     time.Now().Format(time.RFC3339),
@@ -1323,7 +2069,7 @@ func (s *DocumentService) updateDocumentStorage(ctx context.Context, documentID,
 	}
 	
 	if len(tenantResult.Records) == 0 {
-		return errors.NotFound("Document not found")
+		return errors.NotFound("Document not found").WithErrorCode(errors.CodeDocumentNotFound)
 	}
 	
 	tenantID := ""
@@ -1366,14 +2112,19 @@ func (s *DocumentService) canUserAccessDocument(ctx context.Context, document *m
 	return hasAccess
 }
 
-func (s *DocumentService) canUserWriteDocument(ctx context.Context, document *models.Document, userID string) bool {
-	// Owner can always write
-	if document.OwnerID == userID {
-		return true
-	}
-
-	// TODO: Check write permissions from notebook sharing
-	return false
+// authorizeDocument checks that a user may perform action on a document:
+// its owner, or a user the document's notebook is shared with whose share
+// role grants it
+func (s *DocumentService) authorizeDocument(ctx context.Context, document *models.Document, userID, action string) error {
+	subject := models.Subject{UserID: userID}
+	if document.OwnerID != userID {
+		subject = s.notebookService.ShareSubject(ctx, document.TenantID, document.NotebookID, userID)
+	}
+	return s.authz.Authorize(ctx, subject, action, models.Resource{
+		Type:    models.ResourceDocument,
+		ID:      document.ID,
+		OwnerID: document.OwnerID,
+	})
 }
 
 // getDocumentByIDInternal is an internal helper that retrieves a document with tenant isolation
@@ -1386,7 +2137,7 @@ func (s *DocumentService) getDocumentByIDInternal(ctx context.Context, documentI
 		       d.extracted_text, d.processing_result, d.processing_time, d.confidence_score, d.metadata, d.notebook_id, d.owner_id,
 		       d.space_type, d.space_id, d.tenant_id,
 		       d.tags, d.search_text, d.processing_job_id, d.processed_at,
-		       d.created_at, d.updated_at
+		       d.moderation_status, d.created_at, d.updated_at
 	`
 
 	params := map[string]interface{}{
@@ -1400,7 +2151,7 @@ func (s *DocumentService) getDocumentByIDInternal(ctx context.Context, documentI
 	}
 
 	if len(result.Records) == 0 {
-		return nil, errors.NotFound("Document not found")
+		return nil, errors.NotFound("Document not found").WithErrorCode(errors.CodeDocumentNotFound)
 	}
 
 	return s.recordToDocument(result.Records[0])
@@ -1515,6 +2266,13 @@ func (s *DocumentService) recordToDocument(record interface{}) (*models.Document
 			document.ProcessedAt = &t
 		}
 	}
+	if val, ok := r.Get("d.restricted"); ok && val != nil {
+		document.Restricted, _ = val.(bool)
+	}
+	if val, ok := r.Get("d.moderation_status"); ok && val != nil {
+		status, _ := val.(string)
+		document.ModerationStatus = models.ModerationStatus(status)
+	}
 
 	// Extract processing_job_id
 	if val, ok := r.Get("d.processing_job_id"); ok && val != nil {
@@ -1540,6 +2298,27 @@ func (s *DocumentService) recordToDocument(record interface{}) (*models.Document
 		}
 	}
 
+	// Extract ocr_settings - stored as JSON string in Neo4j
+	if val, ok := r.Get("d.ocr_settings"); ok && val != nil {
+		if v, ok := val.(string); ok && v != "" {
+			var ocr models.OCRSettings
+			if err := json.Unmarshal([]byte(v), &ocr); err == nil {
+				document.OCRSettings = &ocr
+			}
+		}
+	}
+	if val, ok := r.Get("d.processing_provider"); ok && val != nil {
+		if v, ok := val.(string); ok {
+			document.ProcessingProvider = v
+		}
+	}
+	if val, ok := r.Get("d.processing_priority"); ok && val != nil {
+		if v, ok := val.(string); ok {
+			document.ProcessingPriority = v
+		}
+	}
+	document.ExtractedText, document.TextEncrypted = s.encryption.Open(document.TenantID, document.ExtractedText)
+
 	return document, nil
 }
 
@@ -1768,6 +2547,12 @@ func (s *DocumentService) recordToDocumentResponse(record interface{}) (*models.
 		NotebookID:   getString("d.notebook_id"),
 		OwnerID:      getString("d.owner_id"),
 		Tags:         getStringArray("d.tags"),
+		Restricted: func() bool {
+			val, _ := neo4jRecord.Get("d.restricted")
+			restricted, _ := val.(bool)
+			return restricted
+		}(),
+		ModerationStatus: models.ModerationStatus(getString("d.moderation_status")),
 		ExtractedText: getString("d.extracted_text"),
 		ProcessingTime: func() *int64 {
 			if val, found := neo4jRecord.Get("d.processing_time"); found && val != nil {
@@ -1803,84 +2588,14 @@ func (s *DocumentService) recordToDocumentResponse(record interface{}) (*models.
 			AvatarURL: ownerAvatarURL,
 		}
 	}
+	doc.ExtractedText, _ = s.encryption.Open(getString("d.tenant_id"), doc.ExtractedText)
 
 	return doc, nil
 }
 
-// DownloadDocumentFile downloads the file content for a document
-func (s *DocumentService) DownloadDocumentFile(ctx context.Context, documentID, userID string, spaceContext *models.SpaceContext) ([]byte, *models.Document, error) {
-	s.logger.Info("Starting document file download", 
-		zap.String("document_id", documentID),
-		zap.String("user_id", userID),
-	)
-
-	// First, get the document to verify access and get storage info
-	document, err := s.GetDocumentByID(ctx, documentID, userID, spaceContext)
-	if err != nil {
-		s.logger.Error("Failed to get document for download", 
-			zap.String("document_id", documentID),
-			zap.Error(err),
-		)
-		return nil, nil, fmt.Errorf("failed to get document: %w", err)
-	}
-
-	// Check if the document has storage path
-	if document.StoragePath == "" {
-		s.logger.Error("Document has no storage path", 
-			zap.String("document_id", documentID),
-		)
-		return nil, nil, fmt.Errorf("document file not available for download")
-	}
-
-	// Extract the key from storage path (supports both "bucket:key" and legacy "key" formats)
-	var key string
-	if strings.Contains(document.StoragePath, ":") {
-		// New format: "bucket:key"
-		parts := strings.SplitN(document.StoragePath, ":", 2)
-		if len(parts) == 2 {
-			key = parts[1]
-		} else {
-			s.logger.Error("Invalid storage path format", 
-				zap.String("document_id", documentID),
-				zap.String("storage_path", document.StoragePath),
-			)
-			return nil, nil, fmt.Errorf("invalid storage path format")
-		}
-	} else {
-		// Legacy format: just the key (backward compatibility)
-		key = document.StoragePath
-	}
-
-	// Download the file using the storage service
-	if s.storageService == nil {
-		s.logger.Error("Storage service not available for download",
-			zap.String("document_id", documentID),
-		)
-		return nil, nil, fmt.Errorf("storage service not available")
-	}
-
-	fileData, err := s.storageService.DownloadFileFromTenantBucket(ctx, spaceContext.TenantID, key)
-	if err != nil {
-		s.logger.Error("Failed to download file from storage", 
-			zap.String("document_id", documentID),
-			zap.String("key", key),
-			zap.String("tenant_id", spaceContext.TenantID),
-			zap.Error(err),
-		)
-		return nil, nil, fmt.Errorf("failed to download file: %w", err)
-	}
-
-	s.logger.Info("Document file downloaded successfully", 
-		zap.String("document_id", documentID),
-		zap.String("original_name", document.OriginalName),
-		zap.Int("size_bytes", len(fileData)),
-	)
-
-	return fileData, document, nil
-}
-
 // ReprocessDocument resubmits a document for text extraction processing
-func (s *DocumentService) ReprocessDocument(ctx context.Context, document *models.Document, spaceContext *models.SpaceContext) (*models.ProcessingJob, error) {
+// with a priority tier, or the tier it was last submitted with when empty
+func (s *DocumentService) ReprocessDocument(ctx context.Context, document *models.Document, spaceContext *models.SpaceContext, priority string) (*models.ProcessingJob, error) {
 	s.logger.Info("Starting document reprocessing", 
 		zap.String("document_id", document.ID),
 		zap.String("original_name", document.OriginalName),
@@ -1892,8 +2607,14 @@ func (s *DocumentService) ReprocessDocument(ctx context.Context, document *model
 		return nil, fmt.Errorf("document has no storage path - cannot reprocess")
 	}
 
+	tier, err := s.processingPriority(spaceContext, priority, document.ProcessingPriority)
+	if err != nil {
+		return nil, err
+	}
+	document.ProcessingPriority = tier
+
 	// Set document status to processing
-	err := s.updateDocumentStatus(ctx, document.ID, "processing", nil, "")
+	err = s.updateDocumentStatus(ctx, document.ID, "processing", nil, "")
 	if err != nil {
 		s.logger.Error("Failed to update document status for reprocessing",
 			zap.String("document_id", document.ID),
@@ -1914,11 +2635,12 @@ func (s *DocumentService) ReprocessDocument(ctx context.Context, document *model
 
 	// Create processing job
 	job := &models.ProcessingJob{
-		ID:          uuid.New().String(),
-		DocumentID:  document.ID,
-		Type:        "reprocess_document",
-		Status:      "pending",
-		Priority:    1, // High priority for reprocessing
+		ID:           uuid.New().String(),
+		DocumentID:   document.ID,
+		Type:         "reprocess_document",
+		Status:       "pending",
+		Priority:     processingQueueLevel(tier),
+		PriorityTier: tier,
 		Config: map[string]interface{}{
 			"original_name": document.OriginalName,
 			"mime_type": document.MimeType,
@@ -1930,11 +2652,25 @@ func (s *DocumentService) ReprocessDocument(ctx context.Context, document *model
 		CreatedAt: time.Now().UTC(),
 		UpdatedAt: time.Now().UTC(),
 	}
+	// The provider that has the document's file reprocesses it, even if
+	// its space has since chosen another
+	provider := s.documentProcessingProvider(document)
+	if provider != nil {
+		ocr := models.SpaceOCRSettings(s.spaceSettings(ctx, spaceContext.SpaceID))
+		s.recordProcessingSettings(ctx, document, spaceContext, job.Config, provider, ocr)
+	}
 
 	// Submit processing job
-	if s.processingService != nil {
-		submittedJob, err := s.processingService.SubmitProcessingJob(ctx, spaceContext.TenantID, document.ID, "reprocess_document", job.Config)
+	if provider != nil {
+		// The document keeps its processed file, but the vectors of its
+		// earlier text are recycled once it is resubmitted
+		recycleID := s.recordArtifactRecycle(ctx, document, false)
+		submitStartedAt := time.Now()
+		submittedJob, err := provider.ProcessFile(ctx, spaceContext.TenantID, document.ID, "reprocess_document", job.Config)
 		if err != nil {
+			if recycleID != "" {
+				s.deletions.DiscardRecycle(ctx, recycleID)
+			}
 			s.logger.Error("Failed to submit reprocessing job to processing service",
 				zap.String("document_id", document.ID),
 				zap.String("job_id", job.ID),
@@ -1947,6 +2683,13 @@ func (s *DocumentService) ReprocessDocument(ctx context.Context, document *model
 			return nil, fmt.Errorf("failed to submit reprocessing job: %w", err)
 		}
 		job = submittedJob
+		s.recordPipelineTimes(ctx, document.ID, spaceContext.TenantID, map[string]time.Time{
+			pipelineSubmitStartedAt: submitStartedAt,
+			pipelineSubmittedAt:     time.Now(),
+		})
+		if recycleID != "" {
+			s.deletions.StartRecycle(ctx, recycleID)
+		}
 	}
 
 	s.logger.Info("Document reprocessing job created successfully",
@@ -1966,6 +2709,9 @@ func (s *DocumentService) clearDocumentProcessingData(ctx context.Context, docum
 		SET d.extracted_text = null, 
 		    d.processing_result = null,
 		    d.processed_at = null,
+		    d.submit_started_at = null,
+		    d.submitted_at = null,
+		    d.indexed_at = null,
 		    d.updated_at = $updated_at
 		RETURN d.id
 	`
@@ -2140,161 +2886,16 @@ func (s *DocumentService) recordProcessingFailure(ctx context.Context, documentI
 	if len(result.Records) > 0 {
 		if countVal, ok := result.Records[0].Get("count"); ok {
 			if count, ok := countVal.(int64); ok {
-				if count <= 3 {
-					// Attempt retry for first 3 failures
+				if count <= maxRetryAttempts {
 					s.scheduleRetryProcessing(ctx, documentID, tenantID, count)
 				} else {
-					s.logger.Error("Document processing has failed multiple times - maximum retries exceeded",
-						zap.String("document_id", documentID),
-						zap.String("tenant_id", tenantID),
-						zap.Int64("failure_count", count),
-						zap.String("alert", "repeated_processing_failure"),
-					)
-					// Here you could trigger alerts, webhooks, or other escalation mechanisms
+					s.escalateRetries(ctx, documentID, tenantID, count, errorMsg)
 				}
 			}
 		}
 	}
 }
 
-// scheduleRetryProcessing schedules a retry for failed text extraction
-func (s *DocumentService) scheduleRetryProcessing(ctx context.Context, documentID, tenantID string, retryCount int64) {
-	// Calculate exponential backoff delay: 2^retryCount minutes
-	delayMinutes := int(math.Pow(2, float64(retryCount))) // 2, 4, 8 minutes
-	retryAt := time.Now().UTC().Add(time.Duration(delayMinutes) * time.Minute)
-	
-	s.logger.Info("Scheduling document processing retry",
-		zap.String("document_id", documentID),
-		zap.String("tenant_id", tenantID),
-		zap.Int64("retry_attempt", retryCount),
-		zap.Int("delay_minutes", delayMinutes),
-		zap.Time("retry_at", retryAt),
-	)
-
-	// Create retry job in database
-	query := `
-		CREATE (j:ProcessingRetryJob {
-			id: $job_id,
-			document_id: $document_id,
-			tenant_id: $tenant_id,
-			retry_attempt: $retry_attempt,
-			status: 'scheduled',
-			retry_at: $retry_at,
-			created_at: $created_at,
-			updated_at: $created_at
-		})
-		RETURN j.id as job_id
-	`
-	
-	jobID := uuid.New().String()
-	params := map[string]interface{}{
-		"job_id": jobID,
-		"document_id": documentID,
-		"tenant_id": tenantID,
-		"retry_attempt": retryCount,
-		"retry_at": retryAt,
-		"created_at": time.Now().UTC(),
-	}
-
-	_, err := s.neo4j.ExecuteQuery(ctx, query, params)
-	if err != nil {
-		s.logger.Error("Failed to schedule retry job",
-			zap.String("document_id", documentID),
-			zap.String("job_id", jobID),
-			zap.Error(err),
-		)
-		return
-	}
-
-	// Start goroutine to handle the retry after delay
-	go s.handleScheduledRetry(documentID, tenantID, jobID, retryAt)
-}
-
-// handleScheduledRetry processes a scheduled retry after the delay period
-func (s *DocumentService) handleScheduledRetry(documentID, tenantID, jobID string, retryAt time.Time) {
-	// Wait for the scheduled time
-	time.Sleep(time.Until(retryAt))
-	
-	// Create context for retry operation
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
-	defer cancel()
-
-	s.logger.Info("Starting scheduled document processing retry",
-		zap.String("document_id", documentID),
-		zap.String("job_id", jobID),
-	)
-
-	// Mark retry job as in progress
-	err := s.updateRetryJobStatus(ctx, jobID, "in_progress")
-	if err != nil {
-		s.logger.Error("Failed to update retry job status",
-			zap.String("job_id", jobID),
-			zap.Error(err),
-		)
-		return
-	}
-
-	// Get document details
-	document, err := s.getDocumentForRetry(ctx, documentID, tenantID)
-	if err != nil {
-		s.logger.Error("Failed to get document for retry",
-			zap.String("document_id", documentID),
-			zap.Error(err),
-		)
-		_ = s.updateRetryJobStatus(ctx, jobID, "failed")
-		return
-	}
-
-	// Attempt reprocessing
-	spaceContext := &models.SpaceContext{
-		TenantID: tenantID,
-		UserID: document.OwnerID, // Use document owner for retry context
-	}
-
-	_, err = s.ReprocessDocument(ctx, document, spaceContext)
-	if err != nil {
-		s.logger.Error("Document retry processing failed",
-			zap.String("document_id", documentID),
-			zap.String("job_id", jobID),
-			zap.Error(err),
-		)
-		_ = s.updateRetryJobStatus(ctx, jobID, "failed")
-		return
-	}
-
-	// Mark retry job as completed
-	err = s.updateRetryJobStatus(ctx, jobID, "completed")
-	if err != nil {
-		s.logger.Error("Failed to mark retry job as completed",
-			zap.String("job_id", jobID),
-			zap.Error(err),
-		)
-	}
-
-	s.logger.Info("Document processing retry completed successfully",
-		zap.String("document_id", documentID),
-		zap.String("job_id", jobID),
-	)
-}
-
-// updateRetryJobStatus updates the status of a retry job
-func (s *DocumentService) updateRetryJobStatus(ctx context.Context, jobID, status string) error {
-	query := `
-		MATCH (j:ProcessingRetryJob {id: $job_id})
-		SET j.status = $status, j.updated_at = $updated_at
-		RETURN j.id
-	`
-	
-	params := map[string]interface{}{
-		"job_id": jobID,
-		"status": status,
-		"updated_at": time.Now().UTC(),
-	}
-
-	_, err := s.neo4j.ExecuteQuery(ctx, query, params)
-	return err
-}
-
 // getDocumentForRetry retrieves a document for retry processing
 func (s *DocumentService) getDocumentForRetry(ctx context.Context, documentID, tenantID string) (*models.Document, error) {
 	query := `
@@ -2361,6 +2962,8 @@ func (s *DocumentService) deleteDocumentRecord(ctx context.Context, documentID s
 		return fmt.Errorf("failed to delete document record: %w", err)
 	}
 
+	publishDomainEvent(ctx, s.events, s.logger, NewDocumentEvent(EventDocumentDeleted, documentID, "", nil))
+
 	s.logger.Info("Document record deleted and notebook counts decremented",
 		zap.String("document_id", documentID))
 
@@ -2512,6 +3115,26 @@ func (s *DocumentService) FindDocumentByAudiModalFileID(ctx context.Context, aud
 	return document, nil
 }
 
+// IsCurrentProcessingJob reports whether the AudiModal file of a processing
+// outcome is the one the document was last submitted as. An outcome of an
+// earlier submission, such as the file of a replaced version, is stale. A
+// document without a recorded file accepts any outcome; a missing document
+// none.
+func (s *DocumentService) IsCurrentProcessingJob(ctx context.Context, documentID, audimodalFileID string) (bool, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "document.processing_job_id"), `
+		MATCH (d:Document {id: $id})
+		RETURN d.processing_job_id AS processing_job_id
+	`, map[string]interface{}{"id": documentID})
+	if err != nil {
+		return false, errors.Database("Failed to read document processing job", err)
+	}
+	if len(result.Records) == 0 {
+		return false, nil
+	}
+	jobID := recordString(result.Records[0], "processing_job_id")
+	return jobID == "" || audimodalFileID == "" || jobID == audimodalFileID, nil
+}
+
 // extractFilenameFromPath extracts the filename from a URL or file path
 func extractFilenameFromPath(path string) string {
 	// Remove URL scheme if present
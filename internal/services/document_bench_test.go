@@ -0,0 +1,76 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// benchDocumentRecord builds a record shaped like the document list queries
+func benchDocumentRecord() *neo4j.Record {
+	now := time.Now()
+	fields := map[string]interface{}{
+		"d.id":               "3f8c1a52-7d4b-4e0f-9a1e-2c6b5d4e3f21",
+		"d.name":             "Quarterly report",
+		"d.description":      "Financial results for Q3",
+		"d.type":             "pdf",
+		"d.status":           "processed",
+		"d.original_name":    "q3-report.pdf",
+		"d.mime_type":        "application/pdf",
+		"d.size_bytes":       int64(2 << 20),
+		"d.notebook_id":      "9b2d7e41-0c3a-4f5e-8d6b-1a2c3d4e5f60",
+		"d.owner_id":         "5e6f7a8b-9c0d-4e1f-a2b3-c4d5e6f7a8b9",
+		"d.space_type":       "personal",
+		"d.space_id":         "space_1234567890",
+		"d.tenant_id":        "tenant_1234567890",
+		"d.tags":             []interface{}{"finance", "quarterly", "2026"},
+		"d.extracted_text":   strings.Repeat("Revenue grew across all regions. ", 200),
+		"d.processing_time":  int64(1840),
+		"d.confidence_score": 0.97,
+		"d.processed_at":     now,
+		"d.created_at":       now,
+		"d.updated_at":       now,
+		"owner.username":     "jdoe",
+		"owner.full_name":    "Jane Doe",
+		"owner.avatar_url":   "https://example.com/avatar.png",
+	}
+
+	record := &neo4j.Record{}
+	for key, value := range fields {
+		record.Keys = append(record.Keys, key)
+		record.Values = append(record.Values, value)
+	}
+	return record
+}
+
+func BenchmarkRecordToDocumentResponse(b *testing.B) {
+	s := &DocumentService{logger: setupTestLogger(b)}
+	record := benchDocumentRecord()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.recordToDocumentResponse(record); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDocumentResponseJSON(b *testing.B) {
+	s := &DocumentService{logger: setupTestLogger(b)}
+	document, err := s.recordToDocumentResponse(benchDocumentRecord())
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := json.Marshal(document); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// bulkUploadWorkers bounds how many files of a bulk upload are stored and
+// submitted for processing at once
+const bulkUploadWorkers = 4
+
+// BulkUpload uploads files into a notebook, several at a time, and submits
+// each for processing like UploadDocument. Files succeed or fail on their
+// own: the response reports the document or the error of every file, in
+// the order given. Only a notebook the user cannot upload into fails the
+// whole upload.
+func (s *DocumentService) BulkUpload(ctx context.Context, notebookID string, files []models.BulkUploadFile, tags []string, ownerID string, spaceCtx *models.SpaceContext) (*models.BulkUploadResponse, error) {
+	notebook, err := s.notebookService.GetNotebookByID(ctx, notebookID, ownerID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+	if notebook.TenantID != spaceCtx.TenantID || notebook.SpaceID != spaceCtx.SpaceID {
+		return nil, errors.ForbiddenWithDetails("Notebook not accessible in this space", map[string]interface{}{
+			"notebook_id": notebookID,
+			"space_id":    spaceCtx.SpaceID,
+		}).WithErrorCode(errors.CodeNotebookNotAccessible)
+	}
+	if !spaceCtx.CanCreate() {
+		return nil, errors.Forbidden("Insufficient permissions to upload documents")
+	}
+
+	results := make([]*models.BulkUploadResult, len(files))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < bulkUploadWorkers && w < len(files); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = s.uploadBulkFile(ctx, notebookID, files[i], tags, ownerID, spaceCtx)
+			}
+		}()
+	}
+	for i := range files {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	response := &models.BulkUploadResponse{NotebookID: notebookID, Results: results}
+	for _, result := range results {
+		if result.Status == models.BulkUploadCreated {
+			response.Succeeded++
+		} else {
+			response.Failed++
+		}
+	}
+
+	s.logger.Info("Bulk upload completed",
+		zap.String("notebook_id", notebookID),
+		zap.Int("succeeded", response.Succeeded),
+		zap.Int("failed", response.Failed))
+	return response, nil
+}
+
+// uploadBulkFile uploads one file of a bulk upload
+func (s *DocumentService) uploadBulkFile(ctx context.Context, notebookID string, file models.BulkUploadFile, tags []string, ownerID string, spaceCtx *models.SpaceContext) *models.BulkUploadResult {
+	req := models.DocumentUploadRequest{
+		DocumentCreateRequest: models.DocumentCreateRequest{
+			Name:       file.FileName,
+			NotebookID: notebookID,
+			Tags:       tags,
+		},
+		FileData: file.Data,
+	}
+	fileInfo := models.FileInfo{
+		OriginalName: file.FileName,
+		MimeType:     file.MimeType,
+		SizeBytes:    int64(len(file.Data)),
+	}
+
+	document, err := s.UploadDocument(ctx, req, ownerID, spaceCtx, fileInfo)
+	if err != nil {
+		s.logger.Warn("Bulk upload file failed",
+			zap.String("notebook_id", notebookID),
+			zap.String("file_name", file.FileName),
+			zap.Error(err))
+		return BulkUploadFailure(file.FileName, err)
+	}
+	return &models.BulkUploadResult{
+		FileName: file.FileName,
+		Status:   models.BulkUploadCreated,
+		Document: document.ToResponse(),
+	}
+}
+
+// BulkUploadFailure reports a file of a bulk upload that failed with err
+func BulkUploadFailure(fileName string, err error) *models.BulkUploadResult {
+	apiErr := errors.Normalize(err)
+	return &models.BulkUploadResult{
+		FileName: fileName,
+		Status:   models.BulkUploadFailed,
+		Error: &models.JobError{
+			Code:      apiErr.Code,
+			ErrorCode: apiErr.ErrorCode,
+			Message:   apiErr.Message,
+		},
+	}
+}
@@ -0,0 +1,357 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/pmezard/go-difflib/difflib"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// DocumentComparisonService compares the extracted text of two documents,
+// or of two versions of one, line by line. Texts over the configured size
+// limits are refused rather than diffed. Diffs are cached by the texts
+// compared, so the same texts are diffed once however they are named.
+type DocumentComparisonService struct {
+	content *DocumentContentService
+	cache   ResultCache
+	config  config.CompareConfig
+	logger  *logger.Logger
+}
+
+// NewDocumentComparisonService creates a document comparison service.
+// Without cache diffs are cached in-process.
+func NewDocumentComparisonService(content *DocumentContentService, cache ResultCache, cfg config.CompareConfig, log *logger.Logger) *DocumentComparisonService {
+	if cache == nil {
+		cache = NewLocalResultCache()
+	}
+	return &DocumentComparisonService{
+		content: content,
+		cache:   cache,
+		config:  cfg,
+		logger:  log.WithService("document_comparison_service"),
+	}
+}
+
+// comparedText is one side of a comparison
+type comparedText struct {
+	info  *models.DocumentCompareText
+	text  string
+	lines []string
+}
+
+// textDiff is the part of a comparison that depends on the texts alone,
+// which is what is cached
+type textDiff struct {
+	Similarity float64            `json:"similarity"`
+	Identical  bool               `json:"identical"`
+	Insertions int                `json:"insertions"`
+	Deletions  int                `json:"deletions"`
+	Hunks      []*models.DiffHunk `json:"hunks"`
+	ComputedAt time.Time          `json:"computed_at"`
+}
+
+// Compare returns the difference between the texts of two documents, or
+// two versions, the user can read
+func (s *DocumentComparisonService) Compare(ctx context.Context, req *models.DocumentCompareRequest, userID string, spaceCtx *models.SpaceContext) (*models.DocumentCompareResponse, error) {
+	contextLines := models.DefaultDiffContextLines
+	if req.ContextLines != nil {
+		contextLines = *req.ContextLines
+	}
+	if contextLines < 0 || contextLines > models.MaxDiffContextLines {
+		return nil, errors.ValidationWithDetails("context_lines is out of range", map[string]interface{}{
+			"context_lines": contextLines,
+			"max":           models.MaxDiffContextLines,
+		})
+	}
+
+	base, err := s.side(ctx, req.Base, userID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.side(ctx, req.Target, userID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	key := compareCacheKey(base.text, target.text, contextLines)
+	diff, ok := s.cached(ctx, key)
+	if !ok {
+		diff = diffLines(base.lines, target.lines, contextLines)
+		s.store(ctx, key, diff)
+	}
+
+	return &models.DocumentCompareResponse{
+		Base:       base.info,
+		Target:     target.info,
+		Similarity: diff.Similarity,
+		Identical:  diff.Identical,
+		Insertions: diff.Insertions,
+		Deletions:  diff.Deletions,
+		Hunks:      diff.Hunks,
+		ComputedAt: diff.ComputedAt,
+	}, nil
+}
+
+// side reads the text of one side of a comparison and checks it against
+// the size limits
+func (s *DocumentComparisonService) side(ctx context.Context, side models.DocumentCompareSide, userID string, spaceCtx *models.SpaceContext) (*comparedText, error) {
+	documents := s.content.documents
+	document, err := documents.GetDocumentByID(ctx, side.DocumentID, userID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := documents.neo4j.ExecuteQuery(database.WithQueryName(ctx, "document.compare_version"), `
+		MATCH (d:Document {id: $id, tenant_id: $tenant_id})
+		OPTIONAL MATCH (d)-[:HAS_VERSION]->(v:DocumentVersion {version: $version})
+		RETURN coalesce(d.current_version, 1) AS current_version,
+		       v IS NOT NULL AS found, v.extracted_text AS text
+	`, map[string]interface{}{
+		"id":        document.ID,
+		"tenant_id": spaceCtx.TenantID,
+		"version":   side.Version,
+	})
+	if err != nil {
+		s.logger.Error("Failed to read document version text", zap.String("document_id", document.ID), zap.Error(err))
+		return nil, errors.Database("Failed to read the text to compare", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, errors.NotFound("Document not found")
+	}
+	record := result.Records[0]
+
+	info := &models.DocumentCompareText{
+		DocumentID: document.ID,
+		Name:       document.Name,
+		Version:    int(recordInt64(record, "current_version")),
+	}
+	var text string
+	if side.Version == 0 || side.Version == info.Version {
+		if !document.IsProcessed() {
+			return nil, errors.FileNotProcessedWithDetails("Document has not been processed", map[string]interface{}{
+				"document_id": document.ID,
+				"status":      document.Status,
+			})
+		}
+		extracted, err := s.content.documentText(ctx, document, userID, spaceCtx)
+		if err != nil {
+			return nil, err
+		}
+		text, info.Redacted = extracted.Text, extracted.Redacted
+	} else {
+		info.Version = side.Version
+		if text, err = s.versionText(ctx, document, record, side.Version, userID, spaceCtx); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(text) > s.config.MaxTextBytes {
+		return nil, compareTooLarge(info, "bytes", s.config.MaxTextBytes, len(text))
+	}
+	lines := splitLines(text)
+	if len(lines) > s.config.MaxLines {
+		return nil, compareTooLarge(info, "lines", s.config.MaxLines, len(lines))
+	}
+	info.Lines = len(lines)
+	info.Characters = utf8.RuneCountInString(text)
+	return &comparedText{info: info, text: strings.Join(lines, "\n"), lines: lines}, nil
+}
+
+// versionText returns the text kept on an earlier version of a document.
+// Versions replaced before they were processed kept none, and the text of
+// earlier versions is not redacted, so users the notebook's redaction
+// rules apply to may only compare current texts.
+func (s *DocumentComparisonService) versionText(ctx context.Context, document *models.Document, record *neo4j.Record, number int, userID string, spaceCtx *models.SpaceContext) (string, error) {
+	found, _ := record.Get("found")
+	if exists, _ := found.(bool); !exists {
+		return "", errors.NotFoundWithDetails("Document version not found", map[string]interface{}{
+			"document_id": document.ID,
+			"version":     number,
+		}).WithErrorCode(errors.CodeDocumentVersionNotFound)
+	}
+
+	rules, err := s.content.rules(ctx, document, userID, spaceCtx)
+	if err != nil {
+		return "", err
+	}
+	if rules != nil {
+		return "", errors.ForbiddenWithDetails("Earlier versions cannot be compared under the notebook's redaction rules", map[string]interface{}{
+			"document_id": document.ID,
+			"version":     number,
+		})
+	}
+
+	stored := recordString(record, "text")
+	if stored == "" {
+		return "", errors.FileNotProcessedWithDetails("The text of this version was not kept", map[string]interface{}{
+			"document_id": document.ID,
+			"version":     number,
+			"hint":        "Only versions processed before they were replaced keep their text.",
+		})
+	}
+	text, encrypted := s.content.documents.encryption.Open(document.TenantID, stored)
+	if encrypted && text == "" {
+		return "", errors.Internal("Failed to decrypt the text of the version")
+	}
+	return text, nil
+}
+
+// compareTooLarge is the error for a text over a comparison size limit
+func compareTooLarge(info *models.DocumentCompareText, limit string, max, size int) error {
+	return errors.NewAPIError(errors.ErrUnprocessableEntity, "Text is too large to compare", map[string]interface{}{
+		"document_id": info.DocumentID,
+		"version":     info.Version,
+		"limit":       limit,
+		"max":         max,
+		"size":        size,
+	}).WithErrorCode(errors.CodeCompareTooLarge)
+}
+
+// splitLines splits a text into its lines, without their line endings. A
+// final line ending does not start another line.
+func splitLines(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if text == "" {
+		return []string{}
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines diffs two texts' lines into hunks with contextLines unchanged
+// lines around their changes. Similarity weighs the lines by their length
+// in characters, counting their line ending.
+func diffLines(base, target []string, contextLines int) *textDiff {
+	matcher := difflib.NewMatcherWithJunk(base, target, false, nil)
+	diff := &textDiff{Hunks: []*models.DiffHunk{}, ComputedAt: time.Now().UTC()}
+	for _, op := range matcher.GetOpCodes() {
+		switch op.Tag {
+		case 'r':
+			diff.Deletions += op.I2 - op.I1
+			diff.Insertions += op.J2 - op.J1
+		case 'd':
+			diff.Deletions += op.I2 - op.I1
+		case 'i':
+			diff.Insertions += op.J2 - op.J1
+		}
+	}
+
+	matched := 0
+	for _, block := range matcher.GetMatchingBlocks() {
+		matched += lineChars(base[block.A : block.A+block.Size])
+	}
+	diff.Similarity = 1
+	if total := lineChars(base) + lineChars(target); total > 0 {
+		diff.Similarity = math.Round(2*float64(matched)/float64(total)*10000) / 10000
+	}
+	diff.Identical = diff.Insertions == 0 && diff.Deletions == 0
+	if diff.Identical {
+		return diff
+	}
+
+	for _, group := range matcher.GetGroupedOpCodes(contextLines) {
+		diff.Hunks = append(diff.Hunks, diffHunk(base, target, group))
+	}
+	return diff
+}
+
+// diffHunk returns the hunk of a group of operations
+func diffHunk(base, target []string, group []difflib.OpCode) *models.DiffHunk {
+	first, last := group[0], group[len(group)-1]
+	hunk := &models.DiffHunk{
+		BaseStart:   first.I1,
+		BaseLines:   last.I2 - first.I1,
+		TargetStart: first.J1,
+		TargetLines: last.J2 - first.J1,
+	}
+	if hunk.BaseLines > 0 {
+		hunk.BaseStart++
+	}
+	if hunk.TargetLines > 0 {
+		hunk.TargetStart++
+	}
+	for _, op := range group {
+		if op.Tag == 'e' {
+			hunk.Lines = appendDiffLines(hunk.Lines, models.DiffEqual, base[op.I1:op.I2])
+			continue
+		}
+		if op.Tag == 'r' || op.Tag == 'd' {
+			hunk.Lines = appendDiffLines(hunk.Lines, models.DiffDelete, base[op.I1:op.I2])
+		}
+		if op.Tag == 'r' || op.Tag == 'i' {
+			hunk.Lines = appendDiffLines(hunk.Lines, models.DiffInsert, target[op.J1:op.J2])
+		}
+	}
+	return hunk
+}
+
+func appendDiffLines(lines []models.DiffLine, op string, texts []string) []models.DiffLine {
+	for _, text := range texts {
+		lines = append(lines, models.DiffLine{Op: op, Text: text})
+	}
+	return lines
+}
+
+// lineChars counts the characters of lines and their line endings
+func lineChars(lines []string) int {
+	chars := 0
+	for _, line := range lines {
+		chars += utf8.RuneCountInString(line) + 1
+	}
+	return chars
+}
+
+// compareCacheKey names the cached diff of two texts
+func compareCacheKey(base, target string, contextLines int) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%d:%d:", contextLines, len(base))
+	hash.Write([]byte(base))
+	hash.Write([]byte(target))
+	return fmt.Sprintf("tas:compare:%x", hash.Sum(nil))
+}
+
+// cached returns a cached diff. An unreadable cache entry counts as a miss.
+func (s *DocumentComparisonService) cached(ctx context.Context, key string) (*textDiff, bool) {
+	if s.config.CacheSeconds <= 0 {
+		return nil, false
+	}
+	value, err := s.cache.Get(ctx, key)
+	if err != nil {
+		s.logger.Warn("Failed to read cached comparison", zap.Error(err))
+		return nil, false
+	}
+	if value == "" {
+		return nil, false
+	}
+	var diff textDiff
+	if err := json.Unmarshal([]byte(value), &diff); err != nil {
+		return nil, false
+	}
+	return &diff, true
+}
+
+func (s *DocumentComparisonService) store(ctx context.Context, key string, diff *textDiff) {
+	if s.config.CacheSeconds <= 0 {
+		return
+	}
+	value, err := json.Marshal(diff)
+	if err == nil {
+		err = s.cache.Set(ctx, key, string(value), time.Duration(s.config.CacheSeconds)*time.Second)
+	}
+	if err != nil {
+		s.logger.Warn("Failed to cache comparison", zap.Error(err))
+	}
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+func TestDiffLines(t *testing.T) {
+	base := splitLines("one\ntwo\nthree\nfour\nfive\nsix\nseven\n")
+	target := splitLines("one\ntwo\n3\nfour\nfive\nsix\nseven\neight")
+
+	diff := diffLines(base, target, 1)
+	assert.False(t, diff.Identical)
+	assert.Equal(t, 2, diff.Insertions)
+	assert.Equal(t, 1, diff.Deletions)
+	require.Len(t, diff.Hunks, 2, "changes further apart than the context are separate hunks")
+
+	first := diff.Hunks[0]
+	assert.Equal(t, 2, first.BaseStart)
+	assert.Equal(t, 3, first.BaseLines)
+	assert.Equal(t, 2, first.TargetStart)
+	assert.Equal(t, 3, first.TargetLines)
+	assert.Equal(t, []models.DiffLine{
+		{Op: models.DiffEqual, Text: "two"},
+		{Op: models.DiffDelete, Text: "three"},
+		{Op: models.DiffInsert, Text: "3"},
+		{Op: models.DiffEqual, Text: "four"},
+	}, first.Lines)
+
+	last := diff.Hunks[1]
+	assert.Equal(t, 7, last.BaseStart)
+	assert.Equal(t, 1, last.BaseLines)
+	assert.Equal(t, 7, last.TargetStart)
+	assert.Equal(t, 2, last.TargetLines)
+	assert.Equal(t, models.DiffInsert, last.Lines[1].Op)
+
+	// 28 characters in common out of 34 and 36
+	assert.Equal(t, 0.8, diff.Similarity)
+}
+
+func TestDiffLinesIdenticalAndEmpty(t *testing.T) {
+	diff := diffLines(splitLines("same\r\ntext\r\n"), splitLines("same\ntext"), 3)
+	assert.True(t, diff.Identical)
+	assert.Equal(t, 1.0, diff.Similarity)
+	assert.Empty(t, diff.Hunks)
+
+	diff = diffLines(splitLines(""), splitLines(""), 3)
+	assert.True(t, diff.Identical)
+	assert.Equal(t, 1.0, diff.Similarity)
+
+	diff = diffLines(splitLines(""), splitLines("new\n"), 3)
+	assert.Equal(t, 0.0, diff.Similarity)
+	require.Len(t, diff.Hunks, 1)
+	assert.Equal(t, 0, diff.Hunks[0].BaseStart, "an empty side starts after line 0")
+	assert.Equal(t, 0, diff.Hunks[0].BaseLines)
+	assert.Equal(t, 1, diff.Hunks[0].TargetStart)
+}
+
+func TestCompareCacheKey(t *testing.T) {
+	key := compareCacheKey("a", "bc", 3)
+	assert.Equal(t, key, compareCacheKey("a", "bc", 3))
+	assert.NotEqual(t, key, compareCacheKey("ab", "c", 3), "the texts are not just concatenated")
+	assert.NotEqual(t, key, compareCacheKey("a", "bc", 2))
+	assert.NotEqual(t, key, compareCacheKey("bc", "a", 3))
+}
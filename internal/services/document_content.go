@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// Document content page sizes, in characters
+const (
+	DefaultContentPageChars = 20000
+	MaxContentPageChars     = 200000
+)
+
+// DocumentText is the extracted text of a document as a user may read it
+type DocumentText struct {
+	Text string
+	// Redacted is set when the notebook's redaction rules apply to the
+	// user, and RedactedChunks counts the chunks they left out
+	Redacted       bool
+	RedactedChunks int
+}
+
+// DocumentContentService serves the extracted text and chunks of documents
+// a page at a time, to the users allowed to read them and with the
+// notebook's redaction rules applied, so clients need not fetch a whole
+// document record to read its text.
+type DocumentContentService struct {
+	documents *DocumentService
+	redaction *RedactionService
+	chunks    ChunkSource
+	logger    *logger.Logger
+}
+
+// NewDocumentContentService creates a document content service. redaction
+// may be nil, in which case texts are served whole; chunks may be nil when
+// no processing service is configured.
+func NewDocumentContentService(documents *DocumentService, redaction *RedactionService, chunks ChunkSource, log *logger.Logger) *DocumentContentService {
+	return &DocumentContentService{
+		documents: documents,
+		redaction: redaction,
+		chunks:    chunks,
+		logger:    log.WithService("document_content_service"),
+	}
+}
+
+// Text returns the text of a document a user may read. It is checked that
+// the user can read the document.
+func (s *DocumentContentService) Text(ctx context.Context, documentID, userID string, spaceCtx *models.SpaceContext) (*models.Document, *DocumentText, error) {
+	document, err := s.documents.GetDocumentByID(ctx, documentID, userID, spaceCtx)
+	if err != nil {
+		return nil, nil, err
+	}
+	text, err := s.documentText(ctx, document, userID, spaceCtx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return document, text, nil
+}
+
+// documentText returns the text of a document the user was checked to be
+// able to read
+func (s *DocumentContentService) documentText(ctx context.Context, document *models.Document, userID string, spaceCtx *models.SpaceContext) (*DocumentText, error) {
+	rules, err := s.rules(ctx, document, userID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+	if rules != nil {
+		text, omitted, err := s.redaction.RedactText(ctx, document, rules)
+		if err != nil {
+			return nil, err
+		}
+		return &DocumentText{Text: text, Redacted: true, RedactedChunks: omitted}, nil
+	}
+
+	if document.ExtractedText != "" {
+		return &DocumentText{Text: document.ExtractedText}, nil
+	}
+	text, err := s.documents.GetExtractedText(ctx, document)
+	if err != nil {
+		if errors.IsAPIError(err) {
+			return nil, err
+		}
+		return nil, errors.ExternalService("Failed to read the text of the document", err)
+	}
+	return &DocumentText{Text: text}, nil
+}
+
+// Page returns limit characters of the text of a document from offset,
+// clamping limit to MaxContentPageChars and defaulting it when it is not
+// positive. An offset past the end gives an empty page.
+func (s *DocumentContentService) Page(ctx context.Context, documentID string, offset, limit int, userID string, spaceCtx *models.SpaceContext) (*models.DocumentContentPage, error) {
+	if offset < 0 {
+		return nil, errors.ValidationWithDetails("offset must not be negative", map[string]interface{}{
+			"offset": offset,
+		})
+	}
+	_, text, err := s.Text(ctx, documentID, userID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	page := pageText(text.Text, offset, limit)
+	page.DocumentID = documentID
+	page.Redacted = text.Redacted
+	page.RedactedChunks = text.RedactedChunks
+	return page, nil
+}
+
+// pageText cuts a page of limit characters from offset out of text
+func pageText(text string, offset, limit int) *models.DocumentContentPage {
+	if limit <= 0 {
+		limit = DefaultContentPageChars
+	}
+	if limit > MaxContentPageChars {
+		limit = MaxContentPageChars
+	}
+
+	page := &models.DocumentContentPage{
+		Offset:      offset,
+		TotalLength: utf8.RuneCountInString(text),
+	}
+	// Walk to the byte positions of the page's first and last characters
+	start, end, n := len(text), len(text), 0
+	for i := range text {
+		if n == offset {
+			start = i
+		}
+		if n == offset+limit {
+			end = i
+			break
+		}
+		n++
+	}
+	if start > end {
+		start = end
+	}
+	page.Text = text[start:end]
+	page.Length = utf8.RuneCountInString(page.Text)
+	if next := offset + page.Length; page.Length > 0 && next < page.TotalLength {
+		page.HasMore = true
+		page.NextOffset = &next
+	}
+	return page
+}
+
+// Chunks returns a page of the chunks of a document a user may read, with
+// the number left out of the page by the notebook's redaction rules. It is
+// checked that the user can read the document.
+func (s *DocumentContentService) Chunks(ctx context.Context, documentID string, limit, offset int, userID string, spaceCtx *models.SpaceContext) (*ChunksResponse, int, error) {
+	if s.chunks == nil {
+		return nil, 0, errors.ServiceUnavailable("Document chunks are not available")
+	}
+	document, err := s.documents.GetDocumentByID(ctx, documentID, userID, spaceCtx)
+	if err != nil {
+		return nil, 0, err
+	}
+	rules, err := s.rules(ctx, document, userID, spaceCtx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	page, err := s.chunks.GetFileChunks(ctx, document.TenantID, documentFileID(document), limit, offset)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, 0, errors.FileNotProcessedWithDetails("Document has not been processed or has no chunks", map[string]interface{}{
+				"document_id": documentID,
+				"status":      document.Status,
+			})
+		}
+		return nil, 0, errors.ExternalService("Failed to read the chunks of the document", err)
+	}
+	if rules == nil {
+		return page, 0, nil
+	}
+
+	kept := make([]ChunkData, 0, len(page.Data))
+	redacted := 0
+	for _, chunk := range page.Data {
+		if rules.Omits(chunk.PIIDetected, models.ChunkSection(chunk.Context, chunk.Metadata)) {
+			redacted++
+			continue
+		}
+		kept = append(kept, chunk)
+	}
+	filtered := *page
+	filtered.Data = kept
+	return &filtered, redacted, nil
+}
+
+// rules returns the redaction rules that apply to a user reading a
+// document, or nil when none do
+func (s *DocumentContentService) rules(ctx context.Context, document *models.Document, userID string, spaceCtx *models.SpaceContext) (*models.RedactionRules, error) {
+	if s.redaction == nil {
+		return nil, nil
+	}
+	return s.redaction.RulesFor(ctx, document, userID, spaceCtx)
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageText(t *testing.T) {
+	text := "Größe — naïve café"
+
+	page := pageText(text, 0, 5)
+	assert.Equal(t, "Größe", page.Text)
+	assert.Equal(t, 5, page.Length)
+	assert.Equal(t, 18, page.TotalLength)
+	assert.True(t, page.HasMore)
+	require.NotNil(t, page.NextOffset)
+	assert.Equal(t, 5, *page.NextOffset)
+
+	// Pages never split a character
+	page = pageText(text, *page.NextOffset, 8)
+	assert.Equal(t, " — naïve", page.Text)
+	assert.Equal(t, 13, *page.NextOffset)
+
+	page = pageText(text, 13, 100)
+	assert.Equal(t, " café", page.Text)
+	assert.False(t, page.HasMore)
+	assert.Nil(t, page.NextOffset)
+
+	page = pageText(text, 40, 10)
+	assert.Empty(t, page.Text)
+	assert.Equal(t, 0, page.Length)
+	assert.False(t, page.HasMore)
+
+	page = pageText("", 0, 10)
+	assert.Empty(t, page.Text)
+	assert.Equal(t, 0, page.TotalLength)
+}
+
+func TestPageTextLimits(t *testing.T) {
+	text := strings.Repeat("a", MaxContentPageChars+10)
+
+	page := pageText(text, 0, 0)
+	assert.Equal(t, DefaultContentPageChars, page.Length)
+
+	page = pageText(text, 0, MaxContentPageChars*2)
+	assert.Equal(t, MaxContentPageChars, page.Length)
+	assert.True(t, page.HasMore)
+}
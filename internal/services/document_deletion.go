@@ -0,0 +1,500 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// Document deletion limits
+const (
+	// documentDeletionClaimBatch bounds how many deletions one poll runs
+	documentDeletionClaimBatch = 10
+	// documentDeletionLease is how long a claimed deletion belongs to the
+	// run that claimed it before it is assumed abandoned
+	documentDeletionLease = 10 * time.Minute
+	// documentDeletionMaxAttempts is how many times a target is tried
+	// before the deletion is marked failed for an operator
+	documentDeletionMaxAttempts = 10
+	// documentDeletionRetention is how long completed deletions are kept
+	documentDeletionRetention = 7 * 24 * time.Hour
+)
+
+// DeletionFileStore deletes processed files and their chunks from the
+// processing provider that made them. ProcessingProviderRegistry
+// implements it.
+type DeletionFileStore interface {
+	DeleteArtifacts(ctx context.Context, provider, tenantID, fileID string) error
+}
+
+// DocumentVectorRemover deletes the vectors of a deleted or reprocessed
+// document. VectorSyncService implements it.
+type DocumentVectorRemover interface {
+	RemoveDocumentVectors(ctx context.Context, documentID string) error
+	// RemoveStaleVectors deletes a document's vectors unless they were
+	// synced again after its VectorSync node had the given generation
+	RemoveStaleVectors(ctx context.Context, documentID string, generation int64) error
+}
+
+// DeletionOrchestrator removes the copies of deleted documents held outside
+// Neo4j: the processed file and chunks, the vectors and the stored files.
+// DocumentService records a DocumentDeletion node in the query that deletes
+// the document, so no copy is forgotten if this replica stops. Each target
+// is deleted on its own and its outcome recorded; failed targets are
+// retried with backoff by the leader's document_deletions job until they
+// succeed or run out of attempts. The same ledger recycles the artifacts a
+// reprocessed document's previous processing left behind (see
+// artifact_recycle.go).
+type DeletionOrchestrator struct {
+	neo4j   *database.Neo4jClient
+	storage StorageService
+	files   DeletionFileStore
+	vectors DocumentVectorRemover
+	logger  *logger.Logger
+}
+
+// NewDeletionOrchestrator creates a deletion orchestrator. Targets whose
+// service is nil are not deleted.
+func NewDeletionOrchestrator(neo4j *database.Neo4jClient, storage StorageService, files DeletionFileStore, log *logger.Logger) *DeletionOrchestrator {
+	return &DeletionOrchestrator{
+		neo4j:   neo4j,
+		storage: storage,
+		files:   files,
+		logger:  log.WithService("deletion_orchestrator"),
+	}
+}
+
+// SetVectorRemover makes deletions remove documents' vectors too
+func (o *DeletionOrchestrator) SetVectorRemover(vectors DocumentVectorRemover) {
+	o.vectors = vectors
+}
+
+// documentDeletionFields are the properties returned for a
+// DocumentDeletion node x
+const documentDeletionFields = `
+	x.id AS id, x.document_id AS document_id, x.tenant_id AS tenant_id,
+	coalesce(x.reason, 'deleted') AS reason, x.vector_generation AS vector_generation,
+	x.status AS status, x.targets AS targets, x.file_id AS file_id,
+	x.processing_provider AS processing_provider,
+	x.storage_keys AS storage_keys, x.next_attempt_at AS next_attempt_at,
+	x.created_at AS created_at, x.updated_at AS updated_at,
+	x.completed_at AS completed_at
+`
+
+// createDocumentDeletionClause records the deletion of document d in the
+// query that deletes it. It follows a clause that carries version_paths,
+// the storage keys of the document's versions, which are deleted with the
+// key of its current file.
+const createDocumentDeletionClause = `
+	CREATE (x:DocumentDeletion {
+		id: $deletion_id, document_id: $document_id, tenant_id: $tenant_id,
+		reason: $deletion_reason, status: $deletion_status, targets: $deletion_targets, file_id: $deletion_file_id,
+		processing_provider: $deletion_processing_provider,
+		next_attempt_at: $deletion_now, created_at: $deletion_now, updated_at: $deletion_now
+	})
+	SET x.storage_keys = reduce(keys = [], key IN [$deletion_storage_key] + version_paths |
+		CASE WHEN key IS NULL OR key = '' OR key IN keys THEN keys ELSE keys + key END)
+`
+
+// deletionWork is a claimed deletion with what its targets delete
+type deletionWork struct {
+	deletion    *models.DocumentDeletion
+	fileID      string   // Processed file of the document's current version
+	provider    string   // Processing provider that has the file
+	storageKeys []string // Keys of the files of every version
+	// vectorGeneration is the generation of a reprocessed document's
+	// VectorSync node when it was reprocessed, -1 when it had none
+	vectorGeneration int64
+}
+
+// plan returns the deletion of a document and the parameters of
+// createDocumentDeletionClause recording it. fileID is the document's
+// processed file and storageKey the key of its current file.
+func (o *DeletionOrchestrator) plan(document *models.Document, fileID, storageKey string) (*models.DocumentDeletion, map[string]interface{}) {
+	now := time.Now().UTC()
+	deletion := &models.DocumentDeletion{
+		ID:            uuid.New().String(),
+		DocumentID:    document.ID,
+		TenantID:      document.TenantID,
+		Reason:        models.DocumentDeletionReasonDeleted,
+		FileID:        fileID,
+		Status:        models.DocumentDeletionPending,
+		NextAttemptAt: &now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if o.files != nil && fileID != "" {
+		deletion.Targets = append(deletion.Targets, &models.DeletionTarget{Name: models.DeletionTargetAudiModal, Status: models.DeletionTargetPending})
+	}
+	if o.vectors != nil {
+		deletion.Targets = append(deletion.Targets, &models.DeletionTarget{Name: models.DeletionTargetVectors, Status: models.DeletionTargetPending})
+	}
+	if o.storage != nil {
+		deletion.Targets = append(deletion.Targets, &models.DeletionTarget{Name: models.DeletionTargetStorage, Status: models.DeletionTargetPending})
+	}
+
+	return deletion, map[string]interface{}{
+		"deletion_id":                  deletion.ID,
+		"deletion_reason":              deletion.Reason,
+		"deletion_status":              deletion.Status,
+		"deletion_targets":             encodeDeletionTargets(deletion.Targets),
+		"deletion_file_id":             fileID,
+		"deletion_processing_provider": document.ProcessingProvider,
+		"deletion_storage_key":         storageKey,
+		"deletion_now":                 now,
+	}
+}
+
+// Run deletes the targets of a recorded deletion now rather than on the
+// next poll, as DeleteDocument does once the document is gone. Targets
+// that fail are left to the poll.
+func (o *DeletionOrchestrator) Run(ctx context.Context, deletionID string) {
+	ctx = context.WithoutCancel(ctx)
+	works, err := o.claim(ctx, deletionID)
+	if err != nil {
+		o.logger.Error("Failed to claim document deletion", zap.String("deletion_id", deletionID), zap.Error(err))
+		return
+	}
+	for _, work := range works {
+		o.execute(ctx, work)
+	}
+}
+
+// ProcessDue runs the deletions whose next attempt is due and forgets
+// completed ones past their retention. It is a singleton job run by the
+// leader replica.
+func (o *DeletionOrchestrator) ProcessDue(ctx context.Context) error {
+	works, err := o.claim(ctx, "")
+	if err != nil {
+		return err
+	}
+	for _, work := range works {
+		if ctx.Err() != nil {
+			// Shutting down: the lease hands the rest to the next leader
+			return ctx.Err()
+		}
+		o.execute(ctx, work)
+	}
+
+	_, err = o.neo4j.ExecuteQuery(database.WithQueryName(ctx, "document_deletion.purge"), `
+		MATCH (x:DocumentDeletion {status: $completed})
+		WHERE x.completed_at < $cutoff
+		DELETE x
+	`, map[string]interface{}{
+		"completed": models.DocumentDeletionCompleted,
+		"cutoff":    time.Now().UTC().Add(-documentDeletionRetention),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to purge completed document deletions: %w", err)
+	}
+	return nil
+}
+
+// claim leases the due pending deletions, or the one of deletionID when
+// it is due. The claim re-checks the due time under the node's write
+// lock, so overlapping runs do not claim the same deletion.
+func (o *DeletionOrchestrator) claim(ctx context.Context, deletionID string) ([]*deletionWork, error) {
+	now := time.Now().UTC()
+	result, err := o.neo4j.ExecuteQuery(database.WithQueryName(ctx, "document_deletion.claim"), `
+		MATCH (x:DocumentDeletion {status: $pending})
+		WHERE x.next_attempt_at <= $now AND ($id = '' OR x.id = $id)
+		WITH x ORDER BY x.next_attempt_at LIMIT $limit
+		SET x._claim_lock = true
+		REMOVE x._claim_lock
+		WITH x WHERE x.next_attempt_at <= $now
+		SET x.next_attempt_at = $lease_until, x.updated_at = $now
+		RETURN `+documentDeletionFields, map[string]interface{}{
+		"pending":     models.DocumentDeletionPending,
+		"id":          deletionID,
+		"now":         now,
+		"lease_until": now.Add(documentDeletionLease),
+		"limit":       documentDeletionClaimBatch,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim document deletions: %w", err)
+	}
+
+	works := make([]*deletionWork, 0, len(result.Records))
+	for _, record := range result.Records {
+		work, err := recordToDeletionWork(record)
+		if err != nil {
+			o.logger.Error("Skipped unreadable document deletion", zap.String("deletion_id", recordString(record, "id")), zap.Error(err))
+			continue
+		}
+		works = append(works, work)
+	}
+	return works, nil
+}
+
+// execute deletes the pending targets of a claimed deletion and records
+// the outcome of each
+func (o *DeletionOrchestrator) execute(ctx context.Context, work *deletionWork) {
+	deletion := work.deletion
+	retry := 0
+	for _, target := range deletion.Targets {
+		if target.Status != models.DeletionTargetPending {
+			continue
+		}
+		err := o.deleteTarget(ctx, work, target.Name)
+		if stderrors.Is(err, errArtifactsInUse) {
+			target.Status, target.Error = models.DeletionTargetSkipped, err.Error()
+			continue
+		}
+		if err != nil {
+			target.Attempts++
+			target.Error = err.Error()
+			if target.Attempts >= documentDeletionMaxAttempts {
+				target.Status = models.DeletionTargetFailed
+			} else if target.Attempts > retry {
+				retry = target.Attempts
+			}
+			o.logger.FromContext(ctx).Warn("Failed to delete document copies",
+				zap.String("deletion_id", deletion.ID),
+				zap.String("document_id", deletion.DocumentID),
+				zap.String("target", target.Name),
+				zap.Int("attempts", target.Attempts),
+				zap.String("status", target.Status),
+				zap.Error(err))
+			continue
+		}
+		deletedAt := time.Now().UTC()
+		target.Status, target.Error, target.DeletedAt = models.DeletionTargetDeleted, "", &deletedAt
+	}
+
+	now := time.Now().UTC()
+	deletion.UpdatedAt = now
+	deletion.NextAttemptAt = nil
+	switch {
+	case retry > 0:
+		next := now.Add(vectorSyncBackoff(int64(retry)))
+		deletion.NextAttemptAt = &next
+	case deletionTargetsFailed(deletion.Targets):
+		deletion.Status = models.DocumentDeletionFailed
+		o.logger.Error("Gave up deleting document copies",
+			zap.String("deletion_id", deletion.ID),
+			zap.String("document_id", deletion.DocumentID))
+	default:
+		deletion.Status = models.DocumentDeletionCompleted
+		deletion.CompletedAt = &now
+	}
+
+	if err := o.store(ctx, deletion); err != nil {
+		// The lease expires and the deletion is claimed again; every
+		// target tolerates copies that are already gone
+		o.logger.Error("Failed to record document deletion", zap.String("deletion_id", deletion.ID), zap.Error(err))
+	}
+}
+
+// deleteTarget deletes a document's copies from one target. Copies that
+// are already gone count as deleted, so a target can be retried.
+func (o *DeletionOrchestrator) deleteTarget(ctx context.Context, work *deletionWork, target string) error {
+	deletion := work.deletion
+	switch target {
+	case models.DeletionTargetAudiModal:
+		if o.files == nil {
+			return fmt.Errorf("processing service is not configured")
+		}
+		if deletion.Reason == models.DocumentDeletionReasonReprocessed {
+			return o.recycleFile(ctx, work)
+		}
+		if err := o.files.DeleteArtifacts(ctx, work.provider, deletion.TenantID, work.fileID); err != nil {
+			return err
+		}
+		// Chunks kept in Neo4j name the processed file or the document;
+		// synced chunks also name the document by ID
+		_, err := o.neo4j.ExecuteQuery(database.WithQueryName(ctx, "document_deletion.chunks"), `
+			MATCH (c:Chunk {tenant_id: $tenant_id})
+			WHERE c.file_id IN $file_ids OR c.document_id = $document_id
+			DETACH DELETE c
+		`, map[string]interface{}{
+			"tenant_id":   deletion.TenantID,
+			"file_ids":    []string{work.fileID, deletion.DocumentID},
+			"document_id": deletion.DocumentID,
+		})
+		return err
+	case models.DeletionTargetVectors:
+		if o.vectors == nil {
+			return fmt.Errorf("vector store is not configured")
+		}
+		if deletion.Reason == models.DocumentDeletionReasonReprocessed {
+			if work.vectorGeneration < 0 {
+				// The document had no vectors when it was reprocessed
+				return nil
+			}
+			return o.vectors.RemoveStaleVectors(ctx, deletion.DocumentID, work.vectorGeneration)
+		}
+		return o.vectors.RemoveDocumentVectors(ctx, deletion.DocumentID)
+	case models.DeletionTargetStorage:
+		if o.storage == nil {
+			return fmt.Errorf("storage service is not configured")
+		}
+		for _, key := range work.storageKeys {
+			if err := o.storage.DeleteFileFromTenantBucket(ctx, deletion.TenantID, key); err != nil {
+				return fmt.Errorf("failed to delete %s: %w", key, err)
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown deletion target %s", target)
+}
+
+// store saves the outcome of a deletion's run
+func (o *DeletionOrchestrator) store(ctx context.Context, deletion *models.DocumentDeletion) error {
+	_, err := o.neo4j.ExecuteQuery(database.WithQueryName(ctx, "document_deletion.store"), `
+		MATCH (x:DocumentDeletion {id: $id})
+		SET x.status = $status, x.targets = $targets, x.next_attempt_at = $next_attempt_at,
+		    x.updated_at = $updated_at, x.completed_at = $completed_at
+	`, map[string]interface{}{
+		"id":              deletion.ID,
+		"status":          deletion.Status,
+		"targets":         encodeDeletionTargets(deletion.Targets),
+		"next_attempt_at": optionalTime(deletion.NextAttemptAt),
+		"updated_at":      deletion.UpdatedAt,
+		"completed_at":    optionalTime(deletion.CompletedAt),
+	})
+	return err
+}
+
+// ListDeletions lists document deletions, most recently updated first,
+// optionally in one status and for one reason
+func (o *DeletionOrchestrator) ListDeletions(ctx context.Context, status, reason string, limit, offset int) ([]*models.DocumentDeletion, bool, error) {
+	result, err := o.neo4j.ExecuteQuery(database.WithQueryName(ctx, "document_deletion.list"), `
+		MATCH (x:DocumentDeletion)
+		WHERE ($status = '' OR x.status = $status)
+		  AND ($reason = '' OR coalesce(x.reason, 'deleted') = $reason)
+		RETURN `+documentDeletionFields+`
+		ORDER BY x.updated_at DESC
+		SKIP $offset LIMIT $limit
+	`, map[string]interface{}{
+		"status": status,
+		"reason": reason,
+		"offset": offset,
+		"limit":  limit + 1,
+	})
+	if err != nil {
+		return nil, false, errors.Database("Failed to list document deletions", err)
+	}
+
+	deletions := make([]*models.DocumentDeletion, 0, len(result.Records))
+	for _, record := range result.Records {
+		work, err := recordToDeletionWork(record)
+		if err != nil {
+			return nil, false, errors.Internal("Failed to read document deletion")
+		}
+		deletions = append(deletions, work.deletion)
+	}
+	deletions, hasMore := pagination.Trim(deletions, limit)
+	return deletions, hasMore, nil
+}
+
+// RetryDeletion makes the failed targets of a deletion pending again and
+// schedules it for the next poll
+func (o *DeletionOrchestrator) RetryDeletion(ctx context.Context, deletionID string) (*models.DocumentDeletion, error) {
+	result, err := o.neo4j.ExecuteQuery(database.WithQueryName(ctx, "document_deletion.get"), `
+		MATCH (x:DocumentDeletion {id: $id})
+		RETURN `+documentDeletionFields, map[string]interface{}{"id": deletionID})
+	if err != nil {
+		return nil, errors.Database("Failed to get document deletion", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, errors.NotFound("Document deletion not found").WithErrorCode(errors.CodeDocumentDeletionNotFound)
+	}
+	work, err := recordToDeletionWork(result.Records[0])
+	if err != nil {
+		return nil, errors.Internal("Failed to read document deletion")
+	}
+
+	deletion := work.deletion
+	if deletion.Status != models.DocumentDeletionFailed {
+		return deletion, nil
+	}
+	for _, target := range deletion.Targets {
+		if target.Status == models.DeletionTargetFailed {
+			target.Status, target.Attempts = models.DeletionTargetPending, 0
+		}
+	}
+	now := time.Now().UTC()
+	deletion.Status = models.DocumentDeletionPending
+	deletion.NextAttemptAt = &now
+	deletion.UpdatedAt = now
+	if err := o.store(ctx, deletion); err != nil {
+		return nil, errors.Database("Failed to retry document deletion", err)
+	}
+	return deletion, nil
+}
+
+// deletionTargetsFailed reports whether a target gave up
+func deletionTargetsFailed(targets []*models.DeletionTarget) bool {
+	for _, target := range targets {
+		if target.Status == models.DeletionTargetFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeDeletionTargets encodes targets as the JSON string stored on the
+// DocumentDeletion node
+func encodeDeletionTargets(targets []*models.DeletionTarget) string {
+	encoded, err := json.Marshal(targets)
+	if err != nil {
+		return "[]"
+	}
+	return string(encoded)
+}
+
+// recordToDeletionWork reads the documentDeletionFields of a record
+func recordToDeletionWork(record *neo4j.Record) (*deletionWork, error) {
+	deletion := &models.DocumentDeletion{
+		ID:         recordString(record, "id"),
+		DocumentID: recordString(record, "document_id"),
+		TenantID:   recordString(record, "tenant_id"),
+		Reason:     recordString(record, "reason"),
+		FileID:     recordString(record, "file_id"),
+		Status:     recordString(record, "status"),
+		CreatedAt:  recordTime(record, "created_at"),
+		UpdatedAt:  recordTime(record, "updated_at"),
+	}
+	if targets := recordString(record, "targets"); targets != "" {
+		if err := json.Unmarshal([]byte(targets), &deletion.Targets); err != nil {
+			return nil, err
+		}
+	}
+	if at := recordTime(record, "next_attempt_at"); !at.IsZero() {
+		deletion.NextAttemptAt = &at
+	}
+	if at := recordTime(record, "completed_at"); !at.IsZero() {
+		deletion.CompletedAt = &at
+	}
+
+	work := &deletionWork{
+		deletion:         deletion,
+		fileID:           recordString(record, "file_id"),
+		provider:         recordString(record, "processing_provider"),
+		vectorGeneration: -1,
+	}
+	if value, ok := record.Get("vector_generation"); ok && value != nil {
+		work.vectorGeneration = recordInt64(record, "vector_generation")
+	}
+	if value, ok := record.Get("storage_keys"); ok {
+		keys, _ := value.([]interface{})
+		for _, key := range keys {
+			if name, ok := key.(string); ok {
+				work.storageKeys = append(work.storageKeys, name)
+			}
+		}
+	}
+	return work, nil
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+// deletingStorage records the keys deleted from tenant buckets, failing
+// for the key in fail
+type deletingStorage struct {
+	StorageService
+	deleted []string
+	fail    string
+}
+
+func (s *deletingStorage) DeleteFileFromTenantBucket(ctx context.Context, tenantID, key string) error {
+	if key == s.fail {
+		return stderrors.New("storage unavailable")
+	}
+	s.deleted = append(s.deleted, tenantID+"/"+key)
+	return nil
+}
+
+// deletingFiles is a processing provider's file store
+type deletingFiles struct{}
+
+func (f *deletingFiles) DeleteArtifacts(ctx context.Context, provider, tenantID, fileID string) error {
+	return nil
+}
+
+// removingVectors records the documents whose vectors were removed
+type removingVectors struct {
+	removed []string
+}
+
+func (v *removingVectors) RemoveDocumentVectors(ctx context.Context, documentID string) error {
+	v.removed = append(v.removed, documentID)
+	return nil
+}
+
+func (v *removingVectors) RemoveStaleVectors(ctx context.Context, documentID string, generation int64) error {
+	v.removed = append(v.removed, fmt.Sprintf("%s@%d", documentID, generation))
+	return nil
+}
+
+func TestDeletionPlanTargetsConfiguredServices(t *testing.T) {
+	document := &models.Document{ID: "doc-1", TenantID: "tenant-1"}
+
+	orchestrator := NewDeletionOrchestrator(nil, &deletingStorage{}, nil, setupTestLogger(t))
+	deletion, params := orchestrator.plan(document, "file-1", "key-1")
+	require.Len(t, deletion.Targets, 1, "AudiModal and vectors are not configured")
+	assert.Equal(t, models.DeletionTargetStorage, deletion.Targets[0].Name)
+	assert.Equal(t, models.DocumentDeletionPending, deletion.Status)
+	assert.Equal(t, deletion.ID, params["deletion_id"])
+	assert.Equal(t, "key-1", params["deletion_storage_key"])
+
+	orchestrator.SetVectorRemover(&removingVectors{})
+	deletion, _ = orchestrator.plan(document, "", "key-1")
+	names := make([]string, 0, len(deletion.Targets))
+	for _, target := range deletion.Targets {
+		names = append(names, target.Name)
+	}
+	assert.Equal(t, []string{models.DeletionTargetVectors, models.DeletionTargetStorage}, names)
+}
+
+func TestDeletionTargetStorageDeletesEveryVersion(t *testing.T) {
+	storage := &deletingStorage{}
+	orchestrator := NewDeletionOrchestrator(nil, storage, nil, setupTestLogger(t))
+	work := &deletionWork{
+		deletion:    &models.DocumentDeletion{ID: "deletion-1", DocumentID: "doc-1", TenantID: "tenant-1"},
+		storageKeys: []string{"documents/doc-1/v1.pdf", "documents/doc-1/versions/2/v2.pdf"},
+	}
+
+	require.NoError(t, orchestrator.deleteTarget(context.Background(), work, models.DeletionTargetStorage))
+	assert.Equal(t, []string{"tenant-1/documents/doc-1/v1.pdf", "tenant-1/documents/doc-1/versions/2/v2.pdf"}, storage.deleted)
+
+	storage.fail = "documents/doc-1/versions/2/v2.pdf"
+	assert.Error(t, orchestrator.deleteTarget(context.Background(), work, models.DeletionTargetStorage))
+}
+
+func TestDeletionTargetVectors(t *testing.T) {
+	orchestrator := NewDeletionOrchestrator(nil, nil, nil, setupTestLogger(t))
+	work := &deletionWork{deletion: &models.DocumentDeletion{DocumentID: "doc-1", TenantID: "tenant-1"}}
+	assert.Error(t, orchestrator.deleteTarget(context.Background(), work, models.DeletionTargetVectors), "no vector store is configured")
+
+	vectors := &removingVectors{}
+	orchestrator.SetVectorRemover(vectors)
+	require.NoError(t, orchestrator.deleteTarget(context.Background(), work, models.DeletionTargetVectors))
+	assert.Equal(t, []string{"doc-1"}, vectors.removed)
+}
+
+func TestDeletionTargetsFailed(t *testing.T) {
+	targets := []*models.DeletionTarget{
+		{Name: models.DeletionTargetStorage, Status: models.DeletionTargetDeleted},
+		{Name: models.DeletionTargetVectors, Status: models.DeletionTargetPending},
+	}
+	assert.False(t, deletionTargetsFailed(targets))
+
+	targets[1].Status = models.DeletionTargetFailed
+	assert.True(t, deletionTargetsFailed(targets))
+}
+
+func TestRecyclePlanTargetsEarlierArtifacts(t *testing.T) {
+	document := &models.Document{ID: "doc-1", TenantID: "tenant-1"}
+	orchestrator := NewDeletionOrchestrator(nil, &deletingStorage{}, &deletingFiles{}, setupTestLogger(t))
+	orchestrator.SetVectorRemover(&removingVectors{})
+
+	recycle := orchestrator.planRecycle(document, "file-1")
+	names := make([]string, 0, len(recycle.Targets))
+	for _, target := range recycle.Targets {
+		names = append(names, target.Name)
+	}
+	assert.Equal(t, []string{models.DeletionTargetAudiModal, models.DeletionTargetVectors}, names, "stored files are kept")
+	assert.Equal(t, models.DocumentDeletionReasonReprocessed, recycle.Reason)
+	assert.Equal(t, "file-1", recycle.FileID)
+	require.NotNil(t, recycle.NextAttemptAt)
+	assert.True(t, recycle.NextAttemptAt.After(recycle.CreatedAt), "held until it is started")
+
+	recycle = orchestrator.planRecycle(document, "")
+	require.Len(t, recycle.Targets, 1, "a document that keeps its file keeps its processed file")
+	assert.Equal(t, models.DeletionTargetVectors, recycle.Targets[0].Name)
+}
+
+func TestRecycleTargetVectors(t *testing.T) {
+	vectors := &removingVectors{}
+	orchestrator := NewDeletionOrchestrator(nil, nil, nil, setupTestLogger(t))
+	orchestrator.SetVectorRemover(vectors)
+	work := &deletionWork{
+		deletion:         &models.DocumentDeletion{DocumentID: "doc-1", TenantID: "tenant-1", Reason: models.DocumentDeletionReasonReprocessed},
+		vectorGeneration: -1,
+	}
+
+	require.NoError(t, orchestrator.deleteTarget(context.Background(), work, models.DeletionTargetVectors))
+	assert.Empty(t, vectors.removed, "the document had no vectors")
+
+	work.vectorGeneration = 3
+	require.NoError(t, orchestrator.deleteTarget(context.Background(), work, models.DeletionTargetVectors))
+	assert.Equal(t, []string{"doc-1@3"}, vectors.removed, "only vectors of the recorded generation")
+}
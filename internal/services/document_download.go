@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// Document download modes
+const (
+	// DownloadModeAuto redirects files from the configured size and
+	// streams the rest
+	DownloadModeAuto     = "auto"
+	DownloadModeStream   = "stream"
+	DownloadModeRedirect = "redirect"
+)
+
+// singleByteRangePattern matches a Range header naming one byte range
+var singleByteRangePattern = regexp.MustCompile(`^bytes=(\d+-\d*|-\d+)$`)
+
+// DocumentDownload is the file of a document being downloaded: opened for
+// streaming, or presigned for the client to fetch from storage itself
+type DocumentDownload struct {
+	Document *models.Document
+	// File is the file, or the range of it, to stream; nil when the file
+	// was not modified or is redirected
+	File *FileReader
+	// NotModified is set when the file still has the ETag the client holds
+	NotModified bool
+	// URL is the presigned URL to redirect to, valid until ExpiresAt
+	URL       string
+	ExpiresAt time.Time
+}
+
+// SetDownloads sets which document files are redirected to presigned URLs
+// rather than streamed, and how long the URLs stay valid
+func (s *DocumentService) SetDownloads(cfg config.DownloadConfig) {
+	s.downloads = cfg
+}
+
+// SingleByteRange returns a Range header value when it names a single byte
+// range, and "" otherwise, so other ranges are answered with the whole file
+func SingleByteRange(header string) string {
+	header = strings.TrimSpace(header)
+	if !singleByteRangePattern.MatchString(header) {
+		return ""
+	}
+	return header
+}
+
+// DownloadDocumentFile opens the file of a document the user can read for
+// streaming, whole or by the byte range opts names, without loading it into
+// memory. In redirect mode, or in auto mode for files from the configured
+// size, it presigns a URL to the file instead.
+func (s *DocumentService) DownloadDocumentFile(ctx context.Context, documentID, userID string, spaceCtx *models.SpaceContext, mode string, opts FileReadOptions) (*DocumentDownload, error) {
+	switch mode {
+	case "", DownloadModeAuto, DownloadModeStream, DownloadModeRedirect:
+	default:
+		return nil, errors.ValidationWithDetails("Invalid download mode", map[string]interface{}{
+			"mode":    mode,
+			"allowed": []string{DownloadModeAuto, DownloadModeStream, DownloadModeRedirect},
+		})
+	}
+
+	document, err := s.GetDocumentByID(ctx, documentID, userID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+	if s.storageService == nil {
+		return nil, errors.ServiceUnavailable("Storage service not configured")
+	}
+	key := document.StoragePath
+	if bucket, path, ok := strings.Cut(key, ":"); ok && bucket != "" {
+		// Stored as "bucket:key"; older documents store the key alone
+		key = path
+	}
+	if key == "" {
+		return nil, errors.NotFoundWithDetails("Document file not available for download", map[string]interface{}{
+			"document_id": documentID,
+		})
+	}
+
+	download := &DocumentDownload{Document: document}
+	redirect := mode == DownloadModeRedirect ||
+		(mode != DownloadModeStream && s.downloads.RedirectBytes > 0 && document.SizeBytes >= s.downloads.RedirectBytes)
+	if redirect {
+		expiration := time.Duration(s.downloads.URLSeconds) * time.Second
+		url, err := s.storageService.PresignTenantDownload(ctx, spaceCtx.TenantID, key, document.OriginalName, expiration)
+		if err != nil {
+			return nil, errors.ExternalService("Failed to presign download", err)
+		}
+		download.URL, download.ExpiresAt = url, time.Now().Add(expiration).UTC()
+		return download, nil
+	}
+
+	if !strings.HasPrefix(opts.IfRange, `"`) {
+		// Only a strong ETag can tell that the range is of the file the
+		// client holds; dates and weak tags get the whole file
+		if opts.IfRange != "" {
+			opts.Range = ""
+		}
+		opts.IfRange = ""
+	}
+	download.File, err = s.storageService.ReadTenantFile(ctx, spaceCtx.TenantID, key, opts)
+	switch {
+	case stderrors.Is(err, ErrFileNotModified):
+		download.NotModified = true
+	case stderrors.Is(err, ErrRangeNotSatisfiable):
+		return nil, errors.NewAPIError(errors.ErrRangeNotSatisfiable, "Requested range not satisfiable", map[string]interface{}{
+			"range":      opts.Range,
+			"size_bytes": document.SizeBytes,
+		})
+	case err != nil:
+		s.logger.Error("Failed to read document file",
+			zap.String("document_id", documentID),
+			zap.String("key", key),
+			zap.Error(err))
+		return nil, errors.ExternalService("Failed to download file", err)
+	}
+	return download, nil
+}
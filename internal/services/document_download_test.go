@@ -0,0 +1,30 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+func TestSingleByteRange(t *testing.T) {
+	for _, header := range []string{"bytes=0-1023", "bytes=512-", "bytes=-256", " bytes=0-0 "} {
+		assert.NotEmpty(t, SingleByteRange(header), header)
+	}
+	for _, header := range []string{"", "bytes=0-1,4-5", "bytes=-", "items=0-10", "bytes=a-b"} {
+		assert.Empty(t, SingleByteRange(header), "%q is answered with the whole file", header)
+	}
+}
+
+func TestDownloadDocumentFileRejectsUnknownMode(t *testing.T) {
+	service := &DocumentService{}
+	_, err := service.DownloadDocumentFile(context.Background(), "doc-1", "user-1", nil, "fast", FileReadOptions{})
+	apiErr, ok := errors.AsAPIError(err)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, "fast", apiErr.Details["mode"])
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// documentGraphQuery finds the nodes within %d relationships of a document.
+// Paths stay inside the tenant: only the last node of a path may be a user,
+// so traversals cannot pass through a user into other tenants.
+const documentGraphQuery = `
+	MATCH (d:Document {id: $document_id, tenant_id: $tenant_id})
+	MATCH p = (d)-[*1..%d]-(n)
+	WHERE n <> d
+	  AND all(x IN tail(nodes(p)) WHERE (x.tenant_id = $tenant_id OR (x:User AND x = n)) AND %s)
+	WITH n, min(length(p)) AS distance
+	RETURN n.id AS id, head(labels(n)) AS type,
+	       coalesce(n.name, n.full_name, n.username) AS name, distance
+	ORDER BY distance, type, id
+	SKIP $offset
+	LIMIT $limit
+`
+
+// GetDocumentGraph returns one page of the nodes within depth relationships
+// of a document, nearest first. The depth is capped by the traversal limit,
+// and traversals the planner expects to touch too many rows are refused.
+func (s *DocumentService) GetDocumentGraph(ctx context.Context, documentID, userID string, spaceCtx *models.SpaceContext, depth, limit, offset int) (*models.DocumentGraphResponse, error) {
+	if _, err := s.GetDocumentByID(ctx, documentID, userID, spaceCtx); err != nil {
+		return nil, err
+	}
+
+	depth = s.neo4j.ClampDepth(depth)
+	limit, offset = pagination.Clamp(limit, offset)
+
+	ctx = database.WithQueryName(ctx, "document.graph")
+	query := fmt.Sprintf(documentGraphQuery, depth, database.SoftDeleteFilter(ctx, "x"))
+	params := map[string]interface{}{
+		"document_id": documentID,
+		"tenant_id":   spaceCtx.TenantID,
+		"offset":      offset,
+		"limit":       limit + 1,
+	}
+
+	if err := s.neo4j.CheckQueryCost(ctx, query, params); err != nil {
+		return nil, traversalError(err, map[string]interface{}{
+			"document_id": documentID,
+			"depth":       depth,
+		})
+	}
+
+	result, err := s.neo4j.ExecuteQuery(ctx, query, params)
+	if err != nil {
+		s.logger.Error("Failed to get document graph", zap.String("document_id", documentID), zap.Error(err))
+		return nil, errors.Database("Failed to retrieve document graph", err)
+	}
+
+	nodes := make([]*models.DocumentGraphNode, 0, len(result.Records))
+	for _, record := range result.Records {
+		node := &models.DocumentGraphNode{}
+		if val, ok := record.Get("id"); ok && val != nil {
+			node.ID, _ = val.(string)
+		}
+		if val, ok := record.Get("type"); ok && val != nil {
+			node.Type, _ = val.(string)
+		}
+		if val, ok := record.Get("name"); ok && val != nil {
+			node.Name, _ = val.(string)
+		}
+		if val, ok := record.Get("distance"); ok && val != nil {
+			if distance, ok := val.(int64); ok {
+				node.Distance = int(distance)
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	nodes, hasMore := pagination.Trim(nodes, limit)
+
+	return &models.DocumentGraphResponse{
+		DocumentID: documentID,
+		Depth:      depth,
+		Nodes:      nodes,
+		Limit:      limit,
+		Offset:     offset,
+		HasMore:    hasMore,
+	}, nil
+}
+
+// traversalError turns a refused traversal into a 422 telling the client to
+// narrow the request; other errors are returned unchanged
+func traversalError(err error, details map[string]interface{}) error {
+	var costErr *database.QueryCostError
+	if !stderrors.As(err, &costErr) {
+		return err
+	}
+	details["estimated_rows"] = costErr.EstimatedRows
+	details["max_rows"] = costErr.MaxRows
+	return errors.NewAPIError(errors.ErrUnprocessableEntity,
+		"The request would traverse too much of the graph; lower the depth or narrow the request", details).
+		WithErrorCode(errors.CodeQueryTooExpensive)
+}
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+func TestTraversalErrorMapsRefusedQueries(t *testing.T) {
+	refused := fmt.Errorf("graph: %w", &database.QueryCostError{Name: "document.graph", EstimatedRows: 500000, MaxRows: 100000})
+
+	err := traversalError(refused, map[string]interface{}{"depth": 3})
+	apiErr, ok := err.(*errors.APIError)
+	require.True(t, ok)
+	assert.Equal(t, errors.CodeQueryTooExpensive, apiErr.ErrorCode)
+	assert.Equal(t, http.StatusUnprocessableEntity, errors.GetHTTPStatusCodeFromErrorCode(apiErr.Code))
+	assert.Equal(t, int64(500000), apiErr.Details["estimated_rows"])
+	assert.Equal(t, 3, apiErr.Details["depth"])
+
+	other := fmt.Errorf("connection refused")
+	assert.Same(t, other, traversalError(other, map[string]interface{}{}))
+}
@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// Document properties holding pipeline milestones, besides created_at and
+// processed_at
+const (
+	pipelineStoredAt        = "stored_at"
+	pipelineSubmitStartedAt = "submit_started_at"
+	pipelineSubmittedAt     = "submitted_at"
+	pipelineIndexedAt       = "indexed_at"
+)
+
+// pipelineStageDurations is the Cypher expression for each stage's duration
+// in milliseconds; it is null until the stage completes
+var pipelineStageDurations = map[string]string{
+	models.PipelineStageUpload:     "duration.inMilliseconds(d.created_at, d.stored_at).milliseconds",
+	models.PipelineStageSubmit:     "duration.inMilliseconds(d.submit_started_at, d.submitted_at).milliseconds",
+	models.PipelineStageProcessing: "duration.inMilliseconds(d.submitted_at, d.processed_at).milliseconds",
+	models.PipelineStageIndexing:   "duration.inMilliseconds(d.processed_at, d.indexed_at).milliseconds",
+}
+
+// pipelineSummaryTenantLimit caps the tenants in a pipeline summary, busiest first
+const pipelineSummaryTenantLimit = 100
+
+// recordPipelineTimes stores pipeline milestones on a document. The
+// timings are diagnostic, so a failure is logged rather than returned.
+func (s *DocumentService) recordPipelineTimes(ctx context.Context, documentID, tenantID string, times map[string]time.Time) {
+	properties := make([]string, 0, len(times))
+	for property := range times {
+		properties = append(properties, property)
+	}
+	sort.Strings(properties)
+
+	params := map[string]interface{}{
+		"document_id": documentID,
+		"tenant_id":   tenantID,
+	}
+	setClauses := make([]string, 0, len(properties))
+	for _, property := range properties {
+		setClauses = append(setClauses, fmt.Sprintf("d.%[1]s = datetime($%[1]s)", property))
+		params[property] = times[property].UTC().Format(time.RFC3339Nano)
+	}
+
+	query := fmt.Sprintf(`
+		MATCH (d:Document {id: $document_id, tenant_id: $tenant_id})
+		SET %s
+	`, strings.Join(setClauses, ", "))
+	if _, err := s.neo4j.ExecuteQueryWithLogging(ctx, query, params); err != nil {
+		s.logger.Warn("Failed to record document pipeline timing",
+			zap.String("document_id", documentID),
+			zap.Strings("milestones", properties),
+			zap.Error(err))
+	}
+}
+
+// GetDocumentPipeline returns the stage timings of a document the user can access
+func (s *DocumentService) GetDocumentPipeline(ctx context.Context, documentID, userID string, spaceCtx *models.SpaceContext) (*models.DocumentPipeline, error) {
+	document, err := s.GetDocumentByID(ctx, documentID, userID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		MATCH (d:Document {id: $document_id, tenant_id: $tenant_id})
+		RETURN d.id AS id, d.tenant_id AS tenant_id, d.status AS status,
+		       d.created_at AS created_at, d.stored_at AS stored_at,
+		       d.submit_started_at AS submit_started_at, d.submitted_at AS submitted_at,
+		       d.processed_at AS processed_at, d.indexed_at AS indexed_at
+	`
+	result, err := s.neo4j.ExecuteQueryWithLogging(ctx, query, map[string]interface{}{
+		"document_id": document.ID,
+		"tenant_id":   document.TenantID,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to get document pipeline", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, errors.NotFound("Document not found").WithErrorCode(errors.CodeDocumentNotFound)
+	}
+	return recordToDocumentPipeline(result.Records[0]), nil
+}
+
+// PipelineSummary summarizes stage timings per tenant for documents created
+// since the given time, and returns the slowest documents that finished
+// processing. An empty tenantID covers every tenant.
+func (s *DocumentService) PipelineSummary(ctx context.Context, since time.Time, tenantID string, slowest int) (*models.PipelineSummaryResponse, error) {
+	params := map[string]interface{}{
+		"since":        since.UTC().Format(time.RFC3339),
+		"tenant_id":    tenantID,
+		"tenant_limit": pipelineSummaryTenantLimit,
+		"slowest":      slowest,
+	}
+	filter := `
+		MATCH (d:Document)
+		WHERE d.created_at >= datetime($since)
+		  AND d.status <> 'deleted'
+		  AND ($tenant_id = '' OR d.tenant_id = $tenant_id)
+	`
+
+	durations := make([]string, 0, len(models.PipelineStages))
+	aggregates := make([]string, 0, len(models.PipelineStages)*5)
+	for _, stage := range models.PipelineStages {
+		// Clamped like NewDocumentPipeline; some milestones have second precision
+		durations = append(durations, fmt.Sprintf("CASE WHEN %[1]s < 0 THEN 0 ELSE %[1]s END AS %[2]s", pipelineStageDurations[stage], stage))
+		aggregates = append(aggregates, fmt.Sprintf(
+			"count(%[1]s) AS %[1]s_count, percentileCont(%[1]s, 0.5) AS %[1]s_p50, "+
+				"percentileCont(%[1]s, 0.95) AS %[1]s_p95, percentileCont(%[1]s, 0.99) AS %[1]s_p99, max(%[1]s) AS %[1]s_max",
+			stage))
+	}
+	summaryQuery := filter + fmt.Sprintf(`
+		WITH d.tenant_id AS tenant_id, %s
+		RETURN tenant_id, count(*) AS documents, %s
+		ORDER BY documents DESC
+		LIMIT $tenant_limit
+	`, strings.Join(durations, ", "), strings.Join(aggregates, ", "))
+
+	result, err := s.neo4j.ExecuteQueryWithLogging(ctx, summaryQuery, params)
+	if err != nil {
+		return nil, errors.Database("Failed to summarize document pipeline", err)
+	}
+
+	response := &models.PipelineSummaryResponse{
+		Since:   since.UTC(),
+		Tenants: make([]models.TenantPipelineSummary, 0, len(result.Records)),
+		Slowest: []*models.DocumentPipeline{},
+	}
+	for _, record := range result.Records {
+		summary := models.TenantPipelineSummary{}
+		if value, _ := record.Get("tenant_id"); value != nil {
+			summary.TenantID, _ = value.(string)
+		}
+		if value, _ := record.Get("documents"); value != nil {
+			summary.Documents, _ = value.(int64)
+		}
+		for _, stage := range models.PipelineStages {
+			stageSummary := models.PipelineStageSummary{Stage: stage}
+			if value, _ := record.Get(stage + "_count"); value != nil {
+				stageSummary.Documents, _ = value.(int64)
+			}
+			stageSummary.P50Ms = recordFloat(record, stage+"_p50")
+			stageSummary.P95Ms = recordFloat(record, stage+"_p95")
+			stageSummary.P99Ms = recordFloat(record, stage+"_p99")
+			stageSummary.MaxMs = recordFloat(record, stage+"_max")
+			summary.Stages = append(summary.Stages, stageSummary)
+		}
+		response.Tenants = append(response.Tenants, summary)
+	}
+
+	if slowest <= 0 {
+		return response, nil
+	}
+	slowestQuery := filter + `
+		  AND d.processed_at IS NOT NULL
+		RETURN d.id AS id, d.tenant_id AS tenant_id, d.status AS status,
+		       d.created_at AS created_at, d.stored_at AS stored_at,
+		       d.submit_started_at AS submit_started_at, d.submitted_at AS submitted_at,
+		       d.processed_at AS processed_at, d.indexed_at AS indexed_at
+		ORDER BY duration.inMilliseconds(d.created_at, coalesce(d.indexed_at, d.processed_at)).milliseconds DESC
+		LIMIT $slowest
+	`
+	result, err = s.neo4j.ExecuteQueryWithLogging(ctx, slowestQuery, params)
+	if err != nil {
+		return nil, errors.Database("Failed to find slowest documents", err)
+	}
+	for _, record := range result.Records {
+		response.Slowest = append(response.Slowest, recordToDocumentPipeline(record))
+	}
+	return response, nil
+}
+
+// recordToDocumentPipeline builds a document's pipeline from a record
+// returning its id, tenant, status and milestone timestamps
+func recordToDocumentPipeline(record *neo4j.Record) *models.DocumentPipeline {
+	text := func(key string) string {
+		value, _ := record.Get(key)
+		s, _ := value.(string)
+		return s
+	}
+	timestamp := func(key string) *time.Time {
+		value, _ := record.Get(key)
+		if t, ok := value.(time.Time); ok {
+			return &t
+		}
+		return nil
+	}
+
+	ts := models.PipelineTimestamps{
+		StoredAt:        timestamp(pipelineStoredAt),
+		SubmitStartedAt: timestamp(pipelineSubmitStartedAt),
+		SubmittedAt:     timestamp(pipelineSubmittedAt),
+		ProcessedAt:     timestamp("processed_at"),
+		IndexedAt:       timestamp(pipelineIndexedAt),
+	}
+	if created := timestamp("created_at"); created != nil {
+		ts.CreatedAt = *created
+	}
+	return models.NewDocumentPipeline(text("id"), text("tenant_id"), text("status"), ts)
+}
+
+// recordString reads a string record value, empty when null
+func recordString(record *neo4j.Record, key string) string {
+	value, _ := record.Get(key)
+	s, _ := value.(string)
+	return s
+}
+
+// recordInt64 reads an integer record value, zero when null
+func recordInt64(record *neo4j.Record, key string) int64 {
+	value, _ := record.Get(key)
+	n, _ := value.(int64)
+	return n
+}
+
+// recordFloat reads a numeric record value as a float, zero when null
+func recordFloat(record *neo4j.Record, key string) float64 {
+	value, _ := record.Get(key)
+	switch v := value.(type) {
+	case float64:
+		return v
+	case int64:
+		return float64(v)
+	}
+	return 0
+}
+
+// resultCount reads a count from a processing result, which holds ints
+// when built locally and float64s when decoded from JSON
+func resultCount(result map[string]interface{}, key string) int64 {
+	switch v := result[key].(type) {
+	case int:
+		return int64(v)
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+func TestRecordToDocumentPipeline(t *testing.T) {
+	created := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	record := &neo4j.Record{
+		Keys: []string{"id", "tenant_id", "status", "created_at", "stored_at",
+			"submit_started_at", "submitted_at", "processed_at", "indexed_at"},
+		Values: []interface{}{"doc-1", "tenant_1", "processed", created,
+			created.Add(800 * time.Millisecond),
+			created.Add(900 * time.Millisecond),
+			created.Add(1500 * time.Millisecond),
+			// processed_at has second precision and reads as before the submit finished
+			created.Add(time.Second),
+			nil},
+	}
+
+	pipeline := recordToDocumentPipeline(record)
+	assert.Equal(t, "doc-1", pipeline.DocumentID)
+	assert.Equal(t, "tenant_1", pipeline.TenantID)
+	require.Len(t, pipeline.Stages, len(models.PipelineStages))
+
+	durations := map[string]*int64{}
+	for _, stage := range pipeline.Stages {
+		durations[stage.Stage] = stage.DurationMs
+	}
+	require.NotNil(t, durations[models.PipelineStageUpload])
+	assert.Equal(t, int64(800), *durations[models.PipelineStageUpload])
+	assert.Equal(t, int64(600), *durations[models.PipelineStageSubmit])
+	assert.Equal(t, int64(0), *durations[models.PipelineStageProcessing])
+	assert.Nil(t, durations[models.PipelineStageIndexing], "indexing has not finished")
+	assert.Equal(t, int64(1400), pipeline.TotalMs)
+}
+
+func TestResultCount(t *testing.T) {
+	assert.Equal(t, int64(12), resultCount(map[string]interface{}{"embeddings_created": 12}, "embeddings_created"))
+	assert.Equal(t, int64(3), resultCount(map[string]interface{}{"embeddings_created": float64(3)}, "embeddings_created"))
+	assert.Zero(t, resultCount(nil, "embeddings_created"))
+}
@@ -0,0 +1,489 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// Defaults of reprocessing campaigns
+const (
+	defaultCampaignStatus = "failed"
+	defaultCampaignLimit  = 100
+)
+
+// retryJobFields are the properties returned for a ProcessingRetryJob j
+const retryJobFields = `
+	j.id as id, j.document_id as document_id, j.tenant_id as tenant_id,
+	j.campaign_id as campaign_id, j.retry_attempt as retry_attempt,
+	j.status as status, j.retry_at as retry_at, j.last_error as last_error,
+	j.created_at as created_at, j.updated_at as updated_at
+`
+
+// ListFailedRetryJobs lists the processing retry jobs that failed, most
+// recently failed first. They are not retried again unless requeued.
+func (s *DocumentService) ListFailedRetryJobs(ctx context.Context, limit, offset int) ([]*models.ProcessingRetryJob, bool, error) {
+	limit, offset = pagination.Clamp(limit, offset)
+	query := `
+		MATCH (j:ProcessingRetryJob {status: $status})
+		RETURN ` + retryJobFields + `
+		ORDER BY j.updated_at DESC
+		SKIP $offset
+		LIMIT $limit
+	`
+	result, err := s.neo4j.ExecuteQuery(ctx, query, map[string]interface{}{
+		"status": models.RetryJobStatusFailed,
+		"offset": offset,
+		"limit":  limit + 1,
+	})
+	if err != nil {
+		return nil, false, errors.Database("Failed to list failed retry jobs", err)
+	}
+
+	jobs := make([]*models.ProcessingRetryJob, 0, len(result.Records))
+	for _, record := range result.Records {
+		jobs = append(jobs, recordToRetryJob(record))
+	}
+	jobs, hasMore := pagination.Trim(jobs, limit)
+	return jobs, hasMore, nil
+}
+
+// RequeueRetryJob schedules a failed retry job to run on the next poll of
+// the retry queue
+func (s *DocumentService) RequeueRetryJob(ctx context.Context, jobID string) (*models.ProcessingRetryJob, error) {
+	now := time.Now().UTC()
+	query := `
+		MATCH (j:ProcessingRetryJob {id: $job_id, status: $failed})
+		SET j.status = $scheduled, j.retry_at = $now, j.updated_at = $now
+		RETURN ` + retryJobFields
+	result, err := s.neo4j.ExecuteQuery(ctx, query, map[string]interface{}{
+		"job_id":    jobID,
+		"failed":    models.RetryJobStatusFailed,
+		"scheduled": models.RetryJobStatusScheduled,
+		"now":       now,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to requeue retry job", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, errors.NotFoundWithDetails("Retry job not found", map[string]interface{}{
+			"job_id": jobID,
+		}).WithErrorCode(errors.CodeRetryJobNotFound)
+	}
+
+	s.logger.Info("Requeued failed processing retry job", zap.String("job_id", jobID))
+	return recordToRetryJob(result.Records[0]), nil
+}
+
+// StartReprocessingCampaign queues the documents matching the request for
+// reprocessing as retry jobs due now. The retry queue claims a bounded
+// batch per poll, so a large campaign is resubmitted gradually rather than
+// all at once. Documents with a pending retry job are skipped.
+func (s *DocumentService) StartReprocessingCampaign(ctx context.Context, req models.ReprocessCampaignRequest) (*models.ReprocessCampaignResponse, error) {
+	if req.Status == "" {
+		req.Status = defaultCampaignStatus
+	}
+	if req.Limit <= 0 {
+		req.Limit = defaultCampaignLimit
+	}
+	var createdAfter interface{}
+	if req.CreatedAfter != nil {
+		createdAfter = req.CreatedAfter.UTC().Format(time.RFC3339)
+	}
+
+	response := &models.ReprocessCampaignResponse{DryRun: req.DryRun, DocumentIDs: []string{}}
+	now := time.Now().UTC()
+	params := map[string]interface{}{
+		"status":        req.Status,
+		"tenant_id":     req.TenantID,
+		"created_after": createdAfter,
+		"limit":         req.Limit,
+		"pending":       []string{models.RetryJobStatusScheduled, models.RetryJobStatusInProgress},
+	}
+
+	query := `
+		MATCH (d:Document {status: $status})
+		WHERE ($tenant_id = '' OR d.tenant_id = $tenant_id)
+		  AND ($created_after IS NULL OR d.created_at >= datetime($created_after))
+		  AND NOT EXISTS {
+		      MATCH (j:ProcessingRetryJob {document_id: d.id})
+		      WHERE j.status IN $pending
+		  }
+		WITH d ORDER BY d.created_at LIMIT $limit
+	`
+	if !req.DryRun {
+		response.CampaignID = uuid.New().String()
+		params["campaign_id"] = response.CampaignID
+		params["now"] = now
+		query += `
+		CREATE (j:ProcessingRetryJob {
+			id: randomUUID(),
+			document_id: d.id,
+			tenant_id: d.tenant_id,
+			campaign_id: $campaign_id,
+			retry_attempt: 0,
+			status: 'scheduled',
+			retry_at: $now,
+			created_at: $now,
+			updated_at: $now
+		})
+		`
+	}
+	query += `RETURN d.id as document_id`
+
+	result, err := s.neo4j.ExecuteQuery(ctx, query, params)
+	if err != nil {
+		return nil, errors.Database("Failed to start reprocessing campaign", err)
+	}
+	for _, record := range result.Records {
+		response.DocumentIDs = append(response.DocumentIDs, recordString(record, "document_id"))
+	}
+	response.Queued = len(response.DocumentIDs)
+
+	s.logger.Info("Reprocessing campaign started",
+		zap.String("campaign_id", response.CampaignID),
+		zap.Bool("dry_run", req.DryRun),
+		zap.String("status", req.Status),
+		zap.String("tenant_id", req.TenantID),
+		zap.Int("queued", response.Queued),
+	)
+	return response, nil
+}
+
+// Retry queue tuning. A failed document is retried at most
+// maxRetryAttempts times and the n-th retry waits retryBaseDelay * 2^n,
+// i.e. 2, 4 and 8 minutes.
+const (
+	maxRetryAttempts    = 3
+	retryBaseDelay      = time.Minute
+	retryClaimBatchSize = 10
+)
+
+// retryDelay is the backoff before a retry attempt
+func retryDelay(attempt int64) time.Duration {
+	if attempt < 0 {
+		attempt = 0
+	}
+	if attempt > maxRetryAttempts {
+		attempt = maxRetryAttempts
+	}
+	return retryBaseDelay << attempt
+}
+
+// retryClaim is a retry job claimed by this replica
+type retryClaim struct {
+	jobID      string
+	documentID string
+	tenantID   string
+	attempt    int64
+	// priority is the tier the document was submitted with
+	priority string
+}
+
+// scheduleRetryProcessing queues a retry of a document whose processing
+// failed. ProcessDueRetries on the leader replica picks it up once its
+// backoff has passed, so it survives restarts. The retry is queued with
+// the document's processing priority and keeps its tier.
+func (s *DocumentService) scheduleRetryProcessing(ctx context.Context, documentID, tenantID string, attempt int64) {
+	now := time.Now().UTC()
+	retryAt := now.Add(retryDelay(attempt))
+	jobID := uuid.New().String()
+
+	s.logger.Info("Scheduling document processing retry",
+		zap.String("document_id", documentID),
+		zap.String("tenant_id", tenantID),
+		zap.Int64("retry_attempt", attempt),
+		zap.Time("retry_at", retryAt),
+	)
+
+	query := `
+		OPTIONAL MATCH (d:Document {id: $document_id, tenant_id: $tenant_id})
+		CREATE (j:ProcessingRetryJob {
+			id: $job_id,
+			document_id: $document_id,
+			tenant_id: $tenant_id,
+			retry_attempt: $retry_attempt,
+			status: $scheduled,
+			retry_at: $retry_at,
+			priority_tier: d.processing_priority,
+			priority: coalesce($queue_levels[d.processing_priority], $normal_level),
+			created_at: $now,
+			updated_at: $now
+		})
+	`
+	_, err := s.neo4j.ExecuteQuery(ctx, query, map[string]interface{}{
+		"job_id":        jobID,
+		"document_id":   documentID,
+		"tenant_id":     tenantID,
+		"retry_attempt": attempt,
+		"scheduled":     models.RetryJobStatusScheduled,
+		"retry_at":      retryAt,
+		"queue_levels":  retryQueueLevels(),
+		"normal_level":  processingQueueLevel(config.PriorityNormal),
+		"now":           now,
+	})
+	if err != nil {
+		s.logger.Error("Failed to schedule retry job",
+			zap.String("document_id", documentID),
+			zap.String("job_id", jobID),
+			zap.Error(err),
+		)
+	}
+}
+
+// ProcessDueRetries claims retry jobs whose retry time has passed and runs
+// them in the background. It is a singleton job run by the leader replica.
+// Each claim takes a lease that lasts as long as a retry may run; a job
+// whose lease ran out, because its replica died or hung, is claimed again
+// by a later poll. The claim locks each job and re-checks its status, so
+// two polls that overlap while leadership changes hands do not both start
+// the same job.
+func (s *DocumentService) ProcessDueRetries(ctx context.Context) error {
+	now := time.Now().UTC()
+	lease := 2 * s.backgroundJobTimeout
+
+	// Jobs claimed before leases were recorded expire by their last update
+	requeueQuery := `
+		MATCH (j:ProcessingRetryJob {status: $in_progress})
+		WHERE j.lease_expires_at < $now
+		   OR (j.lease_expires_at IS NULL AND j.updated_at < $stale_before)
+		SET j.status = $scheduled, j.updated_at = $now
+		REMOVE j.lease_owner, j.lease_expires_at
+		RETURN count(j) as requeued
+	`
+	result, err := s.neo4j.ExecuteQuery(ctx, requeueQuery, map[string]interface{}{
+		"in_progress":  models.RetryJobStatusInProgress,
+		"scheduled":    models.RetryJobStatusScheduled,
+		"stale_before": now.Add(-lease),
+		"now":          now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to requeue expired retry jobs: %w", err)
+	}
+	if requeued := recordInt(result.Records, "requeued"); requeued > 0 {
+		s.logger.Warn("Requeued processing retry jobs with expired leases", zap.Int64("count", requeued))
+	}
+
+	// Writing _claim_lock takes the node's write lock; a job claimed by a
+	// concurrent poll while this one waited no longer passes the status
+	// check. Due jobs of higher priority are claimed first.
+	claimQuery := `
+		MATCH (j:ProcessingRetryJob {status: $scheduled})
+		WHERE j.retry_at <= $now
+		WITH j ORDER BY coalesce(j.priority, $normal_level) DESC, j.retry_at LIMIT $limit
+		SET j._claim_lock = true
+		REMOVE j._claim_lock
+		WITH j WHERE j.status = $scheduled
+		SET j.status = $in_progress,
+		    j.lease_owner = $owner,
+		    j.lease_expires_at = $lease_expires_at,
+		    j.updated_at = $now
+		RETURN j.id as job_id, j.document_id as document_id, j.tenant_id as tenant_id,
+		       j.retry_attempt as retry_attempt, j.priority_tier as priority_tier
+	`
+	result, err = s.neo4j.ExecuteQuery(ctx, claimQuery, map[string]interface{}{
+		"scheduled":        models.RetryJobStatusScheduled,
+		"normal_level":     processingQueueLevel(config.PriorityNormal),
+		"in_progress":      models.RetryJobStatusInProgress,
+		"owner":            s.instanceID,
+		"lease_expires_at": now.Add(lease),
+		"now":              now,
+		"limit":            retryClaimBatchSize,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to claim retry jobs: %w", err)
+	}
+
+	for _, record := range result.Records {
+		claim := retryClaim{
+			jobID:      recordString(record, "job_id"),
+			documentID: recordString(record, "document_id"),
+			tenantID:   recordString(record, "tenant_id"),
+			priority:   recordString(record, "priority_tier"),
+		}
+		if value, _ := record.Get("retry_attempt"); value != nil {
+			claim.attempt, _ = value.(int64)
+		}
+		if claim.jobID == "" || claim.documentID == "" || claim.tenantID == "" {
+			s.logger.Error("Skipping malformed retry job",
+				zap.String("job_id", claim.jobID),
+				zap.String("document_id", claim.documentID),
+				zap.String("tenant_id", claim.tenantID),
+			)
+			// Fail it so an expired lease does not bring it back
+			if claim.jobID != "" {
+				s.settleRetryJob(ctx, claim, models.RetryJobStatusFailed, claim.attempt, nil, "malformed retry job")
+			}
+			continue
+		}
+
+		started := s.workers.Go(func() {
+			s.runRetryJob(claim)
+		})
+		if !started {
+			// Shutting down: hand the job back for the next leader
+			s.settleRetryJob(context.WithoutCancel(ctx), claim, models.RetryJobStatusScheduled, claim.attempt, nil, "")
+			s.logger.Info("Server shutting down, retry job left scheduled",
+				zap.String("document_id", claim.documentID),
+				zap.String("job_id", claim.jobID),
+			)
+		}
+	}
+
+	return nil
+}
+
+// runRetryJob reprocesses the document of a claimed retry job
+func (s *DocumentService) runRetryJob(claim retryClaim) {
+	// A retry that has started is allowed to finish during shutdown; the
+	// shutdown deadline bounds how long we wait
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(s.backgroundCtx), s.backgroundJobTimeout)
+	defer cancel()
+
+	s.logger.Info("Starting scheduled document processing retry",
+		zap.String("document_id", claim.documentID),
+		zap.String("job_id", claim.jobID),
+		zap.Int64("retry_attempt", claim.attempt),
+	)
+
+	document, err := s.getDocumentForRetry(ctx, claim.documentID, claim.tenantID)
+	if err != nil {
+		// A deleted document is not retried again
+		s.logger.Error("Failed to get document for retry",
+			zap.String("document_id", claim.documentID),
+			zap.Error(err),
+		)
+		s.settleRetryJob(ctx, claim, models.RetryJobStatusFailed, claim.attempt, nil, err.Error())
+		return
+	}
+
+	// Reprocess as the document owner, with the tier the document was
+	// submitted with; it was checked against the plan then
+	spaceContext := &models.SpaceContext{
+		TenantID: claim.tenantID,
+		UserID:   document.OwnerID,
+	}
+	if claim.priority != "" {
+		document.ProcessingPriority = claim.priority
+	}
+	if _, err := s.ReprocessDocument(ctx, document, spaceContext, ""); err != nil {
+		s.logger.Error("Document retry processing failed",
+			zap.String("document_id", claim.documentID),
+			zap.String("job_id", claim.jobID),
+			zap.Error(err),
+		)
+		s.failRetryRun(ctx, claim, err)
+		return
+	}
+
+	s.settleRetryJob(ctx, claim, models.RetryJobStatusCompleted, claim.attempt, nil, "")
+	s.logger.Info("Document processing retry completed successfully",
+		zap.String("document_id", claim.documentID),
+		zap.String("job_id", claim.jobID),
+	)
+}
+
+// failRetryRun reschedules a failed retry run after its backoff, or moves
+// the job to the dead-letter queue and escalates once its attempts are used
+func (s *DocumentService) failRetryRun(ctx context.Context, claim retryClaim, runErr error) {
+	attempt := claim.attempt + 1
+	if attempt > maxRetryAttempts {
+		s.settleRetryJob(ctx, claim, models.RetryJobStatusFailed, claim.attempt, nil, runErr.Error())
+		s.escalateRetries(ctx, claim.documentID, claim.tenantID, claim.attempt, runErr.Error())
+		return
+	}
+
+	retryAt := time.Now().UTC().Add(retryDelay(attempt))
+	s.settleRetryJob(ctx, claim, models.RetryJobStatusScheduled, attempt, &retryAt, runErr.Error())
+}
+
+// settleRetryJob ends this replica's lease on a retry job, moving it to
+// status. A job whose lease expired and was claimed again is left to its
+// new owner.
+func (s *DocumentService) settleRetryJob(ctx context.Context, claim retryClaim, status string, attempt int64, retryAt *time.Time, lastError string) {
+	query := `
+		MATCH (j:ProcessingRetryJob {id: $job_id, status: $in_progress, lease_owner: $owner})
+		SET j.status = $status,
+		    j.retry_attempt = $retry_attempt,
+		    j.retry_at = coalesce($retry_at, j.retry_at),
+		    j.last_error = CASE WHEN $last_error = '' THEN j.last_error ELSE $last_error END,
+		    j.updated_at = $now
+		REMOVE j.lease_owner, j.lease_expires_at
+		RETURN j.id as id
+	`
+	result, err := s.neo4j.ExecuteQuery(ctx, query, map[string]interface{}{
+		"job_id":        claim.jobID,
+		"in_progress":   models.RetryJobStatusInProgress,
+		"owner":         s.instanceID,
+		"status":        status,
+		"retry_attempt": attempt,
+		"retry_at":      optionalTime(retryAt),
+		"last_error":    lastError,
+		"now":           time.Now().UTC(),
+	})
+	if err != nil {
+		s.logger.Error("Failed to update retry job",
+			zap.String("job_id", claim.jobID),
+			zap.String("status", status),
+			zap.Error(err),
+		)
+		return
+	}
+	if len(result.Records) == 0 {
+		s.logger.Warn("Lost the lease of a retry job before it finished",
+			zap.String("job_id", claim.jobID),
+			zap.String("status", status),
+		)
+	}
+}
+
+// escalateRetries reports a document whose processing kept failing after
+// its retries. The alert event lets operators route it like other alerts;
+// the document's failed retry job stays in the dead-letter queue.
+func (s *DocumentService) escalateRetries(ctx context.Context, documentID, tenantID string, attempts int64, lastError string) {
+	s.logger.Error("Document processing has failed multiple times - maximum retries exceeded",
+		zap.String("document_id", documentID),
+		zap.String("tenant_id", tenantID),
+		zap.Int64("failure_count", attempts),
+		zap.String("alert", "repeated_processing_failure"),
+	)
+	publishDomainEvent(ctx, s.events, s.logger, NewDocumentEvent(EventProcessingRetriesExhausted, documentID, "", map[string]interface{}{
+		"tenant_id": tenantID,
+		"attempts":  attempts,
+		"error":     lastError,
+	}))
+}
+
+// recordToRetryJob reads the retryJobFields of a record
+func recordToRetryJob(record *neo4j.Record) *models.ProcessingRetryJob {
+	job := &models.ProcessingRetryJob{
+		ID:         recordString(record, "id"),
+		DocumentID: recordString(record, "document_id"),
+		TenantID:   recordString(record, "tenant_id"),
+		CampaignID: recordString(record, "campaign_id"),
+		Status:     recordString(record, "status"),
+		LastError:  recordString(record, "last_error"),
+	}
+	if value, _ := record.Get("retry_attempt"); value != nil {
+		job.RetryAttempt, _ = value.(int64)
+	}
+	if value, _ := record.Get("retry_at"); value != nil {
+		job.RetryAt, _ = value.(time.Time)
+	}
+	if value, _ := record.Get("created_at"); value != nil {
+		job.CreatedAt, _ = value.(time.Time)
+	}
+	if value, _ := record.Get("updated_at"); value != nil {
+		job.UpdatedAt, _ = value.(time.Time)
+	}
+	return job
+}
@@ -0,0 +1,16 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 2*time.Minute, retryDelay(1))
+	assert.Equal(t, 4*time.Minute, retryDelay(2))
+	assert.Equal(t, 8*time.Minute, retryDelay(3))
+	assert.Equal(t, 8*time.Minute, retryDelay(10), "the backoff is capped")
+	assert.Equal(t, time.Minute, retryDelay(-1))
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+// Steps of the sagas that add a document. The saga data holds tenant_id
+// and, as the steps are recorded, document_id, storage_key,
+// processing_provider and processing_file_id.
+const (
+	sagaStepDocumentRecord = "document.record"
+	sagaStepStoredFile     = "document.stored_file"
+	sagaStepProcessingFile = "document.processing_file"
+)
+
+// Kinds of the sagas that add a document
+const (
+	sagaKindUpload        = "document.upload"
+	sagaKindConfirmUpload = "document.confirm_upload"
+	sagaKindResumable     = "document.resumable_upload"
+)
+
+// uploadSagaStaleAfter is how long an upload saga may go without progress
+// before recovery undoes it, well past the time an upload takes
+const uploadSagaStaleAfter = time.Hour
+
+// registerSagaCompensations sets how each step of adding a document is
+// undone
+func (s *DocumentService) registerSagaCompensations() {
+	s.sagas.Register(sagaStepDocumentRecord, func(ctx context.Context, data map[string]string) error {
+		return s.deleteDocumentRecord(ctx, data["document_id"])
+	})
+	s.sagas.Register(sagaStepStoredFile, func(ctx context.Context, data map[string]string) error {
+		if s.storageService == nil {
+			return fmt.Errorf("storage service not configured")
+		}
+		return s.storageService.DeleteFileFromTenantBucket(ctx, data["tenant_id"], data["storage_key"])
+	})
+	s.sagas.Register(sagaStepProcessingFile, func(ctx context.Context, data map[string]string) error {
+		// Sagas recorded before providers were named hold the AudiModal
+		// file as audimodal_file_id
+		fileID := data["processing_file_id"]
+		if fileID == "" {
+			fileID = data["audimodal_file_id"]
+		}
+		provider := s.documentProcessingProvider(&models.Document{ProcessingProvider: data["processing_provider"]})
+		if provider == nil {
+			return fmt.Errorf("processing provider %q not configured", data["processing_provider"])
+		}
+		return provider.DeleteArtifacts(ctx, data["tenant_id"], fileID)
+	})
+}
+
+// beginStoredDocumentSaga starts the saga of submitting a document whose
+// record and file exist already, so both are removed if it fails
+func (s *DocumentService) beginStoredDocumentSaga(ctx context.Context, kind, documentID, tenantID, storageKey string) (*Saga, error) {
+	saga, err := s.sagas.Begin(ctx, kind, map[string]string{"tenant_id": tenantID})
+	if err != nil {
+		return nil, err
+	}
+	if err := saga.Record(ctx, sagaStepDocumentRecord, map[string]string{"document_id": documentID}); err != nil {
+		s.abortSaga(ctx, saga, err)
+		return nil, err
+	}
+	if err := saga.Record(ctx, sagaStepStoredFile, map[string]string{"storage_key": storageKey}); err != nil {
+		s.abortSaga(ctx, saga, err)
+		return nil, err
+	}
+	return saga, nil
+}
+
+// abortSaga undoes the steps of a failed saga. Steps that cannot be undone
+// now are left to RecoverSagas.
+func (s *DocumentService) abortSaga(ctx context.Context, saga *Saga, cause error) {
+	if err := saga.Abort(ctx, cause); err != nil {
+		s.logger.Error("Failed to roll back document, left for recovery",
+			zap.String("saga_id", saga.ID),
+			zap.String("document_id", saga.Data["document_id"]),
+			zap.Error(err))
+	}
+}
+
+// RecoverSagas rolls back the document additions a crashed replica left
+// half done, and retries rollbacks that failed
+func (s *DocumentService) RecoverSagas(ctx context.Context) error {
+	return s.sagas.Recover(ctx, uploadSagaStaleAfter)
+}
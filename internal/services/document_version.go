@@ -0,0 +1,399 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// documentVersionFields are the columns recordToDocumentVersion reads
+const documentVersionFields = `v.document_id AS document_id, v.version AS version,
+	       v.original_name AS original_name, v.mime_type AS mime_type,
+	       v.size_bytes AS size_bytes, v.checksum AS checksum,
+	       v.storage_path AS storage_path, v.storage_bucket AS storage_bucket,
+	       v.created_by AS created_by, v.created_at AS created_at`
+
+// firstVersionClause records a document's own file as its version 1 when it
+// has no versions yet, so documents uploaded before versioning gain a
+// history the first time a version is added
+const firstVersionClause = `
+		OPTIONAL MATCH (d)-[:HAS_VERSION]->(existing:DocumentVersion)
+		WITH d, count(existing) AS versions
+		FOREACH (_ IN CASE WHEN versions = 0 THEN [1] ELSE [] END |
+			CREATE (d)-[:HAS_VERSION]->(:DocumentVersion {
+				document_id: d.id, version: 1,
+				original_name: d.original_name, mime_type: d.mime_type,
+				size_bytes: d.size_bytes, checksum: d.checksum,
+				storage_path: d.storage_path, storage_bucket: d.storage_bucket,
+				created_by: d.owner_id, created_at: d.created_at
+			}))`
+
+// UploadDocumentVersion stores a new file for a document and makes it the
+// current version. The document keeps its ID, notebook and metadata; its
+// file is reprocessed. Earlier files stay in storage as earlier versions.
+func (s *DocumentService) UploadDocumentVersion(ctx context.Context, documentID string, data []byte, fileInfo models.FileInfo, userID string, spaceCtx *models.SpaceContext) (*models.DocumentVersion, error) {
+	document, err := s.versionableDocument(ctx, documentID, userID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+	if fileInfo, err = s.validateUpload(ctx, spaceCtx, fileInfo, data); err != nil {
+		return nil, err
+	}
+	if s.quotas != nil {
+		if err := s.quotas.CheckStorage(ctx, spaceCtx.SpaceID, int64(len(data))); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.scanUpload(ctx, spaceCtx, userID, fileInfo, data); err != nil {
+		return nil, err
+	}
+
+	// Reserve the number first so concurrent uploads get different ones
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "document_version.reserve"), `
+		MATCH (d:Document {id: $id})
+		SET d.version_count = coalesce(d.version_count, 1) + 1
+		RETURN d.version_count AS version
+	`, map[string]interface{}{"id": documentID})
+	if err != nil {
+		return nil, errors.Database("Failed to create document version", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, errors.NotFound("Document not found").WithErrorCode(errors.CodeDocumentNotFound)
+	}
+
+	checksum := sha256.Sum256(data)
+	version := &models.DocumentVersion{
+		DocumentID:   documentID,
+		Version:      int(recordInt64(result.Records[0], "version")),
+		OriginalName: fileInfo.OriginalName,
+		MimeType:     fileInfo.MimeType,
+		SizeBytes:    int64(len(data)),
+		Checksum:     hex.EncodeToString(checksum[:]),
+		Current:      true,
+		CreatedBy:    userID,
+		CreatedAt:    time.Now().UTC(),
+	}
+	storageKey := fmt.Sprintf("spaces/%s/notebooks/%s/documents/%s/versions/%d/%s",
+		spaceCtx.SpaceType, document.NotebookID, document.ID, version.Version, version.OriginalName)
+
+	storagePath, err := s.storageService.UploadFileToTenantBucket(ctx, spaceCtx.TenantID, storageKey, data, version.MimeType)
+	if err != nil {
+		return nil, errors.ExternalService("Failed to upload file", err)
+	}
+	version.StorageBucket, version.StoragePath = tenantBucketName(spaceCtx.TenantID), storageKey
+	if parts := strings.SplitN(storagePath, ":", 2); len(parts) == 2 {
+		version.StorageBucket, version.StoragePath = parts[0], parts[1]
+	}
+
+	_, err = s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "document_version.create"), `
+		MATCH (d:Document {id: $id})`+firstVersionClause+`
+		CREATE (d)-[:HAS_VERSION]->(:DocumentVersion {
+			document_id: d.id, version: $version,
+			original_name: $original_name, mime_type: $mime_type,
+			size_bytes: $size_bytes, checksum: $checksum,
+			storage_path: $storage_path, storage_bucket: $storage_bucket,
+			created_by: $created_by, created_at: $created_at
+		})
+		WITH d
+		`+currentVersionClause, map[string]interface{}{
+		"id":             documentID,
+		"version":        version.Version,
+		"original_name":  version.OriginalName,
+		"mime_type":      version.MimeType,
+		"size_bytes":     version.SizeBytes,
+		"checksum":       version.Checksum,
+		"storage_path":   version.StoragePath,
+		"storage_bucket": version.StorageBucket,
+		"created_by":     userID,
+		"created_at":     version.CreatedAt,
+		"updated_at":     version.CreatedAt.Format(time.RFC3339),
+	})
+	if err != nil {
+		s.logger.Error("Failed to record document version", zap.String("document_id", documentID), zap.Error(err))
+		if deleteErr := s.storageService.DeleteFileFromTenantBucket(ctx, spaceCtx.TenantID, version.StoragePath); deleteErr != nil {
+			s.logger.Warn("Failed to delete file of unrecorded version", zap.String("key", version.StoragePath), zap.Error(deleteErr))
+		}
+		return nil, errors.Database("Failed to create document version", err)
+	}
+
+	s.logger.Info("Document version uploaded",
+		zap.String("document_id", documentID),
+		zap.Int("version", version.Version),
+		zap.Int64("size_bytes", version.SizeBytes))
+
+	applyDocumentVersion(document, version)
+	if err := s.resubmitFile(ctx, document, spaceCtx, map[string]interface{}{"file_data": data}, userID); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// currentVersionClause makes the version given by the query's parameters
+// the document's current file, moving the notebook's size total by the
+// difference. The text extracted from the outgoing version is kept on it,
+// as stored, so earlier versions can still be compared. It expects d in
+// scope.
+const currentVersionClause = `OPTIONAL MATCH (d)-[:HAS_VERSION]->(outgoing:DocumentVersion {version: coalesce(d.current_version, 1)})
+		FOREACH (version IN CASE WHEN outgoing IS NULL OR d.status <> 'processed' OR d.extracted_text IS NULL THEN [] ELSE [outgoing] END |
+			SET version.extracted_text = d.extracted_text)
+		WITH d
+		OPTIONAL MATCH (d)-[:BELONGS_TO]->(n:Notebook)
+		FOREACH (notebook IN CASE WHEN n IS NULL THEN [] ELSE [n] END |
+			SET notebook.total_size_bytes = COALESCE(notebook.total_size_bytes, 0) - COALESCE(d.size_bytes, 0) + $size_bytes)
+		SET d.current_version = $version,
+		    d.original_name = $original_name,
+		    d.mime_type = $mime_type,
+		    d.size_bytes = $size_bytes,
+		    d.checksum = $checksum,
+		    d.storage_path = $storage_path,
+		    d.storage_bucket = $storage_bucket,
+		    d.updated_at = datetime($updated_at)`
+
+// ListDocumentVersions returns the versions of a document, newest first. A
+// document never given a new file has only its version 1.
+func (s *DocumentService) ListDocumentVersions(ctx context.Context, documentID, userID string, spaceCtx *models.SpaceContext) (*models.DocumentVersionListResponse, error) {
+	document, err := s.GetDocumentByID(ctx, documentID, userID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "document_version.list"), `
+		MATCH (d:Document {id: $id})-[:HAS_VERSION]->(v:DocumentVersion)
+		RETURN `+documentVersionFields+`, coalesce(d.current_version, 1) AS current_version
+		ORDER BY v.version DESC
+	`, map[string]interface{}{"id": documentID})
+	if err != nil {
+		return nil, errors.Database("Failed to list document versions", err)
+	}
+
+	response := &models.DocumentVersionListResponse{CurrentVersion: 1}
+	for _, record := range result.Records {
+		response.CurrentVersion = int(recordInt64(record, "current_version"))
+		response.Versions = append(response.Versions, recordToDocumentVersion(record))
+	}
+	if len(response.Versions) == 0 {
+		response.Versions = []*models.DocumentVersion{documentAsFirstVersion(document)}
+	}
+	for _, version := range response.Versions {
+		version.Current = version.Version == response.CurrentVersion
+	}
+	return response, nil
+}
+
+// GetDocumentVersion returns a version of a document
+func (s *DocumentService) GetDocumentVersion(ctx context.Context, documentID string, number int, userID string, spaceCtx *models.SpaceContext) (*models.DocumentVersion, error) {
+	versions, err := s.ListDocumentVersions(ctx, documentID, userID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+	for _, version := range versions.Versions {
+		if version.Version == number {
+			return version, nil
+		}
+	}
+	return nil, errors.NotFoundWithDetails("Document version not found", map[string]interface{}{
+		"document_id": documentID,
+		"version":     number,
+	}).WithErrorCode(errors.CodeDocumentVersionNotFound)
+}
+
+// OpenDocumentVersion opens the file of a version of a document for
+// reading. The caller closes it.
+func (s *DocumentService) OpenDocumentVersion(ctx context.Context, documentID string, number int, userID string, spaceCtx *models.SpaceContext) (*models.DocumentVersion, io.ReadCloser, error) {
+	version, err := s.GetDocumentVersion(ctx, documentID, number, userID, spaceCtx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if s.storageService == nil {
+		return nil, nil, errors.Internal("Storage service not configured")
+	}
+
+	file, err := s.storageService.OpenFileFromTenantBucket(ctx, spaceCtx.TenantID, version.StoragePath)
+	if err != nil {
+		return nil, nil, errors.ExternalService("Failed to download file", err)
+	}
+	return version, file, nil
+}
+
+// RestoreDocumentVersion makes an earlier version of a document current
+// again and reprocesses its file. Versions after it are kept.
+func (s *DocumentService) RestoreDocumentVersion(ctx context.Context, documentID string, number int, userID string, spaceCtx *models.SpaceContext) (*models.DocumentVersion, error) {
+	document, err := s.versionableDocument(ctx, documentID, userID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+	version, err := s.GetDocumentVersion(ctx, documentID, number, userID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+	if version.Current {
+		return version, nil
+	}
+
+	file, err := s.storageService.OpenFileFromTenantBucket(ctx, spaceCtx.TenantID, version.StoragePath)
+	if err != nil {
+		return nil, errors.ExternalService("Failed to read version file", err)
+	}
+	defer file.Close()
+
+	_, err = s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "document_version.restore"), `
+		MATCH (d:Document {id: $id})
+		`+currentVersionClause, map[string]interface{}{
+		"id":             documentID,
+		"version":        version.Version,
+		"original_name":  version.OriginalName,
+		"mime_type":      version.MimeType,
+		"size_bytes":     version.SizeBytes,
+		"checksum":       version.Checksum,
+		"storage_path":   version.StoragePath,
+		"storage_bucket": version.StorageBucket,
+		"updated_at":     time.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		s.logger.Error("Failed to restore document version", zap.String("document_id", documentID), zap.Error(err))
+		return nil, errors.Database("Failed to restore document version", err)
+	}
+
+	s.logger.Info("Document version restored",
+		zap.String("document_id", documentID),
+		zap.Int("version", version.Version))
+
+	version.Current = true
+	applyDocumentVersion(document, version)
+	if err := s.resubmitFile(ctx, document, spaceCtx, map[string]interface{}{"file_reader": file}, userID); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// versionableDocument returns a document the user may add versions to or
+// restore versions of
+func (s *DocumentService) versionableDocument(ctx context.Context, documentID, userID string, spaceCtx *models.SpaceContext) (*models.Document, error) {
+	document, err := s.GetDocumentByID(ctx, documentID, userID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeDocument(ctx, document, userID, models.ActionDocumentUpdate); err != nil {
+		return nil, err
+	}
+	if err := s.legalHolds.CheckDocument(ctx, document); err != nil {
+		return nil, err
+	}
+	if document.Status == "uploading" {
+		return nil, errors.ConflictWithDetails("Document file has not been uploaded yet", map[string]interface{}{
+			"document_id": documentID,
+		})
+	}
+	if s.storageService == nil {
+		return nil, errors.Internal("Storage service not configured")
+	}
+	return document, nil
+}
+
+// resubmitFile submits a document's new current file for processing.
+// Unlike submitForProcessing it keeps the document when submission fails,
+// marking it failed, since its other versions are still there.
+func (s *DocumentService) resubmitFile(ctx context.Context, document *models.Document, spaceCtx *models.SpaceContext, file map[string]interface{}, userID string) error {
+	if err := s.clearDocumentProcessingData(ctx, document.ID, spaceCtx.TenantID); err != nil {
+		s.logger.Warn("Failed to clear previous processing data", zap.String("document_id", document.ID), zap.Error(err))
+	}
+	publishDomainEvent(ctx, s.events, s.logger, NewDocumentEvent(EventDocumentUpdated, document.ID, userID, documentEventData(document)))
+
+	if !s.processingConfigured() {
+		return errors.ServiceUnavailable("Document processing service is not configured. Please contact support.")
+	}
+	processingConfig := map[string]interface{}{
+		"extract_text":     true,
+		"extract_metadata": true,
+		"filename":         document.OriginalName,
+		"mime_type":        document.MimeType,
+	}
+	for key, value := range file {
+		processingConfig[key] = value
+	}
+	provider := s.applyProcessingSettings(ctx, document, spaceCtx, processingConfig)
+
+	// The processed file of the earlier version and the vectors of its
+	// text are recycled once the new file is submitted
+	recycleID := s.recordArtifactRecycle(ctx, document, true)
+	job, err := provider.ProcessFile(ctx, spaceCtx.TenantID, document.ID, "extract", processingConfig)
+	if err != nil {
+		if recycleID != "" {
+			s.deletions.DiscardRecycle(ctx, recycleID)
+		}
+		s.countProcessingJob(spaceCtx.TenantID, "submit_failed")
+		s.logger.Error("Failed to submit document version for processing", zap.String("document_id", document.ID), zap.Error(err))
+		if statusErr := s.updateDocumentStatus(ctx, document.ID, "failed", nil, "Processing submission failed"); statusErr != nil {
+			s.logger.Error("Failed to update document status", zap.Error(statusErr))
+		}
+		return errors.ServiceUnavailable("Document processing service is currently unavailable. Please try again later.")
+	}
+	s.countProcessingJob(spaceCtx.TenantID, "submitted")
+
+	fileID := processingFileID(job)
+	document.Status = "processing"
+	document.ProcessingJobID = fileID
+	if err := s.updateDocumentStatusWithJobID(ctx, document.ID, document.Status, job.Result, "", fileID); err != nil {
+		s.logger.Error("Failed to update document status", zap.String("document_id", document.ID), zap.Error(err))
+	}
+	if recycleID != "" {
+		s.deletions.StartRecycle(ctx, recycleID)
+	}
+	return nil
+}
+
+// applyDocumentVersion sets a document's file fields to those of a version
+func applyDocumentVersion(document *models.Document, version *models.DocumentVersion) {
+	document.OriginalName = version.OriginalName
+	document.MimeType = version.MimeType
+	document.SizeBytes = version.SizeBytes
+	document.Checksum = version.Checksum
+	document.StoragePath = version.StoragePath
+	document.StorageBucket = version.StorageBucket
+	document.UpdatedAt = time.Now()
+}
+
+// documentAsFirstVersion describes the file of a document without versions
+// as its version 1
+func documentAsFirstVersion(document *models.Document) *models.DocumentVersion {
+	return &models.DocumentVersion{
+		DocumentID:    document.ID,
+		Version:       1,
+		OriginalName:  document.OriginalName,
+		MimeType:      document.MimeType,
+		SizeBytes:     document.SizeBytes,
+		Checksum:      document.Checksum,
+		StoragePath:   document.StoragePath,
+		StorageBucket: document.StorageBucket,
+		Current:       true,
+		CreatedBy:     document.OwnerID,
+		CreatedAt:     document.CreatedAt,
+	}
+}
+
+// recordToDocumentVersion reads the documentVersionFields of a record
+func recordToDocumentVersion(record *neo4j.Record) *models.DocumentVersion {
+	return &models.DocumentVersion{
+		DocumentID:    recordString(record, "document_id"),
+		Version:       int(recordInt64(record, "version")),
+		OriginalName:  recordString(record, "original_name"),
+		MimeType:      recordString(record, "mime_type"),
+		SizeBytes:     recordInt64(record, "size_bytes"),
+		Checksum:      recordString(record, "checksum"),
+		StoragePath:   recordString(record, "storage_path"),
+		StorageBucket: recordString(record, "storage_bucket"),
+		CreatedBy:     recordString(record, "created_by"),
+		CreatedAt:     recordTime(record, "created_at"),
+	}
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+func TestDocumentAsFirstVersion(t *testing.T) {
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	document := &models.Document{
+		ID:            "document-1",
+		OriginalName:  "report.pdf",
+		MimeType:      "application/pdf",
+		SizeBytes:     2048,
+		Checksum:      "abc",
+		StoragePath:   "spaces/personal/notebooks/n/documents/document-1/report.pdf",
+		StorageBucket: "aether-tenant",
+		OwnerID:       "user-1",
+		CreatedAt:     createdAt,
+	}
+
+	version := documentAsFirstVersion(document)
+
+	assert.Equal(t, 1, version.Version)
+	assert.True(t, version.Current)
+	assert.Equal(t, "document-1", version.DocumentID)
+	assert.Equal(t, document.StoragePath, version.StoragePath)
+	assert.Equal(t, "user-1", version.CreatedBy)
+	assert.Equal(t, createdAt, version.CreatedAt)
+}
+
+func TestApplyDocumentVersion(t *testing.T) {
+	document := &models.Document{ID: "document-1", Name: "Report", OriginalName: "report.pdf", SizeBytes: 2048}
+	version := &models.DocumentVersion{
+		Version:       2,
+		OriginalName:  "report-v2.docx",
+		MimeType:      "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+		SizeBytes:     4096,
+		Checksum:      "def",
+		StoragePath:   "spaces/personal/notebooks/n/documents/document-1/versions/2/report-v2.docx",
+		StorageBucket: "aether-tenant",
+	}
+
+	applyDocumentVersion(document, version)
+
+	assert.Equal(t, "Report", document.Name, "the document keeps its name")
+	assert.Equal(t, "report-v2.docx", document.OriginalName)
+	assert.Equal(t, int64(4096), document.SizeBytes)
+	assert.Equal(t, "def", document.Checksum)
+	assert.Equal(t, version.StoragePath, document.StoragePath)
+}
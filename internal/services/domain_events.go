@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+)
+
+// DomainEventPublisher publishes domain events after a state change has
+// been committed to Neo4j
+type DomainEventPublisher interface {
+	PublishEvent(ctx context.Context, event Event) error
+}
+
+// DomainEventHandler consumes domain events published on this instance
+type DomainEventHandler func(ctx context.Context, event Event) error
+
+// DomainEventBus publishes domain events to Kafka, when configured, and
+// hands them to in-process handlers such as the reporting projector. Each
+// event is handled on the replica that produced it, so handlers see every
+// event exactly once across the cluster without a consumer group.
+type DomainEventBus struct {
+	kafkaService *KafkaService
+	logger       *logger.Logger
+
+	mu       sync.RWMutex
+	handlers []DomainEventHandler
+}
+
+// NewDomainEventBus creates a domain event bus. kafkaService may be nil.
+func NewDomainEventBus(kafkaService *KafkaService, log *logger.Logger) *DomainEventBus {
+	return &DomainEventBus{
+		kafkaService: kafkaService,
+		logger:       log.WithService("domain_events"),
+	}
+}
+
+// Subscribe registers a handler for every event published from now on
+func (b *DomainEventBus) Subscribe(handler DomainEventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+// PublishEvent fills in the event envelope and delivers the event. Every
+// handler runs even if Kafka or an earlier handler fails; the first error
+// is returned.
+func (b *DomainEventBus) PublishEvent(ctx context.Context, event Event) error {
+	if event.ID == "" {
+		event.ID = uuid.New().String()
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+	if event.Version == "" {
+		event.Version = "1.0"
+	}
+	if event.Source == "" {
+		event.Source = "aether-backend"
+	}
+
+	var firstErr error
+	if b.kafkaService != nil {
+		firstErr = b.kafkaService.PublishEvent(ctx, event)
+	}
+
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			b.logger.Error("Domain event handler failed",
+				zap.String("event_id", event.ID),
+				zap.String("event_type", string(event.Type)),
+				zap.Error(err),
+			)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	return firstErr
+}
+
+// publishDomainEvent publishes an event on behalf of a service. The state
+// change it describes has already succeeded, so a failure is logged rather
+// than returned to the caller.
+func publishDomainEvent(ctx context.Context, publisher DomainEventPublisher, log *logger.Logger, event Event) {
+	if publisher == nil {
+		return
+	}
+	if err := publisher.PublishEvent(ctx, event); err != nil {
+		log.Warn("Failed to publish domain event",
+			zap.String("event_type", string(event.Type)),
+			zap.String("subject", event.Subject),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomainEventBusDeliversToEveryHandler(t *testing.T) {
+	bus := NewDomainEventBus(nil, setupTestLogger(t))
+
+	var received []Event
+	bus.Subscribe(func(ctx context.Context, event Event) error {
+		received = append(received, event)
+		return errors.New("projection unavailable")
+	})
+	bus.Subscribe(func(ctx context.Context, event Event) error {
+		received = append(received, event)
+		return nil
+	})
+
+	err := bus.PublishEvent(context.Background(), NewDocumentEvent(EventDocumentDeleted, "doc-1", "user-1", nil))
+	assert.EqualError(t, err, "projection unavailable")
+
+	// The envelope is filled in once, so every handler sees the same event
+	assert.Len(t, received, 2)
+	assert.NotEmpty(t, received[0].ID)
+	assert.False(t, received[0].Timestamp.IsZero())
+	assert.Equal(t, "aether-backend", received[0].Source)
+	assert.Equal(t, received[0], received[1])
+}
+
+func TestPublishDomainEventWithoutPublisher(t *testing.T) {
+	// Services without a publisher skip publishing
+	publishDomainEvent(context.Background(), nil, setupTestLogger(t), NewNotebookEvent(EventNotebookCreated, "nb-1", "user-1", nil))
+}
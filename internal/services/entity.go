@@ -0,0 +1,642 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// Entity extraction limits
+const (
+	// maxDocumentEntities bounds the entities linked to one document; the
+	// most mentioned are kept
+	maxDocumentEntities = 100
+	maxEntityNameLength = 200
+	defaultRelatedLimit = 10
+	maxRelatedLimit     = 50
+)
+
+// entityTypeAliases maps the entity types processors report to ours.
+// Other types, such as amounts, are not promoted.
+var entityTypeAliases = map[string]models.EntityType{
+	"person":       models.EntityTypePerson,
+	"people":       models.EntityTypePerson,
+	"per":          models.EntityTypePerson,
+	"organization": models.EntityTypeOrganization,
+	"organisation": models.EntityTypeOrganization,
+	"org":          models.EntityTypeOrganization,
+	"company":      models.EntityTypeOrganization,
+	"topic":        models.EntityTypeTopic,
+	"keyword":      models.EntityTypeTopic,
+	"location":     models.EntityTypeLocation,
+	"loc":          models.EntityTypeLocation,
+	"gpe":          models.EntityTypeLocation,
+	"place":        models.EntityTypeLocation,
+	"date":         models.EntityTypeDate,
+}
+
+// organizationSuffixes are dropped when comparing organization names, so
+// "Acme Corp." and "ACME Corporation" are one entity
+var organizationSuffixes = map[string]bool{
+	"inc": true, "incorporated": true, "corp": true, "corporation": true,
+	"co": true, "company": true, "ltd": true, "limited": true, "llc": true,
+	"plc": true, "gmbh": true, "ag": true, "sa": true,
+}
+
+// personTitles are dropped when comparing person names
+var personTitles = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "dr": true, "prof": true,
+}
+
+// EntityService promotes the people, organizations, topics, locations and
+// dates detected in processed documents to Entity nodes, linked from the
+// documents that mention them. Entities are per tenant and deduplicated by a normalized
+// name; spellings that normalize alike are merged, and users can merge
+// entities the normalization missed.
+type EntityService struct {
+	neo4j      *database.Neo4jClient
+	encryption *TextEncryptionService
+	logger     *logger.Logger
+}
+
+// NewEntityService creates a new entity service
+func NewEntityService(neo4j *database.Neo4jClient, log *logger.Logger) *EntityService {
+	return &EntityService{
+		neo4j:  neo4j,
+		logger: log.WithService("entity_service"),
+	}
+}
+
+// SetTextEncryption sets the service decrypting the text of documents in
+// spaces that encrypt it at rest; without it their text is not scanned
+func (s *EntityService) SetTextEncryption(encryption *TextEncryptionService) {
+	s.encryption = encryption
+}
+
+// HandleDomainEvent links the entities of a document once it is processed,
+// replacing those of an earlier run. Deleted documents keep their links so
+// a restore brings them back; reads skip deleted documents.
+func (s *EntityService) HandleDomainEvent(ctx context.Context, event Event) error {
+	if event.Type == EventDocumentProcessed {
+		return s.ExtractDocumentEntities(ctx, event.Subject)
+	}
+	return nil
+}
+
+// ExtractDocumentEntities links a document to the entities its processing
+// result reports. Entities of the types the processor reported none of are
+// recognized in the document's extracted text.
+func (s *EntityService) ExtractDocumentEntities(ctx context.Context, documentID string) error {
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (d:Document {id: $id})
+		WHERE `+database.NotDeleted("d")+`
+		RETURN d.tenant_id AS tenant_id, d.processing_result AS processing_result,
+		       `+textExcerptColumn("d", "text_chars")+` AS text
+	`, map[string]interface{}{"id": documentID, "text_chars": maxEntityTextBytes})
+	if err != nil {
+		return errors.Database("Failed to read document for entity extraction", err)
+	}
+	if len(result.Records) == 0 {
+		return nil
+	}
+
+	var processingResult map[string]interface{}
+	if raw := recordString(result.Records[0], "processing_result"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &processingResult); err != nil {
+			s.logger.Warn("Skipping entity extraction of unreadable processing result",
+				zap.String("document_id", documentID), zap.Error(err))
+			return nil
+		}
+	}
+
+	text := s.encryption.Excerpt(recordString(result.Records[0], "tenant_id"), recordString(result.Records[0], "text"), maxEntityTextBytes)
+	entities := mergeTextEntities(extractEntities(processingResult), extractTextEntities(text))
+	if err := s.linkEntities(ctx, documentID, entities); err != nil {
+		return err
+	}
+	s.logger.Debug("Linked document entities",
+		zap.String("document_id", documentID),
+		zap.Int("entities", len(entities)))
+	return nil
+}
+
+// linkEntities replaces the MENTIONS of a document, merging its entities
+// into the tenant's by type and key. Entities no document mentions any
+// more are removed.
+func (s *EntityService) linkEntities(ctx context.Context, documentID string, entities []models.ExtractedEntity) error {
+	byType := make(map[models.EntityType][]map[string]interface{})
+	for _, entity := range entities {
+		byType[entity.Type] = append(byType[entity.Type], map[string]interface{}{
+			"key":      entityKey(entity.Type, entity.Name),
+			"name":     entity.Name,
+			"mentions": entity.Mentions,
+		})
+	}
+	now := time.Now().UTC()
+
+	_, err := s.neo4j.WriteTransaction(database.WithQueryName(ctx, "entity.link"), func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		result, err := tx.Run(ctx, `
+			MATCH (d:Document {id: $document_id})-[m:MENTIONS]->(e:Entity)
+			DELETE m
+			RETURN collect(e.id) AS previous
+		`, map[string]interface{}{"document_id": documentID})
+		if err != nil {
+			return nil, err
+		}
+		record, err := result.Single(ctx)
+		if err != nil {
+			return nil, err
+		}
+		previous, _ := record.Get("previous")
+
+		for entityType, batch := range byType {
+			// Labels cannot be parameters; they come from a fixed set
+			_, err := tx.Run(ctx, fmt.Sprintf(`
+				MATCH (d:Document {id: $document_id})
+				UNWIND $entities AS entity
+				MERGE (e:Entity {tenant_id: d.tenant_id, type: $type, key: entity.key})
+				ON CREATE SET e:%s, e.id = randomUUID(), e.name = entity.name,
+				              e.aliases = [], e.created_at = $now
+				SET e.updated_at = $now,
+				    e.aliases = CASE WHEN entity.name = e.name OR entity.name IN e.aliases
+				                     THEN e.aliases ELSE e.aliases + entity.name END
+				MERGE (d)-[m:MENTIONS]->(e)
+				SET m.count = entity.mentions, m.updated_at = $now
+			`, entityType.Label()), map[string]interface{}{
+				"document_id": documentID,
+				"type":        string(entityType),
+				"entities":    batch,
+				"now":         now,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		_, err = tx.Run(ctx, `
+			MATCH (e:Entity)
+			WHERE e.id IN $previous AND NOT EXISTS { (e)<-[:MENTIONS]-(:Document) }
+			DETACH DELETE e
+		`, map[string]interface{}{"previous": previous})
+		return nil, err
+	})
+	if err != nil {
+		s.logger.Error("Failed to link document entities", zap.String("document_id", documentID), zap.Error(err))
+		return errors.Database("Failed to link document entities", err)
+	}
+	return nil
+}
+
+// entityFields are the entity properties recordToEntity reads besides
+// document_count
+const entityFields = `
+	e.id AS id, e.tenant_id AS tenant_id, e.type AS type, e.name AS name,
+	e.aliases AS aliases, e.created_at AS created_at, e.updated_at AS updated_at
+`
+
+// entityDocumentCount counts the live documents mentioning e
+func entityDocumentCount(ctx context.Context) string {
+	return "size([(e)<-[:MENTIONS]-(counted:Document) WHERE " + database.SoftDeleteFilter(ctx, "counted") + " | counted])"
+}
+
+// ListEntities lists one page of the entities of the space's tenant, most
+// mentioned first, optionally of one type or with a name or alias
+// containing query. Entities only deleted documents mention are left out.
+func (s *EntityService) ListEntities(ctx context.Context, entityType models.EntityType, query string, spaceCtx *models.SpaceContext, limit, offset int) ([]*models.Entity, bool, error) {
+	if !spaceCtx.CanRead() {
+		return nil, false, errors.Forbidden("Insufficient permissions to read entities")
+	}
+	limit, offset = pagination.Clamp(limit, offset)
+
+	ctx = database.WithQueryName(ctx, "entity.list")
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (e:Entity {tenant_id: $tenant_id})
+		WHERE ($type = '' OR e.type = $type)
+		  AND ($query = '' OR toLower(e.name) CONTAINS $query
+		       OR any(alias IN e.aliases WHERE toLower(alias) CONTAINS $query))
+		WITH e, `+entityDocumentCount(ctx)+` AS document_count
+		WHERE document_count > 0
+		RETURN `+entityFields+`, document_count
+		ORDER BY document_count DESC, name, id
+		SKIP $offset
+		LIMIT $limit
+	`, map[string]interface{}{
+		"tenant_id": spaceCtx.TenantID,
+		"type":      string(entityType),
+		"query":     strings.ToLower(strings.TrimSpace(query)),
+		"offset":    offset,
+		"limit":     limit + 1,
+	})
+	if err != nil {
+		s.logger.Error("Failed to list entities", zap.Error(err))
+		return nil, false, errors.Database("Failed to list entities", err)
+	}
+
+	entities := make([]*models.Entity, 0, len(result.Records))
+	for _, record := range result.Records {
+		entities = append(entities, recordToEntity(record))
+	}
+	entities, hasMore := pagination.Trim(entities, limit)
+	return entities, hasMore, nil
+}
+
+// ListNotebookEntities lists one page of the entities the documents of a
+// notebook mention, mentioned by most of its documents first, optionally of
+// one type or with a name or alias containing query. DocumentCount counts
+// the notebook's documents only. The caller checks the user may read the
+// notebook.
+func (s *EntityService) ListNotebookEntities(ctx context.Context, notebookID string, entityType models.EntityType, query string, spaceCtx *models.SpaceContext, limit, offset int) ([]*models.Entity, bool, error) {
+	if !spaceCtx.CanRead() {
+		return nil, false, errors.Forbidden("Insufficient permissions to read entities")
+	}
+	limit, offset = pagination.Clamp(limit, offset)
+
+	ctx = database.WithQueryName(ctx, "entity.notebook")
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (d:Document {notebook_id: $notebook_id, tenant_id: $tenant_id})-[m:MENTIONS]->(e:Entity)
+		WHERE `+database.SoftDeleteFilter(ctx, "d")+`
+		  AND ($type = '' OR e.type = $type)
+		  AND ($query = '' OR toLower(e.name) CONTAINS $query
+		       OR any(alias IN e.aliases WHERE toLower(alias) CONTAINS $query))
+		WITH e, count(DISTINCT d) AS document_count, sum(coalesce(m.count, 1)) AS mentions
+		RETURN `+entityFields+`, document_count
+		ORDER BY document_count DESC, mentions DESC, name, id
+		SKIP $offset
+		LIMIT $limit
+	`, map[string]interface{}{
+		"notebook_id": notebookID,
+		"tenant_id":   spaceCtx.TenantID,
+		"type":        string(entityType),
+		"query":       strings.ToLower(strings.TrimSpace(query)),
+		"offset":      offset,
+		"limit":       limit + 1,
+	})
+	if err != nil {
+		s.logger.Error("Failed to list notebook entities", zap.String("notebook_id", notebookID), zap.Error(err))
+		return nil, false, errors.Database("Failed to list entities", err)
+	}
+
+	entities := make([]*models.Entity, 0, len(result.Records))
+	for _, record := range result.Records {
+		entities = append(entities, recordToEntity(record))
+	}
+	entities, hasMore := pagination.Trim(entities, limit)
+	return entities, hasMore, nil
+}
+
+// GetEntity returns an entity of the space's tenant
+func (s *EntityService) GetEntity(ctx context.Context, entityID string, spaceCtx *models.SpaceContext) (*models.Entity, error) {
+	if !spaceCtx.CanRead() {
+		return nil, errors.Forbidden("Insufficient permissions to read entities")
+	}
+
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (e:Entity {id: $id, tenant_id: $tenant_id})
+		RETURN `+entityFields+`, `+entityDocumentCount(ctx)+` AS document_count
+	`, map[string]interface{}{
+		"id":        entityID,
+		"tenant_id": spaceCtx.TenantID,
+	})
+	if err != nil {
+		s.logger.Error("Failed to get entity", zap.String("entity_id", entityID), zap.Error(err))
+		return nil, errors.Database("Failed to retrieve entity", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, errors.NotFoundWithDetails("Entity not found", map[string]interface{}{
+			"entity_id": entityID,
+		}).WithErrorCode(errors.CodeEntityNotFound)
+	}
+	return recordToEntity(result.Records[0]), nil
+}
+
+// ListEntityDocuments lists one page of the documents mentioning an
+// entity, most mentions first, optionally only those of one notebook
+func (s *EntityService) ListEntityDocuments(ctx context.Context, entityID, notebookID string, spaceCtx *models.SpaceContext, limit, offset int) (*models.EntityDocumentsResponse, error) {
+	if _, err := s.GetEntity(ctx, entityID, spaceCtx); err != nil {
+		return nil, err
+	}
+	limit, offset = pagination.Clamp(limit, offset)
+
+	ctx = database.WithQueryName(ctx, "entity.documents")
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (e:Entity {id: $id, tenant_id: $tenant_id})<-[m:MENTIONS]-(d:Document)
+		WHERE `+database.SoftDeleteFilter(ctx, "d")+`
+		  AND ($notebook_id = '' OR d.notebook_id = $notebook_id)
+		RETURN d.id AS id, d.name AS name, d.mime_type AS mime_type, d.notebook_id AS notebook_id,
+		       m.count AS mentions
+		ORDER BY mentions DESC, d.updated_at DESC, id
+		SKIP $offset
+		LIMIT $limit
+	`, map[string]interface{}{
+		"id":          entityID,
+		"notebook_id": notebookID,
+		"tenant_id":   spaceCtx.TenantID,
+		"offset":      offset,
+		"limit":       limit + 1,
+	})
+	if err != nil {
+		s.logger.Error("Failed to list entity documents", zap.String("entity_id", entityID), zap.Error(err))
+		return nil, errors.Database("Failed to list entity documents", err)
+	}
+
+	documents := make([]*models.EntityDocument, 0, len(result.Records))
+	for _, record := range result.Records {
+		documents = append(documents, &models.EntityDocument{
+			Document: &models.SemanticSearchDocument{
+				ID:         recordString(record, "id"),
+				Name:       recordString(record, "name"),
+				MimeType:   recordString(record, "mime_type"),
+				NotebookID: recordString(record, "notebook_id"),
+			},
+			Mentions: int(recordInt64(record, "mentions")),
+		})
+	}
+	documents, hasMore := pagination.Trim(documents, limit)
+
+	return &models.EntityDocumentsResponse{
+		EntityID:  entityID,
+		Documents: documents,
+		Limit:     limit,
+		Offset:    offset,
+		HasMore:   hasMore,
+	}, nil
+}
+
+// ListRelatedEntities lists the entities most often mentioned by the same
+// documents as an entity, optionally of one type. With the topic type it
+// walks from a topic to its neighbouring topics.
+func (s *EntityService) ListRelatedEntities(ctx context.Context, entityID string, entityType models.EntityType, spaceCtx *models.SpaceContext, limit int) (*models.RelatedEntitiesResponse, error) {
+	if _, err := s.GetEntity(ctx, entityID, spaceCtx); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultRelatedLimit
+	}
+	if limit > maxRelatedLimit {
+		limit = maxRelatedLimit
+	}
+
+	ctx = database.WithQueryName(ctx, "entity.related")
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (:Entity {id: $id, tenant_id: $tenant_id})<-[:MENTIONS]-(shared:Document)-[:MENTIONS]->(e:Entity)
+		WHERE e.id <> $id AND ($type = '' OR e.type = $type)
+		  AND `+database.SoftDeleteFilter(ctx, "shared")+`
+		WITH e, count(DISTINCT shared) AS shared_documents
+		RETURN `+entityFields+`, `+entityDocumentCount(ctx)+` AS document_count, shared_documents
+		ORDER BY shared_documents DESC, name, id
+		LIMIT $limit
+	`, map[string]interface{}{
+		"id":        entityID,
+		"tenant_id": spaceCtx.TenantID,
+		"type":      string(entityType),
+		"limit":     limit,
+	})
+	if err != nil {
+		s.logger.Error("Failed to list related entities", zap.String("entity_id", entityID), zap.Error(err))
+		return nil, errors.Database("Failed to list related entities", err)
+	}
+
+	related := make([]*models.RelatedEntity, 0, len(result.Records))
+	for _, record := range result.Records {
+		related = append(related, &models.RelatedEntity{
+			Entity:          recordToEntity(record),
+			SharedDocuments: int(recordInt64(record, "shared_documents")),
+		})
+	}
+	return &models.RelatedEntitiesResponse{EntityID: entityID, Related: related}, nil
+}
+
+// ListDocumentEntities lists the entities a document mentions, most
+// mentioned first
+func (s *EntityService) ListDocumentEntities(ctx context.Context, documentID string, spaceCtx *models.SpaceContext) ([]*models.Entity, error) {
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (:Document {id: $document_id, tenant_id: $tenant_id})-[m:MENTIONS]->(e:Entity)
+		RETURN `+entityFields+`, `+entityDocumentCount(ctx)+` AS document_count, m.count AS mentions
+		ORDER BY mentions DESC, name, id
+	`, map[string]interface{}{
+		"document_id": documentID,
+		"tenant_id":   spaceCtx.TenantID,
+	})
+	if err != nil {
+		s.logger.Error("Failed to list document entities", zap.String("document_id", documentID), zap.Error(err))
+		return nil, errors.Database("Failed to list document entities", err)
+	}
+
+	entities := make([]*models.Entity, 0, len(result.Records))
+	for _, record := range result.Records {
+		entities = append(entities, recordToEntity(record))
+	}
+	return entities, nil
+}
+
+// MergeEntities merges an entity into a target of the same type: the
+// target takes over its mentions and its spellings as aliases, and the
+// entity is removed. Documents mentioning both keep one mention with the
+// counts added up.
+func (s *EntityService) MergeEntities(ctx context.Context, entityID, targetID string, spaceCtx *models.SpaceContext) (*models.Entity, error) {
+	if !spaceCtx.CanUpdate() {
+		return nil, errors.Forbidden("Insufficient permissions to merge entities")
+	}
+	if entityID == targetID {
+		return nil, errors.ValidationWithDetails("An entity cannot be merged into itself", map[string]interface{}{
+			"entity_id": entityID,
+		}).WithErrorCode(errors.CodeEntityMergeInvalid)
+	}
+	source, err := s.GetEntity(ctx, entityID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.GetEntity(ctx, targetID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+	if source.Type != target.Type {
+		return nil, errors.ValidationWithDetails("Only entities of the same type can be merged", map[string]interface{}{
+			"entity_type": source.Type,
+			"target_type": target.Type,
+		}).WithErrorCode(errors.CodeEntityMergeInvalid)
+	}
+
+	params := map[string]interface{}{
+		"source_id": entityID,
+		"target_id": targetID,
+		"tenant_id": spaceCtx.TenantID,
+		"now":       time.Now().UTC(),
+	}
+	_, err = s.neo4j.WriteTransaction(database.WithQueryName(ctx, "entity.merge"), func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		_, err := tx.Run(ctx, `
+			MATCH (d:Document)-[m:MENTIONS]->(:Entity {id: $source_id, tenant_id: $tenant_id})
+			MATCH (target:Entity {id: $target_id, tenant_id: $tenant_id})
+			MERGE (d)-[merged:MENTIONS]->(target)
+			SET merged.count = coalesce(merged.count, 0) + coalesce(m.count, 0),
+			    merged.updated_at = $now
+		`, params)
+		if err != nil {
+			return nil, err
+		}
+		_, err = tx.Run(ctx, `
+			MATCH (source:Entity {id: $source_id, tenant_id: $tenant_id})
+			MATCH (target:Entity {id: $target_id, tenant_id: $tenant_id})
+			SET target.aliases = reduce(aliases = [], alias IN coalesce(target.aliases, []) + source.name + coalesce(source.aliases, []) |
+			                            CASE WHEN alias = target.name OR alias IN aliases THEN aliases ELSE aliases + alias END),
+			    target.updated_at = $now
+			DETACH DELETE source
+		`, params)
+		return nil, err
+	})
+	if err != nil {
+		s.logger.Error("Failed to merge entities",
+			zap.String("entity_id", entityID),
+			zap.String("target_id", targetID),
+			zap.Error(err))
+		return nil, errors.Database("Failed to merge entities", err)
+	}
+
+	s.logger.Info("Merged entities",
+		zap.String("entity_id", entityID),
+		zap.String("target_id", targetID),
+		zap.String("user_id", spaceCtx.UserID))
+	return s.GetEntity(ctx, targetID, spaceCtx)
+}
+
+func recordToEntity(record *neo4j.Record) *models.Entity {
+	entity := &models.Entity{
+		ID:            recordString(record, "id"),
+		TenantID:      recordString(record, "tenant_id"),
+		Type:          models.EntityType(recordString(record, "type")),
+		Name:          recordString(record, "name"),
+		DocumentCount: int(recordInt64(record, "document_count")),
+	}
+	if value, ok := record.Get("aliases"); ok && value != nil {
+		if aliases, ok := value.([]interface{}); ok {
+			for _, alias := range aliases {
+				if alias, ok := alias.(string); ok {
+					entity.Aliases = append(entity.Aliases, alias)
+				}
+			}
+		}
+	}
+	if value, ok := record.Get("created_at"); ok && value != nil {
+		entity.CreatedAt, _ = value.(time.Time)
+	}
+	if value, ok := record.Get("updated_at"); ok && value != nil {
+		entity.UpdatedAt, _ = value.(time.Time)
+	}
+	return entity
+}
+
+// extractEntities reads the entities a processing result reports. They
+// are listed under "entities", each with a name ("text" or "name"), a type
+// ("type" or "label") and optionally a mention count ("count" or
+// "mentions"); topics are listed by name under "topics" or "main_topics".
+// Entities are deduplicated by key, keeping the first spelling seen.
+func extractEntities(result map[string]interface{}) []models.ExtractedEntity {
+	var entities []models.ExtractedEntity
+	index := make(map[string]int)
+	add := func(entityType models.EntityType, name string, mentions int) {
+		name = strings.Join(strings.Fields(name), " ")
+		if entityType == models.EntityTypeDate {
+			name = normalizeEntityDate(name)
+		}
+		if len(name) > maxEntityNameLength {
+			return
+		}
+		key := entityKey(entityType, name)
+		if len(key) < 2 {
+			return
+		}
+		if mentions < 1 {
+			mentions = 1
+		}
+		id := string(entityType) + ":" + key
+		if i, ok := index[id]; ok {
+			entities[i].Mentions += mentions
+			return
+		}
+		index[id] = len(entities)
+		entities = append(entities, models.ExtractedEntity{Type: entityType, Name: name, Mentions: mentions})
+	}
+
+	if list, ok := result["entities"].([]interface{}); ok {
+		for _, item := range list {
+			fields, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			entityType, ok := entityTypeAliases[strings.ToLower(firstString(fields, "type", "label"))]
+			if !ok {
+				continue
+			}
+			mentions := 0
+			for _, key := range []string{"count", "mentions"} {
+				if count, ok := fields[key].(float64); ok {
+					mentions = int(count)
+					break
+				}
+			}
+			add(entityType, firstString(fields, "text", "name"), mentions)
+		}
+	}
+	for _, key := range []string{"topics", "main_topics"} {
+		if list, ok := result[key].([]interface{}); ok {
+			for _, item := range list {
+				switch topic := item.(type) {
+				case string:
+					add(models.EntityTypeTopic, topic, 0)
+				case map[string]interface{}:
+					add(models.EntityTypeTopic, firstString(topic, "name", "text"), 0)
+				}
+			}
+		}
+	}
+
+	if len(entities) > maxDocumentEntities {
+		sort.SliceStable(entities, func(i, j int) bool {
+			return entities[i].Mentions > entities[j].Mentions
+		})
+		entities = entities[:maxDocumentEntities]
+	}
+	return entities
+}
+
+func firstString(fields map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := fields[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// entityKey normalizes an entity name for deduplication: case, punctuation
+// and spacing are ignored, as are the legal suffixes of organizations and
+// the titles of people
+func entityKey(entityType models.EntityType, name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '&'
+	})
+	switch entityType {
+	case models.EntityTypeOrganization:
+		for len(words) > 1 && organizationSuffixes[words[len(words)-1]] {
+			words = words[:len(words)-1]
+		}
+	case models.EntityTypePerson:
+		for len(words) > 1 && personTitles[words[0]] {
+			words = words[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
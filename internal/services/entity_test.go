@@ -0,0 +1,80 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+func TestEntityKey(t *testing.T) {
+	assert.Equal(t, "acme", entityKey(models.EntityTypeOrganization, "ACME Corp."))
+	assert.Equal(t, "acme", entityKey(models.EntityTypeOrganization, "Acme Corporation, Inc."))
+	assert.Equal(t, "johnson & johnson", entityKey(models.EntityTypeOrganization, "Johnson & Johnson"))
+	assert.Equal(t, "co", entityKey(models.EntityTypeOrganization, "Co"), "a name is never dropped entirely")
+	assert.Equal(t, "jane doe", entityKey(models.EntityTypePerson, "Dr. Jane  Doe"))
+	assert.Equal(t, "inc", entityKey(models.EntityTypeTopic, "Inc"), "suffixes only apply to organizations")
+}
+
+func TestExtractEntities(t *testing.T) {
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"entities": [
+			{"text": "Acme Corp", "type": "ORG", "count": 3},
+			{"text": "ACME Corporation", "type": "organization"},
+			{"name": "Jane Doe", "label": "PERSON", "mentions": 2},
+			{"text": "Paris", "type": "LOCATION"},
+			{"text": "March 3, 2026", "type": "DATE"},
+			{"text": "$5,000", "type": "MONEY"},
+			{"text": "", "type": "person"}
+		],
+		"topics": ["Supply chain", {"name": "supply  chain"}],
+		"main_topics": ["Risk"]
+	}`), &result))
+
+	assert.Equal(t, []models.ExtractedEntity{
+		{Type: models.EntityTypeOrganization, Name: "Acme Corp", Mentions: 4},
+		{Type: models.EntityTypePerson, Name: "Jane Doe", Mentions: 2},
+		{Type: models.EntityTypeLocation, Name: "Paris", Mentions: 1},
+		{Type: models.EntityTypeDate, Name: "2026-03-03", Mentions: 1},
+		{Type: models.EntityTypeTopic, Name: "Supply chain", Mentions: 2},
+		{Type: models.EntityTypeTopic, Name: "Risk", Mentions: 1},
+	}, extractEntities(result))
+
+	assert.Empty(t, extractEntities(nil))
+}
+
+func TestExtractTextEntities(t *testing.T) {
+	text := "On March 3, 2026 Dr. Jane Doe met Acme Corp. in Berlin. " +
+		"The contract with Globex Group was signed on 2026-03-03 and renewed on 4 Feb 2027. " +
+		"John Q. Public flew from New York to Berlin in January. See Appendix B. Due 2026-02-30."
+
+	assert.Equal(t, []models.ExtractedEntity{
+		{Type: models.EntityTypeDate, Name: "2026-03-03", Mentions: 2},
+		{Type: models.EntityTypeDate, Name: "2027-02-04", Mentions: 1},
+		{Type: models.EntityTypeOrganization, Name: "Acme Corp", Mentions: 1},
+		{Type: models.EntityTypeOrganization, Name: "Globex Group", Mentions: 1},
+		{Type: models.EntityTypePerson, Name: "Jane Doe", Mentions: 1},
+		{Type: models.EntityTypePerson, Name: "John Q. Public", Mentions: 1},
+		{Type: models.EntityTypeLocation, Name: "Berlin", Mentions: 2},
+		{Type: models.EntityTypeLocation, Name: "New York", Mentions: 1},
+	}, extractTextEntities(text))
+
+	assert.Empty(t, extractTextEntities(""))
+}
+
+func TestMergeTextEntities(t *testing.T) {
+	reported := []models.ExtractedEntity{{Type: models.EntityTypePerson, Name: "Jane Doe", Mentions: 2}}
+	fromText := []models.ExtractedEntity{
+		{Type: models.EntityTypePerson, Name: "John Q. Public", Mentions: 1},
+		{Type: models.EntityTypeLocation, Name: "Berlin", Mentions: 1},
+	}
+
+	assert.Equal(t, []models.ExtractedEntity{
+		{Type: models.EntityTypePerson, Name: "Jane Doe", Mentions: 2},
+		{Type: models.EntityTypeLocation, Name: "Berlin", Mentions: 1},
+	}, mergeTextEntities(reported, fromText), "types the processor reported are not recognized in the text")
+}
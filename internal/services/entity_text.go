@@ -0,0 +1,174 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+// maxEntityTextBytes is how much of a document's extracted text is scanned
+// for entities
+const maxEntityTextBytes = 200_000
+
+// Patterns of the entities recognized in extracted text. They favour
+// precision over recall: a person needs a title or a middle initial, an
+// organization a legal or institutional suffix, and a location a
+// preposition before it.
+var (
+	isoDatePattern       = regexp.MustCompile(`\b(\d{4})-(\d{2})-(\d{2})\b`)
+	monthDayYearPattern  = regexp.MustCompile(`\b(Jan(?:uary)?|Feb(?:ruary)?|Mar(?:ch)?|Apr(?:il)?|May|June?|July?|Aug(?:ust)?|Sep(?:t(?:ember)?)?|Oct(?:ober)?|Nov(?:ember)?|Dec(?:ember)?)\.? (\d{1,2})(?:st|nd|rd|th)?,? (\d{4})\b`)
+	dayMonthYearPattern  = regexp.MustCompile(`\b(\d{1,2}) (Jan(?:uary)?|Feb(?:ruary)?|Mar(?:ch)?|Apr(?:il)?|May|June?|July?|Aug(?:ust)?|Sep(?:t(?:ember)?)?|Oct(?:ober)?|Nov(?:ember)?|Dec(?:ember)?)\.? (\d{4})\b`)
+	titledPersonPattern  = regexp.MustCompile(`\b(?:Mr|Mrs|Ms|Dr|Prof)\.? ((?:[A-Z][a-z]+)(?: [A-Z][a-z]+){0,2})\b`)
+	initialPersonPattern = regexp.MustCompile(`\b([A-Z][a-z]+ [A-Z]\. [A-Z][a-z]+)\b`)
+	organizationPattern  = regexp.MustCompile(`\b((?:[A-Z][A-Za-z&'-]*\s){1,4}(?:Inc|Corp|Corporation|Company|LLC|Ltd|Limited|PLC|GmbH|AG|Group|Bank|University|Institute|Foundation|Association)\b\.?)`)
+	universityPattern    = regexp.MustCompile(`\b((?:University|Institute|Bank) of(?: [A-Z][a-z]+){1,3})\b`)
+	locationPattern      = regexp.MustCompile(`\b(?:in|at|from|near|to|across) ((?:[A-Z][a-z]+)(?: [A-Z][a-z]+){0,2})\b`)
+)
+
+// entityStopWords are capitalized words that start no person or place
+// name, such as months or the words that open a sentence
+var entityStopWords = map[string]bool{
+	"january": true, "february": true, "march": true, "april": true, "may": true, "june": true,
+	"july": true, "august": true, "september": true, "october": true, "november": true, "december": true,
+	"monday": true, "tuesday": true, "wednesday": true, "thursday": true, "friday": true,
+	"saturday": true, "sunday": true, "the": true, "this": true, "that": true, "these": true,
+	"our": true, "their": true, "his": true, "her": true, "its": true, "section": true,
+	"table": true, "figure": true, "appendix": true, "chapter": true, "page": true, "q1": true,
+	"q2": true, "q3": true, "q4": true, "i": true, "we": true, "you": true, "it": true,
+}
+
+// entityMonths maps the month names and abbreviations dates use to months
+var entityMonths = map[string]time.Month{
+	"jan": time.January, "feb": time.February, "mar": time.March, "apr": time.April,
+	"may": time.May, "jun": time.June, "jul": time.July, "aug": time.August,
+	"sep": time.September, "oct": time.October, "nov": time.November, "dec": time.December,
+}
+
+// extractTextEntities recognizes the people, organizations, locations and
+// dates mentioned in a document's text, for processors that report none.
+// Dates are named in ISO form so that spellings of one day are one entity.
+func extractTextEntities(text string) []models.ExtractedEntity {
+	if len(text) > maxEntityTextBytes {
+		text = text[:maxEntityTextBytes]
+	}
+
+	var entities []models.ExtractedEntity
+	index := make(map[string]int)
+	add := func(entityType models.EntityType, name string) {
+		name = strings.TrimSuffix(strings.Join(strings.Fields(name), " "), ".")
+		if len(name) > maxEntityNameLength {
+			return
+		}
+		key := entityKey(entityType, name)
+		if len(key) < 2 {
+			return
+		}
+		id := string(entityType) + ":" + key
+		if i, ok := index[id]; ok {
+			entities[i].Mentions++
+			return
+		}
+		index[id] = len(entities)
+		entities = append(entities, models.ExtractedEntity{Type: entityType, Name: name, Mentions: 1})
+	}
+
+	for _, match := range isoDatePattern.FindAllStringSubmatch(text, -1) {
+		if date, ok := entityDate(match[1], match[2], match[3]); ok {
+			add(models.EntityTypeDate, date)
+		}
+	}
+	for _, match := range monthDayYearPattern.FindAllStringSubmatch(text, -1) {
+		if date, ok := entityDate(match[3], match[1], match[2]); ok {
+			add(models.EntityTypeDate, date)
+		}
+	}
+	for _, match := range dayMonthYearPattern.FindAllStringSubmatch(text, -1) {
+		if date, ok := entityDate(match[3], match[2], match[1]); ok {
+			add(models.EntityTypeDate, date)
+		}
+	}
+
+	organizations := make(map[string]bool)
+	for _, pattern := range []*regexp.Regexp{organizationPattern, universityPattern} {
+		for _, match := range pattern.FindAllStringSubmatch(text, -1) {
+			add(models.EntityTypeOrganization, match[1])
+			for _, word := range strings.Fields(match[1]) {
+				organizations[strings.Trim(word, ".,")] = true
+			}
+		}
+	}
+	for _, pattern := range []*regexp.Regexp{titledPersonPattern, initialPersonPattern} {
+		for _, match := range pattern.FindAllStringSubmatch(text, -1) {
+			if !entityStopWords[strings.ToLower(strings.Fields(match[1])[0])] {
+				add(models.EntityTypePerson, match[1])
+			}
+		}
+	}
+	for _, match := range locationPattern.FindAllStringSubmatch(text, -1) {
+		first := strings.Fields(match[1])[0]
+		if entityStopWords[strings.ToLower(first)] || organizations[first] {
+			continue
+		}
+		add(models.EntityTypeLocation, match[1])
+	}
+
+	if len(entities) > maxDocumentEntities {
+		sort.SliceStable(entities, func(i, j int) bool {
+			return entities[i].Mentions > entities[j].Mentions
+		})
+		entities = entities[:maxDocumentEntities]
+	}
+	return entities
+}
+
+// entityDate returns the ISO form of a date given as year, month (a number
+// or a name) and day, or false when it is not a valid date
+func entityDate(year, month, day string) (string, bool) {
+	m, ok := entityMonths[strings.ToLower(month[:min(3, len(month))])]
+	if !ok {
+		n, err := strconv.Atoi(month)
+		if err != nil {
+			return "", false
+		}
+		m = time.Month(n)
+	}
+	d, err := strconv.Atoi(day)
+	if err != nil {
+		return "", false
+	}
+	date, err := time.Parse("2006-01-02", fmt.Sprintf("%s-%02d-%02d", year, int(m), d))
+	if err != nil {
+		return "", false
+	}
+	return date.Format("2006-01-02"), true
+}
+
+// normalizeEntityDate returns the ISO form of a date name in one of the
+// forms extractTextEntities recognizes, or the name as it is
+func normalizeEntityDate(name string) string {
+	if dates := extractTextEntities(name); len(dates) == 1 && dates[0].Type == models.EntityTypeDate {
+		return dates[0].Name
+	}
+	return name
+}
+
+// mergeTextEntities adds the entities recognized in the text to those a
+// processor reported, for the types it reported none of
+func mergeTextEntities(reported, fromText []models.ExtractedEntity) []models.ExtractedEntity {
+	types := make(map[models.EntityType]bool)
+	for _, entity := range reported {
+		types[entity.Type] = true
+	}
+	merged := reported
+	for _, entity := range fromText {
+		if !types[entity.Type] && len(merged) < maxDocumentEntities {
+			merged = append(merged, entity)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,420 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/i18n"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+// PubSub carries messages between replicas. The Redis client implements
+// it; LocalPubSub stands in when Redis is disabled.
+type PubSub interface {
+	Publish(ctx context.Context, channel string, payload []byte) error
+	// Subscribe delivers messages published to channel until ctx is done,
+	// then closes the returned channel
+	Subscribe(ctx context.Context, channel string) (<-chan []byte, error)
+}
+
+// localPubSubBuffer is how many messages a local subscriber may fall
+// behind before further messages are dropped
+const localPubSubBuffer = 64
+
+// LocalPubSub is an in-process PubSub. It only reaches subscribers of one
+// process, so it must not be used when several replicas run.
+type LocalPubSub struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan []byte]struct{}
+}
+
+// NewLocalPubSub creates an in-process PubSub
+func NewLocalPubSub() *LocalPubSub {
+	return &LocalPubSub{subscribers: make(map[string]map[chan []byte]struct{})}
+}
+
+// Publish hands payload to every current subscriber of channel. Like Redis
+// it never blocks on a slow subscriber; one whose buffer is full misses
+// the message.
+func (p *LocalPubSub) Publish(ctx context.Context, channel string, payload []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for subscriber := range p.subscribers[channel] {
+		select {
+		case subscriber <- payload:
+		default:
+		}
+	}
+	return nil
+}
+
+// Subscribe registers a subscriber of channel until ctx is done
+func (p *LocalPubSub) Subscribe(ctx context.Context, channel string) (<-chan []byte, error) {
+	subscriber := make(chan []byte, localPubSubBuffer)
+
+	p.mu.Lock()
+	if p.subscribers[channel] == nil {
+		p.subscribers[channel] = make(map[chan []byte]struct{})
+	}
+	p.subscribers[channel][subscriber] = struct{}{}
+	p.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		p.mu.Lock()
+		delete(p.subscribers[channel], subscriber)
+		if len(p.subscribers[channel]) == 0 {
+			delete(p.subscribers, channel)
+		}
+		close(subscriber)
+		p.mu.Unlock()
+	}()
+	return subscriber, nil
+}
+
+// Event hub topics
+const (
+	// HubTopicDocumentStatus carries document processing status changes
+	HubTopicDocumentStatus = "document.status"
+	// HubTopicNotebookChanged carries notebook creations, updates and
+	// deletions; Status is the action
+	HubTopicNotebookChanged = "notebook.changed"
+	// HubTopicAgentChanged carries agent creations, updates and deletions;
+	// Status is the action
+	HubTopicAgentChanged = "agent.changed"
+	// HubTopicStreamEvent carries stored live stream events
+	HubTopicStreamEvent = "stream.event"
+	// HubTopicJobProgress carries processing job state changes; Status is
+	// the job status
+	HubTopicJobProgress = "job.progress"
+	// HubTopicNotification carries notifications for one user, named by
+	// the user_id of the data; Status is the kind of notification
+	HubTopicNotification = "notification"
+)
+
+// Notification kinds
+const (
+	NotificationDocumentProcessed = "document.processed"
+	NotificationDocumentFailed    = "document.failed"
+	NotificationReportReady       = "report.ready"
+	NotificationNotebookDigest    = "notebook.digest"
+	NotificationQuotaThreshold    = "quota.threshold"
+)
+
+// Actions reported as the status of change topics
+const (
+	HubActionCreated = "created"
+	HubActionUpdated = "updated"
+	HubActionDeleted = "deleted"
+)
+
+// HubEvent is a real-time notification fanned out to every replica
+type HubEvent struct {
+	Topic      string                 `json:"topic"`
+	TenantID   string                 `json:"tenant_id"`
+	ResourceID string                 `json:"resource_id"`
+	Status     string                 `json:"status,omitempty"`
+	Data       map[string]interface{} `json:"data,omitempty"`
+	Timestamp  time.Time              `json:"timestamp"`
+}
+
+// EventHubChannel returns the pub/sub channel of a tenant's topic
+func EventHubChannel(tenantID, topic string) string {
+	return fmt.Sprintf("tas:events:%s:%s", tenantID, topic)
+}
+
+// EventHub fans real-time events out over pub/sub channels namespaced by
+// tenant, so WebSocket connections on any replica learn of changes made on
+// another without polling Neo4j. Delivery is best effort: subscribers only
+// receive events published while they are connected.
+type EventHub struct {
+	broker PubSub
+	queue  *DeliveryQueue // Carries notifications when set
+	logger *logger.Logger
+}
+
+// NewEventHub creates an event hub over broker, falling back to in-process
+// delivery when broker is nil
+func NewEventHub(broker PubSub, log *logger.Logger) *EventHub {
+	if broker == nil {
+		broker = NewLocalPubSub()
+	}
+	return &EventHub{
+		broker: broker,
+		logger: log.WithService("event_hub"),
+	}
+}
+
+// SetDeliveryQueue hands notifications to a delivery queue, so one
+// published while Redis or this replica is briefly unavailable still
+// reaches the user's connections, and registers their delivery
+func (h *EventHub) SetDeliveryQueue(queue *DeliveryQueue) {
+	h.queue = queue
+	queue.Handle(DeliveryKindNotification, h.deliverNotification)
+}
+
+// Publish sends an event to the subscribers of its tenant and topic
+func (h *EventHub) Publish(ctx context.Context, event *HubEvent) error {
+	if event.TenantID == "" || event.Topic == "" {
+		return fmt.Errorf("hub event needs a tenant and a topic")
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode hub event: %w", err)
+	}
+	return h.broker.Publish(ctx, EventHubChannel(event.TenantID, event.Topic), payload)
+}
+
+// PublishDocumentStatus announces a document status change
+func (h *EventHub) PublishDocumentStatus(ctx context.Context, tenantID, documentID, status, errorMsg string) {
+	var data map[string]interface{}
+	if errorMsg != "" {
+		data = map[string]interface{}{"error": errorMsg}
+	}
+	h.PublishChange(ctx, HubTopicDocumentStatus, tenantID, documentID, status, data)
+}
+
+// PublishJobProgress announces a processing job's state
+func (h *EventHub) PublishJobProgress(ctx context.Context, job *models.ProcessingJob) {
+	data := map[string]interface{}{"document_id": job.DocumentID}
+	if job.Progress > 0 {
+		data["progress"] = job.Progress
+	}
+	if job.Error != "" {
+		data["error"] = job.Error
+	}
+	h.PublishChange(ctx, HubTopicJobProgress, job.TenantID, job.ID, job.Status, data)
+}
+
+// PublishNotification tells a user that something happened to one of
+// their resources. The message is in English; message_args carries the
+// arguments of the notification's text so subscribers can localize it.
+func (h *EventHub) PublishNotification(ctx context.Context, tenantID, userID, resourceID, kind string, args map[string]string) {
+	data := map[string]interface{}{
+		"user_id":      userID,
+		"message":      i18n.Notification(i18n.DefaultLocale, kind, args),
+		"message_args": args,
+	}
+	if h.queue == nil || tenantID == "" {
+		h.PublishChange(ctx, HubTopicNotification, tenantID, resourceID, kind, data)
+		return
+	}
+
+	event := &HubEvent{
+		Topic:      HubTopicNotification,
+		TenantID:   tenantID,
+		ResourceID: resourceID,
+		Status:     kind,
+		Data:       data,
+		Timestamp:  time.Now(),
+	}
+	if err := h.queue.Enqueue(ctx, DeliveryKindNotification, event); err != nil {
+		h.logger.FromContext(ctx).Warn("Failed to queue notification; publishing it directly",
+			zap.String("kind", kind),
+			zap.String("resource_id", resourceID),
+			zap.Error(err),
+		)
+		h.PublishChange(ctx, HubTopicNotification, tenantID, resourceID, kind, data)
+	}
+}
+
+// deliverNotification publishes a queued notification. It is the delivery
+// queue's handler of notifications; failures are retried by the queue.
+func (h *EventHub) deliverNotification(ctx context.Context, payload json.RawMessage) error {
+	var event HubEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		// Handing it out again would not help
+		h.logger.Error("Dropping malformed notification", zap.Error(err))
+		return nil
+	}
+	return h.Publish(ctx, &event)
+}
+
+// PublishChange announces a change of a tenant's resource. Failures are
+// logged rather than returned: the change is already stored and clients
+// see it on their next read.
+func (h *EventHub) PublishChange(ctx context.Context, topic, tenantID, resourceID, status string, data map[string]interface{}) {
+	if tenantID == "" {
+		h.logger.Debug("Skipping hub event without tenant",
+			zap.String("topic", topic),
+			zap.String("resource_id", resourceID),
+		)
+		return
+	}
+
+	event := &HubEvent{
+		Topic:      topic,
+		TenantID:   tenantID,
+		ResourceID: resourceID,
+		Status:     status,
+		Data:       data,
+	}
+	if err := h.Publish(ctx, event); err != nil {
+		h.logger.FromContext(ctx).Warn("Failed to publish hub event",
+			zap.String("topic", topic),
+			zap.String("resource_id", resourceID),
+			zap.String("status", status),
+			zap.Error(err),
+		)
+	}
+}
+
+// HandleDomainEvent announces document status changes and notebook changes
+// published on the domain event bus, tells the owner of a document when
+// its processing finished, tells the users a report is addressed to that
+// it is ready, tells users what their notebook digests hold, and warns the
+// admins of a space whose quota use reached a threshold
+func (h *EventHub) HandleDomainEvent(ctx context.Context, event Event) error {
+	var action string
+	switch event.Type {
+	case EventDocumentStatusChanged, EventDocumentProcessed, EventDocumentFailed:
+		tenantID, _ := event.Data["tenant_id"].(string)
+		status, _ := event.Data["status"].(string)
+		errorMsg, _ := event.Data["error"].(string)
+		h.PublishDocumentStatus(ctx, tenantID, event.Subject, status, errorMsg)
+		h.notifyDocumentOwner(ctx, event)
+		return nil
+	case EventReportGenerated:
+		h.notifyReportRecipients(ctx, event)
+		return nil
+	case EventNotebookDigestSent:
+		h.notifyDigestRecipients(ctx, event)
+		return nil
+	case EventQuotaThresholdReached:
+		h.notifyQuotaRecipients(ctx, event)
+		return nil
+	case EventNotebookCreated:
+		action = HubActionCreated
+	case EventNotebookUpdated:
+		action = HubActionUpdated
+	case EventNotebookDeleted:
+		action = HubActionDeleted
+	default:
+		return nil
+	}
+
+	tenantID, _ := event.Data["tenant_id"].(string)
+	spaceID, _ := event.Data["space_id"].(string)
+	h.PublishChange(ctx, HubTopicNotebookChanged, tenantID, event.Subject, action, map[string]interface{}{
+		"space_id": spaceID,
+	})
+	return nil
+}
+
+// notifyDocumentOwner tells the owner named by a document processed or
+// failed event about it
+func (h *EventHub) notifyDocumentOwner(ctx context.Context, event Event) {
+	ownerID, _ := event.Data["owner_id"].(string)
+	if ownerID == "" {
+		return
+	}
+	tenantID, _ := event.Data["tenant_id"].(string)
+	name, _ := event.Data["name"].(string)
+
+	switch event.Type {
+	case EventDocumentProcessed:
+		h.PublishNotification(ctx, tenantID, ownerID, event.Subject, NotificationDocumentProcessed,
+			map[string]string{"name": name})
+	case EventDocumentFailed:
+		h.PublishNotification(ctx, tenantID, ownerID, event.Subject, NotificationDocumentFailed,
+			map[string]string{"name": name})
+	}
+}
+
+// notifyReportRecipients tells the users named by a report generated event
+// that the report can be downloaded
+func (h *EventHub) notifyReportRecipients(ctx context.Context, event Event) {
+	tenantID, _ := event.Data["tenant_id"].(string)
+	name, _ := event.Data["name"].(string)
+	for _, userID := range notifyUserIDs(event) {
+		h.PublishNotification(ctx, tenantID, userID, event.Subject, NotificationReportReady,
+			map[string]string{"name": name})
+	}
+}
+
+// notifyDigestRecipients tells the users named by a notebook digest event
+// how many of the notebook's documents are new and changed
+func (h *EventHub) notifyDigestRecipients(ctx context.Context, event Event) {
+	tenantID, _ := event.Data["tenant_id"].(string)
+	name, _ := event.Data["name"].(string)
+	args := map[string]string{
+		"name":    name,
+		"new":     fmt.Sprint(event.Data["new"]),
+		"changed": fmt.Sprint(event.Data["changed"]),
+	}
+	for _, userID := range notifyUserIDs(event) {
+		h.PublishNotification(ctx, tenantID, userID, event.Subject, NotificationNotebookDigest, args)
+	}
+}
+
+// notifyQuotaRecipients warns the users named by a quota threshold event
+// that the space used that share of its quota
+func (h *EventHub) notifyQuotaRecipients(ctx context.Context, event Event) {
+	tenantID, _ := event.Data["tenant_id"].(string)
+	quota, _ := event.Data["quota"].(string)
+	args := map[string]string{
+		"quota":     quota,
+		"threshold": fmt.Sprint(event.Data["threshold"]),
+	}
+	for _, userID := range notifyUserIDs(event) {
+		h.PublishNotification(ctx, tenantID, userID, event.Subject, NotificationQuotaThreshold, args)
+	}
+}
+
+// notifyUserIDs returns the users named by the notify_user_ids of an event
+func notifyUserIDs(event Event) []string {
+	var userIDs []string
+	switch ids := event.Data["notify_user_ids"].(type) {
+	case []string:
+		userIDs = ids
+	case []interface{}:
+		// Decoded from JSON
+		for _, id := range ids {
+			if userID, ok := id.(string); ok {
+				userIDs = append(userIDs, userID)
+			}
+		}
+	}
+	return userIDs
+}
+
+// Subscribe delivers the events of a tenant's topic until ctx is done,
+// then closes the returned channel. Malformed messages are skipped.
+func (h *EventHub) Subscribe(ctx context.Context, tenantID, topic string) (<-chan *HubEvent, error) {
+	messages, err := h.broker.Subscribe(ctx, EventHubChannel(tenantID, topic))
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan *HubEvent)
+	go func() {
+		defer close(events)
+		for payload := range messages {
+			var event HubEvent
+			if err := json.Unmarshal(payload, &event); err != nil {
+				h.logger.Warn("Skipping malformed hub event", zap.Error(err))
+				continue
+			}
+			select {
+			case events <- &event:
+			case <-ctx.Done():
+				// Drain so the broker can release the subscription
+				for range messages {
+				}
+				return
+			}
+		}
+	}()
+	return events, nil
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventHubChannel(t *testing.T) {
+	assert.Equal(t, "tas:events:tenant_1:document.status", EventHubChannel("tenant_1", HubTopicDocumentStatus))
+}
+
+func receiveHubEvent(t *testing.T, events <-chan *HubEvent) *HubEvent {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no hub event received")
+		return nil
+	}
+}
+
+func TestEventHubFansOutPerTenant(t *testing.T) {
+	hub := NewEventHub(nil, setupTestLogger(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	first, err := hub.Subscribe(ctx, "tenant_1", HubTopicDocumentStatus)
+	require.NoError(t, err)
+	second, err := hub.Subscribe(ctx, "tenant_1", HubTopicDocumentStatus)
+	require.NoError(t, err)
+	other, err := hub.Subscribe(ctx, "tenant_2", HubTopicDocumentStatus)
+	require.NoError(t, err)
+
+	hub.PublishDocumentStatus(ctx, "tenant_1", "doc", "failed", "boom")
+
+	for _, events := range []<-chan *HubEvent{first, second} {
+		event := receiveHubEvent(t, events)
+		assert.Equal(t, "doc", event.ResourceID)
+		assert.Equal(t, "failed", event.Status)
+		assert.Equal(t, "boom", event.Data["error"])
+		assert.False(t, event.Timestamp.IsZero())
+	}
+	select {
+	case event := <-other:
+		t.Fatalf("event leaked to another tenant: %+v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestEventHubSubscriptionEndsWithContext(t *testing.T) {
+	broker := NewLocalPubSub()
+	hub := NewEventHub(broker, setupTestLogger(t))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	events, err := hub.Subscribe(ctx, "tenant_1", HubTopicDocumentStatus)
+	require.NoError(t, err)
+	cancel()
+
+	select {
+	case _, ok := <-events:
+		assert.False(t, ok, "the event channel is closed")
+	case <-time.After(time.Second):
+		t.Fatal("subscription did not end")
+	}
+	assert.Eventually(t, func() bool {
+		broker.mu.Lock()
+		defer broker.mu.Unlock()
+		return len(broker.subscribers) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestEventHubAnnouncesDocumentStatusEvents(t *testing.T) {
+	hub := NewEventHub(nil, setupTestLogger(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := hub.Subscribe(ctx, "tenant_1", HubTopicDocumentStatus)
+	require.NoError(t, err)
+
+	require.NoError(t, hub.HandleDomainEvent(ctx, NewDocumentEvent(EventDocumentFailed, "doc", "", map[string]interface{}{
+		"status":    "failed",
+		"error":     "boom",
+		"tenant_id": "tenant_1",
+	})))
+
+	event := receiveHubEvent(t, events)
+	assert.Equal(t, "doc", event.ResourceID)
+	assert.Equal(t, "failed", event.Status)
+	assert.Equal(t, "boom", event.Data["error"])
+}
+
+func TestEventHubRejectsEventWithoutTenant(t *testing.T) {
+	hub := NewEventHub(nil, setupTestLogger(t))
+	assert.Error(t, hub.Publish(context.Background(), &HubEvent{Topic: HubTopicDocumentStatus}))
+}
+
+func TestEventHubNotifiesDocumentOwner(t *testing.T) {
+	hub := NewEventHub(nil, setupTestLogger(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := hub.Subscribe(ctx, "tenant_1", HubTopicNotification)
+	require.NoError(t, err)
+
+	require.NoError(t, hub.HandleDomainEvent(ctx, NewDocumentEvent(EventDocumentProcessed, "doc", "", map[string]interface{}{
+		"status":    "processed",
+		"tenant_id": "tenant_1",
+		"owner_id":  "user_1",
+		"name":      "Report",
+	})))
+
+	event := receiveHubEvent(t, events)
+	assert.Equal(t, "doc", event.ResourceID)
+	assert.Equal(t, NotificationDocumentProcessed, event.Status)
+	assert.Equal(t, "user_1", event.Data["user_id"])
+	assert.Contains(t, event.Data["message"], "Report")
+}
+
+func TestEventHubNotifiesReportRecipients(t *testing.T) {
+	hub := NewEventHub(nil, setupTestLogger(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := hub.Subscribe(ctx, "tenant_1", HubTopicNotification)
+	require.NoError(t, err)
+
+	require.NoError(t, hub.HandleDomainEvent(ctx, Event{
+		Type:    EventReportGenerated,
+		Subject: "run-1",
+		Data: map[string]interface{}{
+			"tenant_id":       "tenant_1",
+			"name":            "Weekly processing",
+			"notify_user_ids": []interface{}{"user_1", "user_2"},
+		},
+	}))
+
+	for _, userID := range []string{"user_1", "user_2"} {
+		event := receiveHubEvent(t, events)
+		assert.Equal(t, "run-1", event.ResourceID)
+		assert.Equal(t, NotificationReportReady, event.Status)
+		assert.Equal(t, userID, event.Data["user_id"])
+		assert.Contains(t, event.Data["message"], "Weekly processing")
+	}
+}
+
+func TestEventHubWarnsQuotaRecipients(t *testing.T) {
+	hub := NewEventHub(nil, setupTestLogger(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := hub.Subscribe(ctx, "tenant_1", HubTopicNotification)
+	require.NoError(t, err)
+
+	require.NoError(t, hub.HandleDomainEvent(ctx, Event{
+		Type:    EventQuotaThresholdReached,
+		Subject: "space-1",
+		Data: map[string]interface{}{
+			"tenant_id":       "tenant_1",
+			"quota":           "storage_bytes",
+			"threshold":       float64(90), // Decoded from JSON
+			"notify_user_ids": []string{"user_1"},
+		},
+	}))
+
+	event := receiveHubEvent(t, events)
+	assert.Equal(t, "space-1", event.ResourceID)
+	assert.Equal(t, NotificationQuotaThreshold, event.Status)
+	assert.Equal(t, "user_1", event.Data["user_id"])
+	assert.Equal(t, "A space has used 90% of its storage_bytes quota", event.Data["message"])
+}
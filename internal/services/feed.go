@@ -0,0 +1,303 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// feedTokenPrefix marks feed tokens so they are recognisable in URLs and
+// secret scanners
+const feedTokenPrefix = "aft_"
+
+// feedTokenUseInterval bounds how often a token's last use is written;
+// feed readers poll every few minutes
+const feedTokenUseInterval = time.Hour
+
+// FeedService manages the tokens feed readers authenticate with and
+// gathers the content of token-authenticated feeds
+type FeedService struct {
+	neo4j  *database.Neo4jClient
+	audit  *AuditService
+	spaces *SpaceService
+	logger *logger.Logger
+}
+
+// NewFeedService creates a new feed service
+func NewFeedService(neo4j *database.Neo4jClient, audit *AuditService, spaces *SpaceService, log *logger.Logger) *FeedService {
+	return &FeedService{
+		neo4j:  neo4j,
+		audit:  audit,
+		spaces: spaces,
+		logger: log.WithService("feed_service"),
+	}
+}
+
+// CreateToken creates a token for one feed. The caller checks that the user
+// may read the feed; the returned secret is not stored and cannot be
+// retrieved again.
+func (s *FeedService) CreateToken(ctx context.Context, userID string, feed models.FeedType, resourceID, spaceID string) (*models.FeedToken, string, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, "", errors.Internal("Failed to generate feed token")
+	}
+	secret := feedTokenPrefix + hex.EncodeToString(random)
+
+	token := &models.FeedToken{
+		ID:         uuid.New().String(),
+		UserID:     userID,
+		Feed:       feed,
+		ResourceID: resourceID,
+		SpaceID:    spaceID,
+		CreatedAt:  time.Now().UTC(),
+	}
+	_, err := s.neo4j.ExecuteQuery(ctx, `
+		CREATE (t:FeedToken {
+			id: $id,
+			token_hash: $token_hash,
+			user_id: $user_id,
+			feed: $feed,
+			resource_id: $resource_id,
+			space_id: $space_id,
+			created_at: datetime($created_at)
+		})
+	`, map[string]interface{}{
+		"id":          token.ID,
+		"token_hash":  hashFeedToken(secret),
+		"user_id":     token.UserID,
+		"feed":        string(token.Feed),
+		"resource_id": token.ResourceID,
+		"space_id":    token.SpaceID,
+		"created_at":  token.CreatedAt.Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return nil, "", errors.Database("Failed to create feed token", err)
+	}
+
+	s.logger.Info("Feed token created",
+		zap.String("token_id", token.ID),
+		zap.String("user_id", userID),
+		zap.String("feed", string(feed)),
+		zap.String("resource_id", resourceID),
+	)
+	return token, secret, nil
+}
+
+// feedTokenFields is the RETURN clause shared by feed token queries
+const feedTokenFields = `
+	t.id AS id, t.user_id AS user_id, t.feed AS feed, t.resource_id AS resource_id,
+	t.space_id AS space_id, t.created_at AS created_at, t.last_used_at AS last_used_at
+`
+
+// ListTokens lists the user's feed tokens, newest first
+func (s *FeedService) ListTokens(ctx context.Context, userID string) ([]*models.FeedToken, error) {
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (t:FeedToken {user_id: $user_id})
+		RETURN `+feedTokenFields+`
+		ORDER BY t.created_at DESC
+	`, map[string]interface{}{"user_id": userID})
+	if err != nil {
+		return nil, errors.Database("Failed to list feed tokens", err)
+	}
+
+	tokens := make([]*models.FeedToken, 0, len(result.Records))
+	for _, record := range result.Records {
+		tokens = append(tokens, recordToFeedToken(record))
+	}
+	return tokens, nil
+}
+
+// RevokeToken deletes one of the user's feed tokens
+func (s *FeedService) RevokeToken(ctx context.Context, userID, tokenID string) error {
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (t:FeedToken {id: $id, user_id: $user_id})
+		DELETE t
+		RETURN count(t) AS deleted
+	`, map[string]interface{}{"id": tokenID, "user_id": userID})
+	if err != nil {
+		return errors.Database("Failed to revoke feed token", err)
+	}
+	if recordInt(result.Records, "deleted") == 0 {
+		return errors.NotFoundWithDetails("Feed token not found", map[string]interface{}{
+			"token_id": tokenID,
+		}).WithErrorCode(errors.CodeFeedTokenNotFound)
+	}
+
+	s.logger.Info("Feed token revoked", zap.String("token_id", tokenID), zap.String("user_id", userID))
+	return nil
+}
+
+// Authenticate returns the token granting access to a feed. resourceID
+// must match the resource the token was created for.
+func (s *FeedService) Authenticate(ctx context.Context, secret string, feed models.FeedType, resourceID string) (*models.FeedToken, error) {
+	invalid := errors.Unauthorized("Feed token is missing, revoked or not valid for this feed").WithErrorCode(errors.CodeFeedTokenInvalid)
+	if secret == "" {
+		return nil, invalid
+	}
+
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (t:FeedToken {token_hash: $token_hash})
+		RETURN `+feedTokenFields, map[string]interface{}{"token_hash": hashFeedToken(secret)})
+	if err != nil {
+		return nil, errors.Database("Failed to check feed token", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, invalid
+	}
+	token := recordToFeedToken(result.Records[0])
+	if token.Feed != feed || token.ResourceID != resourceID {
+		return nil, invalid
+	}
+
+	now := time.Now().UTC()
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) > feedTokenUseInterval {
+		if _, err := s.neo4j.ExecuteQuery(ctx, `
+			MATCH (t:FeedToken {id: $id})
+			SET t.last_used_at = datetime($now)
+		`, map[string]interface{}{"id": token.ID, "now": now.Format(time.RFC3339Nano)}); err != nil {
+			s.logger.Warn("Failed to record feed token use", zap.String("token_id", token.ID), zap.Error(err))
+		}
+	}
+	return token, nil
+}
+
+// NotebookActivity returns the notebook a notebook activity token was
+// created for and up to limit of its most recent audit events, after
+// checking that the token's user can still read it
+func (s *FeedService) NotebookActivity(ctx context.Context, token *models.FeedToken, limit int) (*models.Notebook, []*models.AuditEvent, error) {
+	// Access may have been lost since the token was created
+	notebook, err := s.readableNotebook(ctx, token.ResourceID, token.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The space narrows the search to events the space index finds
+	events, err := s.audit.Query(ctx, models.AuditFilter{
+		NotebookID: notebook.ID,
+		SpaceIDs:   []string{notebook.SpaceID},
+		Limit:      limit,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return notebook, events.Events, nil
+}
+
+// NotebookDocumentChanges returns a notebook and up to limit of the most
+// recent audit events of its documents from from until to, newest first,
+// after checking that the user can read it. The audit log is read a page
+// at a time.
+func (s *FeedService) NotebookDocumentChanges(ctx context.Context, notebookID, userID string, from, to time.Time, limit int) (*models.Notebook, []*models.AuditEvent, error) {
+	notebook, err := s.readableNotebook(ctx, notebookID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var events []*models.AuditEvent
+	for len(events) < limit {
+		page, err := s.audit.Query(ctx, models.AuditFilter{
+			NotebookID:   notebook.ID,
+			ResourceType: "document",
+			SpaceIDs:     []string{notebook.SpaceID},
+			From:         &from,
+			To:           &to,
+			Limit:        min(limit-len(events), pagination.MaxLimit),
+			Offset:       len(events),
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		events = append(events, page.Events...)
+		if !page.HasMore {
+			break
+		}
+	}
+	return notebook, events, nil
+}
+
+// readableNotebook returns a notebook the user owns or is a member of the
+// space of
+func (s *FeedService) readableNotebook(ctx context.Context, notebookID, userID string) (*models.Notebook, error) {
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (n:Notebook {id: $notebook_id})
+		WHERE `+database.SoftDeleteFilter(ctx, "n")+`
+		RETURN n.name AS name, n.owner_id AS owner_id, n.space_id AS space_id, n.tenant_id AS tenant_id
+	`, map[string]interface{}{"notebook_id": notebookID})
+	if err != nil {
+		return nil, errors.Database("Failed to get notebook", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, errors.NotFoundWithDetails("Notebook not found", map[string]interface{}{
+			"notebook_id": notebookID,
+		}).WithErrorCode(errors.CodeNotebookNotFound)
+	}
+	record := result.Records[0]
+	text := func(key string) string {
+		value, _ := record.Get(key)
+		str, _ := value.(string)
+		return str
+	}
+	notebook := &models.Notebook{
+		ID:       notebookID,
+		Name:     text("name"),
+		OwnerID:  text("owner_id"),
+		SpaceID:  text("space_id"),
+		TenantID: text("tenant_id"),
+	}
+
+	if notebook.OwnerID != userID {
+		role, err := s.spaces.GetUserRoleInSpace(ctx, notebook.SpaceID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if role == "" {
+			return nil, errors.ForbiddenWithDetails("Notebook not accessible", map[string]interface{}{
+				"notebook_id": notebook.ID,
+			}).WithErrorCode(errors.CodeNotebookNotAccessible)
+		}
+	}
+	return notebook, nil
+}
+
+// hashFeedToken returns the stored form of a feed token
+func hashFeedToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func recordToFeedToken(record *neo4j.Record) *models.FeedToken {
+	text := func(key string) string {
+		value, _ := record.Get(key)
+		str, _ := value.(string)
+		return str
+	}
+
+	token := &models.FeedToken{
+		ID:         text("id"),
+		UserID:     text("user_id"),
+		Feed:       models.FeedType(text("feed")),
+		ResourceID: text("resource_id"),
+		SpaceID:    text("space_id"),
+	}
+	if value, _ := record.Get("created_at"); value != nil {
+		token.CreatedAt, _ = value.(time.Time)
+	}
+	if value, _ := record.Get("last_used_at"); value != nil {
+		if t, ok := value.(time.Time); ok {
+			token.LastUsedAt = &t
+		}
+	}
+	return token
+}
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/filetype"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// FileValidator checks uploaded files against their content: the MIME type
+// a client declares and the file's extension must agree with what the file
+// is, and the type must be one the deployment and the space accept
+type FileValidator struct {
+	// allowedTypes are the types the deployment accepts; empty accepts all
+	allowedTypes []string
+	logger       *logger.Logger
+}
+
+// NewFileValidator creates the validator of uploaded files, or returns nil
+// when validation is turned off
+func NewFileValidator(cfg config.FileValidationConfig, log *logger.Logger) *FileValidator {
+	if !cfg.Validate {
+		return nil
+	}
+	return &FileValidator{
+		allowedTypes: cfg.AllowedTypes,
+		logger:       log.WithService("file_validator"),
+	}
+}
+
+// Check validates a file uploaded to a space with the given settings and
+// returns the MIME type to store it under: the declared type when the
+// content agrees with it, or the detected one when the client declared
+// none or only application/octet-stream
+func (v *FileValidator) Check(settings map[string]interface{}, fileName, declared string, data []byte) (string, error) {
+	if v == nil {
+		return declared, nil
+	}
+	space := models.SpaceFileValidationSettings(settings)
+	if err := checkSpaceFileSize(space, int64(len(data))); err != nil {
+		return "", err
+	}
+
+	claimed := filetype.Normalize(declared)
+	if claimed == filetype.OctetStream {
+		claimed = ""
+	}
+	extensionTypes := filetype.ForExtension(fileName)
+	if len(data) == 0 {
+		// An empty file has no content to contradict its type
+		return v.checkAllowed(space, resolveFileType(claimed, "", extensionTypes, declared))
+	}
+
+	detected := filetype.Detect(data)
+	if claimed != "" && !filetype.Matches(detected, claimed) {
+		v.logger.Warn("Upload content does not match its declared type",
+			zap.String("file_name", fileName),
+			zap.String("declared_type", claimed),
+			zap.String("detected_type", detected))
+		return "", fileTypeMismatch(fileName, claimed, detected, extensionTypes,
+			"The file's content does not match the MIME type it was sent with. Send the file with its actual type, or check that the right file was selected.")
+	}
+	if len(extensionTypes) > 0 && !filetype.Matches(detected, extensionTypes[0]) {
+		v.logger.Warn("Upload content does not match its extension",
+			zap.String("file_name", fileName),
+			zap.String("detected_type", detected))
+		return "", fileTypeMismatch(fileName, claimed, detected, extensionTypes,
+			"The file's content does not match its extension. Rename the file with the extension of its actual type, or check that the right file was selected.")
+	}
+
+	return v.checkAllowed(space, resolveFileType(claimed, detected, extensionTypes, declared))
+}
+
+// CheckDeclared validates a file before its content is sent, as for
+// resumable and direct uploads, by its size, name and declared MIME type.
+// The content is checked by Check once it arrives, where it can be.
+func (v *FileValidator) CheckDeclared(settings map[string]interface{}, fileName, declared string, sizeBytes int64) error {
+	if v == nil {
+		return nil
+	}
+	space := models.SpaceFileValidationSettings(settings)
+	if err := checkSpaceFileSize(space, sizeBytes); err != nil {
+		return err
+	}
+
+	claimed := filetype.Normalize(declared)
+	if claimed == filetype.OctetStream {
+		claimed = ""
+	}
+	extensionTypes := filetype.ForExtension(fileName)
+	if claimed != "" && len(extensionTypes) > 0 && !filetype.Matches(extensionTypes[0], claimed) {
+		return fileTypeMismatch(fileName, claimed, "", extensionTypes,
+			"The file's extension does not match the MIME type it was declared with. Declare the type of the file's actual format.")
+	}
+
+	_, err := v.checkAllowed(space, resolveFileType(claimed, "", extensionTypes, declared))
+	return err
+}
+
+// checkAllowed rejects a type the deployment or the space does not accept
+func (v *FileValidator) checkAllowed(space *models.FileValidationSettings, mimeType string) (string, error) {
+	if !typeAllowed(mimeType, v.allowedTypes) {
+		return "", fileTypeNotAllowed(mimeType, v.allowedTypes, "deployment")
+	}
+	if !typeAllowed(mimeType, space.AllowedTypes) {
+		return "", fileTypeNotAllowed(mimeType, space.AllowedTypes, "space")
+	}
+	return mimeType, nil
+}
+
+// resolveFileType picks the type to store a file under: the declared type,
+// then a specific detected type, then the extension's type, then whatever
+// was detected
+func resolveFileType(claimed, detected string, extensionTypes []string, declared string) string {
+	switch {
+	case claimed != "":
+		// Keep parameters such as the charset the client sent
+		return declared
+	case detected != "" && detected != filetype.OctetStream && detected != filetype.TextPlain &&
+		detected != filetype.Zip && detected != filetype.OLEStorage:
+		return detected
+	case len(extensionTypes) > 0:
+		return extensionTypes[0]
+	case detected != "" && detected != filetype.OLEStorage:
+		return detected
+	}
+	return filetype.OctetStream
+}
+
+// typeAllowed reports whether a type matches one of a list of patterns; an
+// empty list allows every type
+func typeAllowed(mimeType string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if filetype.MatchesPattern(mimeType, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSpaceFileSize rejects a file over the space's size limit
+func checkSpaceFileSize(space *models.FileValidationSettings, sizeBytes int64) error {
+	if space.MaxSizeBytes > 0 && sizeBytes > space.MaxSizeBytes {
+		return errors.ValidationWithDetails("File exceeds the space's size limit", map[string]interface{}{
+			"max_bytes":  space.MaxSizeBytes,
+			"size_bytes": sizeBytes,
+		}).WithErrorCode(errors.CodeFileTooLarge)
+	}
+	return nil
+}
+
+// fileTypeMismatch is the error for a file whose content, extension and
+// declared type disagree
+func fileTypeMismatch(fileName, declared, detected string, extensionTypes []string, hint string) error {
+	details := map[string]interface{}{
+		"file_name": fileName,
+		"extension": strings.ToLower(filepath.Ext(fileName)),
+		"hint":      hint,
+	}
+	if declared != "" {
+		details["declared_type"] = declared
+	}
+	if detected != "" {
+		details["detected_type"] = detected
+	}
+	if len(extensionTypes) > 0 {
+		details["expected_types"] = extensionTypes
+	}
+	return errors.NewAPIError(errors.ErrUnprocessableEntity, "File type does not match its content", details).
+		WithErrorCode(errors.CodeFileTypeMismatch)
+}
+
+// fileTypeNotAllowed is the error for a file of a type the deployment or
+// the space does not accept
+func fileTypeNotAllowed(mimeType string, allowed []string, restrictedBy string) error {
+	return errors.NewAPIError(errors.ErrUnsupportedMediaType, "File type is not allowed", map[string]interface{}{
+		"mime_type":     mimeType,
+		"allowed_types": allowed,
+		"restricted_by": restrictedBy,
+		"hint":          "Upload the file in one of the allowed types, or ask an administrator to allow this one.",
+	}).WithErrorCode(errors.CodeFileTypeNotAllowed)
+}
+
+// SetFileValidator sets the validator checking uploaded files against
+// their content; nil trusts the types clients declare
+func (s *DocumentService) SetFileValidator(validator *FileValidator) {
+	s.fileValidator = validator
+}
+
+// validateUpload checks an uploaded file against its content and the
+// space's restrictions, returning its file info with the MIME type to
+// store it under
+func (s *DocumentService) validateUpload(ctx context.Context, spaceCtx *models.SpaceContext, fileInfo models.FileInfo, data []byte) (models.FileInfo, error) {
+	if s.fileValidator == nil {
+		return fileInfo, nil
+	}
+	mimeType, err := s.fileValidator.Check(s.spaceSettings(ctx, spaceCtx.SpaceID), fileInfo.OriginalName, fileInfo.MimeType, data)
+	if err != nil {
+		return fileInfo, err
+	}
+	fileInfo.MimeType = mimeType
+	return fileInfo, nil
+}
+
+// validateDeclaredUpload checks a file whose content has not arrived yet by
+// its name, declared type and size
+func (s *DocumentService) validateDeclaredUpload(ctx context.Context, spaceCtx *models.SpaceContext, fileName, mimeType string, sizeBytes int64) error {
+	if s.fileValidator == nil {
+		return nil
+	}
+	return s.fileValidator.CheckDeclared(s.spaceSettings(ctx, spaceCtx.SpaceID), fileName, mimeType, sizeBytes)
+}
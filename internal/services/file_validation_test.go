@@ -0,0 +1,101 @@
+package services
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+func TestFileValidatorCheck(t *testing.T) {
+	validator := NewFileValidator(config.FileValidationConfig{Validate: true}, setupTestLogger(t))
+	pdf := []byte("%PDF-1.7\n1 0 obj")
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+	mimeType, err := validator.Check(nil, "report.pdf", "application/pdf", pdf)
+	require.NoError(t, err)
+	assert.Equal(t, "application/pdf", mimeType)
+
+	mimeType, err = validator.Check(nil, "report.pdf", "", pdf)
+	require.NoError(t, err)
+	assert.Equal(t, "application/pdf", mimeType, "a missing type is detected")
+
+	mimeType, err = validator.Check(nil, "notes.csv", "application/octet-stream", []byte("a,b\n1,2\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "text/csv", mimeType, "text takes its extension's type")
+
+	mimeType, err = validator.Check(nil, "notes.csv", "text/csv; charset=utf-8", []byte("a,b\n1,2\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "text/csv; charset=utf-8", mimeType, "the declared type is kept")
+
+	_, err = validator.Check(nil, "report.pdf", "application/pdf", png)
+	apiErr, ok := errors.AsAPIError(err)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusUnprocessableEntity, apiErr.StatusCode)
+	assert.Equal(t, errors.CodeFileTypeMismatch, apiErr.ErrorCode)
+	assert.Equal(t, "image/png", apiErr.Details["detected_type"])
+	assert.Equal(t, ".pdf", apiErr.Details["extension"])
+
+	_, err = validator.Check(nil, "report.pdf", "image/png", png)
+	apiErr, ok = errors.AsAPIError(err)
+	require.True(t, ok, "the extension must agree with the content too")
+	assert.Equal(t, errors.CodeFileTypeMismatch, apiErr.ErrorCode)
+
+	var nilValidator *FileValidator
+	mimeType, err = nilValidator.Check(nil, "report.pdf", "image/png", pdf)
+	require.NoError(t, err, "a nil validator trusts the declared type")
+	assert.Equal(t, "image/png", mimeType)
+}
+
+func TestFileValidatorAllowedTypes(t *testing.T) {
+	validator := NewFileValidator(config.FileValidationConfig{
+		Validate:     true,
+		AllowedTypes: []string{"application/pdf", "image/*", "text/*"},
+	}, setupTestLogger(t))
+	settings := map[string]interface{}{
+		models.FileValidationSettingsKey: map[string]interface{}{
+			"allowed_types":  []interface{}{"application/pdf"},
+			"max_size_bytes": float64(20),
+		},
+	}
+
+	_, err := validator.Check(nil, "image.png", "", []byte("\x89PNG\r\n\x1a\n"))
+	require.NoError(t, err)
+
+	_, err = validator.Check(nil, "data.json", "application/json", []byte(`{"a":1}`))
+	apiErr, ok := errors.AsAPIError(err)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusUnsupportedMediaType, apiErr.StatusCode)
+	assert.Equal(t, errors.CodeFileTypeNotAllowed, apiErr.ErrorCode)
+	assert.Equal(t, "deployment", apiErr.Details["restricted_by"])
+
+	_, err = validator.Check(settings, "image.png", "", []byte("\x89PNG\r\n\x1a\n"))
+	apiErr, ok = errors.AsAPIError(err)
+	require.True(t, ok)
+	assert.Equal(t, "space", apiErr.Details["restricted_by"])
+
+	_, err = validator.Check(settings, "report.pdf", "", []byte("%PDF-1.7\n1 0 obj\n<< /Type /Catalog >>"))
+	apiErr, ok = errors.AsAPIError(err)
+	require.True(t, ok)
+	assert.Equal(t, errors.CodeFileTooLarge, apiErr.ErrorCode)
+
+	assert.Nil(t, NewFileValidator(config.FileValidationConfig{}, setupTestLogger(t)), "validation can be turned off")
+}
+
+func TestFileValidatorCheckDeclared(t *testing.T) {
+	validator := NewFileValidator(config.FileValidationConfig{Validate: true}, setupTestLogger(t))
+
+	require.NoError(t, validator.CheckDeclared(nil, "video.mp4", "video/mp4", 1<<30))
+	require.NoError(t, validator.CheckDeclared(nil, "archive.bin", "", 1<<30))
+
+	err := validator.CheckDeclared(nil, "video.mp4", "application/pdf", 1<<30)
+	apiErr, ok := errors.AsAPIError(err)
+	require.True(t, ok)
+	assert.Equal(t, errors.CodeFileTypeMismatch, apiErr.ErrorCode)
+	assert.Equal(t, []string{"video/mp4"}, apiErr.Details["expected_types"])
+}
@@ -0,0 +1,286 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/validation"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// ImportService imports the export archives of other tools, such as a
+// Notion export or a Google Takeout archive, into notebooks and documents
+type ImportService struct {
+	notebooks *NotebookService
+	documents *DocumentService
+	logger    *logger.Logger
+
+	// maxFiles bounds the files of an archive, and maxFileBytes each file
+	maxFiles     int
+	maxFileBytes int64
+}
+
+// NewImportService creates a new import service
+func NewImportService(notebooks *NotebookService, documents *DocumentService, maxFiles int, maxFileBytes int64, log *logger.Logger) *ImportService {
+	return &ImportService{
+		notebooks:    notebooks,
+		documents:    documents,
+		logger:       log.WithService("import_service"),
+		maxFiles:     maxFiles,
+		maxFileBytes: maxFileBytes,
+	}
+}
+
+// ImportArchive is an export archive checked and planned for import. Close
+// releases the archive and removes its file.
+type ImportArchive struct {
+	format   string
+	name     string
+	parentID string
+	filePath string
+	reader   *zip.ReadCloser
+	items    []*importItem
+}
+
+// Close closes the archive and removes its file
+func (a *ImportArchive) Close() error {
+	err := a.reader.Close()
+	if removeErr := os.Remove(a.filePath); err == nil && !os.IsNotExist(removeErr) {
+		err = removeErr
+	}
+	return err
+}
+
+// Open checks the export archive at filePath and plans its import, so a
+// request with a bad archive fails before the import job starts. The
+// archive's notebook is named name, and created under the notebook
+// parentID when given. The file is removed when Open fails, and otherwise
+// when the archive is closed.
+func (s *ImportService) Open(ctx context.Context, format, filePath, name, parentID, userID string, spaceCtx *models.SpaceContext) (*ImportArchive, error) {
+	remove := func() { os.Remove(filePath) }
+
+	importer, ok := exportImporters[format]
+	if !ok {
+		remove()
+		return nil, errors.ValidationWithDetails("Invalid import format", map[string]interface{}{
+			"format":  format,
+			"formats": models.ImportFormats,
+		})
+	}
+	if !spaceCtx.CanCreate() {
+		remove()
+		return nil, errors.Forbidden("Insufficient permissions to import documents")
+	}
+	if parentID != "" {
+		parent, err := s.notebooks.GetNotebookByID(ctx, parentID, userID, spaceCtx)
+		if err != nil {
+			remove()
+			return nil, err
+		}
+		if parent.TenantID != spaceCtx.TenantID || parent.SpaceID != spaceCtx.SpaceID {
+			remove()
+			return nil, errors.ForbiddenWithDetails("Notebook not accessible in this space", map[string]interface{}{
+				"notebook_id": parentID,
+				"space_id":    spaceCtx.SpaceID,
+			}).WithErrorCode(errors.CodeNotebookNotAccessible)
+		}
+	}
+
+	reader, err := zip.OpenReader(filePath)
+	if err != nil {
+		remove()
+		return nil, errors.Validation("The file is not a zip archive", nil)
+	}
+	archive := &ImportArchive{
+		format:   format,
+		name:     validation.SanitizeFilename(strings.TrimSuffix(name, path.Ext(name))),
+		parentID: parentID,
+		filePath: filePath,
+		reader:   reader,
+	}
+
+	if len(reader.File) > s.maxFiles {
+		archive.Close()
+		return nil, errors.ValidationWithDetails("The archive holds too many files", map[string]interface{}{
+			"max_files": s.maxFiles,
+		})
+	}
+	archive.items, err = importer.plan(reader.File)
+	if err != nil {
+		archive.Close()
+		return nil, err
+	}
+	imported := 0
+	for _, item := range archive.items {
+		if item.skip == "" && item.err == nil {
+			imported++
+		}
+	}
+	if imported == 0 {
+		archive.Close()
+		return nil, errors.Validation("The archive holds no files to import", nil)
+	}
+	return archive, nil
+}
+
+// Run imports a planned archive: a notebook is created for the archive,
+// its folders become notebooks under it and its files documents, which are
+// submitted for processing like uploads. Items succeed or fail on their
+// own and are reported in the result in archive order. progress is told
+// after each item.
+func (s *ImportService) Run(ctx context.Context, archive *ImportArchive, userID string, spaceCtx *models.SpaceContext, progress func(models.JobProgress)) (*models.ImportResult, error) {
+	root, err := s.notebooks.CreateNotebook(ctx, models.NotebookCreateRequest{
+		Name:        archive.name,
+		Description: "Imported from a " + archive.format + " export",
+		Visibility:  "private",
+		ParentID:    archive.parentID,
+	}, userID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.ImportResult{
+		Format:     archive.format,
+		NotebookID: root.ID,
+		Notebooks:  1,
+		Items:      make([]*models.ImportItemResult, 0, len(archive.items)),
+	}
+	// Notebooks created for the archive's folders, keyed by folder path
+	folders := map[string]string{"": root.ID}
+	importer := exportImporters[archive.format]
+
+	for i, item := range archive.items {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		itemResult := &models.ImportItemResult{Path: item.path}
+		switch {
+		case item.skip != "":
+			itemResult.Status = models.ImportItemSkipped
+			itemResult.Reason = item.skip
+			result.Skipped++
+		case item.err != nil:
+			itemResult.Status = models.ImportItemFailed
+			itemResult.Error = BulkUploadFailure(item.path, item.err).Error
+			result.Failed++
+		default:
+			notebookID, err := s.folderNotebook(ctx, folders, item.folders, result, userID, spaceCtx)
+			if err == nil {
+				itemResult.NotebookID = notebookID
+				var document *models.Document
+				document, err = s.importItem(ctx, importer, item, notebookID, userID, spaceCtx)
+				if err == nil {
+					itemResult.DocumentID = document.ID
+				}
+			}
+			if err != nil {
+				s.logger.Warn("Import item failed",
+					zap.String("notebook_id", root.ID),
+					zap.String("path", item.path),
+					zap.Error(err))
+				itemResult.Status = models.ImportItemFailed
+				itemResult.Error = BulkUploadFailure(item.path, err).Error
+				result.Failed++
+			} else {
+				itemResult.Status = models.ImportItemCreated
+				result.Documents++
+			}
+		}
+		result.Items = append(result.Items, itemResult)
+
+		if progress != nil {
+			progress(models.JobProgress{Total: len(archive.items), Completed: i + 1, Failed: result.Failed})
+		}
+	}
+
+	s.logger.Info("Import completed",
+		zap.String("format", archive.format),
+		zap.String("notebook_id", root.ID),
+		zap.Int("documents", result.Documents),
+		zap.Int("skipped", result.Skipped),
+		zap.Int("failed", result.Failed))
+	return result, nil
+}
+
+// folderNotebook returns the notebook of an archive folder, creating it
+// and the notebooks of its parent folders the first time they are needed
+func (s *ImportService) folderNotebook(ctx context.Context, folders map[string]string, names []string, result *models.ImportResult, userID string, spaceCtx *models.SpaceContext) (string, error) {
+	parentID := folders[""]
+	for i := range names {
+		key := strings.Join(names[:i+1], "/")
+		if id, ok := folders[key]; ok {
+			parentID = id
+			continue
+		}
+		notebook, err := s.notebooks.CreateNotebook(ctx, models.NotebookCreateRequest{
+			Name:       validation.SanitizeFilename(names[i]),
+			Visibility: "private",
+			ParentID:   parentID,
+		}, userID, spaceCtx)
+		if err != nil {
+			return "", err
+		}
+		folders[key] = notebook.ID
+		result.Notebooks++
+		parentID = notebook.ID
+	}
+	return parentID, nil
+}
+
+// importItem reads a file of the archive and uploads it as a document
+func (s *ImportService) importItem(ctx context.Context, importer exportImporter, item *importItem, notebookID, userID string, spaceCtx *models.SpaceContext) (*models.Document, error) {
+	if int64(item.file.UncompressedSize64) > s.maxFileBytes {
+		return nil, s.fileTooLarge()
+	}
+	reader, err := item.file.Open()
+	if err != nil {
+		return nil, errors.Validation("The file cannot be read from the archive", nil)
+	}
+	defer reader.Close()
+	// The size in the zip header is not trusted, a file could be larger
+	data, err := io.ReadAll(io.LimitReader(reader, s.maxFileBytes+1))
+	if err != nil {
+		return nil, errors.Validation("The file cannot be read from the archive", nil)
+	}
+	if int64(len(data)) > s.maxFileBytes {
+		return nil, s.fileTooLarge()
+	}
+
+	importer.annotate(item, data)
+
+	mimeType := mime.TypeByExtension(path.Ext(item.name))
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+	return s.documents.UploadDocument(ctx, models.DocumentUploadRequest{
+		DocumentCreateRequest: models.DocumentCreateRequest{
+			Name:       item.name,
+			NotebookID: notebookID,
+			Tags:       item.tags,
+			Metadata:   item.metadata,
+		},
+		FileData: data,
+	}, userID, spaceCtx, models.FileInfo{
+		OriginalName: item.name,
+		MimeType:     mimeType,
+		SizeBytes:    int64(len(data)),
+	})
+}
+
+// fileTooLarge is the error of a file over the upload size limit
+func (s *ImportService) fileTooLarge() error {
+	return errors.ValidationWithDetails("File exceeds the upload size limit", map[string]interface{}{
+		"max_bytes": s.maxFileBytes,
+	}).WithErrorCode(errors.CodeFileTooLarge)
+}
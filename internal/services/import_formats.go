@@ -0,0 +1,375 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/validation"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// maxImportListingBytes bounds the SharePoint listing read while an import
+// is planned
+const maxImportListingBytes = 10 << 20
+
+// importItem is an entry of an export archive and the document it becomes
+type importItem struct {
+	path     string   // Path of the entry in the archive
+	folders  []string // Notebooks under the import's notebook, outermost first
+	name     string   // Name of the document
+	file     *zip.File
+	tags     []string
+	metadata map[string]interface{}
+	skip     string // Why the entry is not imported
+	err      error  // Why the entry cannot be imported
+}
+
+// exportImporter maps the entries of an export archive to the notebooks
+// and documents they become
+type exportImporter interface {
+	// plan lists the items of an archive, in the order they are imported
+	plan(files []*zip.File) ([]*importItem, error)
+	// annotate adds what the content of an item tells about it, such as
+	// the properties of a Notion page
+	annotate(item *importItem, data []byte)
+}
+
+// exportImporters are the importers of each import format
+var exportImporters = map[string]exportImporter{
+	models.ImportFormatNotion:      notionImporter{},
+	models.ImportFormatGoogleDrive: googleDriveImporter{},
+	models.ImportFormatSharePoint:  sharePointImporter{},
+}
+
+// archiveFile is a file of an export archive at a cleaned path
+type archiveFile struct {
+	file *zip.File
+	path string
+}
+
+// archiveFiles returns the files of an archive worth importing, leaving out
+// directories and the metadata files operating systems add. Files whose
+// path leaves the archive are returned as skipped items.
+func archiveFiles(files []*zip.File) ([]archiveFile, []*importItem) {
+	var kept []archiveFile
+	var unsafe []*importItem
+	for _, file := range files {
+		name := strings.ReplaceAll(file.Name, "\\", "/")
+		if file.FileInfo().IsDir() || strings.HasSuffix(name, "/") {
+			continue
+		}
+		cleaned := path.Clean(name)
+		if path.IsAbs(name) || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+			unsafe = append(unsafe, &importItem{path: file.Name, skip: "The path leaves the archive"})
+			continue
+		}
+		base := path.Base(cleaned)
+		if strings.HasPrefix(cleaned, "__MACOSX/") || strings.HasPrefix(base, ".") || base == "Thumbs.db" || base == "desktop.ini" {
+			continue
+		}
+		kept = append(kept, archiveFile{file: file, path: cleaned})
+	}
+	return kept, unsafe
+}
+
+// newImportItem returns the item of a file at archivePath, placed in the
+// notebooks named by folders. Every item records where it came from.
+func newImportItem(source string, file archiveFile, folders []string, name string) *importItem {
+	metadata := map[string]interface{}{
+		"import_source": source,
+		"source_path":   file.path,
+	}
+	if modified := file.file.Modified; !modified.IsZero() {
+		metadata["source_modified_at"] = modified.UTC()
+	}
+	return &importItem{
+		path:     file.path,
+		folders:  folders,
+		name:     validation.SanitizeFilename(name),
+		file:     file.file,
+		metadata: metadata,
+	}
+}
+
+// splitArchivePath splits a cleaned archive path into its folders and name
+func splitArchivePath(p string) ([]string, string) {
+	parts := strings.Split(p, "/")
+	return parts[:len(parts)-1], parts[len(parts)-1]
+}
+
+// notionIDSuffix matches the page ID Notion appends to exported names
+var notionIDSuffix = regexp.MustCompile(` ([0-9a-f]{32})$`)
+
+// notionProperty matches a property line of an exported Notion page
+var notionProperty = regexp.MustCompile(`^([^:]{1,64}): (.*)$`)
+
+// notionImporter imports Notion "Markdown & CSV" exports. Pages are
+// Markdown files and their sub-pages and attachments sit in a folder named
+// like the page, which becomes a notebook. The page IDs Notion appends to
+// names are removed and kept as metadata, and page properties are kept as
+// metadata, their tags as tags. A database is exported twice, as its view
+// and as name_all.csv with every row; only the latter is imported.
+type notionImporter struct{}
+
+// stripNotionID removes the page ID from an exported name, returning the
+// name and the ID
+func stripNotionID(name string) (string, string) {
+	ext := path.Ext(name)
+	if ext == ".md" || ext == ".csv" || ext == ".html" {
+		name = strings.TrimSuffix(name, ext)
+	} else {
+		ext = ""
+	}
+	if match := notionIDSuffix.FindStringSubmatchIndex(name); match != nil {
+		return name[:match[0]] + ext, name[match[2]:match[3]]
+	}
+	return name + ext, ""
+}
+
+func (notionImporter) plan(files []*zip.File) ([]*importItem, error) {
+	kept, items := archiveFiles(files)
+
+	all := make(map[string]bool)
+	for _, file := range kept {
+		if strings.HasSuffix(file.path, "_all.csv") {
+			all[strings.TrimSuffix(file.path, "_all.csv")+".csv"] = true
+		}
+	}
+
+	for _, file := range kept {
+		folders, name := splitArchivePath(file.path)
+		for i, folder := range folders {
+			folders[i], _ = stripNotionID(folder)
+		}
+		if all[file.path] {
+			items = append(items, &importItem{path: file.path, skip: "Database view; the export of all its rows is imported"})
+			continue
+		}
+		name = strings.Replace(name, "_all.csv", ".csv", 1)
+		title, pageID := stripNotionID(name)
+		item := newImportItem(models.ImportFormatNotion, file, folders, title)
+		if pageID != "" {
+			item.metadata["notion_id"] = pageID
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// annotate reads the properties Notion writes below a page's title, one
+// "Name: value" line each
+func (notionImporter) annotate(item *importItem, data []byte) {
+	if path.Ext(item.path) != ".md" {
+		return
+	}
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	i := 0
+	for i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+		i++
+	}
+	if i == len(lines) || !strings.HasPrefix(lines[i], "# ") {
+		return
+	}
+	for i++; i < len(lines) && strings.TrimSpace(lines[i]) == ""; i++ {
+	}
+
+	properties := make(map[string]interface{})
+	for ; i < len(lines); i++ {
+		match := notionProperty.FindStringSubmatch(lines[i])
+		if match == nil {
+			break
+		}
+		key, value := strings.TrimSpace(match[1]), strings.TrimSpace(match[2])
+		properties[key] = value
+		if strings.EqualFold(key, "tags") {
+			for _, tag := range strings.Split(value, ",") {
+				// Tags are single words; multi-word Notion tags are hyphenated
+				tag = strings.Join(strings.Fields(tag), "-")
+				if tag = validation.SanitizeTag(tag); tag != "" {
+					item.tags = append(item.tags, tag)
+				}
+			}
+		}
+	}
+	if len(properties) > 0 {
+		item.metadata["notion_properties"] = properties
+	}
+}
+
+// googleDriveImporter imports Google Takeout archives of Drive, and zipped
+// Drive folders. Folders become notebooks; the Takeout/Drive prefix and
+// Takeout's archive browser page are left out.
+type googleDriveImporter struct{}
+
+func (googleDriveImporter) plan(files []*zip.File) ([]*importItem, error) {
+	kept, items := archiveFiles(files)
+	for _, file := range kept {
+		folders, name := splitArchivePath(file.path)
+		if len(folders) > 0 && folders[0] == "Takeout" {
+			if len(folders) == 1 {
+				items = append(items, &importItem{path: file.path, skip: "Takeout index, not a Drive file"})
+				continue
+			}
+			folders = folders[1:]
+			if folders[0] == "Drive" {
+				folders = folders[1:]
+			}
+		}
+		items = append(items, newImportItem(models.ImportFormatGoogleDrive, file, folders, name))
+	}
+	return items, nil
+}
+
+func (googleDriveImporter) annotate(item *importItem, data []byte) {}
+
+// sharePointImporter imports a zipped SharePoint document library together
+// with the library's "Export to CSV" listing, the one CSV file at the root
+// of the archive. Folders become notebooks; the listing's columns, such as
+// Modified By or custom columns, are kept as metadata of the file on the
+// same row. Listed files missing from the archive fail.
+type sharePointImporter struct{}
+
+// sharePointRow is a file row of a library listing
+type sharePointRow struct {
+	path    string // Library path of the file, without leading slash
+	columns map[string]interface{}
+	matched bool
+}
+
+func (sharePointImporter) plan(files []*zip.File) ([]*importItem, error) {
+	kept, items := archiveFiles(files)
+
+	var listing *archiveFile
+	var documents []archiveFile
+	for i, file := range kept {
+		if !strings.Contains(file.path, "/") && strings.EqualFold(path.Ext(file.path), ".csv") {
+			if listing != nil {
+				return nil, errors.Validation("The archive holds more than one CSV file at its root; keep only the library listing there", nil)
+			}
+			listing = &kept[i]
+			continue
+		}
+		documents = append(documents, file)
+	}
+	if listing == nil {
+		return nil, errors.Validation("SharePoint imports need the library's Export to CSV listing at the root of the archive", nil)
+	}
+	rows, err := readSharePointListing(listing.file)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range documents {
+		folders, name := splitArchivePath(file.path)
+		item := newImportItem(models.ImportFormatSharePoint, file, folders, name)
+		if row := matchSharePointRow(rows, file.path); row != nil {
+			row.matched = true
+			if len(row.columns) > 0 {
+				item.metadata["sharepoint_columns"] = row.columns
+			}
+		}
+		items = append(items, item)
+	}
+	for _, row := range rows {
+		if !row.matched {
+			items = append(items, &importItem{
+				path: row.path,
+				err:  errors.NotFound("The listed file is not in the archive"),
+			})
+		}
+	}
+	return items, nil
+}
+
+func (sharePointImporter) annotate(item *importItem, data []byte) {}
+
+// readSharePointListing reads the file rows of a library listing. Name is
+// required; Path, the folder of the file, and Item Type are optional.
+func readSharePointListing(file *zip.File) ([]*sharePointRow, error) {
+	invalid := func() error {
+		return errors.ValidationWithDetails("The library listing is not a valid Export to CSV file", map[string]interface{}{
+			"path": file.Name,
+		})
+	}
+	reader, err := file.Open()
+	if err != nil {
+		return nil, invalid()
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(io.LimitReader(reader, maxImportListingBytes+1))
+	if err != nil || len(data) > maxImportListingBytes {
+		return nil, invalid()
+	}
+
+	records := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	records.FieldsPerRecord = -1
+	header, err := records.Read()
+	if err != nil {
+		return nil, invalid()
+	}
+	column := func(name string) int {
+		for i, heading := range header {
+			if strings.EqualFold(strings.TrimSpace(heading), name) {
+				return i
+			}
+		}
+		return -1
+	}
+	nameColumn, pathColumn, typeColumn := column("Name"), column("Path"), column("Item Type")
+	if nameColumn < 0 {
+		return nil, invalid()
+	}
+
+	var rows []*sharePointRow
+	for {
+		record, err := records.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, invalid()
+		}
+		value := func(i int) string {
+			if i < 0 || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		if value(nameColumn) == "" || strings.EqualFold(value(typeColumn), "Folder") {
+			continue
+		}
+
+		row := &sharePointRow{path: value(nameColumn), columns: make(map[string]interface{})}
+		if folder := strings.Trim(value(pathColumn), "/"); folder != "" {
+			row.path = folder + "/" + row.path
+		}
+		for i, heading := range header {
+			if i == nameColumn || i == pathColumn || value(i) == "" {
+				continue
+			}
+			row.columns[strings.TrimSpace(heading)] = value(i)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// matchSharePointRow returns the listing row of the file at archivePath.
+// Listings give server-relative paths, such as sites/team/Shared
+// Documents/Plans, so a row matches when its path ends with the file's.
+func matchSharePointRow(rows []*sharePointRow, archivePath string) *sharePointRow {
+	archivePath = strings.ToLower(archivePath)
+	for _, row := range rows {
+		listed := strings.ToLower(row.path)
+		if listed == archivePath || strings.HasSuffix(listed, "/"+archivePath) || strings.HasSuffix(archivePath, "/"+listed) {
+			return row
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// newTestArchive builds a zip archive of files, keyed by path, in order
+func newTestArchive(t *testing.T, files ...[2]string) []*zip.File {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	writer := zip.NewWriter(buf)
+	for _, file := range files {
+		w, err := writer.Create(file[0])
+		require.NoError(t, err)
+		_, err = w.Write([]byte(file[1]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	return reader.File
+}
+
+// importPlan summarizes a plan as folders/name of imported items, and the
+// paths of skipped and failed ones
+func importPlan(items []*importItem) (imported, skipped, failed []string) {
+	for _, item := range items {
+		switch {
+		case item.skip != "":
+			skipped = append(skipped, item.path)
+		case item.err != nil:
+			failed = append(failed, item.path)
+		default:
+			imported = append(imported, joinImportPath(item.folders, item.name))
+		}
+	}
+	return imported, skipped, failed
+}
+
+func joinImportPath(folders []string, name string) string {
+	result := ""
+	for _, folder := range folders {
+		result += folder + "/"
+	}
+	return result + name
+}
+
+func TestNotionImportPlan(t *testing.T) {
+	items, err := notionImporter{}.plan(newTestArchive(t,
+		[2]string{"Wiki 0123456789abcdef0123456789abcdef.md", "# Wiki"},
+		[2]string{"Wiki 0123456789abcdef0123456789abcdef/Onboarding fedcba9876543210fedcba9876543210.md", "# Onboarding"},
+		[2]string{"Wiki 0123456789abcdef0123456789abcdef/diagram.png", "png"},
+		[2]string{"Tasks 00000000000000000000000000000001.csv", "Name"},
+		[2]string{"Tasks 00000000000000000000000000000001_all.csv", "Name"},
+		[2]string{"__MACOSX/._Wiki.md", ""},
+		[2]string{"../escape.md", ""},
+	))
+	require.NoError(t, err)
+
+	imported, skipped, failed := importPlan(items)
+	assert.Equal(t, []string{"Wiki.md", "Wiki/Onboarding.md", "Wiki/diagram.png", "Tasks.csv"}, imported)
+	assert.Equal(t, []string{"../escape.md", "Tasks 00000000000000000000000000000001.csv"}, skipped)
+	assert.Empty(t, failed)
+
+	assert.Equal(t, "fedcba9876543210fedcba9876543210", items[2].metadata["notion_id"])
+	assert.Equal(t, models.ImportFormatNotion, items[2].metadata["import_source"])
+	assert.Equal(t, "Wiki 0123456789abcdef0123456789abcdef/Onboarding fedcba9876543210fedcba9876543210.md", items[2].metadata["source_path"])
+}
+
+func TestNotionImportProperties(t *testing.T) {
+	item := &importItem{path: "Plan.md", metadata: map[string]interface{}{}}
+	notionImporter{}.annotate(item, []byte("# Plan\n\nStatus: In progress\nTags: Q3 Roadmap, urgent\n\nThe plan: ship it.\n"))
+
+	assert.Equal(t, map[string]interface{}{
+		"Status": "In progress",
+		"Tags":   "Q3 Roadmap, urgent",
+	}, item.metadata["notion_properties"])
+	assert.Equal(t, []string{"q3-roadmap", "urgent"}, item.tags)
+
+	page := &importItem{path: "Notes.md", metadata: map[string]interface{}{}}
+	notionImporter{}.annotate(page, []byte("No title: not a property\n"))
+	assert.NotContains(t, page.metadata, "notion_properties")
+}
+
+func TestGoogleDriveImportPlan(t *testing.T) {
+	items, err := googleDriveImporter{}.plan(newTestArchive(t,
+		[2]string{"Takeout/archive_browser.html", "<html>"},
+		[2]string{"Takeout/Drive/Reports/2024/summary.pdf", "%PDF"},
+		[2]string{"Takeout/Drive/notes.txt", "notes"},
+		[2]string{"Takeout/Drive/.DS_Store", ""},
+	))
+	require.NoError(t, err)
+
+	imported, skipped, _ := importPlan(items)
+	assert.Equal(t, []string{"Reports/2024/summary.pdf", "notes.txt"}, imported)
+	assert.Equal(t, []string{"Takeout/archive_browser.html"}, skipped)
+}
+
+func TestSharePointImportPlan(t *testing.T) {
+	listing := "\xef\xbb\xbfName,Path,Item Type,Modified By,Department\n" +
+		"Plans,sites/team/Shared Documents,Folder,Ada,\n" +
+		"budget.xlsx,sites/team/Shared Documents/Plans,Item,Ada,Finance\n" +
+		"missing.docx,sites/team/Shared Documents,Item,Ada,\n"
+	items, err := sharePointImporter{}.plan(newTestArchive(t,
+		[2]string{"listing.csv", listing},
+		[2]string{"Plans/Budget.xlsx", "xlsx"},
+		[2]string{"readme.txt", "unlisted"},
+	))
+	require.NoError(t, err)
+
+	imported, _, failed := importPlan(items)
+	assert.Equal(t, []string{"Plans/Budget.xlsx", "readme.txt"}, imported)
+	assert.Equal(t, []string{"sites/team/Shared Documents/missing.docx"}, failed)
+	assert.Equal(t, errors.CodeNotFound, errors.Normalize(items[2].err).ErrorCode)
+
+	assert.Equal(t, map[string]interface{}{
+		"Item Type":   "Item",
+		"Modified By": "Ada",
+		"Department":  "Finance",
+	}, items[0].metadata["sharepoint_columns"])
+	assert.NotContains(t, items[1].metadata, "sharepoint_columns")
+}
+
+func TestSharePointImportNeedsOneListing(t *testing.T) {
+	_, err := sharePointImporter{}.plan(newTestArchive(t, [2]string{"Plans/budget.xlsx", "xlsx"}))
+	assert.Equal(t, errors.CodeValidation, errors.Normalize(err).ErrorCode)
+
+	_, err = sharePointImporter{}.plan(newTestArchive(t,
+		[2]string{"a.csv", "Name\n"},
+		[2]string{"b.csv", "Name\n"},
+	))
+	assert.Equal(t, errors.CodeValidation, errors.Normalize(err).ErrorCode)
+
+	_, err = sharePointImporter{}.plan(newTestArchive(t, [2]string{"listing.csv", "Title,Path\n"}))
+	assert.Equal(t, errors.CodeValidation, errors.Normalize(err).ErrorCode)
+}
@@ -0,0 +1,643 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// maxMIMEDepth bounds the nesting of multipart bodies
+const maxMIMEDepth = 10
+
+// maxEmailTitleRunes bounds the subject in the names of email documents
+const maxEmailTitleRunes = 100
+
+// spaceContextResolver resolves the space a user acts in
+type spaceContextResolver interface {
+	ResolveSpaceContext(ctx context.Context, userID string, req models.SpaceContextRequest) (*models.SpaceContext, error)
+}
+
+// InboundEmailService gives notebooks inbound email addresses and adds the
+// mail they receive to the notebooks as documents: the message body and
+// each attachment become a document named after the subject, uploaded as
+// the notebook's owner
+type InboundEmailService struct {
+	neo4j     *database.Neo4jClient
+	notebooks *NotebookService
+	documents *DocumentService
+	spaces    spaceContextResolver
+	logger    *logger.Logger
+
+	domain             string
+	maxMessageBytes    int64
+	minAttachmentBytes int64
+	maxFileBytes       int64
+}
+
+// NewInboundEmailService creates a new inbound email service. Documents
+// over maxFileBytes are skipped like uploads over the limit.
+func NewInboundEmailService(neo4j *database.Neo4jClient, notebooks *NotebookService, documents *DocumentService, spaces spaceContextResolver, cfg config.InboundEmailConfig, maxFileBytes int64, log *logger.Logger) *InboundEmailService {
+	return &InboundEmailService{
+		neo4j:              neo4j,
+		notebooks:          notebooks,
+		documents:          documents,
+		spaces:             spaces,
+		logger:             log.WithService("inbound_email_service"),
+		domain:             strings.ToLower(cfg.Domain),
+		maxMessageBytes:    cfg.MaxMessageBytes,
+		minAttachmentBytes: cfg.MinAttachmentBytes,
+		maxFileBytes:       maxFileBytes,
+	}
+}
+
+// CreateAddress gives a notebook a new inbound email address, replacing
+// the one it had. Only the notebook's owner manages its address.
+func (s *InboundEmailService) CreateAddress(ctx context.Context, notebookID string, req models.InboundEmailRequest, userID string, spaceCtx *models.SpaceContext) (*models.InboundEmailAddress, error) {
+	if err := s.ownNotebook(ctx, notebookID, userID, spaceCtx); err != nil {
+		return nil, err
+	}
+	senders := make([]string, 0, len(req.AllowedSenders))
+	for _, sender := range req.AllowedSenders {
+		sender = strings.ToLower(strings.TrimSpace(sender))
+		if !strings.Contains(sender, "@") || strings.Count(sender, "@") > 1 || strings.HasSuffix(sender, "@") {
+			return nil, errors.ValidationWithDetails("Allowed senders are email addresses or @domain", map[string]interface{}{
+				"sender": sender,
+			})
+		}
+		senders = append(senders, sender)
+	}
+
+	random := make([]byte, 10)
+	if _, err := rand.Read(random); err != nil {
+		return nil, errors.Internal("Failed to generate inbound email address")
+	}
+	token := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(random))
+
+	now := time.Now().UTC()
+	_, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "inbound_email.create"), `
+		MATCH (n:Notebook {id: $notebook_id, tenant_id: $tenant_id})
+		SET n.inbound_email_token = $token,
+		    n.inbound_email_senders = $senders,
+		    n.inbound_email_created_at = $now
+	`, map[string]interface{}{
+		"notebook_id": notebookID,
+		"tenant_id":   spaceCtx.TenantID,
+		"token":       token,
+		"senders":     senders,
+		"now":         now,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to create inbound email address", err)
+	}
+
+	s.logger.Info("Inbound email address created", zap.String("notebook_id", notebookID), zap.String("user_id", userID))
+	return &models.InboundEmailAddress{
+		NotebookID:     notebookID,
+		Address:        token + "@" + s.domain,
+		AllowedSenders: senders,
+		CreatedAt:      now,
+	}, nil
+}
+
+// GetAddress returns the inbound email address of a notebook
+func (s *InboundEmailService) GetAddress(ctx context.Context, notebookID, userID string, spaceCtx *models.SpaceContext) (*models.InboundEmailAddress, error) {
+	if err := s.ownNotebook(ctx, notebookID, userID, spaceCtx); err != nil {
+		return nil, err
+	}
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "inbound_email.get"), `
+		MATCH (n:Notebook {id: $notebook_id, tenant_id: $tenant_id})
+		WHERE n.inbound_email_token IS NOT NULL
+		RETURN n.inbound_email_token as token, n.inbound_email_senders as senders,
+		       n.inbound_email_created_at as created_at
+	`, map[string]interface{}{
+		"notebook_id": notebookID,
+		"tenant_id":   spaceCtx.TenantID,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to get inbound email address", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, s.addressNotFound(notebookID)
+	}
+
+	record := result.Records[0]
+	return &models.InboundEmailAddress{
+		NotebookID:     notebookID,
+		Address:        recordString(record, "token") + "@" + s.domain,
+		AllowedSenders: recordStrings(record, "senders"),
+		CreatedAt:      recordTime(record, "created_at"),
+	}, nil
+}
+
+// DeleteAddress removes the inbound email address of a notebook; mail
+// sent to it is rejected from then on
+func (s *InboundEmailService) DeleteAddress(ctx context.Context, notebookID, userID string, spaceCtx *models.SpaceContext) error {
+	if err := s.ownNotebook(ctx, notebookID, userID, spaceCtx); err != nil {
+		return err
+	}
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "inbound_email.delete"), `
+		MATCH (n:Notebook {id: $notebook_id, tenant_id: $tenant_id})
+		WHERE n.inbound_email_token IS NOT NULL
+		REMOVE n.inbound_email_token, n.inbound_email_senders, n.inbound_email_created_at
+		RETURN n.id as id
+	`, map[string]interface{}{
+		"notebook_id": notebookID,
+		"tenant_id":   spaceCtx.TenantID,
+	})
+	if err != nil {
+		return errors.Database("Failed to delete inbound email address", err)
+	}
+	if len(result.Records) == 0 {
+		return s.addressNotFound(notebookID)
+	}
+	s.logger.Info("Inbound email address deleted", zap.String("notebook_id", notebookID), zap.String("user_id", userID))
+	return nil
+}
+
+// ownNotebook checks that ingestion is configured and that the user owns
+// the notebook
+func (s *InboundEmailService) ownNotebook(ctx context.Context, notebookID, userID string, spaceCtx *models.SpaceContext) error {
+	if s.domain == "" {
+		return errors.ServiceUnavailable("Inbound email is not configured").WithErrorCode(errors.CodeInboundEmailDisabled)
+	}
+	notebook, err := s.notebooks.GetNotebookByID(ctx, notebookID, userID, spaceCtx)
+	if err != nil {
+		return err
+	}
+	if notebook.OwnerID != userID {
+		return errors.Forbidden("Only the notebook owner can manage its inbound email address")
+	}
+	return nil
+}
+
+func (s *InboundEmailService) addressNotFound(notebookID string) error {
+	return errors.NotFoundWithDetails("Notebook has no inbound email address", map[string]interface{}{
+		"notebook_id": notebookID,
+	}).WithErrorCode(errors.CodeInboundEmailNotFound)
+}
+
+// sesNotification is the notification an SES receipt rule with an SNS
+// action publishes for a received email
+type sesNotification struct {
+	NotificationType string `json:"notificationType"`
+	Mail             struct {
+		MessageID string `json:"messageId"`
+		Source    string `json:"source"`
+	} `json:"mail"`
+	Receipt struct {
+		Recipients   []string   `json:"recipients"`
+		SpamVerdict  sesVerdict `json:"spamVerdict"`
+		VirusVerdict sesVerdict `json:"virusVerdict"`
+		Action       struct {
+			Encoding string `json:"encoding"`
+		} `json:"action"`
+	} `json:"receipt"`
+	Content string `json:"content"`
+}
+
+type sesVerdict struct {
+	Status string `json:"status"` // PASS, FAIL, GRAY or PROCESSING_FAILED
+}
+
+// ReceiveSESNotification adds the email of an SES receipt notification to
+// the notebooks it was addressed to. Mail that cannot be added is
+// rejected in the result rather than failing, since SES would only deliver
+// it again; an error is returned when nothing was added because uploads
+// failed, so the delivery is retried.
+func (s *InboundEmailService) ReceiveSESNotification(ctx context.Context, payload string) (*models.InboundEmailResult, error) {
+	var notification sesNotification
+	if err := json.Unmarshal([]byte(payload), &notification); err != nil {
+		return nil, errors.Validation("Invalid SES notification", err)
+	}
+	rejected := func(reason string) (*models.InboundEmailResult, error) {
+		s.logger.Info("Rejected inbound email",
+			zap.String("message_id", notification.Mail.MessageID),
+			zap.String("reason", reason),
+		)
+		return &models.InboundEmailResult{
+			Status:    models.InboundEmailRejected,
+			Reason:    reason,
+			MessageID: notification.Mail.MessageID,
+		}, nil
+	}
+
+	if notification.NotificationType != "Received" || notification.Content == "" {
+		return rejected(models.InboundEmailInvalid)
+	}
+	if notification.Receipt.VirusVerdict.Status == "FAIL" {
+		return rejected(models.InboundEmailVirus)
+	}
+	if notification.Receipt.SpamVerdict.Status == "FAIL" {
+		return rejected(models.InboundEmailSpam)
+	}
+
+	raw := []byte(notification.Content)
+	if strings.EqualFold(notification.Receipt.Action.Encoding, "BASE64") {
+		decoded, err := base64.StdEncoding.DecodeString(notification.Content)
+		if err != nil {
+			return rejected(models.InboundEmailInvalid)
+		}
+		raw = decoded
+	}
+	if int64(len(raw)) > s.maxMessageBytes {
+		return rejected(models.InboundEmailTooLarge)
+	}
+
+	email, err := parseInboundEmail(raw)
+	if err != nil {
+		return rejected(models.InboundEmailInvalid)
+	}
+	if email.spam {
+		return rejected(models.InboundEmailSpam)
+	}
+	if email.from == "" {
+		email.from = strings.ToLower(notification.Mail.Source)
+	}
+
+	var tokens []string
+	for _, recipient := range notification.Receipt.Recipients {
+		local, domain, ok := strings.Cut(strings.ToLower(recipient), "@")
+		if ok && domain == s.domain && local != "" {
+			tokens = append(tokens, local)
+		}
+	}
+	targets, err := s.findNotebooks(ctx, tokens)
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) == 0 {
+		return rejected(models.InboundEmailUnknownRecipient)
+	}
+
+	parts, skipped := planEmailDocuments(email, s.minAttachmentBytes, s.maxFileBytes)
+	if len(parts) == 0 {
+		return rejected(models.InboundEmailEmpty)
+	}
+
+	result := &models.InboundEmailResult{MessageID: notification.Mail.MessageID}
+	added, failed := 0, 0
+	for _, target := range targets {
+		delivery := &models.InboundEmailDelivery{
+			NotebookID:  target.notebookID,
+			DocumentIDs: []string{},
+			Skipped:     append([]*models.InboundEmailSkip(nil), skipped...),
+		}
+		result.Deliveries = append(result.Deliveries, delivery)
+		if !senderAllowed(email.from, target.senders) {
+			delivery.Reason = models.InboundEmailSenderNotAllowed
+			continue
+		}
+
+		for _, part := range parts {
+			document, err := s.upload(ctx, target, email, part)
+			if err != nil {
+				s.logger.Error("Failed to add inbound email document",
+					zap.String("message_id", notification.Mail.MessageID),
+					zap.String("notebook_id", target.notebookID),
+					zap.Error(err),
+				)
+				delivery.Skipped = append(delivery.Skipped, &models.InboundEmailSkip{Name: part.name, Reason: models.InboundEmailUploadFailed})
+				failed++
+				continue
+			}
+			delivery.DocumentIDs = append(delivery.DocumentIDs, document.ID)
+			added++
+		}
+	}
+
+	switch {
+	case added > 0:
+		result.Status = models.InboundEmailAccepted
+	case failed > 0:
+		return nil, errors.Internal("Failed to add the email to its notebooks")
+	default:
+		result.Status = models.InboundEmailRejected
+		result.Reason = models.InboundEmailSenderNotAllowed
+	}
+	s.logger.Info("Received inbound email",
+		zap.String("message_id", notification.Mail.MessageID),
+		zap.String("status", result.Status),
+		zap.Int("documents", added),
+	)
+	return result, nil
+}
+
+// emailTarget is a notebook an email was addressed to
+type emailTarget struct {
+	notebookID string
+	tenantID   string
+	spaceType  string
+	spaceID    string
+	ownerID    string // Keycloak ID of the notebook's owner
+	senders    []string
+}
+
+// findNotebooks returns the notebooks with the given inbound address tokens
+func (s *InboundEmailService) findNotebooks(ctx context.Context, tokens []string) ([]*emailTarget, error) {
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "inbound_email.find_notebooks"), `
+		MATCH (n:Notebook)
+		WHERE n.inbound_email_token IN $tokens AND `+database.NotDeleted("n")+`
+		MATCH (owner:User)
+		WHERE owner.id = n.owner_id OR owner.keycloak_id = n.owner_id
+		RETURN n.id as notebook_id, n.tenant_id as tenant_id, n.space_type as space_type,
+		       n.space_id as space_id, owner.keycloak_id as owner_id,
+		       n.inbound_email_senders as senders
+	`, map[string]interface{}{"tokens": tokens})
+	if err != nil {
+		return nil, errors.Database("Failed to find the notebooks of inbound email", err)
+	}
+
+	targets := make([]*emailTarget, 0, len(result.Records))
+	for _, record := range result.Records {
+		targets = append(targets, &emailTarget{
+			notebookID: recordString(record, "notebook_id"),
+			tenantID:   recordString(record, "tenant_id"),
+			spaceType:  recordString(record, "space_type"),
+			spaceID:    recordString(record, "space_id"),
+			ownerID:    recordString(record, "owner_id"),
+			senders:    recordStrings(record, "senders"),
+		})
+	}
+	return targets, nil
+}
+
+// upload adds a part of an email to a notebook as its owner
+func (s *InboundEmailService) upload(ctx context.Context, target *emailTarget, email *inboundEmail, part *emailPart) (*models.Document, error) {
+	spaceCtx, err := s.spaces.ResolveSpaceContext(ctx, target.ownerID, models.SpaceContextRequest{
+		SpaceType: models.SpaceType(target.spaceType),
+		SpaceID:   target.spaceID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := map[string]interface{}{
+		"source":           "email",
+		"email_from":       email.from,
+		"email_to":         strings.Join(email.to, ", "),
+		"email_subject":    email.subject,
+		"email_message_id": email.messageID,
+	}
+	if !email.date.IsZero() {
+		metadata["email_date"] = email.date.UTC().Format(time.RFC3339)
+	}
+	return s.documents.UploadDocument(ctx, models.DocumentUploadRequest{
+		DocumentCreateRequest: models.DocumentCreateRequest{
+			Name:       part.name,
+			NotebookID: target.notebookID,
+			Tags:       []string{"email"},
+			Metadata:   metadata,
+		},
+		FileData: part.data,
+	}, target.ownerID, spaceCtx, models.FileInfo{
+		OriginalName: part.name,
+		MimeType:     part.mimeType,
+		SizeBytes:    int64(len(part.data)),
+	})
+}
+
+// senderAllowed reports whether sender may mail a notebook allowing the
+// given senders, addresses or "@domain"; any sender may when none are
+func senderAllowed(sender string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	sender = strings.ToLower(sender)
+	for _, entry := range allowed {
+		if sender == entry || (strings.HasPrefix(entry, "@") && strings.HasSuffix(sender, entry)) {
+			return true
+		}
+	}
+	return false
+}
+
+// inboundEmail is a parsed email
+type inboundEmail struct {
+	from      string
+	to        []string
+	subject   string
+	messageID string
+	date      time.Time
+	spam      bool // Flagged by a spam filter before SES
+
+	text        []byte
+	html        []byte
+	attachments []*emailPart
+}
+
+// emailPart is a body or attachment of an email
+type emailPart struct {
+	name     string
+	mimeType string
+	data     []byte
+}
+
+// parseInboundEmail parses an RFC 5322 message into its headers, first
+// text and HTML bodies, and attachments
+func parseInboundEmail(raw []byte) (*inboundEmail, error) {
+	message, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := new(mime.WordDecoder)
+	email := &inboundEmail{
+		messageID: strings.Trim(message.Header.Get("Message-Id"), "<> "),
+		spam:      strings.EqualFold(strings.TrimSpace(message.Header.Get("X-Spam-Flag")), "yes"),
+	}
+	email.subject = message.Header.Get("Subject")
+	if subject, err := decoder.DecodeHeader(email.subject); err == nil {
+		email.subject = subject
+	}
+	email.subject = strings.TrimSpace(email.subject)
+	if from, err := message.Header.AddressList("From"); err == nil && len(from) > 0 {
+		email.from = strings.ToLower(from[0].Address)
+	}
+	if to, err := message.Header.AddressList("To"); err == nil {
+		for _, address := range to {
+			email.to = append(email.to, strings.ToLower(address.Address))
+		}
+	}
+	if date, err := message.Header.Date(); err == nil {
+		email.date = date
+	}
+
+	if err := email.walk(textproto.MIMEHeader(message.Header), message.Body, 0); err != nil {
+		return nil, err
+	}
+	return email, nil
+}
+
+// walk collects the bodies and attachments of a part and its subparts
+func (e *inboundEmail) walk(header textproto.MIMEHeader, body io.Reader, depth int) error {
+	if depth > maxMIMEDepth {
+		return fmt.Errorf("MIME parts are nested more than %d deep", maxMIMEDepth)
+	}
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", nil
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := e.walk(part.Header, part, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	data, err := io.ReadAll(transferDecoder(header.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		return err
+	}
+	name := partFilename(header, params)
+	disposition, _, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	attachment := name != "" || disposition == "attachment"
+
+	switch {
+	case !attachment && mediaType == "text/plain":
+		if e.text == nil {
+			e.text = data
+		}
+	case !attachment && mediaType == "text/html":
+		if e.html == nil {
+			e.html = data
+		}
+	default:
+		if name == "" {
+			name = "attachment"
+			if extensions, _ := mime.ExtensionsByType(mediaType); len(extensions) > 0 {
+				name += extensions[0]
+			}
+		}
+		e.attachments = append(e.attachments, &emailPart{name: name, mimeType: mediaType, data: data})
+	}
+	return nil
+}
+
+// transferDecoder decodes a part's Content-Transfer-Encoding
+func transferDecoder(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	default:
+		return body
+	}
+}
+
+// partFilename returns the file name of a part, without any directories
+func partFilename(header textproto.MIMEHeader, typeParams map[string]string) string {
+	name := typeParams["name"]
+	if _, params, err := mime.ParseMediaType(header.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+		name = params["filename"]
+	}
+	if decoded, err := new(mime.WordDecoder).DecodeHeader(name); err == nil {
+		name = decoded
+	}
+	name = path.Base(strings.ReplaceAll(strings.TrimSpace(name), "\\", "/"))
+	if name == "." || name == "/" {
+		return ""
+	}
+	return name
+}
+
+// planEmailDocuments returns the documents an email adds, all named after
+// its subject, and the parts skipped for their size. The text body, or the
+// HTML body of an email without one, is a document of its own.
+func planEmailDocuments(email *inboundEmail, minAttachmentBytes, maxFileBytes int64) ([]*emailPart, []*models.InboundEmailSkip) {
+	title := emailTitle(email)
+	var parts []*emailPart
+	var skipped []*models.InboundEmailSkip
+	add := func(part *emailPart, minBytes int64) {
+		switch size := int64(len(part.data)); {
+		case size > maxFileBytes:
+			skipped = append(skipped, &models.InboundEmailSkip{Name: part.name, Reason: models.InboundEmailTooLarge})
+		case size < minBytes:
+			skipped = append(skipped, &models.InboundEmailSkip{Name: part.name, Reason: models.InboundEmailTooSmall})
+		default:
+			parts = append(parts, part)
+		}
+	}
+
+	switch {
+	case len(bytes.TrimSpace(email.text)) > 0:
+		add(&emailPart{name: title + ".txt", mimeType: "text/plain", data: email.text}, 1)
+	case len(bytes.TrimSpace(email.html)) > 0:
+		add(&emailPart{name: title + ".html", mimeType: "text/html", data: email.html}, 1)
+	}
+	for _, attachment := range email.attachments {
+		add(&emailPart{
+			name:     title + " - " + attachment.name,
+			mimeType: attachment.mimeType,
+			data:     attachment.data,
+		}, minAttachmentBytes)
+	}
+	return parts, skipped
+}
+
+// emailTitle returns the subject of an email fit for document names, or
+// names its sender when it has none
+func emailTitle(email *inboundEmail) string {
+	title := strings.Join(strings.FieldsFunc(email.subject, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsControl(r) || r == '/' || r == '\\'
+	}), " ")
+	if runes := []rune(title); len(runes) > maxEmailTitleRunes {
+		title = strings.TrimSpace(string(runes[:maxEmailTitleRunes]))
+	}
+	if title != "" {
+		return title
+	}
+	if email.from != "" {
+		return "Email from " + email.from
+	}
+	return "Email"
+}
+
+// recordStrings returns a list of strings of a record
+func recordStrings(record *neo4j.Record, key string) []string {
+	value, _ := record.Get(key)
+	values, _ := value.([]interface{})
+	strs := make([]string, 0, len(values))
+	for _, v := range values {
+		if str, ok := v.(string); ok {
+			strs = append(strs, str)
+		}
+	}
+	return strs
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+const testEmail = "From: =?UTF-8?Q?Ana_Mar=C3=ADa?= <Ana@Example.com>\r\n" +
+	"To: x7k2m9q4r8t1v5w3@inbox.aether.test\r\n" +
+	"Subject: =?UTF-8?Q?Q3_audit/findings_=E2=9C=93?=\r\n" +
+	"Message-ID: <msg-1@example.com>\r\n" +
+	"Date: Fri, 16 Oct 2026 09:30:00 +0000\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=outer\r\n" +
+	"\r\n" +
+	"--outer\r\n" +
+	"Content-Type: multipart/alternative; boundary=inner\r\n" +
+	"\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"The findings are attached =E2=80=94 see the report.\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/html; charset=utf-8\r\n" +
+	"\r\n" +
+	"<p>The findings are attached</p>\r\n" +
+	"--inner--\r\n" +
+	"--outer\r\n" +
+	"Content-Type: application/pdf; name=\"report.pdf\"\r\n" +
+	"Content-Disposition: attachment; filename=\"..\\\\reports\\\\report.pdf\"\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"JVBERi0xLjQKJcOkw7zDtsOfCjIgMCBvYmoKPDwvTGVuZ3RoIDMgMCBSPj4Kc3RyZWFt\r\n" +
+	"--outer\r\n" +
+	"Content-Type: image/png\r\n" +
+	"Content-Disposition: inline; filename=\"logo.png\"\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"iVBORw0KGgo=\r\n" +
+	"--outer--\r\n"
+
+func TestParseInboundEmail(t *testing.T) {
+	email, err := parseInboundEmail([]byte(testEmail))
+	require.NoError(t, err)
+
+	assert.Equal(t, "ana@example.com", email.from)
+	assert.Equal(t, []string{"x7k2m9q4r8t1v5w3@inbox.aether.test"}, email.to)
+	assert.Equal(t, "Q3 audit/findings ✓", email.subject)
+	assert.Equal(t, "msg-1@example.com", email.messageID)
+	assert.Equal(t, 2026, email.date.Year())
+	assert.False(t, email.spam)
+
+	assert.Equal(t, "The findings are attached — see the report.", string(email.text))
+	assert.Equal(t, "<p>The findings are attached</p>", string(email.html))
+	require.Len(t, email.attachments, 2)
+	assert.Equal(t, "report.pdf", email.attachments[0].name)
+	assert.Equal(t, "application/pdf", email.attachments[0].mimeType)
+	assert.True(t, strings.HasPrefix(string(email.attachments[0].data), "%PDF-1.4"))
+	assert.Equal(t, "logo.png", email.attachments[1].name)
+}
+
+func TestParseInboundEmailFlaggedAsSpam(t *testing.T) {
+	email, err := parseInboundEmail([]byte("From: a@example.com\r\nX-Spam-Flag: YES\r\nSubject: Offer\r\n\r\nBuy now\r\n"))
+	require.NoError(t, err)
+	assert.True(t, email.spam)
+	assert.Equal(t, "Buy now\r\n", string(email.text))
+
+	_, err = parseInboundEmail([]byte("not an email"))
+	assert.Error(t, err)
+}
+
+func TestPlanEmailDocuments(t *testing.T) {
+	email, err := parseInboundEmail([]byte(testEmail))
+	require.NoError(t, err)
+
+	parts, skipped := planEmailDocuments(email, 16, 1<<20)
+	require.Len(t, parts, 2)
+	assert.Equal(t, "Q3 audit findings ✓.txt", parts[0].name)
+	assert.Equal(t, "text/plain", parts[0].mimeType)
+	assert.Equal(t, "Q3 audit findings ✓ - report.pdf", parts[1].name)
+	assert.Equal(t, []*models.InboundEmailSkip{
+		{Name: "Q3 audit findings ✓ - logo.png", Reason: models.InboundEmailTooSmall},
+	}, skipped)
+
+	// Without a text body the HTML body is added; parts over the upload
+	// limit are skipped
+	email.text = nil
+	parts, skipped = planEmailDocuments(email, 0, 40)
+	require.Len(t, parts, 2)
+	assert.Equal(t, "Q3 audit findings ✓.html", parts[0].name)
+	assert.Equal(t, "Q3 audit findings ✓ - logo.png", parts[1].name)
+	assert.Equal(t, models.InboundEmailTooLarge, skipped[0].Reason)
+}
+
+func TestEmailTitle(t *testing.T) {
+	assert.Equal(t, "Email from ana@example.com", emailTitle(&inboundEmail{from: "ana@example.com"}))
+	assert.Equal(t, "Email", emailTitle(&inboundEmail{subject: " \t"}))
+	assert.Len(t, []rune(emailTitle(&inboundEmail{subject: strings.Repeat("é", 300)})), maxEmailTitleRunes)
+}
+
+func TestSenderAllowed(t *testing.T) {
+	assert.True(t, senderAllowed("ana@example.com", nil))
+	assert.True(t, senderAllowed("Ana@Example.com", []string{"ana@example.com"}))
+	assert.True(t, senderAllowed("bo@example.com", []string{"@example.com"}))
+	assert.False(t, senderAllowed("bo@notexample.com", []string{"@example.com"}))
+	assert.False(t, senderAllowed("", []string{"ana@example.com"}))
+}
@@ -0,0 +1,422 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// JobRunFunc does the work of an async job and returns its result.
+// Progress reported through progress is visible to pollers while it runs.
+type JobRunFunc func(ctx context.Context, progress func(models.JobProgress)) (map[string]interface{}, error)
+
+// jobProgressInterval bounds how often reported progress is written
+const jobProgressInterval = time.Second
+
+// JobService runs long operations as async jobs. Jobs run on the replica
+// that started them and are tracked as Job nodes, so a poll answered by
+// any replica sees their progress.
+type JobService struct {
+	neo4j     *database.Neo4jClient
+	logger    *logger.Logger
+	retention time.Duration
+	slots     chan struct{}
+
+	// Jobs are tracked by the server's worker group and waiting jobs give
+	// up when the background context is cancelled on shutdown
+	backgroundCtx context.Context
+	timeout       time.Duration
+	workers       *WorkerGroup
+}
+
+// NewJobService creates a job service running at most cfg.MaxConcurrent
+// jobs at once
+func NewJobService(neo4j *database.Neo4jClient, cfg config.JobsConfig, log *logger.Logger) *JobService {
+	return &JobService{
+		neo4j:         neo4j,
+		logger:        log.WithService("job_service"),
+		retention:     time.Duration(cfg.RetentionHours) * time.Hour,
+		slots:         make(chan struct{}, cfg.MaxConcurrent),
+		backgroundCtx: context.Background(),
+		timeout:       defaultBackgroundJobTimeout,
+		workers:       NewWorkerGroup(),
+	}
+}
+
+// SetBackgroundContext sets the context whose cancellation fails jobs that
+// are still waiting to run
+func (s *JobService) SetBackgroundContext(ctx context.Context) {
+	s.backgroundCtx = ctx
+}
+
+// SetWorkerGroup sets the group that tracks running jobs so shutdown can
+// wait for them
+func (s *JobService) SetWorkerGroup(workers *WorkerGroup) {
+	if workers != nil {
+		s.workers = workers
+	}
+}
+
+// SetTimeout overrides the deadline of a single job
+func (s *JobService) SetTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		s.timeout = timeout
+	}
+}
+
+// Start records a queued job and runs it in the background. The job keeps
+// the values of ctx, such as the request ID, but not its cancellation.
+func (s *JobService) Start(ctx context.Context, jobType models.JobType, tenantID, userID string, run JobRunFunc) (*models.Job, error) {
+	job := &models.Job{
+		ID:        uuid.New().String(),
+		Type:      jobType,
+		Status:    models.JobStatusQueued,
+		TenantID:  tenantID,
+		CreatedBy: userID,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	query := `
+		CREATE (j:Job {
+			id: $id,
+			type: $type,
+			status: $status,
+			tenant_id: $tenant_id,
+			created_by: $created_by,
+			total: 0,
+			completed: 0,
+			failed: 0,
+			created_at: $now,
+			updated_at: $now
+		})
+	`
+	_, err := s.neo4j.ExecuteQuery(ctx, query, map[string]interface{}{
+		"id":         job.ID,
+		"type":       string(job.Type),
+		"status":     string(job.Status),
+		"tenant_id":  job.TenantID,
+		"created_by": job.CreatedBy,
+		"now":        job.CreatedAt,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to create job", err)
+	}
+
+	jobCtx := context.WithoutCancel(ctx)
+	if !s.workers.Go(func() { s.run(jobCtx, job.ID, run) }) {
+		apiErr := errors.ServiceUnavailable("Server is shutting down")
+		s.finish(jobCtx, job.ID, nil, nil, apiErr)
+		return nil, apiErr
+	}
+
+	s.logger.Info("Job started",
+		zap.String("job_id", job.ID),
+		zap.String("type", string(job.Type)),
+		zap.String("user_id", userID),
+	)
+	return job, nil
+}
+
+// run waits for a free slot, then runs a job and records its outcome
+func (s *JobService) run(ctx context.Context, jobID string, run JobRunFunc) {
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	case <-s.backgroundCtx.Done():
+		s.finish(ctx, jobID, nil, nil, errors.ServiceUnavailable("Server shut down before the job started"))
+		return
+	}
+
+	now := time.Now().UTC()
+	_, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (j:Job {id: $id})
+		SET j.status = $status, j.started_at = $now, j.updated_at = $now
+	`, map[string]interface{}{"id": jobID, "status": string(models.JobStatusRunning), "now": now})
+	if err != nil {
+		s.logger.Error("Failed to mark job running", zap.String("job_id", jobID), zap.Error(err))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	var (
+		mu        sync.Mutex
+		latest    *models.JobProgress
+		lastWrite time.Time
+	)
+	progress := func(p models.JobProgress) {
+		mu.Lock()
+		defer mu.Unlock()
+		latest = &p
+		if time.Since(lastWrite) < jobProgressInterval {
+			return
+		}
+		lastWrite = time.Now()
+		if err := s.writeProgress(ctx, jobID, p); err != nil {
+			s.logger.Warn("Failed to record job progress", zap.String("job_id", jobID), zap.Error(err))
+		}
+	}
+
+	result, err := s.safeRun(ctx, run, progress)
+	mu.Lock()
+	defer mu.Unlock()
+	s.finish(ctx, jobID, latest, result, err)
+}
+
+// safeRun runs a job, turning a panic into a failure so it cannot take
+// the server down
+func (s *JobService) safeRun(ctx context.Context, run JobRunFunc, progress func(models.JobProgress)) (result map[string]interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("Job panicked", zap.Any("panic", r), zap.Stack("stack"))
+			err = errors.Internal("Job failed unexpectedly")
+		}
+	}()
+	return run(ctx, progress)
+}
+
+// writeProgress records the progress of a running job
+func (s *JobService) writeProgress(ctx context.Context, jobID string, p models.JobProgress) error {
+	_, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (j:Job {id: $id})
+		SET j.total = $total, j.completed = $completed, j.failed = $failed, j.updated_at = $now
+	`, map[string]interface{}{
+		"id":        jobID,
+		"total":     p.Total,
+		"completed": p.Completed,
+		"failed":    p.Failed,
+		"now":       time.Now().UTC(),
+	})
+	return err
+}
+
+// finish records the final progress and result of a job, or why it
+// failed. It writes with a fresh deadline so a job that timed out is
+// still recorded as failed.
+func (s *JobService) finish(ctx context.Context, jobID string, progress *models.JobProgress, result map[string]interface{}, runErr error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+	defer cancel()
+
+	params := map[string]interface{}{
+		"id":            jobID,
+		"status":        string(models.JobStatusSucceeded),
+		"result":        nil,
+		"error_code":    nil,
+		"error_catalog": nil,
+		"error_message": nil,
+		"now":           time.Now().UTC(),
+	}
+	if progress == nil {
+		progress = &models.JobProgress{}
+	}
+	params["total"] = progress.Total
+	params["completed"] = progress.Completed
+	params["failed"] = progress.Failed
+	if runErr != nil {
+		apiErr := errors.Normalize(runErr)
+		params["status"] = string(models.JobStatusFailed)
+		params["error_code"] = apiErr.Code
+		params["error_catalog"] = apiErr.ErrorCode
+		params["error_message"] = apiErr.Message
+	} else if result != nil {
+		encoded, err := json.Marshal(result)
+		if err != nil {
+			s.finish(ctx, jobID, progress, nil, errors.Internal("Failed to encode job result"))
+			return
+		}
+		params["result"] = string(encoded)
+	}
+
+	_, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (j:Job {id: $id})
+		SET j.status = $status,
+		    j.total = $total,
+		    j.completed = $completed,
+		    j.failed = $failed,
+		    j.result = $result,
+		    j.error_code = $error_code,
+		    j.error_catalog_code = $error_catalog,
+		    j.error_message = $error_message,
+		    j.completed_at = $now,
+		    j.updated_at = $now
+	`, params)
+	if err != nil {
+		s.logger.Error("Failed to record job outcome", zap.String("job_id", jobID), zap.Error(err))
+		return
+	}
+
+	if runErr != nil {
+		s.logger.Warn("Job failed", zap.String("job_id", jobID), zap.Error(runErr))
+		return
+	}
+	s.logger.Info("Job succeeded", zap.String("job_id", jobID))
+}
+
+// jobFields are the properties returned for a Job j
+const jobFields = `
+	j.id as id, j.type as type, j.status as status, j.tenant_id as tenant_id,
+	j.created_by as created_by, j.total as total, j.completed as completed,
+	j.failed as failed, j.result as result, j.error_code as error_code,
+	j.error_catalog_code as error_catalog_code, j.error_message as error_message,
+	j.created_at as created_at, j.started_at as started_at, j.completed_at as completed_at
+`
+
+// GetJob gets a job started by the user
+func (s *JobService) GetJob(ctx context.Context, jobID, userID string) (*models.Job, error) {
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (j:Job {id: $id, created_by: $user_id})
+		RETURN `+jobFields, map[string]interface{}{"id": jobID, "user_id": userID})
+	if err != nil {
+		return nil, errors.Database("Failed to get job", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, errors.NotFoundWithDetails("Job not found", map[string]interface{}{
+			"job_id": jobID,
+		}).WithErrorCode(errors.CodeJobNotFound)
+	}
+	return recordToJob(result.Records[0]), nil
+}
+
+// ListJobs lists the jobs the user started, newest first, optionally of
+// one type or status
+func (s *JobService) ListJobs(ctx context.Context, userID string, jobType models.JobType, status models.JobStatus, limit, offset int) ([]*models.Job, bool, error) {
+	limit, offset = pagination.Clamp(limit, offset)
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (j:Job {created_by: $user_id})
+		WHERE ($type = '' OR j.type = $type) AND ($status = '' OR j.status = $status)
+		RETURN `+jobFields+`
+		ORDER BY j.created_at DESC
+		SKIP $offset
+		LIMIT $limit
+	`, map[string]interface{}{
+		"user_id": userID,
+		"type":    string(jobType),
+		"status":  string(status),
+		"offset":  offset,
+		"limit":   limit + 1,
+	})
+	if err != nil {
+		return nil, false, errors.Database("Failed to list jobs", err)
+	}
+
+	jobs := make([]*models.Job, 0, len(result.Records))
+	for _, record := range result.Records {
+		jobs = append(jobs, recordToJob(record))
+	}
+	jobs, hasMore := pagination.Trim(jobs, limit)
+	return jobs, hasMore, nil
+}
+
+// CleanupJobs fails jobs whose replica stopped reporting on them and
+// deletes finished jobs past their retention. It is a singleton job run by
+// the leader replica.
+func (s *JobService) CleanupJobs(ctx context.Context) error {
+	now := time.Now().UTC()
+
+	// A running job writes at least when it finishes, and a job may not
+	// run for longer than the timeout; queued jobs wait for at most as long
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (j:Job)
+		WHERE j.status IN $unfinished AND j.updated_at < $stale_before
+		SET j.status = $failed,
+		    j.error_code = 'SERVICE_UNAVAILABLE',
+		    j.error_message = 'The job was interrupted',
+		    j.completed_at = $now,
+		    j.updated_at = $now
+		RETURN count(j) as interrupted
+	`, map[string]interface{}{
+		"unfinished":   []string{string(models.JobStatusQueued), string(models.JobStatusRunning)},
+		"failed":       string(models.JobStatusFailed),
+		"stale_before": now.Add(-2 * s.timeout),
+		"now":          now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fail interrupted jobs: %w", err)
+	}
+	if interrupted := recordInt(result.Records, "interrupted"); interrupted > 0 {
+		s.logger.Warn("Failed interrupted jobs", zap.Int64("count", interrupted))
+	}
+
+	result, err = s.neo4j.ExecuteQuery(ctx, `
+		MATCH (j:Job)
+		WHERE j.completed_at < $expired_before
+		WITH j LIMIT 10000
+		DETACH DELETE j
+		RETURN count(*) as deleted
+	`, map[string]interface{}{
+		"expired_before": now.Add(-s.retention),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete expired jobs: %w", err)
+	}
+	if deleted := recordInt(result.Records, "deleted"); deleted > 0 {
+		s.logger.Info("Deleted expired jobs", zap.Int64("count", deleted))
+	}
+	return nil
+}
+
+// recordInt reads an integer of the first record, zero when there is none
+func recordInt(records []*neo4j.Record, key string) int64 {
+	if len(records) == 0 {
+		return 0
+	}
+	value, _ := records[0].Get(key)
+	n, _ := value.(int64)
+	return n
+}
+
+// recordToJob reads the jobFields of a record
+func recordToJob(record *neo4j.Record) *models.Job {
+	job := &models.Job{
+		ID:        recordString(record, "id"),
+		Type:      models.JobType(recordString(record, "type")),
+		Status:    models.JobStatus(recordString(record, "status")),
+		TenantID:  recordString(record, "tenant_id"),
+		CreatedBy: recordString(record, "created_by"),
+	}
+	for key, target := range map[string]*int{
+		"total":     &job.Progress.Total,
+		"completed": &job.Progress.Completed,
+		"failed":    &job.Progress.Failed,
+	} {
+		value, _ := record.Get(key)
+		n, _ := value.(int64)
+		*target = int(n)
+	}
+	if encoded := recordString(record, "result"); encoded != "" {
+		_ = json.Unmarshal([]byte(encoded), &job.Result)
+	}
+	if code := recordString(record, "error_code"); code != "" {
+		job.Error = &models.JobError{
+			Code:      code,
+			ErrorCode: recordString(record, "error_catalog_code"),
+			Message:   recordString(record, "error_message"),
+		}
+	}
+	if value, _ := record.Get("created_at"); value != nil {
+		job.CreatedAt, _ = value.(time.Time)
+	}
+	for key, target := range map[string]**time.Time{
+		"started_at":   &job.StartedAt,
+		"completed_at": &job.CompletedAt,
+	} {
+		value, _ := record.Get(key)
+		if t, ok := value.(time.Time); ok {
+			*target = &t
+		}
+	}
+	return job
+}
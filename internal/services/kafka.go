@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/segmentio/kafka-go"
@@ -20,6 +21,12 @@ type KafkaService struct {
 	logger  *logger.Logger
 	config  config.KafkaConfig
 	brokers []string
+
+	// ctx is cancelled on Close so consumer loops exit; consumers tracks
+	// the loops so shutdown can wait for in-flight messages
+	ctx       context.Context
+	cancel    context.CancelFunc
+	consumers sync.WaitGroup
 }
 
 // Message represents a Kafka message
@@ -43,23 +50,105 @@ const (
 	EventUserLoggedIn EventType = "user.logged_in"
 
 	// Notebook events
-	EventNotebookCreated EventType = "notebook.created"
-	EventNotebookUpdated EventType = "notebook.updated"
-	EventNotebookDeleted EventType = "notebook.deleted"
-	EventNotebookShared  EventType = "notebook.shared"
+	EventNotebookCreated  EventType = "notebook.created"
+	EventNotebookUpdated  EventType = "notebook.updated"
+	EventNotebookDeleted  EventType = "notebook.deleted"
+	EventNotebookShared   EventType = "notebook.shared"
+	EventNotebookUnshared EventType = "notebook.unshared"
+	EventNotebookRestored EventType = "notebook.restored" // Restored from the trash
 
 	// Document events
-	EventDocumentUploaded  EventType = "document.uploaded"
-	EventDocumentProcessed EventType = "document.processed"
-	EventDocumentFailed    EventType = "document.failed"
-	EventDocumentDeleted   EventType = "document.deleted"
+	EventDocumentUploaded      EventType = "document.uploaded"
+	EventDocumentUpdated       EventType = "document.updated"
+	EventDocumentStatusChanged EventType = "document.status_changed"
+	EventDocumentProcessed     EventType = "document.processed"
+	EventDocumentFailed        EventType = "document.failed"
+	EventDocumentDeleted       EventType = "document.deleted"
+	EventDocumentRestored      EventType = "document.restored" // Restored from the trash
 
 	// Processing events
 	EventProcessingStarted   EventType = "processing.started"
 	EventProcessingCompleted EventType = "processing.completed"
 	EventProcessingFailed    EventType = "processing.failed"
+
+	// EventProcessingRetriesExhausted reports a document whose processing
+	// still fails after its retries
+	EventProcessingRetriesExhausted EventType = "processing.retries_exhausted"
+
+	// SLO events
+	EventSLOBurnRateAlert    EventType = "slo.burn_rate_alert"
+	EventSLOBurnRateResolved EventType = "slo.burn_rate_resolved"
+
+	// Usage anomaly events
+	EventUsageAnomalyDetected EventType = "usage.anomaly_detected"
+	EventUsageAnomalyResolved EventType = "usage.anomaly_resolved"
+
+	// Synthetic monitoring events
+	EventSyntheticProbeFailed    EventType = "synthetic.probe_failed"
+	EventSyntheticProbeRecovered EventType = "synthetic.probe_recovered"
+
+	// Report events
+	EventReportGenerated EventType = "report.generated"
+
+	// EventNotebookDigestSent reports a notebook digest sent to a user
+	EventNotebookDigestSent EventType = "notebook.digest_sent"
+
+	// Space membership events
+	EventMemberAdded EventType = "member.added"
+
+	// Agent events
+	EventAgentExecuted EventType = "agent.executed"
+
+	// EventQuotaThresholdReached reports a space whose use of a quota
+	// reached a warning threshold
+	EventQuotaThresholdReached EventType = "quota.threshold_reached"
 )
 
+// eventTopics maps the event types aether publishes to their topics;
+// other types go to the "events" topic
+var eventTopics = map[EventType]string{
+	EventUserCreated:                "users",
+	EventUserUpdated:                "users",
+	EventUserDeleted:                "users",
+	EventUserLoggedIn:               "users",
+	EventNotebookCreated:            "notebooks",
+	EventNotebookUpdated:            "notebooks",
+	EventNotebookDeleted:            "notebooks",
+	EventNotebookShared:             "notebooks",
+	EventNotebookUnshared:           "notebooks",
+	EventNotebookRestored:           "notebooks",
+	EventDocumentUploaded:           "documents",
+	EventDocumentUpdated:            "documents",
+	EventDocumentStatusChanged:      "documents",
+	EventDocumentProcessed:          "documents",
+	EventDocumentFailed:             "documents",
+	EventDocumentDeleted:            "documents",
+	EventDocumentRestored:           "documents",
+	EventProcessingStarted:          "processing",
+	EventProcessingCompleted:        "processing",
+	EventProcessingFailed:           "processing",
+	EventProcessingRetriesExhausted: "alerts",
+	EventSLOBurnRateAlert:           "alerts",
+	EventSLOBurnRateResolved:        "alerts",
+	EventUsageAnomalyDetected:       "alerts",
+	EventUsageAnomalyResolved:       "alerts",
+	EventSyntheticProbeFailed:       "alerts",
+	EventSyntheticProbeRecovered:    "alerts",
+	EventReportGenerated:            "reports",
+	EventNotebookDigestSent:         "notebooks",
+	EventMemberAdded:                "spaces",
+	EventAgentExecuted:              "agents",
+	EventQuotaThresholdReached:      "spaces",
+}
+
+// IsAetherEventType reports whether aether publishes events of the type.
+// Projections and the audit log trust these events, so other services
+// must not publish them.
+func IsAetherEventType(eventType EventType) bool {
+	_, ok := eventTopics[eventType]
+	return ok
+}
+
 // Event represents a domain event
 type Event struct {
 	ID        string                 `json:"id"`
@@ -74,7 +163,11 @@ type Event struct {
 
 // NewKafkaService creates a new Kafka service
 func NewKafkaService(cfg config.KafkaConfig, log *logger.Logger) (*KafkaService, error) {
+	serviceCtx, serviceCancel := context.WithCancel(context.Background())
+
 	service := &KafkaService{
+		ctx:     serviceCtx,
+		cancel:  serviceCancel,
 		readers: make(map[string]*kafka.Reader),
 		logger:  log.WithService("kafka"),
 		config:  cfg,
@@ -101,6 +194,7 @@ func NewKafkaService(cfg config.KafkaConfig, log *logger.Logger) (*KafkaService,
 	defer cancel()
 
 	if err := service.testConnection(ctx); err != nil {
+		serviceCancel()
 		return nil, fmt.Errorf("failed to connect to Kafka: %w", err)
 	}
 
@@ -163,6 +257,13 @@ func (k *KafkaService) PublishEvent(ctx context.Context, event Event) error {
 		})
 	}
 
+	// Propagate the correlation ID so consumers can continue the trace
+	if requestID := logger.RequestIDFromContext(ctx); requestID != "" {
+		message.Headers = append(message.Headers, kafka.Header{
+			Key: logger.KafkaRequestIDHeader, Value: []byte(requestID),
+		})
+	}
+
 	// Publish message
 	start := time.Now()
 	err = k.writer.WriteMessages(ctx, message)
@@ -179,7 +280,7 @@ func (k *KafkaService) PublishEvent(ctx context.Context, event Event) error {
 		return fmt.Errorf("failed to publish event: %w", err)
 	}
 
-	k.logger.Info("Event published successfully",
+	k.logger.FromContext(ctx).Info("Event published successfully",
 		zap.String("event_id", event.ID),
 		zap.String("event_type", string(event.Type)),
 		zap.String("topic", topic),
@@ -192,6 +293,74 @@ func (k *KafkaService) PublishEvent(ctx context.Context, event Event) error {
 
 // PublishMessage publishes a generic message to Kafka
 func (k *KafkaService) PublishMessage(ctx context.Context, msg Message) error {
+	kafkaMsg, err := k.toKafkaMessage(ctx, msg)
+	if err != nil {
+		return err
+	}
+
+	// Publish message
+	start := time.Now()
+	err = k.writer.WriteMessages(ctx, kafkaMsg)
+	duration := time.Since(start).Seconds() * 1000
+
+	if err != nil {
+		k.logger.Error("Failed to publish message",
+			zap.String("topic", msg.Topic),
+			zap.String("key", msg.Key),
+			zap.Float64("duration_ms", duration),
+			zap.Error(err),
+		)
+		return fmt.Errorf("failed to publish message: %w", err)
+	}
+
+	k.logger.Debug("Message published successfully",
+		zap.String("topic", msg.Topic),
+		zap.String("key", msg.Key),
+		zap.Float64("duration_ms", duration),
+	)
+
+	return nil
+}
+
+// PublishMessages publishes several messages in a single write
+func (k *KafkaService) PublishMessages(ctx context.Context, msgs []Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	kafkaMsgs := make([]kafka.Message, 0, len(msgs))
+	for _, msg := range msgs {
+		kafkaMsg, err := k.toKafkaMessage(ctx, msg)
+		if err != nil {
+			return err
+		}
+		kafkaMsgs = append(kafkaMsgs, kafkaMsg)
+	}
+
+	start := time.Now()
+	err := k.writer.WriteMessages(ctx, kafkaMsgs...)
+	duration := time.Since(start).Seconds() * 1000
+
+	if err != nil {
+		k.logger.Error("Failed to publish messages",
+			zap.Int("count", len(msgs)),
+			zap.Float64("duration_ms", duration),
+			zap.Error(err),
+		)
+		return fmt.Errorf("failed to publish messages: %w", err)
+	}
+
+	k.logger.Debug("Messages published successfully",
+		zap.Int("count", len(msgs)),
+		zap.Float64("duration_ms", duration),
+	)
+
+	return nil
+}
+
+// toKafkaMessage serializes msg and attaches its headers, including the
+// request ID from ctx
+func (k *KafkaService) toKafkaMessage(ctx context.Context, msg Message) (kafka.Message, error) {
 	// Set timestamp if not provided
 	if msg.Timestamp.IsZero() {
 		msg.Timestamp = time.Now()
@@ -209,7 +378,7 @@ func (k *KafkaService) PublishMessage(ctx context.Context, msg Message) error {
 				zap.String("key", msg.Key),
 				zap.Error(err),
 			)
-			return fmt.Errorf("failed to serialize message value: %w", err)
+			return kafka.Message{}, fmt.Errorf("failed to serialize message value: %w", err)
 		}
 	}
 
@@ -227,29 +396,15 @@ func (k *KafkaService) PublishMessage(ctx context.Context, msg Message) error {
 			Key: key, Value: []byte(value),
 		})
 	}
-
-	// Publish message
-	start := time.Now()
-	err = k.writer.WriteMessages(ctx, kafkaMsg)
-	duration := time.Since(start).Seconds() * 1000
-
-	if err != nil {
-		k.logger.Error("Failed to publish message",
-			zap.String("topic", msg.Topic),
-			zap.String("key", msg.Key),
-			zap.Float64("duration_ms", duration),
-			zap.Error(err),
-		)
-		return fmt.Errorf("failed to publish message: %w", err)
+	if _, exists := msg.Headers[logger.KafkaRequestIDHeader]; !exists {
+		if requestID := logger.RequestIDFromContext(ctx); requestID != "" {
+			kafkaMsg.Headers = append(kafkaMsg.Headers, kafka.Header{
+				Key: logger.KafkaRequestIDHeader, Value: []byte(requestID),
+			})
+		}
 	}
 
-	k.logger.Debug("Message published successfully",
-		zap.String("topic", msg.Topic),
-		zap.String("key", msg.Key),
-		zap.Float64("duration_ms", duration),
-	)
-
-	return nil
+	return kafkaMsg, nil
 }
 
 // Subscribe creates a consumer for a topic
@@ -277,6 +432,7 @@ func (k *KafkaService) Subscribe(topic string, groupID string, handler MessageHa
 	k.readers[readerKey] = reader
 
 	// Start consuming in a goroutine
+	k.consumers.Add(1)
 	go k.consume(reader, handler, topic, groupID)
 
 	k.logger.Info("Subscribed to topic",
@@ -287,15 +443,75 @@ func (k *KafkaService) Subscribe(topic string, groupID string, handler MessageHa
 	return nil
 }
 
+// SubscribeAllPartitions consumes every partition of topic without a
+// consumer group, starting at the latest offset, so every caller receives
+// every message. No offsets are committed and no group is left behind on
+// the broker when the instance goes away. Partitions added to the topic
+// later are not consumed until the next start.
+func (k *KafkaService) SubscribeAllPartitions(ctx context.Context, topic string, handler MessageHandler) error {
+	conn, err := kafka.DialContext(ctx, "tcp", k.brokers[0])
+	if err != nil {
+		return fmt.Errorf("failed to connect to Kafka broker: %w", err)
+	}
+	defer conn.Close()
+
+	partitions, err := conn.ReadPartitions(topic)
+	if err != nil {
+		return fmt.Errorf("failed to read partitions of topic %s: %w", topic, err)
+	}
+
+	for _, partition := range partitions {
+		readerKey := fmt.Sprintf("%s-partition-%d", topic, partition.ID)
+		if _, exists := k.readers[readerKey]; exists {
+			return fmt.Errorf("reader for topic %s partition %d already exists", topic, partition.ID)
+		}
+
+		reader := kafka.NewReader(kafka.ReaderConfig{
+			Brokers:     k.brokers,
+			Topic:       topic,
+			Partition:   partition.ID,
+			MinBytes:    1,
+			MaxBytes:    10e6, // 10MB
+			MaxWait:     500 * time.Millisecond,
+			ErrorLogger: kafka.LoggerFunc(k.logError),
+			Logger:      kafka.LoggerFunc(k.logInfo),
+		})
+		if err := reader.SetOffset(kafka.LastOffset); err != nil {
+			reader.Close()
+			return fmt.Errorf("failed to seek topic %s partition %d: %w", topic, partition.ID, err)
+		}
+
+		k.readers[readerKey] = reader
+		k.consumers.Add(1)
+		go k.consume(reader, handler, topic, "")
+	}
+
+	k.logger.Info("Subscribed to all partitions of topic",
+		zap.String("topic", topic),
+		zap.Int("partitions", len(partitions)),
+	)
+
+	return nil
+}
+
 // MessageHandler is a function type for handling messages
 type MessageHandler func(ctx context.Context, message kafka.Message) error
 
 // consume consumes messages from a Kafka topic
 func (k *KafkaService) consume(reader *kafka.Reader, handler MessageHandler, topic, groupID string) {
+	defer k.consumers.Done()
+
 	for {
-		ctx := context.Background()
+		ctx := k.ctx
 		message, err := reader.ReadMessage(ctx)
 		if err != nil {
+			if ctx.Err() != nil {
+				k.logger.Info("Kafka consumer stopped",
+					zap.String("topic", topic),
+					zap.String("group_id", groupID),
+				)
+				return
+			}
 			k.logger.Error("Failed to read message",
 				zap.String("topic", topic),
 				zap.String("group_id", groupID),
@@ -304,12 +520,18 @@ func (k *KafkaService) consume(reader *kafka.Reader, handler MessageHandler, top
 			continue
 		}
 
+		// Continue the producer's trace, or start a new one. A message that
+		// was read is processed to completion even if shutdown begins.
+		ctx = logger.ContextWithRequestID(context.WithoutCancel(ctx), requestIDFromHeaders(message.Headers))
+		ctx, _ = logger.EnsureRequestID(ctx)
+		log := k.logger.FromContext(ctx)
+
 		start := time.Now()
 		err = handler(ctx, message)
 		duration := time.Since(start).Seconds() * 1000
 
 		if err != nil {
-			k.logger.Error("Message handler failed",
+			log.Error("Message handler failed",
 				zap.String("topic", topic),
 				zap.String("group_id", groupID),
 				zap.String("key", string(message.Key)),
@@ -318,7 +540,7 @@ func (k *KafkaService) consume(reader *kafka.Reader, handler MessageHandler, top
 			)
 			// TODO: Implement dead letter queue or retry mechanism
 		} else {
-			k.logger.Debug("Message processed successfully",
+			log.Debug("Message processed successfully",
 				zap.String("topic", topic),
 				zap.String("group_id", groupID),
 				zap.String("key", string(message.Key)),
@@ -357,8 +579,23 @@ func (k *KafkaService) Unsubscribe(topic string, groupID string) error {
 	return nil
 }
 
-// Close closes the Kafka service
+// Shutdown stops consuming, waits for in-flight messages until ctx is done,
+// then flushes the producer and closes all connections
+func (k *KafkaService) Shutdown(ctx context.Context) error {
+	k.cancel()
+
+	if err := waitGroupContext(ctx, &k.consumers); err != nil {
+		k.logger.Warn("Timed out waiting for Kafka consumers to finish", zap.Error(err))
+	}
+
+	return k.Close()
+}
+
+// Close closes the Kafka service. The writer flushes pending messages before closing.
 func (k *KafkaService) Close() error {
+	// Stop consumer loops
+	k.cancel()
+
 	// Close writer
 	if err := k.writer.Close(); err != nil {
 		k.logger.Error("Failed to close Kafka writer", zap.Error(err))
@@ -386,26 +623,7 @@ func (k *KafkaService) HealthCheck(ctx context.Context) error {
 // Helper methods
 
 func (k *KafkaService) getTopicForEvent(eventType EventType) string {
-	// Map event types to topics
-	topicMap := map[EventType]string{
-		EventUserCreated:         "users",
-		EventUserUpdated:         "users",
-		EventUserDeleted:         "users",
-		EventUserLoggedIn:        "users",
-		EventNotebookCreated:     "notebooks",
-		EventNotebookUpdated:     "notebooks",
-		EventNotebookDeleted:     "notebooks",
-		EventNotebookShared:      "notebooks",
-		EventDocumentUploaded:    "documents",
-		EventDocumentProcessed:   "documents",
-		EventDocumentFailed:      "documents",
-		EventDocumentDeleted:     "documents",
-		EventProcessingStarted:   "processing",
-		EventProcessingCompleted: "processing",
-		EventProcessingFailed:    "processing",
-	}
-
-	baseTopic, exists := topicMap[eventType]
+	baseTopic, exists := eventTopics[eventType]
 	if !exists {
 		baseTopic = "events"
 	}
@@ -450,6 +668,17 @@ func (k *KafkaService) logInfo(msg string, args ...interface{}) {
 	k.logger.Debug("Kafka info", zap.String("message", fmt.Sprintf(msg, args...)))
 }
 
+// requestIDFromHeaders returns the correlation ID carried in Kafka headers,
+// or "" when it is missing or not a valid request ID
+func requestIDFromHeaders(headers []kafka.Header) string {
+	for _, h := range headers {
+		if h.Key == logger.KafkaRequestIDHeader && logger.ValidRequestID(string(h.Value)) {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
 func generateEventID() string {
 	// Simple event ID generation - in production, use UUID or similar
 	return fmt.Sprintf("evt_%d", time.Now().UnixNano())
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+)
+
+func TestKafkaMessageCarriesRequestID(t *testing.T) {
+	k := &KafkaService{logger: setupTestLogger(t)}
+	ctx := logger.ContextWithRequestID(context.Background(), "req-1")
+
+	headerValue := func(msg kafka.Message) string {
+		for _, h := range msg.Headers {
+			if h.Key == logger.KafkaRequestIDHeader {
+				return string(h.Value)
+			}
+		}
+		return ""
+	}
+
+	msg, err := k.toKafkaMessage(ctx, Message{Topic: "documents", Value: map[string]string{"id": "d1"}})
+	require.NoError(t, err)
+	assert.Equal(t, "req-1", headerValue(msg))
+	assert.Equal(t, "req-1", requestIDFromHeaders(msg.Headers), "consumers continue the producer's trace")
+
+	msg, err = k.toKafkaMessage(ctx, Message{Topic: "documents", Headers: map[string]string{logger.KafkaRequestIDHeader: "req-explicit"}})
+	require.NoError(t, err)
+	assert.Equal(t, "req-explicit", headerValue(msg), "an explicit header wins")
+
+	msg, err = k.toKafkaMessage(context.Background(), Message{Topic: "documents"})
+	require.NoError(t, err)
+	assert.Empty(t, headerValue(msg))
+
+	invalid := []kafka.Header{{Key: logger.KafkaRequestIDHeader, Value: []byte("bad id\n")}}
+	assert.Empty(t, requestIDFromHeaders(invalid), "an invalid ID is not continued")
+}
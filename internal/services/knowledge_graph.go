@@ -0,0 +1,411 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// graphRootLabels are the Neo4j labels of the nodes a graph view can be
+// centred on
+var graphRootLabels = map[models.GraphRootType]string{
+	models.GraphRootDocument: "Document",
+	models.GraphRootNotebook: "Notebook",
+	models.GraphRootEntity:   "Entity",
+}
+
+// graphRelationships are the relationships a graph view follows: documents
+// belong to notebooks, notebooks contain notebooks, documents mention
+// entities and are similar to other documents
+const graphRelationships = "BELONGS_TO|CONTAINS|MENTIONS|SIMILAR_TO"
+
+// graphNodeAccess is the condition under which the user may see node %[1]s.
+// Documents and notebooks must be in the space; private notebooks and
+// their documents are only visible to their owner and the users and teams
+// they are shared with. Entities are shared by the tenant. Other nodes,
+// such as spaces and users, are never part of a view.
+var graphNodeAccess = `(%[2]s AND CASE
+	  WHEN %[1]s:Notebook THEN %[1]s.space_id = $space_id
+	       AND (%[1]s.visibility <> 'private' OR %[1]s.owner_id = $user_id
+	            OR ` + notebookSharedWith("%[1]s") + `)
+	  WHEN %[1]s:Document THEN %[1]s.space_id = $space_id
+	       AND (%[1]s.owner_id = $user_id OR EXISTS {
+	            MATCH (%[1]s)-[:BELONGS_TO]->(nb:Notebook)
+	            WHERE nb.visibility <> 'private' OR nb.owner_id = $user_id
+	                  OR ` + notebookSharedWith("nb") + ` })
+	  WHEN %[1]s:Entity THEN %[1]s.tenant_id = $tenant_id
+	  ELSE false
+	END)`
+
+// graphNodeFields returns the fields of graph node %[1]s
+const graphNodeFields = `%[1]s.id AS id,
+	       CASE WHEN %[1]s:Document THEN 'document' WHEN %[1]s:Notebook THEN 'notebook' ELSE %[1]s.type END AS type,
+	       coalesce(%[1]s.name, %[1]s.original_name, %[1]s.id) AS label`
+
+// graphNodesQuery finds one page of the nodes within %[2]d relationships
+// of the root. Paths only pass through nodes the user may see, so hidden
+// nodes do not reveal how visible ones are connected.
+const graphNodesQuery = `
+	MATCH (root:%[1]s {id: $root_id, tenant_id: $tenant_id})
+	MATCH p = (root)-[:` + graphRelationships + `*1..%[2]d]-(n)
+	WHERE n <> root AND all(x IN nodes(p) WHERE %[3]s)
+	WITH n, min(length(p)) AS distance
+	RETURN %[4]s, distance
+	ORDER BY distance, type, id
+	SKIP $offset
+	LIMIT $limit
+`
+
+// graphEdgesQuery finds the relationships between the nodes of a view
+const graphEdgesQuery = `
+	UNWIND $ids AS id
+	MATCH (a:Document|Notebook|Entity {id: id, tenant_id: $tenant_id})-[r:` + graphRelationships + `]->(b)
+	WHERE b.id IN $ids AND b.tenant_id = $tenant_id
+	RETURN a.id AS source, b.id AS target, type(r) AS type, coalesce(r.count, 0) AS weight,
+	       coalesce(r.score, 0.0) AS score
+	ORDER BY source, target, type
+`
+
+// notebookGraphDocumentsQuery finds one page of the documents of a
+// notebook the user may see
+const notebookGraphDocumentsQuery = `
+	MATCH (root:Notebook {id: $root_id, tenant_id: $tenant_id})<-[:BELONGS_TO]-(n:Document)
+	WHERE %[1]s
+	RETURN %[2]s, 1 AS distance
+	ORDER BY label, id
+	SKIP $offset
+	LIMIT $limit
+`
+
+// notebookGraphEntitiesQuery finds the entities the documents of a page
+// mention most
+const notebookGraphEntitiesQuery = `
+	MATCH (d:Document {tenant_id: $tenant_id})-[m:MENTIONS]->(n:Entity {tenant_id: $tenant_id})
+	WHERE d.id IN $document_ids
+	WITH n, sum(coalesce(m.count, 1)) AS mentions
+	ORDER BY mentions DESC, n.id
+	LIMIT $limit
+	RETURN %[1]s, 2 AS distance
+`
+
+// GraphQuery selects a knowledge graph view. A Token, taken from a
+// previous view's page or expansion tokens, replaces the root, depth and
+// offset.
+type GraphQuery struct {
+	RootType models.GraphRootType
+	RootID   string
+	Depth    int
+	Limit    int
+	Offset   int
+	Token    string
+}
+
+// graphToken is the view a page or expansion token stands for
+type graphToken struct {
+	RootType models.GraphRootType `json:"r"`
+	RootID   string               `json:"i"`
+	Depth    int                  `json:"d"`
+	Offset   int                  `json:"o,omitempty"`
+}
+
+// encodeGraphToken returns the token of a view
+func encodeGraphToken(token graphToken) string {
+	data, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeGraphToken parses a page or expansion token
+func decodeGraphToken(value string) (graphToken, error) {
+	var token graphToken
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err == nil {
+		err = json.Unmarshal(data, &token)
+	}
+	if err != nil || graphRootLabels[token.RootType] == "" || token.RootID == "" || token.Offset < 0 {
+		return graphToken{}, errors.ValidationWithDetails("Invalid graph token", map[string]interface{}{
+			"param": "token",
+		}).WithErrorCode(errors.CodeInvalidGraphToken)
+	}
+	return token, nil
+}
+
+// graphRootType is the root type a view centred on a node of nodeType uses
+func graphRootType(nodeType string) models.GraphRootType {
+	switch models.GraphRootType(nodeType) {
+	case models.GraphRootDocument, models.GraphRootNotebook:
+		return models.GraphRootType(nodeType)
+	default:
+		return models.GraphRootEntity
+	}
+}
+
+// KnowledgeGraphService returns bounded views of the knowledge graph of a
+// space for visualization: the documents, notebooks and entities around a
+// node and the relationships between them
+type KnowledgeGraphService struct {
+	neo4j  *database.Neo4jClient
+	logger *logger.Logger
+}
+
+// NewKnowledgeGraphService creates a knowledge graph service
+func NewKnowledgeGraphService(neo4j *database.Neo4jClient, log *logger.Logger) *KnowledgeGraphService {
+	return &KnowledgeGraphService{
+		neo4j:  neo4j,
+		logger: log.WithService("knowledge_graph_service"),
+	}
+}
+
+// GetGraph returns one page of the nodes within the query's depth of its
+// root that the user may see, nearest first, and the edges between them.
+// The depth is capped by the traversal limit, and traversals the planner
+// expects to touch too many rows are refused.
+func (s *KnowledgeGraphService) GetGraph(ctx context.Context, query GraphQuery, userID string, spaceCtx *models.SpaceContext) (*models.GraphResponse, error) {
+	if !spaceCtx.CanRead() {
+		return nil, errors.Forbidden("Insufficient permissions to read the graph")
+	}
+	if query.Token != "" {
+		token, err := decodeGraphToken(query.Token)
+		if err != nil {
+			return nil, err
+		}
+		query.RootType, query.RootID, query.Depth, query.Offset = token.RootType, token.RootID, token.Depth, token.Offset
+	}
+	label := graphRootLabels[query.RootType]
+	if label == "" {
+		return nil, errors.ValidationWithDetails("Invalid root type", map[string]interface{}{
+			"param":   "root_type",
+			"allowed": []models.GraphRootType{models.GraphRootDocument, models.GraphRootNotebook, models.GraphRootEntity},
+		})
+	}
+	if query.RootID == "" {
+		return nil, errors.Validation("root_id is required", nil)
+	}
+
+	depth := s.neo4j.ClampDepth(query.Depth)
+	limit, offset := pagination.Clamp(query.Limit, query.Offset)
+	params := map[string]interface{}{
+		"root_id":   query.RootID,
+		"tenant_id": spaceCtx.TenantID,
+		"space_id":  spaceCtx.SpaceID,
+		"user_id":   userID,
+	}
+
+	root, err := s.getRoot(ctx, label, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = database.WithQueryName(ctx, "graph.nodes")
+	nodesQuery := fmt.Sprintf(graphNodesQuery, label, depth,
+		fmt.Sprintf(graphNodeAccess, "x", database.SoftDeleteFilter(ctx, "x")),
+		fmt.Sprintf(graphNodeFields, "n"))
+	params["offset"] = offset
+	params["limit"] = limit + 1
+
+	if err := s.neo4j.CheckQueryCost(ctx, nodesQuery, params); err != nil {
+		return nil, traversalError(err, map[string]interface{}{
+			"root_id": query.RootID,
+			"depth":   depth,
+		})
+	}
+
+	result, err := s.neo4j.ExecuteQuery(ctx, nodesQuery, params)
+	if err != nil {
+		s.logger.Error("Failed to get graph nodes", zap.String("root_id", query.RootID), zap.Error(err))
+		return nil, errors.Database("Failed to retrieve graph", err)
+	}
+
+	nodes := make([]*models.GraphNode, 0, len(result.Records))
+	for _, record := range result.Records {
+		node := recordToGraphNode(record)
+		node.ExpandToken = encodeGraphToken(graphToken{RootType: graphRootType(node.Type), RootID: node.ID, Depth: depth})
+		nodes = append(nodes, node)
+	}
+	nodes, hasMore := pagination.Trim(nodes, limit)
+
+	ids := make([]string, 0, len(nodes)+1)
+	ids = append(ids, root.ID)
+	for _, node := range nodes {
+		ids = append(ids, node.ID)
+	}
+	edges, err := s.getEdges(ctx, ids, spaceCtx.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.GraphResponse{
+		Root:  root,
+		Depth: depth,
+		Nodes: nodes,
+		Edges: edges,
+	}
+	if hasMore {
+		response.NextPageToken = encodeGraphToken(graphToken{RootType: query.RootType, RootID: query.RootID, Depth: depth, Offset: offset + limit})
+	}
+	return response, nil
+}
+
+// GetNotebookGraph returns one page of the documents of a notebook the user
+// may see, the entities they mention most, up to limit, and the edges
+// between them and the notebook: BELONGS_TO, MENTIONS, and SIMILAR_TO
+// between documents of the page. token is a previous page's
+// next_page_token.
+func (s *KnowledgeGraphService) GetNotebookGraph(ctx context.Context, notebookID string, limit int, token, userID string, spaceCtx *models.SpaceContext) (*models.GraphResponse, error) {
+	if !spaceCtx.CanRead() {
+		return nil, errors.Forbidden("Insufficient permissions to read the graph")
+	}
+	offset := 0
+	if token != "" {
+		decoded, err := decodeGraphToken(token)
+		if err != nil {
+			return nil, err
+		}
+		if decoded.RootType != models.GraphRootNotebook || decoded.RootID != notebookID {
+			return nil, errors.ValidationWithDetails("Invalid graph token", map[string]interface{}{
+				"param": "token",
+			}).WithErrorCode(errors.CodeInvalidGraphToken)
+		}
+		offset = decoded.Offset
+	}
+	limit, offset = pagination.Clamp(limit, offset)
+
+	query := GraphQuery{RootType: models.GraphRootNotebook, RootID: notebookID}
+	params := map[string]interface{}{
+		"root_id":   notebookID,
+		"tenant_id": spaceCtx.TenantID,
+		"space_id":  spaceCtx.SpaceID,
+		"user_id":   userID,
+	}
+	root, err := s.getRoot(ctx, graphRootLabels[models.GraphRootNotebook], query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = database.WithQueryName(ctx, "graph.notebook_documents")
+	params["offset"] = offset
+	params["limit"] = limit + 1
+	result, err := s.neo4j.ExecuteQuery(ctx, fmt.Sprintf(notebookGraphDocumentsQuery,
+		fmt.Sprintf(graphNodeAccess, "n", database.SoftDeleteFilter(ctx, "n")),
+		fmt.Sprintf(graphNodeFields, "n")), params)
+	if err != nil {
+		s.logger.Error("Failed to get notebook graph documents", zap.String("notebook_id", notebookID), zap.Error(err))
+		return nil, errors.Database("Failed to retrieve graph", err)
+	}
+	documents := make([]*models.GraphNode, 0, len(result.Records))
+	for _, record := range result.Records {
+		documents = append(documents, recordToGraphNode(record))
+	}
+	documents, hasMore := pagination.Trim(documents, limit)
+
+	ids := make([]string, 0, 2*len(documents)+1)
+	ids = append(ids, root.ID)
+	for _, document := range documents {
+		ids = append(ids, document.ID)
+	}
+	nodes := documents
+	if len(documents) > 0 {
+		result, err = s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "graph.notebook_entities"), fmt.Sprintf(notebookGraphEntitiesQuery, fmt.Sprintf(graphNodeFields, "n")), map[string]interface{}{
+			"tenant_id":    spaceCtx.TenantID,
+			"document_ids": ids[1:],
+			"limit":        limit,
+		})
+		if err != nil {
+			s.logger.Error("Failed to get notebook graph entities", zap.String("notebook_id", notebookID), zap.Error(err))
+			return nil, errors.Database("Failed to retrieve graph", err)
+		}
+		for _, record := range result.Records {
+			entity := recordToGraphNode(record)
+			nodes = append(nodes, entity)
+			ids = append(ids, entity.ID)
+		}
+	}
+	for _, node := range nodes {
+		node.ExpandToken = encodeGraphToken(graphToken{RootType: graphRootType(node.Type), RootID: node.ID, Depth: 1})
+	}
+
+	edges, err := s.getEdges(ctx, ids, spaceCtx.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.GraphResponse{
+		Root:  root,
+		Depth: 2,
+		Nodes: nodes,
+		Edges: edges,
+	}
+	if hasMore {
+		response.NextPageToken = encodeGraphToken(graphToken{RootType: models.GraphRootNotebook, RootID: notebookID, Depth: 2, Offset: offset + limit})
+	}
+	return response, nil
+}
+
+// getRoot returns the root node of a view, or not found when the user may
+// not see it
+func (s *KnowledgeGraphService) getRoot(ctx context.Context, label string, query GraphQuery, params map[string]interface{}) (*models.GraphNode, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "graph.root"), fmt.Sprintf(`
+		MATCH (root:%s {id: $root_id, tenant_id: $tenant_id})
+		WHERE %s
+		RETURN %s
+	`, label, fmt.Sprintf(graphNodeAccess, "root", database.SoftDeleteFilter(ctx, "root")), fmt.Sprintf(graphNodeFields, "root")), params)
+	if err != nil {
+		s.logger.Error("Failed to get graph root", zap.String("root_id", query.RootID), zap.Error(err))
+		return nil, errors.Database("Failed to retrieve graph", err)
+	}
+	if len(result.Records) == 0 {
+		codes := map[models.GraphRootType]string{
+			models.GraphRootDocument: errors.CodeDocumentNotFound,
+			models.GraphRootNotebook: errors.CodeNotebookNotFound,
+			models.GraphRootEntity:   errors.CodeEntityNotFound,
+		}
+		return nil, errors.NotFoundWithDetails("Graph root not found", map[string]interface{}{
+			"root_type": string(query.RootType),
+			"root_id":   query.RootID,
+		}).WithErrorCode(codes[query.RootType])
+	}
+	return recordToGraphNode(result.Records[0]), nil
+}
+
+// getEdges returns the relationships between the nodes of a view
+func (s *KnowledgeGraphService) getEdges(ctx context.Context, ids []string, tenantID string) ([]*models.GraphEdge, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "graph.edges"), graphEdgesQuery, map[string]interface{}{
+		"ids":       ids,
+		"tenant_id": tenantID,
+	})
+	if err != nil {
+		s.logger.Error("Failed to get graph edges", zap.Error(err))
+		return nil, errors.Database("Failed to retrieve graph", err)
+	}
+
+	edges := make([]*models.GraphEdge, 0, len(result.Records))
+	for _, record := range result.Records {
+		edges = append(edges, &models.GraphEdge{
+			Source: recordString(record, "source"),
+			Target: recordString(record, "target"),
+			Type:   recordString(record, "type"),
+			Weight: int(recordInt64(record, "weight")),
+			Score:  recordFloat(record, "score"),
+		})
+	}
+	return edges, nil
+}
+
+// recordToGraphNode reads a node returned with graphNodeFields
+func recordToGraphNode(record *neo4j.Record) *models.GraphNode {
+	return &models.GraphNode{
+		ID:       recordString(record, "id"),
+		Type:     recordString(record, "type"),
+		Label:    recordString(record, "label"),
+		Distance: int(recordInt64(record, "distance")),
+	}
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+func TestGraphTokens(t *testing.T) {
+	token := graphToken{RootType: models.GraphRootNotebook, RootID: "nb-1", Depth: 2, Offset: 40}
+	decoded, err := decodeGraphToken(encodeGraphToken(token))
+	require.NoError(t, err)
+	assert.Equal(t, token, decoded)
+
+	for _, value := range []string{"not base64!", encodeGraphToken(graphToken{RootType: "space", RootID: "sp-1"}), encodeGraphToken(graphToken{RootType: models.GraphRootEntity})} {
+		_, err := decodeGraphToken(value)
+		apiErr, ok := errors.AsAPIError(err)
+		require.True(t, ok, value)
+		assert.Equal(t, errors.CodeInvalidGraphToken, apiErr.ErrorCode)
+	}
+
+	assert.Equal(t, models.GraphRootDocument, graphRootType("document"))
+	assert.Equal(t, models.GraphRootEntity, graphRootType("organization"), "people, organizations and topics are entities")
+}
+
+func TestNotebookGraphRejectsOtherTokens(t *testing.T) {
+	s := &KnowledgeGraphService{logger: setupTestLogger(t)}
+	spaceCtx := &models.SpaceContext{TenantID: "tenant-1", SpaceID: "space-1", UserRole: "viewer", Permissions: []string{"read"}}
+
+	for _, token := range []string{
+		encodeGraphToken(graphToken{RootType: models.GraphRootNotebook, RootID: "nb-2", Depth: 2, Offset: 20}),
+		encodeGraphToken(graphToken{RootType: models.GraphRootDocument, RootID: "nb-1", Depth: 2, Offset: 20}),
+	} {
+		_, err := s.GetNotebookGraph(context.Background(), "nb-1", 20, token, "user-1", spaceCtx)
+		apiErr, ok := errors.AsAPIError(err)
+		require.True(t, ok)
+		assert.Equal(t, errors.CodeInvalidGraphToken, apiErr.ErrorCode)
+	}
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+)
+
+// LeaderLockKey is the lock replicas compete for to run singleton jobs
+const LeaderLockKey = "aether-be:leader"
+
+// LockStore provides expiring, owner-checked locks shared by all replicas.
+// database.RedisClient implements it; LocalLockStore serves single-instance
+// deployments without Redis.
+type LockStore interface {
+	AcquireLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error)
+	RenewLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error)
+	ReleaseLock(ctx context.Context, key, owner string) error
+}
+
+// LocalLockStore is an in-process LockStore. It only coordinates goroutines
+// of one process, so it must not be used when several replicas run.
+type LocalLockStore struct {
+	mu    sync.Mutex
+	locks map[string]localLock
+}
+
+type localLock struct {
+	owner     string
+	expiresAt time.Time
+}
+
+// NewLocalLockStore creates an empty in-process lock store
+func NewLocalLockStore() *LocalLockStore {
+	return &LocalLockStore{locks: make(map[string]localLock)}
+}
+
+// AcquireLock takes the lock for owner if it is free or expired
+func (l *LocalLockStore) AcquireLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if lock, held := l.locks[key]; held && time.Now().Before(lock.expiresAt) {
+		return false, nil
+	}
+	l.locks[key] = localLock{owner: owner, expiresAt: time.Now().Add(ttl)}
+	return true, nil
+}
+
+// RenewLock extends the lock if owner still holds it
+func (l *LocalLockStore) RenewLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	lock, held := l.locks[key]
+	if !held || lock.owner != owner || time.Now().After(lock.expiresAt) {
+		return false, nil
+	}
+	l.locks[key] = localLock{owner: owner, expiresAt: time.Now().Add(ttl)}
+	return true, nil
+}
+
+// ReleaseLock deletes the lock if owner still holds it
+func (l *LocalLockStore) ReleaseLock(ctx context.Context, key, owner string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if lock, held := l.locks[key]; held && lock.owner == owner {
+		delete(l.locks, key)
+	}
+	return nil
+}
+
+// LeaderElector keeps one replica at a time as leader by holding an
+// expiring lock. Singleton jobs (see Scheduler) run only on the leader; if
+// it dies, the lease expires and another replica takes over.
+type LeaderElector struct {
+	locks  LockStore
+	key    string
+	id     string
+	ttl    time.Duration
+	leader atomic.Bool
+	logger *logger.Logger
+}
+
+// NewLeaderElector creates an elector for the given lock key. id must be
+// unique per replica.
+func NewLeaderElector(locks LockStore, key, id string, ttl time.Duration, log *logger.Logger) *LeaderElector {
+	return &LeaderElector{
+		locks:  locks,
+		key:    key,
+		id:     id,
+		ttl:    ttl,
+		logger: log.WithService("leader_election"),
+	}
+}
+
+// IsLeader reports whether this replica currently holds the lease
+func (e *LeaderElector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// Run campaigns for and renews the lease until ctx is done, then releases
+// it so another replica can take over without waiting for expiry
+func (e *LeaderElector) Run(ctx context.Context) {
+	// Renew well before expiry so a slow round trip does not lose the lease
+	interval := e.ttl / 3
+
+	e.campaign(ctx)
+	for sleepContext(ctx, interval) {
+		e.campaign(ctx)
+	}
+
+	if e.leader.Swap(false) {
+		releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := e.locks.ReleaseLock(releaseCtx, e.key, e.id); err != nil {
+			e.logger.Warn("Failed to release leader lease", zap.Error(err))
+		}
+		e.logger.Info("Stepped down as leader", zap.String("instance_id", e.id))
+	}
+}
+
+// campaign acquires or renews the lease and records the outcome. Errors
+// from the lock store count as lost leadership so two replicas never both
+// believe they lead.
+func (e *LeaderElector) campaign(ctx context.Context) {
+	var (
+		held bool
+		err  error
+	)
+	if e.leader.Load() {
+		held, err = e.locks.RenewLock(ctx, e.key, e.id, e.ttl)
+	} else {
+		held, err = e.locks.AcquireLock(ctx, e.key, e.id, e.ttl)
+	}
+	if err != nil {
+		if ctx.Err() == nil {
+			e.logger.Warn("Leader election failed", zap.Error(err))
+		}
+		held = false
+	}
+
+	if was := e.leader.Swap(held); was != held {
+		if held {
+			e.logger.Info("Elected leader", zap.String("instance_id", e.id))
+		} else {
+			e.logger.Warn("Lost leadership", zap.String("instance_id", e.id))
+		}
+	}
+}
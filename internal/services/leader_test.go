@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalLockStoreOwnership(t *testing.T) {
+	ctx := context.Background()
+	locks := NewLocalLockStore()
+
+	acquired, err := locks.AcquireLock(ctx, "job", "a", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	acquired, _ = locks.AcquireLock(ctx, "job", "b", time.Minute)
+	assert.False(t, acquired, "held lock must not be taken by another owner")
+
+	renewed, _ := locks.RenewLock(ctx, "job", "b", time.Minute)
+	assert.False(t, renewed, "only the owner may renew")
+
+	require.NoError(t, locks.ReleaseLock(ctx, "job", "b"))
+	acquired, _ = locks.AcquireLock(ctx, "job", "b", time.Minute)
+	assert.False(t, acquired, "release by a non-owner must be ignored")
+
+	require.NoError(t, locks.ReleaseLock(ctx, "job", "a"))
+	acquired, _ = locks.AcquireLock(ctx, "job", "b", time.Minute)
+	assert.True(t, acquired)
+}
+
+func TestLocalLockStoreExpiry(t *testing.T) {
+	ctx := context.Background()
+	locks := NewLocalLockStore()
+
+	acquired, _ := locks.AcquireLock(ctx, "job", "a", 10*time.Millisecond)
+	require.True(t, acquired)
+	time.Sleep(20 * time.Millisecond)
+
+	renewed, _ := locks.RenewLock(ctx, "job", "a", time.Minute)
+	assert.False(t, renewed, "an expired lease cannot be renewed")
+
+	acquired, _ = locks.AcquireLock(ctx, "job", "b", time.Minute)
+	assert.True(t, acquired, "an expired lease is free to take")
+}
+
+func TestLeaderElectorSingleLeader(t *testing.T) {
+	locks := NewLocalLockStore()
+	log := setupTestLogger(t)
+	first := NewLeaderElector(locks, LeaderLockKey, "replica-1", time.Minute, log)
+	second := NewLeaderElector(locks, LeaderLockKey, "replica-2", time.Minute, log)
+
+	ctx := context.Background()
+	first.campaign(ctx)
+	second.campaign(ctx)
+	assert.True(t, first.IsLeader())
+	assert.False(t, second.IsLeader())
+
+	// Renewal keeps the lease with the current leader
+	first.campaign(ctx)
+	second.campaign(ctx)
+	assert.True(t, first.IsLeader())
+	assert.False(t, second.IsLeader())
+}
+
+func TestLeaderElectorStepsDownOnShutdown(t *testing.T) {
+	locks := NewLocalLockStore()
+	log := setupTestLogger(t)
+	first := NewLeaderElector(locks, LeaderLockKey, "replica-1", time.Minute, log)
+	second := NewLeaderElector(locks, LeaderLockKey, "replica-2", time.Minute, log)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		first.Run(ctx)
+		close(done)
+	}()
+	require.Eventually(t, first.IsLeader, time.Second, 5*time.Millisecond)
+
+	cancel()
+	<-done
+	assert.False(t, first.IsLeader())
+
+	// The released lease is available at once rather than after expiry
+	second.campaign(context.Background())
+	assert.True(t, second.IsLeader())
+}
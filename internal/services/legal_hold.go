@@ -0,0 +1,821 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// documentHeldBy returns the condition that the legal hold target
+// held_target covers document alias: it is the document's notebook or the
+// user who owns it
+func documentHeldBy(alias string) string {
+	return fmt.Sprintf("((held_target:Notebook AND (%[1]s)-[:BELONGS_TO]->(held_target)) OR (held_target:User AND held_target.id = %[1]s.owner_id))", alias)
+}
+
+// notebookHeldBy returns the condition that the legal hold target
+// held_target covers notebook alias: it is the notebook, the user who owns
+// it or the owner of one of its documents
+func notebookHeldBy(alias string) string {
+	return fmt.Sprintf("(held_target = %[1]s OR (held_target:User AND (held_target.id = %[1]s.owner_id OR EXISTS { MATCH (:Document {owner_id: held_target.id})-[:BELONGS_TO]->(%[1]s) })))", alias)
+}
+
+// activeLegalHoldMatch matches the active legal holds of the tenant of
+// node alias with their targets as held_target
+func activeLegalHoldMatch(alias string) string {
+	return fmt.Sprintf("(legal_hold:LegalHold {status: 'active', tenant_id: %s.tenant_id})-[:HOLDS]->(held_target)", alias)
+}
+
+// documentUnderLegalHold returns the condition that document alias is
+// under an active legal hold
+func documentUnderLegalHold(alias string) string {
+	return "EXISTS { MATCH " + activeLegalHoldMatch(alias) + " WHERE " + documentHeldBy(alias) + " }"
+}
+
+// notebookUnderLegalHold returns the condition that notebook alias, or a
+// document of it, is under an active legal hold
+func notebookUnderLegalHold(alias string) string {
+	return "EXISTS { MATCH " + activeLegalHoldMatch(alias) + " WHERE " + notebookHeldBy(alias) + " }"
+}
+
+// legalHoldFields is the RETURN clause recordToLegalHold reads
+const legalHoldFields = `
+	h.id AS id, h.tenant_id AS tenant_id, h.name AS name, h.matter AS matter,
+	h.description AS description, h.status AS status,
+	h.created_by AS created_by, h.created_at AS created_at,
+	h.released_by AS released_by, h.released_at AS released_at,
+	h.release_reason AS release_reason,
+	[(h)-[:HOLDS]->(u:User) | u.id] AS user_ids,
+	[(h)-[:HOLDS]->(n:Notebook) | n.id] AS notebook_ids
+`
+
+// LegalHoldService places users and notebooks under e-discovery holds,
+// which keep their documents and notebooks from being edited, deleted or
+// purged, and collects and exports held content. Exports are bundles in
+// object storage written the way tenant exports are, whose manifest
+// records the chain of custody of the collected files; the manifest's hash
+// is recorded in the audit log.
+type LegalHoldService struct {
+	neo4j      *database.Neo4jClient
+	exports    *TenantExportService
+	encryption *TextEncryptionService
+	audit      *AuditService
+	logger     *logger.Logger
+}
+
+// NewLegalHoldService creates a legal hold service. Exports respond 503
+// while the tenant export service has no storage.
+func NewLegalHoldService(neo4j *database.Neo4jClient, exports *TenantExportService, audit *AuditService, log *logger.Logger) *LegalHoldService {
+	return &LegalHoldService{
+		neo4j:   neo4j,
+		exports: exports,
+		audit:   audit,
+		logger:  log.WithService("legal_hold"),
+	}
+}
+
+// SetTextEncryption sets the service decrypting the extracted text of
+// documents in spaces that encrypt text at rest, so collections search it
+func (s *LegalHoldService) SetTextEncryption(encryption *TextEncryptionService) {
+	s.encryption = encryption
+}
+
+// CreateHold places users and notebooks of a tenant under a new legal hold
+func (s *LegalHoldService) CreateHold(ctx context.Context, req models.LegalHoldCreateRequest, actorID string) (*models.LegalHold, error) {
+	userIDs, notebookIDs := distinctIDs(req.UserIDs), distinctIDs(req.NotebookIDs)
+	if len(userIDs) == 0 && len(notebookIDs) == 0 {
+		return nil, errors.Validation("user_ids or notebook_ids is required", nil)
+	}
+
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "legal_hold.targets"), `
+		OPTIONAL MATCH (sp:Space {tenant_id: $tenant_id})
+		WITH count(sp) AS spaces
+		OPTIONAL MATCH (u:User)
+		WHERE u.id IN $user_ids
+		WITH spaces, collect(DISTINCT u.id) AS user_ids
+		OPTIONAL MATCH (n:Notebook {tenant_id: $tenant_id})
+		WHERE n.id IN $notebook_ids
+		RETURN spaces, user_ids, collect(DISTINCT n.id) AS notebook_ids
+	`, map[string]interface{}{
+		"tenant_id":    req.TenantID,
+		"user_ids":     userIDs,
+		"notebook_ids": notebookIDs,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to look up legal hold targets", err)
+	}
+	if recordInt(result.Records, "spaces") == 0 {
+		return nil, errors.NotFoundWithDetails("Tenant not found", map[string]interface{}{
+			"tenant_id": req.TenantID,
+		}).WithErrorCode(errors.CodeTenantNotFound)
+	}
+	foundUsers, _ := result.Records[0].Get("user_ids")
+	foundNotebooks, _ := result.Records[0].Get("notebook_ids")
+	users, _ := foundUsers.([]interface{})
+	notebooks, _ := foundNotebooks.([]interface{})
+	missingUsers, missingNotebooks := missingIDs(userIDs, users), missingIDs(notebookIDs, notebooks)
+	if len(missingUsers) > 0 || len(missingNotebooks) > 0 {
+		return nil, errors.ValidationWithDetails("Some users or notebooks to hold do not exist in the tenant", map[string]interface{}{
+			"missing_user_ids":     missingUsers,
+			"missing_notebook_ids": missingNotebooks,
+		})
+	}
+
+	hold := &models.LegalHold{
+		ID:          uuid.New().String(),
+		TenantID:    req.TenantID,
+		Name:        req.Name,
+		Matter:      req.Matter,
+		Description: req.Description,
+		Status:      models.LegalHoldActive,
+		UserIDs:     userIDs,
+		NotebookIDs: notebookIDs,
+		CreatedBy:   actorID,
+		CreatedAt:   time.Now().UTC(),
+	}
+	_, err = s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "legal_hold.create"), `
+		CREATE (h:LegalHold {
+			id: $id, tenant_id: $tenant_id, name: $name, matter: $matter,
+			description: $description, status: $status,
+			created_by: $created_by, created_at: datetime($created_at)
+		})
+		WITH h
+		CALL {
+			WITH h
+			MATCH (u:User)
+			WHERE u.id IN $user_ids
+			MERGE (h)-[:HOLDS]->(u)
+		}
+		CALL {
+			WITH h
+			MATCH (n:Notebook {tenant_id: $tenant_id})
+			WHERE n.id IN $notebook_ids
+			MERGE (h)-[:HOLDS]->(n)
+		}
+	`, map[string]interface{}{
+		"id":           hold.ID,
+		"tenant_id":    hold.TenantID,
+		"name":         hold.Name,
+		"matter":       hold.Matter,
+		"description":  hold.Description,
+		"status":       string(hold.Status),
+		"created_by":   actorID,
+		"created_at":   hold.CreatedAt.Format(time.RFC3339Nano),
+		"user_ids":     userIDs,
+		"notebook_ids": notebookIDs,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to create legal hold", err)
+	}
+
+	s.logger.FromContext(ctx).Info("Legal hold placed",
+		zap.Bool("audit", true),
+		zap.String("hold_id", hold.ID),
+		zap.String("tenant_id", hold.TenantID),
+		zap.Int("users", len(userIDs)),
+		zap.Int("notebooks", len(notebookIDs)),
+		zap.String("actor_id", actorID),
+	)
+	recordAuditEvent(ctx, s.audit, s.logger, &models.AuditEvent{
+		Action:       "legal_hold.create",
+		ResourceType: "legal_hold",
+		ResourceID:   hold.ID,
+		TenantID:     hold.TenantID,
+		ActorID:      actorID,
+		Source:       ConfigSourceAdmin,
+		Details: map[string]interface{}{
+			"name":         hold.Name,
+			"matter":       hold.Matter,
+			"user_ids":     userIDs,
+			"notebook_ids": notebookIDs,
+		},
+	})
+	return hold, nil
+}
+
+// GetHold returns a legal hold
+func (s *LegalHoldService) GetHold(ctx context.Context, holdID string) (*models.LegalHold, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "legal_hold.get"), `
+		MATCH (h:LegalHold {id: $id})
+		RETURN `+legalHoldFields, map[string]interface{}{"id": holdID})
+	if err != nil {
+		return nil, errors.Database("Failed to get legal hold", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, errors.NotFoundWithDetails("Legal hold not found", map[string]interface{}{
+			"hold_id": holdID,
+		}).WithErrorCode(errors.CodeLegalHoldNotFound)
+	}
+	return recordToLegalHold(result.Records[0]), nil
+}
+
+// activeHold returns a legal hold that has not been released
+func (s *LegalHoldService) activeHold(ctx context.Context, holdID string) (*models.LegalHold, error) {
+	hold, err := s.GetHold(ctx, holdID)
+	if err != nil {
+		return nil, err
+	}
+	if hold.Status != models.LegalHoldActive {
+		return nil, errors.ConflictWithDetails("The legal hold has been released", map[string]interface{}{
+			"hold_id":     holdID,
+			"released_at": hold.ReleasedAt,
+		}).WithErrorCode(errors.CodeLegalHoldReleased)
+	}
+	return hold, nil
+}
+
+// ListHolds lists the legal holds of a tenant, or of every tenant when
+// tenantID is empty, with a status or any, newest first
+func (s *LegalHoldService) ListHolds(ctx context.Context, tenantID string, status models.LegalHoldStatus, limit, offset int) (*models.LegalHoldListResponse, error) {
+	switch status {
+	case "", models.LegalHoldActive, models.LegalHoldReleased:
+	default:
+		return nil, errors.ValidationWithDetails("Unknown legal hold status", map[string]interface{}{
+			"status":  status,
+			"allowed": []string{"active", "released"},
+		})
+	}
+	limit, offset = pagination.Clamp(limit, offset)
+
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "legal_hold.list"), `
+		MATCH (h:LegalHold)
+		WHERE ($tenant_id = '' OR h.tenant_id = $tenant_id)
+		  AND ($status = '' OR h.status = $status)
+		RETURN `+legalHoldFields+`
+		ORDER BY h.created_at DESC, h.id
+		SKIP $offset
+		LIMIT $limit
+	`, map[string]interface{}{
+		"tenant_id": tenantID,
+		"status":    string(status),
+		"offset":    offset,
+		"limit":     limit + 1,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to list legal holds", err)
+	}
+
+	holds := make([]*models.LegalHold, 0, len(result.Records))
+	for _, record := range result.Records {
+		holds = append(holds, recordToLegalHold(record))
+	}
+	holds, hasMore := pagination.Trim(holds, limit)
+	return &models.LegalHoldListResponse{
+		Holds:   holds,
+		Limit:   limit,
+		Offset:  offset,
+		HasMore: hasMore,
+	}, nil
+}
+
+// ReleaseHold releases a legal hold. Its content can be edited and deleted
+// again unless another hold covers it; the hold is kept for the record.
+func (s *LegalHoldService) ReleaseHold(ctx context.Context, holdID, reason, actorID string) (*models.LegalHold, error) {
+	if _, err := s.activeHold(ctx, holdID); err != nil {
+		return nil, err
+	}
+
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "legal_hold.release"), `
+		MATCH (h:LegalHold {id: $id, status: $active})
+		SET h.status = $released,
+		    h.released_by = $actor_id,
+		    h.released_at = datetime(),
+		    h.release_reason = $reason
+		RETURN `+legalHoldFields, map[string]interface{}{
+		"id":       holdID,
+		"active":   string(models.LegalHoldActive),
+		"released": string(models.LegalHoldReleased),
+		"actor_id": actorID,
+		"reason":   reason,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to release legal hold", err)
+	}
+	if len(result.Records) == 0 {
+		// Released concurrently
+		_, err := s.activeHold(ctx, holdID)
+		return nil, err
+	}
+	hold := recordToLegalHold(result.Records[0])
+
+	s.logger.FromContext(ctx).Info("Legal hold released",
+		zap.Bool("audit", true),
+		zap.String("hold_id", holdID),
+		zap.String("tenant_id", hold.TenantID),
+		zap.String("actor_id", actorID),
+	)
+	recordAuditEvent(ctx, s.audit, s.logger, &models.AuditEvent{
+		Action:       "legal_hold.release",
+		ResourceType: "legal_hold",
+		ResourceID:   holdID,
+		TenantID:     hold.TenantID,
+		ActorID:      actorID,
+		Source:       ConfigSourceAdmin,
+		Details:      map[string]interface{}{"reason": reason},
+	})
+	return hold, nil
+}
+
+// CheckDocument fails with 409 and AETHER-HOLD-001 when a document is
+// under an active legal hold, so it is not edited or deleted
+func (s *LegalHoldService) CheckDocument(ctx context.Context, document *models.Document) error {
+	if s == nil {
+		return nil
+	}
+	return s.check(ctx, "legal_hold.check_document", `
+		MATCH (d:Document {id: $id, tenant_id: $tenant_id})
+		MATCH `+activeLegalHoldMatch("d")+`
+		WHERE `+documentHeldBy("d")+`
+		RETURN collect(DISTINCT legal_hold.id) AS hold_ids
+	`, "document_id", document.ID, document.TenantID)
+}
+
+// CheckNotebook fails with 409 and AETHER-HOLD-001 when a notebook, or a
+// document of it, is under an active legal hold, so it is not edited or
+// deleted
+func (s *LegalHoldService) CheckNotebook(ctx context.Context, notebookID, tenantID string) error {
+	if s == nil {
+		return nil
+	}
+	return s.check(ctx, "legal_hold.check_notebook", `
+		MATCH (n:Notebook {id: $id, tenant_id: $tenant_id})
+		MATCH `+activeLegalHoldMatch("n")+`
+		WHERE `+notebookHeldBy("n")+`
+		RETURN collect(DISTINCT legal_hold.id) AS hold_ids
+	`, "notebook_id", notebookID, tenantID)
+}
+
+// CheckOrganization fails with 409 and AETHER-HOLD-001 when a legal hold
+// is active in the tenant of one of an organization's spaces, so the
+// organization is not deleted with its content
+func (s *LegalHoldService) CheckOrganization(ctx context.Context, organizationID string) error {
+	if s == nil {
+		return nil
+	}
+	return s.check(ctx, "legal_hold.check_organization", `
+		MATCH (:Organization {id: $id})-[:HAS_SPACE]->(sp:Space)
+		MATCH (legal_hold:LegalHold {status: 'active', tenant_id: sp.tenant_id})
+		RETURN collect(DISTINCT legal_hold.id) AS hold_ids
+	`, "organization_id", organizationID, "")
+}
+
+// check runs a query returning the hold_ids of the active legal holds
+// covering a resource, failing when there are any
+func (s *LegalHoldService) check(ctx context.Context, queryName, query, idKey, id, tenantID string) error {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, queryName), query, map[string]interface{}{
+		"id":        id,
+		"tenant_id": tenantID,
+	})
+	if err != nil {
+		return errors.Database("Failed to check legal holds", err)
+	}
+	if len(result.Records) == 0 {
+		return nil
+	}
+	holdIDs := recordStrings(result.Records[0], "hold_ids")
+	if len(holdIDs) == 0 {
+		return nil
+	}
+	return errors.ConflictWithDetails("Content under a legal hold cannot be edited or deleted", map[string]interface{}{
+		idKey:      id,
+		"hold_ids": holdIDs,
+	}).WithErrorCode(errors.CodeUnderLegalHold)
+}
+
+// heldDocument is a document a collection found, with the storage keys of
+// its current file and of its versions' files
+type heldDocument struct {
+	document    *models.LegalHoldDocument
+	storageKeys []string
+}
+
+// legalHoldMatcher finds the keywords of collection criteria in text, as
+// whole words and ignoring case
+type legalHoldMatcher struct {
+	keywords []string
+	patterns []*regexp.Regexp
+}
+
+// newLegalHoldMatcher checks collection criteria and returns their matcher
+func newLegalHoldMatcher(criteria models.LegalHoldCriteria) (*legalHoldMatcher, error) {
+	if criteria.From != nil && criteria.To != nil && !criteria.From.Before(*criteria.To) {
+		return nil, errors.ValidationWithDetails("from must be before to", map[string]interface{}{
+			"from": criteria.From,
+			"to":   criteria.To,
+		})
+	}
+	m := &legalHoldMatcher{}
+	for _, keyword := range distinctIDs(criteria.Keywords) {
+		if pattern := termPattern([]string{keyword}); pattern != nil {
+			m.keywords = append(m.keywords, strings.TrimSpace(keyword))
+			m.patterns = append(m.patterns, pattern)
+		}
+	}
+	return m, nil
+}
+
+// match returns the keywords found in the texts, and whether the texts
+// match: without keywords, every text does
+func (m *legalHoldMatcher) match(texts ...string) ([]string, bool) {
+	if len(m.patterns) == 0 {
+		return nil, true
+	}
+	var matched []string
+	for i, pattern := range m.patterns {
+		for _, text := range texts {
+			if pattern.MatchString(text) {
+				matched = append(matched, m.keywords[i])
+				break
+			}
+		}
+	}
+	return matched, len(matched) > 0
+}
+
+// collect returns the documents a legal hold covers that match the
+// criteria, oldest first. Documents in the trash are included, since the
+// hold keeps them from being purged.
+func (s *LegalHoldService) collect(ctx context.Context, hold *models.LegalHold, criteria models.LegalHoldCriteria) ([]*heldDocument, error) {
+	matcher, err := newLegalHoldMatcher(criteria)
+	if err != nil {
+		return nil, err
+	}
+	params := map[string]interface{}{
+		"hold_id":   hold.ID,
+		"tenant_id": hold.TenantID,
+		"from":      nil,
+		"to":        nil,
+	}
+	if criteria.From != nil {
+		params["from"] = criteria.From.UTC().Format(time.RFC3339Nano)
+	}
+	if criteria.To != nil {
+		params["to"] = criteria.To.UTC().Format(time.RFC3339Nano)
+	}
+
+	var documents []*heldDocument
+	_, err = s.neo4j.StreamQuery(database.WithQueryName(ctx, "legal_hold.collect"), `
+		MATCH (h:LegalHold {id: $hold_id})
+		CALL {
+			WITH h
+			MATCH (h)-[:HOLDS]->(:Notebook)<-[:BELONGS_TO]-(x:Document {tenant_id: $tenant_id})
+			RETURN x
+			UNION
+			WITH h
+			MATCH (h)-[:HOLDS]->(u:User)
+			MATCH (x:Document {tenant_id: $tenant_id, owner_id: u.id})
+			RETURN x
+		}
+		WITH DISTINCT x
+		WHERE ($from IS NULL OR x.created_at >= datetime($from))
+		  AND ($to IS NULL OR x.created_at < datetime($to))
+		OPTIONAL MATCH (x)-[:HAS_VERSION]->(v:DocumentVersion)
+		WITH x, collect(v.storage_path) AS version_paths
+		RETURN x.id AS id, x.name AS name, x.description AS description,
+		       x.tags AS tags, x.notebook_id AS notebook_id, x.owner_id AS owner_id,
+		       x.status AS status, x.mime_type AS mime_type, x.size_bytes AS size_bytes,
+		       x.checksum AS checksum, x.created_at AS created_at, x.updated_at AS updated_at,
+		       x.extracted_text AS text, `+tenantExportStorageKeys+` AS storage_keys
+		ORDER BY x.created_at, x.id
+	`, params, func(record *neo4j.Record) error {
+		text, _ := s.encryption.Open(hold.TenantID, recordString(record, "text"))
+		matched, ok := matcher.match(recordString(record, "name"), recordString(record, "description"),
+			strings.Join(recordStrings(record, "tags"), " "), text)
+		if !ok {
+			return nil
+		}
+		documents = append(documents, &heldDocument{
+			document: &models.LegalHoldDocument{
+				DocumentID:      recordString(record, "id"),
+				Name:            recordString(record, "name"),
+				NotebookID:      recordString(record, "notebook_id"),
+				OwnerID:         recordString(record, "owner_id"),
+				Status:          recordString(record, "status"),
+				MimeType:        recordString(record, "mime_type"),
+				SizeBytes:       recordInt64(record, "size_bytes"),
+				Checksum:        recordString(record, "checksum"),
+				CreatedAt:       recordTime(record, "created_at"),
+				UpdatedAt:       recordTime(record, "updated_at"),
+				MatchedKeywords: matched,
+			},
+			storageKeys: recordStrings(record, "storage_keys"),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to collect held documents", err)
+	}
+	return documents, nil
+}
+
+// Collect returns one page of the documents an active legal hold covers
+// that match the criteria, oldest first
+func (s *LegalHoldService) Collect(ctx context.Context, holdID string, criteria models.LegalHoldCriteria, limit, offset int) (*models.LegalHoldCollection, error) {
+	hold, err := s.activeHold(ctx, holdID)
+	if err != nil {
+		return nil, err
+	}
+	limit, offset = pagination.Clamp(limit, offset)
+	documents, err := s.collect(ctx, hold, criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	page := make([]*models.LegalHoldDocument, 0, limit)
+	for i := offset; i < len(documents) && len(page) < limit; i++ {
+		page = append(page, documents[i].document)
+	}
+	return &models.LegalHoldCollection{
+		HoldID:    holdID,
+		Criteria:  criteria,
+		Documents: page,
+		Total:     len(documents),
+		Limit:     limit,
+		Offset:    offset,
+		HasMore:   pagination.HasMore(offset, len(page), len(documents)),
+	}, nil
+}
+
+// CheckExport checks that a legal hold is active, that the criteria are
+// valid and that exports are available, and returns the hold, so an export
+// fails fast rather than in a job the caller has to poll
+func (s *LegalHoldService) CheckExport(ctx context.Context, holdID string, criteria models.LegalHoldCriteria) (*models.LegalHold, error) {
+	if s.exports == nil || s.exports.storage == nil {
+		return nil, errors.ServiceUnavailable("Storage is not available for exports")
+	}
+	if _, err := newLegalHoldMatcher(criteria); err != nil {
+		return nil, err
+	}
+	return s.activeHold(ctx, holdID)
+}
+
+// Export collects the documents an active legal hold covers that match the
+// criteria and writes them to a bundle under exports/legal-holds/: their
+// records, extracted text and stored files, and the audit events of the
+// documents and of the hold. The manifest records the hold, the criteria,
+// who collected the bundle and when, the SHA-256 hash of every file and
+// the custody steps; its own hash is returned and recorded in the audit
+// log. Each document and stored file written is reported through progress.
+func (s *LegalHoldService) Export(ctx context.Context, holdID string, criteria models.LegalHoldCriteria, actorID string, progress func(models.JobProgress)) (*models.LegalHoldExport, error) {
+	hold, err := s.CheckExport(ctx, holdID, criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	collectedAt := time.Now().UTC()
+	documents, err := s.collect(ctx, hold, criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	storage := s.exports.storage
+	bundle := &tenantExportBundle{
+		storage:  storage,
+		prefix:   fmt.Sprintf("exports/legal-holds/%s/%s/", holdID, collectedAt.Format("20060102T150405Z")),
+		manifest: &models.TenantExportManifest{Files: []models.TenantExportFile{}},
+	}
+	manifest := &models.LegalHoldExportManifest{
+		Version:     models.LegalHoldExportManifestVersion,
+		Hold:        hold,
+		Criteria:    criteria,
+		CollectedBy: actorID,
+		CollectedAt: collectedAt,
+		Counts:      make(map[string]int),
+		Custody: []models.LegalHoldCustodyEntry{{
+			At:      collectedAt,
+			ActorID: actorID,
+			Action:  "collected",
+			Detail:  fmt.Sprintf("%d held documents matched the criteria", len(documents)),
+		}},
+	}
+
+	report := models.JobProgress{Total: len(documents)}
+	for _, document := range documents {
+		report.Total += len(document.storageKeys)
+	}
+	advance := func(failed bool) {
+		if failed {
+			report.Failed++
+		} else {
+			report.Completed++
+		}
+		progress(report)
+	}
+
+	var records strings.Builder
+	encoder := json.NewEncoder(&records)
+	documentIDs := make([]string, 0, len(documents))
+	texts, copied := 0, 0
+	for _, held := range documents {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		documentIDs = append(documentIDs, held.document.DocumentID)
+		if err := encoder.Encode(struct {
+			*models.LegalHoldDocument
+			StorageKeys []string `json:"storage_keys"`
+		}{held.document, held.storageKeys}); err != nil {
+			return nil, errors.Internal("Failed to encode held document")
+		}
+
+		text, err := s.documentText(ctx, hold.TenantID, held.document.DocumentID)
+		if err != nil {
+			return nil, err
+		}
+		if text != "" {
+			file := models.TenantExportFile{Path: "text/" + held.document.DocumentID + ".txt", DocumentID: held.document.DocumentID}
+			if err := bundle.put(ctx, file, []byte(text), "text/plain; charset=utf-8"); err != nil {
+				return nil, err
+			}
+			texts++
+		}
+		advance(false)
+
+		for _, key := range held.storageKeys {
+			ok, err := s.exports.copyFile(ctx, bundle, hold.TenantID, tenantExportDocumentFile{documentID: held.document.DocumentID, key: key})
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				copied++
+			}
+			advance(!ok)
+		}
+	}
+	if err := bundle.put(ctx, models.TenantExportFile{Path: "documents.ndjson"}, []byte(records.String()), "application/x-ndjson"); err != nil {
+		return nil, err
+	}
+
+	auditEvents, err := s.writeAuditEvents(ctx, bundle, append(documentIDs, holdID))
+	if err != nil {
+		return nil, err
+	}
+
+	completedAt := time.Now().UTC()
+	manifest.CompletedAt = completedAt
+	manifest.Counts["documents"] = len(documents)
+	manifest.Counts["texts"] = texts
+	manifest.Counts["files"] = copied
+	manifest.Counts["audit_events"] = auditEvents
+	manifest.Files = bundle.manifest.Files
+	manifest.Missing = bundle.manifest.Missing
+	manifest.Custody = append(manifest.Custody,
+		models.LegalHoldCustodyEntry{
+			At:      completedAt,
+			ActorID: actorID,
+			Action:  "copied",
+			Detail:  fmt.Sprintf("%d stored files and %d texts copied to %s; %d stored files could not be read", copied, texts, bundle.prefix, len(bundle.manifest.Missing)),
+		},
+		models.LegalHoldCustodyEntry{
+			At:      completedAt,
+			ActorID: actorID,
+			Action:  "sealed",
+			Detail:  "Manifest hashed and its SHA-256 recorded in the audit log as legal_hold.export",
+		})
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, errors.Internal("Failed to encode legal hold export manifest")
+	}
+	manifestKey := bundle.prefix + "manifest.json"
+	if _, err := storage.UploadFile(ctx, manifestKey, data, "application/json"); err != nil {
+		return nil, errors.ExternalService("Failed to store legal hold export manifest", err)
+	}
+	url, err := storage.GetFileURL(ctx, manifestKey, tenantExportURLExpiry)
+	if err != nil {
+		return nil, errors.ExternalService("Failed to sign legal hold export URL", err)
+	}
+	sum := sha256.Sum256(data)
+	manifestSHA256 := hex.EncodeToString(sum[:])
+
+	s.logger.FromContext(ctx).Info("Exported legal hold",
+		zap.Bool("audit", true),
+		zap.String("hold_id", holdID),
+		zap.String("prefix", bundle.prefix),
+		zap.Int("documents", len(documents)),
+		zap.Int("files", copied),
+		zap.Int("missing_files", len(bundle.manifest.Missing)),
+		zap.Int64("bytes", bundle.bytes),
+		zap.String("actor_id", actorID))
+	recordAuditEvent(ctx, s.audit, s.logger, &models.AuditEvent{
+		Action:       "legal_hold.export",
+		ResourceType: "legal_hold",
+		ResourceID:   holdID,
+		TenantID:     hold.TenantID,
+		ActorID:      actorID,
+		Source:       ConfigSourceAdmin,
+		Details: map[string]interface{}{
+			"prefix":          bundle.prefix,
+			"manifest_sha256": manifestSHA256,
+			"documents":       len(documents),
+			"files":           copied,
+			"keywords":        criteria.Keywords,
+		},
+	})
+
+	return &models.LegalHoldExport{
+		HoldID:         holdID,
+		Prefix:         bundle.prefix,
+		ManifestKey:    manifestKey,
+		ManifestSHA256: manifestSHA256,
+		Documents:      len(documents),
+		Files:          copied,
+		MissingFiles:   len(bundle.manifest.Missing),
+		AuditEvents:    auditEvents,
+		Bytes:          bundle.bytes,
+		ManifestURL:    url,
+		ExpiresAt:      completedAt.Add(tenantExportURLExpiry),
+	}, nil
+}
+
+// documentText returns the stored extracted text of a document, decrypted
+func (s *LegalHoldService) documentText(ctx context.Context, tenantID, documentID string) (string, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "legal_hold.document_text"), `
+		MATCH (d:Document {id: $id, tenant_id: $tenant_id})
+		RETURN d.extracted_text AS text
+	`, map[string]interface{}{"id": documentID, "tenant_id": tenantID})
+	if err != nil {
+		return "", errors.Database("Failed to read held document text", err)
+	}
+	if len(result.Records) == 0 {
+		return "", nil
+	}
+	text, _ := s.encryption.Open(tenantID, recordString(result.Records[0], "text"))
+	return text, nil
+}
+
+// writeAuditEvents writes the audit events of the resources to the bundle,
+// oldest first
+func (s *LegalHoldService) writeAuditEvents(ctx context.Context, bundle *tenantExportBundle, resourceIDs []string) (int, error) {
+	if s.audit == nil {
+		return 0, nil
+	}
+	var buf strings.Builder
+	encoder := json.NewEncoder(&buf)
+	count, err := s.audit.Export(ctx, models.AuditFilter{ResourceIDs: resourceIDs}, func(event *models.AuditEvent) error {
+		return encoder.Encode(event)
+	})
+	if err != nil {
+		return count, err
+	}
+	return count, bundle.put(ctx, models.TenantExportFile{Path: "audit_events.ndjson"}, []byte(buf.String()), "application/x-ndjson")
+}
+
+// recordToLegalHold reads the legalHoldFields of a record
+func recordToLegalHold(record *neo4j.Record) *models.LegalHold {
+	hold := &models.LegalHold{
+		ID:            recordString(record, "id"),
+		TenantID:      recordString(record, "tenant_id"),
+		Name:          recordString(record, "name"),
+		Matter:        recordString(record, "matter"),
+		Description:   recordString(record, "description"),
+		Status:        models.LegalHoldStatus(recordString(record, "status")),
+		UserIDs:       recordStrings(record, "user_ids"),
+		NotebookIDs:   recordStrings(record, "notebook_ids"),
+		CreatedBy:     recordString(record, "created_by"),
+		CreatedAt:     recordTime(record, "created_at"),
+		ReleasedBy:    recordString(record, "released_by"),
+		ReleaseReason: recordString(record, "release_reason"),
+	}
+	if hold.UserIDs == nil {
+		hold.UserIDs = []string{}
+	}
+	if hold.NotebookIDs == nil {
+		hold.NotebookIDs = []string{}
+	}
+	if t := recordTime(record, "released_at"); !t.IsZero() {
+		hold.ReleasedAt = &t
+	}
+	return hold
+}
+
+// distinctIDs returns the non-blank values of ids, trimmed, without
+// duplicates and in order
+func distinctIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	distinct := make([]string, 0, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		distinct = append(distinct, id)
+	}
+	return distinct
+}
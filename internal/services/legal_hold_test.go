@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+func TestLegalHoldMatcher(t *testing.T) {
+	m, err := newLegalHoldMatcher(models.LegalHoldCriteria{Keywords: []string{"merger", " Project Falcon ", "merger", ""}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"merger", "Project Falcon"}, m.keywords, "keywords are trimmed and deduplicated")
+
+	matched, ok := m.match("Board minutes", "Notes on the MERGER and on project falcon")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"merger", "Project Falcon"}, matched)
+
+	// Keywords match whole words only
+	_, ok = m.match("Mergers and acquisitions")
+	assert.False(t, ok)
+
+	// Without keywords everything matches
+	all, err := newLegalHoldMatcher(models.LegalHoldCriteria{})
+	require.NoError(t, err)
+	matched, ok = all.match("anything")
+	assert.True(t, ok)
+	assert.Empty(t, matched)
+}
+
+func TestLegalHoldMatcherRejectsEmptyRange(t *testing.T) {
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(-time.Hour)
+	_, err := newLegalHoldMatcher(models.LegalHoldCriteria{From: &from, To: &to})
+	assert.Error(t, err)
+
+	_, err = newLegalHoldMatcher(models.LegalHoldCriteria{From: &from, To: &from})
+	assert.Error(t, err, "the range excludes to, so from must be before it")
+
+	_, err = newLegalHoldMatcher(models.LegalHoldCriteria{From: &from})
+	assert.NoError(t, err)
+}
+
+func TestLegalHoldChecksWithoutService(t *testing.T) {
+	var holds *LegalHoldService
+	ctx := context.Background()
+	assert.NoError(t, holds.CheckDocument(ctx, &models.Document{ID: "doc-1", TenantID: "tenant-1"}))
+	assert.NoError(t, holds.CheckNotebook(ctx, "notebook-1", "tenant-1"))
+	assert.NoError(t, holds.CheckOrganization(ctx, "org-1"))
+}
+
+func TestDistinctIDs(t *testing.T) {
+	assert.Equal(t, []string{"a", "b"}, distinctIDs([]string{" a", "b", "a ", "  "}))
+	assert.Empty(t, distinctIDs(nil))
+}
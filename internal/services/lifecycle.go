@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"sync"
+)
+
+// WorkerGroup tracks background goroutines (scheduled retries, processors)
+// so shutdown can wait for in-flight work instead of abandoning it
+type WorkerGroup struct {
+	mu     sync.Mutex
+	wg     sync.WaitGroup
+	closed bool
+}
+
+// NewWorkerGroup creates an empty worker group
+func NewWorkerGroup() *WorkerGroup {
+	return &WorkerGroup{}
+}
+
+// Go runs fn in a tracked goroutine. It returns false without running fn
+// once the group has been closed for shutdown.
+func (g *WorkerGroup) Go(fn func()) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.closed {
+		return false
+	}
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		fn()
+	}()
+	return true
+}
+
+// Close stops the group from accepting new work
+func (g *WorkerGroup) Close() {
+	g.mu.Lock()
+	g.closed = true
+	g.mu.Unlock()
+}
+
+// Wait closes the group and blocks until all workers finish or ctx is done
+func (g *WorkerGroup) Wait(ctx context.Context) error {
+	g.Close()
+	return waitGroupContext(ctx, &g.wg)
+}
+
+// waitGroupContext waits for wg, giving up when ctx is done
+func waitGroupContext(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
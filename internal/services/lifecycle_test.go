@@ -0,0 +1,40 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerGroupWaitsForWorkers(t *testing.T) {
+	group := NewWorkerGroup()
+	finished := make(chan struct{})
+
+	assert.True(t, group.Go(func() {
+		time.Sleep(10 * time.Millisecond)
+		close(finished)
+	}))
+
+	assert.NoError(t, group.Wait(context.Background()))
+	select {
+	case <-finished:
+	default:
+		t.Fatal("Wait returned before the worker finished")
+	}
+
+	assert.False(t, group.Go(func() {}), "closed group must reject new work")
+}
+
+func TestWorkerGroupWaitDeadline(t *testing.T) {
+	group := NewWorkerGroup()
+	release := make(chan struct{})
+	defer close(release)
+
+	group.Go(func() { <-release })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, group.Wait(ctx), context.DeadlineExceeded)
+}
@@ -0,0 +1,262 @@
+package services
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// maintenanceWindowFields are the columns recordToMaintenanceWindow reads
+const maintenanceWindowFields = `m.scope AS scope, m.tenant_id AS tenant_id, m.message AS message,
+	       m.started_at AS started_at, m.expected_end_at AS expected_end_at, m.started_by AS started_by`
+
+// maintenanceWindows are the windows in progress as last read from Neo4j
+type maintenanceWindows struct {
+	system  *models.MaintenanceWindow
+	tenants map[string]*models.MaintenanceWindow
+}
+
+// MaintenanceService keeps the maintenance windows operators start through
+// the admin API, during which writes are rejected system-wide or for one
+// tenant. Windows are stored as MaintenanceWindow nodes so every replica
+// applies them; each replica keeps them in memory, re-read on an interval,
+// so checking a request costs no query.
+type MaintenanceService struct {
+	neo4j    *database.Neo4jClient
+	audit    *AuditService
+	interval time.Duration
+	windows  atomic.Pointer[maintenanceWindows]
+	logger   *logger.Logger
+}
+
+// NewMaintenanceService creates a maintenance service that re-reads the
+// windows every interval once Run is started. audit may be nil, in which
+// case windows started and ended are only logged.
+func NewMaintenanceService(neo4j *database.Neo4jClient, audit *AuditService, interval time.Duration, log *logger.Logger) *MaintenanceService {
+	s := &MaintenanceService{
+		neo4j:    neo4j,
+		audit:    audit,
+		interval: interval,
+		logger:   log.WithService("maintenance_service"),
+	}
+	s.windows.Store(&maintenanceWindows{})
+	return s
+}
+
+// Run reads the windows now and then every interval until ctx is cancelled
+func (s *MaintenanceService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Refresh(ctx); err != nil && ctx.Err() == nil {
+			// The windows last read stay in effect
+			s.logger.Warn("Failed to read maintenance windows", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh re-reads the windows in progress from Neo4j
+func (s *MaintenanceService) Refresh(ctx context.Context) error {
+	windows, err := s.read(ctx)
+	if err != nil {
+		return err
+	}
+
+	current := &maintenanceWindows{tenants: make(map[string]*models.MaintenanceWindow)}
+	for _, window := range windows {
+		if window.Scope == models.MaintenanceScopeSystem {
+			current.system = window
+		} else {
+			current.tenants[window.TenantID] = window
+		}
+	}
+	s.windows.Store(current)
+	return nil
+}
+
+// Window returns the maintenance window that applies to a tenant, nil when
+// there is none. The system-wide window applies to every tenant and takes
+// precedence; an empty tenant ID only matches the system-wide window.
+func (s *MaintenanceService) Window(tenantID string) *models.MaintenanceWindow {
+	current := s.windows.Load()
+	if current.system != nil || tenantID == "" {
+		return current.system
+	}
+	return current.tenants[tenantID]
+}
+
+// Status returns the windows that apply to a tenant, for the maintenance
+// banner of its users
+func (s *MaintenanceService) Status(tenantID string) *models.MaintenanceStatus {
+	current := s.windows.Load()
+	status := &models.MaintenanceStatus{Windows: make([]*models.MaintenanceWindow, 0, 2)}
+	if current.system != nil {
+		status.Windows = append(status.Windows, current.system)
+	}
+	if window := current.tenants[tenantID]; tenantID != "" && window != nil {
+		status.Windows = append(status.Windows, window)
+	}
+	status.Active = len(status.Windows) > 0
+	return status
+}
+
+// List returns every window in progress, read from Neo4j rather than from
+// this replica's copy
+func (s *MaintenanceService) List(ctx context.Context) (*models.MaintenanceStatus, error) {
+	windows, err := s.read(ctx)
+	if err != nil {
+		return nil, errors.Database("Failed to list maintenance windows", err)
+	}
+	return &models.MaintenanceStatus{Active: len(windows) > 0, Windows: windows}, nil
+}
+
+// Start starts maintenance system-wide, or for one tenant when tenantID is
+// set. Starting a window that is in progress changes its banner and keeps
+// its start.
+func (s *MaintenanceService) Start(ctx context.Context, tenantID string, req models.MaintenanceRequest, actorID string) (*models.MaintenanceWindow, error) {
+	scope := maintenanceScope(tenantID)
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "maintenance.start"), `
+		MERGE (m:MaintenanceWindow {key: $key})
+		ON CREATE SET m.scope = $scope, m.tenant_id = $tenant_id,
+		              m.started_at = $now, m.started_by = $actor_id
+		SET m.message = $message, m.expected_end_at = $expected_end_at, m.updated_at = $now
+		RETURN `+maintenanceWindowFields, map[string]interface{}{
+		"key":             maintenanceKey(tenantID),
+		"scope":           scope,
+		"tenant_id":       tenantID,
+		"actor_id":        actorID,
+		"message":         req.Message,
+		"expected_end_at": nullableTime(req.ExpectedEndAt),
+		"now":             time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to start maintenance", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, errors.Internal("Maintenance window was not stored")
+	}
+	window := recordToMaintenanceWindow(result.Records[0])
+
+	// This replica applies the window at once; the others on their next read
+	if err := s.Refresh(ctx); err != nil {
+		s.logger.Warn("Failed to read maintenance windows", zap.Error(err))
+	}
+	s.recordAudit(ctx, "maintenance.start", window, actorID)
+	return window, nil
+}
+
+// End ends the system-wide maintenance, or that of one tenant when
+// tenantID is set
+func (s *MaintenanceService) End(ctx context.Context, tenantID, actorID string) error {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "maintenance.end"), `
+		MATCH (m:MaintenanceWindow {key: $key})
+		WITH m, `+maintenanceWindowFields+`
+		DELETE m
+		RETURN scope, tenant_id, message, started_at, expected_end_at, started_by
+	`, map[string]interface{}{"key": maintenanceKey(tenantID)})
+	if err != nil {
+		return errors.Database("Failed to end maintenance", err)
+	}
+	if len(result.Records) == 0 {
+		return errors.NotFoundWithDetails("No maintenance is in progress", map[string]interface{}{
+			"scope":     maintenanceScope(tenantID),
+			"tenant_id": tenantID,
+		})
+	}
+
+	if err := s.Refresh(ctx); err != nil {
+		s.logger.Warn("Failed to read maintenance windows", zap.Error(err))
+	}
+	s.recordAudit(ctx, "maintenance.end", recordToMaintenanceWindow(result.Records[0]), actorID)
+	return nil
+}
+
+// read returns the windows stored in Neo4j, the system-wide one first
+func (s *MaintenanceService) read(ctx context.Context) ([]*models.MaintenanceWindow, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "maintenance.list"), `
+		MATCH (m:MaintenanceWindow)
+		RETURN `+maintenanceWindowFields+`
+		ORDER BY m.scope = $system DESC, m.started_at
+	`, map[string]interface{}{"system": models.MaintenanceScopeSystem})
+	if err != nil {
+		return nil, err
+	}
+
+	windows := make([]*models.MaintenanceWindow, 0, len(result.Records))
+	for _, record := range result.Records {
+		windows = append(windows, recordToMaintenanceWindow(record))
+	}
+	return windows, nil
+}
+
+// recordAudit logs a window started or ended and records it in the audit
+// log
+func (s *MaintenanceService) recordAudit(ctx context.Context, action string, window *models.MaintenanceWindow, actorID string) {
+	s.logger.FromContext(ctx).Info("Maintenance changed",
+		zap.Bool("audit", true),
+		zap.String("action", action),
+		zap.String("scope", window.Scope),
+		zap.String("tenant_id", window.TenantID),
+		zap.String("actor_id", actorID),
+	)
+
+	recordAuditEvent(ctx, s.audit, s.logger, &models.AuditEvent{
+		Action:       action,
+		ResourceType: "maintenance",
+		ResourceID:   maintenanceKey(window.TenantID),
+		TenantID:     window.TenantID,
+		ActorID:      actorID,
+		Source:       ConfigSourceAdmin,
+		Details: map[string]interface{}{
+			"scope":   window.Scope,
+			"message": window.Message,
+		},
+	})
+}
+
+// maintenanceScope returns the scope of the window of a tenant, or of the
+// system-wide window when tenantID is empty
+func maintenanceScope(tenantID string) string {
+	if tenantID == "" {
+		return models.MaintenanceScopeSystem
+	}
+	return models.MaintenanceScopeTenant
+}
+
+// maintenanceKey identifies the window of a scope: there is at most one
+// system-wide window and one per tenant
+func maintenanceKey(tenantID string) string {
+	if tenantID == "" {
+		return models.MaintenanceScopeSystem
+	}
+	return models.MaintenanceScopeTenant + ":" + tenantID
+}
+
+// recordToMaintenanceWindow reads the maintenanceWindowFields of a record
+func recordToMaintenanceWindow(record *neo4j.Record) *models.MaintenanceWindow {
+	window := &models.MaintenanceWindow{
+		Scope:     recordString(record, "scope"),
+		TenantID:  recordString(record, "tenant_id"),
+		Message:   recordString(record, "message"),
+		StartedAt: recordTime(record, "started_at"),
+		StartedBy: recordString(record, "started_by"),
+	}
+	if at := recordTime(record, "expected_end_at"); !at.IsZero() {
+		window.ExpectedEndAt = &at
+	}
+	return window
+}
@@ -117,7 +117,7 @@ func (m *MockStorageService) GetFileURL(ctx context.Context, key string, expirat
 }
 
 // setupTestLogger creates a test logger with minimal output
-func setupTestLogger(t *testing.T) *logger.Logger {
+func setupTestLogger(t testing.TB) *logger.Logger {
 	loggerConfig := logger.Config{
 		Level:  "error", // Reduce log noise in tests
 		Format: "json",
@@ -0,0 +1,508 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// Moderation limits
+const (
+	// moderationMaxAttempts bounds the scans of a document a provider
+	// fails on; the document then stays held until a reviewer decides on it
+	moderationMaxAttempts = 3
+	// moderationTimeout bounds the scan of a notebook's text while it is
+	// being saved
+	moderationTimeout = 20 * time.Second
+)
+
+// ModerationProvider decides whether text may be shown in a public
+// notebook
+type ModerationProvider interface {
+	// Name identifies the provider on moderated documents
+	Name() string
+	// Moderate returns the provider's verdict on text
+	Moderate(ctx context.Context, text string) (*models.ModerationVerdict, error)
+}
+
+// keywordModerationProvider blocks or flags text containing configured
+// words and phrases, matched case-insensitively as whole words
+type keywordModerationProvider struct {
+	blocked *regexp.Regexp
+	flagged *regexp.Regexp
+}
+
+// newKeywordModerationProvider creates a keyword provider. Either list may
+// be empty.
+func newKeywordModerationProvider(blocked, flagged []string) *keywordModerationProvider {
+	return &keywordModerationProvider{
+		blocked: termPattern(blocked),
+		flagged: termPattern(flagged),
+	}
+}
+
+// termPattern compiles a case-insensitive whole-word pattern matching any
+// of terms, or returns nil when there are none
+func termPattern(terms []string) *regexp.Regexp {
+	quoted := make([]string, 0, len(terms))
+	for _, term := range terms {
+		if term = strings.TrimSpace(term); term != "" {
+			quoted = append(quoted, regexp.QuoteMeta(term))
+		}
+	}
+	if len(quoted) == 0 {
+		return nil
+	}
+	return regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`)
+}
+
+func (p *keywordModerationProvider) Name() string { return "keywords" }
+
+func (p *keywordModerationProvider) Moderate(_ context.Context, text string) (*models.ModerationVerdict, error) {
+	if p.blocked != nil {
+		if match := p.blocked.FindString(text); match != "" {
+			return &models.ModerationVerdict{
+				Action:     models.ModerationBlock,
+				Categories: []string{"blocked_term"},
+				Reason:     fmt.Sprintf("Contains the blocked term %q", strings.ToLower(match)),
+			}, nil
+		}
+	}
+	if p.flagged != nil {
+		if match := p.flagged.FindString(text); match != "" {
+			return &models.ModerationVerdict{
+				Action:     models.ModerationFlag,
+				Categories: []string{"flagged_term"},
+				Reason:     fmt.Sprintf("Contains the flagged term %q", strings.ToLower(match)),
+			}, nil
+		}
+	}
+	return &models.ModerationVerdict{Action: models.ModerationAllow}, nil
+}
+
+// moderationPrompt asks the model for a verdict as JSON
+const moderationPrompt = `You moderate content before it is published in a notebook anyone can read.
+Decide whether the text may be published. Block sexual content involving minors, credible threats, instructions for serious harm, hate speech and doxxing of private individuals. Flag for human review other sexual content, graphic violence, harassment and anything you are unsure of. Allow everything else, including frank discussion of difficult topics.
+Answer only with a JSON object: {"action": "allow" | "flag" | "block", "categories": [short snake_case labels], "reason": "one sentence"}.`
+
+// routerModerationProvider asks a model through the LLM router, with the
+// service's own key
+type routerModerationProvider struct {
+	chat     *routerChat
+	model    string
+	provider string
+}
+
+func (p *routerModerationProvider) Name() string { return "router" }
+
+func (p *routerModerationProvider) Moderate(ctx context.Context, text string) (*models.ModerationVerdict, error) {
+	if !p.chat.serviceAuth() {
+		return nil, fmt.Errorf("router service auth is not configured")
+	}
+	answer, err := p.chat.complete(ctx, routerChatRequest{
+		Model:     p.model,
+		Provider:  p.provider,
+		MaxTokens: 200,
+		Messages: []map[string]string{
+			{"role": "system", "content": moderationPrompt},
+			{"role": "user", "content": text},
+		},
+	}, "")
+	if err != nil {
+		return nil, err
+	}
+	return parseModerationVerdict(answer.Content)
+}
+
+// parseModerationVerdict reads the verdict a model answered with, ignoring
+// any prose or code fence around the JSON object
+func parseModerationVerdict(answer string) (*models.ModerationVerdict, error) {
+	start, end := strings.Index(answer, "{"), strings.LastIndex(answer, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("moderation answer has no JSON object: %q", truncateRunes(answer, 200))
+	}
+	var verdict models.ModerationVerdict
+	if err := json.Unmarshal([]byte(answer[start:end+1]), &verdict); err != nil {
+		return nil, fmt.Errorf("failed to decode moderation answer: %w", err)
+	}
+	verdict.Action = strings.ToLower(strings.TrimSpace(verdict.Action))
+	switch verdict.Action {
+	case models.ModerationAllow, models.ModerationFlag, models.ModerationBlock:
+		return &verdict, nil
+	default:
+		return nil, fmt.Errorf("moderation answer has unknown action %q", verdict.Action)
+	}
+}
+
+// moderationQueueSet queues the documents bound as d for a scan when public
+// is true, holding them if hold is set or they were never scanned, and
+// clears the moderation of the others, which are no longer in a public
+// notebook
+const moderationQueueSet = `
+	SET d.moderation_status = CASE
+	        WHEN NOT public THEN null
+	        WHEN $hold OR d.moderation_status IS NULL THEN 'pending'
+	        ELSE d.moderation_status END,
+	    d.moderation_pending = CASE WHEN public THEN true END,
+	    d.moderation_attempts = CASE WHEN public THEN 0 END
+`
+
+// ModerationService moderates the documents of public notebooks before the
+// space can read them. A document uploaded to, processed in or moved into
+// a public notebook is held, visible only to its owner and the space's
+// owners and admins, until the configured provider has scanned its name,
+// description and text. The provider's verdict approves it, flags it for
+// review while leaving it readable, or blocks it; reviewers can override
+// any verdict. The names and descriptions of public notebooks are scanned
+// as they are saved.
+type ModerationService struct {
+	neo4j     *database.Neo4jClient
+	documents *DocumentService
+	provider  ModerationProvider
+	config    config.ModerationConfig
+	logger    *logger.Logger
+}
+
+// NewModerationService creates a moderation service with the configured
+// provider
+func NewModerationService(neo4j *database.Neo4jClient, documents *DocumentService, router *config.RouterConfig, cfg config.ModerationConfig, log *logger.Logger) (*ModerationService, error) {
+	var provider ModerationProvider
+	switch cfg.Provider {
+	case "keywords":
+		provider = newKeywordModerationProvider(cfg.BlockedTerms, cfg.FlaggedTerms)
+	case "router":
+		provider = &routerModerationProvider{chat: newRouterChat(router), model: cfg.Model, provider: cfg.RouterProvider}
+	default:
+		return nil, fmt.Errorf("unknown moderation provider %q", cfg.Provider)
+	}
+	return &ModerationService{
+		neo4j:     neo4j,
+		documents: documents,
+		provider:  provider,
+		config:    cfg,
+		logger:    log.WithService("moderation_service"),
+	}, nil
+}
+
+// HandleDomainEvent queues the documents of public notebooks for a scan.
+// Uploaded and processed documents are held until it; updated ones keep
+// their status unless they were never scanned. Documents leave moderation
+// when they or their notebook stop being public. It is subscribed to the
+// domain event bus.
+func (s *ModerationService) HandleDomainEvent(ctx context.Context, event Event) error {
+	if !s.config.Enabled {
+		return nil
+	}
+
+	var query string
+	hold := false
+	switch event.Type {
+	case EventDocumentUploaded, EventDocumentProcessed:
+		hold = true
+		fallthrough
+	case EventDocumentUpdated:
+		query = `
+			MATCH (d:Document {id: $id})
+			OPTIONAL MATCH (d)-[:BELONGS_TO]->(n:Notebook)
+			WITH d, coalesce(n.visibility = 'public', false) AS public
+			WHERE public OR d.moderation_status IS NOT NULL
+		` + moderationQueueSet
+	case EventNotebookCreated, EventNotebookUpdated:
+		// Documents already scanned in the notebook are not scanned again
+		query = `
+			MATCH (n:Notebook {id: $id})<-[:BELONGS_TO]-(d:Document)
+			WITH d, coalesce(n.visibility = 'public', false) AS public
+			WHERE public = (d.moderation_status IS NULL)
+		` + moderationQueueSet
+	default:
+		return nil
+	}
+
+	_, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "moderation.queue"), query, map[string]interface{}{
+		"id":   event.Subject,
+		"hold": hold,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to queue moderation: %w", err)
+	}
+	return nil
+}
+
+// CheckPublicText scans the name and description of a notebook that is
+// public, refusing them when the provider blocks them. It does nothing
+// when moderation is disabled or the receiver is nil.
+func (s *ModerationService) CheckPublicText(ctx context.Context, texts ...string) error {
+	if s == nil || !s.config.Enabled {
+		return nil
+	}
+	text := strings.TrimSpace(strings.Join(texts, "\n\n"))
+	if text == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, moderationTimeout)
+	defer cancel()
+	verdict, err := s.provider.Moderate(ctx, text)
+	if err != nil {
+		s.logger.Error("Failed to moderate notebook text", zap.String("provider", s.provider.Name()), zap.Error(err))
+		return errors.ExternalService("Failed to moderate the text of the public notebook", err)
+	}
+	if verdict.Action == models.ModerationBlock {
+		return errors.NewAPIError(errors.ErrUnprocessableEntity, "The text is not allowed in a public notebook", map[string]interface{}{
+			"categories": verdict.Categories,
+			"reason":     verdict.Reason,
+		}).WithErrorCode(errors.CodeContentNotAllowed)
+	}
+	if verdict.Action == models.ModerationFlag {
+		s.logger.Warn("Public notebook text flagged by moderation",
+			zap.Strings("categories", verdict.Categories),
+			zap.String("reason", verdict.Reason))
+	}
+	return nil
+}
+
+// ModeratePending scans the queued documents that have finished
+// processing. It is a singleton job run by the leader replica; a document
+// the provider fails on stays queued for a few attempts, and held after
+// them until a reviewer decides on it.
+func (s *ModerationService) ModeratePending(ctx context.Context) error {
+	if !s.config.Enabled {
+		return nil
+	}
+
+	ctx = database.WithQueryName(ctx, "moderation.pending")
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (d:Document)
+		WHERE d.moderation_pending = true AND d.status IN ['processed', 'failed']
+		      AND `+database.NotDeleted("d")+`
+		RETURN d.id AS id, d.tenant_id AS tenant_id, d.name AS name,
+		       coalesce(d.description, '') AS description,
+		       `+textExcerptColumn("d", "input_chars")+` AS text,
+		       coalesce(d.moderation_attempts, 0) AS attempts
+		ORDER BY d.updated_at
+		LIMIT $limit
+	`, map[string]interface{}{
+		"input_chars": s.config.InputChars,
+		"limit":       s.config.BatchSize,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list documents to moderate: %w", err)
+	}
+
+	failed := 0
+	for _, record := range result.Records {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		documentID := recordString(record, "id")
+		text := s.documents.encryption.Excerpt(recordString(record, "tenant_id"), recordString(record, "text"), s.config.InputChars)
+		content := strings.TrimSpace(strings.Join([]string{recordString(record, "name"), recordString(record, "description"), text}, "\n\n"))
+
+		verdict, err := s.provider.Moderate(ctx, content)
+		if err == nil {
+			err = s.applyVerdict(ctx, documentID, verdict)
+		}
+		if err != nil {
+			failed++
+			s.logger.Warn("Failed to moderate document", zap.String("document_id", documentID), zap.Error(err))
+			s.countAttempt(ctx, documentID, recordInt64(record, "attempts")+1 < moderationMaxAttempts)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d documents failed to moderate", failed, len(result.Records))
+	}
+	return nil
+}
+
+// applyVerdict records a provider's verdict on a queued document
+func (s *ModerationService) applyVerdict(ctx context.Context, documentID string, verdict *models.ModerationVerdict) error {
+	status := verdict.Status()
+	_, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "moderation.verdict"), `
+		MATCH (d:Document {id: $id})
+		WHERE d.moderation_pending = true
+		SET d.moderation_status = $status,
+		    d.moderation_provider = $provider,
+		    d.moderation_categories = $categories,
+		    d.moderation_reason = $reason,
+		    d.moderated_at = datetime()
+		REMOVE d.moderation_pending, d.moderation_attempts,
+		       d.moderation_reviewed_by, d.moderation_reviewed_at, d.moderation_review_note
+	`, map[string]interface{}{
+		"id":         documentID,
+		"status":     string(status),
+		"provider":   s.provider.Name(),
+		"categories": verdict.Categories,
+		"reason":     verdict.Reason,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record moderation verdict: %w", err)
+	}
+	if status != models.ModerationApproved {
+		s.logger.Info("Document moderated",
+			zap.String("document_id", documentID),
+			zap.String("status", string(status)),
+			zap.Strings("categories", verdict.Categories))
+	}
+	return nil
+}
+
+// countAttempt counts a failed scan of a document, taking it off the queue
+// unless retry is set. It stays held either way.
+func (s *ModerationService) countAttempt(ctx context.Context, documentID string, retry bool) {
+	query := `
+		MATCH (d:Document {id: $id})
+		REMOVE d.moderation_pending, d.moderation_attempts
+	`
+	if retry {
+		query = `
+			MATCH (d:Document {id: $id})
+			SET d.moderation_attempts = coalesce(d.moderation_attempts, 0) + 1
+		`
+	}
+	if _, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "moderation.attempt"), query, map[string]interface{}{"id": documentID}); err != nil {
+		s.logger.Warn("Failed to count moderation attempt", zap.String("document_id", documentID), zap.Error(err))
+	}
+}
+
+// moderatedDocumentColumns returns the columns recordToModeratedDocument
+// reads
+const moderatedDocumentColumns = `
+	d.id AS document_id, d.name AS name, d.notebook_id AS notebook_id,
+	d.owner_id AS owner_id, d.moderation_status AS status,
+	d.moderation_provider AS provider, d.moderation_categories AS categories,
+	d.moderation_reason AS reason, d.moderated_at AS moderated_at,
+	d.moderation_reviewed_by AS reviewed_by, d.moderation_review_note AS review_note,
+	d.moderation_reviewed_at AS reviewed_at
+`
+
+// ListModeratedDocuments lists the moderated documents of the space with a
+// status, or those held or flagged when status is empty, most recently
+// moderated first. Only the space's owners and admins can list them.
+func (s *ModerationService) ListModeratedDocuments(ctx context.Context, status models.ModerationStatus, limit, offset int, spaceCtx *models.SpaceContext) (*models.ModeratedDocumentListResponse, error) {
+	if !spaceCtx.CanManage() {
+		return nil, errors.Forbidden("Only space owners and admins can review moderated documents")
+	}
+	statuses := []string{string(models.ModerationPending), string(models.ModerationFlagged), string(models.ModerationBlocked)}
+	switch status {
+	case "":
+	case models.ModerationPending, models.ModerationApproved, models.ModerationFlagged, models.ModerationBlocked:
+		statuses = []string{string(status)}
+	default:
+		return nil, errors.ValidationWithDetails("Unknown moderation status", map[string]interface{}{
+			"status":  status,
+			"allowed": []string{"pending", "approved", "flagged", "blocked"},
+		})
+	}
+	limit, offset = pagination.Clamp(limit, offset)
+
+	ctx = database.WithQueryName(ctx, "moderation.list")
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (d:Document {space_id: $space_id, tenant_id: $tenant_id})
+		WHERE d.moderation_status IN $statuses AND `+database.SoftDeleteFilter(ctx, "d")+`
+		RETURN `+moderatedDocumentColumns+`
+		ORDER BY coalesce(d.moderated_at, d.updated_at) DESC, d.id
+		SKIP $offset
+		LIMIT $limit
+	`, map[string]interface{}{
+		"space_id":  spaceCtx.SpaceID,
+		"tenant_id": spaceCtx.TenantID,
+		"statuses":  statuses,
+		"offset":    offset,
+		"limit":     limit + 1,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to list moderated documents", err)
+	}
+
+	records, hasMore := pagination.Trim(result.Records, limit)
+	documents := make([]*models.ModeratedDocument, 0, len(records))
+	for _, record := range records {
+		documents = append(documents, recordToModeratedDocument(record))
+	}
+	return &models.ModeratedDocumentListResponse{
+		Documents: documents,
+		Limit:     limit,
+		Offset:    offset,
+		HasMore:   hasMore,
+	}, nil
+}
+
+// ReviewDocument records a reviewer's decision on a moderated document,
+// approving it for the space or blocking it. The decision stands until the
+// document is processed again. Only the space's owners and admins can
+// review documents.
+func (s *ModerationService) ReviewDocument(ctx context.Context, documentID string, req models.ModerationReviewRequest, userID string, spaceCtx *models.SpaceContext) (*models.ModeratedDocument, error) {
+	if !spaceCtx.CanManage() {
+		return nil, errors.Forbidden("Only space owners and admins can review moderated documents")
+	}
+
+	ctx = database.WithQueryName(ctx, "moderation.review")
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (d:Document {id: $id, space_id: $space_id, tenant_id: $tenant_id})
+		WHERE d.moderation_status IS NOT NULL AND `+database.SoftDeleteFilter(ctx, "d")+`
+		SET d.moderation_status = $decision,
+		    d.moderation_reviewed_by = $user_id,
+		    d.moderation_review_note = $note,
+		    d.moderation_reviewed_at = datetime()
+		REMOVE d.moderation_pending, d.moderation_attempts
+		RETURN `+moderatedDocumentColumns+`
+	`, map[string]interface{}{
+		"id":        documentID,
+		"space_id":  spaceCtx.SpaceID,
+		"tenant_id": spaceCtx.TenantID,
+		"decision":  string(req.Decision),
+		"user_id":   userID,
+		"note":      req.Note,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to review document", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, errors.NotFoundWithDetails("Document not found or not moderated", map[string]interface{}{
+			"document_id": documentID,
+		}).WithErrorCode(errors.CodeDocumentNotFound)
+	}
+
+	s.logger.Info("Moderated document reviewed",
+		zap.String("document_id", documentID),
+		zap.String("decision", string(req.Decision)),
+		zap.String("user_id", userID))
+	return recordToModeratedDocument(result.Records[0]), nil
+}
+
+// recordToModeratedDocument reads the columns of moderatedDocumentColumns
+func recordToModeratedDocument(record *neo4j.Record) *models.ModeratedDocument {
+	document := &models.ModeratedDocument{
+		DocumentID: recordString(record, "document_id"),
+		Name:       recordString(record, "name"),
+		NotebookID: recordString(record, "notebook_id"),
+		OwnerID:    recordString(record, "owner_id"),
+		Status:     models.ModerationStatus(recordString(record, "status")),
+		Provider:   recordString(record, "provider"),
+		Categories: recordStrings(record, "categories"),
+		Reason:     recordString(record, "reason"),
+		ReviewedBy: recordString(record, "reviewed_by"),
+		ReviewNote: recordString(record, "review_note"),
+	}
+	if t := recordTime(record, "moderated_at"); !t.IsZero() {
+		document.ModeratedAt = &t
+	}
+	if t := recordTime(record, "reviewed_at"); !t.IsZero() {
+		document.ReviewedAt = &t
+	}
+	return document
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+func TestKeywordModerationProvider(t *testing.T) {
+	p := newKeywordModerationProvider([]string{"forbidden phrase", " "}, []string{"gamble"})
+	ctx := context.Background()
+
+	verdict, err := p.Moderate(ctx, "Notes on a FORBIDDEN   phrase")
+	require.NoError(t, err)
+	assert.Equal(t, models.ModerationAllow, verdict.Action, "phrases match with their own spacing")
+
+	verdict, err = p.Moderate(ctx, "A Forbidden Phrase in the title")
+	require.NoError(t, err)
+	assert.Equal(t, models.ModerationBlock, verdict.Action)
+	assert.Equal(t, []string{"blocked_term"}, verdict.Categories)
+	assert.Equal(t, models.ModerationBlocked, verdict.Status())
+
+	verdict, err = p.Moderate(ctx, "Odds for those who gamble, and the gambler")
+	require.NoError(t, err)
+	assert.Equal(t, models.ModerationFlag, verdict.Action)
+	assert.Equal(t, models.ModerationFlagged, verdict.Status())
+
+	// Terms match whole words only
+	verdict, err = p.Moderate(ctx, "The gambler's ruin")
+	require.NoError(t, err)
+	assert.Equal(t, models.ModerationAllow, verdict.Action)
+	assert.Equal(t, models.ModerationApproved, verdict.Status())
+
+	empty := newKeywordModerationProvider(nil, nil)
+	verdict, err = empty.Moderate(ctx, "anything at all")
+	require.NoError(t, err)
+	assert.Equal(t, models.ModerationAllow, verdict.Action)
+}
+
+func TestParseModerationVerdict(t *testing.T) {
+	verdict, err := parseModerationVerdict("```json\n{\"action\": \"Block\", \"categories\": [\"threat\"], \"reason\": \"A credible threat\"}\n```")
+	require.NoError(t, err)
+	assert.Equal(t, models.ModerationBlock, verdict.Action)
+	assert.Equal(t, []string{"threat"}, verdict.Categories)
+
+	_, err = parseModerationVerdict("I cannot help with that")
+	assert.Error(t, err)
+	_, err = parseModerationVerdict(`{"action": "maybe"}`)
+	assert.Error(t, err)
+}
+
+func TestModerationStatusHeld(t *testing.T) {
+	assert.True(t, models.ModerationPending.Held())
+	assert.True(t, models.ModerationBlocked.Held())
+	assert.False(t, models.ModerationFlagged.Held())
+	assert.False(t, models.ModerationApproved.Held())
+	assert.False(t, models.ModerationStatus("").Held())
+}
+
+func TestCheckPublicText(t *testing.T) {
+	cfg := config.ModerationConfig{Enabled: true, Provider: "keywords", BlockedTerms: []string{"contraband"}}
+	s, err := NewModerationService(nil, nil, nil, cfg, setupTestLogger(t))
+	require.NoError(t, err)
+
+	assert.NoError(t, s.CheckPublicText(context.Background(), "Field notes", "Birds of the estuary"))
+
+	err = s.CheckPublicText(context.Background(), "Field notes", "Where to buy contraband")
+	apiErr, ok := errors.AsAPIError(err)
+	require.True(t, ok)
+	assert.Equal(t, errors.CodeContentNotAllowed, apiErr.ErrorCode)
+
+	// Disabled, or not configured, nothing is scanned
+	cfg.Enabled = false
+	disabled, err := NewModerationService(nil, nil, nil, cfg, setupTestLogger(t))
+	require.NoError(t, err)
+	assert.NoError(t, disabled.CheckPublicText(context.Background(), "contraband"))
+	var missing *ModerationService
+	assert.NoError(t, missing.CheckPublicText(context.Background(), "contraband"))
+
+	_, err = NewModerationService(nil, nil, nil, config.ModerationConfig{Provider: "oracle"}, setupTestLogger(t))
+	assert.Error(t, err)
+}
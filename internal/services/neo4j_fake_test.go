@@ -0,0 +1,413 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+)
+
+// fakeNeo4jHandler answers a query with the keys and rows of its result
+type fakeNeo4jHandler func(query string, params map[string]interface{}) ([]string, [][]interface{})
+
+// newFakeNeo4j returns a client connected to a Bolt 4.4 server that answers
+// every query with handler, so that services can be tested against their
+// queries' results without a database. Queries run one at a time.
+func newFakeNeo4j(t *testing.T, handler fakeNeo4jHandler) *database.Neo4jClient {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var conns sync.WaitGroup
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conns.Add(1)
+			go func() {
+				defer conns.Done()
+				defer conn.Close()
+				serveFakeBolt(conn, func(query string, params map[string]interface{}) ([]string, [][]interface{}) {
+					mu.Lock()
+					defer mu.Unlock()
+					return handler(query, params)
+				})
+			}()
+		}
+	}()
+
+	client, err := database.NewNeo4jClient(config.DatabaseConfig{
+		URI:                           "bolt://" + listener.Addr().String(),
+		Username:                      "neo4j",
+		Password:                      "test",
+		Database:                      "neo4j",
+		MaxConns:                      4,
+		ConnAcquisitionTimeoutSeconds: 5,
+		QueryTimeoutSeconds:           5,
+		PoolMaxQueue:                  16,
+		PoolWaitTimeoutMs:             5000,
+	}, setupTestLogger(t))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		client.Close(context.Background())
+		listener.Close()
+		conns.Wait()
+	})
+	return client
+}
+
+// Bolt message signatures
+const (
+	boltHello    = 0x01
+	boltGoodbye  = 0x02
+	boltReset    = 0x0F
+	boltRun      = 0x10
+	boltBegin    = 0x11
+	boltCommit   = 0x12
+	boltRollback = 0x13
+	boltDiscard  = 0x2F
+	boltPull     = 0x3F
+	boltSuccess  = 0x70
+	boltRecord   = 0x71
+)
+
+// boltStruct is a PackStream structure, such as a message or a temporal
+// value
+type boltStruct struct {
+	signature byte
+	fields    []interface{}
+}
+
+// serveFakeBolt speaks enough Bolt 4.4 to a driver to run queries in
+// transactions
+func serveFakeBolt(conn net.Conn, handler fakeNeo4jHandler) {
+	reader := bufio.NewReader(conn)
+	handshake := make([]byte, 20)
+	if _, err := io.ReadFull(reader, handshake); err != nil {
+		return
+	}
+	if _, err := conn.Write([]byte{0, 0, 4, 4}); err != nil {
+		return
+	}
+
+	var rows [][]interface{}
+	for {
+		message, err := readBoltMessage(reader)
+		if err != nil {
+			return
+		}
+		var replies [][]byte
+		switch message.signature {
+		case boltHello:
+			replies = append(replies, boltReply(boltSuccess, map[string]interface{}{"server": "Neo4j/4.4.0", "connection_id": "bolt-1"}))
+		case boltRun:
+			query, _ := message.fields[0].(string)
+			params, _ := message.fields[1].(map[string]interface{})
+			var keys []string
+			keys, rows = handler(query, params)
+			fields := make([]interface{}, len(keys))
+			for i, key := range keys {
+				fields[i] = key
+			}
+			replies = append(replies, boltReply(boltSuccess, map[string]interface{}{"fields": fields, "t_first": 0, "qid": 0}))
+		case boltPull:
+			for _, row := range rows {
+				replies = append(replies, boltReply(boltRecord, row))
+			}
+			rows = nil
+			replies = append(replies, boltReply(boltSuccess, map[string]interface{}{"has_more": false, "type": "rw", "db": "neo4j"}))
+		case boltDiscard:
+			rows = nil
+			replies = append(replies, boltReply(boltSuccess, map[string]interface{}{"has_more": false}))
+		case boltCommit:
+			replies = append(replies, boltReply(boltSuccess, map[string]interface{}{"bookmark": "fake:1"}))
+		case boltBegin, boltRollback, boltReset:
+			replies = append(replies, boltReply(boltSuccess, map[string]interface{}{}))
+		case boltGoodbye:
+			return
+		default:
+			panic(fmt.Sprintf("fake Neo4j: unexpected Bolt message 0x%02X", message.signature))
+		}
+		for _, reply := range replies {
+			if err := writeBoltMessage(conn, reply); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readBoltMessage reads the chunks of a message, skipping NOOPs
+func readBoltMessage(r *bufio.Reader) (*boltStruct, error) {
+	var message bytes.Buffer
+	for {
+		var size uint16
+		if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			if message.Len() == 0 {
+				continue
+			}
+			break
+		}
+		if _, err := io.CopyN(&message, r, int64(size)); err != nil {
+			return nil, err
+		}
+	}
+	value, err := unpackBolt(bufio.NewReader(&message))
+	if err != nil {
+		return nil, err
+	}
+	structure, ok := value.(*boltStruct)
+	if !ok {
+		return nil, fmt.Errorf("fake Neo4j: message is a %T", value)
+	}
+	return structure, nil
+}
+
+// writeBoltMessage writes a message in one chunk
+func writeBoltMessage(w io.Writer, message []byte) error {
+	frame := make([]byte, 0, len(message)+4)
+	frame = binary.BigEndian.AppendUint16(frame, uint16(len(message)))
+	frame = append(frame, message...)
+	frame = append(frame, 0, 0)
+	_, err := w.Write(frame)
+	return err
+}
+
+// boltReply packs a response message with one field
+func boltReply(signature byte, field interface{}) []byte {
+	var buf bytes.Buffer
+	buf.Write([]byte{0xB1, signature})
+	packBolt(&buf, field)
+	return buf.Bytes()
+}
+
+// packBolt writes the PackStream encoding of the values a fake answers
+// with
+func packBolt(buf *bytes.Buffer, value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteByte(0xC0)
+	case bool:
+		if v {
+			buf.WriteByte(0xC3)
+		} else {
+			buf.WriteByte(0xC2)
+		}
+	case int:
+		packBoltInt(buf, int64(v))
+	case int64:
+		packBoltInt(buf, v)
+	case float64:
+		buf.WriteByte(0xC1)
+		binary.Write(buf, binary.BigEndian, math.Float64bits(v))
+	case string:
+		packBoltHeader(buf, 0x80, 0xD0, len(v))
+		buf.WriteString(v)
+	case []string:
+		packBoltHeader(buf, 0x90, 0xD4, len(v))
+		for _, item := range v {
+			packBolt(buf, item)
+		}
+	case []interface{}:
+		packBoltHeader(buf, 0x90, 0xD4, len(v))
+		for _, item := range v {
+			packBolt(buf, item)
+		}
+	case map[string]interface{}:
+		packBoltHeader(buf, 0xA0, 0xD8, len(v))
+		for key, item := range v {
+			packBolt(buf, key)
+			packBolt(buf, item)
+		}
+	default:
+		panic(fmt.Sprintf("fake Neo4j: cannot pack %T", value))
+	}
+}
+
+func packBoltInt(buf *bytes.Buffer, v int64) {
+	switch {
+	case v >= -16 && v <= 127:
+		buf.WriteByte(byte(int8(v)))
+	case v >= math.MinInt8 && v <= math.MaxInt8:
+		buf.WriteByte(0xC8)
+		buf.WriteByte(byte(int8(v)))
+	case v >= math.MinInt16 && v <= math.MaxInt16:
+		buf.WriteByte(0xC9)
+		binary.Write(buf, binary.BigEndian, int16(v))
+	case v >= math.MinInt32 && v <= math.MaxInt32:
+		buf.WriteByte(0xCA)
+		binary.Write(buf, binary.BigEndian, int32(v))
+	default:
+		buf.WriteByte(0xCB)
+		binary.Write(buf, binary.BigEndian, v)
+	}
+}
+
+// packBoltHeader writes the marker of a string, list or map of n items:
+// tiny below 16, then 8, 16 and 32 bit sizes
+func packBoltHeader(buf *bytes.Buffer, tiny, sized byte, n int) {
+	switch {
+	case n < 16:
+		buf.WriteByte(tiny | byte(n))
+	case n <= math.MaxUint8:
+		buf.Write([]byte{sized, byte(n)})
+	case n <= math.MaxUint16:
+		buf.WriteByte(sized + 1)
+		binary.Write(buf, binary.BigEndian, uint16(n))
+	default:
+		buf.WriteByte(sized + 2)
+		binary.Write(buf, binary.BigEndian, uint32(n))
+	}
+}
+
+// unpackBolt reads a PackStream value the driver sent
+func unpackBolt(r *bufio.Reader) (interface{}, error) {
+	marker, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	readInt := func(size int) (int64, error) {
+		data := make([]byte, size)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return 0, err
+		}
+		switch size {
+		case 1:
+			return int64(int8(data[0])), nil
+		case 2:
+			return int64(int16(binary.BigEndian.Uint16(data))), nil
+		case 4:
+			return int64(int32(binary.BigEndian.Uint32(data))), nil
+		}
+		return int64(binary.BigEndian.Uint64(data)), nil
+	}
+	readSize := func(size int) (int, error) {
+		data := make([]byte, size)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return 0, err
+		}
+		switch size {
+		case 1:
+			return int(data[0]), nil
+		case 2:
+			return int(binary.BigEndian.Uint16(data)), nil
+		}
+		return int(binary.BigEndian.Uint32(data)), nil
+	}
+	readString := func(n int) (string, error) {
+		data := make([]byte, n)
+		_, err := io.ReadFull(r, data)
+		return string(data), err
+	}
+	readList := func(n int) (interface{}, error) {
+		list := make([]interface{}, n)
+		for i := range list {
+			if list[i], err = unpackBolt(r); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	}
+	readMap := func(n int) (interface{}, error) {
+		m := make(map[string]interface{}, n)
+		for i := 0; i < n; i++ {
+			key, err := unpackBolt(r)
+			if err != nil {
+				return nil, err
+			}
+			if m[fmt.Sprint(key)], err = unpackBolt(r); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	}
+	sizeOf := map[byte]int{0: 1, 1: 2, 2: 4}
+
+	switch {
+	case marker < 0x80 || marker >= 0xF0:
+		return int64(int8(marker)), nil
+	case marker < 0x90:
+		return readString(int(marker & 0x0F))
+	case marker < 0xA0:
+		return readList(int(marker & 0x0F))
+	case marker < 0xB0:
+		return readMap(int(marker & 0x0F))
+	case marker < 0xC0:
+		signature, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		fields, err := readList(int(marker & 0x0F))
+		if err != nil {
+			return nil, err
+		}
+		return &boltStruct{signature: signature, fields: fields.([]interface{})}, nil
+	}
+	switch marker {
+	case 0xC0:
+		return nil, nil
+	case 0xC1:
+		bits, err := readInt(8)
+		return math.Float64frombits(uint64(bits)), err
+	case 0xC2, 0xC3:
+		return marker == 0xC3, nil
+	case 0xC8, 0xC9, 0xCA, 0xCB:
+		return readInt(1 << (marker - 0xC8))
+	case 0xCC, 0xCD, 0xCE:
+		n, err := readSize(sizeOf[marker-0xCC])
+		if err != nil {
+			return nil, err
+		}
+		data := make([]byte, n)
+		_, err = io.ReadFull(r, data)
+		return data, err
+	case 0xD0, 0xD1, 0xD2:
+		n, err := readSize(sizeOf[marker-0xD0])
+		if err != nil {
+			return nil, err
+		}
+		return readString(n)
+	case 0xD4, 0xD5, 0xD6:
+		n, err := readSize(sizeOf[marker-0xD4])
+		if err != nil {
+			return nil, err
+		}
+		return readList(n)
+	case 0xD8, 0xD9, 0xDA:
+		n, err := readSize(sizeOf[marker-0xD8])
+		if err != nil {
+			return nil, err
+		}
+		return readMap(n)
+	}
+	return nil, fmt.Errorf("fake Neo4j: unknown PackStream marker 0x%02X", marker)
+}
+
+// queryHas reports whether a query contains all of parts, ignoring
+// whitespace differences
+func queryHas(query string, parts ...string) bool {
+	normalized := strings.Join(strings.Fields(query), " ")
+	for _, part := range parts {
+		if !strings.Contains(normalized, part) {
+			return false
+		}
+	}
+	return true
+}
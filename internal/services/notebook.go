@@ -12,13 +12,17 @@ import (
 	"github.com/Tributary-ai-services/aether-be/internal/database"
 	"github.com/Tributary-ai-services/aether-be/internal/logger"
 	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
 	"github.com/Tributary-ai-services/aether-be/pkg/errors"
 )
 
 // NotebookService handles notebook-related business logic
 type NotebookService struct {
-	neo4j  *database.Neo4jClient
-	logger *logger.Logger
+	neo4j      *database.Neo4jClient
+	events     DomainEventPublisher
+	moderation *ModerationService
+	legalHolds *LegalHoldService
+	logger     *logger.Logger
 }
 
 // NewNotebookService creates a new notebook service
@@ -29,6 +33,23 @@ func NewNotebookService(neo4j *database.Neo4jClient, log *logger.Logger) *Notebo
 	}
 }
 
+// SetEventPublisher sets the publisher notified of notebook changes
+func (s *NotebookService) SetEventPublisher(events DomainEventPublisher) {
+	s.events = events
+}
+
+// SetModeration sets the moderation the names and descriptions of public
+// notebooks pass as they are saved
+func (s *NotebookService) SetModeration(moderation *ModerationService) {
+	s.moderation = moderation
+}
+
+// SetLegalHolds sets the service keeping notebooks under legal hold from
+// being edited or deleted
+func (s *NotebookService) SetLegalHolds(legalHolds *LegalHoldService) {
+	s.legalHolds = legalHolds
+}
+
 // CreateNotebook creates a new notebook
 func (s *NotebookService) CreateNotebook(ctx context.Context, req models.NotebookCreateRequest, ownerID string, spaceCtx *models.SpaceContext) (*models.Notebook, error) {
 	// Validate user can create in this space
@@ -46,6 +67,11 @@ func (s *NotebookService) CreateNotebook(ctx context.Context, req models.Noteboo
 
 	// Create new notebook
 	notebook := models.NewNotebook(req, ownerID, spaceCtx)
+	if notebook.IsPublic() {
+		if err := s.moderation.CheckPublicText(ctx, notebook.Name, notebook.Description); err != nil {
+			return nil, err
+		}
+	}
 
 	// Check if parent exists (if specified)
 	if req.ParentID != "" {
@@ -56,7 +82,7 @@ func (s *NotebookService) CreateNotebook(ctx context.Context, req models.Noteboo
 		if !parentExists {
 			return nil, errors.NotFoundWithDetails("Parent notebook not found", map[string]interface{}{
 				"parent_id": req.ParentID,
-			})
+			}).WithErrorCode(errors.CodeNotebookNotFound)
 		}
 	}
 
@@ -149,6 +175,9 @@ func (s *NotebookService) CreateNotebook(ctx context.Context, req models.Noteboo
 		)
 	}
 
+	publishDomainEvent(ctx, s.events, s.logger,
+		NewNotebookEvent(EventNotebookCreated, notebook.ID, ownerID, notebookEventData(notebook)))
+
 	s.logger.Info("Notebook created successfully",
 		zap.String("notebook_id", notebook.ID),
 		zap.String("name", notebook.Name),
@@ -162,6 +191,7 @@ func (s *NotebookService) CreateNotebook(ctx context.Context, req models.Noteboo
 func (s *NotebookService) GetNotebookByID(ctx context.Context, notebookID string, userID string, spaceCtx *models.SpaceContext) (*models.Notebook, error) {
 	query := `
 		MATCH (n:Notebook {id: $notebook_id, tenant_id: $tenant_id})
+		WHERE ` + database.SoftDeleteFilter(ctx, "n") + `
 		OPTIONAL MATCH (n)-[:OWNED_BY]->(owner:User)
 		RETURN n.id, n.name, n.description, n.visibility, n.status, n.owner_id,
 		       n.space_type, n.space_id, n.tenant_id, n.parent_id, n.team_id,
@@ -184,7 +214,7 @@ func (s *NotebookService) GetNotebookByID(ctx context.Context, notebookID string
 	if len(result.Records) == 0 {
 		return nil, errors.NotFoundWithDetails("Notebook not found", map[string]interface{}{
 			"notebook_id": notebookID,
-		})
+		}).WithErrorCode(errors.CodeNotebookNotFound)
 	}
 
 	notebook, err := s.recordToNotebook(result.Records[0])
@@ -197,7 +227,7 @@ func (s *NotebookService) GetNotebookByID(ctx context.Context, notebookID string
 		return nil, errors.ForbiddenWithDetails("Notebook not accessible in this space", map[string]interface{}{
 			"notebook_id": notebookID,
 			"space_id":    spaceCtx.SpaceID,
-		})
+		}).WithErrorCode(errors.CodeNotebookNotAccessible)
 	}
 
 	// Check if user has read permissions in the space
@@ -220,9 +250,18 @@ func (s *NotebookService) UpdateNotebook(ctx context.Context, notebookID string,
 	if !spaceCtx.CanUpdate() {
 		return nil, errors.Forbidden("Write access denied to notebook")
 	}
+	if err := s.legalHolds.CheckNotebook(ctx, notebookID, spaceCtx.TenantID); err != nil {
+		return nil, err
+	}
 
 	// Update notebook fields
+	wasPublic, name, description := notebook.IsPublic(), notebook.Name, notebook.Description
 	notebook.Update(req)
+	if notebook.IsPublic() && (!wasPublic || notebook.Name != name || notebook.Description != description) {
+		if err := s.moderation.CheckPublicText(ctx, notebook.Name, notebook.Description); err != nil {
+			return nil, err
+		}
+	}
 
 	// Serialize compliance settings to JSON string for Neo4j storage
 	var complianceSettingsJSON string
@@ -270,6 +309,9 @@ func (s *NotebookService) UpdateNotebook(ctx context.Context, notebookID string,
 		return nil, errors.Database("Failed to update notebook", err)
 	}
 
+	publishDomainEvent(ctx, s.events, s.logger,
+		NewNotebookEvent(EventNotebookUpdated, notebookID, userID, notebookEventData(notebook)))
+
 	s.logger.Info("Notebook updated successfully",
 		zap.String("notebook_id", notebookID),
 		zap.String("name", notebook.Name),
@@ -290,11 +332,19 @@ func (s *NotebookService) DeleteNotebook(ctx context.Context, notebookID string,
 	if !spaceCtx.CanDelete() {
 		return errors.Forbidden("Insufficient permissions to delete notebook")
 	}
+	if err := s.legalHolds.CheckNotebook(ctx, notebookID, spaceCtx.TenantID); err != nil {
+		return err
+	}
 
-	// Soft delete: update status to deleted
+	// Soft delete: update status and record who deleted it, as for spaces.
+	// The notebook stays in the trash, restorable, until it is purged.
+	now := time.Now().Format(time.RFC3339)
 	query := `
 		MATCH (n:Notebook {id: $notebook_id, tenant_id: $tenant_id})
-		SET n.status = 'deleted',
+		SET n.previous_status = n.status,
+		    n.status = $status,
+		    n.deleted_at = datetime($deleted_at),
+		    n.deleted_by = $deleted_by,
 		    n.updated_at = datetime($updated_at)
 		RETURN n
 	`
@@ -302,7 +352,10 @@ func (s *NotebookService) DeleteNotebook(ctx context.Context, notebookID string,
 	params := map[string]interface{}{
 		"notebook_id": notebookID,
 		"tenant_id":   spaceCtx.TenantID,
-		"updated_at":  time.Now().Format(time.RFC3339),
+		"status":      database.StatusDeleted,
+		"deleted_at":  now,
+		"deleted_by":  userID,
+		"updated_at":  now,
 	}
 
 	_, err = s.neo4j.ExecuteQueryWithLogging(ctx, query, params)
@@ -311,6 +364,12 @@ func (s *NotebookService) DeleteNotebook(ctx context.Context, notebookID string,
 		return errors.Database("Failed to delete notebook", err)
 	}
 
+	publishDomainEvent(ctx, s.events, s.logger,
+		NewNotebookEvent(EventNotebookDeleted, notebookID, userID, map[string]interface{}{
+			"space_id":  notebook.SpaceID,
+			"tenant_id": notebook.TenantID,
+		}))
+
 	s.logger.Info("Notebook deleted successfully",
 		zap.String("notebook_id", notebookID),
 		zap.String("name", notebook.Name),
@@ -327,13 +386,7 @@ func (s *NotebookService) ListNotebooks(ctx context.Context, userID string, spac
 		zap.Int("offset", offset),
 	)
 
-	// Set defaults
-	if limit <= 0 || limit > 100 {
-		limit = 20
-	}
-	if offset < 0 {
-		offset = 0
-	}
+	limit, offset = pagination.Clamp(limit, offset)
 
 	// Check if user has read permissions in the space
 	if !spaceCtx.CanRead() {
@@ -342,7 +395,8 @@ func (s *NotebookService) ListNotebooks(ctx context.Context, userID string, spac
 
 	query := `
 		MATCH (n:Notebook)
-		WHERE n.status = 'active' 
+		WHERE n.status = 'active'
+			AND ` + database.SoftDeleteFilter(ctx, "n") + `
 			AND n.tenant_id = $tenant_id
 			AND n.space_id = $space_id
 		OPTIONAL MATCH (n)-[:OWNED_BY]->(owner:User)
@@ -376,14 +430,9 @@ func (s *NotebookService) ListNotebooks(ctx context.Context, userID string, spac
 	)
 
 	notebooks := make([]*models.NotebookResponse, 0, len(result.Records))
-	hasMore := false
-
-	for i, record := range result.Records {
-		if i >= limit {
-			hasMore = true
-			break
-		}
+	records, hasMore := pagination.Trim(result.Records, limit)
 
+	for _, record := range records {
 		notebook, err := s.recordToNotebookResponse(record)
 		if err != nil {
 			s.logger.Error("Failed to parse notebook record", zap.Error(err))
@@ -396,7 +445,8 @@ func (s *NotebookService) ListNotebooks(ctx context.Context, userID string, spac
 	// Get total count
 	countQuery := `
 		MATCH (n:Notebook)
-		WHERE n.status = 'active' 
+		WHERE n.status = 'active'
+			AND ` + database.SoftDeleteFilter(ctx, "n") + `
 			AND n.tenant_id = $tenant_id
 			AND n.space_id = $space_id
 		RETURN count(n) as total
@@ -430,6 +480,50 @@ func (s *NotebookService) ListNotebooks(ctx context.Context, userID string, spac
 	}, nil
 }
 
+// GetNotebooksByIDs returns the notebooks with the given IDs that are
+// readable in the space, keyed by ID. IDs outside the space are left out
+// rather than reported, so one query can serve a batch of lookups.
+func (s *NotebookService) GetNotebooksByIDs(ctx context.Context, notebookIDs []string, spaceCtx *models.SpaceContext) (map[string]*models.NotebookResponse, error) {
+	if !spaceCtx.CanRead() {
+		return nil, errors.Forbidden("Insufficient permissions to read notebooks")
+	}
+
+	query := `
+		MATCH (n:Notebook)
+		WHERE n.id IN $notebook_ids
+			AND n.tenant_id = $tenant_id
+			AND n.space_id = $space_id
+			AND ` + database.SoftDeleteFilter(ctx, "n") + `
+		OPTIONAL MATCH (n)-[:OWNED_BY]->(owner:User)
+		RETURN n.id, n.name, n.description, n.visibility, n.status, n.owner_id,
+		       n.space_type, n.space_id, n.tenant_id, n.parent_id, n.team_id,
+		       n.compliance_settings, n.document_count, n.total_size_bytes,
+		       n.tags, n.created_at, n.updated_at,
+		       owner.username, owner.full_name, owner.avatar_url
+	`
+
+	result, err := s.neo4j.ExecuteQueryWithLogging(ctx, query, map[string]interface{}{
+		"notebook_ids": notebookIDs,
+		"tenant_id":    spaceCtx.TenantID,
+		"space_id":     spaceCtx.SpaceID,
+	})
+	if err != nil {
+		s.logger.Error("Failed to get notebooks by ID", zap.Int("count", len(notebookIDs)), zap.Error(err))
+		return nil, errors.Database("Failed to retrieve notebooks", err)
+	}
+
+	notebooks := make(map[string]*models.NotebookResponse, len(result.Records))
+	for _, record := range result.Records {
+		notebook, err := s.recordToNotebookResponse(record)
+		if err != nil {
+			s.logger.Error("Failed to parse notebook record", zap.Error(err))
+			continue
+		}
+		notebooks[notebook.ID] = notebook
+	}
+	return notebooks, nil
+}
+
 // SearchNotebooks searches for notebooks within a space
 func (s *NotebookService) SearchNotebooks(ctx context.Context, req models.NotebookSearchRequest, userID string, spaceCtx *models.SpaceContext) (*models.NotebookListResponse, error) {
 	// Set defaults
@@ -448,6 +542,7 @@ func (s *NotebookService) SearchNotebooks(ctx context.Context, req models.Notebo
 	// Build query conditions - filter by space
 	whereConditions := []string{
 		"n.status = 'active'",
+		database.SoftDeleteFilter(ctx, "n"),
 		"n.tenant_id = $tenant_id",
 		"n.space_id = $space_id",
 	}
@@ -510,14 +605,9 @@ func (s *NotebookService) SearchNotebooks(ctx context.Context, req models.Notebo
 	}
 
 	notebooks := make([]*models.NotebookResponse, 0, len(result.Records))
-	hasMore := false
-
-	for i, record := range result.Records {
-		if i >= req.Limit {
-			hasMore = true
-			break
-		}
+	records, hasMore := pagination.Trim(result.Records, req.Limit)
 
+	for _, record := range records {
 		notebook, err := s.recordToNotebookResponse(record)
 		if err != nil {
 			s.logger.Error("Failed to parse notebook record", zap.Error(err))
@@ -536,55 +626,77 @@ func (s *NotebookService) SearchNotebooks(ctx context.Context, req models.Notebo
 	}, nil
 }
 
-// ShareNotebook shares a notebook with users or groups
-func (s *NotebookService) ShareNotebook(ctx context.Context, notebookID string, req models.NotebookShareRequest, userID string, spaceCtx *models.SpaceContext) error {
-	// Get notebook and check permissions
-	notebook, err := s.GetNotebookByID(ctx, notebookID, userID, spaceCtx)
-	if err != nil {
-		return err
-	}
-
-	// Check if user can share notebook (must be owner or have admin permission)
-	if notebook.OwnerID != userID {
-		return errors.Forbidden("Only notebook owner can share notebook")
-	}
+// reconcileNotebookCountsQuery recomputes the counters of the given notebooks
+// from their documents at write time, so changes made since the scan are
+// not overwritten with stale values. Trashed documents are not counted.
+var reconcileNotebookCountsQuery = `
+	UNWIND $rows AS row
+	MATCH (n:Notebook {id: row.id, tenant_id: row.tenant_id})
+	OPTIONAL MATCH (d:Document)-[:BELONGS_TO]->(n)
+	WHERE ` + database.NotDeleted("d") + `
+	WITH n, count(d) AS document_count, sum(coalesce(d.size_bytes, 0)) AS total_size_bytes
+	SET n.document_count = document_count,
+	    n.total_size_bytes = total_size_bytes
+`
+
+// reconcileCountsFlushRows is how many drifted notebooks are corrected per
+// write while reconciling
+const reconcileCountsFlushRows = database.DefaultBatchSize
+
+// ReconcileDocumentCounts repairs notebook document_count and
+// total_size_bytes counters that drifted from the documents actually
+// attached, e.g. after a failed upload or delete. Notebooks are scanned as a
+// stream and corrected reconcileCountsFlushRows at a time, so the job's
+// memory does not grow with the number of notebooks.
+func (s *NotebookService) ReconcileDocumentCounts(ctx context.Context) error {
+	query := `
+		MATCH (n:Notebook)
+		OPTIONAL MATCH (d:Document)-[:BELONGS_TO]->(n)
+		WHERE ` + database.NotDeleted("d") + `
+		WITH n, count(d) AS actual_count, sum(coalesce(d.size_bytes, 0)) AS actual_size
+		WHERE coalesce(n.document_count, 0) <> actual_count
+		   OR coalesce(n.total_size_bytes, 0) <> actual_size
+		RETURN n.id AS id, n.tenant_id AS tenant_id
+	`
 
-	// Create sharing relationships
-	for _, sharedUserID := range req.UserIDs {
-		for _, permission := range req.Permissions {
-			if err := s.createSharingRelationship(ctx, notebookID, sharedUserID, "", permission, userID); err != nil {
-				s.logger.Error("Failed to create sharing relationship",
-					zap.String("notebook_id", notebookID),
-					zap.String("shared_user_id", sharedUserID),
-					zap.String("permission", permission),
-					zap.Error(err),
-				)
-				return err
-			}
+	rows := make([]map[string]interface{}, 0, reconcileCountsFlushRows)
+	corrected := 0
+	flush := func() error {
+		if len(rows) == 0 {
+			return nil
+		}
+		if _, err := s.neo4j.WriteBatch(database.WithQueryName(ctx, "notebook.reconcile_counts"), reconcileNotebookCountsQuery, rows, nil); err != nil {
+			return fmt.Errorf("failed to correct notebook counts: %w", err)
 		}
+		corrected += len(rows)
+		rows = rows[:0]
+		return nil
 	}
 
-	for _, groupID := range req.GroupIDs {
-		for _, permission := range req.Permissions {
-			if err := s.createSharingRelationship(ctx, notebookID, "", groupID, permission, userID); err != nil {
-				s.logger.Error("Failed to create group sharing relationship",
-					zap.String("notebook_id", notebookID),
-					zap.String("group_id", groupID),
-					zap.String("permission", permission),
-					zap.Error(err),
-				)
-				return err
-			}
+	_, err := s.neo4j.StreamQuery(database.WithQueryName(ctx, "notebook.reconcile_counts_scan"), query, nil, func(record *neo4j.Record) error {
+		id, _ := record.Get("id")
+		tenantID, _ := record.Get("tenant_id")
+		rows = append(rows, map[string]interface{}{
+			"id":        s.getString(id),
+			"tenant_id": s.getString(tenantID),
+		})
+		if len(rows) < reconcileCountsFlushRows {
+			return nil
 		}
+		return flush()
+	})
+	if err == nil {
+		err = flush()
+	}
+	if corrected > 0 {
+		s.logger.Warn("Corrected drifted notebook document counts", zap.Int("notebooks", corrected))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to scan notebook counts: %w", err)
+	}
+	if corrected == 0 {
+		s.logger.Debug("Notebook document counts are consistent")
 	}
-
-	s.logger.Info("Notebook shared successfully",
-		zap.String("notebook_id", notebookID),
-		zap.Strings("user_ids", req.UserIDs),
-		zap.Strings("group_ids", req.GroupIDs),
-		zap.Strings("permissions", req.Permissions),
-	)
-
 	return nil
 }
 
@@ -697,25 +809,10 @@ func (s *NotebookService) createBelongsToSpaceRelationship(ctx context.Context,
 	return nil
 }
 
-func (s *NotebookService) createSharingRelationship(ctx context.Context, notebookID, userID, groupID, permission, grantedBy string) error {
-	// Implementation would create sharing relationships in Neo4j
-	return nil
-}
-
 func (s *NotebookService) canUserAccessNotebook(ctx context.Context, notebook *models.Notebook, userID string) bool {
 	return notebook.CanBeAccessedBy(userID)
 }
 
-func (s *NotebookService) canUserWriteNotebook(ctx context.Context, notebook *models.Notebook, userID string) bool {
-	// Owner can always write
-	if notebook.OwnerID == userID {
-		return true
-	}
-
-	// TODO: Check write permissions from sharing relationships
-	return false
-}
-
 func (s *NotebookService) recordToNotebook(record interface{}) (*models.Notebook, error) {
 	// Cast the record to the proper Neo4j record type
 	neo4jRecord, ok := record.(*neo4j.Record)
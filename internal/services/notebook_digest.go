@@ -0,0 +1,376 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// Notebook digest limits
+const (
+	// notebookDigestDueBatch is how many due digests one scheduled run sends
+	notebookDigestDueBatch = 50
+	// notebookDigestMaxEvents bounds the audit events a digest is built
+	// from; a busier notebook's digest covers its latest changes
+	notebookDigestMaxEvents = 1000
+	// notebookDigestListed bounds the documents of each section an email
+	// lists by name
+	notebookDigestListed = 50
+)
+
+// notebookDigestFields are the columns recordToNotebookDigestSubscription
+// reads
+const notebookDigestFields = `d.id AS id, d.notebook_id AS notebook_id, n.name AS notebook_name,
+	       d.user_id AS user_id, d.cadence AS cadence, d.next_run_at AS next_run_at,
+	       d.last_sent_at AS last_sent_at, d.last_error AS last_error,
+	       d.created_at AS created_at, d.updated_at AS updated_at`
+
+// NotebookDigestService sends users who subscribe to a notebook a daily or
+// weekly summary of the documents added to and changed in it. Digests are
+// built from the notebook's activity feed by the leader once their period
+// ends, announced in the app and, when SMTP is configured, emailed.
+type NotebookDigestService struct {
+	neo4j  *database.Neo4jClient
+	feeds  *FeedService
+	mailer reportMailer // nil when digests are not emailed
+	events DomainEventPublisher
+	logger *logger.Logger
+}
+
+// NewNotebookDigestService creates a notebook digest service. Digests are
+// emailed through the SMTP server of scheduled reports.
+func NewNotebookDigestService(neo4j *database.Neo4jClient, feeds *FeedService, cfg config.ReportsConfig, log *logger.Logger) *NotebookDigestService {
+	return &NotebookDigestService{
+		neo4j:  neo4j,
+		feeds:  feeds,
+		mailer: newSMTPReportMailer(cfg),
+		logger: log.WithService("notebook_digest_service"),
+	}
+}
+
+// SetEventPublisher sets the bus digests are announced on
+func (s *NotebookDigestService) SetEventPublisher(events DomainEventPublisher) {
+	s.events = events
+}
+
+// Subscribe subscribes a user to a notebook's digest, or changes the
+// cadence of their subscription. The caller checks that the user can read
+// the notebook. The first digest is sent when the current period ends.
+func (s *NotebookDigestService) Subscribe(ctx context.Context, notebookID, userID string, req models.NotebookDigestSubscribeRequest) (*models.NotebookDigestSubscription, error) {
+	now := time.Now().UTC()
+	nextRunAt := reportPeriodShift(req.Cadence, reportPeriodStart(req.Cadence, now), 1)
+
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "notebook_digest.subscribe"), `
+		MERGE (d:NotebookDigestSubscription {notebook_id: $notebook_id, user_id: $user_id})
+		ON CREATE SET d.id = $id, d.created_at = $now
+		WITH d, d.cadence <> $cadence OR d.cadence IS NULL AS changed
+		SET d.cadence = $cadence, d.updated_at = $now,
+		    d.next_run_at = CASE WHEN changed THEN $next_run_at ELSE d.next_run_at END
+		WITH d
+		MATCH (n:Notebook {id: d.notebook_id})
+		RETURN `+notebookDigestFields+`
+	`, map[string]interface{}{
+		"notebook_id": notebookID,
+		"user_id":     userID,
+		"id":          uuid.New().String(),
+		"cadence":     req.Cadence,
+		"next_run_at": nextRunAt,
+		"now":         now,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to subscribe to notebook digest", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, errors.NotFoundWithDetails("Notebook not found", map[string]interface{}{
+			"notebook_id": notebookID,
+		}).WithErrorCode(errors.CodeNotebookNotFound)
+	}
+	subscription := recordToNotebookDigestSubscription(result.Records[0])
+
+	s.logger.Info("Notebook digest subscribed",
+		zap.String("notebook_id", notebookID),
+		zap.String("user_id", userID),
+		zap.String("cadence", req.Cadence))
+	return subscription, nil
+}
+
+// GetSubscription returns a user's subscription to a notebook's digest
+func (s *NotebookDigestService) GetSubscription(ctx context.Context, notebookID, userID string) (*models.NotebookDigestSubscription, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "notebook_digest.get"), `
+		MATCH (d:NotebookDigestSubscription {notebook_id: $notebook_id, user_id: $user_id})
+		OPTIONAL MATCH (n:Notebook {id: d.notebook_id})
+		RETURN `+notebookDigestFields+`
+	`, map[string]interface{}{"notebook_id": notebookID, "user_id": userID})
+	if err != nil {
+		return nil, errors.Database("Failed to get notebook digest subscription", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, notebookDigestNotFound(notebookID)
+	}
+	return recordToNotebookDigestSubscription(result.Records[0]), nil
+}
+
+// ListSubscriptions lists a user's digest subscriptions, oldest first
+func (s *NotebookDigestService) ListSubscriptions(ctx context.Context, userID string) (*models.NotebookDigestSubscriptionList, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "notebook_digest.list"), `
+		MATCH (d:NotebookDigestSubscription {user_id: $user_id})
+		OPTIONAL MATCH (n:Notebook {id: d.notebook_id})
+		RETURN `+notebookDigestFields+`
+		ORDER BY d.created_at
+	`, map[string]interface{}{"user_id": userID})
+	if err != nil {
+		return nil, errors.Database("Failed to list notebook digest subscriptions", err)
+	}
+
+	list := &models.NotebookDigestSubscriptionList{
+		Subscriptions: make([]*models.NotebookDigestSubscription, 0, len(result.Records)),
+	}
+	for _, record := range result.Records {
+		list.Subscriptions = append(list.Subscriptions, recordToNotebookDigestSubscription(record))
+	}
+	return list, nil
+}
+
+// Unsubscribe deletes a user's subscription to a notebook's digest
+func (s *NotebookDigestService) Unsubscribe(ctx context.Context, notebookID, userID string) error {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "notebook_digest.unsubscribe"), `
+		MATCH (d:NotebookDigestSubscription {notebook_id: $notebook_id, user_id: $user_id})
+		DELETE d
+		RETURN count(d) AS deleted
+	`, map[string]interface{}{"notebook_id": notebookID, "user_id": userID})
+	if err != nil {
+		return errors.Database("Failed to unsubscribe from notebook digest", err)
+	}
+	if recordInt(result.Records, "deleted") == 0 {
+		return notebookDigestNotFound(notebookID)
+	}
+
+	s.logger.Info("Notebook digest unsubscribed", zap.String("notebook_id", notebookID), zap.String("user_id", userID))
+	return nil
+}
+
+// ProcessDue sends the digests whose period has ended, as the leader's
+// notebook_digests job. A digest with no changes is not sent. A digest
+// that cannot be built, because the user lost access to the notebook or it
+// was deleted, records the error and is tried again next period.
+func (s *NotebookDigestService) ProcessDue(ctx context.Context) error {
+	now := time.Now().UTC()
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "notebook_digest.due"), `
+		MATCH (d:NotebookDigestSubscription)
+		WHERE d.next_run_at <= $now
+		OPTIONAL MATCH (n:Notebook {id: d.notebook_id})
+		OPTIONAL MATCH (u:User {id: d.user_id})
+		RETURN `+notebookDigestFields+`, u.email AS email
+		ORDER BY d.next_run_at
+		LIMIT $limit
+	`, map[string]interface{}{
+		"now":   now,
+		"limit": notebookDigestDueBatch,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to find due notebook digests: %w", err)
+	}
+
+	for _, record := range result.Records {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		subscription := recordToNotebookDigestSubscription(record)
+		end := reportPeriodStart(subscription.Cadence, now)
+		start := reportPeriodShift(subscription.Cadence, end, -1)
+		if err := s.send(ctx, subscription, recordString(record, "email"), start, end); err != nil {
+			s.logger.Error("Failed to record notebook digest",
+				zap.String("subscription_id", subscription.ID),
+				zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// send builds and delivers a subscription's digest of a period, then moves
+// the subscription to its next period. An email that fails is recorded as
+// the subscription's error; the digest is still announced in the app.
+func (s *NotebookDigestService) send(ctx context.Context, subscription *models.NotebookDigestSubscription, email string, start, end time.Time) error {
+	var sentAt interface{}
+	var failure string
+
+	notebook, events, err := s.feeds.NotebookDocumentChanges(ctx, subscription.NotebookID, subscription.UserID, start, end, notebookDigestMaxEvents)
+	if err != nil {
+		failure = err.Error()
+		s.logger.Warn("Notebook digest could not be built",
+			zap.String("subscription_id", subscription.ID),
+			zap.Error(err))
+	} else if digest := buildNotebookDigest(notebook, events, start, end); !digest.Empty() {
+		if s.mailer != nil && email != "" {
+			if err := s.mailer.Send(ctx, notebookDigestEmail(email, digest)); err != nil {
+				failure = err.Error()
+				s.logger.Warn("Notebook digest email failed",
+					zap.String("subscription_id", subscription.ID),
+					zap.Error(err))
+			}
+		}
+		publishDomainEvent(ctx, s.events, s.logger, Event{
+			Type:    EventNotebookDigestSent,
+			Subject: notebook.ID,
+			Data: map[string]interface{}{
+				"tenant_id":       notebook.TenantID,
+				"space_id":        notebook.SpaceID,
+				"name":            notebook.Name,
+				"cadence":         subscription.Cadence,
+				"period_start":    start,
+				"period_end":      end,
+				"new":             len(digest.New),
+				"changed":         len(digest.Changed),
+				"notify_user_ids": []string{subscription.UserID},
+			},
+		})
+		sentAt = time.Now().UTC()
+	}
+
+	_, err = s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "notebook_digest.record"), `
+		MATCH (d:NotebookDigestSubscription {id: $id})
+		SET d.next_run_at = $next_run_at, d.last_error = $error,
+		    d.last_sent_at = coalesce($sent_at, d.last_sent_at)
+	`, map[string]interface{}{
+		"id":          subscription.ID,
+		"next_run_at": reportPeriodShift(subscription.Cadence, end, 1),
+		"error":       failure,
+		"sent_at":     sentAt,
+	})
+	if err != nil {
+		return errors.Database("Failed to record notebook digest", err)
+	}
+	return nil
+}
+
+// buildNotebookDigest sorts the documents a notebook's audit events of a
+// period name into new and changed ones. Events come newest first.
+// Documents deleted by the end of the period are left out.
+func buildNotebookDigest(notebook *models.Notebook, events []*models.AuditEvent, start, end time.Time) *models.NotebookDigest {
+	digest := &models.NotebookDigest{
+		NotebookID:   notebook.ID,
+		NotebookName: notebook.Name,
+		PeriodStart:  start,
+		PeriodEnd:    end,
+		New:          []*models.NotebookDigestDocument{},
+		Changed:      []*models.NotebookDigestDocument{},
+	}
+
+	documents := map[string]*models.NotebookDigestDocument{}
+	added := map[string]bool{}
+	for i := len(events) - 1; i >= 0; i-- {
+		event := events[i]
+		if event.ResourceID == "" {
+			continue
+		}
+		switch EventType(event.Action) {
+		case EventDocumentUploaded, EventDocumentUpdated, EventDocumentRestored:
+			document, ok := documents[event.ResourceID]
+			if !ok {
+				document = &models.NotebookDigestDocument{ID: event.ResourceID, Name: event.ResourceID}
+				documents[event.ResourceID] = document
+			}
+			if name, _ := event.Details["name"].(string); name != "" {
+				document.Name = name
+			}
+			document.ChangedAt = event.CreatedAt
+			if EventType(event.Action) == EventDocumentUploaded {
+				added[event.ResourceID] = true
+			}
+		case EventDocumentDeleted:
+			delete(documents, event.ResourceID)
+			delete(added, event.ResourceID)
+		}
+	}
+
+	for id, document := range documents {
+		if added[id] {
+			digest.New = append(digest.New, document)
+		} else {
+			digest.Changed = append(digest.Changed, document)
+		}
+	}
+	for _, section := range [][]*models.NotebookDigestDocument{digest.New, digest.Changed} {
+		sort.Slice(section, func(i, j int) bool {
+			if section[i].ChangedAt.Equal(section[j].ChangedAt) {
+				return section[i].ID < section[j].ID
+			}
+			return section[i].ChangedAt.After(section[j].ChangedAt)
+		})
+	}
+	return digest
+}
+
+// notebookDigestEmail writes a digest as a plain text email, listing the
+// most recently changed documents of each section by name
+func notebookDigestEmail(to string, digest *models.NotebookDigest) reportEmail {
+	period := reportPeriodLabel(digest.PeriodStart, digest.PeriodEnd)
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Changes to the documents of notebook %q for %s.\n", digest.NotebookName, period)
+	sections := []struct {
+		title     string
+		documents []*models.NotebookDigestDocument
+	}{
+		{"New documents", digest.New},
+		{"Changed documents", digest.Changed},
+	}
+	for _, section := range sections {
+		if len(section.documents) == 0 {
+			continue
+		}
+		fmt.Fprintf(&body, "\n%s (%d):\n", section.title, len(section.documents))
+		for i, document := range section.documents {
+			if i == notebookDigestListed {
+				fmt.Fprintf(&body, "- and %d more\n", len(section.documents)-i)
+				break
+			}
+			fmt.Fprintf(&body, "- %s (%s)\n", document.Name, document.ChangedAt.UTC().Format("2006-01-02 15:04 UTC"))
+		}
+	}
+	body.WriteString("\nYou receive this digest because you subscribed to the notebook's changes in Aether.\n")
+
+	return reportEmail{
+		To:      []string{to},
+		Subject: fmt.Sprintf("%s: %d new, %d changed (%s)", digest.NotebookName, len(digest.New), len(digest.Changed), period),
+		Body:    body.String(),
+	}
+}
+
+func notebookDigestNotFound(notebookID string) error {
+	return errors.NotFoundWithDetails("Notebook digest subscription not found", map[string]interface{}{
+		"notebook_id": notebookID,
+	}).WithErrorCode(errors.CodeDigestSubscriptionNotFound)
+}
+
+// recordToNotebookDigestSubscription reads the notebookDigestFields of a
+// record
+func recordToNotebookDigestSubscription(record *neo4j.Record) *models.NotebookDigestSubscription {
+	subscription := &models.NotebookDigestSubscription{
+		ID:           recordString(record, "id"),
+		NotebookID:   recordString(record, "notebook_id"),
+		NotebookName: recordString(record, "notebook_name"),
+		UserID:       recordString(record, "user_id"),
+		Cadence:      recordString(record, "cadence"),
+		NextRunAt:    recordTime(record, "next_run_at"),
+		LastError:    recordString(record, "last_error"),
+		CreatedAt:    recordTime(record, "created_at"),
+		UpdatedAt:    recordTime(record, "updated_at"),
+	}
+	if at := recordTime(record, "last_sent_at"); !at.IsZero() {
+		subscription.LastSentAt = &at
+	}
+	return subscription
+}
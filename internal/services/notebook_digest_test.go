@@ -0,0 +1,92 @@
+package services
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+func TestBuildNotebookDigest(t *testing.T) {
+	start := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 7)
+	at := func(hours int) time.Time { return start.Add(time.Duration(hours) * time.Hour) }
+	event := func(action, documentID, name string, hours int) *models.AuditEvent {
+		return &models.AuditEvent{
+			Action:       action,
+			ResourceType: "document",
+			ResourceID:   documentID,
+			Details:      map[string]interface{}{"name": name, "notebook_id": "nb-1"},
+			CreatedAt:    at(hours),
+		}
+	}
+
+	// Newest first, as the audit log returns them
+	events := []*models.AuditEvent{
+		event("document.deleted", "doc-gone", "draft.txt", 40),
+		event("document.updated", "doc-old", "contract-v2.pdf", 30),
+		event("document.restored", "doc-trash", "", 25),
+		event("document.updated", "doc-new", "notes.md", 20),
+		event("document.uploaded", "doc-gone", "draft.txt", 15),
+		event("document.uploaded", "doc-late", "slides.pptx", 12),
+		event("document.uploaded", "doc-new", "notes.txt", 10),
+	}
+	notebook := &models.Notebook{ID: "nb-1", Name: "Legal"}
+	digest := buildNotebookDigest(notebook, events, start, end)
+
+	assert.Equal(t, "Legal", digest.NotebookName)
+	assert.False(t, digest.Empty())
+	require.Len(t, digest.New, 2, "documents deleted by the end of the period are left out")
+	assert.Equal(t, "doc-new", digest.New[0].ID, "most recently changed first")
+	assert.Equal(t, "notes.md", digest.New[0].Name, "the latest name is listed")
+	assert.Equal(t, at(20), digest.New[0].ChangedAt)
+	assert.Equal(t, "doc-late", digest.New[1].ID)
+
+	require.Len(t, digest.Changed, 2)
+	assert.Equal(t, "doc-old", digest.Changed[0].ID)
+	assert.Equal(t, "doc-trash", digest.Changed[1].Name, "documents without a name in the event are listed by ID")
+
+	assert.True(t, buildNotebookDigest(notebook, nil, start, end).Empty())
+}
+
+func TestNotebookDigestEmail(t *testing.T) {
+	start := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	digest := &models.NotebookDigest{
+		NotebookName: "Legal",
+		PeriodStart:  start,
+		PeriodEnd:    start.AddDate(0, 0, 1),
+		New: []*models.NotebookDigestDocument{
+			{ID: "doc-1", Name: "contract.pdf", ChangedAt: start.Add(9 * time.Hour)},
+		},
+	}
+	for i := 0; i < notebookDigestListed+3; i++ {
+		digest.Changed = append(digest.Changed, &models.NotebookDigestDocument{ID: "doc", Name: "memo.txt", ChangedAt: start})
+	}
+
+	email := notebookDigestEmail("ana@example.com", digest)
+	assert.Equal(t, []string{"ana@example.com"}, email.To)
+	assert.Equal(t, "Legal: 1 new, 53 changed (2026-10-12)", email.Subject)
+	assert.Contains(t, email.Body, "New documents (1):\n- contract.pdf (2026-10-12 09:00 UTC)\n")
+	assert.Contains(t, email.Body, "Changed documents (53):\n")
+	assert.Contains(t, email.Body, "- and 3 more\n")
+
+	// Digests are sent as text alone
+	data, err := buildReportEmail("reports@example.com", email, start)
+	require.NoError(t, err)
+	message, err := mail.ReadMessage(bytes.NewReader(data))
+	require.NoError(t, err)
+	_, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	body, err := io.ReadAll(message.Body)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(body), "--"+params["boundary"]), "one part and the closing boundary")
+	assert.NotContains(t, string(body), "attachment")
+}
@@ -0,0 +1,287 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// Notebook question answering limits
+const (
+	// qaExcerptRunes bounds the excerpt of a source returned to the caller
+	qaExcerptRunes = 300
+	// qaContextRunes bounds how much of each chunk is put in the prompt
+	qaContextRunes = 2000
+	// qaMaxTerms bounds the question terms a keyword retrieval matches
+	qaMaxTerms = 8
+)
+
+// qaSystemPrompt instructs the model to answer only from the numbered
+// sources it is given
+const qaSystemPrompt = `You answer questions about a user's documents using only the numbered sources provided.
+Cite the sources you use by their number in square brackets, e.g. [1] or [2][3].
+If the sources do not contain the answer, say that you could not find it in the notebook. Do not make up facts.`
+
+// qaNoSourcesAnswer is the answer given when nothing in the notebook
+// matches the question; the model is not asked
+const qaNoSourcesAnswer = "I could not find anything in this notebook's documents that answers the question."
+
+// qaStopWords are left out of the terms a keyword retrieval matches
+var qaStopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "was": true, "were": true,
+	"what": true, "which": true, "who": true, "whom": true, "when": true, "where": true,
+	"why": true, "how": true, "does": true, "did": true, "can": true, "could": true,
+	"should": true, "would": true, "about": true, "with": true, "from": true, "this": true,
+	"that": true, "these": true, "those": true, "there": true, "their": true, "have": true,
+	"has": true, "had": true, "into": true, "any": true, "all": true, "not": true,
+}
+
+// NotebookQAService answers one-off questions about a notebook's documents
+// without an agent: it retrieves the notebook's most relevant chunks and
+// asks the LLM router to answer from them, citing them as sources
+type NotebookQAService struct {
+	neo4j     *database.Neo4jClient
+	notebooks *NotebookService
+	vectors   *VectorSearchService
+	chat      *routerChat
+	config    *config.QAConfig
+	logger    *logger.Logger
+}
+
+// NewNotebookQAService creates a notebook question answering service.
+// Without vectors, or with vector search disabled, chunks are retrieved by
+// keyword.
+func NewNotebookQAService(neo4j *database.Neo4jClient, notebooks *NotebookService, vectors *VectorSearchService, router *config.RouterConfig, cfg *config.QAConfig, log *logger.Logger) *NotebookQAService {
+	return &NotebookQAService{
+		neo4j:     neo4j,
+		notebooks: notebooks,
+		vectors:   vectors,
+		chat:      newRouterChat(router),
+		config:    cfg,
+		logger:    log.WithService("notebook_qa_service"),
+	}
+}
+
+// qaChunk is a chunk retrieved as context for an answer
+type qaChunk struct {
+	chunkID      string
+	documentID   string
+	documentName string
+	chunkNumber  int
+	page         int
+	content      string
+	score        float64
+}
+
+// Ask answers a question from the chunks of a notebook's documents. The
+// user's token is passed to the router unless it authenticates with the
+// service's own key.
+func (s *NotebookQAService) Ask(ctx context.Context, notebookID string, req models.NotebookAskRequest, userID, authToken string, spaceCtx *models.SpaceContext) (*models.NotebookAskResponse, error) {
+	if !s.chat.enabled() {
+		return nil, errors.ServiceUnavailable("Question answering requires the LLM router")
+	}
+	if !spaceCtx.CanRead() {
+		return nil, errors.Forbidden("Insufficient permissions to read the notebook")
+	}
+	if _, err := s.notebooks.GetNotebookByID(ctx, notebookID, userID, spaceCtx); err != nil {
+		return nil, err
+	}
+
+	chunks, retrieval, err := s.retrieve(ctx, notebookID, req.Question, userID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.NotebookAskResponse{
+		NotebookID: notebookID,
+		Question:   req.Question,
+		Sources:    make([]*models.NotebookAskSource, 0, len(chunks)),
+		Retrieval:  retrieval,
+		Model:      s.config.Model,
+	}
+	for i, chunk := range chunks {
+		response.Sources = append(response.Sources, &models.NotebookAskSource{
+			Index:        i + 1,
+			DocumentID:   chunk.documentID,
+			DocumentName: chunk.documentName,
+			ChunkID:      chunk.chunkID,
+			ChunkNumber:  chunk.chunkNumber,
+			Page:         chunk.page,
+			Score:        chunk.score,
+			Excerpt:      truncateRunes(strings.TrimSpace(chunk.content), qaExcerptRunes),
+		})
+	}
+	if len(chunks) == 0 {
+		response.Answer = qaNoSourcesAnswer
+		return response, nil
+	}
+
+	answer, err := s.complete(ctx, buildQAMessages(req.Question, chunks), authToken)
+	if err != nil {
+		s.logger.Error("Failed to answer notebook question", zap.String("notebook_id", notebookID), zap.Error(err))
+		return nil, errors.ExternalService("Failed to call LLM service", err)
+	}
+	response.Answer = answer.Content
+	response.TokensUsed = answer.TokensUsed
+	if answer.Model != "" {
+		response.Model = answer.Model
+	}
+
+	s.logger.Info("Answered notebook question",
+		zap.String("notebook_id", notebookID),
+		zap.String("retrieval", retrieval),
+		zap.Int("sources", len(chunks)),
+		zap.Int("tokens_used", answer.TokensUsed))
+	return response, nil
+}
+
+// retrieve returns the notebook's chunks most relevant to the question and
+// how they were found. Vector search is preferred; when it is unavailable
+// or fails, chunks are matched by the question's terms.
+func (s *NotebookQAService) retrieve(ctx context.Context, notebookID, question, userID string, spaceCtx *models.SpaceContext) ([]qaChunk, string, error) {
+	if s.vectors != nil && s.vectors.config.Enabled {
+		found, err := s.vectors.SemanticSearch(ctx, spaceCtx.SpaceID, userID, models.SemanticSearchRequest{
+			Query:       question,
+			Mode:        models.SemanticSearchHybrid,
+			TopK:        s.config.ContextChunks,
+			NotebookIDs: []string{notebookID},
+		})
+		if err == nil {
+			chunks := make([]qaChunk, 0, len(found.Hits))
+			for _, hit := range found.Hits {
+				if strings.TrimSpace(hit.Content) == "" {
+					continue
+				}
+				chunks = append(chunks, qaChunk{
+					chunkID:      hit.ChunkID,
+					documentID:   hit.Document.ID,
+					documentName: hit.Document.Name,
+					content:      hit.Content,
+					score:        hit.Score,
+				})
+			}
+			return chunks, "vector", nil
+		}
+		s.logger.Warn("Vector retrieval failed, matching chunks by keyword",
+			zap.String("notebook_id", notebookID),
+			zap.Error(err))
+	}
+
+	chunks, err := s.keywordChunks(ctx, notebookID, question, spaceCtx)
+	return chunks, "keyword", err
+}
+
+// keywordChunks returns the notebook's chunks containing the most question
+// terms. Chunks are stored under the document's ID or, for files processed
+// by AudiModal, its processing job ID; chunks mirrored by ChunkSyncService
+// also name the document.
+func (s *NotebookQAService) keywordChunks(ctx context.Context, notebookID, question string, spaceCtx *models.SpaceContext) ([]qaChunk, error) {
+	terms := questionTerms(question)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	ctx = database.WithQueryName(ctx, "notebook_qa.keyword_chunks")
+	query := `
+		MATCH (d:Document {notebook_id: $notebook_id, space_id: $space_id})
+		WHERE ` + database.SoftDeleteFilter(ctx, "d") + `
+		MATCH (c:Chunk {tenant_id: $tenant_id})
+		WHERE c.file_id = d.id OR c.file_id = d.processing_job_id OR c.document_id = d.id
+		WITH d, c, size([t IN $terms WHERE toLower(c.content) CONTAINS t]) AS matched
+		WHERE matched > 0
+		RETURN c.id AS chunk_id, d.id AS document_id, d.name AS document_name,
+		       c.chunk_number AS chunk_number, c.page_number AS page_number,
+		       c.content AS content, matched
+		ORDER BY matched DESC, d.name, c.chunk_number
+		LIMIT $limit
+	`
+	result, err := s.neo4j.ExecuteQuery(ctx, query, map[string]interface{}{
+		"notebook_id": notebookID,
+		"space_id":    spaceCtx.SpaceID,
+		"tenant_id":   spaceCtx.TenantID,
+		"terms":       terms,
+		"limit":       s.config.ContextChunks,
+	})
+	if err != nil {
+		s.logger.Error("Failed to retrieve notebook chunks", zap.String("notebook_id", notebookID), zap.Error(err))
+		return nil, errors.Database("Failed to retrieve notebook chunks", err)
+	}
+
+	chunks := make([]qaChunk, 0, len(result.Records))
+	for _, record := range result.Records {
+		chunks = append(chunks, recordToQAChunk(record, len(terms)))
+	}
+	return chunks, nil
+}
+
+// recordToQAChunk reads a chunk found by keyword, scored by the share of
+// the question's terms it contains
+func recordToQAChunk(record *neo4j.Record, terms int) qaChunk {
+	return qaChunk{
+		chunkID:      recordString(record, "chunk_id"),
+		documentID:   recordString(record, "document_id"),
+		documentName: recordString(record, "document_name"),
+		chunkNumber:  int(recordInt64(record, "chunk_number")),
+		page:         int(recordInt64(record, "page_number")),
+		content:      recordString(record, "content"),
+		score:        float64(recordInt64(record, "matched")) / float64(terms),
+	}
+}
+
+// questionTerms returns the distinct lower-cased words of a question worth
+// matching: stop words and words under three letters are left out
+func questionTerms(question string) []string {
+	words := strings.FieldsFunc(strings.ToLower(question), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	seen := make(map[string]bool, len(words))
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		if len([]rune(word)) < 3 || qaStopWords[word] || seen[word] {
+			continue
+		}
+		seen[word] = true
+		terms = append(terms, word)
+		if len(terms) == qaMaxTerms {
+			break
+		}
+	}
+	return terms
+}
+
+// buildQAMessages returns the chat messages asking the model to answer the
+// question from the numbered chunks
+func buildQAMessages(question string, chunks []qaChunk) []map[string]string {
+	var sources strings.Builder
+	for i, chunk := range chunks {
+		name := chunk.documentName
+		if chunk.page > 0 {
+			name += fmt.Sprintf(" (page %d)", chunk.page)
+		}
+		fmt.Fprintf(&sources, "[%d] %s\n%s\n\n", i+1, name, truncateRunes(strings.TrimSpace(chunk.content), qaContextRunes))
+	}
+	return []map[string]string{
+		{"role": "system", "content": qaSystemPrompt},
+		{"role": "user", "content": fmt.Sprintf("Sources:\n\n%sQuestion: %s", sources.String(), question)},
+	}
+}
+
+// complete asks the router for a chat completion of messages
+func (s *NotebookQAService) complete(ctx context.Context, messages []map[string]string, authToken string) (*LLMResponse, error) {
+	return s.chat.complete(ctx, routerChatRequest{
+		Model:     s.config.Model,
+		Provider:  s.config.Provider,
+		MaxTokens: s.config.MaxTokens,
+		Messages:  messages,
+	}, authToken)
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+)
+
+func TestQuestionTerms(t *testing.T) {
+	assert.Equal(t, []string{"main", "findings", "audit"}, questionTerms("What were the main findings of the Q3 audit? The AUDIT!"))
+	assert.Empty(t, questionTerms("who is it?"))
+}
+
+func TestNotebookQAComplete(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer user-token", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.Write([]byte(`{"model":"gpt-4o-mini-2024","choices":[{"message":{"content":" Revenue grew [1]. "}}],"usage":{"total_tokens":42}}`))
+	}))
+	defer server.Close()
+
+	router := &config.RouterConfig{Enabled: true}
+	router.Service.BaseURL = server.URL + "/"
+	router.Endpoints.ChatCompletions = "/v1/chat/completions"
+	service := NewNotebookQAService(nil, nil, nil, router, &config.QAConfig{Model: "gpt-4o-mini", Provider: "openai", MaxTokens: 100, ContextChunks: 4}, setupTestLogger(t))
+
+	messages := buildQAMessages("How did revenue change?", []qaChunk{{documentName: "Q3 report.pdf", content: "Revenue grew 12%."}})
+	answer, err := service.complete(context.Background(), messages, "user-token")
+	require.NoError(t, err)
+	assert.Equal(t, "Revenue grew [1].", answer.Content)
+	assert.Equal(t, 42, answer.TokensUsed)
+	assert.Equal(t, "gpt-4o-mini-2024", answer.Model)
+
+	assert.Equal(t, "gpt-4o-mini", body["model"])
+	sent := body["messages"].([]interface{})
+	require.Len(t, sent, 2)
+	prompt := sent[1].(map[string]interface{})["content"].(string)
+	assert.True(t, strings.Contains(prompt, "[1] Q3 report.pdf\nRevenue grew 12%."), prompt)
+	assert.True(t, strings.HasSuffix(prompt, "Question: How did revenue change?"), prompt)
+}
+
+func TestBuildQAMessagesCitesPages(t *testing.T) {
+	messages := buildQAMessages("What grew?", []qaChunk{
+		{documentName: "Q3 report.pdf", page: 4, content: "Revenue grew 12%."},
+		{documentName: "Notes.txt", content: "Costs fell."},
+	})
+	prompt := messages[1]["content"]
+	assert.Contains(t, prompt, "[1] Q3 report.pdf (page 4)\nRevenue grew 12%.")
+	assert.Contains(t, prompt, "[2] Notes.txt\nCosts fell.")
+}
@@ -0,0 +1,347 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// notebookShareMatch follows the shares of a notebook to the user $user_id,
+// shared with directly or as a member of a shared team. Users are matched
+// by internal or Keycloak ID, since handlers pass either.
+const notebookShareMatch = `-[share:SHARED_WITH]->(:User|Team)<-[:MEMBER_OF*0..1]-(sharee:User)
+	WHERE (sharee.id = $user_id OR sharee.keycloak_id = $user_id)`
+
+// notebookSharedWith returns the condition that notebook alias is shared
+// with the user $user_id
+func notebookSharedWith(alias string) string {
+	return "EXISTS { MATCH (" + alias + ")" + notebookShareMatch + " }"
+}
+
+// ShareNotebook shares a notebook with users and teams of its organization
+// and returns all its shares. Sharing again with the same user or team
+// changes its role. Only the notebook's owner shares it.
+func (s *NotebookService) ShareNotebook(ctx context.Context, notebookID string, req models.NotebookShareRequest, userID string, spaceCtx *models.SpaceContext) ([]*models.NotebookShare, error) {
+	notebook, err := s.GetNotebookByID(ctx, notebookID, userID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+	if notebook.OwnerID != userID {
+		return nil, errors.Forbidden("Only the notebook owner can share it")
+	}
+	if !spaceCtx.IsOrganizationSpace() {
+		return nil, errors.Validation("Notebooks are shared with the members and teams of an organization; personal notebooks cannot be shared", nil)
+	}
+	if len(req.UserIDs) == 0 && len(req.TeamIDs) == 0 {
+		return nil, errors.Validation("user_ids or team_ids is required", nil)
+	}
+	for _, id := range req.UserIDs {
+		if id == notebook.OwnerID {
+			return nil, errors.Validation("The notebook owner cannot be given a share", nil)
+		}
+	}
+	if req.UserIDs == nil {
+		req.UserIDs = []string{}
+	}
+	if req.TeamIDs == nil {
+		req.TeamIDs = []string{}
+	}
+
+	// Users must be members of the notebook's organization, and teams
+	// belong to it
+	query := `
+		OPTIONAL MATCH (u:User)-[:MEMBER_OF]->(:Organization {id: $organization_id})
+		WHERE u.id IN $user_ids
+		WITH collect(DISTINCT u.id) AS user_ids
+		OPTIONAL MATCH (t:Team {organization_id: $organization_id})
+		WHERE t.id IN $team_ids
+		RETURN user_ids, collect(DISTINCT t.id) AS team_ids
+	`
+	params := map[string]interface{}{
+		"organization_id": spaceCtx.SpaceID,
+		"user_ids":        req.UserIDs,
+		"team_ids":        req.TeamIDs,
+	}
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "notebook.share_targets"), query, params)
+	if err != nil {
+		return nil, errors.Database("Failed to find the users and teams to share with", err)
+	}
+	var foundUsers, foundTeams []interface{}
+	if len(result.Records) > 0 {
+		if value, ok := result.Records[0].Get("user_ids"); ok {
+			foundUsers, _ = value.([]interface{})
+		}
+		if value, ok := result.Records[0].Get("team_ids"); ok {
+			foundTeams, _ = value.([]interface{})
+		}
+	}
+	missingUsers, missingTeams := missingIDs(req.UserIDs, foundUsers), missingIDs(req.TeamIDs, foundTeams)
+	if len(missingUsers) > 0 || len(missingTeams) > 0 {
+		return nil, errors.NotFoundWithDetails("Users or teams not found in this organization", map[string]interface{}{
+			"user_ids": missingUsers,
+			"team_ids": missingTeams,
+		}).WithErrorCode(errors.CodeShareTargetNotFound)
+	}
+
+	query = `
+		MATCH (n:Notebook {id: $notebook_id, tenant_id: $tenant_id})
+		OPTIONAL MATCH (u:User) WHERE u.id IN $user_ids
+		OPTIONAL MATCH (t:Team {organization_id: $organization_id}) WHERE t.id IN $team_ids
+		WITH n, collect(DISTINCT u) + collect(DISTINCT t) AS targets
+		UNWIND targets AS target
+		MERGE (n)-[s:SHARED_WITH]->(target)
+		SET s.role = $role,
+		    s.shared_by = $shared_by,
+		    s.shared_at = $now
+	`
+	params["notebook_id"] = notebookID
+	params["tenant_id"] = spaceCtx.TenantID
+	params["role"] = req.Role
+	params["shared_by"] = userID
+	params["now"] = time.Now().UTC()
+	if _, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "notebook.share"), query, params); err != nil {
+		s.logger.Error("Failed to share notebook", zap.String("notebook_id", notebookID), zap.Error(err))
+		return nil, errors.Database("Failed to share notebook", err)
+	}
+
+	publishDomainEvent(ctx, s.events, s.logger,
+		NewNotebookEvent(EventNotebookShared, notebookID, userID, map[string]interface{}{
+			"space_id":  notebook.SpaceID,
+			"tenant_id": notebook.TenantID,
+			"role":      req.Role,
+			"user_ids":  req.UserIDs,
+			"team_ids":  req.TeamIDs,
+		}))
+
+	s.logger.Info("Notebook shared",
+		zap.String("notebook_id", notebookID),
+		zap.String("role", req.Role),
+		zap.Int("users", len(req.UserIDs)),
+		zap.Int("teams", len(req.TeamIDs)))
+
+	return s.listShares(ctx, notebookID, spaceCtx.TenantID)
+}
+
+// missingIDs returns the IDs of requested not in found
+func missingIDs(requested []string, found []interface{}) []string {
+	seen := make(map[string]bool, len(found))
+	for _, id := range found {
+		if s, ok := id.(string); ok {
+			seen[s] = true
+		}
+	}
+	missing := []string{}
+	for _, id := range requested {
+		if !seen[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+// ListNotebookShares returns the users and teams a notebook is shared with
+func (s *NotebookService) ListNotebookShares(ctx context.Context, notebookID, userID string, spaceCtx *models.SpaceContext) ([]*models.NotebookShare, error) {
+	if _, err := s.GetNotebookByID(ctx, notebookID, userID, spaceCtx); err != nil {
+		return nil, err
+	}
+	return s.listShares(ctx, notebookID, spaceCtx.TenantID)
+}
+
+// listShares returns the shares of a notebook, oldest first. Shares made
+// before roles existed are viewers.
+func (s *NotebookService) listShares(ctx context.Context, notebookID, tenantID string) ([]*models.NotebookShare, error) {
+	query := `
+		MATCH (n:Notebook {id: $notebook_id, tenant_id: $tenant_id})-[s:SHARED_WITH]->(target:User|Team)
+		RETURN CASE WHEN target:Team THEN 'team' ELSE 'user' END AS share_type,
+		       target.id AS share_id,
+		       coalesce(target.full_name, target.name, target.username) AS name,
+		       coalesce(s.role, 'viewer') AS role,
+		       s.shared_by AS shared_by,
+		       s.shared_at AS shared_at
+		ORDER BY shared_at, share_id
+	`
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "notebook.list_shares"), query, map[string]interface{}{
+		"notebook_id": notebookID,
+		"tenant_id":   tenantID,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to list notebook shares", err)
+	}
+
+	shares := make([]*models.NotebookShare, 0, len(result.Records))
+	for _, record := range result.Records {
+		shares = append(shares, recordToNotebookShare(notebookID, record))
+	}
+	return shares, nil
+}
+
+// recordToNotebookShare converts a row of listShares
+func recordToNotebookShare(notebookID string, record *neo4j.Record) *models.NotebookShare {
+	return &models.NotebookShare{
+		NotebookID: notebookID,
+		ShareType:  recordString(record, "share_type"),
+		ShareID:    recordString(record, "share_id"),
+		Name:       recordString(record, "name"),
+		Role:       recordString(record, "role"),
+		SharedBy:   recordString(record, "shared_by"),
+		SharedAt:   recordTime(record, "shared_at"),
+	}
+}
+
+// UnshareNotebook removes the share of a notebook with a user or team.
+// Only the notebook's owner unshares it.
+func (s *NotebookService) UnshareNotebook(ctx context.Context, notebookID, shareType, shareID, userID string, spaceCtx *models.SpaceContext) error {
+	notebook, err := s.GetNotebookByID(ctx, notebookID, userID, spaceCtx)
+	if err != nil {
+		return err
+	}
+	if notebook.OwnerID != userID {
+		return errors.Forbidden("Only the notebook owner can unshare it")
+	}
+
+	label := "User"
+	if shareType == models.NotebookShareTeam {
+		label = "Team"
+	}
+	query := `
+		MATCH (n:Notebook {id: $notebook_id, tenant_id: $tenant_id})-[s:SHARED_WITH]->(:` + label + ` {id: $share_id})
+		DELETE s
+		RETURN count(*) AS removed
+	`
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "notebook.unshare"), query, map[string]interface{}{
+		"notebook_id": notebookID,
+		"tenant_id":   spaceCtx.TenantID,
+		"share_id":    shareID,
+	})
+	if err != nil {
+		return errors.Database("Failed to unshare notebook", err)
+	}
+	if recordInt(result.Records, "removed") == 0 {
+		return errors.NotFoundWithDetails("The notebook is not shared with that user or team", map[string]interface{}{
+			"share_type": shareType,
+			"share_id":   shareID,
+		}).WithErrorCode(errors.CodeShareNotFound)
+	}
+
+	publishDomainEvent(ctx, s.events, s.logger,
+		NewNotebookEvent(EventNotebookUnshared, notebookID, userID, map[string]interface{}{
+			"space_id":   notebook.SpaceID,
+			"tenant_id":  notebook.TenantID,
+			"share_type": shareType,
+			"share_id":   shareID,
+		}))
+	return nil
+}
+
+// NotebookRole returns the highest role a notebook is shared with the user
+// with, directly or through their teams, or "" when it is not shared with
+// them
+func (s *NotebookService) NotebookRole(ctx context.Context, tenantID, notebookID, userID string) (string, error) {
+	query := `
+		MATCH (n:Notebook {id: $notebook_id, tenant_id: $tenant_id})` + notebookShareMatch + `
+		RETURN collect(coalesce(share.role, 'viewer')) AS roles
+	`
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "notebook.share_role"), query, map[string]interface{}{
+		"notebook_id": notebookID,
+		"tenant_id":   tenantID,
+		"user_id":     userID,
+	})
+	if err != nil {
+		return "", errors.Database("Failed to check notebook shares", err)
+	}
+	if len(result.Records) == 0 {
+		return "", nil
+	}
+	return highestRecordRole(result.Records[0]), nil
+}
+
+// ShareSubject returns a user as a subject holding the role a notebook is
+// shared with them with, on the notebook and its documents; it holds no
+// role when the notebook is not shared with them or the role can't be read
+func (s *NotebookService) ShareSubject(ctx context.Context, tenantID, notebookID, userID string) models.Subject {
+	subject := models.Subject{UserID: userID}
+	role, err := s.NotebookRole(ctx, tenantID, notebookID, userID)
+	if err != nil {
+		s.logger.Warn("Failed to read notebook share role", zap.String("notebook_id", notebookID), zap.Error(err))
+	}
+	if role != "" {
+		subject.Roles = []string{"notebook:" + role}
+	}
+	return subject
+}
+
+// highestRecordRole returns the highest of the roles collected in a row
+func highestRecordRole(record *neo4j.Record) string {
+	value, _ := record.Get("roles")
+	values, _ := value.([]interface{})
+	roles := make([]string, 0, len(values))
+	for _, role := range values {
+		if role, ok := role.(string); ok {
+			roles = append(roles, role)
+		}
+	}
+	return models.HighestNotebookRole(roles)
+}
+
+// ListSharedNotebooks returns the notebooks of the space shared with the
+// user, directly or through their teams, most recently shared first
+func (s *NotebookService) ListSharedNotebooks(ctx context.Context, userID string, spaceCtx *models.SpaceContext, limit, offset int) (*models.SharedNotebookListResponse, error) {
+	if !spaceCtx.CanRead() {
+		return nil, errors.Forbidden("Insufficient permissions to read notebooks")
+	}
+	limit, offset = pagination.Clamp(limit, offset)
+
+	query := `
+		MATCH (n:Notebook {tenant_id: $tenant_id, space_id: $space_id})` + notebookShareMatch + `
+		  AND ` + database.NotDeleted("n") + `
+		WITH n, collect(coalesce(share.role, 'viewer')) AS roles, max(share.shared_at) AS shared_at
+		RETURN n.id AS id, n.name AS name, n.description AS description, n.owner_id AS owner_id,
+		       n.document_count AS document_count, n.updated_at AS updated_at,
+		       roles, shared_at
+		ORDER BY shared_at DESC, id
+		SKIP $offset LIMIT $limit
+	`
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "notebook.list_shared"), query, map[string]interface{}{
+		"tenant_id": spaceCtx.TenantID,
+		"space_id":  spaceCtx.SpaceID,
+		"user_id":   userID,
+		"offset":    offset,
+		"limit":     limit + 1,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to list shared notebooks", err)
+	}
+
+	notebooks := make([]*models.SharedNotebook, 0, len(result.Records))
+	for _, record := range result.Records {
+		notebooks = append(notebooks, recordToSharedNotebook(record))
+	}
+	notebooks, hasMore := pagination.Trim(notebooks, limit)
+	return &models.SharedNotebookListResponse{
+		Notebooks: notebooks,
+		Limit:     limit,
+		Offset:    offset,
+		HasMore:   hasMore,
+	}, nil
+}
+
+// recordToSharedNotebook converts a row of ListSharedNotebooks
+func recordToSharedNotebook(record *neo4j.Record) *models.SharedNotebook {
+	return &models.SharedNotebook{
+		ID:            recordString(record, "id"),
+		Name:          recordString(record, "name"),
+		Description:   recordString(record, "description"),
+		OwnerID:       recordString(record, "owner_id"),
+		DocumentCount: int(recordInt64(record, "document_count")),
+		Role:          highestRecordRole(record),
+		SharedAt:      recordTime(record, "shared_at"),
+		UpdatedAt:     recordTime(record, "updated_at"),
+	}
+}
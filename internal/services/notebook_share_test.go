@@ -0,0 +1,393 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+func TestNotebookRoles(t *testing.T) {
+	assert.Equal(t, models.NotebookRoleEditor, models.HighestNotebookRole([]string{"viewer", "editor", "commenter"}))
+	assert.Equal(t, models.NotebookRoleCommenter, models.HighestNotebookRole([]string{"viewer", "commenter"}))
+	assert.Empty(t, models.HighestNotebookRole(nil))
+
+	assert.True(t, models.NotebookRoleAtLeast(models.NotebookRoleEditor, models.NotebookRoleEditor))
+	assert.True(t, models.NotebookRoleAtLeast(models.NotebookRoleCommenter, models.NotebookRoleViewer))
+	assert.False(t, models.NotebookRoleAtLeast(models.NotebookRoleCommenter, models.NotebookRoleEditor))
+	assert.False(t, models.NotebookRoleAtLeast("", models.NotebookRoleViewer))
+}
+
+func TestMissingShareTargets(t *testing.T) {
+	assert.Equal(t, []string{"user-2"}, missingIDs([]string{"user-1", "user-2"}, []interface{}{"user-1"}))
+	assert.Empty(t, missingIDs([]string{"team-1"}, []interface{}{"team-1"}))
+}
+
+func TestSharedNotebookHasHighestRole(t *testing.T) {
+	sharedAt := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	notebook := recordToSharedNotebook(&neo4j.Record{
+		Keys:   []string{"id", "name", "description", "owner_id", "document_count", "updated_at", "roles", "shared_at"},
+		Values: []interface{}{"nb-1", "Research", nil, "user-1", int64(4), nil, []interface{}{"viewer", "editor"}, sharedAt},
+	})
+
+	assert.Equal(t, &models.SharedNotebook{
+		ID:            "nb-1",
+		Name:          "Research",
+		OwnerID:       "user-1",
+		DocumentCount: 4,
+		Role:          models.NotebookRoleEditor,
+		SharedAt:      sharedAt,
+	}, notebook)
+}
+
+func TestNotebookSharedWithFollowsTeams(t *testing.T) {
+	condition := notebookSharedWith("nb")
+	assert.Contains(t, condition, "MATCH (nb)-[share:SHARED_WITH]->(:User|Team)<-[:MEMBER_OF*0..1]-(sharee:User)")
+	assert.Contains(t, condition, "sharee.keycloak_id = $user_id")
+}
+
+// shareGraph answers the notebook sharing queries from memory: notebooks,
+// the members of organizations and teams, and the shares between them
+type shareGraph struct {
+	t         *testing.T
+	notebooks map[string]*models.Notebook
+	members   map[string][]string            // Organization → users
+	teams     map[string]shareGraphTeam      // By ID
+	shares    map[string]map[string][]string // Notebook → "user:id" or "team:id" → role, shared_by
+}
+
+type shareGraphTeam struct {
+	organizationID string
+	members        []string
+}
+
+func newShareGraph(t *testing.T) *shareGraph {
+	return &shareGraph{
+		t: t,
+		notebooks: map[string]*models.Notebook{
+			"nb-1":     {ID: "nb-1", Name: "Research", OwnerID: "owner-1", Visibility: "private", SpaceType: models.SpaceTypeOrganization, SpaceID: "org-1", TenantID: "tenant-1"},
+			"nb-other": {ID: "nb-other", Name: "Elsewhere", OwnerID: "owner-1", Visibility: "private", SpaceType: models.SpaceTypeOrganization, SpaceID: "org-2", TenantID: "tenant-1"},
+		},
+		members: map[string][]string{
+			"org-1": {"owner-1", "viewer-1", "commenter-1", "editor-1", "member-1"},
+			"org-2": {"outsider-1"},
+		},
+		teams: map[string]shareGraphTeam{
+			"team-1": {organizationID: "org-1", members: []string{"editor-1"}},
+			"team-2": {organizationID: "org-2", members: []string{"outsider-1"}},
+		},
+		shares: map[string]map[string][]string{},
+	}
+}
+
+func (g *shareGraph) isUser(id string) bool {
+	for _, users := range g.members {
+		if containsString(users, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// roles returns the roles a notebook is shared with a user with, directly
+// or through their teams
+func (g *shareGraph) roles(notebookID, userID string) []interface{} {
+	roles := []interface{}{}
+	for target, share := range g.shares[notebookID] {
+		kind, id, _ := strings.Cut(target, ":")
+		if (kind == "user" && id == userID) || (kind == "team" && containsString(g.teams[id].members, userID)) {
+			roles = append(roles, share[0])
+		}
+	}
+	return roles
+}
+
+func (g *shareGraph) handle(query string, params map[string]interface{}) ([]string, [][]interface{}) {
+	str := func(name string) string {
+		value, _ := params[name].(string)
+		return value
+	}
+	strs := func(name string) []string {
+		values, _ := params[name].([]interface{})
+		ids := make([]string, 0, len(values))
+		for _, value := range values {
+			ids = append(ids, value.(string))
+		}
+		return ids
+	}
+
+	switch {
+	case queryHas(query, "OPTIONAL MATCH (n)-[:OWNED_BY]->(owner:User)"):
+		keys := []string{"n.id", "n.name", "n.visibility", "n.owner_id", "n.space_type", "n.space_id", "n.tenant_id"}
+		n := g.notebooks[str("notebook_id")]
+		if n == nil || n.TenantID != str("tenant_id") {
+			return keys, nil
+		}
+		return keys, [][]interface{}{{n.ID, n.Name, n.Visibility, n.OwnerID, string(n.SpaceType), n.SpaceID, n.TenantID}}
+
+	case queryHas(query, "MATCH (u:User)-[:MEMBER_OF]->(:Organization {id: $organization_id})"):
+		users, teams := []interface{}{}, []interface{}{}
+		for _, id := range strs("user_ids") {
+			if containsString(g.members[str("organization_id")], id) {
+				users = append(users, id)
+			}
+		}
+		for _, id := range strs("team_ids") {
+			if team, ok := g.teams[id]; ok && team.organizationID == str("organization_id") {
+				teams = append(teams, id)
+			}
+		}
+		return []string{"user_ids", "team_ids"}, [][]interface{}{{users, teams}}
+
+	case queryHas(query, "MERGE (n)-[s:SHARED_WITH]->(target)"):
+		notebookID := str("notebook_id")
+		if g.shares[notebookID] == nil {
+			g.shares[notebookID] = map[string][]string{}
+		}
+		for _, id := range strs("user_ids") {
+			if g.isUser(id) {
+				g.shares[notebookID]["user:"+id] = []string{str("role"), str("shared_by")}
+			}
+		}
+		for _, id := range strs("team_ids") {
+			if g.teams[id].organizationID == str("organization_id") {
+				g.shares[notebookID]["team:"+id] = []string{str("role"), str("shared_by")}
+			}
+		}
+		return nil, nil
+
+	case queryHas(query, "RETURN CASE WHEN target:Team THEN 'team' ELSE 'user' END AS share_type"):
+		targets := make([]string, 0)
+		for target := range g.shares[str("notebook_id")] {
+			targets = append(targets, target)
+		}
+		sort.Strings(targets)
+		rows := make([][]interface{}, 0, len(targets))
+		for _, target := range targets {
+			kind, id, _ := strings.Cut(target, ":")
+			share := g.shares[str("notebook_id")][target]
+			rows = append(rows, []interface{}{kind, id, nil, share[0], share[1], nil})
+		}
+		return []string{"share_type", "share_id", "name", "role", "shared_by", "shared_at"}, rows
+
+	case queryHas(query, "DELETE s", "RETURN count(*) AS removed"):
+		target := "user:" + str("share_id")
+		if queryHas(query, "(:Team {id: $share_id})") {
+			target = "team:" + str("share_id")
+		}
+		removed := 0
+		if _, ok := g.shares[str("notebook_id")][target]; ok {
+			delete(g.shares[str("notebook_id")], target)
+			removed = 1
+		}
+		return []string{"removed"}, [][]interface{}{{removed}}
+
+	case queryHas(query, "RETURN collect(coalesce(share.role, 'viewer')) AS roles"):
+		return []string{"roles"}, [][]interface{}{{g.roles(str("notebook_id"), str("user_id"))}}
+
+	case queryHas(query, "ORDER BY shared_at DESC, id"):
+		ids := make([]string, 0)
+		for id, n := range g.notebooks {
+			if n.TenantID == str("tenant_id") && n.SpaceID == str("space_id") && len(g.roles(id, str("user_id"))) > 0 {
+				ids = append(ids, id)
+			}
+		}
+		sort.Strings(ids)
+		rows := make([][]interface{}, 0, len(ids))
+		for _, id := range ids {
+			n := g.notebooks[id]
+			rows = append(rows, []interface{}{n.ID, n.Name, nil, n.OwnerID, 0, nil, g.roles(id, str("user_id")), nil})
+		}
+		return []string{"id", "name", "description", "owner_id", "document_count", "updated_at", "roles", "shared_at"}, rows
+
+	case queryHas(query, "RETURN count(n) > 0 as has_access"):
+		n := g.notebooks[str("notebook_id")]
+		access := n != nil && (n.Visibility == "public" || n.OwnerID == str("user_id") || len(g.roles(n.ID, str("user_id"))) > 0)
+		return []string{"has_access"}, [][]interface{}{{access}}
+	}
+	g.t.Errorf("unexpected query: %s", query)
+	return nil, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// newShareTestServices returns notebook and document services reading
+// graph
+func newShareTestServices(t *testing.T, graph *shareGraph) (*NotebookService, *DocumentService) {
+	client := newFakeNeo4j(t, graph.handle)
+	log := setupTestLogger(t)
+	notebooks := NewNotebookService(client, log)
+	return notebooks, &DocumentService{neo4j: client, notebookService: notebooks, logger: log}
+}
+
+// orgSpace is the organization space of nb-1 as held by a member
+func orgSpace(userID string) *models.SpaceContext {
+	return &models.SpaceContext{
+		SpaceType:      models.SpaceTypeOrganization,
+		SpaceID:        "org-1",
+		TenantID:       "tenant-1",
+		OrganizationID: "org-1",
+		UserID:         userID,
+		UserRole:       "member",
+		Permissions:    []string{"read", "write", "create", "update"},
+	}
+}
+
+func assertAPIError(t *testing.T, err error, status int, code string) *errors.APIError {
+	t.Helper()
+	apiErr, ok := err.(*errors.APIError)
+	require.True(t, ok, "expected an API error, got %v", err)
+	assert.Equal(t, status, apiErr.StatusCode)
+	if code != "" {
+		assert.Equal(t, code, apiErr.ErrorCode)
+	}
+	return apiErr
+}
+
+func TestShareNotebook(t *testing.T) {
+	graph := newShareGraph(t)
+	notebooks, _ := newShareTestServices(t, graph)
+	ctx := context.Background()
+	share := func(userID string, req models.NotebookShareRequest) ([]*models.NotebookShare, error) {
+		return notebooks.ShareNotebook(ctx, "nb-1", req, userID, orgSpace(userID))
+	}
+
+	shares, err := share("owner-1", models.NotebookShareRequest{UserIDs: []string{"viewer-1"}, TeamIDs: []string{"team-1"}, Role: models.NotebookRoleViewer})
+	require.NoError(t, err)
+	require.Len(t, shares, 2)
+	assert.Equal(t, "team", shares[0].ShareType)
+	assert.Equal(t, "team-1", shares[0].ShareID)
+	assert.Equal(t, "user", shares[1].ShareType)
+	assert.Equal(t, "viewer-1", shares[1].ShareID)
+	assert.Equal(t, models.NotebookRoleViewer, shares[1].Role)
+	assert.Equal(t, "owner-1", shares[1].SharedBy)
+
+	shares, err = share("owner-1", models.NotebookShareRequest{TeamIDs: []string{"team-1"}, Role: models.NotebookRoleEditor})
+	require.NoError(t, err)
+	require.Len(t, shares, 2, "sharing again changes the role")
+	assert.Equal(t, models.NotebookRoleEditor, shares[0].Role)
+
+	_, err = share("member-1", models.NotebookShareRequest{UserIDs: []string{"commenter-1"}, Role: models.NotebookRoleViewer})
+	assertAPIError(t, err, http.StatusForbidden, "")
+
+	_, err = share("owner-1", models.NotebookShareRequest{UserIDs: []string{"commenter-1", "outsider-1", "ghost-1"}, TeamIDs: []string{"team-2"}, Role: models.NotebookRoleViewer})
+	apiErr := assertAPIError(t, err, http.StatusNotFound, errors.CodeShareTargetNotFound)
+	assert.Equal(t, []string{"outsider-1", "ghost-1"}, apiErr.Details["user_ids"], "users must be members of the notebook's organization")
+	assert.Equal(t, []string{"team-2"}, apiErr.Details["team_ids"])
+	assert.NotContains(t, graph.shares["nb-1"], "user:commenter-1", "nothing is shared when a target is missing")
+
+	_, err = share("owner-1", models.NotebookShareRequest{UserIDs: []string{"owner-1"}, Role: models.NotebookRoleViewer})
+	assert.True(t, errors.IsValidation(err), "the owner cannot be given a share")
+	_, err = share("owner-1", models.NotebookShareRequest{Role: models.NotebookRoleViewer})
+	assert.True(t, errors.IsValidation(err))
+
+	_, err = notebooks.ShareNotebook(ctx, "nb-missing", models.NotebookShareRequest{UserIDs: []string{"viewer-1"}, Role: models.NotebookRoleViewer}, "owner-1", orgSpace("owner-1"))
+	assertAPIError(t, err, http.StatusNotFound, errors.CodeNotebookNotFound)
+
+	personal := &models.SpaceContext{SpaceType: models.SpaceTypePersonal, SpaceID: "org-1", TenantID: "tenant-1", UserID: "owner-1", UserRole: "owner"}
+	_, err = notebooks.ShareNotebook(ctx, "nb-1", models.NotebookShareRequest{UserIDs: []string{"viewer-1"}, Role: models.NotebookRoleViewer}, "owner-1", personal)
+	assert.True(t, errors.IsValidation(err), "personal notebooks cannot be shared")
+}
+
+func TestUnshareNotebook(t *testing.T) {
+	graph := newShareGraph(t)
+	notebooks, _ := newShareTestServices(t, graph)
+	ctx := context.Background()
+	_, err := notebooks.ShareNotebook(ctx, "nb-1", models.NotebookShareRequest{UserIDs: []string{"viewer-1"}, TeamIDs: []string{"team-1"}, Role: models.NotebookRoleViewer}, "owner-1", orgSpace("owner-1"))
+	require.NoError(t, err)
+
+	err = notebooks.UnshareNotebook(ctx, "nb-1", models.NotebookShareUser, "viewer-1", "member-1", orgSpace("member-1"))
+	assertAPIError(t, err, http.StatusForbidden, "")
+
+	require.NoError(t, notebooks.UnshareNotebook(ctx, "nb-1", models.NotebookShareUser, "viewer-1", "owner-1", orgSpace("owner-1")))
+	role, err := notebooks.NotebookRole(ctx, "tenant-1", "nb-1", "viewer-1")
+	require.NoError(t, err)
+	assert.Empty(t, role)
+
+	err = notebooks.UnshareNotebook(ctx, "nb-1", models.NotebookShareUser, "viewer-1", "owner-1", orgSpace("owner-1"))
+	assertAPIError(t, err, http.StatusNotFound, errors.CodeShareNotFound)
+	err = notebooks.UnshareNotebook(ctx, "nb-1", models.NotebookShareUser, "team-1", "owner-1", orgSpace("owner-1"))
+	assertAPIError(t, err, http.StatusNotFound, errors.CodeShareNotFound)
+
+	require.NoError(t, notebooks.UnshareNotebook(ctx, "nb-1", models.NotebookShareTeam, "team-1", "owner-1", orgSpace("owner-1")))
+	assert.Empty(t, graph.shares["nb-1"])
+}
+
+func TestListSharedNotebooks(t *testing.T) {
+	graph := newShareGraph(t)
+	notebooks, _ := newShareTestServices(t, graph)
+	ctx := context.Background()
+	_, err := notebooks.ShareNotebook(ctx, "nb-1", models.NotebookShareRequest{UserIDs: []string{"editor-1"}, Role: models.NotebookRoleViewer}, "owner-1", orgSpace("owner-1"))
+	require.NoError(t, err)
+	_, err = notebooks.ShareNotebook(ctx, "nb-1", models.NotebookShareRequest{TeamIDs: []string{"team-1"}, Role: models.NotebookRoleEditor}, "owner-1", orgSpace("owner-1"))
+	require.NoError(t, err)
+	graph.shares["nb-other"] = map[string][]string{"user:editor-1": {models.NotebookRoleViewer, "owner-1"}}
+
+	list, err := notebooks.ListSharedNotebooks(ctx, "editor-1", orgSpace("editor-1"), 10, 0)
+	require.NoError(t, err)
+	require.Len(t, list.Notebooks, 1, "notebooks of other spaces are left out")
+	assert.Equal(t, "nb-1", list.Notebooks[0].ID)
+	assert.Equal(t, models.NotebookRoleEditor, list.Notebooks[0].Role, "the highest role, through a team, applies")
+	assert.False(t, list.HasMore)
+
+	list, err = notebooks.ListSharedNotebooks(ctx, "member-1", orgSpace("member-1"), 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, list.Notebooks)
+}
+
+func TestNotebookShareRolesAuthorizeDocuments(t *testing.T) {
+	graph := newShareGraph(t)
+	notebooks, documents := newShareTestServices(t, graph)
+	ctx := context.Background()
+	for user, role := range map[string]string{"viewer-1": models.NotebookRoleViewer, "commenter-1": models.NotebookRoleCommenter} {
+		_, err := notebooks.ShareNotebook(ctx, "nb-1", models.NotebookShareRequest{UserIDs: []string{user}, Role: role}, "owner-1", orgSpace("owner-1"))
+		require.NoError(t, err)
+	}
+	_, err := notebooks.ShareNotebook(ctx, "nb-1", models.NotebookShareRequest{TeamIDs: []string{"team-1"}, Role: models.NotebookRoleEditor}, "owner-1", orgSpace("owner-1"))
+	require.NoError(t, err)
+
+	document := &models.Document{ID: "doc-1", NotebookID: "nb-1", OwnerID: "owner-1", TenantID: "tenant-1"}
+	allowed := map[string][]string{
+		"owner-1":     {models.ActionDocumentRead, models.ActionDocumentUpdate, models.ActionDocumentDelete},
+		"viewer-1":    {models.ActionDocumentRead},
+		"commenter-1": {models.ActionDocumentRead},
+		"editor-1":    {models.ActionDocumentRead, models.ActionDocumentUpdate},
+		"member-1":    {},
+	}
+	for user, actions := range allowed {
+		for _, action := range []string{models.ActionDocumentRead, models.ActionDocumentUpdate, models.ActionDocumentDelete} {
+			err := documents.authorizeDocument(ctx, document, user, action)
+			if containsString(actions, action) {
+				assert.NoError(t, err, "%s may %s", user, action)
+			} else {
+				assertAPIError(t, err, http.StatusForbidden, errors.CodePermissionDenied)
+			}
+		}
+
+		access, err := documents.verifyNotebookAccess(ctx, "nb-1", user)
+		require.NoError(t, err)
+		assert.Equal(t, len(actions) > 0, access, "%s can open the notebook", user)
+	}
+
+	require.NoError(t, notebooks.UnshareNotebook(ctx, "nb-1", models.NotebookShareUser, "viewer-1", "owner-1", orgSpace("owner-1")))
+	assert.Error(t, documents.authorizeDocument(ctx, document, "viewer-1", models.ActionDocumentRead), "an unshared user loses access")
+	access, err := documents.verifyNotebookAccess(ctx, "nb-1", "viewer-1")
+	require.NoError(t, err)
+	assert.False(t, access)
+}
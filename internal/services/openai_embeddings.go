@@ -67,7 +67,8 @@ func NewOpenAIEmbeddingProvider(config *config.OpenAIConfig, log *logger.Logger)
 		dimensions: dimensions,
 		baseURL:    baseURL,
 		httpClient: &http.Client{
-			Timeout: time.Duration(config.TimeoutSeconds) * time.Second,
+			Timeout:   time.Duration(config.TimeoutSeconds) * time.Second,
+			Transport: logger.NewRequestIDTransport(nil),
 		},
 		log: log,
 	}
@@ -13,6 +13,7 @@ import (
 	"github.com/Tributary-ai-services/aether-be/internal/database"
 	"github.com/Tributary-ai-services/aether-be/internal/logger"
 	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
 	"github.com/Tributary-ai-services/aether-be/pkg/errors"
 )
 
@@ -20,6 +21,8 @@ import (
 type OrganizationService struct {
 	neo4j       *database.Neo4jClient
 	audiModal   *AudiModalService
+	authz       *AuthorizationService
+	legalHolds  *LegalHoldService
 	logger      *logger.Logger
 }
 
@@ -32,6 +35,19 @@ func NewOrganizationService(neo4j *database.Neo4jClient, audiModal *AudiModalSer
 	}
 }
 
+// SetAuthorizationService sets the service deciding what members may do
+// and which custom roles they can be given; without it only the built-in
+// roles exist
+func (s *OrganizationService) SetAuthorizationService(authz *AuthorizationService) {
+	s.authz = authz
+}
+
+// SetLegalHolds sets the service keeping organizations with content under
+// legal hold from being deleted
+func (s *OrganizationService) SetLegalHolds(legalHolds *LegalHoldService) {
+	s.legalHolds = legalHolds
+}
+
 // CreateOrganization creates a new organization
 func (s *OrganizationService) CreateOrganization(ctx context.Context, req models.OrganizationCreateRequest, createdBy string) (*models.Organization, error) {
 	// Check if slug is already taken
@@ -287,7 +303,7 @@ func (s *OrganizationService) GetOrganization(ctx context.Context, orgID string,
 	if len(records) == 0 {
 		return nil, errors.NotFoundWithDetails("Organization not found", map[string]interface{}{
 			"org_id": orgID,
-		})
+		}).WithErrorCode(errors.CodeOrganizationNotFound)
 	}
 
 	record := records[0]
@@ -357,18 +373,10 @@ func (s *OrganizationService) GetOrganizations(ctx context.Context, userID strin
 // UpdateOrganization updates an organization
 func (s *OrganizationService) UpdateOrganization(ctx context.Context, orgID string, req models.OrganizationUpdateRequest, userID string) (*models.Organization, error) {
 	// Check if user has permission to update
-	userRole, err := s.getUserRoleInOrganization(ctx, orgID, userID)
-	if err != nil {
+	if _, err := s.AuthorizeMember(ctx, orgID, userID, models.ActionOrganizationUpdate); err != nil {
 		return nil, err
 	}
 
-	if userRole != "owner" && userRole != "admin" {
-		return nil, errors.ForbiddenWithDetails("Insufficient permissions to update organization", map[string]interface{}{
-			"user_role": userRole,
-			"org_id":    orgID,
-		})
-	}
-
 	// Check slug uniqueness if being updated
 	if req.Slug != nil {
 		exists, err := s.organizationSlugExistsExcluding(ctx, *req.Slug, orgID)
@@ -485,7 +493,7 @@ func (s *OrganizationService) UpdateOrganization(ctx context.Context, orgID stri
 	if len(records) == 0 {
 		return nil, errors.NotFoundWithDetails("Organization not found", map[string]interface{}{
 			"org_id": orgID,
-		})
+		}).WithErrorCode(errors.CodeOrganizationNotFound)
 	}
 
 	org, err := s.recordToOrganization(records[0])
@@ -499,20 +507,142 @@ func (s *OrganizationService) UpdateOrganization(ctx context.Context, orgID stri
 
 // DeleteOrganization deletes an organization
 func (s *OrganizationService) DeleteOrganization(ctx context.Context, orgID string, userID string) error {
-	// Check if user has permission to delete
-	userRole, err := s.getUserRoleInOrganization(ctx, orgID, userID)
-	if err != nil {
+	if err := s.CheckCanDeleteOrganization(ctx, orgID, userID); err != nil {
+		return err
+	}
+
+	return s.deleteOrganizationInternal(ctx, orgID)
+}
+
+// CheckCanDeleteOrganization checks that the user's role may delete the
+// organization, which only owners' does among the built-in roles, and that
+// no legal hold is active in its spaces
+func (s *OrganizationService) CheckCanDeleteOrganization(ctx context.Context, orgID string, userID string) error {
+	if _, err := s.AuthorizeMember(ctx, orgID, userID, models.ActionOrganizationDelete); err != nil {
 		return err
 	}
+	return s.legalHolds.CheckOrganization(ctx, orgID)
+}
+
+// AuthorizeMember checks that the role of a member of an organization
+// grants action on it, and returns the role. Users who are not members are
+// refused.
+func (s *OrganizationService) AuthorizeMember(ctx context.Context, orgID, userID, action string) (string, error) {
+	role, err := s.getUserRoleInOrganization(ctx, orgID, userID)
+	if err != nil {
+		return "", err
+	}
+	if err := s.AuthorizeRole(ctx, orgID, userID, role, action); err != nil {
+		return "", err
+	}
+	return role, nil
+}
+
+// AuthorizeRole checks that role, held by userID in an organization, grants
+// action on it
+func (s *OrganizationService) AuthorizeRole(ctx context.Context, orgID, userID, role, action string) error {
+	subject := models.Subject{UserID: userID, OrganizationID: orgID, Roles: []string{role}}
+	return s.authz.Authorize(ctx, subject, action, models.Resource{Type: models.ResourceOrganization, ID: orgID})
+}
+
+// checkAssignableRole checks that a role can be given to members of an
+// organization: a built-in member role other than owner, or one of its
+// custom roles
+func (s *OrganizationService) checkAssignableRole(orgID, role string) error {
+	if role != "owner" && s.authz.RoleExists(orgID, role) {
+		return nil
+	}
+	return errors.ValidationWithDetails("Unknown role", map[string]interface{}{
+		"role": role,
+	}).WithErrorCode(errors.CodeInvalidRole)
+}
+
+// ApplyBillingSubscription records the subscription reported by the
+// billing provider in the organization's billing. Providers do not deliver
+// changes in order, so a change older than the recorded one is ignored;
+// it reports whether the billing was updated.
+func (s *OrganizationService) ApplyBillingSubscription(ctx context.Context, sub models.BillingSubscription) (bool, error) {
+	session := s.neo4j.Session(ctx, func(c *neo4j.SessionConfig) {
+		c.AccessMode = neo4j.AccessModeWrite
+	})
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		result, err := tx.Run(ctx, `MATCH (o:Organization {id: $org_id}) RETURN o.billing as billing`, map[string]interface{}{
+			"org_id": sub.OrganizationID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		record, err := result.Single(ctx)
+		if err != nil {
+			return nil, errors.NotFoundWithDetails("Organization not found", map[string]interface{}{
+				"org_id": sub.OrganizationID,
+			}).WithErrorCode(errors.CodeOrganizationNotFound)
+		}
+
+		billing := map[string]interface{}{}
+		if stored, ok := record.Get("billing"); ok && stored != nil {
+			if storedStr, ok := stored.(string); ok && storedStr != "" {
+				if err := json.Unmarshal([]byte(storedStr), &billing); err != nil {
+					billing = map[string]interface{}{}
+				}
+			}
+		}
+		if changed, ok := billing["subscriptionChangedAt"].(string); ok {
+			if last, err := time.Parse(time.RFC3339, changed); err == nil && !sub.ChangedAt.After(last) {
+				return false, nil
+			}
+		}
+
+		billing["subscriptionStatus"] = sub.Status
+		billing["stripeCustomerId"] = sub.CustomerID
+		billing["stripeSubscriptionId"] = sub.SubscriptionID
+		billing["subscriptionChangedAt"] = sub.ChangedAt.UTC().Format(time.RFC3339)
+		if sub.Plan != "" {
+			billing["plan"] = sub.Plan
+		}
+		if sub.Seats > 0 {
+			billing["seats"] = sub.Seats
+		}
+		if sub.CurrentPeriodEnd != nil {
+			billing["nextBillingDate"] = sub.CurrentPeriodEnd.UTC().Format(time.RFC3339)
+		}
+		data, err := json.Marshal(billing)
+		if err != nil {
+			return nil, err
+		}
 
-	if userRole != "owner" {
-		return errors.ForbiddenWithDetails("Only organization owners can delete organizations", map[string]interface{}{
-			"user_role": userRole,
-			"org_id":    orgID,
+		_, err = tx.Run(ctx, `
+			MATCH (o:Organization {id: $org_id})
+			SET o.billing = $billing, o.updated_at = datetime($updated_at)
+		`, map[string]interface{}{
+			"org_id":     sub.OrganizationID,
+			"billing":    string(data),
+			"updated_at": time.Now().Format(time.RFC3339),
 		})
+		if err != nil {
+			return nil, err
+		}
+		return true, nil
+	})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, err
+		}
+		s.logger.Error("Failed to apply billing subscription", zap.Error(err), zap.String("org_id", sub.OrganizationID))
+		return false, errors.Database("Failed to update organization billing", err)
 	}
 
-	return s.deleteOrganizationInternal(ctx, orgID)
+	applied := result.(bool)
+	if applied {
+		s.logger.Info("Organization billing updated",
+			zap.String("org_id", sub.OrganizationID),
+			zap.String("status", sub.Status),
+			zap.String("plan", sub.Plan),
+		)
+	}
+	return applied, nil
 }
 
 // Internal helper methods
@@ -945,8 +1075,10 @@ func (s *OrganizationService) recordToOrganization(record *neo4j.Record) (*model
 	return org, nil
 }
 
-// GetOrganizationMembers retrieves all members of an organization
-func (s *OrganizationService) GetOrganizationMembers(ctx context.Context, orgID string, userID string) ([]*models.OrganizationMember, error) {
+// GetOrganizationMembers retrieves one page of the members of an
+// organization, oldest first. Team memberships are only looked up for the
+// members on the page.
+func (s *OrganizationService) GetOrganizationMembers(ctx context.Context, orgID string, userID string, limit, offset int) (*models.OrganizationMemberListResponse, error) {
 	// Check if user has access to view organization members
 	userRole, err := s.getUserRoleInOrganization(ctx, orgID, userID)
 	if err != nil {
@@ -961,17 +1093,25 @@ func (s *OrganizationService) GetOrganizationMembers(ctx context.Context, orgID
 		})
 	}
 
+	limit, offset = pagination.Clamp(limit, offset)
+
 	query := `
 		MATCH (o:Organization {id: $org_id})<-[r:MEMBER_OF]-(u:User)
+		WITH u, r
+		ORDER BY r.joined_at ASC, u.id ASC
+		SKIP $offset
+		LIMIT $limit
 		OPTIONAL MATCH (t:Team {organization_id: $org_id})<-[:MEMBER_OF]-(u)
 		WITH u, r, collect(t.id) as team_ids
 		RETURN u.id as user_id, u.full_name as name, u.email as email, u.username as username, u.avatar_url as avatar,
 			   r.role as role, r.joined_at as joined_at, r.invited_by as invited_by, r.title as title, r.department as department,
 			   team_ids
-		ORDER BY r.joined_at ASC`
+		ORDER BY r.joined_at ASC, u.id ASC`
 
 	params := map[string]interface{}{
 		"org_id": orgID,
+		"offset": offset,
+		"limit":  limit,
 	}
 
 	session := s.neo4j.Session(ctx, func(c *neo4j.SessionConfig) {
@@ -984,7 +1124,23 @@ func (s *OrganizationService) GetOrganizationMembers(ctx context.Context, orgID
 		if err != nil {
 			return nil, err
 		}
-		return result.Collect(ctx)
+		records, err := result.Collect(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		countResult, err := tx.Run(ctx, `
+			MATCH (o:Organization {id: $org_id})<-[r:MEMBER_OF]-(:User)
+			RETURN count(r) as total`, params)
+		if err != nil {
+			return nil, err
+		}
+		countRecord, err := countResult.Single(ctx)
+		if err != nil {
+			return nil, err
+		}
+		total, _ := countRecord.Get("total")
+		return memberPage{records: records, total: total.(int64)}, nil
 	})
 
 	if err != nil {
@@ -994,10 +1150,10 @@ func (s *OrganizationService) GetOrganizationMembers(ctx context.Context, orgID
 		})
 	}
 
-	records := result.([]*neo4j.Record)
-	members := make([]*models.OrganizationMember, 0, len(records))
+	page := result.(memberPage)
+	members := make([]*models.OrganizationMemberResponse, 0, len(page.records))
 
-	for _, record := range records {
+	for _, record := range page.records {
 		member := &models.OrganizationMember{
 			OrgID: orgID,
 		}
@@ -1043,25 +1199,32 @@ func (s *OrganizationService) GetOrganizationMembers(ctx context.Context, orgID
 			}
 		}
 
-		members = append(members, member)
+		members = append(members, member.ToMemberResponse())
 	}
 
-	return members, nil
+	return &models.OrganizationMemberListResponse{
+		Members: members,
+		Total:   int(page.total),
+		Limit:   limit,
+		Offset:  offset,
+		HasMore: pagination.HasMore(offset, len(members), int(page.total)),
+	}, nil
+}
+
+// memberPage is a page of member records with the total member count
+type memberPage struct {
+	records []*neo4j.Record
+	total   int64
 }
 
 // InviteOrganizationMember invites a new member to the organization
 func (s *OrganizationService) InviteOrganizationMember(ctx context.Context, orgID string, req models.OrganizationInviteRequest, invitedBy string) (*models.OrganizationMember, error) {
 	// Check if user has permission to invite
-	userRole, err := s.getUserRoleInOrganization(ctx, orgID, invitedBy)
-	if err != nil {
+	if _, err := s.AuthorizeMember(ctx, orgID, invitedBy, models.ActionOrganizationMembers); err != nil {
 		return nil, err
 	}
-
-	if userRole != "owner" && userRole != "admin" {
-		return nil, errors.ForbiddenWithDetails("Insufficient permissions to invite organization members", map[string]interface{}{
-			"user_role": userRole,
-			"org_id":    orgID,
-		})
+	if err := s.checkAssignableRole(orgID, req.Role); err != nil {
+		return nil, err
 	}
 
 	// Find user by email
@@ -1093,7 +1256,7 @@ func (s *OrganizationService) InviteOrganizationMember(ctx context.Context, orgI
 	if len(userRecords) == 0 {
 		return nil, errors.NotFoundWithDetails("User not found", map[string]interface{}{
 			"email": req.Email,
-		})
+		}).WithErrorCode(errors.CodeUserNotFound)
 	}
 
 	userRecord := userRecords[0]
@@ -1146,16 +1309,12 @@ func (s *OrganizationService) InviteOrganizationMember(ctx context.Context, orgI
 // UpdateOrganizationMemberRole updates an organization member's role
 func (s *OrganizationService) UpdateOrganizationMemberRole(ctx context.Context, orgID string, targetUserID string, req models.OrganizationMemberRoleUpdateRequest, updatedBy string) error {
 	// Check if user has permission to update roles
-	userRole, err := s.getUserRoleInOrganization(ctx, orgID, updatedBy)
+	userRole, err := s.AuthorizeMember(ctx, orgID, updatedBy, models.ActionOrganizationMembers)
 	if err != nil {
 		return err
 	}
-
-	if userRole != "owner" && userRole != "admin" {
-		return errors.ForbiddenWithDetails("Insufficient permissions to update member roles", map[string]interface{}{
-			"user_role": userRole,
-			"org_id":    orgID,
-		})
+	if err := s.checkAssignableRole(orgID, req.Role); err != nil {
+		return err
 	}
 
 	// Don't allow changing owner role or promoting to owner unless you are owner
@@ -1230,18 +1389,11 @@ func (s *OrganizationService) UpdateOrganizationMemberRole(ctx context.Context,
 // RemoveOrganizationMember removes a member from the organization
 func (s *OrganizationService) RemoveOrganizationMember(ctx context.Context, orgID string, targetUserID string, removedBy string) error {
 	// Check if user has permission to remove members
-	userRole, err := s.getUserRoleInOrganization(ctx, orgID, removedBy)
+	userRole, err := s.AuthorizeMember(ctx, orgID, removedBy, models.ActionOrganizationMembers)
 	if err != nil {
 		return err
 	}
 
-	if userRole != "owner" && userRole != "admin" {
-		return errors.ForbiddenWithDetails("Insufficient permissions to remove organization members", map[string]interface{}{
-			"user_role": userRole,
-			"org_id":    orgID,
-		})
-	}
-
 	// Check target user's role - can't remove owners unless you are owner
 	targetRole, err := s.getUserRoleInOrganization(ctx, orgID, targetUserID)
 	if err != nil {
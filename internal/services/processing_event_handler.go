@@ -3,156 +3,339 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/segmentio/kafka-go"
 	"go.uber.org/zap"
 
 	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// Topics AudiModal reports processing outcomes on. Both carry a
+// ProcessingCompleteEvent; events on the failed topic are failures
+// whatever their success flag says.
+const (
+	ProcessingCompleteTopic = "processing.complete"
+	ProcessingFailedTopic   = "processing.failed"
+)
+
+const (
+	processingConsumerGroup = "aether-be-processing-consumer"
+
+	// processingEventTTL is how long a processing event ID is remembered,
+	// well past any redelivery of the event
+	processingEventTTL = 24 * time.Hour
 )
 
 // ProcessingCompleteEvent represents the event from audimodal when processing completes
 type ProcessingCompleteEvent struct {
-	ID        string    `json:"id"`
-	Type      string    `json:"type"`
-	Source    string    `json:"source"`
-	TenantID  string    `json:"tenant_id"`
-	Timestamp time.Time `json:"timestamp"`
-	Version   string    `json:"version"`
+	ID        string                 `json:"id"`
+	Type      string                 `json:"type"`
+	Source    string                 `json:"source"`
+	TenantID  string                 `json:"tenant_id"`
+	Timestamp time.Time              `json:"timestamp"`
+	Version   string                 `json:"version"`
 	Data      ProcessingCompleteData `json:"data"`
 }
 
 // ProcessingCompleteData contains the processing result data
 type ProcessingCompleteData struct {
-	FileID              string        `json:"file_id"`                        // AudiModal file UUID
-	DocumentID          string        `json:"document_id,omitempty"`          // Neo4j Document.id from Aether-BE for cross-service consistency
-	URL                 string        `json:"url"`
-	TotalProcessingTime time.Duration `json:"total_processing_time"`
-	ChunksCreated       int           `json:"chunks_created"`
-	EmbeddingsCreated   int           `json:"embeddings_created"`
-	DLPViolationsFound  int           `json:"dlp_violations_found"`
-	FinalDataClass      string        `json:"final_data_class"`
-	StorageLocation     string        `json:"storage_location"`
-	Success             bool          `json:"success"`
-}
-
-// ProcessingEventHandler handles processing-related events from Kafka
+	FileID              string             `json:"file_id"`               // AudiModal file UUID
+	DocumentID          string             `json:"document_id,omitempty"` // Neo4j Document.id from Aether-BE for cross-service consistency
+	URL                 string             `json:"url"`
+	TotalProcessingTime time.Duration      `json:"total_processing_time"`
+	ChunksCreated       int                `json:"chunks_created"`
+	EmbeddingsCreated   int                `json:"embeddings_created"`
+	DLPViolationsFound  int                `json:"dlp_violations_found"`
+	FinalDataClass      string             `json:"final_data_class"`
+	StorageLocation     string             `json:"storage_location"`
+	Success             bool               `json:"success"`
+	Error               string             `json:"error,omitempty"`    // Why processing failed
+	Entities            []ProcessingEntity `json:"entities,omitempty"` // People, organizations and other entities detected in the text
+	Topics              []string           `json:"topics,omitempty"`
+}
+
+// ProcessingEntity is an entity detected in a processed file
+type ProcessingEntity struct {
+	Text  string `json:"text"`
+	Type  string `json:"type"`
+	Count int    `json:"count,omitempty"`
+}
+
+// ProcessingEventHandler consumes the processing outcomes AudiModal
+// publishes on Kafka, or delivers to the processing webhook, and records
+// them on their documents. Each event is applied once: its ID is claimed
+// in the lock store shared by the replicas, and an outcome for a file the
+// document has since been resubmitted as is ignored. Recording the outcome
+// publishes a domain event, which the event hub announces to real-time
+// clients.
 type ProcessingEventHandler struct {
 	documentService *DocumentService
 	kafkaService    *KafkaService
+	seen            LockStore
 	logger          *logger.Logger
 }
 
-// NewProcessingEventHandler creates a new processing event handler
-func NewProcessingEventHandler(documentService *DocumentService, kafkaService *KafkaService, log *logger.Logger) *ProcessingEventHandler {
+// ProcessingCallbackResult is what a processing callback changed
+type ProcessingCallbackResult struct {
+	EventID    string `json:"event_id"`
+	DocumentID string `json:"document_id,omitempty"`
+	Status     string `json:"status,omitempty"` // Document status recorded
+	// Applied is false when the event was handled already or reports on a
+	// submission the document has since replaced
+	Applied bool `json:"applied"`
+}
+
+// NewProcessingEventHandler creates a new processing event handler. seen
+// remembers the IDs of handled events; it may be nil to handle repeated
+// events again. kafkaService may be nil when events only arrive through
+// the processing webhook.
+func NewProcessingEventHandler(documentService *DocumentService, kafkaService *KafkaService, seen LockStore, log *logger.Logger) *ProcessingEventHandler {
 	return &ProcessingEventHandler{
 		documentService: documentService,
 		kafkaService:    kafkaService,
+		seen:            seen,
 		logger:          log.WithService("processing_event_handler"),
 	}
 }
 
 // Start starts listening for processing events
 func (h *ProcessingEventHandler) Start() error {
-	topic := "processing.complete"
-	groupID := "aether-be-processing-consumer"
-
-	h.logger.Info("Starting processing event handler",
-		zap.String("topic", topic),
-		zap.String("group_id", groupID),
-	)
-
-	return h.kafkaService.Subscribe(topic, groupID, h.handleProcessingComplete)
+	for _, topic := range []string{ProcessingCompleteTopic, ProcessingFailedTopic} {
+		h.logger.Info("Starting processing event handler",
+			zap.String("topic", topic),
+			zap.String("group_id", processingConsumerGroup),
+		)
+		if err := h.kafkaService.Subscribe(topic, processingConsumerGroup, h.HandleMessage); err != nil {
+			h.Stop()
+			return err
+		}
+	}
+	return nil
 }
 
 // Stop stops the event handler
 func (h *ProcessingEventHandler) Stop() error {
-	return h.kafkaService.Unsubscribe("processing.complete", "aether-be-processing-consumer")
+	var firstErr error
+	for _, topic := range []string{ProcessingCompleteTopic, ProcessingFailedTopic} {
+		if err := h.kafkaService.Unsubscribe(topic, processingConsumerGroup); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
 }
 
-// handleProcessingComplete handles a processing.complete event
-func (h *ProcessingEventHandler) handleProcessingComplete(ctx context.Context, message kafka.Message) error {
-	var event ProcessingCompleteEvent
-	if err := json.Unmarshal(message.Value, &event); err != nil {
-		h.logger.Error("Failed to unmarshal processing complete event",
+// HandleMessage applies a processing event. Malformed events are logged
+// and dropped, since redelivering them cannot succeed; an event that
+// fails to apply is released so a redelivery is applied.
+func (h *ProcessingEventHandler) HandleMessage(ctx context.Context, message kafka.Message) error {
+	log := h.logger.FromContext(ctx)
+
+	event, err := decodeProcessingEvent(message.Topic, message.Value)
+	if err != nil {
+		log.Warn("Dropped invalid processing event",
+			zap.String("topic", message.Topic),
+			zap.Int("size_bytes", len(message.Value)),
 			zap.Error(err),
-			zap.String("raw_value", string(message.Value)),
 		)
-		return err
+		return nil
 	}
 
-	h.logger.Info("Received processing complete event",
+	_, err = h.handle(ctx, event, false)
+	return err
+}
+
+// HandleCallback applies a processing event delivered to the processing
+// webhook. Unlike events read from Kafka, a callback must name its tenant,
+// and its document is looked up in that tenant only: a callback for a
+// document of another tenant, or of none, fails as not found.
+func (h *ProcessingEventHandler) HandleCallback(ctx context.Context, event *ProcessingCompleteEvent) (*ProcessingCallbackResult, error) {
+	if err := validateProcessingEvent(event); err != nil {
+		return nil, errors.Validation("Invalid processing callback", err)
+	}
+	if event.TenantID == "" {
+		return nil, errors.ValidationWithDetails("Processing callbacks must name their tenant", map[string]interface{}{
+			"event_id": event.ID,
+		})
+	}
+	return h.handle(ctx, event, true)
+}
+
+// handle claims and applies an event, releasing it when it fails to apply
+// so a redelivery is applied. scoped limits its document to its tenant.
+func (h *ProcessingEventHandler) handle(ctx context.Context, event *ProcessingCompleteEvent, scoped bool) (*ProcessingCallbackResult, error) {
+	log := h.logger.FromContext(ctx)
+
+	owner := uuid.New().String()
+	claimed, err := h.claim(ctx, event.ID, owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim processing event %s: %w", event.ID, err)
+	}
+	if !claimed {
+		log.Info("Ignored repeated processing event", zap.String("event_id", event.ID))
+		return &ProcessingCallbackResult{EventID: event.ID}, nil
+	}
+
+	result, err := h.apply(ctx, event, scoped)
+	if err != nil {
+		if h.seen != nil {
+			if releaseErr := h.seen.ReleaseLock(ctx, processingEventKey(event.ID), owner); releaseErr != nil {
+				log.Warn("Failed to release processing event", zap.String("event_id", event.ID), zap.Error(releaseErr))
+			}
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+// claim records that owner is handling an event, returning false when it
+// was handled already
+func (h *ProcessingEventHandler) claim(ctx context.Context, eventID, owner string) (bool, error) {
+	if h.seen == nil {
+		return true, nil
+	}
+	return h.seen.AcquireLock(ctx, processingEventKey(eventID), owner, processingEventTTL)
+}
+
+// apply records the outcome of a processing event on its document. An
+// event whose document cannot be found is dropped, since redelivering it
+// cannot succeed, unless scoped, when it fails as not found.
+func (h *ProcessingEventHandler) apply(ctx context.Context, event *ProcessingCompleteEvent, scoped bool) (*ProcessingCallbackResult, error) {
+	log := h.logger.FromContext(ctx)
+	log.Info("Received processing event",
 		zap.String("event_id", event.ID),
 		zap.String("source", event.Source),
 		zap.String("tenant_id", event.TenantID),
 		zap.String("file_id", event.Data.FileID),
 		zap.String("document_id", event.Data.DocumentID),
-		zap.String("storage_location", event.Data.StorageLocation),
 		zap.Int("chunks_created", event.Data.ChunksCreated),
 		zap.Bool("success", event.Data.Success),
 	)
 
-	// Document ID resolution priority:
-	// 1. DocumentID from event (most reliable - directly from AudiModal's stored neo4j_document_id)
-	// 2. Find by AudiModal file ID (requires processing_job_id to be set during upload)
-	// 3. Extract from URL/path or find by URL/filename
+	documentID := h.resolveDocumentID(ctx, event)
+	if documentID == "" {
+		log.Error("Could not find document for processing event",
+			zap.String("event_id", event.ID),
+			zap.String("url", event.Data.URL),
+			zap.String("file_id", event.Data.FileID),
+		)
+		if scoped {
+			return nil, errors.NotFound("Document not found").WithErrorCode(errors.CodeDocumentNotFound)
+		}
+		return &ProcessingCallbackResult{EventID: event.ID}, nil // Don't retry - document not found
+	}
+	if scoped {
+		// A document ID named by the event is not yet checked against its
+		// tenant
+		if _, err := h.documentService.getDocumentByIDInternal(ctx, documentID, event.TenantID); err != nil {
+			return nil, err
+		}
+	}
 
-	var documentID string
+	current, err := h.documentService.IsCurrentProcessingJob(ctx, documentID, event.Data.FileID)
+	if err != nil {
+		return nil, err
+	}
+	if !current {
+		log.Info("Ignored processing event of a superseded submission",
+			zap.String("event_id", event.ID),
+			zap.String("document_id", documentID),
+			zap.String("file_id", event.Data.FileID),
+		)
+		return &ProcessingCallbackResult{EventID: event.ID, DocumentID: documentID}, nil
+	}
+
+	status, result, errorMsg := processingEventOutcome(event)
+	if err := h.documentService.UpdateProcessingResult(ctx, documentID, status, result, errorMsg); err != nil {
+		log.Error("Failed to update document processing result",
+			zap.String("document_id", documentID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	log.Info("Document processing result synced to Neo4j",
+		zap.String("document_id", documentID),
+		zap.String("status", status),
+		zap.Int("chunks_created", event.Data.ChunksCreated),
+	)
+	return &ProcessingCallbackResult{EventID: event.ID, DocumentID: documentID, Status: status, Applied: true}, nil
+}
 
-	// Priority 1: Use DocumentID from event if available (most reliable method)
+// resolveDocumentID finds the document of a processing event: by the
+// document ID AudiModal stored for the file, then by the AudiModal file ID
+// recorded on submission, then by the file's URL or name. It returns ""
+// when none matches.
+func (h *ProcessingEventHandler) resolveDocumentID(ctx context.Context, event *ProcessingCompleteEvent) string {
 	if event.Data.DocumentID != "" {
-		documentID = event.Data.DocumentID
-		h.logger.Info("Using document_id from event (most reliable)",
-			zap.String("document_id", documentID))
+		return event.Data.DocumentID
 	}
 
-	// Priority 2: Try to find document by audimodal file ID
-	if documentID == "" && event.Data.FileID != "" {
+	if event.Data.FileID != "" {
 		doc, err := h.documentService.FindDocumentByAudiModalFileID(ctx, event.Data.FileID, event.TenantID)
 		if err != nil {
 			h.logger.Warn("Error looking up document by audimodal file ID",
 				zap.String("file_id", event.Data.FileID),
 				zap.Error(err))
 		} else if doc != nil {
-			documentID = doc.ID
-			h.logger.Info("Found document by audimodal file ID",
-				zap.String("file_id", event.Data.FileID),
-				zap.String("document_id", documentID))
+			return doc.ID
 		}
 	}
 
-	// Priority 3: Fallback - try to extract from path or find by URL/filename
-	if documentID == "" {
-		documentID = h.extractDocumentID(event.Data.URL, event.Data.StorageLocation)
+	if event.Data.URL == "" {
+		return ""
 	}
+	doc, err := h.documentService.FindDocumentByURL(ctx, event.Data.URL, event.TenantID)
+	if err != nil || doc == nil {
+		return ""
+	}
+	return doc.ID
+}
 
-	if documentID == "" {
-		h.logger.Warn("Could not extract document ID from event, trying URL lookup",
-			zap.String("url", event.Data.URL),
-			zap.String("storage_location", event.Data.StorageLocation),
-		)
-		// Try to find document by URL in Neo4j (includes filename fallback)
-		doc, err := h.documentService.FindDocumentByURL(ctx, event.Data.URL, event.TenantID)
-		if err != nil || doc == nil {
-			h.logger.Error("Could not find document for processing event",
-				zap.String("url", event.Data.URL),
-				zap.String("file_id", event.Data.FileID),
-				zap.Error(err),
-			)
-			return nil // Don't retry - document not found
-		}
-		documentID = doc.ID
+// decodeProcessingEvent decodes and validates a processing event received
+// on topic
+func decodeProcessingEvent(topic string, value []byte) (*ProcessingCompleteEvent, error) {
+	var event ProcessingCompleteEvent
+	if err := json.Unmarshal(value, &event); err != nil {
+		return nil, fmt.Errorf("malformed event: %w", err)
+	}
+	if err := validateProcessingEvent(&event); err != nil {
+		return nil, err
+	}
+	if topic == ProcessingFailedTopic {
+		event.Data.Success = false
+	}
+	return &event, nil
+}
+
+// validateProcessingEvent checks that an event has an ID and names its
+// document
+func validateProcessingEvent(event *ProcessingCompleteEvent) error {
+	if event.ID == "" {
+		return fmt.Errorf("event has no id")
+	}
+	if event.Data.DocumentID == "" && event.Data.FileID == "" && event.Data.URL == "" {
+		return fmt.Errorf("event %s names no document_id, file_id or url", event.ID)
 	}
+	return nil
+}
 
-	// Determine status based on success
+// processingEventOutcome returns the document status, processing result
+// and error message a processing event records
+func processingEventOutcome(event *ProcessingCompleteEvent) (string, map[string]interface{}, string) {
 	status := "processed"
 	errorMsg := ""
 	if !event.Data.Success {
 		status = "failed"
-		errorMsg = "Processing failed in audimodal"
+		errorMsg = event.Data.Error
+		if errorMsg == "" {
+			errorMsg = "Processing failed in audimodal"
+		}
 	}
 
-	// Build result map
 	result := map[string]interface{}{
 		"audimodal_file_id":    event.Data.FileID, // Store AudiModal file ID for cross-service lookup
 		"chunks_created":       event.Data.ChunksCreated,
@@ -161,30 +344,17 @@ func (h *ProcessingEventHandler) handleProcessingComplete(ctx context.Context, m
 		"final_data_class":     event.Data.FinalDataClass,
 		"processing_time_ms":   event.Data.TotalProcessingTime.Milliseconds(),
 	}
-
-	// Update document in Neo4j
-	err := h.documentService.UpdateProcessingResult(ctx, documentID, status, result, errorMsg)
-	if err != nil {
-		h.logger.Error("Failed to update document processing result",
-			zap.String("document_id", documentID),
-			zap.Error(err),
-		)
-		return err
+	// Kept for the entity graph, which reads them once the document is processed
+	if len(event.Data.Entities) > 0 {
+		result["entities"] = event.Data.Entities
 	}
-
-	h.logger.Info("Document processing result synced to Neo4j",
-		zap.String("document_id", documentID),
-		zap.String("status", status),
-		zap.Int("chunks_created", event.Data.ChunksCreated),
-	)
-
-	return nil
+	if len(event.Data.Topics) > 0 {
+		result["topics"] = event.Data.Topics
+	}
+	return status, result, errorMsg
 }
 
-// extractDocumentID attempts to extract document ID from URL or path
-func (h *ProcessingEventHandler) extractDocumentID(url, storagePath string) string {
-	// This is a simplified implementation
-	// In practice, you may need to query the database to find the document
-	// based on URL matching or path parsing
-	return ""
+// processingEventKey is the lock store key of a handled processing event
+func processingEventKey(eventID string) string {
+	return "aether-be:processing-event:" + eventID
 }
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeProcessingEvent(t *testing.T) {
+	event, err := decodeProcessingEvent(ProcessingCompleteTopic, []byte(`{"id":"evt-1","tenant_id":"t","data":{"file_id":"file-1","success":true}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "evt-1", event.ID)
+	assert.True(t, event.Data.Success)
+
+	event, err = decodeProcessingEvent(ProcessingFailedTopic, []byte(`{"id":"evt-2","data":{"document_id":"doc-1","success":true}}`))
+	require.NoError(t, err)
+	assert.False(t, event.Data.Success, "events on the failed topic are failures")
+
+	for name, value := range map[string]string{
+		"malformed":   `{"id":`,
+		"no id":       `{"data":{"file_id":"file-1"}}`,
+		"no document": `{"id":"evt-3","data":{"success":true}}`,
+	} {
+		_, err := decodeProcessingEvent(ProcessingCompleteTopic, []byte(value))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestProcessingEventOutcome(t *testing.T) {
+	status, result, errorMsg := processingEventOutcome(&ProcessingCompleteEvent{Data: ProcessingCompleteData{
+		FileID:            "file-1",
+		Success:           true,
+		EmbeddingsCreated: 4,
+		Topics:            []string{"finance"},
+	}})
+	assert.Equal(t, "processed", status)
+	assert.Empty(t, errorMsg)
+	assert.Equal(t, "file-1", result["audimodal_file_id"])
+	assert.Equal(t, []string{"finance"}, result["topics"])
+	assert.NotContains(t, result, "entities")
+
+	status, _, errorMsg = processingEventOutcome(&ProcessingCompleteEvent{Data: ProcessingCompleteData{Error: "unsupported format"}})
+	assert.Equal(t, "failed", status)
+	assert.Equal(t, "unsupported format", errorMsg)
+
+	_, _, errorMsg = processingEventOutcome(&ProcessingCompleteEvent{})
+	assert.Equal(t, "Processing failed in audimodal", errorMsg)
+}
+
+func TestProcessingEventHandlerSkipsInvalidAndRepeatedEvents(t *testing.T) {
+	seen := NewLocalLockStore()
+	handler := NewProcessingEventHandler(nil, nil, seen, setupTestLogger(t))
+	ctx := context.Background()
+
+	assert.NoError(t, handler.HandleMessage(ctx, kafka.Message{Topic: ProcessingCompleteTopic, Value: []byte(`not json`)}))
+
+	claimed, err := seen.AcquireLock(ctx, processingEventKey("evt-1"), "other-replica", processingEventTTL)
+	require.NoError(t, err)
+	require.True(t, claimed)
+	// The document service is never reached for an event handled already
+	assert.NoError(t, handler.HandleMessage(ctx, kafka.Message{
+		Topic: ProcessingCompleteTopic,
+		Value: []byte(`{"id":"evt-1","data":{"document_id":"doc-1","success":true}}`),
+	}))
+}
+
+func TestProcessingEventHandlerCallbacks(t *testing.T) {
+	seen := NewLocalLockStore()
+	handler := NewProcessingEventHandler(nil, nil, seen, setupTestLogger(t))
+	ctx := context.Background()
+
+	_, err := handler.HandleCallback(ctx, &ProcessingCompleteEvent{ID: "evt-1", Data: ProcessingCompleteData{Success: true}})
+	assert.Error(t, err, "a callback names its document")
+	_, err = handler.HandleCallback(ctx, &ProcessingCompleteEvent{ID: "evt-1", Data: ProcessingCompleteData{DocumentID: "doc-1"}})
+	assert.Error(t, err, "a callback names its tenant")
+
+	claimed, err := seen.AcquireLock(ctx, processingEventKey("evt-2"), "kafka-consumer", processingEventTTL)
+	require.NoError(t, err)
+	require.True(t, claimed)
+	// An event already applied from Kafka is acknowledged without reaching
+	// the document service
+	result, err := handler.HandleCallback(ctx, &ProcessingCompleteEvent{
+		ID:       "evt-2",
+		TenantID: "tenant-1",
+		Data:     ProcessingCompleteData{DocumentID: "doc-1", Success: true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &ProcessingCallbackResult{EventID: "evt-2"}, result)
+}
@@ -0,0 +1,389 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// ProcessingJobFilter selects the processing jobs of a tenant, optionally
+// of one document or in one status
+type ProcessingJobFilter struct {
+	TenantID   string
+	DocumentID string
+	Status     string
+}
+
+// ProcessingJobRepository stores document processing jobs so they survive
+// restarts and report real state transitions
+type ProcessingJobRepository interface {
+	Create(ctx context.Context, job *models.ProcessingJob) error
+	// Get finds a job by its ID or by the AudiModal file ID it processes,
+	// which documents store as their processing job ID
+	Get(ctx context.Context, id string) (*models.ProcessingJob, error)
+	// Update stores the job's status, progress, result and error. Moving
+	// to a status the stored job cannot reach is a conflict.
+	Update(ctx context.Context, job *models.ProcessingJob) error
+	// List lists jobs newest first
+	List(ctx context.Context, filter ProcessingJobFilter, limit, offset int) ([]*models.ProcessingJob, bool, error)
+	// FinishDocumentJobs moves the unfinished jobs of a document to a final
+	// status and returns their IDs
+	FinishDocumentJobs(ctx context.Context, tenantID, documentID, status, errorMsg string) ([]string, error)
+}
+
+// Neo4jProcessingJobRepository stores processing jobs as ProcessingJob nodes
+type Neo4jProcessingJobRepository struct {
+	neo4j  *database.Neo4jClient
+	logger *logger.Logger
+}
+
+// NewNeo4jProcessingJobRepository creates a processing job repository
+func NewNeo4jProcessingJobRepository(neo4j *database.Neo4jClient, log *logger.Logger) *Neo4jProcessingJobRepository {
+	return &Neo4jProcessingJobRepository{
+		neo4j:  neo4j,
+		logger: log.WithService("processing_job_repository"),
+	}
+}
+
+const processingJobFields = `
+	j.id as id, j.document_id as document_id, j.tenant_id as tenant_id,
+	j.type as type, j.status as status, j.priority as priority, j.priority_tier as priority_tier,
+	j.progress as progress, j.config as config, j.result as result,
+	j.error as error, j.created_at as created_at, j.updated_at as updated_at,
+	j.started_at as started_at, j.completed_at as completed_at
+`
+
+// Create stores a new job
+func (r *Neo4jProcessingJobRepository) Create(ctx context.Context, job *models.ProcessingJob) error {
+	now := time.Now().UTC()
+	if job.CreatedAt.IsZero() {
+		job.CreatedAt = now
+	}
+	stampProcessingJob(job, now)
+
+	params := processingJobParams(job)
+	params["document_id"] = job.DocumentID
+	params["tenant_id"] = job.TenantID
+	params["type"] = job.Type
+	params["priority"] = job.Priority
+	params["priority_tier"] = job.PriorityTier
+	params["created_at"] = job.CreatedAt
+
+	_, err := r.neo4j.ExecuteQuery(ctx, `
+		CREATE (j:ProcessingJob {
+			id: $id,
+			document_id: $document_id,
+			tenant_id: $tenant_id,
+			type: $type,
+			status: $status,
+			priority: $priority,
+			priority_tier: $priority_tier,
+			progress: $progress,
+			config: $config,
+			result: $result,
+			error: $error,
+			file_id: $file_id,
+			created_at: $created_at,
+			updated_at: $updated_at,
+			started_at: $started_at,
+			completed_at: $completed_at
+		})
+	`, params)
+	if err != nil {
+		return errors.Database("Failed to create processing job", err)
+	}
+	return nil
+}
+
+// Get finds a job by its ID or AudiModal file ID
+func (r *Neo4jProcessingJobRepository) Get(ctx context.Context, id string) (*models.ProcessingJob, error) {
+	result, err := r.neo4j.ExecuteQuery(ctx, `
+		MATCH (j:ProcessingJob)
+		WHERE j.id = $id OR j.file_id = $id
+		RETURN `+processingJobFields+`
+		ORDER BY j.created_at DESC
+		LIMIT 1
+	`, map[string]interface{}{"id": id})
+	if err != nil {
+		return nil, errors.Database("Failed to get processing job", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, errors.NotFoundWithDetails("Processing job not found", map[string]interface{}{
+			"job_id": id,
+		}).WithErrorCode(errors.CodeProcessingJobNotFound)
+	}
+	return recordToProcessingJob(result.Records[0]), nil
+}
+
+// Update stores a job's state if the stored job may move to its status
+func (r *Neo4jProcessingJobRepository) Update(ctx context.Context, job *models.ProcessingJob) error {
+	stampProcessingJob(job, time.Now().UTC())
+
+	params := processingJobParams(job)
+	params["sources"] = models.ProcessingJobSources(job.Status)
+	result, err := r.neo4j.ExecuteQuery(ctx, `
+		MATCH (j:ProcessingJob {id: $id})
+		WHERE j.status IN $sources
+		SET j.status = $status,
+		    j.progress = $progress,
+		    j.config = $config,
+		    j.result = $result,
+		    j.error = $error,
+		    j.file_id = $file_id,
+		    j.updated_at = $updated_at,
+		    j.started_at = coalesce(j.started_at, $started_at),
+		    j.completed_at = coalesce(j.completed_at, $completed_at)
+		RETURN j.id as id
+	`, params)
+	if err != nil {
+		return errors.Database("Failed to update processing job", err)
+	}
+	if len(result.Records) > 0 {
+		return nil
+	}
+
+	stored, err := r.Get(ctx, job.ID)
+	if err != nil {
+		return err
+	}
+	return errors.ConflictWithDetails("Processing job cannot move to the requested status", map[string]interface{}{
+		"job_id": job.ID,
+		"from":   stored.Status,
+		"to":     job.Status,
+	}).WithErrorCode(errors.CodeProcessingJobTransition)
+}
+
+// List lists the jobs matching filter, newest first
+func (r *Neo4jProcessingJobRepository) List(ctx context.Context, filter ProcessingJobFilter, limit, offset int) ([]*models.ProcessingJob, bool, error) {
+	limit, offset = pagination.Clamp(limit, offset)
+	result, err := r.neo4j.ExecuteQuery(ctx, `
+		MATCH (j:ProcessingJob {tenant_id: $tenant_id})
+		WHERE ($document_id = '' OR j.document_id = $document_id) AND ($status = '' OR j.status = $status)
+		RETURN `+processingJobFields+`
+		ORDER BY j.created_at DESC
+		SKIP $offset
+		LIMIT $limit
+	`, map[string]interface{}{
+		"tenant_id":   filter.TenantID,
+		"document_id": filter.DocumentID,
+		"status":      filter.Status,
+		"offset":      offset,
+		"limit":       limit + 1,
+	})
+	if err != nil {
+		return nil, false, errors.Database("Failed to list processing jobs", err)
+	}
+
+	jobs := make([]*models.ProcessingJob, 0, len(result.Records))
+	for _, record := range result.Records {
+		jobs = append(jobs, recordToProcessingJob(record))
+	}
+	jobs, hasMore := pagination.Trim(jobs, limit)
+	return jobs, hasMore, nil
+}
+
+// FinishDocumentJobs ends the unfinished jobs of a document
+func (r *Neo4jProcessingJobRepository) FinishDocumentJobs(ctx context.Context, tenantID, documentID, status, errorMsg string) ([]string, error) {
+	if !models.ProcessingJobFinished(status) {
+		return nil, errors.Internal("Processing jobs can only be finished with a final status")
+	}
+
+	result, err := r.neo4j.ExecuteQuery(ctx, `
+		MATCH (j:ProcessingJob {tenant_id: $tenant_id, document_id: $document_id})
+		WHERE j.status IN $unfinished
+		SET j.status = $status,
+		    j.progress = CASE WHEN $status = $completed THEN 100.0 ELSE j.progress END,
+		    j.error = CASE WHEN $error = '' THEN j.error ELSE $error END,
+		    j.started_at = coalesce(j.started_at, $now),
+		    j.completed_at = $now,
+		    j.updated_at = $now
+		RETURN j.id as id
+	`, map[string]interface{}{
+		"tenant_id":   tenantID,
+		"document_id": documentID,
+		"unfinished":  []string{models.ProcessingJobPending, models.ProcessingJobProcessing},
+		"status":      status,
+		"completed":   models.ProcessingJobCompleted,
+		"error":       errorMsg,
+		"now":         time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to finish processing jobs", err)
+	}
+
+	finished := make([]string, 0, len(result.Records))
+	for _, record := range result.Records {
+		finished = append(finished, recordString(record, "id"))
+	}
+	if len(finished) > 0 {
+		r.logger.Debug("Finished processing jobs of document",
+			zap.String("document_id", documentID),
+			zap.String("status", status),
+			zap.Int("count", len(finished)),
+		)
+	}
+	return finished, nil
+}
+
+// AnnouncedProcessingJobRepository announces the state changes of stored
+// processing jobs on the event hub, so real-time clients can follow a
+// job's progress without polling
+type AnnouncedProcessingJobRepository struct {
+	ProcessingJobRepository
+	hub *EventHub
+}
+
+// NewAnnouncedProcessingJobRepository wraps jobs to announce their changes
+// on hub
+func NewAnnouncedProcessingJobRepository(jobs ProcessingJobRepository, hub *EventHub) *AnnouncedProcessingJobRepository {
+	return &AnnouncedProcessingJobRepository{ProcessingJobRepository: jobs, hub: hub}
+}
+
+// Create stores and announces a new job
+func (r *AnnouncedProcessingJobRepository) Create(ctx context.Context, job *models.ProcessingJob) error {
+	if err := r.ProcessingJobRepository.Create(ctx, job); err != nil {
+		return err
+	}
+	r.hub.PublishJobProgress(ctx, job)
+	return nil
+}
+
+// Update stores and announces a job's new state
+func (r *AnnouncedProcessingJobRepository) Update(ctx context.Context, job *models.ProcessingJob) error {
+	if err := r.ProcessingJobRepository.Update(ctx, job); err != nil {
+		return err
+	}
+	r.hub.PublishJobProgress(ctx, job)
+	return nil
+}
+
+// FinishDocumentJobs finishes and announces the unfinished jobs of a
+// document
+func (r *AnnouncedProcessingJobRepository) FinishDocumentJobs(ctx context.Context, tenantID, documentID, status, errorMsg string) ([]string, error) {
+	finished, err := r.ProcessingJobRepository.FinishDocumentJobs(ctx, tenantID, documentID, status, errorMsg)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range finished {
+		job := &models.ProcessingJob{ID: id, TenantID: tenantID, DocumentID: documentID, Status: status, Error: errorMsg}
+		if status == models.ProcessingJobCompleted {
+			job.Progress = 100
+		}
+		r.hub.PublishJobProgress(ctx, job)
+	}
+	return finished, nil
+}
+
+// stampProcessingJob sets the timestamps implied by a job's status
+func stampProcessingJob(job *models.ProcessingJob, now time.Time) {
+	job.UpdatedAt = now
+	if job.StartedAt == nil && job.Status != models.ProcessingJobPending {
+		job.StartedAt = &now
+	}
+	if job.CompletedAt == nil && models.ProcessingJobFinished(job.Status) {
+		job.CompletedAt = &now
+	}
+}
+
+// processingJobParams holds the mutable fields of a job as query
+// parameters. The uploaded file in the config, as data or as a reader, is
+// not stored.
+func processingJobParams(job *models.ProcessingJob) map[string]interface{} {
+	config := make(map[string]interface{}, len(job.Config))
+	for key, value := range job.Config {
+		if key != "file_data" && key != "file_reader" {
+			config[key] = value
+		}
+	}
+	fileID, _ := job.Config["audimodal_file_id"].(string)
+
+	return map[string]interface{}{
+		"id":           job.ID,
+		"status":       job.Status,
+		"progress":     job.Progress,
+		"config":       encodeJSONProperty(config),
+		"result":       encodeJSONProperty(job.Result),
+		"error":        job.Error,
+		"file_id":      fileID,
+		"updated_at":   job.UpdatedAt,
+		"started_at":   optionalTime(job.StartedAt),
+		"completed_at": optionalTime(job.CompletedAt),
+	}
+}
+
+// encodeJSONProperty encodes a map as a JSON string property, empty when
+// there is nothing to store
+func encodeJSONProperty(value map[string]interface{}) string {
+	if len(value) == 0 {
+		return ""
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(encoded)
+}
+
+// optionalTime returns a time parameter, nil for a missing time
+func optionalTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
+
+// recordToProcessingJob reads the processingJobFields of a record
+func recordToProcessingJob(record *neo4j.Record) *models.ProcessingJob {
+	job := &models.ProcessingJob{
+		ID:           recordString(record, "id"),
+		DocumentID:   recordString(record, "document_id"),
+		TenantID:     recordString(record, "tenant_id"),
+		Type:         recordString(record, "type"),
+		Status:       recordString(record, "status"),
+		PriorityTier: recordString(record, "priority_tier"),
+		Error:        recordString(record, "error"),
+	}
+	if value, _ := record.Get("priority"); value != nil {
+		n, _ := value.(int64)
+		job.Priority = int(n)
+	}
+	if value, _ := record.Get("progress"); value != nil {
+		job.Progress, _ = value.(float64)
+	}
+	if encoded := recordString(record, "config"); encoded != "" {
+		_ = json.Unmarshal([]byte(encoded), &job.Config)
+	}
+	if encoded := recordString(record, "result"); encoded != "" {
+		_ = json.Unmarshal([]byte(encoded), &job.Result)
+	}
+	if job.Config == nil {
+		job.Config = make(map[string]interface{})
+	}
+	for key, target := range map[string]*time.Time{
+		"created_at": &job.CreatedAt,
+		"updated_at": &job.UpdatedAt,
+	} {
+		if value, _ := record.Get(key); value != nil {
+			*target, _ = value.(time.Time)
+		}
+	}
+	for key, target := range map[string]**time.Time{
+		"started_at":   &job.StartedAt,
+		"completed_at": &job.CompletedAt,
+	} {
+		value, _ := record.Get(key)
+		if t, ok := value.(time.Time); ok {
+			*target = &t
+		}
+	}
+	return job
+}
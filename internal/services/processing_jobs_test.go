@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+func TestProcessingJobTransitions(t *testing.T) {
+	assert.True(t, models.ProcessingJobCanTransition(models.ProcessingJobPending, models.ProcessingJobProcessing))
+	assert.True(t, models.ProcessingJobCanTransition(models.ProcessingJobProcessing, models.ProcessingJobCompleted))
+	assert.True(t, models.ProcessingJobCanTransition(models.ProcessingJobProcessing, models.ProcessingJobProcessing), "progress updates keep the status")
+	assert.False(t, models.ProcessingJobCanTransition(models.ProcessingJobProcessing, models.ProcessingJobPending))
+	assert.False(t, models.ProcessingJobCanTransition(models.ProcessingJobCompleted, models.ProcessingJobFailed))
+
+	assert.ElementsMatch(t, []string{models.ProcessingJobCompleted, models.ProcessingJobPending, models.ProcessingJobProcessing},
+		models.ProcessingJobSources(models.ProcessingJobCompleted))
+	assert.Equal(t, []string{models.ProcessingJobPending}, models.ProcessingJobSources(models.ProcessingJobPending))
+}
+
+func TestStampProcessingJob(t *testing.T) {
+	now := time.Now().UTC()
+	job := &models.ProcessingJob{Status: models.ProcessingJobPending}
+	stampProcessingJob(job, now)
+	assert.Nil(t, job.StartedAt)
+	assert.Nil(t, job.CompletedAt)
+
+	job.Status = models.ProcessingJobProcessing
+	stampProcessingJob(job, now)
+	assert.Equal(t, &now, job.StartedAt)
+	assert.Nil(t, job.CompletedAt)
+
+	later := now.Add(time.Minute)
+	job.Status = models.ProcessingJobFailed
+	stampProcessingJob(job, later)
+	assert.Equal(t, &now, job.StartedAt, "the start time is kept")
+	assert.Equal(t, &later, job.CompletedAt)
+	assert.Equal(t, later, job.UpdatedAt)
+}
+
+func TestProcessingJobParamsDropFileData(t *testing.T) {
+	params := processingJobParams(&models.ProcessingJob{
+		ID:     "job-1",
+		Status: models.ProcessingJobProcessing,
+		Config: map[string]interface{}{
+			"file_data":         []byte("contents"),
+			"filename":          "report.pdf",
+			"audimodal_file_id": "file-1",
+		},
+	})
+	assert.Equal(t, `{"audimodal_file_id":"file-1","filename":"report.pdf"}`, params["config"])
+	assert.Equal(t, "file-1", params["file_id"])
+	assert.Equal(t, "", params["result"])
+	assert.Nil(t, params["completed_at"])
+}
+
+// finishingJobRepository finishes the jobs it is given
+type finishingJobRepository struct {
+	ProcessingJobRepository
+	finished []string
+}
+
+func (r *finishingJobRepository) FinishDocumentJobs(ctx context.Context, tenantID, documentID, status, errorMsg string) ([]string, error) {
+	return r.finished, nil
+}
+
+func TestAnnouncedProcessingJobRepositoryAnnouncesFinishedJobs(t *testing.T) {
+	hub := NewEventHub(nil, setupTestLogger(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := hub.Subscribe(ctx, "tenant_1", HubTopicJobProgress)
+	require.NoError(t, err)
+
+	jobs := NewAnnouncedProcessingJobRepository(&finishingJobRepository{finished: []string{"job_1"}}, hub)
+	finished, err := jobs.FinishDocumentJobs(ctx, "tenant_1", "doc", models.ProcessingJobCompleted, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"job_1"}, finished)
+
+	event := receiveHubEvent(t, events)
+	assert.Equal(t, "job_1", event.ResourceID)
+	assert.Equal(t, models.ProcessingJobCompleted, event.Status)
+	assert.Equal(t, "doc", event.Data["document_id"])
+	assert.Equal(t, 100.0, event.Data["progress"])
+}
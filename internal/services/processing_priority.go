@@ -0,0 +1,83 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// ProcessingPriorityKey is the key of a processing config holding the
+// priority tier a document is processed with
+const ProcessingPriorityKey = "priority"
+
+// processingQueueLevels are the priorities, from 0 to 10, processing jobs
+// and retries of each tier are queued with
+var processingQueueLevels = map[string]int{
+	config.PriorityLow:      2,
+	config.PriorityNormal:   5,
+	config.PriorityHigh:     8,
+	config.PriorityRealtime: 10,
+}
+
+// processingQueueLevel returns the queue priority of a tier, that of
+// normal for an unknown tier
+func processingQueueLevel(tier string) int {
+	if level, ok := processingQueueLevels[tier]; ok {
+		return level
+	}
+	return processingQueueLevels[config.PriorityNormal]
+}
+
+// retryQueueLevels returns processingQueueLevels as a query parameter
+func retryQueueLevels() map[string]interface{} {
+	levels := make(map[string]interface{}, len(processingQueueLevels))
+	for tier, level := range processingQueueLevels {
+		levels[tier] = level
+	}
+	return levels
+}
+
+// SetProcessingPriorities sets the default processing priority and the
+// highest tier each billing plan may ask for
+func (s *DocumentService) SetProcessingPriorities(cfg config.ProcessingPriorityConfig) {
+	s.priorities = cfg
+}
+
+// processingPriority returns the tier a document submitted in a space is
+// processed with. A requested tier above the cap of the space's billing
+// plan is refused. Without one the document keeps the tier it was last
+// submitted with, or else takes the default, lowered to the plan's cap.
+func (s *DocumentService) processingPriority(spaceCtx *models.SpaceContext, requested, current string) (string, error) {
+	maxTier := s.priorities.Cap(spaceCtx.Plan)
+	if requested = strings.ToLower(strings.TrimSpace(requested)); requested != "" {
+		rank := config.PriorityRank(requested)
+		if rank < 0 {
+			return "", errors.ValidationWithDetails("Invalid processing priority", map[string]interface{}{
+				"priority": requested,
+				"allowed":  config.ProcessingPriorities,
+			})
+		}
+		if rank > config.PriorityRank(maxTier) {
+			return "", errors.ForbiddenWithDetails("The space's plan does not allow this processing priority", map[string]interface{}{
+				"priority":     requested,
+				"max_priority": maxTier,
+				"plan":         spaceCtx.Plan,
+			}).WithErrorCode(errors.CodePriorityNotAllowed)
+		}
+		return requested, nil
+	}
+	if config.PriorityRank(current) >= 0 {
+		return current, nil
+	}
+
+	tier := s.priorities.Default
+	if config.PriorityRank(tier) < 0 {
+		tier = config.PriorityNormal
+	}
+	if config.PriorityRank(tier) > config.PriorityRank(maxTier) {
+		tier = maxTier
+	}
+	return tier, nil
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+func TestProcessingPriority(t *testing.T) {
+	s := &DocumentService{}
+	s.SetProcessingPriorities(config.ProcessingPriorityConfig{
+		Default:  config.PriorityHigh,
+		PlanCaps: "enterprise=realtime,free=normal,default=high",
+	})
+	enterprise := &models.SpaceContext{Plan: "enterprise"}
+	free := &models.SpaceContext{Plan: "free"}
+
+	tier, err := s.processingPriority(enterprise, "realtime", "")
+	require.NoError(t, err)
+	assert.Equal(t, config.PriorityRealtime, tier)
+
+	_, err = s.processingPriority(free, "high", "")
+	apiErr, ok := errors.AsAPIError(err)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+	assert.Equal(t, errors.CodePriorityNotAllowed, apiErr.ErrorCode)
+	assert.Equal(t, config.PriorityNormal, apiErr.Details["max_priority"])
+
+	_, err = s.processingPriority(enterprise, "urgent", "")
+	apiErr, ok = errors.AsAPIError(err)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+
+	tier, err = s.processingPriority(free, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, config.PriorityNormal, tier, "the default is lowered to the plan's cap")
+
+	tier, err = s.processingPriority(&models.SpaceContext{}, "", config.PriorityRealtime)
+	require.NoError(t, err)
+	assert.Equal(t, config.PriorityRealtime, tier, "a document keeps the tier it was submitted with")
+
+	tier, err = (&DocumentService{}).processingPriority(free, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, config.PriorityNormal, tier)
+}
+
+func TestProcessingQueueLevel(t *testing.T) {
+	assert.Equal(t, 10, processingQueueLevel(config.PriorityRealtime))
+	assert.Equal(t, 2, processingQueueLevel(config.PriorityLow))
+	assert.Equal(t, processingQueueLevel(config.PriorityNormal), processingQueueLevel(""))
+	assert.Less(t, processingQueueLevel(config.PriorityNormal), processingQueueLevel(config.PriorityHigh))
+}
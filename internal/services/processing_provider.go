@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+// AudiModalProviderName is the name AudiModal is registered under, and the
+// provider of documents processed before providers were recorded
+const AudiModalProviderName = "audimodal"
+
+// ProcessingFileIDKey is the key of a processing job's config naming the
+// provider's copy of the file, when it differs from the job ID
+const ProcessingFileIDKey = "file_id"
+
+// ProcessingProvider extracts the text of documents. DocumentService hands
+// each document to the provider of its space and keeps using that provider
+// for the document, so adding a processor, such as a local Tika or
+// unstructured.io, only takes registering it.
+type ProcessingProvider interface {
+	// Name identifies the provider in space settings and on documents
+	Name() string
+	// ProcessFile submits a document's file, given in config as
+	// "file_data" bytes or a "file_reader", with "filename", "mime_type"
+	// and "ocr" settings. The returned job's config names the provider's
+	// copy of the file under ProcessingFileIDKey.
+	ProcessFile(ctx context.Context, tenantID, documentID, jobType string, config map[string]interface{}) (*models.ProcessingJob, error)
+	// GetStatus returns a submitted job, with its result once finished
+	GetStatus(ctx context.Context, jobID string) (*models.ProcessingJob, error)
+	// CancelJob stops a job that has not finished
+	CancelJob(ctx context.Context, jobID string) error
+	// GetContent returns the text extracted from a processed file
+	GetContent(ctx context.Context, tenantID, fileID string) (string, error)
+	// DeleteArtifacts deletes a processed file and what was derived from
+	// it, such as its chunks; artifacts that are already gone are not an
+	// error
+	DeleteArtifacts(ctx context.Context, tenantID, fileID string) error
+}
+
+// processingFileID returns the provider's copy of a job's file
+func processingFileID(job *models.ProcessingJob) string {
+	if fileID, ok := job.Config[ProcessingFileIDKey].(string); ok && fileID != "" {
+		return fileID
+	}
+	return job.ID
+}
+
+// AudiModalProvider processes documents with AudiModal
+type AudiModalProvider struct {
+	audiModal *AudiModalService
+}
+
+// NewAudiModalProvider creates a processing provider backed by AudiModal
+func NewAudiModalProvider(audiModal *AudiModalService) *AudiModalProvider {
+	return &AudiModalProvider{audiModal: audiModal}
+}
+
+// Name returns AudiModalProviderName
+func (p *AudiModalProvider) Name() string {
+	return AudiModalProviderName
+}
+
+// ProcessFile uploads a document's file to AudiModal
+func (p *AudiModalProvider) ProcessFile(ctx context.Context, tenantID, documentID, jobType string, config map[string]interface{}) (*models.ProcessingJob, error) {
+	job, err := p.audiModal.SubmitProcessingJob(ctx, tenantID, documentID, jobType, config)
+	if err != nil {
+		return nil, err
+	}
+	audiModalFileIDToConfig(job)
+	return job, nil
+}
+
+// GetStatus returns a job, refreshed from AudiModal while it runs
+func (p *AudiModalProvider) GetStatus(ctx context.Context, jobID string) (*models.ProcessingJob, error) {
+	job, err := p.audiModal.GetProcessingJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	audiModalFileIDToConfig(job)
+	return job, nil
+}
+
+// CancelJob records a job as cancelled
+func (p *AudiModalProvider) CancelJob(ctx context.Context, jobID string) error {
+	return p.audiModal.CancelProcessingJob(ctx, jobID)
+}
+
+// GetContent returns the text AudiModal extracted from a file
+func (p *AudiModalProvider) GetContent(ctx context.Context, tenantID, fileID string) (string, error) {
+	return p.audiModal.GetFileContent(ctx, tenantID, fileID)
+}
+
+// DeleteArtifacts deletes a file and its chunks from AudiModal
+func (p *AudiModalProvider) DeleteArtifacts(ctx context.Context, tenantID, fileID string) error {
+	return p.audiModal.DeleteFile(ctx, tenantID, fileID)
+}
+
+// audiModalFileIDToConfig names the AudiModal file of a job under
+// ProcessingFileIDKey
+func audiModalFileIDToConfig(job *models.ProcessingJob) {
+	if job.Config == nil {
+		return
+	}
+	if fileID, ok := job.Config["audimodal_file_id"].(string); ok && fileID != "" {
+		job.Config[ProcessingFileIDKey] = fileID
+	}
+}
+
+// ProcessingProviderRegistry holds the processing providers spaces can
+// choose from. The first provider registered is the default, used by
+// spaces that have not chosen one.
+type ProcessingProviderRegistry struct {
+	mu          sync.RWMutex
+	providers   map[string]ProcessingProvider
+	defaultName string
+}
+
+// NewProcessingProviderRegistry creates a registry of providers
+func NewProcessingProviderRegistry(providers ...ProcessingProvider) *ProcessingProviderRegistry {
+	registry := &ProcessingProviderRegistry{providers: make(map[string]ProcessingProvider)}
+	for _, provider := range providers {
+		registry.Register(provider)
+	}
+	return registry
+}
+
+// Register adds a provider, replacing any registered under its name
+func (r *ProcessingProviderRegistry) Register(provider ProcessingProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[provider.Name()] = provider
+	if r.defaultName == "" {
+		r.defaultName = provider.Name()
+	}
+}
+
+// SetDefault makes a registered provider the default
+func (r *ProcessingProviderRegistry) SetDefault(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.providers[name]; !ok {
+		return fmt.Errorf("processing provider %q is not registered", name)
+	}
+	r.defaultName = name
+	return nil
+}
+
+// Get returns the provider of a name, or the default for an empty name
+func (r *ProcessingProviderRegistry) Get(name string) (ProcessingProvider, bool) {
+	if r == nil {
+		return nil, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if name == "" {
+		name = r.defaultName
+	}
+	provider, ok := r.providers[name]
+	return provider, ok
+}
+
+// Default returns the name of the default provider, empty when none is
+// registered
+func (r *ProcessingProviderRegistry) Default() string {
+	if r == nil {
+		return ""
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.defaultName
+}
+
+// Names returns the names of the registered providers, sorted
+func (r *ProcessingProviderRegistry) Names() []string {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DeleteArtifacts deletes a processed file with the provider that made
+// it; an empty provider is the one of documents processed before
+// providers were recorded
+func (r *ProcessingProviderRegistry) DeleteArtifacts(ctx context.Context, providerName, tenantID, fileID string) error {
+	if providerName == "" {
+		providerName = AudiModalProviderName
+	}
+	provider, ok := r.Get(providerName)
+	if !ok {
+		return fmt.Errorf("processing provider %q is not registered", providerName)
+	}
+	return provider.DeleteArtifacts(ctx, tenantID, fileID)
+}
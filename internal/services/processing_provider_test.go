@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+// recordingProvider is a processing provider that records the files whose
+// artifacts it deleted
+type recordingProvider struct {
+	name    string
+	deleted []string
+}
+
+func (p *recordingProvider) Name() string { return p.name }
+
+func (p *recordingProvider) ProcessFile(ctx context.Context, tenantID, documentID, jobType string, config map[string]interface{}) (*models.ProcessingJob, error) {
+	return &models.ProcessingJob{ID: "job-1", DocumentID: documentID, Config: config}, nil
+}
+
+func (p *recordingProvider) GetStatus(ctx context.Context, jobID string) (*models.ProcessingJob, error) {
+	return &models.ProcessingJob{ID: jobID}, nil
+}
+
+func (p *recordingProvider) CancelJob(ctx context.Context, jobID string) error { return nil }
+
+func (p *recordingProvider) GetContent(ctx context.Context, tenantID, fileID string) (string, error) {
+	return "text of " + fileID, nil
+}
+
+func (p *recordingProvider) DeleteArtifacts(ctx context.Context, tenantID, fileID string) error {
+	p.deleted = append(p.deleted, tenantID+"/"+fileID)
+	return nil
+}
+
+func TestProcessingProviderRegistry(t *testing.T) {
+	audiModal := &recordingProvider{name: AudiModalProviderName}
+	tika := &recordingProvider{name: "tika"}
+	registry := NewProcessingProviderRegistry(audiModal, tika)
+
+	assert.Equal(t, AudiModalProviderName, registry.Default(), "the first provider registered is the default")
+	assert.Equal(t, []string{AudiModalProviderName, "tika"}, registry.Names())
+
+	provider, ok := registry.Get("")
+	require.True(t, ok)
+	assert.Same(t, audiModal, provider)
+	_, ok = registry.Get("unstructured")
+	assert.False(t, ok)
+
+	require.NoError(t, registry.SetDefault("tika"))
+	provider, _ = registry.Get("")
+	assert.Same(t, tika, provider)
+	assert.Error(t, registry.SetDefault("unstructured"))
+
+	var empty *ProcessingProviderRegistry
+	_, ok = empty.Get("")
+	assert.False(t, ok)
+	assert.Empty(t, empty.Default())
+}
+
+func TestProcessingProviderRegistryDeleteArtifacts(t *testing.T) {
+	audiModal := &recordingProvider{name: AudiModalProviderName}
+	tika := &recordingProvider{name: "tika"}
+	registry := NewProcessingProviderRegistry(tika, audiModal)
+
+	require.NoError(t, registry.DeleteArtifacts(context.Background(), "tika", "tenant-1", "file-1"))
+	// Documents processed before providers were recorded are AudiModal's
+	require.NoError(t, registry.DeleteArtifacts(context.Background(), "", "tenant-1", "file-2"))
+	assert.Equal(t, []string{"tenant-1/file-1"}, tika.deleted)
+	assert.Equal(t, []string{"tenant-1/file-2"}, audiModal.deleted)
+
+	assert.Error(t, registry.DeleteArtifacts(context.Background(), "unstructured", "tenant-1", "file-3"))
+}
+
+func TestProcessingFileID(t *testing.T) {
+	job := &models.ProcessingJob{ID: "job-1", Config: map[string]interface{}{"audimodal_file_id": "file-1"}}
+	assert.Equal(t, "job-1", processingFileID(job))
+
+	audiModalFileIDToConfig(job)
+	assert.Equal(t, "file-1", processingFileID(job))
+}
+
+func TestSpaceProcessingProvider(t *testing.T) {
+	audiModal := &recordingProvider{name: AudiModalProviderName}
+	tika := &recordingProvider{name: "tika"}
+	s := &DocumentService{processors: NewProcessingProviderRegistry(audiModal, tika), logger: setupTestLogger(t)}
+
+	settings := map[string]interface{}{models.ProcessingSettingsKey: map[string]interface{}{"provider": "tika"}}
+	assert.Same(t, tika, s.spaceProcessingProvider(settings, "space-1"))
+	assert.Same(t, audiModal, s.spaceProcessingProvider(nil, "space-1"))
+
+	// A provider that is no longer registered falls back to the default
+	settings[models.ProcessingSettingsKey] = map[string]interface{}{"provider": "unstructured"}
+	assert.Same(t, audiModal, s.spaceProcessingProvider(settings, "space-1"))
+
+	// Documents keep the provider that processed them
+	assert.Same(t, tika, s.documentProcessingProvider(&models.Document{ProcessingProvider: "tika"}))
+	assert.Same(t, audiModal, s.documentProcessingProvider(&models.Document{}))
+	assert.Nil(t, s.documentProcessingProvider(&models.Document{ProcessingProvider: "unstructured"}))
+}
+
+func TestValidateProcessingProvider(t *testing.T) {
+	s := &SpaceService{}
+	assert.NoError(t, s.validateProcessingProvider("anything"), "without registered providers any is allowed")
+
+	s.SetProcessingProviders([]string{AudiModalProviderName, "tika"})
+	assert.NoError(t, s.validateProcessingProvider("tika"))
+	assert.NoError(t, s.validateProcessingProvider(""), "empty chooses the default")
+	assert.Error(t, s.validateProcessingProvider("unstructured"))
+}
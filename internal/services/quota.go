@@ -0,0 +1,333 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// monthlyQuotaCounters are the SpaceQuotaUsage properties that count each
+// monthly quota
+var monthlyQuotaCounters = map[string]string{
+	models.QuotaAgentExecutions: "agent_executions",
+	models.QuotaStreamEvents:    "stream_events",
+}
+
+// QuotaService enforces the quotas of spaces. Documents and storage are
+// counted from the space's Document nodes when a document is added; agent
+// executions and stream events are counted per calendar month on a
+// SpaceQuotaUsage node, which each execution or event reserves before it
+// runs. Limits default by space type and can be raised per space by
+// administrators. With enforcement off usage is still counted. While
+// quotas are enforced, usage reaching a warning threshold publishes a
+// quota.threshold_reached event.
+type QuotaService struct {
+	neo4j      *database.Neo4jClient
+	audit      *AuditService
+	events     DomainEventPublisher
+	enforced   bool
+	thresholds []int // Warning thresholds in percent, ascending
+	logger     *logger.Logger
+}
+
+// NewQuotaService creates a quota service. audit may be nil, in which case
+// changed limits are only logged.
+func NewQuotaService(neo4j *database.Neo4jClient, audit *AuditService, cfg config.QuotaConfig, log *logger.Logger) *QuotaService {
+	// Validated with the configuration
+	thresholds, _ := cfg.Thresholds()
+	return &QuotaService{
+		neo4j:      neo4j,
+		audit:      audit,
+		enforced:   cfg.Enforced,
+		thresholds: thresholds,
+		logger:     log.WithService("quota_service"),
+	}
+}
+
+// SetEventPublisher sets the publisher of quota threshold events; without
+// one no warnings are sent
+func (s *QuotaService) SetEventPublisher(publisher DomainEventPublisher) {
+	s.events = publisher
+}
+
+// Usage returns a space's use of its quotas
+func (s *QuotaService) Usage(ctx context.Context, spaceID string) (*models.SpaceQuotaUsage, error) {
+	quotas, err := s.limits(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	if quotas == nil {
+		return nil, errors.NotFoundWithDetails("Space not found", map[string]interface{}{"space_id": spaceID})
+	}
+
+	documents, bytes, err := s.documentUsage(ctx, spaceID)
+	if err != nil {
+		return nil, errors.Database("Failed to read space usage", err)
+	}
+
+	period, resetsAt := monthPeriod(time.Now())
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "quota.monthly_usage"), `
+		MATCH (u:SpaceQuotaUsage {space_id: $space_id, period: $period})
+		RETURN u.agent_executions AS agent_executions, u.stream_events AS stream_events
+	`, map[string]interface{}{"space_id": spaceID, "period": period})
+	if err != nil {
+		return nil, errors.Database("Failed to read space usage", err)
+	}
+	var executions, events int64
+	if len(result.Records) > 0 {
+		executions = recordInt64(result.Records[0], "agent_executions")
+		events = recordInt64(result.Records[0], "stream_events")
+	}
+
+	return &models.SpaceQuotaUsage{
+		SpaceID:  spaceID,
+		Period:   period,
+		ResetsAt: resetsAt,
+		Enforced: s.enforced,
+		Quotas: []*models.QuotaUsage{
+			models.NewQuotaUsage(models.QuotaDocuments, documents, int64(quotas.MaxDocuments), false),
+			models.NewQuotaUsage(models.QuotaStorageBytes, bytes, quotas.MaxStorageBytes, false),
+			models.NewQuotaUsage(models.QuotaAgentExecutions, executions, quotas.MaxAgentExecutions, true),
+			models.NewQuotaUsage(models.QuotaStreamEvents, events, quotas.MaxStreamEvents, true),
+		},
+	}, nil
+}
+
+// CheckDocument refuses a new document of sizeBytes that would take the
+// space over its document or storage quota
+func (s *QuotaService) CheckDocument(ctx context.Context, spaceID string, sizeBytes int64) error {
+	return s.checkDocuments(ctx, spaceID, 1, sizeBytes)
+}
+
+// CheckStorage refuses sizeBytes more, such as a new version of a
+// document, that would take the space over its storage quota
+func (s *QuotaService) CheckStorage(ctx context.Context, spaceID string, sizeBytes int64) error {
+	return s.checkDocuments(ctx, spaceID, 0, sizeBytes)
+}
+
+// checkDocuments refuses adding documents and bytes over the space's
+// quotas, and warns when an addition it allows reaches a warning
+// threshold. Concurrent uploads are checked against the same count, so a
+// space can end slightly over its quota.
+func (s *QuotaService) checkDocuments(ctx context.Context, spaceID string, documents int, sizeBytes int64) error {
+	if !s.enforced || spaceID == "" {
+		return nil
+	}
+	quotas, err := s.limits(ctx, spaceID)
+	if err != nil || quotas == nil {
+		return err
+	}
+
+	used, bytes, err := s.documentUsage(ctx, spaceID)
+	if err != nil {
+		return errors.Database("Failed to check space quota", err)
+	}
+	if documents > 0 && used+int64(documents) > int64(quotas.MaxDocuments) {
+		return errors.PaymentRequiredWithDetails("The space has reached its document quota", map[string]interface{}{
+			"space_id": spaceID,
+			"quota":    models.QuotaDocuments,
+			"limit":    quotas.MaxDocuments,
+			"used":     used,
+		}).WithErrorCode(errors.CodeDocumentQuotaExceeded)
+	}
+	if bytes+sizeBytes > quotas.MaxStorageBytes {
+		return errors.PaymentRequiredWithDetails("The file exceeds the space's storage quota", map[string]interface{}{
+			"space_id":  spaceID,
+			"quota":     models.QuotaStorageBytes,
+			"limit":     quotas.MaxStorageBytes,
+			"used":      bytes,
+			"requested": sizeBytes,
+		}).WithErrorCode(errors.CodeStorageQuotaExceeded)
+	}
+
+	s.warn(ctx, spaceID, models.QuotaDocuments, used, used+int64(documents), int64(quotas.MaxDocuments))
+	s.warn(ctx, spaceID, models.QuotaStorageBytes, bytes, bytes+sizeBytes, quotas.MaxStorageBytes)
+	return nil
+}
+
+// ReserveAgentExecution counts an agent execution against the space's
+// monthly quota, or refuses it when the quota is used up
+func (s *QuotaService) ReserveAgentExecution(ctx context.Context, spaceID string) error {
+	return s.reserve(ctx, spaceID, models.QuotaAgentExecutions, errors.CodeAgentExecutionQuotaExceeded,
+		"The space has used its agent executions for this month")
+}
+
+// ReserveStreamEvent counts a stream event against the space's monthly
+// quota, or refuses it when the quota is used up
+func (s *QuotaService) ReserveStreamEvent(ctx context.Context, spaceID string) error {
+	return s.reserve(ctx, spaceID, models.QuotaStreamEvents, errors.CodeStreamEventQuotaExceeded,
+		"The space has used its stream events for this month")
+}
+
+// reserve adds one to a monthly counter unless that would exceed the
+// quota. The usage node is locked before its counter is read, so
+// concurrent reservations cannot overshoot the quota.
+func (s *QuotaService) reserve(ctx context.Context, spaceID, quota, code, message string) error {
+	if spaceID == "" {
+		return nil
+	}
+	quotas, err := s.limits(ctx, spaceID)
+	if err != nil || quotas == nil {
+		return err
+	}
+	limit := quotas.MaxStreamEvents
+	if quota == models.QuotaAgentExecutions {
+		limit = quotas.MaxAgentExecutions
+	}
+
+	counter := monthlyQuotaCounters[quota]
+	period, resetsAt := monthPeriod(time.Now())
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "quota.reserve"), fmt.Sprintf(`
+		MERGE (u:SpaceQuotaUsage {space_id: $space_id, period: $period})
+		ON CREATE SET u.agent_executions = 0, u.stream_events = 0, u.created_at = $now
+		SET u.updated_at = $now
+		WITH u
+		WHERE NOT $enforced OR coalesce(u.%[1]s, 0) < $limit
+		SET u.%[1]s = coalesce(u.%[1]s, 0) + 1
+		RETURN u.%[1]s AS used
+	`, counter), map[string]interface{}{
+		"space_id": spaceID,
+		"period":   period,
+		"limit":    limit,
+		"enforced": s.enforced,
+		"now":      time.Now().UTC(),
+	})
+	if err != nil {
+		return errors.Database("Failed to check space quota", err)
+	}
+	if len(result.Records) > 0 {
+		if s.enforced {
+			used := recordInt64(result.Records[0], "used")
+			s.warn(ctx, spaceID, quota, used-1, used, limit)
+		}
+		return nil
+	}
+
+	s.logger.Info("Space quota exhausted",
+		zap.String("space_id", spaceID),
+		zap.String("quota", quota),
+		zap.Int64("limit", limit))
+	return errors.NewAPIError(errors.ErrTooManyRequests, message, map[string]interface{}{
+		"space_id":  spaceID,
+		"quota":     quota,
+		"limit":     limit,
+		"used":      limit,
+		"resets_at": resetsAt,
+	}).WithErrorCode(code)
+}
+
+// UpdateLimits changes the limits of a space's quotas
+func (s *QuotaService) UpdateLimits(ctx context.Context, spaceID string, req models.SpaceQuotasUpdateRequest, actorID string) (*models.SpaceQuotas, error) {
+	quotas, err := s.limits(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	if quotas == nil {
+		return nil, errors.NotFoundWithDetails("Space not found", map[string]interface{}{"space_id": spaceID})
+	}
+	req.Apply(quotas)
+
+	stored, err := json.Marshal(quotas)
+	if err != nil {
+		return nil, errors.InternalWithCause("Failed to serialize space quotas", err)
+	}
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "quota.update_limits"), `
+		MATCH (sp:Space {id: $space_id})
+		SET sp.quotas = $quotas, sp.updated_at = datetime()
+		RETURN sp.tenant_id AS tenant_id
+	`, map[string]interface{}{"space_id": spaceID, "quotas": string(stored)})
+	if err != nil {
+		return nil, errors.Database("Failed to update space quotas", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, errors.NotFoundWithDetails("Space not found", map[string]interface{}{"space_id": spaceID})
+	}
+
+	s.logger.FromContext(ctx).Info("Space quotas changed",
+		zap.Bool("audit", true),
+		zap.String("space_id", spaceID),
+		zap.String("actor_id", actorID),
+	)
+	recordAuditEvent(ctx, s.audit, s.logger, &models.AuditEvent{
+		Action:       "space.quotas.update",
+		ResourceType: "space",
+		ResourceID:   spaceID,
+		SpaceID:      spaceID,
+		TenantID:     recordString(result.Records[0], "tenant_id"),
+		ActorID:      actorID,
+		Source:       ConfigSourceAdmin,
+		Details: map[string]interface{}{
+			"max_documents":        quotas.MaxDocuments,
+			"max_storage_bytes":    quotas.MaxStorageBytes,
+			"max_agent_executions": quotas.MaxAgentExecutions,
+			"max_stream_events":    quotas.MaxStreamEvents,
+		},
+	})
+	return quotas, nil
+}
+
+// limits returns the quotas of a space, nil when the space does not exist
+func (s *QuotaService) limits(ctx context.Context, spaceID string) (*models.SpaceQuotas, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "quota.limits"), `
+		MATCH (sp:Space {id: $space_id})
+		RETURN sp.space_type AS type, sp.quotas AS quotas
+	`, map[string]interface{}{"space_id": spaceID})
+	if err != nil {
+		return nil, errors.Database("Failed to read space quotas", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, nil
+	}
+
+	record := result.Records[0]
+	quotas, err := spaceQuotas(models.SpaceType(recordString(record, "type")), recordString(record, "quotas"))
+	if err != nil {
+		s.logger.Warn("Failed to parse space quotas", zap.String("space_id", spaceID), zap.Error(err))
+	}
+	return quotas, nil
+}
+
+// documentUsage counts the documents of a space outside the trash and the
+// bytes of their files, every stored version included
+func (s *QuotaService) documentUsage(ctx context.Context, spaceID string) (int64, int64, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "quota.document_usage"), `
+		MATCH (d:Document {space_id: $space_id})
+		WHERE `+database.NotDeleted("d")+`
+		OPTIONAL MATCH (d)-[:HAS_VERSION]->(v:DocumentVersion)
+		WITH d, count(v) AS versions, sum(coalesce(v.size_bytes, 0)) AS versioned
+		RETURN count(d) AS documents,
+		       sum(CASE WHEN versions = 0 THEN coalesce(d.size_bytes, 0) ELSE versioned END) AS bytes
+	`, map[string]interface{}{"space_id": spaceID})
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(result.Records) == 0 {
+		return 0, 0, nil
+	}
+	return recordInt64(result.Records[0], "documents"), recordInt64(result.Records[0], "bytes"), nil
+}
+
+// spaceQuotas returns the default quotas of a space type with the limits
+// stored on a space applied. The defaults are returned with the error when
+// the stored limits cannot be read.
+func spaceQuotas(spaceType models.SpaceType, stored string) (*models.SpaceQuotas, error) {
+	quotas := models.DefaultSpaceQuotas(spaceType)
+	if stored == "" {
+		return quotas, nil
+	}
+	if err := json.Unmarshal([]byte(stored), quotas); err != nil {
+		return models.DefaultSpaceQuotas(spaceType), err
+	}
+	// Usage is counted, never stored
+	quotas.UsedNotebooks, quotas.UsedDocuments, quotas.UsedStorageBytes = 0, 0, 0
+	return quotas, nil
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+func TestSpaceQuotas(t *testing.T) {
+	defaults := models.DefaultSpaceQuotas(models.SpaceTypePersonal)
+
+	quotas, err := spaceQuotas(models.SpaceTypePersonal, "")
+	require.NoError(t, err)
+	assert.Equal(t, defaults, quotas)
+
+	quotas, err = spaceQuotas(models.SpaceTypePersonal, `{"max_documents":5000,"max_agent_executions":10,"used_documents":42}`)
+	require.NoError(t, err)
+	assert.Equal(t, 5000, quotas.MaxDocuments)
+	assert.Equal(t, int64(10), quotas.MaxAgentExecutions)
+	assert.Equal(t, defaults.MaxStorageBytes, quotas.MaxStorageBytes, "limits not stored keep their default")
+	assert.Zero(t, quotas.UsedDocuments, "usage is never read from the store")
+
+	quotas, err = spaceQuotas(models.SpaceTypeOrganization, "{not json")
+	assert.Error(t, err)
+	assert.Equal(t, models.DefaultSpaceQuotas(models.SpaceTypeOrganization), quotas)
+}
+
+func TestSpaceQuotasUpdateRequestApply(t *testing.T) {
+	documents, events := 20, int64(500)
+	quotas := models.DefaultSpaceQuotas(models.SpaceTypeOrganization)
+	storage := quotas.MaxStorageBytes
+
+	req := models.SpaceQuotasUpdateRequest{MaxDocuments: &documents, MaxStreamEvents: &events}
+	req.Apply(quotas)
+
+	assert.Equal(t, 20, quotas.MaxDocuments)
+	assert.Equal(t, int64(500), quotas.MaxStreamEvents)
+	assert.Equal(t, storage, quotas.MaxStorageBytes)
+}
+
+func TestNewQuotaUsage(t *testing.T) {
+	usage := models.NewQuotaUsage(models.QuotaDocuments, 750, 1000, false)
+	assert.Equal(t, int64(250), usage.Remaining)
+	assert.Equal(t, 75.0, usage.Percent)
+
+	over := models.NewQuotaUsage(models.QuotaAgentExecutions, 1200, 1000, true)
+	assert.Zero(t, over.Remaining)
+	assert.Equal(t, 120.0, over.Percent)
+	assert.True(t, over.Monthly)
+
+	spaceUsage := &models.SpaceQuotaUsage{Quotas: []*models.QuotaUsage{usage, over}}
+	assert.Same(t, over, spaceUsage.Quota(models.QuotaAgentExecutions))
+	assert.Nil(t, spaceUsage.Quota(models.QuotaStreamEvents))
+}
+
+func TestCrossedThreshold(t *testing.T) {
+	thresholds := []int{80, 90, 100}
+
+	assert.Equal(t, 80, crossedThreshold(thresholds, 79, 80, 100))
+	assert.Zero(t, crossedThreshold(thresholds, 80, 81, 100), "a threshold is reached once")
+	assert.Equal(t, 90, crossedThreshold(thresholds, 70, 95, 100), "the highest threshold passed is reported")
+	assert.Equal(t, 100, crossedThreshold(thresholds, 9, 10, 10))
+	assert.Zero(t, crossedThreshold(thresholds, 100, 100, 100), "usage did not grow")
+	assert.Zero(t, crossedThreshold(thresholds, 0, 5, 0), "no limit")
+	assert.Equal(t, 80, crossedThreshold(thresholds, 4<<30-1<<20, 4<<30, 5<<30), "storage bytes")
+}
+
+func TestQuotaChecksWithoutSpace(t *testing.T) {
+	ctx := context.Background()
+
+	// Neither needs the database: enforcement is off, or there is no space
+	// to count against
+	off := NewQuotaService(nil, nil, config.QuotaConfig{Enforced: false}, setupTestLogger(t))
+	assert.NoError(t, off.CheckDocument(ctx, "space-1", 1<<40))
+
+	on := NewQuotaService(nil, nil, config.QuotaConfig{Enforced: true}, setupTestLogger(t))
+	assert.NoError(t, on.CheckDocument(ctx, "", 1<<40))
+	assert.NoError(t, on.ReserveAgentExecution(ctx, ""))
+	assert.NoError(t, on.ReserveStreamEvent(ctx, ""))
+}
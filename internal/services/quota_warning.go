@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+)
+
+// quotaWarningRoles are the roles, in a space or in the organization that
+// owns it, whose users are warned about the space's quotas
+var quotaWarningRoles = []string{"owner", "admin"}
+
+// crossedThreshold returns the highest threshold, in percent of limit,
+// that usage reached going from before to after, or 0 when it reached none
+func crossedThreshold(thresholds []int, before, after, limit int64) int {
+	if limit <= 0 || after <= before {
+		return 0
+	}
+	crossed := 0
+	for _, threshold := range thresholds {
+		mark := int64(threshold) * limit
+		if before*100 < mark && mark <= after*100 {
+			crossed = threshold
+		}
+	}
+	return crossed
+}
+
+// warn publishes a quota threshold event when usage of a space's quota
+// going from before to after reached a warning threshold. Each threshold
+// of a quota is reported once per calendar month: a SpaceQuotaWarning node
+// remembers the highest one reported. Warnings are best effort, so
+// failures are logged.
+func (s *QuotaService) warn(ctx context.Context, spaceID, quota string, before, after, limit int64) {
+	threshold := crossedThreshold(s.thresholds, before, after, limit)
+	if threshold == 0 || s.events == nil {
+		return
+	}
+
+	period, _ := monthPeriod(time.Now())
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "quota.warn"), `
+		MERGE (w:SpaceQuotaWarning {space_id: $space_id, quota: $quota, period: $period})
+		ON CREATE SET w.threshold = 0, w.created_at = $now
+		WITH w
+		WHERE w.threshold < $threshold
+		SET w.threshold = $threshold, w.updated_at = $now
+		WITH w
+		MATCH (sp:Space {id: $space_id})
+		OPTIONAL MATCH (owner:User)-[:OWNS]->(sp)
+		WITH sp, collect(owner.id) AS owners
+		OPTIONAL MATCH (member:User)-[m:MEMBER_OF]->(sp)
+		WHERE m.role IN $roles
+		WITH sp, owners + collect(member.id) AS users
+		OPTIONAL MATCH (:Organization {id: sp.owner_id})<-[om:MEMBER_OF]-(admin:User)
+		WHERE sp.owner_type = 'organization' AND om.role IN $roles
+		RETURN sp.tenant_id AS tenant_id, users + collect(admin.id) AS user_ids
+	`, map[string]interface{}{
+		"space_id":  spaceID,
+		"quota":     quota,
+		"period":    period,
+		"threshold": threshold,
+		"roles":     quotaWarningRoles,
+		"now":       time.Now().UTC(),
+	})
+	if err != nil {
+		s.logger.Warn("Failed to record quota warning",
+			zap.String("space_id", spaceID),
+			zap.String("quota", quota),
+			zap.Error(err))
+		return
+	}
+	if len(result.Records) == 0 {
+		// Already reported this month
+		return
+	}
+
+	record := result.Records[0]
+	s.logger.Info("Space quota threshold reached",
+		zap.String("space_id", spaceID),
+		zap.String("quota", quota),
+		zap.Int("threshold", threshold),
+		zap.Int64("used", after),
+		zap.Int64("limit", limit))
+	publishDomainEvent(ctx, s.events, s.logger, Event{
+		Type:    EventQuotaThresholdReached,
+		Subject: spaceID,
+		Data: map[string]interface{}{
+			"tenant_id":       recordString(record, "tenant_id"),
+			"space_id":        spaceID,
+			"quota":           quota,
+			"threshold":       threshold,
+			"used":            after,
+			"limit":           limit,
+			"period":          period,
+			"notify_user_ids": uniqueStrings(recordStrings(record, "user_ids")),
+		},
+	})
+}
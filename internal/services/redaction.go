@@ -0,0 +1,290 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// redactionPageSize is how many chunks are read at a time to assemble a
+// redacted text
+const redactionPageSize = 100
+
+// ChunkSource lists the chunks of processed files.
+// AudiModalService implements it.
+type ChunkSource interface {
+	GetFileChunks(ctx context.Context, tenantID string, fileID string, limit, offset int) (*ChunksResponse, error)
+}
+
+// RedactionService manages the redaction rules of notebooks and decides
+// which users they apply to. Rules are kept on the notebook node.
+type RedactionService struct {
+	neo4j     *database.Neo4jClient
+	notebooks *NotebookService
+	chunks    ChunkSource
+	logger    *logger.Logger
+}
+
+// NewRedactionService creates a new redaction service. chunks may be nil
+// when no processing service is configured, in which case redacted texts
+// cannot be assembled.
+func NewRedactionService(neo4j *database.Neo4jClient, notebooks *NotebookService, chunks ChunkSource, log *logger.Logger) *RedactionService {
+	return &RedactionService{
+		neo4j:     neo4j,
+		notebooks: notebooks,
+		chunks:    chunks,
+		logger:    log.WithService("redaction_service"),
+	}
+}
+
+// SetRules sets the redaction rules of a notebook, replacing the ones it
+// had. Only the notebook's owner manages its rules.
+func (s *RedactionService) SetRules(ctx context.Context, notebookID string, req models.RedactionRulesRequest, userID string, spaceCtx *models.SpaceContext) (*models.RedactionRules, error) {
+	if err := s.ownNotebook(ctx, notebookID, userID, spaceCtx); err != nil {
+		return nil, err
+	}
+
+	roles := uniqueStrings(req.Roles)
+	if len(roles) == 0 {
+		roles = models.DefaultRedactionRoles
+	}
+	sections := make([]string, 0, len(req.Sections))
+	for _, section := range req.Sections {
+		if section = strings.TrimSpace(section); section != "" {
+			sections = append(sections, section)
+		}
+	}
+	sections = uniqueStrings(sections)
+	if !req.OmitPIIChunks && len(sections) == 0 {
+		return nil, errors.Validation("Redaction rules omit PII chunks or sections", nil)
+	}
+
+	now := time.Now().UTC()
+	_, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "redaction.set"), `
+		MATCH (n:Notebook {id: $notebook_id, tenant_id: $tenant_id})
+		SET n.redaction_roles = $roles,
+		    n.redaction_omit_pii = $omit_pii,
+		    n.redaction_sections = $sections,
+		    n.redaction_updated_by = $user_id,
+		    n.redaction_updated_at = $now
+	`, map[string]interface{}{
+		"notebook_id": notebookID,
+		"tenant_id":   spaceCtx.TenantID,
+		"roles":       roles,
+		"omit_pii":    req.OmitPIIChunks,
+		"sections":    sections,
+		"user_id":     userID,
+		"now":         now,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to set redaction rules", err)
+	}
+
+	s.logger.Info("Redaction rules set",
+		zap.String("notebook_id", notebookID),
+		zap.String("user_id", userID),
+		zap.Strings("roles", roles))
+	return &models.RedactionRules{
+		NotebookID:    notebookID,
+		Roles:         roles,
+		OmitPIIChunks: req.OmitPIIChunks,
+		Sections:      sections,
+		UpdatedBy:     userID,
+		UpdatedAt:     now,
+	}, nil
+}
+
+// GetRules returns the redaction rules of a notebook
+func (s *RedactionService) GetRules(ctx context.Context, notebookID, userID string, spaceCtx *models.SpaceContext) (*models.RedactionRules, error) {
+	if err := s.ownNotebook(ctx, notebookID, userID, spaceCtx); err != nil {
+		return nil, err
+	}
+	rules, err := s.notebookRules(ctx, spaceCtx.TenantID, notebookID)
+	if err != nil {
+		return nil, err
+	}
+	if rules == nil {
+		return nil, s.rulesNotFound(notebookID)
+	}
+	return rules, nil
+}
+
+// DeleteRules removes the redaction rules of a notebook; its documents are
+// read whole by everyone it is shared with from then on
+func (s *RedactionService) DeleteRules(ctx context.Context, notebookID, userID string, spaceCtx *models.SpaceContext) error {
+	if err := s.ownNotebook(ctx, notebookID, userID, spaceCtx); err != nil {
+		return err
+	}
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "redaction.delete"), `
+		MATCH (n:Notebook {id: $notebook_id, tenant_id: $tenant_id})
+		WHERE n.redaction_roles IS NOT NULL
+		REMOVE n.redaction_roles, n.redaction_omit_pii, n.redaction_sections,
+		       n.redaction_updated_by, n.redaction_updated_at
+		RETURN n.id as id
+	`, map[string]interface{}{
+		"notebook_id": notebookID,
+		"tenant_id":   spaceCtx.TenantID,
+	})
+	if err != nil {
+		return errors.Database("Failed to delete redaction rules", err)
+	}
+	if len(result.Records) == 0 {
+		return s.rulesNotFound(notebookID)
+	}
+
+	s.logger.Info("Redaction rules deleted", zap.String("notebook_id", notebookID), zap.String("user_id", userID))
+	return nil
+}
+
+// RulesFor returns the redaction rules that apply to a user reading a
+// document, or nil when the user reads it whole: its owner, the space's
+// owners and admins, and users the notebook is not shared with at a
+// redacted role
+func (s *RedactionService) RulesFor(ctx context.Context, document *models.Document, userID string, spaceCtx *models.SpaceContext) (*models.RedactionRules, error) {
+	return s.rulesFor(ctx, document.TenantID, document.OwnerID, document.NotebookID, userID, spaceCtx)
+}
+
+// RulesForFile returns the redaction rules that apply to a user reading
+// the chunks of a processed file, found by the processing job ID of its
+// document; see RulesFor
+func (s *RedactionService) RulesForFile(ctx context.Context, fileID, userID string, spaceCtx *models.SpaceContext) (*models.RedactionRules, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "redaction.file_document"), `
+		MATCH (d:Document {tenant_id: $tenant_id})
+		WHERE d.processing_job_id = $file_id OR d.id = $file_id
+		RETURN d.owner_id as owner_id, d.notebook_id as notebook_id
+		LIMIT 1
+	`, map[string]interface{}{
+		"tenant_id": spaceCtx.TenantID,
+		"file_id":   fileID,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to find the document of a file", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, nil
+	}
+	record := result.Records[0]
+	return s.rulesFor(ctx, spaceCtx.TenantID, recordString(record, "owner_id"), recordString(record, "notebook_id"), userID, spaceCtx)
+}
+
+func (s *RedactionService) rulesFor(ctx context.Context, tenantID, ownerID, notebookID, userID string, spaceCtx *models.SpaceContext) (*models.RedactionRules, error) {
+	if ownerID == userID || notebookID == "" || spaceCtx.CanManage() {
+		return nil, nil
+	}
+	rules, err := s.notebookRules(ctx, tenantID, notebookID)
+	if err != nil || rules == nil {
+		return nil, err
+	}
+	role, err := s.notebooks.NotebookRole(ctx, tenantID, notebookID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !rules.AppliesTo(role) {
+		return nil, nil
+	}
+	return rules, nil
+}
+
+// notebookRules reads the redaction rules of a notebook, or nil when it has
+// none
+func (s *RedactionService) notebookRules(ctx context.Context, tenantID, notebookID string) (*models.RedactionRules, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "redaction.get"), `
+		MATCH (n:Notebook {id: $notebook_id, tenant_id: $tenant_id})
+		WHERE n.redaction_roles IS NOT NULL
+		RETURN n.redaction_roles as roles, n.redaction_omit_pii as omit_pii,
+		       n.redaction_sections as sections, n.redaction_updated_by as updated_by,
+		       n.redaction_updated_at as updated_at
+	`, map[string]interface{}{
+		"notebook_id": notebookID,
+		"tenant_id":   tenantID,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to read redaction rules", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, nil
+	}
+	return recordToRedactionRules(notebookID, result.Records[0]), nil
+}
+
+func recordToRedactionRules(notebookID string, record *neo4j.Record) *models.RedactionRules {
+	omitPII, _ := record.Get("omit_pii")
+	rules := &models.RedactionRules{
+		NotebookID: notebookID,
+		Roles:      recordStrings(record, "roles"),
+		Sections:   recordStrings(record, "sections"),
+		UpdatedBy:  recordString(record, "updated_by"),
+		UpdatedAt:  recordTime(record, "updated_at"),
+	}
+	rules.OmitPIIChunks, _ = omitPII.(bool)
+	return rules
+}
+
+// RedactText assembles the text of a document from the chunks the rules
+// keep, in chunk order, and returns it with the number of chunks left out
+func (s *RedactionService) RedactText(ctx context.Context, document *models.Document, rules *models.RedactionRules) (string, int, error) {
+	if s.chunks == nil {
+		return "", 0, errors.ServiceUnavailable("Text extraction service not available")
+	}
+	fileID := documentFileID(document)
+
+	var kept []ChunkData
+	omitted := 0
+	for offset := 0; ; offset += redactionPageSize {
+		page, err := s.chunks.GetFileChunks(ctx, document.TenantID, fileID, redactionPageSize, offset)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return "", 0, errors.FileNotProcessedWithDetails("File has not been processed or chunks not found", map[string]interface{}{
+					"document_id": document.ID,
+				})
+			}
+			return "", 0, errors.ExternalService("Failed to read the chunks of the document", err)
+		}
+		for _, chunk := range page.Data {
+			if rules.Omits(chunk.PIIDetected, models.ChunkSection(chunk.Context, chunk.Metadata)) {
+				omitted++
+				continue
+			}
+			kept = append(kept, chunk)
+		}
+		if len(page.Data) < redactionPageSize || offset+len(page.Data) >= page.Total {
+			break
+		}
+	}
+
+	sort.SliceStable(kept, func(i, j int) bool {
+		return kept[i].ChunkNumber < kept[j].ChunkNumber
+	})
+	parts := make([]string, len(kept))
+	for i, chunk := range kept {
+		parts[i] = chunk.Content
+	}
+	return strings.Join(parts, "\n\n"), omitted, nil
+}
+
+// ownNotebook checks that the user owns the notebook
+func (s *RedactionService) ownNotebook(ctx context.Context, notebookID, userID string, spaceCtx *models.SpaceContext) error {
+	notebook, err := s.notebooks.GetNotebookByID(ctx, notebookID, userID, spaceCtx)
+	if err != nil {
+		return err
+	}
+	if notebook.OwnerID != userID {
+		return errors.Forbidden("Only the notebook owner can manage its redaction rules")
+	}
+	return nil
+}
+
+func (s *RedactionService) rulesNotFound(notebookID string) error {
+	return errors.NotFoundWithDetails("Notebook has no redaction rules", map[string]interface{}{
+		"notebook_id": notebookID,
+	}).WithErrorCode(errors.CodeRedactionRulesNotFound)
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// fakeChunkSource serves chunks from memory, a page at a time
+type fakeChunkSource struct {
+	chunks []ChunkData
+	fileID string
+}
+
+func (f *fakeChunkSource) GetFileChunks(ctx context.Context, tenantID string, fileID string, limit, offset int) (*ChunksResponse, error) {
+	if fileID != f.fileID {
+		return nil, errors.NotFound("File not found")
+	}
+	end := offset + limit
+	if end > len(f.chunks) {
+		end = len(f.chunks)
+	}
+	page := []ChunkData{}
+	if offset < end {
+		page = f.chunks[offset:end]
+	}
+	return &ChunksResponse{Data: page, Total: len(f.chunks), Limit: limit, Offset: offset}, nil
+}
+
+func TestRedactionRulesOmits(t *testing.T) {
+	rules := &models.RedactionRules{Roles: []string{"viewer"}, OmitPIIChunks: true, Sections: []string{" Salaries "}}
+
+	assert.True(t, rules.AppliesTo("viewer"))
+	assert.False(t, rules.AppliesTo("editor"))
+	assert.False(t, rules.AppliesTo(""))
+
+	assert.True(t, rules.Omits(true, ""))
+	assert.True(t, rules.Omits(false, "salaries"))
+	assert.False(t, rules.Omits(false, "Benefits"))
+	assert.False(t, rules.Omits(false, ""))
+
+	rules.OmitPIIChunks = false
+	assert.False(t, rules.Omits(true, "Benefits"))
+}
+
+func TestChunkSection(t *testing.T) {
+	assert.Equal(t, "Salaries", models.ChunkSection(map[string]string{"section": " Salaries "}, nil))
+	assert.Equal(t, "Appendix", models.ChunkSection(nil, map[string]interface{}{"heading": "Appendix"}))
+	assert.Equal(t, "Intro", models.ChunkSection(map[string]string{"section": "", "section_title": "Intro"}, map[string]interface{}{"heading": "Other"}))
+	assert.Empty(t, models.ChunkSection(nil, map[string]interface{}{"heading": 3}))
+}
+
+func TestRedactTextKeepsChunkOrder(t *testing.T) {
+	source := &fakeChunkSource{fileID: "file_1"}
+	for i := 250; i > 0; i-- {
+		chunk := ChunkData{ChunkNumber: i, Content: fmt.Sprintf("chunk %d", i)}
+		switch {
+		case i%50 == 0:
+			chunk.PIIDetected = true
+		case i == 3:
+			chunk.Context = map[string]string{"section": "Salaries"}
+		}
+		source.chunks = append(source.chunks, chunk)
+	}
+	s := &RedactionService{chunks: source, logger: setupTestLogger(t)}
+	document := &models.Document{ID: "doc_1", TenantID: "tenant_1", ProcessingJobID: "file_1"}
+	rules := &models.RedactionRules{OmitPIIChunks: true, Sections: []string{"salaries"}}
+
+	text, omitted, err := s.RedactText(context.Background(), document, rules)
+	require.NoError(t, err)
+	assert.Equal(t, 6, omitted)
+	assert.Contains(t, text, "chunk 1\n\nchunk 2\n\nchunk 4\n\n")
+	assert.NotContains(t, text, "chunk 3\n")
+	assert.NotContains(t, text, "chunk 50\n")
+	assert.NotContains(t, text, "chunk 250")
+	assert.Contains(t, text, "chunk 249")
+}
+
+func TestRedactTextUnprocessedFile(t *testing.T) {
+	s := &RedactionService{chunks: &fakeChunkSource{fileID: "other"}, logger: setupTestLogger(t)}
+	document := &models.Document{ID: "doc_1", TenantID: "tenant_1", ProcessingJobID: "file_1"}
+
+	_, _, err := s.RedactText(context.Background(), document, &models.RedactionRules{OmitPIIChunks: true})
+	require.Error(t, err)
+	assert.Equal(t, errors.ErrFileNotProcessed, err.(*errors.APIError).Code)
+
+	s.chunks = nil
+	_, _, err = s.RedactText(context.Background(), document, &models.RedactionRules{OmitPIIChunks: true})
+	require.Error(t, err)
+	assert.Equal(t, 503, err.(*errors.APIError).StatusCode)
+}
@@ -0,0 +1,413 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/webhooks"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// tenantRegionFields are the columns recordToTenantRegion reads
+const tenantRegionFields = `r.tenant_id AS tenant_id, r.active_region AS active_region, r.passive_region AS passive_region,
+	       r.pinned_at AS pinned_at, r.pinned_by AS pinned_by, r.failed_over_at AS failed_over_at,
+	       coalesce(r.failovers, 0) AS failovers`
+
+// replicationHookEvent is the X-Webhook-Event of replication hook calls
+const replicationHookEvent = "tenant.failover"
+
+// replicationHookPayload is the body of a replication hook call. The hook
+// promotes, or checks, the tenant's replicas in the target region and
+// answers 2xx once the tenant can be served from there.
+type replicationHookPayload struct {
+	Event       string    `json:"event"`
+	TenantID    string    `json:"tenant_id"`
+	FromRegion  string    `json:"from_region"`
+	ToRegion    string    `json:"to_region"`
+	RequestedBy string    `json:"requested_by"`
+	RequestedAt time.Time `json:"requested_at"`
+}
+
+// RegionService pins tenants to the region that serves them in a
+// multi-region deployment, and fails them over to their passive region.
+// Pins are stored as TenantRegion nodes; each replica keeps them in memory,
+// re-read on an interval, so routing a request costs no query. Replicating
+// Neo4j and S3 to the passive region is left to the stores; replication
+// hooks are called before a failover to promote or check the replicas.
+type RegionService struct {
+	neo4j    *database.Neo4jClient
+	audit    *AuditService
+	local    string
+	urls     map[string]string
+	hooks    []config.ReplicationHook
+	secret   string
+	client   *http.Client
+	interval time.Duration
+	pins     atomic.Pointer[map[string]*models.TenantRegion]
+	logger   *logger.Logger
+}
+
+// NewRegionService creates a region service for the region cfg names. audit
+// may be nil, in which case pins and failovers are only logged.
+func NewRegionService(neo4j *database.Neo4jClient, audit *AuditService, cfg config.RegionConfig, log *logger.Logger) *RegionService {
+	// Validate rejects bad regions and hooks at startup
+	urls, _ := cfg.RegionURLs()
+	hooks, _ := cfg.Hooks()
+
+	s := &RegionService{
+		neo4j:    neo4j,
+		audit:    audit,
+		local:    cfg.Name,
+		urls:     urls,
+		hooks:    hooks,
+		secret:   cfg.HookSecret,
+		client:   &http.Client{Timeout: time.Duration(cfg.HookTimeoutSecs) * time.Second},
+		interval: time.Duration(cfg.RefreshSeconds) * time.Second,
+		logger:   log.WithService("region_service"),
+	}
+	s.pins.Store(&map[string]*models.TenantRegion{})
+	return s
+}
+
+// Run reads the pins now and then every interval until ctx is cancelled
+func (s *RegionService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Refresh(ctx); err != nil && ctx.Err() == nil {
+			// The pins last read stay in effect
+			s.logger.Warn("Failed to read tenant regions", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh re-reads the pins from Neo4j
+func (s *RegionService) Refresh(ctx context.Context) error {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "region.pins"), `
+		MATCH (r:TenantRegion)
+		RETURN `+tenantRegionFields, nil)
+	if err != nil {
+		return err
+	}
+
+	pins := make(map[string]*models.TenantRegion, len(result.Records))
+	for _, record := range result.Records {
+		pin := recordToTenantRegion(record)
+		pins[pin.TenantID] = pin
+	}
+	s.pins.Store(&pins)
+	return nil
+}
+
+// LocalRegion returns the region this deployment serves
+func (s *RegionService) LocalRegion() string {
+	return s.local
+}
+
+// RegionURL returns the base URL of a region, empty when it is unknown
+func (s *RegionService) RegionURL(region string) string {
+	return s.urls[region]
+}
+
+// Pin returns the pin of a tenant as last read, nil when it has none;
+// tenants without a pin are served by every region
+func (s *RegionService) Pin(tenantID string) *models.TenantRegion {
+	return (*s.pins.Load())[tenantID]
+}
+
+// Regions returns the configured regions with the number of tenants each
+// serves and replicates, read from Neo4j
+func (s *RegionService) Regions(ctx context.Context) (*models.RegionStatus, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "region.counts"), `
+		MATCH (r:TenantRegion)
+		RETURN r.active_region AS active_region, r.passive_region AS passive_region, count(r) AS tenants
+	`, nil)
+	if err != nil {
+		return nil, errors.Database("Failed to count tenants by region", err)
+	}
+
+	byName := make(map[string]*models.Region, len(s.urls))
+	status := &models.RegionStatus{Region: s.local, Regions: make([]*models.Region, 0, len(s.urls))}
+	for name, url := range s.urls {
+		region := &models.Region{Name: name, URL: url, Local: name == s.local}
+		byName[name] = region
+		status.Regions = append(status.Regions, region)
+	}
+	for _, record := range result.Records {
+		tenants := recordInt64(record, "tenants")
+		if region := byName[recordString(record, "active_region")]; region != nil {
+			region.ActiveTenants += tenants
+		}
+		if region := byName[recordString(record, "passive_region")]; region != nil {
+			region.PassiveTenants += tenants
+		}
+	}
+	sort.Slice(status.Regions, func(i, j int) bool {
+		return status.Regions[i].Name < status.Regions[j].Name
+	})
+	return status, nil
+}
+
+// GetPin returns the pin of a tenant, read from Neo4j rather than from this
+// replica's copy
+func (s *RegionService) GetPin(ctx context.Context, tenantID string) (*models.TenantRegion, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "region.get"), `
+		MATCH (r:TenantRegion {tenant_id: $tenant_id})
+		RETURN `+tenantRegionFields, map[string]interface{}{"tenant_id": tenantID})
+	if err != nil {
+		return nil, errors.Database("Failed to get tenant region", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, errors.NotFoundWithDetails("Tenant is not pinned to a region", map[string]interface{}{
+			"tenant_id": tenantID,
+		}).WithErrorCode(errors.CodeTenantRegionNotPinned)
+	}
+	return recordToTenantRegion(result.Records[0]), nil
+}
+
+// SetPin pins a tenant to an active region and, optionally, a passive one
+func (s *RegionService) SetPin(ctx context.Context, tenantID string, req models.TenantRegionRequest, actorID string) (*models.TenantRegion, error) {
+	for _, region := range []string{req.ActiveRegion, req.PassiveRegion} {
+		if err := s.checkRegion(region); region != "" && err != nil {
+			return nil, err
+		}
+	}
+	if req.PassiveRegion == req.ActiveRegion {
+		req.PassiveRegion = ""
+	}
+
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "region.pin"), `
+		MERGE (r:TenantRegion {tenant_id: $tenant_id})
+		SET r.active_region = $active_region, r.passive_region = $passive_region,
+		    r.pinned_at = $now, r.pinned_by = $actor_id
+		RETURN `+tenantRegionFields, map[string]interface{}{
+		"tenant_id":      tenantID,
+		"active_region":  req.ActiveRegion,
+		"passive_region": req.PassiveRegion,
+		"actor_id":       actorID,
+		"now":            time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to pin tenant region", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, errors.Internal("Tenant region was not stored")
+	}
+	pin := recordToTenantRegion(result.Records[0])
+
+	s.refreshNow(ctx)
+	s.recordAudit(ctx, "tenant.region.pin", pin, actorID, map[string]interface{}{
+		"active_region":  pin.ActiveRegion,
+		"passive_region": pin.PassiveRegion,
+	})
+	return pin, nil
+}
+
+// Failover makes a tenant's passive region, or req.TargetRegion, its
+// active one. The replication hooks are called first, in order; when one
+// fails the pin is left alone unless req.Force is set. The regions swap,
+// so the former active region becomes the passive one.
+func (s *RegionService) Failover(ctx context.Context, tenantID string, req models.TenantFailoverRequest, actorID string) (*models.TenantFailover, error) {
+	pin, err := s.GetPin(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	target := req.TargetRegion
+	if target == "" {
+		target = pin.PassiveRegion
+	}
+	if target == "" {
+		return nil, errors.BadRequestWithDetails("Tenant has no passive region to fail over to", map[string]interface{}{
+			"tenant_id": tenantID,
+		}).WithErrorCode(errors.CodeRegionUnknown)
+	}
+	if err := s.checkRegion(target); err != nil {
+		return nil, err
+	}
+	if target == pin.ActiveRegion {
+		return nil, errors.ConflictWithDetails("Tenant is already active in the region", map[string]interface{}{
+			"tenant_id": tenantID,
+			"region":    target,
+		}).WithErrorCode(errors.CodeTenantAlreadyInRegion)
+	}
+
+	failover := &models.TenantFailover{FromRegion: pin.ActiveRegion, ToRegion: target}
+	failover.Hooks = s.runHooks(ctx, replicationHookPayload{
+		Event:       replicationHookEvent,
+		TenantID:    tenantID,
+		FromRegion:  pin.ActiveRegion,
+		ToRegion:    target,
+		RequestedBy: actorID,
+		RequestedAt: time.Now().UTC(),
+	})
+	for _, hook := range failover.Hooks {
+		if !hook.Succeeded() && !req.Force {
+			return nil, errors.NewAPIError(errors.ErrBadGateway, "Replication hook failed, tenant was not failed over", map[string]interface{}{
+				"tenant_id": tenantID,
+				"hooks":     failover.Hooks,
+			}).WithErrorCode(errors.CodeReplicationHookFailed)
+		}
+	}
+
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "region.failover"), `
+		MATCH (r:TenantRegion {tenant_id: $tenant_id})
+		SET r.passive_region = r.active_region, r.active_region = $target,
+		    r.failed_over_at = $now, r.failovers = coalesce(r.failovers, 0) + 1
+		RETURN `+tenantRegionFields, map[string]interface{}{
+		"tenant_id": tenantID,
+		"target":    target,
+		"now":       time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to fail over tenant", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, errors.Internal("Tenant region was not stored")
+	}
+	failover.Tenant = recordToTenantRegion(result.Records[0])
+
+	s.refreshNow(ctx)
+	s.recordAudit(ctx, "tenant.region.failover", failover.Tenant, actorID, map[string]interface{}{
+		"from_region": failover.FromRegion,
+		"to_region":   failover.ToRegion,
+		"forced":      req.Force,
+	})
+	return failover, nil
+}
+
+// runHooks calls the replication hooks in order, stopping at the first
+// that fails
+func (s *RegionService) runHooks(ctx context.Context, payload replicationHookPayload) []*models.ReplicationHookResult {
+	results := make([]*models.ReplicationHookResult, 0, len(s.hooks))
+	body, _ := json.Marshal(payload)
+	for _, hook := range s.hooks {
+		result := s.callHook(ctx, hook, body)
+		results = append(results, result)
+
+		log := s.logger.FromContext(ctx).With(
+			zap.String("hook", hook.Name),
+			zap.String("tenant_id", payload.TenantID),
+			zap.String("to_region", payload.ToRegion),
+		)
+		if !result.Succeeded() {
+			log.Warn("Replication hook failed", zap.Int("status", result.Status), zap.String("error", result.Error))
+			break
+		}
+		log.Info("Replication hook succeeded", zap.Int64("duration_ms", result.DurationMs))
+	}
+	return results
+}
+
+// callHook posts a failover to a replication hook, signed like outbound
+// webhooks
+func (s *RegionService) callHook(ctx context.Context, hook config.ReplicationHook, body []byte) *models.ReplicationHookResult {
+	result := &models.ReplicationHookResult{Name: hook.Name}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		result.Error = fmt.Sprintf("invalid hook request: %v", err)
+		return result
+	}
+	id, now := uuid.NewString(), time.Now().UTC()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-ID", id)
+	req.Header.Set("X-Webhook-Event", replicationHookEvent)
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set("X-Webhook-Signature", webhooks.Sign(s.secret, id, now, body))
+
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	result.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	result.Status = resp.StatusCode
+	return result
+}
+
+// checkRegion returns an error unless region is a configured region
+func (s *RegionService) checkRegion(region string) error {
+	if _, ok := s.urls[region]; ok {
+		return nil
+	}
+	regions := make([]string, 0, len(s.urls))
+	for name := range s.urls {
+		regions = append(regions, name)
+	}
+	sort.Strings(regions)
+	return errors.BadRequestWithDetails("Unknown region", map[string]interface{}{
+		"region":  region,
+		"regions": regions,
+	}).WithErrorCode(errors.CodeRegionUnknown)
+}
+
+// refreshNow applies a changed pin on this replica at once; the others
+// apply it on their next read
+func (s *RegionService) refreshNow(ctx context.Context) {
+	if err := s.Refresh(ctx); err != nil {
+		s.logger.Warn("Failed to read tenant regions", zap.Error(err))
+	}
+}
+
+// recordAudit logs a pin changed and records it in the audit log
+func (s *RegionService) recordAudit(ctx context.Context, action string, pin *models.TenantRegion, actorID string, details map[string]interface{}) {
+	s.logger.FromContext(ctx).Info("Tenant region changed",
+		zap.Bool("audit", true),
+		zap.String("action", action),
+		zap.String("tenant_id", pin.TenantID),
+		zap.String("active_region", pin.ActiveRegion),
+		zap.String("actor_id", actorID),
+	)
+
+	recordAuditEvent(ctx, s.audit, s.logger, &models.AuditEvent{
+		Action:       action,
+		ResourceType: "tenant_region",
+		ResourceID:   pin.TenantID,
+		TenantID:     pin.TenantID,
+		ActorID:      actorID,
+		Source:       ConfigSourceAdmin,
+		Details:      details,
+	})
+}
+
+// recordToTenantRegion reads the tenantRegionFields of a record
+func recordToTenantRegion(record *neo4j.Record) *models.TenantRegion {
+	pin := &models.TenantRegion{
+		TenantID:      recordString(record, "tenant_id"),
+		ActiveRegion:  recordString(record, "active_region"),
+		PassiveRegion: recordString(record, "passive_region"),
+		PinnedAt:      recordTime(record, "pinned_at"),
+		PinnedBy:      recordString(record, "pinned_by"),
+		Failovers:     recordInt64(record, "failovers"),
+	}
+	if at := recordTime(record, "failed_over_at"); !at.IsZero() {
+		pin.FailedOverAt = &at
+	}
+	return pin
+}
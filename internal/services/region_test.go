@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/webhooks"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+func TestRegionHooksStopAtFirstFailure(t *testing.T) {
+	var called []string
+	var payload replicationHookPayload
+	hook := func(name string, status int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = append(called, name)
+			body, _ := io.ReadAll(r.Body)
+			_, err := webhooks.HMACScheme{}.Verify(r.Header, body, "hook-secret")
+			assert.NoError(t, err, name)
+			require.NoError(t, json.Unmarshal(body, &payload))
+			w.WriteHeader(status)
+		}))
+	}
+	neo4jHook, s3Hook, dnsHook := hook("neo4j", http.StatusOK), hook("s3", http.StatusServiceUnavailable), hook("dns", http.StatusOK)
+	defer neo4jHook.Close()
+	defer s3Hook.Close()
+	defer dnsHook.Close()
+
+	service := NewRegionService(nil, nil, config.RegionConfig{
+		Name:             "eu-west",
+		Regions:          "eu-west=https://eu.aether.example.com,us-east=https://us.aether.example.com",
+		RefreshSeconds:   10,
+		ReplicationHooks: "neo4j=" + neo4jHook.URL + ",s3=" + s3Hook.URL + ",dns=" + dnsHook.URL,
+		HookSecret:       "hook-secret",
+		HookTimeoutSecs:  5,
+	}, setupTestLogger(t))
+
+	results := service.runHooks(context.Background(), replicationHookPayload{
+		Event:       replicationHookEvent,
+		TenantID:    "tenant-1",
+		FromRegion:  "eu-west",
+		ToRegion:    "us-east",
+		RequestedAt: time.Now().UTC(),
+	})
+
+	assert.Equal(t, []string{"neo4j", "s3"}, called, "hooks after a failure are not called")
+	require.Len(t, results, 2)
+	assert.True(t, results[0].Succeeded())
+	assert.False(t, results[1].Succeeded())
+	assert.Equal(t, http.StatusServiceUnavailable, results[1].Status)
+	assert.Equal(t, "us-east", payload.ToRegion)
+}
+
+func TestRegionPins(t *testing.T) {
+	service := NewRegionService(nil, nil, config.RegionConfig{
+		Name:           "eu-west",
+		Regions:        "eu-west=https://eu.aether.example.com/,us-east=https://us.aether.example.com",
+		RefreshSeconds: 10,
+	}, setupTestLogger(t))
+
+	assert.Equal(t, "eu-west", service.LocalRegion())
+	assert.Equal(t, "https://eu.aether.example.com", service.RegionURL("eu-west"))
+	assert.Nil(t, service.Pin("tenant-1"), "no tenant is pinned before the pins are read")
+
+	assert.NoError(t, service.checkRegion("us-east"))
+	err := service.checkRegion("ap-south")
+	apiErr, ok := err.(*errors.APIError)
+	require.True(t, ok)
+	assert.Equal(t, errors.CodeRegionUnknown, apiErr.ErrorCode)
+}
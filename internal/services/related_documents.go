@@ -0,0 +1,263 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// Related document limits
+const (
+	// relatedLinksPerDocument is how many SIMILAR_TO relationships a
+	// document gets, to its best ranked similar documents
+	relatedLinksPerDocument = 10
+	// relatedMinScore is the similarity below which documents are not linked
+	relatedMinScore = 0.2
+	// relatedMaxDepth bounds the SIMILAR_TO relationships a traversal
+	// follows; the graph's traversal limit may lower it further
+	relatedMaxDepth = 3
+	// relatedBatchSize is how many queued documents a run of the job links
+	relatedBatchSize = 50
+)
+
+// relatedDocumentsQuery finds the documents within %[1]d SIMILAR_TO
+// relationships of a document, scored by the best path to them. Paths only
+// pass through documents the user may see.
+const relatedDocumentsQuery = `
+	MATCH (d:Document {id: $document_id, tenant_id: $tenant_id})
+	MATCH p = (d)-[:SIMILAR_TO*1..%[1]d]-(o:Document)
+	WHERE o <> d AND all(x IN nodes(p) WHERE %[2]s)
+	WITH o, reduce(score = 1.0, r IN relationships(p) | score * coalesce(r.score, 0.0)) AS score, length(p) AS distance
+	WITH o, max(score) AS score, min(distance) AS distance
+	ORDER BY score DESC, distance, o.id
+	LIMIT $limit
+	RETURN o.id AS id, o.name AS name, o.mime_type AS mime_type, o.notebook_id AS notebook_id,
+	       score, distance
+`
+
+// RelatedDocumentService materializes the similarity of documents in the
+// graph: each processed document gets SIMILAR_TO relationships to the
+// documents SimilarDocumentService ranks best for it, so related documents
+// can be traversed and drawn in graph views
+type RelatedDocumentService struct {
+	neo4j     *database.Neo4jClient
+	documents *DocumentService
+	similar   *SimilarDocumentService
+	logger    *logger.Logger
+}
+
+// NewRelatedDocumentService creates a related document service
+func NewRelatedDocumentService(neo4j *database.Neo4jClient, documents *DocumentService, similar *SimilarDocumentService, log *logger.Logger) *RelatedDocumentService {
+	return &RelatedDocumentService{
+		neo4j:     neo4j,
+		documents: documents,
+		similar:   similar,
+		logger:    log.WithService("related_document_service"),
+	}
+}
+
+// HandleDomainEvent queues processed and updated documents to have their
+// SIMILAR_TO relationships recomputed. It is subscribed to the domain
+// event bus.
+func (s *RelatedDocumentService) HandleDomainEvent(ctx context.Context, event Event) error {
+	if event.Type != EventDocumentProcessed && event.Type != EventDocumentUpdated {
+		return nil
+	}
+	_, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "related.queue"), `
+		MATCH (d:Document {id: $id})
+		SET d.related_pending = true
+	`, map[string]interface{}{"id": event.Subject})
+	if err != nil {
+		return fmt.Errorf("failed to queue related documents: %w", err)
+	}
+	return nil
+}
+
+// LinkPending recomputes the SIMILAR_TO relationships of queued documents.
+// It is a singleton job run by the leader replica.
+func (s *RelatedDocumentService) LinkPending(ctx context.Context) error {
+	ctx = database.WithQueryName(ctx, "related.pending")
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (d:Document)
+		WHERE d.related_pending = true AND `+database.NotDeleted("d")+`
+		RETURN d.id AS id, d.tenant_id AS tenant_id, d.space_id AS space_id
+		ORDER BY d.updated_at
+		LIMIT $limit
+	`, map[string]interface{}{"limit": relatedBatchSize})
+	if err != nil {
+		return fmt.Errorf("failed to list documents to link: %w", err)
+	}
+
+	failed := 0
+	for _, record := range result.Records {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		documentID := recordString(record, "id")
+		spaceCtx := &models.SpaceContext{
+			TenantID: recordString(record, "tenant_id"),
+			SpaceID:  recordString(record, "space_id"),
+		}
+		document, err := s.documents.getDocumentByIDInternal(ctx, documentID, spaceCtx.TenantID)
+		if err == nil {
+			_, err = s.link(ctx, document, spaceCtx)
+		}
+		if err != nil && !errors.IsNotFound(err) {
+			failed++
+			s.logger.Warn("Failed to link related documents", zap.String("document_id", documentID), zap.Error(err))
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d documents failed to link", failed, len(result.Records))
+	}
+	return nil
+}
+
+// GetRelatedDocuments returns up to limit documents within depth SIMILAR_TO
+// relationships of a document the user can read, best first. A document
+// that was never linked is linked first.
+func (s *RelatedDocumentService) GetRelatedDocuments(ctx context.Context, documentID, userID string, spaceCtx *models.SpaceContext, depth, limit int) (*models.RelatedDocumentsResponse, error) {
+	document, err := s.documents.GetDocumentByID(ctx, documentID, userID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+	if depth < 1 {
+		depth = 1
+	}
+	if depth > relatedMaxDepth {
+		depth = relatedMaxDepth
+	}
+	depth = s.neo4j.ClampDepth(depth)
+	limit, _ = pagination.Clamp(limit, 0)
+
+	linkedAt, err := s.linkedAt(ctx, document)
+	if err != nil {
+		return nil, err
+	}
+	if linkedAt.IsZero() {
+		if linkedAt, err = s.link(ctx, document, spaceCtx); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx = database.WithQueryName(ctx, "related.traverse")
+	query := fmt.Sprintf(relatedDocumentsQuery, depth, fmt.Sprintf(graphNodeAccess, "x", database.SoftDeleteFilter(ctx, "x")))
+	params := map[string]interface{}{
+		"document_id": document.ID,
+		"tenant_id":   spaceCtx.TenantID,
+		"space_id":    spaceCtx.SpaceID,
+		"user_id":     userID,
+		"limit":       limit,
+	}
+	if err := s.neo4j.CheckQueryCost(ctx, query, params); err != nil {
+		return nil, traversalError(err, map[string]interface{}{
+			"document_id": document.ID,
+			"depth":       depth,
+		})
+	}
+	result, err := s.neo4j.ExecuteQuery(ctx, query, params)
+	if err != nil {
+		s.logger.Error("Failed to traverse related documents", zap.String("document_id", document.ID), zap.Error(err))
+		return nil, errors.Database("Failed to find related documents", err)
+	}
+
+	related := make([]*models.RelatedDocument, 0, len(result.Records))
+	for _, record := range result.Records {
+		related = append(related, &models.RelatedDocument{
+			Document: &models.SemanticSearchDocument{
+				ID:         recordString(record, "id"),
+				Name:       recordString(record, "name"),
+				MimeType:   recordString(record, "mime_type"),
+				NotebookID: recordString(record, "notebook_id"),
+			},
+			Score:    recordFloat(record, "score"),
+			Distance: int(recordInt64(record, "distance")),
+		})
+	}
+
+	return &models.RelatedDocumentsResponse{
+		DocumentID: document.ID,
+		Depth:      depth,
+		Documents:  related,
+		LinkedAt:   linkedAt,
+	}, nil
+}
+
+// linkedAt returns when the SIMILAR_TO relationships of a document were
+// last computed, or the zero time when they never were
+func (s *RelatedDocumentService) linkedAt(ctx context.Context, document *models.Document) (time.Time, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "related.linked_at"), `
+		MATCH (d:Document {id: $id, tenant_id: $tenant_id})
+		RETURN d.related_linked_at AS linked_at
+	`, map[string]interface{}{
+		"id":        document.ID,
+		"tenant_id": document.TenantID,
+	})
+	if err != nil {
+		return time.Time{}, errors.Database("Failed to find related documents", err)
+	}
+	if len(result.Records) == 0 {
+		return time.Time{}, nil
+	}
+	return recordTime(result.Records[0], "linked_at"), nil
+}
+
+// link ranks the documents similar to a document and replaces its
+// SIMILAR_TO relationships with ones to the best of them, taking it off
+// the queue
+func (s *RelatedDocumentService) link(ctx context.Context, document *models.Document, spaceCtx *models.SpaceContext) (time.Time, error) {
+	ranking, err := s.similar.rank(ctx, document, spaceCtx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	s.similar.store(ctx, ranking)
+
+	links := make([]map[string]interface{}, 0, relatedLinksPerDocument)
+	for _, similar := range ranking.Documents {
+		if len(links) == relatedLinksPerDocument {
+			break
+		}
+		if similar.Score < relatedMinScore {
+			continue
+		}
+		links = append(links, map[string]interface{}{
+			"id":    similar.Document.ID,
+			"score": similar.Score,
+		})
+	}
+
+	now := time.Now().UTC()
+	_, err = s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "related.link"), `
+		MATCH (d:Document {id: $id, tenant_id: $tenant_id})
+		OPTIONAL MATCH (d)-[old:SIMILAR_TO]->()
+		DELETE old
+		WITH DISTINCT d
+		SET d.related_linked_at = $now
+		REMOVE d.related_pending
+		WITH d
+		UNWIND $links AS link
+		MATCH (o:Document {id: link.id, tenant_id: $tenant_id})
+		MERGE (d)-[r:SIMILAR_TO]->(o)
+		SET r.score = link.score, r.linked_at = $now
+	`, map[string]interface{}{
+		"id":        document.ID,
+		"tenant_id": spaceCtx.TenantID,
+		"links":     links,
+		"now":       now,
+	})
+	if err != nil {
+		return time.Time{}, errors.Database("Failed to link related documents", err)
+	}
+
+	s.logger.Debug("Linked related documents", zap.String("document_id", document.ID), zap.Int("links", len(links)))
+	return now, nil
+}
@@ -0,0 +1,926 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/reports"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// Report limits
+const (
+	maxReportSchedulesPerSpace = 20
+	// reportRunsKept is how many runs of a schedule are kept; older runs
+	// and their files are deleted
+	reportRunsKept = 60
+	// reportDueBatch is how many due schedules one scheduled run generates
+	reportDueBatch = 20
+	// reportRowLimit bounds the rows of a report
+	reportRowLimit = 1000
+	// reportRetryDelay is how long a failed scheduled report waits before
+	// it is generated again
+	reportRetryDelay = time.Hour
+)
+
+// reportScheduleFields are the columns recordToReportSchedule reads
+const reportScheduleFields = `r.id AS id, r.space_id AS space_id, r.space_type AS space_type,
+	       r.tenant_id AS tenant_id, r.name AS name, r.kind AS kind, r.format AS format,
+	       r.cadence AS cadence, r.email_to AS email_to, r.notify_user_ids AS notify_user_ids,
+	       r.next_run_at AS next_run_at, r.last_run_at AS last_run_at, r.last_status AS last_status,
+	       r.last_error AS last_error, r.created_by AS created_by, r.created_at AS created_at`
+
+// reportRunFields are the columns recordToReportRun reads
+const reportRunFields = `x.id AS id, x.schedule_id AS schedule_id, x.kind AS kind, x.format AS format,
+	       x.period_start AS period_start, x.period_end AS period_end, x.status AS status,
+	       x.error AS error, x.file_name AS file_name, x.size_bytes AS size_bytes,
+	       x.emailed_to AS emailed_to, x.created_at AS created_at`
+
+// ReportService generates the scheduled reports of spaces: a processing
+// summary, storage usage or agent cost, rendered to PDF or CSV. Each report
+// is stored in the tenant's bucket, announced to users in the app and,
+// when SMTP is configured, emailed as an attachment. Reports are generated
+// by the leader once their period ends, or on demand for the period so far.
+type ReportService struct {
+	neo4j   *database.Neo4jClient
+	storage StorageService
+	mailer  reportMailer // nil when reports are not emailed
+	events  DomainEventPublisher
+	logger  *logger.Logger
+}
+
+// NewReportService creates a report service. Reports cannot be generated
+// while storage is nil.
+func NewReportService(neo4j *database.Neo4jClient, storage StorageService, cfg config.ReportsConfig, log *logger.Logger) *ReportService {
+	return &ReportService{
+		neo4j:   neo4j,
+		storage: storage,
+		mailer:  newSMTPReportMailer(cfg),
+		logger:  log.WithService("report_service"),
+	}
+}
+
+// SetEventPublisher sets where generated reports are announced
+func (s *ReportService) SetEventPublisher(events DomainEventPublisher) {
+	s.events = events
+}
+
+// CreateSchedule schedules a report of the current space. The first report
+// covers the period after the current one.
+func (s *ReportService) CreateSchedule(ctx context.Context, req models.ReportScheduleCreateRequest, spaceCtx *models.SpaceContext) (*models.ReportSchedule, error) {
+	if !spaceCtx.CanManage() {
+		return nil, errors.Forbidden("Only space owners and admins can manage reports")
+	}
+	if s.storage == nil {
+		return nil, errors.ServiceUnavailable("Storage is not available for reports")
+	}
+	if len(req.EmailTo) > 0 && s.mailer == nil {
+		return nil, errors.ValidationWithDetails("Reports cannot be emailed on this deployment", map[string]interface{}{
+			"email_to": req.EmailTo,
+		})
+	}
+	notifyUserIDs := req.NotifyUserIDs
+	if len(notifyUserIDs) == 0 {
+		notifyUserIDs = []string{spaceCtx.UserID}
+	}
+
+	now := time.Now().UTC()
+	schedule := &models.ReportSchedule{
+		ID:            uuid.New().String(),
+		SpaceID:       spaceCtx.SpaceID,
+		SpaceType:     spaceCtx.SpaceType,
+		TenantID:      spaceCtx.TenantID,
+		Name:          req.Name,
+		Kind:          req.Kind,
+		Format:        req.Format,
+		Cadence:       req.Cadence,
+		EmailTo:       req.EmailTo,
+		NotifyUserIDs: notifyUserIDs,
+		NextRunAt:     reportPeriodShift(req.Cadence, reportPeriodStart(req.Cadence, now), 1),
+		CreatedBy:     spaceCtx.UserID,
+		CreatedAt:     now,
+	}
+
+	// Agent costs are reported as the change of each agent's totals, so
+	// the first report counts from now
+	baseline := ""
+	if schedule.Kind == models.ReportAgentCost {
+		totals, err := s.agentTotals(ctx, schedule)
+		if err != nil {
+			return nil, err
+		}
+		if baseline, err = encodeAgentTotals(totals); err != nil {
+			return nil, errors.InternalWithCause("Failed to encode agent totals", err)
+		}
+	}
+
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "report.create_schedule"), `
+		OPTIONAL MATCH (existing:ReportSchedule {space_id: $space_id, tenant_id: $tenant_id})
+		WITH count(existing) AS schedules
+		WHERE schedules < $max_schedules
+		CREATE (r:ReportSchedule {
+			id: $id, space_id: $space_id, space_type: $space_type, tenant_id: $tenant_id,
+			name: $name, kind: $kind, format: $format, cadence: $cadence,
+			email_to: $email_to, notify_user_ids: $notify_user_ids,
+			next_run_at: $next_run_at, agent_baseline: $agent_baseline,
+			created_by: $created_by, created_at: $created_at
+		})
+		RETURN r.id AS id
+	`, map[string]interface{}{
+		"id":              schedule.ID,
+		"space_id":        schedule.SpaceID,
+		"space_type":      string(schedule.SpaceType),
+		"tenant_id":       schedule.TenantID,
+		"max_schedules":   maxReportSchedulesPerSpace,
+		"name":            schedule.Name,
+		"kind":            schedule.Kind,
+		"format":          schedule.Format,
+		"cadence":         schedule.Cadence,
+		"email_to":        schedule.EmailTo,
+		"notify_user_ids": schedule.NotifyUserIDs,
+		"next_run_at":     schedule.NextRunAt,
+		"agent_baseline":  baseline,
+		"created_by":      schedule.CreatedBy,
+		"created_at":      schedule.CreatedAt,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to create report schedule", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, errors.ValidationWithDetails("Space has too many report schedules", map[string]interface{}{
+			"max_schedules": maxReportSchedulesPerSpace,
+		})
+	}
+
+	s.logger.Info("Report schedule created",
+		zap.String("schedule_id", schedule.ID),
+		zap.String("space_id", schedule.SpaceID),
+		zap.String("kind", schedule.Kind),
+		zap.String("cadence", schedule.Cadence))
+	return schedule, nil
+}
+
+// ListSchedules returns the report schedules of the current space, oldest
+// first
+func (s *ReportService) ListSchedules(ctx context.Context, spaceCtx *models.SpaceContext) (*models.ReportScheduleList, error) {
+	if !spaceCtx.CanManage() {
+		return nil, errors.Forbidden("Only space owners and admins can manage reports")
+	}
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "report.list_schedules"), `
+		MATCH (r:ReportSchedule {space_id: $space_id, tenant_id: $tenant_id})
+		RETURN `+reportScheduleFields+`
+		ORDER BY r.created_at
+	`, map[string]interface{}{
+		"space_id":  spaceCtx.SpaceID,
+		"tenant_id": spaceCtx.TenantID,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to list report schedules", err)
+	}
+
+	list := &models.ReportScheduleList{Schedules: make([]*models.ReportSchedule, 0, len(result.Records))}
+	for _, record := range result.Records {
+		list.Schedules = append(list.Schedules, recordToReportSchedule(record))
+	}
+	return list, nil
+}
+
+// GetSchedule returns a report schedule of the current space
+func (s *ReportService) GetSchedule(ctx context.Context, scheduleID string, spaceCtx *models.SpaceContext) (*models.ReportSchedule, error) {
+	if !spaceCtx.CanManage() {
+		return nil, errors.Forbidden("Only space owners and admins can manage reports")
+	}
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "report.get_schedule"), `
+		MATCH (r:ReportSchedule {id: $id, space_id: $space_id, tenant_id: $tenant_id})
+		RETURN `+reportScheduleFields, map[string]interface{}{
+		"id":        scheduleID,
+		"space_id":  spaceCtx.SpaceID,
+		"tenant_id": spaceCtx.TenantID,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to get report schedule", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, reportScheduleNotFound(scheduleID)
+	}
+	return recordToReportSchedule(result.Records[0]), nil
+}
+
+// DeleteSchedule deletes a report schedule of the current space with its
+// runs and their files
+func (s *ReportService) DeleteSchedule(ctx context.Context, scheduleID string, spaceCtx *models.SpaceContext) error {
+	schedule, err := s.GetSchedule(ctx, scheduleID, spaceCtx)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "report.delete_schedule"), `
+		MATCH (r:ReportSchedule {id: $id})
+		OPTIONAL MATCH (r)-[:GENERATED]->(x:ReportRun)
+		WITH r, collect(x) AS runs, [run IN collect(x) WHERE run.storage_key IS NOT NULL | run.storage_key] AS keys
+		FOREACH (run IN runs | DETACH DELETE run)
+		DETACH DELETE r
+		RETURN keys
+	`, map[string]interface{}{"id": schedule.ID})
+	if err != nil {
+		return errors.Database("Failed to delete report schedule", err)
+	}
+	if len(result.Records) > 0 {
+		s.deleteReportFiles(ctx, schedule.TenantID, recordStrings(result.Records[0], "keys"))
+	}
+
+	s.logger.Info("Report schedule deleted", zap.String("schedule_id", schedule.ID), zap.String("space_id", schedule.SpaceID))
+	return nil
+}
+
+// RunNow generates a schedule's report for its current period so far. It
+// is delivered like a scheduled report but leaves the schedule's next run
+// as it is.
+func (s *ReportService) RunNow(ctx context.Context, scheduleID string, spaceCtx *models.SpaceContext) (*models.ReportRun, error) {
+	schedule, err := s.GetSchedule(ctx, scheduleID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+	if s.storage == nil {
+		return nil, errors.ServiceUnavailable("Storage is not available for reports")
+	}
+	now := time.Now().UTC()
+	run, err := s.generate(ctx, schedule, reportPeriodStart(schedule.Cadence, now), now, false)
+	if err != nil {
+		return nil, errors.InternalWithCause("Failed to generate report", err)
+	}
+	return run, nil
+}
+
+// ListRuns returns the runs of a report schedule, newest first
+func (s *ReportService) ListRuns(ctx context.Context, scheduleID string, spaceCtx *models.SpaceContext) (*models.ReportRunList, error) {
+	schedule, err := s.GetSchedule(ctx, scheduleID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "report.list_runs"), `
+		MATCH (:ReportSchedule {id: $id})-[:GENERATED]->(x:ReportRun)
+		RETURN `+reportRunFields+`
+		ORDER BY x.created_at DESC
+	`, map[string]interface{}{"id": schedule.ID})
+	if err != nil {
+		return nil, errors.Database("Failed to list report runs", err)
+	}
+
+	list := &models.ReportRunList{Runs: make([]*models.ReportRun, 0, len(result.Records))}
+	for _, record := range result.Records {
+		list.Runs = append(list.Runs, recordToReportRun(record))
+	}
+	return list, nil
+}
+
+// DownloadRun returns the file of a report run of the current space with
+// its name and content type
+func (s *ReportService) DownloadRun(ctx context.Context, runID string, spaceCtx *models.SpaceContext) ([]byte, string, string, error) {
+	if !spaceCtx.CanManage() {
+		return nil, "", "", errors.Forbidden("Only space owners and admins can manage reports")
+	}
+	if s.storage == nil {
+		return nil, "", "", errors.ServiceUnavailable("Storage is not available for reports")
+	}
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "report.get_run"), `
+		MATCH (:ReportSchedule {space_id: $space_id, tenant_id: $tenant_id})-[:GENERATED]->(x:ReportRun {id: $id})
+		WHERE x.storage_key IS NOT NULL
+		RETURN x.storage_key AS storage_key, x.file_name AS file_name, x.format AS format
+	`, map[string]interface{}{
+		"id":        runID,
+		"space_id":  spaceCtx.SpaceID,
+		"tenant_id": spaceCtx.TenantID,
+	})
+	if err != nil {
+		return nil, "", "", errors.Database("Failed to get report run", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, "", "", errors.NotFoundWithDetails("Report not found", map[string]interface{}{
+			"run_id": runID,
+		}).WithErrorCode(errors.CodeReportRunNotFound)
+	}
+
+	record := result.Records[0]
+	data, err := s.storage.DownloadFileFromTenantBucket(ctx, spaceCtx.TenantID, recordString(record, "storage_key"))
+	if err != nil {
+		return nil, "", "", errors.ExternalService("Failed to download report", err)
+	}
+	return data, recordString(record, "file_name"), reportContentType(recordString(record, "format")), nil
+}
+
+// ProcessDue generates the reports whose period has ended, as the leader's
+// space_reports job. A report that fails is generated again after
+// reportRetryDelay, for the same period unless another has ended.
+func (s *ReportService) ProcessDue(ctx context.Context) error {
+	if s.storage == nil {
+		return nil
+	}
+	now := time.Now().UTC()
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "report.due"), `
+		MATCH (r:ReportSchedule)
+		WHERE r.next_run_at <= $now
+		RETURN `+reportScheduleFields+`
+		ORDER BY r.next_run_at
+		LIMIT $limit
+	`, map[string]interface{}{
+		"now":   now,
+		"limit": reportDueBatch,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to find due reports: %w", err)
+	}
+
+	for _, record := range result.Records {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		schedule := recordToReportSchedule(record)
+		end := reportPeriodStart(schedule.Cadence, now)
+		start := reportPeriodShift(schedule.Cadence, end, -1)
+		if _, err := s.generate(ctx, schedule, start, end, true); err != nil {
+			s.logger.Error("Failed to record report run",
+				zap.String("schedule_id", schedule.ID),
+				zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// generate renders, stores and delivers a schedule's report of a period
+// and records the run. A report that cannot be built or stored is recorded
+// as a failed run; one that could not be emailed still succeeds, with the
+// email's error. Scheduled runs move the schedule to its next period.
+func (s *ReportService) generate(ctx context.Context, schedule *models.ReportSchedule, start, end time.Time, scheduled bool) (*models.ReportRun, error) {
+	now := time.Now().UTC()
+	run := &models.ReportRun{
+		ID:          uuid.New().String(),
+		ScheduleID:  schedule.ID,
+		Kind:        schedule.Kind,
+		Format:      schedule.Format,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Status:      models.ReportRunSucceeded,
+		CreatedAt:   now,
+	}
+
+	storageKey, baseline, data, err := s.render(ctx, schedule, run)
+	if err != nil {
+		run.Status = models.ReportRunFailed
+		run.Error = err.Error()
+		s.logger.Warn("Report generation failed",
+			zap.String("schedule_id", schedule.ID),
+			zap.String("kind", schedule.Kind),
+			zap.Error(err))
+	} else {
+		run.SizeBytes = int64(len(data))
+		if len(schedule.EmailTo) > 0 && s.mailer != nil {
+			if err := s.email(ctx, schedule, run, data); err != nil {
+				run.Error = err.Error()
+				s.logger.Warn("Report email failed", zap.String("schedule_id", schedule.ID), zap.Error(err))
+			} else {
+				run.EmailedTo = len(schedule.EmailTo)
+			}
+		}
+	}
+
+	nextRunAt := schedule.NextRunAt
+	if scheduled {
+		nextRunAt = reportPeriodShift(schedule.Cadence, end, 1)
+		if run.Status == models.ReportRunFailed && now.Add(reportRetryDelay).Before(nextRunAt) {
+			nextRunAt = now.Add(reportRetryDelay)
+		}
+	}
+	if !scheduled || run.Status == models.ReportRunFailed {
+		// Only a delivered scheduled report moves the agent cost baseline
+		baseline = nil
+	}
+	if err := s.recordRun(ctx, schedule, run, storageKey, nextRunAt, baseline); err != nil {
+		return nil, err
+	}
+
+	if run.Status == models.ReportRunSucceeded {
+		publishDomainEvent(ctx, s.events, s.logger, Event{
+			Type:    EventReportGenerated,
+			Subject: run.ID,
+			Data: map[string]interface{}{
+				"tenant_id":       schedule.TenantID,
+				"space_id":        schedule.SpaceID,
+				"schedule_id":     schedule.ID,
+				"name":            schedule.Name,
+				"kind":            schedule.Kind,
+				"format":          schedule.Format,
+				"period_start":    run.PeriodStart,
+				"period_end":      run.PeriodEnd,
+				"notify_user_ids": schedule.NotifyUserIDs,
+			},
+		})
+	}
+	return run, nil
+}
+
+// render builds, renders and stores a report, returning its storage key,
+// the agent totals it counted from, when it reports agent cost, and the
+// rendered file
+func (s *ReportService) render(ctx context.Context, schedule *models.ReportSchedule, run *models.ReportRun) (string, *string, []byte, error) {
+	report, baseline, err := s.buildReport(ctx, schedule, run.PeriodStart, run.PeriodEnd)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	report.GeneratedAt = run.CreatedAt
+
+	var buf bytes.Buffer
+	if schedule.Format == models.ReportFormatCSV {
+		err = reports.WriteCSV(&buf, *report)
+	} else {
+		err = reports.WritePDF(&buf, *report)
+	}
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("failed to render report: %w", err)
+	}
+
+	run.FileName = reportFileName(schedule, run)
+	key := fmt.Sprintf("reports/%s/%s/%s.%s", schedule.SpaceID, schedule.ID, run.ID, schedule.Format)
+	if _, err := s.storage.UploadFileToTenantBucket(ctx, schedule.TenantID, key, buf.Bytes(), reportContentType(schedule.Format)); err != nil {
+		return "", nil, nil, fmt.Errorf("failed to store report: %w", err)
+	}
+	return key, baseline, buf.Bytes(), nil
+}
+
+// email sends a report to the schedule's addresses
+func (s *ReportService) email(ctx context.Context, schedule *models.ReportSchedule, run *models.ReportRun, data []byte) error {
+	period := reportPeriodLabel(run.PeriodStart, run.PeriodEnd)
+	return s.mailer.Send(ctx, reportEmail{
+		To:      schedule.EmailTo,
+		Subject: fmt.Sprintf("%s: %s", schedule.Name, period),
+		Body: fmt.Sprintf("Attached is the %s report %q for %s.\n\nYou receive it because you were added to the report's recipients in Aether.\n",
+			reportKindTitle(schedule.Kind), schedule.Name, period),
+		FileName:    run.FileName,
+		ContentType: reportContentType(schedule.Format),
+		Data:        data,
+	})
+}
+
+// recordRun stores a run, updates its schedule and deletes the runs past
+// reportRunsKept with their files. A nil baseline keeps the schedule's.
+func (s *ReportService) recordRun(ctx context.Context, schedule *models.ReportSchedule, run *models.ReportRun, storageKey string, nextRunAt time.Time, baseline *string) error {
+	var key interface{}
+	if storageKey != "" {
+		key = storageKey
+	}
+	var newBaseline interface{}
+	if baseline != nil {
+		newBaseline = *baseline
+	}
+
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "report.record_run"), `
+		MATCH (r:ReportSchedule {id: $schedule_id})
+		CREATE (r)-[:GENERATED]->(x:ReportRun {
+			id: $id, schedule_id: $schedule_id, kind: $kind, format: $format,
+			period_start: $period_start, period_end: $period_end, status: $status,
+			error: $error, storage_key: $storage_key, file_name: $file_name,
+			size_bytes: $size_bytes, emailed_to: $emailed_to, created_at: $created_at
+		})
+		SET r.last_run_at = $created_at, r.last_status = $status, r.last_error = $error,
+		    r.next_run_at = $next_run_at,
+		    r.agent_baseline = coalesce($agent_baseline, r.agent_baseline)
+		WITH r
+		MATCH (r)-[:GENERATED]->(old:ReportRun)
+		WITH old ORDER BY old.created_at DESC
+		SKIP $kept
+		WITH collect(old) AS expired, [run IN collect(old) WHERE run.storage_key IS NOT NULL | run.storage_key] AS keys
+		FOREACH (run IN expired | DETACH DELETE run)
+		RETURN keys
+	`, map[string]interface{}{
+		"schedule_id":    schedule.ID,
+		"id":             run.ID,
+		"kind":           run.Kind,
+		"format":         run.Format,
+		"period_start":   run.PeriodStart,
+		"period_end":     run.PeriodEnd,
+		"status":         run.Status,
+		"error":          run.Error,
+		"storage_key":    key,
+		"file_name":      run.FileName,
+		"size_bytes":     run.SizeBytes,
+		"emailed_to":     run.EmailedTo,
+		"created_at":     run.CreatedAt,
+		"next_run_at":    nextRunAt,
+		"agent_baseline": newBaseline,
+		"kept":           reportRunsKept,
+	})
+	if err != nil {
+		return errors.Database("Failed to record report run", err)
+	}
+	if len(result.Records) > 0 {
+		s.deleteReportFiles(ctx, schedule.TenantID, recordStrings(result.Records[0], "keys"))
+	}
+	return nil
+}
+
+// deleteReportFiles deletes the stored files of deleted runs. A file that
+// cannot be deleted is only logged; nothing refers to it any more.
+func (s *ReportService) deleteReportFiles(ctx context.Context, tenantID string, keys []string) {
+	if s.storage == nil {
+		return
+	}
+	for _, key := range keys {
+		if err := s.storage.DeleteFileFromTenantBucket(ctx, tenantID, key); err != nil {
+			s.logger.Warn("Failed to delete report file", zap.String("key", key), zap.Error(err))
+		}
+	}
+}
+
+// buildReport gathers the figures of a schedule's report for a period
+func (s *ReportService) buildReport(ctx context.Context, schedule *models.ReportSchedule, start, end time.Time) (*reports.Report, *string, error) {
+	report := &reports.Report{
+		Title:    schedule.Name,
+		Subtitle: fmt.Sprintf("%s report, %s", reportKindTitle(schedule.Kind), reportPeriodLabel(start, end)),
+	}
+	switch schedule.Kind {
+	case models.ReportProcessingSummary:
+		return report, nil, s.buildProcessingSummary(ctx, report, schedule, start, end)
+	case models.ReportStorageUsage:
+		return report, nil, s.buildStorageUsage(ctx, report, schedule, end)
+	case models.ReportAgentCost:
+		baseline, err := s.buildAgentCost(ctx, report, schedule)
+		return report, baseline, err
+	}
+	return nil, nil, fmt.Errorf("unknown report kind %q", schedule.Kind)
+}
+
+// buildProcessingSummary counts, per notebook, the documents uploaded in
+// the period and those that finished or failed processing in it
+func (s *ReportService) buildProcessingSummary(ctx context.Context, report *reports.Report, schedule *models.ReportSchedule, start, end time.Time) error {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "report.processing_summary"), `
+		MATCH (d:Document {space_id: $space_id, tenant_id: $tenant_id})
+		WITH d,
+		     coalesce(d.created_at >= $start AND d.created_at < $end, false) AS uploaded,
+		     coalesce(d.processed_at >= $start AND d.processed_at < $end, false) AS processed,
+		     coalesce(d.status = $failed AND d.updated_at >= $start AND d.updated_at < $end, false) AS failed
+		WHERE uploaded OR processed OR failed
+		OPTIONAL MATCH (n:Notebook {id: d.notebook_id})
+		WITH coalesce(n.name, d.notebook_id) AS notebook, d, uploaded, processed, failed
+		RETURN notebook,
+		       sum(CASE WHEN uploaded THEN 1 ELSE 0 END) AS uploaded,
+		       sum(CASE WHEN uploaded THEN coalesce(d.size_bytes, 0) ELSE 0 END) AS bytes_uploaded,
+		       sum(CASE WHEN processed THEN 1 ELSE 0 END) AS processed,
+		       sum(CASE WHEN failed THEN 1 ELSE 0 END) AS failed
+		ORDER BY uploaded DESC, notebook
+		LIMIT $limit
+	`, map[string]interface{}{
+		"space_id":  schedule.SpaceID,
+		"tenant_id": schedule.TenantID,
+		"start":     start,
+		"end":       end,
+		"failed":    "failed",
+		"limit":     reportRowLimit,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to count processed documents: %w", err)
+	}
+
+	report.Columns = []string{"Notebook", "Uploaded", "Uploaded size", "Processed", "Failed"}
+	var uploaded, bytesUploaded, processed, failed int64
+	for _, record := range result.Records {
+		row := []int64{recordInt64(record, "uploaded"), recordInt64(record, "bytes_uploaded"),
+			recordInt64(record, "processed"), recordInt64(record, "failed")}
+		uploaded += row[0]
+		bytesUploaded += row[1]
+		processed += row[2]
+		failed += row[3]
+		report.Rows = append(report.Rows, []string{recordString(record, "notebook"),
+			fmt.Sprint(row[0]), formatReportBytes(row[1]), fmt.Sprint(row[2]), fmt.Sprint(row[3])})
+	}
+	report.Figures = []reports.Figure{
+		{Label: "Documents uploaded", Value: fmt.Sprintf("%d (%s)", uploaded, formatReportBytes(bytesUploaded))},
+		{Label: "Documents processed", Value: fmt.Sprint(processed)},
+		{Label: "Documents failed", Value: fmt.Sprint(failed)},
+	}
+	if processed+failed > 0 {
+		report.Figures = append(report.Figures, reports.Figure{
+			Label: "Success rate",
+			Value: fmt.Sprintf("%.1f%%", 100*float64(processed)/float64(processed+failed)),
+		})
+	}
+	return nil
+}
+
+// buildStorageUsage lists the documents and bytes each notebook stores,
+// as of the end of the period
+func (s *ReportService) buildStorageUsage(ctx context.Context, report *reports.Report, schedule *models.ReportSchedule, end time.Time) error {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "report.storage_usage"), `
+		MATCH (d:Document {space_id: $space_id, tenant_id: $tenant_id})
+		WHERE `+database.NotDeleted("d")+` AND d.created_at < $end
+		OPTIONAL MATCH (n:Notebook {id: d.notebook_id})
+		WITH coalesce(n.name, d.notebook_id) AS notebook, d
+		RETURN notebook, count(d) AS documents, sum(coalesce(d.size_bytes, 0)) AS bytes
+		ORDER BY bytes DESC, notebook
+		LIMIT $limit
+	`, map[string]interface{}{
+		"space_id":  schedule.SpaceID,
+		"tenant_id": schedule.TenantID,
+		"end":       end,
+		"limit":     reportRowLimit,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to total storage: %w", err)
+	}
+
+	report.Columns = []string{"Notebook", "Documents", "Size", "Bytes"}
+	var documents, bytesStored int64
+	for _, record := range result.Records {
+		count, size := recordInt64(record, "documents"), recordInt64(record, "bytes")
+		documents += count
+		bytesStored += size
+		report.Rows = append(report.Rows, []string{recordString(record, "notebook"),
+			fmt.Sprint(count), formatReportBytes(size), fmt.Sprint(size)})
+	}
+	report.Figures = []reports.Figure{
+		{Label: "Documents stored", Value: fmt.Sprint(documents)},
+		{Label: "Storage used", Value: formatReportBytes(bytesStored)},
+	}
+	report.Notes = []string{"Documents in the trash are not counted."}
+	return nil
+}
+
+// agentTotal is an agent's lifetime executions and cost
+type agentTotal struct {
+	Executions int64   `json:"executions"`
+	CostUSD    float64 `json:"cost_usd"`
+}
+
+// agentTotals reads the lifetime totals of a space's agents, by agent ID
+func (s *ReportService) agentTotals(ctx context.Context, schedule *models.ReportSchedule) (map[string]agentTotal, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "report.agent_totals"), `
+		MATCH (a:Agent {space_id: $space_id, tenant_id: $tenant_id})
+		RETURN a.id AS id, coalesce(a.total_executions, 0) AS executions, toFloat(coalesce(a.total_cost_usd, 0)) AS cost
+	`, map[string]interface{}{
+		"space_id":  schedule.SpaceID,
+		"tenant_id": schedule.TenantID,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to read agent totals", err)
+	}
+	totals := make(map[string]agentTotal, len(result.Records))
+	for _, record := range result.Records {
+		totals[recordString(record, "id")] = agentTotal{
+			Executions: recordInt64(record, "executions"),
+			CostUSD:    recordFloat(record, "cost"),
+		}
+	}
+	return totals, nil
+}
+
+// buildAgentCost lists each agent's executions and cost since the previous
+// scheduled report, as the change of its lifetime totals; agents only
+// record totals. It returns the totals to count the next report from.
+func (s *ReportService) buildAgentCost(ctx context.Context, report *reports.Report, schedule *models.ReportSchedule) (*string, error) {
+	baseline, err := s.agentBaseline(ctx, schedule.ID)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "report.agent_cost"), `
+		MATCH (a:Agent {space_id: $space_id, tenant_id: $tenant_id})
+		RETURN a.id AS id, a.name AS name, a.type AS type,
+		       coalesce(a.total_executions, 0) AS executions, toFloat(coalesce(a.total_cost_usd, 0)) AS cost
+		ORDER BY cost DESC, name
+		LIMIT $limit
+	`, map[string]interface{}{
+		"space_id":  schedule.SpaceID,
+		"tenant_id": schedule.TenantID,
+		"limit":     reportRowLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent costs: %w", err)
+	}
+
+	totals := make(map[string]agentTotal, len(result.Records))
+	rows := make([]agentCostRow, 0, len(result.Records))
+	for _, record := range result.Records {
+		id := recordString(record, "id")
+		total := agentTotal{Executions: recordInt64(record, "executions"), CostUSD: recordFloat(record, "cost")}
+		totals[id] = total
+		rows = append(rows, agentCostRow{
+			name:      recordString(record, "name"),
+			agentType: recordString(record, "type"),
+			period:    agentTotalSince(total, baseline[id]),
+			lifetime:  total,
+		})
+	}
+	// Order agents by their cost in the period, highest first
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].period.CostUSD > rows[j].period.CostUSD })
+
+	report.Columns = []string{"Agent", "Type", "Executions", "Cost (USD)", "Lifetime cost (USD)"}
+	var executions int64
+	var cost float64
+	for _, row := range rows {
+		executions += row.period.Executions
+		cost += row.period.CostUSD
+		report.Rows = append(report.Rows, []string{row.name, row.agentType, fmt.Sprint(row.period.Executions),
+			fmt.Sprintf("%.4f", row.period.CostUSD), fmt.Sprintf("%.4f", row.lifetime.CostUSD)})
+	}
+	report.Figures = []reports.Figure{
+		{Label: "Agent executions", Value: fmt.Sprint(executions)},
+		{Label: "Agent cost", Value: fmt.Sprintf("$%.2f", cost)},
+	}
+	report.Notes = []string{"Executions and cost are counted since the previous scheduled report, or since the schedule was created."}
+
+	encoded, err := encodeAgentTotals(totals)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode agent totals: %w", err)
+	}
+	return &encoded, nil
+}
+
+// agentBaseline reads the agent totals a schedule's next report counts from
+func (s *ReportService) agentBaseline(ctx context.Context, scheduleID string) (map[string]agentTotal, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "report.agent_baseline"), `
+		MATCH (r:ReportSchedule {id: $id})
+		RETURN r.agent_baseline AS agent_baseline
+	`, map[string]interface{}{"id": scheduleID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent baseline: %w", err)
+	}
+	baseline := map[string]agentTotal{}
+	if len(result.Records) == 0 {
+		return baseline, nil
+	}
+	if encoded := recordString(result.Records[0], "agent_baseline"); encoded != "" {
+		if err := json.Unmarshal([]byte(encoded), &baseline); err != nil {
+			return nil, fmt.Errorf("invalid agent baseline: %w", err)
+		}
+	}
+	return baseline, nil
+}
+
+func encodeAgentTotals(totals map[string]agentTotal) (string, error) {
+	data, err := json.Marshal(totals)
+	return string(data), err
+}
+
+// agentTotalSince is what an agent added to its totals since a baseline.
+// Totals that went down, as when an agent's statistics were reset, count
+// from zero.
+func agentTotalSince(total, baseline agentTotal) agentTotal {
+	if total.Executions < baseline.Executions || total.CostUSD < baseline.CostUSD {
+		return total
+	}
+	return agentTotal{
+		Executions: total.Executions - baseline.Executions,
+		CostUSD:    total.CostUSD - baseline.CostUSD,
+	}
+}
+
+// agentCostRow is an agent's line of an agent cost report
+type agentCostRow struct {
+	name      string
+	agentType string
+	period    agentTotal
+	lifetime  agentTotal
+}
+
+// reportPeriodStart returns the start of the UTC day, Monday-based week or
+// month containing t
+func reportPeriodStart(cadence string, t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch cadence {
+	case models.ReportCadenceWeekly:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case models.ReportCadenceMonthly:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+// reportPeriodShift moves a period start by n periods
+func reportPeriodShift(cadence string, start time.Time, n int) time.Time {
+	switch cadence {
+	case models.ReportCadenceWeekly:
+		return start.AddDate(0, 0, 7*n)
+	case models.ReportCadenceMonthly:
+		return start.AddDate(0, n, 0)
+	}
+	return start.AddDate(0, 0, n)
+}
+
+// reportPeriodLabel describes a period by its days; the end is exclusive
+func reportPeriodLabel(start, end time.Time) string {
+	last := end.Add(-time.Nanosecond)
+	if start.Format("2006-01-02") == last.Format("2006-01-02") {
+		return start.Format("2006-01-02")
+	}
+	return start.Format("2006-01-02") + " to " + last.Format("2006-01-02")
+}
+
+func reportKindTitle(kind string) string {
+	switch kind {
+	case models.ReportProcessingSummary:
+		return "Processing summary"
+	case models.ReportStorageUsage:
+		return "Storage usage"
+	case models.ReportAgentCost:
+		return "Agent cost"
+	}
+	return kind
+}
+
+func reportContentType(format string) string {
+	if format == models.ReportFormatCSV {
+		return reports.ContentTypeCSV
+	}
+	return reports.ContentTypePDF
+}
+
+// reportFileNameUnsafe matches what is left out of report file names
+var reportFileNameUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// reportFileName names a run's file after its schedule and period
+func reportFileName(schedule *models.ReportSchedule, run *models.ReportRun) string {
+	name := strings.Trim(reportFileNameUnsafe.ReplaceAllString(schedule.Name, "-"), "-.")
+	if name == "" {
+		name = schedule.Kind
+	}
+	if len(name) > 80 {
+		name = name[:80]
+	}
+	return fmt.Sprintf("%s-%s.%s", name, run.PeriodStart.Format("2006-01-02"), schedule.Format)
+}
+
+// formatReportBytes formats a size in binary units
+func formatReportBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit && exp < 4; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTP"[exp])
+}
+
+func reportScheduleNotFound(scheduleID string) error {
+	return errors.NotFoundWithDetails("Report schedule not found", map[string]interface{}{
+		"schedule_id": scheduleID,
+	}).WithErrorCode(errors.CodeReportScheduleNotFound)
+}
+
+// recordToReportSchedule reads the reportScheduleFields of a record
+func recordToReportSchedule(record *neo4j.Record) *models.ReportSchedule {
+	schedule := &models.ReportSchedule{
+		ID:            recordString(record, "id"),
+		SpaceID:       recordString(record, "space_id"),
+		SpaceType:     models.SpaceType(recordString(record, "space_type")),
+		TenantID:      recordString(record, "tenant_id"),
+		Name:          recordString(record, "name"),
+		Kind:          recordString(record, "kind"),
+		Format:        recordString(record, "format"),
+		Cadence:       recordString(record, "cadence"),
+		EmailTo:       recordStrings(record, "email_to"),
+		NotifyUserIDs: recordStrings(record, "notify_user_ids"),
+		NextRunAt:     recordTime(record, "next_run_at"),
+		LastStatus:    recordString(record, "last_status"),
+		LastError:     recordString(record, "last_error"),
+		CreatedBy:     recordString(record, "created_by"),
+		CreatedAt:     recordTime(record, "created_at"),
+	}
+	if at := recordTime(record, "last_run_at"); !at.IsZero() {
+		schedule.LastRunAt = &at
+	}
+	return schedule
+}
+
+// recordToReportRun reads the reportRunFields of a record
+func recordToReportRun(record *neo4j.Record) *models.ReportRun {
+	return &models.ReportRun{
+		ID:          recordString(record, "id"),
+		ScheduleID:  recordString(record, "schedule_id"),
+		Kind:        recordString(record, "kind"),
+		Format:      recordString(record, "format"),
+		PeriodStart: recordTime(record, "period_start"),
+		PeriodEnd:   recordTime(record, "period_end"),
+		Status:      recordString(record, "status"),
+		Error:       recordString(record, "error"),
+		FileName:    recordString(record, "file_name"),
+		SizeBytes:   recordInt64(record, "size_bytes"),
+		EmailedTo:   int(recordInt64(record, "emailed_to")),
+		CreatedAt:   recordTime(record, "created_at"),
+	}
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+)
+
+// reportMailer emails a rendered report
+type reportMailer interface {
+	Send(ctx context.Context, message reportEmail) error
+}
+
+// reportEmail is a report with the text sent along with it
+type reportEmail struct {
+	To          []string
+	Subject     string
+	Body        string
+	FileName    string
+	ContentType string
+	Data        []byte
+}
+
+// smtpReportMailer sends reports through an SMTP server. net/smtp upgrades
+// to TLS when the server offers it and refuses PLAIN authentication
+// without TLS, except to localhost.
+type smtpReportMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// newSMTPReportMailer returns the mailer of the configured SMTP server, or
+// nil when reports are not emailed
+func newSMTPReportMailer(cfg config.ReportsConfig) reportMailer {
+	if cfg.SMTPAddr == "" {
+		return nil
+	}
+	mailer := &smtpReportMailer{addr: cfg.SMTPAddr, from: cfg.From}
+	if cfg.SMTPUsername != "" {
+		host, _, err := net.SplitHostPort(cfg.SMTPAddr)
+		if err != nil {
+			host = cfg.SMTPAddr
+		}
+		mailer.auth = smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, host)
+	}
+	return mailer
+}
+
+// Send sends a report to its recipients in one message. net/smtp takes no
+// context, so a cancelled context only stops a send that has not started.
+func (m *smtpReportMailer) Send(ctx context.Context, message reportEmail) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	data, err := buildReportEmail(m.from, message, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := smtp.SendMail(m.addr, m.auth, m.from, message.To, data); err != nil {
+		return fmt.Errorf("failed to send report email: %w", err)
+	}
+	return nil
+}
+
+// buildReportEmail builds a MIME message with the report attached, or with
+// the text alone when it has no file name. The subject and file name come
+// from the schedule's name, so they are encoded rather than written into
+// headers as they are.
+func buildReportEmail(from string, message reportEmail, date time.Time) ([]byte, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+
+	text, err := parts.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	quoted := quotedprintable.NewWriter(text)
+	if _, err := quoted.Write([]byte(message.Body)); err != nil {
+		return nil, err
+	}
+	if err := quoted.Close(); err != nil {
+		return nil, err
+	}
+
+	if message.FileName != "" {
+		attachment, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(strings.Split(message.ContentType, ";")[0], map[string]string{"name": message.FileName})},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": message.FileName})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeBase64Lines(attachment, message.Data); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	headers := []string{
+		"From: " + from,
+		"To: " + strings.Join(message.To, ", "),
+		"Subject: " + mime.QEncoding.Encode("utf-8", stripHeaderBreaks(message.Subject)),
+		"Date: " + date.UTC().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/mixed; boundary=" + parts.Boundary(),
+	}
+	for _, header := range headers {
+		out.WriteString(header + "\r\n")
+	}
+	out.WriteString("\r\n")
+	out.Write(body.Bytes())
+	return out.Bytes(), nil
+}
+
+// stripHeaderBreaks replaces line breaks, which would start a new header
+func stripHeaderBreaks(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
+
+// writeBase64Lines writes data in base64 lines of 76 characters, as MIME
+// requires
+func writeBase64Lines(w io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 0 {
+		n := 76
+		if len(encoded) < n {
+			n = len(encoded)
+		}
+		if _, err := w.Write([]byte(encoded[:n] + "\r\n")); err != nil {
+			return err
+		}
+		encoded = encoded[n:]
+	}
+	return nil
+}
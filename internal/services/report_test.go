@@ -0,0 +1,135 @@
+package services
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+func TestReportPeriods(t *testing.T) {
+	// A Wednesday afternoon
+	now := time.Date(2026, 10, 14, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		cadence       string
+		start, next   time.Time
+		previousStart time.Time
+	}{
+		{
+			cadence:       models.ReportCadenceDaily,
+			start:         time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC),
+			next:          time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC),
+			previousStart: time.Date(2026, 10, 13, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			cadence:       models.ReportCadenceWeekly,
+			start:         time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC),
+			next:          time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+			previousStart: time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			cadence:       models.ReportCadenceMonthly,
+			start:         time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+			next:          time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+			previousStart: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cadence, func(t *testing.T) {
+			start := reportPeriodStart(tt.cadence, now)
+			assert.Equal(t, tt.start, start)
+			assert.Equal(t, tt.next, reportPeriodShift(tt.cadence, start, 1))
+			assert.Equal(t, tt.previousStart, reportPeriodShift(tt.cadence, start, -1))
+		})
+	}
+
+	sunday := time.Date(2026, 10, 18, 23, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC), reportPeriodStart(models.ReportCadenceWeekly, sunday),
+		"weeks start on Monday")
+	local := time.Date(2026, 10, 15, 1, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	assert.Equal(t, time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC), reportPeriodStart(models.ReportCadenceDaily, local),
+		"periods are UTC")
+}
+
+func TestReportPeriodLabel(t *testing.T) {
+	day := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, "2026-10-12", reportPeriodLabel(day, day.AddDate(0, 0, 1)))
+	assert.Equal(t, "2026-10-12 to 2026-10-18", reportPeriodLabel(day, day.AddDate(0, 0, 7)))
+}
+
+func TestAgentTotalSince(t *testing.T) {
+	total := agentTotal{Executions: 12, CostUSD: 3.5}
+	assert.Equal(t, agentTotal{Executions: 2, CostUSD: 1.25}, agentTotalSince(total, agentTotal{Executions: 10, CostUSD: 2.25}))
+	assert.Equal(t, total, agentTotalSince(total, agentTotal{}), "agents created since count in full")
+	assert.Equal(t, total, agentTotalSince(total, agentTotal{Executions: 20, CostUSD: 9}), "reset totals count from zero")
+}
+
+func TestReportFileName(t *testing.T) {
+	run := &models.ReportRun{PeriodStart: time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)}
+	schedule := &models.ReportSchedule{Name: "Weekly: processing / ops", Kind: models.ReportProcessingSummary, Format: models.ReportFormatPDF}
+	assert.Equal(t, "Weekly-processing-ops-2026-10-12.pdf", reportFileName(schedule, run))
+
+	schedule.Name = "Коммерция"
+	schedule.Format = models.ReportFormatCSV
+	assert.Equal(t, "processing_summary-2026-10-12.csv", reportFileName(schedule, run))
+}
+
+func TestFormatReportBytes(t *testing.T) {
+	assert.Equal(t, "512 B", formatReportBytes(512))
+	assert.Equal(t, "1.5 KiB", formatReportBytes(1536))
+	assert.Equal(t, "3.0 GiB", formatReportBytes(3<<30))
+}
+
+func TestBuildReportEmail(t *testing.T) {
+	attachment := []byte("%PDF-1.4\nreport")
+	data, err := buildReportEmail("reports@example.com", reportEmail{
+		To:          []string{"ops@example.com", "cfo@example.com"},
+		Subject:     "Storage: Q3\r\nBcc: attacker@example.com",
+		Body:        "Attached is the report.\n",
+		FileName:    "storage-2026-10-12.pdf",
+		ContentType: "application/pdf",
+		Data:        attachment,
+	}, time.Date(2026, 10, 12, 0, 5, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	message, err := mail.ReadMessage(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Empty(t, message.Header.Get("Bcc"), "line breaks cannot add headers")
+	subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Storage: Q3  Bcc: attacker@example.com", subject)
+	assert.Equal(t, "ops@example.com, cfo@example.com", message.Header.Get("To"))
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/mixed", mediaType)
+
+	parts := multipart.NewReader(message.Body, params["boundary"])
+	text, err := parts.NextPart()
+	require.NoError(t, err)
+	body, err := io.ReadAll(text)
+	require.NoError(t, err)
+	assert.Equal(t, "Attached is the report.\r\n", string(body), "text lines end in CRLF")
+
+	file, err := parts.NextPart()
+	require.NoError(t, err)
+	assert.Equal(t, "storage-2026-10-12.pdf", file.FileName())
+	encoded, err := io.ReadAll(file)
+	require.NoError(t, err)
+	decoded, err := base64.StdEncoding.DecodeString(string(bytes.ReplaceAll(encoded, []byte("\r\n"), nil)))
+	require.NoError(t, err)
+	assert.Equal(t, attachment, decoded)
+
+	_, err = parts.NextPart()
+	assert.Equal(t, io.EOF, err)
+}
@@ -0,0 +1,516 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// reportingSchema creates the event log and the read-model tables. Every
+// statement is idempotent so it runs on each start.
+var reportingSchema = []string{
+	`CREATE TABLE IF NOT EXISTS domain_events (
+		seq         BIGSERIAL PRIMARY KEY,
+		event_id    TEXT NOT NULL UNIQUE,
+		event_type  TEXT NOT NULL,
+		subject     TEXT NOT NULL,
+		user_id     TEXT,
+		occurred_at TIMESTAMPTZ NOT NULL,
+		payload     JSONB NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS reporting_notebooks (
+		id         TEXT PRIMARY KEY,
+		name       TEXT NOT NULL,
+		status     TEXT NOT NULL,
+		visibility TEXT,
+		owner_id   TEXT,
+		space_type TEXT,
+		space_id   TEXT,
+		tenant_id  TEXT,
+		parent_id  TEXT,
+		team_id    TEXT,
+		created_at TIMESTAMPTZ,
+		updated_at TIMESTAMPTZ,
+		deleted_at TIMESTAMPTZ
+	)`,
+	`CREATE INDEX IF NOT EXISTS reporting_notebooks_space_idx ON reporting_notebooks (space_id)`,
+	`CREATE TABLE IF NOT EXISTS reporting_documents (
+		id           TEXT PRIMARY KEY,
+		name         TEXT NOT NULL,
+		type         TEXT,
+		status       TEXT NOT NULL,
+		mime_type    TEXT,
+		size_bytes   BIGINT NOT NULL DEFAULT 0,
+		notebook_id  TEXT,
+		owner_id     TEXT,
+		space_type   TEXT,
+		space_id     TEXT,
+		tenant_id    TEXT,
+		last_error   TEXT,
+		created_at   TIMESTAMPTZ,
+		updated_at   TIMESTAMPTZ,
+		processed_at TIMESTAMPTZ,
+		deleted_at   TIMESTAMPTZ
+	)`,
+	`CREATE INDEX IF NOT EXISTS reporting_documents_notebook_idx ON reporting_documents (notebook_id)`,
+	`CREATE INDEX IF NOT EXISTS reporting_documents_space_idx ON reporting_documents (space_id)`,
+	`CREATE TABLE IF NOT EXISTS reporting_usage_daily (
+		space_id            TEXT NOT NULL,
+		day                 DATE NOT NULL,
+		documents_uploaded  INTEGER NOT NULL DEFAULT 0,
+		bytes_uploaded      BIGINT NOT NULL DEFAULT 0,
+		documents_processed INTEGER NOT NULL DEFAULT 0,
+		documents_failed    INTEGER NOT NULL DEFAULT 0,
+		documents_deleted   INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (space_id, day)
+	)`,
+}
+
+// reportingRebuildBatchSize is how many logged events a rebuild reads at a time
+const reportingRebuildBatchSize = 500
+
+// sqlExecer is the part of *sql.DB and *sql.Tx the projection needs
+type sqlExecer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// ReportingProjector materializes documents, notebooks and daily usage into
+// PostgreSQL for reporting. Every event it receives is appended to the
+// domain_events log in the same transaction as the read-model update, so
+// the read models can be rebuilt from the log after a schema or logic change.
+type ReportingProjector struct {
+	db     *sql.DB
+	logger *logger.Logger
+}
+
+// NewReportingProjector creates a new reporting projector
+func NewReportingProjector(db *sql.DB, log *logger.Logger) *ReportingProjector {
+	return &ReportingProjector{
+		db:     db,
+		logger: log.WithService("reporting_projector"),
+	}
+}
+
+// EnsureSchema creates the reporting tables if they do not exist
+func (p *ReportingProjector) EnsureSchema(ctx context.Context) error {
+	for _, statement := range reportingSchema {
+		if _, err := p.db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("failed to create reporting schema: %w", err)
+		}
+	}
+	return nil
+}
+
+// Apply logs an event and projects it. Events already in the log are
+// skipped, so redelivery is harmless. Apply is a DomainEventHandler.
+func (p *ReportingProjector) Apply(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to serialize event: %w", err)
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin reporting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO domain_events (event_id, event_type, subject, user_id, occurred_at, payload)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (event_id) DO NOTHING
+	`, event.ID, string(event.Type), event.Subject, event.UserID, event.Timestamp, payload)
+	if err != nil {
+		return fmt.Errorf("failed to log event: %w", err)
+	}
+	if inserted, err := result.RowsAffected(); err == nil && inserted == 0 {
+		return nil
+	}
+
+	if err := projectEvent(ctx, tx, event); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Rebuild truncates the read models and replays the whole event log into
+// them in one transaction. New events wait for the rebuild to finish, and
+// readers see the old read models until it commits.
+func (p *ReportingProjector) Rebuild(ctx context.Context) (int, error) {
+	start := time.Now()
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, errors.Database("Failed to begin reporting rebuild", err)
+	}
+	defer tx.Rollback()
+
+	// Apply inserts into the log first, so locking it orders every
+	// concurrent Apply entirely before or after the rebuild
+	statements := []string{
+		`LOCK TABLE domain_events IN EXCLUSIVE MODE`,
+		`TRUNCATE reporting_documents, reporting_notebooks, reporting_usage_daily`,
+	}
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return 0, errors.Database("Failed to reset reporting tables", err)
+		}
+	}
+
+	replayed := 0
+	var lastSeq int64
+	for {
+		events, seq, err := readEventBatch(ctx, tx, lastSeq)
+		if err != nil {
+			return replayed, errors.Database("Failed to read event log", err)
+		}
+		if len(events) == 0 {
+			break
+		}
+		for _, event := range events {
+			if err := projectEvent(ctx, tx, event); err != nil {
+				return replayed, errors.Database("Failed to replay event", err)
+			}
+		}
+		replayed += len(events)
+		lastSeq = seq
+	}
+
+	if err := tx.Commit(); err != nil {
+		return replayed, errors.Database("Failed to commit reporting rebuild", err)
+	}
+
+	p.logger.Info("Reporting read models rebuilt",
+		zap.Int("events", replayed),
+		zap.Duration("duration", time.Since(start)),
+	)
+	return replayed, nil
+}
+
+// readEventBatch reads the events logged after seq. The rows are closed
+// before returning because the transaction's connection cannot run the
+// projection statements while a result set is open.
+func readEventBatch(ctx context.Context, tx *sql.Tx, afterSeq int64) ([]Event, int64, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT seq, payload FROM domain_events
+		WHERE seq > $1
+		ORDER BY seq
+		LIMIT $2
+	`, afterSeq, reportingRebuildBatchSize)
+	if err != nil {
+		return nil, afterSeq, err
+	}
+	defer rows.Close()
+
+	var events []Event
+	lastSeq := afterSeq
+	for rows.Next() {
+		var payload []byte
+		if err := rows.Scan(&lastSeq, &payload); err != nil {
+			return nil, afterSeq, err
+		}
+		var event Event
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return nil, afterSeq, fmt.Errorf("failed to decode logged event %d: %w", lastSeq, err)
+		}
+		events = append(events, event)
+	}
+	return events, lastSeq, rows.Err()
+}
+
+// Status reports how far the projection has got
+func (p *ReportingProjector) Status(ctx context.Context) (*models.ReportingStatus, error) {
+	status := &models.ReportingStatus{}
+	var lastEventAt sql.NullTime
+
+	err := p.db.QueryRowContext(ctx, `
+		SELECT
+			(SELECT count(*) FROM domain_events),
+			(SELECT max(occurred_at) FROM domain_events),
+			(SELECT count(*) FROM reporting_documents),
+			(SELECT count(*) FROM reporting_notebooks)
+	`).Scan(&status.Events, &lastEventAt, &status.Documents, &status.Notebooks)
+	if err != nil {
+		return nil, errors.Database("Failed to read reporting status", err)
+	}
+
+	if lastEventAt.Valid {
+		status.LastEventAt = &lastEventAt.Time
+	}
+	return status, nil
+}
+
+// UsageReport totals the daily usage read model per space for the days
+// from from up to but excluding to. An empty spaceID reports every space.
+func (p *ReportingProjector) UsageReport(ctx context.Context, from, to time.Time, spaceID string) (*models.UsageReport, error) {
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT space_id,
+		       sum(documents_uploaded), sum(bytes_uploaded), sum(documents_processed),
+		       sum(documents_failed), sum(documents_deleted)
+		FROM reporting_usage_daily
+		WHERE day >= $1 AND day < $2 AND ($3 = '' OR space_id = $3)
+		GROUP BY space_id
+		ORDER BY space_id
+	`, from, to, spaceID)
+	if err != nil {
+		return nil, errors.Database("Failed to read usage report", err)
+	}
+	defer rows.Close()
+
+	report := &models.UsageReport{From: from, To: to, Spaces: []models.SpaceUsage{}}
+	for rows.Next() {
+		var usage models.SpaceUsage
+		if err := rows.Scan(&usage.SpaceID, &usage.DocumentsUploaded, &usage.BytesUploaded,
+			&usage.DocumentsProcessed, &usage.DocumentsFailed, &usage.DocumentsDeleted); err != nil {
+			return nil, errors.Database("Failed to read usage report", err)
+		}
+		report.Spaces = append(report.Spaces, usage)
+		report.Total.DocumentsUploaded += usage.DocumentsUploaded
+		report.Total.BytesUploaded += usage.BytesUploaded
+		report.Total.DocumentsProcessed += usage.DocumentsProcessed
+		report.Total.DocumentsFailed += usage.DocumentsFailed
+		report.Total.DocumentsDeleted += usage.DocumentsDeleted
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Database("Failed to read usage report", err)
+	}
+	return report, nil
+}
+
+// documentSnapshot is the document state carried by document.uploaded and
+// document.updated events
+type documentSnapshot struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Type       string    `json:"type"`
+	Status     string    `json:"status"`
+	MimeType   string    `json:"mime_type"`
+	SizeBytes  int64     `json:"size_bytes"`
+	NotebookID string    `json:"notebook_id"`
+	OwnerID    string    `json:"owner_id"`
+	SpaceType  string    `json:"space_type"`
+	SpaceID    string    `json:"space_id"`
+	TenantID   string    `json:"tenant_id"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// notebookSnapshot is the notebook state carried by notebook.created and
+// notebook.updated events
+type notebookSnapshot struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Status     string    `json:"status"`
+	Visibility string    `json:"visibility"`
+	OwnerID    string    `json:"owner_id"`
+	SpaceType  string    `json:"space_type"`
+	SpaceID    string    `json:"space_id"`
+	TenantID   string    `json:"tenant_id"`
+	ParentID   string    `json:"parent_id"`
+	TeamID     string    `json:"team_id"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// documentEventData builds the payload of document snapshot events
+func documentEventData(doc *models.Document) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          doc.ID,
+		"name":        doc.Name,
+		"type":        doc.Type,
+		"status":      doc.Status,
+		"mime_type":   doc.MimeType,
+		"size_bytes":  doc.SizeBytes,
+		"notebook_id": doc.NotebookID,
+		"owner_id":    doc.OwnerID,
+		"space_type":  string(doc.SpaceType),
+		"space_id":    doc.SpaceID,
+		"tenant_id":   doc.TenantID,
+		"created_at":  doc.CreatedAt,
+		"updated_at":  doc.UpdatedAt,
+	}
+}
+
+// notebookEventData builds the payload of notebook snapshot events
+func notebookEventData(notebook *models.Notebook) map[string]interface{} {
+	return map[string]interface{}{
+		"id":         notebook.ID,
+		"name":       notebook.Name,
+		"status":     notebook.Status,
+		"visibility": notebook.Visibility,
+		"owner_id":   notebook.OwnerID,
+		"space_type": string(notebook.SpaceType),
+		"space_id":   notebook.SpaceID,
+		"tenant_id":  notebook.TenantID,
+		"parent_id":  notebook.ParentID,
+		"team_id":    notebook.TeamID,
+		"created_at": notebook.CreatedAt,
+		"updated_at": notebook.UpdatedAt,
+	}
+}
+
+// decodeEventData decodes an event payload into a snapshot. Payloads are
+// decoded through JSON so live events and events replayed from the log are
+// handled identically.
+func decodeEventData(event Event, v interface{}) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("invalid %s payload: %w", event.Type, err)
+	}
+	return nil
+}
+
+// projectEvent applies one event to the read models. Snapshot upserts only
+// overwrite older state, so events applied out of order by different
+// replicas converge. Event types without a projection are ignored.
+func projectEvent(ctx context.Context, exec sqlExecer, event Event) error {
+	day := event.Timestamp.UTC().Format("2006-01-02")
+
+	switch event.Type {
+	case EventNotebookCreated, EventNotebookUpdated:
+		var nb notebookSnapshot
+		if err := decodeEventData(event, &nb); err != nil {
+			return err
+		}
+		_, err := exec.ExecContext(ctx, `
+			INSERT INTO reporting_notebooks (id, name, status, visibility, owner_id, space_type,
+				space_id, tenant_id, parent_id, team_id, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			ON CONFLICT (id) DO UPDATE SET
+				name = EXCLUDED.name,
+				status = EXCLUDED.status,
+				visibility = EXCLUDED.visibility,
+				updated_at = EXCLUDED.updated_at
+			WHERE reporting_notebooks.updated_at <= EXCLUDED.updated_at
+		`, nb.ID, nb.Name, nb.Status, nb.Visibility, nb.OwnerID, nb.SpaceType,
+			nb.SpaceID, nb.TenantID, nb.ParentID, nb.TeamID, nb.CreatedAt, nb.UpdatedAt)
+		return wrapProjectionError(event, err)
+
+	case EventNotebookDeleted:
+		_, err := exec.ExecContext(ctx, `
+			UPDATE reporting_notebooks
+			SET status = $2, deleted_at = $3, updated_at = $3
+			WHERE id = $1
+		`, event.Subject, database.StatusDeleted, event.Timestamp)
+		return wrapProjectionError(event, err)
+
+	case EventNotebookRestored, EventDocumentRestored:
+		var restored struct {
+			Status string `json:"status"`
+		}
+		if err := decodeEventData(event, &restored); err != nil {
+			return err
+		}
+		table := "reporting_notebooks"
+		if event.Type == EventDocumentRestored {
+			table = "reporting_documents"
+		}
+		_, err := exec.ExecContext(ctx, `
+			UPDATE `+table+`
+			SET status = $2, deleted_at = NULL, updated_at = $3
+			WHERE id = $1
+		`, event.Subject, restored.Status, event.Timestamp)
+		return wrapProjectionError(event, err)
+
+	case EventDocumentUploaded, EventDocumentUpdated:
+		var doc documentSnapshot
+		if err := decodeEventData(event, &doc); err != nil {
+			return err
+		}
+		_, err := exec.ExecContext(ctx, `
+			INSERT INTO reporting_documents (id, name, type, status, mime_type, size_bytes,
+				notebook_id, owner_id, space_type, space_id, tenant_id, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+			ON CONFLICT (id) DO UPDATE SET
+				name = EXCLUDED.name,
+				status = EXCLUDED.status,
+				updated_at = EXCLUDED.updated_at
+			WHERE reporting_documents.updated_at <= EXCLUDED.updated_at
+		`, doc.ID, doc.Name, doc.Type, doc.Status, doc.MimeType, doc.SizeBytes,
+			doc.NotebookID, doc.OwnerID, doc.SpaceType, doc.SpaceID, doc.TenantID, doc.CreatedAt, doc.UpdatedAt)
+		if err != nil || event.Type != EventDocumentUploaded {
+			return wrapProjectionError(event, err)
+		}
+
+		_, err = exec.ExecContext(ctx, `
+			INSERT INTO reporting_usage_daily (space_id, day, documents_uploaded, bytes_uploaded)
+			VALUES ($1, $2, 1, $3)
+			ON CONFLICT (space_id, day) DO UPDATE SET
+				documents_uploaded = reporting_usage_daily.documents_uploaded + 1,
+				bytes_uploaded = reporting_usage_daily.bytes_uploaded + EXCLUDED.bytes_uploaded
+		`, doc.SpaceID, day, doc.SizeBytes)
+		return wrapProjectionError(event, err)
+
+	case EventDocumentStatusChanged, EventDocumentProcessed, EventDocumentFailed:
+		var change struct {
+			Status string `json:"status"`
+			Error  string `json:"error"`
+		}
+		if err := decodeEventData(event, &change); err != nil {
+			return err
+		}
+		_, err := exec.ExecContext(ctx, `
+			UPDATE reporting_documents
+			SET status = $2,
+			    last_error = NULLIF($3, ''),
+			    processed_at = CASE WHEN $4 THEN $5 ELSE processed_at END,
+			    updated_at = $5
+			WHERE id = $1 AND (updated_at IS NULL OR updated_at <= $5)
+		`, event.Subject, change.Status, change.Error, event.Type == EventDocumentProcessed, event.Timestamp)
+		if err != nil {
+			return wrapProjectionError(event, err)
+		}
+
+		switch event.Type {
+		case EventDocumentProcessed:
+			return wrapProjectionError(event, incrementUsage(ctx, exec, "documents_processed", event.Subject, day))
+		case EventDocumentFailed:
+			return wrapProjectionError(event, incrementUsage(ctx, exec, "documents_failed", event.Subject, day))
+		}
+		return nil
+
+	case EventDocumentDeleted:
+		_, err := exec.ExecContext(ctx, `
+			UPDATE reporting_documents
+			SET status = $2, deleted_at = $3, updated_at = $3
+			WHERE id = $1
+		`, event.Subject, database.StatusDeleted, event.Timestamp)
+		if err != nil {
+			return wrapProjectionError(event, err)
+		}
+		return wrapProjectionError(event, incrementUsage(ctx, exec, "documents_deleted", event.Subject, day))
+	}
+
+	return nil
+}
+
+// incrementUsage counts a document event against the space the document
+// belongs to. column is one of the fixed counter columns, never user input.
+func incrementUsage(ctx context.Context, exec sqlExecer, column, documentID, day string) error {
+	_, err := exec.ExecContext(ctx, fmt.Sprintf(`
+		INSERT INTO reporting_usage_daily (space_id, day, %[1]s)
+		SELECT space_id, $2, 1 FROM reporting_documents WHERE id = $1 AND space_id IS NOT NULL
+		ON CONFLICT (space_id, day) DO UPDATE SET
+			%[1]s = reporting_usage_daily.%[1]s + 1
+	`, column), documentID, day)
+	return err
+}
+
+func wrapProjectionError(event Event, err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("failed to project %s event %s: %w", event.Type, event.ID, err)
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+// recordingExecer records the statements a projection runs
+type recordingExecer struct {
+	queries []string
+	args    [][]interface{}
+}
+
+func (r *recordingExecer) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	r.queries = append(r.queries, strings.Join(strings.Fields(query), " "))
+	r.args = append(r.args, args)
+	return nil, nil
+}
+
+// replayed round-trips an event through JSON as a rebuild does
+func replayed(t *testing.T, event Event) Event {
+	data, err := json.Marshal(event)
+	require.NoError(t, err)
+	var decoded Event
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	return decoded
+}
+
+func testDocument() *models.Document {
+	created := time.Date(2026, 3, 10, 9, 30, 0, 0, time.UTC)
+	return &models.Document{
+		ID:         "doc-1",
+		Name:       "Report",
+		Type:       "pdf",
+		Status:     "uploaded",
+		MimeType:   "application/pdf",
+		SizeBytes:  2048,
+		NotebookID: "nb-1",
+		OwnerID:    "user-1",
+		SpaceType:  models.SpaceTypePersonal,
+		SpaceID:    "space-1",
+		TenantID:   "tenant-1",
+		CreatedAt:  created,
+		UpdatedAt:  created,
+	}
+}
+
+func TestProjectDocumentUploaded(t *testing.T) {
+	doc := testDocument()
+	event := NewDocumentEvent(EventDocumentUploaded, doc.ID, doc.OwnerID, documentEventData(doc))
+	event.Timestamp = doc.CreatedAt
+
+	// Live and replayed events must project identically
+	for name, e := range map[string]Event{"live": event, "replayed": replayed(t, event)} {
+		t.Run(name, func(t *testing.T) {
+			exec := &recordingExecer{}
+			require.NoError(t, projectEvent(context.Background(), exec, e))
+
+			require.Len(t, exec.queries, 2)
+			assert.Contains(t, exec.queries[0], "INSERT INTO reporting_documents")
+			assert.Equal(t, []interface{}{"doc-1", "Report", "pdf", "uploaded", "application/pdf", int64(2048),
+				"nb-1", "user-1", "personal", "space-1", "tenant-1", doc.CreatedAt, doc.UpdatedAt}, exec.args[0])
+
+			assert.Contains(t, exec.queries[1], "INSERT INTO reporting_usage_daily")
+			assert.Equal(t, []interface{}{"space-1", "2026-03-10", int64(2048)}, exec.args[1])
+		})
+	}
+}
+
+func TestProjectDocumentUpdatedSkipsUsage(t *testing.T) {
+	doc := testDocument()
+	exec := &recordingExecer{}
+	require.NoError(t, projectEvent(context.Background(), exec,
+		NewDocumentEvent(EventDocumentUpdated, doc.ID, doc.OwnerID, documentEventData(doc))))
+
+	require.Len(t, exec.queries, 1)
+	assert.Contains(t, exec.queries[0], "WHERE reporting_documents.updated_at <= EXCLUDED.updated_at")
+}
+
+func TestProjectDocumentStatusEvents(t *testing.T) {
+	at := time.Date(2026, 3, 10, 23, 59, 0, 0, time.UTC)
+
+	tests := []struct {
+		eventType EventType
+		processed bool
+		counter   string
+	}{
+		{EventDocumentStatusChanged, false, ""},
+		{EventDocumentProcessed, true, "documents_processed"},
+		{EventDocumentFailed, false, "documents_failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.eventType), func(t *testing.T) {
+			event := NewDocumentEvent(tt.eventType, "doc-1", "", map[string]interface{}{"status": "x", "error": ""})
+			event.Timestamp = at
+
+			exec := &recordingExecer{}
+			require.NoError(t, projectEvent(context.Background(), exec, event))
+
+			assert.Contains(t, exec.queries[0], "UPDATE reporting_documents")
+			assert.Equal(t, []interface{}{"doc-1", "x", "", tt.processed, at}, exec.args[0])
+
+			if tt.counter == "" {
+				assert.Len(t, exec.queries, 1)
+				return
+			}
+			require.Len(t, exec.queries, 2)
+			assert.Contains(t, exec.queries[1], tt.counter+" = reporting_usage_daily."+tt.counter+" + 1")
+			assert.Equal(t, []interface{}{"doc-1", "2026-03-10"}, exec.args[1])
+		})
+	}
+}
+
+func TestProjectDeletes(t *testing.T) {
+	at := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	exec := &recordingExecer{}
+	event := NewDocumentEvent(EventDocumentDeleted, "doc-1", "user-1", nil)
+	event.Timestamp = at
+	require.NoError(t, projectEvent(context.Background(), exec, event))
+	require.Len(t, exec.queries, 2)
+	assert.Equal(t, []interface{}{"doc-1", "deleted", at}, exec.args[0])
+	assert.Contains(t, exec.queries[1], "documents_deleted")
+
+	exec = &recordingExecer{}
+	event = NewNotebookEvent(EventNotebookDeleted, "nb-1", "user-1", nil)
+	event.Timestamp = at
+	require.NoError(t, projectEvent(context.Background(), exec, event))
+	require.Len(t, exec.queries, 1)
+	assert.Contains(t, exec.queries[0], "UPDATE reporting_notebooks")
+	assert.Equal(t, []interface{}{"nb-1", "deleted", at}, exec.args[0])
+}
+
+func TestProjectRestores(t *testing.T) {
+	at := time.Date(2026, 3, 11, 8, 0, 0, 0, time.UTC)
+
+	exec := &recordingExecer{}
+	event := NewDocumentEvent(EventDocumentRestored, "doc-1", "user-1", map[string]interface{}{"status": "processed"})
+	event.Timestamp = at
+	require.NoError(t, projectEvent(context.Background(), exec, replayed(t, event)))
+	require.Len(t, exec.queries, 1)
+	assert.Contains(t, exec.queries[0], "UPDATE reporting_documents SET status = $2, deleted_at = NULL")
+	assert.Equal(t, []interface{}{"doc-1", "processed", at}, exec.args[0])
+
+	exec = &recordingExecer{}
+	event = NewNotebookEvent(EventNotebookRestored, "nb-1", "user-1", map[string]interface{}{"status": "active"})
+	event.Timestamp = at
+	require.NoError(t, projectEvent(context.Background(), exec, replayed(t, event)))
+	require.Len(t, exec.queries, 1)
+	assert.Contains(t, exec.queries[0], "UPDATE reporting_notebooks")
+	assert.Equal(t, []interface{}{"nb-1", "active", at}, exec.args[0])
+}
+
+func TestProjectNotebookCreated(t *testing.T) {
+	created := time.Date(2026, 3, 10, 9, 30, 0, 0, time.UTC)
+	notebook := &models.Notebook{
+		ID:         "nb-1",
+		Name:       "Research",
+		Status:     "active",
+		Visibility: "private",
+		OwnerID:    "user-1",
+		SpaceType:  models.SpaceTypePersonal,
+		SpaceID:    "space-1",
+		TenantID:   "tenant-1",
+		CreatedAt:  created,
+		UpdatedAt:  created,
+	}
+
+	exec := &recordingExecer{}
+	event := NewNotebookEvent(EventNotebookCreated, notebook.ID, notebook.OwnerID, notebookEventData(notebook))
+	require.NoError(t, projectEvent(context.Background(), exec, replayed(t, event)))
+
+	require.Len(t, exec.queries, 1)
+	assert.Contains(t, exec.queries[0], "INSERT INTO reporting_notebooks")
+	assert.Equal(t, []interface{}{"nb-1", "Research", "active", "private", "user-1", "personal",
+		"space-1", "tenant-1", "", "", created, created}, exec.args[0])
+}
+
+func TestProjectIgnoresOtherEvents(t *testing.T) {
+	exec := &recordingExecer{}
+	require.NoError(t, projectEvent(context.Background(), exec, NewUserEvent(EventUserLoggedIn, "user-1", nil)))
+	assert.Empty(t, exec.queries)
+}
+
+func TestProjectRejectsMalformedPayload(t *testing.T) {
+	exec := &recordingExecer{}
+	event := NewDocumentEvent(EventDocumentUploaded, "doc-1", "user-1", map[string]interface{}{"size_bytes": "large"})
+	assert.Error(t, projectEvent(context.Background(), exec, event))
+	assert.Empty(t, exec.queries)
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/metrics"
+)
+
+// routerChat asks the LLM router for chat completions on behalf of the
+// services that call a model without an agent
+type routerChat struct {
+	router *config.RouterConfig
+	client *http.Client
+}
+
+// newRouterChat creates a router chat client. router may be nil.
+func newRouterChat(router *config.RouterConfig) *routerChat {
+	transport := metrics.NewExternalTransport("router", logger.NewRequestIDTransport(nil), metrics.ExternalTransportOptions{})
+	return &routerChat{
+		router: router,
+		client: &http.Client{
+			Timeout:   defaultExternalHTTPTimeout,
+			Transport: transport,
+		},
+	}
+}
+
+// enabled reports whether the router is configured
+func (r *routerChat) enabled() bool {
+	return r.router != nil && r.router.Enabled && r.router.Service.BaseURL != ""
+}
+
+// serviceAuth reports whether the router accepts the service's own key, so
+// a completion can be asked for without a user's token
+func (r *routerChat) serviceAuth() bool {
+	return r.enabled() && r.router.Service.UseServiceAuth && r.router.Service.APIKey != ""
+}
+
+// routerChatRequest is a chat completion asked of the router
+type routerChatRequest struct {
+	Model     string
+	Provider  string
+	MaxTokens int
+	Messages  []map[string]string
+}
+
+// routerCompletion is the part of an OpenAI-compatible chat completion the
+// answer is read from
+type routerCompletion struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		TotalTokens int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// complete asks the router for a chat completion. The user's token is
+// passed to the router unless it authenticates with the service's own key.
+func (r *routerChat) complete(ctx context.Context, request routerChatRequest, authToken string) (*LLMResponse, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"model":       request.Model,
+		"provider":    request.Provider,
+		"messages":    request.Messages,
+		"max_tokens":  request.MaxTokens,
+		"temperature": 0.2,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	url := strings.TrimSuffix(r.router.Service.BaseURL, "/") + r.router.Endpoints.ChatCompletions
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.router.Service.UseServiceAuth && r.router.Service.APIKey != "" {
+		req.Header.Set("X-API-Key", r.router.Service.APIKey)
+	} else if authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+authToken)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("router service returned %d: %s", resp.StatusCode, string(data))
+	}
+
+	var completion routerCompletion
+	if err := json.NewDecoder(resp.Body).Decode(&completion); err != nil {
+		return nil, fmt.Errorf("failed to decode router response: %w", err)
+	}
+	if len(completion.Choices) == 0 {
+		return nil, fmt.Errorf("no response from LLM")
+	}
+	return &LLMResponse{
+		Content:    strings.TrimSpace(completion.Choices[0].Message.Content),
+		TokensUsed: completion.Usage.TotalTokens,
+		Model:      completion.Model,
+		Provider:   request.Provider,
+	}, nil
+}
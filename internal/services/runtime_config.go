@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// Sources of runtime configuration changes recorded in the audit trail
+const (
+	ConfigSourceSignal = "sighup"
+	ConfigSourceAdmin  = "admin_api"
+)
+
+// RuntimeConfigService applies runtime configuration changes (log level,
+// rate limits, feature flags, AudiModal timeouts) and records an audit entry
+// for every change
+type RuntimeConfigService struct {
+	store   *config.RuntimeStore
+	audit   *AuditService
+	envFile string
+	logger  *logger.Logger
+
+	// mu serializes reload and update so audit entries match the applied order
+	mu sync.Mutex
+}
+
+// NewRuntimeConfigService creates a new runtime config service. envFile is
+// re-read on Reload; an empty path reloads from the process environment only.
+// audit may be nil, in which case changes are only logged.
+func NewRuntimeConfigService(store *config.RuntimeStore, audit *AuditService, envFile string, log *logger.Logger) *RuntimeConfigService {
+	return &RuntimeConfigService{
+		store:   store,
+		audit:   audit,
+		envFile: envFile,
+		logger:  log.WithService("runtime_config_service"),
+	}
+}
+
+// Current returns the active runtime configuration
+func (s *RuntimeConfigService) Current() config.RuntimeConfig {
+	return s.store.Current()
+}
+
+// Reload re-reads the runtime configuration from the environment file
+func (s *RuntimeConfigService) Reload(ctx context.Context, actorID, source string) ([]config.RuntimeChange, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next, err := config.LoadRuntimeConfig(s.envFile)
+	if err != nil {
+		s.logger.Error("Runtime configuration reload rejected", zap.String("source", source), zap.Error(err))
+		return nil, errors.ValidationWithDetails("Invalid runtime configuration", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	return s.apply(ctx, next, actorID, source)
+}
+
+// Update applies a runtime configuration submitted through the admin API
+func (s *RuntimeConfigService) Update(ctx context.Context, next config.RuntimeConfig, actorID string) ([]config.RuntimeChange, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.apply(ctx, next, actorID, ConfigSourceAdmin)
+}
+
+// apply validates and stores the configuration and audits the changes
+func (s *RuntimeConfigService) apply(ctx context.Context, next config.RuntimeConfig, actorID, source string) ([]config.RuntimeChange, error) {
+	changes, err := s.store.Apply(next)
+	if err != nil {
+		return nil, errors.ValidationWithDetails("Invalid runtime configuration", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	if len(changes) == 0 {
+		s.logger.Info("Runtime configuration unchanged", zap.String("source", source))
+		return changes, nil
+	}
+
+	s.recordAudit(ctx, actorID, source, changes)
+	return changes, nil
+}
+
+// recordAudit logs the change and records it in the audit log
+func (s *RuntimeConfigService) recordAudit(ctx context.Context, actorID, source string, changes []config.RuntimeChange) {
+	s.logger.FromContext(ctx).Info("Runtime configuration changed",
+		zap.Bool("audit", true),
+		zap.String("actor_id", actorID),
+		zap.String("source", source),
+		zap.Any("changes", changes),
+	)
+
+	recordAuditEvent(ctx, s.audit, s.logger, &models.AuditEvent{
+		Action:       "config.update",
+		ResourceType: "runtime_config",
+		ActorID:      actorID,
+		Source:       source,
+		Details:      map[string]interface{}{"changes": changes},
+	})
+}
@@ -0,0 +1,342 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+// S3 watch limits
+const (
+	// s3WatchMaxListPages bounds the listing of a polled prefix; larger
+	// prefixes should be watched through SQS
+	s3WatchMaxListPages = 50
+	// sqsVisibilityTimeout hides received messages from other readers
+	// while their objects are ingested
+	sqsVisibilityTimeout = 300
+	// sqsMaxResponseBytes bounds the responses read from SQS
+	sqsMaxResponseBytes = 4 << 20
+)
+
+// s3WatchObject is an object of a watched bucket
+type s3WatchObject struct {
+	Key          string
+	ETag         string
+	Size         int64
+	LastModified time.Time
+}
+
+// sqsMessage is a message received from an SQS queue
+type sqsMessage struct {
+	MessageID     string `json:"MessageId"`
+	ReceiptHandle string `json:"ReceiptHandle"`
+	Body          string `json:"Body"`
+}
+
+// s3WatchSource reads the bucket and queue of a watcher
+type s3WatchSource interface {
+	// ListModified returns up to limit objects under prefix modified at
+	// or after since, oldest first
+	ListModified(ctx context.Context, prefix string, since time.Time, limit int) ([]s3WatchObject, error)
+	// GetObject returns an object's content and type; ok is false when it
+	// is larger than maxBytes
+	GetObject(ctx context.Context, key string, maxBytes int64) (data []byte, contentType string, ok bool, err error)
+	// ReceiveMessages returns the queue's waiting messages
+	ReceiveMessages(ctx context.Context) ([]sqsMessage, error)
+	// DeleteMessage deletes a handled message from the queue
+	DeleteMessage(ctx context.Context, receiptHandle string) error
+}
+
+// newAWSS3WatchSource reads a watcher's bucket and queue with the
+// deployment's credentials, or those of the customer's role when the
+// watcher has one
+func newAWSS3WatchSource(ctx context.Context, watcher *models.S3Watcher) (s3WatchSource, error) {
+	awsConfig, err := config.LoadDefaultConfig(ctx, config.WithRegion(watcher.Region))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	if watcher.RoleARN != "" {
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(awsConfig), watcher.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+			o.ExternalID = aws.String(watcher.ExternalID)
+			o.RoleSessionName = "aether-s3-watch-" + watcher.ID
+		})
+		awsConfig.Credentials = aws.NewCredentialsCache(provider)
+	}
+
+	source := &awsS3WatchSource{
+		s3:     s3.NewFromConfig(awsConfig),
+		bucket: watcher.Bucket,
+	}
+	if watcher.QueueURL != "" {
+		endpoint, err := sqsEndpoint(watcher.QueueURL, watcher.Region)
+		if err != nil {
+			return nil, err
+		}
+		source.queue = &sqsQueue{
+			client:      &http.Client{Timeout: 30 * time.Second},
+			credentials: awsConfig.Credentials,
+			signer:      v4.NewSigner(),
+			region:      watcher.Region,
+			queueURL:    watcher.QueueURL,
+			endpoint:    endpoint,
+		}
+	}
+	return source, nil
+}
+
+// awsS3WatchSource reads a watched bucket with the S3 SDK and its queue
+// over the SQS JSON protocol
+type awsS3WatchSource struct {
+	s3     *s3.Client
+	bucket string
+	queue  *sqsQueue
+}
+
+// ListModified lists the prefix, which S3 orders by key, and keeps the
+// objects modified since the given time
+func (s *awsS3WatchSource) ListModified(ctx context.Context, prefix string, since time.Time, limit int) ([]s3WatchObject, error) {
+	var objects []s3WatchObject
+	input := &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	}
+	for page := 0; page < s3WatchMaxListPages; page++ {
+		output, err := s.s3.ListObjectsV2(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range output.Contents {
+			modified := aws.ToTime(object.LastModified)
+			if modified.Before(since) {
+				continue
+			}
+			objects = append(objects, s3WatchObject{
+				Key:          aws.ToString(object.Key),
+				ETag:         strings.Trim(aws.ToString(object.ETag), `"`),
+				Size:         aws.ToInt64(object.Size),
+				LastModified: modified,
+			})
+		}
+		if !aws.ToBool(output.IsTruncated) {
+			break
+		}
+		input.ContinuationToken = output.NextContinuationToken
+	}
+
+	sort.SliceStable(objects, func(i, j int) bool {
+		return objects[i].LastModified.Before(objects[j].LastModified)
+	})
+	if len(objects) > limit {
+		objects = objects[:limit]
+	}
+	return objects, nil
+}
+
+// GetObject downloads an object, stopping past maxBytes
+func (s *awsS3WatchSource) GetObject(ctx context.Context, key string, maxBytes int64) ([]byte, string, bool, error) {
+	output, err := s.s3.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, "", false, err
+	}
+	defer output.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(output.Body, maxBytes+1))
+	if err != nil {
+		return nil, "", false, err
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, "", false, nil
+	}
+	return data, aws.ToString(output.ContentType), true, nil
+}
+
+// ReceiveMessages receives up to ten messages without waiting
+func (s *awsS3WatchSource) ReceiveMessages(ctx context.Context) ([]sqsMessage, error) {
+	if s.queue == nil {
+		return nil, fmt.Errorf("watcher has no queue")
+	}
+	var output struct {
+		Messages []sqsMessage `json:"Messages"`
+	}
+	err := s.queue.call(ctx, "ReceiveMessage", map[string]interface{}{
+		"QueueUrl":            s.queue.queueURL,
+		"MaxNumberOfMessages": 10,
+		"VisibilityTimeout":   sqsVisibilityTimeout,
+	}, &output)
+	return output.Messages, err
+}
+
+// DeleteMessage deletes a handled message
+func (s *awsS3WatchSource) DeleteMessage(ctx context.Context, receiptHandle string) error {
+	if s.queue == nil {
+		return fmt.Errorf("watcher has no queue")
+	}
+	return s.queue.call(ctx, "DeleteMessage", map[string]interface{}{
+		"QueueUrl":      s.queue.queueURL,
+		"ReceiptHandle": receiptHandle,
+	}, nil)
+}
+
+// sqsQueue calls an SQS queue over the JSON protocol, signing requests
+// with Signature Version 4
+type sqsQueue struct {
+	client      *http.Client
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+	region      string
+	queueURL    string
+	endpoint    string
+	now         func() time.Time // Overridden in tests
+}
+
+// sqsError is the error body of the SQS JSON protocol
+type sqsError struct {
+	Type    string `json:"__type"`
+	Message string `json:"message"`
+}
+
+// call runs an SQS action, decoding its response into output
+func (q *sqsQueue) call(ctx context.Context, action string, input, output interface{}) error {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, q.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.0")
+	req.Header.Set("X-Amz-Target", "AmazonSQS."+action)
+
+	credentials, err := q.credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+	now := time.Now
+	if q.now != nil {
+		now = q.now
+	}
+	payloadHash := sha256.Sum256(body)
+	if err := q.signer.SignHTTP(ctx, credentials, req, hex.EncodeToString(payloadHash[:]), "sqs", q.region, now()); err != nil {
+		return fmt.Errorf("failed to sign SQS request: %w", err)
+	}
+
+	resp, err := q.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("SQS %s failed: %w", action, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, sqsMaxResponseBytes))
+	if err != nil {
+		return fmt.Errorf("failed to read SQS %s response: %w", action, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var failure sqsError
+		_ = json.Unmarshal(data, &failure)
+		return fmt.Errorf("SQS %s failed with status %d: %s %s", action, resp.StatusCode, failure.Type, failure.Message)
+	}
+	if output == nil {
+		return nil
+	}
+	return json.Unmarshal(data, output)
+}
+
+// sqsHost matches the hosts of SQS queue URLs, whose region is captured
+var sqsHost = regexp.MustCompile(`^sqs\.([a-z0-9-]+)\.amazonaws\.com(\.cn)?$`)
+
+// sqsEndpoint returns the endpoint of an SQS queue URL in region. Only
+// SQS hosts are accepted, so a watcher cannot direct signed requests
+// elsewhere.
+func sqsEndpoint(queueURL, region string) (string, error) {
+	parsed, err := url.Parse(queueURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid queue URL: %w", err)
+	}
+	match := sqsHost.FindStringSubmatch(parsed.Host)
+	if parsed.Scheme != "https" || match == nil {
+		return "", fmt.Errorf("queue URL must be an https://sqs.<region>.amazonaws.com URL")
+	}
+	if match[1] != region {
+		return "", fmt.Errorf("queue is in region %s, not the bucket's region %s", match[1], region)
+	}
+	return "https://" + parsed.Host + "/", nil
+}
+
+// s3EventNotification is an S3 event notification, as S3 sends it to SQS
+// directly or to an SNS topic the queue subscribes to
+type s3EventNotification struct {
+	Event   string `json:"Event"` // s3:TestEvent when the notification is configured
+	Records []struct {
+		EventName string `json:"eventName"`
+		S3        struct {
+			Bucket struct {
+				Name string `json:"name"`
+			} `json:"bucket"`
+			Object struct {
+				Key  string `json:"key"`
+				Size int64  `json:"size"`
+				ETag string `json:"eTag"`
+			} `json:"object"`
+		} `json:"s3"`
+	} `json:"Records"`
+}
+
+// parseS3EventMessage returns the objects created in bucket according to
+// the S3 event notification in an SQS message body. Notifications that
+// arrive through SNS are unwrapped first.
+func parseS3EventMessage(body, bucket string) ([]s3WatchObject, error) {
+	var envelope struct {
+		Type    string `json:"Type"`
+		Message string `json:"Message"`
+	}
+	if err := json.Unmarshal([]byte(body), &envelope); err != nil {
+		return nil, fmt.Errorf("invalid S3 event notification: %w", err)
+	}
+	if envelope.Type == "Notification" && envelope.Message != "" {
+		body = envelope.Message
+	}
+
+	var notification s3EventNotification
+	if err := json.Unmarshal([]byte(body), &notification); err != nil {
+		return nil, fmt.Errorf("invalid S3 event notification: %w", err)
+	}
+	var objects []s3WatchObject
+	for _, record := range notification.Records {
+		if !strings.HasPrefix(record.EventName, "ObjectCreated:") || record.S3.Bucket.Name != bucket {
+			continue
+		}
+		// Keys are URL encoded in notifications, with "+" for spaces
+		key, err := url.QueryUnescape(record.S3.Object.Key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid object key %q: %w", record.S3.Object.Key, err)
+		}
+		objects = append(objects, s3WatchObject{
+			Key:  key,
+			ETag: strings.Trim(record.S3.Object.ETag, `"`),
+			Size: record.S3.Object.Size,
+		})
+	}
+	return objects, nil
+}
@@ -0,0 +1,659 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// maxS3WatchersPerNotebook bounds the watchers of one notebook
+const maxS3WatchersPerNotebook = 10
+
+// S3WatcherService watches customers' S3 prefixes and ingests their new
+// objects into notebooks, as documents owned by the notebook's owner. A
+// watcher reads the bucket's event notifications from SQS, or lists the
+// prefix for objects modified since its last run. Objects already ingested
+// with the same ETag are suppressed, so redelivered notifications and
+// overlapping listings add nothing.
+type S3WatcherService struct {
+	neo4j     *database.Neo4jClient
+	notebooks *NotebookService
+	documents *DocumentService
+	spaces    spaceContextResolver
+	logger    *logger.Logger
+
+	enabled          bool
+	principalARN     string
+	maxObjectsPerRun int
+	maxFileBytes     int64
+
+	// sources opens the bucket and queue of a watcher
+	sources func(ctx context.Context, watcher *models.S3Watcher) (s3WatchSource, error)
+}
+
+// NewS3WatcherService creates a new S3 watcher service. Objects over
+// maxFileBytes are skipped like uploads over the limit.
+func NewS3WatcherService(neo4j *database.Neo4jClient, notebooks *NotebookService, documents *DocumentService, spaces spaceContextResolver, cfg config.S3WatchConfig, maxFileBytes int64, log *logger.Logger) *S3WatcherService {
+	return &S3WatcherService{
+		neo4j:            neo4j,
+		notebooks:        notebooks,
+		documents:        documents,
+		spaces:           spaces,
+		logger:           log.WithService("s3_watcher_service"),
+		enabled:          cfg.Enabled,
+		principalARN:     cfg.PrincipalARN,
+		maxObjectsPerRun: cfg.MaxObjectsPerRun,
+		maxFileBytes:     maxFileBytes,
+		sources:          newAWSS3WatchSource,
+	}
+}
+
+// Setup returns what a tenant's role trust policy needs for watchers to
+// assume it
+func (s *S3WatcherService) Setup(spaceCtx *models.SpaceContext) (*models.S3WatchSetup, error) {
+	if !s.enabled {
+		return nil, s.disabled()
+	}
+	return &models.S3WatchSetup{
+		PrincipalARN: s.principalARN,
+		ExternalID:   s3WatchExternalID(spaceCtx.TenantID),
+	}, nil
+}
+
+// s3WatchExternalID is the external ID watchers of a tenant assume roles
+// with. It is derived from the tenant rather than chosen by the customer,
+// so one tenant cannot have watchers assume another tenant's role.
+func s3WatchExternalID(tenantID string) string {
+	return "aether-" + tenantID
+}
+
+// CreateWatcher starts watching an S3 prefix for a notebook. Only the
+// notebook's owner manages its watchers.
+func (s *S3WatcherService) CreateWatcher(ctx context.Context, notebookID string, req models.S3WatcherCreateRequest, userID string, spaceCtx *models.SpaceContext) (*models.S3Watcher, error) {
+	if err := s.ownNotebook(ctx, notebookID, userID, spaceCtx); err != nil {
+		return nil, err
+	}
+	for _, pattern := range append(append([]string{}, req.Include...), req.Exclude...) {
+		if err := checkS3Glob(pattern); err != nil {
+			return nil, errors.ValidationWithDetails("Invalid include or exclude pattern", map[string]interface{}{
+				"pattern": pattern,
+			})
+		}
+	}
+	mode := models.S3WatchModePoll
+	if req.QueueURL != "" {
+		if _, err := sqsEndpoint(req.QueueURL, req.Region); err != nil {
+			return nil, errors.ValidationWithDetails("Invalid queue URL", map[string]interface{}{
+				"queue_url": req.QueueURL,
+				"reason":    err.Error(),
+			})
+		}
+		mode = models.S3WatchModeSQS
+	}
+
+	now := time.Now().UTC()
+	watcher := &models.S3Watcher{
+		ID:         uuid.New().String(),
+		NotebookID: notebookID,
+		Bucket:     req.Bucket,
+		Prefix:     req.Prefix,
+		Region:     req.Region,
+		RoleARN:    req.RoleARN,
+		QueueURL:   req.QueueURL,
+		Mode:       mode,
+		Include:    req.Include,
+		Exclude:    req.Exclude,
+		Status:     models.S3WatcherActive,
+		CreatedBy:  userID,
+		CreatedAt:  now,
+	}
+	if watcher.RoleARN != "" {
+		watcher.ExternalID = s3WatchExternalID(spaceCtx.TenantID)
+	}
+
+	// Objects modified before the watcher was created are not ingested
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "s3_watcher.create"), `
+		MATCH (n:Notebook {id: $notebook_id, tenant_id: $tenant_id})
+		OPTIONAL MATCH (n)-[:HAS_S3_WATCHER]->(existing:S3Watcher)
+		WITH n, count(existing) AS watchers
+		WHERE watchers < $max_watchers
+		CREATE (n)-[:HAS_S3_WATCHER]->(w:S3Watcher {
+			id: $id, notebook_id: $notebook_id, tenant_id: $tenant_id,
+			bucket: $bucket, prefix: $prefix, region: $region,
+			role_arn: $role_arn, external_id: $external_id, queue_url: $queue_url,
+			mode: $mode, include: $include, exclude: $exclude,
+			status: $status, ingested_count: 0, watermark: $now,
+			created_by: $created_by, created_at: $now
+		})
+		RETURN w.id AS id
+	`, map[string]interface{}{
+		"notebook_id":  notebookID,
+		"tenant_id":    spaceCtx.TenantID,
+		"max_watchers": maxS3WatchersPerNotebook,
+		"id":           watcher.ID,
+		"bucket":       watcher.Bucket,
+		"prefix":       watcher.Prefix,
+		"region":       watcher.Region,
+		"role_arn":     watcher.RoleARN,
+		"external_id":  watcher.ExternalID,
+		"queue_url":    watcher.QueueURL,
+		"mode":         watcher.Mode,
+		"include":      watcher.Include,
+		"exclude":      watcher.Exclude,
+		"status":       watcher.Status,
+		"created_by":   userID,
+		"now":          now,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to create S3 watcher", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, errors.ValidationWithDetails("Notebook has too many S3 watchers", map[string]interface{}{
+			"max_watchers": maxS3WatchersPerNotebook,
+		})
+	}
+
+	s.logger.Info("S3 watcher created",
+		zap.String("watcher_id", watcher.ID),
+		zap.String("notebook_id", notebookID),
+		zap.String("bucket", watcher.Bucket),
+		zap.String("mode", watcher.Mode))
+	return watcher, nil
+}
+
+// s3WatcherFields are the properties returned for an S3Watcher node w
+const s3WatcherFields = `
+	w.id AS id, w.notebook_id AS notebook_id, w.bucket AS bucket, w.prefix AS prefix,
+	w.region AS region, w.role_arn AS role_arn, w.external_id AS external_id,
+	w.queue_url AS queue_url, w.mode AS mode, w.include AS include, w.exclude AS exclude,
+	w.status AS status, w.last_error AS last_error, w.last_run_at AS last_run_at,
+	w.ingested_count AS ingested_count, w.created_by AS created_by, w.created_at AS created_at
+`
+
+// ListWatchers lists the S3 watchers of a notebook, oldest first
+func (s *S3WatcherService) ListWatchers(ctx context.Context, notebookID, userID string, spaceCtx *models.SpaceContext) (*models.S3WatcherList, error) {
+	if err := s.ownNotebook(ctx, notebookID, userID, spaceCtx); err != nil {
+		return nil, err
+	}
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "s3_watcher.list"), `
+		MATCH (:Notebook {id: $notebook_id, tenant_id: $tenant_id})-[:HAS_S3_WATCHER]->(w:S3Watcher)
+		RETURN `+s3WatcherFields+`
+		ORDER BY w.created_at
+	`, map[string]interface{}{
+		"notebook_id": notebookID,
+		"tenant_id":   spaceCtx.TenantID,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to list S3 watchers", err)
+	}
+
+	list := &models.S3WatcherList{Watchers: make([]*models.S3Watcher, 0, len(result.Records))}
+	for _, record := range result.Records {
+		list.Watchers = append(list.Watchers, recordToS3Watcher(record))
+	}
+	return list, nil
+}
+
+// GetWatcher returns an S3 watcher of a notebook
+func (s *S3WatcherService) GetWatcher(ctx context.Context, notebookID, watcherID, userID string, spaceCtx *models.SpaceContext) (*models.S3Watcher, error) {
+	if err := s.ownNotebook(ctx, notebookID, userID, spaceCtx); err != nil {
+		return nil, err
+	}
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "s3_watcher.get"), `
+		MATCH (:Notebook {id: $notebook_id, tenant_id: $tenant_id})-[:HAS_S3_WATCHER]->(w:S3Watcher {id: $watcher_id})
+		RETURN `+s3WatcherFields, map[string]interface{}{
+		"notebook_id": notebookID,
+		"tenant_id":   spaceCtx.TenantID,
+		"watcher_id":  watcherID,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to get S3 watcher", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, s.watcherNotFound(watcherID)
+	}
+	return recordToS3Watcher(result.Records[0]), nil
+}
+
+// DeleteWatcher stops watching an S3 prefix. The objects it ingested stay
+// in the notebook.
+func (s *S3WatcherService) DeleteWatcher(ctx context.Context, notebookID, watcherID, userID string, spaceCtx *models.SpaceContext) error {
+	if err := s.ownNotebook(ctx, notebookID, userID, spaceCtx); err != nil {
+		return err
+	}
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "s3_watcher.delete"), `
+		MATCH (:Notebook {id: $notebook_id, tenant_id: $tenant_id})-[:HAS_S3_WATCHER]->(w:S3Watcher {id: $watcher_id})
+		OPTIONAL MATCH (w)-[:SAW]->(o:S3WatchedObject)
+		DETACH DELETE o
+		WITH DISTINCT w
+		DETACH DELETE w
+		RETURN count(w) AS deleted
+	`, map[string]interface{}{
+		"notebook_id": notebookID,
+		"tenant_id":   spaceCtx.TenantID,
+		"watcher_id":  watcherID,
+	})
+	if err != nil {
+		return errors.Database("Failed to delete S3 watcher", err)
+	}
+	if len(result.Records) == 0 || recordInt64(result.Records[0], "deleted") == 0 {
+		return s.watcherNotFound(watcherID)
+	}
+	s.logger.Info("S3 watcher deleted", zap.String("watcher_id", watcherID), zap.String("notebook_id", notebookID))
+	return nil
+}
+
+// ownNotebook checks that watchers are enabled and that the user owns the
+// notebook
+func (s *S3WatcherService) ownNotebook(ctx context.Context, notebookID, userID string, spaceCtx *models.SpaceContext) error {
+	if !s.enabled {
+		return s.disabled()
+	}
+	notebook, err := s.notebooks.GetNotebookByID(ctx, notebookID, userID, spaceCtx)
+	if err != nil {
+		return err
+	}
+	if notebook.OwnerID != userID {
+		return errors.Forbidden("Only the notebook owner can manage its S3 watchers")
+	}
+	return nil
+}
+
+func (s *S3WatcherService) disabled() error {
+	return errors.ServiceUnavailable("S3 watchers are not enabled").WithErrorCode(errors.CodeS3WatchDisabled)
+}
+
+func (s *S3WatcherService) watcherNotFound(watcherID string) error {
+	return errors.NotFoundWithDetails("S3 watcher not found", map[string]interface{}{
+		"watcher_id": watcherID,
+	}).WithErrorCode(errors.CodeS3WatcherNotFound)
+}
+
+// s3WatchTarget is a watcher with what ingesting into its notebook needs
+type s3WatchTarget struct {
+	watcher   *models.S3Watcher
+	tenantID  string
+	spaceType string
+	spaceID   string
+	ownerID   string
+	watermark time.Time
+}
+
+// ProcessDue runs every watcher of a notebook that is not deleted, as the
+// leader's s3_watchers job. A failing watcher is recorded as failing with
+// its error and does not stop the others.
+func (s *S3WatcherService) ProcessDue(ctx context.Context) error {
+	if !s.enabled {
+		return nil
+	}
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "s3_watcher.due"), `
+		MATCH (n:Notebook)-[:HAS_S3_WATCHER]->(w:S3Watcher)
+		WHERE `+database.NotDeleted("n")+`
+		MATCH (owner:User)
+		WHERE owner.id = n.owner_id OR owner.keycloak_id = n.owner_id
+		RETURN `+s3WatcherFields+`, w.watermark AS watermark, n.tenant_id AS tenant_id,
+		       n.space_type AS space_type, n.space_id AS space_id, owner.keycloak_id AS owner_id
+	`, nil)
+	if err != nil {
+		return fmt.Errorf("failed to find S3 watchers: %w", err)
+	}
+
+	for _, record := range result.Records {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		target := &s3WatchTarget{
+			watcher:   recordToS3Watcher(record),
+			tenantID:  recordString(record, "tenant_id"),
+			spaceType: recordString(record, "space_type"),
+			spaceID:   recordString(record, "space_id"),
+			ownerID:   recordString(record, "owner_id"),
+			watermark: recordTime(record, "watermark"),
+		}
+		ingested, runErr := s.runWatcher(ctx, target)
+		if runErr != nil {
+			s.logger.Warn("S3 watcher run failed",
+				zap.String("watcher_id", target.watcher.ID),
+				zap.String("bucket", target.watcher.Bucket),
+				zap.Error(runErr))
+		}
+		s.recordRun(ctx, target, ingested, runErr)
+	}
+	return nil
+}
+
+// runWatcher ingests the new objects of a watcher's prefix, returning how
+// many were ingested
+func (s *S3WatcherService) runWatcher(ctx context.Context, target *s3WatchTarget) (int, error) {
+	source, err := s.sources(ctx, target.watcher)
+	if err != nil {
+		return 0, err
+	}
+	if target.watcher.Mode == models.S3WatchModeSQS {
+		return s.drainQueue(ctx, target, source)
+	}
+	return s.pollPrefix(ctx, target, source)
+}
+
+// drainQueue ingests the objects of the queue's notifications. A message is
+// deleted once all its objects are handled; one whose objects failed stays
+// in the queue and is received again after its visibility timeout.
+func (s *S3WatcherService) drainQueue(ctx context.Context, target *s3WatchTarget, source s3WatchSource) (int, error) {
+	ingested, handled := 0, 0
+	var firstErr error
+	for handled < s.maxObjectsPerRun {
+		messages, err := source.ReceiveMessages(ctx)
+		if err != nil {
+			return ingested, err
+		}
+		if len(messages) == 0 {
+			break
+		}
+		for _, message := range messages {
+			objects, err := parseS3EventMessage(message.Body, target.watcher.Bucket)
+			if err != nil {
+				// A message that is not a notification would only come back
+				s.logger.Warn("Deleting unreadable S3 watcher message",
+					zap.String("watcher_id", target.watcher.ID),
+					zap.String("message_id", message.MessageID),
+					zap.Error(err))
+			}
+			failed := false
+			for _, object := range objects {
+				if !strings.HasPrefix(object.Key, target.watcher.Prefix) {
+					continue
+				}
+				added, err := s.ingest(ctx, target, source, object)
+				if err != nil {
+					failed = true
+					if firstErr == nil {
+						firstErr = err
+					}
+					continue
+				}
+				if added {
+					ingested++
+				}
+				handled++
+			}
+			if failed {
+				continue
+			}
+			if err := source.DeleteMessage(ctx, message.ReceiptHandle); err != nil {
+				return ingested, err
+			}
+		}
+	}
+	return ingested, firstErr
+}
+
+// pollPrefix ingests the objects modified since the watcher's watermark,
+// oldest first, moving the watermark past each. It stops at the first
+// failure so the object is tried again on the next run.
+func (s *S3WatcherService) pollPrefix(ctx context.Context, target *s3WatchTarget, source s3WatchSource) (int, error) {
+	objects, err := source.ListModified(ctx, target.watcher.Prefix, target.watermark, s.maxObjectsPerRun)
+	if err != nil {
+		return 0, err
+	}
+	ingested := 0
+	for _, object := range objects {
+		added, err := s.ingest(ctx, target, source, object)
+		if err != nil {
+			return ingested, err
+		}
+		if added {
+			ingested++
+		}
+		// Objects modified in the same second as the watermark are listed
+		// again and suppressed as duplicates
+		target.watermark = object.LastModified
+	}
+	return ingested, nil
+}
+
+// ingest adds an object to the watcher's notebook unless it is filtered
+// out, was ingested before with the same ETag, or cannot be a document. It
+// reports whether a document was added.
+func (s *S3WatcherService) ingest(ctx context.Context, target *s3WatchTarget, source s3WatchSource, object s3WatchObject) (bool, error) {
+	watcher := target.watcher
+	if !s3KeyWatched(watcher, object.Key) {
+		return false, nil
+	}
+	dedupeKey := s3WatchDedupeKey(watcher.ID, object.Key, object.ETag)
+	seen, err := s.seen(ctx, dedupeKey)
+	if err != nil || seen {
+		return false, err
+	}
+
+	if strings.HasSuffix(object.Key, "/") || object.Size == 0 || object.Size > s.maxFileBytes {
+		return false, s.recordObject(ctx, watcher.ID, dedupeKey, object, models.S3ObjectSkipped, "")
+	}
+	data, contentType, ok, err := source.GetObject(ctx, object.Key, s.maxFileBytes)
+	if err != nil {
+		return false, fmt.Errorf("failed to download s3://%s/%s: %w", watcher.Bucket, object.Key, err)
+	}
+	if !ok || len(data) == 0 {
+		return false, s.recordObject(ctx, watcher.ID, dedupeKey, object, models.S3ObjectSkipped, "")
+	}
+
+	spaceCtx, err := s.spaces.ResolveSpaceContext(ctx, target.ownerID, models.SpaceContextRequest{
+		SpaceType: models.SpaceType(target.spaceType),
+		SpaceID:   target.spaceID,
+	})
+	if err != nil {
+		return false, err
+	}
+	name := path.Base(object.Key)
+	document, err := s.documents.UploadDocument(ctx, models.DocumentUploadRequest{
+		DocumentCreateRequest: models.DocumentCreateRequest{
+			Name:       name,
+			NotebookID: watcher.NotebookID,
+			Tags:       []string{"s3"},
+			Metadata: map[string]interface{}{
+				"source":        "s3",
+				"s3_bucket":     watcher.Bucket,
+				"s3_key":        object.Key,
+				"s3_etag":       object.ETag,
+				"s3_watcher_id": watcher.ID,
+			},
+		},
+		FileData: data,
+	}, target.ownerID, spaceCtx, models.FileInfo{
+		OriginalName: name,
+		MimeType:     s3ObjectMimeType(name, contentType),
+		SizeBytes:    int64(len(data)),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to ingest s3://%s/%s: %w", watcher.Bucket, object.Key, err)
+	}
+
+	s.logger.Info("S3 object ingested",
+		zap.String("watcher_id", watcher.ID),
+		zap.String("key", object.Key),
+		zap.String("document_id", document.ID))
+	return true, s.recordObject(ctx, watcher.ID, dedupeKey, object, models.S3ObjectIngested, document.ID)
+}
+
+// seen reports whether an object version was handled before
+func (s *S3WatcherService) seen(ctx context.Context, dedupeKey string) (bool, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "s3_watcher.seen"), `
+		MATCH (o:S3WatchedObject {dedupe_key: $dedupe_key})
+		RETURN o.dedupe_key AS dedupe_key
+	`, map[string]interface{}{"dedupe_key": dedupeKey})
+	if err != nil {
+		return false, fmt.Errorf("failed to check for duplicate S3 object: %w", err)
+	}
+	return len(result.Records) > 0, nil
+}
+
+// recordObject records how an object version was handled, suppressing it
+// from then on
+func (s *S3WatcherService) recordObject(ctx context.Context, watcherID, dedupeKey string, object s3WatchObject, status, documentID string) error {
+	_, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "s3_watcher.record_object"), `
+		MATCH (w:S3Watcher {id: $watcher_id})
+		MERGE (w)-[:SAW]->(o:S3WatchedObject {dedupe_key: $dedupe_key})
+		ON CREATE SET o.key = $key, o.etag = $etag, o.size = $size, o.status = $status,
+		              o.document_id = $document_id, o.created_at = $now
+	`, map[string]interface{}{
+		"watcher_id":  watcherID,
+		"dedupe_key":  dedupeKey,
+		"key":         object.Key,
+		"etag":        object.ETag,
+		"size":        object.Size,
+		"status":      status,
+		"document_id": documentID,
+		"now":         time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record S3 object: %w", err)
+	}
+	return nil
+}
+
+// recordRun records the outcome of a watcher's run and its watermark
+func (s *S3WatcherService) recordRun(ctx context.Context, target *s3WatchTarget, ingested int, runErr error) {
+	status, lastError := models.S3WatcherActive, ""
+	if runErr != nil {
+		status, lastError = models.S3WatcherFailing, runErr.Error()
+	}
+	_, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "s3_watcher.record_run"), `
+		MATCH (w:S3Watcher {id: $watcher_id})
+		SET w.status = $status, w.last_error = $last_error, w.last_run_at = $now,
+		    w.watermark = $watermark, w.ingested_count = coalesce(w.ingested_count, 0) + $ingested
+	`, map[string]interface{}{
+		"watcher_id": target.watcher.ID,
+		"status":     status,
+		"last_error": lastError,
+		"now":        time.Now().UTC(),
+		"watermark":  target.watermark,
+		"ingested":   ingested,
+	})
+	if err != nil {
+		s.logger.Error("Failed to record S3 watcher run", zap.String("watcher_id", target.watcher.ID), zap.Error(err))
+	}
+}
+
+// s3WatchDedupeKey identifies a version of an object seen by a watcher
+func s3WatchDedupeKey(watcherID, key, etag string) string {
+	sum := sha256.Sum256([]byte(watcherID + "\x00" + key + "\x00" + etag))
+	return hex.EncodeToString(sum[:])
+}
+
+// s3ObjectMimeType returns the type of an object from its content type,
+// or from its name when S3 has only the generic binary type
+func s3ObjectMimeType(name, contentType string) string {
+	if contentType != "" && contentType != "binary/octet-stream" && contentType != "application/octet-stream" {
+		return contentType
+	}
+	if byExtension := mime.TypeByExtension(path.Ext(name)); byExtension != "" {
+		return byExtension
+	}
+	return "application/octet-stream"
+}
+
+// s3KeyWatched reports whether a watcher ingests an object. The key,
+// relative to the prefix, must match an include pattern, when there are
+// any, and no exclude pattern.
+func s3KeyWatched(watcher *models.S3Watcher, key string) bool {
+	if !strings.HasPrefix(key, watcher.Prefix) {
+		return false
+	}
+	relative := strings.TrimPrefix(strings.TrimPrefix(key, watcher.Prefix), "/")
+	for _, pattern := range watcher.Exclude {
+		if matchS3Glob(pattern, relative) {
+			return false
+		}
+	}
+	if len(watcher.Include) == 0 {
+		return true
+	}
+	for _, pattern := range watcher.Include {
+		if matchS3Glob(pattern, relative) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchS3Glob matches a key against a glob. "*", "?" and character classes
+// stay within a path segment and "**" spans any number of segments. A
+// pattern without "/" matches the last segment, so "*.pdf" matches PDFs
+// at any depth.
+func matchS3Glob(pattern, key string) bool {
+	if !strings.Contains(pattern, "/") {
+		return matchGlobSegments([]string{pattern}, []string{path.Base(key)})
+	}
+	return matchGlobSegments(strings.Split(pattern, "/"), strings.Split(key, "/"))
+}
+
+func matchGlobSegments(pattern, key []string) bool {
+	if len(pattern) == 0 {
+		return len(key) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(key); i++ {
+			if matchGlobSegments(pattern[1:], key[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(key) == 0 {
+		return false
+	}
+	if ok, err := path.Match(pattern[0], key[0]); err != nil || !ok {
+		return false
+	}
+	return matchGlobSegments(pattern[1:], key[1:])
+}
+
+// checkS3Glob checks that each segment of a glob is a valid pattern
+func checkS3Glob(pattern string) error {
+	for _, segment := range strings.Split(pattern, "/") {
+		if _, err := path.Match(segment, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordToS3Watcher reads the s3WatcherFields of a record
+func recordToS3Watcher(record *neo4j.Record) *models.S3Watcher {
+	watcher := &models.S3Watcher{
+		ID:            recordString(record, "id"),
+		NotebookID:    recordString(record, "notebook_id"),
+		Bucket:        recordString(record, "bucket"),
+		Prefix:        recordString(record, "prefix"),
+		Region:        recordString(record, "region"),
+		RoleARN:       recordString(record, "role_arn"),
+		ExternalID:    recordString(record, "external_id"),
+		QueueURL:      recordString(record, "queue_url"),
+		Mode:          recordString(record, "mode"),
+		Include:       recordStrings(record, "include"),
+		Exclude:       recordStrings(record, "exclude"),
+		Status:        recordString(record, "status"),
+		LastError:     recordString(record, "last_error"),
+		IngestedCount: recordInt64(record, "ingested_count"),
+		CreatedBy:     recordString(record, "created_by"),
+		CreatedAt:     recordTime(record, "created_at"),
+	}
+	if at := recordTime(record, "last_run_at"); !at.IsZero() {
+		watcher.LastRunAt = &at
+	}
+	return watcher
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+func TestMatchS3Glob(t *testing.T) {
+	cases := []struct {
+		pattern, key string
+		match        bool
+	}{
+		{"*.pdf", "report.pdf", true},
+		{"*.pdf", "2024/q1/report.pdf", true},
+		{"*.pdf", "report.docx", false},
+		{"reports/*.pdf", "reports/q1.pdf", true},
+		{"reports/*.pdf", "reports/2024/q1.pdf", false},
+		{"reports/**/*.pdf", "reports/2024/q1/report.pdf", true},
+		{"reports/**/*.pdf", "reports/q1.pdf", true},
+		{"**/tmp/*", "a/b/tmp/x.txt", true},
+		{"**/tmp/*", "a/b/tmp", false},
+		{"data/?.csv", "data/a.csv", true},
+		{"data/[a-c].csv", "data/d.csv", false},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.match, matchS3Glob(tc.pattern, tc.key), "%s against %s", tc.pattern, tc.key)
+	}
+
+	assert.NoError(t, checkS3Glob("reports/**/*.pdf"))
+	assert.Error(t, checkS3Glob("reports/[a-"))
+}
+
+func TestS3KeyWatched(t *testing.T) {
+	watcher := &models.S3Watcher{
+		Prefix:  "incoming/",
+		Include: []string{"*.pdf", "*.docx"},
+		Exclude: []string{"drafts/**"},
+	}
+	assert.True(t, s3KeyWatched(watcher, "incoming/contract.pdf"))
+	assert.True(t, s3KeyWatched(watcher, "incoming/2024/memo.docx"))
+	assert.False(t, s3KeyWatched(watcher, "incoming/image.png"), "not included")
+	assert.False(t, s3KeyWatched(watcher, "incoming/drafts/contract.pdf"), "excludes win")
+	assert.False(t, s3KeyWatched(watcher, "outgoing/contract.pdf"), "outside the prefix")
+
+	all := &models.S3Watcher{Prefix: "incoming"}
+	assert.True(t, s3KeyWatched(all, "incoming/anything.bin"), "no includes match all")
+}
+
+func TestS3WatchDedupeKey(t *testing.T) {
+	key := s3WatchDedupeKey("watcher-1", "a.pdf", "etag-1")
+	assert.Equal(t, key, s3WatchDedupeKey("watcher-1", "a.pdf", "etag-1"))
+	assert.NotEqual(t, key, s3WatchDedupeKey("watcher-1", "a.pdf", "etag-2"), "a new version is ingested")
+	assert.NotEqual(t, key, s3WatchDedupeKey("watcher-2", "a.pdf", "etag-1"), "watchers dedupe separately")
+}
+
+func TestS3ObjectMimeType(t *testing.T) {
+	assert.Equal(t, "text/csv", s3ObjectMimeType("a.pdf", "text/csv"))
+	assert.Equal(t, "application/pdf", s3ObjectMimeType("a.pdf", "binary/octet-stream"))
+	assert.Equal(t, "application/octet-stream", s3ObjectMimeType("a.unknownext", ""))
+}
+
+const s3CreatedEvent = `{"Records":[
+	{"eventName":"ObjectCreated:Put","s3":{"bucket":{"name":"acme-docs"},"object":{"key":"incoming/Q1+report%281%29.pdf","size":1024,"eTag":"abc123"}}},
+	{"eventName":"ObjectRemoved:Delete","s3":{"bucket":{"name":"acme-docs"},"object":{"key":"incoming/old.pdf"}}},
+	{"eventName":"ObjectCreated:Copy","s3":{"bucket":{"name":"other-bucket"},"object":{"key":"incoming/x.pdf"}}}
+]}`
+
+func TestParseS3EventMessage(t *testing.T) {
+	objects, err := parseS3EventMessage(s3CreatedEvent, "acme-docs")
+	require.NoError(t, err)
+	require.Len(t, objects, 1)
+	assert.Equal(t, s3WatchObject{Key: "incoming/Q1 report(1).pdf", ETag: "abc123", Size: 1024}, objects[0])
+
+	// Through SNS the notification is the envelope's message
+	envelope, err := json.Marshal(map[string]string{"Type": "Notification", "Message": s3CreatedEvent})
+	require.NoError(t, err)
+	objects, err = parseS3EventMessage(string(envelope), "acme-docs")
+	require.NoError(t, err)
+	assert.Len(t, objects, 1)
+
+	objects, err = parseS3EventMessage(`{"Service":"Amazon S3","Event":"s3:TestEvent","Bucket":"acme-docs"}`, "acme-docs")
+	require.NoError(t, err)
+	assert.Empty(t, objects)
+
+	_, err = parseS3EventMessage("not json", "acme-docs")
+	assert.Error(t, err)
+}
+
+func TestSQSEndpoint(t *testing.T) {
+	endpoint, err := sqsEndpoint("https://sqs.eu-west-1.amazonaws.com/123456789012/acme-docs", "eu-west-1")
+	require.NoError(t, err)
+	assert.Equal(t, "https://sqs.eu-west-1.amazonaws.com/", endpoint)
+
+	_, err = sqsEndpoint("https://sqs.us-east-1.amazonaws.com/123456789012/acme-docs", "eu-west-1")
+	assert.Error(t, err, "another region")
+	_, err = sqsEndpoint("http://sqs.eu-west-1.amazonaws.com/123456789012/acme-docs", "eu-west-1")
+	assert.Error(t, err, "not https")
+	_, err = sqsEndpoint("https://attacker.example.com/123456789012/acme-docs", "eu-west-1")
+	assert.Error(t, err, "not SQS")
+}
+
+func TestSQSQueueCall(t *testing.T) {
+	var targets []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		targets = append(targets, r.Header.Get("X-Amz-Target"))
+		assert.Equal(t, "application/x-amz-json-1.0", r.Header.Get("Content-Type"))
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20240102/eu-west-1/sqs/aws4_request"))
+
+		body, _ := io.ReadAll(r.Body)
+		var input map[string]interface{}
+		require.NoError(t, json.Unmarshal(body, &input))
+		assert.Equal(t, "https://sqs.eu-west-1.amazonaws.com/123456789012/acme-docs", input["QueueUrl"])
+
+		switch r.Header.Get("X-Amz-Target") {
+		case "AmazonSQS.ReceiveMessage":
+			_, _ = w.Write([]byte(`{"Messages":[{"MessageId":"m-1","ReceiptHandle":"r-1","Body":"{}"}]}`))
+		case "AmazonSQS.DeleteMessage":
+			assert.Equal(t, "r-1", input["ReceiptHandle"])
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"com.amazonaws.sqs#InvalidAction","message":"unknown action"}`))
+		}
+	}))
+	defer server.Close()
+
+	source := &awsS3WatchSource{queue: &sqsQueue{
+		client:      server.Client(),
+		credentials: credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", ""),
+		signer:      v4.NewSigner(),
+		region:      "eu-west-1",
+		queueURL:    "https://sqs.eu-west-1.amazonaws.com/123456789012/acme-docs",
+		endpoint:    server.URL,
+		now:         func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) },
+	}}
+
+	messages, err := source.ReceiveMessages(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []sqsMessage{{MessageID: "m-1", ReceiptHandle: "r-1", Body: "{}"}}, messages)
+	require.NoError(t, source.DeleteMessage(context.Background(), "r-1"))
+
+	err = source.queue.call(context.Background(), "Purge", map[string]interface{}{"QueueUrl": source.queue.queueURL}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "InvalidAction")
+	assert.Equal(t, []string{"AmazonSQS.ReceiveMessage", "AmazonSQS.DeleteMessage", "AmazonSQS.Purge"}, targets)
+}
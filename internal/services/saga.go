@@ -0,0 +1,294 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+)
+
+// Saga statuses
+const (
+	// SagaRunning is a saga whose steps are still being carried out
+	SagaRunning = "running"
+	// SagaCompensating is a saga whose steps are being undone; a saga left
+	// in it had a compensation fail and is retried by recovery
+	SagaCompensating = "compensating"
+	// SagaFailed is a saga recovery gave up compensating; it is kept for
+	// an operator
+	SagaFailed = "failed"
+)
+
+// sagaMaxAttempts is how many times the compensation of a saga is tried
+// before it is marked failed
+const sagaMaxAttempts = 5
+
+// SagaCompensation undoes a step from the data recorded for its saga. It
+// must succeed when the step's effect is already gone or never happened,
+// since a step is recorded before its effect and may be undone twice.
+type SagaCompensation func(ctx context.Context, data map[string]string) error
+
+// SagaCoordinator runs multi-step operations across Neo4j, object storage
+// and AudiModal as sagas: each step registers what undoes it, and a
+// failure undoes the completed steps in reverse. Sagas are stored in Neo4j
+// as they progress, so Recover undoes those a crashed replica left behind.
+// Compensations are registered by step name rather than as closures for
+// the same reason.
+type SagaCoordinator struct {
+	neo4j         *database.Neo4jClient // nil keeps sagas in memory only
+	compensations map[string]SagaCompensation
+	logger        *logger.Logger
+}
+
+// Saga is one run of a multi-step operation
+type Saga struct {
+	ID       string
+	Kind     string
+	Steps    []string          // Recorded steps, in the order they were taken
+	Data     map[string]string // What the compensations of the steps need
+	Attempts int               // Compensations tried so far
+
+	coordinator *SagaCoordinator
+}
+
+// NewSagaCoordinator creates a saga coordinator. neo4j may be nil, in
+// which case sagas are not stored and cannot be recovered after a crash.
+func NewSagaCoordinator(neo4j *database.Neo4jClient, log *logger.Logger) *SagaCoordinator {
+	return &SagaCoordinator{
+		neo4j:         neo4j,
+		compensations: make(map[string]SagaCompensation),
+		logger:        log.WithService("saga"),
+	}
+}
+
+// Register sets the compensation of a step
+func (c *SagaCoordinator) Register(step string, compensate SagaCompensation) {
+	c.compensations[step] = compensate
+}
+
+// Begin starts and stores a saga
+func (c *SagaCoordinator) Begin(ctx context.Context, kind string, data map[string]string) (*Saga, error) {
+	saga := &Saga{
+		ID:          uuid.New().String(),
+		Kind:        kind,
+		Data:        make(map[string]string, len(data)),
+		coordinator: c,
+	}
+	for key, value := range data {
+		saga.Data[key] = value
+	}
+
+	if c.neo4j == nil {
+		return saga, nil
+	}
+	dataJSON, err := json.Marshal(saga.Data)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	_, err = c.neo4j.ExecuteQuery(database.WithQueryName(ctx, "saga.begin"), `
+		CREATE (:Saga {
+			id: $id, kind: $kind, status: $status, steps: [], data: $data,
+			attempts: 0, created_at: $now, updated_at: $now
+		})
+	`, map[string]interface{}{
+		"id":     saga.ID,
+		"kind":   kind,
+		"status": SagaRunning,
+		"data":   string(dataJSON),
+		"now":    now,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin %s saga: %w", kind, err)
+	}
+	return saga, nil
+}
+
+// Record adds a step to the saga with the data its compensation needs.
+// Record a step before taking it when its compensation can be built up
+// front, so a crash during the step is undone too; otherwise right after.
+func (s *Saga) Record(ctx context.Context, step string, data map[string]string) error {
+	if _, ok := s.coordinator.compensations[step]; !ok {
+		return fmt.Errorf("saga step %s has no compensation", step)
+	}
+	s.Steps = append(s.Steps, step)
+	for key, value := range data {
+		s.Data[key] = value
+	}
+	if err := s.coordinator.store(ctx, s, SagaRunning); err != nil {
+		return fmt.Errorf("failed to record saga step %s: %w", step, err)
+	}
+	return nil
+}
+
+// Finish ends a saga whose steps all succeeded, forgetting it. A saga that
+// cannot be forgotten is compensated by recovery, so the failure is logged.
+func (s *Saga) Finish(ctx context.Context) {
+	if err := s.coordinator.delete(ctx, s.ID); err != nil {
+		s.coordinator.logger.Error("Failed to finish saga",
+			zap.String("saga_id", s.ID),
+			zap.String("kind", s.Kind),
+			zap.Error(err))
+	}
+}
+
+// Abort undoes the recorded steps of a saga, newest first, because cause
+// ended it. It runs even if ctx is cancelled, as a cancelled request is a
+// common cause. Steps whose compensation fails are left to recovery.
+func (s *Saga) Abort(ctx context.Context, cause error) error {
+	ctx = context.WithoutCancel(ctx)
+	s.coordinator.logger.Warn("Compensating saga",
+		zap.String("saga_id", s.ID),
+		zap.String("kind", s.Kind),
+		zap.Strings("steps", s.Steps),
+		zap.NamedError("cause", cause))
+	return s.coordinator.compensate(ctx, s)
+}
+
+// Recover compensates the sagas that have not progressed for staleAfter,
+// left by a replica that crashed or by compensations that failed. Run it
+// on one replica at a time.
+func (c *SagaCoordinator) Recover(ctx context.Context, staleAfter time.Duration) error {
+	if c.neo4j == nil {
+		return nil
+	}
+	result, err := c.neo4j.ExecuteQuery(database.WithQueryName(ctx, "saga.stale"), `
+		MATCH (s:Saga)
+		WHERE s.status IN $statuses AND s.updated_at < $cutoff
+		RETURN s.id AS id, s.kind AS kind, s.steps AS steps, s.data AS data, s.attempts AS attempts
+		ORDER BY s.updated_at
+		LIMIT 100
+	`, map[string]interface{}{
+		"statuses": []string{SagaRunning, SagaCompensating},
+		"cutoff":   time.Now().UTC().Add(-staleAfter),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to find stale sagas: %w", err)
+	}
+
+	var firstErr error
+	for _, record := range result.Records {
+		saga, err := c.recordToSaga(record)
+		if err != nil {
+			c.logger.Error("Skipped unreadable saga", zap.String("saga_id", recordString(record, "id")), zap.Error(err))
+			continue
+		}
+		if err := c.compensate(ctx, saga); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if len(result.Records) > 0 {
+		c.logger.Info("Recovered stale sagas", zap.Int("count", len(result.Records)))
+	}
+	return firstErr
+}
+
+// compensate undoes the steps of a saga newest first. The saga is
+// forgotten once all are undone; otherwise the steps left are stored for
+// recovery to retry, until sagaMaxAttempts is reached.
+func (c *SagaCoordinator) compensate(ctx context.Context, saga *Saga) error {
+	saga.Attempts++
+	for len(saga.Steps) > 0 {
+		step := saga.Steps[len(saga.Steps)-1]
+		compensation, ok := c.compensations[step]
+		if !ok {
+			return c.compensationFailed(ctx, saga, step, fmt.Errorf("no compensation registered"))
+		}
+		if err := compensation(ctx, saga.Data); err != nil {
+			return c.compensationFailed(ctx, saga, step, err)
+		}
+		saga.Steps = saga.Steps[:len(saga.Steps)-1]
+	}
+	if err := c.delete(ctx, saga.ID); err != nil {
+		c.logger.Warn("Failed to forget compensated saga", zap.String("saga_id", saga.ID), zap.Error(err))
+	}
+	return nil
+}
+
+// compensationFailed stores a saga whose compensation of step failed
+func (c *SagaCoordinator) compensationFailed(ctx context.Context, saga *Saga, step string, cause error) error {
+	status := SagaCompensating
+	if saga.Attempts >= sagaMaxAttempts {
+		status = SagaFailed
+	}
+	c.logger.Error("Saga compensation failed",
+		zap.String("saga_id", saga.ID),
+		zap.String("kind", saga.Kind),
+		zap.String("step", step),
+		zap.Int("attempts", saga.Attempts),
+		zap.String("status", status),
+		zap.Error(cause))
+
+	if err := c.store(ctx, saga, status); err != nil {
+		c.logger.Error("Failed to store saga", zap.String("saga_id", saga.ID), zap.Error(err))
+	}
+	return fmt.Errorf("failed to compensate saga step %s: %w", step, cause)
+}
+
+// store saves the progress of a saga
+func (c *SagaCoordinator) store(ctx context.Context, saga *Saga, status string) error {
+	if c.neo4j == nil {
+		return nil
+	}
+	dataJSON, err := json.Marshal(saga.Data)
+	if err != nil {
+		return err
+	}
+	_, err = c.neo4j.ExecuteQuery(database.WithQueryName(ctx, "saga.store"), `
+		MATCH (s:Saga {id: $id})
+		SET s.status = $status, s.steps = $steps, s.data = $data,
+		    s.attempts = $attempts, s.updated_at = $now
+	`, map[string]interface{}{
+		"id":       saga.ID,
+		"status":   status,
+		"steps":    saga.Steps,
+		"data":     string(dataJSON),
+		"attempts": saga.Attempts,
+		"now":      time.Now().UTC(),
+	})
+	return err
+}
+
+// delete forgets a saga
+func (c *SagaCoordinator) delete(ctx context.Context, id string) error {
+	if c.neo4j == nil {
+		return nil
+	}
+	_, err := c.neo4j.ExecuteQuery(database.WithQueryName(ctx, "saga.delete"), `
+		MATCH (s:Saga {id: $id})
+		DELETE s
+	`, map[string]interface{}{"id": id})
+	return err
+}
+
+// recordToSaga reads a saga stored by the coordinator
+func (c *SagaCoordinator) recordToSaga(record *neo4j.Record) (*Saga, error) {
+	saga := &Saga{
+		ID:          recordString(record, "id"),
+		Kind:        recordString(record, "kind"),
+		Attempts:    int(recordInt64(record, "attempts")),
+		Data:        make(map[string]string),
+		coordinator: c,
+	}
+	if value, ok := record.Get("steps"); ok {
+		steps, _ := value.([]interface{})
+		for _, step := range steps {
+			if name, ok := step.(string); ok {
+				saga.Steps = append(saga.Steps, name)
+			}
+		}
+	}
+	if data := recordString(record, "data"); data != "" {
+		if err := json.Unmarshal([]byte(data), &saga.Data); err != nil {
+			return nil, err
+		}
+	}
+	return saga, nil
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSagaAbortCompensatesNewestFirst(t *testing.T) {
+	coordinator := NewSagaCoordinator(nil, setupTestLogger(t))
+	var undone []string
+	for _, step := range []string{"record", "file"} {
+		step := step
+		coordinator.Register(step, func(ctx context.Context, data map[string]string) error {
+			undone = append(undone, step+":"+data[step])
+			return nil
+		})
+	}
+	ctx := context.Background()
+
+	saga, err := coordinator.Begin(ctx, "test", map[string]string{"tenant_id": "tenant-1"})
+	require.NoError(t, err)
+	require.NoError(t, saga.Record(ctx, "record", map[string]string{"record": "doc-1"}))
+	require.NoError(t, saga.Record(ctx, "file", map[string]string{"file": "key-1"}))
+
+	require.NoError(t, saga.Abort(ctx, stderrors.New("submit failed")))
+	assert.Equal(t, []string{"file:key-1", "record:doc-1"}, undone)
+	assert.Empty(t, saga.Steps)
+}
+
+func TestSagaAbortKeepsStepsWhoseCompensationFails(t *testing.T) {
+	coordinator := NewSagaCoordinator(nil, setupTestLogger(t))
+	var undone []string
+	coordinator.Register("record", func(ctx context.Context, data map[string]string) error {
+		undone = append(undone, "record")
+		return nil
+	})
+	coordinator.Register("file", func(ctx context.Context, data map[string]string) error {
+		return stderrors.New("storage unavailable")
+	})
+	ctx := context.Background()
+
+	saga, err := coordinator.Begin(ctx, "test", nil)
+	require.NoError(t, err)
+	require.NoError(t, saga.Record(ctx, "record", nil))
+	require.NoError(t, saga.Record(ctx, "file", nil))
+
+	assert.Error(t, saga.Abort(ctx, stderrors.New("submit failed")))
+	assert.Empty(t, undone, "earlier steps wait until the failed one is undone")
+	assert.Equal(t, []string{"record", "file"}, saga.Steps)
+	assert.Equal(t, 1, saga.Attempts)
+}
+
+func TestSagaRecordRequiresCompensation(t *testing.T) {
+	coordinator := NewSagaCoordinator(nil, setupTestLogger(t))
+	saga, err := coordinator.Begin(context.Background(), "test", nil)
+	require.NoError(t, err)
+
+	assert.Error(t, saga.Record(context.Background(), "unknown", nil))
+}
+
+func TestDocumentServiceRegistersUploadCompensations(t *testing.T) {
+	service := NewDocumentService(nil, nil, setupTestLogger(t))
+
+	for _, step := range []string{sagaStepDocumentRecord, sagaStepStoredFile, sagaStepProcessingFile} {
+		assert.Contains(t, service.sagas.compensations, step)
+	}
+}
@@ -0,0 +1,332 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// Scanner checks files for malware
+type Scanner interface {
+	// Name identifies the scanner in scan results and audit events
+	Name() string
+	// Scan returns the scanner's verdict on a file
+	Scan(ctx context.Context, data []byte) (*models.ScanResult, error)
+}
+
+// QuarantineStorage keeps infected files in a bucket of their own, out of
+// the tenants' buckets
+type QuarantineStorage interface {
+	UploadFileToBucket(ctx context.Context, bucket, key string, data []byte, contentType string, metadata map[string]string) error
+}
+
+// clamdChunkBytes is the size of the chunks a file is streamed to clamd in
+const clamdChunkBytes = 64 * 1024
+
+// clamdScanner scans files with a ClamAV daemon's INSTREAM command
+type clamdScanner struct {
+	address string
+}
+
+func (s *clamdScanner) Name() string { return "clamav" }
+
+func (s *clamdScanner) Scan(ctx context.Context, data []byte) (*models.ScanResult, error) {
+	conn, err := dialScanner(ctx, s.address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// The file is sent as chunks each prefixed with its length, ended by
+	// an empty chunk
+	writer := bufio.NewWriter(conn)
+	if _, err := writer.WriteString("zINSTREAM\x00"); err != nil {
+		return nil, fmt.Errorf("failed to send to clamd: %w", err)
+	}
+	var size [4]byte
+	for start := 0; start < len(data); start += clamdChunkBytes {
+		chunk := data[start:min(start+clamdChunkBytes, len(data))]
+		binary.BigEndian.PutUint32(size[:], uint32(len(chunk)))
+		writer.Write(size[:])
+		writer.Write(chunk)
+	}
+	binary.BigEndian.PutUint32(size[:], 0)
+	writer.Write(size[:])
+	if err := writer.Flush(); err != nil {
+		return nil, fmt.Errorf("failed to send to clamd: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && reply == "" {
+		return nil, fmt.Errorf("failed to read clamd reply: %w", err)
+	}
+	return parseClamdReply(strings.TrimRight(reply, "\x00\n"))
+}
+
+// parseClamdReply reads clamd's reply to INSTREAM: "stream: OK" for clean
+// files, "stream: <signature> FOUND" for infected ones and
+// "<message> ERROR" when the file could not be scanned
+func parseClamdReply(reply string) (*models.ScanResult, error) {
+	result := &models.ScanResult{Scanner: "clamav"}
+	switch {
+	case strings.HasSuffix(reply, " FOUND"):
+		signature := strings.TrimSuffix(reply, " FOUND")
+		if i := strings.Index(signature, ": "); i >= 0 {
+			signature = signature[i+2:]
+		}
+		result.Infected = true
+		result.Threats = []string{signature}
+		return result, nil
+	case strings.HasSuffix(reply, ": OK"):
+		return result, nil
+	default:
+		return nil, fmt.Errorf("clamd could not scan the file: %s", reply)
+	}
+}
+
+// icapScanner scans files with an ICAP server's RESPMOD method (RFC 3507),
+// as an HTTP response to be adapted
+type icapScanner struct {
+	address string
+	service string
+}
+
+func (s *icapScanner) Name() string { return "icap" }
+
+func (s *icapScanner) Scan(ctx context.Context, data []byte) (*models.ScanResult, error) {
+	conn, err := dialScanner(ctx, s.address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	response := "HTTP/1.1 200 OK\r\n" +
+		"Content-Type: application/octet-stream\r\n" +
+		"Content-Length: " + strconv.Itoa(len(data)) + "\r\n\r\n"
+	writer := bufio.NewWriter(conn)
+	fmt.Fprintf(writer, "RESPMOD icap://%s/%s ICAP/1.0\r\n", s.address, strings.TrimPrefix(s.service, "/"))
+	fmt.Fprintf(writer, "Host: %s\r\n", s.address)
+	writer.WriteString("Allow: 204\r\n")
+	fmt.Fprintf(writer, "Encapsulated: res-hdr=0, res-body=%d\r\n\r\n", len(response))
+	writer.WriteString(response)
+	if len(data) > 0 {
+		fmt.Fprintf(writer, "%x\r\n", len(data))
+		writer.Write(data)
+		writer.WriteString("\r\n")
+	}
+	writer.WriteString("0\r\n\r\n")
+	if err := writer.Flush(); err != nil {
+		return nil, fmt.Errorf("failed to send to ICAP server: %w", err)
+	}
+
+	reader := textproto.NewReader(bufio.NewReader(conn))
+	status, err := reader.ReadLine()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ICAP reply: %w", err)
+	}
+	headers, err := reader.ReadMIMEHeader()
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read ICAP reply: %w", err)
+	}
+	return parseICAPReply(status, headers)
+}
+
+// parseICAPReply reads an ICAP server's reply to RESPMOD. 204 means the
+// file is clean; a 200 adapting it names the malware found in
+// X-Infection-Found, X-Violations-Found or X-Virus-ID.
+func parseICAPReply(status string, headers textproto.MIMEHeader) (*models.ScanResult, error) {
+	fields := strings.Fields(status)
+	if len(fields) < 2 || !strings.HasPrefix(fields[0], "ICAP/") {
+		return nil, fmt.Errorf("malformed ICAP reply: %q", status)
+	}
+	result := &models.ScanResult{Scanner: "icap"}
+	switch fields[1] {
+	case "204":
+		return result, nil
+	case "200":
+	default:
+		return nil, fmt.Errorf("ICAP server could not scan the file: %s", status)
+	}
+
+	if found := headers.Get("X-Infection-Found"); found != "" {
+		// Type=0; Resolution=2; Threat=<name>;
+		threat := found
+		for _, field := range strings.Split(found, ";") {
+			if name, ok := strings.CutPrefix(strings.TrimSpace(field), "Threat="); ok {
+				threat = name
+			}
+		}
+		result.Threats = append(result.Threats, threat)
+	}
+	if virus := headers.Get("X-Virus-ID"); virus != "" && len(result.Threats) == 0 {
+		result.Threats = append(result.Threats, virus)
+	}
+	if violations := headers.Get("X-Violations-Found"); violations != "" && len(result.Threats) == 0 {
+		result.Threats = append(result.Threats, "policy violation")
+	}
+	result.Infected = len(result.Threats) > 0
+	return result, nil
+}
+
+// dialScanner connects to a scanner, bounding the whole exchange by ctx's
+// deadline
+func dialScanner(ctx context.Context, address string) (net.Conn, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to scanner at %s: %w", address, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	return conn, nil
+}
+
+// ScannerService scans the files uploaded to spaces that turn scanning on
+// before they are stored. Infected files are rejected, copied to the
+// quarantine bucket and recorded in the audit log.
+type ScannerService struct {
+	scanner    Scanner
+	quarantine QuarantineStorage
+	bucket     string
+	audit      *AuditService
+	logger     *logger.Logger
+
+	scanByDefault bool
+	failOpen      bool
+	timeout       time.Duration
+}
+
+// NewScannerService creates a scanner service with the configured scanner
+func NewScannerService(cfg config.ScannerConfig, quarantine QuarantineStorage, audit *AuditService, log *logger.Logger) (*ScannerService, error) {
+	var scanner Scanner
+	switch cfg.Provider {
+	case "clamav":
+		scanner = &clamdScanner{address: cfg.Address}
+	case "icap":
+		scanner = &icapScanner{address: cfg.Address, service: cfg.ICAPService}
+	default:
+		return nil, fmt.Errorf("unknown scanner provider %q", cfg.Provider)
+	}
+	return &ScannerService{
+		scanner:       scanner,
+		quarantine:    quarantine,
+		bucket:        cfg.QuarantineBucket,
+		audit:         audit,
+		logger:        log.WithService("scanner_service"),
+		scanByDefault: cfg.ScanByDefault,
+		failOpen:      cfg.FailOpen,
+		timeout:       time.Duration(cfg.TimeoutSeconds) * time.Second,
+	}, nil
+}
+
+// Enabled reports whether the uploads of a space with these settings are
+// scanned. A nil service scans nothing.
+func (s *ScannerService) Enabled(settings map[string]interface{}) bool {
+	if s == nil {
+		return false
+	}
+	if enabled := models.SpaceScanSettings(settings).Enabled; enabled != nil {
+		return *enabled
+	}
+	return s.scanByDefault
+}
+
+// CheckUpload scans a file uploaded to a space by actorID. An infected
+// file is quarantined and rejected with AETHER-SCAN-001. When the scanner
+// fails the file is rejected with AETHER-SCAN-002, or let through when the
+// service fails open.
+func (s *ScannerService) CheckUpload(ctx context.Context, spaceCtx *models.SpaceContext, actorID string, file models.FileInfo, data []byte) error {
+	scanCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	result, err := s.scanner.Scan(scanCtx, data)
+	cancel()
+	if err != nil {
+		if s.failOpen {
+			s.logger.Warn("Malware scan failed, storing the file unscanned",
+				zap.String("space_id", spaceCtx.SpaceID),
+				zap.String("file_name", file.OriginalName),
+				zap.Error(err))
+			return nil
+		}
+		s.logger.Error("Malware scan failed, rejecting the file",
+			zap.String("space_id", spaceCtx.SpaceID),
+			zap.String("file_name", file.OriginalName),
+			zap.Error(err))
+		return errors.ServiceUnavailable("The file could not be scanned for malware; try again later").WithErrorCode(errors.CodeScannerUnavailable)
+	}
+	if !result.Infected {
+		return nil
+	}
+
+	quarantineID := uuid.New().String()
+	checksum := sha256.Sum256(data)
+	name := path.Base("/" + file.OriginalName)
+	if name == "/" {
+		name = "file"
+	}
+	key := fmt.Sprintf("%s/%s/%s", spaceCtx.TenantID, quarantineID, name)
+	if err := s.quarantine.UploadFileToBucket(ctx, s.bucket, key, data, "application/octet-stream", map[string]string{
+		"tenant-id": spaceCtx.TenantID,
+		"space-id":  spaceCtx.SpaceID,
+		"scanner":   result.Scanner,
+	}); err != nil {
+		// The file is rejected regardless; the audit event records that
+		// it was not kept
+		s.logger.Error("Failed to quarantine infected file",
+			zap.String("quarantine_id", quarantineID),
+			zap.String("space_id", spaceCtx.SpaceID),
+			zap.Error(err))
+		key = ""
+	}
+
+	s.logger.FromContext(ctx).Warn("Infected upload rejected",
+		zap.Bool("audit", true),
+		zap.String("quarantine_id", quarantineID),
+		zap.String("space_id", spaceCtx.SpaceID),
+		zap.String("file_name", file.OriginalName),
+		zap.Strings("threats", result.Threats),
+		zap.String("actor_id", actorID),
+	)
+	recordAuditEvent(ctx, s.audit, s.logger, &models.AuditEvent{
+		Action:       "upload.quarantine",
+		ResourceType: "quarantined_file",
+		ResourceID:   quarantineID,
+		SpaceID:      spaceCtx.SpaceID,
+		TenantID:     spaceCtx.TenantID,
+		ActorID:      actorID,
+		Details: map[string]interface{}{
+			"file_name":         file.OriginalName,
+			"mime_type":         file.MimeType,
+			"size_bytes":        len(data),
+			"sha256":            hex.EncodeToString(checksum[:]),
+			"scanner":           result.Scanner,
+			"threats":           result.Threats,
+			"quarantine_bucket": s.bucket,
+			"quarantine_key":    key,
+		},
+	})
+
+	return errors.NewAPIError(errors.ErrUnprocessableEntity, "The file contains malware and was rejected", map[string]interface{}{
+		"file_name":     file.OriginalName,
+		"threats":       result.Threats,
+		"quarantine_id": quarantineID,
+	}).WithErrorCode(errors.CodeFileInfected)
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// eicar is the EICAR antivirus test file
+const eicar = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// serveOnce accepts one connection on a local port and answers it with
+// handle, returning the address and the bytes the client sent
+func serveOnce(t *testing.T, handle func(conn net.Conn) []byte) (string, <-chan []byte) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		received <- handle(conn)
+	}()
+	return listener.Addr().String(), received
+}
+
+// fakeClamd reads an INSTREAM request and replies as clamd does, finding
+// the EICAR file
+func fakeClamd(conn net.Conn) []byte {
+	reader := bufio.NewReader(conn)
+	if command, err := reader.ReadString(0); err != nil || command != "zINSTREAM\x00" {
+		return nil
+	}
+	var data bytes.Buffer
+	for {
+		var size uint32
+		if err := binary.Read(reader, binary.BigEndian, &size); err != nil {
+			return nil
+		}
+		if size == 0 {
+			break
+		}
+		if _, err := io.CopyN(&data, reader, int64(size)); err != nil {
+			return nil
+		}
+	}
+	if strings.Contains(data.String(), "EICAR-STANDARD-ANTIVIRUS-TEST-FILE") {
+		conn.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+	} else {
+		conn.Write([]byte("stream: OK\x00"))
+	}
+	return data.Bytes()
+}
+
+func TestClamdScanner(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Larger than a chunk, so the file is streamed in several
+	clean := bytes.Repeat([]byte("quarterly report "), clamdChunkBytes/8)
+	address, received := serveOnce(t, fakeClamd)
+	result, err := (&clamdScanner{address: address}).Scan(ctx, clean)
+	require.NoError(t, err)
+	assert.False(t, result.Infected)
+	assert.Equal(t, clean, <-received)
+
+	address, _ = serveOnce(t, fakeClamd)
+	result, err = (&clamdScanner{address: address}).Scan(ctx, []byte(eicar))
+	require.NoError(t, err)
+	assert.True(t, result.Infected)
+	assert.Equal(t, []string{"Eicar-Test-Signature"}, result.Threats)
+}
+
+func TestParseClamdReply(t *testing.T) {
+	_, err := parseClamdReply("INSTREAM size limit exceeded. ERROR")
+	assert.Error(t, err)
+
+	result, err := parseClamdReply("stream: OK")
+	require.NoError(t, err)
+	assert.False(t, result.Infected)
+}
+
+func TestICAPScanner(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	reply := func(status, headers string) func(net.Conn) []byte {
+		return func(conn net.Conn) []byte {
+			// Read the whole request, up to the last chunk, before replying
+			reader := bufio.NewReader(conn)
+			var request bytes.Buffer
+			for !bytes.HasSuffix(request.Bytes(), []byte("\r\n0\r\n\r\n")) {
+				line, err := reader.ReadBytes('\n')
+				request.Write(line)
+				if err != nil {
+					return nil
+				}
+			}
+			fmt.Fprintf(conn, "ICAP/1.0 %s\r\nISTag: \"test\"\r\n%s\r\n", status, headers)
+			return request.Bytes()
+		}
+	}
+
+	address, received := serveOnce(t, reply("204 No Content", ""))
+	result, err := (&icapScanner{address: address, service: "avscan"}).Scan(ctx, []byte("hello"))
+	require.NoError(t, err)
+	assert.False(t, result.Infected)
+	request := string(<-received)
+	assert.True(t, strings.HasPrefix(request, "RESPMOD icap://"+address+"/avscan ICAP/1.0\r\n"), request)
+	assert.Contains(t, request, "\r\n5\r\nhello\r\n0\r\n\r\n")
+
+	address, _ = serveOnce(t, reply("200 OK", "X-Infection-Found: Type=0; Resolution=2; Threat=Eicar-Test-Signature;\r\n"))
+	result, err = (&icapScanner{address: address, service: "avscan"}).Scan(ctx, []byte(eicar))
+	require.NoError(t, err)
+	assert.True(t, result.Infected)
+	assert.Equal(t, []string{"Eicar-Test-Signature"}, result.Threats)
+
+	address, _ = serveOnce(t, reply("500 Server Error", ""))
+	_, err = (&icapScanner{address: address, service: "avscan"}).Scan(ctx, []byte("hello"))
+	assert.Error(t, err)
+}
+
+// fakeScanner returns a fixed verdict
+type fakeScanner struct {
+	result *models.ScanResult
+	err    error
+}
+
+func (s *fakeScanner) Name() string { return "fake" }
+
+func (s *fakeScanner) Scan(context.Context, []byte) (*models.ScanResult, error) {
+	return s.result, s.err
+}
+
+// fakeQuarantine records the files it is given
+type fakeQuarantine struct {
+	bucket, key string
+	data        []byte
+}
+
+func (q *fakeQuarantine) UploadFileToBucket(_ context.Context, bucket, key string, data []byte, _ string, _ map[string]string) error {
+	q.bucket, q.key, q.data = bucket, key, data
+	return nil
+}
+
+func TestScannerServiceCheckUpload(t *testing.T) {
+	ctx := context.Background()
+	spaceCtx := &models.SpaceContext{SpaceID: "space-1", TenantID: "tenant-1"}
+	file := models.FileInfo{OriginalName: "invoice.pdf", MimeType: "application/pdf"}
+	quarantine := &fakeQuarantine{}
+	service := &ScannerService{
+		scanner:    &fakeScanner{result: &models.ScanResult{Scanner: "fake"}},
+		quarantine: quarantine,
+		bucket:     "aether-quarantine",
+		logger:     setupTestLogger(t),
+		timeout:    time.Second,
+	}
+
+	require.NoError(t, service.CheckUpload(ctx, spaceCtx, "user-1", file, []byte("clean")))
+	assert.Nil(t, quarantine.data, "clean files are not quarantined")
+
+	service.scanner = &fakeScanner{result: &models.ScanResult{Scanner: "fake", Infected: true, Threats: []string{"Eicar-Test-Signature"}}}
+	err := service.CheckUpload(ctx, spaceCtx, "user-1", file, []byte(eicar))
+	apiErr, ok := errors.AsAPIError(err)
+	require.True(t, ok)
+	assert.Equal(t, errors.CodeFileInfected, apiErr.ErrorCode)
+	assert.Equal(t, []string{"Eicar-Test-Signature"}, apiErr.Details["threats"])
+	assert.Equal(t, "aether-quarantine", quarantine.bucket)
+	assert.Equal(t, "tenant-1/"+apiErr.Details["quarantine_id"].(string)+"/invoice.pdf", quarantine.key)
+	assert.Equal(t, []byte(eicar), quarantine.data)
+
+	// Scanner failures reject the file unless the service fails open
+	service.scanner = &fakeScanner{err: fmt.Errorf("connection refused")}
+	apiErr, ok = errors.AsAPIError(service.CheckUpload(ctx, spaceCtx, "user-1", file, []byte("data")))
+	require.True(t, ok)
+	assert.Equal(t, errors.CodeScannerUnavailable, apiErr.ErrorCode)
+
+	service.failOpen = true
+	assert.NoError(t, service.CheckUpload(ctx, spaceCtx, "user-1", file, []byte("data")))
+}
+
+func TestScannerServiceEnabled(t *testing.T) {
+	var none *ScannerService
+	assert.False(t, none.Enabled(nil))
+
+	service := &ScannerService{scanByDefault: true}
+	assert.True(t, service.Enabled(nil), "spaces that have not chosen follow the default")
+	assert.False(t, service.Enabled(map[string]interface{}{
+		models.ScanSettingsKey: map[string]interface{}{"enabled": false},
+	}))
+
+	service.scanByDefault = false
+	assert.True(t, service.Enabled(map[string]interface{}{
+		models.ScanSettingsKey: map[string]interface{}{"enabled": true},
+	}))
+	assert.False(t, service.Enabled(map[string]interface{}{
+		models.ScanSettingsKey: map[string]interface{}{"enabled": nil},
+	}))
+}
@@ -0,0 +1,513 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/cron"
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// JobFunc is the work of a scheduled job
+type JobFunc func(ctx context.Context) error
+
+// Scheduler runs background jobs (retry polling, reconciliation, purges,
+// rollups) on cron schedules. Jobs run only on the elected leader. Each
+// job's next run time is persisted, so a run missed while no replica led
+// is caught up, and a run is claimed before it starts so a leadership
+// handover does not run it twice. Every run is recorded in a bounded
+// history.
+type Scheduler struct {
+	neo4j        *database.Neo4jClient
+	elector      *LeaderElector
+	instanceID   string
+	historyLimit int
+	logger       *logger.Logger
+
+	mu      sync.Mutex
+	jobs    []*scheduledJob
+	started bool
+}
+
+// scheduledJob is a registered job and its in-memory state
+type scheduledJob struct {
+	name     string
+	spec     string
+	schedule cron.Schedule
+	fn       JobFunc
+	running  atomic.Bool
+	next     atomic.Pointer[time.Time]
+}
+
+// NewScheduler creates a scheduler. neo4j may be nil, in which case
+// schedules and run history are kept in memory only.
+func NewScheduler(neo4j *database.Neo4jClient, elector *LeaderElector, instanceID string, historyLimit int, log *logger.Logger) *Scheduler {
+	return &Scheduler{
+		neo4j:        neo4j,
+		elector:      elector,
+		instanceID:   instanceID,
+		historyLimit: historyLimit,
+		logger:       log.WithService("scheduler"),
+	}
+}
+
+// Register adds a job. spec is a cron expression (see cron.Parse). Jobs
+// must be registered before Run.
+func (s *Scheduler) Register(name, spec string, fn JobFunc) error {
+	schedule, err := cron.Parse(spec)
+	if err != nil {
+		return fmt.Errorf("invalid schedule for job %s: %w", name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return fmt.Errorf("cannot register job %s: scheduler already running", name)
+	}
+	for _, job := range s.jobs {
+		if job.name == name {
+			return fmt.Errorf("job %s is already registered", name)
+		}
+	}
+
+	s.jobs = append(s.jobs, &scheduledJob{
+		name:     name,
+		spec:     spec,
+		schedule: schedule,
+		fn:       fn,
+	})
+	return nil
+}
+
+// Run schedules every registered job until ctx is done. A run in progress
+// is allowed to finish.
+func (s *Scheduler) Run(ctx context.Context) {
+	s.mu.Lock()
+	s.started = true
+	jobs := s.jobs
+	s.mu.Unlock()
+
+	s.logger.Info("Starting job scheduler", zap.Int("jobs", len(jobs)))
+
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		wg.Add(1)
+		go func(job *scheduledJob) {
+			defer wg.Done()
+			s.runLoop(ctx, job)
+		}(job)
+	}
+	wg.Wait()
+}
+
+// runLoop waits for each activation of job and runs it if this replica
+// leads and wins the claim
+func (s *Scheduler) runLoop(ctx context.Context, job *scheduledJob) {
+	next := s.syncJob(ctx, job)
+
+	for {
+		if next.IsZero() {
+			s.logger.Error("Job schedule has no future activation", zap.String("job", job.name), zap.String("schedule", job.spec))
+			return
+		}
+		job.next.Store(&next)
+
+		if !sleepContext(ctx, time.Until(next)) {
+			return
+		}
+
+		now := time.Now().UTC()
+		following := job.schedule.Next(now)
+		if !s.elector.IsLeader() {
+			next = following
+			continue
+		}
+
+		claimed, persistedNext, err := s.claim(ctx, job, now, following)
+		if err != nil {
+			if ctx.Err() == nil {
+				s.logger.Error("Failed to claim scheduled job", zap.String("job", job.name), zap.Error(err))
+			}
+			next = following
+			continue
+		}
+		if !claimed {
+			// Another leader already ran this activation
+			next = persistedNext
+			continue
+		}
+
+		s.execute(ctx, job)
+		next = following
+	}
+}
+
+// syncJob persists the job definition and returns when it should run
+// next. A persisted next run time in the past means a run was missed and
+// is returned so the job catches up once.
+func (s *Scheduler) syncJob(ctx context.Context, job *scheduledJob) time.Time {
+	now := time.Now().UTC()
+	next := job.schedule.Next(now)
+	if s.neo4j == nil {
+		return next
+	}
+
+	// A changed schedule discards the next run time computed from the old one
+	query := `
+		MERGE (j:ScheduledJob {name: $name})
+		ON CREATE SET j.created_at = $now, j.next_run_at = $next, j.schedule = $schedule
+		WITH j, j.schedule <> $schedule AS changed
+		SET j.schedule = $schedule,
+		    j.next_run_at = CASE WHEN changed THEN $next ELSE j.next_run_at END,
+		    j.updated_at = $now
+		RETURN j.next_run_at AS next_run_at
+	`
+	result, err := s.neo4j.ExecuteQuery(ctx, query, map[string]interface{}{
+		"name":     job.name,
+		"schedule": job.spec,
+		"now":      now,
+		"next":     next,
+	})
+	if err != nil {
+		s.logger.Warn("Failed to persist scheduled job, using in-memory schedule", zap.String("job", job.name), zap.Error(err))
+		return next
+	}
+
+	if len(result.Records) > 0 {
+		if value, ok := result.Records[0].Get("next_run_at"); ok {
+			if persisted, ok := value.(time.Time); ok {
+				if persisted.Before(now) {
+					s.logger.Info("Catching up missed job run", zap.String("job", job.name), zap.Time("missed_at", persisted))
+					return now
+				}
+				return persisted.UTC()
+			}
+		}
+	}
+	return next
+}
+
+// claim marks the activation due at now as taken by this replica and moves
+// the persisted next run time to following. It reports false, with the
+// persisted next run time, when the activation was already taken.
+func (s *Scheduler) claim(ctx context.Context, job *scheduledJob, now, following time.Time) (bool, time.Time, error) {
+	if s.neo4j == nil {
+		return true, following, nil
+	}
+
+	// The lock write serializes concurrent claims; the due check reads the
+	// committed next run time after the lock is held
+	query := `
+		MATCH (j:ScheduledJob {name: $name})
+		SET j._claim_lock = true
+		REMOVE j._claim_lock
+		WITH j, j.next_run_at <= $now AS due
+		FOREACH (_ IN CASE WHEN due THEN [1] ELSE [] END |
+			SET j.next_run_at = $next, j.last_run_at = $now, j.last_instance_id = $instance_id
+		)
+		RETURN due, j.next_run_at AS next_run_at
+	`
+	result, err := s.neo4j.ExecuteQuery(ctx, query, map[string]interface{}{
+		"name":        job.name,
+		"now":         now,
+		"next":        following,
+		"instance_id": s.instanceID,
+	})
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	if len(result.Records) == 0 {
+		// The definition is missing, e.g. deleted by hand; recreate it
+		s.syncJob(ctx, job)
+		return true, following, nil
+	}
+
+	record := result.Records[0]
+	dueValue, _ := record.Get("due")
+	nextValue, _ := record.Get("next_run_at")
+	due, _ := dueValue.(bool)
+	persistedNext, ok := nextValue.(time.Time)
+	if !ok {
+		persistedNext = following
+	}
+	return due, persistedNext.UTC(), nil
+}
+
+// execute runs job once and records the run
+func (s *Scheduler) execute(ctx context.Context, job *scheduledJob) {
+	job.running.Store(true)
+	defer job.running.Store(false)
+
+	run := &models.JobRun{
+		ID:         uuid.New().String(),
+		JobName:    job.name,
+		InstanceID: s.instanceID,
+		Status:     models.JobRunStatusRunning,
+		StartedAt:  time.Now().UTC(),
+	}
+	s.recordRunStart(ctx, run)
+
+	err := job.fn(ctx)
+
+	finished := time.Now().UTC()
+	run.FinishedAt = &finished
+	run.DurationMs = finished.Sub(run.StartedAt).Milliseconds()
+	run.Status = models.JobRunStatusSucceeded
+	if err != nil {
+		run.Status = models.JobRunStatusFailed
+		run.Error = err.Error()
+		if ctx.Err() == nil {
+			s.logger.Error("Scheduled job failed", zap.String("job", job.name), zap.Int64("duration_ms", run.DurationMs), zap.Error(err))
+		}
+	} else {
+		s.logger.Debug("Scheduled job completed", zap.String("job", job.name), zap.Int64("duration_ms", run.DurationMs))
+	}
+
+	// Record the outcome even when shutdown interrupted the run
+	s.recordRunEnd(context.WithoutCancel(ctx), run)
+}
+
+// recordRunStart persists a running run. Runs still marked running from an
+// earlier leader never finished, so they are marked failed.
+func (s *Scheduler) recordRunStart(ctx context.Context, run *models.JobRun) {
+	if s.neo4j == nil {
+		return
+	}
+
+	query := `
+		MATCH (j:ScheduledJob {name: $job_name})
+		OPTIONAL MATCH (j)-[:HAS_RUN]->(stale:ScheduledJobRun {status: 'running'})
+		SET stale.status = 'failed', stale.error = 'run did not finish'
+		WITH DISTINCT j
+		CREATE (j)-[:HAS_RUN]->(r:ScheduledJobRun {
+			id: $id,
+			job_name: $job_name,
+			instance_id: $instance_id,
+			status: $status,
+			started_at: $started_at
+		})
+		SET j.last_status = $status
+	`
+	params := map[string]interface{}{
+		"id":          run.ID,
+		"job_name":    run.JobName,
+		"instance_id": run.InstanceID,
+		"status":      run.Status,
+		"started_at":  run.StartedAt,
+	}
+	if _, err := s.neo4j.ExecuteQuery(ctx, query, params); err != nil {
+		s.logger.Warn("Failed to record job run start", zap.String("job", run.JobName), zap.Error(err))
+	}
+}
+
+// recordRunEnd persists the outcome of run and trims the job's history.
+// The job has already run, so failures are logged, not returned.
+func (s *Scheduler) recordRunEnd(ctx context.Context, run *models.JobRun) {
+	if s.neo4j == nil {
+		return
+	}
+
+	query := `
+		MATCH (j:ScheduledJob {name: $job_name})-[:HAS_RUN]->(r:ScheduledJobRun {id: $id})
+		SET r.status = $status,
+		    r.error = $error,
+		    r.finished_at = $finished_at,
+		    r.duration_ms = $duration_ms,
+		    j.last_status = $status,
+		    j.last_error = $error
+	`
+	params := map[string]interface{}{
+		"id":          run.ID,
+		"job_name":    run.JobName,
+		"status":      run.Status,
+		"error":       run.Error,
+		"finished_at": *run.FinishedAt,
+		"duration_ms": run.DurationMs,
+	}
+	if _, err := s.neo4j.ExecuteQuery(ctx, query, params); err != nil {
+		s.logger.Warn("Failed to record job run", zap.String("job", run.JobName), zap.Error(err))
+		return
+	}
+
+	trimQuery := `
+		MATCH (:ScheduledJob {name: $job_name})-[:HAS_RUN]->(r:ScheduledJobRun)
+		WITH r ORDER BY r.started_at DESC
+		SKIP $limit
+		DETACH DELETE r
+	`
+	if _, err := s.neo4j.ExecuteQuery(ctx, trimQuery, map[string]interface{}{
+		"job_name": run.JobName,
+		"limit":    s.historyLimit,
+	}); err != nil {
+		s.logger.Warn("Failed to trim job run history", zap.String("job", run.JobName), zap.Error(err))
+	}
+}
+
+// Jobs lists the registered jobs with their persisted state
+func (s *Scheduler) Jobs(ctx context.Context) ([]*models.ScheduledJob, error) {
+	s.mu.Lock()
+	registered := s.jobs
+	s.mu.Unlock()
+
+	jobs := make([]*models.ScheduledJob, 0, len(registered))
+	byName := make(map[string]*models.ScheduledJob, len(registered))
+	names := make([]string, 0, len(registered))
+	for _, job := range registered {
+		info := &models.ScheduledJob{
+			Name:     job.name,
+			Schedule: job.spec,
+			Running:  job.running.Load(),
+		}
+		if next := job.next.Load(); next != nil {
+			nextRun := *next
+			info.NextRunAt = &nextRun
+		}
+		jobs = append(jobs, info)
+		byName[job.name] = info
+		names = append(names, job.name)
+	}
+
+	if s.neo4j == nil || len(names) == 0 {
+		return jobs, nil
+	}
+
+	query := `
+		MATCH (j:ScheduledJob)
+		WHERE j.name IN $names
+		RETURN j.name AS name, j.next_run_at AS next_run_at, j.last_run_at AS last_run_at,
+		       j.last_status AS last_status, j.last_error AS last_error
+	`
+	result, err := s.neo4j.ExecuteQuery(ctx, query, map[string]interface{}{"names": names})
+	if err != nil {
+		return nil, errors.Database("Failed to get scheduled jobs", err)
+	}
+
+	for _, record := range result.Records {
+		name, _ := record.Get("name")
+		info, ok := byName[fmt.Sprint(name)]
+		if !ok {
+			continue
+		}
+		// The persisted next run time is authoritative across replicas
+		if value, _ := record.Get("next_run_at"); value != nil {
+			if t, ok := value.(time.Time); ok {
+				info.NextRunAt = &t
+			}
+		}
+		if value, _ := record.Get("last_run_at"); value != nil {
+			if t, ok := value.(time.Time); ok {
+				info.LastRunAt = &t
+			}
+		}
+		if value, _ := record.Get("last_status"); value != nil {
+			info.LastStatus, _ = value.(string)
+		}
+		if value, _ := record.Get("last_error"); value != nil {
+			info.LastError, _ = value.(string)
+		}
+	}
+	return jobs, nil
+}
+
+// JobRuns returns up to limit recent runs of a job, newest first
+func (s *Scheduler) JobRuns(ctx context.Context, name string, limit int) ([]*models.JobRun, error) {
+	if !s.registered(name) {
+		return nil, errors.NotFound("Scheduled job not found").WithErrorCode(errors.CodeScheduledJobNotFound)
+	}
+	if s.neo4j == nil {
+		return []*models.JobRun{}, nil
+	}
+
+	query := `
+		MATCH (:ScheduledJob {name: $name})-[:HAS_RUN]->(r:ScheduledJobRun)
+		RETURN r.id AS id, r.job_name AS job_name, r.instance_id AS instance_id,
+		       r.status AS status, r.error AS error, r.started_at AS started_at,
+		       r.finished_at AS finished_at, r.duration_ms AS duration_ms
+		ORDER BY r.started_at DESC
+		LIMIT $limit
+	`
+	result, err := s.neo4j.ExecuteQuery(ctx, query, map[string]interface{}{
+		"name":  name,
+		"limit": limit,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to get job runs", err)
+	}
+
+	runs := make([]*models.JobRun, 0, len(result.Records))
+	for _, record := range result.Records {
+		run := &models.JobRun{}
+		if value, _ := record.Get("id"); value != nil {
+			run.ID, _ = value.(string)
+		}
+		if value, _ := record.Get("job_name"); value != nil {
+			run.JobName, _ = value.(string)
+		}
+		if value, _ := record.Get("instance_id"); value != nil {
+			run.InstanceID, _ = value.(string)
+		}
+		if value, _ := record.Get("status"); value != nil {
+			run.Status, _ = value.(string)
+		}
+		if value, _ := record.Get("error"); value != nil {
+			run.Error, _ = value.(string)
+		}
+		if value, _ := record.Get("started_at"); value != nil {
+			run.StartedAt, _ = value.(time.Time)
+		}
+		if value, _ := record.Get("finished_at"); value != nil {
+			if t, ok := value.(time.Time); ok {
+				run.FinishedAt = &t
+			}
+		}
+		if value, _ := record.Get("duration_ms"); value != nil {
+			run.DurationMs, _ = value.(int64)
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+// Upcoming returns up to max run times of a job after from and before
+// until, computed from its schedule
+func (s *Scheduler) Upcoming(name string, from, until time.Time, max int) []time.Time {
+	s.mu.Lock()
+	var schedule cron.Schedule
+	for _, job := range s.jobs {
+		if job.name == name {
+			schedule = job.schedule
+		}
+	}
+	s.mu.Unlock()
+
+	var runs []time.Time
+	if schedule == nil {
+		return runs
+	}
+	for next := schedule.Next(from); !next.IsZero() && next.Before(until) && len(runs) < max; next = schedule.Next(next) {
+		runs = append(runs, next)
+	}
+	return runs
+}
+
+func (s *Scheduler) registered(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, job := range s.jobs {
+		if job.name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/cron"
+)
+
+// intervalSchedule fires every interval; cron schedules are too coarse for tests
+type intervalSchedule time.Duration
+
+func (s intervalSchedule) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(s))
+}
+
+var _ cron.Schedule = intervalSchedule(0)
+
+func TestSchedulerRegister(t *testing.T) {
+	log := setupTestLogger(t)
+	elector := NewLeaderElector(NewLocalLockStore(), LeaderLockKey, "replica-1", time.Minute, log)
+	scheduler := NewScheduler(nil, elector, "replica-1", 10, log)
+	noop := func(context.Context) error { return nil }
+
+	require.NoError(t, scheduler.Register("cleanup", "0 3 * * *", noop))
+	assert.ErrorContains(t, scheduler.Register("cleanup", "@hourly", noop), "already registered")
+	assert.ErrorContains(t, scheduler.Register("rollup", "every hour", noop), "invalid schedule")
+
+	jobs, err := scheduler.Jobs(context.Background())
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "cleanup", jobs[0].Name)
+	assert.Equal(t, "0 3 * * *", jobs[0].Schedule)
+
+	_, err = scheduler.JobRuns(context.Background(), "missing", 10)
+	assert.Error(t, err)
+}
+
+func TestSchedulerRunsOnlyOnLeader(t *testing.T) {
+	locks := NewLocalLockStore()
+	log := setupTestLogger(t)
+	leader := NewLeaderElector(locks, LeaderLockKey, "replica-1", time.Minute, log)
+	follower := NewLeaderElector(locks, LeaderLockKey, "replica-2", time.Minute, log)
+	leader.campaign(context.Background())
+	follower.campaign(context.Background())
+
+	var leaderRuns, followerRuns atomic.Int32
+	leaderScheduler := NewScheduler(nil, leader, "replica-1", 10, log)
+	followerScheduler := NewScheduler(nil, follower, "replica-2", 10, log)
+	leaderScheduler.jobs = append(leaderScheduler.jobs, &scheduledJob{
+		name:     "test",
+		schedule: intervalSchedule(5 * time.Millisecond),
+		fn: func(context.Context) error {
+			leaderRuns.Add(1)
+			return nil
+		},
+	})
+	followerScheduler.jobs = append(followerScheduler.jobs, &scheduledJob{
+		name:     "test",
+		schedule: intervalSchedule(5 * time.Millisecond),
+		fn: func(context.Context) error {
+			followerRuns.Add(1)
+			return nil
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	go followerScheduler.Run(ctx)
+	leaderScheduler.Run(ctx)
+
+	assert.Positive(t, leaderRuns.Load())
+	assert.Zero(t, followerRuns.Load())
+
+	jobs, err := leaderScheduler.Jobs(context.Background())
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.NotNil(t, jobs[0].NextRunAt)
+	assert.False(t, jobs[0].Running)
+}
+
+func TestSchedulerRejectsRegistrationAfterStart(t *testing.T) {
+	log := setupTestLogger(t)
+	elector := NewLeaderElector(NewLocalLockStore(), LeaderLockKey, "replica-1", time.Minute, log)
+	scheduler := NewScheduler(nil, elector, "replica-1", 10, log)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	scheduler.Run(ctx)
+
+	err := scheduler.Register("late", "@hourly", func(context.Context) error { return nil })
+	assert.ErrorContains(t, err, "already running")
+}
+
+func TestSchedulerUpcoming(t *testing.T) {
+	log := setupTestLogger(t)
+	elector := NewLeaderElector(NewLocalLockStore(), LeaderLockKey, "replica-1", time.Minute, log)
+	scheduler := NewScheduler(nil, elector, "replica-1", 10, log)
+	require.NoError(t, scheduler.Register("cleanup", "0 3 * * *", func(context.Context) error { return nil }))
+
+	from := time.Date(2026, 10, 16, 3, 0, 0, 0, time.UTC)
+	runs := scheduler.Upcoming("cleanup", from, from.Add(72*time.Hour), 10)
+	assert.Equal(t, []time.Time{from.AddDate(0, 0, 1), from.AddDate(0, 0, 2)}, runs)
+
+	assert.Len(t, scheduler.Upcoming("cleanup", from, from.AddDate(1, 0, 0), 5), 5)
+	assert.Empty(t, scheduler.Upcoming("missing", from, from.AddDate(1, 0, 0), 5))
+}
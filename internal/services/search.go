@@ -0,0 +1,300 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// Unified search limits
+const (
+	defaultSearchLimit = 5
+	// searchCandidates is how many of the most recently updated matches of
+	// each type are ranked; the ranking cannot run in the query
+	searchCandidates   = 50
+	searchSnippetRunes = 200
+	searchTitleRunes   = 80
+	// searchRecencyWindow is how long a recent update lifts a hit's score
+	searchRecencyWindow = 30 * 24 * time.Hour
+)
+
+// SearchService searches documents, notebooks, agents and live events at
+// once for the global search bar
+type SearchService struct {
+	neo4j  *database.Neo4jClient
+	teams  *TeamService
+	logger *logger.Logger
+}
+
+// NewSearchService creates a new unified search service
+func NewSearchService(neo4j *database.Neo4jClient, teams *TeamService, log *logger.Logger) *SearchService {
+	return &SearchService{
+		neo4j:  neo4j,
+		teams:  teams,
+		logger: log.WithService("search_service"),
+	}
+}
+
+// searchCandidate is a matching entity before it is scored
+type searchCandidate struct {
+	hit  *models.SearchHit
+	text string // Description or content the query may also match
+}
+
+// Search runs the query against each requested type in parallel. Results
+// are limited to what the user may read in the space; a type whose query
+// fails is reported as unavailable rather than failing the search.
+func (s *SearchService) Search(ctx context.Context, req models.UnifiedSearchRequest, userID string, spaceCtx *models.SpaceContext) (*models.UnifiedSearchResponse, error) {
+	if !spaceCtx.CanRead() {
+		return nil, errors.Forbidden("Insufficient permissions to search this space")
+	}
+	if req.Limit <= 0 {
+		req.Limit = defaultSearchLimit
+	}
+	types := req.Types
+	if len(types) == 0 {
+		types = models.SearchTypes
+	}
+
+	query := strings.ToLower(strings.TrimSpace(req.Query))
+	params := map[string]interface{}{
+		"query":      query,
+		"user_id":    userID,
+		"tenant_id":  spaceCtx.TenantID,
+		"space_id":   spaceCtx.SpaceID,
+		"candidates": searchCandidates,
+	}
+
+	candidates := make([][]searchCandidate, len(types))
+	failed := make([]bool, len(types))
+	var wg sync.WaitGroup
+	for i, searchType := range types {
+		wg.Add(1)
+		go func(i int, searchType models.SearchType) {
+			defer wg.Done()
+			found, err := s.searchType(ctx, searchType, params, userID)
+			if err != nil {
+				s.logger.FromContext(ctx).Error("Unified search failed for type",
+					zap.String("type", string(searchType)),
+					zap.Error(err),
+				)
+				failed[i] = true
+				return
+			}
+			candidates[i] = found
+		}(i, searchType)
+	}
+	wg.Wait()
+
+	response := &models.UnifiedSearchResponse{
+		Query:   req.Query,
+		Results: []*models.SearchHit{},
+		Groups:  []*models.SearchGroup{},
+	}
+	now := time.Now()
+	for i, searchType := range types {
+		if failed[i] {
+			response.Unavailable = append(response.Unavailable, searchType)
+			continue
+		}
+		group := rankSearchCandidates(searchType, query, candidates[i], req.Limit, now)
+		if len(group.Hits) == 0 {
+			continue
+		}
+		response.Groups = append(response.Groups, group)
+		response.Results = append(response.Results, group.Hits...)
+	}
+	if len(response.Unavailable) == len(types) {
+		return nil, errors.Database("Failed to search", nil)
+	}
+
+	sort.SliceStable(response.Groups, func(i, j int) bool {
+		return response.Groups[i].Hits[0].Score > response.Groups[j].Hits[0].Score
+	})
+	sortSearchHits(response.Results)
+	return response, nil
+}
+
+// searchType returns the matches of one type the user may read, most
+// recently updated first
+func (s *SearchService) searchType(ctx context.Context, searchType models.SearchType, params map[string]interface{}, userID string) ([]searchCandidate, error) {
+	var cypher string
+	switch searchType {
+	case models.SearchTypeDocument:
+		cypher = `
+			MATCH (d:Document)
+			WHERE ` + database.SoftDeleteFilter(ctx, "d") + `
+			  AND d.tenant_id = $tenant_id AND d.space_id = $space_id
+			  AND toLower(d.search_text) CONTAINS $query
+			RETURN d.id AS id, d.name AS title, d.description AS text, d.type AS kind,
+			       d.notebook_id AS notebook_id, d.updated_at AS updated_at
+			ORDER BY d.updated_at DESC
+			LIMIT $candidates
+		`
+	case models.SearchTypeNotebook:
+		cypher = `
+			MATCH (n:Notebook)
+			WHERE ` + database.SoftDeleteFilter(ctx, "n") + `
+			  AND n.tenant_id = $tenant_id AND n.space_id = $space_id
+			  AND toLower(n.search_text) CONTAINS $query
+			RETURN n.id AS id, n.name AS title, n.description AS text, null AS kind,
+			       null AS notebook_id, n.updated_at AS updated_at
+			ORDER BY n.updated_at DESC
+			LIMIT $candidates
+		`
+	case models.SearchTypeAgent:
+		// Agents follow the access rules of the agent list
+		teamIDs, err := s.teams.GetUserTeamIDs(ctx, userID)
+		if err != nil {
+			// Search the user's own and public agents only
+			s.logger.Warn("Failed to get user team IDs for search", zap.String("user_id", userID), zap.Error(err))
+			teamIDs = []string{}
+		}
+		params = withParam(params, "team_ids", teamIDs)
+		cypher = `
+			MATCH (a:Agent)
+			WHERE a.space_id = $space_id
+			  AND (a.owner_id = $user_id OR a.is_public = true OR a.team_id IN $team_ids)
+			  AND toLower(a.search_text) CONTAINS $query
+			RETURN a.id AS id, a.name AS title, a.description AS text, a.type AS kind,
+			       null AS notebook_id, a.updated_at AS updated_at
+			ORDER BY a.updated_at DESC
+			LIMIT $candidates
+		`
+	case models.SearchTypeEvent:
+		// Live events belong to the tenant rather than a space
+		cypher = `
+			MATCH (e:LiveEvent)
+			WHERE e.tenant_id = $tenant_id AND toLower(e.content) CONTAINS $query
+			RETURN e.id AS id, e.content AS title, e.content AS text, e.event_type AS kind,
+			       null AS notebook_id, e.processed_at AS updated_at
+			ORDER BY e.processed_at DESC
+			LIMIT $candidates
+		`
+	default:
+		return nil, nil
+	}
+
+	result, err := s.neo4j.ExecuteQuery(ctx, cypher, params)
+	if err != nil {
+		return nil, err
+	}
+	found := make([]searchCandidate, 0, len(result.Records))
+	for _, record := range result.Records {
+		found = append(found, recordToSearchCandidate(record, searchType))
+	}
+	return found, nil
+}
+
+// withParam returns a copy of params with one more parameter, as params is
+// shared by the concurrent queries
+func withParam(params map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(params)+1)
+	for k, v := range params {
+		copied[k] = v
+	}
+	copied[key] = value
+	return copied
+}
+
+func recordToSearchCandidate(record *neo4j.Record, searchType models.SearchType) searchCandidate {
+	text := recordString(record, "text")
+	hit := &models.SearchHit{
+		Type:       searchType,
+		ID:         recordString(record, "id"),
+		Title:      recordString(record, "title"),
+		Kind:       recordString(record, "kind"),
+		NotebookID: recordString(record, "notebook_id"),
+	}
+	if value, _ := record.Get("updated_at"); value != nil {
+		hit.UpdatedAt, _ = value.(time.Time)
+	}
+	if searchType == models.SearchTypeEvent {
+		// Events have no title of their own; show the start of the content
+		hit.Title = truncateRunes(hit.Title, searchTitleRunes)
+	}
+	if text != hit.Title {
+		hit.Snippet = truncateRunes(text, searchSnippetRunes)
+	}
+	return searchCandidate{hit: hit, text: text}
+}
+
+// rankSearchCandidates scores the candidates of one type and keeps the
+// best limit of them
+func rankSearchCandidates(searchType models.SearchType, query string, candidates []searchCandidate, limit int, now time.Time) *models.SearchGroup {
+	hits := make([]*models.SearchHit, 0, len(candidates))
+	for _, candidate := range candidates {
+		candidate.hit.Score = searchScore(query, candidate.hit.Title, candidate.text, candidate.hit.UpdatedAt, now)
+		hits = append(hits, candidate.hit)
+	}
+	sortSearchHits(hits)
+
+	group := &models.SearchGroup{Type: searchType, Hits: hits}
+	if len(hits) > limit {
+		group.Hits, group.HasMore = hits[:limit], true
+	}
+	return group
+}
+
+// sortSearchHits orders hits by score, then by most recent update
+func sortSearchHits(hits []*models.SearchHit) {
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].UpdatedAt.After(hits[j].UpdatedAt)
+	})
+}
+
+// searchScore ranks a match between 0 and 1.1 so hits of different types
+// compare: title matches beat matches in the description or content, and
+// updates in the last 30 days add up to 0.1. query is lower case.
+func searchScore(query, title, text string, updatedAt, now time.Time) float64 {
+	title = strings.ToLower(title)
+
+	var score float64
+	switch {
+	case title == query:
+		score = 1.0
+	case strings.HasPrefix(title, query):
+		score = 0.8
+	case strings.Contains(" "+title, " "+query):
+		// A word of the title starts with the query
+		score = 0.7
+	case strings.Contains(title, query):
+		score = 0.6
+	case strings.Contains(strings.ToLower(text), query):
+		score = 0.4
+	default:
+		// Matched on tags or extracted text only
+		score = 0.2
+	}
+
+	if age := now.Sub(updatedAt); !updatedAt.IsZero() && age < searchRecencyWindow {
+		if age < 0 {
+			age = 0
+		}
+		score += 0.1 * (1 - float64(age)/float64(searchRecencyWindow))
+	}
+	return score
+}
+
+// truncateRunes cuts s to at most n runes, marking the cut with an ellipsis
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimSpace(string(runes[:n-1])) + "…"
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"github.com/Tributary-ai-services/aether-be/internal/filterql"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// documentFilterFields are the fields of document filter expressions
+var documentFilterFields = filterql.Schema{
+	"tag":       {Property: "tags", Kind: filterql.KindList},
+	"mime":      {Property: "mime_type", Kind: filterql.KindMIME},
+	"type":      {Property: "type", Kind: filterql.KindString},
+	"status":    {Property: "status", Kind: filterql.KindString},
+	"name":      {Property: "name", Kind: filterql.KindText},
+	"filename":  {Property: "original_name", Kind: filterql.KindText},
+	"notebook":  {Property: "notebook_id", Kind: filterql.KindString},
+	"owner":     {Property: "owner_id", Kind: filterql.KindString},
+	"size":      {Property: "size_bytes", Kind: filterql.KindNumber},
+	"created":   {Property: "created_at", Kind: filterql.KindTime},
+	"updated":   {Property: "updated_at", Kind: filterql.KindTime},
+	"processed": {Property: "processed_at", Kind: filterql.KindTime},
+}
+
+// liveEventFilterFields are the fields of live event filter expressions
+var liveEventFilterFields = filterql.Schema{
+	"type":       {Property: "event_type", Kind: filterql.KindString},
+	"source":     {Property: "stream_source_id", Kind: filterql.KindString},
+	"media":      {Property: "media_type", Kind: filterql.KindString},
+	"sentiment":  {Property: "sentiment", Kind: filterql.KindString},
+	"content":    {Property: "content", Kind: filterql.KindText},
+	"confidence": {Property: "confidence", Kind: filterql.KindNumber},
+	"score":      {Property: "sentiment_score", Kind: filterql.KindNumber},
+	"processed":  {Property: "processed_at", Kind: filterql.KindTime},
+	"occurred":   {Property: "event_timestamp", Kind: filterql.KindTime},
+}
+
+// compileFilter compiles a filter expression into a predicate on variable,
+// adding its parameters to params. An empty expression adds nothing.
+func compileFilter(expr string, schema filterql.Schema, variable string, params map[string]interface{}) (string, error) {
+	predicate, filterParams, err := filterql.Compile(expr, schema, variable)
+	if err != nil {
+		return "", errors.ValidationWithDetails("Invalid filter expression", map[string]interface{}{
+			"param":  "filter",
+			"reason": err.Error(),
+			"fields": schema.Names(),
+		}).WithErrorCode(errors.CodeInvalidFilter)
+	}
+	for name, value := range filterParams {
+		params[name] = value
+	}
+	return predicate, nil
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+func TestCompileFilter(t *testing.T) {
+	params := map[string]interface{}{"tenant_id": "tenant_1"}
+	predicate, err := compileFilter("tag:contract AND mime:pdf AND created:>2024-01-01", documentFilterFields, "d", params)
+	require.NoError(t, err)
+	assert.Contains(t, predicate, "d.tags")
+	assert.Len(t, params, 4, "filter parameters are added to the query's")
+
+	predicate, err = compileFilter("", liveEventFilterFields, "e", params)
+	require.NoError(t, err)
+	assert.Empty(t, predicate)
+
+	_, err = compileFilter("tag:contract", liveEventFilterFields, "e", params)
+	require.Error(t, err)
+	apiErr, ok := errors.AsAPIError(err)
+	require.True(t, ok)
+	assert.Equal(t, errors.CodeInvalidFilter, apiErr.ErrorCode)
+	assert.True(t, errors.IsValidation(err))
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+func TestSearchScore(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := now.Add(-90 * 24 * time.Hour)
+
+	exact := searchScore("roadmap", "Roadmap", "", old, now)
+	prefix := searchScore("roadmap", "Roadmap 2026", "", old, now)
+	word := searchScore("roadmap", "Product roadmap", "", old, now)
+	inside := searchScore("map", "Roadmap", "", old, now)
+	text := searchScore("roadmap", "Planning", "The 2026 roadmap", old, now)
+	elsewhere := searchScore("roadmap", "Planning", "", old, now)
+
+	assert.Equal(t, 1.0, exact)
+	assert.Greater(t, exact, prefix)
+	assert.Greater(t, prefix, word)
+	assert.Greater(t, word, inside)
+	assert.Greater(t, inside, text)
+	assert.Greater(t, text, elsewhere)
+
+	// Recent updates lift a hit without outranking a better match
+	assert.InDelta(t, 0.1, searchScore("roadmap", "Roadmap", "", now, now)-exact, 1e-9)
+	assert.InDelta(t, 0.05, searchScore("roadmap", "Roadmap", "", now.Add(-15*24*time.Hour), now)-exact, 1e-9)
+	assert.Less(t, searchScore("roadmap", "Product roadmap", "", now, now), prefix)
+}
+
+func TestRankSearchCandidates(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	candidate := func(id, title string, updatedAt time.Time) searchCandidate {
+		return searchCandidate{hit: &models.SearchHit{Type: models.SearchTypeNotebook, ID: id, Title: title, UpdatedAt: updatedAt}}
+	}
+	candidates := []searchCandidate{
+		candidate("newest", "Meeting notes", now),
+		candidate("exact", "Budget", now.Add(-60*24*time.Hour)),
+		candidate("older-tie", "Budget plans", now.Add(-90*24*time.Hour)),
+		candidate("newer-tie", "Budget review", now.Add(-80*24*time.Hour)),
+	}
+
+	group := rankSearchCandidates(models.SearchTypeNotebook, "budget", candidates, 3, now)
+	require.Len(t, group.Hits, 3)
+	assert.True(t, group.HasMore)
+	assert.Equal(t, "exact", group.Hits[0].ID)
+	assert.Equal(t, "newer-tie", group.Hits[1].ID, "equal scores rank the most recent first")
+	assert.Equal(t, "older-tie", group.Hits[2].ID)
+
+	group = rankSearchCandidates(models.SearchTypeNotebook, "budget", candidates, 5, now)
+	assert.Len(t, group.Hits, 4)
+	assert.False(t, group.HasMore)
+}
+
+func TestTruncateRunes(t *testing.T) {
+	assert.Equal(t, "short", truncateRunes("short", 10))
+	assert.Equal(t, "héllo…", truncateRunes("héllo wörld", 7))
+}
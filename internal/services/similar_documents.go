@@ -0,0 +1,384 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// Similar document limits
+const (
+	defaultSimilarLimit = 10
+	// maxSimilarDocuments is how many related documents are ranked and
+	// cached; requests for fewer are served from the same list
+	maxSimilarDocuments = 50
+	// similarCandidateLimit bounds the documents scored per request
+	similarCandidateLimit = 200
+	// similarQueryRunes is how much of a document's text is searched for
+	// its neighbours in the vector index
+	similarQueryRunes = 2000
+	similarCacheTTL   = time.Hour
+)
+
+// How the signals relating two documents are weighed. Graph proximity is
+// split between sharing a notebook and sharing referenced entities, which
+// count fully from similarFullReferences shared entities on.
+const (
+	similarVectorWeight   = 0.5
+	similarTagWeight      = 0.25
+	similarGraphWeight    = 0.25
+	similarFullReferences = 3
+)
+
+// ResultCache stores computed results for a while. The Redis client
+// implements it; LocalResultCache stands in when Redis is disabled.
+type ResultCache interface {
+	// Get returns the value of key, or "" when it is not set
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// SimilarDocumentService recommends the documents of a space related to a
+// document, combining vector similarity of their contents, shared tags and
+// graph proximity. Rankings are cached per document and dropped when the
+// document changes or is reprocessed.
+type SimilarDocumentService struct {
+	neo4j     *database.Neo4jClient
+	documents *DocumentService
+	vectors   *VectorSearchService
+	cache     ResultCache
+	logger    *logger.Logger
+}
+
+// NewSimilarDocumentService creates a similar document service. Without
+// vectors, or with vector search disabled, documents are related by tags
+// and the graph alone; without cache rankings are cached in-process.
+func NewSimilarDocumentService(neo4j *database.Neo4jClient, documents *DocumentService, vectors *VectorSearchService, cache ResultCache, log *logger.Logger) *SimilarDocumentService {
+	if cache == nil {
+		cache = NewLocalResultCache()
+	}
+	return &SimilarDocumentService{
+		neo4j:     neo4j,
+		documents: documents,
+		vectors:   vectors,
+		cache:     cache,
+		logger:    log.WithService("similar_document_service"),
+	}
+}
+
+// similarCacheKey names the cached ranking of a document
+func similarCacheKey(documentID string) string {
+	return fmt.Sprintf("tas:similar:%s", documentID)
+}
+
+// GetSimilarDocuments returns up to limit documents related to a document
+// the user can read, best first
+func (s *SimilarDocumentService) GetSimilarDocuments(ctx context.Context, documentID, userID string, spaceCtx *models.SpaceContext, limit int) (*models.SimilarDocumentsResponse, error) {
+	document, err := s.documents.GetDocumentByID(ctx, documentID, userID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultSimilarLimit
+	}
+	if limit > maxSimilarDocuments {
+		limit = maxSimilarDocuments
+	}
+
+	response, ok := s.cached(ctx, documentID)
+	if !ok {
+		if response, err = s.rank(ctx, document, spaceCtx); err != nil {
+			return nil, err
+		}
+		s.store(ctx, response)
+	}
+
+	if len(response.Documents) > limit {
+		response.Documents = response.Documents[:limit]
+	}
+	return response, nil
+}
+
+// HandleDomainEvent drops the cached ranking of a document that changed,
+// was reprocessed or was deleted. Rankings that list the document expire
+// with the cache.
+func (s *SimilarDocumentService) HandleDomainEvent(ctx context.Context, event Event) error {
+	switch event.Type {
+	case EventDocumentUpdated, EventDocumentStatusChanged, EventDocumentProcessed, EventDocumentDeleted:
+		if err := s.cache.Delete(ctx, similarCacheKey(event.Subject)); err != nil {
+			return fmt.Errorf("failed to invalidate similar documents of %s: %w", event.Subject, err)
+		}
+	}
+	return nil
+}
+
+// cached returns the cached ranking of a document. An unreadable cache
+// entry counts as a miss.
+func (s *SimilarDocumentService) cached(ctx context.Context, documentID string) (*models.SimilarDocumentsResponse, bool) {
+	value, err := s.cache.Get(ctx, similarCacheKey(documentID))
+	if err != nil {
+		s.logger.Warn("Failed to read cached similar documents", zap.String("document_id", documentID), zap.Error(err))
+		return nil, false
+	}
+	if value == "" {
+		return nil, false
+	}
+	var response models.SimilarDocumentsResponse
+	if err := json.Unmarshal([]byte(value), &response); err != nil {
+		return nil, false
+	}
+	return &response, true
+}
+
+func (s *SimilarDocumentService) store(ctx context.Context, response *models.SimilarDocumentsResponse) {
+	value, err := json.Marshal(response)
+	if err == nil {
+		err = s.cache.Set(ctx, similarCacheKey(response.DocumentID), string(value), similarCacheTTL)
+	}
+	if err != nil {
+		s.logger.Warn("Failed to cache similar documents", zap.String("document_id", response.DocumentID), zap.Error(err))
+	}
+}
+
+// similarCandidatesQuery scores the documents of the space that share a
+// notebook, a tag or a referenced entity with the document, or that the
+// vector index found, preferring the latter
+const similarCandidatesQuery = `
+	MATCH (d:Document {id: $document_id, tenant_id: $tenant_id})
+	MATCH (o:Document {space_id: $space_id})
+	WHERE o <> d AND %s
+	  AND (o.id IN $vector_ids
+	       OR o.notebook_id = d.notebook_id
+	       OR any(tag IN coalesce(o.tags, []) WHERE tag IN coalesce(d.tags, []))
+	       OR EXISTS { (d)-[:MENTIONS]->(:Entity)<-[:MENTIONS]-(o) })
+	OPTIONAL MATCH (d)-[:MENTIONS]->(e:Entity)<-[:MENTIONS]-(o)
+	WITH d, o, count(DISTINCT e) AS shared_references
+	WITH d, o, shared_references,
+	     [tag IN coalesce(o.tags, []) WHERE tag IN coalesce(d.tags, [])] AS shared_tags
+	ORDER BY o.id IN $vector_ids DESC, shared_references DESC, size(shared_tags) DESC,
+	         o.notebook_id = d.notebook_id DESC, o.updated_at DESC
+	LIMIT $limit
+	RETURN o.id AS id, o.name AS name, o.mime_type AS mime_type, o.notebook_id AS notebook_id,
+	       shared_tags, size(coalesce(d.tags, [])) + size(coalesce(o.tags, [])) AS tag_total,
+	       o.notebook_id = d.notebook_id AS same_notebook, shared_references
+`
+
+// rank computes the ranking of a document
+func (s *SimilarDocumentService) rank(ctx context.Context, document *models.Document, spaceCtx *models.SpaceContext) (*models.SimilarDocumentsResponse, error) {
+	vectorScores := s.vectorScores(ctx, document, spaceCtx)
+	vectorIDs := make([]string, 0, len(vectorScores))
+	for id := range vectorScores {
+		vectorIDs = append(vectorIDs, id)
+	}
+
+	ctx = database.WithQueryName(ctx, "document.similar")
+	result, err := s.neo4j.ExecuteQuery(ctx, fmt.Sprintf(similarCandidatesQuery, database.SoftDeleteFilter(ctx, "o")), map[string]interface{}{
+		"document_id": document.ID,
+		"tenant_id":   spaceCtx.TenantID,
+		"space_id":    spaceCtx.SpaceID,
+		"vector_ids":  vectorIDs,
+		"limit":       similarCandidateLimit,
+	})
+	if err != nil {
+		s.logger.Error("Failed to find similar documents", zap.String("document_id", document.ID), zap.Error(err))
+		return nil, errors.Database("Failed to find similar documents", err)
+	}
+
+	similar := make([]*models.SimilarDocument, 0, len(result.Records))
+	for _, record := range result.Records {
+		candidate := recordToSimilarDocument(record)
+		candidate.VectorScore = vectorScores[candidate.Document.ID]
+		candidate.Score = similarityScore(candidate.VectorScore, len(candidate.SharedTags), int(recordInt64(record, "tag_total")),
+			candidate.SameNotebook, candidate.SharedReferences)
+		similar = append(similar, candidate)
+	}
+	sort.SliceStable(similar, func(i, j int) bool {
+		if similar[i].Score != similar[j].Score {
+			return similar[i].Score > similar[j].Score
+		}
+		return similar[i].Document.ID < similar[j].Document.ID
+	})
+	if len(similar) > maxSimilarDocuments {
+		similar = similar[:maxSimilarDocuments]
+	}
+
+	return &models.SimilarDocumentsResponse{
+		DocumentID:  document.ID,
+		Documents:   similar,
+		GeneratedAt: time.Now().UTC(),
+	}, nil
+}
+
+// vectorScores searches the vector index with the document's text and
+// returns the best chunk score of each other document of the space found.
+// Vector search is an optional signal: when it is unavailable the ranking
+// goes on without it.
+func (s *SimilarDocumentService) vectorScores(ctx context.Context, document *models.Document, spaceCtx *models.SpaceContext) map[string]float64 {
+	scores := make(map[string]float64)
+	if s.vectors == nil || !s.vectors.config.Enabled {
+		return scores
+	}
+
+	query := document.ExtractedText
+	if query == "" {
+		query = document.Name + " " + document.Description
+	}
+	query = truncateRunes(query, similarQueryRunes)
+
+	tenant, err := s.vectors.spaces.ResolveTenant(ctx, models.TenantLookupBySpaceID, spaceCtx.SpaceID)
+	if err != nil {
+		s.logger.Warn("Skipping vector similarity", zap.String("document_id", document.ID), zap.Error(err))
+		return scores
+	}
+	req := models.SemanticSearchRequest{
+		Query: query,
+		Mode:  models.SemanticSearchVector,
+		TopK:  similarCandidateLimit / semanticOverfetch,
+	}
+	found, err := s.vectors.searchNamespace(ctx, s.vectors.namespace(tenant), req)
+	if err == nil {
+		var hits []*models.SemanticSearchHit
+		if hits, err = s.vectors.mapToDocuments(ctx, spaceCtx.SpaceID, req, found.Results); err == nil {
+			for _, hit := range hits {
+				if hit.Document.ID != document.ID && hit.Score > scores[hit.Document.ID] {
+					scores[hit.Document.ID] = clampUnit(hit.Score)
+				}
+			}
+		}
+	}
+	if err != nil {
+		s.logger.Warn("Skipping vector similarity", zap.String("document_id", document.ID), zap.Error(err))
+	}
+	return scores
+}
+
+func recordToSimilarDocument(record *neo4j.Record) *models.SimilarDocument {
+	similar := &models.SimilarDocument{
+		Document: &models.SemanticSearchDocument{
+			ID:         recordString(record, "id"),
+			Name:       recordString(record, "name"),
+			MimeType:   recordString(record, "mime_type"),
+			NotebookID: recordString(record, "notebook_id"),
+		},
+		SharedReferences: int(recordInt64(record, "shared_references")),
+	}
+	if value, ok := record.Get("same_notebook"); ok && value != nil {
+		similar.SameNotebook, _ = value.(bool)
+	}
+	if value, ok := record.Get("shared_tags"); ok && value != nil {
+		if tags, ok := value.([]interface{}); ok {
+			for _, tag := range tags {
+				if tag, ok := tag.(string); ok {
+					similar.SharedTags = append(similar.SharedTags, tag)
+				}
+			}
+		}
+	}
+	return similar
+}
+
+// similarityScore weighs the signals relating two documents. Tags count by
+// the Jaccard index of the two documents' tags; tagTotal is the number of
+// tags of both together, shared ones counted twice.
+func similarityScore(vectorScore float64, sharedTags, tagTotal int, sameNotebook bool, sharedReferences int) float64 {
+	tagScore := 0.0
+	if union := tagTotal - sharedTags; union > 0 {
+		tagScore = float64(sharedTags) / float64(union)
+	}
+
+	graphScore := 0.0
+	if sameNotebook {
+		graphScore += 0.5
+	}
+	if sharedReferences > similarFullReferences {
+		sharedReferences = similarFullReferences
+	}
+	graphScore += 0.5 * float64(sharedReferences) / similarFullReferences
+
+	return similarVectorWeight*clampUnit(vectorScore) + similarTagWeight*tagScore + similarGraphWeight*graphScore
+}
+
+func clampUnit(value float64) float64 {
+	if value < 0 {
+		return 0
+	}
+	if value > 1 {
+		return 1
+	}
+	return value
+}
+
+// LocalResultCache is an in-process ResultCache. Invalidations only reach
+// the replica that handled the change, so with several replicas rankings
+// may be stale until they expire.
+type LocalResultCache struct {
+	mu      sync.Mutex
+	entries map[string]localCacheEntry
+}
+
+type localCacheEntry struct {
+	value     string
+	expiresAt time.Time
+}
+
+// NewLocalResultCache creates an in-process result cache
+func NewLocalResultCache() *LocalResultCache {
+	return &LocalResultCache{entries: make(map[string]localCacheEntry)}
+}
+
+// Get returns the value of key, or "" when it is not set or expired
+func (l *LocalResultCache) Get(ctx context.Context, key string) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.entries[key]
+	if !ok {
+		return "", nil
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(l.entries, key)
+		return "", nil
+	}
+	return entry.value, nil
+}
+
+// Set stores the string form of value under key until expiration passes
+func (l *LocalResultCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Drop expired entries now and then so the map does not grow unbounded
+	now := time.Now()
+	if len(l.entries)%100 == 99 {
+		for existing, entry := range l.entries {
+			if now.After(entry.expiresAt) {
+				delete(l.entries, existing)
+			}
+		}
+	}
+	l.entries[key] = localCacheEntry{value: fmt.Sprint(value), expiresAt: now.Add(expiration)}
+	return nil
+}
+
+// Delete removes keys
+func (l *LocalResultCache) Delete(ctx context.Context, keys ...string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		delete(l.entries, key)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimilarityScore(t *testing.T) {
+	assert.Zero(t, similarityScore(0, 0, 0, false, 0))
+	assert.InDelta(t, 1.0, similarityScore(1, 2, 4, true, 5), 1e-9, "every signal at its best scores 1")
+
+	// Two of four distinct tags shared, same notebook, one shared entity
+	score := similarityScore(0.8, 2, 6, true, 1)
+	assert.InDelta(t, 0.5*0.8+0.25*0.5+0.25*(0.5+0.5/3), score, 1e-9)
+
+	assert.Greater(t, similarityScore(0.9, 0, 0, false, 0), similarityScore(0, 1, 4, true, 0),
+		"content similarity outweighs sharing a notebook and a tag")
+}
+
+func TestLocalResultCache(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLocalResultCache()
+
+	require.NoError(t, cache.Set(ctx, "a", "ranking", time.Hour))
+	value, err := cache.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "ranking", value)
+
+	require.NoError(t, cache.Delete(ctx, "a"))
+	value, _ = cache.Get(ctx, "a")
+	assert.Empty(t, value)
+
+	require.NoError(t, cache.Set(ctx, "b", "ranking", -time.Second))
+	value, _ = cache.Get(ctx, "b")
+	assert.Empty(t, value, "expired entries are misses")
+}
+
+func TestSimilarDocumentsInvalidation(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLocalResultCache()
+	service := &SimilarDocumentService{cache: cache}
+	require.NoError(t, cache.Set(ctx, similarCacheKey("doc-1"), "ranking", time.Hour))
+
+	require.NoError(t, service.HandleDomainEvent(ctx, NewDocumentEvent(EventUserUpdated, "doc-1", "", nil)))
+	value, _ := cache.Get(ctx, similarCacheKey("doc-1"))
+	assert.NotEmpty(t, value)
+
+	require.NoError(t, service.HandleDomainEvent(ctx, NewDocumentEvent(EventDocumentProcessed, "doc-1", "", nil)))
+	value, _ = cache.Get(ctx, similarCacheKey("doc-1"))
+	assert.Empty(t, value, "reprocessing drops the ranking")
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/metrics"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+// SLOMonitor evaluates this replica's SLOs periodically and publishes an
+// event when an objective starts or stops burning its error budget faster
+// than the alert thresholds. Each replica alerts on its own traffic.
+type SLOMonitor struct {
+	tracker    *metrics.SLOTracker
+	events     DomainEventPublisher
+	instanceID string
+	interval   time.Duration
+	logger     *logger.Logger
+
+	mu       sync.Mutex
+	alerting map[string]bool
+}
+
+// NewSLOMonitor creates a monitor evaluating the tracker every interval
+func NewSLOMonitor(tracker *metrics.SLOTracker, instanceID string, interval time.Duration, log *logger.Logger) *SLOMonitor {
+	return &SLOMonitor{
+		tracker:    tracker,
+		instanceID: instanceID,
+		interval:   interval,
+		logger:     log.WithService("slo_monitor"),
+		alerting:   make(map[string]bool),
+	}
+}
+
+// SetEventPublisher sets where alert events are published
+func (m *SLOMonitor) SetEventPublisher(events DomainEventPublisher) {
+	m.events = events
+}
+
+// Report returns the current SLO compliance of this replica
+func (m *SLOMonitor) Report() *models.SLOReport {
+	report := m.tracker.Report()
+	report.InstanceID = m.instanceID
+	return report
+}
+
+// Run evaluates the SLOs until ctx is cancelled
+func (m *SLOMonitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Evaluate(ctx)
+		}
+	}
+}
+
+// Evaluate publishes an event for every objective whose alert state changed
+// since the last evaluation
+func (m *SLOMonitor) Evaluate(ctx context.Context) {
+	report := m.Report()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, status := range report.Objectives {
+		key := status.Objective
+		if status.RouteGroup != "" {
+			key += ":" + status.RouteGroup
+		}
+		if m.alerting[key] == status.Alerting {
+			continue
+		}
+		m.alerting[key] = status.Alerting
+
+		eventType := EventSLOBurnRateResolved
+		if status.Alerting {
+			eventType = EventSLOBurnRateAlert
+			m.logger.Warn("SLO error budget burning too fast",
+				zap.String("objective", key),
+				zap.Float64("burn_rate_1h", status.BurnRate1h),
+				zap.Float64("burn_rate_6h", status.BurnRate6h),
+				zap.Float64("error_budget_remaining", status.ErrorBudgetRemaining),
+			)
+		} else {
+			m.logger.Info("SLO burn rate back within threshold", zap.String("objective", key))
+		}
+
+		publishDomainEvent(ctx, m.events, m.logger, Event{
+			Type:    eventType,
+			Subject: key,
+			Data: map[string]interface{}{
+				"instance_id":            report.InstanceID,
+				"objective":              status.Objective,
+				"route_group":            status.RouteGroup,
+				"target":                 status.Target,
+				"sli":                    status.SLI,
+				"burn_rate_1h":           status.BurnRate1h,
+				"burn_rate_6h":           status.BurnRate6h,
+				"error_budget_remaining": status.ErrorBudgetRemaining,
+			},
+		})
+	}
+}
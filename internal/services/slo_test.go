@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/metrics"
+)
+
+func TestSLOMonitorPublishesAlertStateChanges(t *testing.T) {
+	tracker := metrics.NewSLOTracker(metrics.SLOObjectives{
+		WindowDays:         30,
+		AvailabilityTarget: 0.999,
+		LatencyP95Ms:       1000,
+		ProcessingTarget:   0.95,
+		FastBurnRate:       14.4,
+		SlowBurnRate:       6,
+	})
+	monitor := NewSLOMonitor(tracker, "replica-1", time.Minute, setupTestLogger(t))
+
+	bus := NewDomainEventBus(nil, setupTestLogger(t))
+	var received []Event
+	bus.Subscribe(func(ctx context.Context, event Event) error {
+		received = append(received, event)
+		return nil
+	})
+	monitor.SetEventPublisher(bus)
+
+	for i := 0; i < 50; i++ {
+		tracker.RecordRequest("/api/v1/notebooks", http.StatusInternalServerError, time.Millisecond)
+	}
+	monitor.Evaluate(context.Background())
+	monitor.Evaluate(context.Background())
+
+	// Alerts are edge-triggered
+	require.Len(t, received, 1)
+	assert.Equal(t, EventSLOBurnRateAlert, received[0].Type)
+	assert.Equal(t, "availability", received[0].Subject)
+	assert.Equal(t, "replica-1", received[0].Data["instance_id"])
+
+	for i := 0; i < 10000; i++ {
+		tracker.RecordRequest("/api/v1/notebooks", http.StatusOK, time.Millisecond)
+	}
+	monitor.Evaluate(context.Background())
+
+	require.Len(t, received, 2)
+	assert.Equal(t, EventSLOBurnRateResolved, received[1].Type)
+	assert.Equal(t, "replica-1", monitor.Report().InstanceID)
+}
@@ -3,14 +3,19 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"go.uber.org/zap"
 
 	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/filetype"
 	"github.com/Tributary-ai-services/aether-be/internal/logger"
 	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
 	"github.com/Tributary-ai-services/aether-be/pkg/errors"
 )
 
@@ -18,7 +23,21 @@ import (
 // This is separate from SpaceContextService which handles context resolution
 type SpaceService struct {
 	neo4j  *database.Neo4jClient
+	events DomainEventPublisher
+	authz  *AuthorizationService
 	logger *logger.Logger
+
+	// ocrLanguagePacks are the OCR languages spaces can enable; nil allows
+	// any
+	ocrLanguagePacks []string
+
+	// processingProviders are the processing providers spaces can choose;
+	// nil allows any
+	processingProviders []string
+
+	// scannerConfigured is whether uploads can be scanned for malware;
+	// spaces cannot turn scanning on without a scanner
+	scannerConfigured bool
 }
 
 // NewSpaceService creates a new space service
@@ -29,6 +48,42 @@ func NewSpaceService(neo4j *database.Neo4jClient, log *logger.Logger) *SpaceServ
 	}
 }
 
+// SetEventPublisher sets the publisher notified of new space members
+func (s *SpaceService) SetEventPublisher(events DomainEventPublisher) {
+	s.events = events
+}
+
+// SetAuthorizationService resolves the permissions of custom organization
+// roles; without it only the built-in roles grant permissions
+func (s *SpaceService) SetAuthorizationService(authz *AuthorizationService) {
+	s.authz = authz
+}
+
+// AuthorizeRole checks that role, held by userID in a space, grants action
+// on it
+func (s *SpaceService) AuthorizeRole(ctx context.Context, spaceID, userID, role, action string) error {
+	subject := models.Subject{UserID: userID, Roles: []string{role}}
+	return s.authz.Authorize(ctx, subject, action, models.Resource{Type: models.ResourceSpace, ID: spaceID})
+}
+
+// SetOCRLanguagePacks limits the OCR languages spaces can enable to the
+// language packs AudiModal has installed
+func (s *SpaceService) SetOCRLanguagePacks(packs []string) {
+	s.ocrLanguagePacks = packs
+}
+
+// SetProcessingProviders limits the processing providers spaces can choose
+// to the registered ones
+func (s *SpaceService) SetProcessingProviders(providers []string) {
+	s.processingProviders = providers
+}
+
+// SetScannerConfigured lets spaces turn malware scanning on, when a
+// scanner is configured
+func (s *SpaceService) SetScannerConfigured(configured bool) {
+	s.scannerConfigured = configured
+}
+
 // CreateSpace creates a new organization space linked to an organization via HAS_SPACE relationship
 // Organization ID is REQUIRED - spaces must belong to an organization
 func (s *SpaceService) CreateSpace(ctx context.Context, userID string, req models.SpaceCreateRequest) (*models.Space, error) {
@@ -60,7 +115,7 @@ func (s *SpaceService) CreateSpace(ctx context.Context, userID string, req model
 	if len(orgResult.Records) == 0 {
 		return nil, errors.NotFoundWithDetails("Organization not found", map[string]interface{}{
 			"organization_id": req.OrganizationID,
-		})
+		}).WithErrorCode(errors.CodeOrganizationNotFound)
 	}
 
 	// Create the space using the factory method
@@ -175,6 +230,7 @@ func (s *SpaceService) GetSpaceByID(ctx context.Context, spaceID string) (*model
 
 	query := `
 		MATCH (sp:Space {id: $space_id})
+		WHERE ` + database.SoftDeleteFilter(ctx, "sp") + `
 		OPTIONAL MATCH (sp)<-[:OWNS]-(owner:User)
 		RETURN sp.id, sp.tenant_id, sp.audimodal_tenant_id, sp.deeplake_namespace,
 		       sp.name, sp.description, sp.space_type as type, sp.visibility,
@@ -207,6 +263,109 @@ func (s *SpaceService) GetSpaceByID(ctx context.Context, spaceID string) (*model
 	return space, nil
 }
 
+// tenantSpaceMatches are the Space node conditions of each tenant lookup
+var tenantSpaceMatches = map[models.TenantLookupKey]string{
+	models.TenantLookupByTenantID:          "sp.tenant_id = $value",
+	models.TenantLookupBySpaceID:           "sp.id = $value",
+	models.TenantLookupByAudimodalTenantID: "sp.audimodal_tenant_id = $value",
+}
+
+// tenantUserMatches are the User node conditions of each tenant lookup.
+// Older users have no personal_space_id, which is then derived from the
+// tenant ID as in recordToUser, and AudiModal knows personal tenants by
+// their tenant ID without the "tenant_" prefix.
+var tenantUserMatches = map[models.TenantLookupKey]string{
+	models.TenantLookupByTenantID: "u.personal_tenant_id = $value",
+	models.TenantLookupBySpaceID: `(u.personal_space_id = $value OR
+		(u.personal_space_id IS NULL AND $value STARTS WITH 'space_' AND
+		 u.personal_tenant_id IN ['tenant_' + substring($value, 6), substring($value, 6)]))`,
+	models.TenantLookupByAudimodalTenantID: "u.personal_tenant_id IN [$value, 'tenant_' + $value]",
+}
+
+// ResolveTenant looks up a tenant by its tenant ID, its space ID or the
+// tenant ID AudiModal assigned to it. Organization tenants are Space nodes;
+// personal tenants may only be recorded on their user.
+func (s *SpaceService) ResolveTenant(ctx context.Context, key models.TenantLookupKey, value string) (*models.TenantInfo, error) {
+	spaceMatch, ok := tenantSpaceMatches[key]
+	if !ok {
+		return nil, errors.BadRequestWithDetails("Unknown tenant lookup", map[string]interface{}{
+			"key": string(key),
+		})
+	}
+	if value == "" {
+		return nil, errors.ValidationWithDetails("Tenant lookup value is required", map[string]interface{}{
+			"field": string(key),
+		})
+	}
+	params := map[string]interface{}{"value": value}
+
+	spaceQuery := `
+		MATCH (sp:Space)
+		WHERE ` + spaceMatch + ` AND ` + database.SoftDeleteFilter(ctx, "sp") + `
+		RETURN sp.tenant_id, sp.id, sp.space_type, sp.name, sp.owner_id,
+		       sp.audimodal_tenant_id, sp.deeplake_namespace, sp.status
+		LIMIT 1
+	`
+	result, err := s.neo4j.ExecuteQueryWithLogging(ctx, spaceQuery, params)
+	if err != nil {
+		return nil, errors.Database("Failed to resolve tenant", err)
+	}
+	if len(result.Records) > 0 {
+		r := result.Records[0]
+		tenant := &models.TenantInfo{
+			TenantID:          recordString(r, "sp.tenant_id"),
+			SpaceID:           recordString(r, "sp.id"),
+			SpaceType:         models.SpaceType(recordString(r, "sp.space_type")),
+			Name:              recordString(r, "sp.name"),
+			OwnerID:           recordString(r, "sp.owner_id"),
+			AudimodalTenantID: recordString(r, "sp.audimodal_tenant_id"),
+			DeeplakeNamespace: recordString(r, "sp.deeplake_namespace"),
+			Status:            models.SpaceStatus(recordString(r, "sp.status")),
+		}
+		if tenant.DeeplakeNamespace == "" {
+			tenant.DeeplakeNamespace = tenant.TenantID
+		}
+		return tenant, nil
+	}
+
+	userQuery := `
+		MATCH (u:User)
+		WHERE u.personal_tenant_id IS NOT NULL AND ` + tenantUserMatches[key] + `
+		RETURN u.id, u.personal_tenant_id, u.personal_space_id, u.full_name, u.status
+		LIMIT 1
+	`
+	result, err = s.neo4j.ExecuteQueryWithLogging(ctx, userQuery, params)
+	if err != nil {
+		return nil, errors.Database("Failed to resolve tenant", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, errors.NotFoundWithDetails("Tenant not found", map[string]interface{}{
+			string(key): value,
+		})
+	}
+
+	r := result.Records[0]
+	user := &models.User{}
+	user.SetPersonalTenantInfo(recordString(r, "u.personal_tenant_id"), "")
+	if spaceID := recordString(r, "u.personal_space_id"); spaceID != "" {
+		user.PersonalSpaceID = spaceID
+	}
+	status := models.SpaceStatusActive
+	if recordString(r, "u.status") == "suspended" {
+		status = models.SpaceStatusSuspended
+	}
+	return &models.TenantInfo{
+		TenantID:          user.PersonalTenantID,
+		SpaceID:           user.PersonalSpaceID,
+		SpaceType:         models.SpaceTypePersonal,
+		Name:              fmt.Sprintf("%s's Personal Space", recordString(r, "u.full_name")),
+		OwnerID:           recordString(r, "u.id"),
+		AudimodalTenantID: strings.TrimPrefix(user.PersonalTenantID, "tenant_"),
+		DeeplakeNamespace: user.PersonalTenantID,
+		Status:            status,
+	}, nil
+}
+
 // GetUserSpaces retrieves all spaces a user has access to:
 // - Personal spaces: via OWNS relationship
 // - Organization spaces: via user's MEMBER_OF relationship to Organization which HAS_SPACE
@@ -244,6 +403,7 @@ func (s *SpaceService) GetUserSpaces(ctx context.Context, userID string) ([]*mod
 		UNWIND (personal_spaces + org_spaces) as space_info
 		WITH space_info
 		WHERE space_info.space IS NOT NULL
+		  AND ` + database.SoftDeleteFilter(ctx, "space_info.space") + `
 		RETURN DISTINCT
 		       space_info.space.id as id,
 		       space_info.space.name as name,
@@ -338,6 +498,33 @@ func (s *SpaceService) GetUserRoleInSpace(ctx context.Context, spaceID, userID s
 func (s *SpaceService) UpdateSpace(ctx context.Context, spaceID string, req models.SpaceUpdateRequest) (*models.Space, error) {
 	s.logger.Info("Updating space", zap.String("space_id", spaceID))
 
+	if req.OCR != nil {
+		if err := s.validateOCRLanguages(req.OCR.Languages); err != nil {
+			return nil, err
+		}
+	}
+	if req.Processing != nil {
+		if err := s.validateProcessingProvider(req.Processing.Provider); err != nil {
+			return nil, err
+		}
+	}
+	if req.Scanning != nil && req.Scanning.Enabled != nil && *req.Scanning.Enabled && !s.scannerConfigured {
+		return nil, errors.ValidationWithDetails("Malware scanning is not configured", map[string]interface{}{
+			"setting": "scanning.enabled",
+		})
+	}
+
+	if req.FileValidation != nil {
+		for _, pattern := range req.FileValidation.AllowedTypes {
+			if !filetype.ValidPattern(pattern) {
+				return nil, errors.ValidationWithDetails("Allowed file type is not a MIME type", map[string]interface{}{
+					"setting": "file_validation.allowed_types",
+					"value":   pattern,
+				})
+			}
+		}
+	}
+
 	// Get current space to verify it exists
 	space, err := s.GetSpaceByID(ctx, spaceID)
 	if err != nil {
@@ -347,12 +534,23 @@ func (s *SpaceService) UpdateSpace(ctx context.Context, spaceID string, req mode
 	// Apply updates
 	space.Update(req)
 
+	// Serialize settings to JSON string for Neo4j, as on creation
+	settingsJSON := ""
+	if space.Settings != nil {
+		settingsBytes, err := json.Marshal(space.Settings)
+		if err != nil {
+			return nil, errors.Internal("Failed to serialize space settings")
+		}
+		settingsJSON = string(settingsBytes)
+	}
+
 	// Update in Neo4j
 	query := `
 		MATCH (sp:Space {id: $space_id})
 		SET sp.name = $name,
 		    sp.description = $description,
 		    sp.visibility = $visibility,
+		    sp.settings = $settings,
 		    sp.updated_at = datetime($updated_at)
 		RETURN sp.id
 	`
@@ -362,6 +560,7 @@ func (s *SpaceService) UpdateSpace(ctx context.Context, spaceID string, req mode
 		"name":        space.Name,
 		"description": space.Description,
 		"visibility":  space.Visibility,
+		"settings":    settingsJSON,
 		"updated_at":  space.UpdatedAt.Format(time.RFC3339),
 	}
 
@@ -379,6 +578,35 @@ func (s *SpaceService) UpdateSpace(ctx context.Context, spaceID string, req mode
 	return space, nil
 }
 
+// validateOCRLanguages checks that OCR languages have installed language
+// packs
+func (s *SpaceService) validateOCRLanguages(languages []string) error {
+	if s.ocrLanguagePacks == nil {
+		return nil
+	}
+	for _, language := range languages {
+		if !slices.Contains(s.ocrLanguagePacks, language) {
+			return errors.ValidationWithDetails("OCR language pack is not installed", map[string]interface{}{
+				"language":  language,
+				"available": s.ocrLanguagePacks,
+			})
+		}
+	}
+	return nil
+}
+
+// validateProcessingProvider checks that a processing provider is
+// registered; empty chooses the default
+func (s *SpaceService) validateProcessingProvider(provider string) error {
+	if provider == "" || s.processingProviders == nil || slices.Contains(s.processingProviders, provider) {
+		return nil
+	}
+	return errors.ValidationWithDetails("Processing provider is not registered", map[string]interface{}{
+		"provider":  provider,
+		"available": s.processingProviders,
+	})
+}
+
 // DeleteSpace performs a soft delete on a Space
 func (s *SpaceService) DeleteSpace(ctx context.Context, spaceID, deletedBy string) error {
 	s.logger.Info("Deleting space",
@@ -397,7 +625,7 @@ func (s *SpaceService) DeleteSpace(ctx context.Context, spaceID, deletedBy strin
 	// Soft delete: update status and set deleted_at
 	query := `
 		MATCH (sp:Space {id: $space_id})
-		SET sp.status = 'deleted',
+		SET sp.status = $status,
 		    sp.deleted_at = datetime($deleted_at),
 		    sp.deleted_by = $deleted_by,
 		    sp.updated_at = datetime($updated_at)
@@ -406,6 +634,7 @@ func (s *SpaceService) DeleteSpace(ctx context.Context, spaceID, deletedBy strin
 
 	params := map[string]interface{}{
 		"space_id":   spaceID,
+		"status":     database.StatusDeleted,
 		"deleted_at": now.Format(time.RFC3339),
 		"deleted_by": deletedBy,
 		"updated_at": now.Format(time.RFC3339),
@@ -433,17 +662,13 @@ func (s *SpaceService) GetSpaceNotebooks(ctx context.Context, spaceID string, li
 		zap.Int("offset", offset),
 	)
 
-	if limit <= 0 || limit > 100 {
-		limit = 20
-	}
-	if offset < 0 {
-		offset = 0
-	}
+	limit, offset = pagination.Clamp(limit, offset)
 
 	// Get notebooks via BELONGS_TO relationship
 	query := `
 		MATCH (n:Notebook)-[:BELONGS_TO]->(sp:Space {id: $space_id})
 		WHERE n.status = 'active'
+		  AND ` + database.SoftDeleteFilter(ctx, "n") + `
 		OPTIONAL MATCH (n)-[:OWNED_BY]->(owner:User)
 		RETURN n.id, n.name, n.description, n.visibility, n.status, n.owner_id,
 		       n.document_count, n.total_size_bytes, n.tags,
@@ -480,6 +705,7 @@ func (s *SpaceService) GetSpaceNotebooks(ctx context.Context, spaceID string, li
 	countQuery := `
 		MATCH (n:Notebook)-[:BELONGS_TO]->(sp:Space {id: $space_id})
 		WHERE n.status = 'active'
+		  AND ` + database.SoftDeleteFilter(ctx, "n") + `
 		RETURN count(n) as total
 	`
 
@@ -547,6 +773,25 @@ func (s *SpaceService) recordToSpace(record interface{}) (*models.Space, error)
 	if val, ok := r.Get("sp.deleted_by"); ok && val != nil {
 		space.DeletedBy = val.(string)
 	}
+	// Settings are stored as a JSON string
+	if val, ok := r.Get("sp.settings"); ok && val != nil {
+		if settings, ok := val.(string); ok && settings != "" {
+			if err := json.Unmarshal([]byte(settings), &space.Settings); err != nil {
+				s.logger.Warn("Failed to parse space settings", zap.String("space_id", space.ID), zap.Error(err))
+			}
+		}
+	}
+	// Quota limits set for the space are stored as a JSON string over the
+	// defaults of its type
+	stored := ""
+	if val, ok := r.Get("sp.quotas"); ok && val != nil {
+		stored, _ = val.(string)
+	}
+	quotas, err := spaceQuotas(space.Type, stored)
+	if err != nil {
+		s.logger.Warn("Failed to parse space quotas", zap.String("space_id", space.ID), zap.Error(err))
+	}
+	space.Quotas = quotas
 
 	// Parse timestamps
 	if val, ok := r.Get("sp.created_at"); ok && val != nil {
@@ -601,7 +846,7 @@ func (s *SpaceService) recordToSpaceInfo(record interface{}) (*models.SpaceInfo,
 	}
 
 	// Map role to permissions
-	spaceInfo.Permissions = s.getRolePermissions(spaceInfo.UserRole)
+	spaceInfo.Permissions = s.authz.SpacePermissions(spaceInfo.OrganizationID, spaceInfo.UserRole)
 
 	return spaceInfo, nil
 }
@@ -700,22 +945,6 @@ func (s *SpaceService) recordToNotebookResponse(record interface{}) (*models.Not
 	return notebook, nil
 }
 
-// getRolePermissions maps roles to permissions
-func (s *SpaceService) getRolePermissions(role string) []string {
-	switch role {
-	case "owner":
-		return []string{"read", "write", "create", "update", "delete", "admin"}
-	case "admin":
-		return []string{"read", "write", "create", "update", "delete"}
-	case "member":
-		return []string{"read", "write", "create", "update"}
-	case "viewer":
-		return []string{"read"}
-	default:
-		return []string{"read"}
-	}
-}
-
 // =============================================================================
 // MEMBER_OF Relationship Methods
 // =============================================================================
@@ -752,7 +981,7 @@ func (s *SpaceService) AddMember(ctx context.Context, spaceID, userID, role, inv
 	}
 
 	// Verify space exists
-	_, err = s.GetSpaceByID(ctx, spaceID)
+	space, err := s.GetSpaceByID(ctx, spaceID)
 	if err != nil {
 		return err
 	}
@@ -773,7 +1002,7 @@ func (s *SpaceService) AddMember(ctx context.Context, spaceID, userID, role, inv
 		"user_id":     userID,
 		"space_id":    spaceID,
 		"role":        role,
-		"permissions": s.getRolePermissions(role),
+		"permissions": s.authz.SpacePermissions("", role),
 		"invited_by":  invitedBy,
 	}
 
@@ -800,6 +1029,18 @@ func (s *SpaceService) AddMember(ctx context.Context, spaceID, userID, role, inv
 		zap.String("role", role),
 	)
 
+	publishDomainEvent(ctx, s.events, s.logger, Event{
+		Type:    EventMemberAdded,
+		Subject: userID,
+		UserID:  invitedBy,
+		Data: map[string]interface{}{
+			"space_id":   spaceID,
+			"tenant_id":  space.TenantID,
+			"user_id":    userID,
+			"role":       role,
+			"invited_by": invitedBy,
+		},
+	})
 	return nil
 }
 
@@ -811,12 +1052,7 @@ func (s *SpaceService) GetSpaceMembers(ctx context.Context, spaceID string, limi
 		zap.Int("offset", offset),
 	)
 
-	if limit <= 0 || limit > 100 {
-		limit = 20
-	}
-	if offset < 0 {
-		offset = 0
-	}
+	limit, offset = pagination.Clamp(limit, offset)
 
 	// Get all members including owner (via OWNS) and members (via MEMBER_OF)
 	query := `
@@ -914,7 +1150,7 @@ func (s *SpaceService) GetSpaceMembers(ctx context.Context, spaceID string, limi
 		Total:   total,
 		Limit:   limit,
 		Offset:  offset,
-		HasMore: offset+len(members) < total,
+		HasMore: pagination.HasMore(offset, len(members), total),
 	}, nil
 }
 
@@ -965,7 +1201,7 @@ func (s *SpaceService) UpdateMemberRole(ctx context.Context, spaceID, userID, ne
 		"user_id":     userID,
 		"space_id":    spaceID,
 		"new_role":    newRole,
-		"permissions": s.getRolePermissions(newRole),
+		"permissions": s.authz.SpacePermissions("", newRole),
 	}
 
 	result, err := s.neo4j.ExecuteQueryWithLogging(ctx, query, params)
@@ -1113,7 +1349,7 @@ func (s *SpaceService) recordToSpaceMember(record interface{}, spaceID string) (
 	}
 
 	// Set permissions based on role
-	member.Permissions = s.getRolePermissions(member.Role)
+	member.Permissions = s.authz.SpacePermissions("", member.Role)
 
 	return member, nil
 }
@@ -1143,36 +1379,45 @@ type ConsistencyCheckResult struct {
 	OrphanedSpaces             int                    `json:"orphaned_spaces"`
 	UsersWithoutOwnsRelation   int                    `json:"users_without_owns_relation"`
 	Inconsistencies            []*InconsistencyReport `json:"inconsistencies"`
+	Limit                      int                    `json:"limit"`     // Inconsistencies reported per entity type
+	Truncated                  bool                   `json:"truncated"` // More inconsistencies exist than were reported
 	CheckedAt                  time.Time              `json:"checked_at"`
 }
 
 // CheckConsistency performs a consistency check between embedded fields and relationships
 // This helps detect drift in the hybrid model (embedded fields + relationships)
-func (s *SpaceService) CheckConsistency(ctx context.Context) (*ConsistencyCheckResult, error) {
-	s.logger.Info("Running consistency check")
+// At most limit inconsistencies are reported per entity type, so the check
+// stays bounded on large graphs; Truncated reports that more exist.
+func (s *SpaceService) CheckConsistency(ctx context.Context, limit int) (*ConsistencyCheckResult, error) {
+	limit, _ = pagination.Clamp(limit, 0)
+	s.logger.Info("Running consistency check", zap.Int("limit", limit))
 
 	result := &ConsistencyCheckResult{
 		Inconsistencies: make([]*InconsistencyReport, 0),
+		Limit:           limit,
 		CheckedAt:       time.Now(),
 	}
 
 	// Check notebook consistency
-	notebookInconsistencies, err := s.checkNotebookConsistency(ctx)
+	notebookInconsistencies, err := s.checkNotebookConsistency(ctx, limit+1)
 	if err != nil {
 		s.logger.Error("Failed to check notebook consistency", zap.Error(err))
 		return nil, err
 	}
+	notebookInconsistencies, notebooksTruncated := pagination.Trim(notebookInconsistencies, limit)
 	result.Inconsistencies = append(result.Inconsistencies, notebookInconsistencies...)
 	result.InconsistentNotebooks = len(notebookInconsistencies)
 
 	// Check user-space consistency
-	userInconsistencies, err := s.checkUserSpaceConsistency(ctx)
+	userInconsistencies, err := s.checkUserSpaceConsistency(ctx, limit+1)
 	if err != nil {
 		s.logger.Error("Failed to check user-space consistency", zap.Error(err))
 		return nil, err
 	}
+	userInconsistencies, usersTruncated := pagination.Trim(userInconsistencies, limit)
 	result.Inconsistencies = append(result.Inconsistencies, userInconsistencies...)
 	result.InconsistentUsers = len(userInconsistencies)
+	result.Truncated = notebooksTruncated || usersTruncated
 
 	// Count orphaned entities
 	orphanCounts, err := s.countOrphanedEntities(ctx)
@@ -1205,7 +1450,7 @@ func (s *SpaceService) CheckConsistency(ctx context.Context) (*ConsistencyCheckR
 }
 
 // checkNotebookConsistency checks if notebook embedded fields match their BELONGS_TO relationship
-func (s *SpaceService) checkNotebookConsistency(ctx context.Context) ([]*InconsistencyReport, error) {
+func (s *SpaceService) checkNotebookConsistency(ctx context.Context, limit int) ([]*InconsistencyReport, error) {
 	query := `
 		MATCH (n:Notebook)
 		WHERE n.space_id IS NOT NULL
@@ -1225,9 +1470,10 @@ func (s *SpaceService) checkNotebookConsistency(ctx context.Context) ([]*Inconsi
 		           WHEN n.tenant_id <> s.tenant_id THEN 'Tenant ID mismatch'
 		           ELSE 'Unknown'
 		       END as issue
+		LIMIT $limit
 	`
 
-	result, err := s.neo4j.ExecuteQueryWithLogging(ctx, query, nil)
+	result, err := s.neo4j.ExecuteQueryWithLogging(ctx, query, map[string]interface{}{"limit": limit})
 	if err != nil {
 		return nil, errors.Database("Failed to check notebook consistency", err)
 	}
@@ -1264,7 +1510,7 @@ func (s *SpaceService) checkNotebookConsistency(ctx context.Context) ([]*Inconsi
 }
 
 // checkUserSpaceConsistency checks if user personal_space_id matches their OWNS relationship
-func (s *SpaceService) checkUserSpaceConsistency(ctx context.Context) ([]*InconsistencyReport, error) {
+func (s *SpaceService) checkUserSpaceConsistency(ctx context.Context, limit int) ([]*InconsistencyReport, error) {
 	query := `
 		MATCH (u:User)
 		WHERE u.personal_space_id IS NOT NULL
@@ -1281,9 +1527,10 @@ func (s *SpaceService) checkUserSpaceConsistency(ctx context.Context) ([]*Incons
 		           WHEN u.personal_space_id <> s.id THEN 'Personal space ID mismatch'
 		           ELSE 'Unknown'
 		       END as issue
+		LIMIT $limit
 	`
 
-	result, err := s.neo4j.ExecuteQueryWithLogging(ctx, query, nil)
+	result, err := s.neo4j.ExecuteQueryWithLogging(ctx, query, map[string]interface{}{"limit": limit})
 	if err != nil {
 		return nil, errors.Database("Failed to check user-space consistency", err)
 	}
@@ -1341,7 +1588,7 @@ func (s *SpaceService) countOrphanedEntities(ctx context.Context) (map[string]in
 
 	// Count orphaned personal spaces (no OWNS relationship)
 	spaceQuery := `
-		MATCH (s:Space {type: "personal"})
+		MATCH (s:Space {space_type: "personal"})
 		WHERE NOT EXISTS { (:User)-[:OWNS]->(s) }
 		RETURN count(s) as count
 	`
@@ -1411,6 +1658,172 @@ func (s *SpaceService) getTotals(ctx context.Context) (map[string]int, error) {
 	return totals, nil
 }
 
+// ConsistencyRepairResult reports what a consistency repair changed, or
+// would change in a dry run
+type ConsistencyRepairResult struct {
+	DryRun                       bool      `json:"dry_run"`
+	NotebookFieldsUpdated        int       `json:"notebook_fields_updated"`        // Embedded IDs reset from BELONGS_TO
+	NotebookRelationshipsCreated int       `json:"notebook_relationships_created"` // BELONGS_TO created from the embedded space ID
+	UserRelationshipsCreated     int       `json:"user_relationships_created"`     // OWNS created from personal_space_id
+	Unrepairable                 int       `json:"unrepairable"`                   // Entities whose embedded space does not exist
+	RepairedAt                   time.Time `json:"repaired_at"`
+}
+
+// consistencyRepairs are the repair steps: each matches the entities to
+// fix and, unless the repair is a dry run, applies the fix to them
+var consistencyRepairs = []struct {
+	match string
+	fix   string
+	count func(*ConsistencyRepairResult, int)
+}{
+	{
+		// BELONGS_TO is what access checks traverse, so it wins over the
+		// embedded fields
+		match: `MATCH (n:Notebook)-[:BELONGS_TO]->(s:Space)
+		        WHERE n.space_id <> s.id OR (s.tenant_id IS NOT NULL AND coalesce(n.tenant_id, '') <> s.tenant_id)`,
+		fix:   `SET n.space_id = s.id, n.tenant_id = s.tenant_id, n.updated_at = datetime()`,
+		count: func(r *ConsistencyRepairResult, n int) { r.NotebookFieldsUpdated = n },
+	},
+	{
+		match: `MATCH (n:Notebook)
+		        WHERE n.space_id IS NOT NULL AND NOT EXISTS { (n)-[:BELONGS_TO]->(:Space) }
+		        MATCH (s:Space {id: n.space_id})`,
+		fix:   `MERGE (n)-[r:BELONGS_TO]->(s) ON CREATE SET r.created_at = datetime()`,
+		count: func(r *ConsistencyRepairResult, n int) { r.NotebookRelationshipsCreated = n },
+	},
+	{
+		match: `MATCH (u:User)
+		        WHERE u.personal_space_id IS NOT NULL
+		          AND NOT EXISTS { (u)-[:OWNS]->(:Space {id: u.personal_space_id}) }
+		        MATCH (s:Space {id: u.personal_space_id})`,
+		fix:   `MERGE (u)-[r:OWNS]->(s) ON CREATE SET r.created_at = datetime()`,
+		count: func(r *ConsistencyRepairResult, n int) { r.UserRelationshipsCreated = n },
+	},
+}
+
+// unrepairableQuery counts notebooks and users whose embedded space does
+// not exist, which a repair cannot fix
+const unrepairableQuery = `
+	CALL {
+		MATCH (n:Notebook)
+		WHERE n.space_id IS NOT NULL AND NOT EXISTS { (n)-[:BELONGS_TO]->(:Space) }
+		  AND NOT EXISTS { MATCH (s:Space {id: n.space_id}) }
+		RETURN count(n) as entities
+		UNION ALL
+		MATCH (u:User)
+		WHERE u.personal_space_id IS NOT NULL
+		  AND NOT EXISTS { MATCH (s:Space {id: u.personal_space_id}) }
+		RETURN count(u) as entities
+	}
+	RETURN sum(entities) as count
+`
+
+// RepairConsistency fixes the drift CheckConsistency reports: embedded
+// space and tenant IDs that disagree with BELONGS_TO are reset from it, and
+// missing BELONGS_TO and OWNS relationships are created from the embedded
+// IDs. A dry run only counts what would change.
+func (s *SpaceService) RepairConsistency(ctx context.Context, dryRun bool) (*ConsistencyRepairResult, error) {
+	result := &ConsistencyRepairResult{DryRun: dryRun, RepairedAt: time.Now()}
+
+	for _, repair := range consistencyRepairs {
+		query := repair.match
+		if !dryRun {
+			query += "\n" + repair.fix
+		}
+		count, err := s.countQuery(ctx, query+"\nRETURN count(*) as count")
+		if err != nil {
+			return nil, errors.Database("Failed to repair graph consistency", err)
+		}
+		repair.count(result, count)
+	}
+
+	unrepairable, err := s.countQuery(ctx, unrepairableQuery)
+	if err != nil {
+		return nil, errors.Database("Failed to repair graph consistency", err)
+	}
+	result.Unrepairable = unrepairable
+
+	s.logger.Info("Consistency repair complete",
+		zap.Bool("dry_run", dryRun),
+		zap.Int("notebook_fields_updated", result.NotebookFieldsUpdated),
+		zap.Int("notebook_relationships_created", result.NotebookRelationshipsCreated),
+		zap.Int("user_relationships_created", result.UserRelationshipsCreated),
+		zap.Int("unrepairable", result.Unrepairable),
+	)
+	return result, nil
+}
+
+// OrphanCleanupResult reports what an orphan cleanup deleted, or would
+// delete in a dry run
+type OrphanCleanupResult struct {
+	DryRun           bool      `json:"dry_run"`
+	SpacesDeleted    int       `json:"spaces_deleted"`     // Empty personal spaces nobody owns
+	RetryJobsDeleted int       `json:"retry_jobs_deleted"` // Retry jobs of deleted documents
+	CleanedAt        time.Time `json:"cleaned_at"`
+}
+
+// orphanedSpacesMatch matches personal spaces that no user owns or names
+// as their personal space and that hold nothing. Spaces a user still names
+// are left for RepairConsistency to reattach.
+const orphanedSpacesMatch = `
+	MATCH (s:Space {space_type: "personal"})
+	WHERE NOT EXISTS { (:User)-[:OWNS]->(s) }
+	  AND NOT EXISTS { MATCH (u:User {personal_space_id: s.id}) }
+	  AND NOT EXISTS { ()-[:BELONGS_TO]->(s) }
+`
+
+// orphanedRetryJobsMatch matches retry jobs whose document no longer exists
+const orphanedRetryJobsMatch = `
+	MATCH (j:ProcessingRetryJob)
+	WHERE NOT EXISTS { MATCH (d:Document {id: j.document_id}) }
+`
+
+// CleanupOrphans deletes empty personal spaces without an owner and the
+// retry jobs of deleted documents. A dry run only counts them.
+func (s *SpaceService) CleanupOrphans(ctx context.Context, dryRun bool) (*OrphanCleanupResult, error) {
+	result := &OrphanCleanupResult{DryRun: dryRun, CleanedAt: time.Now()}
+
+	deletes := []struct {
+		match, variable string
+		count           *int
+	}{
+		{orphanedSpacesMatch, "s", &result.SpacesDeleted},
+		{orphanedRetryJobsMatch, "j", &result.RetryJobsDeleted},
+	}
+	for _, d := range deletes {
+		query := d.match + "\nWITH collect(" + d.variable + ") as orphans"
+		if !dryRun {
+			query += "\nFOREACH (o IN orphans | DETACH DELETE o)"
+		}
+		count, err := s.countQuery(ctx, query+"\nRETURN size(orphans) as count")
+		if err != nil {
+			return nil, errors.Database("Failed to clean up orphaned entities", err)
+		}
+		*d.count = count
+	}
+
+	s.logger.Info("Orphan cleanup complete",
+		zap.Bool("dry_run", dryRun),
+		zap.Int("spaces_deleted", result.SpacesDeleted),
+		zap.Int("retry_jobs_deleted", result.RetryJobsDeleted),
+	)
+	return result, nil
+}
+
+// countQuery runs a query returning a single count column
+func (s *SpaceService) countQuery(ctx context.Context, query string) (int, error) {
+	result, err := s.neo4j.ExecuteQueryWithLogging(ctx, query, nil)
+	if err != nil {
+		return 0, err
+	}
+	if len(result.Records) == 0 {
+		return 0, nil
+	}
+	value, _ := result.Records[0].Get("count")
+	count, _ := value.(int64)
+	return int(count), nil
+}
+
 // CheckUserSpaceAccess checks if a user has access to a space and returns their role
 // It checks for:
 // 1. Direct OWNS relationship (owner)
@@ -1429,6 +1842,7 @@ func (s *SpaceService) CheckUserSpaceAccess(ctx context.Context, userID, spaceID
 	// 3. Space is owned by an organization that the user is a member of
 	query := `
 		MATCH (sp:Space {id: $space_id})
+		WHERE ` + database.SoftDeleteFilter(ctx, "sp") + `
 		MATCH (u:User)
 		WHERE u.keycloak_id = $user_id OR u.id = $user_id
 
@@ -18,6 +18,7 @@ type SpaceContextService struct {
 	orgService   *OrganizationService
 	spaceService *SpaceService
 	audiModal    *AudiModalService
+	authz        *AuthorizationService
 	logger       *logger.Logger
 }
 
@@ -32,6 +33,12 @@ func NewSpaceContextService(userService *UserService, orgService *OrganizationSe
 	}
 }
 
+// SetAuthorizationService resolves the permissions of custom organization
+// roles; without it only the built-in roles grant permissions
+func (s *SpaceContextService) SetAuthorizationService(authz *AuthorizationService) {
+	s.authz = authz
+}
+
 // ResolveSpaceContext resolves a space context for a user
 func (s *SpaceContextService) ResolveSpaceContext(ctx context.Context, userID string, req models.SpaceContextRequest) (*models.SpaceContext, error) {
 	var spaceContext *models.SpaceContext
@@ -102,11 +109,13 @@ func (s *SpaceContextService) resolvePersonalSpace(ctx context.Context, userID,
 		SpaceID:     spaceID,
 		TenantID:    tenantID,
 		APIKey:      apiKey,
+		Plan:        "personal",
 		UserID:      userID,
 		UserRole:    "owner",
 		SpaceName:   fmt.Sprintf("%s's Personal Space", user.FullName),
 		ResolvedAt:  time.Now(),
 		Permissions: []string{"read", "write", "create", "update", "delete"},
+		Authorizer:  s.authz,
 	}, nil
 }
 
@@ -142,25 +151,10 @@ func (s *SpaceContextService) resolveOrganizationSpaceFromOrg(ctx context.Contex
 		})
 	}
 
-	// Check user membership
-	members, err := s.orgService.GetOrganizationMembers(ctx, org.ID, userID)
-	if err != nil || len(members) == 0 {
-		return nil, errors.ForbiddenWithDetails("User is not a member of this organization", map[string]interface{}{
-			"user_id": userID,
-			"org_id":  org.ID,
-		})
-	}
-
-	// Find the user's member record
-	var member *models.OrganizationMember
-	for _, m := range members {
-		if m.UserID == userID {
-			member = m
-			break
-		}
-	}
-
-	if member == nil {
+	// Check user membership; only the user's own membership is read, so
+	// large organizations cost no more to resolve than small ones
+	role, err := s.orgService.GetUserRoleInOrganization(ctx, org.ID, userID)
+	if err != nil || role == "" {
 		return nil, errors.ForbiddenWithDetails("User is not a member of this organization", map[string]interface{}{
 			"user_id": userID,
 			"org_id":  org.ID,
@@ -168,18 +162,21 @@ func (s *SpaceContextService) resolveOrganizationSpaceFromOrg(ctx context.Contex
 	}
 
 	// Map member role to permissions
-	permissions := s.getRolePermissions(member.Role)
+	permissions := s.authz.SpacePermissions(org.ID, role)
 
 	return &models.SpaceContext{
-		SpaceType:   models.SpaceTypeOrganization,
-		SpaceID:     org.ID,
-		TenantID:    org.TenantID,
-		APIKey:      org.TenantAPIKey,
-		UserID:      userID,
-		UserRole:    member.Role,
-		SpaceName:   org.Name,
-		ResolvedAt:  time.Now(),
-		Permissions: permissions,
+		SpaceType:      models.SpaceTypeOrganization,
+		SpaceID:        org.ID,
+		TenantID:       org.TenantID,
+		APIKey:         org.TenantAPIKey,
+		Plan:           org.BillingPlan(),
+		OrganizationID: org.ID,
+		UserID:         userID,
+		UserRole:       role,
+		SpaceName:      org.Name,
+		ResolvedAt:     time.Now(),
+		Permissions:    permissions,
+		Authorizer:     s.authz,
 	}, nil
 }
 
@@ -223,19 +220,28 @@ func (s *SpaceContextService) resolveOrganizationSpaceFromSpaceNode(ctx context.
 		})
 	}
 
-	// Map role to permissions
-	permissions := s.getRolePermissions(role)
+	// Map role to permissions; custom roles are those of the organization
+	// owning the space
+	var orgID string
+	if space.OwnerType == models.SpaceOwnerTypeOrganization {
+		orgID = space.OwnerID
+	}
+	permissions := s.authz.SpacePermissions(orgID, role)
 
+	// The plan is left unset since the owning organization is not loaded;
+	// the default processing rate limit applies
 	return &models.SpaceContext{
-		SpaceType:   models.SpaceTypeOrganization,
-		SpaceID:     spaceID,
-		TenantID:    space.TenantID,
-		APIKey:      "", // Space nodes may not have API keys directly
-		UserID:      userID,
-		UserRole:    role,
-		SpaceName:   space.Name,
-		ResolvedAt:  time.Now(),
-		Permissions: permissions,
+		SpaceType:      models.SpaceTypeOrganization,
+		SpaceID:        spaceID,
+		TenantID:       space.TenantID,
+		APIKey:         "", // Space nodes may not have API keys directly
+		OrganizationID: orgID,
+		UserID:         userID,
+		UserRole:       role,
+		SpaceName:      space.Name,
+		ResolvedAt:     time.Now(),
+		Permissions:    permissions,
+		Authorizer:     s.authz,
 	}, nil
 }
 
@@ -346,25 +352,12 @@ func (s *SpaceContextService) GetUserSpaces(ctx context.Context, userID string)
 	for _, org := range orgs {
 		if org.HasTenant() {
 			// Get the user's role in this organization
-			members, err := s.orgService.GetOrganizationMembers(ctx, org.ID, userID)
-			if err != nil || len(members) == 0 {
+			userRole, err := s.orgService.GetUserRoleInOrganization(ctx, org.ID, userID)
+			if err != nil || userRole == "" {
 				continue
 			}
 			
-			// Find user's role
-			var userRole string
-			for _, member := range members {
-				if member.UserID == userID {
-					userRole = member.Role
-					break
-				}
-			}
-			
-			if userRole == "" {
-				continue
-			}
-			
-			permissions := s.getRolePermissions(userRole)
+			permissions := s.authz.SpacePermissions(org.ID, userRole)
 			response.OrganizationSpaces = append(response.OrganizationSpaces, &models.SpaceInfo{
 				SpaceType:   models.SpaceTypeOrganization,
 				SpaceID:     org.ID,
@@ -422,22 +415,6 @@ func (s *SpaceContextService) ValidateSpaceAccess(ctx context.Context, userID st
 	return err
 }
 
-// getRolePermissions maps organization roles to permissions
-func (s *SpaceContextService) getRolePermissions(role string) []string {
-	switch role {
-	case "owner":
-		return []string{"read", "write", "create", "update", "delete", "admin"}
-	case "admin":
-		return []string{"read", "write", "create", "update", "delete"}
-	case "member":
-		return []string{"read", "write", "create", "update"}
-	case "viewer":
-		return []string{"read"}
-	default:
-		return []string{"read"}
-	}
-}
-
 // Cache helpers
 
 // Redis caching methods removed - no longer using Redis
\ No newline at end of file
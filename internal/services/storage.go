@@ -5,10 +5,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
 	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
@@ -18,14 +20,17 @@ import (
 
 	appConfig "github.com/Tributary-ai-services/aether-be/internal/config"
 	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/metrics"
 )
 
 // S3StorageService implements StorageService for AWS S3/MinIO
 type S3StorageService struct {
-	client *s3.Client
-	bucket string
-	logger *logger.Logger
-	config appConfig.StorageConfig
+	client    *s3.Client
+	transport *metrics.ExternalTransport
+	metrics   *metrics.Metrics
+	bucket    string
+	logger    *logger.Logger
+	config    appConfig.StorageConfig
 }
 
 // NewS3StorageService creates a new S3 storage service
@@ -48,8 +53,17 @@ func NewS3StorageService(cfg appConfig.StorageConfig, log *logger.Logger) (*S3St
 		})
 	}
 
+	// Each SDK attempt passes through the transport, so retries are counted
+	transport := metrics.NewExternalTransport("s3", awshttp.NewBuildableClient().GetTransport(), metrics.ExternalTransportOptions{
+		Operation: func(req *http.Request) string {
+			return awsmiddleware.GetOperationName(req.Context())
+		},
+		Attempt: metrics.AWSAttempt,
+	})
+
 	// Create S3 client
 	s3Client := s3.NewFromConfig(awsConfig, func(o *s3.Options) {
+		o.HTTPClient = &http.Client{Transport: transport}
 		if cfg.Endpoint != "" {
 			o.BaseEndpoint = aws.String(cfg.Endpoint)
 			o.UsePathStyle = true // Required for MinIO
@@ -61,10 +75,11 @@ func NewS3StorageService(cfg appConfig.StorageConfig, log *logger.Logger) (*S3St
 	})
 
 	service := &S3StorageService{
-		client: s3Client,
-		bucket: cfg.Bucket,
-		logger: log.WithService("s3_storage"),
-		config: cfg,
+		client:    s3Client,
+		transport: transport,
+		bucket:    cfg.Bucket,
+		logger:    log.WithService("s3_storage"),
+		config:    cfg,
 	}
 
 	// Test connection
@@ -84,6 +99,12 @@ func NewS3StorageService(cfg appConfig.StorageConfig, log *logger.Logger) (*S3St
 	return service, nil
 }
 
+// SetMetrics records metrics for calls to S3 and per-tenant storage traffic
+func (s *S3StorageService) SetMetrics(m *metrics.Metrics) {
+	s.transport.SetMetrics(m)
+	s.metrics = m
+}
+
 // UploadFile uploads a file to S3
 func (s *S3StorageService) UploadFile(ctx context.Context, key string, data []byte, contentType string) (string, error) {
 	start := time.Now()
@@ -629,6 +650,10 @@ func (s *S3StorageService) UploadFileToTenantBucket(ctx context.Context, tenantI
 		return "", fmt.Errorf("failed to upload file: %w", err)
 	}
 
+	if s.metrics != nil {
+		s.metrics.RecordTenantStorageBytes(tenantID, "upload", int64(len(data)))
+	}
+
 	s.logger.Info("File uploaded to tenant bucket successfully",
 		zap.String("key", key),
 		zap.String("bucket", bucketName),
@@ -694,6 +719,10 @@ func (s *S3StorageService) DownloadFileFromTenantBucket(ctx context.Context, ten
 	}
 
 	data := buf.Bytes()
+	if s.metrics != nil {
+		s.metrics.RecordTenantStorageBytes(tenantID, "download", int64(len(data)))
+	}
+
 	s.logger.Info("File downloaded from tenant bucket successfully",
 		zap.String("key", key),
 		zap.String("bucket", bucketName),
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"go.uber.org/zap"
+)
+
+// Outcomes of a conditional or partial read that are not failures
+var (
+	// ErrFileNotModified is returned when a file still has the ETag a
+	// conditional read named
+	ErrFileNotModified = errors.New("file not modified")
+	// ErrRangeNotSatisfiable is returned when a byte range starts past the
+	// end of a file
+	ErrRangeNotSatisfiable = errors.New("byte range not satisfiable")
+)
+
+// FileReadOptions make a read of a file partial or conditional
+type FileReadOptions struct {
+	// Range is a single byte range as in an HTTP Range header, such as
+	// "bytes=0-1023"; empty reads the whole file
+	Range string
+	// IfRange is an ETag the file must still have for Range to apply;
+	// otherwise the whole file is read
+	IfRange string
+	// IfNoneMatch is an ETag; a file that still has it is not read
+	IfNoneMatch string
+}
+
+// FileReader is a file, or a byte range of it, opened for reading. The
+// caller closes Body.
+type FileReader struct {
+	Body          io.ReadCloser
+	ContentLength int64 // Bytes Body reads
+	// ContentRange is the range read, as in a Content-Range header, or
+	// empty when the whole file is read
+	ContentRange string
+	ETag         string
+	LastModified time.Time
+}
+
+// ReadTenantFile opens a file of a tenant's bucket, or a byte range of it,
+// for reading without loading it into memory. It returns
+// ErrFileNotModified and ErrRangeNotSatisfiable for the reads those name.
+func (s *S3StorageService) ReadTenantFile(ctx context.Context, tenantID, key string, opts FileReadOptions) (*FileReader, error) {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(tenantBucketName(tenantID)),
+		Key:    aws.String(key),
+	}
+	if opts.Range != "" {
+		input.Range = aws.String(opts.Range)
+		if opts.IfRange != "" {
+			input.IfMatch = aws.String(opts.IfRange)
+		}
+	}
+	if opts.IfNoneMatch != "" {
+		input.IfNoneMatch = aws.String(opts.IfNoneMatch)
+	}
+
+	output, err := s.client.GetObject(ctx, input)
+	if err != nil {
+		var responseErr *awshttp.ResponseError
+		if errors.As(err, &responseErr) {
+			switch responseErr.HTTPStatusCode() {
+			case http.StatusNotModified:
+				return nil, ErrFileNotModified
+			case http.StatusRequestedRangeNotSatisfiable:
+				return nil, ErrRangeNotSatisfiable
+			case http.StatusPreconditionFailed:
+				if opts.IfRange != "" {
+					// The file changed since the client read the rest of it
+					opts.Range, opts.IfRange = "", ""
+					return s.ReadTenantFile(ctx, tenantID, key, opts)
+				}
+			}
+		}
+		return nil, fmt.Errorf("failed to read file in tenant bucket: %w", err)
+	}
+
+	return &FileReader{
+		Body:          output.Body,
+		ContentLength: aws.ToInt64(output.ContentLength),
+		ContentRange:  aws.ToString(output.ContentRange),
+		ETag:          aws.ToString(output.ETag),
+		LastModified:  aws.ToTime(output.LastModified),
+	}, nil
+}
+
+// PresignTenantDownload returns a URL a client can GET a file of a
+// tenant's bucket from, saving it as fileName, until it expires
+func (s *S3StorageService) PresignTenantDownload(ctx context.Context, tenantID, key, fileName string, expiration time.Duration) (string, error) {
+	bucketName := tenantBucketName(tenantID)
+	result, err := s3.NewPresignClient(s.client).PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket:                     aws.String(bucketName),
+		Key:                        aws.String(key),
+		ResponseContentDisposition: aws.String("attachment; filename=\"" + fileName + "\""),
+	}, func(opts *s3.PresignOptions) {
+		opts.Expires = expiration
+	})
+	if err != nil {
+		s.logger.Error("Failed to presign download",
+			zap.String("bucket", bucketName),
+			zap.String("key", key),
+			zap.Error(err))
+		return "", fmt.Errorf("failed to presign download: %w", err)
+	}
+	return result.URL, nil
+}
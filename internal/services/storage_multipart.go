@@ -0,0 +1,142 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+// tenantBucketName returns the bucket of a tenant's files
+func tenantBucketName(tenantID string) string {
+	return fmt.Sprintf("aether-%s", extractTenantSuffix(tenantID))
+}
+
+// CreateMultipartUpload starts a multipart upload of key into a tenant's
+// bucket and returns its upload ID
+func (s *S3StorageService) CreateMultipartUpload(ctx context.Context, tenantID, key, contentType string) (string, error) {
+	bucketName := tenantBucketName(tenantID)
+	if err := s.ensureBucketExists(ctx, bucketName); err != nil {
+		return "", fmt.Errorf("failed to ensure bucket exists: %w", err)
+	}
+
+	output, err := s.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(bucketName),
+		Key:         aws.String(key),
+		ContentType: aws.String(contentType),
+		Metadata: map[string]string{
+			"uploaded-by": "aether-backend",
+			"upload-time": time.Now().Format(time.RFC3339),
+			"tenant-id":   tenantID,
+		},
+	})
+	if err != nil {
+		s.logger.Error("Failed to create multipart upload",
+			zap.String("bucket", bucketName),
+			zap.String("key", key),
+			zap.Error(err))
+		return "", fmt.Errorf("failed to create multipart upload: %w", err)
+	}
+	return aws.ToString(output.UploadId), nil
+}
+
+// UploadPart stores one part of a multipart upload and returns its ETag.
+// Sending a part again replaces it.
+func (s *S3StorageService) UploadPart(ctx context.Context, tenantID, key, uploadID string, partNumber int, data []byte) (string, error) {
+	bucketName := tenantBucketName(tenantID)
+	output, err := s.client.UploadPart(ctx, &s3.UploadPartInput{
+		Bucket:        aws.String(bucketName),
+		Key:           aws.String(key),
+		UploadId:      aws.String(uploadID),
+		PartNumber:    aws.Int32(int32(partNumber)),
+		Body:          bytes.NewReader(data),
+		ContentLength: aws.Int64(int64(len(data))),
+	})
+	if err != nil {
+		s.logger.Error("Failed to upload part",
+			zap.String("bucket", bucketName),
+			zap.String("key", key),
+			zap.Int("part_number", partNumber),
+			zap.Error(err))
+		return "", fmt.Errorf("failed to upload part %d: %w", partNumber, err)
+	}
+
+	if s.metrics != nil {
+		s.metrics.RecordTenantStorageBytes(tenantID, "upload", int64(len(data)))
+	}
+	return aws.ToString(output.ETag), nil
+}
+
+// CompleteMultipartUpload assembles the parts of a multipart upload into
+// its object and returns the object's path as bucket:key
+func (s *S3StorageService) CompleteMultipartUpload(ctx context.Context, tenantID, key, uploadID string, parts []*models.UploadSessionPart) (string, error) {
+	bucketName := tenantBucketName(tenantID)
+
+	completed := make([]types.CompletedPart, 0, len(parts))
+	for _, part := range parts {
+		completed = append(completed, types.CompletedPart{
+			ETag:       aws.String(part.ETag),
+			PartNumber: aws.Int32(int32(part.Number)),
+		})
+	}
+	sort.Slice(completed, func(i, j int) bool {
+		return aws.ToInt32(completed[i].PartNumber) < aws.ToInt32(completed[j].PartNumber)
+	})
+
+	_, err := s.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(bucketName),
+		Key:             aws.String(key),
+		UploadId:        aws.String(uploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: completed},
+	})
+	if err != nil {
+		s.logger.Error("Failed to complete multipart upload",
+			zap.String("bucket", bucketName),
+			zap.String("key", key),
+			zap.Int("parts", len(parts)),
+			zap.Error(err))
+		return "", fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+
+	s.logger.Info("Multipart upload completed",
+		zap.String("bucket", bucketName),
+		zap.String("key", key),
+		zap.Int("parts", len(parts)))
+	return fmt.Sprintf("%s:%s", bucketName, key), nil
+}
+
+// AbortMultipartUpload discards a multipart upload and the parts stored
+// for it
+func (s *S3StorageService) AbortMultipartUpload(ctx context.Context, tenantID, key, uploadID string) error {
+	_, err := s.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(tenantBucketName(tenantID)),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to abort multipart upload: %w", err)
+	}
+	return nil
+}
+
+// OpenFileFromTenantBucket opens a file of a tenant's bucket for reading
+// without loading it into memory. The caller closes it.
+func (s *S3StorageService) OpenFileFromTenantBucket(ctx context.Context, tenantID, key string) (io.ReadCloser, error) {
+	output, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(tenantBucketName(tenantID)),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file in tenant bucket: %w", err)
+	}
+	return output.Body, nil
+}
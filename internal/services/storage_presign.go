@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"go.uber.org/zap"
+)
+
+// PresignTenantUpload returns a URL a client can PUT a file of a tenant's
+// bucket to, with the given content type and length, until it expires
+func (s *S3StorageService) PresignTenantUpload(ctx context.Context, tenantID, key, contentType string, sizeBytes int64, expiration time.Duration) (string, error) {
+	bucketName := tenantBucketName(tenantID)
+	if err := s.ensureBucketExists(ctx, bucketName); err != nil {
+		return "", fmt.Errorf("failed to ensure bucket exists: %w", err)
+	}
+
+	result, err := s3.NewPresignClient(s.client).PresignPutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(bucketName),
+		Key:           aws.String(key),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(sizeBytes),
+	}, func(opts *s3.PresignOptions) {
+		opts.Expires = expiration
+	})
+	if err != nil {
+		s.logger.Error("Failed to presign upload",
+			zap.String("bucket", bucketName),
+			zap.String("key", key),
+			zap.Error(err))
+		return "", fmt.Errorf("failed to presign upload: %w", err)
+	}
+	return result.URL, nil
+}
+
+// GetTenantFileInfo returns the metadata of a file of a tenant's bucket,
+// or nil when it does not exist
+func (s *S3StorageService) GetTenantFileInfo(ctx context.Context, tenantID, key string) (*FileMetadata, error) {
+	result, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(tenantBucketName(tenantID)),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get file info: %w", err)
+	}
+
+	return &FileMetadata{
+		Key:          key,
+		Size:         aws.ToInt64(result.ContentLength),
+		ContentType:  aws.ToString(result.ContentType),
+		ETag:         aws.ToString(result.ETag),
+		LastModified: aws.ToTime(result.LastModified),
+		Metadata:     result.Metadata,
+	}, nil
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"go.uber.org/zap"
+)
+
+// UploadFileToBucket stores a file in a named bucket outside the tenants'
+// buckets, creating the bucket on first use
+func (s *S3StorageService) UploadFileToBucket(ctx context.Context, bucket, key string, data []byte, contentType string, metadata map[string]string) error {
+	if err := s.ensureBucketExists(ctx, bucket); err != nil {
+		return fmt.Errorf("failed to ensure bucket exists: %w", err)
+	}
+
+	objectMetadata := map[string]string{
+		"uploaded-by": "aether-backend",
+		"upload-time": time.Now().Format(time.RFC3339),
+	}
+	for name, value := range metadata {
+		objectMetadata[name] = value
+	}
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(bucket),
+		Key:           aws.String(key),
+		Body:          bytes.NewReader(data),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(int64(len(data))),
+		Metadata:      objectMetadata,
+	})
+	if err != nil {
+		s.logger.Error("Failed to upload file to bucket",
+			zap.String("bucket", bucket),
+			zap.String("key", key),
+			zap.Error(err))
+		return fmt.Errorf("failed to upload file: %w", err)
+	}
+	return nil
+}
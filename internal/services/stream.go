@@ -23,6 +23,24 @@ type StreamService struct {
 	connectionsMux    sync.RWMutex
 	eventChannel      chan *models.LiveEvent
 	eventProcessors   map[string]EventProcessor
+
+	// bus fans stored events out to all replicas; nil broadcasts locally
+	// only, which is correct for a single instance
+	bus StreamEventBus
+	// hub delivers stored events to unified WebSocket subscribers
+	hub *EventHub
+	// quotas count ingested events against the space's monthly quota;
+	// nil enforces none
+	quotas *QuotaService
+
+	// ctx is cancelled by Stop to end the event processing goroutine;
+	// done is closed once queued events have been drained and stored.
+	// closeMu orders enqueues before Stop so none land after the drain.
+	ctx     context.Context
+	cancel  context.CancelFunc
+	done    chan struct{}
+	closeMu sync.RWMutex
+	closing bool
 }
 
 // EventProcessor interface for processing different types of events
@@ -38,7 +56,12 @@ type SentimentAnalysisProcessor struct {
 
 // NewStreamService creates a new stream service
 func NewStreamService(neo4j *database.Neo4jClient, log *logger.Logger) *StreamService {
+	ctx, cancel := context.WithCancel(context.Background())
+
 	service := &StreamService{
+		ctx:               ctx,
+		cancel:            cancel,
+		done:              make(chan struct{}),
 		neo4j:             neo4j,
 		logger:            log.WithService("stream_service"),
 		activeConnections: make(map[string]*models.StreamConnection),
@@ -336,20 +359,64 @@ func (s *StreamService) IngestEvent(ctx context.Context, sourceID, eventType, co
 	if source.Status != "active" {
 		return nil, fmt.Errorf("stream source is not active")
 	}
+	if s.quotas != nil {
+		if err := s.quotas.ReserveStreamEvent(ctx, spaceContext.SpaceID); err != nil {
+			return nil, err
+		}
+	}
 
 	event := models.NewLiveEvent(sourceID, eventType, content, mediaType, spaceContext.TenantID, spaceContext.SpaceID)
 	event.Metadata = metadata
 
-	// Queue event for asynchronous processing
+	if err := s.enqueueEvent(event); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// IngestWebhookEvent ingests an event a stream source delivered by
+// webhook. The delivery is not made in a space, so the event is recorded
+// in the space the source belongs to.
+func (s *StreamService) IngestWebhookEvent(ctx context.Context, sourceID, eventType, content, mediaType string, metadata map[string]interface{}) (*models.LiveEvent, error) {
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (s:StreamSource {id: $source_id})
+		RETURN s.tenant_id as tenant_id, s.organization_id as organization_id
+	`, map[string]interface{}{"source_id": sourceID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stream source: %w", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, fmt.Errorf("stream source not found")
+	}
+
+	record := result.Records[0]
+	spaceContext := &models.SpaceContext{
+		TenantID: recordString(record, "tenant_id"),
+		SpaceID:  recordString(record, "organization_id"),
+	}
+	return s.IngestEvent(ctx, sourceID, eventType, content, mediaType, metadata, spaceContext)
+}
+
+// enqueueEvent queues an event for asynchronous processing. The closing
+// check and the send happen under closeMu so an event accepted here is
+// always in the channel before Stop lets the drain begin.
+func (s *StreamService) enqueueEvent(event *models.LiveEvent) error {
+	s.closeMu.RLock()
+	defer s.closeMu.RUnlock()
+
+	if s.closing {
+		return fmt.Errorf("stream service is shutting down")
+	}
+
 	select {
 	case s.eventChannel <- event:
 		s.logger.Debug("Event queued for processing", zap.String("event_id", event.ID))
+		return nil
 	default:
 		s.logger.Warn("Event channel full, dropping event", zap.String("event_id", event.ID))
-		return nil, fmt.Errorf("event processing queue is full")
+		return fmt.Errorf("event processing queue is full")
 	}
-
-	return event, nil
 }
 
 // GetLiveEvents retrieves recent live events
@@ -382,6 +449,13 @@ func (s *StreamService) GetLiveEvents(ctx context.Context, spaceContext *models.
 		whereConditions = append(whereConditions, "e.confidence >= $min_confidence")
 		parameters["min_confidence"] = filters.MinConfidence
 	}
+	filter, err := compileFilter(filters.Filter, liveEventFilterFields, "e", parameters)
+	if err != nil {
+		return nil, 0, err
+	}
+	if filter != "" {
+		whereConditions = append(whereConditions, filter)
+	}
 
 	whereClause := strings.Join(whereConditions, " AND ")
 
@@ -472,6 +546,26 @@ func (s *StreamService) GetStreamAnalytics(ctx context.Context, spaceContext *mo
 	return analytics, nil
 }
 
+// SetEventBus routes broadcasts through bus so clients connected to any
+// replica receive events ingested by this one. The bus must deliver
+// events back to BroadcastEvent on every replica, including this one.
+// Must be called before the server starts accepting events.
+func (s *StreamService) SetEventBus(bus StreamEventBus) {
+	s.bus = bus
+}
+
+// SetQuotaService sets the service that enforces the monthly stream event
+// quota of spaces
+func (s *StreamService) SetQuotaService(quotas *QuotaService) {
+	s.quotas = quotas
+}
+
+// SetEventHub publishes each stored event once to the event hub, whose
+// subscribers on every replica receive it
+func (s *StreamService) SetEventHub(hub *EventHub) {
+	s.hub = hub
+}
+
 // AddStreamConnection adds a new WebSocket connection for real-time events
 func (s *StreamService) AddStreamConnection(conn *models.StreamConnection) {
 	s.connectionsMux.Lock()
@@ -519,109 +613,225 @@ func (s *StreamService) BroadcastEvent(event *models.LiveEvent) {
 	}
 }
 
-// processEvents processes events from the event channel
+// Stop stops accepting events and ends the event processing goroutine
+// after the queued events have been drained
+func (s *StreamService) Stop() {
+	s.closeMu.Lock()
+	s.closing = true
+	s.closeMu.Unlock()
+
+	s.cancel()
+}
+
+// Shutdown stops the service and waits until queued events are stored or
+// ctx is done
+func (s *StreamService) Shutdown(ctx context.Context) error {
+	s.Stop()
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		s.logger.Warn("Timed out draining stream events", zap.Int("queued", len(s.eventChannel)))
+		return ctx.Err()
+	}
+}
+
+// Stream events are stored in batches to keep up with high-volume sources
+const (
+	streamEventBatchSize     = 100
+	streamEventFlushInterval = 500 * time.Millisecond
+)
+
+// processEvents processes events from the event channel until Stop is called.
+// Processed events are buffered and stored in batches, flushing when the
+// buffer is full or the flush interval elapses.
 func (s *StreamService) processEvents() {
-	for event := range s.eventChannel {
-		start := time.Now()
-		
-		// Process the event with available processors
-		processedEvent := event
-		for _, processor := range s.eventProcessors {
-			var err error
-			processedEvent, err = processor.ProcessEvent(context.Background(), processedEvent)
-			if err != nil {
-				s.logger.Error("Failed to process event", 
-					zap.String("event_id", event.ID),
-					zap.String("processor", processor.GetProcessorType()),
-					zap.Error(err))
-				continue
+	ticker := time.NewTicker(streamEventFlushInterval)
+	defer ticker.Stop()
+
+	pending := make([]*models.LiveEvent, 0, streamEventBatchSize)
+	flush := func(ctx context.Context) {
+		if len(pending) == 0 {
+			return
+		}
+		s.flushEvents(ctx, pending)
+		pending = pending[:0]
+	}
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			s.drainEvents(pending)
+			close(s.done)
+			s.logger.Info("Stream event processing stopped")
+			return
+		case event := <-s.eventChannel:
+			pending = append(pending, s.processEvent(event))
+			if len(pending) >= streamEventBatchSize {
+				flush(s.ctx)
 			}
+		case <-ticker.C:
+			flush(s.ctx)
+		}
+	}
+}
+
+// drainEvents processes events still queued at shutdown and stores them
+// together with the pending batch, using a fresh deadline since the service
+// context is already cancelled
+func (s *StreamService) drainEvents(pending []*models.LiveEvent) {
+drain:
+	for {
+		select {
+		case event := <-s.eventChannel:
+			pending = append(pending, s.processEvent(event))
+		default:
+			break drain
 		}
+	}
+
+	if len(pending) == 0 {
+		return
+	}
 
-		// Calculate processing time
-		processingTime := float64(time.Since(start).Nanoseconds()) / 1000000.0 // Convert to milliseconds
-		processedEvent.ProcessingTime = processingTime
+	ctx, cancel := context.WithTimeout(context.Background(), defaultEventProcessTimeout)
+	defer cancel()
 
-		// Store the processed event
-		err := s.storeEvent(context.Background(), processedEvent)
+	s.flushEvents(ctx, pending)
+	s.logger.Info("Drained stream events", zap.Int("count", len(pending)))
+}
+
+// processEvent runs the registered processors on a single event
+func (s *StreamService) processEvent(event *models.LiveEvent) *models.LiveEvent {
+	ctx, cancel := context.WithTimeout(s.ctx, defaultEventProcessTimeout)
+	defer cancel()
+
+	start := time.Now()
+	
+	// Process the event with available processors
+	processedEvent := event
+	for _, processor := range s.eventProcessors {
+		result, err := processor.ProcessEvent(ctx, processedEvent)
 		if err != nil {
-			s.logger.Error("Failed to store event", zap.String("event_id", event.ID), zap.Error(err))
+			s.logger.Error("Failed to process event", 
+				zap.String("event_id", event.ID),
+				zap.String("processor", processor.GetProcessorType()),
+				zap.Error(err))
 			continue
 		}
+		processedEvent = result
+	}
 
-		// Broadcast to WebSocket connections
-		s.BroadcastEvent(processedEvent)
+	// Calculate processing time
+	processingTime := float64(time.Since(start).Nanoseconds()) / 1000000.0 // Convert to milliseconds
+	processedEvent.ProcessingTime = processingTime
 
-		s.logger.Debug("Processed event", 
-			zap.String("event_id", event.ID),
-			zap.Float64("processing_time_ms", processingTime))
-	}
-}
+	s.logger.Debug("Processed event", 
+		zap.String("event_id", event.ID),
+		zap.Float64("processing_time_ms", processingTime))
 
-// storeEvent stores an event in Neo4j
-func (s *StreamService) storeEvent(ctx context.Context, event *models.LiveEvent) error {
-	query := `
-		CREATE (e:LiveEvent {
-			id: $id,
-			stream_source_id: $stream_source_id,
-			event_type: $event_type,
-			content: $content,
-			media_type: $media_type,
-			media_url: $media_url,
-			sentiment: $sentiment,
-			sentiment_score: $sentiment_score,
-			confidence: $confidence,
-			processing_time: $processing_time,
-			has_audit_trail: $has_audit_trail,
-			audit_score: $audit_score,
-			metadata: $metadata,
-			extracted_data: $extracted_data,
-			processed_at: $processed_at,
-			event_timestamp: $event_timestamp,
-			tenant_id: $tenant_id,
-			organization_id: $organization_id
-		})
-		WITH e
-		MATCH (s:StreamSource {id: $stream_source_id})
-		CREATE (s)-[:GENERATED]->(e)
-		RETURN e
-	`
+	return processedEvent
+}
 
-	parameters := map[string]interface{}{
-		"id":               event.ID,
-		"stream_source_id": event.StreamSourceID,
-		"event_type":       event.EventType,
-		"content":          event.Content,
-		"media_type":       event.MediaType,
-		"media_url":        event.MediaURL,
-		"sentiment":        event.Sentiment,
-		"sentiment_score":  event.SentimentScore,
-		"confidence":       event.Confidence,
-		"processing_time":  event.ProcessingTime,
-		"has_audit_trail":  event.HasAuditTrail,
-		"audit_score":      event.AuditScore,
-		"metadata":         serializeParameters(event.Metadata),
-		"extracted_data":   serializeParameters(event.ExtractedData),
-		"processed_at":     event.ProcessedAt,
-		"event_timestamp":  event.EventTimestamp,
-		"tenant_id":        event.TenantID,
-		"organization_id":  event.OrganizationID,
+// flushEvents stores a batch of processed events and broadcasts them
+func (s *StreamService) flushEvents(parent context.Context, events []*models.LiveEvent) {
+	ctx, cancel := context.WithTimeout(parent, defaultEventProcessTimeout)
+	defer cancel()
+
+	if err := s.storeEvents(ctx, events); err != nil {
+		// One bad row fails the whole UNWIND; write rows one at a time so the
+		// rest of the batch is not lost
+		s.logger.Warn("Failed to store event batch, retrying row by row",
+			zap.Int("count", len(events)), zap.Error(err))
+		events = s.storeEventsIndividually(ctx, events)
+		if len(events) == 0 {
+			return
+		}
 	}
 
-	session := s.neo4j.Session(ctx)
-	defer session.Close(ctx)
+	if s.hub != nil {
+		for _, event := range events {
+			s.hub.PublishChange(ctx, HubTopicStreamEvent, event.TenantID, event.ID, HubActionCreated, map[string]interface{}{
+				"event": event,
+			})
+		}
+	}
 
-	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
-		result, err := tx.Run(ctx, query, parameters)
-		if err != nil {
-			return nil, err
+	if s.bus != nil {
+		err := s.bus.Publish(ctx, events)
+		if err == nil {
+			return
 		}
-		return result.Collect(ctx)
-	})
+		// Clients on this replica still get the events; other replicas miss them
+		s.logger.Error("Failed to publish events to other replicas, broadcasting locally",
+			zap.Int("count", len(events)), zap.Error(err))
+	}
 
+	// Broadcast to WebSocket connections
+	for _, event := range events {
+		s.BroadcastEvent(event)
+	}
+}
+
+// storeEventsQuery creates LiveEvent nodes and links them to their source.
+// Events are created even when the source no longer exists.
+const storeEventsQuery = `
+	UNWIND $rows AS row
+	CREATE (e:LiveEvent)
+	SET e = row
+	WITH e
+	MATCH (s:StreamSource {id: e.stream_source_id})
+	CREATE (s)-[:GENERATED]->(e)
+`
+
+// storeEvents stores events in Neo4j using batched UNWIND writes
+func (s *StreamService) storeEvents(ctx context.Context, events []*models.LiveEvent) error {
+	rows := make([]map[string]interface{}, 0, len(events))
+	for _, event := range events {
+		rows = append(rows, map[string]interface{}{
+			"id":               event.ID,
+			"stream_source_id": event.StreamSourceID,
+			"event_type":       event.EventType,
+			"content":          event.Content,
+			"media_type":       event.MediaType,
+			"media_url":        event.MediaURL,
+			"sentiment":        event.Sentiment,
+			"sentiment_score":  event.SentimentScore,
+			"confidence":       event.Confidence,
+			"processing_time":  event.ProcessingTime,
+			"has_audit_trail":  event.HasAuditTrail,
+			"audit_score":      event.AuditScore,
+			"metadata":         serializeParameters(event.Metadata),
+			"extracted_data":   serializeParameters(event.ExtractedData),
+			"processed_at":     event.ProcessedAt,
+			"event_timestamp":  event.EventTimestamp,
+			"tenant_id":        event.TenantID,
+			"organization_id":  event.OrganizationID,
+		})
+	}
+
+	_, err := s.neo4j.WriteBatch(database.WithQueryName(ctx, "stream.store_events"), storeEventsQuery, rows, nil)
 	return err
 }
 
+// storeEventsIndividually writes events one per transaction and returns
+// those that were stored
+func (s *StreamService) storeEventsIndividually(ctx context.Context, events []*models.LiveEvent) []*models.LiveEvent {
+	stored := make([]*models.LiveEvent, 0, len(events))
+	for _, event := range events {
+		if err := s.storeEvents(ctx, []*models.LiveEvent{event}); err != nil {
+			s.logger.Error("Failed to store event",
+				zap.String("event_id", event.ID),
+				zap.String("stream_source_id", event.StreamSourceID),
+				zap.Error(err))
+			continue
+		}
+		stored = append(stored, event)
+	}
+	return stored
+}
+
 // recordToStreamSource converts a Neo4j record to a StreamSource
 func (s *StreamService) recordToStreamSource(record *neo4j.Record, alias string) (*models.StreamSource, error) {
 	node, found := record.Get(alias)
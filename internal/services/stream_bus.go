@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+// StreamEventBus fans stored live events out to every replica. WebSocket
+// connections live on the replica that accepted them, so an event ingested
+// on one replica must reach the others before it can be broadcast.
+type StreamEventBus interface {
+	Publish(ctx context.Context, events []*models.LiveEvent) error
+}
+
+// streamEventsTopic carries processed live events between replicas
+const streamEventsTopic = "stream.events"
+
+// streamBusStartTimeout bounds the partition lookup when the bus starts
+const streamBusStartTimeout = 10 * time.Second
+
+// KafkaStreamBus is a StreamEventBus backed by a Kafka topic. Each replica
+// reads every partition without a consumer group, so every replica receives
+// every event and rollouts leave no stale groups behind.
+type KafkaStreamBus struct {
+	kafkaService *KafkaService
+	topic        string
+	logger       *logger.Logger
+}
+
+// NewKafkaStreamBus creates a stream event bus
+func NewKafkaStreamBus(kafkaService *KafkaService, log *logger.Logger) *KafkaStreamBus {
+	topic := streamEventsTopic
+	if kafkaService.config.TopicPrefix != "" {
+		topic = fmt.Sprintf("%s.%s", kafkaService.config.TopicPrefix, streamEventsTopic)
+	}
+
+	return &KafkaStreamBus{
+		kafkaService: kafkaService,
+		topic:        topic,
+		logger:       log.WithService("stream_bus"),
+	}
+}
+
+// Start consumes events published by any replica and hands them to
+// broadcast for delivery to this replica's connections. Consumption starts
+// at the latest offset: events from before the replica started are not
+// replayed to its clients.
+func (b *KafkaStreamBus) Start(broadcast func(event *models.LiveEvent)) error {
+	b.logger.Info("Starting stream event bus", zap.String("topic", b.topic))
+
+	ctx, cancel := context.WithTimeout(context.Background(), streamBusStartTimeout)
+	defer cancel()
+
+	return b.kafkaService.SubscribeAllPartitions(ctx, b.topic, func(ctx context.Context, message kafka.Message) error {
+		var event models.LiveEvent
+		if err := json.Unmarshal(message.Value, &event); err != nil {
+			// Malformed messages cannot succeed on retry; skip them
+			b.logger.Error("Failed to decode stream event", zap.Error(err))
+			return nil
+		}
+		broadcast(&event)
+		return nil
+	})
+}
+
+// Publish sends events to all replicas, keyed by tenant so each tenant's
+// events stay ordered
+func (b *KafkaStreamBus) Publish(ctx context.Context, events []*models.LiveEvent) error {
+	msgs := make([]Message, 0, len(events))
+	for _, event := range events {
+		msgs = append(msgs, Message{
+			Topic: b.topic,
+			Key:   event.TenantID,
+			Value: event,
+		})
+	}
+	return b.kafkaService.PublishMessages(ctx, msgs)
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+func TestStreamServiceEnqueueStopsAtShutdown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &StreamService{
+		ctx:          ctx,
+		cancel:       cancel,
+		logger:       setupTestLogger(t),
+		eventChannel: make(chan *models.LiveEvent, 1),
+	}
+
+	require.NoError(t, s.enqueueEvent(&models.LiveEvent{ID: "first"}))
+	assert.ErrorContains(t, s.enqueueEvent(&models.LiveEvent{ID: "second"}), "queue is full")
+
+	s.Stop()
+	<-s.eventChannel
+	assert.ErrorContains(t, s.enqueueEvent(&models.LiveEvent{ID: "third"}), "shutting down")
+	assert.Empty(t, s.eventChannel, "no event may be queued once Stop returns")
+}
+
+func BenchmarkRecordToLiveEvent(b *testing.B) {
+	s := &StreamService{logger: setupTestLogger(b)}
+	now := time.Now()
+	record := &neo4j.Record{
+		Keys: []string{"e"},
+		Values: []interface{}{neo4j.Node{Props: map[string]interface{}{
+			"id":               "evt-1",
+			"stream_source_id": "src-1",
+			"event_type":       "mention",
+			"content":          "Great quarterly results announced today",
+			"media_type":       "text",
+			"sentiment":        "positive",
+			"sentiment_score":  0.82,
+			"confidence":       0.91,
+			"processing_time":  12.5,
+			"has_audit_trail":  true,
+			"audit_score":      0.88,
+			"tenant_id":        "tenant-1",
+			"organization_id":  "org-1",
+			"processed_at":     now,
+			"event_timestamp":  now,
+			"metadata":         `{"channel":"twitter","lang":"en"}`,
+		}}},
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		event, err := s.recordToLiveEvent(record, "e")
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := json.Marshal(event); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,445 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// Search suggestion limits
+const (
+	defaultSuggestLimit = 8
+	// suggestOverfetch reads more index members than requested, as an
+	// entity matched through several words or a tag used by several
+	// documents yields duplicates
+	suggestOverfetch = 4
+	// maxSuggestTerms bounds how many words of one name are indexed
+	maxSuggestTerms = 8
+	// maxSuggestTermBytes matches the longest query
+	maxSuggestTermBytes = 100
+	// suggestBuildLimit bounds each entity type when a space's index is
+	// first built
+	suggestBuildLimit = 5000
+)
+
+// SuggestIndex stores prefix indexes. The Redis client implements it;
+// LocalSuggestIndex stands in when Redis is disabled.
+type SuggestIndex interface {
+	// ReplaceIndexMembers makes members the only members ownerKey
+	// contributes, all to indexKey
+	ReplaceIndexMembers(ctx context.Context, indexKey, ownerKey string, members []string) error
+	// RangeIndexPrefix returns up to limit members of indexKey starting
+	// with prefix, in lexicographic order
+	RangeIndexPrefix(ctx context.Context, indexKey, prefix string, limit int64) ([]string, error)
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error)
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// SuggestService completes what a user types in the search bar from a
+// prefix index of the names of a space's documents and notebooks and the
+// tags of its documents. The index is kept current from domain events and
+// built from Neo4j the first time a space is queried.
+type SuggestService struct {
+	neo4j  *database.Neo4jClient
+	index  SuggestIndex
+	logger *logger.Logger
+}
+
+// NewSuggestService creates a search suggestion service over index,
+// falling back to an in-process index when index is nil
+func NewSuggestService(neo4j *database.Neo4jClient, index SuggestIndex, log *logger.Logger) *SuggestService {
+	if index == nil {
+		index = NewLocalSuggestIndex()
+	}
+	return &SuggestService{
+		neo4j:  neo4j,
+		index:  index,
+		logger: log.WithService("suggest_service"),
+	}
+}
+
+// suggestIndexKey names the index of a space
+func suggestIndexKey(tenantID, spaceID string) string {
+	return fmt.Sprintf("tas:suggest:%s:%s", tenantID, spaceID)
+}
+
+// suggestOwnerKey names the record of the members an entity contributes
+func suggestOwnerKey(entityType, id string) string {
+	return fmt.Sprintf("tas:suggest:owner:%s:%s", entityType, id)
+}
+
+// suggestMember encodes a suggestion under the term it is found by. The
+// term comes first so members sort, and are ranged, by it.
+func suggestMember(term string, suggestionType models.SuggestionType, id, text string) string {
+	return strings.Join([]string{term, string(suggestionType), id, text}, "\x00")
+}
+
+// parseSuggestMember decodes a member written by suggestMember
+func parseSuggestMember(member string) (*models.SearchSuggestion, bool) {
+	parts := strings.SplitN(member, "\x00", 4)
+	if len(parts) != 4 {
+		return nil, false
+	}
+	return &models.SearchSuggestion{
+		Type: models.SuggestionType(parts[1]),
+		ID:   parts[2],
+		Text: parts[3],
+	}, true
+}
+
+// normalizeSuggestQuery lowercases a query the way terms are indexed
+func normalizeSuggestQuery(query string) string {
+	return strings.ToLower(strings.TrimSpace(query))
+}
+
+// suggestTerms returns the terms text is found by: the whole text and the
+// rest of it from the start of each later word, so "Q3 sales report" is
+// suggested for "q3", "sales" and "report"
+func suggestTerms(text string) []string {
+	text = normalizeSuggestQuery(text)
+	if text == "" {
+		return nil
+	}
+
+	terms := []string{truncateTerm(text)}
+	previous := rune(0)
+	for i, r := range text {
+		if i > 0 && isSuggestSeparator(previous) && !isSuggestSeparator(r) {
+			terms = append(terms, truncateTerm(text[i:]))
+			if len(terms) == maxSuggestTerms {
+				break
+			}
+		}
+		previous = r
+	}
+	return terms
+}
+
+func isSuggestSeparator(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune("-_./", r)
+}
+
+// truncateTerm cuts a term to maxSuggestTermBytes without splitting a rune
+func truncateTerm(term string) string {
+	if len(term) <= maxSuggestTermBytes {
+		return term
+	}
+	cut := maxSuggestTermBytes
+	for cut > 0 && !utf8RuneStart(term[cut]) {
+		cut--
+	}
+	return term[:cut]
+}
+
+func utf8RuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// Suggest returns completions of the query from the space's index
+func (s *SuggestService) Suggest(ctx context.Context, req models.SearchSuggestRequest, spaceCtx *models.SpaceContext) (*models.SearchSuggestResponse, error) {
+	if !spaceCtx.CanRead() {
+		return nil, errors.Forbidden("Insufficient permissions to search this space")
+	}
+	if req.Limit <= 0 {
+		req.Limit = defaultSuggestLimit
+	}
+
+	indexKey := suggestIndexKey(spaceCtx.TenantID, spaceCtx.SpaceID)
+	if err := s.ensureBuilt(ctx, indexKey, spaceCtx.TenantID, spaceCtx.SpaceID); err != nil {
+		return nil, err
+	}
+
+	members, err := s.index.RangeIndexPrefix(ctx, indexKey, normalizeSuggestQuery(req.Query), int64(req.Limit*suggestOverfetch))
+	if err != nil {
+		return nil, errors.ExternalService("Failed to read search suggestions", err)
+	}
+
+	response := &models.SearchSuggestResponse{
+		Query:       req.Query,
+		Suggestions: []*models.SearchSuggestion{},
+	}
+	seen := make(map[string]bool, len(members))
+	for _, member := range members {
+		suggestion, ok := parseSuggestMember(member)
+		if !ok {
+			continue
+		}
+		// A tag is one suggestion however many documents use it
+		key := string(suggestion.Type) + "\x00" + suggestion.ID
+		if suggestion.Type == models.SuggestionTag {
+			key = string(suggestion.Type) + "\x00" + suggestion.Text
+			suggestion.ID = ""
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		response.Suggestions = append(response.Suggestions, suggestion)
+		if len(response.Suggestions) == req.Limit {
+			break
+		}
+	}
+	return response, nil
+}
+
+// HandleDomainEvent keeps the index current as documents and notebooks
+// change. Deletions reindex too: an entity that is gone leaves the index.
+func (s *SuggestService) HandleDomainEvent(ctx context.Context, event Event) error {
+	switch event.Type {
+	case EventDocumentUploaded, EventDocumentUpdated, EventDocumentDeleted, EventDocumentRestored:
+		return s.ReindexDocument(ctx, event.Subject)
+	case EventNotebookCreated, EventNotebookUpdated, EventNotebookDeleted, EventNotebookRestored:
+		return s.ReindexNotebook(ctx, event.Subject)
+	}
+	return nil
+}
+
+// ReindexDocument indexes the name and tags of a document, or removes them
+// when the document is deleted
+func (s *SuggestService) ReindexDocument(ctx context.Context, documentID string) error {
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (d:Document {id: $id})
+		WHERE `+database.NotDeleted("d")+`
+		RETURN d.id AS id, d.name AS name, d.tags AS tags,
+		       d.tenant_id AS tenant_id, d.space_id AS space_id
+	`, map[string]interface{}{"id": documentID})
+	if err != nil {
+		return errors.Database("Failed to read document for search suggestions", err)
+	}
+
+	indexKey, members := "", []string(nil)
+	if len(result.Records) > 0 {
+		indexKey, members = documentSuggestMembers(result.Records[0])
+	}
+	return s.replace(ctx, indexKey, suggestOwnerKey("document", documentID), members)
+}
+
+// ReindexNotebook indexes the name of a notebook, or removes it when the
+// notebook is deleted
+func (s *SuggestService) ReindexNotebook(ctx context.Context, notebookID string) error {
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (n:Notebook {id: $id})
+		WHERE `+database.NotDeleted("n")+`
+		RETURN n.id AS id, n.name AS name, n.tenant_id AS tenant_id, n.space_id AS space_id
+	`, map[string]interface{}{"id": notebookID})
+	if err != nil {
+		return errors.Database("Failed to read notebook for search suggestions", err)
+	}
+
+	indexKey, members := "", []string(nil)
+	if len(result.Records) > 0 {
+		indexKey, members = notebookSuggestMembers(result.Records[0])
+	}
+	return s.replace(ctx, indexKey, suggestOwnerKey("notebook", notebookID), members)
+}
+
+func (s *SuggestService) replace(ctx context.Context, indexKey, ownerKey string, members []string) error {
+	if err := s.index.ReplaceIndexMembers(ctx, indexKey, ownerKey, members); err != nil {
+		return errors.ExternalService("Failed to update search suggestions", err)
+	}
+	return nil
+}
+
+// documentSuggestMembers returns the index of a document's space and the
+// members its name and tags contribute
+func documentSuggestMembers(record *neo4j.Record) (string, []string) {
+	id, name := recordString(record, "id"), recordString(record, "name")
+	var members []string
+	for _, term := range suggestTerms(name) {
+		members = append(members, suggestMember(term, models.SuggestionDocument, id, name))
+	}
+	if value, ok := record.Get("tags"); ok {
+		tags, _ := value.([]interface{})
+		for _, tag := range tags {
+			if tag, ok := tag.(string); ok && strings.TrimSpace(tag) != "" {
+				members = append(members, suggestMember(truncateTerm(normalizeSuggestQuery(tag)), models.SuggestionTag, id, tag))
+			}
+		}
+	}
+	return suggestIndexKey(recordString(record, "tenant_id"), recordString(record, "space_id")), members
+}
+
+// notebookSuggestMembers returns the index of a notebook's space and the
+// members its name contributes
+func notebookSuggestMembers(record *neo4j.Record) (string, []string) {
+	id, name := recordString(record, "id"), recordString(record, "name")
+	var members []string
+	for _, term := range suggestTerms(name) {
+		members = append(members, suggestMember(term, models.SuggestionNotebook, id, name))
+	}
+	return suggestIndexKey(recordString(record, "tenant_id"), recordString(record, "space_id")), members
+}
+
+// ensureBuilt indexes a space's documents and notebooks the first time the
+// space is queried, such as after Redis was emptied. The request that
+// claims the build waits for it; concurrent ones read the partial index.
+func (s *SuggestService) ensureBuilt(ctx context.Context, indexKey, tenantID, spaceID string) error {
+	builtKey := indexKey + ":built"
+	claimed, err := s.index.SetNX(ctx, builtKey, time.Now().Unix(), 0)
+	if err != nil {
+		return errors.ExternalService("Failed to read search suggestions", err)
+	}
+	if !claimed {
+		return nil
+	}
+
+	if err := s.build(ctx, tenantID, spaceID); err != nil {
+		// Let the next request try again
+		if deleteErr := s.index.Delete(ctx, builtKey); deleteErr != nil {
+			s.logger.Warn("Failed to reset search suggestion index", zap.String("space_id", spaceID), zap.Error(deleteErr))
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *SuggestService) build(ctx context.Context, tenantID, spaceID string) error {
+	params := map[string]interface{}{
+		"tenant_id": tenantID,
+		"space_id":  spaceID,
+		"limit":     suggestBuildLimit,
+	}
+	sources := []struct {
+		entityType string
+		query      string
+		members    func(*neo4j.Record) (string, []string)
+	}{
+		{"document", `
+			MATCH (d:Document)
+			WHERE d.tenant_id = $tenant_id AND d.space_id = $space_id AND ` + database.NotDeleted("d") + `
+			RETURN d.id AS id, d.name AS name, d.tags AS tags,
+			       d.tenant_id AS tenant_id, d.space_id AS space_id
+			ORDER BY d.updated_at DESC
+			LIMIT $limit
+		`, documentSuggestMembers},
+		{"notebook", `
+			MATCH (n:Notebook)
+			WHERE n.tenant_id = $tenant_id AND n.space_id = $space_id AND ` + database.NotDeleted("n") + `
+			RETURN n.id AS id, n.name AS name, n.tenant_id AS tenant_id, n.space_id AS space_id
+			ORDER BY n.updated_at DESC
+			LIMIT $limit
+		`, notebookSuggestMembers},
+	}
+
+	indexed := 0
+	for _, source := range sources {
+		result, err := s.neo4j.ExecuteQuery(ctx, source.query, params)
+		if err != nil {
+			return errors.Database("Failed to build search suggestions", err)
+		}
+		for _, record := range result.Records {
+			indexKey, members := source.members(record)
+			if err := s.replace(ctx, indexKey, suggestOwnerKey(source.entityType, recordString(record, "id")), members); err != nil {
+				return err
+			}
+			indexed++
+		}
+	}
+
+	s.logger.FromContext(ctx).Info("Built search suggestion index",
+		zap.String("space_id", spaceID),
+		zap.Int("entities", indexed),
+	)
+	return nil
+}
+
+// LocalSuggestIndex is an in-process SuggestIndex. Each replica would
+// hold its own copy, so it must not be used when several replicas run.
+type LocalSuggestIndex struct {
+	mu      sync.Mutex
+	indexes map[string]map[string]struct{}
+	owners  map[string]localIndexOwner
+	keys    map[string]struct{}
+}
+
+type localIndexOwner struct {
+	indexKey string
+	members  []string
+}
+
+// NewLocalSuggestIndex creates an in-process suggestion index
+func NewLocalSuggestIndex() *LocalSuggestIndex {
+	return &LocalSuggestIndex{
+		indexes: make(map[string]map[string]struct{}),
+		owners:  make(map[string]localIndexOwner),
+		keys:    make(map[string]struct{}),
+	}
+}
+
+// ReplaceIndexMembers makes members the only members ownerKey contributes
+func (l *LocalSuggestIndex) ReplaceIndexMembers(ctx context.Context, indexKey, ownerKey string, members []string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if previous, ok := l.owners[ownerKey]; ok {
+		for _, member := range previous.members {
+			delete(l.indexes[previous.indexKey], member)
+		}
+		delete(l.owners, ownerKey)
+	}
+	if len(members) == 0 {
+		return nil
+	}
+
+	if l.indexes[indexKey] == nil {
+		l.indexes[indexKey] = make(map[string]struct{})
+	}
+	for _, member := range members {
+		l.indexes[indexKey][member] = struct{}{}
+	}
+	l.owners[ownerKey] = localIndexOwner{indexKey: indexKey, members: members}
+	return nil
+}
+
+// RangeIndexPrefix returns up to limit members starting with prefix
+func (l *LocalSuggestIndex) RangeIndexPrefix(ctx context.Context, indexKey, prefix string, limit int64) ([]string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var found []string
+	for member := range l.indexes[indexKey] {
+		if strings.HasPrefix(member, prefix) {
+			found = append(found, member)
+		}
+	}
+	sort.Strings(found)
+	if int64(len(found)) > limit {
+		found = found[:limit]
+	}
+	return found, nil
+}
+
+// SetNX records key unless it is already set; expiration is ignored
+func (l *LocalSuggestIndex) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.keys[key]; ok {
+		return false, nil
+	}
+	l.keys[key] = struct{}{}
+	return true, nil
+}
+
+// Delete forgets keys recorded by SetNX
+func (l *LocalSuggestIndex) Delete(ctx context.Context, keys ...string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		delete(l.keys, key)
+	}
+	return nil
+}
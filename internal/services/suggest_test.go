@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+func TestSuggestTerms(t *testing.T) {
+	assert.Equal(t, []string{"q3 sales-report.pdf", "sales-report.pdf", "report.pdf", "pdf"}, suggestTerms("  Q3 Sales-Report.pdf "))
+	assert.Nil(t, suggestTerms("   "))
+	assert.Len(t, suggestTerms("a b c d e f g h i j k"), maxSuggestTerms)
+}
+
+// newTestSuggestService returns a service whose index for the test space
+// counts as built, so Suggest does not read Neo4j
+func newTestSuggestService(t *testing.T) (*SuggestService, *models.SpaceContext) {
+	t.Helper()
+	service := NewSuggestService(nil, nil, setupTestLogger(t))
+	spaceCtx := &models.SpaceContext{TenantID: "tenant_1", SpaceID: "space_1", UserRole: "owner"}
+	_, err := service.index.SetNX(context.Background(), suggestIndexKey("tenant_1", "space_1")+":built", 1, 0)
+	require.NoError(t, err)
+	return service, spaceCtx
+}
+
+func indexTestEntity(t *testing.T, service *SuggestService, entityType string, suggestionType models.SuggestionType, id, name string, tags ...string) {
+	t.Helper()
+	var members []string
+	for _, term := range suggestTerms(name) {
+		members = append(members, suggestMember(term, suggestionType, id, name))
+	}
+	for _, tag := range tags {
+		members = append(members, suggestMember(tag, models.SuggestionTag, id, tag))
+	}
+	require.NoError(t, service.replace(context.Background(), suggestIndexKey("tenant_1", "space_1"), suggestOwnerKey(entityType, id), members))
+}
+
+func TestSuggest(t *testing.T) {
+	service, spaceCtx := newTestSuggestService(t)
+	indexTestEntity(t, service, "document", models.SuggestionDocument, "doc-1", "Sales report", "sales")
+	indexTestEntity(t, service, "document", models.SuggestionDocument, "doc-2", "Quarterly sales", "sales")
+	indexTestEntity(t, service, "notebook", models.SuggestionNotebook, "nb-1", "Sales")
+	indexTestEntity(t, service, "document", models.SuggestionDocument, "doc-3", "Roadmap")
+
+	response, err := service.Suggest(context.Background(), models.SearchSuggestRequest{Query: " SAL"}, spaceCtx)
+	require.NoError(t, err)
+	assert.Equal(t, []*models.SearchSuggestion{
+		{Type: models.SuggestionDocument, ID: "doc-2", Text: "Quarterly sales"},
+		{Type: models.SuggestionNotebook, ID: "nb-1", Text: "Sales"},
+		{Type: models.SuggestionTag, Text: "sales"},
+		{Type: models.SuggestionDocument, ID: "doc-1", Text: "Sales report"},
+	}, response.Suggestions, "a tag shared by documents is suggested once")
+
+	response, err = service.Suggest(context.Background(), models.SearchSuggestRequest{Query: "sal", Limit: 2}, spaceCtx)
+	require.NoError(t, err)
+	assert.Len(t, response.Suggestions, 2)
+
+	response, err = service.Suggest(context.Background(), models.SearchSuggestRequest{Query: "zzz"}, spaceCtx)
+	require.NoError(t, err)
+	assert.Empty(t, response.Suggestions)
+}
+
+func TestSuggestReplaceRemovesStaleMembers(t *testing.T) {
+	service, spaceCtx := newTestSuggestService(t)
+	indexTestEntity(t, service, "document", models.SuggestionDocument, "doc-1", "Draft")
+	indexTestEntity(t, service, "document", models.SuggestionDocument, "doc-1", "Final")
+
+	response, err := service.Suggest(context.Background(), models.SearchSuggestRequest{Query: "dra"}, spaceCtx)
+	require.NoError(t, err)
+	assert.Empty(t, response.Suggestions, "a renamed document is no longer found by its old name")
+
+	// A deleted document contributes nothing
+	require.NoError(t, service.replace(context.Background(), "", suggestOwnerKey("document", "doc-1"), nil))
+	response, err = service.Suggest(context.Background(), models.SearchSuggestRequest{Query: "fin"}, spaceCtx)
+	require.NoError(t, err)
+	assert.Empty(t, response.Suggestions)
+}
+
+func TestSuggestRequiresReadAccess(t *testing.T) {
+	service, _ := newTestSuggestService(t)
+	_, err := service.Suggest(context.Background(), models.SearchSuggestRequest{Query: "a"}, &models.SpaceContext{TenantID: "tenant_1", SpaceID: "space_1"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,508 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// Summary limits
+const (
+	// summaryMaxAttempts bounds the automatic attempts at a document's
+	// summary; it can still be refreshed on demand
+	summaryMaxAttempts = 3
+	// summaryNotebookDocuments bounds the documents a digest is written from
+	summaryNotebookDocuments = 200
+	// summaryMinDocumentRunes is the least text of each document a digest
+	// is given, however many documents the notebook has
+	summaryMinDocumentRunes = 300
+)
+
+// Summary prompts
+const (
+	documentSummaryPrompt = `You write the abstract of a document for the person who owns it.
+Summarize the document's purpose, main points and conclusions in one to three short paragraphs of plain prose.
+Use only the text provided and do not make up facts. Do not start with "This document".`
+	notebookSummaryPrompt = `You write a digest of a notebook: a collection of documents gathered by its owner.
+Describe in one to three short paragraphs of plain prose what the notebook covers, the main themes its documents share and where they differ, naming documents where it helps.
+Use only the material provided and do not make up facts.`
+)
+
+// SummaryService writes document abstracts and notebook digests through
+// the LLM router and caches them on the Document and Notebook nodes. The
+// tokens each space spends are counted per calendar month against
+// SUMMARY_MONTHLY_TOKEN_BUDGET.
+type SummaryService struct {
+	neo4j     *database.Neo4jClient
+	documents *DocumentService
+	notebooks *NotebookService
+	chat      *routerChat
+	config    *config.SummaryConfig
+	logger    *logger.Logger
+}
+
+// NewSummaryService creates a summary service
+func NewSummaryService(neo4j *database.Neo4jClient, documents *DocumentService, notebooks *NotebookService, router *config.RouterConfig, cfg *config.SummaryConfig, log *logger.Logger) *SummaryService {
+	return &SummaryService{
+		neo4j:     neo4j,
+		documents: documents,
+		notebooks: notebooks,
+		chat:      newRouterChat(router),
+		config:    cfg,
+		logger:    log.WithService("summary_service"),
+	}
+}
+
+// autoEnabled reports whether processed documents are summarized
+// automatically. The scheduled job has no user token, so the router must
+// accept the service's own key.
+func (s *SummaryService) autoEnabled() bool {
+	return s.config.AutoEnabled && s.chat.serviceAuth()
+}
+
+// HandleDomainEvent queues processed documents for an automatic summary,
+// so a reprocessed document gets a fresh one. It is subscribed to the
+// domain event bus.
+func (s *SummaryService) HandleDomainEvent(ctx context.Context, event Event) error {
+	if event.Type != EventDocumentProcessed || !s.autoEnabled() {
+		return nil
+	}
+	_, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "summary.queue"), `
+		MATCH (d:Document {id: $id})
+		SET d.summary_pending = true, d.summary_attempts = 0
+	`, map[string]interface{}{"id": event.Subject})
+	if err != nil {
+		return fmt.Errorf("failed to queue document summary: %w", err)
+	}
+	return nil
+}
+
+// DocumentSummary returns the abstract of a document, writing it when it
+// has none or when refresh is set
+func (s *SummaryService) DocumentSummary(ctx context.Context, documentID string, refresh bool, userID, authToken string, spaceCtx *models.SpaceContext) (*models.Summary, error) {
+	if refresh && !spaceCtx.CanUpdate() {
+		return nil, errors.Forbidden("Insufficient permissions to refresh the summary")
+	}
+	document, err := s.documents.GetDocumentByID(ctx, documentID, userID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !refresh {
+		cached, _, err := s.cachedSummary(ctx, models.SummaryDocument, documentID)
+		if err != nil {
+			return nil, err
+		}
+		if cached != nil {
+			cached.Stale = document.ProcessedAt != nil && document.ProcessedAt.After(cached.GeneratedAt)
+			return cached, nil
+		}
+	}
+
+	text := truncateRunes(strings.TrimSpace(document.ExtractedText), s.config.InputChars)
+	if text == "" {
+		return nil, errors.ConflictWithDetails("Document has no extracted text to summarize", map[string]interface{}{
+			"document_id": documentID,
+			"status":      document.Status,
+		}).WithErrorCode(errors.CodeNothingToSummarize)
+	}
+	return s.generate(ctx, document.SpaceID, models.SummaryDocument, documentID, "", buildDocumentSummaryMessages(document.Name, text), authToken)
+}
+
+// summarySource is a processed document a notebook digest is written from
+type summarySource struct {
+	documentID         string
+	name               string
+	summary            string
+	excerpt            string
+	summaryGeneratedAt time.Time
+	processedAt        time.Time
+}
+
+// NotebookSummary returns the digest of a notebook, writing it when it has
+// none or when refresh is set
+func (s *SummaryService) NotebookSummary(ctx context.Context, notebookID string, refresh bool, userID, authToken string, spaceCtx *models.SpaceContext) (*models.Summary, error) {
+	if refresh && !spaceCtx.CanUpdate() {
+		return nil, errors.Forbidden("Insufficient permissions to refresh the summary")
+	}
+	if _, err := s.notebooks.GetNotebookByID(ctx, notebookID, userID, spaceCtx); err != nil {
+		return nil, err
+	}
+
+	sources, err := s.notebookSources(ctx, notebookID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+	fingerprint := summarySourceFingerprint(sources)
+
+	if !refresh {
+		cached, cachedFingerprint, err := s.cachedSummary(ctx, models.SummaryNotebook, notebookID)
+		if err != nil {
+			return nil, err
+		}
+		if cached != nil {
+			cached.Stale = cachedFingerprint != fingerprint
+			return cached, nil
+		}
+	}
+
+	if len(sources) == 0 {
+		return nil, errors.ConflictWithDetails("Notebook has no processed documents to summarize", map[string]interface{}{
+			"notebook_id": notebookID,
+		}).WithErrorCode(errors.CodeNothingToSummarize)
+	}
+	return s.generate(ctx, spaceCtx.SpaceID, models.SummaryNotebook, notebookID, fingerprint, buildNotebookSummaryMessages(sources, s.config.InputChars), authToken)
+}
+
+// notebookSources returns the processed documents of a notebook with their
+// abstracts and the start of their text
+func (s *SummaryService) notebookSources(ctx context.Context, notebookID string, spaceCtx *models.SpaceContext) ([]summarySource, error) {
+	ctx = database.WithQueryName(ctx, "summary.notebook_sources")
+	query := `
+		MATCH (d:Document {notebook_id: $notebook_id, space_id: $space_id})
+		WHERE ` + database.SoftDeleteFilter(ctx, "d") + ` AND d.status = 'processed'
+		RETURN d.id AS id, d.name AS name, d.summary AS summary,
+		       d.summary_generated_at AS summary_generated_at, d.processed_at AS processed_at,
+		       d.tenant_id AS tenant_id, ` + textExcerptColumn("d", "excerpt_chars") + ` AS excerpt
+		ORDER BY d.name, d.id
+		LIMIT $limit
+	`
+	result, err := s.neo4j.ExecuteQuery(ctx, query, map[string]interface{}{
+		"notebook_id":   notebookID,
+		"space_id":      spaceCtx.SpaceID,
+		"excerpt_chars": s.config.InputChars,
+		"limit":         summaryNotebookDocuments,
+	})
+	if err != nil {
+		s.logger.Error("Failed to read notebook documents for summary", zap.String("notebook_id", notebookID), zap.Error(err))
+		return nil, errors.Database("Failed to read notebook documents", err)
+	}
+
+	sources := make([]summarySource, 0, len(result.Records))
+	for _, record := range result.Records {
+		source := summarySource{
+			documentID: recordString(record, "id"),
+			name:       recordString(record, "name"),
+			summary:    recordString(record, "summary"),
+			excerpt:    s.documents.encryption.Excerpt(recordString(record, "tenant_id"), recordString(record, "excerpt"), s.config.InputChars),
+		}
+		source.summaryGeneratedAt = recordTime(record, "summary_generated_at")
+		source.processedAt = recordTime(record, "processed_at")
+		sources = append(sources, source)
+	}
+	return sources, nil
+}
+
+// summarySourceFingerprint identifies the versions of the documents a
+// digest is written from, so a digest goes stale when a document is added,
+// removed, reprocessed or summarized again
+func summarySourceFingerprint(sources []summarySource) string {
+	hash := sha256.New()
+	for _, source := range sources {
+		fmt.Fprintf(hash, "%s|%d|%d\n", source.documentID, source.processedAt.UnixNano(), source.summaryGeneratedAt.UnixNano())
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// summaryLabel returns the node label a subject's summary is cached on
+func summaryLabel(subject models.SummarySubjectType) string {
+	if subject == models.SummaryNotebook {
+		return "Notebook"
+	}
+	return "Document"
+}
+
+// cachedSummary reads the summary cached on a subject's node and the
+// fingerprint of what it was written from; nil when it has none
+func (s *SummaryService) cachedSummary(ctx context.Context, subject models.SummarySubjectType, subjectID string) (*models.Summary, string, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "summary.cached"), `
+		MATCH (x:`+summaryLabel(subject)+` {id: $id})
+		WHERE x.summary IS NOT NULL
+		RETURN x.summary AS summary, x.summary_model AS model, x.summary_tokens AS tokens,
+		       x.summary_generated_at AS generated_at, x.summary_source AS source
+	`, map[string]interface{}{"id": subjectID})
+	if err != nil {
+		return nil, "", errors.Database("Failed to read summary", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, "", nil
+	}
+
+	record := result.Records[0]
+	summary := &models.Summary{
+		SubjectType: subject,
+		SubjectID:   subjectID,
+		Summary:     recordString(record, "summary"),
+		Model:       recordString(record, "model"),
+		TokensUsed:  recordInt64(record, "tokens"),
+		GeneratedAt: recordTime(record, "generated_at"),
+		Cached:      true,
+	}
+	return summary, recordString(record, "source"), nil
+}
+
+// generate asks the model for a summary within the space's budget, counts
+// the tokens spent and caches the summary on the subject's node
+func (s *SummaryService) generate(ctx context.Context, spaceID string, subject models.SummarySubjectType, subjectID, fingerprint string, messages []map[string]string, authToken string) (*models.Summary, error) {
+	if !s.chat.enabled() {
+		return nil, errors.ServiceUnavailable("Summaries require the LLM router")
+	}
+	usage, err := s.Usage(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	if usage.Exhausted() {
+		return nil, errors.NewAPIError(errors.ErrTooManyRequests, "Summary token budget exhausted for this month", map[string]interface{}{
+			"token_budget": usage.TokenBudget,
+			"tokens_used":  usage.TokensUsed,
+			"resets_at":    usage.ResetsAt,
+		}).WithErrorCode(errors.CodeSummaryBudgetExhausted)
+	}
+
+	answer, err := s.chat.complete(ctx, routerChatRequest{
+		Model:     s.config.Model,
+		Provider:  s.config.Provider,
+		MaxTokens: s.config.MaxTokens,
+		Messages:  messages,
+	}, authToken)
+	if err != nil {
+		s.logger.Error("Failed to summarize",
+			zap.String("subject_type", string(subject)),
+			zap.String("subject_id", subjectID),
+			zap.Error(err))
+		return nil, errors.ExternalService("Failed to call LLM service", err)
+	}
+	// The tokens were spent even if caching the summary fails
+	s.recordUsage(ctx, spaceID, int64(answer.TokensUsed))
+
+	summary := &models.Summary{
+		SubjectType: subject,
+		SubjectID:   subjectID,
+		Summary:     answer.Content,
+		Model:       answer.Model,
+		TokensUsed:  int64(answer.TokensUsed),
+		GeneratedAt: time.Now().UTC(),
+	}
+	if summary.Model == "" {
+		summary.Model = s.config.Model
+	}
+
+	var source interface{}
+	if fingerprint != "" {
+		source = fingerprint
+	}
+	_, err = s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "summary.store"), `
+		MATCH (x:`+summaryLabel(subject)+` {id: $id})
+		SET x.summary = $summary, x.summary_model = $model, x.summary_tokens = $tokens,
+		    x.summary_generated_at = $generated_at, x.summary_source = $source
+		REMOVE x.summary_pending, x.summary_attempts
+	`, map[string]interface{}{
+		"id":           subjectID,
+		"summary":      summary.Summary,
+		"model":        summary.Model,
+		"tokens":       summary.TokensUsed,
+		"generated_at": summary.GeneratedAt,
+		"source":       source,
+	})
+	if err != nil {
+		s.logger.Error("Failed to cache summary", zap.String("subject_id", subjectID), zap.Error(err))
+		return nil, errors.Database("Failed to save summary", err)
+	}
+
+	s.logger.Info("Summarized",
+		zap.String("subject_type", string(subject)),
+		zap.String("subject_id", subjectID),
+		zap.Int64("tokens_used", summary.TokensUsed))
+	return summary, nil
+}
+
+// monthPeriod returns the calendar month (UTC) of t, as YYYY-MM, and when
+// it ends. Summary budgets and monthly quotas are counted per period.
+func monthPeriod(t time.Time) (string, time.Time) {
+	t = t.UTC()
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start.Format("2006-01"), start.AddDate(0, 1, 0)
+}
+
+// Usage returns the tokens a space has spent on summaries this month
+func (s *SummaryService) Usage(ctx context.Context, spaceID string) (*models.SummaryUsage, error) {
+	period, resetsAt := monthPeriod(time.Now())
+	usage := &models.SummaryUsage{
+		SpaceID:     spaceID,
+		Period:      period,
+		TokenBudget: int64(s.config.MonthlyTokenBudget),
+		ResetsAt:    resetsAt,
+	}
+
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "summary.usage"), `
+		MATCH (u:SummaryUsage {space_id: $space_id, period: $period})
+		RETURN u.tokens_used AS tokens_used, u.summaries AS summaries
+	`, map[string]interface{}{"space_id": spaceID, "period": period})
+	if err != nil {
+		return nil, errors.Database("Failed to read summary usage", err)
+	}
+	if len(result.Records) > 0 {
+		usage.TokensUsed = recordInt64(result.Records[0], "tokens_used")
+		usage.Summaries = recordInt64(result.Records[0], "summaries")
+	}
+	return usage, nil
+}
+
+// recordUsage adds the tokens of a summary to the space's month. The
+// budget is checked before a summary is written, so the last summary of a
+// month may overshoot it.
+func (s *SummaryService) recordUsage(ctx context.Context, spaceID string, tokens int64) {
+	period, _ := monthPeriod(time.Now())
+	_, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "summary.record_usage"), `
+		MERGE (u:SummaryUsage {space_id: $space_id, period: $period})
+		ON CREATE SET u.tokens_used = 0, u.summaries = 0, u.created_at = $now
+		SET u.tokens_used = u.tokens_used + $tokens,
+		    u.summaries = u.summaries + 1,
+		    u.updated_at = $now
+	`, map[string]interface{}{
+		"space_id": spaceID,
+		"period":   period,
+		"tokens":   tokens,
+		"now":      time.Now().UTC(),
+	})
+	if err != nil {
+		s.logger.Error("Failed to record summary usage",
+			zap.String("space_id", spaceID),
+			zap.Int64("tokens", tokens),
+			zap.Error(err))
+	}
+}
+
+// SummarizePending writes the summaries of documents queued since they
+// were processed. It is a singleton job run by the leader replica. A
+// document is given up after summaryMaxAttempts failures, or when its
+// space's budget is spent; its summary can still be refreshed on demand.
+func (s *SummaryService) SummarizePending(ctx context.Context) error {
+	if !s.autoEnabled() {
+		return nil
+	}
+
+	ctx = database.WithQueryName(ctx, "summary.pending")
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (d:Document)
+		WHERE d.summary_pending = true AND `+database.NotDeleted("d")+`
+		RETURN d.id AS id, d.name AS name, d.space_id AS space_id,
+		       d.tenant_id AS tenant_id, `+textExcerptColumn("d", "input_chars")+` AS text,
+		       coalesce(d.summary_attempts, 0) AS attempts
+		ORDER BY d.updated_at
+		LIMIT $limit
+	`, map[string]interface{}{
+		"input_chars": s.config.InputChars,
+		"limit":       s.config.BatchSize,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list documents to summarize: %w", err)
+	}
+
+	failed := 0
+	for _, record := range result.Records {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		documentID := recordString(record, "id")
+		text := strings.TrimSpace(s.documents.encryption.Excerpt(recordString(record, "tenant_id"), recordString(record, "text"), s.config.InputChars))
+		if text == "" {
+			s.dequeue(ctx, documentID, false)
+			continue
+		}
+
+		_, err := s.generate(ctx, recordString(record, "space_id"), models.SummaryDocument, documentID, "", buildDocumentSummaryMessages(recordString(record, "name"), text), "")
+		if err == nil {
+			continue
+		}
+		if apiErr, ok := errors.AsAPIError(err); ok && apiErr.ErrorCode == errors.CodeSummaryBudgetExhausted {
+			s.logger.Info("Skipping document summary, space budget exhausted",
+				zap.String("document_id", documentID),
+				zap.String("space_id", recordString(record, "space_id")))
+			s.dequeue(ctx, documentID, false)
+			continue
+		}
+		failed++
+		s.dequeue(ctx, documentID, recordInt64(record, "attempts")+1 < summaryMaxAttempts)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d document summaries failed", failed, len(result.Records))
+	}
+	return nil
+}
+
+// dequeue takes a document off the summary queue, or counts a failed
+// attempt and leaves it queued when retry is set
+func (s *SummaryService) dequeue(ctx context.Context, documentID string, retry bool) {
+	query := `
+		MATCH (d:Document {id: $id})
+		REMOVE d.summary_pending, d.summary_attempts
+	`
+	if retry {
+		query = `
+		MATCH (d:Document {id: $id})
+		SET d.summary_attempts = coalesce(d.summary_attempts, 0) + 1
+	`
+	}
+	if _, err := s.neo4j.ExecuteQuery(ctx, query, map[string]interface{}{"id": documentID}); err != nil {
+		s.logger.Warn("Failed to update document summary queue", zap.String("document_id", documentID), zap.Error(err))
+	}
+}
+
+// buildDocumentSummaryMessages returns the chat messages asking the model
+// for the abstract of a document
+func buildDocumentSummaryMessages(name, text string) []map[string]string {
+	return []map[string]string{
+		{"role": "system", "content": documentSummaryPrompt},
+		{"role": "user", "content": fmt.Sprintf("Document: %s\n\n%s", name, text)},
+	}
+}
+
+// buildNotebookSummaryMessages returns the chat messages asking the model
+// for the digest of a notebook, giving each document its abstract or, when
+// it has none, the start of its text, within inputChars overall
+func buildNotebookSummaryMessages(sources []summarySource, inputChars int) []map[string]string {
+	perDocument := inputChars / len(sources)
+	if perDocument < summaryMinDocumentRunes {
+		perDocument = summaryMinDocumentRunes
+	}
+
+	var material strings.Builder
+	used := 0
+	for _, source := range sources {
+		text := strings.TrimSpace(source.summary)
+		if text == "" {
+			text = strings.TrimSpace(source.excerpt)
+		}
+		text = truncateRunes(text, perDocument)
+		if used > 0 && used+len([]rune(text)) > inputChars {
+			break
+		}
+		used += len([]rune(text))
+		fmt.Fprintf(&material, "## %s\n%s\n\n", source.name, text)
+	}
+	return []map[string]string{
+		{"role": "system", "content": notebookSummaryPrompt},
+		{"role": "user", "content": fmt.Sprintf("Documents of the notebook:\n\n%s", material.String())},
+	}
+}
+
+// recordTime reads a datetime record value, zero when null
+func recordTime(record *neo4j.Record, key string) time.Time {
+	value, _ := record.Get(key)
+	t, _ := value.(time.Time)
+	return t
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+func TestSummaryPeriod(t *testing.T) {
+	period, resetsAt := monthPeriod(time.Date(2026, 12, 31, 23, 59, 0, 0, time.UTC))
+	assert.Equal(t, "2026-12", period)
+	assert.Equal(t, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), resetsAt)
+}
+
+func TestSummaryUsageExhausted(t *testing.T) {
+	assert.False(t, (&models.SummaryUsage{TokensUsed: 1 << 40}).Exhausted(), "zero budget is unlimited")
+	assert.False(t, (&models.SummaryUsage{TokensUsed: 99, TokenBudget: 100}).Exhausted())
+	assert.True(t, (&models.SummaryUsage{TokensUsed: 100, TokenBudget: 100}).Exhausted())
+}
+
+func TestSummarySourceFingerprint(t *testing.T) {
+	processed := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	sources := []summarySource{
+		{documentID: "a", processedAt: processed},
+		{documentID: "b", processedAt: processed},
+	}
+	fingerprint := summarySourceFingerprint(sources)
+	assert.Equal(t, fingerprint, summarySourceFingerprint(sources))
+
+	reprocessed := []summarySource{sources[0], {documentID: "b", processedAt: processed.Add(time.Hour)}}
+	assert.NotEqual(t, fingerprint, summarySourceFingerprint(reprocessed))
+	assert.NotEqual(t, fingerprint, summarySourceFingerprint(sources[:1]))
+}
+
+func TestBuildNotebookSummaryMessages(t *testing.T) {
+	messages := buildNotebookSummaryMessages([]summarySource{
+		{name: "Q3 report.pdf", summary: "Revenue grew 12%.", excerpt: "Full text of the report"},
+		{name: "Notes.txt", excerpt: strings.Repeat("x", 5000)},
+	}, 1000)
+
+	require.Len(t, messages, 2)
+	content := messages[1]["content"]
+	assert.Contains(t, content, "## Q3 report.pdf\nRevenue grew 12%.")
+	assert.NotContains(t, content, "Full text of the report")
+	assert.Contains(t, content, "## Notes.txt\n")
+	assert.Less(t, len(content), 1000)
+}
+
+func TestRefreshSummaryRequiresUpdatePermission(t *testing.T) {
+	service := NewSummaryService(nil, nil, nil, nil, &config.SummaryConfig{}, setupTestLogger(t))
+	reader := &models.SpaceContext{SpaceID: "space-1", UserRole: "viewer", Permissions: []string{"read"}}
+
+	_, err := service.DocumentSummary(context.Background(), "document-1", true, "user-1", "", reader)
+	assert.True(t, errors.IsForbidden(err))
+	_, err = service.NotebookSummary(context.Background(), "notebook-1", true, "user-1", "", reader)
+	assert.True(t, errors.IsForbidden(err))
+}
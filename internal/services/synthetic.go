@@ -0,0 +1,323 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/metrics"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+// syntheticUserAgent identifies probe traffic in access logs
+const syntheticUserAgent = "aether-synthetic-probe/1.0"
+
+// syntheticProbeOrder is the order probes run and are reported in. Later
+// probes need the token from the login probe.
+var syntheticProbeOrder = []string{
+	models.SyntheticProbeLogin,
+	models.SyntheticProbeUpload,
+	models.SyntheticProbeSearch,
+	models.SyntheticProbeAgentDryRun,
+}
+
+// SyntheticProber exercises the critical paths of the API the way a client
+// would: it logs in as a dedicated account, uploads and deletes a small
+// document, searches and dry-runs an agent. Each probe's success and latency
+// are exported as metrics, and an event is published when a probe starts or
+// stops failing.
+type SyntheticProber struct {
+	cfg        config.SyntheticConfig
+	oauth      *oauth2.Config
+	client     *http.Client
+	events     DomainEventPublisher
+	metrics    *metrics.Metrics
+	instanceID string
+	logger     *logger.Logger
+
+	mu        sync.Mutex
+	results   map[string]models.SyntheticProbeResult
+	lastRunAt *time.Time
+}
+
+// NewSyntheticProber creates a prober logging in through the Keycloak realm
+func NewSyntheticProber(cfg config.SyntheticConfig, keycloak config.KeycloakConfig, instanceID string, log *logger.Logger) *SyntheticProber {
+	return &SyntheticProber{
+		cfg: cfg,
+		oauth: &oauth2.Config{
+			ClientID:     keycloak.ClientID,
+			ClientSecret: keycloak.ClientSecret,
+			Endpoint: oauth2.Endpoint{
+				TokenURL: fmt.Sprintf("%s/realms/%s/protocol/openid-connect/token", strings.TrimSuffix(keycloak.URL, "/"), keycloak.Realm),
+			},
+			Scopes: []string{"openid"},
+		},
+		client:     &http.Client{Timeout: time.Duration(cfg.TimeoutSeconds) * time.Second},
+		instanceID: instanceID,
+		logger:     log.WithService("synthetic_prober"),
+		results:    make(map[string]models.SyntheticProbeResult),
+	}
+}
+
+// SetEventPublisher sets where probe failure events are published
+func (p *SyntheticProber) SetEventPublisher(events DomainEventPublisher) {
+	p.events = events
+}
+
+// SetMetrics sets where probe results are recorded
+func (p *SyntheticProber) SetMetrics(m *metrics.Metrics) {
+	p.metrics = m
+}
+
+// Report returns the last result of every probe
+func (p *SyntheticProber) Report() *models.SyntheticReport {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	report := &models.SyntheticReport{
+		InstanceID: p.instanceID,
+		LastRunAt:  p.lastRunAt,
+		Probes:     make([]models.SyntheticProbeResult, 0, len(p.results)),
+	}
+	for _, probe := range syntheticProbeOrder {
+		if result, ok := p.results[probe]; ok {
+			report.Probes = append(report.Probes, result)
+		}
+	}
+	return report
+}
+
+// RunOnce runs every probe. It returns an error naming the probes that
+// failed, so failures show in the scheduler's run history.
+func (p *SyntheticProber) RunOnce(ctx context.Context) error {
+	var failed []string
+	record := func(result models.SyntheticProbeResult) {
+		if !result.Success && !result.Skipped {
+			failed = append(failed, result.Probe)
+		}
+		p.record(ctx, result)
+	}
+
+	token, login := p.login(ctx)
+	record(login)
+	for _, probe := range syntheticProbeOrder[1:] {
+		if token == "" {
+			record(models.SyntheticProbeResult{
+				Probe:     probe,
+				Skipped:   true,
+				Error:     "login failed",
+				CheckedAt: time.Now().UTC(),
+			})
+			continue
+		}
+		switch probe {
+		case models.SyntheticProbeUpload:
+			record(p.probeUpload(ctx, token))
+		case models.SyntheticProbeSearch:
+			record(p.probeSearch(ctx, token))
+		case models.SyntheticProbeAgentDryRun:
+			record(p.probeAgentDryRun(ctx, token))
+		}
+	}
+
+	now := time.Now().UTC()
+	p.mu.Lock()
+	p.lastRunAt = &now
+	p.mu.Unlock()
+
+	if len(failed) > 0 {
+		return fmt.Errorf("synthetic probes failed: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// login obtains an access token for the probe account with the password grant
+func (p *SyntheticProber) login(ctx context.Context) (string, models.SyntheticProbeResult) {
+	start := time.Now()
+	result := models.SyntheticProbeResult{Probe: models.SyntheticProbeLogin}
+
+	token, err := p.oauth.PasswordCredentialsToken(context.WithValue(ctx, oauth2.HTTPClient, p.client), p.cfg.Username, p.cfg.Password)
+	result.DurationMs = time.Since(start).Milliseconds()
+	result.CheckedAt = time.Now().UTC()
+	if err != nil {
+		var retrieveErr *oauth2.RetrieveError
+		if errors.As(err, &retrieveErr) && retrieveErr.Response != nil {
+			result.StatusCode = retrieveErr.Response.StatusCode
+		}
+		result.Error = err.Error()
+		return "", result
+	}
+	result.Success = true
+	result.StatusCode = http.StatusOK
+	return token.AccessToken, result
+}
+
+// probeUpload uploads a small text document and deletes it again
+func (p *SyntheticProber) probeUpload(ctx context.Context, token string) models.SyntheticProbeResult {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	name := fmt.Sprintf("synthetic-probe-%s.txt", uuid.New().String()[:8])
+	_ = form.WriteField("notebook_id", p.cfg.NotebookID)
+	_ = form.WriteField("name", name)
+	part, err := form.CreateFormFile("file", name)
+	if err == nil {
+		_, err = part.Write([]byte("Synthetic monitoring probe document. Safe to delete.\n"))
+	}
+	if err == nil {
+		err = form.Close()
+	}
+	if err != nil {
+		return models.SyntheticProbeResult{Probe: models.SyntheticProbeUpload, Error: err.Error(), CheckedAt: time.Now().UTC()}
+	}
+
+	var document struct {
+		ID string `json:"id"`
+	}
+	result := p.call(ctx, models.SyntheticProbeUpload, token, http.MethodPost, "/api/v1/documents/upload", form.FormDataContentType(), &body, &document)
+	if !result.Success || document.ID == "" {
+		if result.Success {
+			result.Success = false
+			result.Error = "upload response has no document ID"
+		}
+		return result
+	}
+
+	// Cleanup is not part of the measured path, but a failure is reported
+	// because probe documents would otherwise pile up
+	cleanup := p.call(ctx, models.SyntheticProbeUpload, token, http.MethodDelete, "/api/v1/documents/"+url.PathEscape(document.ID), "", nil, nil)
+	if !cleanup.Success {
+		result.Success = false
+		result.StatusCode = cleanup.StatusCode
+		result.Error = "delete probe document: " + cleanup.Error
+	}
+	return result
+}
+
+// probeSearch runs a document search in the probe space
+func (p *SyntheticProber) probeSearch(ctx context.Context, token string) models.SyntheticProbeResult {
+	query := url.Values{"query": {"synthetic probe"}, "limit": {"1"}}
+	return p.call(ctx, models.SyntheticProbeSearch, token, http.MethodGet, "/api/v1/documents/search?"+query.Encode(), "", nil, nil)
+}
+
+// probeAgentDryRun resolves and prepares an agent execution without
+// calling the LLM
+func (p *SyntheticProber) probeAgentDryRun(ctx context.Context, token string) models.SyntheticProbeResult {
+	if p.cfg.AgentID == "" {
+		return models.SyntheticProbeResult{
+			Probe:     models.SyntheticProbeAgentDryRun,
+			Skipped:   true,
+			Error:     "no agent configured",
+			CheckedAt: time.Now().UTC(),
+		}
+	}
+	body, _ := json.Marshal(models.AgentExecuteRequest{Input: "Synthetic monitoring probe", DryRun: true})
+	return p.call(ctx, models.SyntheticProbeAgentDryRun, token, http.MethodPost,
+		"/api/v1/agents/"+url.PathEscape(p.cfg.AgentID)+"/execute", "application/json", bytes.NewReader(body), nil)
+}
+
+// call sends an authenticated request in the probe space. A 2xx response
+// is a success; its JSON body is decoded into out when out is set.
+func (p *SyntheticProber) call(ctx context.Context, probe, token, method, path, contentType string, body io.Reader, out interface{}) (result models.SyntheticProbeResult) {
+	start := time.Now()
+	result.Probe = probe
+	defer func() {
+		result.DurationMs = time.Since(start).Milliseconds()
+		result.CheckedAt = time.Now().UTC()
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(p.cfg.BaseURL, "/")+path, body)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("X-Space-Type", p.cfg.SpaceType)
+	req.Header.Set("X-Space-ID", p.cfg.SpaceID)
+	req.Header.Set("X-Request-ID", "synthetic-"+uuid.New().String())
+	req.Header.Set("User-Agent", syntheticUserAgent)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+
+	result.StatusCode = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		result.Error = fmt.Sprintf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+		return result
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			result.Error = "decode response: " + err.Error()
+			return result
+		}
+	}
+	result.Success = true
+	return result
+}
+
+// record stores a probe result, exports it and publishes an event when the
+// probe starts or stops failing. Skipped probes change neither.
+func (p *SyntheticProber) record(ctx context.Context, result models.SyntheticProbeResult) {
+	p.mu.Lock()
+	previous, seen := p.results[result.Probe]
+	p.results[result.Probe] = result
+	p.mu.Unlock()
+
+	if result.Skipped {
+		return
+	}
+	if p.metrics != nil {
+		p.metrics.RecordSyntheticProbe(result.Probe, result.Success, time.Duration(result.DurationMs)*time.Millisecond)
+	}
+
+	wasFailing := seen && !previous.Success && !previous.Skipped
+	switch {
+	case !result.Success && !wasFailing:
+		p.logger.Warn("Synthetic probe failed",
+			zap.String("probe", result.Probe),
+			zap.Int("status_code", result.StatusCode),
+			zap.String("error", result.Error),
+		)
+		p.publish(ctx, EventSyntheticProbeFailed, result)
+	case result.Success && wasFailing:
+		p.logger.Info("Synthetic probe recovered", zap.String("probe", result.Probe))
+		p.publish(ctx, EventSyntheticProbeRecovered, result)
+	}
+}
+
+func (p *SyntheticProber) publish(ctx context.Context, eventType EventType, result models.SyntheticProbeResult) {
+	publishDomainEvent(ctx, p.events, p.logger, Event{
+		Type:    eventType,
+		Subject: result.Probe,
+		Data: map[string]interface{}{
+			"instance_id": p.instanceID,
+			"probe":       result.Probe,
+			"status_code": result.StatusCode,
+			"duration_ms": result.DurationMs,
+			"error":       result.Error,
+			"checked_at":  result.CheckedAt,
+		},
+	})
+}
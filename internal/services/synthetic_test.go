@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+// fakeProbeTarget serves the token endpoint and the API paths the probes
+// call. Search answers with searchStatus.
+func fakeProbeTarget(t *testing.T, searchStatus *atomic.Int32) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/realms/aether/protocol/openid-connect/token" {
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "password", r.PostForm.Get("grant_type"))
+			assert.Equal(t, "probe", r.PostForm.Get("username"))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token":"probe-token","token_type":"Bearer","expires_in":300}`))
+			return
+		}
+
+		assert.Equal(t, "Bearer probe-token", r.Header.Get("Authorization"))
+		assert.Equal(t, "space-1", r.Header.Get("X-Space-ID"))
+		assert.True(t, strings.HasPrefix(r.Header.Get("X-Request-ID"), "synthetic-"))
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/documents/upload":
+			require.NoError(t, r.ParseMultipartForm(1<<20))
+			assert.Equal(t, "notebook-1", r.FormValue("notebook_id"))
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"doc-1"}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/v1/documents/doc-1":
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/documents/search":
+			w.WriteHeader(int(searchStatus.Load()))
+			_, _ = w.Write([]byte(`{"documents":[]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/agents/agent-1/execute":
+			var req models.AgentExecuteRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.True(t, req.DryRun)
+			_, _ = w.Write([]byte(`{"agent_id":"agent-1"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestSyntheticProberRunsProbesAndAlertsOnStateChanges(t *testing.T) {
+	var searchStatus atomic.Int32
+	searchStatus.Store(http.StatusOK)
+	server := fakeProbeTarget(t, &searchStatus)
+	defer server.Close()
+
+	prober := NewSyntheticProber(config.SyntheticConfig{
+		BaseURL:        server.URL,
+		Username:       "probe",
+		Password:       "secret",
+		SpaceType:      "personal",
+		SpaceID:        "space-1",
+		NotebookID:     "notebook-1",
+		AgentID:        "agent-1",
+		TimeoutSeconds: 5,
+	}, config.KeycloakConfig{URL: server.URL, Realm: "aether", ClientID: "aether-backend"}, "replica-1", setupTestLogger(t))
+
+	bus := NewDomainEventBus(nil, setupTestLogger(t))
+	var received []Event
+	bus.Subscribe(func(ctx context.Context, event Event) error {
+		received = append(received, event)
+		return nil
+	})
+	prober.SetEventPublisher(bus)
+
+	require.NoError(t, prober.RunOnce(context.Background()))
+	report := prober.Report()
+	assert.Equal(t, "replica-1", report.InstanceID)
+	require.NotNil(t, report.LastRunAt)
+	require.Len(t, report.Probes, 4)
+	for _, result := range report.Probes {
+		assert.True(t, result.Success, result.Probe)
+	}
+	assert.Empty(t, received)
+
+	// Failures are edge-triggered
+	searchStatus.Store(http.StatusInternalServerError)
+	err := prober.RunOnce(context.Background())
+	assert.ErrorContains(t, err, models.SyntheticProbeSearch)
+	assert.Error(t, prober.RunOnce(context.Background()))
+	require.Len(t, received, 1)
+	assert.Equal(t, EventSyntheticProbeFailed, received[0].Type)
+	assert.Equal(t, models.SyntheticProbeSearch, received[0].Subject)
+	assert.Equal(t, http.StatusInternalServerError, prober.Report().Probes[2].StatusCode)
+
+	searchStatus.Store(http.StatusOK)
+	require.NoError(t, prober.RunOnce(context.Background()))
+	require.Len(t, received, 2)
+	assert.Equal(t, EventSyntheticProbeRecovered, received[1].Type)
+}
+
+func TestSyntheticProberSkipsProbesWhenLoginFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+	}))
+	defer server.Close()
+
+	prober := NewSyntheticProber(config.SyntheticConfig{
+		BaseURL:        server.URL,
+		Username:       "probe",
+		Password:       "wrong",
+		TimeoutSeconds: 5,
+	}, config.KeycloakConfig{URL: server.URL, Realm: "aether"}, "replica-1", setupTestLogger(t))
+
+	err := prober.RunOnce(context.Background())
+	assert.EqualError(t, err, "synthetic probes failed: login")
+
+	report := prober.Report()
+	require.Len(t, report.Probes, 4)
+	assert.False(t, report.Probes[0].Success)
+	assert.Equal(t, http.StatusUnauthorized, report.Probes[0].StatusCode)
+	for _, result := range report.Probes[1:] {
+		assert.True(t, result.Skipped, result.Probe)
+	}
+}
@@ -143,7 +143,7 @@ func (s *TeamService) GetTeam(ctx context.Context, teamID string, userID string)
 	if len(records) == 0 {
 		return nil, errors.NotFoundWithDetails("Team not found", map[string]interface{}{
 			"team_id": teamID,
-		})
+		}).WithErrorCode(errors.CodeTeamNotFound)
 	}
 
 	record := records[0]
@@ -326,7 +326,7 @@ func (s *TeamService) UpdateTeam(ctx context.Context, teamID string, req models.
 	if len(records) == 0 {
 		return nil, errors.NotFoundWithDetails("Team not found", map[string]interface{}{
 			"team_id": teamID,
-		})
+		}).WithErrorCode(errors.CodeTeamNotFound)
 	}
 
 	team, err := s.recordToTeam(records[0])
@@ -604,7 +604,7 @@ func (s *TeamService) InviteTeamMember(ctx context.Context, teamID string, req m
 	if len(userRecords) == 0 {
 		return nil, errors.NotFoundWithDetails("User not found", map[string]interface{}{
 			"email": req.Email,
-		})
+		}).WithErrorCode(errors.CodeUserNotFound)
 	}
 
 	userRecord := userRecords[0]
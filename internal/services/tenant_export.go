@@ -0,0 +1,440 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// tenantExportURLExpiry is how long the manifest URL of a tenant export
+// stays valid
+const tenantExportURLExpiry = 24 * time.Hour
+
+// tenantExportStorageKeys is the distinct storage keys of document x's
+// current file and of the files of its versions, from version_paths
+const tenantExportStorageKeys = `reduce(keys = [], key IN [x.storage_path] + version_paths |
+	CASE WHEN key IS NULL OR key = '' OR key IN keys THEN keys ELSE keys + key END)`
+
+// TenantExportService exports everything a tenant holds, for offboarding:
+// its spaces, notebooks, documents with their versions, the stored files
+// of every version and the audit events of its spaces. The bundle is
+// written to object storage under exports/tenants/ with a manifest that
+// lists each file's SHA-256 hash. Like notebook exports, bundles are not
+// deleted by Aether.
+type TenantExportService struct {
+	neo4j   *database.Neo4jClient
+	storage StorageService
+	audit   *AuditService
+	logger  *logger.Logger
+}
+
+// NewTenantExportService creates a tenant export service. Exports respond
+// 503 while storage is nil.
+func NewTenantExportService(neo4j *database.Neo4jClient, storage StorageService, log *logger.Logger) *TenantExportService {
+	return &TenantExportService{
+		neo4j:   neo4j,
+		storage: storage,
+		logger:  log.WithService("tenant_export"),
+	}
+}
+
+// SetAuditService makes exports include the audit events of the tenant's
+// spaces
+func (s *TenantExportService) SetAuditService(audit *AuditService) {
+	s.audit = audit
+}
+
+// tenantExportScope is what a tenant export covers, counted up front to
+// report progress against
+type tenantExportScope struct {
+	spaceIDs    []string
+	notebooks   int
+	documents   int
+	files       int
+	auditEvents int
+}
+
+// total is the number of items the export writes
+func (s tenantExportScope) total() int {
+	return len(s.spaceIDs) + s.notebooks + s.documents + s.files + s.auditEvents
+}
+
+// CheckTenant checks that a tenant exists and that exports are available,
+// so an export fails fast rather than in a job the caller has to poll
+func (s *TenantExportService) CheckTenant(ctx context.Context, tenantID string) error {
+	if s.storage == nil {
+		return errors.ServiceUnavailable("Storage is not available for exports")
+	}
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "tenant_export.check"), `
+		MATCH (sp:Space {tenant_id: $tenant_id})
+		RETURN count(sp) AS spaces
+	`, map[string]interface{}{"tenant_id": tenantID})
+	if err != nil {
+		return errors.Database("Failed to look up tenant", err)
+	}
+	if recordInt(result.Records, "spaces") == 0 {
+		return errors.NotFoundWithDetails("Tenant not found", map[string]interface{}{
+			"tenant_id": tenantID,
+		}).WithErrorCode(errors.CodeTenantNotFound)
+	}
+	return nil
+}
+
+// scope counts what an export of the tenant covers
+func (s *TenantExportService) scope(ctx context.Context, tenantID string) (*tenantExportScope, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "tenant_export.scope"), `
+		OPTIONAL MATCH (sp:Space {tenant_id: $tenant_id})
+		WITH collect(sp.id) AS space_ids
+		CALL {
+			MATCH (n:Notebook {tenant_id: $tenant_id})
+			RETURN count(n) AS notebooks
+		}
+		CALL {
+			MATCH (x:Document {tenant_id: $tenant_id})
+			OPTIONAL MATCH (x)-[:HAS_VERSION]->(v:DocumentVersion)
+			WITH x, collect(v.storage_path) AS version_paths
+			RETURN count(x) AS documents, sum(size(`+tenantExportStorageKeys+`)) AS files
+		}
+		CALL {
+			WITH space_ids
+			MATCH (a:AuditEvent)
+			WHERE a.space_id IN space_ids
+			RETURN count(a) AS audit_events
+		}
+		RETURN space_ids, notebooks, documents, files, audit_events
+	`, map[string]interface{}{"tenant_id": tenantID})
+	if err != nil {
+		return nil, errors.Database("Failed to count tenant data", err)
+	}
+
+	scope := &tenantExportScope{
+		notebooks: int(recordInt(result.Records, "notebooks")),
+		documents: int(recordInt(result.Records, "documents")),
+		files:     int(recordInt(result.Records, "files")),
+	}
+	if s.audit != nil {
+		scope.auditEvents = int(recordInt(result.Records, "audit_events"))
+	}
+	if len(result.Records) > 0 {
+		value, _ := result.Records[0].Get("space_ids")
+		ids, _ := value.([]interface{})
+		for _, id := range ids {
+			if spaceID, ok := id.(string); ok {
+				scope.spaceIDs = append(scope.spaceIDs, spaceID)
+			}
+		}
+	}
+	return scope, nil
+}
+
+// tenantExportBundle writes the files of a tenant export and records them
+// in its manifest
+type tenantExportBundle struct {
+	storage  StorageService
+	prefix   string
+	manifest *models.TenantExportManifest
+	bytes    int64
+}
+
+// put stores a file of the bundle, hashing it for the manifest
+func (b *tenantExportBundle) put(ctx context.Context, file models.TenantExportFile, data []byte, contentType string) error {
+	sum := sha256.Sum256(data)
+	file.SHA256 = hex.EncodeToString(sum[:])
+	file.Bytes = int64(len(data))
+	if _, err := b.storage.UploadFile(ctx, b.prefix+file.Path, data, contentType); err != nil {
+		return errors.ExternalService("Failed to store tenant export", err)
+	}
+	b.manifest.Files = append(b.manifest.Files, file)
+	b.bytes += file.Bytes
+	return nil
+}
+
+// tenantExportDocumentFile is a stored file of a document to copy into a
+// bundle
+type tenantExportDocumentFile struct {
+	documentID string
+	key        string
+}
+
+// Export writes the bundle of a tenant and returns where it is, reporting
+// each space, notebook, document, file and audit event it writes through
+// progress. Stored files that cannot be read are listed in the manifest as
+// missing and counted as failed; any other failure fails the export.
+func (s *TenantExportService) Export(ctx context.Context, tenantID string, progress func(models.JobProgress)) (*models.TenantExport, error) {
+	if err := s.CheckTenant(ctx, tenantID); err != nil {
+		return nil, err
+	}
+	scope, err := s.scope(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	bundle := &tenantExportBundle{
+		storage: s.storage,
+		prefix:  fmt.Sprintf("exports/tenants/%s/%s/", tenantID, now.Format("20060102T150405Z")),
+		manifest: &models.TenantExportManifest{
+			Version:   models.TenantExportManifestVersion,
+			TenantID:  tenantID,
+			CreatedAt: now,
+			Counts:    make(map[string]int),
+			Files:     []models.TenantExportFile{},
+		},
+	}
+	report := models.JobProgress{Total: scope.total()}
+	advance := func(failed bool) {
+		if failed {
+			report.Failed++
+		} else {
+			report.Completed++
+		}
+		progress(report)
+	}
+	params := map[string]interface{}{"tenant_id": tenantID}
+
+	spaces, err := s.writeSection(ctx, bundle, "spaces.ndjson", "tenant_export.spaces", `
+		MATCH (x:Space {tenant_id: $tenant_id})
+		RETURN properties(x) AS properties
+		ORDER BY x.created_at
+	`, params, advance, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	notebooks, err := s.writeSection(ctx, bundle, "notebooks.ndjson", "tenant_export.notebooks", `
+		MATCH (x:Notebook {tenant_id: $tenant_id})
+		RETURN properties(x) AS properties
+		ORDER BY x.created_at
+	`, params, advance, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Documents carry their versions, and name the files to copy
+	var files []tenantExportDocumentFile
+	documents, err := s.writeSection(ctx, bundle, "documents.ndjson", "tenant_export.documents", `
+		MATCH (x:Document {tenant_id: $tenant_id})
+		OPTIONAL MATCH (x)-[:HAS_VERSION]->(v:DocumentVersion)
+		WITH x, collect(properties(v)) AS versions, collect(v.storage_path) AS version_paths
+		RETURN properties(x) AS properties, versions, `+tenantExportStorageKeys+` AS storage_keys
+		ORDER BY x.created_at
+	`, params, advance, func(record *neo4j.Record, line map[string]interface{}) {
+		if versions, ok := record.Get("versions"); ok {
+			line["versions"] = exportValue(versions)
+		}
+		documentID, _ := line["id"].(string)
+		keys, _ := record.Get("storage_keys")
+		list, _ := keys.([]interface{})
+		for _, key := range list {
+			if name, ok := key.(string); ok {
+				files = append(files, tenantExportDocumentFile{documentID: documentID, key: name})
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	auditEvents, err := s.writeAuditEvents(ctx, bundle, scope.spaceIDs, advance)
+	if err != nil {
+		return nil, err
+	}
+
+	copied := 0
+	for _, file := range files {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		ok, err := s.copyFile(ctx, bundle, tenantID, file)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			copied++
+		}
+		advance(!ok)
+	}
+
+	bundle.manifest.Counts["spaces"] = spaces
+	bundle.manifest.Counts["notebooks"] = notebooks
+	bundle.manifest.Counts["documents"] = documents
+	bundle.manifest.Counts["files"] = copied
+	bundle.manifest.Counts["audit_events"] = auditEvents
+	manifest, err := json.MarshalIndent(bundle.manifest, "", "  ")
+	if err != nil {
+		return nil, errors.Internal("Failed to encode tenant export manifest")
+	}
+	manifestKey := bundle.prefix + "manifest.json"
+	if _, err := s.storage.UploadFile(ctx, manifestKey, manifest, "application/json"); err != nil {
+		return nil, errors.ExternalService("Failed to store tenant export manifest", err)
+	}
+	url, err := s.storage.GetFileURL(ctx, manifestKey, tenantExportURLExpiry)
+	if err != nil {
+		return nil, errors.ExternalService("Failed to sign tenant export URL", err)
+	}
+	manifestSum := sha256.Sum256(manifest)
+
+	s.logger.Info("Exported tenant",
+		zap.String("tenant_id", tenantID),
+		zap.String("prefix", bundle.prefix),
+		zap.Int("documents", documents),
+		zap.Int("files", copied),
+		zap.Int("missing_files", len(bundle.manifest.Missing)),
+		zap.Int64("bytes", bundle.bytes))
+
+	return &models.TenantExport{
+		TenantID:       tenantID,
+		Prefix:         bundle.prefix,
+		ManifestKey:    manifestKey,
+		ManifestSHA256: hex.EncodeToString(manifestSum[:]),
+		Spaces:         spaces,
+		Notebooks:      notebooks,
+		Documents:      documents,
+		Files:          copied,
+		MissingFiles:   len(bundle.manifest.Missing),
+		AuditEvents:    auditEvents,
+		Bytes:          bundle.bytes,
+		ManifestURL:    url,
+		ExpiresAt:      now.Add(tenantExportURLExpiry),
+	}, nil
+}
+
+// writeSection writes the nodes a query returns as the properties column
+// to the bundle as newline-delimited JSON and returns how many it wrote.
+// extend adds to each line from the rest of its record.
+func (s *TenantExportService) writeSection(ctx context.Context, bundle *tenantExportBundle, path, queryName, query string, params map[string]interface{}, advance func(failed bool), extend func(*neo4j.Record, map[string]interface{})) (int, error) {
+	var buf strings.Builder
+	encoder := json.NewEncoder(&buf)
+	written := 0
+	_, err := s.neo4j.StreamQuery(database.WithQueryName(ctx, queryName), query, params, func(record *neo4j.Record) error {
+		value, _ := record.Get("properties")
+		properties, _ := value.(map[string]interface{})
+		line := exportProperties(properties)
+		if extend != nil {
+			extend(record, line)
+		}
+		if err := encoder.Encode(line); err != nil {
+			return err
+		}
+		written++
+		advance(false)
+		return nil
+	})
+	if err != nil {
+		return written, errors.Database("Failed to export "+strings.TrimSuffix(path, ".ndjson"), err)
+	}
+	return written, bundle.put(ctx, models.TenantExportFile{Path: path}, []byte(buf.String()), "application/x-ndjson")
+}
+
+// writeAuditEvents writes the audit events of the tenant's spaces to the
+// bundle, oldest first
+func (s *TenantExportService) writeAuditEvents(ctx context.Context, bundle *tenantExportBundle, spaceIDs []string, advance func(failed bool)) (int, error) {
+	if s.audit == nil || len(spaceIDs) == 0 {
+		return 0, nil
+	}
+	var buf strings.Builder
+	encoder := json.NewEncoder(&buf)
+	count, err := s.audit.Export(ctx, models.AuditFilter{SpaceIDs: spaceIDs}, func(event *models.AuditEvent) error {
+		if err := encoder.Encode(event); err != nil {
+			return err
+		}
+		advance(false)
+		return nil
+	})
+	if err != nil {
+		return count, err
+	}
+	return count, bundle.put(ctx, models.TenantExportFile{Path: "audit_events.ndjson"}, []byte(buf.String()), "application/x-ndjson")
+}
+
+// copyFile copies a document's stored file into the bundle under files/,
+// reporting false when it cannot be read, which the manifest records
+func (s *TenantExportService) copyFile(ctx context.Context, bundle *tenantExportBundle, tenantID string, file tenantExportDocumentFile) (bool, error) {
+	entry := models.TenantExportFile{
+		Path:       "files/" + file.key,
+		DocumentID: file.documentID,
+		SourceKey:  file.key,
+	}
+	data, err := s.readFile(ctx, tenantID, file.key)
+	if err != nil {
+		s.logger.Warn("Stored file missing from tenant export",
+			zap.String("tenant_id", tenantID),
+			zap.String("document_id", file.documentID),
+			zap.String("key", file.key),
+			zap.Error(err))
+		entry.Error = err.Error()
+		bundle.manifest.Missing = append(bundle.manifest.Missing, entry)
+		return false, nil
+	}
+	return true, bundle.put(ctx, entry, data, "application/octet-stream")
+}
+
+// readFile reads a file from the tenant's bucket
+func (s *TenantExportService) readFile(ctx context.Context, tenantID, key string) ([]byte, error) {
+	reader, err := s.storage.OpenFileFromTenantBucket(ctx, tenantID, key)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// exportProperties converts the properties of a node for export, leaving
+// out credentials and internal bookkeeping such as claim locks
+func exportProperties(properties map[string]interface{}) map[string]interface{} {
+	line := make(map[string]interface{}, len(properties))
+	for key, value := range properties {
+		if strings.HasPrefix(key, "_") || strings.Contains(key, "api_key") {
+			continue
+		}
+		line[key] = exportValue(value)
+	}
+	return line
+}
+
+// exportValue converts a Neo4j value to one JSON encodes faithfully. Dates,
+// times and durations encode as empty objects otherwise, so they are
+// written as ISO 8601 strings.
+func exportValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[key] = exportValue(item)
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(v))
+		for i, item := range v {
+			converted[i] = exportValue(item)
+		}
+		return converted
+	case time.Time:
+		return v
+	case dbtype.Date:
+		return v.Time().Format(time.DateOnly)
+	case dbtype.LocalDateTime:
+		return v.Time().Format("2006-01-02T15:04:05.999999999")
+	case dbtype.LocalTime:
+		return v.Time().Format("15:04:05.999999999")
+	case dbtype.Time:
+		return v.Time().Format("15:04:05.999999999Z07:00")
+	case dbtype.Duration:
+		return v.String()
+	}
+	return value
+}
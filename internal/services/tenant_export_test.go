@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	stderrors "errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+// exportStorage keeps uploaded files in memory and serves the tenant
+// files in stored
+type exportStorage struct {
+	StorageService
+	stored   map[string]string
+	uploaded map[string][]byte
+}
+
+func (s *exportStorage) UploadFile(ctx context.Context, key string, data []byte, contentType string) (string, error) {
+	s.uploaded[key] = data
+	return key, nil
+}
+
+func (s *exportStorage) OpenFileFromTenantBucket(ctx context.Context, tenantID, key string) (io.ReadCloser, error) {
+	data, ok := s.stored[tenantID+"/"+key]
+	if !ok {
+		return nil, stderrors.New("no such key")
+	}
+	return io.NopCloser(strings.NewReader(data)), nil
+}
+
+func TestTenantExportCopyFileHashesIntoManifest(t *testing.T) {
+	storage := &exportStorage{
+		stored:   map[string]string{"tenant-1/documents/doc-1/report.pdf": "%PDF-1.4"},
+		uploaded: make(map[string][]byte),
+	}
+	service := NewTenantExportService(nil, storage, setupTestLogger(t))
+	bundle := &tenantExportBundle{
+		storage:  storage,
+		prefix:   "exports/tenants/tenant-1/20261016T000000Z/",
+		manifest: &models.TenantExportManifest{},
+	}
+
+	ok, err := service.copyFile(context.Background(), bundle, "tenant-1", tenantExportDocumentFile{documentID: "doc-1", key: "documents/doc-1/report.pdf"})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("%PDF-1.4"), storage.uploaded["exports/tenants/tenant-1/20261016T000000Z/files/documents/doc-1/report.pdf"])
+
+	sum := sha256.Sum256([]byte("%PDF-1.4"))
+	require.Len(t, bundle.manifest.Files, 1)
+	assert.Equal(t, models.TenantExportFile{
+		Path:       "files/documents/doc-1/report.pdf",
+		SHA256:     hex.EncodeToString(sum[:]),
+		Bytes:      8,
+		DocumentID: "doc-1",
+		SourceKey:  "documents/doc-1/report.pdf",
+	}, bundle.manifest.Files[0])
+	assert.Equal(t, int64(8), bundle.bytes)
+}
+
+func TestTenantExportCopyFileRecordsMissingFiles(t *testing.T) {
+	storage := &exportStorage{stored: map[string]string{}, uploaded: make(map[string][]byte)}
+	service := NewTenantExportService(nil, storage, setupTestLogger(t))
+	bundle := &tenantExportBundle{storage: storage, manifest: &models.TenantExportManifest{}}
+
+	ok, err := service.copyFile(context.Background(), bundle, "tenant-1", tenantExportDocumentFile{documentID: "doc-1", key: "documents/doc-1/gone.pdf"})
+	require.NoError(t, err, "a missing file does not fail the export")
+	assert.False(t, ok)
+	assert.Empty(t, bundle.manifest.Files)
+	require.Len(t, bundle.manifest.Missing, 1)
+	assert.Equal(t, "doc-1", bundle.manifest.Missing[0].DocumentID)
+	assert.NotEmpty(t, bundle.manifest.Missing[0].Error)
+	assert.Empty(t, storage.uploaded)
+}
+
+func TestExportPropertiesLeavesOutCredentials(t *testing.T) {
+	created := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+	line := exportProperties(map[string]interface{}{
+		"id":             "space-1",
+		"tenant_api_key": "secret",
+		"_claim_lock":    true,
+		"created_at":     created,
+		"retain_until":   dbtype.Date(created),
+		"tags":           []interface{}{"a", dbtype.Date(created)},
+	})
+
+	assert.Equal(t, map[string]interface{}{
+		"id":           "space-1",
+		"created_at":   created,
+		"retain_until": "2026-10-16",
+		"tags":         []interface{}{"a", "2026-10-16"},
+	}, line)
+}
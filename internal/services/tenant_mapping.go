@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// tenantMappingTTL is how long a resolved mapping is reused before the
+// space and AudiModal are consulted again
+const tenantMappingTTL = 24 * time.Hour
+
+// AudiModalMapping is the AudiModal tenant and datasource a tenant's files
+// are processed under
+type AudiModalMapping struct {
+	TenantUUID     string `json:"tenant_uuid"`
+	DataSourceUUID string `json:"datasource_uuid"`
+}
+
+// TenantMappingService resolves the AudiModal tenant and datasource of an
+// aether tenant. The AudiModal tenant is the one recorded on the tenant's
+// Space; spaces without one get an AudiModal tenant provisioned and
+// recorded. Datasources are provisioned on demand. Mappings are cached,
+// in Redis when it is enabled, so replicas share them.
+type TenantMappingService struct {
+	neo4j     *database.Neo4jClient
+	spaces    *SpaceService
+	audiModal *AudiModalService
+	config    *config.AudiModalConfig
+	cache     ResultCache
+	logger    *logger.Logger
+}
+
+// NewTenantMappingService creates a tenant mapping service. Without cache
+// mappings are cached in-process.
+func NewTenantMappingService(neo4j *database.Neo4jClient, spaces *SpaceService, audiModal *AudiModalService, cfg *config.AudiModalConfig, cache ResultCache, log *logger.Logger) *TenantMappingService {
+	if cache == nil {
+		cache = NewLocalResultCache()
+	}
+	return &TenantMappingService{
+		neo4j:     neo4j,
+		spaces:    spaces,
+		audiModal: audiModal,
+		config:    cfg,
+		cache:     cache,
+		logger:    log.WithService("tenant_mapping_service"),
+	}
+}
+
+// tenantMappingCacheKey names the cached mapping of a tenant
+func tenantMappingCacheKey(tenantID string) string {
+	return fmt.Sprintf("tas:audimodal:mapping:%s", tenantID)
+}
+
+// Resolve returns the AudiModal tenant and datasource of an aether tenant,
+// provisioning them when the tenant has none yet
+func (s *TenantMappingService) Resolve(ctx context.Context, tenantID string) (*AudiModalMapping, error) {
+	if tenantID == "" {
+		return nil, errors.Validation("Tenant ID is required", nil)
+	}
+	if mapping, ok := s.cached(ctx, tenantID); ok {
+		return mapping, nil
+	}
+
+	mapping, err := s.resolve(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := json.Marshal(mapping)
+	if err == nil {
+		err = s.cache.Set(ctx, tenantMappingCacheKey(tenantID), string(value), tenantMappingTTL)
+	}
+	if err != nil {
+		s.logger.Warn("Failed to cache AudiModal tenant mapping", zap.String("tenant_id", tenantID), zap.Error(err))
+	}
+
+	s.logger.Info("Resolved AudiModal tenant mapping",
+		zap.String("tenant_id", tenantID),
+		zap.String("audimodal_tenant_uuid", mapping.TenantUUID),
+		zap.String("audimodal_datasource_uuid", mapping.DataSourceUUID))
+	return mapping, nil
+}
+
+// cached returns the cached mapping of a tenant, if any
+func (s *TenantMappingService) cached(ctx context.Context, tenantID string) (*AudiModalMapping, bool) {
+	value, err := s.cache.Get(ctx, tenantMappingCacheKey(tenantID))
+	if err != nil {
+		s.logger.Warn("Failed to read cached AudiModal tenant mapping", zap.String("tenant_id", tenantID), zap.Error(err))
+		return nil, false
+	}
+	if value == "" {
+		return nil, false
+	}
+	var mapping AudiModalMapping
+	if err := json.Unmarshal([]byte(value), &mapping); err != nil || mapping.TenantUUID == "" || mapping.DataSourceUUID == "" {
+		return nil, false
+	}
+	return &mapping, true
+}
+
+// resolve looks up or provisions the mapping of a tenant
+func (s *TenantMappingService) resolve(ctx context.Context, tenantID string) (*AudiModalMapping, error) {
+	tenantUUID, err := s.audiModalTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if tenantUUID == "" {
+		// A shared AudiModal tenant may be configured for tenants without
+		// their own, e.g. in development
+		if s.config != nil && isValidUUID(s.config.DefaultTenantUUID) && isValidUUID(s.config.DefaultDataSourceUUID) {
+			return &AudiModalMapping{
+				TenantUUID:     s.config.DefaultTenantUUID,
+				DataSourceUUID: s.config.DefaultDataSourceUUID,
+			}, nil
+		}
+		if tenantUUID, err = s.provisionTenant(ctx, tenantID); err != nil {
+			return nil, err
+		}
+	}
+
+	dataSourceUUID, err := s.audiModal.getOrCreateDataSource(ctx, tenantUUID)
+	if err != nil {
+		return nil, errors.ExternalService("Failed to provision AudiModal datasource", err)
+	}
+
+	return &AudiModalMapping{
+		TenantUUID:     tenantUUID,
+		DataSourceUUID: dataSourceUUID,
+	}, nil
+}
+
+// audiModalTenant returns the AudiModal tenant recorded for a tenant, or ""
+// when it has none. Tenants created through AudiModal carry its tenant ID.
+func (s *TenantMappingService) audiModalTenant(ctx context.Context, tenantID string) (string, error) {
+	tenant, err := s.spaces.ResolveTenant(ctx, models.TenantLookupByTenantID, tenantID)
+	if err != nil && !errors.IsNotFound(err) {
+		return "", err
+	}
+	if tenant != nil {
+		if id := strings.TrimPrefix(tenant.AudimodalTenantID, "tenant_"); isValidUUID(id) {
+			return id, nil
+		}
+	}
+	if id := strings.TrimPrefix(tenantID, "tenant_"); isValidUUID(id) {
+		return id, nil
+	}
+	return "", nil
+}
+
+// provisionTenant finds or creates the AudiModal tenant of a tenant and
+// records it on the tenant's spaces
+func (s *TenantMappingService) provisionTenant(ctx context.Context, tenantID string) (string, error) {
+	strippedID := strings.TrimPrefix(tenantID, "tenant_")
+	tenantName := fmt.Sprintf("aether-%s", strippedID)
+
+	var tenantUUID string
+	existing, err := s.audiModal.GetTenantByName(ctx, tenantName)
+	if err != nil {
+		s.logger.Warn("Failed to look up AudiModal tenant, will try to create it",
+			zap.String("tenant_name", tenantName),
+			zap.Error(err))
+	}
+	if existing != nil {
+		tenantUUID = existing.ID
+	} else {
+		s.logger.Info("Creating AudiModal tenant",
+			zap.String("tenant_id", tenantID),
+			zap.String("tenant_name", tenantName))
+
+		resp, err := s.audiModal.CreateTenant(ctx, CreateTenantRequest{
+			Name:         tenantName,
+			DisplayName:  fmt.Sprintf("Aether Tenant %s", strippedID),
+			BillingPlan:  "personal",
+			BillingEmail: "noreply@aether.ai",
+			Quotas: TenantQuotas{
+				FilesPerHour:         100,
+				StorageGB:            10,
+				ComputeHours:         10,
+				APIRequestsPerMinute: 100,
+				MaxConcurrentJobs:    2,
+				MaxFileSize:          104857600, // 100MB
+				MaxChunksPerFile:     500,
+				VectorStorageGB:      5,
+			},
+			Compliance: TenantCompliance{
+				GDPR:               true,
+				DataResidency:      []string{},
+				RetentionDays:      365,
+				EncryptionRequired: true,
+			},
+			ContactInfo: TenantContactInfo{
+				AdminEmail:     "noreply@aether.ai",
+				SecurityEmail:  "noreply@aether.ai",
+				BillingEmail:   "noreply@aether.ai",
+				TechnicalEmail: "noreply@aether.ai",
+			},
+		})
+		if err != nil {
+			return "", errors.ExternalService("Failed to provision AudiModal tenant", err)
+		}
+		// AudiModal answers with tenant_<UUID>
+		tenantUUID = strings.TrimPrefix(resp.TenantID, "tenant_")
+	}
+
+	query := `
+		MATCH (sp:Space {tenant_id: $tenant_id})
+		WHERE coalesce(sp.audimodal_tenant_id, '') = ''
+		SET sp.audimodal_tenant_id = $audimodal_tenant_id
+	`
+	params := map[string]interface{}{
+		"tenant_id":           tenantID,
+		"audimodal_tenant_id": tenantUUID,
+	}
+	if _, err := s.neo4j.ExecuteQueryWithLogging(database.WithQueryName(ctx, "record_audimodal_tenant"), query, params); err != nil {
+		// The mapping still holds; the tenant is looked up again next time
+		s.logger.Warn("Failed to record AudiModal tenant on space",
+			zap.String("tenant_id", tenantID),
+			zap.String("audimodal_tenant_uuid", tenantUUID),
+			zap.Error(err))
+	}
+
+	return tenantUUID, nil
+}
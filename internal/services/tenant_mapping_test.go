@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+func TestTenantMappingResolveCached(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLocalResultCache()
+	mappings := NewTenantMappingService(nil, nil, nil, nil, cache, setupTestLogger(t))
+
+	_, err := mappings.Resolve(ctx, "")
+	assert.True(t, errors.IsValidation(err))
+
+	value := `{"tenant_uuid":"9b2f6d0e-3c1a-4d5e-8f7a-1b2c3d4e5f60","datasource_uuid":"0f1e2d3c-4b5a-6978-8a9b-0c1d2e3f4a5b"}`
+	require.NoError(t, cache.Set(ctx, tenantMappingCacheKey("tenant_1766596584"), value, time.Hour))
+
+	mapping, err := mappings.Resolve(ctx, "tenant_1766596584")
+	require.NoError(t, err)
+	assert.Equal(t, "9b2f6d0e-3c1a-4d5e-8f7a-1b2c3d4e5f60", mapping.TenantUUID)
+	assert.Equal(t, "0f1e2d3c-4b5a-6978-8a9b-0c1d2e3f4a5b", mapping.DataSourceUUID)
+}
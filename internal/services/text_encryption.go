@@ -0,0 +1,415 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/fieldcrypt"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// textEncryptionRequestBudget bounds how long a change of a space's setting
+// migrates its documents before answering; repeating the change carries on
+// with the rest
+const textEncryptionRequestBudget = 20 * time.Second
+
+// unmigratedDocumentsFilter matches the documents of a space whose text is
+// not yet stored the way $encrypt asks
+const unmigratedDocumentsFilter = `d.extracted_text <> '' AND (d.extracted_text STARTS WITH $prefix) <> $encrypt`
+
+// unmigratedVersionsFilter matches the earlier versions of a space's
+// documents whose kept text is not yet stored the way $encrypt asks
+const unmigratedVersionsFilter = `v.extracted_text <> '' AND (v.extracted_text STARTS WITH $prefix) <> $encrypt`
+
+// migrateTextQuery stores the migrated text of documents, unless it changed
+// since it was read
+const migrateTextQuery = `
+	UNWIND $rows AS row
+	MATCH (d:Document {id: row.id, tenant_id: $tenant_id})
+	WHERE d.extracted_text = row.previous
+	SET d.extracted_text = row.text,
+	    d.search_text = row.search_text,
+	    d.processing_result = coalesce(row.processing_result, d.processing_result)
+`
+
+// TextEncryptionService encrypts the extracted text of documents at rest in
+// the spaces that turn it on, with AES-256-GCM under a key per tenant. Text
+// is encrypted when written and decrypted when documents are read, so
+// callers see plaintext. Encrypted text is left out of search_text and the
+// fulltext index, so documents of those spaces are found by name,
+// description and tags only.
+type TextEncryptionService struct {
+	neo4j  *database.Neo4jClient
+	cipher *fieldcrypt.Cipher
+	audit  *AuditService
+	config config.TextEncryptionConfig
+	logger *logger.Logger
+}
+
+// NewTextEncryptionService creates a text encryption service with the
+// configured master key
+func NewTextEncryptionService(neo4j *database.Neo4jClient, audit *AuditService, cfg config.TextEncryptionConfig, log *logger.Logger) (*TextEncryptionService, error) {
+	key, err := fieldcrypt.ParseKey(cfg.MasterKey)
+	if err != nil {
+		return nil, err
+	}
+	cipher, err := fieldcrypt.New(key)
+	if err != nil {
+		return nil, err
+	}
+	return &TextEncryptionService{
+		neo4j:  neo4j,
+		cipher: cipher,
+		audit:  audit,
+		config: cfg,
+		logger: log.WithService("text_encryption_service"),
+	}, nil
+}
+
+// Seal returns the extracted text of a document as it is to be stored:
+// encrypted with its tenant's key when its space encrypts text at rest,
+// which the returned flag reports. A nil service stores text as it is.
+func (s *TextEncryptionService) Seal(ctx context.Context, documentID, tenantID, text string) (string, bool, error) {
+	if s == nil {
+		return text, false, nil
+	}
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "text_encryption.document_space"), `
+		MATCH (d:Document {id: $document_id})
+		OPTIONAL MATCH (sp:Space {id: d.space_id})
+		RETURN coalesce(sp.text_encryption, false) AS encrypted
+	`, map[string]interface{}{"document_id": documentID})
+	if err != nil {
+		return "", false, errors.Database("Failed to read the encryption of the document's space", err)
+	}
+	if len(result.Records) == 0 {
+		return text, false, nil
+	}
+	if encrypted, _ := result.Records[0].Get("encrypted"); encrypted != true {
+		return text, false, nil
+	}
+	sealed, err := s.cipher.Encrypt(tenantID, text)
+	if err != nil {
+		return "", false, errors.InternalWithCause("Failed to encrypt the extracted text", err)
+	}
+	return sealed, true, nil
+}
+
+// Open returns stored extracted text decrypted, and whether it was
+// encrypted. Text that cannot be decrypted, such as without the key, reads
+// as empty rather than as ciphertext.
+func (s *TextEncryptionService) Open(tenantID, stored string) (string, bool) {
+	if !fieldcrypt.IsEncrypted(stored) {
+		return stored, false
+	}
+	if s == nil {
+		return "", true
+	}
+	text, err := s.cipher.Decrypt(tenantID, stored)
+	if err != nil {
+		s.logger.Warn("Failed to decrypt extracted text", zap.String("tenant_id", tenantID), zap.Error(err))
+		return "", true
+	}
+	return text, true
+}
+
+// Excerpt returns the first chars characters of stored extracted text,
+// decrypted. Queries reading an excerpt return encrypted text whole, since
+// a part of it cannot be decrypted; see textExcerptColumn.
+func (s *TextEncryptionService) Excerpt(tenantID, stored string, chars int) string {
+	text, _ := s.Open(tenantID, stored)
+	if runes := []rune(text); len(runes) > chars {
+		return string(runes[:chars])
+	}
+	return text
+}
+
+// textExcerptColumn returns the Cypher expression of the first $param
+// characters of a document's extracted text, or of all of it when it is
+// encrypted, to be cut with Excerpt once decrypted
+func textExcerptColumn(alias, param string) string {
+	return fmt.Sprintf("CASE WHEN %[1]s.extracted_text STARTS WITH '%[3]s' THEN %[1]s.extracted_text ELSE substring(coalesce(%[1]s.extracted_text, ''), 0, $%[2]s) END",
+		alias, param, fieldcrypt.Prefix)
+}
+
+// SetSpaceEncryption turns the encryption at rest of a space's extracted
+// text on or off, then encrypts, or decrypts, the text its documents
+// already have. Documents written from then on follow the new setting.
+// Migration stops after textEncryptionRequestBudget; the response counts
+// the documents remaining, which repeating the change migrates.
+func (s *TextEncryptionService) SetSpaceEncryption(ctx context.Context, spaceID string, enabled bool, actorID string) (*models.SpaceEncryption, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "text_encryption.set"), `
+		MATCH (sp:Space {id: $space_id})
+		WITH sp, coalesce(sp.text_encryption, false) AS previous
+		SET sp.text_encryption = $enabled, sp.updated_at = datetime()
+		RETURN sp.tenant_id AS tenant_id, previous
+	`, map[string]interface{}{"space_id": spaceID, "enabled": enabled})
+	if err != nil {
+		return nil, errors.Database("Failed to update space encryption", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, errors.NotFoundWithDetails("Space not found", map[string]interface{}{"space_id": spaceID})
+	}
+	tenantID := recordString(result.Records[0], "tenant_id")
+	previous, _ := result.Records[0].Get("previous")
+
+	migrated, err := s.migrate(ctx, spaceID, tenantID, enabled)
+	if err != nil {
+		return nil, err
+	}
+	remaining, err := s.unmigrated(ctx, spaceID, tenantID, enabled)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.FromContext(ctx).Info("Space text encryption changed",
+		zap.Bool("audit", true),
+		zap.String("space_id", spaceID),
+		zap.Bool("enabled", enabled),
+		zap.Int("migrated", migrated),
+		zap.Int("remaining", remaining),
+		zap.String("actor_id", actorID),
+	)
+	recordAuditEvent(ctx, s.audit, s.logger, &models.AuditEvent{
+		Action:       "space.encryption.update",
+		ResourceType: "space",
+		ResourceID:   spaceID,
+		SpaceID:      spaceID,
+		TenantID:     tenantID,
+		ActorID:      actorID,
+		Source:       ConfigSourceAdmin,
+		Details: map[string]interface{}{
+			"enabled":   enabled,
+			"previous":  previous == true,
+			"migrated":  migrated,
+			"remaining": remaining,
+		},
+	})
+
+	return &models.SpaceEncryption{
+		SpaceID:   spaceID,
+		Enabled:   enabled,
+		Migrated:  migrated,
+		Remaining: remaining,
+	}, nil
+}
+
+// migrate stores the text of a space's documents the way encrypt asks, a
+// batch at a time, then the text kept on their earlier versions, returning
+// how many texts it migrated
+func (s *TextEncryptionService) migrate(ctx context.Context, spaceID, tenantID string, encrypt bool) (int, error) {
+	deadline := time.Now().Add(textEncryptionRequestBudget)
+	migrated := 0
+	for _, batch := range []func(context.Context, string, string, bool) (int, int, error){s.migrateBatch, s.migrateVersionBatch} {
+		for time.Now().Before(deadline) && ctx.Err() == nil {
+			read, written, err := batch(ctx, spaceID, tenantID, encrypt)
+			if err != nil {
+				return migrated, err
+			}
+			migrated += written
+			// A batch none of which could be migrated would be read again
+			if read < s.config.BatchSize || written == 0 {
+				break
+			}
+		}
+	}
+	return migrated, nil
+}
+
+// migrateBatch migrates a batch of a space's documents, returning how many
+// it read and how many of them it migrated. Text that cannot be decrypted
+// is left as it is.
+func (s *TextEncryptionService) migrateBatch(ctx context.Context, spaceID, tenantID string, encrypt bool) (int, int, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "text_encryption.read_batch"), `
+		MATCH (d:Document {space_id: $space_id, tenant_id: $tenant_id})
+		WHERE `+unmigratedDocumentsFilter+`
+		RETURN d.id AS id, d.extracted_text AS text, d.processing_result AS processing_result,
+		       d.name AS name, d.description AS description, d.tags AS tags
+		LIMIT $limit
+	`, map[string]interface{}{
+		"space_id":  spaceID,
+		"tenant_id": tenantID,
+		"prefix":    fieldcrypt.Prefix,
+		"encrypt":   encrypt,
+		"limit":     s.config.BatchSize,
+	})
+	if err != nil {
+		return 0, 0, errors.Database("Failed to read documents to migrate", err)
+	}
+
+	rows := make([]map[string]interface{}, 0, len(result.Records))
+	for _, record := range result.Records {
+		row, err := s.migratedRow(tenantID, encrypt, record.AsMap())
+		if err != nil {
+			s.logger.Warn("Failed to migrate extracted text",
+				zap.String("document_id", recordString(record, "id")),
+				zap.Bool("encrypt", encrypt),
+				zap.Error(err))
+			continue
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) > 0 {
+		params := map[string]interface{}{"tenant_id": tenantID}
+		if _, err := s.neo4j.WriteBatch(database.WithQueryName(ctx, "text_encryption.write_batch"), migrateTextQuery, rows, params); err != nil {
+			return 0, 0, errors.Database("Failed to store migrated text", err)
+		}
+	}
+	return len(result.Records), len(rows), nil
+}
+
+// migrateVersionBatch migrates the text kept on a batch of the earlier
+// versions of a space's documents, returning how many it read and how many
+// of them it migrated
+func (s *TextEncryptionService) migrateVersionBatch(ctx context.Context, spaceID, tenantID string, encrypt bool) (int, int, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "text_encryption.read_version_batch"), `
+		MATCH (d:Document {space_id: $space_id, tenant_id: $tenant_id})-[:HAS_VERSION]->(v:DocumentVersion)
+		WHERE `+unmigratedVersionsFilter+`
+		RETURN d.id AS id, v.version AS version, v.extracted_text AS text
+		LIMIT $limit
+	`, map[string]interface{}{
+		"space_id":  spaceID,
+		"tenant_id": tenantID,
+		"prefix":    fieldcrypt.Prefix,
+		"encrypt":   encrypt,
+		"limit":     s.config.BatchSize,
+	})
+	if err != nil {
+		return 0, 0, errors.Database("Failed to read document versions to migrate", err)
+	}
+
+	rows := make([]map[string]interface{}, 0, len(result.Records))
+	for _, record := range result.Records {
+		previous := recordString(record, "text")
+		var text string
+		if encrypt {
+			text, err = s.cipher.Encrypt(tenantID, previous)
+		} else {
+			text, err = s.cipher.Decrypt(tenantID, previous)
+		}
+		if err != nil {
+			s.logger.Warn("Failed to migrate extracted text of document version",
+				zap.String("document_id", recordString(record, "id")),
+				zap.Int64("version", recordInt64(record, "version")),
+				zap.Bool("encrypt", encrypt),
+				zap.Error(err))
+			continue
+		}
+		rows = append(rows, map[string]interface{}{
+			"id":       recordString(record, "id"),
+			"version":  recordInt64(record, "version"),
+			"previous": previous,
+			"text":     text,
+		})
+	}
+	if len(rows) > 0 {
+		params := map[string]interface{}{"tenant_id": tenantID}
+		if _, err := s.neo4j.WriteBatch(database.WithQueryName(ctx, "text_encryption.write_version_batch"), `
+			UNWIND $rows AS row
+			MATCH (d:Document {id: row.id, tenant_id: $tenant_id})-[:HAS_VERSION]->(v:DocumentVersion {version: row.version})
+			WHERE v.extracted_text = row.previous
+			SET v.extracted_text = row.text
+		`, rows, params); err != nil {
+			return 0, 0, errors.Database("Failed to store migrated version text", err)
+		}
+	}
+	return len(result.Records), len(rows), nil
+}
+
+// migratedRow returns the properties a document gets once its text is
+// migrated. Encrypted documents lose the copy of their text in the
+// processing result, and their search text leaves the text out.
+func (s *TextEncryptionService) migratedRow(tenantID string, encrypt bool, record map[string]interface{}) (map[string]interface{}, error) {
+	previous, _ := record["text"].(string)
+	document := &models.Document{TextEncrypted: encrypt}
+	document.Name, _ = record["name"].(string)
+	document.Description, _ = record["description"].(string)
+	if tags, ok := record["tags"].([]interface{}); ok {
+		for _, tag := range tags {
+			if tag, ok := tag.(string); ok {
+				document.Tags = append(document.Tags, tag)
+			}
+		}
+	}
+
+	var err error
+	var text string
+	if encrypt {
+		document.ExtractedText = previous
+		text, err = s.cipher.Encrypt(tenantID, previous)
+	} else {
+		text, err = s.cipher.Decrypt(tenantID, previous)
+		document.ExtractedText = text
+	}
+	if err != nil {
+		return nil, err
+	}
+	document.RefreshSearchText()
+
+	row := map[string]interface{}{
+		"id":                record["id"],
+		"previous":          previous,
+		"text":              text,
+		"search_text":       document.SearchText,
+		"processing_result": nil,
+	}
+	if stored, ok := record["processing_result"].(string); ok && encrypt {
+		var result map[string]interface{}
+		if json.Unmarshal([]byte(stored), &result) == nil {
+			if _, ok := result["extracted_text"]; ok {
+				stripped, err := json.Marshal(withoutExtractedText(result))
+				if err != nil {
+					return nil, err
+				}
+				row["processing_result"] = string(stripped)
+			}
+		}
+	}
+	return row, nil
+}
+
+// unmigrated counts the documents of a space, and the earlier versions
+// keeping text, whose text is not yet stored the way encrypt asks
+func (s *TextEncryptionService) unmigrated(ctx context.Context, spaceID, tenantID string, encrypt bool) (int, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "text_encryption.unmigrated"), `
+		MATCH (d:Document {space_id: $space_id, tenant_id: $tenant_id})
+		OPTIONAL MATCH (d)-[:HAS_VERSION]->(v:DocumentVersion)
+		WHERE `+unmigratedVersionsFilter+`
+		WITH d, count(v) AS versions
+		RETURN sum(CASE WHEN `+unmigratedDocumentsFilter+` THEN 1 ELSE 0 END) + sum(versions) AS remaining
+	`, map[string]interface{}{
+		"space_id":  spaceID,
+		"tenant_id": tenantID,
+		"prefix":    fieldcrypt.Prefix,
+		"encrypt":   encrypt,
+	})
+	if err != nil {
+		return 0, errors.Database("Failed to count documents to migrate", err)
+	}
+	if len(result.Records) == 0 {
+		return 0, nil
+	}
+	return int(recordInt64(result.Records[0], "remaining")), nil
+}
+
+// withoutExtractedText returns a processing result without its copy of the
+// extracted text, which documents of encrypted spaces keep only encrypted
+func withoutExtractedText(result map[string]interface{}) map[string]interface{} {
+	if _, ok := result["extracted_text"]; !ok {
+		return result
+	}
+	stripped := make(map[string]interface{}, len(result))
+	for key, value := range result {
+		if key != "extracted_text" {
+			stripped[key] = value
+		}
+	}
+	return stripped
+}
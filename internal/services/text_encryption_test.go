@@ -0,0 +1,89 @@
+package services
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/fieldcrypt"
+)
+
+func newTestTextEncryption(t *testing.T) *TextEncryptionService {
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{3}, fieldcrypt.KeySize))
+	s, err := NewTextEncryptionService(nil, nil, config.TextEncryptionConfig{MasterKey: key, BatchSize: 10}, setupTestLogger(t))
+	require.NoError(t, err)
+	return s
+}
+
+func TestTextEncryptionOpen(t *testing.T) {
+	s := newTestTextEncryption(t)
+	sealed, err := s.cipher.Encrypt("tenant-a", "Confidential findings")
+	require.NoError(t, err)
+
+	text, encrypted := s.Open("tenant-a", sealed)
+	assert.True(t, encrypted)
+	assert.Equal(t, "Confidential findings", text)
+	assert.Equal(t, "Confidential", s.Excerpt("tenant-a", sealed, 12))
+
+	text, encrypted = s.Open("tenant-a", "plain text")
+	assert.False(t, encrypted)
+	assert.Equal(t, "plain text", text)
+
+	// Text of another tenant, or without the key, reads as empty
+	text, encrypted = s.Open("tenant-b", sealed)
+	assert.True(t, encrypted)
+	assert.Empty(t, text)
+	var none *TextEncryptionService
+	text, _ = none.Open("tenant-a", sealed)
+	assert.Empty(t, text)
+	assert.Equal(t, "plain", none.Excerpt("tenant-a", "plain text", 5))
+}
+
+func TestTextEncryptionMigratedRow(t *testing.T) {
+	s := newTestTextEncryption(t)
+	record := map[string]interface{}{
+		"id":                "doc-1",
+		"text":              "Quarterly revenue grew",
+		"processing_result": `{"extracted_text":"Quarterly revenue grew","chunks_created":4}`,
+		"name":              "Report",
+		"description":       "Q3",
+		"tags":              []interface{}{"finance"},
+	}
+
+	row, err := s.migratedRow("tenant-a", true, record)
+	require.NoError(t, err)
+	assert.Equal(t, "Quarterly revenue grew", row["previous"])
+	assert.True(t, fieldcrypt.IsEncrypted(row["text"].(string)))
+	assert.Equal(t, "Report Q3 finance", row["search_text"])
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(row["processing_result"].(string)), &result))
+	assert.Equal(t, map[string]interface{}{"chunks_created": float64(4)}, result)
+
+	// Decrypting restores the text to the search text
+	record["text"] = row["text"]
+	row, err = s.migratedRow("tenant-a", false, record)
+	require.NoError(t, err)
+	assert.Equal(t, "Quarterly revenue grew", row["text"])
+	assert.Equal(t, "Report Q3 finance Quarterly revenue grew", row["search_text"])
+	assert.Nil(t, row["processing_result"])
+
+	_, err = s.migratedRow("tenant-b", false, record)
+	assert.Error(t, err)
+}
+
+func TestWithoutExtractedText(t *testing.T) {
+	result := map[string]interface{}{"extracted_text": "text", "status": "done"}
+	assert.Equal(t, map[string]interface{}{"status": "done"}, withoutExtractedText(result))
+	assert.Contains(t, result, "extracted_text", "the result is not changed")
+}
+
+func TestTextExcerptColumn(t *testing.T) {
+	assert.Equal(t,
+		"CASE WHEN d.extracted_text STARTS WITH 'enc:v1:' THEN d.extracted_text ELSE substring(coalesce(d.extracted_text, ''), 0, $chars) END",
+		textExcerptColumn("d", "chars"))
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+// Default per-operation timeouts used when configuration does not override them
+const (
+	defaultExternalHTTPTimeout  = 30 * time.Second
+	defaultBackgroundJobTimeout = 30 * time.Minute
+	defaultEventProcessTimeout  = 10 * time.Second
+)
+
+// sleepContext waits for d or until ctx is done, returning false if the
+// wait was interrupted (e.g. by shutdown)
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
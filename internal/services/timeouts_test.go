@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSleepContext(t *testing.T) {
+	assert.True(t, sleepContext(context.Background(), time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	assert.False(t, sleepContext(ctx, time.Hour), "a cancelled wait reports the interruption")
+	assert.Less(t, time.Since(start), time.Second)
+	assert.False(t, sleepContext(ctx, 0))
+}
+
+func TestAgentServiceHTTPTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	s := NewAgentService(nil, nil, nil, nil, server.URL, setupTestLogger(t))
+	assert.Equal(t, defaultExternalHTTPTimeout, s.httpClient.Timeout)
+
+	s.SetHTTPTimeout(50 * time.Millisecond)
+	assert.Equal(t, 50*time.Millisecond, s.httpClient.Timeout)
+	s.SetHTTPTimeout(0)
+	assert.Equal(t, 50*time.Millisecond, s.httpClient.Timeout, "0 keeps the configured timeout")
+
+	start := time.Now()
+	_, err := s.httpClient.Get(server.URL)
+	require.Error(t, err, "a call slower than the timeout fails")
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestBackgroundWorkStopsOnShutdown(t *testing.T) {
+	backgroundCtx, cancel := context.WithCancel(context.Background())
+	workers := NewWorkerGroup()
+
+	// A scheduled retry waiting for its time, as DocumentService runs them
+	interrupted := make(chan bool, 1)
+	require.True(t, workers.Go(func() {
+		interrupted <- !sleepContext(backgroundCtx, time.Hour)
+	}))
+
+	// Shutdown cancels the background context, then waits for the workers
+	cancel()
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer waitCancel()
+	require.NoError(t, workers.Wait(waitCtx))
+	assert.True(t, <-interrupted)
+
+	assert.False(t, workers.Go(func() {}), "no work starts after shutdown")
+}
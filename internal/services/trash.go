@@ -0,0 +1,446 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// trashPurgeBatchSize is the most documents and notebooks each scheduled
+// purge deletes; the rest wait for the next run
+const trashPurgeBatchSize = 100
+
+// trashedDocumentFields are the fields of a trashed document d that
+// purging it needs
+const trashedDocumentFields = `d.id AS id, d.tenant_id AS tenant_id, d.name AS name,
+	d.storage_path AS storage_path, d.processing_job_id AS processing_job_id,
+	d.processing_provider AS processing_provider`
+
+// TrashService lists, restores and purges soft-deleted documents and
+// notebooks. DocumentService.DeleteDocument and NotebookService.DeleteNotebook
+// move them to the trash; they are purged, with their files in AudiModal,
+// the vector store and storage, once the retention has passed or when a
+// user empties them from the trash.
+type TrashService struct {
+	neo4j     *database.Neo4jClient
+	documents *DocumentService
+	events    DomainEventPublisher
+	retention time.Duration
+	logger    *logger.Logger
+}
+
+// NewTrashService creates a trash service keeping deleted items for
+// retentionDays
+func NewTrashService(neo4j *database.Neo4jClient, documents *DocumentService, retentionDays int, log *logger.Logger) *TrashService {
+	return &TrashService{
+		neo4j:     neo4j,
+		documents: documents,
+		retention: time.Duration(retentionDays) * 24 * time.Hour,
+		logger:    log.WithService("trash"),
+	}
+}
+
+// SetEventPublisher sets the publisher notified of restored documents and
+// notebooks
+func (s *TrashService) SetEventPublisher(events DomainEventPublisher) {
+	s.events = events
+}
+
+// ValidTrashItemType reports whether itemType names a kind of trash item
+func ValidTrashItemType(itemType string) bool {
+	return itemType == models.TrashItemDocument || itemType == models.TrashItemNotebook
+}
+
+// List returns a page of the trash of a space, most recently deleted
+// first, optionally of one item type. Restricted documents are listed only
+// to their owner and the space's managers.
+func (s *TrashService) List(ctx context.Context, itemType string, userID string, spaceCtx *models.SpaceContext, limit, offset int) (*models.TrashListResponse, error) {
+	if !spaceCtx.CanRead() {
+		return nil, errors.Forbidden("Insufficient permissions to list the trash")
+	}
+	limit, offset = pagination.Clamp(limit, offset)
+
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "trash.list"), `
+		CALL {
+			MATCH (d:Document {tenant_id: $tenant_id, space_id: $space_id, status: $deleted})
+			WHERE ($type = '' OR $type = 'document') AND `+restrictedDocumentFilter("d")+`
+			RETURN 'document' AS type, d.id AS id, d.name AS name, d.notebook_id AS notebook_id,
+			       d.size_bytes AS size_bytes, coalesce(d.deleted_at, d.updated_at) AS deleted_at,
+			       d.deleted_by AS deleted_by
+			UNION ALL
+			MATCH (n:Notebook {tenant_id: $tenant_id, space_id: $space_id, status: $deleted})
+			WHERE $type = '' OR $type = 'notebook'
+			RETURN 'notebook' AS type, n.id AS id, n.name AS name, null AS notebook_id,
+			       null AS size_bytes, coalesce(n.deleted_at, n.updated_at) AS deleted_at,
+			       n.deleted_by AS deleted_by
+		}
+		RETURN type, id, name, notebook_id, size_bytes, deleted_at, deleted_by
+		ORDER BY deleted_at DESC, id
+		SKIP $offset LIMIT $limit
+	`, map[string]interface{}{
+		"tenant_id":        spaceCtx.TenantID,
+		"space_id":         spaceCtx.SpaceID,
+		"deleted":          database.StatusDeleted,
+		"type":             itemType,
+		"user_id":          userID,
+		"can_manage_space": spaceCtx.CanManage(),
+		"offset":           offset,
+		"limit":            limit + 1,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to list the trash", err)
+	}
+
+	items := make([]*models.TrashItem, 0, len(result.Records))
+	for _, record := range result.Records {
+		items = append(items, s.recordToTrashItem(record))
+	}
+	items, hasMore := pagination.Trim(items, limit)
+	return &models.TrashListResponse{
+		Items:         items,
+		RetentionDays: int(s.retention / (24 * time.Hour)),
+		Limit:         limit,
+		Offset:        offset,
+		HasMore:       hasMore,
+	}, nil
+}
+
+// recordToTrashItem reads an item of the trash list
+func (s *TrashService) recordToTrashItem(record *neo4j.Record) *models.TrashItem {
+	deletedAt := recordTime(record, "deleted_at")
+	return &models.TrashItem{
+		Type:       recordString(record, "type"),
+		ID:         recordString(record, "id"),
+		Name:       recordString(record, "name"),
+		NotebookID: recordString(record, "notebook_id"),
+		SizeBytes:  recordInt64(record, "size_bytes"),
+		DeletedAt:  deletedAt,
+		DeletedBy:  recordString(record, "deleted_by"),
+		PurgeAt:    deletedAt.Add(s.retention),
+	}
+}
+
+// trashedDocument is a document in the trash with what restoring and
+// purging it checks
+type trashedDocument struct {
+	document       *models.Document
+	ownerID        string
+	restricted     bool
+	notebookStatus string
+}
+
+// getTrashedDocument returns a document in the trash of a space, checking
+// that the user may restore or purge it: its owner or a user who may
+// delete in the space, as for DeleteDocument
+func (s *TrashService) getTrashedDocument(ctx context.Context, documentID, userID string, spaceCtx *models.SpaceContext) (*trashedDocument, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "trash.get_document"), `
+		MATCH (d:Document {id: $id, tenant_id: $tenant_id, space_id: $space_id, status: $deleted})
+		OPTIONAL MATCH (d)-[:BELONGS_TO]->(n:Notebook)
+		RETURN `+trashedDocumentFields+`, d.owner_id AS owner_id,
+		       coalesce(d.restricted, false) AS restricted, n.status AS notebook_status
+	`, map[string]interface{}{
+		"id":        documentID,
+		"tenant_id": spaceCtx.TenantID,
+		"space_id":  spaceCtx.SpaceID,
+		"deleted":   database.StatusDeleted,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to get trashed document", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, notInTrash(models.TrashItemDocument, documentID)
+	}
+
+	record := result.Records[0]
+	restricted, _ := record.Get("restricted")
+	trashed := &trashedDocument{
+		document:       recordToTrashedDocument(record),
+		ownerID:        recordString(record, "owner_id"),
+		notebookStatus: recordString(record, "notebook_status"),
+	}
+	trashed.restricted, _ = restricted.(bool)
+
+	if trashed.restricted && trashed.ownerID != userID && !spaceCtx.CanManage() {
+		return nil, notInTrash(models.TrashItemDocument, documentID)
+	}
+	if trashed.ownerID != userID && !spaceCtx.CanDelete() {
+		return nil, errors.Forbidden("You don't have permission to restore or purge this document")
+	}
+	return trashed, nil
+}
+
+// checkTrashedNotebook checks that a notebook is in the trash of a space
+// and that the user may restore or purge it
+func (s *TrashService) checkTrashedNotebook(ctx context.Context, notebookID string, spaceCtx *models.SpaceContext) error {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "trash.get_notebook"), `
+		MATCH (n:Notebook {id: $id, tenant_id: $tenant_id, space_id: $space_id, status: $deleted})
+		RETURN count(n) AS notebooks
+	`, map[string]interface{}{
+		"id":        notebookID,
+		"tenant_id": spaceCtx.TenantID,
+		"space_id":  spaceCtx.SpaceID,
+		"deleted":   database.StatusDeleted,
+	})
+	if err != nil {
+		return errors.Database("Failed to get trashed notebook", err)
+	}
+	if recordInt(result.Records, "notebooks") == 0 {
+		return notInTrash(models.TrashItemNotebook, notebookID)
+	}
+	if !spaceCtx.CanDelete() {
+		return errors.Forbidden("Insufficient permissions to restore or purge notebook")
+	}
+	return nil
+}
+
+// notInTrash is the error for an item missing from the trash
+func notInTrash(itemType, id string) error {
+	return errors.NotFoundWithDetails("Not in the trash", map[string]interface{}{
+		"type": itemType,
+		"id":   id,
+	}).WithErrorCode(errors.CodeNotInTrash)
+}
+
+// Restore takes a document or notebook out of the trash. A document gets
+// back the status it had, except that one deleted while uploading or
+// processing is restored as failed, as its processing was cancelled, so it
+// can be reprocessed. A document of a trashed notebook cannot be restored
+// until the notebook is.
+func (s *TrashService) Restore(ctx context.Context, itemType, id, userID string, spaceCtx *models.SpaceContext) error {
+	if itemType == models.TrashItemNotebook {
+		return s.restoreNotebook(ctx, id, userID, spaceCtx)
+	}
+
+	trashed, err := s.getTrashedDocument(ctx, id, userID, spaceCtx)
+	if err != nil {
+		return err
+	}
+	if trashed.notebookStatus == database.StatusDeleted {
+		return errors.ConflictWithDetails("The document's notebook is in the trash", map[string]interface{}{
+			"document_id": id,
+			"notebook_id": trashed.document.NotebookID,
+		}).WithErrorCode(errors.CodeNotebookInTrash)
+	}
+
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "trash.restore_document"), `
+		MATCH (d:Document {id: $id, tenant_id: $tenant_id, status: $deleted})
+		OPTIONAL MATCH (d)-[:BELONGS_TO]->(n:Notebook {tenant_id: $tenant_id})
+		SET n.document_count = COALESCE(n.document_count, 0) + 1,
+		    n.total_size_bytes = COALESCE(n.total_size_bytes, 0) + COALESCE(d.size_bytes, 0),
+		    n.updated_at = datetime()
+		SET d.status = CASE
+		        WHEN d.previous_status IN ['uploading', 'processing'] THEN 'failed'
+		        ELSE coalesce(d.previous_status, 'processed')
+		    END,
+		    d.updated_at = datetime()
+		REMOVE d.previous_status, d.deleted_at, d.deleted_by
+		RETURN d.status AS status, d.notebook_id AS notebook_id
+	`, map[string]interface{}{
+		"id":        id,
+		"tenant_id": spaceCtx.TenantID,
+		"deleted":   database.StatusDeleted,
+	})
+	if err != nil {
+		return errors.Database("Failed to restore document", err)
+	}
+	if len(result.Records) == 0 {
+		// Restored or purged concurrently
+		return notInTrash(models.TrashItemDocument, id)
+	}
+
+	publishDomainEvent(ctx, s.events, s.logger,
+		NewDocumentEvent(EventDocumentRestored, id, userID, map[string]interface{}{
+			"status":      recordString(result.Records[0], "status"),
+			"notebook_id": recordString(result.Records[0], "notebook_id"),
+			"tenant_id":   spaceCtx.TenantID,
+		}))
+
+	s.logger.Info("Document restored from trash", zap.String("document_id", id))
+	return nil
+}
+
+// restoreNotebook takes a notebook out of the trash with the status it had
+func (s *TrashService) restoreNotebook(ctx context.Context, notebookID, userID string, spaceCtx *models.SpaceContext) error {
+	if err := s.checkTrashedNotebook(ctx, notebookID, spaceCtx); err != nil {
+		return err
+	}
+
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "trash.restore_notebook"), `
+		MATCH (n:Notebook {id: $id, tenant_id: $tenant_id, status: $deleted})
+		SET n.status = coalesce(n.previous_status, 'active'),
+		    n.updated_at = datetime()
+		REMOVE n.previous_status, n.deleted_at, n.deleted_by
+		RETURN n.status AS status
+	`, map[string]interface{}{
+		"id":        notebookID,
+		"tenant_id": spaceCtx.TenantID,
+		"deleted":   database.StatusDeleted,
+	})
+	if err != nil {
+		return errors.Database("Failed to restore notebook", err)
+	}
+	if len(result.Records) == 0 {
+		return notInTrash(models.TrashItemNotebook, notebookID)
+	}
+
+	publishDomainEvent(ctx, s.events, s.logger,
+		NewNotebookEvent(EventNotebookRestored, notebookID, userID, map[string]interface{}{
+			"status":    recordString(result.Records[0], "status"),
+			"space_id":  spaceCtx.SpaceID,
+			"tenant_id": spaceCtx.TenantID,
+		}))
+
+	s.logger.Info("Notebook restored from trash", zap.String("notebook_id", notebookID))
+	return nil
+}
+
+// Purge permanently deletes a document or notebook in the trash without
+// waiting for the retention to pass. A notebook is purged with all its
+// documents.
+func (s *TrashService) Purge(ctx context.Context, itemType, id, userID string, spaceCtx *models.SpaceContext) error {
+	if itemType == models.TrashItemNotebook {
+		if err := s.checkTrashedNotebook(ctx, id, spaceCtx); err != nil {
+			return err
+		}
+		if err := s.documents.legalHolds.CheckNotebook(ctx, id, spaceCtx.TenantID); err != nil {
+			return err
+		}
+		return s.purgeNotebook(ctx, spaceCtx.TenantID, id)
+	}
+
+	trashed, err := s.getTrashedDocument(ctx, id, userID, spaceCtx)
+	if err != nil {
+		return err
+	}
+	if err := s.documents.legalHolds.CheckDocument(ctx, trashed.document); err != nil {
+		return err
+	}
+	return s.documents.purgeDocument(ctx, trashed.document)
+}
+
+// purgeNotebook permanently deletes a notebook and its documents, trashed
+// or not. Its child notebooks become top-level notebooks.
+func (s *TrashService) purgeNotebook(ctx context.Context, tenantID, notebookID string) error {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "trash.notebook_documents"), `
+		MATCH (d:Document {tenant_id: $tenant_id})-[:BELONGS_TO]->(:Notebook {id: $id, tenant_id: $tenant_id})
+		RETURN `+trashedDocumentFields, map[string]interface{}{
+		"id":        notebookID,
+		"tenant_id": tenantID,
+	})
+	if err != nil {
+		return errors.Database("Failed to list notebook documents", err)
+	}
+	for _, record := range result.Records {
+		if err := s.documents.purgeDocument(ctx, recordToTrashedDocument(record)); err != nil {
+			return err
+		}
+	}
+
+	_, err = s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "trash.purge_notebook"), `
+		MATCH (n:Notebook {id: $id, tenant_id: $tenant_id})
+		OPTIONAL MATCH (child:Notebook {parent_id: $id, tenant_id: $tenant_id})
+		SET child.parent_id = null
+		WITH DISTINCT n
+		DETACH DELETE n
+	`, map[string]interface{}{
+		"id":        notebookID,
+		"tenant_id": tenantID,
+	})
+	if err != nil {
+		return errors.Database("Failed to purge notebook", err)
+	}
+
+	s.logger.Info("Notebook purged",
+		zap.String("notebook_id", notebookID),
+		zap.Int("documents", len(result.Records)))
+	return nil
+}
+
+// recordToTrashedDocument reads the trashedDocumentFields of a record
+func recordToTrashedDocument(record *neo4j.Record) *models.Document {
+	return &models.Document{
+		ID:                 recordString(record, "id"),
+		TenantID:           recordString(record, "tenant_id"),
+		Name:               recordString(record, "name"),
+		StoragePath:        recordString(record, "storage_path"),
+		ProcessingJobID:    recordString(record, "processing_job_id"),
+		ProcessingProvider: recordString(record, "processing_provider"),
+	}
+}
+
+// PurgeExpired permanently deletes the documents and notebooks that have
+// been in the trash longer than the retention, as a scheduled job.
+// Notebooks are purged first, with their documents. Items that fail are
+// logged and retried on the next run; items under a legal hold are kept
+// until it is released.
+func (s *TrashService) PurgeExpired(ctx context.Context) error {
+	params := map[string]interface{}{
+		"deleted": database.StatusDeleted,
+		"cutoff":  time.Now().Add(-s.retention).UTC().Format(time.RFC3339),
+		"limit":   trashPurgeBatchSize,
+	}
+
+	notebooks, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "trash.expired_notebooks"), `
+		MATCH (n:Notebook {status: $deleted})
+		WHERE coalesce(n.deleted_at, n.updated_at) < datetime($cutoff)
+		  AND NOT `+notebookUnderLegalHold("n")+`
+		RETURN n.id AS id, n.tenant_id AS tenant_id
+		LIMIT $limit
+	`, params)
+	if err != nil {
+		return fmt.Errorf("failed to find expired trashed notebooks: %w", err)
+	}
+
+	purged, failed := 0, 0
+	for _, record := range notebooks.Records {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		notebookID := recordString(record, "id")
+		if err := s.purgeNotebook(ctx, recordString(record, "tenant_id"), notebookID); err != nil {
+			s.logger.Error("Failed to purge trashed notebook", zap.String("notebook_id", notebookID), zap.Error(err))
+			failed++
+			continue
+		}
+		purged++
+	}
+
+	documents, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "trash.expired_documents"), `
+		MATCH (d:Document {status: $deleted})
+		WHERE coalesce(d.deleted_at, d.updated_at) < datetime($cutoff)
+		  AND NOT `+documentUnderLegalHold("d")+`
+		RETURN `+trashedDocumentFields+`
+		LIMIT $limit
+	`, params)
+	if err != nil {
+		return fmt.Errorf("failed to find expired trashed documents: %w", err)
+	}
+	for _, record := range documents.Records {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		document := recordToTrashedDocument(record)
+		if err := s.documents.purgeDocument(ctx, document); err != nil {
+			failed++
+			continue
+		}
+		purged++
+	}
+
+	if purged > 0 {
+		s.logger.Info("Purged expired trash", zap.Int("items", purged))
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to purge %d of %d expired trash items", failed, purged+failed)
+	}
+	return nil
+}
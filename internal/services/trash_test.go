@@ -0,0 +1,67 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+func TestTrashItemPurgedAfterRetention(t *testing.T) {
+	service := NewTrashService(nil, nil, 30, setupTestLogger(t))
+	deletedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	item := service.recordToTrashItem(&neo4j.Record{
+		Keys:   []string{"type", "id", "name", "notebook_id", "size_bytes", "deleted_at", "deleted_by"},
+		Values: []interface{}{"document", "doc-1", "Report", "nb-1", int64(2048), deletedAt, "user-1"},
+	})
+
+	assert.Equal(t, &models.TrashItem{
+		Type:       models.TrashItemDocument,
+		ID:         "doc-1",
+		Name:       "Report",
+		NotebookID: "nb-1",
+		SizeBytes:  2048,
+		DeletedAt:  deletedAt,
+		DeletedBy:  "user-1",
+		PurgeAt:    time.Date(2026, 10, 31, 12, 0, 0, 0, time.UTC),
+	}, item)
+}
+
+func TestTrashedNotebookItemHasNoDocumentFields(t *testing.T) {
+	service := NewTrashService(nil, nil, 7, setupTestLogger(t))
+
+	item := service.recordToTrashItem(&neo4j.Record{
+		Keys:   []string{"type", "id", "name", "notebook_id", "size_bytes", "deleted_at", "deleted_by"},
+		Values: []interface{}{"notebook", "nb-1", "Research", nil, nil, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), nil},
+	})
+
+	assert.Equal(t, models.TrashItemNotebook, item.Type)
+	assert.Empty(t, item.NotebookID)
+	assert.Zero(t, item.SizeBytes)
+	assert.Equal(t, time.Date(2026, 10, 8, 0, 0, 0, 0, time.UTC), item.PurgeAt)
+}
+
+func TestRecordToTrashedDocument(t *testing.T) {
+	document := recordToTrashedDocument(&neo4j.Record{
+		Keys:   []string{"id", "tenant_id", "name", "storage_path", "processing_job_id"},
+		Values: []interface{}{"doc-1", "tenant-1", "Report", "bucket:documents/doc-1/report.pdf", nil},
+	})
+
+	assert.Equal(t, &models.Document{
+		ID:          "doc-1",
+		TenantID:    "tenant-1",
+		Name:        "Report",
+		StoragePath: "bucket:documents/doc-1/report.pdf",
+	}, document)
+}
+
+func TestValidTrashItemType(t *testing.T) {
+	assert.True(t, ValidTrashItemType("document"))
+	assert.True(t, ValidTrashItemType("notebook"))
+	assert.False(t, ValidTrashItemType("space"))
+	assert.False(t, ValidTrashItemType(""))
+}
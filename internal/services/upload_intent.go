@@ -0,0 +1,290 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// uploadIntentTTL is how long the presigned URL of a direct upload accepts
+// the file, and how long the upload can be confirmed
+const uploadIntentTTL = time.Hour
+
+// CreateUploadIntent starts a direct upload of a file into a notebook: the
+// document is created in status uploading, and the client stores the file
+// with the presigned URL returned, so the file never passes through the
+// API. The upload is finished by ConfirmUpload.
+func (s *DocumentService) CreateUploadIntent(ctx context.Context, notebookID string, req models.UploadIntentCreateRequest, ownerID string, spaceCtx *models.SpaceContext) (*models.UploadIntent, error) {
+	if s.maxDirectUploadBytes > 0 && req.SizeBytes > s.maxDirectUploadBytes {
+		return nil, errors.ValidationWithDetails("File exceeds the direct upload size limit", map[string]interface{}{
+			"max_bytes":  s.maxDirectUploadBytes,
+			"size_bytes": req.SizeBytes,
+		}).WithErrorCode(errors.CodeFileTooLarge)
+	}
+	if _, _, err := mime.ParseMediaType(req.MimeType); err != nil {
+		return nil, errors.ValidationWithDetails("Invalid MIME type", map[string]interface{}{
+			"mime_type": req.MimeType,
+		})
+	}
+	if err := s.validateDeclaredUpload(ctx, spaceCtx, req.FileName, req.MimeType, req.SizeBytes); err != nil {
+		return nil, err
+	}
+	if s.storageService == nil {
+		return nil, errors.Internal("Storage service not configured")
+	}
+
+	name := req.Name
+	if name == "" {
+		name = req.FileName
+	}
+	document, err := s.CreateDocument(ctx, models.DocumentCreateRequest{
+		Name:        name,
+		Description: req.Description,
+		NotebookID:  notebookID,
+		Tags:        req.Tags,
+		Priority:    req.Priority,
+	}, ownerID, spaceCtx, models.FileInfo{
+		OriginalName: req.FileName,
+		MimeType:     req.MimeType,
+		SizeBytes:    req.SizeBytes,
+		Checksum:     strings.ToLower(req.ChecksumSHA256),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	storageKey := fmt.Sprintf("spaces/%s/notebooks/%s/documents/%s/%s",
+		spaceCtx.SpaceType, document.NotebookID, document.ID, document.OriginalName)
+	expiresAt := time.Now().UTC().Add(uploadIntentTTL)
+
+	uploadURL, err := s.storageService.PresignTenantUpload(ctx, spaceCtx.TenantID, storageKey, document.MimeType, document.SizeBytes, uploadIntentTTL)
+	if err != nil {
+		s.discardUploadIntent(ctx, document.ID)
+		return nil, errors.ExternalService("Failed to create upload URL", err)
+	}
+
+	document.UpdateStorageInfo(storageKey, tenantBucketName(spaceCtx.TenantID))
+	_, err = s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "document.upload_intent"), `
+		MATCH (d:Document {id: $id})
+		SET d.storage_path = $storage_path,
+		    d.storage_bucket = $storage_bucket,
+		    d.upload_expires_at = $expires_at
+	`, map[string]interface{}{
+		"id":             document.ID,
+		"storage_path":   document.StoragePath,
+		"storage_bucket": document.StorageBucket,
+		"expires_at":     expiresAt,
+	})
+	if err != nil {
+		s.logger.Error("Failed to record upload intent", zap.String("document_id", document.ID), zap.Error(err))
+		s.discardUploadIntent(ctx, document.ID)
+		return nil, errors.Database("Failed to record upload intent", err)
+	}
+
+	s.logger.Info("Direct upload started",
+		zap.String("document_id", document.ID),
+		zap.Int64("size_bytes", document.SizeBytes))
+	return &models.UploadIntent{
+		Document:  document.ToResponse(),
+		UploadURL: uploadURL,
+		Method:    http.MethodPut,
+		Headers:   map[string]string{"Content-Type": document.MimeType},
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// ConfirmUpload finishes a direct upload: the stored file must have the
+// size, MIME type and SHA-256 checksum declared for it, and the document is
+// then submitted for processing. A file that does not match is deleted, so
+// the client can store it again while the upload URL is valid.
+func (s *DocumentService) ConfirmUpload(ctx context.Context, documentID, userID string, spaceCtx *models.SpaceContext) (*models.Document, error) {
+	document, err := s.GetDocumentByID(ctx, documentID, userID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+	if document.OwnerID != userID {
+		return nil, errors.Forbidden("Only the uploader can confirm an upload")
+	}
+	if s.storageService == nil {
+		return nil, errors.Internal("Storage service not configured")
+	}
+
+	expiresAt, err := s.uploadIntentExpiry(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+	if expiresAt.IsZero() || document.Status != "uploading" || time.Now().After(expiresAt) {
+		return nil, errors.ConflictWithDetails("Document has no pending upload", map[string]interface{}{
+			"document_id": documentID,
+			"status":      document.Status,
+		}).WithErrorCode(errors.CodeUploadNotActive)
+	}
+
+	info, err := s.storageService.GetTenantFileInfo(ctx, spaceCtx.TenantID, document.StoragePath)
+	if err != nil {
+		return nil, errors.ExternalService("Failed to check uploaded file", err)
+	}
+	if info == nil {
+		return nil, errors.ConflictWithDetails("The file has not been uploaded", map[string]interface{}{
+			"document_id": documentID,
+		}).WithErrorCode(errors.CodeUploadNotReceived)
+	}
+
+	reason := directUploadMismatch(document, info)
+	if reason == "" {
+		var checksum string
+		if checksum, err = s.tenantFileChecksum(ctx, spaceCtx.TenantID, document.StoragePath); err != nil {
+			return nil, err
+		}
+		if checksum != document.Checksum {
+			reason = "checksum_mismatch"
+		}
+	}
+	if reason != "" {
+		if deleteErr := s.storageService.DeleteFileFromTenantBucket(ctx, spaceCtx.TenantID, document.StoragePath); deleteErr != nil {
+			s.logger.Warn("Failed to delete mismatched upload", zap.String("document_id", documentID), zap.Error(deleteErr))
+		}
+		return nil, errors.ValidationWithDetails("The uploaded file does not match the upload", map[string]interface{}{
+			"document_id": documentID,
+			"reason":      reason,
+		}).WithErrorCode(errors.CodeUploadMismatch)
+	}
+
+	// Claim the upload so concurrent confirmations submit it once
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "document.confirm_upload"), `
+		MATCH (d:Document {id: $id})
+		WHERE d.upload_expires_at IS NOT NULL
+		REMOVE d.upload_expires_at
+		RETURN count(d) AS claimed
+	`, map[string]interface{}{"id": documentID})
+	if err != nil {
+		return nil, errors.Database("Failed to confirm upload", err)
+	}
+	if recordInt(result.Records, "claimed") == 0 {
+		return nil, errors.Conflict("Document has no pending upload").WithErrorCode(errors.CodeUploadNotActive)
+	}
+
+	saga, err := s.beginStoredDocumentSaga(ctx, sagaKindConfirmUpload, documentID, spaceCtx.TenantID, document.StoragePath)
+	if err != nil {
+		if deleteErr := s.storageService.DeleteFileFromTenantBucket(ctx, spaceCtx.TenantID, document.StoragePath); deleteErr != nil {
+			s.logger.Warn("Failed to delete file of failed upload", zap.String("document_id", documentID), zap.Error(deleteErr))
+		}
+		s.discardUploadIntent(ctx, documentID)
+		return nil, errors.Database("Failed to confirm upload", err)
+	}
+
+	file, err := s.storageService.OpenFileFromTenantBucket(ctx, spaceCtx.TenantID, document.StoragePath)
+	if err != nil {
+		s.abortSaga(ctx, saga, err)
+		return nil, errors.ExternalService("Failed to read uploaded file", err)
+	}
+	defer file.Close()
+
+	if err := s.submitForProcessing(ctx, saga, document, spaceCtx, map[string]interface{}{"file_reader": file}, info.LastModified); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Direct upload confirmed",
+		zap.String("document_id", document.ID),
+		zap.Int64("size_bytes", info.Size))
+	return document, nil
+}
+
+// uploadIntentExpiry returns when the direct upload of a document expires,
+// or the zero time when it has none pending
+func (s *DocumentService) uploadIntentExpiry(ctx context.Context, documentID string) (time.Time, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "document.upload_intent_expiry"), `
+		MATCH (d:Document {id: $id})
+		RETURN d.upload_expires_at AS expires_at
+	`, map[string]interface{}{"id": documentID})
+	if err != nil {
+		return time.Time{}, errors.Database("Failed to read upload intent", err)
+	}
+	if len(result.Records) == 0 {
+		return time.Time{}, nil
+	}
+	return recordTime(result.Records[0], "expires_at"), nil
+}
+
+// tenantFileChecksum returns the hex SHA-256 checksum of a stored file,
+// streaming it from storage
+func (s *DocumentService) tenantFileChecksum(ctx context.Context, tenantID, key string) (string, error) {
+	file, err := s.storageService.OpenFileFromTenantBucket(ctx, tenantID, key)
+	if err != nil {
+		return "", errors.ExternalService("Failed to read uploaded file", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", errors.ExternalService("Failed to read uploaded file", err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// CleanupUploadIntents deletes the documents of direct uploads that were
+// not confirmed in time, and any file stored for them
+func (s *DocumentService) CleanupUploadIntents(ctx context.Context) error {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "document.expired_upload_intents"), `
+		MATCH (d:Document)
+		WHERE d.upload_expires_at < $now
+		RETURN d.id AS id, d.tenant_id AS tenant_id, d.storage_path AS storage_path
+		LIMIT 100
+	`, map[string]interface{}{"now": time.Now().UTC()})
+	if err != nil {
+		return fmt.Errorf("failed to find expired upload intents: %w", err)
+	}
+
+	for _, record := range result.Records {
+		documentID := recordString(record, "id")
+		if s.storageService != nil {
+			if err := s.storageService.DeleteFileFromTenantBucket(ctx, recordString(record, "tenant_id"), recordString(record, "storage_path")); err != nil {
+				s.logger.Warn("Failed to delete file of expired upload", zap.String("document_id", documentID), zap.Error(err))
+			}
+		}
+		s.discardUploadIntent(ctx, documentID)
+	}
+	if len(result.Records) > 0 {
+		s.logger.Info("Deleted expired direct uploads", zap.Int("count", len(result.Records)))
+	}
+	return nil
+}
+
+// discardUploadIntent deletes the document of a direct upload that did not
+// complete
+func (s *DocumentService) discardUploadIntent(ctx context.Context, documentID string) {
+	if err := s.deleteDocumentRecord(ctx, documentID); err != nil {
+		s.logger.Error("Failed to delete document of unfinished upload",
+			zap.String("document_id", documentID),
+			zap.Error(err))
+	}
+}
+
+// directUploadMismatch compares a stored file with what was declared for
+// it, returning why they differ or "" when they match
+func directUploadMismatch(document *models.Document, info *FileMetadata) string {
+	if info.Size != document.SizeBytes {
+		return "size_mismatch"
+	}
+	stored, _, err := mime.ParseMediaType(info.ContentType)
+	if err != nil {
+		return "mime_type_mismatch"
+	}
+	declared, _, err := mime.ParseMediaType(document.MimeType)
+	if err != nil || !strings.EqualFold(stored, declared) {
+		return "mime_type_mismatch"
+	}
+	return ""
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+func TestDirectUploadMismatch(t *testing.T) {
+	document := &models.Document{SizeBytes: 1024, MimeType: "text/plain"}
+
+	assert.Empty(t, directUploadMismatch(document, &FileMetadata{Size: 1024, ContentType: "Text/Plain; charset=utf-8"}))
+	assert.Equal(t, "size_mismatch", directUploadMismatch(document, &FileMetadata{Size: 1023, ContentType: "text/plain"}))
+	assert.Equal(t, "mime_type_mismatch", directUploadMismatch(document, &FileMetadata{Size: 1024, ContentType: "application/pdf"}))
+	assert.Equal(t, "mime_type_mismatch", directUploadMismatch(document, &FileMetadata{Size: 1024}))
+}
+
+func TestCreateUploadIntentRejectsOversizedFile(t *testing.T) {
+	service := NewDocumentService(nil, nil, setupTestLogger(t))
+	service.SetMaxDirectUploadBytes(100)
+
+	_, err := service.CreateUploadIntent(context.Background(), "notebook-1", models.UploadIntentCreateRequest{
+		FileName:  "big.bin",
+		MimeType:  "application/octet-stream",
+		SizeBytes: 101,
+	}, "user-1", nil)
+	require.Error(t, err)
+	apiErr, ok := err.(*errors.APIError)
+	require.True(t, ok)
+	assert.Equal(t, errors.CodeFileTooLarge, apiErr.ErrorCode)
+}
@@ -0,0 +1,586 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// uploadSessionTTL is how long a resumable upload accepts parts, and how
+// long a finished one can still be looked up
+const uploadSessionTTL = 24 * time.Hour
+
+// maxReportedMissingParts bounds the missing parts listed when an upload
+// is completed too early
+const maxReportedMissingParts = 100
+
+// uploadSessionFields returns the fields of upload session u and its
+// stored parts
+const uploadSessionFields = `u.id AS id, u.document_id AS document_id, u.notebook_id AS notebook_id,
+	       u.space_id AS space_id, u.tenant_id AS tenant_id, u.owner_id AS owner_id,
+	       u.file_name AS file_name, u.mime_type AS mime_type, u.size_bytes AS size_bytes,
+	       u.part_size AS part_size, u.part_count AS part_count, u.status AS status,
+	       u.storage_key AS storage_key, u.upload_id AS upload_id,
+	       u.created_at AS created_at, u.updated_at AS updated_at, u.expires_at AS expires_at,
+	       [p IN parts WHERE p IS NOT NULL | p {.number, .size_bytes, .etag}] AS parts`
+
+// UploadSessionService runs resumable uploads of files too large to send,
+// or to hold in memory, in one request. An upload creates its document,
+// stores the file's parts as they arrive in an S3 multipart upload, and
+// assembles them and submits the document for processing on completion.
+type UploadSessionService struct {
+	neo4j     *database.Neo4jClient
+	documents *DocumentService
+	maxBytes  int64
+	partBytes int64
+	logger    *logger.Logger
+}
+
+// NewUploadSessionService creates an upload session service
+func NewUploadSessionService(neo4j *database.Neo4jClient, documents *DocumentService, limits *config.BodyLimitConfig, log *logger.Logger) *UploadSessionService {
+	return &UploadSessionService{
+		neo4j:     neo4j,
+		documents: documents,
+		maxBytes:  limits.ResumableUploadBytes,
+		partBytes: limits.UploadPartBytes,
+		logger:    log.WithService("upload_session_service"),
+	}
+}
+
+// storage returns the storage the document service stores files in
+func (s *UploadSessionService) storage() (StorageService, error) {
+	if s.documents.storageService == nil {
+		return nil, errors.Internal("Storage service not configured")
+	}
+	return s.documents.storageService, nil
+}
+
+// Initiate starts a resumable upload of a file into a notebook. The
+// document is created in status uploading and its parts are expected
+// within uploadSessionTTL.
+func (s *UploadSessionService) Initiate(ctx context.Context, notebookID string, req models.UploadSessionCreateRequest, ownerID string, spaceCtx *models.SpaceContext) (*models.UploadSession, error) {
+	if req.SizeBytes > s.maxBytes {
+		return nil, errors.ValidationWithDetails("File exceeds the resumable upload size limit", map[string]interface{}{
+			"max_bytes":  s.maxBytes,
+			"size_bytes": req.SizeBytes,
+		}).WithErrorCode(errors.CodeFileTooLarge)
+	}
+	storage, err := s.storage()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.documents.validateDeclaredUpload(ctx, spaceCtx, req.FileName, req.MimeType, req.SizeBytes); err != nil {
+		return nil, err
+	}
+
+	mimeType := req.MimeType
+	if mimeType == "" {
+		if mimeType = mime.TypeByExtension(filepath.Ext(req.FileName)); mimeType == "" {
+			mimeType = "application/octet-stream"
+		}
+	}
+	name := req.Name
+	if name == "" {
+		name = req.FileName
+	}
+
+	document, err := s.documents.CreateDocument(ctx, models.DocumentCreateRequest{
+		Name:        name,
+		Description: req.Description,
+		NotebookID:  notebookID,
+		Tags:        req.Tags,
+		Priority:    req.Priority,
+	}, ownerID, spaceCtx, models.FileInfo{
+		OriginalName: req.FileName,
+		MimeType:     mimeType,
+		SizeBytes:    req.SizeBytes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	session := &models.UploadSession{
+		ID:         uuid.New().String(),
+		DocumentID: document.ID,
+		NotebookID: notebookID,
+		SpaceID:    spaceCtx.SpaceID,
+		TenantID:   spaceCtx.TenantID,
+		OwnerID:    ownerID,
+		FileName:   req.FileName,
+		MimeType:   mimeType,
+		SizeBytes:  req.SizeBytes,
+		PartSize:   s.partBytes,
+		PartCount:  int((req.SizeBytes + s.partBytes - 1) / s.partBytes),
+		Status:     models.UploadSessionActive,
+		Parts:      []*models.UploadSessionPart{},
+		StorageKey: fmt.Sprintf("spaces/%s/notebooks/%s/documents/%s/%s",
+			spaceCtx.SpaceType, notebookID, document.ID, document.OriginalName),
+		CreatedAt: now,
+		UpdatedAt: now,
+		ExpiresAt: now.Add(uploadSessionTTL),
+	}
+
+	session.UploadID, err = storage.CreateMultipartUpload(ctx, spaceCtx.TenantID, session.StorageKey, mimeType)
+	if err != nil {
+		s.discardDocument(ctx, session)
+		return nil, errors.ExternalService("Failed to start upload", err)
+	}
+
+	_, err = s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "upload_session.create"), `
+		CREATE (u:UploadSession {
+			id: $id, document_id: $document_id, notebook_id: $notebook_id,
+			space_id: $space_id, tenant_id: $tenant_id, owner_id: $owner_id,
+			file_name: $file_name, mime_type: $mime_type, size_bytes: $size_bytes,
+			part_size: $part_size, part_count: $part_count, status: $status,
+			storage_key: $storage_key, upload_id: $upload_id,
+			created_at: $now, updated_at: $now, expires_at: $expires_at
+		})
+	`, map[string]interface{}{
+		"id":          session.ID,
+		"document_id": session.DocumentID,
+		"notebook_id": session.NotebookID,
+		"space_id":    session.SpaceID,
+		"tenant_id":   session.TenantID,
+		"owner_id":    session.OwnerID,
+		"file_name":   session.FileName,
+		"mime_type":   session.MimeType,
+		"size_bytes":  session.SizeBytes,
+		"part_size":   session.PartSize,
+		"part_count":  session.PartCount,
+		"status":      string(session.Status),
+		"storage_key": session.StorageKey,
+		"upload_id":   session.UploadID,
+		"now":         now,
+		"expires_at":  session.ExpiresAt,
+	})
+	if err != nil {
+		s.logger.Error("Failed to create upload session", zap.String("document_id", document.ID), zap.Error(err))
+		s.abortStorage(ctx, session)
+		s.discardDocument(ctx, session)
+		return nil, errors.Database("Failed to create upload session", err)
+	}
+
+	s.logger.Info("Resumable upload started",
+		zap.String("upload_id", session.ID),
+		zap.String("document_id", session.DocumentID),
+		zap.Int64("size_bytes", session.SizeBytes),
+		zap.Int("part_count", session.PartCount))
+	return session, nil
+}
+
+// GetSession returns an upload of the user in the space and the parts
+// stored so far
+func (s *UploadSessionService) GetSession(ctx context.Context, sessionID, userID string, spaceCtx *models.SpaceContext) (*models.UploadSession, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "upload_session.get"), `
+		MATCH (u:UploadSession {id: $id, tenant_id: $tenant_id, space_id: $space_id, owner_id: $user_id})
+		OPTIONAL MATCH (u)-[:HAS_PART]->(p:UploadPart)
+		WITH u, collect(p) AS parts
+		RETURN `+uploadSessionFields, map[string]interface{}{
+		"id":        sessionID,
+		"tenant_id": spaceCtx.TenantID,
+		"space_id":  spaceCtx.SpaceID,
+		"user_id":   userID,
+	})
+	if err != nil {
+		s.logger.Error("Failed to get upload session", zap.String("upload_id", sessionID), zap.Error(err))
+		return nil, errors.Database("Failed to retrieve upload session", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, errors.NotFoundWithDetails("Upload session not found", map[string]interface{}{
+			"upload_id": sessionID,
+		}).WithErrorCode(errors.CodeUploadNotFound)
+	}
+	return recordToUploadSession(result.Records[0]), nil
+}
+
+// activeSession returns an upload that still accepts parts
+func (s *UploadSessionService) activeSession(ctx context.Context, sessionID, userID string, spaceCtx *models.SpaceContext) (*models.UploadSession, error) {
+	session, err := s.GetSession(ctx, sessionID, userID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != models.UploadSessionActive || time.Now().After(session.ExpiresAt) {
+		return nil, errors.ConflictWithDetails("Upload session is no longer active", map[string]interface{}{
+			"upload_id":  sessionID,
+			"status":     string(session.Status),
+			"expires_at": session.ExpiresAt,
+		}).WithErrorCode(errors.CodeUploadNotActive)
+	}
+	return session, nil
+}
+
+// UploadPart stores a part of an upload. Parts may arrive in any order;
+// sending a part again replaces it.
+func (s *UploadSessionService) UploadPart(ctx context.Context, sessionID string, number int, data []byte, userID string, spaceCtx *models.SpaceContext) (*models.UploadSessionPart, error) {
+	session, err := s.activeSession(ctx, sessionID, userID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+	if number < 1 || number > session.PartCount {
+		return nil, errors.ValidationWithDetails("Part number out of range", map[string]interface{}{
+			"part_number": number,
+			"part_count":  session.PartCount,
+		}).WithErrorCode(errors.CodeInvalidUploadPart)
+	}
+	if expected := session.PartLength(number); int64(len(data)) != expected {
+		return nil, errors.ValidationWithDetails("Part has the wrong length", map[string]interface{}{
+			"part_number":    number,
+			"expected_bytes": expected,
+			"received_bytes": len(data),
+		}).WithErrorCode(errors.CodeInvalidUploadPart)
+	}
+	if number == 1 {
+		// The first part holds the magic bytes that identify the file
+		if _, err := s.documents.validateUpload(ctx, spaceCtx, models.FileInfo{
+			OriginalName: session.FileName,
+			MimeType:     session.MimeType,
+		}, data); err != nil {
+			return nil, err
+		}
+	}
+	storage, err := s.storage()
+	if err != nil {
+		return nil, err
+	}
+
+	etag, err := storage.UploadPart(ctx, session.TenantID, session.StorageKey, session.UploadID, number, data)
+	if err != nil {
+		return nil, errors.ExternalService("Failed to store upload part", err)
+	}
+	part := &models.UploadSessionPart{Number: number, SizeBytes: int64(len(data)), ETag: etag}
+
+	_, err = s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "upload_session.part"), `
+		MATCH (u:UploadSession {id: $id})
+		MERGE (u)-[:HAS_PART]->(p:UploadPart {upload_id: $id, number: $number})
+		SET p.size_bytes = $size_bytes, p.etag = $etag, u.updated_at = $now
+	`, map[string]interface{}{
+		"id":         session.ID,
+		"number":     number,
+		"size_bytes": part.SizeBytes,
+		"etag":       etag,
+		"now":        time.Now().UTC(),
+	})
+	if err != nil {
+		s.logger.Error("Failed to record upload part", zap.String("upload_id", session.ID), zap.Int("part_number", number), zap.Error(err))
+		return nil, errors.Database("Failed to record upload part", err)
+	}
+	return part, nil
+}
+
+// Complete assembles the parts of an upload into the document's file and
+// submits the document for processing, streaming the file from storage.
+// Every part must have been stored.
+func (s *UploadSessionService) Complete(ctx context.Context, sessionID, userID string, spaceCtx *models.SpaceContext) (*models.Document, error) {
+	session, err := s.activeSession(ctx, sessionID, userID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+	if missing := missingUploadParts(session); len(missing) > 0 {
+		return nil, errors.ConflictWithDetails("Upload is missing parts", map[string]interface{}{
+			"upload_id":     session.ID,
+			"missing_parts": missing,
+		}).WithErrorCode(errors.CodeUploadIncomplete)
+	}
+	storage, err := s.storage()
+	if err != nil {
+		return nil, err
+	}
+
+	// Claim the upload so concurrent completions assemble it once
+	if claimed, err := s.transition(ctx, session.ID, models.UploadSessionActive, models.UploadSessionCompleted); err != nil {
+		return nil, err
+	} else if !claimed {
+		return nil, errors.Conflict("Upload session is no longer active").WithErrorCode(errors.CodeUploadNotActive)
+	}
+
+	storagePath, err := storage.CompleteMultipartUpload(ctx, session.TenantID, session.StorageKey, session.UploadID, session.Parts)
+	if err != nil {
+		// The parts are still there; let the client try again
+		if _, revertErr := s.transition(ctx, session.ID, models.UploadSessionCompleted, models.UploadSessionActive); revertErr != nil {
+			s.logger.Error("Failed to reopen upload session", zap.String("upload_id", session.ID), zap.Error(revertErr))
+		}
+		return nil, errors.ExternalService("Failed to assemble upload", err)
+	}
+	storedAt := time.Now()
+
+	bucketName, keyPath := tenantBucketName(session.TenantID), session.StorageKey
+	if parts := strings.SplitN(storagePath, ":", 2); len(parts) == 2 {
+		bucketName, keyPath = parts[0], parts[1]
+	}
+	if err := s.documents.updateDocumentStorage(ctx, session.DocumentID, keyPath, bucketName); err != nil {
+		s.logger.Error("Failed to update document storage info",
+			zap.String("document_id", session.DocumentID),
+			zap.Error(err))
+	}
+
+	// The document may have been deleted while its parts were uploaded
+	document, err := s.documents.GetDocumentByID(ctx, session.DocumentID, userID, spaceCtx)
+	var saga *Saga
+	if err == nil {
+		if saga, err = s.documents.beginStoredDocumentSaga(ctx, sagaKindResumable, session.DocumentID, session.TenantID, keyPath); err != nil {
+			err = errors.Database("Failed to complete upload", err)
+		}
+	}
+	if err != nil {
+		if deleteErr := storage.DeleteFileFromTenantBucket(ctx, session.TenantID, keyPath); deleteErr != nil {
+			s.logger.Warn("Failed to delete file of failed upload", zap.String("key", keyPath), zap.Error(deleteErr))
+		}
+		s.discardDocument(ctx, session)
+		s.deleteSession(ctx, session.ID)
+		return nil, err
+	}
+
+	file, err := storage.OpenFileFromTenantBucket(ctx, session.TenantID, keyPath)
+	if err != nil {
+		s.documents.abortSaga(ctx, saga, err)
+		s.deleteSession(ctx, session.ID)
+		return nil, errors.ExternalService("Failed to read uploaded file", err)
+	}
+	defer file.Close()
+
+	if err := s.documents.submitForProcessing(ctx, saga, document, spaceCtx, map[string]interface{}{"file_reader": file}, storedAt); err != nil {
+		// The document and its file have been removed
+		s.deleteSession(ctx, session.ID)
+		return nil, err
+	}
+	s.deleteParts(ctx, session.ID)
+
+	s.logger.Info("Resumable upload completed",
+		zap.String("upload_id", session.ID),
+		zap.String("document_id", document.ID),
+		zap.Int("parts", len(session.Parts)))
+	return document, nil
+}
+
+// Abort cancels an upload, discarding its stored parts and its document
+func (s *UploadSessionService) Abort(ctx context.Context, sessionID, userID string, spaceCtx *models.SpaceContext) error {
+	session, err := s.GetSession(ctx, sessionID, userID, spaceCtx)
+	if err != nil {
+		return err
+	}
+	claimed, err := s.transition(ctx, session.ID, models.UploadSessionActive, models.UploadSessionAborted)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return errors.ConflictWithDetails("Upload session is no longer active", map[string]interface{}{
+			"upload_id": sessionID,
+			"status":    string(session.Status),
+		}).WithErrorCode(errors.CodeUploadNotActive)
+	}
+
+	s.abortStorage(ctx, session)
+	s.discardDocument(ctx, session)
+	s.deleteParts(ctx, session.ID)
+
+	s.logger.Info("Resumable upload aborted",
+		zap.String("upload_id", session.ID),
+		zap.String("document_id", session.DocumentID))
+	return nil
+}
+
+// CleanupUploads aborts uploads that expired before they were completed
+// and deletes finished uploads once they have expired, then deletes the
+// documents of direct uploads that were not confirmed in time and rolls
+// back uploads left half done
+func (s *UploadSessionService) CleanupUploads(ctx context.Context) error {
+	now := time.Now().UTC()
+
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "upload_session.expired"), `
+		MATCH (u:UploadSession {status: $active})
+		WHERE u.expires_at < $now
+		WITH u LIMIT 100
+		SET u.status = $aborted, u.updated_at = $now
+		WITH u, [] AS parts
+		RETURN `+uploadSessionFields, map[string]interface{}{
+		"active":  string(models.UploadSessionActive),
+		"aborted": string(models.UploadSessionAborted),
+		"now":     now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to abort expired uploads: %w", err)
+	}
+	for _, record := range result.Records {
+		session := recordToUploadSession(record)
+		s.abortStorage(ctx, session)
+		s.discardDocument(ctx, session)
+		s.deleteParts(ctx, session.ID)
+	}
+	if len(result.Records) > 0 {
+		s.logger.Info("Aborted expired uploads", zap.Int("count", len(result.Records)))
+	}
+
+	result, err = s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "upload_session.purge"), `
+		MATCH (u:UploadSession)
+		WHERE u.status <> $active AND u.expires_at < $now
+		WITH u LIMIT 10000
+		OPTIONAL MATCH (u)-[:HAS_PART]->(p:UploadPart)
+		WITH u, collect(p) AS parts
+		FOREACH (p IN parts | DETACH DELETE p)
+		DETACH DELETE u
+		RETURN count(*) AS deleted
+	`, map[string]interface{}{
+		"active": string(models.UploadSessionActive),
+		"now":    now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete expired uploads: %w", err)
+	}
+	if deleted := recordInt(result.Records, "deleted"); deleted > 0 {
+		s.logger.Info("Deleted expired uploads", zap.Int64("count", deleted))
+	}
+	if err := s.documents.CleanupUploadIntents(ctx); err != nil {
+		return err
+	}
+	return s.documents.RecoverSagas(ctx)
+}
+
+// transition moves an upload from one status to another, reporting whether
+// it was in the from status
+func (s *UploadSessionService) transition(ctx context.Context, sessionID string, from, to models.UploadSessionStatus) (bool, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "upload_session.transition"), `
+		MATCH (u:UploadSession {id: $id, status: $from})
+		SET u.status = $to, u.updated_at = $now
+		RETURN count(u) AS updated
+	`, map[string]interface{}{
+		"id":   sessionID,
+		"from": string(from),
+		"to":   string(to),
+		"now":  time.Now().UTC(),
+	})
+	if err != nil {
+		s.logger.Error("Failed to update upload session", zap.String("upload_id", sessionID), zap.Error(err))
+		return false, errors.Database("Failed to update upload session", err)
+	}
+	return recordInt(result.Records, "updated") > 0, nil
+}
+
+// abortStorage discards the stored parts of an upload. Parts left behind
+// are removed by the bucket's lifecycle rules, so failures are only logged.
+func (s *UploadSessionService) abortStorage(ctx context.Context, session *models.UploadSession) {
+	storage, err := s.storage()
+	if err == nil {
+		err = storage.AbortMultipartUpload(ctx, session.TenantID, session.StorageKey, session.UploadID)
+	}
+	if err != nil {
+		s.logger.Warn("Failed to abort multipart upload",
+			zap.String("upload_id", session.ID),
+			zap.String("storage_key", session.StorageKey),
+			zap.Error(err))
+	}
+}
+
+// discardDocument deletes the document of an upload that did not complete
+func (s *UploadSessionService) discardDocument(ctx context.Context, session *models.UploadSession) {
+	if err := s.documents.deleteDocumentRecord(ctx, session.DocumentID); err != nil {
+		s.logger.Error("Failed to delete document of unfinished upload",
+			zap.String("upload_id", session.ID),
+			zap.String("document_id", session.DocumentID),
+			zap.Error(err))
+	}
+}
+
+// deleteParts deletes the part records of a finished upload
+func (s *UploadSessionService) deleteParts(ctx context.Context, sessionID string) {
+	_, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "upload_session.delete_parts"), `
+		MATCH (:UploadSession {id: $id})-[:HAS_PART]->(p:UploadPart)
+		DETACH DELETE p
+	`, map[string]interface{}{"id": sessionID})
+	if err != nil {
+		s.logger.Warn("Failed to delete upload parts", zap.String("upload_id", sessionID), zap.Error(err))
+	}
+}
+
+// deleteSession deletes an upload and its parts
+func (s *UploadSessionService) deleteSession(ctx context.Context, sessionID string) {
+	_, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "upload_session.delete"), `
+		MATCH (u:UploadSession {id: $id})
+		OPTIONAL MATCH (u)-[:HAS_PART]->(p:UploadPart)
+		WITH u, collect(p) AS parts
+		FOREACH (p IN parts | DETACH DELETE p)
+		DETACH DELETE u
+	`, map[string]interface{}{"id": sessionID})
+	if err != nil {
+		s.logger.Warn("Failed to delete upload session", zap.String("upload_id", sessionID), zap.Error(err))
+	}
+}
+
+// missingUploadParts returns the numbers of the parts of an upload not
+// stored yet, at most maxReportedMissingParts of them
+func missingUploadParts(session *models.UploadSession) []int {
+	stored := make(map[int]bool, len(session.Parts))
+	for _, part := range session.Parts {
+		stored[part.Number] = true
+	}
+	missing := []int{}
+	for number := 1; number <= session.PartCount && len(missing) < maxReportedMissingParts; number++ {
+		if !stored[number] {
+			missing = append(missing, number)
+		}
+	}
+	return missing
+}
+
+// recordToUploadSession reads the uploadSessionFields of a record
+func recordToUploadSession(record *neo4j.Record) *models.UploadSession {
+	session := &models.UploadSession{
+		ID:         recordString(record, "id"),
+		DocumentID: recordString(record, "document_id"),
+		NotebookID: recordString(record, "notebook_id"),
+		SpaceID:    recordString(record, "space_id"),
+		TenantID:   recordString(record, "tenant_id"),
+		OwnerID:    recordString(record, "owner_id"),
+		FileName:   recordString(record, "file_name"),
+		MimeType:   recordString(record, "mime_type"),
+		SizeBytes:  recordInt64(record, "size_bytes"),
+		PartSize:   recordInt64(record, "part_size"),
+		PartCount:  int(recordInt64(record, "part_count")),
+		Status:     models.UploadSessionStatus(recordString(record, "status")),
+		StorageKey: recordString(record, "storage_key"),
+		UploadID:   recordString(record, "upload_id"),
+		Parts:      []*models.UploadSessionPart{},
+	}
+	for key, target := range map[string]*time.Time{
+		"created_at": &session.CreatedAt,
+		"updated_at": &session.UpdatedAt,
+		"expires_at": &session.ExpiresAt,
+	} {
+		value, _ := record.Get(key)
+		if t, ok := value.(time.Time); ok {
+			*target = t
+		}
+	}
+
+	parts, _ := record.Get("parts")
+	values, _ := parts.([]interface{})
+	for _, value := range values {
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		number, _ := fields["number"].(int64)
+		size, _ := fields["size_bytes"].(int64)
+		etag, _ := fields["etag"].(string)
+		session.Parts = append(session.Parts, &models.UploadSessionPart{Number: int(number), SizeBytes: size, ETag: etag})
+	}
+	sort.Slice(session.Parts, func(i, j int) bool {
+		return session.Parts[i].Number < session.Parts[j].Number
+	})
+	return session
+}
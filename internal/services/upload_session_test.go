@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+func TestUploadSessionPartLength(t *testing.T) {
+	session := &models.UploadSession{SizeBytes: 25, PartSize: 10, PartCount: 3}
+	assert.Equal(t, int64(10), session.PartLength(1))
+	assert.Equal(t, int64(10), session.PartLength(2))
+	assert.Equal(t, int64(5), session.PartLength(3))
+
+	exact := &models.UploadSession{SizeBytes: 20, PartSize: 10, PartCount: 2}
+	assert.Equal(t, int64(10), exact.PartLength(2))
+}
+
+func TestMissingUploadParts(t *testing.T) {
+	session := &models.UploadSession{PartCount: 4, Parts: []*models.UploadSessionPart{{Number: 3}, {Number: 1}}}
+	assert.Equal(t, []int{2, 4}, missingUploadParts(session))
+
+	session.Parts = append(session.Parts, &models.UploadSessionPart{Number: 2}, &models.UploadSessionPart{Number: 4})
+	assert.Empty(t, missingUploadParts(session))
+}
+
+func TestInitiateUploadRejectsOversizedFile(t *testing.T) {
+	service := NewUploadSessionService(nil, nil, &config.BodyLimitConfig{ResumableUploadBytes: 100, UploadPartBytes: 10}, setupTestLogger(t))
+
+	_, err := service.Initiate(context.Background(), "notebook-1", models.UploadSessionCreateRequest{FileName: "big.bin", SizeBytes: 101}, "user-1", nil)
+	require.Error(t, err)
+	apiErr, ok := err.(*errors.APIError)
+	require.True(t, ok)
+	assert.Equal(t, errors.CodeFileTooLarge, apiErr.ErrorCode)
+}
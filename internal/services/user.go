@@ -13,6 +13,7 @@ import (
 	"github.com/Tributary-ai-services/aether-be/internal/database"
 	"github.com/Tributary-ai-services/aether-be/internal/logger"
 	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
 	"github.com/Tributary-ai-services/aether-be/pkg/errors"
 )
 
@@ -430,7 +431,7 @@ func (s *UserService) GetUserByID(ctx context.Context, userID string) (*models.U
 	if len(result.Records) == 0 {
 		return nil, errors.NotFoundWithDetails("User not found", map[string]interface{}{
 			"user_id": userID,
-		})
+		}).WithErrorCode(errors.CodeUserNotFound)
 	}
 
 	user, err := s.recordToUser(result.Records[0])
@@ -465,7 +466,7 @@ func (s *UserService) GetUserByEmail(ctx context.Context, email string) (*models
 	if len(result.Records) == 0 {
 		return nil, errors.NotFoundWithDetails("User not found", map[string]interface{}{
 			"email": email,
-		})
+		}).WithErrorCode(errors.CodeUserNotFound)
 	}
 
 	return s.recordToUser(result.Records[0])
@@ -495,7 +496,7 @@ func (s *UserService) GetUserByKeycloakID(ctx context.Context, keycloakID string
 	if len(result.Records) == 0 {
 		return nil, errors.NotFoundWithDetails("User not found", map[string]interface{}{
 			"keycloak_id": keycloakID,
-		})
+		}).WithErrorCode(errors.CodeUserNotFound)
 	}
 
 	return s.recordToUser(result.Records[0])
@@ -677,14 +678,9 @@ func (s *UserService) SearchUsers(ctx context.Context, req models.UserSearchRequ
 	}
 
 	users := make([]*models.UserResponse, 0, len(result.Records))
-	hasMore := false
-
-	for i, record := range result.Records {
-		if i >= req.Limit {
-			hasMore = true
-			break
-		}
+	records, hasMore := pagination.Trim(result.Records, req.Limit)
 
+	for _, record := range records {
 		user, err := s.recordToUserResponse(record)
 		if err != nil {
 			s.logger.Error("Failed to parse user record", zap.Error(err))
@@ -1048,7 +1044,7 @@ func (s *UserService) MarkTutorialComplete(ctx context.Context, userID string) e
 	if len(result.Records) == 0 {
 		s.logger.Warn("User not found for tutorial completion",
 			zap.String("user_id", userID))
-		return errors.NotFound("User not found")
+		return errors.NotFound("User not found").WithErrorCode(errors.CodeUserNotFound)
 	}
 
 	s.logger.Info("Tutorial marked complete",
@@ -1089,7 +1085,7 @@ func (s *UserService) ResetTutorial(ctx context.Context, userID string) error {
 	if len(result.Records) == 0 {
 		s.logger.Warn("User not found for tutorial reset",
 			zap.String("user_id", userID))
-		return errors.NotFound("User not found")
+		return errors.NotFound("User not found").WithErrorCode(errors.CodeUserNotFound)
 	}
 
 	s.logger.Info("Tutorial reset",
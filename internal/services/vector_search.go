@@ -0,0 +1,274 @@
+package services
+
+import (
+	"context"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// Semantic search defaults
+const (
+	defaultSemanticTopK         = 10
+	defaultSemanticVectorWeight = 0.7
+	// semanticOverfetch asks DeepLake for more chunks than requested, as
+	// hits of unreadable or filtered documents are dropped afterwards
+	semanticOverfetch = 2
+	maxSemanticFetch  = 200
+)
+
+// VectorSearchService searches the chunks indexed in a space's DeepLake
+// namespace and maps them back to the space's documents
+type VectorSearchService struct {
+	config   *config.DeepLakeConfig
+	spaces   *SpaceService
+	neo4j    *database.Neo4jClient
+	datasets *deepLakeDatasets
+	logger   *logger.Logger
+}
+
+// NewVectorSearchService creates a new vector search service
+func NewVectorSearchService(cfg *config.DeepLakeConfig, spaces *SpaceService, neo4j *database.Neo4jClient, log *logger.Logger) *VectorSearchService {
+	return &VectorSearchService{
+		config:   cfg,
+		spaces:   spaces,
+		neo4j:    neo4j,
+		datasets: newDeepLakeDatasets(cfg),
+		logger:   log.WithService("vector_search_service"),
+	}
+}
+
+// deepLakeChunkHit is one result of a DeepLake dataset search. Chunks
+// indexed by AudiModal name their file in the metadata rather than at the
+// top level, and some API versions nest the stored vector.
+type deepLakeChunkHit struct {
+	ID         string                 `json:"id"`
+	Score      float64                `json:"score"`
+	Content    string                 `json:"content"`
+	DocumentID string                 `json:"document_id"`
+	ChunkID    string                 `json:"chunk_id"`
+	Metadata   map[string]interface{} `json:"metadata"`
+	Vector     *deepLakeChunkHit      `json:"vector,omitempty"`
+}
+
+type deepLakeChunkSearchResponse struct {
+	Results     []deepLakeChunkHit `json:"results"`
+	QueryTimeMS float64            `json:"query_time_ms"`
+}
+
+// SemanticSearch searches a space's chunks. The caller must be a member of
+// the space; hits are limited to live documents of the space and, when
+// given, of the requested notebooks.
+func (s *VectorSearchService) SemanticSearch(ctx context.Context, spaceID, userID string, req models.SemanticSearchRequest) (*models.SemanticSearchResponse, error) {
+	if !s.config.Enabled {
+		return nil, errors.ServiceUnavailable("Vector search is disabled").WithErrorCode(errors.CodeVectorSearchDisabled)
+	}
+
+	role, err := s.spaces.GetUserRoleInSpace(ctx, spaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if role == "" {
+		return nil, errors.ForbiddenWithDetails("You do not have access to this space", map[string]interface{}{
+			"space_id": spaceID,
+		}).WithErrorCode(errors.CodeSpaceAccessDenied)
+	}
+	tenant, err := s.spaces.ResolveTenant(ctx, models.TenantLookupBySpaceID, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Mode == "" {
+		req.Mode = models.SemanticSearchVector
+	}
+	if req.TopK <= 0 {
+		req.TopK = defaultSemanticTopK
+	}
+	if req.Mode == models.SemanticSearchHybrid && req.VectorWeight == 0 {
+		req.VectorWeight = defaultSemanticVectorWeight
+	}
+
+	found, err := s.searchNamespace(ctx, s.namespace(tenant), req)
+	if err != nil {
+		return nil, err
+	}
+
+	hits, err := s.mapToDocuments(ctx, spaceID, req, found.Results)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.FromContext(ctx).Debug("Semantic search completed",
+		zap.String("space_id", spaceID),
+		zap.String("mode", string(req.Mode)),
+		zap.Int("chunks", len(found.Results)),
+		zap.Int("hits", len(hits)),
+	)
+	return &models.SemanticSearchResponse{
+		Query:       req.Query,
+		Mode:        req.Mode,
+		Hits:        hits,
+		QueryTimeMS: found.QueryTimeMS,
+	}, nil
+}
+
+// namespace returns the DeepLake namespace of a space, which defaults to
+// its tenant ID
+func (s *VectorSearchService) namespace(tenant *models.TenantInfo) string {
+	if tenant.DeeplakeNamespace != "" {
+		return tenant.DeeplakeNamespace
+	}
+	return tenant.TenantID
+}
+
+// searchNamespace runs the search against the space dataset of a namespace.
+// A namespace without a dataset has nothing indexed yet and finds nothing.
+func (s *VectorSearchService) searchNamespace(ctx context.Context, namespace string, req models.SemanticSearchRequest) (*deepLakeChunkSearchResponse, error) {
+	fetch := req.TopK * semanticOverfetch
+	if fetch > maxSemanticFetch {
+		fetch = maxSemanticFetch
+	}
+	options := map[string]interface{}{
+		"top_k":            fetch,
+		"min_score":        req.MinScore,
+		"include_content":  true,
+		"include_metadata": true,
+	}
+	payload := map[string]interface{}{
+		"query_text": req.Query,
+		"options":    options,
+	}
+	endpoint := "text"
+	if req.Mode == models.SemanticSearchHybrid {
+		endpoint = "hybrid"
+		payload["vector_weight"] = req.VectorWeight
+		payload["text_weight"] = 1 - req.VectorWeight
+		payload["fusion_method"] = "weighted_sum"
+	}
+
+	var found deepLakeChunkSearchResponse
+	status, err := s.datasets.do(ctx, http.MethodPost, namespace, "/search/"+endpoint, payload, &found)
+	if status == http.StatusNotFound {
+		return &deepLakeChunkSearchResponse{}, nil
+	}
+	if err != nil {
+		return nil, errors.ExternalService("Vector search failed", err)
+	}
+	return &found, nil
+}
+
+// mapToDocuments attaches each chunk's document, dropping chunks whose
+// document is not a live document of the space or of the requested
+// notebooks, and keeps the best TopK
+func (s *VectorSearchService) mapToDocuments(ctx context.Context, spaceID string, req models.SemanticSearchRequest, results []deepLakeChunkHit) ([]*models.SemanticSearchHit, error) {
+	hits := make([]*models.SemanticSearchHit, 0, len(results))
+	fileIDs := make([]string, 0, len(results))
+	chunkFiles := make([]string, 0, len(results))
+	for _, result := range results {
+		fileID, hit := chunkHit(result)
+		if fileID == "" {
+			continue
+		}
+		hits = append(hits, hit)
+		chunkFiles = append(chunkFiles, fileID)
+		fileIDs = append(fileIDs, fileID)
+	}
+	if len(hits) == 0 {
+		return hits, nil
+	}
+
+	// Vectors indexed by AudiModal carry its file ID, which documents keep
+	// as processing_job_id; vectors Aether indexed carry the document ID
+	params := map[string]interface{}{
+		"space_id": spaceID,
+		"file_ids": fileIDs,
+	}
+	notebookFilter := ""
+	if len(req.NotebookIDs) > 0 {
+		notebookFilter = "AND d.notebook_id IN $notebook_ids"
+		params["notebook_ids"] = req.NotebookIDs
+	}
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (d:Document)
+		WHERE d.space_id = $space_id
+		  AND (d.id IN $file_ids OR d.processing_job_id IN $file_ids)
+		  AND `+database.SoftDeleteFilter(ctx, "d")+`
+		  `+notebookFilter+`
+		RETURN d.id AS id, d.processing_job_id AS file_id, d.name AS name,
+		       d.mime_type AS mime_type, d.notebook_id AS notebook_id
+	`, params)
+	if err != nil {
+		return nil, errors.Database("Failed to look up search result documents", err)
+	}
+
+	documents := make(map[string]*models.SemanticSearchDocument, len(result.Records))
+	for _, record := range result.Records {
+		document := &models.SemanticSearchDocument{
+			ID:         recordString(record, "id"),
+			Name:       recordString(record, "name"),
+			MimeType:   recordString(record, "mime_type"),
+			NotebookID: recordString(record, "notebook_id"),
+		}
+		documents[document.ID] = document
+		if fileID := recordString(record, "file_id"); fileID != "" {
+			documents[fileID] = document
+		}
+	}
+
+	kept := hits[:0]
+	for i, hit := range hits {
+		if document, ok := documents[chunkFiles[i]]; ok {
+			hit.Document = document
+			kept = append(kept, hit)
+			if len(kept) == req.TopK {
+				break
+			}
+		}
+	}
+	return kept, nil
+}
+
+// chunkHit converts a DeepLake result, returning the ID of the file or
+// document the chunk came from
+func chunkHit(result deepLakeChunkHit) (string, *models.SemanticSearchHit) {
+	source := result
+	if result.Vector != nil {
+		source = *result.Vector
+		if source.Content == "" {
+			source.Content = result.Content
+		}
+	}
+
+	metadata := func(key string) string {
+		value, _ := source.Metadata[key].(string)
+		return value
+	}
+	fileID := source.DocumentID
+	for _, key := range []string{"document_id", "file_id"} {
+		if fileID == "" {
+			fileID = metadata(key)
+		}
+	}
+	chunkID := source.ChunkID
+	if chunkID == "" {
+		chunkID = metadata("chunk_id")
+	}
+	if chunkID == "" {
+		chunkID = source.ID
+	}
+	content := source.Content
+	if content == "" {
+		content = metadata("content")
+	}
+
+	return fileID, &models.SemanticSearchHit{
+		ChunkID: chunkID,
+		Score:   result.Score,
+		Content: content,
+	}
+}
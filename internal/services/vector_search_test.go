@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+func newTestVectorSearchService(t *testing.T, handler http.HandlerFunc) *VectorSearchService {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return NewVectorSearchService(&config.DeepLakeConfig{
+		BaseURL:        server.URL,
+		APIKey:         "secret",
+		TimeoutSeconds: 5,
+		Enabled:        true,
+		SpaceDataset:   "documents",
+	}, nil, nil, setupTestLogger(t))
+}
+
+func TestVectorSearchNamespaceHybrid(t *testing.T) {
+	var payload map[string]interface{}
+	service := newTestVectorSearchService(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/datasets/documents/search/hybrid", r.URL.Path)
+		assert.Equal(t, "tenant_42", r.Header.Get("X-Tenant-ID"))
+		assert.Equal(t, "ApiKey secret", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		_, _ = w.Write([]byte(`{"results":[{"id":"v1","score":0.9,"content":"text","metadata":{"file_id":"f1"}}],"query_time_ms":12.5}`))
+	})
+
+	found, err := service.searchNamespace(context.Background(), "tenant_42", models.SemanticSearchRequest{
+		Query:        "quarterly revenue",
+		Mode:         models.SemanticSearchHybrid,
+		TopK:         10,
+		VectorWeight: 0.75,
+	})
+	require.NoError(t, err)
+	require.Len(t, found.Results, 1)
+	assert.Equal(t, 12.5, found.QueryTimeMS)
+
+	assert.Equal(t, "quarterly revenue", payload["query_text"])
+	assert.Equal(t, 0.75, payload["vector_weight"])
+	assert.Equal(t, 0.25, payload["text_weight"])
+	options := payload["options"].(map[string]interface{})
+	assert.Equal(t, float64(20), options["top_k"], "asks for extra chunks to make up for dropped ones")
+}
+
+func TestVectorSearchNamespaceErrors(t *testing.T) {
+	status := http.StatusNotFound
+	service := newTestVectorSearchService(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/datasets/documents/search/text", r.URL.Path)
+		w.WriteHeader(status)
+	})
+	req := models.SemanticSearchRequest{Query: "anything", Mode: models.SemanticSearchVector, TopK: 5}
+
+	// A namespace without a dataset has nothing indexed
+	found, err := service.searchNamespace(context.Background(), "tenant_42", req)
+	require.NoError(t, err)
+	assert.Empty(t, found.Results)
+
+	status = http.StatusInternalServerError
+	_, err = service.searchNamespace(context.Background(), "tenant_42", req)
+	assert.Error(t, err)
+}
+
+func TestSemanticSearchDisabled(t *testing.T) {
+	service := newTestVectorSearchService(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("DeepLake must not be called")
+	})
+	service.config.Enabled = false
+
+	_, err := service.SemanticSearch(context.Background(), "space_1", "user_1", models.SemanticSearchRequest{Query: "anything"})
+	assert.Error(t, err)
+}
+
+func TestChunkHit(t *testing.T) {
+	fileID, hit := chunkHit(deepLakeChunkHit{
+		ID:       "vec_1",
+		Score:    0.8,
+		Metadata: map[string]interface{}{"file_id": "file_1", "chunk_id": "chunk_1", "content": "from metadata"},
+	})
+	assert.Equal(t, "file_1", fileID)
+	assert.Equal(t, "chunk_1", hit.ChunkID)
+	assert.Equal(t, "from metadata", hit.Content)
+	assert.Equal(t, 0.8, hit.Score)
+
+	// Nested vectors carry the source; the document ID wins over the file ID
+	fileID, hit = chunkHit(deepLakeChunkHit{
+		Score:   0.5,
+		Content: "outer",
+		Vector: &deepLakeChunkHit{
+			ID:         "vec_2",
+			DocumentID: "doc_1",
+			Metadata:   map[string]interface{}{"file_id": "file_2"},
+		},
+	})
+	assert.Equal(t, "doc_1", fileID)
+	assert.Equal(t, "vec_2", hit.ChunkID)
+	assert.Equal(t, "outer", hit.Content)
+	assert.Equal(t, 0.5, hit.Score)
+
+	fileID, _ = chunkHit(deepLakeChunkHit{ID: "orphan"})
+	assert.Empty(t, fileID)
+}
@@ -0,0 +1,682 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// Vector sync limits
+const (
+	// vectorSyncClaimBatch bounds how many documents one poll syncs
+	vectorSyncClaimBatch = 5
+	// vectorSyncLease is how long a claimed document belongs to the poll
+	// that claimed it; one still claimed after that is assumed abandoned by
+	// a replica that died mid-sync and is claimed again
+	vectorSyncLease       = 15 * time.Minute
+	vectorSyncBaseBackoff = 30 * time.Second
+	vectorSyncMaxBackoff  = 30 * time.Minute
+	defaultVectorSyncPage = 50
+)
+
+// VectorSyncChunkSource lists the chunks extracted from a processed file.
+// AudiModalService implements it.
+type VectorSyncChunkSource interface {
+	GetFileChunks(ctx context.Context, tenantID string, fileID string, limit, offset int) (*ChunksResponse, error)
+}
+
+// VectorSyncService pushes the chunk embeddings of processed documents into
+// their space's DeepLake namespace. Domain events queue a document's
+// VectorSync node when it finishes processing, and the leader drains the queue on a schedule, so syncs survive restarts and are
+// retried with backoff. Vector IDs derive from the document ID and chunk
+// position, so a repeated sync overwrites rather than duplicates, and a
+// reprocessed document that shrank has its surplus vectors removed.
+type VectorSyncService struct {
+	neo4j       *database.Neo4jClient
+	documents   *DocumentService
+	spaces      *SpaceService
+	chunks      VectorSyncChunkSource
+	embedder    EmbeddingProvider
+	datasets    *deepLakeDatasets
+	pageSize    int
+	maxAttempts int64
+	logger      *logger.Logger
+}
+
+// NewVectorSyncService creates a vector sync service. Chunks are embedded
+// and stored in batches of the embedding batch size, and a document is
+// given up after the embedding retry count of failed attempts.
+func NewVectorSyncService(
+	neo4j *database.Neo4jClient,
+	documents *DocumentService,
+	spaces *SpaceService,
+	chunks VectorSyncChunkSource,
+	embedder EmbeddingProvider,
+	deepLake *config.DeepLakeConfig,
+	embedding *config.EmbeddingConfig,
+	log *logger.Logger,
+) *VectorSyncService {
+	pageSize := embedding.BatchSize
+	if pageSize <= 0 {
+		pageSize = defaultVectorSyncPage
+	}
+	maxAttempts := int64(embedding.MaxRetries)
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	return &VectorSyncService{
+		neo4j:       neo4j,
+		documents:   documents,
+		spaces:      spaces,
+		chunks:      chunks,
+		embedder:    embedder,
+		datasets:    newDeepLakeDatasets(deepLake),
+		pageSize:    pageSize,
+		maxAttempts: maxAttempts,
+		logger:      log.WithService("vector_sync_service"),
+	}
+}
+
+// vectorSyncFields are the properties returned for a VectorSync node v
+const vectorSyncFields = `
+	v.document_id AS document_id, v.tenant_id AS tenant_id, v.status AS status,
+	v.namespace AS namespace, v.vector_count AS vector_count,
+	v.attempts AS attempts, v.generation AS generation, v.error AS error,
+	v.next_attempt_at AS next_attempt_at, v.synced_at AS synced_at,
+	v.updated_at AS updated_at
+`
+
+// vectorSyncClaim is a VectorSync node claimed by a poll. Its generation
+// changes whenever the document is queued again, so the outcome of a sync
+// that was overtaken by a newer version of the document is discarded.
+type vectorSyncClaim struct {
+	documentID  string
+	tenantID    string
+	status      string
+	namespace   string
+	vectorCount int64
+	attempts    int64
+	generation  int64
+}
+
+// HandleDomainEvent queues processed documents for sync. It is subscribed
+// to the domain event bus.
+func (s *VectorSyncService) HandleDomainEvent(ctx context.Context, event Event) error {
+	if event.Type == EventDocumentProcessed {
+		return s.enqueue(ctx, event.Subject)
+	}
+	return nil
+}
+
+// enqueue queues a document for a sync due now, superseding any sync of an
+// earlier version in flight
+func (s *VectorSyncService) enqueue(ctx context.Context, documentID string) error {
+	now := time.Now().UTC()
+	_, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (d:Document {id: $document_id})
+		MERGE (v:VectorSync {document_id: d.id})
+		ON CREATE SET v.vector_count = 0, v.generation = 0, v.created_at = $now
+		SET v.tenant_id = d.tenant_id,
+		    v.status = $pending,
+		    v.attempts = 0,
+		    v.error = null,
+		    v.generation = v.generation + 1,
+		    v.next_attempt_at = $now,
+		    v.updated_at = $now
+	`, map[string]interface{}{
+		"document_id": documentID,
+		"pending":     models.VectorSyncPending,
+		"now":         now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to queue vector sync: %w", err)
+	}
+	return nil
+}
+
+// ProcessDue claims queued documents whose next attempt is due and syncs
+// or removes their vectors. It is a singleton job run by the leader
+// replica; the claim re-checks the due time under the node's write lock,
+// so overlapping polls do not claim the same document.
+func (s *VectorSyncService) ProcessDue(ctx context.Context) error {
+	now := time.Now().UTC()
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (v:VectorSync)
+		WHERE v.status IN $due AND v.next_attempt_at <= $now
+		WITH v ORDER BY v.next_attempt_at LIMIT $limit
+		SET v._claim_lock = true
+		REMOVE v._claim_lock
+		WITH v WHERE v.next_attempt_at <= $now
+		SET v.status = CASE v.status WHEN $deleting THEN $deleting ELSE $syncing END,
+		    v.next_attempt_at = $lease_until,
+		    v.updated_at = $now
+		RETURN `+vectorSyncFields, map[string]interface{}{
+		"due":         []string{models.VectorSyncPending, models.VectorSyncSyncing, models.VectorSyncDeleting},
+		"deleting":    models.VectorSyncDeleting,
+		"syncing":     models.VectorSyncSyncing,
+		"now":         now,
+		"lease_until": now.Add(vectorSyncLease),
+		"limit":       vectorSyncClaimBatch,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to claim vector syncs: %w", err)
+	}
+
+	for _, record := range result.Records {
+		if ctx.Err() != nil {
+			// Shutting down: the lease hands the rest to the next leader
+			return ctx.Err()
+		}
+		claim := recordToVectorSyncClaim(record)
+		if claim.status == models.VectorSyncDeleting {
+			err = s.removeVectors(ctx, claim)
+		} else {
+			err = s.sync(ctx, claim)
+		}
+		if err != nil {
+			s.recordFailure(ctx, claim, err)
+		}
+	}
+	return nil
+}
+
+// sync pushes the chunks of the document's current version
+func (s *VectorSyncService) sync(ctx context.Context, claim vectorSyncClaim) error {
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (d:Document {id: $document_id})
+		WHERE `+database.SoftDeleteFilter(ctx, "d")+`
+		RETURN d.tenant_id AS tenant_id, d.space_id AS space_id, d.notebook_id AS notebook_id,
+		       d.processing_job_id AS file_id, d.name AS name, d.mime_type AS mime_type,
+		       d.status AS status
+	`, map[string]interface{}{"document_id": claim.documentID})
+	if err != nil {
+		return fmt.Errorf("failed to load document: %w", err)
+	}
+	if len(result.Records) == 0 {
+		// Deleted before its sync ran
+		return s.removeVectors(ctx, claim)
+	}
+	record := result.Records[0]
+	if recordString(record, "status") != "processed" {
+		// Being reprocessed; the processed event queues it again. The
+		// vectors of the earlier text are recycled meanwhile.
+		return s.park(ctx, claim)
+	}
+
+	target := vectorSyncTarget{
+		documentID: claim.documentID,
+		tenantID:   recordString(record, "tenant_id"),
+		spaceID:    recordString(record, "space_id"),
+		notebookID: recordString(record, "notebook_id"),
+		fileID:     recordString(record, "file_id"),
+		name:       recordString(record, "name"),
+		mimeType:   recordString(record, "mime_type"),
+	}
+	if target.fileID == "" {
+		return fmt.Errorf("document has no processed file")
+	}
+	tenant, err := s.spaces.ResolveTenant(ctx, models.TenantLookupBySpaceID, target.spaceID)
+	if err != nil {
+		return fmt.Errorf("failed to resolve space namespace: %w", err)
+	}
+	target.namespace = tenant.DeeplakeNamespace
+	if target.namespace == "" {
+		target.namespace = tenant.TenantID
+	}
+	if claim.namespace == target.namespace {
+		target.previousCount = claim.vectorCount
+	} else if claim.namespace != "" && claim.vectorCount > 0 {
+		// The space moved to another namespace; drop the old copies
+		if err := s.deleteVectors(ctx, claim.namespace, claim.documentID, 0, claim.vectorCount); err != nil {
+			return err
+		}
+	}
+
+	count, err := s.pushChunks(ctx, target)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	_, err = s.neo4j.ExecuteQuery(ctx, `
+		MATCH (v:VectorSync {document_id: $document_id})
+		WHERE v.generation = $generation
+		SET v.status = $synced,
+		    v.namespace = $namespace,
+		    v.vector_count = $vector_count,
+		    v.attempts = 0,
+		    v.error = null,
+		    v.next_attempt_at = null,
+		    v.synced_at = $now,
+		    v.updated_at = $now
+	`, map[string]interface{}{
+		"document_id":  claim.documentID,
+		"generation":   claim.generation,
+		"synced":       models.VectorSyncSynced,
+		"namespace":    target.namespace,
+		"vector_count": count,
+		"now":          now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record vector sync: %w", err)
+	}
+
+	s.logger.Info("Document vectors synced",
+		zap.String("document_id", claim.documentID),
+		zap.String("namespace", target.namespace),
+		zap.Int64("vectors", count),
+	)
+	return nil
+}
+
+// park leaves a document pending without a due time until it is queued again
+func (s *VectorSyncService) park(ctx context.Context, claim vectorSyncClaim) error {
+	_, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (v:VectorSync {document_id: $document_id})
+		WHERE v.generation = $generation
+		SET v.status = $pending, v.next_attempt_at = null, v.updated_at = $now
+	`, map[string]interface{}{
+		"document_id": claim.documentID,
+		"generation":  claim.generation,
+		"pending":     models.VectorSyncPending,
+		"now":         time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to park vector sync: %w", err)
+	}
+	return nil
+}
+
+// RemoveDocumentVectors deletes the vectors of a deleted document and its
+// VectorSync node; a document without one has none. The deletion
+// orchestrator calls it and retries it on failure.
+func (s *VectorSyncService) RemoveDocumentVectors(ctx context.Context, documentID string) error {
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (v:VectorSync {document_id: $document_id})
+		RETURN `+vectorSyncFields, map[string]interface{}{"document_id": documentID})
+	if err != nil {
+		return fmt.Errorf("failed to load vector sync state: %w", err)
+	}
+	if len(result.Records) == 0 {
+		return nil
+	}
+	return s.removeVectors(ctx, recordToVectorSyncClaim(result.Records[0]))
+}
+
+// RemoveStaleVectors deletes the vectors of a reprocessed document, unless
+// its VectorSync node moved past generation: the document was queued again
+// and its new sync overwrites them. The node is kept, with no vectors, for
+// the next sync. The deletion orchestrator calls it and retries it on
+// failure.
+func (s *VectorSyncService) RemoveStaleVectors(ctx context.Context, documentID string, generation int64) error {
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (v:VectorSync {document_id: $document_id})
+		RETURN `+vectorSyncFields, map[string]interface{}{"document_id": documentID})
+	if err != nil {
+		return fmt.Errorf("failed to load vector sync state: %w", err)
+	}
+	if len(result.Records) == 0 {
+		return nil
+	}
+	claim := recordToVectorSyncClaim(result.Records[0])
+	if claim.generation != generation || claim.namespace == "" || claim.vectorCount == 0 {
+		return nil
+	}
+	if err := s.deleteVectors(ctx, claim.namespace, documentID, 0, claim.vectorCount); err != nil {
+		return err
+	}
+	_, err = s.neo4j.ExecuteQuery(ctx, `
+		MATCH (v:VectorSync {document_id: $document_id})
+		WHERE v.generation = $generation
+		SET v.vector_count = 0, v.updated_at = $now
+	`, map[string]interface{}{
+		"document_id": documentID,
+		"generation":  generation,
+		"now":         time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record removed vectors: %w", err)
+	}
+
+	s.logger.Info("Deleted stale document vectors",
+		zap.String("document_id", documentID),
+		zap.String("namespace", claim.namespace),
+		zap.Int64("vectors", claim.vectorCount),
+	)
+	return nil
+}
+
+// removeVectors deletes every vector of a deleted document, then its
+// VectorSync node
+func (s *VectorSyncService) removeVectors(ctx context.Context, claim vectorSyncClaim) error {
+	if claim.namespace != "" && claim.vectorCount > 0 {
+		if err := s.deleteVectors(ctx, claim.namespace, claim.documentID, 0, claim.vectorCount); err != nil {
+			return err
+		}
+	}
+	_, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (v:VectorSync {document_id: $document_id})
+		WHERE v.generation = $generation
+		DETACH DELETE v
+	`, map[string]interface{}{
+		"document_id": claim.documentID,
+		"generation":  claim.generation,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete vector sync state: %w", err)
+	}
+
+	s.logger.Info("Deleted document vectors",
+		zap.String("document_id", claim.documentID),
+		zap.String("namespace", claim.namespace),
+		zap.Int64("vectors", claim.vectorCount),
+	)
+	return nil
+}
+
+// recordFailure schedules the next attempt with exponential backoff, or
+// marks the document failed once its attempts are used up
+func (s *VectorSyncService) recordFailure(ctx context.Context, claim vectorSyncClaim, cause error) {
+	attempts := claim.attempts + 1
+	status := models.VectorSyncPending
+	if claim.status == models.VectorSyncDeleting {
+		status = models.VectorSyncDeleting
+	}
+	now := time.Now().UTC()
+	var nextAttempt interface{} = now.Add(vectorSyncBackoff(attempts))
+	if attempts >= s.maxAttempts {
+		status = models.VectorSyncFailed
+		nextAttempt = nil
+	}
+
+	s.logger.FromContext(ctx).Error("Vector sync attempt failed",
+		zap.String("document_id", claim.documentID),
+		zap.Int64("attempt", attempts),
+		zap.String("status", status),
+		zap.Error(cause),
+	)
+
+	_, err := s.neo4j.ExecuteQuery(context.WithoutCancel(ctx), `
+		MATCH (v:VectorSync {document_id: $document_id})
+		WHERE v.generation = $generation
+		SET v.status = $status,
+		    v.attempts = $attempts,
+		    v.error = $error,
+		    v.next_attempt_at = $next_attempt_at,
+		    v.updated_at = $now
+	`, map[string]interface{}{
+		"document_id":     claim.documentID,
+		"generation":      claim.generation,
+		"status":          status,
+		"attempts":        attempts,
+		"error":           cause.Error(),
+		"next_attempt_at": nextAttempt,
+		"now":             now,
+	})
+	if err != nil {
+		// The lease expires and the document is claimed again
+		s.logger.Error("Failed to record vector sync failure",
+			zap.String("document_id", claim.documentID),
+			zap.Error(err),
+		)
+	}
+}
+
+// vectorSyncBackoff returns the delay before the given failed attempt is
+// retried: 30s, 1m, 2m, ... up to 30m
+func vectorSyncBackoff(attempts int64) time.Duration {
+	backoff := vectorSyncBaseBackoff
+	for i := int64(1); i < attempts && backoff < vectorSyncMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > vectorSyncMaxBackoff {
+		backoff = vectorSyncMaxBackoff
+	}
+	return backoff
+}
+
+// vectorSyncTarget is the document version being pushed and where to
+type vectorSyncTarget struct {
+	documentID    string
+	tenantID      string
+	spaceID       string
+	notebookID    string
+	fileID        string
+	name          string
+	mimeType      string
+	namespace     string
+	previousCount int64 // Vectors the namespace holds from the last sync
+}
+
+// deepLakeVectorUpsert is a chunk vector written to the space dataset. The
+// document fields let semantic search map hits back to documents.
+type deepLakeVectorUpsert struct {
+	ID          string                 `json:"id"`
+	DocumentID  string                 `json:"document_id"`
+	ChunkID     string                 `json:"chunk_id"`
+	Values      []float32              `json:"values"`
+	Content     string                 `json:"content"`
+	ContentHash string                 `json:"content_hash,omitempty"`
+	ChunkIndex  int64                  `json:"chunk_index"`
+	Language    string                 `json:"language,omitempty"`
+	Model       string                 `json:"model,omitempty"`
+	Metadata    map[string]interface{} `json:"metadata"`
+}
+
+// pushChunks embeds and upserts the document's chunks one page at a time
+// and returns how many vectors it now has
+func (s *VectorSyncService) pushChunks(ctx context.Context, target vectorSyncTarget) (int64, error) {
+	var count int64
+	offset := 0
+	for {
+		page, err := s.chunks.GetFileChunks(ctx, target.tenantID, target.fileID, s.pageSize, offset)
+		if err != nil {
+			return 0, fmt.Errorf("failed to list chunks: %w", err)
+		}
+		offset += len(page.Data)
+
+		chunks := make([]ChunkData, 0, len(page.Data))
+		texts := make([]string, 0, len(page.Data))
+		for _, chunk := range page.Data {
+			// Embedding APIs reject empty input
+			if strings.TrimSpace(chunk.Content) == "" {
+				continue
+			}
+			chunks = append(chunks, chunk)
+			texts = append(texts, chunk.Content)
+		}
+
+		if len(texts) > 0 {
+			embeddings, err := s.embedder.GenerateBatchEmbeddings(ctx, texts)
+			if err != nil {
+				return 0, fmt.Errorf("failed to embed chunks: %w", err)
+			}
+			if len(embeddings) != len(texts) {
+				return 0, fmt.Errorf("embedding provider returned %d embeddings for %d chunks", len(embeddings), len(texts))
+			}
+
+			vectors := make([]deepLakeVectorUpsert, len(chunks))
+			for i, chunk := range chunks {
+				vectors[i] = s.chunkVector(target, count+int64(i), chunk, embeddings[i])
+			}
+			if err := s.upsertVectors(ctx, target.namespace, vectors); err != nil {
+				return 0, err
+			}
+			count += int64(len(vectors))
+		}
+
+		if len(page.Data) < s.pageSize || (page.Total > 0 && offset >= page.Total) {
+			break
+		}
+	}
+
+	// Vectors past the new count belong to an earlier, longer version
+	if err := s.deleteVectors(ctx, target.namespace, target.documentID, count, target.previousCount); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// chunkVector builds the vector of the index-th chunk of a document
+func (s *VectorSyncService) chunkVector(target vectorSyncTarget, index int64, chunk ChunkData, embedding []float32) deepLakeVectorUpsert {
+	return deepLakeVectorUpsert{
+		ID:          vectorID(target.documentID, index),
+		DocumentID:  target.documentID,
+		ChunkID:     chunk.ID,
+		Values:      embedding,
+		Content:     chunk.Content,
+		ContentHash: chunk.ContentHash,
+		ChunkIndex:  index,
+		Language:    chunk.Language,
+		Model:       s.embedder.GetModelName(),
+		Metadata: map[string]interface{}{
+			"document_id":  target.documentID,
+			"file_id":      target.fileID,
+			"chunk_id":     chunk.ID,
+			"chunk_number": chunk.ChunkNumber,
+			"chunk_type":   chunk.ChunkType,
+			"notebook_id":  target.notebookID,
+			"space_id":     target.spaceID,
+			"name":         target.name,
+			"mime_type":    target.mimeType,
+		},
+	}
+}
+
+// vectorID names the index-th chunk vector of a document
+func vectorID(documentID string, index int64) string {
+	return fmt.Sprintf("%s_%d", documentID, index)
+}
+
+// upsertVectors writes a batch of vectors, replacing those with the same
+// IDs. The space dataset is created on first use.
+func (s *VectorSyncService) upsertVectors(ctx context.Context, namespace string, vectors []deepLakeVectorUpsert) error {
+	payload := map[string]interface{}{
+		"vectors":   vectors,
+		"overwrite": true,
+	}
+	status, err := s.datasets.do(ctx, http.MethodPost, namespace, "/vectors/batch", payload, nil)
+	if status == http.StatusNotFound {
+		if err := s.datasets.createDataset(ctx, namespace); err != nil {
+			return err
+		}
+		_, err = s.datasets.do(ctx, http.MethodPost, namespace, "/vectors/batch", payload, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to store vectors: %w", err)
+	}
+	return nil
+}
+
+// deleteVectors deletes the vectors of a document with index in [from, to).
+// Vectors already gone are skipped, so an interrupted delete can repeat.
+func (s *VectorSyncService) deleteVectors(ctx context.Context, namespace, documentID string, from, to int64) error {
+	for index := from; index < to; index++ {
+		path := "/vectors/" + url.PathEscape(vectorID(documentID, index))
+		status, err := s.datasets.do(ctx, http.MethodDelete, namespace, path, nil, nil)
+		if err != nil && status != http.StatusNotFound {
+			return fmt.Errorf("failed to delete vector: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetDocumentSync returns the vector sync status of a document the user
+// can access
+func (s *VectorSyncService) GetDocumentSync(ctx context.Context, documentID, userID string, spaceCtx *models.SpaceContext) (*models.DocumentVectorSync, error) {
+	document, err := s.documents.GetDocumentByID(ctx, documentID, userID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+	return s.documentSync(ctx, document.ID)
+}
+
+// ResyncDocument queues a processed document the user may update for a
+// sync due now, including one that failed
+func (s *VectorSyncService) ResyncDocument(ctx context.Context, documentID, userID string, spaceCtx *models.SpaceContext) (*models.DocumentVectorSync, error) {
+	document, err := s.documents.GetDocumentByID(ctx, documentID, userID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+	if !spaceCtx.CanUpdate() {
+		return nil, errors.Forbidden("Insufficient permissions to sync this document")
+	}
+	if document.Status != "processed" {
+		return nil, errors.ConflictWithDetails("Only processed documents can be synced", map[string]interface{}{
+			"document_id": document.ID,
+			"status":      document.Status,
+		})
+	}
+	if err := s.enqueue(ctx, document.ID); err != nil {
+		return nil, errors.Database("Failed to queue vector sync", err)
+	}
+
+	s.logger.Info("Document vector sync requested",
+		zap.String("document_id", document.ID),
+		zap.String("user_id", userID),
+	)
+	return s.documentSync(ctx, document.ID)
+}
+
+// documentSync reads the VectorSync node of a document. A document that
+// was never queued reports VectorSyncNone.
+func (s *VectorSyncService) documentSync(ctx context.Context, documentID string) (*models.DocumentVectorSync, error) {
+	result, err := s.neo4j.ExecuteQuery(ctx, `
+		MATCH (v:VectorSync {document_id: $document_id})
+		RETURN `+vectorSyncFields, map[string]interface{}{"document_id": documentID})
+	if err != nil {
+		return nil, errors.Database("Failed to get vector sync status", err)
+	}
+	if len(result.Records) == 0 {
+		return &models.DocumentVectorSync{DocumentID: documentID, Status: models.VectorSyncNone}, nil
+	}
+	return recordToDocumentVectorSync(result.Records[0]), nil
+}
+
+func recordToVectorSyncClaim(record *neo4j.Record) vectorSyncClaim {
+	return vectorSyncClaim{
+		documentID:  recordString(record, "document_id"),
+		tenantID:    recordString(record, "tenant_id"),
+		status:      recordString(record, "status"),
+		namespace:   recordString(record, "namespace"),
+		vectorCount: recordInt64(record, "vector_count"),
+		attempts:    recordInt64(record, "attempts"),
+		generation:  recordInt64(record, "generation"),
+	}
+}
+
+func recordToDocumentVectorSync(record *neo4j.Record) *models.DocumentVectorSync {
+	sync := &models.DocumentVectorSync{
+		DocumentID:  recordString(record, "document_id"),
+		Status:      recordString(record, "status"),
+		VectorCount: recordInt64(record, "vector_count"),
+		Attempts:    recordInt64(record, "attempts"),
+		Error:       recordString(record, "error"),
+	}
+	if value, _ := record.Get("next_attempt_at"); value != nil {
+		if at, ok := value.(time.Time); ok {
+			sync.NextAttemptAt = &at
+		}
+	}
+	if value, _ := record.Get("synced_at"); value != nil {
+		if at, ok := value.(time.Time); ok {
+			sync.SyncedAt = &at
+		}
+	}
+	if value, _ := record.Get("updated_at"); value != nil {
+		sync.UpdatedAt, _ = value.(time.Time)
+	}
+	return sync
+}
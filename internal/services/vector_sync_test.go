@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+)
+
+// pagedChunkSource serves chunks the way AudiModal pages them
+type pagedChunkSource struct {
+	chunks []ChunkData
+	calls  int
+}
+
+func (p *pagedChunkSource) GetFileChunks(ctx context.Context, tenantID, fileID string, limit, offset int) (*ChunksResponse, error) {
+	p.calls++
+	end := offset + limit
+	if end > len(p.chunks) {
+		end = len(p.chunks)
+	}
+	if offset > end {
+		offset = end
+	}
+	return &ChunksResponse{Success: true, Data: p.chunks[offset:end], Total: len(p.chunks), Limit: limit, Offset: offset}, nil
+}
+
+type fakeEmbedder struct{}
+
+func (fakeEmbedder) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	return []float32{float32(len(text))}, nil
+}
+
+func (f fakeEmbedder) GenerateBatchEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, len(texts))
+	for i, text := range texts {
+		embeddings[i], _ = f.GenerateEmbedding(ctx, text)
+	}
+	return embeddings, nil
+}
+
+func (fakeEmbedder) GetDimensions() int   { return 1 }
+func (fakeEmbedder) GetModelName() string { return "fake" }
+
+// fakeDeepLake records the vectors written to one namespace
+type fakeDeepLake struct {
+	mu            sync.Mutex
+	datasetExists bool
+	vectors       map[string]deepLakeVectorUpsert
+	batches       int
+	deletes       []string
+}
+
+func (f *fakeDeepLake) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v1/datasets":
+		f.datasetExists = true
+		w.WriteHeader(http.StatusCreated)
+	case !f.datasetExists:
+		w.WriteHeader(http.StatusNotFound)
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v1/datasets/documents/vectors/batch":
+		var payload struct {
+			Vectors   []deepLakeVectorUpsert `json:"vectors"`
+			Overwrite bool                   `json:"overwrite"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || !payload.Overwrite {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.batches++
+		for _, vector := range payload.Vectors {
+			f.vectors[vector.ID] = vector
+		}
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/api/v1/datasets/documents/vectors/"):
+		id := strings.TrimPrefix(r.URL.Path, "/api/v1/datasets/documents/vectors/")
+		f.deletes = append(f.deletes, id)
+		if _, ok := f.vectors[id]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(f.vectors, id)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func newTestVectorSyncService(t *testing.T, chunks VectorSyncChunkSource, deepLake http.Handler) *VectorSyncService {
+	t.Helper()
+	server := httptest.NewServer(deepLake)
+	t.Cleanup(server.Close)
+
+	return NewVectorSyncService(nil, nil, nil, chunks, fakeEmbedder{}, &config.DeepLakeConfig{
+		BaseURL:        server.URL,
+		TimeoutSeconds: 5,
+		SpaceDataset:   "documents",
+	}, &config.EmbeddingConfig{BatchSize: 2, MaxRetries: 3}, setupTestLogger(t))
+}
+
+func testChunks(contents ...string) []ChunkData {
+	chunks := make([]ChunkData, len(contents))
+	for i, content := range contents {
+		chunks[i] = ChunkData{ID: fmt.Sprintf("chunk-%d", i), ChunkNumber: i, Content: content}
+	}
+	return chunks
+}
+
+func TestVectorSyncPushChunks(t *testing.T) {
+	deepLake := &fakeDeepLake{vectors: map[string]deepLakeVectorUpsert{}}
+	source := &pagedChunkSource{chunks: testChunks("one", "two", "  ", "four", "five")}
+	service := newTestVectorSyncService(t, source, deepLake)
+	target := vectorSyncTarget{documentID: "doc", fileID: "file", notebookID: "nb", namespace: "tenant_1"}
+
+	count, err := service.pushChunks(context.Background(), target)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), count, "blank chunks are not embedded")
+	assert.Equal(t, 3, source.calls)
+	assert.Equal(t, 3, deepLake.batches, "one upsert per page")
+	assert.True(t, deepLake.datasetExists, "the dataset is created on first use")
+
+	require.Len(t, deepLake.vectors, 4)
+	for i, content := range []string{"one", "two", "four", "five"} {
+		vector := deepLake.vectors[fmt.Sprintf("doc_%d", i)]
+		assert.Equal(t, content, vector.Content)
+		assert.Equal(t, "doc", vector.DocumentID)
+		assert.Equal(t, "file", vector.Metadata["file_id"])
+		assert.Equal(t, "nb", vector.Metadata["notebook_id"])
+	}
+
+	// A repeated sync overwrites the same vectors
+	source.calls = 0
+	target.previousCount = count
+	count, err = service.pushChunks(context.Background(), target)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), count)
+	assert.Len(t, deepLake.vectors, 4)
+	assert.Empty(t, deepLake.deletes)
+
+	// A reprocessed document with fewer chunks loses its surplus vectors
+	source.chunks = testChunks("only")
+	target.previousCount = count
+	count, err = service.pushChunks(context.Background(), target)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	assert.Equal(t, []string{"doc_1", "doc_2", "doc_3"}, deepLake.deletes)
+	require.Len(t, deepLake.vectors, 1)
+	assert.Equal(t, "only", deepLake.vectors["doc_0"].Content)
+}
+
+func TestVectorSyncPushChunksFailure(t *testing.T) {
+	service := newTestVectorSyncService(t, &pagedChunkSource{chunks: testChunks("one")},
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+
+	_, err := service.pushChunks(context.Background(), vectorSyncTarget{documentID: "doc", namespace: "tenant_1"})
+	assert.Error(t, err)
+}
+
+func TestVectorSyncBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, vectorSyncBackoff(1))
+	assert.Equal(t, time.Minute, vectorSyncBackoff(2))
+	assert.Equal(t, 2*time.Minute, vectorSyncBackoff(3))
+	assert.Equal(t, 30*time.Minute, vectorSyncBackoff(20))
+}
+
+func TestVectorSyncIgnoresOtherEvents(t *testing.T) {
+	service := newTestVectorSyncService(t, &pagedChunkSource{}, &fakeDeepLake{})
+	assert.NoError(t, service.HandleDomainEvent(context.Background(), NewDocumentEvent(EventDocumentUploaded, "doc", "user", nil)))
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/reports"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// WatermarkService keeps the original files of documents from users their
+// notebook is shared with view-only. Those users download a PDF rendition
+// of the document's text instead, stamped with their email and the time it
+// was rendered, so that a leaked copy names who it was made for.
+type WatermarkService struct {
+	documents *DocumentService
+	notebooks *NotebookService
+	redaction *RedactionService
+	logger    *logger.Logger
+}
+
+// NewWatermarkService creates a new watermark service. redaction may be
+// nil, in which case renditions show the whole text.
+func NewWatermarkService(documents *DocumentService, notebooks *NotebookService, redaction *RedactionService, log *logger.Logger) *WatermarkService {
+	return &WatermarkService{
+		documents: documents,
+		notebooks: notebooks,
+		redaction: redaction,
+		logger:    log.WithService("watermark_service"),
+	}
+}
+
+// ViewOnly reports whether a user reads a document only through a share of
+// its notebook below editor, and so gets renditions instead of its original
+// file. The document's owner and the space's owners and admins always get
+// the original, as do space members the notebook is not shared with.
+func (s *WatermarkService) ViewOnly(ctx context.Context, document *models.Document, userID string, spaceCtx *models.SpaceContext) (bool, error) {
+	if document.OwnerID == userID || document.NotebookID == "" || spaceCtx.CanManage() {
+		return false, nil
+	}
+	role, err := s.notebooks.NotebookRole(ctx, document.TenantID, document.NotebookID, userID)
+	if err != nil {
+		return false, err
+	}
+	return role != "" && !models.NotebookRoleAtLeast(role, models.NotebookRoleEditor), nil
+}
+
+// Render renders the text of a document as a PDF watermarked with viewer,
+// such as the user's email, and the current time. The notebook's redaction
+// rules apply to the text as they do to the extracted text endpoint.
+func (s *WatermarkService) Render(ctx context.Context, document *models.Document, userID, viewer string, spaceCtx *models.SpaceContext) ([]byte, error) {
+	text, err := s.renditionText(ctx, document, userID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	err = reports.WriteRenditionPDF(&buf, reports.Rendition{
+		Title:      document.Name,
+		Text:       text,
+		Watermark:  viewer,
+		RenderedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, errors.InternalWithCause("Failed to render the document", err)
+	}
+
+	s.logger.Info("Watermarked rendition served",
+		zap.String("document_id", document.ID),
+		zap.String("user_id", userID),
+		zap.Int("size", buf.Len()))
+	return buf.Bytes(), nil
+}
+
+// renditionText returns the text a rendition of a document shows to a user
+func (s *WatermarkService) renditionText(ctx context.Context, document *models.Document, userID string, spaceCtx *models.SpaceContext) (string, error) {
+	if s.redaction != nil {
+		rules, err := s.redaction.RulesFor(ctx, document, userID, spaceCtx)
+		if err != nil {
+			return "", err
+		}
+		if rules != nil {
+			text, _, err := s.redaction.RedactText(ctx, document, rules)
+			return text, err
+		}
+	}
+	if document.ExtractedText != "" {
+		return document.ExtractedText, nil
+	}
+	text, err := s.documents.GetExtractedText(ctx, document)
+	if err != nil {
+		if errors.IsAPIError(err) {
+			return "", err
+		}
+		return "", errors.ExternalService("Failed to read the text of the document", err)
+	}
+	return text, nil
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+)
+
+func TestWatermarkViewOnlyOriginalReaders(t *testing.T) {
+	// None of these reach the notebook shares, which need a database
+	s := &WatermarkService{logger: setupTestLogger(t)}
+	document := &models.Document{ID: "doc_1", OwnerID: "owner_1", NotebookID: "nb_1"}
+	member := &models.SpaceContext{TenantID: "tenant_1", UserRole: "member"}
+
+	viewOnly, err := s.ViewOnly(context.Background(), document, "owner_1", member)
+	require.NoError(t, err)
+	assert.False(t, viewOnly, "the owner gets the original")
+
+	viewOnly, err = s.ViewOnly(context.Background(), document, "user_2", &models.SpaceContext{TenantID: "tenant_1", UserRole: "admin"})
+	require.NoError(t, err)
+	assert.False(t, viewOnly, "space admins get the original")
+
+	viewOnly, err = s.ViewOnly(context.Background(), &models.Document{ID: "doc_2", OwnerID: "owner_1"}, "user_2", member)
+	require.NoError(t, err)
+	assert.False(t, viewOnly, "documents outside notebooks are not shared")
+}
+
+func TestWatermarkRender(t *testing.T) {
+	s := &WatermarkService{logger: setupTestLogger(t)}
+	document := &models.Document{ID: "doc_1", Name: "Budget", ExtractedText: "Line one\nLine two"}
+
+	pdf, err := s.Render(context.Background(), document, "user_2", "viewer@example.com", &models.SpaceContext{TenantID: "tenant_1"})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(pdf), "%PDF-1.4\n"))
+	assert.Contains(t, string(pdf), "(Line two) Tj")
+	assert.Contains(t, string(pdf), "Tm (viewer@example.com - ")
+}
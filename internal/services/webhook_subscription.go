@@ -0,0 +1,925 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+
+	"github.com/Tributary-ai-services/aether-be/internal/config"
+	"github.com/Tributary-ai-services/aether-be/internal/database"
+	"github.com/Tributary-ai-services/aether-be/internal/logger"
+	"github.com/Tributary-ai-services/aether-be/internal/models"
+	"github.com/Tributary-ai-services/aether-be/internal/pagination"
+	"github.com/Tributary-ai-services/aether-be/internal/webhooks"
+	"github.com/Tributary-ai-services/aether-be/pkg/errors"
+)
+
+// Outbound webhook limits
+const (
+	maxWebhookSubscriptionsPerSpace = 20
+	// webhookDeliveryBatch bounds how many deliveries one run sends
+	webhookDeliveryBatch = 50
+	// webhookDeliveryLease is how long a claimed delivery belongs to the
+	// run that claimed it; one still claimed after that is assumed
+	// abandoned by a replica that died mid-send and is claimed again
+	webhookDeliveryLease  = 2 * time.Minute
+	webhookBaseBackoff    = 30 * time.Second
+	webhookMaxBackoff     = time.Hour
+	webhookResponseLimit  = 1 << 10
+	webhookDeliveryMaxAge = 30 * 24 * time.Hour
+	webhookSecretPrefix   = "whsec_"
+	webhookUserAgent      = "Aether-Webhooks/1.0"
+)
+
+// webhookDomainEvents are the domain events delivered to webhooks: the
+// webhook event, the data field naming the event's subject and the event
+// data passed on. Other data, such as the tenant, stays internal.
+var webhookDomainEvents = map[EventType]struct {
+	event   string
+	subject string
+	fields  []string
+}{
+	EventDocumentProcessed:     {models.WebhookEventDocumentProcessed, "document_id", []string{"name", "status"}},
+	EventDocumentFailed:        {models.WebhookEventDocumentFailed, "document_id", []string{"name", "status", "error"}},
+	EventMemberAdded:           {models.WebhookEventMemberAdded, "user_id", []string{"role", "invited_by"}},
+	EventAgentExecuted:         {models.WebhookEventAgentExecuted, "agent_id", []string{"agent_type", "execution_id", "tokens_used", "cost_usd", "response_time_ms"}},
+	EventQuotaThresholdReached: {models.WebhookEventQuotaThreshold, "space_id", []string{"quota", "threshold", "used", "limit", "period"}},
+}
+
+// errWebhookTargetNotAllowed is returned when a webhook would connect to a
+// private, loopback or otherwise internal address
+var errWebhookTargetNotAllowed = fmt.Errorf("webhook target is not a public address")
+
+// webhookSubscriptionFields are the columns recordToWebhookSubscription reads
+const webhookSubscriptionFields = `w.id AS id, w.space_id AS space_id, w.tenant_id AS tenant_id,
+	       w.url AS url, w.events AS events, w.description AS description, w.active AS active,
+	       w.last_delivery_at AS last_delivery_at, w.last_status AS last_status,
+	       w.created_by AS created_by, w.created_at AS created_at, w.updated_at AS updated_at`
+
+// webhookDeliveryFields are the columns recordToWebhookDelivery reads
+const webhookDeliveryFields = `d.id AS id, d.subscription_id AS subscription_id, d.event_id AS event_id,
+	       d.event AS event, d.status AS status, d.test AS test, d.attempts AS attempts,
+	       d.response_status AS response_status, d.response_body AS response_body,
+	       d.error AS error, d.duration_ms AS duration_ms, d.next_attempt_at AS next_attempt_at,
+	       d.last_attempt_at AS last_attempt_at, d.delivered_at AS delivered_at,
+	       d.created_at AS created_at`
+
+// WebhookService delivers events of spaces to the URLs their admins
+// subscribe. Domain events queue a WebhookDelivery per matching
+// subscription, and the leader sends due deliveries on a schedule,
+// retrying failed ones with backoff, so deliveries survive restarts. With a
+// delivery queue the first attempt is handed to the queue instead and sent
+// at once by any replica; the schedule then sends retries and first
+// attempts the queue did not make within webhookDeliveryLease. Each
+// delivery is signed like an HMACScheme webhook with the subscription's
+// secret and keeps its ID across attempts, so subscribers can verify it
+// and drop duplicates.
+type WebhookService struct {
+	neo4j        *database.Neo4jClient
+	client       *http.Client
+	allowPrivate bool
+	maxAttempts  int64
+	queue        *DeliveryQueue // Sends first attempts when set
+	logger       *logger.Logger
+}
+
+// NewWebhookService creates a webhook service
+func NewWebhookService(neo4j *database.Neo4jClient, cfg config.WebhooksConfig, log *logger.Logger) *WebhookService {
+	maxAttempts := int64(cfg.DeliveryAttempts)
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	return &WebhookService{
+		neo4j:        neo4j,
+		client:       newWebhookClient(time.Duration(cfg.DeliveryTimeoutSecs)*time.Second, cfg.AllowPrivateTargets),
+		allowPrivate: cfg.AllowPrivateTargets,
+		maxAttempts:  maxAttempts,
+		logger:       log.WithService("webhook_service"),
+	}
+}
+
+// SetDeliveryQueue hands the first attempt of each delivery to a delivery
+// queue and registers the queue's handler of webhook deliveries
+func (s *WebhookService) SetDeliveryQueue(queue *DeliveryQueue) {
+	s.queue = queue
+	queue.Handle(DeliveryKindWebhook, s.deliverQueued)
+}
+
+// newWebhookClient returns the client deliveries are sent with. It does
+// not follow redirects or use a proxy and, unless allowPrivate, refuses to
+// connect to internal addresses, whatever the subscriber's DNS answers.
+func newWebhookClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+				return errWebhookTargetNotAllowed
+			}
+			return nil
+		}
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConnsPerHost: 2,
+			IdleConnTimeout:     time.Minute,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// publicIP reports whether an address is reachable on the internet rather
+// than internal to a network or host
+func publicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() && !ip.IsMulticast()
+}
+
+// checkURL validates a subscriber URL: http or https, with a host that,
+// unless private targets are allowed, resolves to public addresses only
+func (s *WebhookService) checkURL(ctx context.Context, rawURL string) error {
+	notAllowed := func(reason string) error {
+		return errors.ValidationWithDetails(reason, map[string]interface{}{
+			"url": rawURL,
+		}).WithErrorCode(errors.CodeWebhookTargetNotAllowed)
+	}
+
+	target, err := url.Parse(rawURL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Hostname() == "" {
+		return notAllowed("Webhook URL must be an http or https URL")
+	}
+	if target.User != nil {
+		return notAllowed("Webhook URL must not contain credentials")
+	}
+	if s.allowPrivate {
+		return nil
+	}
+
+	host := target.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		if !publicIP(ip) {
+			return notAllowed("Webhook URL must not point to a private address")
+		}
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		return notAllowed("Webhook URL host cannot be resolved")
+	}
+	for _, addr := range addrs {
+		if !publicIP(addr.IP) {
+			return notAllowed("Webhook URL must not point to a private address")
+		}
+	}
+	return nil
+}
+
+// CreateSubscription subscribes a URL to events of the current space. The
+// returned subscription carries its signing secret, which cannot be
+// retrieved again.
+func (s *WebhookService) CreateSubscription(ctx context.Context, req models.WebhookSubscriptionCreateRequest, spaceCtx *models.SpaceContext) (*models.WebhookSubscription, error) {
+	if !spaceCtx.CanManage() {
+		return nil, errors.Forbidden("Only space owners and admins can manage webhooks")
+	}
+	if err := s.checkURL(ctx, req.URL); err != nil {
+		return nil, err
+	}
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, errors.Internal("Failed to generate webhook secret")
+	}
+
+	now := time.Now().UTC()
+	subscription := &models.WebhookSubscription{
+		ID:          uuid.New().String(),
+		SpaceID:     spaceCtx.SpaceID,
+		TenantID:    spaceCtx.TenantID,
+		URL:         req.URL,
+		Events:      uniqueStrings(req.Events),
+		Description: req.Description,
+		Active:      true,
+		Secret:      webhookSecretPrefix + hex.EncodeToString(random),
+		CreatedBy:   spaceCtx.UserID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "webhook.create_subscription"), `
+		OPTIONAL MATCH (existing:WebhookSubscription {space_id: $space_id, tenant_id: $tenant_id})
+		WITH count(existing) AS subscriptions
+		WHERE subscriptions < $max_subscriptions
+		CREATE (w:WebhookSubscription {
+			id: $id, space_id: $space_id, tenant_id: $tenant_id,
+			url: $url, events: $events, description: $description, active: true,
+			secret: $secret, created_by: $created_by, created_at: $now, updated_at: $now
+		})
+		RETURN w.id AS id
+	`, map[string]interface{}{
+		"id":                subscription.ID,
+		"space_id":          subscription.SpaceID,
+		"tenant_id":         subscription.TenantID,
+		"max_subscriptions": maxWebhookSubscriptionsPerSpace,
+		"url":               subscription.URL,
+		"events":            subscription.Events,
+		"description":       subscription.Description,
+		"secret":            subscription.Secret,
+		"created_by":        subscription.CreatedBy,
+		"now":               now,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to create webhook subscription", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, errors.ValidationWithDetails("Space has too many webhook subscriptions", map[string]interface{}{
+			"max_subscriptions": maxWebhookSubscriptionsPerSpace,
+		})
+	}
+
+	s.logger.Info("Webhook subscription created",
+		zap.String("subscription_id", subscription.ID),
+		zap.String("space_id", subscription.SpaceID),
+		zap.Strings("events", subscription.Events))
+	return subscription, nil
+}
+
+// ListSubscriptions returns the webhook subscriptions of the current
+// space, oldest first
+func (s *WebhookService) ListSubscriptions(ctx context.Context, spaceCtx *models.SpaceContext) (*models.WebhookSubscriptionList, error) {
+	if !spaceCtx.CanManage() {
+		return nil, errors.Forbidden("Only space owners and admins can manage webhooks")
+	}
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "webhook.list_subscriptions"), `
+		MATCH (w:WebhookSubscription {space_id: $space_id, tenant_id: $tenant_id})
+		RETURN `+webhookSubscriptionFields+`
+		ORDER BY w.created_at
+	`, map[string]interface{}{
+		"space_id":  spaceCtx.SpaceID,
+		"tenant_id": spaceCtx.TenantID,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to list webhook subscriptions", err)
+	}
+
+	list := &models.WebhookSubscriptionList{Subscriptions: make([]*models.WebhookSubscription, 0, len(result.Records))}
+	for _, record := range result.Records {
+		list.Subscriptions = append(list.Subscriptions, recordToWebhookSubscription(record))
+	}
+	return list, nil
+}
+
+// GetSubscription returns a webhook subscription of the current space
+func (s *WebhookService) GetSubscription(ctx context.Context, subscriptionID string, spaceCtx *models.SpaceContext) (*models.WebhookSubscription, error) {
+	if !spaceCtx.CanManage() {
+		return nil, errors.Forbidden("Only space owners and admins can manage webhooks")
+	}
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "webhook.get_subscription"), `
+		MATCH (w:WebhookSubscription {id: $id, space_id: $space_id, tenant_id: $tenant_id})
+		RETURN `+webhookSubscriptionFields, map[string]interface{}{
+		"id":        subscriptionID,
+		"space_id":  spaceCtx.SpaceID,
+		"tenant_id": spaceCtx.TenantID,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to get webhook subscription", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, webhookSubscriptionNotFound(subscriptionID)
+	}
+	return recordToWebhookSubscription(result.Records[0]), nil
+}
+
+// UpdateSubscription changes the URL, events, description or state of a
+// webhook subscription of the current space
+func (s *WebhookService) UpdateSubscription(ctx context.Context, subscriptionID string, req models.WebhookSubscriptionUpdateRequest, spaceCtx *models.SpaceContext) (*models.WebhookSubscription, error) {
+	subscription, err := s.GetSubscription(ctx, subscriptionID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+	if req.URL != nil {
+		if err := s.checkURL(ctx, *req.URL); err != nil {
+			return nil, err
+		}
+		subscription.URL = *req.URL
+	}
+	if len(req.Events) > 0 {
+		subscription.Events = uniqueStrings(req.Events)
+	}
+	if req.Description != nil {
+		subscription.Description = *req.Description
+	}
+	if req.Active != nil {
+		subscription.Active = *req.Active
+	}
+	subscription.UpdatedAt = time.Now().UTC()
+
+	_, err = s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "webhook.update_subscription"), `
+		MATCH (w:WebhookSubscription {id: $id})
+		SET w.url = $url, w.events = $events, w.description = $description,
+		    w.active = $active, w.updated_at = $updated_at
+	`, map[string]interface{}{
+		"id":          subscription.ID,
+		"url":         subscription.URL,
+		"events":      subscription.Events,
+		"description": subscription.Description,
+		"active":      subscription.Active,
+		"updated_at":  subscription.UpdatedAt,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to update webhook subscription", err)
+	}
+
+	s.logger.Info("Webhook subscription updated",
+		zap.String("subscription_id", subscription.ID),
+		zap.Bool("active", subscription.Active))
+	return subscription, nil
+}
+
+// DeleteSubscription deletes a webhook subscription of the current space
+// with its deliveries; queued deliveries are not sent
+func (s *WebhookService) DeleteSubscription(ctx context.Context, subscriptionID string, spaceCtx *models.SpaceContext) error {
+	subscription, err := s.GetSubscription(ctx, subscriptionID, spaceCtx)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "webhook.delete_subscription"), `
+		MATCH (w:WebhookSubscription {id: $id})
+		OPTIONAL MATCH (w)-[:DELIVERS]->(d:WebhookDelivery)
+		DETACH DELETE d, w
+	`, map[string]interface{}{"id": subscription.ID})
+	if err != nil {
+		return errors.Database("Failed to delete webhook subscription", err)
+	}
+
+	s.logger.Info("Webhook subscription deleted", zap.String("subscription_id", subscription.ID), zap.String("space_id", subscription.SpaceID))
+	return nil
+}
+
+// ListDeliveries returns a page of a subscription's deliveries, newest
+// first
+func (s *WebhookService) ListDeliveries(ctx context.Context, subscriptionID string, limit, offset int, spaceCtx *models.SpaceContext) (*models.WebhookDeliveryList, error) {
+	subscription, err := s.GetSubscription(ctx, subscriptionID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+	limit, offset = pagination.Clamp(limit, offset)
+
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "webhook.list_deliveries"), `
+		MATCH (:WebhookSubscription {id: $id})-[:DELIVERS]->(d:WebhookDelivery)
+		RETURN `+webhookDeliveryFields+`
+		ORDER BY d.created_at DESC
+		SKIP $offset
+		LIMIT $limit
+	`, map[string]interface{}{
+		"id":     subscription.ID,
+		"offset": offset,
+		"limit":  limit + 1,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to list webhook deliveries", err)
+	}
+
+	deliveries := make([]*models.WebhookDelivery, 0, len(result.Records))
+	for _, record := range result.Records {
+		deliveries = append(deliveries, recordToWebhookDelivery(record))
+	}
+	deliveries, hasMore := pagination.Trim(deliveries, limit)
+	return &models.WebhookDeliveryList{Deliveries: deliveries, Limit: limit, Offset: offset, HasMore: hasMore}, nil
+}
+
+// TestSubscription sends a webhook.test event to a subscription of the
+// current space once, paused or not, and records the delivery
+func (s *WebhookService) TestSubscription(ctx context.Context, subscriptionID string, spaceCtx *models.SpaceContext) (*models.WebhookDelivery, error) {
+	subscription, err := s.GetSubscription(ctx, subscriptionID, spaceCtx)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := s.secret(ctx, subscription.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	payload := models.WebhookPayload{
+		ID:        uuid.New().String(),
+		Event:     models.WebhookEventTest,
+		SpaceID:   subscription.SpaceID,
+		Timestamp: now,
+		Data:      map[string]interface{}{"subscription_id": subscription.ID},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.InternalWithCause("Failed to encode webhook payload", err)
+	}
+
+	delivery := &models.WebhookDelivery{
+		ID:             uuid.New().String(),
+		SubscriptionID: subscription.ID,
+		EventID:        payload.ID,
+		Event:          payload.Event,
+		Test:           true,
+		Attempts:       1,
+		CreatedAt:      now,
+	}
+	attempt := s.send(ctx, subscription.URL, secret, delivery.ID, delivery.Event, body)
+	delivery.Status = models.WebhookDeliveryFailed
+	if attempt.succeeded() {
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.DeliveredAt = &attempt.at
+	}
+	delivery.ResponseStatus = int64(attempt.status)
+	delivery.ResponseBody = attempt.body
+	delivery.Error = attempt.errorMessage()
+	delivery.DurationMs = attempt.duration.Milliseconds()
+	delivery.LastAttemptAt = &attempt.at
+
+	_, err = s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "webhook.record_test"), `
+		MATCH (w:WebhookSubscription {id: $subscription_id})
+		CREATE (w)-[:DELIVERS]->(d:WebhookDelivery {
+			id: $id, subscription_id: w.id, event_id: $event_id, event: $event,
+			payload: $payload, status: $status, test: true, attempts: 1,
+			response_status: $response_status, response_body: $response_body,
+			error: $error, duration_ms: $duration_ms, last_attempt_at: $attempted_at,
+			delivered_at: $delivered_at, created_at: $now, updated_at: $attempted_at
+		})
+		SET w.last_delivery_at = $attempted_at, w.last_status = $status
+	`, map[string]interface{}{
+		"subscription_id": subscription.ID,
+		"id":              delivery.ID,
+		"event_id":        delivery.EventID,
+		"event":           delivery.Event,
+		"payload":         string(body),
+		"status":          delivery.Status,
+		"response_status": delivery.ResponseStatus,
+		"response_body":   delivery.ResponseBody,
+		"error":           delivery.Error,
+		"duration_ms":     delivery.DurationMs,
+		"attempted_at":    attempt.at,
+		"delivered_at":    nullableTime(delivery.DeliveredAt),
+		"now":             now,
+	})
+	if err != nil {
+		return nil, errors.Database("Failed to record webhook test delivery", err)
+	}
+	return delivery, nil
+}
+
+// secret returns the signing secret of a subscription
+func (s *WebhookService) secret(ctx context.Context, subscriptionID string) (string, error) {
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "webhook.get_secret"), `
+		MATCH (w:WebhookSubscription {id: $id})
+		RETURN w.secret AS secret
+	`, map[string]interface{}{"id": subscriptionID})
+	if err != nil {
+		return "", errors.Database("Failed to get webhook subscription", err)
+	}
+	if len(result.Records) == 0 {
+		return "", webhookSubscriptionNotFound(subscriptionID)
+	}
+	return recordString(result.Records[0], "secret"), nil
+}
+
+func webhookSubscriptionNotFound(subscriptionID string) error {
+	return errors.NotFoundWithDetails("Webhook subscription not found", map[string]interface{}{
+		"subscription_id": subscriptionID,
+	}).WithErrorCode(errors.CodeWebhookSubscriptionNotFound)
+}
+
+// HandleDomainEvent queues a delivery of an event for every active
+// subscription of its space that subscribed to it. It is subscribed to the
+// domain event bus.
+func (s *WebhookService) HandleDomainEvent(ctx context.Context, event Event) error {
+	spec, ok := webhookDomainEvents[event.Type]
+	if !ok {
+		return nil
+	}
+	spaceID, _ := event.Data["space_id"].(string)
+	if spaceID == "" {
+		return nil
+	}
+	// Agents read from agent-builder carry no tenant; space IDs are unique
+	// across tenants either way
+	tenantID, _ := event.Data["tenant_id"].(string)
+
+	body, err := json.Marshal(webhookPayload(event, spec.event, spec.subject, spec.fields, spaceID))
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	// Deliveries handed to the delivery queue are left to the schedule
+	// only if the queue did not send them within a lease
+	now := time.Now().UTC()
+	nextAttempt := now
+	if s.queue != nil {
+		nextAttempt = now.Add(webhookDeliveryLease)
+	}
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "webhook.enqueue"), `
+		MATCH (w:WebhookSubscription {space_id: $space_id})
+		WHERE w.active AND $event IN w.events AND ($tenant_id = '' OR w.tenant_id = $tenant_id)
+		CREATE (w)-[:DELIVERS]->(d:WebhookDelivery {
+			id: randomUUID(), subscription_id: w.id, event_id: $event_id, event: $event,
+			payload: $payload, status: $pending, test: false, attempts: 0,
+			next_attempt_at: $next_attempt_at, created_at: $now, updated_at: $now
+		})
+		RETURN d.id AS id
+	`, map[string]interface{}{
+		"space_id":        spaceID,
+		"tenant_id":       tenantID,
+		"event":           spec.event,
+		"event_id":        event.ID,
+		"payload":         string(body),
+		"pending":         models.WebhookDeliveryPending,
+		"next_attempt_at": nextAttempt,
+		"now":             now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to queue webhook deliveries: %w", err)
+	}
+
+	if s.queue != nil {
+		for _, record := range result.Records {
+			deliveryID := recordString(record, "id")
+			if err := s.queue.Enqueue(ctx, DeliveryKindWebhook, webhookJob{DeliveryID: deliveryID}); err != nil {
+				// The schedule sends it once the lease is over
+				s.logger.FromContext(ctx).Warn("Failed to queue webhook delivery",
+					zap.String("delivery_id", deliveryID),
+					zap.Error(err),
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// webhookJob is the delivery queue job of a delivery's first attempt
+type webhookJob struct {
+	DeliveryID string `json:"delivery_id"`
+}
+
+// deliverQueued sends the first attempt of a delivery handed to the
+// delivery queue. A delivery the schedule already sent, or whose
+// subscription was paused or deleted since, is skipped; its outcome is
+// recorded like a scheduled attempt, so only failing to claim it is
+// retried by the queue.
+func (s *WebhookService) deliverQueued(ctx context.Context, payload json.RawMessage) error {
+	var job webhookJob
+	if err := json.Unmarshal(payload, &job); err != nil || job.DeliveryID == "" {
+		s.logger.Error("Dropping malformed webhook delivery job", zap.ByteString("payload", payload))
+		return nil
+	}
+
+	now := time.Now().UTC()
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "webhook.claim_queued_delivery"), `
+		MATCH (w:WebhookSubscription)-[:DELIVERS]->(d:WebhookDelivery {id: $id})
+		WHERE d.status = $pending AND d.attempts = 0 AND w.active
+		SET d._claim_lock = true
+		REMOVE d._claim_lock
+		WITH w, d WHERE d.status = $pending
+		SET d.status = $delivering, d.next_attempt_at = $lease_until, d.updated_at = $now
+		RETURN d.id AS id, d.event AS event, d.payload AS payload, d.attempts AS attempts,
+		       w.url AS url, w.secret AS secret
+	`, map[string]interface{}{
+		"id":          job.DeliveryID,
+		"pending":     models.WebhookDeliveryPending,
+		"delivering":  models.WebhookDeliveryDelivering,
+		"now":         now,
+		"lease_until": now.Add(webhookDeliveryLease),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to claim webhook delivery: %w", err)
+	}
+	if len(result.Records) == 0 {
+		return nil
+	}
+	s.sendClaim(ctx, recordToWebhookClaim(result.Records[0]))
+	return nil
+}
+
+// webhookPayload builds the body delivered for a domain event: its subject
+// under the subject field and the passed-on fields of its data
+func webhookPayload(event Event, webhookEvent, subjectField string, fields []string, spaceID string) models.WebhookPayload {
+	data := map[string]interface{}{subjectField: event.Subject}
+	for _, field := range fields {
+		if value, ok := event.Data[field]; ok {
+			data[field] = value
+		}
+	}
+	if event.UserID != "" && event.Type == EventAgentExecuted {
+		data["user_id"] = event.UserID
+	}
+	return models.WebhookPayload{
+		ID:        event.ID,
+		Event:     webhookEvent,
+		SpaceID:   spaceID,
+		Timestamp: event.Timestamp,
+		Data:      data,
+	}
+}
+
+// webhookClaim is a delivery claimed by a run with what sending it needs
+type webhookClaim struct {
+	id       string
+	event    string
+	payload  string
+	attempts int64
+	url      string
+	secret   string
+}
+
+// ProcessDue sends the deliveries whose next attempt is due, as the
+// leader's webhook_deliveries job, then deletes finished deliveries older
+// than 30 days. The claim re-checks the due time under the delivery's
+// write lock, so overlapping runs do not send a delivery twice. Queued
+// deliveries of paused subscriptions are left until they are resumed.
+func (s *WebhookService) ProcessDue(ctx context.Context) error {
+	now := time.Now().UTC()
+	result, err := s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "webhook.claim_deliveries"), `
+		MATCH (w:WebhookSubscription)-[:DELIVERS]->(d:WebhookDelivery)
+		WHERE d.status IN $due AND d.next_attempt_at <= $now AND w.active
+		WITH w, d ORDER BY d.next_attempt_at LIMIT $limit
+		SET d._claim_lock = true
+		REMOVE d._claim_lock
+		WITH w, d WHERE d.next_attempt_at <= $now
+		SET d.status = $delivering, d.next_attempt_at = $lease_until, d.updated_at = $now
+		RETURN d.id AS id, d.event AS event, d.payload AS payload, d.attempts AS attempts,
+		       w.url AS url, w.secret AS secret
+	`, map[string]interface{}{
+		"due":         []string{models.WebhookDeliveryPending, models.WebhookDeliveryDelivering},
+		"delivering":  models.WebhookDeliveryDelivering,
+		"now":         now,
+		"lease_until": now.Add(webhookDeliveryLease),
+		"limit":       webhookDeliveryBatch,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+
+	for _, record := range result.Records {
+		if ctx.Err() != nil {
+			// Shutting down: the lease hands the rest to the next leader
+			return ctx.Err()
+		}
+		s.sendClaim(ctx, recordToWebhookClaim(record))
+	}
+
+	_, err = s.neo4j.ExecuteQuery(database.WithQueryName(ctx, "webhook.prune_deliveries"), `
+		MATCH (d:WebhookDelivery)
+		WHERE d.status IN $finished AND d.updated_at < $cutoff
+		WITH d LIMIT 1000
+		DETACH DELETE d
+	`, map[string]interface{}{
+		"finished": []string{models.WebhookDeliverySucceeded, models.WebhookDeliveryFailed},
+		"cutoff":   now.Add(-webhookDeliveryMaxAge),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to prune webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+// sendClaim sends a claimed delivery once and records the outcome
+func (s *WebhookService) sendClaim(ctx context.Context, claim webhookClaim) {
+	s.recordAttempt(ctx, claim, s.send(ctx, claim.url, claim.secret, claim.id, claim.event, []byte(claim.payload)))
+}
+
+// recordToWebhookClaim reads a claimed delivery
+func recordToWebhookClaim(record *neo4j.Record) webhookClaim {
+	return webhookClaim{
+		id:       recordString(record, "id"),
+		event:    recordString(record, "event"),
+		payload:  recordString(record, "payload"),
+		attempts: recordInt64(record, "attempts"),
+		url:      recordString(record, "url"),
+		secret:   recordString(record, "secret"),
+	}
+}
+
+// recordAttempt records the outcome of sending a delivery: succeeded, due
+// again after its backoff, or failed once its attempts are used up
+func (s *WebhookService) recordAttempt(ctx context.Context, claim webhookClaim, attempt webhookAttempt) {
+	attempts := claim.attempts + 1
+	status := models.WebhookDeliveryPending
+	var nextAttempt, deliveredAt interface{}
+	switch {
+	case attempt.succeeded():
+		status = models.WebhookDeliverySucceeded
+		deliveredAt = attempt.at
+	case attempts >= s.maxAttempts:
+		status = models.WebhookDeliveryFailed
+	default:
+		nextAttempt = attempt.at.Add(webhookBackoff(attempts))
+	}
+
+	if !attempt.succeeded() {
+		s.logger.FromContext(ctx).Warn("Webhook delivery attempt failed",
+			zap.String("delivery_id", claim.id),
+			zap.String("event", claim.event),
+			zap.Int64("attempt", attempts),
+			zap.Int("response_status", attempt.status),
+			zap.String("status", status),
+			zap.String("error", attempt.errorMessage()),
+		)
+	}
+
+	_, err := s.neo4j.ExecuteQuery(database.WithQueryName(context.WithoutCancel(ctx), "webhook.record_attempt"), `
+		MATCH (w:WebhookSubscription)-[:DELIVERS]->(d:WebhookDelivery {id: $id})
+		SET d.status = $status,
+		    d.attempts = $attempts,
+		    d.response_status = $response_status,
+		    d.response_body = $response_body,
+		    d.error = $error,
+		    d.duration_ms = $duration_ms,
+		    d.next_attempt_at = $next_attempt_at,
+		    d.last_attempt_at = $attempted_at,
+		    d.delivered_at = $delivered_at,
+		    d.updated_at = $attempted_at,
+		    w.last_delivery_at = $attempted_at,
+		    w.last_status = $status
+	`, map[string]interface{}{
+		"id":              claim.id,
+		"status":          status,
+		"attempts":        attempts,
+		"response_status": int64(attempt.status),
+		"response_body":   attempt.body,
+		"error":           attempt.errorMessage(),
+		"duration_ms":     attempt.duration.Milliseconds(),
+		"next_attempt_at": nextAttempt,
+		"attempted_at":    attempt.at,
+		"delivered_at":    deliveredAt,
+	})
+	if err != nil {
+		// The lease expires and the delivery is sent again
+		s.logger.Error("Failed to record webhook delivery attempt",
+			zap.String("delivery_id", claim.id),
+			zap.Error(err),
+		)
+	}
+}
+
+// webhookAttempt is the outcome of sending a delivery once
+type webhookAttempt struct {
+	at       time.Time
+	status   int    // HTTP status of the answer; 0 when there was none
+	body     string // Start of the answer's body
+	err      error
+	duration time.Duration
+}
+
+// succeeded reports whether the subscriber accepted the delivery
+func (a webhookAttempt) succeeded() bool {
+	return a.err == nil && a.status >= 200 && a.status < 300
+}
+
+// errorMessage describes why an attempt failed, empty when it succeeded
+func (a webhookAttempt) errorMessage() string {
+	switch {
+	case a.err != nil:
+		return a.err.Error()
+	case !a.succeeded():
+		return "subscriber answered " + strconv.Itoa(a.status)
+	default:
+		return ""
+	}
+}
+
+// send posts a delivery to a subscriber, signed with its secret
+func (s *WebhookService) send(ctx context.Context, targetURL, secret, deliveryID, event string, body []byte) webhookAttempt {
+	attempt := webhookAttempt{at: time.Now().UTC()}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, targetURL, bytes.NewReader(body))
+	if err != nil {
+		attempt.err = fmt.Errorf("invalid webhook request: %w", err)
+		return attempt
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", webhookUserAgent)
+	req.Header.Set("X-Webhook-ID", deliveryID)
+	req.Header.Set("X-Webhook-Event", event)
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(attempt.at.Unix(), 10))
+	req.Header.Set("X-Webhook-Signature", webhooks.Sign(secret, deliveryID, attempt.at, body))
+
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	attempt.duration = time.Since(start)
+	if err != nil {
+		attempt.err = err
+		return attempt
+	}
+	defer resp.Body.Close()
+
+	attempt.status = resp.StatusCode
+	answer, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseLimit))
+	attempt.body = string(bytes.ToValidUTF8(answer, nil))
+	return attempt
+}
+
+// webhookBackoff returns the delay before a delivery that failed the
+// given number of attempts is sent again: 30s, 1m, 2m, ... up to 1h
+func webhookBackoff(attempts int64) time.Duration {
+	backoff := webhookBaseBackoff
+	for i := int64(1); i < attempts && backoff < webhookMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > webhookMaxBackoff {
+		backoff = webhookMaxBackoff
+	}
+	return backoff
+}
+
+// uniqueStrings returns values without repeats, in their first order
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+// nullableTime returns a time for a query parameter, nil when unset
+func nullableTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
+
+// recordToWebhookSubscription reads the webhookSubscriptionFields of a
+// record
+func recordToWebhookSubscription(record *neo4j.Record) *models.WebhookSubscription {
+	subscription := &models.WebhookSubscription{
+		ID:          recordString(record, "id"),
+		SpaceID:     recordString(record, "space_id"),
+		TenantID:    recordString(record, "tenant_id"),
+		URL:         recordString(record, "url"),
+		Events:      recordStrings(record, "events"),
+		Description: recordString(record, "description"),
+		LastStatus:  recordString(record, "last_status"),
+		CreatedBy:   recordString(record, "created_by"),
+		CreatedAt:   recordTime(record, "created_at"),
+		UpdatedAt:   recordTime(record, "updated_at"),
+	}
+	if value, _ := record.Get("active"); value != nil {
+		subscription.Active, _ = value.(bool)
+	}
+	if at := recordTime(record, "last_delivery_at"); !at.IsZero() {
+		subscription.LastDeliveryAt = &at
+	}
+	return subscription
+}
+
+// recordToWebhookDelivery reads the webhookDeliveryFields of a record
+func recordToWebhookDelivery(record *neo4j.Record) *models.WebhookDelivery {
+	delivery := &models.WebhookDelivery{
+		ID:             recordString(record, "id"),
+		SubscriptionID: recordString(record, "subscription_id"),
+		EventID:        recordString(record, "event_id"),
+		Event:          recordString(record, "event"),
+		Status:         recordString(record, "status"),
+		Attempts:       recordInt64(record, "attempts"),
+		ResponseStatus: recordInt64(record, "response_status"),
+		ResponseBody:   recordString(record, "response_body"),
+		Error:          recordString(record, "error"),
+		DurationMs:     recordInt64(record, "duration_ms"),
+		CreatedAt:      recordTime(record, "created_at"),
+	}
+	if value, _ := record.Get("test"); value != nil {
+		delivery.Test, _ = value.(bool)
+	}
+	for key, target := range map[string]**time.Time{
+		"next_attempt_at": &delivery.NextAttemptAt,
+		"last_attempt_at": &delivery.LastAttemptAt,
+		"delivered_at":    &delivery.DeliveredAt,
+	} {
+		if at := recordTime(record, key); !at.IsZero() {
+			*target = &at
+		}
+	}
+	return delivery
+}
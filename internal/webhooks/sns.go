@@ -0,0 +1,229 @@
+package webhooks
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Types of the messages SNS delivers to an HTTPS subscription
+const (
+	SNSNotification             = "Notification"
+	SNSSubscriptionConfirmation = "SubscriptionConfirmation"
+	SNSUnsubscribeConfirmation  = "UnsubscribeConfirmation"
+)
+
+// SNSMessage is a message Amazon SNS delivers to an HTTPS subscription
+type SNSMessage struct {
+	Type             string `json:"Type" validate:"required"`
+	MessageID        string `json:"MessageId" validate:"required"`
+	TopicArn         string `json:"TopicArn" validate:"required"`
+	Subject          string `json:"Subject,omitempty"`
+	Message          string `json:"Message"`
+	Timestamp        string `json:"Timestamp" validate:"required"`
+	SignatureVersion string `json:"SignatureVersion"`
+	Signature        string `json:"Signature"`
+	SigningCertURL   string `json:"SigningCertURL"`
+	SubscribeURL     string `json:"SubscribeURL,omitempty"`
+	Token            string `json:"Token,omitempty"`
+}
+
+// snsHost matches the hosts SNS serves signing certificates and
+// subscription confirmations from
+var snsHost = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+// maxCertificateBytes bounds a downloaded signing certificate
+const maxCertificateBytes = 64 << 10
+
+// SNSScheme verifies deliveries of Amazon SNS, which signs every message
+// with the key of the certificate at its SigningCertURL. The secret is the
+// ARN of the topic deliveries must come from. The delivery ID is the
+// MessageId, which SNS keeps when it retries. Certificates are downloaded
+// from SNS hosts only, once each.
+type SNSScheme struct {
+	client *http.Client
+	fetch  func(url string) ([]byte, error)
+
+	mu    sync.Mutex
+	certs map[string]*x509.Certificate
+}
+
+// NewSNSScheme creates an SNS scheme downloading certificates and
+// confirming subscriptions with client
+func NewSNSScheme(client *http.Client) *SNSScheme {
+	s := &SNSScheme{
+		client: client,
+		certs:  make(map[string]*x509.Certificate),
+	}
+	s.fetch = s.get
+	return s
+}
+
+// Verify implements Scheme
+func (s *SNSScheme) Verify(header http.Header, body []byte, secret string) (Delivery, error) {
+	var message SNSMessage
+	if err := json.Unmarshal(body, &message); err != nil || message.MessageID == "" || message.TopicArn != secret {
+		return Delivery{}, ErrInvalidSignature
+	}
+	signedAt, err := time.Parse(time.RFC3339Nano, message.Timestamp)
+	if err != nil {
+		return Delivery{}, ErrInvalidSignature
+	}
+
+	signed, err := snsStringToSign(message)
+	if err != nil {
+		return Delivery{}, ErrInvalidSignature
+	}
+	var hash crypto.Hash
+	var digest []byte
+	switch message.SignatureVersion {
+	case "1":
+		sum := sha1.Sum([]byte(signed))
+		hash, digest = crypto.SHA1, sum[:]
+	case "2":
+		sum := sha256.Sum256([]byte(signed))
+		hash, digest = crypto.SHA256, sum[:]
+	default:
+		return Delivery{}, ErrInvalidSignature
+	}
+	signature, err := base64.StdEncoding.DecodeString(message.Signature)
+	if err != nil {
+		return Delivery{}, ErrInvalidSignature
+	}
+
+	cert, err := s.certificate(message.SigningCertURL)
+	if err != nil {
+		return Delivery{}, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	key, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return Delivery{}, ErrInvalidSignature
+	}
+	// x509.Certificate.CheckSignature refuses SHA-1, which version 1
+	// signatures still use
+	if err := rsa.VerifyPKCS1v15(key, hash, digest, signature); err != nil {
+		return Delivery{}, ErrInvalidSignature
+	}
+	return Delivery{ID: message.MessageID, Timestamp: signedAt}, nil
+}
+
+// Confirm confirms the subscription a verified SubscriptionConfirmation
+// message asks for, so SNS starts delivering the topic's notifications
+func (s *SNSScheme) Confirm(ctx context.Context, message SNSMessage) error {
+	if err := checkSNSURL(message.SubscribeURL); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, message.SubscribeURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to confirm SNS subscription: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("SNS subscription confirmation returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// certificate returns the signing certificate at rawURL
+func (s *SNSScheme) certificate(rawURL string) (*x509.Certificate, error) {
+	if err := checkSNSURL(rawURL); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	cert, ok := s.certs[rawURL]
+	s.mu.Unlock()
+	if ok {
+		return cert, nil
+	}
+
+	data, err := s.fetch(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("signing certificate is not PEM encoded")
+	}
+	cert, err = x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing certificate: %w", err)
+	}
+
+	s.mu.Lock()
+	s.certs[rawURL] = cert
+	s.mu.Unlock()
+	return cert, nil
+}
+
+func (s *SNSScheme) get(rawURL string) ([]byte, error) {
+	resp, err := s.client.Get(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download signing certificate: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("signing certificate download returned status %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxCertificateBytes))
+}
+
+// checkSNSURL rejects URLs that are not served by SNS over HTTPS, so a
+// forged message cannot make the server fetch arbitrary URLs
+func checkSNSURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Scheme != "https" || !snsHost.MatchString(parsed.Hostname()) || parsed.Port() != "" {
+		return fmt.Errorf("URL %q is not an SNS URL", rawURL)
+	}
+	return nil
+}
+
+// snsStringToSign returns the text SNS signs for message: the names and
+// values of its signed fields, in order, each on its own line
+func snsStringToSign(message SNSMessage) (string, error) {
+	var fields [][2]string
+	switch message.Type {
+	case SNSNotification:
+		fields = [][2]string{{"Message", message.Message}, {"MessageId", message.MessageID}}
+		if message.Subject != "" {
+			fields = append(fields, [2]string{"Subject", message.Subject})
+		}
+		fields = append(fields, [2]string{"Timestamp", message.Timestamp}, [2]string{"TopicArn", message.TopicArn}, [2]string{"Type", message.Type})
+	case SNSSubscriptionConfirmation, SNSUnsubscribeConfirmation:
+		fields = [][2]string{
+			{"Message", message.Message},
+			{"MessageId", message.MessageID},
+			{"SubscribeURL", message.SubscribeURL},
+			{"Timestamp", message.Timestamp},
+			{"Token", message.Token},
+			{"TopicArn", message.TopicArn},
+			{"Type", message.Type},
+		}
+	default:
+		return "", fmt.Errorf("unknown SNS message type %q", message.Type)
+	}
+
+	var b strings.Builder
+	for _, field := range fields {
+		b.WriteString(field[0] + "\n" + field[1] + "\n")
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,116 @@
+package webhooks
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testTopicArn = "arn:aws:sns:us-east-1:123456789012:inbound-email"
+	testCertURL  = "https://sns.us-east-1.amazonaws.com/SimpleNotificationService-test.pem"
+)
+
+// testSNSScheme returns a scheme serving a generated certificate at
+// testCertURL, and the key it signs with
+func testSNSScheme(t *testing.T) (*SNSScheme, *rsa.PrivateKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sns.amazonaws.com"},
+		NotBefore:    signedAt.Add(-time.Hour),
+		NotAfter:     signedAt.Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	scheme := NewSNSScheme(http.DefaultClient)
+	fetches := 0
+	scheme.fetch = func(url string) ([]byte, error) {
+		fetches++
+		require.Equal(t, 1, fetches, "certificates are cached")
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), nil
+	}
+	return scheme, key
+}
+
+func signSNS(t *testing.T, key *rsa.PrivateKey, message SNSMessage) []byte {
+	message.SignatureVersion = "2"
+	message.SigningCertURL = testCertURL
+	signed, err := snsStringToSign(message)
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	message.Signature = base64.StdEncoding.EncodeToString(signature)
+
+	body, err := json.Marshal(message)
+	require.NoError(t, err)
+	return body
+}
+
+func TestSNSScheme(t *testing.T) {
+	scheme, key := testSNSScheme(t)
+	v := newTestVerifier(testTopicArn, scheme, nil)
+	notification := SNSMessage{
+		Type:      SNSNotification,
+		MessageID: "message-1",
+		TopicArn:  testTopicArn,
+		Subject:   "Amazon SES Email Receipt Notification",
+		Message:   `{"notificationType":"Received"}`,
+		Timestamp: signedAt.Format(time.RFC3339Nano),
+	}
+
+	delivery, err := v.Verify(http.Header{}, signSNS(t, key, notification))
+	require.NoError(t, err)
+	assert.Equal(t, "message-1", delivery.ID)
+
+	// Subscription confirmations sign their URL and token too
+	confirmation := notification
+	confirmation.Type = SNSSubscriptionConfirmation
+	confirmation.Subject = ""
+	confirmation.SubscribeURL = "https://sns.us-east-1.amazonaws.com/?Action=ConfirmSubscription"
+	confirmation.Token = "token-1"
+	_, err = v.Verify(http.Header{}, signSNS(t, key, confirmation))
+	assert.NoError(t, err)
+
+	// The message and the topic are signed
+	var tampered SNSMessage
+	require.NoError(t, json.Unmarshal(signSNS(t, key, notification), &tampered))
+	tampered.Message = `{"notificationType":"Bounce"}`
+	body, _ := json.Marshal(tampered)
+	_, err = v.Verify(http.Header{}, body)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	other := notification
+	other.TopicArn = "arn:aws:sns:us-east-1:123456789012:other"
+	_, err = v.Verify(http.Header{}, signSNS(t, key, other))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestSNSSchemeDownloadsFromSNSOnly(t *testing.T) {
+	for _, url := range []string{
+		"http://sns.us-east-1.amazonaws.com/cert.pem",
+		"https://sns.us-east-1.amazonaws.com.evil.example/cert.pem",
+		"https://evil.example/sns.us-east-1.amazonaws.com/cert.pem",
+		"https://sns.us-east-1.amazonaws.com:8443/cert.pem",
+	} {
+		assert.Error(t, checkSNSURL(url), url)
+	}
+	assert.NoError(t, checkSNSURL(testCertURL))
+	assert.NoError(t, checkSNSURL("https://sns.cn-north-1.amazonaws.com.cn/cert.pem"))
+}
@@ -0,0 +1,220 @@
+// Package webhooks verifies inbound webhook deliveries: each integration
+// has its own secret and signature scheme, deliveries outside the
+// timestamp tolerance are rejected, and a delivery ID is accepted once.
+// middleware.VerifyWebhook applies a Verifier to a route; handlers decode
+// the verified body with DecodePayload.
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Tributary-ai-services/aether-be/internal/validation"
+)
+
+// Verification failures
+var (
+	ErrNotConfigured    = stderrors.New("webhook secret is not configured")
+	ErrInvalidSignature = stderrors.New("webhook signature is missing or invalid")
+	ErrExpired          = stderrors.New("webhook timestamp is outside the tolerance")
+)
+
+// Delivery identifies a verified delivery
+type Delivery struct {
+	ID        string    // Unique per event; redeliveries of an event keep it
+	Timestamp time.Time // When the sender signed the delivery
+}
+
+// Scheme checks the signature of a delivery with the integration's secret
+// and returns the delivery it identifies
+type Scheme interface {
+	Verify(header http.Header, body []byte, secret string) (Delivery, error)
+}
+
+// ReplayStore remembers delivery IDs for the replay window. services.LockStore
+// implements it, so replicas sharing Redis reject each other's replays.
+type ReplayStore interface {
+	AcquireLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error)
+	ReleaseLock(ctx context.Context, key, owner string) error
+}
+
+// Verifier verifies deliveries of one integration
+type Verifier struct {
+	name      string
+	secret    string
+	scheme    Scheme
+	tolerance time.Duration
+	replay    ReplayStore
+	now       func() time.Time
+}
+
+// NewVerifier creates a verifier for the named integration. Deliveries
+// signed more than tolerance away from now are rejected; replay may be nil
+// to accept repeated delivery IDs.
+func NewVerifier(name, secret string, scheme Scheme, tolerance time.Duration, replay ReplayStore) *Verifier {
+	return &Verifier{
+		name:      name,
+		secret:    secret,
+		scheme:    scheme,
+		tolerance: tolerance,
+		replay:    replay,
+		now:       time.Now,
+	}
+}
+
+// Name returns the integration name
+func (v *Verifier) Name() string {
+	return v.name
+}
+
+// Verify checks the signature and timestamp of a delivery
+func (v *Verifier) Verify(header http.Header, body []byte) (Delivery, error) {
+	if v.secret == "" {
+		return Delivery{}, ErrNotConfigured
+	}
+	delivery, err := v.scheme.Verify(header, body, v.secret)
+	if err != nil {
+		return Delivery{}, err
+	}
+	if age := v.now().Sub(delivery.Timestamp); age > v.tolerance || age < -v.tolerance {
+		return Delivery{}, ErrExpired
+	}
+	return delivery, nil
+}
+
+// Claim records that the delivery is being processed by owner. It returns
+// false when the delivery was already claimed within the replay window.
+func (v *Verifier) Claim(ctx context.Context, delivery Delivery, owner string) (bool, error) {
+	if v.replay == nil {
+		return true, nil
+	}
+	// Timestamps are accepted up to tolerance on either side of now, so an
+	// ID must be remembered for twice as long
+	return v.replay.AcquireLock(ctx, v.replayKey(delivery), owner, 2*v.tolerance)
+}
+
+// Release forgets a claimed delivery so the sender's retry is processed
+// after the delivery failed
+func (v *Verifier) Release(ctx context.Context, delivery Delivery, owner string) error {
+	if v.replay == nil {
+		return nil
+	}
+	return v.replay.ReleaseLock(ctx, v.replayKey(delivery), owner)
+}
+
+func (v *Verifier) replayKey(delivery Delivery) string {
+	return "aether-be:webhook:" + v.name + ":" + delivery.ID
+}
+
+// DecodePayload decodes a verified JSON body into v and validates it
+// against its validate tags
+func DecodePayload(body []byte, v interface{}) error {
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("invalid JSON payload: %w", err)
+	}
+	return validation.Validate(v)
+}
+
+// Signature headers of HMACScheme
+const (
+	HeaderID        = "X-Webhook-ID"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// HMACScheme is the scheme of Aether's own integrations. The sender sets
+// X-Webhook-ID to a unique delivery ID, X-Webhook-Timestamp to the Unix
+// time in seconds and X-Webhook-Signature to "v1=" and the hex HMAC-SHA256
+// of "<id>.<timestamp>.<body>". Several comma-separated signatures are
+// accepted while a secret is rotated.
+type HMACScheme struct{}
+
+// Verify implements Scheme
+func (HMACScheme) Verify(header http.Header, body []byte, secret string) (Delivery, error) {
+	id := header.Get(HeaderID)
+	timestamp := header.Get(HeaderTimestamp)
+	if id == "" || timestamp == "" {
+		return Delivery{}, ErrInvalidSignature
+	}
+	signedAt, err := parseUnix(timestamp)
+	if err != nil {
+		return Delivery{}, ErrInvalidSignature
+	}
+
+	expected := sign(secret, id+"."+timestamp+".", body)
+	for _, signature := range strings.Split(header.Get(HeaderSignature), ",") {
+		version, value, _ := strings.Cut(strings.TrimSpace(signature), "=")
+		if version == "v1" && hmac.Equal([]byte(value), []byte(expected)) {
+			return Delivery{ID: id, Timestamp: signedAt}, nil
+		}
+	}
+	return Delivery{}, ErrInvalidSignature
+}
+
+// Sign returns the X-Webhook-Signature value of an HMACScheme delivery
+func Sign(secret, id string, timestamp time.Time, body []byte) string {
+	return "v1=" + sign(secret, id+"."+strconv.FormatInt(timestamp.Unix(), 10)+".", body)
+}
+
+// StripeScheme verifies Stripe's Stripe-Signature header: "t=<timestamp>"
+// and one or more "v1=<hex HMAC-SHA256 of "<timestamp>.<body>">". The
+// delivery ID is the event ID, which Stripe keeps when it retries.
+type StripeScheme struct{}
+
+// Verify implements Scheme
+func (StripeScheme) Verify(header http.Header, body []byte, secret string) (Delivery, error) {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header.Get("Stripe-Signature"), ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	signedAt, err := parseUnix(timestamp)
+	if err != nil || len(signatures) == 0 {
+		return Delivery{}, ErrInvalidSignature
+	}
+
+	expected := sign(secret, timestamp+".", body)
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(signature), []byte(expected)) {
+			var event struct {
+				ID string `json:"id"`
+			}
+			if err := json.Unmarshal(body, &event); err != nil || event.ID == "" {
+				return Delivery{}, ErrInvalidSignature
+			}
+			return Delivery{ID: event.ID, Timestamp: signedAt}, nil
+		}
+	}
+	return Delivery{}, ErrInvalidSignature
+}
+
+// sign returns the hex HMAC-SHA256 of prefix followed by body
+func sign(secret, prefix string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(prefix))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func parseUnix(value string) (time.Time, error) {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(seconds, 0), nil
+}
@@ -0,0 +1,125 @@
+package webhooks
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var signedAt = time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+func hmacHeader(secret, id string, body []byte) http.Header {
+	header := http.Header{}
+	header.Set(HeaderID, id)
+	header.Set(HeaderTimestamp, strconv.FormatInt(signedAt.Unix(), 10))
+	header.Set(HeaderSignature, Sign(secret, id, signedAt, body))
+	return header
+}
+
+func newTestVerifier(secret string, scheme Scheme, replay ReplayStore) *Verifier {
+	v := NewVerifier("test", secret, scheme, 5*time.Minute, replay)
+	v.now = func() time.Time { return signedAt.Add(time.Minute) }
+	return v
+}
+
+func TestHMACScheme(t *testing.T) {
+	body := []byte(`{"file_id":"file-1"}`)
+	v := newTestVerifier("secret-1", HMACScheme{}, nil)
+
+	delivery, err := v.Verify(hmacHeader("secret-1", "delivery-1", body), body)
+	require.NoError(t, err)
+	assert.Equal(t, Delivery{ID: "delivery-1", Timestamp: signedAt}, Delivery{ID: delivery.ID, Timestamp: delivery.Timestamp.UTC()})
+
+	// The body, the delivery ID and the secret are all signed
+	_, err = v.Verify(hmacHeader("secret-1", "delivery-1", body), []byte(`{"file_id":"file-2"}`))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	header := hmacHeader("secret-1", "delivery-1", body)
+	header.Set(HeaderID, "delivery-2")
+	_, err = v.Verify(header, body)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	_, err = v.Verify(hmacHeader("secret-2", "delivery-1", body), body)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	// While a secret is rotated the sender signs with both
+	header = hmacHeader("secret-1", "delivery-1", body)
+	header.Set(HeaderSignature, Sign("old-secret", "delivery-1", signedAt, body)+", "+header.Get(HeaderSignature))
+	_, err = v.Verify(header, body)
+	assert.NoError(t, err)
+}
+
+func TestStripeScheme(t *testing.T) {
+	body := []byte(`{"id":"evt_1","type":"customer.subscription.updated"}`)
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+	header := http.Header{}
+	header.Set("Stripe-Signature", "t="+timestamp+",v1="+sign("whsec_1", timestamp+".", body)+",v0=legacy")
+	v := newTestVerifier("whsec_1", StripeScheme{}, nil)
+
+	delivery, err := v.Verify(header, body)
+	require.NoError(t, err)
+	assert.Equal(t, "evt_1", delivery.ID)
+
+	_, err = v.Verify(header, []byte(`{"id":"evt_2"}`))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestVerifierRejectsStaleAndUnconfigured(t *testing.T) {
+	body := []byte(`{}`)
+
+	v := newTestVerifier("secret-1", HMACScheme{}, nil)
+	v.now = func() time.Time { return signedAt.Add(6 * time.Minute) }
+	_, err := v.Verify(hmacHeader("secret-1", "delivery-1", body), body)
+	assert.ErrorIs(t, err, ErrExpired)
+
+	_, err = newTestVerifier("", HMACScheme{}, nil).Verify(hmacHeader("", "delivery-1", body), body)
+	assert.ErrorIs(t, err, ErrNotConfigured)
+}
+
+// memoryReplayStore is a ReplayStore without expiry
+type memoryReplayStore map[string]string
+
+func (m memoryReplayStore) AcquireLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	if _, held := m[key]; held {
+		return false, nil
+	}
+	m[key] = owner
+	return true, nil
+}
+
+func (m memoryReplayStore) ReleaseLock(ctx context.Context, key, owner string) error {
+	if m[key] == owner {
+		delete(m, key)
+	}
+	return nil
+}
+
+func TestVerifierClaimsDeliveriesOnce(t *testing.T) {
+	ctx := context.Background()
+	v := newTestVerifier("secret-1", HMACScheme{}, memoryReplayStore{})
+	delivery := Delivery{ID: "delivery-1", Timestamp: signedAt}
+
+	claimed, err := v.Claim(ctx, delivery, "request-1")
+	require.NoError(t, err)
+	assert.True(t, claimed)
+	claimed, _ = v.Claim(ctx, delivery, "request-2")
+	assert.False(t, claimed)
+
+	// A released delivery can be retried
+	require.NoError(t, v.Release(ctx, delivery, "request-1"))
+	claimed, _ = v.Claim(ctx, delivery, "request-3")
+	assert.True(t, claimed)
+}
+
+type testPayload struct {
+	FileID string `json:"file_id" validate:"required"`
+}
+
+func TestDecodePayloadValidates(t *testing.T) {
+	assert.NoError(t, DecodePayload([]byte(`{"file_id":"file-1"}`), &testPayload{}))
+	assert.Error(t, DecodePayload([]byte(`{}`), &testPayload{}))
+	assert.Error(t, DecodePayload([]byte(`not json`), &testPayload{}))
+}
@@ -0,0 +1,7544 @@
+// Code generated by cmd/openapi from the handler annotations. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// APIVersion is the version of the API the client was generated from
+const APIVersion = "0.1.0"
+
+// APIError represents a structured API error. Code is the error type;
+// ErrorCode is the stable catalogue code (see catalogue.go).
+type APIError struct {
+	Code      string                 `json:"code,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	ErrorCode string                 `json:"error_code,omitempty"`
+	Message   string                 `json:"message,omitempty"`
+	RequestID string                 `json:"request_id,omitempty"`
+}
+
+// AddMemberRequest represents a request to add a member to a space
+type AddMemberRequest struct {
+	Role   string `json:"role"`
+	UserID string `json:"user_id"`
+}
+
+// AgentCreateRequest represents a request to create an agent
+type AgentCreateRequest struct {
+	Description  string                 `json:"description,omitempty"`
+	IsPublic     bool                   `json:"is_public,omitempty"`
+	IsTemplate   bool                   `json:"is_template,omitempty"`
+	LLMConfig    map[string]interface{} `json:"llm_config"`
+	Name         string                 `json:"name"`
+	SpaceID      string                 `json:"space_id"`
+	SystemPrompt string                 `json:"system_prompt"`
+	Tags         []string               `json:"tags,omitempty"`
+	TeamID       string                 `json:"team_id,omitempty"`
+	Type         AgentType              `json:"type"`
+}
+
+// AgentExecuteRequest represents a request to execute an agent
+type AgentExecuteRequest struct {
+	Context        map[string]interface{} `json:"context,omitempty"`
+	ConversationID string                 `json:"conversation_id,omitempty"`
+	DryRun         bool                   `json:"dry_run,omitempty"`
+	History        []*ConversationMessage `json:"history,omitempty"`
+	Input          string                 `json:"input"`
+	MaxResults     int                    `json:"max_results,omitempty"`
+	OutputFormat   string                 `json:"output_format,omitempty"`
+	// Notebook IDs
+	Sources        []string               `json:"sources,omitempty"`
+	Template       string                 `json:"template,omitempty"`
+	TemplateParams map[string]interface{} `json:"template_params,omitempty"`
+}
+
+// AgentExecuteResponse represents the response from agent execution
+type AgentExecuteResponse struct {
+	AgentID        string                 `json:"agent_id,omitempty"`
+	AgentType      AgentType              `json:"agent_type,omitempty"`
+	CompletedAt    *time.Time             `json:"completed_at,omitempty"`
+	ConversationID string                 `json:"conversation_id,omitempty"`
+	CostUsd        float64                `json:"cost_usd,omitempty"`
+	ExecutionID    string                 `json:"execution_id,omitempty"`
+	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+	Output         string                 `json:"output,omitempty"`
+	Production     *ProductionResult      `json:"production,omitempty"`
+	ResponseTimeMs int                    `json:"response_time_ms,omitempty"`
+	Sources        []*SourceReference     `json:"sources,omitempty"`
+	StartedAt      *time.Time             `json:"started_at,omitempty"`
+	TokensUsed     int                    `json:"tokens_used,omitempty"`
+}
+
+// AgentListResponse represents a paginated list of agents
+type AgentListResponse struct {
+	Agents  []*AgentResponse `json:"agents,omitempty"`
+	HasMore bool             `json:"has_more,omitempty"`
+	Limit   int              `json:"limit,omitempty"`
+	Offset  int              `json:"offset,omitempty"`
+	Total   int              `json:"total,omitempty"`
+}
+
+// AgentResponse represents an agent response with related data
+type AgentResponse struct {
+	AgentBuilderID    string     `json:"agent_builder_id,omitempty"`
+	AvgResponseTimeMs int        `json:"avg_response_time_ms,omitempty"`
+	CreatedAt         *time.Time `json:"created_at,omitempty"`
+	Description       string     `json:"description,omitempty"`
+	ID                string     `json:"id,omitempty"`
+	IsPublic          bool       `json:"is_public,omitempty"`
+	IsTemplate        bool       `json:"is_template,omitempty"`
+	// Notebook IDs
+	KnowledgeSources   []string            `json:"knowledge_sources,omitempty"`
+	LastExecutedAt     *time.Time          `json:"last_executed_at,omitempty"`
+	Name               string              `json:"name,omitempty"`
+	Owner              *PublicUserResponse `json:"owner,omitempty"`
+	OwnerID            string              `json:"owner_id,omitempty"`
+	SpaceID            string              `json:"space_id,omitempty"`
+	SpaceType          SpaceType           `json:"space_type,omitempty"`
+	Status             AgentStatus         `json:"status,omitempty"`
+	SyncedAt           *time.Time          `json:"synced_at,omitempty"`
+	Tags               []string            `json:"tags,omitempty"`
+	Team               *TeamResponse       `json:"team,omitempty"`
+	TeamID             string              `json:"team_id,omitempty"`
+	TotalCostUsd       float64             `json:"total_cost_usd,omitempty"`
+	TotalExecutions    int                 `json:"total_executions,omitempty"`
+	Type               AgentType           `json:"type,omitempty"`
+	UpdatedAt          *time.Time          `json:"updated_at,omitempty"`
+	VectorSearchConfig *VectorSearchConfig `json:"vector_search_config,omitempty"`
+}
+
+// AgentStatus represents the status of an agent
+type AgentStatus string
+
+const (
+	AgentStatusDraft     AgentStatus = "draft"
+	AgentStatusPublished AgentStatus = "published"
+	AgentStatusDisabled  AgentStatus = "disabled"
+)
+
+// AgentType represents the type/behavior of an agent
+type AgentType string
+
+const (
+	AgentTypeQa             AgentType = "qa"
+	AgentTypeConversational AgentType = "conversational"
+	AgentTypeProducer       AgentType = "producer"
+)
+
+// AgentUpdateRequest represents a request to update an agent
+type AgentUpdateRequest struct {
+	Description  string                 `json:"description,omitempty"`
+	IsPublic     bool                   `json:"is_public,omitempty"`
+	IsTemplate   bool                   `json:"is_template,omitempty"`
+	LLMConfig    map[string]interface{} `json:"llm_config,omitempty"`
+	Name         string                 `json:"name,omitempty"`
+	Status       string                 `json:"status,omitempty"`
+	SystemPrompt string                 `json:"system_prompt,omitempty"`
+	Tags         []string               `json:"tags,omitempty"`
+	Type         AgentType              `json:"type,omitempty"`
+}
+
+// AudiModalTimeouts holds the reloadable AudiModal client timeouts
+type AudiModalTimeouts struct {
+	RequestTimeoutSeconds int `json:"request_timeout_seconds,omitempty"`
+}
+
+// AuditEvent is an immutable audit log entry. Entries form a hash chain: each
+// hash covers the entry and the hash of the entry before it, so altering or
+// removing an entry breaks the chain from that point on.
+type AuditEvent struct {
+	Action         string                 `json:"action,omitempty"`
+	ActorID        string                 `json:"actor_id,omitempty"`
+	CreatedAt      *time.Time             `json:"created_at,omitempty"`
+	Details        map[string]interface{} `json:"details,omitempty"`
+	Hash           string                 `json:"hash,omitempty"`
+	ID             string                 `json:"id,omitempty"`
+	OrganizationID string                 `json:"organization_id,omitempty"`
+	PrevHash       string                 `json:"prev_hash,omitempty"`
+	ResourceID     string                 `json:"resource_id,omitempty"`
+	ResourceType   string                 `json:"resource_type,omitempty"`
+	Sequence       int64                  `json:"sequence,omitempty"`
+	Source         string                 `json:"source,omitempty"`
+	SpaceID        string                 `json:"space_id,omitempty"`
+	TenantID       string                 `json:"tenant_id,omitempty"`
+}
+
+// AuditEventListResponse is a page of audit events, newest first
+type AuditEventListResponse struct {
+	Events  []*AuditEvent `json:"events,omitempty"`
+	HasMore bool          `json:"has_more,omitempty"`
+	Limit   int           `json:"limit,omitempty"`
+	Offset  int           `json:"offset,omitempty"`
+}
+
+// AuditVerifyResponse reports the result of checking the audit hash chain
+type AuditVerifyResponse struct {
+	// Sequence of the first entry that fails verification
+	BrokenAt     int64  `json:"broken_at,omitempty"`
+	Checked      int64  `json:"checked,omitempty"`
+	LastSequence int64  `json:"last_sequence,omitempty"`
+	Reason       string `json:"reason,omitempty"`
+	Valid        bool   `json:"valid,omitempty"`
+}
+
+// BatchOperation is one API request of a batch. Path is relative to the
+// server, e.g. /api/v1/notebooks/{id}, and may include a query string.
+type BatchOperation struct {
+	Body interface{} `json:"body,omitempty"`
+	// Echoed in the result to match it up
+	ID     string `json:"id,omitempty"`
+	Method string `json:"method"`
+	Path   string `json:"path"`
+}
+
+// BatchRequest runs several API requests in one call. Operations run in order
+// with the caller's credentials and space headers; a failed operation does not
+// stop the ones after it.
+type BatchRequest struct {
+	// Run as a job and respond 202 instead of waiting
+	Async      bool              `json:"async,omitempty"`
+	Operations []*BatchOperation `json:"operations"`
+}
+
+// BatchResponse holds the results of a batch in operation order. An async
+// batch job has the same fields in its result.
+type BatchResponse struct {
+	Failed    int            `json:"failed,omitempty"`
+	Results   []*BatchResult `json:"results,omitempty"`
+	Succeeded int            `json:"succeeded,omitempty"`
+}
+
+// BatchResult is the response to one operation of a batch
+type BatchResult struct {
+	Body   interface{} `json:"body,omitempty"`
+	ID     string      `json:"id,omitempty"`
+	Status int         `json:"status,omitempty"`
+}
+
+// BulkUploadResponse reports every file of a bulk upload, in the order the
+// files were sent
+type BulkUploadResponse struct {
+	Failed     int                 `json:"failed,omitempty"`
+	NotebookID string              `json:"notebook_id,omitempty"`
+	Results    []*BulkUploadResult `json:"results,omitempty"`
+	Succeeded  int                 `json:"succeeded,omitempty"`
+}
+
+// BulkUploadResult is the outcome of one file of a bulk upload: the created
+// document, or the error the file failed with
+type BulkUploadResult struct {
+	Document *DocumentResponse `json:"document,omitempty"`
+	Error    *JobError         `json:"error,omitempty"`
+	FileName string            `json:"file_name,omitempty"`
+	Status   string            `json:"status,omitempty"`
+}
+
+// ChunkListResponse represents a paginated list of chunks
+type ChunkListResponse struct {
+	Chunks  []*ChunkResponse `json:"chunks,omitempty"`
+	HasMore bool             `json:"has_more,omitempty"`
+	Limit   int              `json:"limit,omitempty"`
+	Offset  int              `json:"offset,omitempty"`
+	// Chunks of the page left out by redaction rules
+	Redacted int `json:"redacted,omitempty"`
+	Total    int `json:"total,omitempty"`
+}
+
+// ChunkQualityMetrics represents quality metrics for a chunk
+type ChunkQualityMetrics struct {
+	// How semantically coherent the content is
+	Coherence float64 `json:"coherence,omitempty"`
+	// How complete the content appears (0.0-1.0)
+	Completeness float64 `json:"completeness,omitempty"`
+	// Content complexity score
+	Complexity float64 `json:"complexity,omitempty"`
+	// Information density score
+	Density float64 `json:"density,omitempty"`
+	// Detected language code
+	Language string `json:"language,omitempty"`
+	// Confidence in detected language
+	LanguageConf float64 `json:"language_conf,omitempty"`
+	// Text readability score
+	Readability float64 `json:"readability,omitempty"`
+	// How unique this chunk is vs others
+	Uniqueness float64 `json:"uniqueness,omitempty"`
+}
+
+// ChunkResponse represents a chunk in API responses
+type ChunkResponse struct {
+	ChunkID            string                 `json:"chunk_id,omitempty"`
+	ChunkNumber        int                    `json:"chunk_number,omitempty"`
+	ChunkType          string                 `json:"chunk_type,omitempty"`
+	Classifications    []string               `json:"classifications,omitempty"`
+	Content            string                 `json:"content,omitempty"`
+	ContentCategory    string                 `json:"content_category,omitempty"`
+	ContentHash        string                 `json:"content_hash,omitempty"`
+	Context            map[string]string      `json:"context,omitempty"`
+	CreatedAt          *time.Time             `json:"created_at,omitempty"`
+	DLPScanResult      string                 `json:"dlp_scan_result,omitempty"`
+	DLPScanStatus      string                 `json:"dlp_scan_status,omitempty"`
+	EndPosition        int64                  `json:"end_position,omitempty"`
+	FileID             string                 `json:"file_id,omitempty"`
+	ID                 string                 `json:"id,omitempty"`
+	Language           string                 `json:"language,omitempty"`
+	LanguageConfidence float64                `json:"language_confidence,omitempty"`
+	LineNumber         int                    `json:"line_number,omitempty"`
+	Metadata           map[string]interface{} `json:"metadata,omitempty"`
+	PageNumber         int                    `json:"page_number,omitempty"`
+	PIIDetected        bool                   `json:"pii_detected,omitempty"`
+	ProcessedAt        *time.Time             `json:"processed_at,omitempty"`
+	ProcessedBy        string                 `json:"processed_by,omitempty"`
+	ProcessingTime     int64                  `json:"processing_time,omitempty"`
+	Quality            *ChunkQualityMetrics   `json:"quality,omitempty"`
+	SchemaInfo         map[string]interface{} `json:"schema_info,omitempty"`
+	SizeBytes          int64                  `json:"size_bytes,omitempty"`
+	StartPosition      int64                  `json:"start_position,omitempty"`
+	UpdatedAt          *time.Time             `json:"updated_at,omitempty"`
+}
+
+// ChunkSearchRequest represents a request to search for chunks
+type ChunkSearchRequest struct {
+	ChunkType       string  `json:"chunk_type,omitempty"`
+	ContentCategory string  `json:"content_category,omitempty"`
+	DLPScanStatus   string  `json:"dlp_scan_status,omitempty"`
+	FileID          string  `json:"file_id,omitempty"`
+	Language        string  `json:"language,omitempty"`
+	Limit           int     `json:"limit,omitempty"`
+	MinQuality      float64 `json:"min_quality,omitempty"`
+	Offset          int     `json:"offset,omitempty"`
+	PIIDetected     bool    `json:"pii_detected,omitempty"`
+	Query           string  `json:"query,omitempty"`
+}
+
+// ClassificationActions are applied to a document its policy matches
+type ClassificationActions struct {
+	AddTags          []string `json:"add_tags,omitempty"`
+	MoveToNotebookID string   `json:"move_to_notebook_id,omitempty"`
+	RestrictAccess   bool     `json:"restrict_access,omitempty"`
+}
+
+// ClassificationCondition is a test of one field of a processed document
+type ClassificationCondition struct {
+	Field    string `json:"field"`
+	Operator string `json:"operator"`
+	Value    string `json:"value,omitempty"`
+	// For in
+	Values []string `json:"values,omitempty"`
+}
+
+// ClassificationEvaluateRequest evaluates policies against documents without
+// applying them. Without a policy the space's enabled policies are evaluated;
+// with one, only that unsaved policy is.
+type ClassificationEvaluateRequest struct {
+	DocumentIDs []string `json:"document_ids,omitempty"`
+	// Its processed documents, up to 100
+	NotebookID string                             `json:"notebook_id,omitempty"`
+	Policy     *ClassificationPolicyCreateRequest `json:"policy,omitempty"`
+}
+
+// ClassificationEvaluateResponse is the outcome of a dry run
+type ClassificationEvaluateResponse struct {
+	// Documents at least one policy matches
+	Matched  int                      `json:"matched,omitempty"`
+	Outcomes []*ClassificationOutcome `json:"outcomes,omitempty"`
+}
+
+// ClassificationOutcome is what the policies do to one document
+type ClassificationOutcome struct {
+	// Combined, leaving out those with no effect
+	Actions      *ClassificationActions `json:"actions,omitempty"`
+	DocumentID   string                 `json:"document_id,omitempty"`
+	DocumentName string                 `json:"document_name,omitempty"`
+	NotebookID   string                 `json:"notebook_id,omitempty"`
+	// Of the matching policies, in the order they ran
+	PolicyIDs []string `json:"policy_ids,omitempty"`
+}
+
+// ClassificationPolicy routes a space's documents once they are processed:
+// when every condition holds, its actions are applied. Policies run in
+// priority order, lowest first; the tags and restriction of every matching
+// policy apply, and the document moves to the notebook of the first that names
+// one.
+type ClassificationPolicy struct {
+	Actions     *ClassificationActions     `json:"actions,omitempty"`
+	Conditions  []*ClassificationCondition `json:"conditions,omitempty"`
+	CreatedAt   *time.Time                 `json:"created_at,omitempty"`
+	CreatedBy   string                     `json:"created_by,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	Enabled     bool                       `json:"enabled,omitempty"`
+	ID          string                     `json:"id,omitempty"`
+	Name        string                     `json:"name,omitempty"`
+	Priority    int                        `json:"priority,omitempty"`
+	SpaceID     string                     `json:"space_id,omitempty"`
+	UpdatedAt   *time.Time                 `json:"updated_at,omitempty"`
+}
+
+// ClassificationPolicyCreateRequest creates a classification policy
+type ClassificationPolicyCreateRequest struct {
+	Actions     *ClassificationActions     `json:"actions,omitempty"`
+	Conditions  []*ClassificationCondition `json:"conditions"`
+	Description string                     `json:"description,omitempty"`
+	// Default true
+	Enabled  bool   `json:"enabled,omitempty"`
+	Name     string `json:"name"`
+	Priority int    `json:"priority,omitempty"`
+}
+
+// ClassificationPolicyListResponse lists a space's classification policies in
+// the order they run
+type ClassificationPolicyListResponse struct {
+	Policies []*ClassificationPolicy `json:"policies,omitempty"`
+}
+
+// ClassificationPolicyUpdateRequest changes the given fields of a
+// classification policy; conditions and actions are replaced whole
+type ClassificationPolicyUpdateRequest struct {
+	Actions     *ClassificationActions     `json:"actions,omitempty"`
+	Conditions  []*ClassificationCondition `json:"conditions,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	Enabled     bool                       `json:"enabled,omitempty"`
+	Name        string                     `json:"name,omitempty"`
+	Priority    int                        `json:"priority,omitempty"`
+}
+
+// ConsistencyCheckResult contains the results of a consistency check
+type ConsistencyCheckResult struct {
+	CheckedAt             *time.Time             `json:"checked_at,omitempty"`
+	Inconsistencies       []*InconsistencyReport `json:"inconsistencies,omitempty"`
+	InconsistentNotebooks int                    `json:"inconsistent_notebooks,omitempty"`
+	InconsistentUsers     int                    `json:"inconsistent_users,omitempty"`
+	// Inconsistencies reported per entity type
+	Limit             int `json:"limit,omitempty"`
+	OrphanedNotebooks int `json:"orphaned_notebooks,omitempty"`
+	OrphanedSpaces    int `json:"orphaned_spaces,omitempty"`
+	TotalNotebooks    int `json:"total_notebooks,omitempty"`
+	TotalUsers        int `json:"total_users,omitempty"`
+	// More inconsistencies exist than were reported
+	Truncated                bool `json:"truncated,omitempty"`
+	UsersWithoutOwnsRelation int  `json:"users_without_owns_relation,omitempty"`
+}
+
+// ConsistencyRepairResult reports what a consistency repair changed, or would
+// change in a dry run
+type ConsistencyRepairResult struct {
+	DryRun bool `json:"dry_run,omitempty"`
+	// Embedded IDs reset from BELONGS_TO
+	NotebookFieldsUpdated int `json:"notebook_fields_updated,omitempty"`
+	// BELONGS_TO created from the embedded space ID
+	NotebookRelationshipsCreated int        `json:"notebook_relationships_created,omitempty"`
+	RepairedAt                   *time.Time `json:"repaired_at,omitempty"`
+	// Entities whose embedded space does not exist
+	Unrepairable int `json:"unrepairable,omitempty"`
+	// OWNS created from personal_space_id
+	UserRelationshipsCreated int `json:"user_relationships_created,omitempty"`
+}
+
+// ConversationMessage represents a message in a conversation
+type ConversationMessage struct {
+	Content   string                 `json:"content"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	Role      string                 `json:"role"`
+	Timestamp *time.Time             `json:"timestamp,omitempty"`
+}
+
+// CreateExperimentRequest represents the request to create a new experiment
+type CreateExperimentRequest struct {
+	// minutes
+	EstimatedDuration int                    `json:"estimated_duration,omitempty"`
+	Hyperparameters   map[string]interface{} `json:"hyperparameters,omitempty"`
+	ModelID           string                 `json:"model_id"`
+	Name              string                 `json:"name"`
+	TestingDataset    string                 `json:"testing_dataset,omitempty"`
+	TrainingDataset   string                 `json:"training_dataset"`
+}
+
+// CreateMLModelRequest represents the request to create a new ML model
+type CreateMLModelRequest struct {
+	Description string                 `json:"description,omitempty"`
+	MediaTypes  []string               `json:"media_types"`
+	Name        string                 `json:"name"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+	Type        string                 `json:"type"`
+	Version     string                 `json:"version"`
+}
+
+// CreateStepRequest represents a step in the workflow creation request
+type CreateStepRequest struct {
+	Conditions    map[string]interface{} `json:"conditions,omitempty"`
+	Configuration map[string]interface{} `json:"configuration,omitempty"`
+	Name          string                 `json:"name"`
+	// "abort", "retry", or specific step name
+	OnFailure string `json:"on_failure,omitempty"`
+	// "next", "complete", or specific step name
+	OnSuccess  string `json:"on_success,omitempty"`
+	Order      int    `json:"order"`
+	RetryCount int    `json:"retry_count,omitempty"`
+	// seconds, default 300
+	Timeout int    `json:"timeout,omitempty"`
+	Type    string `json:"type"`
+}
+
+// CreateStreamSourceRequest represents the request to create a new stream
+// source
+type CreateStreamSourceRequest struct {
+	Configuration map[string]interface{} `json:"configuration"`
+	Name          string                 `json:"name"`
+	Provider      string                 `json:"provider"`
+	Type          string                 `json:"type"`
+}
+
+// CreateTriggerRequest represents a trigger in the workflow creation request
+type CreateTriggerRequest struct {
+	Configuration map[string]interface{} `json:"configuration,omitempty"`
+	Name          string                 `json:"name"`
+	Type          string                 `json:"type"`
+}
+
+// CreateWorkflowRequest represents the request to create a new workflow
+type CreateWorkflowRequest struct {
+	Configuration map[string]interface{}  `json:"configuration,omitempty"`
+	Description   string                  `json:"description,omitempty"`
+	Name          string                  `json:"name"`
+	Steps         []*CreateStepRequest    `json:"steps"`
+	Triggers      []*CreateTriggerRequest `json:"triggers"`
+	Type          string                  `json:"type"`
+}
+
+// DeadLetterListResponse lists failed processing retry jobs, most recently
+// failed first
+type DeadLetterListResponse struct {
+	HasMore bool                  `json:"has_more,omitempty"`
+	Jobs    []*ProcessingRetryJob `json:"jobs,omitempty"`
+	Limit   int                   `json:"limit,omitempty"`
+	Offset  int                   `json:"offset,omitempty"`
+}
+
+// DeletionTarget is the outcome of deleting a document's copies from one
+// service
+type DeletionTarget struct {
+	// Failed attempts
+	Attempts  int        `json:"attempts,omitempty"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	Error     string     `json:"error,omitempty"`
+	Name      string     `json:"name,omitempty"`
+	Status    string     `json:"status,omitempty"`
+}
+
+// DiffHunk is a run of changes with the unchanged lines around them. Starts
+// are 1-based line numbers; a side with no lines starts after the line it
+// follows, as in a unified diff.
+type DiffHunk struct {
+	BaseLines   int         `json:"base_lines,omitempty"`
+	BaseStart   int         `json:"base_start,omitempty"`
+	Lines       []*DiffLine `json:"lines,omitempty"`
+	TargetLines int         `json:"target_lines,omitempty"`
+	TargetStart int         `json:"target_start,omitempty"`
+}
+
+// DiffLine is a line of a diff hunk
+type DiffLine struct {
+	// equal, insert or delete
+	Op   string `json:"op,omitempty"`
+	Text string `json:"text,omitempty"`
+}
+
+// DocumentBase64UploadRequest represents a base64 encoded document upload
+// request
+type DocumentBase64UploadRequest struct {
+	Description string `json:"description,omitempty"`
+	// Base64 encoded file content
+	FileContent string `json:"file_content"`
+	// Original filename
+	FileName string                 `json:"file_name"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	// MIME type of the file
+	MimeType   string   `json:"mime_type"`
+	Name       string   `json:"name"`
+	NotebookID string   `json:"notebook_id"`
+	Priority   string   `json:"priority,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+}
+
+// DocumentCompareRequest asks for the difference between two texts. Base and
+// Target may be two documents or two versions of the same document.
+type DocumentCompareRequest struct {
+	Base         *DocumentCompareSide `json:"base,omitempty"`
+	ContextLines int                  `json:"context_lines,omitempty"`
+	Target       *DocumentCompareSide `json:"target,omitempty"`
+}
+
+// DocumentCompareResponse is the difference between two texts. Similarity is
+// from 0 to 1, the share of their characters the texts have in common.
+type DocumentCompareResponse struct {
+	Base       *DocumentCompareText `json:"base,omitempty"`
+	ComputedAt *time.Time           `json:"computed_at,omitempty"`
+	// Lines only the base has
+	Deletions int         `json:"deletions,omitempty"`
+	Hunks     []*DiffHunk `json:"hunks,omitempty"`
+	Identical bool        `json:"identical,omitempty"`
+	// Lines only the target has
+	Insertions int                  `json:"insertions,omitempty"`
+	Similarity float64              `json:"similarity,omitempty"`
+	Target     *DocumentCompareText `json:"target,omitempty"`
+}
+
+// DocumentCompareSide names one text of a comparison: the extracted text of a
+// document, or of one of its versions
+type DocumentCompareSide struct {
+	DocumentID string `json:"document_id"`
+	Version    int    `json:"version,omitempty"`
+}
+
+// DocumentCompareText describes one text of a comparison. Lines and Characters
+// count the text compared.
+type DocumentCompareText struct {
+	Characters int    `json:"characters,omitempty"`
+	DocumentID string `json:"document_id,omitempty"`
+	Lines      int    `json:"lines,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Redacted   bool   `json:"redacted,omitempty"`
+	Version    int    `json:"version,omitempty"`
+}
+
+// DocumentContentPage is one page of the extracted text of a document. Offsets
+// and lengths count characters (Unicode code points), so a page never splits
+// one.
+type DocumentContentPage struct {
+	DocumentID     string `json:"document_id,omitempty"`
+	HasMore        bool   `json:"has_more,omitempty"`
+	Length         int    `json:"length,omitempty"`
+	NextOffset     int    `json:"next_offset,omitempty"`
+	Offset         int    `json:"offset,omitempty"`
+	Redacted       bool   `json:"redacted,omitempty"`
+	RedactedChunks int    `json:"redacted_chunks,omitempty"`
+	Text           string `json:"text,omitempty"`
+	TotalLength    int    `json:"total_length,omitempty"`
+}
+
+// DocumentCreateRequest represents a request to create a document
+type DocumentCreateRequest struct {
+	Description string                 `json:"description,omitempty"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	Name        string                 `json:"name"`
+	NotebookID  string                 `json:"notebook_id"`
+	Priority    string                 `json:"priority,omitempty"`
+	Tags        []string               `json:"tags,omitempty"`
+}
+
+// DocumentDeletion records the removal of a document's copies from the
+// services other than Neo4j, and the outcome for each: all of them when the
+// document is deleted, or those of its previous processing when it is
+// reprocessed
+type DocumentDeletion struct {
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	DocumentID  string     `json:"document_id,omitempty"`
+	// Processed file deleted from the processing provider
+	FileID        string            `json:"file_id,omitempty"`
+	ID            string            `json:"id,omitempty"`
+	NextAttemptAt *time.Time        `json:"next_attempt_at,omitempty"`
+	Reason        string            `json:"reason,omitempty"`
+	Status        string            `json:"status,omitempty"`
+	Targets       []*DeletionTarget `json:"targets,omitempty"`
+	TenantID      string            `json:"tenant_id,omitempty"`
+	UpdatedAt     *time.Time        `json:"updated_at,omitempty"`
+}
+
+// DocumentDeletionListResponse lists document deletions, most recently updated
+// first
+type DocumentDeletionListResponse struct {
+	Deletions []*DocumentDeletion `json:"deletions,omitempty"`
+	HasMore   bool                `json:"has_more,omitempty"`
+	Limit     int                 `json:"limit,omitempty"`
+	Offset    int                 `json:"offset,omitempty"`
+}
+
+// DocumentGraphNode is a node reachable from a document. Distance is the
+// number of relationships on the shortest path to it.
+type DocumentGraphNode struct {
+	Distance int    `json:"distance,omitempty"`
+	ID       string `json:"id,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Type     string `json:"type,omitempty"`
+}
+
+// DocumentGraphResponse is one page of the nodes around a document, nearest
+// first
+type DocumentGraphResponse struct {
+	Depth      int                  `json:"depth,omitempty"`
+	DocumentID string               `json:"document_id,omitempty"`
+	HasMore    bool                 `json:"has_more,omitempty"`
+	Limit      int                  `json:"limit,omitempty"`
+	Nodes      []*DocumentGraphNode `json:"nodes,omitempty"`
+	Offset     int                  `json:"offset,omitempty"`
+}
+
+// DocumentListResponse represents a paginated list of documents
+type DocumentListResponse struct {
+	Documents []*DocumentResponse `json:"documents,omitempty"`
+	HasMore   bool                `json:"has_more,omitempty"`
+	Limit     int                 `json:"limit,omitempty"`
+	Offset    int                 `json:"offset,omitempty"`
+	Total     int                 `json:"total,omitempty"`
+}
+
+// DocumentPipeline reports how long a document spent in each stage
+type DocumentPipeline struct {
+	DocumentID string                 `json:"document_id,omitempty"`
+	Stages     []*PipelineStageTiming `json:"stages,omitempty"`
+	Status     string                 `json:"status,omitempty"`
+	TenantID   string                 `json:"tenant_id,omitempty"`
+	Timestamps *PipelineTimestamps    `json:"timestamps,omitempty"`
+	// Sum of the completed stages
+	TotalMs int64 `json:"total_ms,omitempty"`
+}
+
+// DocumentResponse represents a document response
+type DocumentResponse struct {
+	AverageChunkSize  int64   `json:"average_chunk_size,omitempty"`
+	ChunkCount        int     `json:"chunk_count,omitempty"`
+	ChunkQualityScore float64 `json:"chunk_quality_score,omitempty"`
+	ChunkingStrategy  string  `json:"chunking_strategy,omitempty"`
+	// AI confidence score (0.0-1.0)
+	ConfidenceScore  float64                `json:"confidenceScore,omitempty"`
+	CreatedAt        *time.Time             `json:"created_at,omitempty"`
+	Description      string                 `json:"description,omitempty"`
+	ExtractedText    string                 `json:"extracted_text,omitempty"`
+	ID               string                 `json:"id,omitempty"`
+	Metadata         map[string]interface{} `json:"metadata,omitempty"`
+	MimeType         string                 `json:"mime_type,omitempty"`
+	ModerationStatus ModerationStatus       `json:"moderation_status,omitempty"`
+	Name             string                 `json:"name,omitempty"`
+	Notebook         *NotebookResponse      `json:"notebook,omitempty"`
+	NotebookID       string                 `json:"notebook_id,omitempty"`
+	OcrSettings      *OCRSettings           `json:"ocr_settings,omitempty"`
+	OriginalName     string                 `json:"original_name,omitempty"`
+	Owner            *PublicUserResponse    `json:"owner,omitempty"`
+	OwnerID          string                 `json:"owner_id,omitempty"`
+	ProcessedAt      *time.Time             `json:"processed_at,omitempty"`
+	// Processing duration in milliseconds
+	ProcessingTime     int64                  `json:"processingTime,omitempty"`
+	ProcessingPriority string                 `json:"processing_priority,omitempty"`
+	ProcessingProvider string                 `json:"processing_provider,omitempty"`
+	ProcessingResult   map[string]interface{} `json:"processing_result,omitempty"`
+	Restricted         bool                   `json:"restricted,omitempty"`
+	SizeBytes          int64                  `json:"size_bytes,omitempty"`
+	Status             string                 `json:"status,omitempty"`
+	Tags               []string               `json:"tags,omitempty"`
+	Type               string                 `json:"type,omitempty"`
+	UpdatedAt          *time.Time             `json:"updated_at,omitempty"`
+}
+
+// DocumentUpdateRequest represents a request to update a document
+type DocumentUpdateRequest struct {
+	Description string                 `json:"description,omitempty"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	Name        string                 `json:"name,omitempty"`
+	Status      string                 `json:"status,omitempty"`
+	Tags        []string               `json:"tags,omitempty"`
+}
+
+// DocumentVectorSync reports how far a document's chunk embeddings have been
+// pushed into its space's DeepLake namespace
+type DocumentVectorSync struct {
+	// Failed attempts since the last success
+	Attempts      int64      `json:"attempts,omitempty"`
+	DocumentID    string     `json:"document_id,omitempty"`
+	Error         string     `json:"error,omitempty"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	Status        string     `json:"status,omitempty"`
+	SyncedAt      *time.Time `json:"synced_at,omitempty"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+	// Vectors stored by the last successful sync
+	VectorCount int64 `json:"vector_count,omitempty"`
+}
+
+// DocumentVersion is one of the files a document has had. Uploading a new file
+// for a document adds a version and makes it current; the files of earlier
+// versions are kept, so any of them can be downloaded or restored.
+type DocumentVersion struct {
+	Checksum     string     `json:"checksum,omitempty"`
+	CreatedAt    *time.Time `json:"created_at,omitempty"`
+	CreatedBy    string     `json:"created_by,omitempty"`
+	Current      bool       `json:"current,omitempty"`
+	DocumentID   string     `json:"document_id,omitempty"`
+	MimeType     string     `json:"mime_type,omitempty"`
+	OriginalName string     `json:"original_name,omitempty"`
+	SizeBytes    int64      `json:"size_bytes,omitempty"`
+	// From 1, the document's first file
+	Version int `json:"version,omitempty"`
+}
+
+// DocumentVersionListResponse lists a document's versions, newest first
+type DocumentVersionListResponse struct {
+	CurrentVersion int                `json:"current_version,omitempty"`
+	Versions       []*DocumentVersion `json:"versions,omitempty"`
+}
+
+// Entity is a person, organization, topic, location or date mentioned by a
+// tenant's documents; dates are named in ISO form. Entities detected under
+// different spellings are merged into one; Aliases keeps the other spellings.
+type Entity struct {
+	Aliases       []string   `json:"aliases,omitempty"`
+	CreatedAt     *time.Time `json:"created_at,omitempty"`
+	DocumentCount int        `json:"document_count,omitempty"`
+	ID            string     `json:"id,omitempty"`
+	Name          string     `json:"name,omitempty"`
+	TenantID      string     `json:"tenant_id,omitempty"`
+	Type          EntityType `json:"type,omitempty"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+}
+
+// EntityDocument is a document mentioning an entity
+type EntityDocument struct {
+	Document *SemanticSearchDocument `json:"document,omitempty"`
+	Mentions int                     `json:"mentions,omitempty"`
+}
+
+// EntityDocumentsResponse is one page of the documents mentioning an entity,
+// most mentions first
+type EntityDocumentsResponse struct {
+	Documents []*EntityDocument `json:"documents,omitempty"`
+	EntityID  string            `json:"entity_id,omitempty"`
+	HasMore   bool              `json:"has_more,omitempty"`
+	Limit     int               `json:"limit,omitempty"`
+	Offset    int               `json:"offset,omitempty"`
+}
+
+// EntityListResponse is one page of entities, most mentioned first. For the
+// entities of a notebook, NotebookID is set and DocumentCount counts the
+// notebook's documents.
+type EntityListResponse struct {
+	Entities   []*Entity `json:"entities,omitempty"`
+	HasMore    bool      `json:"has_more,omitempty"`
+	Limit      int       `json:"limit,omitempty"`
+	NotebookID string    `json:"notebook_id,omitempty"`
+	Offset     int       `json:"offset,omitempty"`
+}
+
+// EntityMergeRequest merges an entity into the target entity
+type EntityMergeRequest struct {
+	TargetID string `json:"target_id"`
+}
+
+// EntityType is the kind of an entity detected in documents
+type EntityType string
+
+const (
+	EntityTypePerson       EntityType = "person"
+	EntityTypeOrganization EntityType = "organization"
+	EntityTypeTopic        EntityType = "topic"
+	EntityTypeLocation     EntityType = "location"
+	EntityTypeDate         EntityType = "date"
+)
+
+// EnumerationsResponse lists the labels of enumerations in a locale
+type EnumerationsResponse struct {
+	Enumerations     map[string]map[string]string `json:"enumerations,omitempty"`
+	Locale           string                       `json:"locale,omitempty"`
+	SupportedLocales []string                     `json:"supported_locales,omitempty"`
+}
+
+// ExecuteWorkflowRequest represents the request to manually execute a workflow
+type ExecuteWorkflowRequest struct {
+	Input     map[string]interface{} `json:"input,omitempty"`
+	TriggerID string                 `json:"trigger_id,omitempty"`
+}
+
+// FeatureFlagsResponse lists the current feature flags
+type FeatureFlagsResponse struct {
+	FeatureFlags map[string]bool `json:"feature_flags,omitempty"`
+}
+
+// FeedToken lets feed readers, which cannot sign in, read one feed on behalf
+// of the user who created the token. The token itself is only returned when it
+// is created; Aether stores its hash.
+type FeedToken struct {
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	Feed       FeedType   `json:"feed,omitempty"`
+	ID         string     `json:"id,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	// The notebook of a notebook activity feed
+	ResourceID string `json:"resource_id,omitempty"`
+	SpaceID    string `json:"space_id,omitempty"`
+	UserID     string `json:"user_id,omitempty"`
+}
+
+// FeedTokenCreateResponse returns a new feed token and the URLs to subscribe
+// to, which embed it
+type FeedTokenCreateResponse struct {
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	Feed       FeedType   `json:"feed,omitempty"`
+	ID         string     `json:"id,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	// The notebook of a notebook activity feed
+	ResourceID string `json:"resource_id,omitempty"`
+	SpaceID    string `json:"space_id,omitempty"`
+	Token      string `json:"token,omitempty"`
+	// By format: rss, atom or ical
+	URLs   map[string]string `json:"urls,omitempty"`
+	UserID string            `json:"user_id,omitempty"`
+}
+
+// FeedTokenListResponse lists the caller's feed tokens, newest first
+type FeedTokenListResponse struct {
+	Tokens []*FeedToken `json:"tokens,omitempty"`
+}
+
+// FeedType identifies what a feed token grants access to
+type FeedType string
+
+const (
+	FeedTypeNotebookActivity FeedType = "notebook_activity"
+	FeedTypeScheduledJobs    FeedType = "scheduled_jobs"
+)
+
+// FileValidationSettings restrict the files uploaded to a space, on top of the
+// deployment's own restrictions
+type FileValidationSettings struct {
+	AllowedTypes []string `json:"allowed_types,omitempty"`
+	MaxSizeBytes int64    `json:"max_size_bytes,omitempty"`
+}
+
+// FrontendLogEntry represents a log entry from the frontend
+type FrontendLogEntry struct {
+	// Additional fields
+	Extra map[string]interface{} `json:"extra,omitempty"`
+	// error, warn, info, debug
+	Level string `json:"level"`
+	// Log message
+	Message string `json:"message"`
+	// Session identifier
+	SessionID string `json:"session_id,omitempty"`
+	// Error stack trace
+	StackTrace string `json:"stack_trace,omitempty"`
+	// Client timestamp (optional)
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+	// Page URL
+	URL string `json:"url,omitempty"`
+	// Browser user agent
+	UserAgent string `json:"user_agent,omitempty"`
+}
+
+// GraphEdge is a relationship between two nodes of a knowledge graph view.
+// Weight is how often a document mentions an entity, and Score how similar the
+// documents of a SIMILAR_TO edge are.
+type GraphEdge struct {
+	Score  float64 `json:"score,omitempty"`
+	Source string  `json:"source,omitempty"`
+	Target string  `json:"target,omitempty"`
+	Type   string  `json:"type,omitempty"`
+	Weight int     `json:"weight,omitempty"`
+}
+
+// GraphNode is a node of a knowledge graph view. Type is document, notebook or
+// the entity type. ExpandToken requests the view centred on the node.
+type GraphNode struct {
+	Distance    int    `json:"distance,omitempty"`
+	ExpandToken string `json:"expand_token,omitempty"`
+	ID          string `json:"id,omitempty"`
+	Label       string `json:"label,omitempty"`
+	Type        string `json:"type,omitempty"`
+}
+
+// GraphQLRequest is a GraphQL query
+type GraphQLRequest struct {
+	OperationName string                 `json:"operationName,omitempty"`
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// GraphResponse is one page of the subgraph around a root node, nearest nodes
+// first, with the edges between the root and the page's nodes. NextPageToken
+// requests the next page.
+type GraphResponse struct {
+	Depth         int          `json:"depth,omitempty"`
+	Edges         []*GraphEdge `json:"edges,omitempty"`
+	NextPageToken string       `json:"next_page_token,omitempty"`
+	Nodes         []*GraphNode `json:"nodes,omitempty"`
+	Root          *GraphNode   `json:"root,omitempty"`
+}
+
+// HealthResponse represents the health check response
+type HealthResponse struct {
+	Services  map[string]interface{} `json:"services,omitempty"`
+	Status    string                 `json:"status,omitempty"`
+	Timestamp *time.Time             `json:"timestamp,omitempty"`
+	Version   string                 `json:"version,omitempty"`
+}
+
+// HybridSearchRequest represents a hybrid vector search request
+type HybridSearchRequest struct {
+	FusionMethod string         `json:"fusion_method,omitempty"`
+	Options      *SearchOptions `json:"options,omitempty"`
+	QueryText    string         `json:"query_text,omitempty"`
+	QueryVector  []float64      `json:"query_vector,omitempty"`
+	TextWeight   float64        `json:"text_weight,omitempty"`
+	VectorWeight float64        `json:"vector_weight,omitempty"`
+}
+
+// InboundEmailAddress is the address mail is sent to for adding its body and
+// attachments to a notebook as documents
+type InboundEmailAddress struct {
+	Address        string     `json:"address,omitempty"`
+	AllowedSenders []string   `json:"allowed_senders,omitempty"`
+	CreatedAt      *time.Time `json:"created_at,omitempty"`
+	NotebookID     string     `json:"notebook_id,omitempty"`
+}
+
+// InboundEmailDelivery is what a received email added to one of the notebooks
+// it was addressed to
+type InboundEmailDelivery struct {
+	DocumentIDs []string `json:"document_ids,omitempty"`
+	NotebookID  string   `json:"notebook_id,omitempty"`
+	// Why nothing was added to the notebook
+	Reason  string              `json:"reason,omitempty"`
+	Skipped []*InboundEmailSkip `json:"skipped,omitempty"`
+}
+
+// InboundEmailRequest creates the inbound email address of a notebook
+type InboundEmailRequest struct {
+	AllowedSenders []string `json:"allowed_senders,omitempty"`
+}
+
+// InboundEmailResult is the outcome of a received email
+type InboundEmailResult struct {
+	Deliveries []*InboundEmailDelivery `json:"deliveries,omitempty"`
+	MessageID  string                  `json:"message_id,omitempty"`
+	// Why the email was rejected
+	Reason string `json:"reason,omitempty"`
+	Status string `json:"status,omitempty"`
+}
+
+// InboundEmailSkip is a part of an email that was not added
+type InboundEmailSkip struct {
+	Name   string `json:"name,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// InconsistencyReport represents a detected inconsistency between embedded
+// fields and relationships
+type InconsistencyReport struct {
+	// Space ID from embedded field
+	EmbeddedSpaceID string `json:"embedded_space_id,omitempty"`
+	// Tenant ID from embedded field
+	EmbeddedTenantID string `json:"embedded_tenant_id,omitempty"`
+	// ID of the entity
+	EntityID string `json:"entity_id,omitempty"`
+	// "notebook" or "user"
+	EntityType string `json:"entity_type,omitempty"`
+	// Description of the inconsistency
+	Issue string `json:"issue,omitempty"`
+	// Space ID from relationship
+	RelationshipSpace string `json:"relationship_space,omitempty"`
+	// Tenant ID from relationship Space
+	RelationshipTenant string `json:"relationship_tenant,omitempty"`
+}
+
+// Job is a long-running operation. Endpoints that start one respond 202 with
+// the job and a Location header; clients poll GET /api/v1/jobs/{id} until it
+// has finished. Finished jobs are kept for the configured retention.
+type Job struct {
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	CreatedBy   string     `json:"created_by,omitempty"`
+	// Set when the job failed
+	Error    *JobError    `json:"error,omitempty"`
+	ID       string       `json:"id,omitempty"`
+	Progress *JobProgress `json:"progress,omitempty"`
+	// Set when the job succeeded; its fields depend on the type
+	Result    map[string]interface{} `json:"result,omitempty"`
+	StartedAt *time.Time             `json:"started_at,omitempty"`
+	Status    JobStatus              `json:"status,omitempty"`
+	TenantID  string                 `json:"tenant_id,omitempty"`
+	Type      JobType                `json:"type,omitempty"`
+}
+
+// JobError describes why a job failed, like an error response
+type JobError struct {
+	Code      string `json:"code,omitempty"`
+	ErrorCode string `json:"error_code,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// JobListResponse lists the caller's jobs, newest first
+type JobListResponse struct {
+	HasMore bool   `json:"has_more,omitempty"`
+	Jobs    []*Job `json:"jobs,omitempty"`
+	Limit   int    `json:"limit,omitempty"`
+	Offset  int    `json:"offset,omitempty"`
+}
+
+// JobProgress counts the items a job has worked through. Total is 0 until the
+// job knows how many items it has.
+type JobProgress struct {
+	Completed int `json:"completed,omitempty"`
+	Failed    int `json:"failed,omitempty"`
+	Total     int `json:"total,omitempty"`
+}
+
+// JobRun records one execution of a scheduled job
+type JobRun struct {
+	DurationMs int64      `json:"duration_ms,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	ID         string     `json:"id,omitempty"`
+	InstanceID string     `json:"instance_id,omitempty"`
+	JobName    string     `json:"job_name,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	// running, succeeded, failed
+	Status string `json:"status,omitempty"`
+}
+
+// JobRunListResponse lists recent runs of a job, newest first
+type JobRunListResponse struct {
+	Runs []*JobRun `json:"runs,omitempty"`
+}
+
+// JobStatus is the state of an async job
+type JobStatus string
+
+const (
+	JobStatusQueued    JobStatus = "queued"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusSucceeded JobStatus = "succeeded"
+	JobStatusFailed    JobStatus = "failed"
+)
+
+// JobType identifies the work an async job does
+type JobType string
+
+const (
+	JobTypeBatch              JobType = "batch"
+	JobTypeNotebookExport     JobType = "notebook_export"
+	JobTypeOrganizationDelete JobType = "organization_delete"
+	JobTypeTenantExport       JobType = "tenant_export"
+	JobTypeImport             JobType = "import"
+	JobTypeLegalHoldExport    JobType = "legal_hold_export"
+	JobTypeDocumentProcessing JobType = "document_processing"
+)
+
+// LegalHold preserves the content of users and notebooks of a tenant for
+// e-discovery. While it is active, the documents of its notebooks and the
+// documents and notebooks its users own cannot be edited or deleted.
+type LegalHold struct {
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	CreatedBy   string     `json:"created_by,omitempty"`
+	Description string     `json:"description,omitempty"`
+	ID          string     `json:"id,omitempty"`
+	// Case or matter reference
+	Matter string `json:"matter,omitempty"`
+	Name   string `json:"name,omitempty"`
+	// Held notebooks
+	NotebookIDs   []string        `json:"notebook_ids,omitempty"`
+	ReleaseReason string          `json:"release_reason,omitempty"`
+	ReleasedAt    *time.Time      `json:"released_at,omitempty"`
+	ReleasedBy    string          `json:"released_by,omitempty"`
+	Status        LegalHoldStatus `json:"status,omitempty"`
+	TenantID      string          `json:"tenant_id,omitempty"`
+	// Custodians
+	UserIDs []string `json:"user_ids,omitempty"`
+}
+
+// LegalHoldCollection is one page of the held documents matching some
+// criteria, oldest first
+type LegalHoldCollection struct {
+	Criteria  *LegalHoldCriteria   `json:"criteria,omitempty"`
+	Documents []*LegalHoldDocument `json:"documents,omitempty"`
+	HasMore   bool                 `json:"has_more,omitempty"`
+	HoldID    string               `json:"hold_id,omitempty"`
+	Limit     int                  `json:"limit,omitempty"`
+	Offset    int                  `json:"offset,omitempty"`
+	Total     int                  `json:"total,omitempty"`
+}
+
+// LegalHoldCreateRequest places users and notebooks of a tenant under a legal
+// hold. At least one user or notebook is required.
+type LegalHoldCreateRequest struct {
+	Description string   `json:"description,omitempty"`
+	Matter      string   `json:"matter,omitempty"`
+	Name        string   `json:"name"`
+	NotebookIDs []string `json:"notebook_ids"`
+	TenantID    string   `json:"tenant_id"`
+	UserIDs     []string `json:"user_ids"`
+}
+
+// LegalHoldCriteria selects the held documents a collection returns. Documents
+// match any of the keywords, as whole words in their name, description, tags
+// or extracted text, and were uploaded in the time range, which includes From
+// and excludes To. Empty criteria match every held document.
+type LegalHoldCriteria struct {
+	From     *time.Time `json:"from,omitempty"`
+	Keywords []string   `json:"keywords,omitempty"`
+	To       *time.Time `json:"to,omitempty"`
+}
+
+// LegalHoldDocument is a held document a collection found
+type LegalHoldDocument struct {
+	// Recorded when the file was uploaded
+	Checksum        string     `json:"checksum,omitempty"`
+	CreatedAt       *time.Time `json:"created_at,omitempty"`
+	DocumentID      string     `json:"document_id,omitempty"`
+	MatchedKeywords []string   `json:"matched_keywords,omitempty"`
+	MimeType        string     `json:"mime_type,omitempty"`
+	Name            string     `json:"name,omitempty"`
+	NotebookID      string     `json:"notebook_id,omitempty"`
+	OwnerID         string     `json:"owner_id,omitempty"`
+	SizeBytes       int64      `json:"size_bytes,omitempty"`
+	// deleted for documents in the trash
+	Status    string     `json:"status,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// LegalHoldListResponse is one page of legal holds, newest first
+type LegalHoldListResponse struct {
+	HasMore bool         `json:"has_more,omitempty"`
+	Holds   []*LegalHold `json:"holds,omitempty"`
+	Limit   int          `json:"limit,omitempty"`
+	Offset  int          `json:"offset,omitempty"`
+}
+
+// LegalHoldReleaseRequest releases a legal hold, saying why
+type LegalHoldReleaseRequest struct {
+	Reason string `json:"reason"`
+}
+
+// LegalHoldStatus is whether a legal hold preserves its content
+type LegalHoldStatus string
+
+const (
+	LegalHoldStatusActive   LegalHoldStatus = "active"
+	LegalHoldStatusReleased LegalHoldStatus = "released"
+)
+
+// LiveEvent represents a real-time event from a stream
+type LiveEvent struct {
+	// 0.0 to 1.0
+	AuditScore float64 `json:"audit_score,omitempty"`
+	// 0.0 to 1.0
+	Confidence float64 `json:"confidence,omitempty"`
+	Content    string  `json:"content,omitempty"`
+	// Original event time
+	EventTimestamp *time.Time `json:"event_timestamp,omitempty"`
+	// mention, multimodal, audio, document, video, image
+	EventType     string                 `json:"event_type,omitempty"`
+	ExtractedData map[string]interface{} `json:"extracted_data,omitempty"`
+	HasAuditTrail bool                   `json:"has_audit_trail,omitempty"`
+	ID            string                 `json:"id,omitempty"`
+	// text, image, video, audio, document
+	MediaType      string                 `json:"media_type,omitempty"`
+	MediaURL       string                 `json:"media_url,omitempty"`
+	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+	OrganizationID string                 `json:"organization_id,omitempty"`
+	ProcessedAt    *time.Time             `json:"processed_at,omitempty"`
+	// milliseconds
+	ProcessingTime float64 `json:"processing_time,omitempty"`
+	// positive, neutral, negative
+	Sentiment string `json:"sentiment,omitempty"`
+	// -1.0 to 1.0
+	SentimentScore float64 `json:"sentiment_score,omitempty"`
+	StreamSourceID string  `json:"stream_source_id,omitempty"`
+	TenantID       string  `json:"tenant_id,omitempty"`
+}
+
+// LogBatchRequest represents a batch of log entries from the frontend
+type LogBatchRequest struct {
+	Logs []*FrontendLogEntry `json:"logs"`
+}
+
+// MLExperiment represents a machine learning experiment
+type MLExperiment struct {
+	CreatedAt           *time.Time             `json:"created_at,omitempty"`
+	CreatedBy           string                 `json:"created_by,omitempty"`
+	EndDate             *time.Time             `json:"end_date,omitempty"`
+	EstimatedCompletion *time.Time             `json:"estimated_completion,omitempty"`
+	Hyperparameters     map[string]interface{} `json:"hyperparameters,omitempty"`
+	ID                  string                 `json:"id,omitempty"`
+	// accuracy, precision, recall, f1_score
+	Metrics        map[string]float64 `json:"metrics,omitempty"`
+	ModelID        string             `json:"model_id,omitempty"`
+	Name           string             `json:"name,omitempty"`
+	OrganizationID string             `json:"organization_id,omitempty"`
+	// 0-100%
+	Progress  float64                `json:"progress,omitempty"`
+	Results   map[string]interface{} `json:"results,omitempty"`
+	StartDate *time.Time             `json:"start_date,omitempty"`
+	// running, completed, failed, paused
+	Status          string     `json:"status,omitempty"`
+	TenantID        string     `json:"tenant_id,omitempty"`
+	TestingDataset  string     `json:"testing_dataset,omitempty"`
+	TrainingDataset string     `json:"training_dataset,omitempty"`
+	UpdatedAt       *time.Time `json:"updated_at,omitempty"`
+}
+
+// MLModel represents a machine learning model in the system
+type MLModel struct {
+	Accuracy    float64    `json:"accuracy,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	CreatedBy   string     `json:"created_by,omitempty"`
+	Description string     `json:"description,omitempty"`
+	ID          string     `json:"id,omitempty"`
+	// document, image, audio, video
+	MediaTypes     []string               `json:"media_types,omitempty"`
+	Name           string                 `json:"name,omitempty"`
+	OrganizationID string                 `json:"organization_id,omitempty"`
+	Parameters     map[string]interface{} `json:"parameters,omitempty"`
+	Predictions    int64                  `json:"predictions,omitempty"`
+	// deployed, training, testing, inactive
+	Status   string `json:"status,omitempty"`
+	TenantID string `json:"tenant_id,omitempty"`
+	// e.g., "45K documents"
+	TrainingData string `json:"training_data,omitempty"`
+	// Classification, NER, Sentiment, ComputerVision
+	Type      string     `json:"type,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	Version   string     `json:"version,omitempty"`
+}
+
+// MLPerformanceMetrics represents system-wide ML performance analytics
+type MLPerformanceMetrics struct {
+	ActiveModels    int     `json:"active_models,omitempty"`
+	AverageAccuracy float64 `json:"average_accuracy,omitempty"`
+	// milliseconds
+	AverageProcessingTime float64            `json:"average_processing_time,omitempty"`
+	CreatedAt             *time.Time         `json:"created_at,omitempty"`
+	Date                  *time.Time         `json:"date,omitempty"`
+	DocumentsProcessed    int64              `json:"documents_processed,omitempty"`
+	ErrorRate             float64            `json:"error_rate,omitempty"`
+	ID                    string             `json:"id,omitempty"`
+	ModelPerformance      map[string]float64 `json:"model_performance,omitempty"`
+	OrganizationID        string             `json:"organization_id,omitempty"`
+	// daily, weekly, monthly
+	Period             string `json:"period,omitempty"`
+	RunningExperiments int    `json:"running_experiments,omitempty"`
+	TenantID           string `json:"tenant_id,omitempty"`
+	TotalPredictions   int64  `json:"total_predictions,omitempty"`
+}
+
+// MaintenanceRequest starts a maintenance window, or changes the banner of the
+// one in progress
+type MaintenanceRequest struct {
+	ExpectedEndAt *time.Time `json:"expected_end_at,omitempty"`
+	Message       string     `json:"message"`
+}
+
+// MaintenanceStatus lists the maintenance windows in progress
+type MaintenanceStatus struct {
+	Active  bool                 `json:"active,omitempty"`
+	Windows []*MaintenanceWindow `json:"windows,omitempty"`
+}
+
+// MaintenanceWindow is a maintenance in progress, during which writes are
+// rejected system-wide or for one tenant
+type MaintenanceWindow struct {
+	ExpectedEndAt *time.Time `json:"expected_end_at,omitempty"`
+	Message       string     `json:"message,omitempty"`
+	Scope         string     `json:"scope,omitempty"`
+	StartedAt     *time.Time `json:"started_at,omitempty"`
+	StartedBy     string     `json:"started_by,omitempty"`
+	// Set when the scope is tenant
+	TenantID string `json:"tenant_id,omitempty"`
+}
+
+// ModeratedDocument is a document of a public notebook as moderation sees it
+type ModeratedDocument struct {
+	Categories  []string         `json:"categories,omitempty"`
+	DocumentID  string           `json:"document_id,omitempty"`
+	ModeratedAt *time.Time       `json:"moderated_at,omitempty"`
+	Name        string           `json:"name,omitempty"`
+	NotebookID  string           `json:"notebook_id,omitempty"`
+	OwnerID     string           `json:"owner_id,omitempty"`
+	Provider    string           `json:"provider,omitempty"`
+	Reason      string           `json:"reason,omitempty"`
+	ReviewNote  string           `json:"review_note,omitempty"`
+	ReviewedAt  *time.Time       `json:"reviewed_at,omitempty"`
+	ReviewedBy  string           `json:"reviewed_by,omitempty"`
+	Status      ModerationStatus `json:"status,omitempty"`
+}
+
+// ModeratedDocumentListResponse is one page of moderated documents, most
+// recently moderated first
+type ModeratedDocumentListResponse struct {
+	Documents []*ModeratedDocument `json:"documents,omitempty"`
+	HasMore   bool                 `json:"has_more,omitempty"`
+	Limit     int                  `json:"limit,omitempty"`
+	Offset    int                  `json:"offset,omitempty"`
+}
+
+// ModerationReviewRequest is a reviewer's decision on a moderated document,
+// overriding the provider's
+type ModerationReviewRequest struct {
+	Decision ModerationStatus `json:"decision"`
+	Note     string           `json:"note,omitempty"`
+}
+
+// ModerationStatus is where a document of a public notebook stands with
+// content moderation
+type ModerationStatus string
+
+const (
+	ModerationStatusPending  ModerationStatus = "pending"
+	ModerationStatusApproved ModerationStatus = "approved"
+	ModerationStatusFlagged  ModerationStatus = "flagged"
+	ModerationStatusBlocked  ModerationStatus = "blocked"
+)
+
+// NotebookAskRequest asks a question about a notebook's documents
+type NotebookAskRequest struct {
+	Question string `json:"question"`
+}
+
+// NotebookAskResponse is a one-off answer to a question about a notebook.
+// Retrieval is "vector" or, when vector search is unavailable, "keyword".
+type NotebookAskResponse struct {
+	Answer     string               `json:"answer,omitempty"`
+	Model      string               `json:"model,omitempty"`
+	NotebookID string               `json:"notebook_id,omitempty"`
+	Question   string               `json:"question,omitempty"`
+	Retrieval  string               `json:"retrieval,omitempty"`
+	Sources    []*NotebookAskSource `json:"sources,omitempty"`
+	TokensUsed int                  `json:"tokens_used,omitempty"`
+}
+
+// NotebookAskSource is a chunk an answer was drawn from. Index is the number
+// the answer cites it by, as [1], [2], ... ChunkNumber and Page locate the
+// chunk in its document when they are known.
+type NotebookAskSource struct {
+	ChunkID      string  `json:"chunk_id,omitempty"`
+	ChunkNumber  int     `json:"chunk_number,omitempty"`
+	DocumentID   string  `json:"document_id,omitempty"`
+	DocumentName string  `json:"document_name,omitempty"`
+	Excerpt      string  `json:"excerpt,omitempty"`
+	Index        int     `json:"index,omitempty"`
+	Page         int     `json:"page,omitempty"`
+	Score        float64 `json:"score,omitempty"`
+}
+
+// NotebookCreateRequest represents a request to create a notebook
+type NotebookCreateRequest struct {
+	ComplianceSettings map[string]interface{} `json:"compliance_settings,omitempty"`
+	Description        string                 `json:"description,omitempty"`
+	Name               string                 `json:"name"`
+	ParentID           string                 `json:"parentId,omitempty"`
+	Tags               []string               `json:"tags,omitempty"`
+	TeamID             string                 `json:"team_id,omitempty"`
+	Visibility         string                 `json:"visibility"`
+}
+
+// NotebookDigestSubscribeRequest subscribes the current user to a digest of a
+// notebook's changes, or changes how often it is sent
+type NotebookDigestSubscribeRequest struct {
+	Cadence string `json:"cadence"`
+}
+
+// NotebookDigestSubscription sends a user a summary of the documents added to
+// and changed in a notebook at the end of every period
+type NotebookDigestSubscription struct {
+	Cadence      string     `json:"cadence,omitempty"`
+	CreatedAt    *time.Time `json:"created_at,omitempty"`
+	ID           string     `json:"id,omitempty"`
+	LastError    string     `json:"last_error,omitempty"`
+	LastSentAt   *time.Time `json:"last_sent_at,omitempty"`
+	NextRunAt    *time.Time `json:"next_run_at,omitempty"`
+	NotebookID   string     `json:"notebook_id,omitempty"`
+	NotebookName string     `json:"notebook_name,omitempty"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+	UserID       string     `json:"user_id,omitempty"`
+}
+
+// NotebookDigestSubscriptionList is the digest subscriptions of a user
+type NotebookDigestSubscriptionList struct {
+	Subscriptions []*NotebookDigestSubscription `json:"subscriptions,omitempty"`
+}
+
+// NotebookListResponse represents a paginated list of notebooks
+type NotebookListResponse struct {
+	HasMore   bool                `json:"hasMore,omitempty"`
+	Limit     int                 `json:"limit,omitempty"`
+	Notebooks []*NotebookResponse `json:"notebooks,omitempty"`
+	Offset    int                 `json:"offset,omitempty"`
+	Total     int                 `json:"total,omitempty"`
+}
+
+// NotebookListResponseV2 is the v2 notebook list, which uses the data and
+// pagination envelope shared by v2 list endpoints
+type NotebookListResponseV2 struct {
+	Data       []*NotebookResponse `json:"data,omitempty"`
+	Pagination *Pagination         `json:"pagination,omitempty"`
+}
+
+// NotebookResponse represents a notebook response
+type NotebookResponse struct {
+	Children           []*NotebookResponse    `json:"children,omitempty"`
+	ComplianceSettings map[string]interface{} `json:"complianceSettings,omitempty"`
+	CreatedAt          *time.Time             `json:"createdAt,omitempty"`
+	Description        string                 `json:"description,omitempty"`
+	DocumentCount      int                    `json:"documentCount,omitempty"`
+	ID                 string                 `json:"id,omitempty"`
+	Name               string                 `json:"name,omitempty"`
+	Owner              *PublicUserResponse    `json:"owner,omitempty"`
+	OwnerID            string                 `json:"ownerId,omitempty"`
+	Parent             *NotebookResponse      `json:"parent,omitempty"`
+	ParentID           string                 `json:"parentId,omitempty"`
+	Status             string                 `json:"status,omitempty"`
+	Tags               []string               `json:"tags,omitempty"`
+	TotalSizeBytes     int64                  `json:"totalSizeBytes,omitempty"`
+	UpdatedAt          *time.Time             `json:"updatedAt,omitempty"`
+	Visibility         string                 `json:"visibility,omitempty"`
+}
+
+// NotebookShare is a user or team a notebook is shared with
+type NotebookShare struct {
+	Name       string `json:"name,omitempty"`
+	NotebookID string `json:"notebook_id,omitempty"`
+	Role       string `json:"role,omitempty"`
+	// ID of the user or team
+	ShareID string `json:"share_id,omitempty"`
+	// user or team
+	ShareType string     `json:"share_type,omitempty"`
+	SharedAt  *time.Time `json:"shared_at,omitempty"`
+	SharedBy  string     `json:"shared_by,omitempty"`
+}
+
+// NotebookShareListResponse lists the shares of a notebook
+type NotebookShareListResponse struct {
+	Shares []*NotebookShare `json:"shares,omitempty"`
+}
+
+// NotebookShareRequest shares a notebook with users and teams, all with the
+// same role. Sharing again with the same user or team changes its role.
+type NotebookShareRequest struct {
+	Role    string   `json:"role"`
+	TeamIDs []string `json:"team_ids,omitempty"`
+	UserIDs []string `json:"user_ids,omitempty"`
+}
+
+// NotebookUpdateRequest represents a request to update a notebook
+type NotebookUpdateRequest struct {
+	ComplianceSettings map[string]interface{} `json:"compliance_settings,omitempty"`
+	Description        string                 `json:"description,omitempty"`
+	Name               string                 `json:"name,omitempty"`
+	Status             string                 `json:"status,omitempty"`
+	Tags               []string               `json:"tags,omitempty"`
+	Visibility         string                 `json:"visibility,omitempty"`
+}
+
+// OCRSettings control AudiModal's OCR fallback, which extracts the text of
+// images and of PDFs without a text layer. Languages are Tesseract language
+// pack codes and must be among the packs AudiModal has installed.
+type OCRSettings struct {
+	Dpi       int      `json:"dpi"`
+	ForceOcr  bool     `json:"force_ocr,omitempty"`
+	Languages []string `json:"languages"`
+}
+
+// OnboardingResult represents the result of user onboarding
+type OnboardingResult struct {
+	CompletedAt       *time.Time      `json:"completed_at,omitempty"`
+	DefaultAgentID    string          `json:"default_agent_id,omitempty"`
+	DefaultNotebookID string          `json:"default_notebook_id,omitempty"`
+	DurationMs        int64           `json:"duration_ms,omitempty"`
+	ErrorMessage      string          `json:"error_message,omitempty"`
+	SampleDocsCount   int             `json:"sample_docs_count,omitempty"`
+	StartedAt         *time.Time      `json:"started_at,omitempty"`
+	Steps             map[string]bool `json:"steps,omitempty"`
+	Success           bool            `json:"success,omitempty"`
+	UserID            string          `json:"user_id,omitempty"`
+}
+
+// OrganizationCreateRequest represents a request to create an organization
+type OrganizationCreateRequest struct {
+	AvatarURL    string                 `json:"avatar_url,omitempty"`
+	Billing      map[string]interface{} `json:"billing,omitempty"`
+	BillingEmail string                 `json:"billingEmail,omitempty"`
+	Description  string                 `json:"description,omitempty"`
+	Location     string                 `json:"location,omitempty"`
+	Name         string                 `json:"name"`
+	Settings     map[string]interface{} `json:"settings,omitempty"`
+	Slug         string                 `json:"slug,omitempty"`
+	Visibility   string                 `json:"visibility"`
+	Website      string                 `json:"website,omitempty"`
+}
+
+// OrganizationInviteRequest represents a request to invite an organization
+// member
+type OrganizationInviteRequest struct {
+	Department string `json:"department,omitempty"`
+	Email      string `json:"email"`
+	// A built-in role other than owner, or a custom role of the organization
+	Role  string `json:"role"`
+	Title string `json:"title,omitempty"`
+}
+
+// OrganizationMemberListResponse represents a paginated list of organization
+// members
+type OrganizationMemberListResponse struct {
+	HasMore bool                          `json:"hasMore,omitempty"`
+	Limit   int                           `json:"limit,omitempty"`
+	Members []*OrganizationMemberResponse `json:"members,omitempty"`
+	Offset  int                           `json:"offset,omitempty"`
+	Total   int                           `json:"total,omitempty"`
+}
+
+// OrganizationMemberResponse represents an organization member response with
+// camelCase fields
+type OrganizationMemberResponse struct {
+	Department string     `json:"department,omitempty"`
+	Email      string     `json:"email,omitempty"`
+	InvitedBy  string     `json:"invitedBy,omitempty"`
+	JoinedAt   *time.Time `json:"joinedAt,omitempty"`
+	Name       string     `json:"name,omitempty"`
+	Role       string     `json:"role,omitempty"`
+	Teams      []string   `json:"teams,omitempty"`
+	Title      string     `json:"title,omitempty"`
+	UserID     string     `json:"userId,omitempty"`
+}
+
+// OrganizationMemberRoleUpdateRequest represents a request to update an
+// organization member's role
+type OrganizationMemberRoleUpdateRequest struct {
+	Department string `json:"department,omitempty"`
+	// A built-in role other than owner, or a custom role of the organization
+	Role  string `json:"role"`
+	Title string `json:"title,omitempty"`
+}
+
+// OrganizationResponse represents an organization response with camelCase
+// fields
+type OrganizationResponse struct {
+	// camelCase for frontend
+	AvatarURL string                 `json:"avatarUrl,omitempty"`
+	Billing   map[string]interface{} `json:"billing,omitempty"`
+	CreatedAt *time.Time             `json:"createdAt,omitempty"`
+	// camelCase for frontend
+	CreatedBy   string `json:"createdBy,omitempty"`
+	Description string `json:"description,omitempty"`
+	ID          string `json:"id,omitempty"`
+	Location    string `json:"location,omitempty"`
+	MemberCount int    `json:"memberCount,omitempty"`
+	Name        string `json:"name,omitempty"`
+	// Standardized on notebook naming
+	NotebookCount int                    `json:"notebookCount,omitempty"`
+	Settings      map[string]interface{} `json:"settings,omitempty"`
+	Slug          string                 `json:"slug,omitempty"`
+	TeamCount     int                    `json:"teamCount,omitempty"`
+	// Include tenant ID for frontend
+	TenantID   string     `json:"tenantId,omitempty"`
+	UpdatedAt  *time.Time `json:"updatedAt,omitempty"`
+	UserRole   string     `json:"userRole,omitempty"`
+	Visibility string     `json:"visibility,omitempty"`
+	Website    string     `json:"website,omitempty"`
+}
+
+// OrganizationUpdateRequest represents a request to update an organization
+type OrganizationUpdateRequest struct {
+	AvatarURL   string                 `json:"avatar_url,omitempty"`
+	Billing     map[string]interface{} `json:"billing,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Location    string                 `json:"location,omitempty"`
+	Name        string                 `json:"name,omitempty"`
+	Settings    map[string]interface{} `json:"settings,omitempty"`
+	Slug        string                 `json:"slug,omitempty"`
+	Visibility  string                 `json:"visibility,omitempty"`
+	Website     string                 `json:"website,omitempty"`
+}
+
+// OrphanCleanupResult reports what an orphan cleanup deleted, or would delete
+// in a dry run
+type OrphanCleanupResult struct {
+	CleanedAt *time.Time `json:"cleaned_at,omitempty"`
+	DryRun    bool       `json:"dry_run,omitempty"`
+	// Retry jobs of deleted documents
+	RetryJobsDeleted int `json:"retry_jobs_deleted,omitempty"`
+	// Empty personal spaces nobody owns
+	SpacesDeleted int `json:"spaces_deleted,omitempty"`
+}
+
+// Pagination describes the page of a v2 list response
+type Pagination struct {
+	HasMore bool `json:"has_more,omitempty"`
+	Limit   int  `json:"limit,omitempty"`
+	Offset  int  `json:"offset,omitempty"`
+	Total   int  `json:"total,omitempty"`
+}
+
+// PipelineStageSummary summarizes one stage's durations, in milliseconds
+type PipelineStageSummary struct {
+	// Documents that completed the stage
+	Documents int64   `json:"documents,omitempty"`
+	MaxMs     float64 `json:"max_ms,omitempty"`
+	P50Ms     float64 `json:"p50_ms,omitempty"`
+	P95Ms     float64 `json:"p95_ms,omitempty"`
+	P99Ms     float64 `json:"p99_ms,omitempty"`
+	Stage     string  `json:"stage,omitempty"`
+}
+
+// PipelineStageTiming is the duration of one stage; stages still in progress
+// or skipped have no duration
+type PipelineStageTiming struct {
+	DurationMs int64  `json:"duration_ms,omitempty"`
+	Stage      string `json:"stage,omitempty"`
+}
+
+// PipelineSummaryResponse summarizes pipeline timings of the documents
+// uploaded since a point in time, with the slowest documents for drilling in
+type PipelineSummaryResponse struct {
+	Since   *time.Time               `json:"since,omitempty"`
+	Slowest []*DocumentPipeline      `json:"slowest,omitempty"`
+	Tenants []*TenantPipelineSummary `json:"tenants,omitempty"`
+}
+
+// PipelineTimestamps records when a document reached each pipeline milestone.
+// Milestones not reached yet are nil.
+type PipelineTimestamps struct {
+	CreatedAt       *time.Time `json:"created_at,omitempty"`
+	IndexedAt       *time.Time `json:"indexed_at,omitempty"`
+	ProcessedAt     *time.Time `json:"processed_at,omitempty"`
+	StoredAt        *time.Time `json:"stored_at,omitempty"`
+	SubmitStartedAt *time.Time `json:"submit_started_at,omitempty"`
+	SubmittedAt     *time.Time `json:"submitted_at,omitempty"`
+}
+
+// ProcessingCallbackResult is what a processing callback changed
+type ProcessingCallbackResult struct {
+	Applied    bool   `json:"applied,omitempty"`
+	DocumentID string `json:"document_id,omitempty"`
+	EventID    string `json:"event_id,omitempty"`
+	// Document status recorded
+	Status string `json:"status,omitempty"`
+}
+
+// ProcessingCompleteData contains the processing result data
+type ProcessingCompleteData struct {
+	ChunksCreated      int `json:"chunks_created,omitempty"`
+	DLPViolationsFound int `json:"dlp_violations_found,omitempty"`
+	// Neo4j Document.id from Aether-BE for cross-service consistency
+	DocumentID        string `json:"document_id,omitempty"`
+	EmbeddingsCreated int    `json:"embeddings_created,omitempty"`
+	// People, organizations and other entities detected in the text
+	Entities []*ProcessingEntity `json:"entities,omitempty"`
+	// Why processing failed
+	Error string `json:"error,omitempty"`
+	// AudiModal file UUID
+	FileID          string   `json:"file_id,omitempty"`
+	FinalDataClass  string   `json:"final_data_class,omitempty"`
+	StorageLocation string   `json:"storage_location,omitempty"`
+	Success         bool     `json:"success,omitempty"`
+	Topics          []string `json:"topics,omitempty"`
+	// Duration in nanoseconds
+	TotalProcessingTime int64  `json:"total_processing_time,omitempty"`
+	URL                 string `json:"url,omitempty"`
+}
+
+// ProcessingCompleteEvent represents the event from audimodal when processing
+// completes
+type ProcessingCompleteEvent struct {
+	Data      *ProcessingCompleteData `json:"data,omitempty"`
+	ID        string                  `json:"id,omitempty"`
+	Source    string                  `json:"source,omitempty"`
+	TenantID  string                  `json:"tenant_id,omitempty"`
+	Timestamp *time.Time              `json:"timestamp,omitempty"`
+	Type      string                  `json:"type,omitempty"`
+	Version   string                  `json:"version,omitempty"`
+}
+
+// ProcessingEntity is an entity detected in a processed file
+type ProcessingEntity struct {
+	Count int    `json:"count,omitempty"`
+	Text  string `json:"text,omitempty"`
+	Type  string `json:"type,omitempty"`
+}
+
+// ProcessingJob represents a document processing job
+type ProcessingJob struct {
+	CompletedAt  *time.Time             `json:"completed_at,omitempty"`
+	Config       map[string]interface{} `json:"config,omitempty"`
+	CreatedAt    *time.Time             `json:"created_at,omitempty"`
+	DocumentID   string                 `json:"document_id"`
+	Error        string                 `json:"error,omitempty"`
+	ID           string                 `json:"id"`
+	Priority     int                    `json:"priority,omitempty"`
+	PriorityTier string                 `json:"priority_tier,omitempty"`
+	Progress     float64                `json:"progress,omitempty"`
+	Result       map[string]interface{} `json:"result,omitempty"`
+	StartedAt    *time.Time             `json:"started_at,omitempty"`
+	Status       string                 `json:"status"`
+	TenantID     string                 `json:"tenant_id,omitempty"`
+	Type         string                 `json:"type"`
+	UpdatedAt    *time.Time             `json:"updated_at,omitempty"`
+}
+
+// ProcessingJobListResponse lists a tenant's processing jobs, newest first
+type ProcessingJobListResponse struct {
+	HasMore bool             `json:"has_more,omitempty"`
+	Jobs    []*ProcessingJob `json:"jobs,omitempty"`
+	Limit   int              `json:"limit,omitempty"`
+	Offset  int              `json:"offset,omitempty"`
+}
+
+// ProcessingRetryJob is a scheduled reprocessing of a document. Failed jobs
+// are not retried again and form the dead-letter queue operators inspect and
+// requeue.
+type ProcessingRetryJob struct {
+	// Set for jobs of a reprocessing campaign
+	CampaignID string     `json:"campaign_id,omitempty"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	DocumentID string     `json:"document_id,omitempty"`
+	ID         string     `json:"id,omitempty"`
+	// Why the last run failed
+	LastError    string     `json:"last_error,omitempty"`
+	RetryAt      *time.Time `json:"retry_at,omitempty"`
+	RetryAttempt int64      `json:"retry_attempt,omitempty"`
+	Status       string     `json:"status,omitempty"`
+	TenantID     string     `json:"tenant_id,omitempty"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+}
+
+// ProcessingSettings choose how a space's documents are processed
+type ProcessingSettings struct {
+	Provider string `json:"provider,omitempty"`
+}
+
+// ProductionResult represents the result of a producer agent execution
+type ProductionResult struct {
+	Content    string                 `json:"content,omitempty"`
+	CreatedAt  *time.Time             `json:"created_at,omitempty"`
+	Format     string                 `json:"format,omitempty"`
+	ID         string                 `json:"id,omitempty"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	Template   string                 `json:"template,omitempty"`
+	Title      string                 `json:"title,omitempty"`
+}
+
+// PublicUserResponse represents a minimal user response for public contexts
+type PublicUserResponse struct {
+	AvatarURL string `json:"avatarUrl,omitempty"`
+	FullName  string `json:"fullName,omitempty"`
+	ID        string `json:"id,omitempty"`
+	Username  string `json:"username,omitempty"`
+}
+
+// QuotaUsage is a space's use of one quota
+type QuotaUsage struct {
+	Limit int64 `json:"limit,omitempty"`
+	// Counted per calendar month, reset at ResetsAt
+	Monthly bool `json:"monthly,omitempty"`
+	// Used as a percentage of the limit; over 100 once exceeded
+	Percent   float64 `json:"percent,omitempty"`
+	Quota     string  `json:"quota,omitempty"`
+	Remaining int64   `json:"remaining,omitempty"`
+	Used      int64   `json:"used,omitempty"`
+}
+
+// RateLimitConfig holds request rate limit settings. Users are limited per
+// route class, by the class's entry in Classes or else by RequestsPerMinute
+// and Burst; tenants likewise, when TenantRequestsPerMinute or TenantClasses
+// set a limit. The processing submissions of each tenant are limited by the
+// rule of its billing plan in ProcessingPlans.
+type RateLimitConfig struct {
+	Burst             int                       `json:"burst,omitempty"`
+	Classes           map[string]*RateLimitRule `json:"classes,omitempty"`
+	DebtCost          float64                   `json:"debt_cost,omitempty"`
+	Enabled           bool                      `json:"enabled,omitempty"`
+	ProcessingPlans   map[string]*RateLimitRule `json:"processing_plans,omitempty"`
+	RequestsPerMinute int                       `json:"requests_per_minute,omitempty"`
+	TenantBurst       int                       `json:"tenant_burst,omitempty"`
+	TenantClasses     map[string]*RateLimitRule `json:"tenant_classes,omitempty"`
+	// 0 leaves tenants unlimited
+	TenantRequestsPerMinute int `json:"tenant_requests_per_minute,omitempty"`
+}
+
+// RateLimitRule is a token bucket: Burst requests at once, refilled at
+// RequestsPerMinute. A rule of 0 requests per minute does not limit. Once the
+// bucket is empty, Overdraft more requests may be taken on credit; the bucket
+// then owes their cost and refills from below zero.
+type RateLimitRule struct {
+	Burst             int `json:"burst,omitempty"`
+	Overdraft         int `json:"overdraft,omitempty"`
+	RequestsPerMinute int `json:"requests_per_minute,omitempty"`
+}
+
+// RedactionRules hide parts of a notebook's documents from the users it is
+// shared with at restricted roles: the extracted text and chunks they read
+// leave out the chunks the rules omit
+type RedactionRules struct {
+	NotebookID string `json:"notebook_id,omitempty"`
+	// Omit chunks in which PII was detected
+	OmitPIIChunks bool `json:"omit_pii_chunks,omitempty"`
+	// Share roles the rules apply to
+	Roles []string `json:"roles,omitempty"`
+	// Omit the chunks of these sections, by title
+	Sections  []string   `json:"sections,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	UpdatedBy string     `json:"updated_by,omitempty"`
+}
+
+// RedactionRulesRequest sets the redaction rules of a notebook, replacing the
+// ones it had
+type RedactionRulesRequest struct {
+	OmitPIIChunks bool     `json:"omit_pii_chunks,omitempty"`
+	Roles         []string `json:"roles,omitempty"`
+	Sections      []string `json:"sections,omitempty"`
+}
+
+// Region is a region of a multi-region deployment
+type Region struct {
+	ActiveTenants int64 `json:"active_tenants,omitempty"`
+	// Whether it is the region answering
+	Local          bool   `json:"local,omitempty"`
+	Name           string `json:"name,omitempty"`
+	PassiveTenants int64  `json:"passive_tenants,omitempty"`
+	URL            string `json:"url,omitempty"`
+}
+
+// RegionStatus lists the regions of a deployment
+type RegionStatus struct {
+	// Region answering
+	Region  string    `json:"region,omitempty"`
+	Regions []*Region `json:"regions,omitempty"`
+}
+
+// RelatedDocument is a document reached from another through SIMILAR_TO
+// relationships. Distance is the number of relationships followed, and Score
+// the product of their similarity scores along the best path.
+type RelatedDocument struct {
+	Distance int                     `json:"distance,omitempty"`
+	Document *SemanticSearchDocument `json:"document,omitempty"`
+	Score    float64                 `json:"score,omitempty"`
+}
+
+// RelatedDocumentsResponse lists the documents related to a document, best
+// first. LinkedAt is when the document's SIMILAR_TO relationships were last
+// computed.
+type RelatedDocumentsResponse struct {
+	Depth      int                `json:"depth,omitempty"`
+	DocumentID string             `json:"document_id,omitempty"`
+	Documents  []*RelatedDocument `json:"documents,omitempty"`
+	LinkedAt   *time.Time         `json:"linked_at,omitempty"`
+}
+
+// RelatedEntitiesResponse lists the entities most often mentioned by the same
+// documents as an entity
+type RelatedEntitiesResponse struct {
+	EntityID string           `json:"entity_id,omitempty"`
+	Related  []*RelatedEntity `json:"related,omitempty"`
+}
+
+// RelatedEntity is an entity mentioned together with another
+type RelatedEntity struct {
+	Entity          *Entity `json:"entity,omitempty"`
+	SharedDocuments int     `json:"shared_documents,omitempty"`
+}
+
+// ReplicationHookResult is the outcome of a replication hook called before a
+// failover
+type ReplicationHookResult struct {
+	DurationMs int64  `json:"duration_ms,omitempty"`
+	Error      string `json:"error,omitempty"`
+	Name       string `json:"name,omitempty"`
+	// HTTP status the hook answered
+	Status int `json:"status,omitempty"`
+}
+
+// ReportRun is one generated report. Failed runs have no file.
+type ReportRun struct {
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	// Recipients the report was emailed to
+	EmailedTo   int        `json:"emailed_to,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	FileName    string     `json:"file_name,omitempty"`
+	Format      string     `json:"format,omitempty"`
+	ID          string     `json:"id,omitempty"`
+	Kind        string     `json:"kind,omitempty"`
+	PeriodEnd   *time.Time `json:"period_end,omitempty"`
+	PeriodStart *time.Time `json:"period_start,omitempty"`
+	ScheduleID  string     `json:"schedule_id,omitempty"`
+	SizeBytes   int64      `json:"size_bytes,omitempty"`
+	Status      string     `json:"status,omitempty"`
+}
+
+// ReportRunList is the runs of a report schedule, newest first
+type ReportRunList struct {
+	Runs []*ReportRun `json:"runs,omitempty"`
+}
+
+// ReportSchedule generates a report of a space at the end of every period
+type ReportSchedule struct {
+	Cadence       string     `json:"cadence,omitempty"`
+	CreatedAt     *time.Time `json:"created_at,omitempty"`
+	CreatedBy     string     `json:"created_by,omitempty"`
+	EmailTo       []string   `json:"email_to,omitempty"`
+	Format        string     `json:"format,omitempty"`
+	ID            string     `json:"id,omitempty"`
+	Kind          string     `json:"kind,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	LastRunAt     *time.Time `json:"last_run_at,omitempty"`
+	LastStatus    string     `json:"last_status,omitempty"`
+	Name          string     `json:"name,omitempty"`
+	NextRunAt     *time.Time `json:"next_run_at,omitempty"`
+	NotifyUserIDs []string   `json:"notify_user_ids,omitempty"`
+	SpaceID       string     `json:"space_id,omitempty"`
+	SpaceType     SpaceType  `json:"space_type,omitempty"`
+}
+
+// ReportScheduleCreateRequest schedules a report of the current space
+type ReportScheduleCreateRequest struct {
+	Cadence       string   `json:"cadence"`
+	EmailTo       []string `json:"email_to,omitempty"`
+	Format        string   `json:"format"`
+	Kind          string   `json:"kind"`
+	Name          string   `json:"name"`
+	NotifyUserIDs []string `json:"notify_user_ids,omitempty"`
+}
+
+// ReportScheduleList is the report schedules of a space
+type ReportScheduleList struct {
+	Schedules []*ReportSchedule `json:"schedules,omitempty"`
+}
+
+// ReportingRebuildResponse reports the outcome of a read-model rebuild
+type ReportingRebuildResponse struct {
+	DurationMs     int64 `json:"duration_ms,omitempty"`
+	EventsReplayed int   `json:"events_replayed,omitempty"`
+}
+
+// ReportingStatus describes the state of the PostgreSQL reporting projection
+type ReportingStatus struct {
+	Documents   int64      `json:"documents,omitempty"`
+	Events      int64      `json:"events,omitempty"`
+	LastEventAt *time.Time `json:"last_event_at,omitempty"`
+	Notebooks   int64      `json:"notebooks,omitempty"`
+}
+
+// ReprocessCampaignRequest selects documents to reprocess in bulk. The
+// documents are queued as processing retry jobs, so the retry poller bounds
+// how fast they are resubmitted.
+type ReprocessCampaignRequest struct {
+	CreatedAfter *time.Time `json:"created_after,omitempty"`
+	DryRun       bool       `json:"dry_run,omitempty"`
+	// Defaults to 100
+	Limit int `json:"limit,omitempty"`
+	// Defaults to failed
+	Status   string `json:"status,omitempty"`
+	TenantID string `json:"tenant_id,omitempty"`
+}
+
+// ReprocessCampaignResponse reports the documents a campaign queued, or would
+// queue in a dry run. Documents that already have a pending retry job are
+// skipped.
+type ReprocessCampaignResponse struct {
+	CampaignID  string   `json:"campaign_id,omitempty"`
+	DocumentIDs []string `json:"document_ids,omitempty"`
+	DryRun      bool     `json:"dry_run,omitempty"`
+	Queued      int      `json:"queued,omitempty"`
+}
+
+// ReprocessFileRequest selects the chunking strategy to reprocess a file with
+type ReprocessFileRequest struct {
+	Strategy       string                 `json:"strategy"`
+	StrategyConfig map[string]interface{} `json:"strategy_config,omitempty"`
+}
+
+// Role is a built-in role or a custom role of an organization, with the
+// actions it grants
+type Role struct {
+	BuiltIn        bool       `json:"built_in,omitempty"`
+	CreatedAt      *time.Time `json:"created_at,omitempty"`
+	CreatedBy      string     `json:"created_by,omitempty"`
+	Description    string     `json:"description,omitempty"`
+	Name           string     `json:"name,omitempty"`
+	OrganizationID string     `json:"organization_id,omitempty"`
+	Permissions    []string   `json:"permissions,omitempty"`
+	UpdatedAt      *time.Time `json:"updated_at,omitempty"`
+}
+
+// RoleCreateRequest defines a custom role of an organization
+type RoleCreateRequest struct {
+	Description string   `json:"description,omitempty"`
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+// RoleList lists the roles of an organization and the actions roles can grant
+type RoleList struct {
+	Actions []string `json:"actions,omitempty"`
+	Roles   []*Role  `json:"roles,omitempty"`
+}
+
+// RoleUpdateRequest changes a custom role; omitted fields keep their value
+type RoleUpdateRequest struct {
+	Description string   `json:"description,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+}
+
+// RuntimeChange describes a single changed runtime setting
+type RuntimeChange struct {
+	Field string      `json:"field,omitempty"`
+	New   interface{} `json:"new,omitempty"`
+	Old   interface{} `json:"old,omitempty"`
+}
+
+// RuntimeConfig is the subset of configuration that can be changed while the
+// server is running, via SIGHUP or the admin API
+type RuntimeConfig struct {
+	Audimodal    *AudiModalTimeouts `json:"audimodal,omitempty"`
+	FeatureFlags map[string]bool    `json:"feature_flags,omitempty"`
+	LogLevel     string             `json:"log_level,omitempty"`
+	RateLimit    *RateLimitConfig   `json:"rate_limit,omitempty"`
+}
+
+// RuntimeConfigResponse represents the runtime configuration and the changes
+// made by the request, if any
+type RuntimeConfigResponse struct {
+	Changes []*RuntimeChange `json:"changes,omitempty"`
+	Config  *RuntimeConfig   `json:"config,omitempty"`
+}
+
+// S3WatchSetup is what a customer puts in the trust policy of the role
+// watchers assume
+type S3WatchSetup struct {
+	ExternalID   string `json:"external_id,omitempty"`
+	PrincipalArn string `json:"principal_arn,omitempty"`
+}
+
+// S3Watcher ingests the new objects of an S3 prefix into a notebook, as
+// documents owned by the notebook's owner
+type S3Watcher struct {
+	Bucket        string     `json:"bucket,omitempty"`
+	CreatedAt     *time.Time `json:"created_at,omitempty"`
+	CreatedBy     string     `json:"created_by,omitempty"`
+	Exclude       []string   `json:"exclude,omitempty"`
+	ExternalID    string     `json:"external_id,omitempty"`
+	ID            string     `json:"id,omitempty"`
+	Include       []string   `json:"include,omitempty"`
+	IngestedCount int64      `json:"ingested_count,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	LastRunAt     *time.Time `json:"last_run_at,omitempty"`
+	Mode          string     `json:"mode,omitempty"`
+	NotebookID    string     `json:"notebook_id,omitempty"`
+	Prefix        string     `json:"prefix,omitempty"`
+	QueueURL      string     `json:"queue_url,omitempty"`
+	Region        string     `json:"region,omitempty"`
+	RoleArn       string     `json:"role_arn,omitempty"`
+	Status        string     `json:"status,omitempty"`
+}
+
+// S3WatcherCreateRequest creates an S3 watcher. Objects under Prefix are
+// ingested when their key, relative to the prefix, matches an Include pattern
+// (any when none) and no Exclude pattern. Patterns are globs where "*" and "?"
+// stay within a path segment and "**" spans segments.
+type S3WatcherCreateRequest struct {
+	Bucket   string   `json:"bucket"`
+	Exclude  []string `json:"exclude,omitempty"`
+	Include  []string `json:"include,omitempty"`
+	Prefix   string   `json:"prefix,omitempty"`
+	QueueURL string   `json:"queue_url,omitempty"`
+	Region   string   `json:"region"`
+	RoleArn  string   `json:"role_arn,omitempty"`
+}
+
+// S3WatcherList is the S3 watchers of a notebook
+type S3WatcherList struct {
+	Watchers []*S3Watcher `json:"watchers,omitempty"`
+}
+
+// SLOReport describes compliance with the service level objectives as seen by
+// one replica
+type SLOReport struct {
+	GeneratedAt *time.Time   `json:"generated_at,omitempty"`
+	InstanceID  string       `json:"instance_id,omitempty"`
+	Objectives  []*SLOStatus `json:"objectives,omitempty"`
+	WindowDays  int          `json:"window_days,omitempty"`
+}
+
+// SLOStatus reports one objective over the compliance window. The SLI is the
+// fraction of good events; burn rates compare the recent bad-event rate with
+// the rate the error budget allows.
+type SLOStatus struct {
+	Alerting             bool    `json:"alerting,omitempty"`
+	BadEvents            int64   `json:"bad_events,omitempty"`
+	BurnRate1h           float64 `json:"burn_rate_1h,omitempty"`
+	BurnRate6h           float64 `json:"burn_rate_6h,omitempty"`
+	Compliant            bool    `json:"compliant,omitempty"`
+	ErrorBudgetRemaining float64 `json:"error_budget_remaining,omitempty"`
+	Events               int64   `json:"events,omitempty"`
+	LatencyThresholdMs   int     `json:"latency_threshold_ms,omitempty"`
+	Objective            string  `json:"objective,omitempty"`
+	P95LatencyMs         float64 `json:"p95_latency_ms,omitempty"`
+	RouteGroup           string  `json:"route_group,omitempty"`
+	Sli                  float64 `json:"sli,omitempty"`
+	Target               float64 `json:"target,omitempty"`
+}
+
+// ScanSettings choose whether the files uploaded to a space are scanned for
+// malware before they are stored
+type ScanSettings struct {
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// ScheduledJob describes a background job registered with the scheduler
+type ScheduledJob struct {
+	LastError  string     `json:"last_error,omitempty"`
+	LastRunAt  *time.Time `json:"last_run_at,omitempty"`
+	LastStatus string     `json:"last_status,omitempty"`
+	Name       string     `json:"name,omitempty"`
+	NextRunAt  *time.Time `json:"next_run_at,omitempty"`
+	Running    bool       `json:"running,omitempty"`
+	Schedule   string     `json:"schedule,omitempty"`
+}
+
+// ScheduledJobListResponse lists the registered jobs
+type ScheduledJobListResponse struct {
+	Jobs []*ScheduledJob `json:"jobs,omitempty"`
+}
+
+// SearchGroup holds the hits of one type, best first
+type SearchGroup struct {
+	HasMore bool         `json:"has_more,omitempty"`
+	Hits    []*SearchHit `json:"hits,omitempty"`
+	Type    SearchType   `json:"type,omitempty"`
+}
+
+// SearchHit is one matching entity
+type SearchHit struct {
+	ID string `json:"id,omitempty"`
+	// Document type, agent type or event type
+	Kind string `json:"kind,omitempty"`
+	// Notebook of a document
+	NotebookID string `json:"notebook_id,omitempty"`
+	// Comparable across types; higher is better
+	Score     float64    `json:"score,omitempty"`
+	Snippet   string     `json:"snippet,omitempty"`
+	Title     string     `json:"title,omitempty"`
+	Type      SearchType `json:"type,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// SearchOptions represents search options
+type SearchOptions struct {
+	Deduplicate     bool                   `json:"deduplicate,omitempty"`
+	Filters         map[string]interface{} `json:"filters,omitempty"`
+	GroupByDocument bool                   `json:"group_by_document,omitempty"`
+	IncludeContent  bool                   `json:"include_content,omitempty"`
+	IncludeMetadata bool                   `json:"include_metadata,omitempty"`
+	MaxDistance     float64                `json:"max_distance,omitempty"`
+	MinScore        float64                `json:"min_score,omitempty"`
+	Rerank          bool                   `json:"rerank,omitempty"`
+	Threshold       float64                `json:"threshold,omitempty"`
+	TopK            int                    `json:"top_k,omitempty"`
+}
+
+// SearchSuggestResponse returns completions in alphabetical order of the text
+// they matched
+type SearchSuggestResponse struct {
+	Query       string              `json:"query,omitempty"`
+	Suggestions []*SearchSuggestion `json:"suggestions,omitempty"`
+}
+
+// SearchSuggestion is one typeahead completion
+type SearchSuggestion struct {
+	// Document or notebook ID; empty for tags
+	ID   string         `json:"id,omitempty"`
+	Text string         `json:"text,omitempty"`
+	Type SuggestionType `json:"type,omitempty"`
+}
+
+// SearchType is an entity type covered by the unified search
+type SearchType string
+
+const (
+	SearchTypeDocument SearchType = "document"
+	SearchTypeNotebook SearchType = "notebook"
+	SearchTypeAgent    SearchType = "agent"
+	SearchTypeEvent    SearchType = "event"
+)
+
+// SemanticSearchDocument is the document a chunk hit belongs to
+type SemanticSearchDocument struct {
+	ID         string `json:"id,omitempty"`
+	MimeType   string `json:"mime_type,omitempty"`
+	Name       string `json:"name,omitempty"`
+	NotebookID string `json:"notebook_id,omitempty"`
+}
+
+// SemanticSearchHit is a matching chunk and its document
+type SemanticSearchHit struct {
+	ChunkID  string                  `json:"chunk_id,omitempty"`
+	Content  string                  `json:"content,omitempty"`
+	Document *SemanticSearchDocument `json:"document,omitempty"`
+	Score    float64                 `json:"score,omitempty"`
+}
+
+// SemanticSearchMode selects how chunks are matched
+type SemanticSearchMode string
+
+const (
+	SemanticSearchModeVector SemanticSearchMode = "vector"
+	SemanticSearchModeHybrid SemanticSearchMode = "hybrid"
+)
+
+// SemanticSearchRequest represents a semantic search of a space's chunks
+type SemanticSearchRequest struct {
+	MinScore float64 `json:"min_score,omitempty"`
+	// Default vector
+	Mode SemanticSearchMode `json:"mode,omitempty"`
+	// Only chunks of documents in these notebooks
+	NotebookIDs []string `json:"notebook_ids,omitempty"`
+	Query       string   `json:"query"`
+	// Default 10
+	TopK int `json:"top_k,omitempty"`
+	// Hybrid only; keywords get the rest. Default 0.7
+	VectorWeight float64 `json:"vector_weight,omitempty"`
+}
+
+// SemanticSearchResponse lists chunk hits, best first. Chunks whose document
+// the caller cannot read, or which no longer exists, are left out.
+type SemanticSearchResponse struct {
+	Hits        []*SemanticSearchHit `json:"hits,omitempty"`
+	Mode        SemanticSearchMode   `json:"mode,omitempty"`
+	Query       string               `json:"query,omitempty"`
+	QueryTimeMs float64              `json:"query_time_ms,omitempty"`
+}
+
+// SharedNotebook is a notebook shared with the user, directly or through one
+// of their teams, with the highest role they were given
+type SharedNotebook struct {
+	Description   string `json:"description,omitempty"`
+	DocumentCount int    `json:"document_count,omitempty"`
+	ID            string `json:"id,omitempty"`
+	Name          string `json:"name,omitempty"`
+	OwnerID       string `json:"owner_id,omitempty"`
+	Role          string `json:"role,omitempty"`
+	// When the latest share was made
+	SharedAt  *time.Time `json:"shared_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// SharedNotebookListResponse is a page of the notebooks shared with the user
+type SharedNotebookListResponse struct {
+	HasMore   bool              `json:"has_more,omitempty"`
+	Limit     int               `json:"limit,omitempty"`
+	Notebooks []*SharedNotebook `json:"notebooks,omitempty"`
+	Offset    int               `json:"offset,omitempty"`
+}
+
+// SimilarDocument is a document related to another and the signals that relate
+// them. Score weighs them into a value between 0 and 1.
+type SimilarDocument struct {
+	Document         *SemanticSearchDocument `json:"document,omitempty"`
+	SameNotebook     bool                    `json:"same_notebook,omitempty"`
+	Score            float64                 `json:"score,omitempty"`
+	SharedReferences int                     `json:"shared_references,omitempty"`
+	SharedTags       []string                `json:"shared_tags,omitempty"`
+	VectorScore      float64                 `json:"vector_score,omitempty"`
+}
+
+// SimilarDocumentsResponse lists the documents most related to a document,
+// best first. GeneratedAt is when the list was computed; lists are cached.
+type SimilarDocumentsResponse struct {
+	DocumentID  string             `json:"document_id,omitempty"`
+	Documents   []*SimilarDocument `json:"documents,omitempty"`
+	GeneratedAt *time.Time         `json:"generated_at,omitempty"`
+}
+
+// SourceReference represents a reference to a source used in agent execution
+type SourceReference struct {
+	ChunkID      string  `json:"chunk_id,omitempty"`
+	Content      string  `json:"content,omitempty"`
+	NotebookID   string  `json:"notebook_id,omitempty"`
+	NotebookName string  `json:"notebook_name,omitempty"`
+	Relevance    float64 `json:"relevance,omitempty"`
+}
+
+// SpaceCreateRequest represents a request to create a new space
+type SpaceCreateRequest struct {
+	Description    string `json:"description,omitempty"`
+	Name           string `json:"name"`
+	OrganizationID string `json:"organization_id,omitempty"`
+	Visibility     string `json:"visibility,omitempty"`
+}
+
+// SpaceEncryption is the encryption at rest of the extracted text of a space's
+// documents, with the progress of migrating them to the setting
+type SpaceEncryption struct {
+	Enabled   bool   `json:"enabled,omitempty"`
+	Migrated  int    `json:"migrated,omitempty"`
+	Remaining int    `json:"remaining,omitempty"`
+	SpaceID   string `json:"space_id,omitempty"`
+}
+
+// SpaceEncryptionUpdateRequest turns the encryption at rest of the extracted
+// text of a space's documents on or off
+type SpaceEncryptionUpdateRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+// SpaceFullResponse represents a complete space response with camelCase fields
+type SpaceFullResponse struct {
+	AudimodalTenantID string                 `json:"audimodalTenantId,omitempty"`
+	CreatedAt         *time.Time             `json:"createdAt,omitempty"`
+	DeeplakeNamespace string                 `json:"deeplakeNamespace,omitempty"`
+	DeletedAt         *time.Time             `json:"deletedAt,omitempty"`
+	Description       string                 `json:"description,omitempty"`
+	ID                string                 `json:"id,omitempty"`
+	MemberCount       int                    `json:"memberCount,omitempty"`
+	Name              string                 `json:"name,omitempty"`
+	NotebookCount     int                    `json:"notebookCount,omitempty"`
+	OwnerID           string                 `json:"ownerId,omitempty"`
+	OwnerType         SpaceOwnerType         `json:"ownerType,omitempty"`
+	Quotas            *SpaceQuotas           `json:"quotas,omitempty"`
+	Settings          map[string]interface{} `json:"settings,omitempty"`
+	Status            SpaceStatus            `json:"status,omitempty"`
+	TenantID          string                 `json:"tenantId,omitempty"`
+	Type              SpaceType              `json:"type,omitempty"`
+	UpdatedAt         *time.Time             `json:"updatedAt,omitempty"`
+	// Current user's role in this space
+	UserRole   string `json:"userRole,omitempty"`
+	Visibility string `json:"visibility,omitempty"`
+}
+
+// SpaceInfo provides public information about a space
+type SpaceInfo struct {
+	// Organization that owns this space (for org spaces)
+	OrganizationID string `json:"organization_id,omitempty"`
+	// Display name of the organization
+	OrganizationName string    `json:"organization_name,omitempty"`
+	Permissions      []string  `json:"permissions,omitempty"`
+	SpaceID          string    `json:"space_id,omitempty"`
+	SpaceName        string    `json:"space_name,omitempty"`
+	SpaceType        SpaceType `json:"space_type,omitempty"`
+	TenantID         string    `json:"tenant_id,omitempty"`
+	UserRole         string    `json:"user_role,omitempty"`
+}
+
+// SpaceListResponse represents a list of available spaces for a user
+type SpaceListResponse struct {
+	CurrentSpace       *SpaceInfo   `json:"current_space,omitempty"`
+	OrganizationSpaces []*SpaceInfo `json:"organization_spaces,omitempty"`
+	PersonalSpace      *SpaceInfo   `json:"personal_space,omitempty"`
+}
+
+// SpaceMemberResponse represents a space member response with camelCase fields
+type SpaceMemberResponse struct {
+	Avatar      string     `json:"avatar,omitempty"`
+	InvitedBy   string     `json:"invitedBy,omitempty"`
+	JoinedAt    *time.Time `json:"joinedAt,omitempty"`
+	Permissions []string   `json:"permissions,omitempty"`
+	Role        string     `json:"role,omitempty"`
+	SpaceID     string     `json:"spaceId,omitempty"`
+	UserEmail   string     `json:"userEmail,omitempty"`
+	UserID      string     `json:"userId,omitempty"`
+	UserName    string     `json:"userName,omitempty"`
+}
+
+// SpaceMembersListResponse represents a paginated list of space members
+type SpaceMembersListResponse struct {
+	HasMore bool                   `json:"hasMore,omitempty"`
+	Limit   int                    `json:"limit,omitempty"`
+	Members []*SpaceMemberResponse `json:"members,omitempty"`
+	Offset  int                    `json:"offset,omitempty"`
+	Total   int                    `json:"total,omitempty"`
+}
+
+// SpaceOwnerType represents the type of owner of a space
+type SpaceOwnerType string
+
+const (
+	SpaceOwnerTypeUser         SpaceOwnerType = "user"
+	SpaceOwnerTypeOrganization SpaceOwnerType = "organization"
+)
+
+// SpaceQuotaUsage is a space's use of its quotas. Monthly quotas count the
+// current calendar month (UTC).
+type SpaceQuotaUsage struct {
+	// Whether writes over a quota are refused
+	Enforced bool `json:"enforced,omitempty"`
+	// YYYY-MM of the monthly quotas
+	Period   string        `json:"period,omitempty"`
+	Quotas   []*QuotaUsage `json:"quotas,omitempty"`
+	ResetsAt *time.Time    `json:"resets_at,omitempty"`
+	SpaceID  string        `json:"space_id,omitempty"`
+}
+
+// SpaceQuotas represents resource quotas for a space
+type SpaceQuotas struct {
+	// Agent executions per calendar month
+	MaxAgentExecutions int64 `json:"max_agent_executions,omitempty"`
+	// Maximum number of documents
+	MaxDocuments int `json:"max_documents,omitempty"`
+	// Maximum number of members (for org spaces)
+	MaxMembersCount int `json:"max_members_count,omitempty"`
+	// Maximum number of notebooks
+	MaxNotebooks int `json:"max_notebooks,omitempty"`
+	// Maximum storage in bytes
+	MaxStorageBytes int64 `json:"max_storage_bytes,omitempty"`
+	// Stream events ingested per calendar month
+	MaxStreamEvents int64 `json:"max_stream_events,omitempty"`
+	// Maximum number of teams (for org spaces)
+	MaxTeamsCount int `json:"max_teams_count,omitempty"`
+	// Current document count
+	UsedDocuments int `json:"used_documents,omitempty"`
+	// Current notebook count
+	UsedNotebooks int `json:"used_notebooks,omitempty"`
+	// Current storage usage
+	UsedStorageBytes int64 `json:"used_storage_bytes,omitempty"`
+}
+
+// SpaceQuotasUpdateRequest changes the limits of a space's quotas; omitted
+// limits keep their value
+type SpaceQuotasUpdateRequest struct {
+	MaxAgentExecutions int64 `json:"max_agent_executions,omitempty"`
+	MaxDocuments       int   `json:"max_documents,omitempty"`
+	MaxMembersCount    int   `json:"max_members_count,omitempty"`
+	MaxNotebooks       int   `json:"max_notebooks,omitempty"`
+	MaxStorageBytes    int64 `json:"max_storage_bytes,omitempty"`
+	MaxStreamEvents    int64 `json:"max_stream_events,omitempty"`
+	MaxTeamsCount      int   `json:"max_teams_count,omitempty"`
+}
+
+// SpaceResponse represents a space creation/update response
+type SpaceResponse struct {
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	Description string     `json:"description,omitempty"`
+	// Organization that owns this space (for org spaces)
+	OrganizationID string `json:"organization_id,omitempty"`
+	// Display name of the organization
+	OrganizationName string     `json:"organization_name,omitempty"`
+	Permissions      []string   `json:"permissions,omitempty"`
+	SpaceID          string     `json:"space_id,omitempty"`
+	SpaceName        string     `json:"space_name,omitempty"`
+	SpaceType        SpaceType  `json:"space_type,omitempty"`
+	TenantID         string     `json:"tenant_id,omitempty"`
+	UpdatedAt        *time.Time `json:"updated_at,omitempty"`
+	UserRole         string     `json:"user_role,omitempty"`
+	Visibility       string     `json:"visibility,omitempty"`
+}
+
+// SpaceStatus represents the status of a space
+type SpaceStatus string
+
+const (
+	SpaceStatusActive    SpaceStatus = "active"
+	SpaceStatusSuspended SpaceStatus = "suspended"
+	SpaceStatusDeleted   SpaceStatus = "deleted"
+)
+
+// SpaceType represents the type of space
+type SpaceType string
+
+const (
+	SpaceTypePersonal     SpaceType = "personal"
+	SpaceTypeOrganization SpaceType = "organization"
+)
+
+// SpaceUpdateRequest represents a request to update a space
+type SpaceUpdateRequest struct {
+	Description    string                  `json:"description,omitempty"`
+	FileValidation *FileValidationSettings `json:"file_validation,omitempty"`
+	Name           string                  `json:"name,omitempty"`
+	Ocr            *OCRSettings            `json:"ocr,omitempty"`
+	Processing     *ProcessingSettings     `json:"processing,omitempty"`
+	Scanning       *ScanSettings           `json:"scanning,omitempty"`
+	Visibility     string                  `json:"visibility,omitempty"`
+}
+
+// SpaceUsage totals the daily usage of a space over a report's period
+type SpaceUsage struct {
+	BytesUploaded      int64  `json:"bytes_uploaded,omitempty"`
+	DocumentsDeleted   int64  `json:"documents_deleted,omitempty"`
+	DocumentsFailed    int64  `json:"documents_failed,omitempty"`
+	DocumentsProcessed int64  `json:"documents_processed,omitempty"`
+	DocumentsUploaded  int64  `json:"documents_uploaded,omitempty"`
+	SpaceID            string `json:"space_id,omitempty"`
+}
+
+// StepResult represents the result of executing a workflow step
+type StepResult struct {
+	CompletedAt  *time.Time             `json:"completed_at,omitempty"`
+	ErrorMessage string                 `json:"error_message,omitempty"`
+	ExecutionID  string                 `json:"execution_id,omitempty"`
+	ID           string                 `json:"id,omitempty"`
+	Input        map[string]interface{} `json:"input,omitempty"`
+	Output       map[string]interface{} `json:"output,omitempty"`
+	RetryAttempt int                    `json:"retry_attempt,omitempty"`
+	// milliseconds
+	Runtime   float64    `json:"runtime,omitempty"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	// pending, running, completed, failed, skipped
+	Status string `json:"status,omitempty"`
+	StepID string `json:"step_id,omitempty"`
+}
+
+// StrategyRecommendationRequest describes a file to recommend a chunking
+// strategy for
+type StrategyRecommendationRequest struct {
+	Complexity  string `json:"complexity,omitempty"`
+	ContentType string `json:"content_type"`
+	FileSize    int64  `json:"file_size"`
+}
+
+// StreamAnalytics represents real-time stream performance analytics
+type StreamAnalytics struct {
+	ActiveStreams int `json:"active_streams,omitempty"`
+	// 99.1%
+	AverageAuditScore float64 `json:"average_audit_score,omitempty"`
+	// milliseconds
+	AverageProcessingTime float64    `json:"average_processing_time,omitempty"`
+	CreatedAt             *time.Time `json:"created_at,omitempty"`
+	ErrorRate             float64    `json:"error_rate,omitempty"`
+	// mention/multimodal/etc counts
+	EventTypeDistribution map[string]int64 `json:"event_type_distribution,omitempty"`
+	EventsPerSecond       float64          `json:"events_per_second,omitempty"`
+	ID                    string           `json:"id,omitempty"`
+	// 2.4M+
+	MediaProcessed int64  `json:"media_processed,omitempty"`
+	OrganizationID string `json:"organization_id,omitempty"`
+	// realtime, hourly, daily
+	Period string `json:"period,omitempty"`
+	// provider -> events/sec
+	ProviderPerformance map[string]float64 `json:"provider_performance,omitempty"`
+	// positive/neutral/negative counts
+	SentimentDistribution map[string]int64 `json:"sentiment_distribution,omitempty"`
+	TenantID              string           `json:"tenant_id,omitempty"`
+	Timestamp             *time.Time       `json:"timestamp,omitempty"`
+	TotalEventsProcessed  int64            `json:"total_events_processed,omitempty"`
+}
+
+// StreamSource represents a live data stream source
+type StreamSource struct {
+	Configuration   map[string]interface{} `json:"configuration,omitempty"`
+	ConnectedAt     *time.Time             `json:"connected_at,omitempty"`
+	CreatedAt       *time.Time             `json:"created_at,omitempty"`
+	CreatedBy       string                 `json:"created_by,omitempty"`
+	ErrorCount      int64                  `json:"error_count,omitempty"`
+	ErrorMessage    string                 `json:"error_message,omitempty"`
+	EventsPerSecond float64                `json:"events_per_second,omitempty"`
+	EventsProcessed int64                  `json:"events_processed,omitempty"`
+	ID              string                 `json:"id,omitempty"`
+	LastEventAt     *time.Time             `json:"last_event_at,omitempty"`
+	Name            string                 `json:"name,omitempty"`
+	OrganizationID  string                 `json:"organization_id,omitempty"`
+	// twitter, stocks, salesforce, youtube, news_api
+	Provider string `json:"provider,omitempty"`
+	// active, paused, disconnected, error
+	Status   string `json:"status,omitempty"`
+	TenantID string `json:"tenant_id,omitempty"`
+	// social, financial, enterprise, media, news
+	Type      string     `json:"type,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// StreamWebhookEvent is an event a stream source pushes to its webhook
+type StreamWebhookEvent struct {
+	Content   string                 `json:"content"`
+	EventType string                 `json:"event_type"`
+	MediaType string                 `json:"media_type"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// SuggestionType is the kind of entity a typeahead suggestion completes to
+type SuggestionType string
+
+const (
+	SuggestionTypeDocument SuggestionType = "document"
+	SuggestionTypeNotebook SuggestionType = "notebook"
+	SuggestionTypeTag      SuggestionType = "tag"
+)
+
+// Summary is an LLM-written summary of a document or notebook. It is cached on
+// the subject's node and only written again when refreshed, or, for a document
+// summarized automatically, when the document is reprocessed.
+type Summary struct {
+	// Served from the cache without calling the model
+	Cached      bool               `json:"cached,omitempty"`
+	GeneratedAt *time.Time         `json:"generated_at,omitempty"`
+	Model       string             `json:"model,omitempty"`
+	Stale       bool               `json:"stale,omitempty"`
+	SubjectID   string             `json:"subject_id,omitempty"`
+	SubjectType SummarySubjectType `json:"subject_type,omitempty"`
+	Summary     string             `json:"summary,omitempty"`
+	// Spent writing this summary
+	TokensUsed int64 `json:"tokens_used,omitempty"`
+}
+
+// SummarySubjectType is what a summary summarizes
+type SummarySubjectType string
+
+const (
+	SummarySubjectTypeDocument SummarySubjectType = "document"
+	SummarySubjectTypeNotebook SummarySubjectType = "notebook"
+)
+
+// SummaryUsage is the tokens a space has spent on summaries in a calendar
+// month (UTC)
+type SummaryUsage struct {
+	// YYYY-MM
+	Period    string     `json:"period,omitempty"`
+	ResetsAt  *time.Time `json:"resets_at,omitempty"`
+	SpaceID   string     `json:"space_id,omitempty"`
+	Summaries int64      `json:"summaries,omitempty"`
+	// 0 is unlimited
+	TokenBudget int64 `json:"token_budget,omitempty"`
+	TokensUsed  int64 `json:"tokens_used,omitempty"`
+}
+
+// SyntheticProbeResult is the outcome of the last run of a probe
+type SyntheticProbeResult struct {
+	CheckedAt  *time.Time `json:"checked_at,omitempty"`
+	DurationMs int64      `json:"duration_ms,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	Probe      string     `json:"probe,omitempty"`
+	Skipped    bool       `json:"skipped,omitempty"`
+	StatusCode int        `json:"status_code,omitempty"`
+	Success    bool       `json:"success,omitempty"`
+}
+
+// SyntheticReport lists the last result of every synthetic probe
+type SyntheticReport struct {
+	InstanceID string                  `json:"instance_id,omitempty"`
+	LastRunAt  *time.Time              `json:"last_run_at,omitempty"`
+	Probes     []*SyntheticProbeResult `json:"probes,omitempty"`
+}
+
+// SystemStatusResponse represents per-dependency status for operators
+type SystemStatusResponse struct {
+	Dependencies []interface{} `json:"dependencies,omitempty"`
+	Draining     bool          `json:"draining,omitempty"`
+	Status       string        `json:"status,omitempty"`
+	Timestamp    *time.Time    `json:"timestamp,omitempty"`
+	Version      string        `json:"version,omitempty"`
+}
+
+// TeamCreateRequest represents a request to create a team
+type TeamCreateRequest struct {
+	Description    string                 `json:"description,omitempty"`
+	Icon           string                 `json:"icon,omitempty"`
+	Name           string                 `json:"name"`
+	OrganizationID string                 `json:"organization_id"`
+	Settings       map[string]interface{} `json:"settings,omitempty"`
+	Visibility     string                 `json:"visibility"`
+}
+
+// TeamInviteRequest represents a request to invite a team member
+type TeamInviteRequest struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+// TeamMemberResponse represents a team member response with camelCase fields
+type TeamMemberResponse struct {
+	Email     string     `json:"email,omitempty"`
+	InvitedBy string     `json:"invitedBy,omitempty"`
+	JoinedAt  *time.Time `json:"joinedAt,omitempty"`
+	Name      string     `json:"name,omitempty"`
+	Role      string     `json:"role,omitempty"`
+	UserID    string     `json:"userId,omitempty"`
+}
+
+// TeamMemberRoleUpdateRequest represents a request to update a team member's
+// role
+type TeamMemberRoleUpdateRequest struct {
+	Role string `json:"role"`
+}
+
+// TeamResponse represents a team response
+type TeamResponse struct {
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+	// camelCase for frontend compatibility
+	CreatedBy     string `json:"createdBy,omitempty"`
+	Description   string `json:"description,omitempty"`
+	Icon          string `json:"icon,omitempty"`
+	ID            string `json:"id,omitempty"`
+	MemberCount   int    `json:"memberCount,omitempty"`
+	Name          string `json:"name,omitempty"`
+	NotebookCount int    `json:"notebookCount,omitempty"`
+	// camelCase for frontend compatibility
+	OrganizationID string                 `json:"organizationId,omitempty"`
+	Settings       map[string]interface{} `json:"settings,omitempty"`
+	UpdatedAt      *time.Time             `json:"updatedAt,omitempty"`
+	UserRole       string                 `json:"userRole,omitempty"`
+	Visibility     string                 `json:"visibility,omitempty"`
+}
+
+// TeamUpdateRequest represents a request to update a team
+type TeamUpdateRequest struct {
+	Description string                 `json:"description,omitempty"`
+	Icon        string                 `json:"icon,omitempty"`
+	Name        string                 `json:"name,omitempty"`
+	Settings    map[string]interface{} `json:"settings,omitempty"`
+	Visibility  string                 `json:"visibility,omitempty"`
+}
+
+// TenantFailover is the outcome of a failover
+type TenantFailover struct {
+	FromRegion string                   `json:"from_region,omitempty"`
+	Hooks      []*ReplicationHookResult `json:"hooks,omitempty"`
+	Tenant     *TenantRegion            `json:"tenant,omitempty"`
+	ToRegion   string                   `json:"to_region,omitempty"`
+}
+
+// TenantFailoverRequest fails a tenant over to another region
+type TenantFailoverRequest struct {
+	// Fail over even when a replication hook fails
+	Force bool `json:"force,omitempty"`
+	// Default the tenant's passive region
+	TargetRegion string `json:"target_region,omitempty"`
+}
+
+// TenantPipelineSummary summarizes the pipeline of a tenant's documents
+type TenantPipelineSummary struct {
+	Documents int64                   `json:"documents,omitempty"`
+	Stages    []*PipelineStageSummary `json:"stages,omitempty"`
+	TenantID  string                  `json:"tenant_id,omitempty"`
+}
+
+// TenantProvisionRequest provisions an organization tenant for an existing
+// user: the organization, with the user as owner, and its first space
+type TenantProvisionRequest struct {
+	Organization *OrganizationCreateRequest `json:"organization"`
+	OwnerID      string                     `json:"owner_id"`
+	// Defaults to the organization name
+	SpaceName string `json:"space_name,omitempty"`
+}
+
+// TenantProvisionResponse describes a provisioned tenant
+type TenantProvisionResponse struct {
+	Organization *OrganizationResponse `json:"organization,omitempty"`
+	Space        *SpaceFullResponse    `json:"space,omitempty"`
+}
+
+// TenantRegion pins a tenant to the region that serves it. Requests made in
+// the tenant's spaces are only served by the active region; the passive region
+// holds the replicas a failover promotes.
+type TenantRegion struct {
+	ActiveRegion  string     `json:"active_region,omitempty"`
+	FailedOverAt  *time.Time `json:"failed_over_at,omitempty"`
+	Failovers     int64      `json:"failovers,omitempty"`
+	PassiveRegion string     `json:"passive_region,omitempty"`
+	PinnedAt      *time.Time `json:"pinned_at,omitempty"`
+	PinnedBy      string     `json:"pinned_by,omitempty"`
+	TenantID      string     `json:"tenant_id,omitempty"`
+}
+
+// TenantRegionRequest pins a tenant to a region
+type TenantRegionRequest struct {
+	ActiveRegion  string `json:"active_region"`
+	PassiveRegion string `json:"passive_region,omitempty"`
+}
+
+// TextSearchRequest represents a text-based vector search request
+type TextSearchRequest struct {
+	Options   *SearchOptions `json:"options,omitempty"`
+	QueryText string         `json:"query_text"`
+}
+
+// TrashItem is a deleted document or notebook that can still be restored. It
+// is purged, with its files, at PurgeAt.
+type TrashItem struct {
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	DeletedBy string     `json:"deleted_by,omitempty"`
+	ID        string     `json:"id,omitempty"`
+	Name      string     `json:"name,omitempty"`
+	// Documents only
+	NotebookID string     `json:"notebook_id,omitempty"`
+	PurgeAt    *time.Time `json:"purge_at,omitempty"`
+	// Documents only
+	SizeBytes int64  `json:"size_bytes,omitempty"`
+	Type      string `json:"type,omitempty"`
+}
+
+// TrashListResponse is a page of the trash of a space, most recently deleted
+// first
+type TrashListResponse struct {
+	HasMore       bool         `json:"has_more,omitempty"`
+	Items         []*TrashItem `json:"items,omitempty"`
+	Limit         int          `json:"limit,omitempty"`
+	Offset        int          `json:"offset,omitempty"`
+	RetentionDays int          `json:"retention_days,omitempty"`
+}
+
+// UnifiedSearchResponse returns the hits of a unified search both ranked
+// together and grouped by type. Groups are ordered by their best hit.
+type UnifiedSearchResponse struct {
+	Groups  []*SearchGroup `json:"groups,omitempty"`
+	Query   string         `json:"query,omitempty"`
+	Results []*SearchHit   `json:"results,omitempty"`
+	// Types that could not be searched
+	Unavailable []SearchType `json:"unavailable,omitempty"`
+}
+
+// UpdateExperimentRequest represents the request to update an experiment
+type UpdateExperimentRequest struct {
+	Metrics  map[string]float64     `json:"metrics,omitempty"`
+	Progress float64                `json:"progress,omitempty"`
+	Results  map[string]interface{} `json:"results,omitempty"`
+	Status   string                 `json:"status,omitempty"`
+}
+
+// UpdateMLModelRequest represents the request to update an ML model
+type UpdateMLModelRequest struct {
+	Description string                 `json:"description,omitempty"`
+	Name        string                 `json:"name,omitempty"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+	Status      string                 `json:"status,omitempty"`
+	Version     string                 `json:"version,omitempty"`
+}
+
+// UpdateMemberRoleRequest represents a request to update a member's role
+type UpdateMemberRoleRequest struct {
+	Role string `json:"role"`
+}
+
+// UpdateStreamSourceRequest represents the request to update a stream source
+type UpdateStreamSourceRequest struct {
+	Configuration map[string]interface{} `json:"configuration,omitempty"`
+	Name          string                 `json:"name,omitempty"`
+	Status        string                 `json:"status,omitempty"`
+}
+
+// UpdateWorkflowRequest represents the request to update a workflow
+type UpdateWorkflowRequest struct {
+	Configuration map[string]interface{} `json:"configuration,omitempty"`
+	Description   string                 `json:"description,omitempty"`
+	Name          string                 `json:"name,omitempty"`
+	Status        string                 `json:"status,omitempty"`
+}
+
+// UploadIntent is where and how to store the file of a direct upload. The
+// document stays in status uploading until the upload is confirmed.
+type UploadIntent struct {
+	Document  *DocumentResponse `json:"document,omitempty"`
+	ExpiresAt *time.Time        `json:"expires_at,omitempty"`
+	// To send with the file
+	Headers map[string]string `json:"headers,omitempty"`
+	// Always PUT
+	Method    string `json:"method,omitempty"`
+	UploadURL string `json:"upload_url,omitempty"`
+}
+
+// UploadIntentCreateRequest describes a file the client will store itself,
+// with a presigned URL, instead of sending it through the API
+type UploadIntentCreateRequest struct {
+	// Of the whole file, hex encoded
+	ChecksumSha256 string `json:"checksum_sha256"`
+	Description    string `json:"description,omitempty"`
+	FileName       string `json:"file_name"`
+	MimeType       string `json:"mime_type"`
+	// Default the file name
+	Name string `json:"name,omitempty"`
+	// Processing priority tier
+	Priority  string   `json:"priority,omitempty"`
+	SizeBytes int64    `json:"size_bytes"`
+	Tags      []string `json:"tags,omitempty"`
+}
+
+// UploadSession is a resumable upload of one file. The file is sent as
+// PartCount parts of PartSize bytes, the last one possibly shorter, in any
+// order and each as often as needed; Parts lists those stored so far, so an
+// interrupted upload resumes by sending the others.
+type UploadSession struct {
+	CreatedAt  *time.Time           `json:"created_at,omitempty"`
+	DocumentID string               `json:"document_id,omitempty"`
+	ExpiresAt  *time.Time           `json:"expires_at,omitempty"`
+	FileName   string               `json:"file_name,omitempty"`
+	ID         string               `json:"id,omitempty"`
+	MimeType   string               `json:"mime_type,omitempty"`
+	NotebookID string               `json:"notebook_id,omitempty"`
+	OwnerID    string               `json:"owner_id,omitempty"`
+	PartCount  int                  `json:"part_count,omitempty"`
+	PartSize   int64                `json:"part_size,omitempty"`
+	Parts      []*UploadSessionPart `json:"parts,omitempty"`
+	SizeBytes  int64                `json:"size_bytes,omitempty"`
+	SpaceID    string               `json:"space_id,omitempty"`
+	Status     UploadSessionStatus  `json:"status,omitempty"`
+	UpdatedAt  *time.Time           `json:"updated_at,omitempty"`
+}
+
+// UploadSessionCreateRequest starts a resumable upload of one file into a
+// notebook
+type UploadSessionCreateRequest struct {
+	Description string `json:"description,omitempty"`
+	FileName    string `json:"file_name"`
+	MimeType    string `json:"mime_type,omitempty"`
+	// Default the file name
+	Name string `json:"name,omitempty"`
+	// Processing priority tier
+	Priority  string   `json:"priority,omitempty"`
+	SizeBytes int64    `json:"size_bytes"`
+	Tags      []string `json:"tags,omitempty"`
+}
+
+// UploadSessionPart is a part of a resumable upload that has been stored
+type UploadSessionPart struct {
+	Etag      string `json:"etag,omitempty"`
+	Number    int    `json:"number,omitempty"`
+	SizeBytes int64  `json:"size_bytes,omitempty"`
+}
+
+// UploadSessionStatus is the state of a resumable upload
+type UploadSessionStatus string
+
+const (
+	UploadSessionStatusActive    UploadSessionStatus = "active"
+	UploadSessionStatusCompleted UploadSessionStatus = "completed"
+	UploadSessionStatusAborted   UploadSessionStatus = "aborted"
+)
+
+// UsageAnomaly is a tenant signal that deviated from its baseline in one
+// interval. The baseline is an exponentially weighted moving average of the
+// previous intervals; the z-score is the deviation in standard deviations.
+type UsageAnomaly struct {
+	Baseline    float64    `json:"baseline,omitempty"`
+	IntervalEnd *time.Time `json:"interval_end,omitempty"`
+	Signal      string     `json:"signal,omitempty"`
+	StdDev      float64    `json:"std_dev,omitempty"`
+	TenantID    string     `json:"tenant_id,omitempty"`
+	Value       float64    `json:"value,omitempty"`
+	ZScore      float64    `json:"z_score,omitempty"`
+}
+
+// UsageAnomalyReport lists the anomalies seen by one replica: those active in
+// the last interval and the most recent ones detected
+type UsageAnomalyReport struct {
+	Active          []*UsageAnomaly `json:"active,omitempty"`
+	InstanceID      string          `json:"instance_id,omitempty"`
+	IntervalSeconds int             `json:"interval_seconds,omitempty"`
+	Recent          []*UsageAnomaly `json:"recent,omitempty"`
+	TrackedTenants  int             `json:"tracked_tenants,omitempty"`
+}
+
+// UsageReport totals the projected daily usage per space for the days from
+// From up to but excluding To
+type UsageReport struct {
+	From   *time.Time    `json:"from,omitempty"`
+	Spaces []*SpaceUsage `json:"spaces,omitempty"`
+	To     *time.Time    `json:"to,omitempty"`
+	Total  *SpaceUsage   `json:"total,omitempty"`
+}
+
+// UserListResponse represents a paginated list of users
+type UserListResponse struct {
+	HasMore bool                  `json:"has_more,omitempty"`
+	Limit   int                   `json:"limit,omitempty"`
+	Offset  int                   `json:"offset,omitempty"`
+	Total   int                   `json:"total,omitempty"`
+	Users   []*PublicUserResponse `json:"users,omitempty"`
+}
+
+// UserPreferences represents user preferences
+type UserPreferences struct {
+	DateFormat    string                 `json:"date_format,omitempty"`
+	Language      string                 `json:"language,omitempty"`
+	Notifications map[string]bool        `json:"notifications,omitempty"`
+	Settings      map[string]interface{} `json:"settings,omitempty"`
+	Theme         string                 `json:"theme,omitempty"`
+	Timezone      string                 `json:"timezone,omitempty"`
+}
+
+// UserResponse represents a user response (may exclude sensitive data)
+type UserResponse struct {
+	AvatarURL string     `json:"avatarUrl,omitempty"`
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+	Email     string     `json:"email,omitempty"`
+	FullName  string     `json:"fullName,omitempty"`
+	ID        string     `json:"id,omitempty"`
+	Status    string     `json:"status,omitempty"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+	Username  string     `json:"username,omitempty"`
+}
+
+// UserStats represents user statistics
+type UserStats struct {
+	DocumentCount      int    `json:"document_count,omitempty"`
+	FailedDocuments    int    `json:"failed_documents,omitempty"`
+	MemberSince        string `json:"member_since,omitempty"`
+	NotebookCount      int    `json:"notebook_count,omitempty"`
+	ProcessedDocuments int    `json:"processed_documents,omitempty"`
+	TotalSizeBytes     int64  `json:"total_size_bytes,omitempty"`
+}
+
+// UserUpdateRequest represents a request to update a user
+type UserUpdateRequest struct {
+	AvatarURL   string                 `json:"avatar_url,omitempty"`
+	FullName    string                 `json:"full_name,omitempty"`
+	Preferences map[string]interface{} `json:"preferences,omitempty"`
+	Status      string                 `json:"status,omitempty"`
+}
+
+// VectorSearchConfig represents agent's vector search configuration
+type VectorSearchConfig struct {
+	MaxResults   int                  `json:"max_results,omitempty"`
+	SearchSpaces []*VectorSearchSpace `json:"search_spaces,omitempty"`
+	Strategy     string               `json:"strategy,omitempty"`
+	Threshold    float64              `json:"threshold,omitempty"`
+}
+
+// VectorSearchInfo represents vector store info for a notebook
+type VectorSearchInfo struct {
+	DatasetID       string `json:"dataset_id,omitempty"`
+	Dimensions      int    `json:"dimensions,omitempty"`
+	DocumentCount   int    `json:"document_count,omitempty"`
+	IndexType       string `json:"index_type,omitempty"`
+	IndexingEnabled bool   `json:"indexing_enabled,omitempty"`
+	LastUpdated     string `json:"last_updated,omitempty"`
+	NotebookID      string `json:"notebook_id,omitempty"`
+	SpaceID         string `json:"space_id,omitempty"`
+	StorageSize     int64  `json:"storage_size,omitempty"`
+	TenantID        string `json:"tenant_id,omitempty"`
+	VectorCount     int64  `json:"vector_count,omitempty"`
+}
+
+// VectorSearchSpace represents a notebook/collection that an agent searches
+type VectorSearchSpace struct {
+	AddedAt      *time.Time             `json:"added_at,omitempty"`
+	AddedBy      string                 `json:"added_by,omitempty"`
+	Filters      map[string]interface{} `json:"filters,omitempty"`
+	NotebookID   string                 `json:"notebook_id"`
+	NotebookName string                 `json:"notebook_name,omitempty"`
+	SearchWeight float64                `json:"search_weight,omitempty"`
+}
+
+// WebhookDelivery is one event sent, or to be sent, to a subscription, with
+// the outcome of its latest attempt
+type WebhookDelivery struct {
+	Attempts    int64      `json:"attempts,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+	DurationMs  int64      `json:"duration_ms,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	Event       string     `json:"event,omitempty"`
+	EventID     string     `json:"event_id,omitempty"`
+	// Sent as X-Webhook-ID; the same on every attempt
+	ID             string     `json:"id,omitempty"`
+	LastAttemptAt  *time.Time `json:"last_attempt_at,omitempty"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	ResponseBody   string     `json:"response_body,omitempty"`
+	ResponseStatus int64      `json:"response_status,omitempty"`
+	Status         string     `json:"status,omitempty"`
+	SubscriptionID string     `json:"subscription_id,omitempty"`
+	Test           bool       `json:"test,omitempty"`
+}
+
+// WebhookDeliveryList is a page of a subscription's deliveries, newest first
+type WebhookDeliveryList struct {
+	Deliveries []*WebhookDelivery `json:"deliveries,omitempty"`
+	HasMore    bool               `json:"has_more,omitempty"`
+	Limit      int                `json:"limit,omitempty"`
+	Offset     int                `json:"offset,omitempty"`
+}
+
+// WebhookSubscription delivers events of a space to a URL, signed with the
+// subscription's secret
+type WebhookSubscription struct {
+	Active         bool       `json:"active,omitempty"`
+	CreatedAt      *time.Time `json:"created_at,omitempty"`
+	CreatedBy      string     `json:"created_by,omitempty"`
+	Description    string     `json:"description,omitempty"`
+	Events         []string   `json:"events,omitempty"`
+	ID             string     `json:"id,omitempty"`
+	LastDeliveryAt *time.Time `json:"last_delivery_at,omitempty"`
+	LastStatus     string     `json:"last_status,omitempty"`
+	Secret         string     `json:"secret,omitempty"`
+	SpaceID        string     `json:"space_id,omitempty"`
+	UpdatedAt      *time.Time `json:"updated_at,omitempty"`
+	URL            string     `json:"url,omitempty"`
+}
+
+// WebhookSubscriptionCreateRequest subscribes a URL to events of the current
+// space
+type WebhookSubscriptionCreateRequest struct {
+	Description string   `json:"description,omitempty"`
+	Events      []string `json:"events"`
+	URL         string   `json:"url"`
+}
+
+// WebhookSubscriptionList is the webhook subscriptions of a space
+type WebhookSubscriptionList struct {
+	Subscriptions []*WebhookSubscription `json:"subscriptions,omitempty"`
+}
+
+// WebhookSubscriptionUpdateRequest changes a webhook subscription. Omitted
+// fields are left as they are.
+type WebhookSubscriptionUpdateRequest struct {
+	Active      bool     `json:"active,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Events      []string `json:"events,omitempty"`
+	URL         string   `json:"url,omitempty"`
+}
+
+// Workflow represents an automated workflow in the system
+type Workflow struct {
+	// milliseconds
+	AverageRuntime float64                `json:"average_runtime,omitempty"`
+	Configuration  map[string]interface{} `json:"configuration,omitempty"`
+	CreatedAt      *time.Time             `json:"created_at,omitempty"`
+	CreatedBy      string                 `json:"created_by,omitempty"`
+	Description    string                 `json:"description,omitempty"`
+	ExecutionCount int64                  `json:"execution_count,omitempty"`
+	ID             string                 `json:"id,omitempty"`
+	LastExecuted   *time.Time             `json:"last_executed,omitempty"`
+	Name           string                 `json:"name,omitempty"`
+	OrganizationID string                 `json:"organization_id,omitempty"`
+	// active, paused, disabled
+	Status string `json:"status,omitempty"`
+	// Stored as separate nodes
+	Steps []*WorkflowStep `json:"steps,omitempty"`
+	// percentage
+	SuccessRate float64 `json:"success_rate,omitempty"`
+	TenantID    string  `json:"tenant_id,omitempty"`
+	// Stored as separate nodes
+	Triggers []*WorkflowTrigger `json:"triggers,omitempty"`
+	// document_processing, compliance_check, approval_chain
+	Type      string     `json:"type,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	Version   string     `json:"version,omitempty"`
+}
+
+// WorkflowAnalytics represents workflow performance analytics
+type WorkflowAnalytics struct {
+	ActiveWorkflows int `json:"active_workflows,omitempty"`
+	// milliseconds
+	AverageRuntime   float64    `json:"average_runtime,omitempty"`
+	CreatedAt        *time.Time `json:"created_at,omitempty"`
+	Date             *time.Time `json:"date,omitempty"`
+	FailedExecutions int64      `json:"failed_executions,omitempty"`
+	ID               string     `json:"id,omitempty"`
+	OrganizationID   string     `json:"organization_id,omitempty"`
+	// daily, weekly, monthly
+	Period string `json:"period,omitempty"`
+	// trigger_type -> count
+	PopularTriggerTypes  map[string]int64 `json:"popular_trigger_types,omitempty"`
+	SuccessfulExecutions int64            `json:"successful_executions,omitempty"`
+	TenantID             string           `json:"tenant_id,omitempty"`
+	TotalExecutions      int64            `json:"total_executions,omitempty"`
+	// milliseconds
+	TotalProcessingTime float64 `json:"total_processing_time,omitempty"`
+	TotalWorkflows      int     `json:"total_workflows,omitempty"`
+	// workflow_id -> success_rate
+	WorkflowPerformance map[string]float64 `json:"workflow_performance,omitempty"`
+}
+
+// WorkflowExecution represents a single execution of a workflow
+type WorkflowExecution struct {
+	CompletedAt    *time.Time             `json:"completed_at,omitempty"`
+	CurrentStep    string                 `json:"current_step,omitempty"`
+	ErrorMessage   string                 `json:"error_message,omitempty"`
+	ID             string                 `json:"id,omitempty"`
+	Input          map[string]interface{} `json:"input,omitempty"`
+	OrganizationID string                 `json:"organization_id,omitempty"`
+	Output         map[string]interface{} `json:"output,omitempty"`
+	// 0-100%
+	Progress float64 `json:"progress,omitempty"`
+	// milliseconds
+	Runtime   float64    `json:"runtime,omitempty"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	// running, completed, failed, cancelled
+	Status string `json:"status,omitempty"`
+	// Stored as separate nodes
+	StepResults []*StepResult `json:"step_results,omitempty"`
+	TenantID    string        `json:"tenant_id,omitempty"`
+	TriggerID   string        `json:"trigger_id,omitempty"`
+	WorkflowID  string        `json:"workflow_id,omitempty"`
+}
+
+// WorkflowStep represents a single step in a workflow
+type WorkflowStep struct {
+	// Conditional execution rules
+	Conditions    map[string]interface{} `json:"conditions,omitempty"`
+	Configuration map[string]interface{} `json:"configuration,omitempty"`
+	CreatedAt     *time.Time             `json:"created_at,omitempty"`
+	ID            string                 `json:"id,omitempty"`
+	Name          string                 `json:"name,omitempty"`
+	// step ID or "abort"
+	OnFailure string `json:"on_failure,omitempty"`
+	// next step ID or "complete"
+	OnSuccess  string `json:"on_success,omitempty"`
+	Order      int    `json:"order,omitempty"`
+	RetryCount int    `json:"retry_count,omitempty"`
+	// seconds
+	Timeout int `json:"timeout,omitempty"`
+	// process_document, compliance_check, approval, notification, ai_analysis
+	Type       string `json:"type,omitempty"`
+	WorkflowID string `json:"workflow_id,omitempty"`
+}
+
+// WorkflowTrigger represents a trigger that can start a workflow
+type WorkflowTrigger struct {
+	Configuration map[string]interface{} `json:"configuration,omitempty"`
+	CreatedAt     *time.Time             `json:"created_at,omitempty"`
+	ID            string                 `json:"id,omitempty"`
+	IsActive      bool                   `json:"is_active,omitempty"`
+	LastTriggered *time.Time             `json:"last_triggered,omitempty"`
+	Name          string                 `json:"name,omitempty"`
+	TriggerCount  int64                  `json:"trigger_count,omitempty"`
+	// upload, schedule, api, webhook, manual
+	Type       string `json:"type,omitempty"`
+	WorkflowID string `json:"workflow_id,omitempty"`
+}
+
+// AbortUpload calls DELETE /api/v1/uploads/{id}.
+//
+// Abort a resumable upload. Cancel a resumable upload, discarding its stored
+// parts and its document.
+func (c *Client) AbortUpload(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/uploads/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// AddKnowledgeSource calls POST /api/v1/agents/{id}/knowledge-sources.
+//
+// Add knowledge source. Link a notebook as a knowledge source for an agent
+func (c *Client) AddKnowledgeSource(ctx context.Context, id string, body VectorSearchSpace) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/agents/"+url.PathEscape(id)+"/knowledge-sources", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AddSpaceMember calls POST /api/v1/spaces/{id}/members.
+//
+// Add space member. Invite a user to a space with a specific role
+func (c *Client) AddSpaceMember(ctx context.Context, id string, body AddMemberRequest) (*SpaceMemberResponse, error) {
+	out := new(SpaceMemberResponse)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/spaces/"+url.PathEscape(id)+"/members", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AskNotebook calls POST /api/v1/notebooks/{id}/ask.
+//
+// Ask a notebook. Answer a one-off question from the documents of a notebook
+// without creating an agent. The notebook's chunks most relevant to the
+// question are retrieved, by vector search or, when it is unavailable, by
+// keyword, and the configured model (QA_MODEL) answers from them through the
+// LLM router, citing them by number. The response lists the cited chunks as
+// sources, in the order they are numbered. When no chunk matches, the answer
+// says so and the model is not called. Fails with 503 when the LLM router is
+// not enabled.
+func (c *Client) AskNotebook(ctx context.Context, id string, body NotebookAskRequest) (*NotebookAskResponse, error) {
+	out := new(NotebookAskResponse)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/notebooks/"+url.PathEscape(id)+"/ask", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AudiModalProcessingWebhook calls POST
+// /webhooks/audimodal/processing-complete.
+//
+// AudiModal processing webhook. Webhook endpoint for AudiModal to notify when
+// document processing is complete. Deliveries are signed with
+// AUDIMODAL_WEBHOOK_SECRET: X-Webhook-Signature is "v1=" and the hex
+// HMAC-SHA256 of "<X-Webhook-ID>.<X-Webhook-Timestamp>.<body>".
+func (c *Client) AudiModalProcessingWebhook(ctx context.Context, body map[string]interface{}) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, http.MethodPost, "/webhooks/audimodal/processing-complete", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Batch calls POST /api/v1/batch.
+//
+// Run a batch of requests. Run up to the configured number of API requests in
+// order, each with the caller's credentials and space headers. Every operation
+// gets its own status and body; a failed operation does not stop the ones
+// after it. With async set the batch runs as a job: the response is 202 with
+// the job, whose result holds the batch response once it has finished.
+func (c *Client) Batch(ctx context.Context, body BatchRequest) (*BatchResponse, error) {
+	out := new(BatchResponse)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/batch", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ChatCompletions calls POST /api/v1/router/chat/completions.
+//
+// Chat completion. OpenAI-compatible chat completion, proxied to the LLM
+// router service
+func (c *Client) ChatCompletions(ctx context.Context, body map[string]interface{}) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/router/chat/completions", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CheckConsistencyParams are the query parameters of CheckConsistency. Zero
+// values are not sent unless the parameter is required.
+type CheckConsistencyParams struct {
+	// Inconsistencies listed per entity type (max 100)
+	Limit int `query:"limit"`
+}
+
+// CheckConsistency calls GET /api/v1/admin/consistency.
+//
+// Check graph consistency. Compare the space and tenant IDs stored on
+// notebooks and users with their relationships. At most limit inconsistencies
+// are listed per entity type; truncated reports that more exist.
+func (c *Client) CheckConsistency(ctx context.Context, params *CheckConsistencyParams) (*ConsistencyCheckResult, error) {
+	out := new(ConsistencyCheckResult)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/admin/consistency", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CleanupOrphansParams are the query parameters of CleanupOrphans. Zero values
+// are not sent unless the parameter is required.
+type CleanupOrphansParams struct {
+	// Count the orphans without deleting them
+	DryRun bool `query:"dry_run"`
+}
+
+// CleanupOrphans calls POST /api/v1/admin/orphans/cleanup.
+//
+// Clean up orphaned entities. Delete personal spaces no user owns and
+// processing retry jobs whose document no longer exists. A dry run only counts
+// them.
+func (c *Client) CleanupOrphans(ctx context.Context, params *CleanupOrphansParams) (*OrphanCleanupResult, error) {
+	out := new(OrphanCleanupResult)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/admin/orphans/cleanup", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CollectLegalHoldParams are the query parameters of CollectLegalHold. Zero
+// values are not sent unless the parameter is required.
+type CollectLegalHoldParams struct {
+	// Page size (max 100)
+	Limit int `query:"limit"`
+	// Page offset
+	Offset int `query:"offset"`
+}
+
+// CollectLegalHold calls POST /api/v1/admin/legal-holds/{hold_id}/collect.
+//
+// Collect held documents. Search the documents an active legal hold covers,
+// including those in the trash, for any of the keywords (whole words, ignoring
+// case, in the name, description, tags or extracted text, decrypted for spaces
+// that encrypt text) uploaded in the time range, which includes from and
+// excludes to. Without criteria every held document matches. Returns one page
+// of matches, oldest first, with the keywords each contains and the total. A
+// released hold responds 409 with AETHER-HOLD-003.
+func (c *Client) CollectLegalHold(ctx context.Context, holdID string, params *CollectLegalHoldParams, body LegalHoldCriteria) (*LegalHoldCollection, error) {
+	out := new(LegalHoldCollection)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/admin/legal-holds/"+url.PathEscape(holdID)+"/collect", params, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CompareDocuments calls POST /api/v1/documents/compare.
+//
+// Compare documents. Diff the extracted text of two documents, or two versions
+// of one, line by line. The response lists the changed lines in hunks with
+// context_lines unchanged lines around them (3 by default, at most 20) and a
+// similarity score from 0 to 1, the share of the texts' characters they have
+// in common. Version 0, or no version, is the document's current text. Earlier
+// versions keep their text only when they were processed before being
+// replaced, and are not compared for users the notebook's redaction rules
+// apply to. Texts over COMPARE_MAX_TEXT_BYTES or COMPARE_MAX_LINES are refused
+// with 422 AETHER-DOC-010. Diffs are cached for COMPARE_CACHE_SECONDS.
+func (c *Client) CompareDocuments(ctx context.Context, body DocumentCompareRequest) (*DocumentCompareResponse, error) {
+	out := new(DocumentCompareResponse)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/documents/compare", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CompleteUpload calls POST /api/v1/uploads/{id}/complete.
+//
+// Complete a resumable upload. Assemble the parts of a resumable upload into
+// the document's file and submit the document for processing. Fails with 409
+// and AETHER-UPLOAD-004 while parts are missing, listing them in
+// details.missing_parts.
+func (c *Client) CompleteUpload(ctx context.Context, id string) (*DocumentResponse, error) {
+	out := new(DocumentResponse)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/uploads/"+url.PathEscape(id)+"/complete", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Completions calls POST /api/v1/router/completions.
+//
+// Text completion. OpenAI-compatible text completion, proxied to the LLM
+// router service
+func (c *Client) Completions(ctx context.Context, body map[string]interface{}) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/router/completions", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ConfirmUpload calls POST /api/v1/documents/{id}/confirm-upload.
+//
+// Confirm a direct upload. Check the file stored for a direct upload against
+// the size, MIME type and SHA-256 checksum declared for it, and submit the
+// document for processing. Fails with 409 and AETHER-UPLOAD-005 when the file
+// has not been stored yet, and with 400 and AETHER-UPLOAD-006 when it does not
+// match; the mismatched file is deleted and can be stored again while the
+// upload URL is valid. Fails with 409 and AETHER-UPLOAD-002 when the upload
+// was confirmed already or has expired.
+func (c *Client) ConfirmUpload(ctx context.Context, id string) (*DocumentResponse, error) {
+	out := new(DocumentResponse)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/documents/"+url.PathEscape(id)+"/confirm-upload", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateAgent calls POST /api/v1/agents.
+//
+// Create agent. Create a new agent with Neo4j relationship management and
+// agent-builder proxy
+func (c *Client) CreateAgent(ctx context.Context, body AgentCreateRequest) (*AgentResponse, error) {
+	out := new(AgentResponse)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/agents", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateClassificationPolicy calls POST /api/v1/classification-policies.
+//
+// Create a classification policy. Create a classification policy in the
+// current space. Once a document of the space is processed, every enabled
+// policy whose conditions all hold for it is applied, in priority order
+// (lowest first): it can move the document to another notebook of the space,
+// tag it, or restrict it to its owner and the space's owners and admins.
+// Conditions test the AudiModal category, content type, language and PII
+// detection, and the document's MIME type, extension, name and tags. Fails
+// with 400 and AETHER-POLICY-002 when a condition lacks its value, the policy
+// has no actions or the target notebook is not in the space. Requires the
+// owner or admin role.
+func (c *Client) CreateClassificationPolicy(ctx context.Context, body ClassificationPolicyCreateRequest) (*ClassificationPolicy, error) {
+	out := new(ClassificationPolicy)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/classification-policies", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateDocument calls POST /api/v1/documents.
+//
+// Create a new document record. Create a document record in Neo4j, typically
+// after external upload to AudiModal
+func (c *Client) CreateDocument(ctx context.Context, body DocumentCreateRequest) (*DocumentResponse, error) {
+	out := new(DocumentResponse)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/documents", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateExperiment calls POST /api/v1/ml/experiments.
+//
+// Create ML experiment. Create a new machine learning experiment
+func (c *Client) CreateExperiment(ctx context.Context, body CreateExperimentRequest) (*MLExperiment, error) {
+	out := new(MLExperiment)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/ml/experiments", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateInboundEmail calls POST /api/v1/notebooks/{id}/inbound-email.
+//
+// Create notebook inbound email address. Give a notebook a new inbound email
+// address, replacing the one it had. The body and attachments of mail sent to
+// the address are added to the notebook as documents named after the subject,
+// owned by the notebook's owner. allowed_senders limits who may send, by
+// address or @domain. Only the notebook's owner manages its address. Fails
+// with 503 and AETHER-EMAIL-001 when inbound email is not configured.
+func (c *Client) CreateInboundEmail(ctx context.Context, id string, body InboundEmailRequest) (*InboundEmailAddress, error) {
+	out := new(InboundEmailAddress)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/notebooks/"+url.PathEscape(id)+"/inbound-email", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateLegalHold calls POST /api/v1/admin/legal-holds.
+//
+// Create a legal hold. Place users and notebooks of a tenant under an
+// e-discovery hold. While the hold is active, the documents of its notebooks
+// and the documents and notebooks its users own cannot be edited, versioned,
+// deleted or purged from the trash (409 with AETHER-HOLD-001), and the trash
+// keeps them past its retention; organizations with a hold in their spaces
+// cannot be deleted. Unknown users or notebooks respond 400 listing them. The
+// hold is recorded in the audit log.
+func (c *Client) CreateLegalHold(ctx context.Context, body LegalHoldCreateRequest) (*LegalHold, error) {
+	out := new(LegalHold)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/admin/legal-holds", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateModel calls POST /api/v1/ml/models.
+//
+// Create ML model. Create a new machine learning model
+func (c *Client) CreateModel(ctx context.Context, body CreateMLModelRequest) (*MLModel, error) {
+	out := new(MLModel)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/ml/models", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateNotebook calls POST /api/v1/notebooks.
+//
+// Create notebook. Create a new notebook
+func (c *Client) CreateNotebook(ctx context.Context, body NotebookCreateRequest) (*NotebookResponse, error) {
+	out := new(NotebookResponse)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/notebooks", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateNotebookFeedToken calls POST /api/v1/notebooks/{id}/feed-tokens.
+//
+// Create a notebook activity feed token. Create a token for the RSS and Atom
+// feeds of a notebook's activity. The response holds the token and the feed
+// URLs, which embed it; the token is not shown again. Feeds stop working when
+// the token is revoked or its creator loses access to the notebook.
+func (c *Client) CreateNotebookFeedToken(ctx context.Context, id string) (*FeedTokenCreateResponse, error) {
+	out := new(FeedTokenCreateResponse)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/notebooks/"+url.PathEscape(id)+"/feed-tokens", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateOrganization calls POST /api/v1/organizations.
+//
+// Create a new organization. Create a new organization with the provided
+// information
+func (c *Client) CreateOrganization(ctx context.Context, body OrganizationCreateRequest) (*OrganizationResponse, error) {
+	out := new(OrganizationResponse)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/organizations", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateOrganizationRole calls POST /api/v1/organizations/{id}/roles.
+//
+// Create organization role. Define a custom role of an organization granting
+// the listed actions. Members can then be invited with or moved to the role.
+func (c *Client) CreateOrganizationRole(ctx context.Context, id string, body RoleCreateRequest) (*Role, error) {
+	out := new(Role)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/organizations/"+url.PathEscape(id)+"/roles", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateReportSchedule calls POST /api/v1/reports/schedules.
+//
+// Create a report schedule. Schedule a report of the current space, rendered
+// to PDF or CSV at the end of every UTC day, week from Monday or calendar
+// month. processing_summary counts the documents uploaded, processed and
+// failed in the period per notebook; storage_usage lists the documents and
+// bytes stored per notebook at its end; agent_cost lists each agent's
+// executions and cost since the previous report. Each report is stored for
+// download, announced in the app to notify_user_ids (the creator when omitted)
+// and emailed as an attachment to email_to. A space has at most 20 schedules.
+// Fails with 400 when email_to is given but email is not configured, and with
+// 503 when storage is unavailable. Requires the owner or admin role.
+func (c *Client) CreateReportSchedule(ctx context.Context, body ReportScheduleCreateRequest) (*ReportSchedule, error) {
+	out := new(ReportSchedule)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/reports/schedules", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateS3Watcher calls POST /api/v1/notebooks/{id}/s3-watchers.
+//
+// Create notebook S3 watcher. Watch an S3 prefix and add its new objects to
+// the notebook as documents owned by the notebook's owner. With queue_url the
+// bucket's ObjectCreated notifications are read from the SQS queue, directly
+// or through SNS; without it the prefix is listed for objects modified since
+// the last run. Only objects created after the watcher are added. include and
+// exclude are globs matched against keys relative to the prefix, where "**"
+// spans path segments and a pattern without "/" matches the file name;
+// excludes win. An object already added with the same ETag is not added again.
+// Only the notebook's owner manages its watchers, at most 10.
+func (c *Client) CreateS3Watcher(ctx context.Context, id string, body S3WatcherCreateRequest) (*S3Watcher, error) {
+	out := new(S3Watcher)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/notebooks/"+url.PathEscape(id)+"/s3-watchers", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateScheduledJobsFeedToken calls POST /api/v1/admin/jobs/feed-tokens.
+//
+// Create a scheduled jobs calendar token. Create a token for the iCal feed of
+// the background jobs' upcoming and recent runs. The response holds the token
+// and the calendar URL, which embeds it; the token is not shown again.
+func (c *Client) CreateScheduledJobsFeedToken(ctx context.Context) (*FeedTokenCreateResponse, error) {
+	out := new(FeedTokenCreateResponse)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/admin/jobs/feed-tokens", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateSpace calls POST /api/v1/spaces.
+//
+// Create space. Create a new space (organization space)
+func (c *Client) CreateSpace(ctx context.Context, body SpaceCreateRequest) (*SpaceResponse, error) {
+	out := new(SpaceResponse)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/spaces", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateStreamSource calls POST /api/v1/streams/sources.
+//
+// Create stream source. Create a new live data stream source
+func (c *Client) CreateStreamSource(ctx context.Context, body CreateStreamSourceRequest) (*StreamSource, error) {
+	out := new(StreamSource)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/streams/sources", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateTeam calls POST /api/v1/teams.
+//
+// Create a new team. Create a new team with the provided information
+func (c *Client) CreateTeam(ctx context.Context, body TeamCreateRequest) (*TeamResponse, error) {
+	out := new(TeamResponse)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/teams", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateUploadIntent calls POST /api/v1/notebooks/{id}/upload-intents.
+//
+// Start a direct upload. Start an upload the client stores in object storage
+// itself, so the file does not pass through the API. The document is created
+// in status uploading, and the response gives a presigned URL to PUT the file
+// to, with the headers to send, valid for an hour. Declare the file's size,
+// MIME type and hex SHA-256 checksum; once stored, confirm the upload with
+// POST /documents/{id}/confirm-upload. Files over MAX_DIRECT_UPLOAD_BYTES or
+// the space's size limit fail with AETHER-DOC-003. A declared MIME type that
+// contradicts the file's extension fails with 422 and AETHER-FILE-001, and a
+// type the deployment or the space does not allow with 415 and
+// AETHER-FILE-002. Documents of uploads not confirmed in time are deleted.
+func (c *Client) CreateUploadIntent(ctx context.Context, id string, body UploadIntentCreateRequest) (*UploadIntent, error) {
+	out := new(UploadIntent)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/notebooks/"+url.PathEscape(id)+"/upload-intents", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateWebhookSubscription calls POST /api/v1/webhook-subscriptions.
+//
+// Create a webhook subscription. Subscribe a URL to events of the current
+// space: document.processed, document.failed, member.added, agent.executed and
+// quota.threshold_reached. Each event is POSTed as JSON with X-Webhook-ID,
+// X-Webhook-Event, X-Webhook-Timestamp and X-Webhook-Signature headers; the
+// signature is "v1=" and the hex HMAC-SHA256 of "<id>.<timestamp>.<body>"
+// keyed with the subscription's secret, which is only returned here. A
+// delivery not answered with 2xx is retried with exponential backoff from 30
+// seconds up to an hour, keeping its X-Webhook-ID. The URL must be http or
+// https and, unless the server allows private targets, resolve to public
+// addresses only; it fails with 400 and AETHER-HOOK-005 otherwise. A space has
+// at most 20 subscriptions. Requires the owner or admin role.
+func (c *Client) CreateWebhookSubscription(ctx context.Context, body WebhookSubscriptionCreateRequest) (*WebhookSubscription, error) {
+	out := new(WebhookSubscription)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/webhook-subscriptions", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateWorkflow calls POST /api/v1/workflows.
+//
+// Create workflow. Create a new automated workflow
+func (c *Client) CreateWorkflow(ctx context.Context, body CreateWorkflowRequest) (*Workflow, error) {
+	out := new(Workflow)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/workflows", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DeleteAgent calls DELETE /api/v1/agents/{id}.
+//
+// Delete agent. Delete an agent from both Neo4j and agent-builder
+func (c *Client) DeleteAgent(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/agents/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// DeleteClassificationPolicy calls DELETE
+// /api/v1/classification-policies/{id}.
+//
+// Delete a classification policy. Delete a classification policy. Documents it
+// already moved, tagged or restricted are left as they are. Requires the owner
+// or admin role.
+func (c *Client) DeleteClassificationPolicy(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/classification-policies/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// DeleteCurrentUser calls DELETE /api/v1/users/me.
+//
+// Delete current user account. Delete the currently authenticated user account
+// (soft delete)
+func (c *Client) DeleteCurrentUser(ctx context.Context) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/users/me", nil, nil, nil)
+}
+
+// DeleteDocument calls DELETE /api/v1/documents/{id}.
+//
+// Delete document. Move a document to the trash. It can be restored from
+// /api/v1/trash until it is purged, with its files, TRASH_RETENTION_DAYS after
+// deletion.
+func (c *Client) DeleteDocument(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/documents/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// DeleteExperiment calls DELETE /api/v1/ml/experiments/{id}.
+//
+// Delete ML experiment. Delete an ML experiment
+func (c *Client) DeleteExperiment(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/ml/experiments/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// DeleteInboundEmail calls DELETE /api/v1/notebooks/{id}/inbound-email.
+//
+// Delete notebook inbound email address. Remove the inbound email address of a
+// notebook; mail sent to it is rejected from then on.
+func (c *Client) DeleteInboundEmail(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/notebooks/"+url.PathEscape(id)+"/inbound-email", nil, nil, nil)
+}
+
+// DeleteModel calls DELETE /api/v1/ml/models/{id}.
+//
+// Delete ML model. Delete an ML model
+func (c *Client) DeleteModel(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/ml/models/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// DeleteNotebook calls DELETE /api/v1/notebooks/{id}.
+//
+// Delete notebook. Move a notebook to the trash. It can be restored from
+// /api/v1/trash until it is purged, with its documents, TRASH_RETENTION_DAYS
+// after deletion.
+func (c *Client) DeleteNotebook(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/notebooks/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// DeleteOrganization calls DELETE /api/v1/organizations/{id}.
+//
+// Delete organization. Delete an organization (only organization owners can
+// delete organizations). Deleting a large organization can take a while; with
+// "Prefer: respond-async" the delete runs as an organization_delete job and
+// the response is 202 with the job.
+func (c *Client) DeleteOrganization(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/organizations/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// DeleteOrganizationRole calls DELETE /api/v1/organizations/{id}/roles/{role}.
+//
+// Delete organization role. Delete a custom role of an organization. Roles
+// still held by members are refused; move the members to another role first.
+func (c *Client) DeleteOrganizationRole(ctx context.Context, id string, role string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/organizations/"+url.PathEscape(id)+"/roles/"+url.PathEscape(role), nil, nil, nil)
+}
+
+// DeleteRedactionRules calls DELETE /api/v1/notebooks/{id}/redaction.
+//
+// Delete notebook redaction rules. Remove the redaction rules of a notebook;
+// everyone it is shared with reads its documents whole from then on.
+func (c *Client) DeleteRedactionRules(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/notebooks/"+url.PathEscape(id)+"/redaction", nil, nil, nil)
+}
+
+// DeleteReportSchedule calls DELETE /api/v1/reports/schedules/{id}.
+//
+// Delete a report schedule. Delete a report schedule of the current space with
+// the reports it generated. Fails with 404 and AETHER-REPORT-001 when the
+// schedule does not exist. Requires the owner or admin role.
+func (c *Client) DeleteReportSchedule(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/reports/schedules/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// DeleteS3Watcher calls DELETE /api/v1/notebooks/{id}/s3-watchers/{watcherId}.
+//
+// Delete notebook S3 watcher. Stop watching an S3 prefix. Documents the
+// watcher added stay in the notebook.
+func (c *Client) DeleteS3Watcher(ctx context.Context, id string, watcherID string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/notebooks/"+url.PathEscape(id)+"/s3-watchers/"+url.PathEscape(watcherID), nil, nil, nil)
+}
+
+// DeleteSpace calls DELETE /api/v1/spaces/{id}.
+//
+// Delete space. Delete a space (soft delete)
+func (c *Client) DeleteSpace(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/spaces/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// DeleteStreamSource calls DELETE /api/v1/streams/sources/{id}.
+//
+// Delete stream source. Delete a stream source
+func (c *Client) DeleteStreamSource(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/streams/sources/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// DeleteTeam calls DELETE /api/v1/teams/{id}.
+//
+// Delete team. Delete a team (only team owners can delete teams)
+func (c *Client) DeleteTeam(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/teams/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// DeleteWebhookSubscription calls DELETE /api/v1/webhook-subscriptions/{id}.
+//
+// Delete a webhook subscription. Delete a webhook subscription of the current
+// space with its delivery log; queued deliveries are not sent. Fails with 404
+// and AETHER-HOOK-004 when the subscription does not exist. Requires the owner
+// or admin role.
+func (c *Client) DeleteWebhookSubscription(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/webhook-subscriptions/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// DeleteWorkflow calls DELETE /api/v1/workflows/{id}.
+//
+// Delete workflow. Delete a workflow
+func (c *Client) DeleteWorkflow(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/workflows/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// DeployModel calls POST /api/v1/ml/models/{id}/deploy.
+//
+// Deploy ML model. Deploy an ML model to production
+func (c *Client) DeployModel(ctx context.Context, id string) (*MLModel, error) {
+	out := new(MLModel)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/ml/models/"+url.PathEscape(id)+"/deploy", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DownloadDocumentParams are the query parameters of DownloadDocument. Zero
+// values are not sent unless the parameter is required.
+type DownloadDocumentParams struct {
+	// auto, stream or redirect
+	Mode string `query:"mode"`
+}
+
+// DownloadDocument calls GET /api/v1/documents/{id}/download.
+//
+// Download document. Download document file content. The file is streamed from
+// storage. A single byte range may be asked for with a Range header, answered
+// with 206 and Content-Range (a range past the end fails with 416); If-Range
+// limits it to the file with that ETag. Responses carry the file's ETag, and
+// If-None-Match with it answers 304 while the file is unchanged. With
+// mode=redirect, or in the default auto mode for files of
+// DOWNLOAD_REDIRECT_BYTES or more, the response is a 307 redirect to a
+// presigned storage URL valid for DOWNLOAD_URL_SECONDS; mode=stream always
+// streams. Users the document's notebook is shared with as viewers or
+// commenters download a PDF rendition of its text instead, watermarked on
+// every page with their email and the time of the download; the notebook's
+// redaction rules apply to it. The X-Rendition header is watermarked on such
+// responses. The original file is restricted to the document's owner, the
+// notebook's editors and the space's owners and admins.
+func (c *Client) DownloadDocument(ctx context.Context, id string, params *DownloadDocumentParams) (io.ReadCloser, error) {
+	resp, err := c.stream(ctx, http.MethodGet, "/api/v1/documents/"+url.PathEscape(id)+"/download", params)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// DownloadDocumentVersion calls GET
+// /api/v1/documents/{id}/versions/{version}/download.
+//
+// Download a document version. Download the file of a version of a document.
+// Fails with 404 and AETHER-DOC-006 when the document has no such version, and
+// with 403 and AETHER-DOC-008 for users the document's notebook is shared with
+// as viewers or commenters, who download watermarked renditions of the current
+// version instead.
+func (c *Client) DownloadDocumentVersion(ctx context.Context, id string, version string) (io.ReadCloser, error) {
+	resp, err := c.stream(ctx, http.MethodGet, "/api/v1/documents/"+url.PathEscape(id)+"/versions/"+url.PathEscape(version)+"/download", nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// DownloadReport calls GET /api/v1/reports/runs/{id}/download.
+//
+// Download a generated report. Download the PDF or CSV file of a generated
+// report. Fails with 404 and AETHER-REPORT-002 when the report does not exist
+// or failed to generate. Requires the owner or admin role.
+func (c *Client) DownloadReport(ctx context.Context, id string) (io.ReadCloser, error) {
+	resp, err := c.stream(ctx, http.MethodGet, "/api/v1/reports/runs/"+url.PathEscape(id)+"/download", nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// EndMaintenance calls DELETE /api/v1/admin/maintenance.
+//
+// End system-wide maintenance. End the system-wide maintenance; tenant
+// maintenance windows stay in progress. Fails with 404 when none is in
+// progress.
+func (c *Client) EndMaintenance(ctx context.Context) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/admin/maintenance", nil, nil, nil)
+}
+
+// EndTenantMaintenance calls DELETE
+// /api/v1/admin/maintenance/tenants/{tenant_id}.
+//
+// End tenant maintenance. End the maintenance of one tenant. Fails with 404
+// when none is in progress for the tenant.
+func (c *Client) EndTenantMaintenance(ctx context.Context, tenantID string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/admin/maintenance/tenants/"+url.PathEscape(tenantID), nil, nil, nil)
+}
+
+// EvaluateClassificationPolicies calls POST
+// /api/v1/classification-policies/evaluate.
+//
+// Evaluate classification policies. Dry run: report what the space's enabled
+// policies, or the unsaved policy given, would do to the documents given or to
+// the processed documents of a notebook (up to 100). Nothing is changed.
+// Requires the owner or admin role.
+func (c *Client) EvaluateClassificationPolicies(ctx context.Context, body ClassificationEvaluateRequest) (*ClassificationEvaluateResponse, error) {
+	out := new(ClassificationEvaluateResponse)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/classification-policies/evaluate", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ExecuteAgent calls POST /api/v1/agents/{id}/execute.
+//
+// Execute agent. Execute an agent with type-specific processing (Q&A,
+// Conversational, Producer)
+func (c *Client) ExecuteAgent(ctx context.Context, id string, body AgentExecuteRequest) (*AgentExecuteResponse, error) {
+	out := new(AgentExecuteResponse)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/agents/"+url.PathEscape(id)+"/execute", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ExecuteWorkflow calls POST /api/v1/workflows/{id}/execute.
+//
+// Execute workflow. Manually execute a workflow
+func (c *Client) ExecuteWorkflow(ctx context.Context, id string, body ExecuteWorkflowRequest) (*WorkflowExecution, error) {
+	out := new(WorkflowExecution)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/workflows/"+url.PathEscape(id)+"/execute", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ExportAuditEventsParams are the query parameters of ExportAuditEvents. Zero
+// values are not sent unless the parameter is required.
+type ExportAuditEventsParams struct {
+	// csv or json
+	Format string `query:"format"`
+	// Actor user ID
+	ActorID string `query:"actor_id"`
+	// Resource type
+	ResourceType string `query:"resource_type"`
+	// Resource ID
+	ResourceID string `query:"resource_id"`
+	// Action
+	Action string `query:"action"`
+	// Space ID
+	SpaceID string `query:"space_id"`
+	// Organization ID, including its spaces
+	OrganizationID string `query:"organization_id"`
+	// Earliest event time (RFC3339, inclusive)
+	From string `query:"from"`
+	// Latest event time (RFC3339, exclusive)
+	To string `query:"to"`
+}
+
+// ExportAuditEvents calls GET /api/v1/admin/audit/export.
+//
+// Export audit events. Stream matching audit events, oldest first, as CSV or
+// newline-delimited JSON
+func (c *Client) ExportAuditEvents(ctx context.Context, params *ExportAuditEventsParams) (io.ReadCloser, error) {
+	resp, err := c.stream(ctx, http.MethodGet, "/api/v1/admin/audit/export", params)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// ExportNotebookDocuments calls GET /api/v1/notebooks/{id}/documents/export.
+//
+// Export notebook documents. Stream document metadata of a notebook as
+// newline-delimited JSON
+func (c *Client) ExportNotebookDocuments(ctx context.Context, id string) (*NDJSONStream[DocumentResponse], error) {
+	resp, err := c.stream(ctx, http.MethodGet, "/api/v1/notebooks/"+url.PathEscape(id)+"/documents/export", nil)
+	if err != nil {
+		return nil, err
+	}
+	return newNDJSONStream[DocumentResponse](resp.Body), nil
+}
+
+// ExportOrganizationAuditEventsParams are the query parameters of
+// ExportOrganizationAuditEvents. Zero values are not sent unless the parameter
+// is required.
+type ExportOrganizationAuditEventsParams struct {
+	// csv or json
+	Format string `query:"format"`
+}
+
+// ExportOrganizationAuditEvents calls GET
+// /api/v1/organizations/{id}/audit/export.
+//
+// Export organization audit events. Stream audit events of an organization and
+// its spaces, oldest first, as CSV or newline-delimited JSON. Requires the
+// organization owner or admin role.
+func (c *Client) ExportOrganizationAuditEvents(ctx context.Context, id string, params *ExportOrganizationAuditEventsParams) (io.ReadCloser, error) {
+	resp, err := c.stream(ctx, http.MethodGet, "/api/v1/organizations/"+url.PathEscape(id)+"/audit/export", params)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// ExportSpaceAuditEventsParams are the query parameters of
+// ExportSpaceAuditEvents. Zero values are not sent unless the parameter is
+// required.
+type ExportSpaceAuditEventsParams struct {
+	// csv or json
+	Format string `query:"format"`
+}
+
+// ExportSpaceAuditEvents calls GET /api/v1/spaces/{id}/audit/export.
+//
+// Export space audit events. Stream audit events of a space, oldest first, as
+// CSV or newline-delimited JSON. Requires the space admin role.
+func (c *Client) ExportSpaceAuditEvents(ctx context.Context, id string, params *ExportSpaceAuditEventsParams) (io.ReadCloser, error) {
+	resp, err := c.stream(ctx, http.MethodGet, "/api/v1/spaces/"+url.PathEscape(id)+"/audit/export", params)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// FailoverTenant calls POST /api/v1/admin/tenants/{tenant_id}/failover.
+//
+// Fail over tenant. Make the tenant's passive region, or target_region, the
+// one that serves it; the former active region becomes the passive one. The
+// replication hooks in REGION_REPLICATION_HOOKS are called first, in order, to
+// promote the tenant's Neo4j and S3 replicas; when one fails the tenant is not
+// failed over and the request fails with 502 and AETHER-REGION-005, unless
+// force is set. The failover is recorded in the audit log.
+func (c *Client) FailoverTenant(ctx context.Context, tenantID string, body TenantFailoverRequest) (*TenantFailover, error) {
+	out := new(TenantFailover)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/admin/tenants/"+url.PathEscape(tenantID)+"/failover", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetAgent calls GET /api/v1/agents/{id}.
+//
+// Get agent. Retrieve an agent by ID with access control
+func (c *Client) GetAgent(ctx context.Context, id string) (*AgentResponse, error) {
+	out := new(AgentResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/agents/"+url.PathEscape(id), nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetAgentStats calls GET /api/v1/stats/agents/{id}.
+//
+// Get agent statistics. Retrieve statistics and metrics for a specific agent
+func (c *Client) GetAgentStats(ctx context.Context, id string) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/stats/agents/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetAnalyticsParams are the query parameters of GetAnalytics. Zero values are
+// not sent unless the parameter is required.
+type GetAnalyticsParams struct {
+	// Analytics period
+	Period string `query:"period"`
+}
+
+// GetAnalytics calls GET /api/v1/ml/analytics.
+//
+// Get ML analytics. Get ML performance analytics for the current tenant
+func (c *Client) GetAnalytics(ctx context.Context, params *GetAnalyticsParams) (*MLPerformanceMetrics, error) {
+	out := new(MLPerformanceMetrics)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/ml/analytics", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetAvailableStrategies calls GET /api/v1/strategies.
+//
+// List chunking strategies. Get the chunking strategies AudiModal supports
+func (c *Client) GetAvailableStrategies(ctx context.Context) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/strategies", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetCapabilities calls GET /api/v1/router/capabilities.
+//
+// List LLM router capabilities. Proxied to the LLM router service
+func (c *Client) GetCapabilities(ctx context.Context) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/router/capabilities", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetChunk calls GET /api/v1/files/{file_id}/chunks/{chunk_id}.
+//
+// Get chunk. Get one chunk of a file
+func (c *Client) GetChunk(ctx context.Context, fileID string, chunkID string) (*ChunkResponse, error) {
+	out := new(ChunkResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/files/"+url.PathEscape(fileID)+"/chunks/"+url.PathEscape(chunkID), nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetClassificationPolicy calls GET /api/v1/classification-policies/{id}.
+//
+// Get a classification policy. Get a classification policy of the current
+// space.
+func (c *Client) GetClassificationPolicy(ctx context.Context, id string) (*ClassificationPolicy, error) {
+	out := new(ClassificationPolicy)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/classification-policies/"+url.PathEscape(id), nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetCurrentUser calls GET /api/v1/users/me.
+//
+// Get current user profile. Get the profile of the currently authenticated
+// user
+func (c *Client) GetCurrentUser(ctx context.Context) (*UserResponse, error) {
+	out := new(UserResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/users/me", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetDocument calls GET /api/v1/documents/{id}.
+//
+// Get document by ID. Get document details by ID
+func (c *Client) GetDocument(ctx context.Context, id string) (*DocumentResponse, error) {
+	out := new(DocumentResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/documents/"+url.PathEscape(id), nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetDocumentAnalysis calls GET /api/v1/documents/{id}/analysis.
+//
+// Get document ML analysis. Get ML analysis summary (entities, sentiment,
+// topics) for a document
+func (c *Client) GetDocumentAnalysis(ctx context.Context, id string) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/documents/"+url.PathEscape(id)+"/analysis", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetDocumentChunksParams are the query parameters of GetDocumentChunks. Zero
+// values are not sent unless the parameter is required.
+type GetDocumentChunksParams struct {
+	// Page size (max 100)
+	Limit int `query:"limit"`
+	// Page offset
+	Offset int `query:"offset"`
+}
+
+// GetDocumentChunks calls GET /api/v1/documents/{id}/chunks.
+//
+// List document chunks. Get a page of the chunks the processing service
+// produced for a document, in the document's space and with the same access
+// checks as GET /api/v1/documents/{id}: restricted documents and documents
+// held for moderation are readable only by their owner and the space's owners
+// and admins. Users the document's notebook is shared with at a redacted role
+// don't get the chunks its redaction rules omit; redacted counts those left
+// out of the page.
+func (c *Client) GetDocumentChunks(ctx context.Context, id string, params *GetDocumentChunksParams) (*ChunkListResponse, error) {
+	out := new(ChunkListResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/documents/"+url.PathEscape(id)+"/chunks", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetDocumentContentParams are the query parameters of GetDocumentContent.
+// Zero values are not sent unless the parameter is required.
+type GetDocumentContentParams struct {
+	// Characters to skip
+	Offset int `query:"offset"`
+	// Characters to return (max 200000)
+	Limit int `query:"limit"`
+	// Response format
+	Format string `query:"format"`
+}
+
+// GetDocumentContent calls GET /api/v1/documents/{id}/content.
+//
+// Get the extracted text of a document. Serve the text extracted from a
+// document one page at a time: limit characters from offset, with the total
+// length and the offset of the next page. With format=text, or a Range header,
+// the whole text is served as text/plain instead, honouring byte ranges (206
+// Partial Content), If-Range and If-None-Match against its ETag. Users the
+// document's notebook is shared with at a redacted role get the text of the
+// chunks its redaction rules keep, with redacted set (X-Redacted-Chunks as
+// plain text). Documents the user cannot read fail as GET
+// /api/v1/documents/{id} does.
+func (c *Client) GetDocumentContent(ctx context.Context, id string, params *GetDocumentContentParams) (*DocumentContentPage, error) {
+	out := new(DocumentContentPage)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/documents/"+url.PathEscape(id)+"/content", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetDocumentExtractedText calls GET /api/v1/documents/{id}/text.
+//
+// Get extracted text for a document. Fetches the extracted text content from
+// the processing provider that processed the document, AudiModal by default.
+// Users the document's notebook is shared with at a redacted role get the text
+// of the chunks its redaction rules keep, with redacted set and the number of
+// chunks left out in redacted_chunks.
+func (c *Client) GetDocumentExtractedText(ctx context.Context, id string) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/documents/"+url.PathEscape(id)+"/text", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetDocumentGraphParams are the query parameters of GetDocumentGraph. Zero
+// values are not sent unless the parameter is required.
+type GetDocumentGraphParams struct {
+	// Relationships to follow
+	Depth int `query:"depth"`
+	// Page size (max 100)
+	Limit int `query:"limit"`
+	// Page offset
+	Offset int `query:"offset"`
+}
+
+// GetDocumentGraph calls GET /api/v1/documents/{id}/graph.
+//
+// Get document graph. Get one page of the notebooks, documents, spaces and
+// users within depth relationships of a document, nearest first. Depth is
+// capped by NEO4J_MAX_TRAVERSAL_DEPTH; traversals estimated to touch too many
+// rows are refused with 422.
+func (c *Client) GetDocumentGraph(ctx context.Context, id string, params *GetDocumentGraphParams) (*DocumentGraphResponse, error) {
+	out := new(DocumentGraphResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/documents/"+url.PathEscape(id)+"/graph", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetDocumentPipeline calls GET /api/v1/documents/{id}/pipeline.
+//
+// Get document pipeline timings. Get the upload, AudiModal submit, processing
+// and indexing durations of a document
+func (c *Client) GetDocumentPipeline(ctx context.Context, id string) (*DocumentPipeline, error) {
+	out := new(DocumentPipeline)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/documents/"+url.PathEscape(id)+"/pipeline", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetDocumentStatus calls GET /api/v1/documents/{id}/status.
+//
+// Get document processing status. Get real-time processing status and progress
+// for a document
+func (c *Client) GetDocumentStatus(ctx context.Context, id string) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/documents/"+url.PathEscape(id)+"/status", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetDocumentSummary calls GET /api/v1/documents/{id}/summary.
+//
+// Get document summary. Get the abstract of a document, written by the
+// configured model (SUMMARY_MODEL) from its extracted text. The abstract is
+// cached on the document; one that does not exist yet is written now, counting
+// against the space's monthly summary token budget. stale is set when the
+// document was reprocessed after the abstract was written. Fails with 409 and
+// AETHER-SUMMARY-002 when the document has no extracted text, and with 429 and
+// AETHER-SUMMARY-001 when the budget is spent.
+func (c *Client) GetDocumentSummary(ctx context.Context, id string) (*Summary, error) {
+	out := new(Summary)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/documents/"+url.PathEscape(id)+"/summary", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetDocumentURLParams are the query parameters of GetDocumentURL. Zero values
+// are not sent unless the parameter is required.
+type GetDocumentURLParams struct {
+	// URL expiration in seconds
+	Expires int `query:"expires"`
+}
+
+// GetDocumentURL calls GET /api/v1/documents/{id}/url.
+//
+// Get document URL. Get a presigned URL for direct document access
+func (c *Client) GetDocumentURL(ctx context.Context, id string, params *GetDocumentURLParams) (map[string]string, error) {
+	var out map[string]string
+	if err := c.do(ctx, http.MethodGet, "/api/v1/documents/"+url.PathEscape(id)+"/url", params, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetDocumentVectorSync calls GET /api/v1/documents/{id}/vector-sync.
+//
+// Get document vector sync status. Get whether the chunk embeddings of a
+// document are stored in its space's DeepLake namespace. Status is none (not
+// queued), pending, syncing, synced, failed (attempts used up) or deleting.
+func (c *Client) GetDocumentVectorSync(ctx context.Context, id string) (*DocumentVectorSync, error) {
+	out := new(DocumentVectorSync)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/documents/"+url.PathEscape(id)+"/vector-sync", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetEntity calls GET /api/v1/entities/{id}.
+//
+// Get entity. Get a person, organization, topic, location or date with its
+// aliases and the number of documents mentioning it.
+func (c *Client) GetEntity(ctx context.Context, id string) (*Entity, error) {
+	out := new(Entity)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/entities/"+url.PathEscape(id), nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetEnumerations calls GET /api/v1/i18n/enumerations.
+//
+// Get enumeration labels. Get the labels of the values of document_status,
+// notebook_status, notebook_visibility, agent_status, role, share_role and
+// job_status, by enumeration and value, in the locale negotiated from
+// Accept-Language. The locale is the first of the client's languages, by
+// quality, that is supported or whose base language is (de for de-AT), and
+// English otherwise; it is returned in Content-Language. Labels missing from a
+// locale fall back to English.
+func (c *Client) GetEnumerations(ctx context.Context) (*EnumerationsResponse, error) {
+	out := new(EnumerationsResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/i18n/enumerations", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetExperiment calls GET /api/v1/ml/experiments/{id}.
+//
+// Get ML experiment. Get a specific ML experiment by ID
+func (c *Client) GetExperiment(ctx context.Context, id string) (*MLExperiment, error) {
+	out := new(MLExperiment)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/ml/experiments/"+url.PathEscape(id), nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetExperimentsParams are the query parameters of GetExperiments. Zero values
+// are not sent unless the parameter is required.
+type GetExperimentsParams struct {
+	// Number of experiments to return
+	Limit int `query:"limit"`
+	// Number of experiments to skip
+	Offset int `query:"offset"`
+}
+
+// GetExperiments calls GET /api/v1/ml/experiments.
+//
+// Get ML experiments. Get a list of ML experiments for the current tenant
+func (c *Client) GetExperiments(ctx context.Context, params *GetExperimentsParams) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/ml/experiments", params, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetFeatureFlags calls GET /api/v1/features.
+//
+// Get feature flags. Get the feature flags of the runtime configuration
+func (c *Client) GetFeatureFlags(ctx context.Context) (*FeatureFlagsResponse, error) {
+	out := new(FeatureFlagsResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/features", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetFileChunksParams are the query parameters of GetFileChunks. Zero values
+// are not sent unless the parameter is required.
+type GetFileChunksParams struct {
+	// Number of chunks to return
+	Limit int `query:"limit"`
+	// Number of chunks to skip
+	Offset int `query:"offset"`
+}
+
+// GetFileChunks calls GET /api/v1/files/{file_id}/chunks.
+//
+// List file chunks. Get the chunks AudiModal produced for a file. Users the
+// file's notebook is shared with at a redacted role don't get the chunks its
+// redaction rules omit; redacted counts those left out of the page.
+func (c *Client) GetFileChunks(ctx context.Context, fileID string, params *GetFileChunksParams) (*ChunkListResponse, error) {
+	out := new(ChunkListResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/files/"+url.PathEscape(fileID)+"/chunks", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetHealth calls GET /api/v1/router/health.
+//
+// LLM router health. Proxied to the LLM router service
+func (c *Client) GetHealth(ctx context.Context) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/router/health", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetInboundEmail calls GET /api/v1/notebooks/{id}/inbound-email.
+//
+// Get notebook inbound email address. Get the inbound email address of a
+// notebook. Fails with 404 and AETHER-EMAIL-002 when it has none.
+func (c *Client) GetInboundEmail(ctx context.Context, id string) (*InboundEmailAddress, error) {
+	out := new(InboundEmailAddress)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/notebooks/"+url.PathEscape(id)+"/inbound-email", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetJobStatus calls GET /api/v1/jobs/{id}.
+//
+// Get job status. Get the status, progress and, once finished, the result or
+// error of an async job started by the caller. IDs of AudiModal processing
+// jobs are also accepted and reported as document_processing jobs.
+func (c *Client) GetJobStatus(ctx context.Context, id string) (*Job, error) {
+	out := new(Job)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/jobs/"+url.PathEscape(id), nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetKnowledgeGraphParams are the query parameters of GetKnowledgeGraph. Zero
+// values are not sent unless the parameter is required.
+type GetKnowledgeGraphParams struct {
+	// Type of the root node
+	RootType string `query:"root_type"`
+	// ID of the root node
+	RootID string `query:"root_id"`
+	// Relationships to follow
+	Depth int `query:"depth"`
+	// Page size (max 100)
+	Limit int `query:"limit"`
+	// Page or expansion token; replaces root_type, root_id and depth
+	Token string `query:"token"`
+}
+
+// GetKnowledgeGraph calls GET /api/v1/graph.
+//
+// Get knowledge graph. Get one page of the documents, notebooks, people,
+// organizations and topics within depth relationships of a document, notebook
+// or entity, nearest first, with the typed edges (BELONGS_TO, CONTAINS,
+// MENTIONS, SIMILAR_TO) between the root and the page's nodes. Nodes the user
+// may not see, such as documents of another user's private notebook, are left
+// out and paths do not pass through them. Pass next_page_token as token for
+// the next page, or a node's expand_token to centre the view on that node.
+// Depth is capped by NEO4J_MAX_TRAVERSAL_DEPTH; traversals estimated to touch
+// too many rows are refused with 422.
+func (c *Client) GetKnowledgeGraph(ctx context.Context, params *GetKnowledgeGraphParams) (*GraphResponse, error) {
+	out := new(GraphResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/graph", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetKnowledgeSources calls GET /api/v1/agents/{id}/knowledge-sources.
+//
+// Get agent knowledge sources. Retrieve all knowledge sources configured for
+// an agent
+func (c *Client) GetKnowledgeSources(ctx context.Context, id string) ([]*VectorSearchSpace, error) {
+	var out []*VectorSearchSpace
+	if err := c.do(ctx, http.MethodGet, "/api/v1/agents/"+url.PathEscape(id)+"/knowledge-sources", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetLegalHold calls GET /api/v1/admin/legal-holds/{hold_id}.
+//
+// Get a legal hold. Get a legal hold with the users and notebooks it holds.
+func (c *Client) GetLegalHold(ctx context.Context, holdID string) (*LegalHold, error) {
+	out := new(LegalHold)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/admin/legal-holds/"+url.PathEscape(holdID), nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetLiveEvent calls GET /api/v1/streams/events/{id}.
+//
+// Get live event. Get a specific live event by ID
+func (c *Client) GetLiveEvent(ctx context.Context, id string) (*LiveEvent, error) {
+	out := new(LiveEvent)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/streams/events/"+url.PathEscape(id), nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetLiveEventsParams are the query parameters of GetLiveEvents. Zero values
+// are not sent unless the parameter is required.
+type GetLiveEventsParams struct {
+	// Number of events to return
+	Limit int `query:"limit"`
+	// Number of events to skip
+	Offset int `query:"offset"`
+	// Comma-separated list of source IDs to filter by
+	SourceIDs string `query:"source_ids"`
+	// Comma-separated list of event types to filter by
+	EventTypes string `query:"event_types"`
+	// Comma-separated list of media types to filter by
+	MediaTypes string `query:"media_types"`
+	// Comma-separated list of sentiments to filter by
+	Sentiments string `query:"sentiments"`
+	// Minimum confidence score to filter by
+	MinConfidence float64 `query:"min_confidence"`
+	// Filter expression, e.g. type:alert AND confidence:>=0.8
+	Filter string `query:"filter"`
+}
+
+// GetLiveEvents calls GET /api/v1/streams/events.
+//
+// Get live events. Get a list of live events with optional filtering. The
+// filter parameter takes an expression of field:value terms combined with AND,
+// OR, NOT and parentheses, e.g. type:alert AND confidence:>=0.8 AND
+// processed:>2024-01-01. Fields are type, source, media, sentiment, content,
+// confidence, score, processed and occurred; the numbers and dates also take
+// >, >=, < and <=.
+func (c *Client) GetLiveEvents(ctx context.Context, params *GetLiveEventsParams) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/streams/events", params, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetMaintenanceStatus calls GET /api/v1/maintenance.
+//
+// Get maintenance status. Get the maintenance in progress system-wide and,
+// when the request names a space, for the space's tenant, to show a banner.
+// While a window is active, writes are rejected with 503 and AETHER-MAINT-001
+// and responses carry X-Maintenance-Mode with its scope; reads and downloads
+// keep working.
+func (c *Client) GetMaintenanceStatus(ctx context.Context) (*MaintenanceStatus, error) {
+	out := new(MaintenanceStatus)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/maintenance", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetModel calls GET /api/v1/ml/models/{id}.
+//
+// Get ML model. Get a specific ML model by ID
+func (c *Client) GetModel(ctx context.Context, id string) (*MLModel, error) {
+	out := new(MLModel)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/ml/models/"+url.PathEscape(id), nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetModelsParams are the query parameters of GetModels. Zero values are not
+// sent unless the parameter is required.
+type GetModelsParams struct {
+	// Number of models to return
+	Limit int `query:"limit"`
+	// Number of models to skip
+	Offset int `query:"offset"`
+}
+
+// GetModels calls GET /api/v1/ml/models.
+//
+// Get ML models. Get a list of ML models for the current tenant
+func (c *Client) GetModels(ctx context.Context, params *GetModelsParams) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/ml/models", params, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetNotebook calls GET /api/v1/notebooks/{id}.
+//
+// Get notebook by ID. Get notebook details by ID
+func (c *Client) GetNotebook(ctx context.Context, id string) (*NotebookResponse, error) {
+	out := new(NotebookResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/notebooks/"+url.PathEscape(id), nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetNotebookDigest calls GET /api/v1/notebooks/{id}/digest.
+//
+// Get a notebook digest subscription. Get the caller's subscription to a
+// notebook's digest, with when the next digest is due and the error of the
+// last one, if any. Fails with 404 and AETHER-FEED-003 when the caller is not
+// subscribed.
+func (c *Client) GetNotebookDigest(ctx context.Context, id string) (*NotebookDigestSubscription, error) {
+	out := new(NotebookDigestSubscription)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/notebooks/"+url.PathEscape(id)+"/digest", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetNotebookGraphParams are the query parameters of GetNotebookGraph. Zero
+// values are not sent unless the parameter is required.
+type GetNotebookGraphParams struct {
+	// Documents per page (max 100)
+	Limit int `query:"limit"`
+	// Page token
+	Token string `query:"token"`
+}
+
+// GetNotebookGraph calls GET /api/v1/notebooks/{id}/graph.
+//
+// Get notebook graph. Get one page of the documents of a notebook the user may
+// see, ordered by name, with the entities they mention most (up to limit) and
+// the edges between them and the notebook for visualization: BELONGS_TO,
+// MENTIONS, and SIMILAR_TO between documents of the page with their similarity
+// score. Pass next_page_token as token for the next page, or a node's
+// expand_token to /graph to centre a view on that node.
+func (c *Client) GetNotebookGraph(ctx context.Context, id string, params *GetNotebookGraphParams) (*GraphResponse, error) {
+	out := new(GraphResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/notebooks/"+url.PathEscape(id)+"/graph", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetNotebookSummary calls GET /api/v1/notebooks/{id}/summary.
+//
+// Get notebook summary. Get the digest of a notebook, written by the
+// configured model from the abstracts, or the start of the text, of its
+// processed documents. The digest is cached on the notebook; one that does not
+// exist yet is written now, counting against the space's monthly summary token
+// budget. stale is set when documents were added, removed, reprocessed or
+// summarized again after the digest was written. Fails with 409 and
+// AETHER-SUMMARY-002 when the notebook has no processed documents.
+func (c *Client) GetNotebookSummary(ctx context.Context, id string) (*Summary, error) {
+	out := new(Summary)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/notebooks/"+url.PathEscape(id)+"/summary", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetOnboardingStatus calls GET /api/v1/users/me/onboarding.
+//
+// Get onboarding status. Check if user onboarding is complete and get default
+// resources
+func (c *Client) GetOnboardingStatus(ctx context.Context) (*OnboardingResult, error) {
+	out := new(OnboardingResult)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/users/me/onboarding", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetOpenAPISpec calls GET /api/v1/openapi.json.
+//
+// Get OpenAPI document. Get the OpenAPI 3.1 document describing this API
+func (c *Client) GetOpenAPISpec(ctx context.Context) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/openapi.json", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetOptimalStrategy calls POST /api/v1/strategies/recommend.
+//
+// Recommend chunking strategy. Recommend a chunking strategy for a file's
+// content type and size
+func (c *Client) GetOptimalStrategy(ctx context.Context, body StrategyRecommendationRequest) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/strategies/recommend", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetOrganization calls GET /api/v1/organizations/{id}.
+//
+// Get organization by ID. Get a specific organization by its ID
+func (c *Client) GetOrganization(ctx context.Context, id string) (*OrganizationResponse, error) {
+	out := new(OrganizationResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/organizations/"+url.PathEscape(id), nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetOrganizationMembersParams are the query parameters of
+// GetOrganizationMembers. Zero values are not sent unless the parameter is
+// required.
+type GetOrganizationMembersParams struct {
+	// Page size (max 100)
+	Limit int `query:"limit"`
+	// Page offset
+	Offset int `query:"offset"`
+}
+
+// GetOrganizationMembers calls GET /api/v1/organizations/{id}/members.
+//
+// Get organization members. Get a page of the members of a specific
+// organization, oldest first
+func (c *Client) GetOrganizationMembers(ctx context.Context, id string, params *GetOrganizationMembersParams) (*OrganizationMemberListResponse, error) {
+	out := new(OrganizationMemberListResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/organizations/"+url.PathEscape(id)+"/members", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetOrganizations calls GET /api/v1/organizations.
+//
+// Get user organizations. Get all organizations the current user is a member
+// of
+func (c *Client) GetOrganizations(ctx context.Context) ([]*OrganizationResponse, error) {
+	var out []*OrganizationResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/organizations", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetPipelineSummaryParams are the query parameters of GetPipelineSummary.
+// Zero values are not sent unless the parameter is required.
+type GetPipelineSummaryParams struct {
+	// How far back to look, as a Go duration (max 720h)
+	Window string `query:"window"`
+	// Only summarize this tenant
+	TenantID string `query:"tenant_id"`
+	// Number of slowest documents to return (max 100)
+	Slowest int `query:"slowest"`
+}
+
+// GetPipelineSummary calls GET /api/v1/admin/pipeline.
+//
+// Get pipeline timing summary. Get per-tenant percentiles of each pipeline
+// stage for recently uploaded documents, and the slowest documents
+func (c *Client) GetPipelineSummary(ctx context.Context, params *GetPipelineSummaryParams) (*PipelineSummaryResponse, error) {
+	out := new(PipelineSummaryResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/admin/pipeline", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetProvider calls GET /api/v1/router/providers/{name}.
+//
+// Get LLM provider. Proxied to the LLM router service
+func (c *Client) GetProvider(ctx context.Context, name string) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/router/providers/"+url.PathEscape(name), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetProviders calls GET /api/v1/router/providers.
+//
+// List LLM providers. Proxied to the LLM router service
+func (c *Client) GetProviders(ctx context.Context) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/router/providers", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetRealtimeAnalytics calls GET /api/v1/streams/analytics/realtime.
+//
+// Get real-time analytics. Get current real-time analytics and performance
+// metrics
+func (c *Client) GetRealtimeAnalytics(ctx context.Context) (*StreamAnalytics, error) {
+	out := new(StreamAnalytics)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/streams/analytics/realtime", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetRedactionRules calls GET /api/v1/notebooks/{id}/redaction.
+//
+// Get notebook redaction rules. Get the redaction rules of a notebook. Fails
+// with 404 and AETHER-REDACT-001 when it has none. Only the notebook's owner
+// reads its rules.
+func (c *Client) GetRedactionRules(ctx context.Context, id string) (*RedactionRules, error) {
+	out := new(RedactionRules)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/notebooks/"+url.PathEscape(id)+"/redaction", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetRelatedDocumentsParams are the query parameters of GetRelatedDocuments.
+// Zero values are not sent unless the parameter is required.
+type GetRelatedDocumentsParams struct {
+	// SIMILAR_TO relationships to follow (max 3)
+	Depth int `query:"depth"`
+	// Documents to return (max 100)
+	Limit int `query:"limit"`
+}
+
+// GetRelatedDocuments calls GET /api/v1/documents/{id}/related.
+//
+// Get related documents. Get the documents within depth SIMILAR_TO
+// relationships of a document, best first. Each processed or updated document
+// is linked to up to 10 of its best ranked similar documents (see
+// /documents/{id}/similar) scoring at least 0.2; a document never linked is
+// linked on the first request. The score of a document found further away is
+// the product of the scores along the best path to it, and paths only pass
+// through documents the user may see. Depth is capped at 3 and by
+// NEO4J_MAX_TRAVERSAL_DEPTH; traversals estimated to touch too many rows are
+// refused with 422.
+func (c *Client) GetRelatedDocuments(ctx context.Context, id string, params *GetRelatedDocumentsParams) (*RelatedDocumentsResponse, error) {
+	out := new(RelatedDocumentsResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/documents/"+url.PathEscape(id)+"/related", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetReportSchedule calls GET /api/v1/reports/schedules/{id}.
+//
+// Get a report schedule. Get a report schedule of the current space. Fails
+// with 404 and AETHER-REPORT-001 when the schedule does not exist. Requires
+// the owner or admin role.
+func (c *Client) GetReportSchedule(ctx context.Context, id string) (*ReportSchedule, error) {
+	out := new(ReportSchedule)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/reports/schedules/"+url.PathEscape(id), nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetReportingStatus calls GET /api/v1/admin/reporting.
+//
+// Get reporting projection status. Get the number of logged domain events and
+// projected rows
+func (c *Client) GetReportingStatus(ctx context.Context) (*ReportingStatus, error) {
+	out := new(ReportingStatus)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/admin/reporting", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetRuntimeConfig calls GET /api/v1/admin/config.
+//
+// Get runtime configuration. Get the configuration that can be changed without
+// a restart
+func (c *Client) GetRuntimeConfig(ctx context.Context) (*RuntimeConfigResponse, error) {
+	out := new(RuntimeConfigResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/admin/config", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetS3WatchSetup calls GET /api/v1/s3-watchers/setup.
+//
+// Get S3 watcher setup. Get the principal watchers assume customer roles as
+// and the external ID of the space's tenant. A role given to a watcher must
+// trust the principal on the condition sts:ExternalId equals the external ID,
+// and allow s3:ListBucket and s3:GetObject on the watched prefix, plus
+// sqs:ReceiveMessage and sqs:DeleteMessage on the queue when one is given.
+// Fails with 503 and AETHER-WATCH-001 when S3 watchers are not enabled.
+func (c *Client) GetS3WatchSetup(ctx context.Context) (*S3WatchSetup, error) {
+	out := new(S3WatchSetup)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/s3-watchers/setup", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetS3Watcher calls GET /api/v1/notebooks/{id}/s3-watchers/{watcherId}.
+//
+// Get notebook S3 watcher. Get an S3 watcher of a notebook. Fails with 404 and
+// AETHER-WATCH-002 when the notebook has no such watcher.
+func (c *Client) GetS3Watcher(ctx context.Context, id string, watcherID string) (*S3Watcher, error) {
+	out := new(S3Watcher)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/notebooks/"+url.PathEscape(id)+"/s3-watchers/"+url.PathEscape(watcherID), nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetSLOReport calls GET /api/v1/admin/slo.
+//
+// Get SLO compliance. Get the SLIs, remaining error budget and burn rates of
+// this replica over the compliance window
+func (c *Client) GetSLOReport(ctx context.Context) (*SLOReport, error) {
+	out := new(SLOReport)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/admin/slo", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetSimilarDocumentsParams are the query parameters of GetSimilarDocuments.
+// Zero values are not sent unless the parameter is required.
+type GetSimilarDocumentsParams struct {
+	// Documents to return (max 50)
+	Limit int `query:"limit"`
+}
+
+// GetSimilarDocuments calls GET /api/v1/documents/{id}/similar.
+//
+// Get similar documents. Get the documents of the space most related to a
+// document, best first. Documents are related by the vector similarity of
+// their contents, shared tags and graph proximity (same notebook, shared
+// referenced entities); each result reports these signals and the combined
+// score. Without vector search documents are related by tags and the graph
+// alone. Rankings are cached per document for an hour and recomputed when the
+// document is updated or reprocessed.
+func (c *Client) GetSimilarDocuments(ctx context.Context, id string, params *GetSimilarDocumentsParams) (*SimilarDocumentsResponse, error) {
+	out := new(SimilarDocumentsResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/documents/"+url.PathEscape(id)+"/similar", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetSpace calls GET /api/v1/spaces/{id}.
+//
+// Get space by ID. Get space details by ID
+func (c *Client) GetSpace(ctx context.Context, id string) (*SpaceFullResponse, error) {
+	out := new(SpaceFullResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/spaces/"+url.PathEscape(id), nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetSpaceUsage calls GET /api/v1/spaces/{id}/usage.
+//
+// Get space usage. Get the documents, storage, and this month's agent
+// executions and stream events of a space against its quotas. Uploads over the
+// document or storage quota fail with 402 and AETHER-QUOTA-001 or
+// AETHER-QUOTA-002; executions and stream events over the monthly quota fail
+// with 429 and AETHER-QUOTA-003 or AETHER-QUOTA-004 until resets_at.
+func (c *Client) GetSpaceUsage(ctx context.Context, id string) (*SpaceQuotaUsage, error) {
+	out := new(SpaceQuotaUsage)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/spaces/"+url.PathEscape(id)+"/usage", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetSpaces calls GET /api/v1/spaces.
+//
+// Get user spaces. Get all spaces accessible to the current user
+func (c *Client) GetSpaces(ctx context.Context) (*SpaceListResponse, error) {
+	out := new(SpaceListResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/spaces", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetStreamAnalyticsParams are the query parameters of GetStreamAnalytics.
+// Zero values are not sent unless the parameter is required.
+type GetStreamAnalyticsParams struct {
+	// Analytics period
+	Period string `query:"period"`
+}
+
+// GetStreamAnalytics calls GET /api/v1/streams/analytics.
+//
+// Get stream analytics. Get stream performance analytics for the current
+// tenant
+func (c *Client) GetStreamAnalytics(ctx context.Context, params *GetStreamAnalyticsParams) (*StreamAnalytics, error) {
+	out := new(StreamAnalytics)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/streams/analytics", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetStreamSource calls GET /api/v1/streams/sources/{id}.
+//
+// Get stream source. Get a specific stream source by ID
+func (c *Client) GetStreamSource(ctx context.Context, id string) (*StreamSource, error) {
+	out := new(StreamSource)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/streams/sources/"+url.PathEscape(id), nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetStreamSourcesParams are the query parameters of GetStreamSources. Zero
+// values are not sent unless the parameter is required.
+type GetStreamSourcesParams struct {
+	// Number of sources to return
+	Limit int `query:"limit"`
+	// Number of sources to skip
+	Offset int `query:"offset"`
+}
+
+// GetStreamSources calls GET /api/v1/streams/sources.
+//
+// Get stream sources. Get a list of stream sources for the current tenant
+func (c *Client) GetStreamSources(ctx context.Context, params *GetStreamSourcesParams) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/streams/sources", params, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetSummaryUsage calls GET /api/v1/summaries/usage.
+//
+// Get summary usage. Get the tokens the current space has spent on summaries
+// this calendar month (UTC), its budget (SUMMARY_MONTHLY_TOKEN_BUDGET, 0 when
+// unlimited) and when the budget renews.
+func (c *Client) GetSummaryUsage(ctx context.Context) (*SummaryUsage, error) {
+	out := new(SummaryUsage)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/summaries/usage", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetSyntheticReport calls GET /api/v1/admin/synthetic.
+//
+// Get synthetic probe results. Get the last success, latency and error of the
+// login, upload, search and agent dry-run probes. Probes run on the leader, so
+// other replicas report no runs.
+func (c *Client) GetSyntheticReport(ctx context.Context) (*SyntheticReport, error) {
+	out := new(SyntheticReport)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/admin/synthetic", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetTeam calls GET /api/v1/teams/{id}.
+//
+// Get team by ID. Get a specific team by its ID
+func (c *Client) GetTeam(ctx context.Context, id string) (*TeamResponse, error) {
+	out := new(TeamResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/teams/"+url.PathEscape(id), nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetTeamMembers calls GET /api/v1/teams/{id}/members.
+//
+// Get team members. Get all members of a specific team
+func (c *Client) GetTeamMembers(ctx context.Context, id string) ([]*TeamMemberResponse, error) {
+	var out []*TeamMemberResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/teams/"+url.PathEscape(id)+"/members", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetTeamsParams are the query parameters of GetTeams. Zero values are not
+// sent unless the parameter is required.
+type GetTeamsParams struct {
+	// Filter by organization ID
+	OrganizationID string `query:"organization_id"`
+}
+
+// GetTeams calls GET /api/v1/teams.
+//
+// Get user teams. Get all teams the current user is a member of or can access
+func (c *Client) GetTeams(ctx context.Context, params *GetTeamsParams) ([]*TeamResponse, error) {
+	var out []*TeamResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/teams", params, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetTenantRegion calls GET /api/v1/admin/tenants/{tenant_id}/region.
+//
+// Get tenant region. Get the region that serves a tenant and the passive
+// region that holds its replicas. Fails with 404 and AETHER-REGION-003 when
+// the tenant is not pinned, in which case every region serves it.
+func (c *Client) GetTenantRegion(ctx context.Context, tenantID string) (*TenantRegion, error) {
+	out := new(TenantRegion)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/admin/tenants/"+url.PathEscape(tenantID)+"/region", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetUpload calls GET /api/v1/uploads/{id}.
+//
+// Get a resumable upload. Get a resumable upload and the parts stored so far.
+// An interrupted upload resumes by sending the parts not listed.
+func (c *Client) GetUpload(ctx context.Context, id string) (*UploadSession, error) {
+	out := new(UploadSession)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/uploads/"+url.PathEscape(id), nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetUsageAnomalies calls GET /api/v1/admin/anomalies.
+//
+// Get usage anomalies. Get the tenant upload, agent cost and error rate
+// anomalies active in the last interval and the most recently detected ones
+func (c *Client) GetUsageAnomalies(ctx context.Context) (*UsageAnomalyReport, error) {
+	out := new(UsageAnomalyReport)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/admin/anomalies", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetUsageReportParams are the query parameters of GetUsageReport. Zero values
+// are not sent unless the parameter is required.
+type GetUsageReportParams struct {
+	// First day of the period (YYYY-MM-DD)
+	From string `query:"from"`
+	// Last day of the period (YYYY-MM-DD), default today
+	To string `query:"to"`
+	// Report only this space
+	SpaceID string `query:"space_id"`
+}
+
+// GetUsageReport calls GET /api/v1/admin/reporting/usage.
+//
+// Get usage report. Total the uploads, processed, failed and deleted documents
+// per space from the reporting read models. The period defaults to the last 30
+// days.
+func (c *Client) GetUsageReport(ctx context.Context, params *GetUsageReportParams) (*UsageReport, error) {
+	out := new(UsageReport)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/admin/reporting/usage", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetUserByID calls GET /api/v1/users/{id}.
+//
+// Get user by ID. Get user profile by ID
+func (c *Client) GetUserByID(ctx context.Context, id string) (*PublicUserResponse, error) {
+	out := new(PublicUserResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/users/"+url.PathEscape(id), nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetUserSpaces calls GET /api/v1/users/me/spaces.
+//
+// Get user spaces. Get all spaces accessible to the current user
+func (c *Client) GetUserSpaces(ctx context.Context) ([]*SpaceInfo, error) {
+	var out []*SpaceInfo
+	if err := c.do(ctx, http.MethodGet, "/api/v1/users/me/spaces", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetUserStats calls GET /api/v1/users/me/stats.
+//
+// Get user statistics. Get statistics for the current user
+func (c *Client) GetUserStats(ctx context.Context) (*UserStats, error) {
+	out := new(UserStats)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/users/me/stats", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetVectorSearchInfo calls GET /api/v1/notebooks/{id}/vector-search/info.
+//
+// Get vector store info. Get information about the vector store for a notebook
+func (c *Client) GetVectorSearchInfo(ctx context.Context, id string) (*VectorSearchInfo, error) {
+	out := new(VectorSearchInfo)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/notebooks/"+url.PathEscape(id)+"/vector-search/info", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetWebhookSubscription calls GET /api/v1/webhook-subscriptions/{id}.
+//
+// Get a webhook subscription. Get a webhook subscription of the current space.
+// Fails with 404 and AETHER-HOOK-004 when the subscription does not exist.
+// Requires the owner or admin role.
+func (c *Client) GetWebhookSubscription(ctx context.Context, id string) (*WebhookSubscription, error) {
+	out := new(WebhookSubscription)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/webhook-subscriptions/"+url.PathEscape(id), nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetWorkflow calls GET /api/v1/workflows/{id}.
+//
+// Get workflow. Get a specific workflow by ID
+func (c *Client) GetWorkflow(ctx context.Context, id string) (*Workflow, error) {
+	out := new(Workflow)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/workflows/"+url.PathEscape(id), nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetWorkflowAnalyticsParams are the query parameters of GetWorkflowAnalytics.
+// Zero values are not sent unless the parameter is required.
+type GetWorkflowAnalyticsParams struct {
+	// Analytics period
+	Period string `query:"period"`
+}
+
+// GetWorkflowAnalytics calls GET /api/v1/workflows/analytics.
+//
+// Get workflow analytics. Get workflow performance analytics for the current
+// tenant
+func (c *Client) GetWorkflowAnalytics(ctx context.Context, params *GetWorkflowAnalyticsParams) (*WorkflowAnalytics, error) {
+	out := new(WorkflowAnalytics)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/workflows/analytics", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetWorkflowExecutionsParams are the query parameters of
+// GetWorkflowExecutions. Zero values are not sent unless the parameter is
+// required.
+type GetWorkflowExecutionsParams struct {
+	// Number of executions to return
+	Limit int `query:"limit"`
+	// Number of executions to skip
+	Offset int `query:"offset"`
+}
+
+// GetWorkflowExecutions calls GET /api/v1/workflows/{id}/executions.
+//
+// Get workflow executions. Get a list of executions for a specific workflow
+func (c *Client) GetWorkflowExecutions(ctx context.Context, id string, params *GetWorkflowExecutionsParams) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/workflows/"+url.PathEscape(id)+"/executions", params, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetWorkflowsParams are the query parameters of GetWorkflows. Zero values are
+// not sent unless the parameter is required.
+type GetWorkflowsParams struct {
+	// Number of workflows to return
+	Limit int `query:"limit"`
+	// Number of workflows to skip
+	Offset int `query:"offset"`
+}
+
+// GetWorkflows calls GET /api/v1/workflows.
+//
+// Get workflows. Get a list of workflows for the current tenant
+func (c *Client) GetWorkflows(ctx context.Context, params *GetWorkflowsParams) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/workflows", params, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// HealthCheck calls GET /health.
+//
+// Health check. Comprehensive health check for all dependencies
+func (c *Client) HealthCheck(ctx context.Context) (*HealthResponse, error) {
+	out := new(HealthResponse)
+	if err := c.do(ctx, http.MethodGet, "/health", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// HybridSearch calls POST /api/v1/notebooks/{id}/vector-search/hybrid.
+//
+// Hybrid vector search. Search indexed documents using both vector and text
+// search
+func (c *Client) HybridSearch(ctx context.Context, id string, body HybridSearchRequest) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/notebooks/"+url.PathEscape(id)+"/vector-search/hybrid", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// InboundEmailWebhook calls POST /webhooks/email.
+//
+// Inbound email webhook. HTTPS subscription of the SNS topic
+// INBOUND_EMAIL_SNS_TOPIC_ARN, to which an SES receipt rule publishes the mail
+// of INBOUND_EMAIL_DOMAIN. Messages are verified with the SNS signing
+// certificate and must come from the topic. A subscription confirmation is
+// confirmed. A received email is added to the notebooks it is addressed to;
+// mail that cannot be added, such as spam, mail over INBOUND_EMAIL_MAX_BYTES
+// or mail to unknown addresses, is rejected with 200 and its reason so SNS
+// does not deliver it again.
+func (c *Client) InboundEmailWebhook(ctx context.Context, body interface{}) (*InboundEmailResult, error) {
+	out := new(InboundEmailResult)
+	if err := c.do(ctx, http.MethodPost, "/webhooks/email", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// IngestEvent calls POST /api/v1/streams/sources/{id}/events.
+//
+// Ingest live event. Ingest a new live event into the system (for
+// testing/simulation)
+func (c *Client) IngestEvent(ctx context.Context, id string, body map[string]interface{}) (*LiveEvent, error) {
+	out := new(LiveEvent)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/streams/sources/"+url.PathEscape(id)+"/events", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// InitiateUpload calls POST /api/v1/notebooks/{id}/uploads.
+//
+// Start a resumable upload. Start a resumable upload of a file too large to
+// send in one request, up to MAX_RESUMABLE_UPLOAD_BYTES. The document is
+// created in status uploading. Send the file as part_count parts of part_size
+// bytes, the last one shorter, with PUT /uploads/{id}/parts/{number}, then
+// complete the upload. Uploads not completed within 24 hours are aborted. The
+// declared MIME type and size are validated as for direct uploads, failing
+// with 422 and AETHER-FILE-001 or 415 and AETHER-FILE-002.
+func (c *Client) InitiateUpload(ctx context.Context, id string, body UploadSessionCreateRequest) (*UploadSession, error) {
+	out := new(UploadSession)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/notebooks/"+url.PathEscape(id)+"/uploads", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// InviteOrganizationMember calls POST /api/v1/organizations/{id}/members.
+//
+// Invite organization member. Invite a new member to the organization by email
+func (c *Client) InviteOrganizationMember(ctx context.Context, id string, body OrganizationInviteRequest) (*OrganizationMemberResponse, error) {
+	out := new(OrganizationMemberResponse)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/organizations/"+url.PathEscape(id)+"/members", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// InviteTeamMember calls POST /api/v1/teams/{id}/members.
+//
+// Invite team member. Invite a new member to the team by email
+func (c *Client) InviteTeamMember(ctx context.Context, id string, body TeamInviteRequest) (*TeamMemberResponse, error) {
+	out := new(TeamMemberResponse)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/teams/"+url.PathEscape(id)+"/members", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LiftDocumentRestriction calls DELETE /api/v1/documents/{id}/restriction.
+//
+// Lift a document restriction. Make a document restricted by a classification
+// policy visible to every member of the space again. A policy that restricts
+// it does so again if the document is reprocessed. Requires the owner or admin
+// role.
+func (c *Client) LiftDocumentRestriction(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/documents/"+url.PathEscape(id)+"/restriction", nil, nil, nil)
+}
+
+// ListAgentsParams are the query parameters of ListAgents. Zero values are not
+// sent unless the parameter is required.
+type ListAgentsParams struct {
+	// Search query
+	Query string `query:"query"`
+	// Space ID filter
+	SpaceID string `query:"space_id"`
+	// Team ID filter
+	TeamID string `query:"team_id"`
+	// Status filter (draft, published, disabled)
+	Status string `query:"status"`
+	// Space type filter (personal, organization)
+	SpaceType string `query:"space_type"`
+	// Public filter
+	IsPublic bool `query:"is_public"`
+	// Template filter
+	IsTemplate bool `query:"is_template"`
+	// Tags filter (comma-separated)
+	Tags string `query:"tags"`
+	// Limit (default 20, max 100)
+	Limit int `query:"limit"`
+	// Offset (default 0)
+	Offset int `query:"offset"`
+}
+
+// ListAgents calls GET /api/v1/agents.
+//
+// List agents. List agents with filtering, pagination, and access control
+func (c *Client) ListAgents(ctx context.Context, params *ListAgentsParams) (*AgentListResponse, error) {
+	out := new(AgentListResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/agents", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListAuditEventsParams are the query parameters of ListAuditEvents. Zero
+// values are not sent unless the parameter is required.
+type ListAuditEventsParams struct {
+	// Actor user ID
+	ActorID string `query:"actor_id"`
+	// Resource type
+	ResourceType string `query:"resource_type"`
+	// Resource ID
+	ResourceID string `query:"resource_id"`
+	// Action, e.g. notebook.deleted
+	Action string `query:"action"`
+	// Space ID
+	SpaceID string `query:"space_id"`
+	// Organization ID, including its spaces
+	OrganizationID string `query:"organization_id"`
+	// Earliest event time (RFC3339, inclusive)
+	From string `query:"from"`
+	// Latest event time (RFC3339, exclusive)
+	To string `query:"to"`
+	// Page size
+	Limit int `query:"limit"`
+	// Page offset
+	Offset int `query:"offset"`
+}
+
+// ListAuditEvents calls GET /api/v1/admin/audit.
+//
+// List audit events. List audit events, newest first, optionally filtered
+func (c *Client) ListAuditEvents(ctx context.Context, params *ListAuditEventsParams) (*AuditEventListResponse, error) {
+	out := new(AuditEventListResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/admin/audit", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListClassificationPolicies calls GET /api/v1/classification-policies.
+//
+// List classification policies. List the classification policies of the
+// current space in the order they run.
+func (c *Client) ListClassificationPolicies(ctx context.Context) (*ClassificationPolicyListResponse, error) {
+	out := new(ClassificationPolicyListResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/classification-policies", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListDeadLettersParams are the query parameters of ListDeadLetters. Zero
+// values are not sent unless the parameter is required.
+type ListDeadLettersParams struct {
+	// Number of jobs to return
+	Limit int `query:"limit"`
+	// Number of jobs to skip
+	Offset int `query:"offset"`
+}
+
+// ListDeadLetters calls GET /api/v1/admin/dead-letters.
+//
+// List dead letters. List the document processing retry jobs that exhausted
+// their attempts, most recently failed first
+func (c *Client) ListDeadLetters(ctx context.Context, params *ListDeadLettersParams) (*DeadLetterListResponse, error) {
+	out := new(DeadLetterListResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/admin/dead-letters", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListDocumentDeletionsParams are the query parameters of
+// ListDocumentDeletions. Zero values are not sent unless the parameter is
+// required.
+type ListDocumentDeletionsParams struct {
+	// Only deletions in this status
+	Status string `query:"status"`
+	// Only deletions for this reason
+	Reason string `query:"reason"`
+	// Number of deletions to return
+	Limit int `query:"limit"`
+	// Number of deletions to skip
+	Offset int `query:"offset"`
+}
+
+// ListDocumentDeletions calls GET /api/v1/admin/deletions.
+//
+// List document deletions. List the removals of deleted documents' files,
+// chunks and vectors from AudiModal, the vector store and storage, with the
+// outcome for each target, most recently updated first. Deletions with reason
+// reprocessed recycle what a reprocessed document's earlier processing left
+// behind: the processed file and chunks it replaced and the vectors of its
+// earlier text. Completed deletions are kept for 7 days.
+func (c *Client) ListDocumentDeletions(ctx context.Context, params *ListDocumentDeletionsParams) (*DocumentDeletionListResponse, error) {
+	out := new(DocumentDeletionListResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/admin/deletions", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListDocumentEntities calls GET /api/v1/documents/{id}/entities.
+//
+// List document entities. List the people, organizations, topics, locations
+// and dates a document mentions, most mentioned first. Entities are linked
+// when the document finishes processing, from those its processor reports and,
+// for the types it reports none of, from its extracted text.
+func (c *Client) ListDocumentEntities(ctx context.Context, id string) ([]*Entity, error) {
+	var out []*Entity
+	if err := c.do(ctx, http.MethodGet, "/api/v1/documents/"+url.PathEscape(id)+"/entities", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListDocumentVersions calls GET /api/v1/documents/{id}/versions.
+//
+// List document versions. List the versions of a document's file, newest
+// first, marking the current one. A document never given a new file has only
+// version 1.
+func (c *Client) ListDocumentVersions(ctx context.Context, id string) (*DocumentVersionListResponse, error) {
+	out := new(DocumentVersionListResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/documents/"+url.PathEscape(id)+"/versions", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListDocumentsByNotebookParams are the query parameters of
+// ListDocumentsByNotebook. Zero values are not sent unless the parameter is
+// required.
+type ListDocumentsByNotebookParams struct {
+	// Results limit (max 100)
+	Limit int `query:"limit"`
+	// Results offset
+	Offset int `query:"offset"`
+}
+
+// ListDocumentsByNotebook calls GET /api/v1/notebooks/{id}/documents.
+//
+// List documents by notebook. List documents in a specific notebook
+func (c *Client) ListDocumentsByNotebook(ctx context.Context, id string, params *ListDocumentsByNotebookParams) (*DocumentListResponse, error) {
+	out := new(DocumentListResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/notebooks/"+url.PathEscape(id)+"/documents", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListEntitiesParams are the query parameters of ListEntities. Zero values are
+// not sent unless the parameter is required.
+type ListEntitiesParams struct {
+	// Entity type
+	Type string `query:"type"`
+	// Text the name or an alias contains
+	Q string `query:"q"`
+	// Page size (max 100)
+	Limit int `query:"limit"`
+	// Page offset
+	Offset int `query:"offset"`
+}
+
+// ListEntities calls GET /api/v1/entities.
+//
+// List entities. List one page of the people, organizations, topics, locations
+// and dates mentioned by the documents of the space's tenant, most mentioned
+// first. Filter by type, or by a name or alias containing q. With type=topic
+// this is the entry point of the topic explorer.
+func (c *Client) ListEntities(ctx context.Context, params *ListEntitiesParams) (*EntityListResponse, error) {
+	out := new(EntityListResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/entities", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListEntityDocumentsParams are the query parameters of ListEntityDocuments.
+// Zero values are not sent unless the parameter is required.
+type ListEntityDocumentsParams struct {
+	// Only the documents of this notebook
+	NotebookID string `query:"notebook_id"`
+	// Page size (max 100)
+	Limit int `query:"limit"`
+	// Page offset
+	Offset int `query:"offset"`
+}
+
+// ListEntityDocuments calls GET /api/v1/entities/{id}/documents.
+//
+// List entity documents. List one page of the documents mentioning an entity,
+// most mentions first, e.g. all documents mentioning an organization. With
+// notebook_id, only the documents of that notebook.
+func (c *Client) ListEntityDocuments(ctx context.Context, id string, params *ListEntityDocumentsParams) (*EntityDocumentsResponse, error) {
+	out := new(EntityDocumentsResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/entities/"+url.PathEscape(id)+"/documents", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListExecutionsParams are the query parameters of ListExecutions. Zero values
+// are not sent unless the parameter is required.
+type ListExecutionsParams struct {
+	// Filter by agent ID
+	AgentID string `query:"agent_id"`
+	// Number of results to return
+	Limit int `query:"limit"`
+	// Number of results to skip
+	Offset int `query:"offset"`
+}
+
+// ListExecutions calls GET /api/v1/executions.
+//
+// Get execution history. Retrieve execution history with optional filtering by
+// agent_id
+func (c *Client) ListExecutions(ctx context.Context, params *ListExecutionsParams) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/executions", params, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListFeedTokens calls GET /api/v1/users/me/feed-tokens.
+//
+// List feed tokens. List the caller's feed tokens, newest first. The tokens
+// themselves are not returned.
+func (c *Client) ListFeedTokens(ctx context.Context) (*FeedTokenListResponse, error) {
+	out := new(FeedTokenListResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/users/me/feed-tokens", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListJobRunsParams are the query parameters of ListJobRuns. Zero values are
+// not sent unless the parameter is required.
+type ListJobRunsParams struct {
+	// Number of runs to return
+	Limit int `query:"limit"`
+}
+
+// ListJobRuns calls GET /api/v1/admin/jobs/{name}/runs.
+//
+// List job runs. List the most recent runs of a scheduled job, newest first
+func (c *Client) ListJobRuns(ctx context.Context, name string, params *ListJobRunsParams) (*JobRunListResponse, error) {
+	out := new(JobRunListResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/admin/jobs/"+url.PathEscape(name)+"/runs", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListJobsParams are the query parameters of ListJobs. Zero values are not
+// sent unless the parameter is required.
+type ListJobsParams struct {
+	// Only jobs of this type
+	Type string `query:"type"`
+	// Only jobs in this status
+	Status string `query:"status"`
+	// Maximum number of jobs to return
+	Limit int `query:"limit"`
+	// Number of jobs to skip
+	Offset int `query:"offset"`
+}
+
+// ListJobs calls GET /api/v1/jobs.
+//
+// List jobs. List the async jobs started by the caller, newest first. Finished
+// jobs are listed until their retention expires.
+func (c *Client) ListJobs(ctx context.Context, params *ListJobsParams) (*JobListResponse, error) {
+	out := new(JobListResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/jobs", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListLegalHoldsParams are the query parameters of ListLegalHolds. Zero values
+// are not sent unless the parameter is required.
+type ListLegalHoldsParams struct {
+	// Tenant ID
+	TenantID string `query:"tenant_id"`
+	// Hold status
+	Status string `query:"status"`
+	// Page size (max 100)
+	Limit int `query:"limit"`
+	// Page offset
+	Offset int `query:"offset"`
+}
+
+// ListLegalHolds calls GET /api/v1/admin/legal-holds.
+//
+// List legal holds. List one page of legal holds, newest first, optionally of
+// one tenant and with one status.
+func (c *Client) ListLegalHolds(ctx context.Context, params *ListLegalHoldsParams) (*LegalHoldListResponse, error) {
+	out := new(LegalHoldListResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/admin/legal-holds", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListMaintenance calls GET /api/v1/admin/maintenance.
+//
+// List maintenance windows. List the maintenance in progress, system-wide
+// first, then per tenant
+func (c *Client) ListMaintenance(ctx context.Context) (*MaintenanceStatus, error) {
+	out := new(MaintenanceStatus)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/admin/maintenance", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListModeratedDocumentsParams are the query parameters of
+// ListModeratedDocuments. Zero values are not sent unless the parameter is
+// required.
+type ListModeratedDocumentsParams struct {
+	// Moderation status
+	Status string `query:"status"`
+	// Page size (max 100)
+	Limit int `query:"limit"`
+	// Page offset
+	Offset int `query:"offset"`
+}
+
+// ListModeratedDocuments calls GET /api/v1/moderation/documents.
+//
+// List moderated documents. List one page of the documents of the space's
+// public notebooks that moderation scanned or holds, most recently moderated
+// first. Without a status, the documents pending a scan, flagged for review
+// and blocked are listed. Pending and blocked documents are hidden from
+// everyone but their owner and the space's owners and admins. Requires the
+// owner or admin role.
+func (c *Client) ListModeratedDocuments(ctx context.Context, params *ListModeratedDocumentsParams) (*ModeratedDocumentListResponse, error) {
+	out := new(ModeratedDocumentListResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/moderation/documents", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListNotebookDigests calls GET /api/v1/users/me/digests.
+//
+// List notebook digest subscriptions. List the caller's notebook digest
+// subscriptions, oldest first
+func (c *Client) ListNotebookDigests(ctx context.Context) (*NotebookDigestSubscriptionList, error) {
+	out := new(NotebookDigestSubscriptionList)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/users/me/digests", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListNotebookEntitiesParams are the query parameters of ListNotebookEntities.
+// Zero values are not sent unless the parameter is required.
+type ListNotebookEntitiesParams struct {
+	// Entity type
+	Type string `query:"type"`
+	// Text the name or an alias contains
+	Q string `query:"q"`
+	// Page size (max 100)
+	Limit int `query:"limit"`
+	// Page offset
+	Offset int `query:"offset"`
+}
+
+// ListNotebookEntities calls GET /api/v1/notebooks/{id}/entities.
+//
+// List notebook entities. List one page of the people, organizations, topics,
+// locations and dates the documents of a notebook mention, mentioned by most
+// of its documents first; document_count counts the notebook's documents.
+// Filter by type, or by a name or alias containing q. List the documents of
+// the notebook mentioning one with /entities/{id}/documents?notebook_id=.
+func (c *Client) ListNotebookEntities(ctx context.Context, id string, params *ListNotebookEntitiesParams) (*EntityListResponse, error) {
+	out := new(EntityListResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/notebooks/"+url.PathEscape(id)+"/entities", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListNotebookShares calls GET /api/v1/notebooks/{id}/shares.
+//
+// List notebook shares. List the users and teams a notebook is shared with and
+// their roles, oldest share first.
+func (c *Client) ListNotebookShares(ctx context.Context, id string) (*NotebookShareListResponse, error) {
+	out := new(NotebookShareListResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/notebooks/"+url.PathEscape(id)+"/shares", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListNotebooksParams are the query parameters of ListNotebooks. Zero values
+// are not sent unless the parameter is required.
+type ListNotebooksParams struct {
+	// Results limit (max 100)
+	Limit int `query:"limit"`
+	// Results offset
+	Offset int `query:"offset"`
+}
+
+// ListNotebooks calls GET /api/v1/notebooks.
+//
+// List notebooks. List notebooks accessible to the current user
+func (c *Client) ListNotebooks(ctx context.Context, params *ListNotebooksParams) (*NotebookListResponse, error) {
+	out := new(NotebookListResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/notebooks", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListNotebooksV2Params are the query parameters of ListNotebooksV2. Zero
+// values are not sent unless the parameter is required.
+type ListNotebooksV2Params struct {
+	// Results limit (max 100)
+	Limit int `query:"limit"`
+	// Results offset
+	Offset int `query:"offset"`
+}
+
+// ListNotebooksV2 calls GET /api/v2/notebooks.
+//
+// List notebooks (v2). List notebooks accessible to the current user. Unlike
+// v1, the notebooks are in data and the page in pagination.
+func (c *Client) ListNotebooksV2(ctx context.Context, params *ListNotebooksV2Params) (*NotebookListResponseV2, error) {
+	out := new(NotebookListResponseV2)
+	if err := c.do(ctx, http.MethodGet, "/api/v2/notebooks", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListOrganizationAuditEventsParams are the query parameters of
+// ListOrganizationAuditEvents. Zero values are not sent unless the parameter
+// is required.
+type ListOrganizationAuditEventsParams struct {
+	// Actor user ID
+	ActorID string `query:"actor_id"`
+	// Resource type
+	ResourceType string `query:"resource_type"`
+	// Resource ID
+	ResourceID string `query:"resource_id"`
+	// Action
+	Action string `query:"action"`
+	// Earliest event time (RFC3339, inclusive)
+	From string `query:"from"`
+	// Latest event time (RFC3339, exclusive)
+	To string `query:"to"`
+	// Page size
+	Limit int `query:"limit"`
+	// Page offset
+	Offset int `query:"offset"`
+}
+
+// ListOrganizationAuditEvents calls GET /api/v1/organizations/{id}/audit.
+//
+// List organization audit events. List audit events of an organization and its
+// spaces, newest first. Requires the organization owner or admin role.
+func (c *Client) ListOrganizationAuditEvents(ctx context.Context, id string, params *ListOrganizationAuditEventsParams) (*AuditEventListResponse, error) {
+	out := new(AuditEventListResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/organizations/"+url.PathEscape(id)+"/audit", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListOrganizationRoles calls GET /api/v1/organizations/{id}/roles.
+//
+// List organization roles. List the built-in member roles and the custom roles
+// of an organization, with the actions roles can grant. Any member may list
+// them.
+func (c *Client) ListOrganizationRoles(ctx context.Context, id string) (*RoleList, error) {
+	out := new(RoleList)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/organizations/"+url.PathEscape(id)+"/roles", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListProcessingJobsParams are the query parameters of ListProcessingJobs.
+// Zero values are not sent unless the parameter is required.
+type ListProcessingJobsParams struct {
+	// Only jobs of this document
+	DocumentID string `query:"document_id"`
+	// Only jobs in this status
+	Status string `query:"status"`
+	// Maximum number of jobs to return
+	Limit int `query:"limit"`
+	// Number of jobs to skip
+	Offset int `query:"offset"`
+}
+
+// ListProcessingJobs calls GET /api/v1/processing-jobs.
+//
+// List processing jobs. List the document processing jobs of the current
+// space's tenant, newest first. Jobs move from pending to processing and end
+// completed, failed or cancelled.
+func (c *Client) ListProcessingJobs(ctx context.Context, params *ListProcessingJobsParams) (*ProcessingJobListResponse, error) {
+	out := new(ProcessingJobListResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/processing-jobs", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListRegions calls GET /api/v1/admin/regions.
+//
+// List regions. List the regions of a multi-region deployment with the number
+// of tenants each serves (active) and replicates (passive), and name the
+// region answering
+func (c *Client) ListRegions(ctx context.Context) (*RegionStatus, error) {
+	out := new(RegionStatus)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/admin/regions", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListRelatedEntitiesParams are the query parameters of ListRelatedEntities.
+// Zero values are not sent unless the parameter is required.
+type ListRelatedEntitiesParams struct {
+	// Entity type
+	Type string `query:"type"`
+	// Entities to return (max 50)
+	Limit int `query:"limit"`
+}
+
+// ListRelatedEntities calls GET /api/v1/entities/{id}/related.
+//
+// List related entities. List the entities most often mentioned by the same
+// documents as an entity. With type=topic it lists the neighbouring topics of
+// the topic explorer.
+func (c *Client) ListRelatedEntities(ctx context.Context, id string, params *ListRelatedEntitiesParams) (*RelatedEntitiesResponse, error) {
+	out := new(RelatedEntitiesResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/entities/"+url.PathEscape(id)+"/related", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListReportRuns calls GET /api/v1/reports/schedules/{id}/runs.
+//
+// List generated reports. List the reports a schedule generated, newest first;
+// the latest 60 are kept. Fails with 404 and AETHER-REPORT-001 when the
+// schedule does not exist. Requires the owner or admin role.
+func (c *Client) ListReportRuns(ctx context.Context, id string) (*ReportRunList, error) {
+	out := new(ReportRunList)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/reports/schedules/"+url.PathEscape(id)+"/runs", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListReportSchedules calls GET /api/v1/reports/schedules.
+//
+// List report schedules. List the report schedules of the current space,
+// oldest first, with the outcome of their last run. Requires the owner or
+// admin role.
+func (c *Client) ListReportSchedules(ctx context.Context) (*ReportScheduleList, error) {
+	out := new(ReportScheduleList)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/reports/schedules", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListS3Watchers calls GET /api/v1/notebooks/{id}/s3-watchers.
+//
+// List notebook S3 watchers. List the S3 watchers of a notebook with the
+// outcome of their last run.
+func (c *Client) ListS3Watchers(ctx context.Context, id string) (*S3WatcherList, error) {
+	out := new(S3WatcherList)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/notebooks/"+url.PathEscape(id)+"/s3-watchers", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListScheduledJobs calls GET /api/v1/admin/jobs.
+//
+// List scheduled jobs. List background jobs with their schedule, next run and
+// last outcome
+func (c *Client) ListScheduledJobs(ctx context.Context) (*ScheduledJobListResponse, error) {
+	out := new(ScheduledJobListResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/admin/jobs", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListSharedNotebooksParams are the query parameters of ListSharedNotebooks.
+// Zero values are not sent unless the parameter is required.
+type ListSharedNotebooksParams struct {
+	// Number of notebooks to return
+	Limit int `query:"limit"`
+	// Number of notebooks to skip
+	Offset int `query:"offset"`
+}
+
+// ListSharedNotebooks calls GET /api/v1/notebooks/shared-with-me.
+//
+// List notebooks shared with me. List the notebooks of the space shared with
+// the user, directly or through one of their teams, most recently shared
+// first. role is the highest role the user was given.
+func (c *Client) ListSharedNotebooks(ctx context.Context, params *ListSharedNotebooksParams) (*SharedNotebookListResponse, error) {
+	out := new(SharedNotebookListResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/notebooks/shared-with-me", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListSpaceAuditEventsParams are the query parameters of ListSpaceAuditEvents.
+// Zero values are not sent unless the parameter is required.
+type ListSpaceAuditEventsParams struct {
+	// Actor user ID
+	ActorID string `query:"actor_id"`
+	// Resource type
+	ResourceType string `query:"resource_type"`
+	// Resource ID
+	ResourceID string `query:"resource_id"`
+	// Action
+	Action string `query:"action"`
+	// Earliest event time (RFC3339, inclusive)
+	From string `query:"from"`
+	// Latest event time (RFC3339, exclusive)
+	To string `query:"to"`
+	// Page size
+	Limit int `query:"limit"`
+	// Page offset
+	Offset int `query:"offset"`
+}
+
+// ListSpaceAuditEvents calls GET /api/v1/spaces/{id}/audit.
+//
+// List space audit events. List audit events of a space, newest first.
+// Requires the space admin role.
+func (c *Client) ListSpaceAuditEvents(ctx context.Context, id string, params *ListSpaceAuditEventsParams) (*AuditEventListResponse, error) {
+	out := new(AuditEventListResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/spaces/"+url.PathEscape(id)+"/audit", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListSpaceMembersParams are the query parameters of ListSpaceMembers. Zero
+// values are not sent unless the parameter is required.
+type ListSpaceMembersParams struct {
+	// Limit
+	Limit int `query:"limit"`
+	// Offset
+	Offset int `query:"offset"`
+}
+
+// ListSpaceMembers calls GET /api/v1/spaces/{id}/members.
+//
+// List space members. Get all members of a space with their roles
+func (c *Client) ListSpaceMembers(ctx context.Context, id string, params *ListSpaceMembersParams) (*SpaceMembersListResponse, error) {
+	out := new(SpaceMembersListResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/spaces/"+url.PathEscape(id)+"/members", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListTrashParams are the query parameters of ListTrash. Zero values are not
+// sent unless the parameter is required.
+type ListTrashParams struct {
+	// Only items of this type
+	Type string `query:"type"`
+	// Number of items to return
+	Limit int `query:"limit"`
+	// Number of items to skip
+	Offset int `query:"offset"`
+}
+
+// ListTrash calls GET /api/v1/trash.
+//
+// List trash. List the deleted documents and notebooks of the space that can
+// still be restored, most recently deleted first. purge_at is when each is
+// purged for good, TRASH_RETENTION_DAYS after deletion. Documents of a deleted
+// notebook are not listed; they are restored and purged with it.
+func (c *Client) ListTrash(ctx context.Context, params *ListTrashParams) (*TrashListResponse, error) {
+	out := new(TrashListResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/trash", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListWebhookDeliveriesParams are the query parameters of
+// ListWebhookDeliveries. Zero values are not sent unless the parameter is
+// required.
+type ListWebhookDeliveriesParams struct {
+	// Page size (max 100)
+	Limit int `query:"limit"`
+	// Page offset
+	Offset int `query:"offset"`
+}
+
+// ListWebhookDeliveries calls GET
+// /api/v1/webhook-subscriptions/{id}/deliveries.
+//
+// List webhook deliveries. List one page of a subscription's deliveries,
+// newest first, with the subscriber's answer to the latest attempt (its body
+// cut at 1 KiB). Deliveries that succeeded or failed are kept for 30 days.
+// Fails with 404 and AETHER-HOOK-004 when the subscription does not exist.
+// Requires the owner or admin role.
+func (c *Client) ListWebhookDeliveries(ctx context.Context, id string, params *ListWebhookDeliveriesParams) (*WebhookDeliveryList, error) {
+	out := new(WebhookDeliveryList)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/webhook-subscriptions/"+url.PathEscape(id)+"/deliveries", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListWebhookSubscriptions calls GET /api/v1/webhook-subscriptions.
+//
+// List webhook subscriptions. List the webhook subscriptions of the current
+// space, oldest first, with the outcome of their latest delivery. Secrets are
+// not returned. Requires the owner or admin role.
+func (c *Client) ListWebhookSubscriptions(ctx context.Context) (*WebhookSubscriptionList, error) {
+	out := new(WebhookSubscriptionList)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/webhook-subscriptions", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LivenessCheck calls GET /healthz.
+//
+// Liveness check. Check if the application is alive
+func (c *Client) LivenessCheck(ctx context.Context) (map[string]string, error) {
+	var out map[string]string
+	if err := c.do(ctx, http.MethodGet, "/healthz", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LivenessCheck2 calls GET /health/live.
+//
+// Liveness check. Check if the application is alive
+func (c *Client) LivenessCheck2(ctx context.Context) (map[string]string, error) {
+	var out map[string]string
+	if err := c.do(ctx, http.MethodGet, "/health/live", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MarkTutorialComplete calls POST /api/v1/users/me/onboarding.
+//
+// Mark tutorial complete. Mark the onboarding tutorial as completed for the
+// current user
+func (c *Client) MarkTutorialComplete(ctx context.Context) (map[string]string, error) {
+	var out map[string]string
+	if err := c.do(ctx, http.MethodPost, "/api/v1/users/me/onboarding", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MergeEntity calls POST /api/v1/entities/{id}/merge.
+//
+// Merge entities. Merge an entity into a target entity of the same type, for
+// spellings the automatic deduplication missed. The target takes over the
+// entity's document mentions and its names as aliases; the entity is removed.
+func (c *Client) MergeEntity(ctx context.Context, id string, body EntityMergeRequest) (*Entity, error) {
+	out := new(Entity)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/entities/"+url.PathEscape(id)+"/merge", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Messages calls POST /api/v1/router/messages.
+//
+// Messages. Anthropic-compatible messages request, proxied to the LLM router
+// service
+func (c *Client) Messages(ctx context.Context, body map[string]interface{}) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/router/messages", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// NotebookActivityAtomParams are the query parameters of NotebookActivityAtom.
+// Zero values are not sent unless the parameter is required.
+type NotebookActivityAtomParams struct {
+	// Feed token
+	Token string `query:"token,required"`
+}
+
+// NotebookActivityAtom calls GET /feeds/notebooks/{id}/atom.
+//
+// Notebook activity Atom feed. Recent activity of a notebook and its documents
+// as Atom 1.0, newest first
+func (c *Client) NotebookActivityAtom(ctx context.Context, id string, params *NotebookActivityAtomParams) (io.ReadCloser, error) {
+	resp, err := c.stream(ctx, http.MethodGet, "/feeds/notebooks/"+url.PathEscape(id)+"/atom", params)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// NotebookActivityRSSParams are the query parameters of NotebookActivityRSS.
+// Zero values are not sent unless the parameter is required.
+type NotebookActivityRSSParams struct {
+	// Feed token
+	Token string `query:"token,required"`
+}
+
+// NotebookActivityRSS calls GET /feeds/notebooks/{id}/rss.
+//
+// Notebook activity RSS feed. Recent activity of a notebook and its documents
+// as RSS 2.0, newest first
+func (c *Client) NotebookActivityRSS(ctx context.Context, id string, params *NotebookActivityRSSParams) (io.ReadCloser, error) {
+	resp, err := c.stream(ctx, http.MethodGet, "/feeds/notebooks/"+url.PathEscape(id)+"/rss", params)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// PinTenantRegion calls PUT /api/v1/admin/tenants/{tenant_id}/region.
+//
+// Pin tenant region. Pin a tenant to the region that serves it and,
+// optionally, a passive region to fail over to. Other regions reject requests
+// in the tenant's spaces with 421 and AETHER-REGION-001, naming the region's
+// URL; every replica applies the pin within REGION_REFRESH_SECONDS. Pinning
+// does not move data: replicate it to the region first. The change is recorded
+// in the audit log.
+func (c *Client) PinTenantRegion(ctx context.Context, tenantID string, body TenantRegionRequest) (*TenantRegion, error) {
+	out := new(TenantRegion)
+	if err := c.do(ctx, http.MethodPut, "/api/v1/admin/tenants/"+url.PathEscape(tenantID)+"/region", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ProcessingCallback calls POST /api/v1/webhooks/processing.
+//
+// Processing callback. Record the outcome of processing a document, for
+// AudiModal or another processing service on deployments without Kafka. The
+// body is the event AudiModal publishes on processing.complete; data.success
+// false records a failure with data.error. The event must carry tenant_id and
+// name its document by data.document_id, data.file_id or data.url, looked up
+// in that tenant only. Deliveries are signed with PROCESSING_WEBHOOK_SECRET:
+// X-Webhook-Signature is "v1=" and the hex HMAC-SHA256 of
+// "<X-Webhook-ID>.<X-Webhook-Timestamp>.<body>". X-Webhook-ID is a nonce: a
+// delivery ID is accepted once, and deliveries signed more than
+// WEBHOOK_TOLERANCE_SECONDS from now are rejected with 401 and
+// AETHER-HOOK-002. An event ID is also applied once, whether it arrives here
+// or on Kafka; a repeated event, or one for a submission the document has
+// since replaced, answers 200 with applied false. Fails with 404 and
+// AETHER-DOC-001 when the tenant has no such document.
+func (c *Client) ProcessingCallback(ctx context.Context, body ProcessingCompleteEvent) (*ProcessingCallbackResult, error) {
+	out := new(ProcessingCallbackResult)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/webhooks/processing", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ProvisionTenant calls POST /api/v1/admin/tenants.
+//
+// Provision a tenant. Create an organization owned by an existing user and its
+// first space, which is registered as an AudiModal tenant
+func (c *Client) ProvisionTenant(ctx context.Context, body TenantProvisionRequest) (*TenantProvisionResponse, error) {
+	out := new(TenantProvisionResponse)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/admin/tenants", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PurgeTrashItem calls DELETE /api/v1/trash/{type}/{id}.
+//
+// Purge from trash. Permanently delete a document or notebook in the trash
+// without waiting for TRASH_RETENTION_DAYS to pass. A notebook is purged with
+// all its documents. The files, chunks and vectors of purged documents are
+// removed from AudiModal, the vector store and storage by the deletion
+// orchestrator (GET /api/v1/admin/deletions).
+func (c *Client) PurgeTrashItem(ctx context.Context, typeParam string, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/trash/"+url.PathEscape(typeParam)+"/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// Query calls POST /api/v1/graphql.
+//
+// Run a GraphQL query. Query spaces, notebooks, documents and agents and their
+// relationships in one request. Fields other than spaces and agent read from
+// the space selected by the X-Space-Type and X-Space-ID headers. Permissions
+// match the REST API. Query errors are returned with status 200 in the errors
+// of the response, each with the code and error_code of the failure in its
+// extensions; queries nested deeper than GRAPHQL_MAX_DEPTH are rejected.
+func (c *Client) Query(ctx context.Context, body GraphQLRequest) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/graphql", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ReadinessCheck calls GET /readyz.
+//
+// Readiness check. Check if the application is ready to serve requests
+func (c *Client) ReadinessCheck(ctx context.Context) (*HealthResponse, error) {
+	out := new(HealthResponse)
+	if err := c.do(ctx, http.MethodGet, "/readyz", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ReadinessCheck2 calls GET /health/ready.
+//
+// Readiness check. Check if the application is ready to serve requests
+func (c *Client) ReadinessCheck2(ctx context.Context) (*HealthResponse, error) {
+	out := new(HealthResponse)
+	if err := c.do(ctx, http.MethodGet, "/health/ready", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RebuildReporting calls POST /api/v1/admin/reporting/rebuild.
+//
+// Rebuild reporting read models. Truncate the reporting tables and replay
+// every logged domain event into them
+func (c *Client) RebuildReporting(ctx context.Context) (*ReportingRebuildResponse, error) {
+	out := new(ReportingRebuildResponse)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/admin/reporting/rebuild", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RefreshDocumentSummary calls POST /api/v1/documents/{id}/summary/refresh.
+//
+// Refresh document summary. Write the abstract of a document again, for
+// example after it was reprocessed, replacing the cached one. Counts against
+// the space's monthly summary token budget.
+func (c *Client) RefreshDocumentSummary(ctx context.Context, id string) (*Summary, error) {
+	out := new(Summary)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/documents/"+url.PathEscape(id)+"/summary/refresh", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RefreshNotebookSummary calls POST /api/v1/notebooks/{id}/summary/refresh.
+//
+// Refresh notebook summary. Write the digest of a notebook again from its
+// current documents, replacing the cached one. Counts against the space's
+// monthly summary token budget.
+func (c *Client) RefreshNotebookSummary(ctx context.Context, id string) (*Summary, error) {
+	out := new(Summary)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/notebooks/"+url.PathEscape(id)+"/summary/refresh", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RefreshProcessingResults calls POST /api/v1/documents/refresh-processing.
+//
+// Refresh processing results. Check AudiModal for updated processing results
+// and update documents accordingly
+func (c *Client) RefreshProcessingResults(ctx context.Context) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/documents/refresh-processing", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ReleaseLegalHold calls POST /api/v1/admin/legal-holds/{hold_id}/release.
+//
+// Release a legal hold. Release a legal hold, saying why. Its content can be
+// edited and deleted again unless another active hold covers it. The hold is
+// kept, released, for the record, and the release is recorded in the audit
+// log. A hold already released responds 409 with AETHER-HOLD-003.
+func (c *Client) ReleaseLegalHold(ctx context.Context, holdID string, body LegalHoldReleaseRequest) (*LegalHold, error) {
+	out := new(LegalHold)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/admin/legal-holds/"+url.PathEscape(holdID)+"/release", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ReloadRuntimeConfig calls POST /api/v1/admin/config/reload.
+//
+// Reload runtime configuration. Re-read runtime configuration from the
+// environment file
+func (c *Client) ReloadRuntimeConfig(ctx context.Context) (*RuntimeConfigResponse, error) {
+	out := new(RuntimeConfigResponse)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/admin/config/reload", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RemoveKnowledgeSource calls DELETE
+// /api/v1/agents/{id}/knowledge-sources/{notebook_id}.
+//
+// Remove knowledge source. Unlink a notebook from an agent's knowledge sources
+func (c *Client) RemoveKnowledgeSource(ctx context.Context, id string, notebookID string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/agents/"+url.PathEscape(id)+"/knowledge-sources/"+url.PathEscape(notebookID), nil, nil, nil)
+}
+
+// RemoveOrganizationMember calls DELETE
+// /api/v1/organizations/{id}/members/{user_id}.
+//
+// Remove organization member. Remove a member from the organization
+func (c *Client) RemoveOrganizationMember(ctx context.Context, id string, userID string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/organizations/"+url.PathEscape(id)+"/members/"+url.PathEscape(userID), nil, nil, nil)
+}
+
+// RemoveSpaceMember calls DELETE /api/v1/spaces/{id}/members/{userId}.
+//
+// Remove space member. Remove a user from a space
+func (c *Client) RemoveSpaceMember(ctx context.Context, id string, userID string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/spaces/"+url.PathEscape(id)+"/members/"+url.PathEscape(userID), nil, nil, nil)
+}
+
+// RemoveTeamMember calls DELETE /api/v1/teams/{id}/members/{user_id}.
+//
+// Remove team member. Remove a member from the team
+func (c *Client) RemoveTeamMember(ctx context.Context, id string, userID string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/teams/"+url.PathEscape(id)+"/members/"+url.PathEscape(userID), nil, nil, nil)
+}
+
+// RepairConsistencyParams are the query parameters of RepairConsistency. Zero
+// values are not sent unless the parameter is required.
+type RepairConsistencyParams struct {
+	// Count the repairs without making them
+	DryRun bool `query:"dry_run"`
+}
+
+// RepairConsistency calls POST /api/v1/admin/consistency/repair.
+//
+// Repair graph consistency. Restore missing notebook and user space
+// relationships and copy the space fields of notebooks from their
+// relationships. Notebooks with neither are reported as unrepairable. A dry
+// run only counts the repairs.
+func (c *Client) RepairConsistency(ctx context.Context, params *RepairConsistencyParams) (*ConsistencyRepairResult, error) {
+	out := new(ConsistencyRepairResult)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/admin/consistency/repair", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ReprocessDocumentParams are the query parameters of ReprocessDocument. Zero
+// values are not sent unless the parameter is required.
+type ReprocessDocumentParams struct {
+	// Processing priority tier, up to the cap of the space's plan; defaults to
+	// the tier the document was last submitted with
+	Priority string `query:"priority"`
+}
+
+// ReprocessDocument calls POST /api/v1/documents/{id}/reprocess.
+//
+// Reprocess document. Re-run text extraction and processing for a document
+func (c *Client) ReprocessDocument(ctx context.Context, id string, params *ReprocessDocumentParams) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/documents/"+url.PathEscape(id)+"/reprocess", params, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ReprocessFileWithStrategy calls POST /api/v1/files/{file_id}/reprocess.
+//
+// Reprocess file. Reprocess a file with a different chunking strategy
+func (c *Client) ReprocessFileWithStrategy(ctx context.Context, fileID string, body ReprocessFileRequest) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/files/"+url.PathEscape(fileID)+"/reprocess", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RequeueDeadLetter calls POST /api/v1/admin/dead-letters/{id}/requeue.
+//
+// Requeue a dead letter. Schedule a failed processing retry job to run on the
+// next poll of the retry queue
+func (c *Client) RequeueDeadLetter(ctx context.Context, id string) (*ProcessingRetryJob, error) {
+	out := new(ProcessingRetryJob)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/admin/dead-letters/"+url.PathEscape(id)+"/requeue", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ResetTutorial calls DELETE /api/v1/users/me/onboarding.
+//
+// Reset tutorial. Reset the onboarding tutorial status for the current user
+// (for testing/re-onboarding)
+func (c *Client) ResetTutorial(ctx context.Context) (map[string]string, error) {
+	var out map[string]string
+	if err := c.do(ctx, http.MethodDelete, "/api/v1/users/me/onboarding", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RestoreDocumentVersion calls POST
+// /api/v1/documents/{id}/versions/{version}/restore.
+//
+// Restore a document version. Make an earlier version of a document current
+// again; its file is processed again. Later versions are kept, and restoring
+// the current version changes nothing. Only the document's owner can restore
+// versions. Fails with 404 and AETHER-DOC-006 when the document has no such
+// version.
+func (c *Client) RestoreDocumentVersion(ctx context.Context, id string, version string) (*DocumentVersion, error) {
+	out := new(DocumentVersion)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/documents/"+url.PathEscape(id)+"/versions/"+url.PathEscape(version)+"/restore", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RestoreTrashItem calls POST /api/v1/trash/{type}/{id}/restore.
+//
+// Restore from trash. Take a document or notebook out of the trash. A document
+// gets back the status it had, except that one deleted while uploading or
+// processing is restored as failed so it can be reprocessed. A document of a
+// deleted notebook fails with 409 and AETHER-TRASH-002 until the notebook is
+// restored. Items not in the trash fail with 404 and AETHER-TRASH-001.
+func (c *Client) RestoreTrashItem(ctx context.Context, typeParam string, id string) error {
+	return c.do(ctx, http.MethodPost, "/api/v1/trash/"+url.PathEscape(typeParam)+"/"+url.PathEscape(id)+"/restore", nil, nil, nil)
+}
+
+// ResyncDocumentVectors calls POST /api/v1/documents/{id}/vector-sync.
+//
+// Resync document vectors. Queue a processed document for a vector sync due
+// now, for example after it failed. The sync runs in the background; poll GET
+// /documents/{id}/vector-sync for the outcome.
+func (c *Client) ResyncDocumentVectors(ctx context.Context, id string) (*DocumentVectorSync, error) {
+	out := new(DocumentVectorSync)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/documents/"+url.PathEscape(id)+"/vector-sync", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RetryDocumentDeletion calls POST /api/v1/admin/deletions/{id}/retry.
+//
+// Retry a document deletion. Make the failed targets of a document deletion
+// pending again, to be retried on the next poll. A deletion that has not
+// failed is returned unchanged.
+func (c *Client) RetryDocumentDeletion(ctx context.Context, id string) (*DocumentDeletion, error) {
+	out := new(DocumentDeletion)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/admin/deletions/"+url.PathEscape(id)+"/retry", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ReviewModeratedDocument calls POST /api/v1/moderation/documents/{id}/review.
+//
+// Review a moderated document. Override moderation's verdict on a document of
+// a public notebook: approved makes it readable by the space, blocked holds it
+// from everyone but its owner and the space's owners and admins. The decision
+// stands until the document is processed again. Requires the owner or admin
+// role.
+func (c *Client) ReviewModeratedDocument(ctx context.Context, id string, body ModerationReviewRequest) (*ModeratedDocument, error) {
+	out := new(ModeratedDocument)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/moderation/documents/"+url.PathEscape(id)+"/review", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RevokeFeedToken calls DELETE /api/v1/users/me/feed-tokens/{id}.
+//
+// Revoke a feed token. Revoke a feed token; feeds using it respond 401 from
+// then on
+func (c *Client) RevokeFeedToken(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/users/me/feed-tokens/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// RunReportSchedule calls POST /api/v1/reports/schedules/{id}/run.
+//
+// Generate a report now. Generate a schedule's report for its current period
+// so far and deliver it like a scheduled one. The schedule's next run is
+// unchanged, and an agent_cost report counts from the previous scheduled
+// report as usual. A report that could not be built or stored is returned with
+// status failed; one that could not be emailed succeeds with the email's
+// error. Fails with 404 and AETHER-REPORT-001 when the schedule does not
+// exist, and with 503 when storage is unavailable. Requires the owner or admin
+// role.
+func (c *Client) RunReportSchedule(ctx context.Context, id string) (*ReportRun, error) {
+	out := new(ReportRun)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/reports/schedules/"+url.PathEscape(id)+"/run", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ScheduledJobsCalendarParams are the query parameters of
+// ScheduledJobsCalendar. Zero values are not sent unless the parameter is
+// required.
+type ScheduledJobsCalendarParams struct {
+	// Feed token
+	Token string `query:"token,required"`
+}
+
+// ScheduledJobsCalendar calls GET /feeds/jobs.ics.
+//
+// Scheduled jobs calendar. The background jobs' runs as an iCal feed: the runs
+// scheduled for the next week as tentative events and each job's recent runs,
+// with their outcome, as confirmed ones
+func (c *Client) ScheduledJobsCalendar(ctx context.Context, params *ScheduledJobsCalendarParams) (io.ReadCloser, error) {
+	resp, err := c.stream(ctx, http.MethodGet, "/feeds/jobs.ics", params)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// SearchParams are the query parameters of Search. Zero values are not sent
+// unless the parameter is required.
+type SearchParams struct {
+	// Search text (2-100 characters)
+	Q string `query:"q,required"`
+	// Types to search: document, notebook, agent, event (comma-separated or
+	// repeated); all when omitted
+	Types []string `query:"types"`
+	// Hits per type (max 20)
+	Limit int `query:"limit"`
+}
+
+// Search calls GET /api/v1/search.
+//
+// Unified search. Search documents, notebooks and agents of the current space
+// and the tenant's live stream events at once. Hits are ranked together in
+// results and grouped by type in groups, best group first. Matching is
+// case-insensitive on names, descriptions, tags and event content.
+func (c *Client) Search(ctx context.Context, params *SearchParams) (*UnifiedSearchResponse, error) {
+	out := new(UnifiedSearchResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/search", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SearchChunks calls POST /api/v1/chunks/search.
+//
+// Search chunks. Search chunks across the files of the space
+func (c *Client) SearchChunks(ctx context.Context, body ChunkSearchRequest) (*ChunkListResponse, error) {
+	out := new(ChunkListResponse)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/chunks/search", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SearchDocumentsParams are the query parameters of SearchDocuments. Zero
+// values are not sent unless the parameter is required.
+type SearchDocumentsParams struct {
+	// Search query
+	Query string `query:"query"`
+	// Notebook ID filter
+	NotebookID string `query:"notebook_id"`
+	// Owner ID filter
+	OwnerID string `query:"owner_id"`
+	// Document type filter
+	Type string `query:"type"`
+	// Status filter
+	Status string `query:"status"`
+	// MIME type filter
+	MimeType string `query:"mime_type"`
+	// Tags filter
+	Tags []string `query:"tags"`
+	// Filter expression, e.g. tag:contract AND mime:pdf AND created:>2024-01-01
+	Filter string `query:"filter"`
+	// Results limit (max 100)
+	Limit int `query:"limit"`
+	// Results offset
+	Offset int `query:"offset"`
+}
+
+// SearchDocuments calls GET /api/v1/documents/search.
+//
+// Search documents. Search documents by query, notebook, owner, etc. The
+// filter parameter takes an expression of field:value terms combined with AND,
+// OR, NOT and parentheses, e.g. tag:contract AND mime:pdf AND
+// created:>2024-01-01. Fields are tag, mime, type, status, name, filename,
+// notebook, owner, size, created, updated and processed; size and the dates
+// also take >, >=, < and <=.
+func (c *Client) SearchDocuments(ctx context.Context, params *SearchDocumentsParams) (*DocumentListResponse, error) {
+	out := new(DocumentListResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/documents/search", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SearchNotebooksParams are the query parameters of SearchNotebooks. Zero
+// values are not sent unless the parameter is required.
+type SearchNotebooksParams struct {
+	// Search query
+	Query string `query:"query"`
+	// Owner ID filter
+	OwnerID string `query:"owner_id"`
+	// Visibility filter
+	Visibility string `query:"visibility"`
+	// Status filter
+	Status string `query:"status"`
+	// Tags filter
+	Tags []string `query:"tags"`
+	// Results limit (max 100)
+	Limit int `query:"limit"`
+	// Results offset
+	Offset int `query:"offset"`
+}
+
+// SearchNotebooks calls GET /api/v1/notebooks/search.
+//
+// Search notebooks. Search notebooks by query, owner, visibility, etc.
+func (c *Client) SearchNotebooks(ctx context.Context, params *SearchNotebooksParams) (*NotebookListResponse, error) {
+	out := new(NotebookListResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/notebooks/search", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SearchUsersParams are the query parameters of SearchUsers. Zero values are
+// not sent unless the parameter is required.
+type SearchUsersParams struct {
+	// Search query
+	Query string `query:"query"`
+	// Username filter
+	Username string `query:"username"`
+	// Email filter
+	Email string `query:"email"`
+	// Status filter
+	Status string `query:"status"`
+	// Role filter
+	Role string `query:"role"`
+	// Results limit (max 100)
+	Limit int `query:"limit"`
+	// Results offset
+	Offset int `query:"offset"`
+}
+
+// SearchUsers calls GET /api/v1/users/search.
+//
+// Search users. Search for users by query, username, or email
+func (c *Client) SearchUsers(ctx context.Context, params *SearchUsersParams) (*UserListResponse, error) {
+	out := new(UserListResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/users/search", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SemanticSearch calls POST /api/v1/spaces/{id}/search/semantic.
+//
+// Semantic search in a space. Search the chunks indexed in the space's
+// DeepLake namespace by meaning. Mode vector ranks by embedding similarity;
+// hybrid blends it with keyword matches using vector_weight. Each hit carries
+// the chunk text and the document it belongs to; chunks of deleted documents,
+// or of notebooks outside notebook_ids, are left out.
+func (c *Client) SemanticSearch(ctx context.Context, id string, body SemanticSearchRequest) (*SemanticSearchResponse, error) {
+	out := new(SemanticSearchResponse)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/spaces/"+url.PathEscape(id)+"/search/semantic", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SetRedactionRules calls PUT /api/v1/notebooks/{id}/redaction.
+//
+// Set notebook redaction rules. Set the redaction rules of a notebook,
+// replacing the ones it had. Users the notebook is shared with at one of roles
+// (viewer and commenter by default) read its documents without the chunks the
+// rules omit: chunks in which PII was detected when omit_pii_chunks is set,
+// and the chunks of the named sections, matched by title ignoring case. The
+// rules apply to the extracted text and chunk endpoints and to the
+// extracted_text of the document; the document's owner and the space's owners
+// and admins read it whole. Only the notebook's owner manages its rules.
+func (c *Client) SetRedactionRules(ctx context.Context, id string, body RedactionRulesRequest) (*RedactionRules, error) {
+	out := new(RedactionRules)
+	if err := c.do(ctx, http.MethodPut, "/api/v1/notebooks/"+url.PathEscape(id)+"/redaction", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ShareNotebook calls POST /api/v1/notebooks/{id}/share.
+//
+// Share notebook. Share a notebook with members and teams of its organization,
+// all with the given role. Viewers read the notebook and its documents, and
+// editors also change its documents. Commenters have the access of viewers
+// until comments are supported. Sharing again with the same user or team
+// changes its role; a team's members get the role through the team, and a user
+// shared with directly and through teams gets the highest role. Only the
+// notebook's owner shares it, and personal notebooks cannot be shared. Users
+// and teams not in the organization fail the request with 404 and
+// AETHER-NB-003, listed in details. Responds with all the notebook's shares.
+func (c *Client) ShareNotebook(ctx context.Context, id string, body NotebookShareRequest) (*NotebookShareListResponse, error) {
+	out := new(NotebookShareListResponse)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/notebooks/"+url.PathEscape(id)+"/share", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StartLegalHoldExport calls POST /api/v1/admin/legal-holds/{hold_id}/export.
+//
+// Start a legal hold export job. Collect the documents an active legal hold
+// covers that match the criteria, as POST
+// /api/v1/admin/legal-holds/{hold_id}/collect does, and export them to a
+// bundle in object storage with their records, extracted text, stored files of
+// every version and audit events. Responds 202 with a legal_hold_export job
+// whose progress counts the documents and files written; once it has succeeded
+// its result gives the bundle prefix, the SHA-256 hash of its manifest and a
+// download URL for it. The manifest records the chain of custody: the hold and
+// criteria, who collected the bundle and when, the hash of every file and the
+// custody steps. Its hash is recorded in the audit log.
+func (c *Client) StartLegalHoldExport(ctx context.Context, holdID string, body LegalHoldCriteria) (*Job, error) {
+	out := new(Job)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/admin/legal-holds/"+url.PathEscape(holdID)+"/export", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StartMaintenance calls PUT /api/v1/admin/maintenance.
+//
+// Start system-wide maintenance. Reject writes of every tenant with 503 and
+// AETHER-MAINT-001, whose details carry the message and expected end for the
+// banner, until maintenance is ended. Reads, downloads and WebSocket streams
+// keep working, as do the admin endpoints that end maintenance. Every replica
+// applies the window within MAINTENANCE_REFRESH_SECONDS. Starting it again
+// changes the message and expected end and keeps the start.
+func (c *Client) StartMaintenance(ctx context.Context, body MaintenanceRequest) (*MaintenanceWindow, error) {
+	out := new(MaintenanceWindow)
+	if err := c.do(ctx, http.MethodPut, "/api/v1/admin/maintenance", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StartNotebookExport calls POST /api/v1/notebooks/{id}/documents/export.
+//
+// Start a notebook export job. Export document metadata of a notebook as
+// newline-delimited JSON to object storage in the background. Responds 202
+// with a notebook_export job; once it has succeeded its result holds a
+// download URL.
+func (c *Client) StartNotebookExport(ctx context.Context, id string) (*Job, error) {
+	out := new(Job)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/notebooks/"+url.PathEscape(id)+"/documents/export", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StartReprocessing calls POST /api/v1/admin/reprocess.
+//
+// Start a reprocessing campaign. Queue the documents with a status, optionally
+// of one tenant and created after a time, for reprocessing through the retry
+// queue. A dry run only lists them.
+func (c *Client) StartReprocessing(ctx context.Context, body ReprocessCampaignRequest) (*ReprocessCampaignResponse, error) {
+	out := new(ReprocessCampaignResponse)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/admin/reprocess", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StartTenantExport calls POST /api/v1/admin/tenants/{tenant_id}/export.
+//
+// Start a tenant export job. Export every space, notebook, document with its
+// versions and stored files, and the audit events of the tenant's spaces to a
+// bundle in object storage, for offboarding. Responds 202 with a tenant_export
+// job whose progress counts the items written; once it has succeeded its
+// result gives the bundle prefix and a download URL for its manifest, which
+// lists the SHA-256 hash of every file.
+func (c *Client) StartTenantExport(ctx context.Context, tenantID string) (*Job, error) {
+	out := new(Job)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/admin/tenants/"+url.PathEscape(tenantID)+"/export", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StartTenantMaintenance calls PUT
+// /api/v1/admin/maintenance/tenants/{tenant_id}.
+//
+// Start tenant maintenance. Reject writes made in the spaces of one tenant
+// with 503 and AETHER-MAINT-001 until its maintenance is ended, like
+// system-wide maintenance. Requests that name no space are not affected.
+// Starting it again changes the message and expected end and keeps the start.
+func (c *Client) StartTenantMaintenance(ctx context.Context, tenantID string, body MaintenanceRequest) (*MaintenanceWindow, error) {
+	out := new(MaintenanceWindow)
+	if err := c.do(ctx, http.MethodPut, "/api/v1/admin/maintenance/tenants/"+url.PathEscape(tenantID), nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StreamSourceWebhook calls POST /webhooks/streams/{id}.
+//
+// Stream source webhook. Ingest a live event pushed by an external stream
+// source. The source must be active; the event is recorded in the source's
+// space. Deliveries are signed with STREAM_WEBHOOK_SECRET: X-Webhook-Signature
+// is "v1=" and the hex HMAC-SHA256 of
+// "<X-Webhook-ID>.<X-Webhook-Timestamp>.<body>".
+func (c *Client) StreamSourceWebhook(ctx context.Context, id string, body StreamWebhookEvent) (*LiveEvent, error) {
+	out := new(LiveEvent)
+	if err := c.do(ctx, http.MethodPost, "/webhooks/streams/"+url.PathEscape(id), nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StripeBillingWebhook calls POST /webhooks/stripe.
+//
+// Stripe billing webhook. Record subscription changes in the billing of the
+// organization named by the subscription's organization_id metadata. Handles
+// customer.subscription.created, .updated and .deleted; other events are
+// acknowledged and ignored. Deliveries are verified with
+// STRIPE_WEBHOOK_SECRET.
+func (c *Client) StripeBillingWebhook(ctx context.Context, body map[string]interface{}) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, http.MethodPost, "/webhooks/stripe", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SubmitFrontendLogs calls POST /api/v1/logs.
+//
+// Submit frontend logs. Receives log entries from the frontend (browser) and
+// logs them server-side for collection by Loki
+func (c *Client) SubmitFrontendLogs(ctx context.Context, body LogBatchRequest) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/logs", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SubscribeNotebookDigest calls PUT /api/v1/notebooks/{id}/digest.
+//
+// Subscribe to a notebook digest. Subscribe the caller to a summary of the
+// documents added to and changed in a notebook, sent at the end of every UTC
+// day or week from Monday, or change the cadence of their subscription.
+// Digests are built from the notebook's activity feed, announced in the app as
+// a notebook.digest notification and, when email is configured, emailed to the
+// caller. Periods with no changes send nothing. The first digest covers the
+// period the subscription was made in.
+func (c *Client) SubscribeNotebookDigest(ctx context.Context, id string, body NotebookDigestSubscribeRequest) (*NotebookDigestSubscription, error) {
+	out := new(NotebookDigestSubscription)
+	if err := c.do(ctx, http.MethodPut, "/api/v1/notebooks/"+url.PathEscape(id)+"/digest", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SuggestParams are the query parameters of Suggest. Zero values are not sent
+// unless the parameter is required.
+type SuggestParams struct {
+	// Text typed so far (1-100 characters)
+	Q string `query:"q,required"`
+	// Maximum suggestions (max 20)
+	Limit int `query:"limit"`
+}
+
+// Suggest calls GET /api/v1/search/suggest.
+//
+// Search suggestions. Typeahead completions for the search bar: names of the
+// current space's documents and notebooks and tags of its documents that start
+// with q, or have a word that does. Matching is case-insensitive. Suggestions
+// are ordered alphabetically by the text they matched.
+func (c *Client) Suggest(ctx context.Context, params *SuggestParams) (*SearchSuggestResponse, error) {
+	out := new(SearchSuggestResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/search/suggest", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SwaggerUI calls GET /api/v1/docs.
+//
+// Browse API documentation. Swagger UI for the OpenAPI document
+func (c *Client) SwaggerUI(ctx context.Context) (io.ReadCloser, error) {
+	resp, err := c.stream(ctx, http.MethodGet, "/api/v1/docs", nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// SystemStatusParams are the query parameters of SystemStatus. Zero values are
+// not sent unless the parameter is required.
+type SystemStatusParams struct {
+	// Run checks now instead of returning cached results
+	Refresh bool `query:"refresh"`
+}
+
+// SystemStatus calls GET /api/v1/admin/system/status.
+//
+// System status. Per-dependency health with latency and last error
+func (c *Client) SystemStatus(ctx context.Context, params *SystemStatusParams) (*SystemStatusResponse, error) {
+	out := new(SystemStatusResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/admin/system/status", params, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TestWebhookSubscription calls POST /api/v1/webhook-subscriptions/{id}/test.
+//
+// Send a test webhook. Send a webhook.test event to a subscription of the
+// current space once, whether it is active or paused, and return the recorded
+// delivery with the subscriber's answer. The test is not retried; a subscriber
+// that does not answer 2xx yields a delivery with status failed. Fails with
+// 404 and AETHER-HOOK-004 when the subscription does not exist. Requires the
+// owner or admin role.
+func (c *Client) TestWebhookSubscription(ctx context.Context, id string) (*WebhookDelivery, error) {
+	out := new(WebhookDelivery)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/webhook-subscriptions/"+url.PathEscape(id)+"/test", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TextSearch calls POST /api/v1/notebooks/{id}/vector-search/text.
+//
+// Text-based vector search. Search indexed documents using text query
+// (converts to embeddings automatically)
+func (c *Client) TextSearch(ctx context.Context, id string, body TextSearchRequest) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/notebooks/"+url.PathEscape(id)+"/vector-search/text", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UnshareNotebook calls DELETE
+// /api/v1/notebooks/{id}/shares/{type}/{share_id}.
+//
+// Unshare notebook. Stop sharing a notebook with a user or team. Members of a
+// team keep any role they were given directly or through other teams. Only the
+// notebook's owner unshares it. Fails with 404 and AETHER-NB-004 when the
+// notebook is not shared with the user or team.
+func (c *Client) UnshareNotebook(ctx context.Context, id string, typeParam string, shareID string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/notebooks/"+url.PathEscape(id)+"/shares/"+url.PathEscape(typeParam)+"/"+url.PathEscape(shareID), nil, nil, nil)
+}
+
+// UnsubscribeNotebookDigest calls DELETE /api/v1/notebooks/{id}/digest.
+//
+// Unsubscribe from a notebook digest. Stop sending the caller digests of a
+// notebook. Fails with 404 and AETHER-FEED-003 when the caller is not
+// subscribed.
+func (c *Client) UnsubscribeNotebookDigest(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/notebooks/"+url.PathEscape(id)+"/digest", nil, nil, nil)
+}
+
+// UpdateAgent calls PUT /api/v1/agents/{id}.
+//
+// Update agent. Update an agent with Neo4j and agent-builder sync
+func (c *Client) UpdateAgent(ctx context.Context, id string, body AgentUpdateRequest) (*AgentResponse, error) {
+	out := new(AgentResponse)
+	if err := c.do(ctx, http.MethodPut, "/api/v1/agents/"+url.PathEscape(id), nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateClassificationPolicy calls PUT /api/v1/classification-policies/{id}.
+//
+// Update a classification policy. Change the given fields of a classification
+// policy; conditions and actions are replaced whole. Documents already
+// processed are not reclassified. Requires the owner or admin role.
+func (c *Client) UpdateClassificationPolicy(ctx context.Context, id string, body ClassificationPolicyUpdateRequest) (*ClassificationPolicy, error) {
+	out := new(ClassificationPolicy)
+	if err := c.do(ctx, http.MethodPut, "/api/v1/classification-policies/"+url.PathEscape(id), nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateCurrentUser calls PUT /api/v1/users/me.
+//
+// Update current user profile. Update the profile of the currently
+// authenticated user
+func (c *Client) UpdateCurrentUser(ctx context.Context, body UserUpdateRequest) (*UserResponse, error) {
+	out := new(UserResponse)
+	if err := c.do(ctx, http.MethodPut, "/api/v1/users/me", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateDocument calls PUT /api/v1/documents/{id}.
+//
+// Update document. Update document metadata
+func (c *Client) UpdateDocument(ctx context.Context, id string, body DocumentUpdateRequest) (*DocumentResponse, error) {
+	out := new(DocumentResponse)
+	if err := c.do(ctx, http.MethodPut, "/api/v1/documents/"+url.PathEscape(id), nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateExperiment calls PUT /api/v1/ml/experiments/{id}.
+//
+// Update ML experiment. Update an existing ML experiment
+func (c *Client) UpdateExperiment(ctx context.Context, id string, body UpdateExperimentRequest) (*MLExperiment, error) {
+	out := new(MLExperiment)
+	if err := c.do(ctx, http.MethodPut, "/api/v1/ml/experiments/"+url.PathEscape(id), nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateModel calls PUT /api/v1/ml/models/{id}.
+//
+// Update ML model. Update an existing ML model
+func (c *Client) UpdateModel(ctx context.Context, id string, body UpdateMLModelRequest) (*MLModel, error) {
+	out := new(MLModel)
+	if err := c.do(ctx, http.MethodPut, "/api/v1/ml/models/"+url.PathEscape(id), nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateNotebook calls PUT /api/v1/notebooks/{id}.
+//
+// Update notebook. Update notebook details
+func (c *Client) UpdateNotebook(ctx context.Context, id string, body NotebookUpdateRequest) (*NotebookResponse, error) {
+	out := new(NotebookResponse)
+	if err := c.do(ctx, http.MethodPut, "/api/v1/notebooks/"+url.PathEscape(id), nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateOrganization calls PUT /api/v1/organizations/{id}.
+//
+// Update organization. Update organization information
+func (c *Client) UpdateOrganization(ctx context.Context, id string, body OrganizationUpdateRequest) (*OrganizationResponse, error) {
+	out := new(OrganizationResponse)
+	if err := c.do(ctx, http.MethodPut, "/api/v1/organizations/"+url.PathEscape(id), nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateOrganizationMemberRole calls PUT
+// /api/v1/organizations/{id}/members/{user_id}.
+//
+// Update member role. Update an organization member's role, title, and
+// department
+func (c *Client) UpdateOrganizationMemberRole(ctx context.Context, id string, userID string, body OrganizationMemberRoleUpdateRequest) error {
+	return c.do(ctx, http.MethodPut, "/api/v1/organizations/"+url.PathEscape(id)+"/members/"+url.PathEscape(userID), nil, body, nil)
+}
+
+// UpdateOrganizationRole calls PUT /api/v1/organizations/{id}/roles/{role}.
+//
+// Update organization role. Change the description or the actions of a custom
+// role. Members holding the role are granted the new actions within the roles
+// refresh interval.
+func (c *Client) UpdateOrganizationRole(ctx context.Context, id string, role string, body RoleUpdateRequest) (*Role, error) {
+	out := new(Role)
+	if err := c.do(ctx, http.MethodPut, "/api/v1/organizations/"+url.PathEscape(id)+"/roles/"+url.PathEscape(role), nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateRuntimeConfig calls PATCH /api/v1/admin/config.
+//
+// Update runtime configuration. Change log level, rate limits, feature flags
+// or AudiModal timeouts without a restart
+func (c *Client) UpdateRuntimeConfig(ctx context.Context, body RuntimeConfig) (*RuntimeConfigResponse, error) {
+	out := new(RuntimeConfigResponse)
+	if err := c.do(ctx, http.MethodPatch, "/api/v1/admin/config", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateSpace calls PUT /api/v1/spaces/{id}.
+//
+// Update space. Update space details and settings. scanning.enabled turns
+// malware scanning of uploads on or off for the space; null follows the
+// deployment default, and turning it on fails with 400 when no scanner is
+// configured. file_validation restricts the files uploaded to the space:
+// allowed_types lists the MIME types it accepts, such as application/pdf or
+// image/*, on top of UPLOAD_ALLOWED_TYPES, and max_size_bytes bounds their
+// size.
+func (c *Client) UpdateSpace(ctx context.Context, id string, body SpaceUpdateRequest) (*SpaceFullResponse, error) {
+	out := new(SpaceFullResponse)
+	if err := c.do(ctx, http.MethodPut, "/api/v1/spaces/"+url.PathEscape(id), nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateSpaceEncryption calls PUT /api/v1/admin/spaces/{space_id}/encryption.
+//
+// Update space text encryption. Turn the encryption at rest of the extracted
+// text of a space's documents on or off, then encrypt, or decrypt, the text
+// its documents already have. Text is encrypted with AES-256-GCM under a key
+// per tenant and decrypted transparently for the users who can read the
+// documents; encrypted text is left out of search, so documents of encrypted
+// spaces are found by name, description and tags. Migration stops after about
+// 20 seconds and reports the documents remaining; repeating the request
+// carries on with them. The change is recorded in the audit log.
+func (c *Client) UpdateSpaceEncryption(ctx context.Context, spaceID string, body SpaceEncryptionUpdateRequest) (*SpaceEncryption, error) {
+	out := new(SpaceEncryption)
+	if err := c.do(ctx, http.MethodPut, "/api/v1/admin/spaces/"+url.PathEscape(spaceID)+"/encryption", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateSpaceMember calls PATCH /api/v1/spaces/{id}/members/{userId}.
+//
+// Update space member. Update a member's role in a space
+func (c *Client) UpdateSpaceMember(ctx context.Context, id string, userID string, body UpdateMemberRoleRequest) (*SpaceMemberResponse, error) {
+	out := new(SpaceMemberResponse)
+	if err := c.do(ctx, http.MethodPatch, "/api/v1/spaces/"+url.PathEscape(id)+"/members/"+url.PathEscape(userID), nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateSpaceQuotas calls PUT /api/v1/admin/spaces/{space_id}/quotas.
+//
+// Update space quotas. Change the limits of a space's quotas, such as after a
+// plan change; omitted limits keep their value. Limits default by space type.
+// The change is recorded in the audit log.
+func (c *Client) UpdateSpaceQuotas(ctx context.Context, spaceID string, body SpaceQuotasUpdateRequest) (*SpaceQuotas, error) {
+	out := new(SpaceQuotas)
+	if err := c.do(ctx, http.MethodPut, "/api/v1/admin/spaces/"+url.PathEscape(spaceID)+"/quotas", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateStreamSource calls PUT /api/v1/streams/sources/{id}.
+//
+// Update stream source. Update an existing stream source
+func (c *Client) UpdateStreamSource(ctx context.Context, id string, body UpdateStreamSourceRequest) (*StreamSource, error) {
+	out := new(StreamSource)
+	if err := c.do(ctx, http.MethodPut, "/api/v1/streams/sources/"+url.PathEscape(id), nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateStreamSourceStatus calls PUT /api/v1/streams/sources/{id}/status.
+//
+// Update stream source status. Update the status of a specific stream source
+// (activate/pause/disconnect)
+func (c *Client) UpdateStreamSourceStatus(ctx context.Context, id string, body map[string]interface{}) (*StreamSource, error) {
+	out := new(StreamSource)
+	if err := c.do(ctx, http.MethodPut, "/api/v1/streams/sources/"+url.PathEscape(id)+"/status", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateTeam calls PUT /api/v1/teams/{id}.
+//
+// Update team. Update team information
+func (c *Client) UpdateTeam(ctx context.Context, id string, body TeamUpdateRequest) (*TeamResponse, error) {
+	out := new(TeamResponse)
+	if err := c.do(ctx, http.MethodPut, "/api/v1/teams/"+url.PathEscape(id), nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateTeamMemberRole calls PUT /api/v1/teams/{id}/members/{user_id}.
+//
+// Update member role. Update a team member's role
+func (c *Client) UpdateTeamMemberRole(ctx context.Context, id string, userID string, body TeamMemberRoleUpdateRequest) error {
+	return c.do(ctx, http.MethodPut, "/api/v1/teams/"+url.PathEscape(id)+"/members/"+url.PathEscape(userID), nil, body, nil)
+}
+
+// UpdateUserPreferences calls PUT /api/v1/users/me/preferences.
+//
+// Update user preferences. Update user preferences and settings
+func (c *Client) UpdateUserPreferences(ctx context.Context, body UserPreferences) (*UserPreferences, error) {
+	out := new(UserPreferences)
+	if err := c.do(ctx, http.MethodPut, "/api/v1/users/me/preferences", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateUserPreferences2 calls GET /api/v1/users/me/preferences.
+//
+// Update user preferences. Update user preferences and settings
+func (c *Client) UpdateUserPreferences2(ctx context.Context, body UserPreferences) (*UserPreferences, error) {
+	out := new(UserPreferences)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/users/me/preferences", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateWebhookSubscription calls PUT /api/v1/webhook-subscriptions/{id}.
+//
+// Update a webhook subscription. Change the URL, events, description or state
+// of a webhook subscription of the current space; omitted fields are
+// unchanged. A paused subscription (active false) gets no new deliveries, and
+// the ones already queued are sent once it is active again. Fails with 404 and
+// AETHER-HOOK-004 when the subscription does not exist, and with 400 and
+// AETHER-HOOK-005 when the URL is not allowed. Requires the owner or admin
+// role.
+func (c *Client) UpdateWebhookSubscription(ctx context.Context, id string, body WebhookSubscriptionUpdateRequest) (*WebhookSubscription, error) {
+	out := new(WebhookSubscription)
+	if err := c.do(ctx, http.MethodPut, "/api/v1/webhook-subscriptions/"+url.PathEscape(id), nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateWorkflow calls PUT /api/v1/workflows/{id}.
+//
+// Update workflow. Update an existing workflow
+func (c *Client) UpdateWorkflow(ctx context.Context, id string, body UpdateWorkflowRequest) (*Workflow, error) {
+	out := new(Workflow)
+	if err := c.do(ctx, http.MethodPut, "/api/v1/workflows/"+url.PathEscape(id), nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateWorkflowStatus calls PUT /api/v1/workflows/{id}/status.
+//
+// Update workflow status. Update workflow status to active, paused, or
+// disabled
+func (c *Client) UpdateWorkflowStatus(ctx context.Context, id string, body map[string]string) (*Workflow, error) {
+	out := new(Workflow)
+	if err := c.do(ctx, http.MethodPut, "/api/v1/workflows/"+url.PathEscape(id)+"/status", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UploadDocumentBase64 calls POST /api/v1/documents/upload-base64.
+//
+// Upload document (base64). Upload a new document using base64 encoded
+// content. In spaces that scan uploads for malware, infected files are
+// rejected with 422 and AETHER-SCAN-001 and quarantined; when the scanner is
+// unavailable uploads fail with 503 and AETHER-SCAN-002. Files whose content
+// contradicts their declared MIME type or extension fail with 422 and
+// AETHER-FILE-001, files of a type the deployment or the space does not allow
+// with 415 and AETHER-FILE-002, and files over the space's size limit with 400
+// and AETHER-DOC-003. A missing or generic MIME type is replaced by the
+// detected one.
+func (c *Client) UploadDocumentBase64(ctx context.Context, body DocumentBase64UploadRequest) (*DocumentResponse, error) {
+	out := new(DocumentResponse)
+	if err := c.do(ctx, http.MethodPost, "/api/v1/documents/upload-base64", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// VerifyAuditLog calls GET /api/v1/admin/audit/verify.
+//
+// Verify audit log. Recompute the audit hash chain and report the first entry
+// that was altered or removed
+func (c *Client) VerifyAuditLog(ctx context.Context) (*AuditVerifyResponse, error) {
+	out := new(AuditVerifyResponse)
+	if err := c.do(ctx, http.MethodGet, "/api/v1/admin/audit/verify", nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,34 @@
+package client
+
+import (
+	"context"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// KeycloakConfig identifies a Keycloak client whose service account calls
+// the API
+type KeycloakConfig struct {
+	// URL is the Keycloak base URL, e.g. https://keycloak.example.com
+	URL          string
+	Realm        string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
+// KeycloakTokenSource returns a token source that obtains tokens with the
+// client credentials grant and refreshes them before they expire. ctx is
+// used for the token requests and may carry an *http.Client under
+// oauth2.HTTPClient.
+func KeycloakTokenSource(ctx context.Context, cfg KeycloakConfig) oauth2.TokenSource {
+	credentials := clientcredentials.Config{
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		TokenURL:     strings.TrimSuffix(cfg.URL, "/") + "/realms/" + cfg.Realm + "/protocol/openid-connect/token",
+		Scopes:       cfg.Scopes,
+	}
+	return credentials.TokenSource(ctx)
+}
@@ -0,0 +1,335 @@
+// Package client is the Go client of the Aether API.
+//
+// The types and most methods in api_gen.go are generated from the OpenAPI
+// document served at /api/v1/openapi.json; document uploads, NDJSON
+// exports and WebSocket streams are implemented by hand. Regenerate the
+// client with make generate after changing a handler annotation.
+//
+// A Client authenticates every request with a bearer token, retries
+// requests the server asks to be retried and decodes error envelopes into
+// *Error:
+//
+//	c, err := client.New("https://aether.example.com",
+//		client.WithTokenSource(client.KeycloakTokenSource(ctx, client.KeycloakConfig{...})))
+//	notebooks, err := c.WithSpace(client.SpaceTypeOrganization, spaceID).ListNotebooks(ctx, nil)
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/oauth2"
+)
+
+// Client calls the Aether API. It is safe for concurrent use.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	tokens     oauth2.TokenSource
+	retry      RetryPolicy
+	userAgent  string
+	spaceType  SpaceType
+	spaceID    string
+}
+
+// RetryPolicy controls how failed requests are retried. Requests are
+// retried when the server answers 429, or 503 with a Retry-After header.
+// Idempotent requests (GET, HEAD, PUT, DELETE, OPTIONS) are also retried on
+// 502, 503 and 504 and on network errors.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts including the first; 1
+	// disables retries
+	MaxAttempts int
+	// MinBackoff is the wait before the first retry, doubled for each
+	// further retry up to MaxBackoff. A Retry-After header overrides it.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy is the retry policy of new clients
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	MinBackoff:  250 * time.Millisecond,
+	MaxBackoff:  10 * time.Second,
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client requests are sent with
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+// WithToken authenticates requests with a fixed bearer token
+func WithToken(token string) Option {
+	return WithTokenSource(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}))
+}
+
+// WithTokenSource authenticates requests with tokens from ts, which should
+// cache and refresh them, e.g. KeycloakTokenSource
+func WithTokenSource(ts oauth2.TokenSource) Option {
+	return func(c *Client) { c.tokens = ts }
+}
+
+// WithRetry sets the retry policy
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) { c.retry = policy }
+}
+
+// WithUserAgent sets the User-Agent header
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) { c.userAgent = userAgent }
+}
+
+// New creates a client of the API at baseURL, e.g.
+// https://aether.example.com
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("parse base URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("base URL %q must be http or https", baseURL)
+	}
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: http.DefaultClient,
+		retry:      DefaultRetryPolicy,
+		userAgent:  "aether-go-client/" + APIVersion,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.retry.MaxAttempts < 1 {
+		c.retry.MaxAttempts = 1
+	}
+	return c, nil
+}
+
+// WithSpace returns a copy of the client whose requests act in the given
+// space, sent as the X-Space-Type and X-Space-ID headers
+func (c *Client) WithSpace(spaceType SpaceType, spaceID string) *Client {
+	copied := *c
+	copied.spaceType = spaceType
+	copied.spaceID = spaceID
+	return &copied
+}
+
+// do sends a request with a JSON body and decodes a JSON response into out
+func (c *Client) do(ctx context.Context, method, path string, params, body, out interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("encode request body: %w", err)
+		}
+	}
+
+	resp, err := c.send(ctx, method, path, params, func() (io.Reader, string) {
+		if payload == nil {
+			return nil, ""
+		}
+		return bytes.NewReader(payload), "application/json"
+	}, true)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	return decodeJSON(resp, out)
+}
+
+// decodeJSON decodes a JSON response into out. A 204 leaves out as is.
+func decodeJSON(resp *http.Response, out interface{}) error {
+	if resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode %s %s response: %w", resp.Request.Method, resp.Request.URL.Path, err)
+	}
+	return nil
+}
+
+// stream sends a request without a body and returns the response for the
+// caller to read and close
+func (c *Client) stream(ctx context.Context, method, path string, params interface{}) (*http.Response, error) {
+	return c.send(ctx, method, path, params, func() (io.Reader, string) { return nil, "" }, true)
+}
+
+// send sends a request, retrying it as the retry policy allows, and turns
+// non-2xx responses into *Error. body is called for each attempt; a
+// request whose body cannot be replayed passes replayable false.
+func (c *Client) send(ctx context.Context, method, path string, params interface{}, body func() (io.Reader, string), replayable bool) (*http.Response, error) {
+	target := *c.baseURL
+	target.Path += path
+	if params != nil {
+		query, err := encodeQuery(params)
+		if err != nil {
+			return nil, err
+		}
+		target.RawQuery = query.Encode()
+	}
+
+	// The same request ID is sent on every attempt so the server logs of
+	// the retries can be correlated
+	requestID := uuid.New().String()
+	idempotent := isIdempotent(method)
+
+	for attempt := 1; ; attempt++ {
+		reader, contentType := body()
+		req, err := http.NewRequestWithContext(ctx, method, target.String(), reader)
+		if err != nil {
+			return nil, fmt.Errorf("create request: %w", err)
+		}
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if err := c.setHeaders(req); err != nil {
+			return nil, err
+		}
+		req.Header.Set("X-Request-ID", requestID)
+
+		resp, err := c.httpClient.Do(req)
+		last := attempt >= c.retry.MaxAttempts || !replayable
+		if err != nil {
+			if last || !idempotent || ctx.Err() != nil {
+				return nil, err
+			}
+			if err := c.wait(ctx, attempt, ""); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if resp.StatusCode < 300 {
+			return resp, nil
+		}
+
+		if !last && shouldRetry(resp, idempotent) {
+			retryAfter := resp.Header.Get("Retry-After")
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			if err := c.wait(ctx, attempt, retryAfter); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		err = decodeError(resp)
+		resp.Body.Close()
+		return nil, err
+	}
+}
+
+// setHeaders sets the auth, space and client headers of a request
+func (c *Client) setHeaders(req *http.Request) error {
+	if c.tokens != nil {
+		token, err := c.tokens.Token()
+		if err != nil {
+			return fmt.Errorf("get access token: %w", err)
+		}
+		token.SetAuthHeader(req)
+	}
+	if c.spaceID != "" {
+		req.Header.Set("X-Space-Type", string(c.spaceType))
+		req.Header.Set("X-Space-ID", c.spaceID)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	return nil
+}
+
+// wait sleeps before the next attempt: the server's Retry-After when given
+// in seconds, otherwise an exponential backoff with jitter
+func (c *Client) wait(ctx context.Context, attempt int, retryAfter string) error {
+	delay := c.retry.MinBackoff << (attempt - 1)
+	if delay > c.retry.MaxBackoff || delay <= 0 {
+		delay = c.retry.MaxBackoff
+	}
+	delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+		delay = time.Duration(seconds) * time.Second
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// shouldRetry reports whether a failed response may be retried. 429 and
+// 503 with Retry-After are sent before the request is handled, so they are
+// safe to retry for any method.
+func shouldRetry(resp *http.Response, idempotent bool) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusServiceUnavailable:
+		return idempotent || resp.Header.Get("Retry-After") != ""
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return idempotent
+	}
+	return false
+}
+
+// encodeQuery encodes a params struct by its query tags. Zero values are
+// left out unless the parameter is required; slices repeat the parameter.
+func encodeQuery(params interface{}) (url.Values, error) {
+	query := url.Values{}
+	v := reflect.ValueOf(params)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return query, nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("query parameters must be a struct, got %s", v.Kind())
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		tag := v.Type().Field(i).Tag.Get("query")
+		if tag == "" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		field := v.Field(i)
+		if field.IsZero() && options != "required" {
+			continue
+		}
+		if field.Kind() == reflect.Slice {
+			for j := 0; j < field.Len(); j++ {
+				query.Add(name, fmt.Sprint(field.Index(j).Interface()))
+			}
+			continue
+		}
+		query.Set(name, fmt.Sprint(field.Interface()))
+	}
+	return query, nil
+}
@@ -0,0 +1,218 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tributary-ai-services/aether-be/internal/openapi"
+)
+
+var fastRetry = RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	c, err := New(server.URL, WithToken("token-1"), WithRetry(fastRetry))
+	require.NoError(t, err)
+	return c
+}
+
+func TestRequestsCarryAuthSpaceAndQuery(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/notebooks/search", r.URL.Path)
+		assert.Equal(t, "Bearer token-1", r.Header.Get("Authorization"))
+		assert.Equal(t, "organization", r.Header.Get("X-Space-Type"))
+		assert.Equal(t, "space-1", r.Header.Get("X-Space-ID"))
+		assert.Equal(t, "aether-go-client/"+APIVersion, r.Header.Get("User-Agent"))
+		// Zero values are left out and lists repeat the parameter
+		assert.Equal(t, "query=research&tags=a&tags=b", r.URL.RawQuery)
+		json.NewEncoder(w).Encode(map[string]interface{}{"total": 1, "notebooks": []map[string]string{{"id": "nb-1"}}})
+	})
+
+	notebooks, err := c.WithSpace(SpaceTypeOrganization, "space-1").SearchNotebooks(context.Background(), &SearchNotebooksParams{Query: "research", Tags: []string{"a", "b"}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, notebooks.Total)
+	require.Len(t, notebooks.Notebooks, 1)
+	assert.Equal(t, "nb-1", notebooks.Notebooks[0].ID)
+}
+
+func TestRetriesKeepTheRequestID(t *testing.T) {
+	var attempts int32
+	var requestIDs []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requestIDs = append(requestIDs, r.Header.Get("X-Request-ID"))
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"name":"Research","visibility":"private"}`, string(body))
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"nb-1","name":"Research"}`))
+	})
+
+	notebook, err := c.CreateNotebook(context.Background(), NotebookCreateRequest{Name: "Research", Visibility: "private"})
+	require.NoError(t, err)
+	assert.Equal(t, "nb-1", notebook.ID)
+	require.Len(t, requestIDs, 3)
+	assert.NotEmpty(t, requestIDs[0])
+	assert.Equal(t, requestIDs[0], requestIDs[1])
+	assert.Equal(t, requestIDs[0], requestIDs[2])
+}
+
+func TestGatewayErrorsRetryOnlyIdempotentRequests(t *testing.T) {
+	var attempts int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	err := c.DeleteNotebook(context.Background(), "nb-1")
+	require.Error(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+
+	atomic.StoreInt32(&attempts, 0)
+	_, err = c.CreateNotebook(context.Background(), NotebookCreateRequest{Name: "Research"})
+	require.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+}
+
+func TestErrorEnvelopesAreDecoded(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"code":"NOT_FOUND","error_code":"AETHER-DOC-001","message":"Document not found","request_id":"req-1"}`))
+	})
+
+	_, err := c.GetDocument(context.Background(), "doc-1")
+	require.Error(t, err)
+	assert.True(t, IsNotFound(err))
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "AETHER-DOC-001", apiErr.ErrorCode)
+	assert.Equal(t, "req-1", apiErr.RequestID)
+	assert.Equal(t, "aether: 404 NOT_FOUND (AETHER-DOC-001): Document not found", err.Error())
+}
+
+func TestUploadDocumentStreamsMultipart(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/documents/upload", r.URL.Path)
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, "nb-1", r.FormValue("notebook_id"))
+		assert.Equal(t, "a,b", r.FormValue("tags"))
+		file, header, err := r.FormFile("file")
+		require.NoError(t, err)
+		data, _ := io.ReadAll(file)
+		assert.Equal(t, "report.pdf", header.Filename)
+		assert.Equal(t, "application/pdf", header.Header.Get("Content-Type"))
+		assert.Equal(t, "%PDF-1.7", string(data))
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"doc-1","name":"report.pdf"}`))
+	})
+
+	doc, err := c.UploadDocument(context.Background(), UploadDocumentRequest{
+		NotebookID:  "nb-1",
+		FileName:    "report.pdf",
+		ContentType: "application/pdf",
+		File:        strings.NewReader("%PDF-1.7"),
+		Tags:        []string{"a", "b"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "doc-1", doc.ID)
+}
+
+func TestExportNotebookDocumentsStreamsItems(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Write([]byte("{\"id\":\"doc-1\"}\n{\"id\":\"doc-2\"}\n"))
+	})
+
+	stream, err := c.ExportNotebookDocuments(context.Background(), "nb-1")
+	require.NoError(t, err)
+	defer stream.Close()
+	var ids []string
+	for stream.Next() {
+		ids = append(ids, stream.Value().ID)
+	}
+	require.NoError(t, stream.Err())
+	assert.Equal(t, []string{"doc-1", "doc-2"}, ids)
+}
+
+func TestStreamJobStatusDialsWebSocket(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"code":"UNAUTHORIZED","message":"User not authenticated"}`))
+			return
+		}
+		assert.Equal(t, "/api/v1/jobs/job-1/stream", r.URL.Path)
+		conn, err := upgrader.Upgrade(w, r, nil)
+		require.NoError(t, err)
+		defer conn.Close()
+		conn.WriteJSON(map[string]interface{}{"type": "status", "job_id": "job-1"})
+	})
+
+	stream, err := c.StreamJobStatus(context.Background(), "job-1")
+	require.NoError(t, err)
+	defer stream.Close()
+	var message map[string]interface{}
+	require.NoError(t, stream.Next(&message))
+	assert.Equal(t, "job-1", message["job_id"])
+
+	unauthenticated := *c
+	unauthenticated.tokens = nil
+	_, err = unauthenticated.StreamJobStatus(context.Background(), "job-1")
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+}
+
+func TestWaitForJobPollsUntilFinished(t *testing.T) {
+	var polls int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/jobs/job-1", r.URL.Path)
+		status := "running"
+		if atomic.AddInt32(&polls, 1) == 3 {
+			status = "succeeded"
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":     "job-1",
+			"status": status,
+			"result": map[string]interface{}{"succeeded": 2},
+		})
+	})
+
+	job, err := c.WaitForJob(context.Background(), "job-1", time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, JobStatusSucceeded, job.Status)
+	assert.Equal(t, float64(2), job.Result["succeeded"])
+	assert.Equal(t, int32(3), atomic.LoadInt32(&polls))
+}
+
+// TestEveryOperationHasAMethod fails when an operation of the spec has no
+// generated or hand-written method
+func TestEveryOperationHasAMethod(t *testing.T) {
+	var doc openapi.Document
+	require.NoError(t, json.Unmarshal(openapi.Spec(), &doc))
+	clientType := reflect.TypeOf(&Client{})
+	for path, item := range doc.Paths {
+		for method, op := range item {
+			_, ok := clientType.MethodByName(op.OperationID)
+			assert.True(t, ok, "%s %s: no method %s", strings.ToUpper(method), path, op.OperationID)
+		}
+	}
+}
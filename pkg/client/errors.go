@@ -0,0 +1,54 @@
+package client
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Error is a non-2xx response of the API. The embedded APIError is the
+// decoded error envelope; ErrorCode is the stable catalogue code, e.g.
+// AETHER-DOC-001.
+type Error struct {
+	StatusCode int
+	APIError
+}
+
+func (e *Error) Error() string {
+	if e.ErrorCode != "" {
+		return fmt.Sprintf("aether: %d %s (%s): %s", e.StatusCode, e.Code, e.ErrorCode, e.Message)
+	}
+	return fmt.Sprintf("aether: %d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// IsNotFound reports whether err is a 404 response
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsConflict reports whether err is a 409 response
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+func hasStatus(err error, statusCode int) bool {
+	var apiErr *Error
+	return stderrors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
+
+// decodeError reads the error envelope of a failed response. Responses
+// without one, such as those of a proxy, keep their status text.
+func decodeError(resp *http.Response) error {
+	apiErr := &Error{StatusCode: resp.StatusCode}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err := json.Unmarshal(body, &apiErr.APIError); err != nil || apiErr.Message == "" {
+		apiErr.Code = http.StatusText(resp.StatusCode)
+		apiErr.Message = string(body)
+	}
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = resp.Header.Get("X-Request-ID")
+	}
+	return apiErr
+}
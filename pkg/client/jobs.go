@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// StartBatch calls POST /api/v1/batch with async set.
+//
+// Run a batch of requests as a job. The job's result holds the
+// BatchResponse once it has finished; see WaitForJob.
+func (c *Client) StartBatch(ctx context.Context, operations []*BatchOperation) (*Job, error) {
+	out := new(Job)
+	body := BatchRequest{Operations: operations, Async: true}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/batch", nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WaitForJob polls an async job every interval until it has succeeded or
+// failed, or ctx is done. A failed job is returned without an error; its
+// Error says why it failed.
+func (c *Client) WaitForJob(ctx context.Context, id string, interval time.Duration) (*Job, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		job, err := c.GetJobStatus(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if job.Status == JobStatusSucceeded || job.Status == JobStatusFailed {
+			return job, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
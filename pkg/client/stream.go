@@ -0,0 +1,158 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// NDJSONStream reads a newline-delimited JSON response one item at a time:
+//
+//	for stream.Next() {
+//		doc := stream.Value()
+//	}
+//	if err := stream.Err(); err != nil { ... }
+type NDJSONStream[T any] struct {
+	body    io.ReadCloser
+	decoder *json.Decoder
+	value   T
+	err     error
+}
+
+func newNDJSONStream[T any](body io.ReadCloser) *NDJSONStream[T] {
+	return &NDJSONStream[T]{body: body, decoder: json.NewDecoder(bufio.NewReader(body))}
+}
+
+// Next decodes the next item, returning false at the end of the stream or
+// on an error
+func (s *NDJSONStream[T]) Next() bool {
+	if s.err != nil {
+		return false
+	}
+	var value T
+	if err := s.decoder.Decode(&value); err != nil {
+		if err != io.EOF {
+			s.err = fmt.Errorf("decode stream item: %w", err)
+		}
+		return false
+	}
+	s.value = value
+	return true
+}
+
+// Value returns the item decoded by the last call to Next
+func (s *NDJSONStream[T]) Value() T {
+	return s.value
+}
+
+// Err returns the error that ended the stream, if any
+func (s *NDJSONStream[T]) Err() error {
+	return s.err
+}
+
+// Close closes the response body
+func (s *NDJSONStream[T]) Close() error {
+	return s.body.Close()
+}
+
+// WebSocketStream receives the JSON messages of a WebSocket stream
+type WebSocketStream struct {
+	conn *websocket.Conn
+}
+
+// Next decodes the next message into v, e.g. a map[string]interface{}
+func (s *WebSocketStream) Next(v interface{}) error {
+	return s.conn.ReadJSON(v)
+}
+
+// SendJSON sends v as a JSON message, e.g. a subscribe request
+func (s *WebSocketStream) SendJSON(v interface{}) error {
+	return s.conn.WriteJSON(v)
+}
+
+// Close closes the connection
+func (s *WebSocketStream) Close() error {
+	return s.conn.Close()
+}
+
+// StreamEventsParams filter the live events of StreamEvents. The lists
+// are comma-separated.
+type StreamEventsParams struct {
+	SourceIDs     string  `query:"source_ids"`
+	EventTypes    string  `query:"event_types"`
+	MediaTypes    string  `query:"media_types"`
+	Sentiments    string  `query:"sentiments"`
+	MinConfidence float64 `query:"min_confidence"`
+}
+
+// StreamDocumentStatus calls GET /api/v1/documents/{id}/stream.
+//
+// Stream document processing status updates over a WebSocket.
+func (c *Client) StreamDocumentStatus(ctx context.Context, id string) (*WebSocketStream, error) {
+	return c.dial(ctx, "/api/v1/documents/"+url.PathEscape(id)+"/stream", nil)
+}
+
+// StreamJobStatus calls GET /api/v1/jobs/{id}/stream.
+//
+// Stream job status updates over a WebSocket.
+func (c *Client) StreamJobStatus(ctx context.Context, id string) (*WebSocketStream, error) {
+	return c.dial(ctx, "/api/v1/jobs/"+url.PathEscape(id)+"/stream", nil)
+}
+
+// StreamEvents calls GET /api/v1/streams/live.
+//
+// Stream live events over a WebSocket.
+func (c *Client) StreamEvents(ctx context.Context, params *StreamEventsParams) (*WebSocketStream, error) {
+	return c.dial(ctx, "/api/v1/streams/live", params)
+}
+
+// SubscribeEvents calls GET /api/v1/ws/events.
+//
+// Stream real-time events over a WebSocket. Send subscribe messages, e.g.
+// {"action":"subscribe","topic":"documents","space_type":"personal","space_id":"..."},
+// with SendJSON to choose which events arrive.
+func (c *Client) SubscribeEvents(ctx context.Context) (*WebSocketStream, error) {
+	return c.dial(ctx, "/api/v1/ws/events", nil)
+}
+
+// dial opens a WebSocket with the headers of a regular request. A refused
+// upgrade is returned as *Error.
+func (c *Client) dial(ctx context.Context, path string, params interface{}) (*WebSocketStream, error) {
+	target := *c.baseURL
+	target.Path += path
+	if target.Scheme == "https" {
+		target.Scheme = "wss"
+	} else {
+		target.Scheme = "ws"
+	}
+	if params != nil {
+		query, err := encodeQuery(params)
+		if err != nil {
+			return nil, err
+		}
+		target.RawQuery = query.Encode()
+	}
+
+	req := &http.Request{Header: make(http.Header)}
+	if err := c.setHeaders(req); err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Request-ID", uuid.New().String())
+
+	conn, resp, err := websocket.DefaultDialer.DialContext(ctx, target.String(), req.Header)
+	if err != nil {
+		if resp != nil && resp.StatusCode != http.StatusSwitchingProtocols {
+			defer resp.Body.Close()
+			return nil, decodeError(resp)
+		}
+		return nil, err
+	}
+	return &WebSocketStream{conn: conn}, nil
+}
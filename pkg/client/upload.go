@@ -0,0 +1,281 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// UploadDocumentRequest is a multipart document upload
+type UploadDocumentRequest struct {
+	NotebookID  string
+	FileName    string
+	ContentType string // Defaults to application/octet-stream
+	// File is streamed to the server, so the upload is not retried
+	File        io.Reader
+	Name        string // Defaults to FileName
+	Description string
+	Tags        []string
+}
+
+// UploadDocument calls POST /api/v1/documents/upload.
+//
+// Upload document. Upload a file into a notebook; the file is streamed
+// rather than buffered.
+func (c *Client) UploadDocument(ctx context.Context, req UploadDocumentRequest) (*DocumentResponse, error) {
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(writeUploadForm(writer, req))
+	}()
+
+	resp, err := c.send(ctx, http.MethodPost, "/api/v1/documents/upload", nil, func() (io.Reader, string) {
+		return pr, writer.FormDataContentType()
+	}, false)
+	// Unblock the writer if the request ended before reading the body
+	pr.Close()
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	out := new(DocumentResponse)
+	if err := decodeJSON(resp, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UploadDocumentVersion calls POST /api/v1/documents/{id}/versions.
+//
+// Upload a document version. Upload a new file for an existing document as
+// its next, current version; the file is streamed, so the upload is not
+// retried.
+func (c *Client) UploadDocumentVersion(ctx context.Context, id, fileName, contentType string, file io.Reader) (*DocumentVersion, error) {
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(writeUploadForm(writer, UploadDocumentRequest{FileName: fileName, ContentType: contentType, File: file}))
+	}()
+
+	resp, err := c.send(ctx, http.MethodPost, "/api/v1/documents/"+url.PathEscape(id)+"/versions", nil, func() (io.Reader, string) {
+		return pr, writer.FormDataContentType()
+	}, false)
+	// Unblock the writer if the request ended before reading the body
+	pr.Close()
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	out := new(DocumentVersion)
+	if err := decodeJSON(resp, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// quoteEscaper escapes quoted strings in multipart headers
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// writeUploadForm writes the fields and then the file, so the server has
+// the fields when the file arrives
+func writeUploadForm(writer *multipart.Writer, req UploadDocumentRequest) error {
+	fields := [][2]string{
+		{"notebook_id", req.NotebookID},
+		{"name", req.Name},
+		{"description", req.Description},
+		{"tags", strings.Join(req.Tags, ",")},
+	}
+	for _, field := range fields {
+		if field[1] == "" {
+			continue
+		}
+		if err := writer.WriteField(field[0], field[1]); err != nil {
+			return err
+		}
+	}
+
+	contentType := req.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="file"; filename="`+quoteEscaper.Replace(req.FileName)+`"`)
+	header.Set("Content-Type", contentType)
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, req.File); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// BulkUploadFile is one file of a bulk upload
+type BulkUploadFile struct {
+	FileName    string
+	ContentType string // Defaults to application/octet-stream
+	File        io.Reader
+}
+
+// BulkUploadDocumentsRequest is a multipart bulk document upload
+type BulkUploadDocumentsRequest struct {
+	NotebookID string
+	// Files are streamed to the server, so the upload is not retried
+	Files []BulkUploadFile
+	Tags  []string
+}
+
+// BulkUploadDocuments calls POST /api/v1/notebooks/{id}/documents/bulk.
+//
+// Bulk upload documents. Upload several files into a notebook in one
+// request; the response reports each file's document or error.
+func (c *Client) BulkUploadDocuments(ctx context.Context, req BulkUploadDocumentsRequest) (*BulkUploadResponse, error) {
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(writeBulkUploadForm(writer, req))
+	}()
+
+	resp, err := c.send(ctx, http.MethodPost, "/api/v1/notebooks/"+url.PathEscape(req.NotebookID)+"/documents/bulk", nil, func() (io.Reader, string) {
+		return pr, writer.FormDataContentType()
+	}, false)
+	// Unblock the writer if the request ended before reading the body
+	pr.Close()
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	out := new(BulkUploadResponse)
+	if err := decodeJSON(resp, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// writeBulkUploadForm writes the tags and then every file as a "files" part
+func writeBulkUploadForm(writer *multipart.Writer, req BulkUploadDocumentsRequest) error {
+	if len(req.Tags) > 0 {
+		if err := writer.WriteField("tags", strings.Join(req.Tags, ",")); err != nil {
+			return err
+		}
+	}
+
+	for _, file := range req.Files {
+		contentType := file.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", `form-data; name="files"; filename="`+quoteEscaper.Replace(file.FileName)+`"`)
+		header.Set("Content-Type", contentType)
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(part, file.File); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+// StartImportRequest is a multipart import of an export archive
+type StartImportRequest struct {
+	Format   string // notion, google_drive or sharepoint
+	FileName string
+	// File is the zip archive, streamed to the server, so the import is
+	// not retried
+	File       io.Reader
+	Name       string // Defaults to FileName
+	NotebookID string // Notebook to create the import's notebook in
+}
+
+// StartImport calls POST /api/v1/imports.
+//
+// Import an export archive. Import the zip export of another tool into a
+// new notebook as an async job; poll the returned job for the outcome of
+// every archive entry.
+func (c *Client) StartImport(ctx context.Context, req StartImportRequest) (*Job, error) {
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(writeImportForm(writer, req))
+	}()
+
+	resp, err := c.send(ctx, http.MethodPost, "/api/v1/imports", nil, func() (io.Reader, string) {
+		return pr, writer.FormDataContentType()
+	}, false)
+	// Unblock the writer if the request ended before reading the body
+	pr.Close()
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	out := new(Job)
+	if err := decodeJSON(resp, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// writeImportForm writes the fields and then the archive
+func writeImportForm(writer *multipart.Writer, req StartImportRequest) error {
+	fields := [][2]string{
+		{"format", req.Format},
+		{"name", req.Name},
+		{"notebook_id", req.NotebookID},
+	}
+	for _, field := range fields {
+		if field[1] == "" {
+			continue
+		}
+		if err := writer.WriteField(field[0], field[1]); err != nil {
+			return err
+		}
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="file"; filename="`+quoteEscaper.Replace(req.FileName)+`"`)
+	header.Set("Content-Type", "application/zip")
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, req.File); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// UploadPart calls PUT /api/v1/uploads/{id}/parts/{number}.
+//
+// Upload a part. Store a part of a resumable upload; parts are numbered
+// from 1 and every part but the last is the upload's part_size bytes. The
+// part is held in memory, so the request is retried as PUT requests are.
+func (c *Client) UploadPart(ctx context.Context, id string, number int, data []byte) (*UploadSessionPart, error) {
+	path := "/api/v1/uploads/" + url.PathEscape(id) + "/parts/" + strconv.Itoa(number)
+	resp, err := c.send(ctx, http.MethodPut, path, nil, func() (io.Reader, string) {
+		return bytes.NewReader(data), "application/octet-stream"
+	}, true)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	out := new(UploadSessionPart)
+	if err := decodeJSON(resp, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
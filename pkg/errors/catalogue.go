@@ -0,0 +1,414 @@
+package errors
+
+// Catalogue codes identify an error condition independently of its
+// message. They are stable: once published a code keeps its meaning and is
+// never reused, so clients can branch on it instead of parsing messages.
+// Every code is documented in docs/ERROR_CODES.md.
+const (
+	// General codes, the default for each error type
+	CodeBadRequest           = "AETHER-GEN-001"
+	CodeUnauthorized         = "AETHER-GEN-002"
+	CodeForbidden            = "AETHER-GEN-003"
+	CodeNotFound             = "AETHER-GEN-004"
+	CodeMethodNotAllowed     = "AETHER-GEN-005"
+	CodeConflict             = "AETHER-GEN-006"
+	CodeUnprocessableEntity  = "AETHER-GEN-007"
+	CodeTooManyRequests      = "AETHER-GEN-008"
+	CodeInternal             = "AETHER-GEN-009"
+	CodeBadGateway           = "AETHER-GEN-010"
+	CodeServiceUnavailable   = "AETHER-GEN-011"
+	CodeGatewayTimeout       = "AETHER-GEN-012"
+	CodeValidation           = "AETHER-GEN-013"
+	CodeDatabase             = "AETHER-GEN-014"
+	CodeExternalService      = "AETHER-GEN-015"
+	CodePayloadTooLarge      = "AETHER-GEN-016"
+	CodeGone                 = "AETHER-GEN-017"
+	CodePaymentRequired      = "AETHER-GEN-018"
+	CodeUnsupportedMediaType = "AETHER-GEN-019"
+	CodeMisdirectedRequest   = "AETHER-GEN-020"
+	CodeRangeNotSatisfiable  = "AETHER-GEN-021"
+
+	// API versions
+	CodeAPIVersionUnsupported      = "AETHER-API-001"
+	CodeAPIVersionSunset           = "AETHER-API-002"
+	CodeContentEncodingUnsupported = "AETHER-API-003"
+
+	// Authentication and spaces
+	CodeNotAuthenticated     = "AETHER-AUTH-001"
+	CodePermissionDenied     = "AETHER-AUTH-002"
+	CodeSpaceContextRequired = "AETHER-SPACE-001"
+	CodeSpaceAccessDenied    = "AETHER-SPACE-002"
+	CodeTenantNotFound       = "AETHER-SPACE-003"
+
+	// Notebooks
+	CodeNotebookNotFound      = "AETHER-NB-001"
+	CodeNotebookNotAccessible = "AETHER-NB-002"
+	CodeShareTargetNotFound   = "AETHER-NB-003"
+	CodeShareNotFound         = "AETHER-NB-004"
+
+	// Documents
+	CodeDocumentNotFound         = "AETHER-DOC-001"
+	CodeDocumentIDRequired       = "AETHER-DOC-002"
+	CodeFileTooLarge             = "AETHER-DOC-003"
+	CodeProcessingInProgress     = "AETHER-DOC-004"
+	CodeFileNotProcessed         = "AETHER-DOC-005"
+	CodeDocumentVersionNotFound  = "AETHER-DOC-006"
+	CodeDocumentDeletionNotFound = "AETHER-DOC-007"
+	CodeOriginalFileRestricted   = "AETHER-DOC-008"
+	CodePriorityNotAllowed       = "AETHER-DOC-009"
+	CodeCompareTooLarge          = "AETHER-DOC-010"
+
+	// Trash
+	CodeNotInTrash      = "AETHER-TRASH-001"
+	CodeNotebookInTrash = "AETHER-TRASH-002"
+
+	// Resumable and direct uploads
+	CodeUploadNotFound    = "AETHER-UPLOAD-001"
+	CodeUploadNotActive   = "AETHER-UPLOAD-002"
+	CodeInvalidUploadPart = "AETHER-UPLOAD-003"
+	CodeUploadIncomplete  = "AETHER-UPLOAD-004"
+	CodeUploadNotReceived = "AETHER-UPLOAD-005"
+	CodeUploadMismatch    = "AETHER-UPLOAD-006"
+
+	// Graph queries and search filters
+	CodeQueryTooExpensive = "AETHER-QUERY-001"
+	CodeInvalidFilter     = "AETHER-QUERY-002"
+	CodeInvalidGraphToken = "AETHER-QUERY-003"
+
+	// Chunks and chunking strategies
+	CodeChunkNotFound      = "AETHER-CHUNK-001"
+	CodeChunkProcessing    = "AETHER-CHUNK-002"
+	CodeChunkEmbedding     = "AETHER-CHUNK-003"
+	CodeChunkValidation    = "AETHER-CHUNK-004"
+	CodeStrategy           = "AETHER-CHUNK-005"
+	CodeStrategyNotFound   = "AETHER-CHUNK-006"
+	CodeStrategyValidation = "AETHER-CHUNK-007"
+
+	// Vector search
+	CodeVectorSearchDisabled = "AETHER-VECTOR-001"
+	CodeVectorSyncDisabled   = "AETHER-VECTOR-002"
+
+	// Entities
+	CodeEntityNotFound     = "AETHER-ENTITY-001"
+	CodeEntityMergeInvalid = "AETHER-ENTITY-002"
+
+	// Summaries
+	CodeSummaryBudgetExhausted = "AETHER-SUMMARY-001"
+	CodeNothingToSummarize     = "AETHER-SUMMARY-002"
+
+	// Classification policies
+	CodeClassificationPolicyNotFound = "AETHER-POLICY-001"
+	CodeInvalidClassificationPolicy  = "AETHER-POLICY-002"
+
+	// Other resources
+	CodeUserNotFound            = "AETHER-USER-001"
+	CodeOrganizationNotFound    = "AETHER-ORG-001"
+	CodeTeamNotFound            = "AETHER-TEAM-001"
+	CodeAgentNotFound           = "AETHER-AGENT-001"
+	CodeWorkflowNotFound        = "AETHER-WF-001"
+	CodeStreamSourceNotFound    = "AETHER-STREAM-001"
+	CodeMLModelNotFound         = "AETHER-ML-001"
+	CodeMLExperimentNotFound    = "AETHER-ML-002"
+	CodeScheduledJobNotFound    = "AETHER-JOB-001"
+	CodeRetryJobNotFound        = "AETHER-JOB-002"
+	CodeJobNotFound             = "AETHER-JOB-003"
+	CodeProcessingJobNotFound   = "AETHER-JOB-004"
+	CodeProcessingJobTransition = "AETHER-JOB-005"
+
+	// Inbound webhooks
+	CodeWebhookSignatureInvalid = "AETHER-HOOK-001"
+	CodeWebhookExpired          = "AETHER-HOOK-002"
+	CodeWebhookNotConfigured    = "AETHER-HOOK-003"
+
+	// Outbound webhooks
+	CodeWebhookSubscriptionNotFound = "AETHER-HOOK-004"
+	CodeWebhookTargetNotAllowed     = "AETHER-HOOK-005"
+
+	// Feeds
+	CodeFeedTokenInvalid           = "AETHER-FEED-001"
+	CodeFeedTokenNotFound          = "AETHER-FEED-002"
+	CodeDigestSubscriptionNotFound = "AETHER-FEED-003"
+
+	// Inbound email
+	CodeInboundEmailDisabled = "AETHER-EMAIL-001"
+	CodeInboundEmailNotFound = "AETHER-EMAIL-002"
+
+	// S3 watchers
+	CodeS3WatchDisabled   = "AETHER-WATCH-001"
+	CodeS3WatcherNotFound = "AETHER-WATCH-002"
+
+	// Reports
+	CodeReportScheduleNotFound = "AETHER-REPORT-001"
+	CodeReportRunNotFound      = "AETHER-REPORT-002"
+
+	// Maintenance
+	CodeMaintenanceInProgress = "AETHER-MAINT-001"
+
+	// Space quotas
+	CodeDocumentQuotaExceeded       = "AETHER-QUOTA-001"
+	CodeStorageQuotaExceeded        = "AETHER-QUOTA-002"
+	CodeAgentExecutionQuotaExceeded = "AETHER-QUOTA-003"
+	CodeStreamEventQuotaExceeded    = "AETHER-QUOTA-004"
+
+	// Regions
+	CodeTenantInOtherRegion   = "AETHER-REGION-001"
+	CodeRegionUnknown         = "AETHER-REGION-002"
+	CodeTenantRegionNotPinned = "AETHER-REGION-003"
+	CodeTenantAlreadyInRegion = "AETHER-REGION-004"
+	CodeReplicationHookFailed = "AETHER-REGION-005"
+
+	// Organization roles
+	CodeRoleNotFound = "AETHER-ROLE-001"
+	CodeRoleExists   = "AETHER-ROLE-002"
+	CodeInvalidRole  = "AETHER-ROLE-003"
+	CodeRoleInUse    = "AETHER-ROLE-004"
+
+	// Rate limits
+	CodeUserRateLimitExceeded       = "AETHER-RATE-001"
+	CodeTenantRateLimitExceeded     = "AETHER-RATE-002"
+	CodeProcessingRateLimitExceeded = "AETHER-RATE-003"
+
+	// Idempotency keys
+	CodeIdempotencyKeyInProgress = "AETHER-IDEM-001"
+	CodeIdempotencyKeyReused     = "AETHER-IDEM-002"
+	CodeInvalidIdempotencyKey    = "AETHER-IDEM-003"
+
+	// Redaction rules
+	CodeRedactionRulesNotFound = "AETHER-REDACT-001"
+
+	// Content moderation
+	CodeContentNotAllowed         = "AETHER-MOD-001"
+	CodeDocumentHeldForModeration = "AETHER-MOD-002"
+
+	// Legal holds
+	CodeUnderLegalHold    = "AETHER-HOLD-001"
+	CodeLegalHoldNotFound = "AETHER-HOLD-002"
+	CodeLegalHoldReleased = "AETHER-HOLD-003"
+
+	// Malware scanning
+	CodeFileInfected       = "AETHER-SCAN-001"
+	CodeScannerUnavailable = "AETHER-SCAN-002"
+
+	// File validation
+	CodeFileTypeMismatch   = "AETHER-FILE-001"
+	CodeFileTypeNotAllowed = "AETHER-FILE-002"
+)
+
+// CatalogueEntry documents one catalogue code
+type CatalogueEntry struct {
+	Code        string `json:"code"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+}
+
+// catalogue lists every code with the error type it is returned with
+var catalogue = []CatalogueEntry{
+	{CodeBadRequest, ErrBadRequest, "The request is malformed"},
+	{CodeUnauthorized, ErrUnauthorized, "Authentication is required or has failed"},
+	{CodeForbidden, ErrForbidden, "The caller may not perform this operation"},
+	{CodeNotFound, ErrNotFound, "The requested resource does not exist"},
+	{CodeMethodNotAllowed, ErrMethodNotAllowed, "The HTTP method is not supported for this resource"},
+	{CodeConflict, ErrConflict, "The request conflicts with the current state of the resource"},
+	{CodeUnprocessableEntity, ErrUnprocessableEntity, "The request is well-formed but cannot be processed"},
+	{CodeTooManyRequests, ErrTooManyRequests, "Too many requests; retry after the Retry-After delay"},
+	{CodeInternal, ErrInternal, "An unexpected server error occurred"},
+	{CodeBadGateway, ErrBadGateway, "An upstream service returned an invalid response"},
+	{CodeServiceUnavailable, ErrServiceUnavailable, "The service is temporarily unavailable"},
+	{CodeGatewayTimeout, ErrGatewayTimeout, "The request timed out"},
+	{CodeValidation, ErrValidation, "The request failed validation"},
+	{CodeDatabase, ErrDatabaseError, "A database operation failed"},
+	{CodeExternalService, ErrExternalService, "A dependent service request failed"},
+	{CodePayloadTooLarge, ErrPayloadTooLarge, "The request body exceeds the size limit for this endpoint"},
+	{CodeGone, ErrGone, "The resource has been removed permanently"},
+	{CodePaymentRequired, ErrPaymentRequired, "A limit of the plan has been reached"},
+	{CodeUnsupportedMediaType, ErrUnsupportedMediaType, "The request body is in a format or encoding the endpoint does not accept"},
+	{CodeMisdirectedRequest, ErrMisdirectedRequest, "The request was sent to a server that does not serve it"},
+	{CodeRangeNotSatisfiable, ErrRangeNotSatisfiable, "The requested byte range starts past the end of the file"},
+
+	{CodeAPIVersionUnsupported, ErrNotFound, "The requested API version does not exist"},
+	{CodeAPIVersionSunset, ErrGone, "The requested API version has passed its sunset date and is no longer served"},
+	{CodeContentEncodingUnsupported, ErrUnsupportedMediaType, "The Content-Encoding of the request body is not supported; send gzip, zstd or identity"},
+
+	{CodeNotAuthenticated, ErrUnauthorized, "The request carries no authenticated user"},
+	{CodePermissionDenied, ErrForbidden, "None of the user's roles grants the action on the resource; details.action names it"},
+	{CodeSpaceContextRequired, ErrBadRequest, "The request must identify a space (X-Space-Type / X-Space-ID)"},
+	{CodeSpaceAccessDenied, ErrForbidden, "The user has no access to the requested space"},
+	{CodeTenantNotFound, ErrNotFound, "No space belongs to that tenant"},
+
+	{CodeNotebookNotFound, ErrNotFound, "The notebook does not exist"},
+	{CodeNotebookNotAccessible, ErrForbidden, "The notebook belongs to a different space"},
+	{CodeShareTargetNotFound, ErrNotFound, "A user or team to share with is not in the notebook's organization; details list which"},
+	{CodeShareNotFound, ErrNotFound, "The notebook is not shared with that user or team"},
+
+	{CodeDocumentNotFound, ErrNotFound, "The document does not exist"},
+	{CodeDocumentIDRequired, ErrValidation, "The document ID is missing"},
+	{CodeFileTooLarge, ErrValidation, "The uploaded file exceeds the size limit"},
+	{CodeProcessingInProgress, ErrProcessingInProgress, "The document is still being processed"},
+	{CodeFileNotProcessed, ErrFileNotProcessed, "The file has not been processed yet"},
+	{CodeDocumentVersionNotFound, ErrNotFound, "The document has no version with that number"},
+	{CodeDocumentDeletionNotFound, ErrNotFound, "No document deletion has that ID"},
+	{CodeOriginalFileRestricted, ErrForbidden, "The original file is restricted to the document's editors"},
+	{CodePriorityNotAllowed, ErrForbidden, "The space's billing plan does not allow that processing priority; details.max_priority is the highest it allows"},
+	{CodeCompareTooLarge, ErrUnprocessableEntity, "A text to compare is over the comparison size limit; details.limit and details.max say which"},
+
+	{CodeNotInTrash, ErrNotFound, "The document or notebook is not in the trash"},
+	{CodeNotebookInTrash, ErrConflict, "The document's notebook is in the trash; restore the notebook first"},
+
+	{CodeUploadNotFound, ErrNotFound, "The upload session does not exist"},
+	{CodeUploadNotActive, ErrConflict, "The upload session has been completed, aborted or has expired"},
+	{CodeInvalidUploadPart, ErrValidation, "The part number is out of range or the part does not have the length the session expects"},
+	{CodeUploadIncomplete, ErrConflict, "Parts of the upload are missing; details.missing_parts lists them"},
+	{CodeUploadNotReceived, ErrConflict, "The file of a direct upload has not been stored at its upload URL yet"},
+	{CodeUploadMismatch, ErrValidation, "The stored file does not have the declared size, MIME type or checksum; details.reason says which"},
+
+	{CodeQueryTooExpensive, ErrUnprocessableEntity, "The request would traverse too much of the graph; lower the depth or narrow the request"},
+	{CodeInvalidFilter, ErrValidation, "The filter expression cannot be parsed or uses an unknown field; details.reason says why"},
+	{CodeInvalidGraphToken, ErrValidation, "The graph page or expansion token is malformed"},
+
+	{CodeChunkNotFound, ErrChunkNotFound, "The chunk does not exist"},
+	{CodeChunkProcessing, ErrChunkProcessing, "Chunking the file failed"},
+	{CodeChunkEmbedding, ErrChunkEmbedding, "Generating chunk embeddings failed"},
+	{CodeChunkValidation, ErrChunkValidation, "The chunk request failed validation"},
+	{CodeStrategy, ErrStrategy, "The chunking strategy failed"},
+	{CodeStrategyNotFound, ErrStrategyNotFound, "The chunking strategy does not exist"},
+	{CodeStrategyValidation, ErrStrategyValidation, "The chunking strategy configuration is invalid"},
+
+	{CodeVectorSearchDisabled, ErrServiceUnavailable, "Vector search is not enabled on this deployment"},
+	{CodeVectorSyncDisabled, ErrServiceUnavailable, "Embedding sync to DeepLake is not enabled on this deployment"},
+
+	{CodeEntityNotFound, ErrNotFound, "The entity does not exist"},
+	{CodeEntityMergeInvalid, ErrValidation, "The entities cannot be merged: they are the same entity or of different types"},
+
+	{CodeSummaryBudgetExhausted, ErrTooManyRequests, "The space has spent its summary token budget for the month; details.resets_at says when it renews"},
+	{CodeNothingToSummarize, ErrConflict, "The document has no extracted text yet, or the notebook has no processed documents"},
+
+	{CodeClassificationPolicyNotFound, ErrNotFound, "The classification policy does not exist"},
+	{CodeInvalidClassificationPolicy, ErrValidation, "A condition lacks its value, the policy has no actions, or its target notebook is not in the space; details.reason says why"},
+
+	{CodeUserNotFound, ErrNotFound, "The user does not exist"},
+	{CodeOrganizationNotFound, ErrNotFound, "The organization does not exist"},
+	{CodeTeamNotFound, ErrNotFound, "The team does not exist"},
+	{CodeAgentNotFound, ErrNotFound, "The agent does not exist"},
+	{CodeWorkflowNotFound, ErrNotFound, "The workflow does not exist"},
+	{CodeStreamSourceNotFound, ErrNotFound, "The stream source does not exist"},
+	{CodeMLModelNotFound, ErrNotFound, "The ML model does not exist"},
+	{CodeMLExperimentNotFound, ErrNotFound, "The ML experiment does not exist"},
+	{CodeScheduledJobNotFound, ErrNotFound, "The scheduled job does not exist"},
+	{CodeRetryJobNotFound, ErrNotFound, "The processing retry job does not exist or has not failed"},
+	{CodeJobNotFound, ErrNotFound, "The async job does not exist, was started by another user or has expired"},
+	{CodeProcessingJobNotFound, ErrNotFound, "The document processing job does not exist"},
+	{CodeProcessingJobTransition, ErrConflict, "The processing job has already finished or cannot move to the requested status"},
+
+	{CodeWebhookSignatureInvalid, ErrUnauthorized, "The webhook signature is missing or does not match the integration's secret"},
+	{CodeWebhookExpired, ErrUnauthorized, "The webhook timestamp is outside the accepted window"},
+	{CodeWebhookNotConfigured, ErrServiceUnavailable, "The webhook integration has no secret configured"},
+	{CodeWebhookSubscriptionNotFound, ErrNotFound, "The webhook subscription does not exist in the current space"},
+	{CodeWebhookTargetNotAllowed, ErrValidation, "The webhook URL is not an http or https URL of a public host"},
+
+	{CodeFeedTokenInvalid, ErrUnauthorized, "The feed token is missing, revoked or was created for another feed"},
+	{CodeFeedTokenNotFound, ErrNotFound, "The feed token does not exist or belongs to another user"},
+	{CodeDigestSubscriptionNotFound, ErrNotFound, "The user is not subscribed to the notebook's digest"},
+
+	{CodeInboundEmailDisabled, ErrServiceUnavailable, "Email-to-notebook ingestion is not configured on this deployment"},
+	{CodeInboundEmailNotFound, ErrNotFound, "The notebook has no inbound email address"},
+
+	{CodeS3WatchDisabled, ErrServiceUnavailable, "S3 bucket watchers are not enabled on this deployment"},
+	{CodeS3WatcherNotFound, ErrNotFound, "The S3 watcher does not exist or belongs to another notebook"},
+
+	{CodeReportScheduleNotFound, ErrNotFound, "The report schedule does not exist or belongs to another space"},
+	{CodeReportRunNotFound, ErrNotFound, "The report does not exist, failed to generate or belongs to another space"},
+
+	{CodeMaintenanceInProgress, ErrServiceUnavailable, "Writes are rejected while maintenance is in progress; details describe the maintenance window"},
+	{CodeDocumentQuotaExceeded, ErrPaymentRequired, "The space holds as many documents as its quota allows; delete documents or raise the quota"},
+	{CodeStorageQuotaExceeded, ErrPaymentRequired, "The file would take the space's storage over its quota; delete documents or raise the quota"},
+	{CodeAgentExecutionQuotaExceeded, ErrTooManyRequests, "The space has used its agent executions for the month; details.resets_at says when they renew"},
+	{CodeStreamEventQuotaExceeded, ErrTooManyRequests, "The space has used its stream events for the month; details.resets_at says when they renew"},
+
+	{CodeTenantInOtherRegion, ErrMisdirectedRequest, "The space's tenant is served by another region; retry against details.region_url"},
+	{CodeRegionUnknown, ErrBadRequest, "The region is not one of the configured regions"},
+	{CodeTenantRegionNotPinned, ErrNotFound, "The tenant is not pinned to a region"},
+	{CodeTenantAlreadyInRegion, ErrConflict, "The tenant is already active in the target region"},
+	{CodeReplicationHookFailed, ErrBadGateway, "A replication hook failed, so the tenant was not failed over; details.hooks has the outcome of each hook"},
+
+	{CodeRoleNotFound, ErrNotFound, "The organization has no custom role of that name"},
+	{CodeRoleExists, ErrConflict, "The organization already has a role of that name, or it is a built-in role"},
+	{CodeInvalidRole, ErrValidation, "The role name is invalid, or a permission is not a known action; details.actions lists them"},
+	{CodeRoleInUse, ErrConflict, "Members still hold the role; assign them another role first"},
+
+	{CodeUserRateLimitExceeded, ErrTooManyRequests, "The user has sent too many requests of the route class; retry after the Retry-After delay"},
+	{CodeTenantRateLimitExceeded, ErrTooManyRequests, "The users of the space's tenant have sent too many requests of the route class; retry after the Retry-After delay"},
+	{CodeProcessingRateLimitExceeded, ErrTooManyRequests, "The space's tenant has submitted too many documents for processing for its plan, overdraft included; retry after the Retry-After delay"},
+
+	{CodeIdempotencyKeyInProgress, ErrConflict, "A request with the same Idempotency-Key is still being handled; retry after the Retry-After delay"},
+	{CodeIdempotencyKeyReused, ErrUnprocessableEntity, "The Idempotency-Key was used for a different request"},
+	{CodeInvalidIdempotencyKey, ErrBadRequest, "The Idempotency-Key header is longer than 255 characters or not printable ASCII"},
+
+	{CodeRedactionRulesNotFound, ErrNotFound, "The notebook has no redaction rules"},
+
+	{CodeContentNotAllowed, ErrUnprocessableEntity, "Moderation blocked the text from a public notebook; details.categories says why"},
+	{CodeDocumentHeldForModeration, ErrForbidden, "The document is in a public notebook and held until moderation approves it"},
+	{CodeUnderLegalHold, ErrConflict, "The document or notebook is under a legal hold and cannot be edited or deleted"},
+	{CodeLegalHoldNotFound, ErrNotFound, "No legal hold has that ID"},
+	{CodeLegalHoldReleased, ErrConflict, "The legal hold has been released"},
+	{CodeFileInfected, ErrUnprocessableEntity, "The file contains malware; it was rejected and quarantined"},
+	{CodeScannerUnavailable, ErrServiceUnavailable, "The file could not be scanned for malware; retry later"},
+	{CodeFileTypeMismatch, ErrUnprocessableEntity, "The file's content does not match its extension or declared MIME type"},
+	{CodeFileTypeNotAllowed, ErrUnsupportedMediaType, "The file's type is not accepted by the space or deployment; details.allowed_types lists those that are"},
+}
+
+// defaultCodes maps each error type to the code used when no more
+// specific one applies
+var defaultCodes = map[string]string{
+	ErrBadRequest:           CodeBadRequest,
+	ErrUnauthorized:         CodeUnauthorized,
+	ErrPaymentRequired:      CodePaymentRequired,
+	ErrForbidden:            CodeForbidden,
+	ErrNotFound:             CodeNotFound,
+	ErrMethodNotAllowed:     CodeMethodNotAllowed,
+	ErrConflict:             CodeConflict,
+	ErrUnprocessableEntity:  CodeUnprocessableEntity,
+	ErrTooManyRequests:      CodeTooManyRequests,
+	ErrPayloadTooLarge:      CodePayloadTooLarge,
+	ErrGone:                 CodeGone,
+	ErrUnsupportedMediaType: CodeUnsupportedMediaType,
+	ErrMisdirectedRequest:   CodeMisdirectedRequest,
+	ErrRangeNotSatisfiable:  CodeRangeNotSatisfiable,
+	ErrInternal:             CodeInternal,
+	ErrBadGateway:           CodeBadGateway,
+	ErrServiceUnavailable:   CodeServiceUnavailable,
+	ErrGatewayTimeout:       CodeGatewayTimeout,
+
+	ErrValidation:       CodeValidation,
+	ErrAuthentication:   CodeUnauthorized,
+	ErrAuthorization:    CodeForbidden,
+	ErrResourceExists:   CodeConflict,
+	ErrResourceNotFound: CodeNotFound,
+	ErrDatabaseError:    CodeDatabase,
+	ErrExternalService:  CodeExternalService,
+
+	ErrChunkNotFound:        CodeChunkNotFound,
+	ErrChunkProcessing:      CodeChunkProcessing,
+	ErrChunkEmbedding:       CodeChunkEmbedding,
+	ErrChunkValidation:      CodeChunkValidation,
+	ErrStrategy:             CodeStrategy,
+	ErrStrategyNotFound:     CodeStrategyNotFound,
+	ErrStrategyValidation:   CodeStrategyValidation,
+	ErrFileNotProcessed:     CodeFileNotProcessed,
+	ErrProcessingInProgress: CodeProcessingInProgress,
+}
+
+// DefaultErrorCode returns the catalogue code for an error type, falling
+// back to the internal error code for unknown types
+func DefaultErrorCode(errorType string) string {
+	if code, ok := defaultCodes[errorType]; ok {
+		return code
+	}
+	return CodeInternal
+}
+
+// Catalogue returns every catalogue entry
+func Catalogue() []CatalogueEntry {
+	entries := make([]CatalogueEntry, len(catalogue))
+	copy(entries, catalogue)
+	return entries
+}
@@ -0,0 +1,90 @@
+package errors
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogueCodes(t *testing.T) {
+	format := regexp.MustCompile(`^AETHER-[A-Z]+-\d{3}$`)
+	doc, err := os.ReadFile("../../docs/ERROR_CODES.md")
+	require.NoError(t, err)
+
+	seen := make(map[string]bool)
+	for _, entry := range Catalogue() {
+		assert.Regexp(t, format, entry.Code)
+		assert.False(t, seen[entry.Code], "duplicate code %s", entry.Code)
+		seen[entry.Code] = true
+		assert.Contains(t, string(doc), "`"+entry.Code+"`", "code %s is not documented", entry.Code)
+	}
+
+	for errorType, code := range defaultCodes {
+		assert.True(t, seen[code], "default code %s for %s is not in the catalogue", code, errorType)
+	}
+}
+
+// TestCatalogueComplete checks that every Code constant declared in
+// catalogue.go has an entry in the catalogue
+func TestCatalogueComplete(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "catalogue.go", nil, 0)
+	require.NoError(t, err)
+
+	listed := make(map[string]bool)
+	for _, entry := range Catalogue() {
+		listed[entry.Code] = true
+	}
+
+	declared := 0
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			for i, name := range spec.(*ast.ValueSpec).Names {
+				if !strings.HasPrefix(name.Name, "Code") {
+					continue
+				}
+				lit, ok := spec.(*ast.ValueSpec).Values[i].(*ast.BasicLit)
+				require.True(t, ok, "%s is not a string literal", name.Name)
+				declared++
+				assert.True(t, listed[strings.Trim(lit.Value, `"`)], "%s is not in the catalogue", name.Name)
+			}
+		}
+	}
+	assert.Equal(t, declared, len(listed), "the catalogue lists codes with no constant")
+}
+
+func TestErrorCodes(t *testing.T) {
+	t.Run("constructors use the type's default code", func(t *testing.T) {
+		assert.Equal(t, CodeNotFound, NotFound("Missing").ErrorCode)
+		assert.Equal(t, CodeChunkNotFound, ChunkNotFound("Missing").ErrorCode)
+		assert.Equal(t, CodeInternal, NewAPIError("UNKNOWN", "Unknown", nil).ErrorCode)
+	})
+
+	t.Run("WithErrorCode leaves the receiver unchanged", func(t *testing.T) {
+		shared := NotFound("Document not found")
+		specific := shared.WithErrorCode(CodeDocumentNotFound)
+
+		assert.Equal(t, CodeDocumentNotFound, specific.ErrorCode)
+		assert.Equal(t, CodeNotFound, shared.ErrorCode)
+	})
+
+	t.Run("Normalize keeps specific codes and fills in missing ones", func(t *testing.T) {
+		specific := Normalize(NotFound("Document not found").WithErrorCode(CodeDocumentNotFound))
+		assert.Equal(t, CodeDocumentNotFound, specific.ErrorCode)
+
+		literal := Normalize(&APIError{Code: ErrConflict, Message: "Exists"})
+		assert.Equal(t, CodeConflict, literal.ErrorCode)
+
+		assert.Equal(t, CodeInternal, Normalize(assert.AnError).ErrorCode)
+	})
+}
@@ -1,6 +1,8 @@
 package errors
 
 import (
+	"context"
+	stderrors "errors"
 	"fmt"
 	"net/http"
 )
@@ -10,12 +12,19 @@ const (
 	// Client errors (4xx)
 	ErrBadRequest          = "BAD_REQUEST"
 	ErrUnauthorized        = "UNAUTHORIZED"
+	ErrPaymentRequired     = "PAYMENT_REQUIRED"
 	ErrForbidden           = "FORBIDDEN"
 	ErrNotFound            = "NOT_FOUND"
 	ErrMethodNotAllowed    = "METHOD_NOT_ALLOWED"
 	ErrConflict            = "CONFLICT"
 	ErrUnprocessableEntity = "UNPROCESSABLE_ENTITY"
 	ErrTooManyRequests     = "TOO_MANY_REQUESTS"
+	ErrPayloadTooLarge     = "PAYLOAD_TOO_LARGE"
+	ErrGone                = "GONE"
+
+	ErrUnsupportedMediaType = "UNSUPPORTED_MEDIA_TYPE"
+	ErrMisdirectedRequest   = "MISDIRECTED_REQUEST"
+	ErrRangeNotSatisfiable  = "RANGE_NOT_SATISFIABLE"
 
 	// Server errors (5xx)
 	ErrInternal           = "INTERNAL_SERVER_ERROR"
@@ -44,11 +53,14 @@ const (
 	ErrProcessingInProgress = "PROCESSING_IN_PROGRESS"
 )
 
-// APIError represents a structured API error
+// APIError represents a structured API error. Code is the error type;
+// ErrorCode is the stable catalogue code (see catalogue.go).
 type APIError struct {
 	Code       string                 `json:"code"`
+	ErrorCode  string                 `json:"error_code"`
 	Message    string                 `json:"message"`
 	Details    map[string]interface{} `json:"details,omitempty"`
+	RequestID  string                 `json:"request_id,omitempty"`
 	StatusCode int                    `json:"-"`
 	Cause      error                  `json:"-"`
 }
@@ -66,10 +78,28 @@ func (e *APIError) Unwrap() error {
 	return e.Cause
 }
 
+// WithRequestID returns a copy of the error carrying the correlation ID
+// returned to the client. The receiver is left unchanged so shared errors
+// never carry another request's ID.
+func (e *APIError) WithRequestID(requestID string) *APIError {
+	clone := *e
+	clone.RequestID = requestID
+	return &clone
+}
+
+// WithErrorCode returns a copy of the error carrying a specific catalogue
+// code in place of its type's default
+func (e *APIError) WithErrorCode(code string) *APIError {
+	clone := *e
+	clone.ErrorCode = code
+	return &clone
+}
+
 // NewAPIError creates a new API error
 func NewAPIError(code, message string, details map[string]interface{}) *APIError {
 	return &APIError{
 		Code:       code,
+		ErrorCode:  DefaultErrorCode(code),
 		Message:    message,
 		Details:    details,
 		StatusCode: GetHTTPStatusCodeFromErrorCode(code),
@@ -80,6 +110,7 @@ func NewAPIError(code, message string, details map[string]interface{}) *APIError
 func NewAPIErrorWithCause(code, message string, cause error, details map[string]interface{}) *APIError {
 	return &APIError{
 		Code:       code,
+		ErrorCode:  DefaultErrorCode(code),
 		Message:    message,
 		Details:    details,
 		Cause:      cause,
@@ -102,6 +133,8 @@ func GetHTTPStatusCodeFromErrorCode(code string) int {
 		return http.StatusBadRequest
 	case ErrUnauthorized, ErrAuthentication:
 		return http.StatusUnauthorized
+	case ErrPaymentRequired:
+		return http.StatusPaymentRequired
 	case ErrForbidden, ErrAuthorization:
 		return http.StatusForbidden
 	case ErrNotFound, ErrResourceNotFound, ErrChunkNotFound, ErrStrategyNotFound, ErrFileNotProcessed:
@@ -114,6 +147,16 @@ func GetHTTPStatusCodeFromErrorCode(code string) int {
 		return http.StatusUnprocessableEntity
 	case ErrTooManyRequests:
 		return http.StatusTooManyRequests
+	case ErrPayloadTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case ErrUnsupportedMediaType:
+		return http.StatusUnsupportedMediaType
+	case ErrMisdirectedRequest:
+		return http.StatusMisdirectedRequest
+	case ErrRangeNotSatisfiable:
+		return http.StatusRequestedRangeNotSatisfiable
+	case ErrGone:
+		return http.StatusGone
 	case ErrBadGateway, ErrExternalService:
 		return http.StatusBadGateway
 	case ErrServiceUnavailable:
@@ -229,6 +272,28 @@ func TooManyRequests(message string) *APIError {
 	return NewAPIError(ErrTooManyRequests, message, nil)
 }
 
+// PaymentRequiredWithDetails creates an error for a plan limit that is
+// reached
+func PaymentRequiredWithDetails(message string, details map[string]interface{}) *APIError {
+	return NewAPIError(ErrPaymentRequired, message, details)
+}
+
+// PayloadTooLarge creates a request body too large error
+func PayloadTooLarge(message string) *APIError {
+	return NewAPIError(ErrPayloadTooLarge, message, nil)
+}
+
+// UnsupportedMediaType creates an error for a request body in a format or
+// encoding the endpoint does not read
+func UnsupportedMediaType(message string) *APIError {
+	return NewAPIError(ErrUnsupportedMediaType, message, nil)
+}
+
+// GoneWithDetails creates an error for something that was removed for good
+func GoneWithDetails(message string, details map[string]interface{}) *APIError {
+	return NewAPIError(ErrGone, message, details)
+}
+
 // Validation creates a validation error
 func Validation(message string, cause error) *APIError {
 	return NewAPIErrorWithCause(ErrValidation, message, cause, nil)
@@ -248,6 +313,48 @@ func AsAPIError(err error) (*APIError, bool) {
 	return nil, false
 }
 
+// Normalize converts any error into an APIError that is safe to return to
+// clients. APIErrors (including wrapped ones) keep their code, message and
+// details; their cause is never serialized. Context deadline errors map to a
+// gateway timeout. Anything else becomes a generic internal error so raw
+// error text from drivers or downstream services never leaks.
+func Normalize(err error) *APIError {
+	if err == nil {
+		return nil
+	}
+
+	var apiErr *APIError
+	if stderrors.As(err, &apiErr) {
+		normalized := *apiErr
+		if normalized.StatusCode == 0 {
+			normalized.StatusCode = GetHTTPStatusCodeFromErrorCode(normalized.Code)
+		}
+		if normalized.ErrorCode == "" {
+			normalized.ErrorCode = DefaultErrorCode(normalized.Code)
+		}
+		// Database errors are internal by nature; keep the code but hide details
+		if normalized.Code == ErrDatabaseError {
+			normalized.Message = "Database operation failed"
+			normalized.Details = nil
+		}
+		// Upstream failures may carry downstream bodies; callers log those instead
+		if normalized.Code == ErrExternalService {
+			normalized.Message = "External service request failed"
+			normalized.Details = nil
+		}
+		return &normalized
+	}
+
+	switch {
+	case stderrors.Is(err, context.DeadlineExceeded):
+		return NewAPIErrorWithCause(ErrGatewayTimeout, "Request timed out", err, nil)
+	case stderrors.Is(err, context.Canceled):
+		return NewAPIErrorWithCause(ErrServiceUnavailable, "Request was cancelled", err, nil)
+	default:
+		return InternalWithCause("Internal server error", err)
+	}
+}
+
 // Error type checking functions
 func IsNotFound(err error) bool {
 	if apiErr, ok := err.(*APIError); ok {
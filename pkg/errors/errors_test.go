@@ -1,6 +1,9 @@
 package errors
 
 import (
+	"context"
+	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -21,6 +24,17 @@ func TestAPIError(t *testing.T) {
 		assert.Contains(t, err.Error(), "TEST_ERROR")
 		assert.Contains(t, err.Error(), "Test error")
 	})
+
+	t.Run("WithRequestID leaves the receiver unchanged", func(t *testing.T) {
+		shared := NotFound("Missing")
+
+		first := shared.WithRequestID("req-1")
+		second := shared.WithRequestID("req-2")
+
+		assert.Equal(t, "req-1", first.RequestID)
+		assert.Equal(t, "req-2", second.RequestID)
+		assert.Empty(t, shared.RequestID)
+	})
 }
 
 func TestErrorCreators(t *testing.T) {
@@ -85,3 +99,52 @@ func TestErrorCheckers(t *testing.T) {
 		assert.False(t, IsUnauthorized(assert.AnError))
 	})
 }
+
+func TestNormalize(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		assert.Nil(t, Normalize(nil))
+	})
+
+	t.Run("wrapped API error keeps code", func(t *testing.T) {
+		wrapped := fmt.Errorf("service layer: %w", NotFound("Document not found"))
+		normalized := Normalize(wrapped)
+
+		assert.Equal(t, ErrNotFound, normalized.Code)
+		assert.Equal(t, "Document not found", normalized.Message)
+		assert.Equal(t, http.StatusNotFound, normalized.StatusCode)
+	})
+
+	t.Run("database error hides details", func(t *testing.T) {
+		err := DatabaseWithDetails("neo4j: syntax error near MATCH", assert.AnError, map[string]interface{}{"query": "MATCH"})
+		normalized := Normalize(err)
+
+		assert.Equal(t, ErrDatabaseError, normalized.Code)
+		assert.Equal(t, "Database operation failed", normalized.Message)
+		assert.Nil(t, normalized.Details)
+	})
+
+	t.Run("external service error hides downstream message", func(t *testing.T) {
+		err := ExternalService("Agent-builder error: {\"trace\":\"db at 10.0.0.7\"}", nil)
+		normalized := Normalize(err)
+
+		assert.Equal(t, ErrExternalService, normalized.Code)
+		assert.Equal(t, http.StatusBadGateway, normalized.StatusCode)
+		assert.Equal(t, "External service request failed", normalized.Message)
+		assert.NotContains(t, normalized.Message, "10.0.0.7")
+	})
+
+	t.Run("deadline exceeded", func(t *testing.T) {
+		normalized := Normalize(fmt.Errorf("query: %w", context.DeadlineExceeded))
+
+		assert.Equal(t, ErrGatewayTimeout, normalized.Code)
+		assert.Equal(t, http.StatusGatewayTimeout, normalized.StatusCode)
+	})
+
+	t.Run("plain error does not leak", func(t *testing.T) {
+		normalized := Normalize(fmt.Errorf("dial tcp 10.0.0.5:7687: connection refused"))
+
+		assert.Equal(t, ErrInternal, normalized.Code)
+		assert.Equal(t, "Internal server error", normalized.Message)
+		assert.NotContains(t, normalized.Message, "10.0.0.5")
+	})
+}
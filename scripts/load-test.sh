@@ -0,0 +1,131 @@
+#!/bin/bash
+
+# Aether Backend Load Test Runner
+#
+# Runs the k6 scenarios in tests/performance/scenarios against a running
+# instance, stores each scenario's summary under tests/performance/reports
+# and compares it with the stored baseline.
+set -euo pipefail
+
+SCRIPT_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)"
+ROOT_DIR="$(dirname "$SCRIPT_DIR")"
+PERF_DIR="$ROOT_DIR/tests/performance"
+SCENARIO_DIR="$PERF_DIR/scenarios"
+BASELINE_DIR="$PERF_DIR/baseline"
+
+# Default values
+SCENARIOS=""
+UPDATE_BASELINE=false
+OUTPUT_DIR="$PERF_DIR/reports/$(date +%Y%m%d-%H%M%S)"
+
+# Colors for output
+RED='\033[0;31m'
+GREEN='\033[0;32m'
+BLUE='\033[0;34m'
+NC='\033[0m' # No Color
+
+# Logging functions
+log_info() {
+    echo -e "${BLUE}[INFO]${NC} $1"
+}
+
+log_success() {
+    echo -e "${GREEN}[SUCCESS]${NC} $1"
+}
+
+log_error() {
+    echo -e "${RED}[ERROR]${NC} $1"
+}
+
+# Usage function
+usage() {
+    cat << EOF
+Usage: $0 [OPTIONS]
+
+Run the k6 load test scenarios and compare the results with the baseline.
+
+OPTIONS:
+    -s, --scenario NAME     Run only this scenario (repeatable): document_upload,
+                            search, agent_execute, stream_ingest
+    -o, --output DIR        Directory for the k6 summaries (default: timestamped
+                            directory under tests/performance/reports)
+    -b, --update-baseline   Copy the results into tests/performance/baseline
+                            instead of comparing against it
+    -h, --help              Show this help message
+
+Scenarios are configured through environment variables: BASE_URL, TOKEN,
+SPACE_TYPE, SPACE_ID, NOTEBOOK_ID, AGENT_ID, STREAM_SOURCE_ID, VUS, DURATION
+and RATE. See tests/performance/README.md.
+EOF
+}
+
+# Parse command line arguments
+while [[ $# -gt 0 ]]; do
+    case $1 in
+        -s|--scenario)
+            SCENARIOS="$SCENARIOS $2"
+            shift 2
+            ;;
+        -o|--output)
+            OUTPUT_DIR="$2"
+            shift 2
+            ;;
+        -b|--update-baseline)
+            UPDATE_BASELINE=true
+            shift
+            ;;
+        -h|--help)
+            usage
+            exit 0
+            ;;
+        *)
+            log_error "Unknown option: $1"
+            usage
+            exit 1
+            ;;
+    esac
+done
+
+if ! command -v k6 >/dev/null 2>&1; then
+    log_error "k6 is not installed, see https://k6.io/docs/get-started/installation/"
+    exit 1
+fi
+
+if [[ -z "$SCENARIOS" ]]; then
+    SCENARIOS="document_upload search agent_execute stream_ingest"
+fi
+
+mkdir -p "$OUTPUT_DIR"
+
+# A scenario that breaches its thresholds still produces a summary, so keep
+# going and report every failure at the end
+FAILED=""
+for scenario in $SCENARIOS; do
+    script="$SCENARIO_DIR/$scenario.js"
+    if [[ ! -f "$script" ]]; then
+        log_error "Unknown scenario: $scenario"
+        exit 1
+    fi
+
+    log_info "Running scenario $scenario"
+    if ! k6 run --quiet --summary-export "$OUTPUT_DIR/$scenario.json" "$script"; then
+        FAILED="$FAILED $scenario"
+    fi
+done
+
+if [[ "$UPDATE_BASELINE" == "true" ]]; then
+    cp "$OUTPUT_DIR"/*.json "$BASELINE_DIR"/
+    log_success "Baseline updated from $OUTPUT_DIR"
+else
+    log_info "Comparing $OUTPUT_DIR with $BASELINE_DIR"
+    if ! (cd "$ROOT_DIR" && go run ./cmd/loadreport -baseline "$BASELINE_DIR" -current "$OUTPUT_DIR"); then
+        FAILED="$FAILED baseline-comparison"
+    fi
+fi
+
+if [[ -n "$FAILED" ]]; then
+    log_error "Load test failed:$FAILED"
+    exit 1
+fi
+
+log_success "Load test passed, reports in $OUTPUT_DIR"
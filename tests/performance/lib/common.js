@@ -0,0 +1,68 @@
+// Shared configuration and helpers for the k6 load scenarios.
+//
+// Every scenario talks to a running aether-be instance. Point it at the
+// target with environment variables, for example:
+//
+//   k6 run -e BASE_URL=http://localhost:8080 -e TOKEN=$TOKEN \
+//          -e SPACE_ID=space_123 tests/performance/scenarios/search.js
+
+import { check } from 'k6';
+
+export const BASE_URL = __ENV.BASE_URL || 'http://localhost:8080';
+export const API = `${BASE_URL}/api/v1`;
+
+export const TOKEN = __ENV.TOKEN || '';
+export const SPACE_TYPE = __ENV.SPACE_TYPE || 'personal';
+export const SPACE_ID = __ENV.SPACE_ID || '';
+export const NOTEBOOK_ID = __ENV.NOTEBOOK_ID || '';
+export const AGENT_ID = __ENV.AGENT_ID || '';
+export const STREAM_SOURCE_ID = __ENV.STREAM_SOURCE_ID || '';
+
+// VUS and DURATION let CI run the same scenario at a smaller scale
+export const VUS = parseInt(__ENV.VUS || '10', 10);
+export const DURATION = __ENV.DURATION || '1m';
+
+// headers returns the authentication and space headers every request needs
+export function headers(extra = {}) {
+  const h = Object.assign(
+    {
+      'X-Space-Type': SPACE_TYPE,
+      'X-Space-ID': SPACE_ID,
+    },
+    extra,
+  );
+  if (TOKEN) {
+    h.Authorization = `Bearer ${TOKEN}`;
+  }
+  return h;
+}
+
+export function jsonHeaders() {
+  return headers({ 'Content-Type': 'application/json' });
+}
+
+// requireEnv fails the run up front instead of producing a report full of
+// 400s when a scenario is missing the IDs it targets
+export function requireEnv(...names) {
+  const missing = names.filter((name) => !__ENV[name]);
+  if (missing.length > 0) {
+    throw new Error(`missing required environment variables: ${missing.join(', ')}`);
+  }
+}
+
+// checkStatus records whether a response had the expected status
+export function checkStatus(res, expected, name) {
+  return check(res, {
+    [`${name} status is ${expected}`]: (r) => r.status === expected,
+  });
+}
+
+// thresholds builds the per-scenario pass/fail criteria. A run whose p95
+// latency or error rate exceeds these fails even without a baseline.
+export function thresholds(p95Ms, maxErrorRate = 0.01) {
+  return {
+    http_req_duration: [`p(95)<${p95Ms}`],
+    http_req_failed: [`rate<${maxErrorRate}`],
+    checks: ['rate>0.99'],
+  };
+}
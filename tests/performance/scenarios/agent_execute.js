@@ -0,0 +1,33 @@
+// Agent execution: synchronous agent runs. Latency here is dominated by
+// the upstream LLM, so the threshold is looser than the CRUD scenarios.
+//
+// Required: TOKEN, SPACE_ID, AGENT_ID
+
+import http from 'k6/http';
+import { sleep } from 'k6';
+import { API, AGENT_ID, VUS, DURATION, jsonHeaders, requireEnv, checkStatus, thresholds } from '../lib/common.js';
+
+requireEnv('SPACE_ID', 'AGENT_ID');
+
+export const options = {
+  vus: VUS,
+  duration: DURATION,
+  thresholds: thresholds(10000, 0.05),
+};
+
+const prompts = [
+  'Summarise the latest quarterly report.',
+  'What are the key risks mentioned in our contracts?',
+  'List the action items from the last meeting.',
+];
+
+export default function () {
+  const input = prompts[Math.floor(Math.random() * prompts.length)];
+  const res = http.post(`${API}/agents/${AGENT_ID}/execute`, JSON.stringify({ input }), {
+    headers: jsonHeaders(),
+    tags: { name: 'agent_execute' },
+    timeout: '60s',
+  });
+  checkStatus(res, 200, 'execute');
+  sleep(2);
+}
@@ -0,0 +1,34 @@
+// Document upload: multipart uploads into a notebook.
+//
+// Required: TOKEN, SPACE_ID, NOTEBOOK_ID
+
+import http from 'k6/http';
+import { sleep } from 'k6';
+import { API, NOTEBOOK_ID, VUS, DURATION, headers, requireEnv, checkStatus, thresholds } from '../lib/common.js';
+
+requireEnv('SPACE_ID', 'NOTEBOOK_ID');
+
+export const options = {
+  vus: VUS,
+  duration: DURATION,
+  thresholds: thresholds(2000),
+};
+
+// A ~16KB text document, large enough to exercise the storage path without
+// making the run bandwidth bound
+const body = 'The quick brown fox jumps over the lazy dog.\n'.repeat(370);
+
+export default function () {
+  const res = http.post(
+    `${API}/documents/upload`,
+    {
+      file: http.file(body, `load-${__VU}-${__ITER}.txt`, 'text/plain'),
+      notebook_id: NOTEBOOK_ID,
+      name: `load test ${__VU}-${__ITER}`,
+      tags: 'load-test',
+    },
+    { headers: headers(), tags: { name: 'document_upload' } },
+  );
+  checkStatus(res, 201, 'upload');
+  sleep(1);
+}
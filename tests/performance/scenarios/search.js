@@ -0,0 +1,27 @@
+// Document search: full-text queries against the space's documents.
+//
+// Required: TOKEN, SPACE_ID
+
+import http from 'k6/http';
+import { sleep } from 'k6';
+import { API, VUS, DURATION, headers, requireEnv, checkStatus, thresholds } from '../lib/common.js';
+
+requireEnv('SPACE_ID');
+
+export const options = {
+  vus: VUS,
+  duration: DURATION,
+  thresholds: thresholds(500),
+};
+
+const queries = ['report', 'revenue', 'contract', 'meeting notes', 'invoice', 'policy'];
+
+export default function () {
+  const query = queries[Math.floor(Math.random() * queries.length)];
+  const res = http.get(`${API}/documents/search?query=${encodeURIComponent(query)}&limit=20`, {
+    headers: headers(),
+    tags: { name: 'document_search' },
+  });
+  checkStatus(res, 200, 'search');
+  sleep(0.5);
+}
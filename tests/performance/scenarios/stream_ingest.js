@@ -0,0 +1,43 @@
+// Stream ingest: a constant arrival rate of live events into one source,
+// which is how producers actually behave.
+//
+// Required: TOKEN, SPACE_ID, STREAM_SOURCE_ID
+// Optional: RATE (events per second, default 50)
+
+import http from 'k6/http';
+import { API, STREAM_SOURCE_ID, VUS, DURATION, jsonHeaders, requireEnv, checkStatus, thresholds } from '../lib/common.js';
+
+requireEnv('SPACE_ID', 'STREAM_SOURCE_ID');
+
+const RATE = parseInt(__ENV.RATE || '50', 10);
+
+export const options = {
+  scenarios: {
+    ingest: {
+      executor: 'constant-arrival-rate',
+      rate: RATE,
+      timeUnit: '1s',
+      duration: DURATION,
+      preAllocatedVUs: VUS,
+      maxVUs: VUS * 5,
+    },
+  },
+  thresholds: thresholds(300),
+};
+
+const eventTypes = ['mention', 'comment', 'review'];
+
+export default function () {
+  const event = {
+    source_id: STREAM_SOURCE_ID,
+    event_type: eventTypes[__ITER % eventTypes.length],
+    content: `Load test event ${__VU}-${__ITER}: the new release looks great`,
+    media_type: 'text',
+    metadata: { load_test: true, vu: __VU },
+  };
+  const res = http.post(`${API}/streams/sources/${STREAM_SOURCE_ID}/events`, JSON.stringify(event), {
+    headers: jsonHeaders(),
+    tags: { name: 'stream_ingest' },
+  });
+  checkStatus(res, 201, 'ingest');
+}